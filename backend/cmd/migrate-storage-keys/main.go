@@ -0,0 +1,138 @@
+// Command migrate-storage-keys moves avatars and meeting files stored under the
+// legacy flat layout (avatars/..., meetings/...) into per-tenant, per-user
+// prefixes (tenants/{tenant}/users/{user}/...) and updates the database to match.
+//
+// Objects are copied before the database row is updated and the old object is
+// deleted last, so the command can be safely re-run after a partial failure.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"strings"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/database"
+	"github.com/smith-dallin/manager-dashboard/internal/storage"
+)
+
+// move describes one object relocation and the row that references it
+type move struct {
+	id     int64
+	oldKey string
+	newKey string
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "log the planned moves without copying objects or updating rows")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if !cfg.S3Enabled {
+		log.Fatalf("S3 must be enabled to migrate storage keys")
+	}
+
+	store, err := storage.NewS3Storage(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize S3 storage: %v", err)
+	}
+
+	pool, err := database.Connect(cfg.DatabaseURL, nil) // Use default pool config for CLI
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	ctx := context.Background()
+	baseURL := strings.TrimSuffix(store.GetURL(""), "/")
+
+	// Avatars are referenced by URL on the user row
+	rows, err := pool.Query(ctx, "SELECT id, avatar_url FROM users WHERE avatar_url LIKE $1", baseURL+"/avatars/%")
+	if err != nil {
+		log.Fatalf("Failed to query users: %v", err)
+	}
+	var avatars []move
+	for rows.Next() {
+		var id int64
+		var avatarURL string
+		if err := rows.Scan(&id, &avatarURL); err != nil {
+			log.Printf("Failed to scan user: %v", err)
+			continue
+		}
+		oldKey := storage.ExtractKeyFromURL(avatarURL, baseURL)
+		if newKey, ok := storage.MigrateAvatarKey(cfg.S3TenantID, id, oldKey); ok {
+			avatars = append(avatars, move{id: id, oldKey: oldKey, newKey: newKey})
+		}
+	}
+	rows.Close()
+
+	// Meeting files are keyed by their uploader
+	rows, err = pool.Query(ctx, "SELECT id, uploaded_by_id, storage_key FROM meeting_attachments WHERE storage_key LIKE 'meetings/%'")
+	if err != nil {
+		log.Fatalf("Failed to query meeting attachments: %v", err)
+	}
+	var attachments []move
+	for rows.Next() {
+		var id, uploaderID int64
+		var oldKey string
+		if err := rows.Scan(&id, &uploaderID, &oldKey); err != nil {
+			log.Printf("Failed to scan meeting attachment: %v", err)
+			continue
+		}
+		if newKey, ok := storage.MigrateMeetingAttachmentKey(cfg.S3TenantID, uploaderID, oldKey); ok {
+			attachments = append(attachments, move{id: id, oldKey: oldKey, newKey: newKey})
+		}
+	}
+	rows.Close()
+
+	log.Printf("Found %d avatars and %d meeting files to migrate into tenant %q", len(avatars), len(attachments), cfg.S3TenantID)
+	if *dryRun {
+		for _, m := range append(avatars, attachments...) {
+			log.Printf("%s -> %s", m.oldKey, m.newKey)
+		}
+		return
+	}
+
+	migrated := 0
+	for _, m := range avatars {
+		if err := relocate(ctx, store, m, func(newKey string) error {
+			_, err := pool.Exec(ctx, "UPDATE users SET avatar_url = $1, updated_at = NOW() WHERE id = $2", store.GetURL(newKey), m.id)
+			return err
+		}); err != nil {
+			log.Printf("Failed to migrate avatar for user %d: %v", m.id, err)
+			continue
+		}
+		migrated++
+	}
+	for _, m := range attachments {
+		if err := relocate(ctx, store, m, func(newKey string) error {
+			_, err := pool.Exec(ctx, "UPDATE meeting_attachments SET storage_key = $1 WHERE id = $2", newKey, m.id)
+			return err
+		}); err != nil {
+			log.Printf("Failed to migrate meeting attachment %d: %v", m.id, err)
+			continue
+		}
+		migrated++
+	}
+
+	log.Printf("✅ Migrated %d of %d stored files", migrated, len(avatars)+len(attachments))
+}
+
+// relocate copies an object to its new key, points the database at it, then removes the old object
+func relocate(ctx context.Context, store *storage.S3Storage, m move, updateRow func(newKey string) error) error {
+	if err := store.Copy(ctx, m.oldKey, m.newKey); err != nil {
+		return err
+	}
+	if err := updateRow(m.newKey); err != nil {
+		// Leave the old object in place; the copy is overwritten on the next run
+		return err
+	}
+	if err := store.Delete(ctx, m.oldKey); err != nil {
+		log.Printf("Migrated %s but failed to delete the old object: %v", m.oldKey, err)
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
+	"net/netip"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -20,6 +24,20 @@ type Config struct {
 	Auth0MgmtClientID     string
 	Auth0MgmtClientSecret string
 	Auth0DBConnection     string // The name of the Auth0 database connection (e.g., "Username-Password-Authentication")
+	// Authorization token configured on the Auth0 log stream webhook; users deleted or blocked in
+	// Auth0 are only deactivated here when it's set
+	Auth0WebhookToken string
+
+	// Generic OIDC provider (Okta, Azure AD, Keycloak, ...), trusted alongside or instead of Auth0
+	OIDCProviderName string // Prefixes the provider's subjects, e.g. "okta"
+	OIDCIssuerURL    string // Must match the iss claim of the provider's tokens exactly
+	OIDCAudience     string
+	OIDCJWKSURL      string // Optional: defaults to the jwks_uri in the issuer's OpenID configuration
+
+	// SAML broker that signs users in with a SAML IdP and issues OIDC tokens for them
+	SAMLBrokerIssuerURL string
+	SAMLBrokerAudience  string
+	SAMLBrokerJWKSURL   string // Optional: defaults to the jwks_uri in the broker's OpenID configuration
 
 	// S3 Configuration
 	S3Enabled         bool
@@ -29,12 +47,31 @@ type Config struct {
 	S3SecretAccessKey string
 	S3Endpoint        string // Optional: for S3-compatible services like R2, MinIO
 	S3PublicURL       string // Optional: custom public URL for accessing files
+	S3TenantID        string // Organization identifier that prefixes every stored object key
+	// How avatar URLs are served: "public" as stored, "signed" as expiring presigned URLs, or "cdn" under AvatarCDNURL
+	AvatarURLMode       string
+	AvatarCDNURL        string // Base URL of the CDN in front of the bucket, for the "cdn" mode
+	AvatarURLTTLMinutes int    // Minutes a signed avatar URL stays valid
+	GravatarEnabled     bool   // Use users' Gravatars, when they have one, as their default avatar instead of initials
 
 	// Jira OAuth 2.0 Configuration
 	JiraClientID     string
 	JiraClientSecret string
 	JiraCallbackURL  string // e.g., http://localhost:3000/api/jira/callback
 
+	// GitHub App OAuth Configuration
+	GitHubClientID     string
+	GitHubClientSecret string
+	GitHubCallbackURL  string // e.g., http://localhost:3000/api/github/oauth/callback
+	GitHubAPIURL       string // REST API root; change for GitHub Enterprise Server
+
+	// Slack App OAuth Configuration, for direct messages and interactive approvals
+	SlackClientID      string
+	SlackClientSecret  string
+	SlackSigningSecret string // Verifies interactive requests from Slack; buttons are rejected without it
+	SlackCallbackURL   string // e.g., http://localhost:3000/api/slack/oauth/callback
+	SlackAPIURL        string // Web API root
+
 	// Server Configuration
 	RateLimitRPS       float64 // Requests per second for rate limiting
 	RateLimitBurst     int     // Burst size for rate limiting
@@ -43,35 +80,102 @@ type Config struct {
 	WriteTimeout       int     // Server write timeout in seconds
 	IdleTimeout        int     // Server idle timeout in seconds
 	ShutdownTimeout    int     // Graceful shutdown timeout in seconds
+	WorkerDrainTimeout int     // Seconds background workers get to finish in-flight work on shutdown
 
 	// Logging Configuration
 	LogLevel  string // debug, info, warn, error
 	LogFormat string // json, text
 
+	// Tracing Configuration
+	TracingOTLPEndpoint string  // OTLP/HTTP traces URL, e.g. http://localhost:4318/v1/traces; tracing is off when empty
+	TracingSampleRatio  float64 // Share (0-1) of new traces that are recorded
+
 	// Database Pool Configuration
-	DBMaxConns             int // Maximum number of connections in pool
-	DBMinConns             int // Minimum number of idle connections
-	DBMaxConnLifetime      int // Maximum connection lifetime in seconds
-	DBMaxConnIdleTime      int // Maximum idle time before closing in seconds
-	DBHealthCheckPeriod    int // Health check interval in seconds
-	DBSlowQueryThresholdMS int // Threshold in milliseconds for logging slow queries
+	DBMaxConns             int    // Maximum number of connections in pool
+	DBMinConns             int    // Minimum number of idle connections
+	DBMaxConnLifetime      int    // Maximum connection lifetime in seconds
+	DBMaxConnIdleTime      int    // Maximum idle time before closing in seconds
+	DBHealthCheckPeriod    int    // Health check interval in seconds
+	DBSlowQueryThresholdMS int    // Threshold in milliseconds for logging slow queries
+	DatabaseReplicaURL     string // Optional read replica for heavy list, org tree, and calendar reads
 
 	// Application Configuration
-	AvatarMaxSizeMB      int // Maximum avatar upload size in MB
-	InvitationExpiryDays int // Number of days until an invitation expires
-	CacheTTLSeconds      int // Default cache TTL in seconds
+	AvatarMaxSizeMB            int // Maximum avatar upload size in MB
+	MeetingAttachmentMaxSizeMB int // Maximum meeting recording/transcript upload size in MB
+	MeetingAttachmentURLTTLMin int // Minutes a signed meeting recording/transcript download link stays valid
+	InvitationExpiryDays       int // Number of days until an invitation expires
+	CacheTTLSeconds            int // Default cache TTL in seconds
+	ExportRetentionHours       int // Hours a generated export file is kept before it is deleted
+	ExportURLTTLMinutes        int // Minutes a signed export download link stays valid
+	ResponseCacheTTLSeconds    int // Seconds a cached read response (org tree, team tasks, reports) is served as fresh
+	ResponseCacheStaleSeconds  int // Seconds after that a stale response is still served while it is refreshed
+	SoftDeleteRetentionDays    int // Days a deleted user, task, or meeting can be restored before it is purged
 
 	// Security Configuration
 	JWKSCacheTTLMinutes              int // JWKS cache TTL in minutes
+	TokenRevocationHours             int // Hours a signed-out user's tokens stay revoked; at least the access token lifetime
 	RateLimiterCleanupIntervalMinutes int // Rate limiter cleanup interval in minutes
 	RateLimiterVisitorTimeoutMinutes  int // Rate limiter visitor timeout in minutes
 	RateLimiterMaxVisitors           int // Maximum number of tracked visitors
 
 	// OAuth Configuration
-	OAuthStateTTLMinutes int // OAuth state store TTL in minutes
+	OAuthStateTTLMinutes int    // OAuth state store TTL in minutes
+	TokenEncryptionKey   string // Base64 32-byte key that encrypts stored Jira tokens; tokens are stored in plaintext without it
+	// Retired base64 32-byte keys that still decrypt tokens until they're re-encrypted on startup
+	TokenEncryptionPreviousKeys []string
 
 	// Jira Configuration
-	JiraMaxUsersPagination int // Maximum users to fetch in Jira pagination
+	JiraMaxUsersPagination  int     // Maximum users to fetch in Jira pagination
+	JiraStoryPointsField    string  // Custom field holding story point estimates
+	JiraWebhookSecret       string  // Secret configured on the Jira webhook; webhooks are rejected without it
+	JiraSyncIntervalMinutes int     // How often mapped users' open issues are resynced into the local cache
+	SprintPointsPerDay      float64 // Default story points one person completes per working day
+
+	// Company calendar
+	CompanyHolidays []string // Company holidays as YYYY-MM-DD dates
+	WorkHoursPerDay float64  // Hours in a working day, the capacity logged Jira time is compared with
+
+	// Manager notes
+	ManagerNotesAdminAudit bool // Lets admins read every supervisor's private notes about a user; each read is audit logged
+
+	// Kudos
+	CompanyValues []string // Company values kudos may be tagged with; any tag is accepted when empty
+
+	// Onboarding
+	OnboardingITDepartment string // Department IT onboarding tasks are assigned to
+
+	// Slack Configuration
+	SlackWebhookURL string // Incoming webhook for capacity warnings and Jira alerts (optional)
+
+	// SCIM provisioning
+	SCIMToken string // Bearer token identity providers send to /scim/v2; provisioning is disabled without it
+
+	// Self-service signup. When enabled, people who sign in without an account or invitation aren't
+	// given one; those whose email is at one of an organization's invitation domains can request
+	// access, which an admin approves.
+	AccessRequestsEnabled bool
+
+	// Hosted deployments shared by several companies. Requests and jobs that aren't scoped to an
+	// organization then see no organization's data, rather than every organization's.
+	MultiTenant bool
+
+	// Load balancers and CDNs in front of the server, as IP addresses and CIDR ranges. Client IPs
+	// and countries are only read from X-Forwarded-For, X-Real-IP, and GeoCountryHeader on requests
+	// they pass on; every other request's client is its peer address.
+	TrustedProxies []string
+
+	// Network restrictions, on top of each organization's own IP allowlist. Countries can only be
+	// enforced behind a trusted proxy that sets GeoCountryHeader.
+	IPAllowlist      []string // IP addresses and CIDR ranges every request must come from; any network when empty
+	AllowedCountries []string // ISO 3166 country codes requests may come from; any country when empty
+	GeoCountryHeader string   // Header the CDN or load balancer sets to the client's country code
+
+	// CSRF protection for requests browsers authenticate with cookies, such as OAuth callbacks;
+	// requests with a bearer token are exempt
+	CSRFEnabled     bool
+	CSRFSecret      string   // Base64 key of at least 32 bytes that signs tokens; a random one is used until restart without it
+	CSRFCookieName  string
+	CSRFExemptPaths []string // Path prefixes that don't need a token, on top of the Jira webhook and invitation links
 
 	// External Service Timeouts (in seconds)
 	ExternalAPITimeoutSecs int // Default timeout for external API calls (Auth0, Jira, etc.)
@@ -84,6 +188,13 @@ type Config struct {
 	ResendFromEmail string
 	ResendFromName  string
 	ResendEnabled   bool
+
+	// Supervisor Digest Configuration
+	SupervisorDigestFrequency string // "daily" or "weekly" (sent on Mondays); digests aren't sent otherwise
+	SupervisorDigestHour      int    // Hour of the day, in UTC, digests are sent from
+
+	// Workload Snapshot Configuration
+	WorkloadSnapshotHour int // Hour of the day, in UTC, everyone's workload is snapshotted from
 }
 
 // IsProduction returns true if running in production mode
@@ -91,16 +202,67 @@ func (c *Config) IsProduction() bool {
 	return c.Environment == "production"
 }
 
+// HasReadReplica returns true if heavy read paths are served by a read replica
+func (c *Config) HasReadReplica() bool {
+	return c.DatabaseReplicaURL != ""
+}
+
+// IsTracingEnabled returns true if traces are exported to an OTLP endpoint
+func (c *Config) IsTracingEnabled() bool {
+	return c.TracingOTLPEndpoint != ""
+}
+
 // IsAuth0MgmtEnabled returns true if Auth0 Management API is configured
 func (c *Config) IsAuth0MgmtEnabled() bool {
 	return c.Auth0MgmtClientID != "" && c.Auth0MgmtClientSecret != ""
 }
 
+// IsAuth0Enabled returns true if Auth0 is configured to sign users in
+func (c *Config) IsAuth0Enabled() bool {
+	return c.Auth0Domain != ""
+}
+
+// IsOIDCEnabled returns true if a generic OIDC provider is configured to sign users in
+func (c *Config) IsOIDCEnabled() bool {
+	return c.OIDCIssuerURL != ""
+}
+
+// IsSAMLBrokerEnabled returns true if a SAML broker is configured to sign users in
+func (c *Config) IsSAMLBrokerEnabled() bool {
+	return c.SAMLBrokerIssuerURL != ""
+}
+
+// IsSlackEnabled returns true if a Slack webhook is configured
+func (c *Config) IsSlackEnabled() bool {
+	return c.SlackWebhookURL != ""
+}
+
+// IsSCIMEnabled returns true if identity providers can provision users over SCIM
+func (c *Config) IsSCIMEnabled() bool {
+	return c.SCIMToken != ""
+}
+
 // IsJiraOAuthEnabled returns true if Jira OAuth is configured
 func (c *Config) IsJiraOAuthEnabled() bool {
 	return c.JiraClientID != "" && c.JiraClientSecret != ""
 }
 
+// IsGitHubOAuthEnabled returns true if the GitHub App's OAuth credentials are configured
+func (c *Config) IsGitHubOAuthEnabled() bool {
+	return c.GitHubClientID != "" && c.GitHubClientSecret != ""
+}
+
+// IsSlackOAuthEnabled returns true if the Slack app's OAuth credentials are configured
+func (c *Config) IsSlackOAuthEnabled() bool {
+	return c.SlackClientID != "" && c.SlackClientSecret != ""
+}
+
+// IsSupervisorDigestEnabled returns true if supervisors are emailed a digest of what needs their
+// attention, which also needs Resend
+func (c *Config) IsSupervisorDigestEnabled() bool {
+	return c.SupervisorDigestFrequency != "" && c.IsResendEnabled()
+}
+
 // IsResendEnabled returns true if Resend email service is configured
 func (c *Config) IsResendEnabled() bool {
 	return c.ResendEnabled && c.ResendAPIKey != ""
@@ -125,6 +287,16 @@ func Load() (*Config, error) {
 		Auth0MgmtClientID:     os.Getenv("AUTH0_MGMT_CLIENT_ID"),
 		Auth0MgmtClientSecret: os.Getenv("AUTH0_MGMT_CLIENT_SECRET"),
 		Auth0DBConnection:     getEnv("AUTH0_DB_CONNECTION", "Username-Password-Authentication"),
+		Auth0WebhookToken:     os.Getenv("AUTH0_WEBHOOK_TOKEN"),
+
+		// Generic OIDC and SAML broker configuration
+		OIDCProviderName:    getEnv("OIDC_PROVIDER_NAME", "oidc"),
+		OIDCIssuerURL:       os.Getenv("OIDC_ISSUER_URL"),
+		OIDCAudience:        os.Getenv("OIDC_AUDIENCE"),
+		OIDCJWKSURL:         os.Getenv("OIDC_JWKS_URL"),
+		SAMLBrokerIssuerURL: os.Getenv("SAML_BROKER_ISSUER_URL"),
+		SAMLBrokerAudience:  os.Getenv("SAML_BROKER_AUDIENCE"),
+		SAMLBrokerJWKSURL:   os.Getenv("SAML_BROKER_JWKS_URL"),
 
 		// S3 Configuration
 		S3Enabled:         s3Enabled,
@@ -134,12 +306,30 @@ func Load() (*Config, error) {
 		S3SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
 		S3Endpoint:        os.Getenv("S3_ENDPOINT"),
 		S3PublicURL:       os.Getenv("S3_PUBLIC_URL"),
+		S3TenantID:        getEnv("S3_TENANT_ID", "default"),
+		AvatarURLMode:       getEnv("AVATAR_URL_MODE", "public"),
+		AvatarCDNURL:        os.Getenv("AVATAR_CDN_URL"),
+		AvatarURLTTLMinutes: getEnvInt("AVATAR_URL_TTL_MINUTES", 60), // 1 hour default
+		GravatarEnabled:     os.Getenv("GRAVATAR_ENABLED") == "true",
 
 		// Jira OAuth Configuration
 		JiraClientID:     os.Getenv("JIRA_CLIENT_ID"),
 		JiraClientSecret: os.Getenv("JIRA_CLIENT_SECRET"),
 		JiraCallbackURL:  getEnv("JIRA_CALLBACK_URL", "http://localhost:3000/api/jira/callback"),
 
+		// GitHub App OAuth Configuration
+		GitHubClientID:     os.Getenv("GITHUB_CLIENT_ID"),
+		GitHubClientSecret: os.Getenv("GITHUB_CLIENT_SECRET"),
+		GitHubCallbackURL:  getEnv("GITHUB_CALLBACK_URL", "http://localhost:3000/api/github/oauth/callback"),
+		GitHubAPIURL:       getEnv("GITHUB_API_URL", "https://api.github.com"),
+
+		// Slack App OAuth Configuration
+		SlackClientID:      os.Getenv("SLACK_CLIENT_ID"),
+		SlackClientSecret:  os.Getenv("SLACK_CLIENT_SECRET"),
+		SlackSigningSecret: os.Getenv("SLACK_SIGNING_SECRET"),
+		SlackCallbackURL:   getEnv("SLACK_CALLBACK_URL", "http://localhost:3000/api/slack/oauth/callback"),
+		SlackAPIURL:        getEnv("SLACK_API_URL", "https://slack.com/api"),
+
 		// Server Configuration
 		RateLimitRPS:     getEnvFloat("RATE_LIMIT_RPS", 100),
 		RateLimitBurst:   getEnvInt("RATE_LIMIT_BURST", 200),
@@ -148,11 +338,17 @@ func Load() (*Config, error) {
 		WriteTimeout:     getEnvInt("WRITE_TIMEOUT_SECS", 30),
 		IdleTimeout:      getEnvInt("IDLE_TIMEOUT_SECS", 120),
 		ShutdownTimeout:  getEnvInt("SHUTDOWN_TIMEOUT_SECS", 30),
+		// Leaves the rest of the shutdown timeout for closing connections and flushing traces
+		WorkerDrainTimeout: getEnvInt("WORKER_DRAIN_TIMEOUT_SECS", 10),
 
 		// Logging Configuration
 		LogLevel:  getEnv("LOG_LEVEL", "info"),
 		LogFormat: getEnv("LOG_FORMAT", "text"),
 
+		// Tracing Configuration
+		TracingOTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", ""),
+		TracingSampleRatio:  getEnvFloat("TRACING_SAMPLE_RATIO", 1.0), // Record every trace by default
+
 		// Database Pool Configuration
 		DBMaxConns:             getEnvInt("DB_MAX_CONNS", 25),
 		DBMinConns:             getEnvInt("DB_MIN_CONNS", 5),
@@ -160,23 +356,75 @@ func Load() (*Config, error) {
 		DBMaxConnIdleTime:      getEnvInt("DB_MAX_CONN_IDLE_TIME_SECS", 1800), // 30 minutes
 		DBHealthCheckPeriod:    getEnvInt("DB_HEALTH_CHECK_PERIOD_SECS", 60),  // 1 minute
 		DBSlowQueryThresholdMS: getEnvInt("DB_SLOW_QUERY_THRESHOLD_MS", 100),  // 100ms
+		DatabaseReplicaURL:     os.Getenv("DATABASE_REPLICA_URL"),
 
 		// Application Configuration
-		AvatarMaxSizeMB:      getEnvInt("AVATAR_MAX_SIZE_MB", 5),       // 5MB default
-		InvitationExpiryDays: getEnvInt("INVITATION_EXPIRY_DAYS", 7),  // 7 days default
-		CacheTTLSeconds:      getEnvInt("CACHE_TTL_SECONDS", 300),     // 5 minutes default
+		AvatarMaxSizeMB:            getEnvInt("AVATAR_MAX_SIZE_MB", 5),              // 5MB default
+		MeetingAttachmentMaxSizeMB: getEnvInt("MEETING_ATTACHMENT_MAX_SIZE_MB", 10), // 10MB default
+		MeetingAttachmentURLTTLMin: getEnvInt("MEETING_ATTACHMENT_URL_TTL_MINUTES", 15), // 15 minutes default
+		InvitationExpiryDays:       getEnvInt("INVITATION_EXPIRY_DAYS", 7),          // 7 days default
+		CacheTTLSeconds:            getEnvInt("CACHE_TTL_SECONDS", 300),             // 5 minutes default
+		ExportRetentionHours:       getEnvInt("EXPORT_RETENTION_HOURS", 72),         // 3 days default
+		ExportURLTTLMinutes:        getEnvInt("EXPORT_URL_TTL_MINUTES", 15),         // 15 minutes default
+		ResponseCacheTTLSeconds:    getEnvInt("RESPONSE_CACHE_TTL_SECONDS", 30),     // 30 seconds default
+		ResponseCacheStaleSeconds:  getEnvInt("RESPONSE_CACHE_STALE_SECONDS", 300), // 5 minutes default
+		SoftDeleteRetentionDays:    getEnvInt("SOFT_DELETE_RETENTION_DAYS", 30),    // 30 days default
 
 		// Security Configuration
 		JWKSCacheTTLMinutes:               getEnvInt("JWKS_CACHE_TTL_MINUTES", 5),               // 5 minutes default
+		TokenRevocationHours:              getEnvInt("TOKEN_REVOCATION_HOURS", 24),              // Auth0's default access token lifetime
 		RateLimiterCleanupIntervalMinutes: getEnvInt("RATE_LIMITER_CLEANUP_INTERVAL_MINUTES", 1), // 1 minute default
 		RateLimiterVisitorTimeoutMinutes:  getEnvInt("RATE_LIMITER_VISITOR_TIMEOUT_MINUTES", 3),  // 3 minutes default
 		RateLimiterMaxVisitors:            getEnvInt("RATE_LIMITER_MAX_VISITORS", 10000),         // 10000 default
 
 		// OAuth Configuration
-		OAuthStateTTLMinutes: getEnvInt("OAUTH_STATE_TTL_MINUTES", 10), // 10 minutes default
+		OAuthStateTTLMinutes:        getEnvInt("OAUTH_STATE_TTL_MINUTES", 10), // 10 minutes default
+		TokenEncryptionKey:          os.Getenv("TOKEN_ENCRYPTION_KEY"),
+		TokenEncryptionPreviousKeys: getEnvList("TOKEN_ENCRYPTION_PREVIOUS_KEYS"),
 
 		// Jira Configuration
-		JiraMaxUsersPagination: getEnvInt("JIRA_MAX_USERS_PAGINATION", 1000), // 1000 default
+		JiraMaxUsersPagination:  getEnvInt("JIRA_MAX_USERS_PAGINATION", 1000),           // 1000 default
+		JiraStoryPointsField:    getEnv("JIRA_STORY_POINTS_FIELD", "customfield_10016"), // Jira Cloud "Story point estimate"
+		JiraWebhookSecret:       os.Getenv("JIRA_WEBHOOK_SECRET"),
+		JiraSyncIntervalMinutes: getEnvInt("JIRA_SYNC_INTERVAL_MINUTES", 15), // 15 minutes default
+		SprintPointsPerDay:      getEnvFloat("SPRINT_POINTS_PER_DAY", 1.0),   // 1 point per person-day default
+
+		// Company calendar
+		CompanyHolidays: getEnvList("COMPANY_HOLIDAYS"),
+		WorkHoursPerDay: getEnvFloat("WORK_HOURS_PER_DAY", 8), // 8 hour days default
+
+		// Manager notes
+		ManagerNotesAdminAudit: os.Getenv("MANAGER_NOTES_ADMIN_AUDIT") == "true",
+
+		// Kudos
+		CompanyValues: getEnvList("COMPANY_VALUES"),
+
+		// Onboarding
+		OnboardingITDepartment: getEnv("ONBOARDING_IT_DEPARTMENT", "IT"),
+
+		// Slack Configuration
+		SlackWebhookURL: os.Getenv("SLACK_WEBHOOK_URL"),
+
+		// SCIM provisioning
+		SCIMToken: os.Getenv("SCIM_TOKEN"),
+
+		// Self-service signup
+		AccessRequestsEnabled: os.Getenv("ACCESS_REQUESTS_ENABLED") == "true",
+
+		// Multi-tenancy
+		MultiTenant: os.Getenv("MULTI_TENANT") == "true",
+
+		// Network restrictions
+		TrustedProxies:   getEnvList("TRUSTED_PROXIES"),
+		IPAllowlist:      getEnvList("IP_ALLOWLIST"),
+		AllowedCountries: getEnvList("ALLOWED_COUNTRIES"),
+		GeoCountryHeader: getEnv("GEO_COUNTRY_HEADER", "CF-IPCountry"), // Cloudflare's default
+
+		// CSRF protection
+		CSRFEnabled:     os.Getenv("CSRF_ENABLED") != "false",
+		CSRFSecret:      os.Getenv("CSRF_SECRET"),
+		CSRFCookieName:  getEnv("CSRF_COOKIE_NAME", "api_csrf"), // Not the frontend's csrf_token, which shares the host
+		CSRFExemptPaths: getEnvList("CSRF_EXEMPT_PATHS"),
 
 		// External Service Timeouts
 		ExternalAPITimeoutSecs: getEnvInt("EXTERNAL_API_TIMEOUT_SECS", 30),  // 30 seconds default
@@ -189,6 +437,13 @@ func Load() (*Config, error) {
 		ResendAPIKey:    os.Getenv("RESEND_API_KEY"),
 		ResendFromEmail: getEnv("RESEND_FROM_EMAIL", "noreply@example.com"),
 		ResendFromName:  getEnv("RESEND_FROM_NAME", "Manager Dashboard"),
+
+		// Supervisor Digest Configuration
+		SupervisorDigestFrequency: os.Getenv("SUPERVISOR_DIGEST_FREQUENCY"),
+		SupervisorDigestHour:      getEnvInt("SUPERVISOR_DIGEST_HOUR", 13), // 13:00 UTC, morning in the Americas
+
+		// Workload Snapshot Configuration
+		WorkloadSnapshotHour: getEnvInt("WORKLOAD_SNAPSHOT_HOUR", 6), // 06:00 UTC, night in the Americas
 	}
 
 	// Validate required configuration
@@ -204,12 +459,18 @@ func (c *Config) Validate() error {
 	if c.DatabaseURL == "" {
 		return fmt.Errorf("DATABASE_URL is required")
 	}
-	if c.Auth0Domain == "" {
-		return fmt.Errorf("AUTH0_DOMAIN is required")
+	if !c.IsAuth0Enabled() && !c.IsOIDCEnabled() && !c.IsSAMLBrokerEnabled() {
+		return fmt.Errorf("AUTH0_DOMAIN, OIDC_ISSUER_URL, or SAML_BROKER_ISSUER_URL is required")
 	}
-	if c.Auth0Audience == "" {
+	if c.IsAuth0Enabled() && c.Auth0Audience == "" {
 		return fmt.Errorf("AUTH0_AUDIENCE is required")
 	}
+	if c.IsOIDCEnabled() && c.OIDCAudience == "" {
+		return fmt.Errorf("OIDC_AUDIENCE is required when OIDC_ISSUER_URL is set")
+	}
+	if c.IsSAMLBrokerEnabled() && c.SAMLBrokerAudience == "" {
+		return fmt.Errorf("SAML_BROKER_AUDIENCE is required when SAML_BROKER_ISSUER_URL is set")
+	}
 
 	// Production-specific validation
 	if c.IsProduction() {
@@ -218,6 +479,32 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	for _, holiday := range c.CompanyHolidays {
+		if _, err := time.Parse("2006-01-02", holiday); err != nil {
+			return fmt.Errorf("COMPANY_HOLIDAYS must be comma-separated YYYY-MM-DD dates, got %q", holiday)
+		}
+	}
+
+	for _, entry := range c.IPAllowlist {
+		if _, err := netip.ParsePrefix(entry); err != nil {
+			if _, err := netip.ParseAddr(entry); err != nil {
+				return fmt.Errorf("IP_ALLOWLIST must be comma-separated IP addresses or CIDR ranges, got %q", entry)
+			}
+		}
+	}
+	for _, entry := range c.TrustedProxies {
+		if _, err := netip.ParsePrefix(entry); err != nil {
+			if _, err := netip.ParseAddr(entry); err != nil {
+				return fmt.Errorf("TRUSTED_PROXIES must be comma-separated IP addresses or CIDR ranges, got %q", entry)
+			}
+		}
+	}
+	for _, country := range c.AllowedCountries {
+		if len(country) != 2 {
+			return fmt.Errorf("ALLOWED_COUNTRIES must be comma-separated two-letter country codes, got %q", country)
+		}
+	}
+
 	// S3 validation
 	if c.S3Enabled {
 		if c.S3Bucket == "" {
@@ -230,10 +517,80 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("S3_SECRET_ACCESS_KEY is required when S3 is enabled")
 		}
 	}
+	switch c.AvatarURLMode {
+	case "public":
+	case "signed":
+		if !c.S3Enabled {
+			return fmt.Errorf("AVATAR_URL_MODE=signed requires S3 to be enabled")
+		}
+		if c.AvatarURLTTLMinutes <= 0 {
+			return fmt.Errorf("AVATAR_URL_TTL_MINUTES must be positive")
+		}
+	case "cdn":
+		if c.AvatarCDNURL == "" {
+			return fmt.Errorf("AVATAR_CDN_URL is required when AVATAR_URL_MODE is cdn")
+		}
+	default:
+		return fmt.Errorf("AVATAR_URL_MODE must be public, signed, or cdn, got %q", c.AvatarURLMode)
+	}
+	if c.TokenEncryptionKey != "" {
+		if key, err := base64.StdEncoding.DecodeString(c.TokenEncryptionKey); err != nil || len(key) != 32 {
+			return fmt.Errorf("TOKEN_ENCRYPTION_KEY must be a base64-encoded 32-byte key")
+		}
+	}
+	if len(c.TokenEncryptionPreviousKeys) > 0 && c.TokenEncryptionKey == "" {
+		return fmt.Errorf("TOKEN_ENCRYPTION_PREVIOUS_KEYS requires TOKEN_ENCRYPTION_KEY")
+	}
+	for _, previous := range c.TokenEncryptionPreviousKeys {
+		if key, err := base64.StdEncoding.DecodeString(previous); err != nil || len(key) != 32 {
+			return fmt.Errorf("TOKEN_ENCRYPTION_PREVIOUS_KEYS must be base64-encoded 32-byte keys")
+		}
+	}
+	if c.CSRFSecret != "" {
+		if key, err := base64.StdEncoding.DecodeString(c.CSRFSecret); err != nil || len(key) < 32 {
+			return fmt.Errorf("CSRF_SECRET must be a base64-encoded key of at least 32 bytes")
+		}
+	}
+	if c.WorkerDrainTimeout <= 0 || c.WorkerDrainTimeout > c.ShutdownTimeout {
+		return fmt.Errorf("WORKER_DRAIN_TIMEOUT_SECS must be positive and no longer than SHUTDOWN_TIMEOUT_SECS")
+	}
+	switch c.SupervisorDigestFrequency {
+	case "", "daily", "weekly":
+	default:
+		return fmt.Errorf("SUPERVISOR_DIGEST_FREQUENCY must be daily or weekly, got %q", c.SupervisorDigestFrequency)
+	}
+	if c.SupervisorDigestHour < 0 || c.SupervisorDigestHour > 23 {
+		return fmt.Errorf("SUPERVISOR_DIGEST_HOUR must be between 0 and 23")
+	}
+	if c.WorkloadSnapshotHour < 0 || c.WorkloadSnapshotHour > 23 {
+		return fmt.Errorf("WORKLOAD_SNAPSHOT_HOUR must be between 0 and 23")
+	}
+	if c.SoftDeleteRetentionDays <= 0 {
+		return fmt.Errorf("SOFT_DELETE_RETENTION_DAYS must be positive")
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+	if !isValidKeySegment(c.S3TenantID) {
+		return fmt.Errorf("S3_TENANT_ID must contain only letters, digits, '-' or '_', got %q", c.S3TenantID)
+	}
 
 	return nil
 }
 
+// isValidKeySegment reports whether s can be used as a single storage key path segment
+func isValidKeySegment(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -241,6 +598,16 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+func getEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 func getEnvInt(key string, fallback int) int {
 	if value := os.Getenv(key); value != "" {
 		if intVal, err := parseInt(value); err == nil {
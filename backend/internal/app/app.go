@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -18,62 +20,166 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 	"github.com/smith-dallin/manager-dashboard/config"
 	"github.com/smith-dallin/manager-dashboard/internal/auth0"
+	"github.com/smith-dallin/manager-dashboard/internal/cache"
+	"github.com/smith-dallin/manager-dashboard/internal/crypto"
 	"github.com/smith-dallin/manager-dashboard/internal/database"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/github"
 	"github.com/smith-dallin/manager-dashboard/internal/graph"
 	"github.com/smith-dallin/manager-dashboard/internal/handlers"
+	"github.com/smith-dallin/manager-dashboard/internal/identity"
 	"github.com/smith-dallin/manager-dashboard/internal/jira"
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
 	"github.com/smith-dallin/manager-dashboard/internal/logger"
 	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/oauth"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
 	"github.com/smith-dallin/manager-dashboard/internal/services"
+	"github.com/smith-dallin/manager-dashboard/internal/slack"
 	"github.com/smith-dallin/manager-dashboard/internal/storage"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+	"github.com/smith-dallin/manager-dashboard/internal/tracing"
 	"golang.org/x/time/rate"
 )
 
+// metricsNamespace prefixes every Prometheus metric the application exports
+const metricsNamespace = "manager_dashboard"
+
 // App encapsulates all application dependencies and provides methods to run and shut down the server
 type App struct {
 	Config *config.Config
 	Logger *logger.Logger
 	DB     *pgxpool.Pool
-	Router chi.Router
-	Server *http.Server
+	// ReplicaDB serves heavy read paths when a read replica is configured, and is nil otherwise
+	ReplicaDB *pgxpool.Pool
+	Router    chi.Router
+	Server    *http.Server
 
 	// Repositories
-	userRepo       *database.UserRepository
-	squadRepo      *database.SquadRepository
-	departmentRepo *database.DepartmentRepository
-	invitationRepo *database.InvitationRepository
-	orgJiraRepo    *database.OrgJiraRepository
-	orgChartRepo   *database.OrgChartRepository
-	timeOffRepo    *database.TimeOffRepository
-	taskRepo       *database.TaskRepository
-	meetingRepo    *database.MeetingRepository
+	userRepo              *database.UserRepository
+	squadRepo             *database.SquadRepository
+	departmentRepo        *database.DepartmentRepository
+	customFieldRepo       *database.CustomFieldRepository
+	skillRepo             *database.SkillRepository
+	managerNoteRepo       *database.ManagerNoteRepository
+	goalRepo              *database.GoalRepository
+	kudosRepo             *database.KudosRepository
+	onboardingRepo        *database.OnboardingRepository
+	userHistoryRepo       *database.UserHistoryRepository
+	auditEventRepo        *database.AuditEventRepository
+	customRoleRepo        *database.CustomRoleRepository
+	sessionRepo           *database.SessionRepository
+	impersonationRepo     *database.ImpersonationRepository
+	organizationRepo      *database.OrganizationRepository
+	invitationRepo        *database.InvitationRepository
+	orgJiraRepo           *database.OrgJiraRepository
+	orgChartRepo          *database.OrgChartRepository
+	timeOffRepo           *database.TimeOffRepository
+	taskRepo              *database.TaskRepository
+	meetingRepo           *database.MeetingRepository
+	meetingAttachmentRepo *database.MeetingAttachmentRepository
+	taskCommentRepo       *database.TaskCommentRepository
+	meetingNotesRepo      *database.MeetingNotesRepository
+	notificationRepo      *database.NotificationRepository
+	workScheduleRepo      *database.WorkScheduleRepository
+	exportJobRepo         *database.ExportJobRepository
+	webhookRepo           *database.WebhookRepository
+	outboxRepo            *database.OutboxRepository
+	supervisorDigestRepo  *database.SupervisorDigestRepository
+	emailTemplateRepo     *database.EmailTemplateRepository
+	reportRepo            *database.ReportRepository
+	adminStatsRepo        *database.AdminStatsRepository
+	workloadSnapshotRepo  *database.WorkloadSnapshotRepository
+	savedViewRepo         *database.SavedViewRepository
+	accessRequestRepo     *database.AccessRequestRepository
+	profileChangeRepo     *database.ProfileChangeRepository
+	jiraIssueCacheRepo    *database.JiraIssueCacheRepository
+	orgGitHubRepo         *database.OrgGitHubRepository
+	orgSlackRepo          *database.OrgSlackRepository
+	orgLinearRepo         *database.OrgLinearRepository
 
 	// Handlers
-	handlers           *handlers.Handlers
-	avatarHandlers     *handlers.AvatarHandlers
-	invitationHandlers *handlers.InvitationHandlers
-	jiraHandlers       *handlers.JiraHandlers
-	orgChartHandlers   *handlers.OrgChartHandlers
-	timeOffHandlers    *handlers.TimeOffHandlers
-	calendarHandlers   *handlers.CalendarHandlers
+	handlers                  *handlers.Handlers
+	avatarHandlers            *handlers.AvatarHandlers
+	invitationHandlers        *handlers.InvitationHandlers
+	jiraHandlers              *handlers.JiraHandlers
+	gitHubHandlers            *handlers.GitHubHandlers
+	slackHandlers             *handlers.SlackHandlers
+	linearHandlers            *handlers.LinearHandlers
+	workItemHandlers          *handlers.WorkItemHandlers
+	orgChartHandlers          *handlers.OrgChartHandlers
+	timeOffHandlers           *handlers.TimeOffHandlers
+	calendarHandlers          *handlers.CalendarHandlers
+	meetingAttachmentHandlers *handlers.MeetingAttachmentHandlers
+	meetingICSHandlers        *handlers.MeetingICSHandlers
+	searchHandlers            *handlers.SearchHandlers
+	exportHandlers            *handlers.ExportHandlers
+	workScheduleHandlers      *handlers.WorkScheduleHandlers
+	skillHandlers             *handlers.SkillHandlers
+	managerNoteHandlers       *handlers.ManagerNoteHandlers
+	goalHandlers              *handlers.GoalHandlers
+	kudosHandlers             *handlers.KudosHandlers
+	notificationHandlers      *handlers.NotificationHandlers
+	onboardingHandlers        *handlers.OnboardingHandlers
+	webhookHandlers           *handlers.WebhookHandlers
+	auditHandlers             *handlers.AuditHandlers
+	roleHandlers              *handlers.RoleHandlers
+	sessionHandlers           *handlers.SessionHandlers
+	impersonationHandlers     *handlers.ImpersonationHandlers
+	ipAllowlistHandlers       *handlers.IPAllowlistHandlers
+	orgSettingsHandlers       *handlers.OrgSettingsHandlers
+	emailTemplateHandlers     *handlers.EmailTemplateHandlers
+	reportHandlers            *handlers.ReportHandlers
+	adminStatsHandlers        *handlers.AdminStatsHandlers
+	workloadHandlers          *handlers.WorkloadHandlers
+	savedViewHandlers         *handlers.SavedViewHandlers
+	accessRequestHandlers     *handlers.AccessRequestHandlers
+	auth0WebhookHandlers      *handlers.Auth0WebhookHandlers
+	scimHandlers              *handlers.SCIMHandlers
 
 	// Services
-	avatarService      *services.AvatarService
-	calendarBFFService *services.CalendarBFFService
-	emailService       *services.EmailService
-	jiraOAuthService   *jira.OAuthService
-	oauthStateStore    oauth.StateStore
+	authorizationService     *services.AuthorizationService
+	avatarService            *services.AvatarService
+	meetingAttachmentService *services.MeetingAttachmentService
+	calendarBFFService       *services.CalendarBFFService
+	sprintCapacityService    *services.SprintCapacityService
+	jiraHealthService        *services.JiraHealthService
+	notificationService      *services.NotificationService
+	slackAppService          *services.SlackAppService
+	onboardingService        *services.OnboardingService
+	exportService            *services.ExportService
+	webhookService           *services.WebhookService
+	sessionService           *services.SessionService
+	eventBroker              *events.Broker
+	responseCache            *middleware.ResponseCache
+	emailService             *services.EmailService
+	emailTemplateService     *services.EmailTemplateService
+	workloadSnapshotService  *services.WorkloadSnapshotService
+	orgSettingsService       *services.OrgSettingsService
+	jiraOAuthService         *jira.OAuthService
+	gitHubOAuthService       *github.OAuthService
+	slackOAuthService        *slack.OAuthService
+	oauthStateStore          oauth.StateStore
+
+	// Background workers
+	workers     *lifecycle.Group
+	streamsDone chan struct{} // Closed when the server starts shutting down to end SSE streams
 
 	// Auth
 	authMiddleware *middleware.AuthMiddleware
+	csrf           *middleware.CSRF // Nil when CSRF protection is disabled
 	auth0Client    *auth0.ManagementClient
+	auth0Sync      *services.Auth0SyncService // Nil when the Auth0 Management API isn't configured
 
 	// GraphQL
 	graphServer *handler.Server
 
 	// Metrics
 	metrics *middleware.Metrics
+
+	// Tracing
+	shutdownTracing func(context.Context) error // Flushes buffered spans to the OTLP collector
 }
 
 // New creates a new App with all dependencies initialized
@@ -83,6 +189,10 @@ func New(cfg *config.Config, log *logger.Logger) (*App, error) {
 		Logger: log,
 	}
 
+	if err := app.initTracing(); err != nil {
+		return nil, err
+	}
+
 	if err := app.initDatabase(); err != nil {
 		return nil, err
 	}
@@ -113,15 +223,28 @@ func New(cfg *config.Config, log *logger.Logger) (*App, error) {
 	return app, nil
 }
 
+func (a *App) initTracing() error {
+	shutdown, err := tracing.Setup(context.Background(), a.Config)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	a.shutdownTracing = shutdown
+	if a.Config.IsTracingEnabled() {
+		a.Logger.Info("Tracing enabled", "endpoint", a.Config.TracingOTLPEndpoint, "sample_ratio", a.Config.TracingSampleRatio)
+	}
+	return nil
+}
+
 func (a *App) initDatabase() error {
 	// Run migrations first (before creating the connection pool)
 	if err := database.RunMigrations(a.Config.DatabaseURL); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	// Create query tracer for slow query logging
+	// Create query tracer for slow query logging, query spans, and per-query latency metrics
 	slowThreshold := time.Duration(a.Config.DBSlowQueryThresholdMS) * time.Millisecond
-	tracer := database.NewQueryTracer(a.Logger, slowThreshold)
+	tracer := database.NewQueryTracer(a.Logger, slowThreshold).
+		WithMetrics(database.NewQueryMetrics(metricsNamespace))
 
 	// Create pool configuration from app config
 	poolCfg := &database.PoolConfig{
@@ -139,19 +262,109 @@ func (a *App) initDatabase() error {
 	}
 	a.DB = pool
 
+	// Writes always go to the primary; the replica only takes reads that tolerate replication lag
+	if a.Config.HasReadReplica() {
+		replica, err := database.Connect(a.Config.DatabaseReplicaURL, poolCfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect to read replica: %w", err)
+		}
+		a.ReplicaDB = replica
+		a.Logger.Info("Read replica enabled for user lists, org trees, and calendars")
+	}
+
 	return nil
 }
 
+// readDB returns the pool for heavy reads: the replica when configured, otherwise the primary
+func (a *App) readDB() *pgxpool.Pool {
+	if a.ReplicaDB != nil {
+		return a.ReplicaDB
+	}
+	return a.DB
+}
+
 func (a *App) initRepositories() error {
-	a.userRepo = database.NewUserRepository(a.DB)
+	// Shared deployments keep anything that isn't scoped to an organization from seeing them all
+	tenant.Require(a.Config.MultiTenant)
+
+	// Jira tokens are encrypted at rest when a key is configured
+	tokenFields, err := crypto.NewFields(a.Config.TokenEncryptionKey, a.Config.TokenEncryptionPreviousKeys)
+	if err != nil {
+		return fmt.Errorf("failed to initialize token encryption: %w", err)
+	}
+
+	a.userRepo = database.NewUserRepository(a.DB).WithEncryption(tokenFields).WithReplica(a.ReplicaDB)
 	a.squadRepo = database.NewSquadRepository(a.DB)
 	a.departmentRepo = database.NewDepartmentRepository(a.DB)
+	a.customFieldRepo = database.NewCustomFieldRepository(a.DB)
+	a.skillRepo = database.NewSkillRepository(a.DB)
+	a.managerNoteRepo = database.NewManagerNoteRepository(a.DB)
+	a.goalRepo = database.NewGoalRepository(a.DB)
+	a.kudosRepo = database.NewKudosRepository(a.DB)
+	a.onboardingRepo = database.NewOnboardingRepository(a.DB)
+	a.userHistoryRepo = database.NewUserHistoryRepository(a.DB)
+	a.auditEventRepo = database.NewAuditEventRepository(a.DB)
+	a.customRoleRepo = database.NewCustomRoleRepository(a.DB)
+	a.sessionRepo = database.NewSessionRepository(a.DB)
+	a.impersonationRepo = database.NewImpersonationRepository(a.DB)
+	a.organizationRepo = database.NewOrganizationRepository(a.DB)
 	a.invitationRepo = database.NewInvitationRepositoryWithConfig(a.DB, a.Config.InvitationExpiryDays)
-	a.orgJiraRepo = database.NewOrgJiraRepository(a.DB)
-	a.orgChartRepo = database.NewOrgChartRepository(a.DB, a.squadRepo)
+	a.orgJiraRepo = database.NewOrgJiraRepository(a.DB).WithEncryption(tokenFields)
+	a.orgChartRepo = database.NewOrgChartRepository(a.DB, a.squadRepo).WithReplica(a.ReplicaDB)
 	a.timeOffRepo = database.NewTimeOffRepository(a.DB)
 	a.taskRepo = database.NewTaskRepository(a.DB)
 	a.meetingRepo = database.NewMeetingRepository(a.DB)
+	a.meetingAttachmentRepo = database.NewMeetingAttachmentRepository(a.DB)
+	a.taskCommentRepo = database.NewTaskCommentRepository(a.DB)
+	a.meetingNotesRepo = database.NewMeetingNotesRepository(a.DB)
+	a.notificationRepo = database.NewNotificationRepository(a.DB)
+	a.workScheduleRepo = database.NewWorkScheduleRepository(a.DB)
+	a.exportJobRepo = database.NewExportJobRepository(a.DB)
+	a.webhookRepo = database.NewWebhookRepository(a.DB)
+	a.outboxRepo = database.NewOutboxRepository(a.DB)
+	a.supervisorDigestRepo = database.NewSupervisorDigestRepository(a.DB)
+	a.emailTemplateRepo = database.NewEmailTemplateRepository(a.DB)
+	a.reportRepo = database.NewReportRepository(a.DB)
+	a.adminStatsRepo = database.NewAdminStatsRepository(a.DB)
+	a.workloadSnapshotRepo = database.NewWorkloadSnapshotRepository(a.DB)
+	a.savedViewRepo = database.NewSavedViewRepository(a.DB)
+	a.accessRequestRepo = database.NewAccessRequestRepository(a.DB)
+	a.profileChangeRepo = database.NewProfileChangeRepository(a.DB)
+	a.jiraIssueCacheRepo = database.NewJiraIssueCacheRepository(a.DB)
+	a.orgGitHubRepo = database.NewOrgGitHubRepository(a.DB)
+	a.orgSlackRepo = database.NewOrgSlackRepository(a.DB).WithEncryption(tokenFields)
+	a.orgLinearRepo = database.NewOrgLinearRepository(a.DB)
+
+	if !tokenFields.Enabled() {
+		a.Logger.Warn("TOKEN_ENCRYPTION_KEY not set - Jira tokens are stored in plaintext")
+		return nil
+	}
+	return a.encryptStoredTokens()
+}
+
+// encryptStoredTokens encrypts Jira and Slack tokens saved before encryption was enabled, and re-encrypts
+// those encrypted with a previous key so it can be retired. It's safe to run on every start since
+// tokens encrypted with the current key are skipped.
+func (a *App) encryptStoredTokens() error {
+	ctx := tenant.AcrossOrgs(context.Background())
+	orgTokens, err := a.orgJiraRepo.EncryptStoredTokens(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt stored Jira tokens: %w", err)
+	}
+	userTokens, err := a.userRepo.EncryptStoredJiraTokens(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt stored user Jira tokens: %w", err)
+	}
+	if orgTokens+userTokens > 0 {
+		a.Logger.Info("Encrypted stored Jira tokens", "org_connections", orgTokens, "users", userTokens)
+	}
+	slackTokens, err := a.orgSlackRepo.EncryptStoredTokens(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt stored Slack tokens: %w", err)
+	}
+	if slackTokens > 0 {
+		a.Logger.Info("Encrypted stored Slack tokens", "org_connections", slackTokens)
+	}
 	return nil
 }
 
@@ -168,7 +381,21 @@ func (a *App) initServices() error {
 		}
 	}
 
-	a.avatarService = services.NewAvatarService(store)
+	// Serve stored avatars through presigned or CDN URLs when configured
+	if store != nil && a.Config.AvatarURLMode != storage.URLModePublic {
+		ttl := time.Duration(a.Config.AvatarURLTTLMinutes) * time.Minute
+		resolver, err := storage.NewURLResolver(store, a.Config.AvatarURLMode, a.Config.AvatarCDNURL, ttl)
+		if err != nil {
+			return fmt.Errorf("failed to configure avatar URLs: %w", err)
+		}
+		models.SetAvatarURLResolver(resolver.Resolve)
+		a.Logger.Info("Avatar URLs resolved", "mode", a.Config.AvatarURLMode)
+	}
+
+	a.authorizationService = services.NewAuthorizationService(a.userRepo)
+	a.avatarService = services.NewAvatarService(store, a.Config.S3TenantID)
+	a.meetingAttachmentService = services.NewMeetingAttachmentService(store, a.Config.S3TenantID,
+		time.Duration(a.Config.MeetingAttachmentURLTTLMin)*time.Minute)
 	if store == nil {
 		a.Logger.Info("Using local file storage for uploads")
 	}
@@ -181,14 +408,55 @@ func (a *App) initServices() error {
 		a.Logger.Info("Jira OAuth not configured - using legacy API token auth only")
 	}
 
-	// Initialize Resend email service (optional)
+	// Initialize GitHub App OAuth service (optional)
+	if a.Config.IsGitHubOAuthEnabled() {
+		a.gitHubOAuthService = github.NewOAuthService(a.Config)
+		a.Logger.Info("GitHub OAuth service initialized")
+	} else {
+		a.Logger.Info("GitHub OAuth not configured - GitHub integration disabled")
+	}
+
+	// Initialize Slack app OAuth service (optional)
+	if a.Config.IsSlackOAuthEnabled() {
+		a.slackOAuthService = slack.NewOAuthService(a.Config)
+		a.Logger.Info("Slack OAuth service initialized")
+	} else {
+		a.Logger.Info("Slack OAuth not configured - Slack app integration disabled")
+	}
+
+	// Initialize Resend email service (optional), sending invitations and notifications with
+	// organizations' customized templates
+	a.emailTemplateService = services.NewEmailTemplateService(a.emailTemplateRepo)
 	if a.Config.IsResendEnabled() {
-		a.emailService = services.NewEmailService(a.Config)
+		a.emailService = services.NewEmailService(a.Config).WithTemplates(a.emailTemplateService)
 		a.Logger.Info("Resend email service initialized")
 	} else {
 		a.Logger.Info("Email service not configured - invitation emails will not be sent")
 	}
 
+	// Slack warnings for sprint capacity checks are optional
+	if !a.Config.IsSlackEnabled() {
+		a.Logger.Info("Slack webhook not configured - sprint capacity warnings and Jira alerts will not be posted")
+	}
+	a.sprintCapacityService = services.NewSprintCapacityService(a.squadRepo, a.timeOffRepo, services.NewSlackNotifier(a.Config), a.Config)
+
+	// Jira refresh failures alert admins over Slack and email, whichever are configured
+	var adminAlerts services.AdminAlertSender
+	if a.emailService != nil {
+		adminAlerts = a.emailService
+	}
+	a.jiraHealthService = services.NewJiraHealthService(a.orgJiraRepo, a.userRepo, services.NewSlackNotifier(a.Config), adminAlerts)
+
+	// In-app notifications are always recorded; the matching emails need Resend
+	var notificationEmails repository.OutboxRepository
+	if a.emailService != nil {
+		notificationEmails = a.outboxRepo
+	}
+	a.notificationService = services.NewNotificationService(a.notificationRepo, a.userRepo, notificationEmails)
+	// Time off DMs are only sent to organizations that installed the Slack app
+	a.slackAppService = services.NewSlackAppService(a.orgSlackRepo, a.userRepo, a.outboxRepo).WithAPIURL(a.Config.SlackAPIURL)
+	a.onboardingService = services.NewOnboardingService(a.onboardingRepo, a.Config.OnboardingITDepartment)
+
 	// Initialize OAuth state store
 	// Use database-backed store in production for horizontal scaling
 	oauthStateTTL := time.Duration(a.Config.OAuthStateTTLMinutes) * time.Minute
@@ -207,20 +475,100 @@ func (a *App) initServices() error {
 		a.Logger.Info("Using in-memory OAuth state store (development mode)")
 	}
 
-	// Initialize Calendar repositories and BFF service
-	calendarRepo := database.NewCalendarRepository(a.taskRepo, a.meetingRepo, a.timeOffRepo)
+	// Initialize Calendar repositories and BFF service. Calendar aggregation only reads, so it runs
+	// against the read replica when one is configured.
+	reads := a.readDB()
+	calendarRepo := database.NewCalendarRepository(database.NewTaskRepository(reads), database.NewMeetingRepository(reads),
+		database.NewTimeOffRepository(reads), database.NewWorkScheduleRepository(reads)).
+		WithUsers(a.userRepo)
 	jiraCalendarClient := jira.NewCalendarJiraClient()
 	a.calendarBFFService = services.NewCalendarBFFService(calendarRepo, a.orgJiraRepo, jiraCalendarClient)
 
+	// In-process broker for live calendar updates
+	a.eventBroker = events.NewBroker(events.DefaultBufferSize)
+
+	// Export jobs are generated in the background; downloads need storage that can sign URLs
+	a.exportService = services.NewExportService(
+		a.exportJobRepo,
+		services.NewUserService(a.userRepo, a.squadRepo).WithCustomFields(a.customFieldRepo),
+		a.timeOffRepo,
+		a.userRepo,
+		store,
+		a.Config.S3TenantID,
+		time.Duration(a.Config.ExportRetentionHours)*time.Hour,
+		time.Duration(a.Config.ExportURLTTLMinutes)*time.Minute,
+	)
+	// Workers run until Shutdown, which gives them a bounded period to finish in-flight work
+	a.workers = lifecycle.NewGroup()
+	a.exportService.Start(a.workers)
+
+	// Users without an uploaded avatar get a generated initials image or their Gravatar
+	services.NewDefaultAvatarService(a.userRepo, store, a.Config.S3TenantID, a.Config.GravatarEnabled).Start(a.workers, a.eventBroker)
+
+	// Lifecycle events are delivered to registered webhook endpoints from a queue, with retries
+	a.webhookService = services.NewWebhookService(a.webhookRepo)
+	a.webhookService.Start(a.workers, a.eventBroker)
+
+	// Emails and Slack messages queued alongside invitations and notifications are sent from the
+	// outbox, with retries
+	outboxService := services.NewOutboxService(a.outboxRepo).WithSlack(a.slackAppService)
+	if a.emailService != nil {
+		outboxService.WithEmailer(a.emailService)
+	}
+	outboxService.Start(a.workers)
+
+	// Supervisors are emailed a daily or weekly digest of what needs their attention
+	if a.Config.IsSupervisorDigestEnabled() {
+		services.NewSupervisorDigestService(a.supervisorDigestRepo, a.userRepo, a.timeOffRepo, a.taskRepo, a.meetingRepo,
+			models.DigestFrequency(a.Config.SupervisorDigestFrequency), a.Config.SupervisorDigestHour).Start(a.workers)
+	}
+
+	// Admins can override these configured defaults for their organization
+	a.orgSettingsService = services.NewOrgSettingsService(a.organizationRepo, map[models.OrgSettingKey]int{
+		models.OrgSettingInvitationExpiryDays:       a.Config.InvitationExpiryDays,
+		models.OrgSettingAvatarMaxSizeMB:            a.Config.AvatarMaxSizeMB,
+		models.OrgSettingMeetingAttachmentMaxSizeMB: a.Config.MeetingAttachmentMaxSizeMB,
+	})
+	a.invitationRepo.WithExpirySetting(a.orgSettingsService)
+
+	// Everyone's open tasks and Jira status counts are snapshotted daily for workload trends
+	a.workloadSnapshotService = services.NewWorkloadSnapshotService(a.workloadSnapshotRepo, a.Config.WorkloadSnapshotHour)
+	a.workloadSnapshotService.Start(a.workers)
+
+	// Sessions are recorded as users authenticate, and revoked tokens are rejected until they expire
+	a.sessionService = services.NewSessionService(a.sessionRepo, time.Duration(a.Config.TokenRevocationHours)*time.Hour)
+	a.sessionService.Start(a.workers)
+
+	// Deleted users, tasks, and meetings can be restored until the retention period passes
+	services.NewPurgeService(a.userRepo, a.taskRepo, a.meetingRepo, time.Duration(a.Config.SoftDeleteRetentionDays)*24*time.Hour).Start(a.workers)
+
+	// Cached read endpoints are invalidated by the domain events their data depends on
+	a.responseCache = middleware.NewResponseCache(cache.New(time.Duration(a.Config.ResponseCacheTTLSeconds)*time.Second, time.Minute), a.workers)
+	a.responseCache.Listen(a.eventBroker)
+
 	return nil
 }
 
 func (a *App) initAuth() error {
-	authMiddleware, err := middleware.NewAuthMiddleware(a.Config.Auth0Domain, a.Config.Auth0Audience, a.userRepo, a.Config.JWKSCacheTTLMinutes)
+	verifier, err := a.newTokenVerifier()
 	if err != nil {
 		return err
 	}
-	a.authMiddleware = authMiddleware
+	a.authMiddleware = middleware.NewAuthMiddleware(verifier, a.userRepo).
+		WithCustomRoles(a.customRoleRepo).
+		WithSessions(a.sessionService).
+		WithImpersonation(a.impersonationRepo).
+		WithIPAllowlists(a.organizationRepo, a.auditEventRepo).
+		WithSignupOrgs(a.invitationRepo, a.organizationRepo)
+	if a.Config.AccessRequestsEnabled {
+		a.authMiddleware.WithSignupApproval()
+	}
+
+	if a.Config.CSRFEnabled {
+		if a.csrf, err = a.newCSRF(); err != nil {
+			return err
+		}
+	}
 
 	// Initialize Auth0 Management API client (optional)
 	if a.Config.IsAuth0MgmtEnabled() {
@@ -233,28 +581,192 @@ func (a *App) initAuth() error {
 	return nil
 }
 
+// newCSRF sets up CSRF protection for cookie-authenticated requests. Without a configured secret
+// tokens are signed with a random key, so they stop working on restart and across instances.
+func (a *App) newCSRF() (*middleware.CSRF, error) {
+	// Config.Validate has checked the secret decodes
+	secret, _ := base64.StdEncoding.DecodeString(a.Config.CSRFSecret)
+	if len(secret) == 0 {
+		a.Logger.Warn("CSRF_SECRET not set - CSRF tokens are signed with a key that changes on restart")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate CSRF secret: %w", err)
+		}
+	}
+	return middleware.NewCSRF(middleware.CSRFConfig{
+		Secret:     secret,
+		CookieName: a.Config.CSRFCookieName,
+		Secure:     a.Config.IsProduction(),
+		// The Jira webhook and Slack interactions are verified by their signatures, and the Auth0 log
+		// stream and invitation links by their token
+		ExemptPaths: append([]string{"/api/jira/webhook", "/api/slack/interactions", "/api/auth0/webhook", "/api/invitations/accept/"}, a.Config.CSRFExemptPaths...),
+	}), nil
+}
+
+// newTokenVerifier trusts tokens from each configured identity provider. Subjects from providers
+// other than Auth0 are prefixed with the provider's name so they can't collide with each other.
+func (a *App) newTokenVerifier() (*identity.Verifier, error) {
+	cacheTTL := time.Duration(a.Config.JWKSCacheTTLMinutes) * time.Minute
+	var configs []identity.ProviderConfig
+	if a.Config.IsAuth0Enabled() {
+		configs = append(configs, identity.ProviderConfig{
+			Name:         "auth0",
+			IssuerURL:    "https://" + a.Config.Auth0Domain + "/",
+			Audience:     a.Config.Auth0Audience,
+			JWKSCacheTTL: cacheTTL,
+		})
+	}
+	if a.Config.IsOIDCEnabled() {
+		configs = append(configs, identity.ProviderConfig{
+			Name:          a.Config.OIDCProviderName,
+			IssuerURL:     a.Config.OIDCIssuerURL,
+			Audience:      a.Config.OIDCAudience,
+			JWKSURL:       a.Config.OIDCJWKSURL,
+			JWKSCacheTTL:  cacheTTL,
+			SubjectPrefix: a.Config.OIDCProviderName + "|",
+		})
+	}
+	if a.Config.IsSAMLBrokerEnabled() {
+		configs = append(configs, identity.ProviderConfig{
+			Name:          "saml",
+			IssuerURL:     a.Config.SAMLBrokerIssuerURL,
+			Audience:      a.Config.SAMLBrokerAudience,
+			JWKSURL:       a.Config.SAMLBrokerJWKSURL,
+			JWKSCacheTTL:  cacheTTL,
+			SubjectPrefix: "saml|",
+		})
+	}
+
+	providers := make([]*identity.Provider, 0, len(configs))
+	for _, cfg := range configs {
+		provider, err := identity.NewProvider(cfg)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+		a.Logger.Info("Identity provider configured", "provider", cfg.Name, "issuer", cfg.IssuerURL)
+	}
+	return identity.NewVerifier(providers...), nil
+}
+
 func (a *App) initHandlers() error {
-	a.handlers = handlers.New(a.userRepo, a.squadRepo, a.departmentRepo)
-	a.avatarHandlers = handlers.NewAvatarHandlersWithConfig(a.userRepo, a.avatarService, a.Config.AvatarMaxSizeMB)
-	a.invitationHandlers = handlers.NewInvitationHandlers(a.invitationRepo, a.userRepo, a.emailService)
-	a.jiraHandlers = handlers.NewJiraHandlersWithConfig(a.userRepo, a.orgJiraRepo, a.timeOffRepo, a.jiraOAuthService, a.oauthStateStore, a.Config.FrontendURL, a.Config.JiraMaxUsersPagination, 0, a.Logger)
-	a.orgChartHandlers = handlers.NewOrgChartHandlers(a.orgChartRepo, a.userRepo)
-	a.timeOffHandlers = handlers.NewTimeOffHandlers(a.timeOffRepo, a.userRepo)
-	a.calendarHandlers = handlers.NewCalendarHandlers(a.calendarBFFService, a.taskRepo, a.meetingRepo)
+	// Deactivating users blocks them in Auth0, and deleting or blocking them in Auth0 deactivates them
+	if a.auth0Client != nil {
+		a.auth0Sync = services.NewAuth0SyncService(a.auth0Client, a.userRepo, a.eventBroker)
+	}
+
+	a.handlers = handlers.NewWithEvents(a.userRepo, a.squadRepo, a.departmentRepo, a.eventBroker).
+		WithCustomFields(a.customFieldRepo).
+		WithHistory(a.userHistoryRepo).
+		WithOnboarding(a.onboardingService).
+		WithSavedViews(a.savedViewRepo).
+		WithAuth0Sync(a.auth0Sync).
+		WithProfileChanges(a.profileChangeRepo).
+		WithNotifications(a.notificationService)
+	a.avatarHandlers = handlers.NewAvatarHandlersWithConfig(a.userRepo, a.avatarService, a.Config.AvatarMaxSizeMB).
+		WithSettings(a.orgSettingsService)
+	a.invitationHandlers = handlers.NewInvitationHandlers(a.invitationRepo, a.userRepo, a.emailService).
+		WithOnboarding(a.onboardingService).
+		WithNotifications(a.notificationService).
+		WithInvitationDomains(a.organizationRepo)
+	// Mapped users' open Jira issues are served from a local cache that a worker keeps fresh
+	jiraIssueSync := services.NewJiraIssueSyncService(a.jiraIssueCacheRepo, a.userRepo, time.Duration(a.Config.JiraSyncIntervalMinutes)*time.Minute)
+	a.jiraHandlers = handlers.NewJiraHandlersWithConfig(a.userRepo, a.orgJiraRepo, a.timeOffRepo, a.jiraOAuthService, a.oauthStateStore, a.Config.FrontendURL, a.Config.JiraMaxUsersPagination, 0, a.sprintCapacityService, a.jiraHealthService, a.Logger).
+		WithWebhookIngestion(a.Config.JiraWebhookSecret, a.jiraIssueCacheRepo, a.eventBroker).
+		WithIssueSync(jiraIssueSync).
+		WithWorklogs(services.NewWorklogService(a.timeOffRepo, a.Config)).
+		WithCapacity(services.NewCapacityService(a.squadRepo, a.userRepo, a.timeOffRepo, a.Config)).
+		WithEpicProgress(services.NewEpicProgressService(a.userRepo, a.timeOffRepo, a.Config))
+	jiraIssueSync.Start(a.workers, a.jiraHandlers.ConnectJira)
+	a.gitHubHandlers = handlers.NewGitHubHandlers(a.userRepo, a.orgGitHubRepo, a.timeOffRepo, a.gitHubOAuthService, a.oauthStateStore, a.Config.FrontendURL, a.Config.GitHubAPIURL, a.Logger)
+	a.slackHandlers = handlers.NewSlackHandlers(a.userRepo, a.orgSlackRepo, a.slackAppService, a.slackOAuthService, a.oauthStateStore, a.Config.FrontendURL, a.Logger)
+	if a.csrf != nil {
+		a.jiraHandlers.WithOAuthStateBinding(a.csrf)
+		a.gitHubHandlers.WithOAuthStateBinding(a.csrf)
+		a.slackHandlers.WithOAuthStateBinding(a.csrf)
+	}
+	a.linearHandlers = handlers.NewLinearHandlers(a.userRepo, a.orgLinearRepo, a.Logger)
+	// Team task views work with whichever tracker the org connected, preferring Jira
+	a.workItemHandlers = handlers.NewWorkItemHandlers(a.userRepo, a.timeOffRepo, 0, a.Logger,
+		services.NewJiraWorkItemProvider(a.orgJiraRepo, jiraIssueSync, a.jiraHandlers.ConnectJira),
+		services.NewLinearWorkItemProvider(a.orgLinearRepo, a.linearHandlers.ConnectLinear))
+	a.orgChartHandlers = handlers.NewOrgChartHandlersWithEvents(a.orgChartRepo, a.userRepo, a.eventBroker).
+		WithNotifications(a.notificationService)
+	a.timeOffHandlers = handlers.NewTimeOffHandlersWithEvents(a.timeOffRepo, a.userRepo, a.eventBroker).
+		WithSquadLeads(a.squadRepo).
+		WithNotifications(a.notificationService).
+		WithSlack(a.slackAppService).
+		WithSavedViews(a.savedViewRepo)
+	a.slackHandlers.WithInteractivity(a.Config.SlackSigningSecret, a.timeOffHandlers)
+	a.streamsDone = make(chan struct{})
+	a.calendarHandlers = handlers.NewCalendarHandlersWithNotifications(a.calendarBFFService, a.taskRepo, a.meetingRepo, a.taskCommentRepo, a.meetingNotesRepo, a.notificationService, a.eventBroker).
+		WithSquadLeads(a.squadRepo).
+		WithShutdown(a.streamsDone).
+		WithSavedViews(a.savedViewRepo)
+	a.meetingICSHandlers = handlers.NewMeetingICSHandlers(services.NewMeetingICSService(a.meetingRepo, a.userRepo), a.eventBroker)
+	a.meetingAttachmentHandlers = handlers.NewMeetingAttachmentHandlersWithConfig(a.meetingRepo, a.meetingAttachmentRepo, a.meetingAttachmentService, a.Config.MeetingAttachmentMaxSizeMB).
+		WithSettings(a.orgSettingsService)
+	a.searchHandlers = handlers.NewSearchHandlers(a.meetingAttachmentRepo, a.meetingAttachmentService)
+	a.exportHandlers = handlers.NewExportHandlers(a.exportService)
+	a.workScheduleHandlers = handlers.NewWorkScheduleHandlers(a.workScheduleRepo)
+	a.skillHandlers = handlers.NewSkillHandlers(a.skillRepo, a.userRepo)
+	a.managerNoteHandlers = handlers.NewManagerNoteHandlers(a.managerNoteRepo, a.userRepo, a.meetingRepo).
+		WithAdminAudit(a.Config.ManagerNotesAdminAudit)
+	a.goalHandlers = handlers.NewGoalHandlers(a.goalRepo, a.userRepo, a.squadRepo)
+	a.kudosHandlers = handlers.NewKudosHandlers(a.kudosRepo, a.userRepo, a.notificationService).
+		WithCompanyValues(a.Config.CompanyValues)
+	a.notificationHandlers = handlers.NewNotificationHandlers(a.notificationRepo)
+	a.onboardingHandlers = handlers.NewOnboardingHandlers(a.onboardingRepo, a.userRepo, a.onboardingService)
+	a.webhookHandlers = handlers.NewWebhookHandlers(a.webhookRepo).WithService(a.webhookService)
+	a.auditHandlers = handlers.NewAuditHandlers(a.auditEventRepo)
+	a.roleHandlers = handlers.NewRoleHandlers(a.customRoleRepo, a.userRepo)
+	a.sessionHandlers = handlers.NewSessionHandlers(a.sessionService, a.userRepo)
+	a.impersonationHandlers = handlers.NewImpersonationHandlers(a.impersonationRepo, a.userRepo)
+	a.ipAllowlistHandlers = handlers.NewIPAllowlistHandlers(a.organizationRepo)
+	a.orgSettingsHandlers = handlers.NewOrgSettingsHandlers(a.orgSettingsService)
+	a.emailTemplateHandlers = handlers.NewEmailTemplateHandlers(a.emailTemplateService)
+	a.reportHandlers = handlers.NewReportHandlers(services.NewReportService(a.reportRepo))
+	a.adminStatsHandlers = handlers.NewAdminStatsHandlers(services.NewAdminStatsService(a.adminStatsRepo))
+	a.workloadHandlers = handlers.NewWorkloadHandlers(a.workloadSnapshotService, a.userRepo)
+	a.savedViewHandlers = handlers.NewSavedViewHandlers(a.savedViewRepo)
+	a.accessRequestHandlers = handlers.NewAccessRequestHandlers(a.accessRequestRepo, a.organizationRepo, a.userRepo).
+		WithOnboarding(a.onboardingService)
+	a.scimHandlers = handlers.NewSCIMHandlers(a.userRepo, a.squadRepo, a.eventBroker).
+		WithOnboarding(a.onboardingService).
+		WithAuth0Sync(a.auth0Sync)
+	a.auth0WebhookHandlers = handlers.NewAuth0WebhookHandlers(a.Config.Auth0WebhookToken, a.auth0Sync)
 	return nil
 }
 
 func (a *App) initGraphQL() error {
-	graphResolver := graph.NewResolver(a.userRepo, a.squadRepo, a.orgJiraRepo, a.auth0Client, a.emailService, a.Config.FrontendURL, a.Logger)
+	graphResolver := graph.NewResolver(a.userRepo, a.squadRepo, a.timeOffRepo, a.taskRepo, a.orgJiraRepo, a.auth0Client, a.emailService, a.Config.FrontendURL, a.Logger)
+	graphResolver.Broker = a.eventBroker
+	graphResolver.Auth0Sync = a.auth0Sync
+	graphResolver.EmployeeService.WithOnboarding(a.onboardingService)
 	a.graphServer = handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: graphResolver}))
+	a.graphServer.SetErrorPresenter(graph.ErrorPresenter)
+	a.graphServer.AroundOperations(graph.ReadOnlyMutationGuard(a.authorizationService))
+	a.graphServer.AroundOperations(graph.AuditMutations(a.auditEventRepo))
+	a.graphServer.AroundResponses(graph.TraceResponses)
+	a.graphServer.AroundFields(graph.TraceResolvers)
 	return nil
 }
 
+// cachePolicy builds a response cache policy using the configured freshness windows
+func (a *App) cachePolicy(name string, invalidateOn ...events.Type) middleware.CachePolicy {
+	return middleware.CachePolicy{
+		Name:         name,
+		TTL:          time.Duration(a.Config.ResponseCacheTTLSeconds) * time.Second,
+		StaleTTL:     time.Duration(a.Config.ResponseCacheStaleSeconds) * time.Second,
+		InvalidateOn: invalidateOn,
+	}
+}
+
 func (a *App) initRouter() {
 	r := chi.NewRouter()
 
 	// Create metrics collector (before rate limiter so we can track rate-limited requests)
-	a.metrics = middleware.NewMetrics("manager_dashboard")
+	a.metrics = middleware.NewMetrics(metricsNamespace)
 
 	// Create endpoint-specific rate limiter with:
 	// - Stricter limits for sensitive endpoints (invitations, user creation, etc.)
@@ -276,20 +788,26 @@ func (a *App) initRouter() {
 
 	// Global middleware
 	r.Use(chimiddleware.RequestID)
+	r.Use(a.clientIPs()) // Client IPs for everything below, from trusted proxies' headers
+	r.Use(tracing.Middleware)   // OpenTelemetry spans, named by route
 	r.Use(a.metrics.Middleware) // Prometheus metrics
 	r.Use(middleware.RequestLogger(a.Logger))
 	r.Use(middleware.RecoveryLogger(a.Logger))
 	r.Use(middleware.SecurityHeaders)
 	r.Use(rateLimiter.Limit)
+	r.Use(a.ipRestrictions())
 	r.Use(middleware.RequestSizeLimiter(int64(a.Config.MaxRequestSizeMB) << 20))
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{a.Config.FrontendURL},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", middleware.ImpersonationSessionHeader},
+		ExposedHeaders:   []string{"Link", middleware.ImpersonatedUserHeader, middleware.ImpersonatedByHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
+	if a.csrf != nil {
+		r.Use(a.csrf.Protect)
+	}
 
 	// Health check, probes, and metrics
 	a.registerHealthRoutes(r)
@@ -308,6 +826,9 @@ func (a *App) initRouter() {
 	// REST API routes
 	a.registerAPIRoutes(r)
 
+	// SCIM provisioning for identity providers
+	a.registerSCIMRoutes(r)
+
 	// Static file server
 	fileServer := http.FileServer(http.Dir("uploads"))
 	r.Handle("/uploads/*", http.StripPrefix("/uploads/", fileServer))
@@ -315,6 +836,27 @@ func (a *App) initRouter() {
 	a.Router = r
 }
 
+// clientIPs resolves each request's client IP, believing forwarding headers only from the
+// configured proxies
+func (a *App) clientIPs() func(http.Handler) http.Handler {
+	// Config.Validate has checked the entries parse
+	proxies, _ := middleware.ParseTrustedProxies(a.Config.TrustedProxies)
+	return middleware.ResolveClientIP(proxies)
+}
+
+// ipRestrictions enforces the deployment-wide IP allowlist and allowed countries on every request
+// but health checks, before authentication
+func (a *App) ipRestrictions() func(http.Handler) http.Handler {
+	// Config.Validate has checked the entries parse
+	allowlist, _ := middleware.ParseIPAllowlist(a.Config.IPAllowlist)
+	return middleware.RestrictIPs(middleware.IPRestrictions{
+		Allowlist:        allowlist,
+		AllowedCountries: a.Config.AllowedCountries,
+		CountryHeader:    a.Config.GeoCountryHeader,
+		SkipPaths:        []string{"/health", "/ready", "/live"},
+	}, a.auditEventRepo)
+}
+
 func (a *App) registerHealthRoutes(r chi.Router) {
 	// Basic health check (for load balancers)
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -397,8 +939,10 @@ func (a *App) registerGraphQLRoutes(r chi.Router) {
 	// GraphQL endpoint (protected)
 	r.Group(func(r chi.Router) {
 		r.Use(a.authMiddleware.Authenticate)
-		r.Use(a.graphqlTimeoutMiddleware) // Apply operation timeout
-		r.Use(a.dataloaderMiddleware)     // Inject dataloaders for N+1 prevention
+		r.Use(middleware.AuditContext)                           // Client IP for mutation audit events
+		r.Use(middleware.RestrictGuests(a.authorizationService)) // Guests have no GraphQL access
+		r.Use(a.graphqlTimeoutMiddleware)                        // Apply operation timeout
+		r.Use(a.dataloaderMiddleware)                            // Inject dataloaders for N+1 prevention
 		r.Post("/graphql", a.graphServer.ServeHTTP)
 	})
 }
@@ -408,7 +952,7 @@ func (a *App) registerGraphQLRoutes(r chi.Router) {
 func (a *App) dataloaderMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Create fresh loaders for each request (they cache within a single request)
-		loaders := graph.NewLoaders(a.userRepo)
+		loaders := graph.NewLoaders(a.userRepo, a.squadRepo, a.timeOffRepo, a.taskRepo)
 		ctx := graph.ContextWithLoaders(r.Context(), loaders)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -425,25 +969,71 @@ func (a *App) graphqlTimeoutMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+func (a *App) registerSCIMRoutes(r chi.Router) {
+	if !a.Config.IsSCIMEnabled() {
+		a.Logger.Info("SCIM_TOKEN not configured - SCIM provisioning is disabled")
+		return
+	}
+
+	r.Route("/scim/v2", func(r chi.Router) {
+		r.Use(middleware.SCIMAuth(a.Config.SCIMToken))
+		r.Use(middleware.AuditRequests(a.auditEventRepo)) // Record provisioning changes
+
+		r.Get("/ServiceProviderConfig", a.scimHandlers.GetServiceProviderConfig)
+
+		r.Get("/Users", a.scimHandlers.GetUsers)
+		r.Post("/Users", a.scimHandlers.CreateUser)
+		r.Get("/Users/{id}", a.scimHandlers.GetUser)
+		r.Put("/Users/{id}", a.scimHandlers.ReplaceUser)
+		r.Patch("/Users/{id}", a.scimHandlers.PatchUser)
+		r.Delete("/Users/{id}", a.scimHandlers.DeleteUser)
+
+		r.Get("/Groups", a.scimHandlers.GetGroups)
+		r.Post("/Groups", a.scimHandlers.CreateGroup)
+		r.Get("/Groups/{id}", a.scimHandlers.GetGroup)
+		r.Put("/Groups/{id}", a.scimHandlers.ReplaceGroup)
+		r.Patch("/Groups/{id}", a.scimHandlers.PatchGroup)
+		r.Delete("/Groups/{id}", a.scimHandlers.DeleteGroup)
+	})
+}
+
 func (a *App) registerAPIRoutes(r chi.Router) {
 	r.Route("/api", func(r chi.Router) {
 		// Protected routes
 		r.Group(func(r chi.Router) {
 			r.Use(a.authMiddleware.Authenticate)
+			r.Use(middleware.AuditRequests(a.auditEventRepo))            // Record every mutating request
+			r.Use(middleware.RequireWriteAccess(a.authorizationService)) // Viewers are read-only
+			r.Use(middleware.RestrictGuests(a.authorizationService))     // Guests only see their own calendar
 
 			// Current user
 			r.Get("/me", a.handlers.GetCurrentUser)
 
 			// Employees (for managers to see their team)
-			r.Get("/employees", a.handlers.GetEmployees)
+			r.With(a.responseCache.Handler(a.cachePolicy("employees", events.UserChanged, events.SquadChanged))).
+				Get("/employees", a.handlers.GetEmployees)
 
 			// Users CRUD
 			r.Get("/users", a.handlers.GetAllUsers)
+			r.Get("/users/search", a.handlers.SearchUsers)
 			r.Get("/users/{id}", a.handlers.GetUserByID)
 			r.Post("/users", a.handlers.CreateUser)
 			r.Put("/users/{id}", a.handlers.UpdateUser)
 			r.Delete("/users/{id}", a.handlers.DeleteUser)
 			r.Post("/users/{id}/deactivate", a.handlers.DeactivateUser)
+			r.Post("/users/{id}/offboard", a.handlers.OffboardUser)
+			r.Post("/users/{id}/reactivate", a.handlers.ReactivateUser)
+			r.Post("/users/{id}/supervisor", a.handlers.ReassignSupervisor)
+			r.Get("/users/{id}/history", a.handlers.GetUserHistory)
+
+			// Title and department changes awaiting a supervisor
+			r.Get("/me/profile-change", a.handlers.GetMyProfileChange)
+			r.Get("/profile-changes", a.handlers.ListProfileChanges)
+			r.Post("/profile-changes/{id}/approve", a.handlers.ApproveProfileChange)
+			r.Post("/profile-changes/{id}/reject", a.handlers.RejectProfileChange)
+
+			// Upcoming birthdays and work anniversaries
+			r.Get("/team/milestones", a.handlers.GetTeamMilestones)
 
 			// Supervisors list
 			r.Get("/supervisors", a.handlers.GetSupervisors)
@@ -454,6 +1044,8 @@ func (a *App) registerAPIRoutes(r chi.Router) {
 			r.Put("/squads/{id}", a.handlers.RenameSquad)
 			r.Delete("/squads/{id}", a.handlers.DeleteSquad)
 			r.Get("/squads/{id}/users", a.handlers.GetUsersBySquad)
+			r.Put("/squads/{id}/lead", a.handlers.SetSquadLead)
+			r.Delete("/squads/{id}/lead", a.handlers.ClearSquadLead)
 
 			// Departments CRUD
 			r.Get("/departments", a.handlers.GetDepartments)
@@ -462,6 +1054,116 @@ func (a *App) registerAPIRoutes(r chi.Router) {
 			r.Delete("/departments/{name}", a.handlers.DeleteDepartment)
 			r.Get("/departments/{name}/users", a.handlers.GetUsersByDepartment)
 
+			// Custom profile fields
+			r.Get("/custom-fields", a.handlers.GetCustomFields)
+			r.Post("/custom-fields", a.handlers.CreateCustomField)
+			r.Put("/custom-fields/{id}", a.handlers.UpdateCustomField)
+			r.Delete("/custom-fields/{id}", a.handlers.DeleteCustomField)
+
+			// Skills catalog, "who knows X" search, and squad coverage
+			r.Get("/skills", a.skillHandlers.GetSkills)
+			r.Post("/skills", a.skillHandlers.CreateSkill)
+			r.Get("/skills/experts", a.skillHandlers.FindExperts)
+			r.Put("/skills/{id}", a.skillHandlers.UpdateSkill)
+			r.Delete("/skills/{id}", a.skillHandlers.DeleteSkill)
+			r.Get("/users/{id}/skills", a.skillHandlers.GetUserSkills)
+			r.Put("/users/{id}/skills", a.skillHandlers.SetUserSkills)
+			r.Get("/squads/{id}/skills", a.skillHandlers.GetSquadSkillCoverage)
+
+			// Supervisors' private notes about their reports
+			r.Get("/users/{id}/notes", a.managerNoteHandlers.GetNotes)
+			r.Post("/users/{id}/notes", a.managerNoteHandlers.CreateNote)
+			r.Get("/users/{id}/notes/audit", a.managerNoteHandlers.AuditNotes)
+			r.Put("/notes/{id}", a.managerNoteHandlers.UpdateNote)
+			r.Delete("/notes/{id}", a.managerNoteHandlers.DeleteNote)
+
+			// Goals and key results
+			r.Get("/goals", a.goalHandlers.GetGoals)
+			r.Post("/goals", a.goalHandlers.CreateGoal)
+			r.Get("/goals/rollup", a.goalHandlers.GetGoalRollup)
+			r.Get("/goals/{id}", a.goalHandlers.GetGoal)
+			r.Put("/goals/{id}", a.goalHandlers.UpdateGoal)
+			r.Delete("/goals/{id}", a.goalHandlers.DeleteGoal)
+			r.Get("/goals/{id}/progress", a.goalHandlers.GetGoalProgress)
+			r.Post("/key-results/{id}/progress", a.goalHandlers.RecordProgress)
+
+			// Kudos
+			r.Get("/kudos", a.kudosHandlers.GetFeed)
+			r.Post("/kudos", a.kudosHandlers.GiveKudos)
+			r.Get("/kudos/departments", a.kudosHandlers.GetDepartmentCounts)
+
+			// In-app notifications for the current user
+			r.Get("/notifications", a.notificationHandlers.GetNotifications)
+			r.Get("/notifications/unread-count", a.notificationHandlers.GetUnreadCount)
+			r.Post("/notifications/read-all", a.notificationHandlers.MarkAllRead)
+			r.Post("/notifications/{id}/read", a.notificationHandlers.MarkRead)
+
+			// Team analytics reports (admins for the organization, supervisors for their team)
+			r.Get("/reports/{report}", a.reportHandlers.GetReport)
+
+			// Daily workload trends
+			r.Get("/workload/trend", a.workloadHandlers.GetWorkloadTrend)
+
+			// Users' saved list filters, applied to tasks, time off, and user search with ?view=
+			r.Get("/saved-views", a.savedViewHandlers.ListViews)
+			r.Post("/saved-views", a.savedViewHandlers.CreateView)
+			r.Put("/saved-views/{id}", a.savedViewHandlers.UpdateView)
+			r.Delete("/saved-views/{id}", a.savedViewHandlers.DeleteView)
+
+			// Audit log (admin only)
+			r.Get("/audit-events", a.auditHandlers.GetEvents)
+
+			// Custom roles and permissions
+			r.Get("/permissions", a.roleHandlers.GetPermissionCatalog)
+			r.Get("/me/permissions", a.roleHandlers.GetMyPermissions)
+			r.Get("/roles", a.roleHandlers.GetRoles)
+			r.Post("/roles", a.roleHandlers.CreateRole)
+			r.Put("/roles/{id}", a.roleHandlers.UpdateRole)
+			r.Delete("/roles/{id}", a.roleHandlers.DeleteRole)
+			r.Get("/users/{id}/roles", a.roleHandlers.GetUserRoles)
+			r.Put("/users/{id}/roles", a.roleHandlers.SetUserRoles)
+
+			// Sessions and signing users out
+			r.Get("/users/{id}/sessions", a.sessionHandlers.GetUserSessions)
+			r.Get("/users/{id}/logins", a.sessionHandlers.GetUserLogins)
+			r.Delete("/users/{id}/sessions", a.sessionHandlers.RevokeUserSessions)
+			r.Delete("/users/{id}/sessions/{sessionId}", a.sessionHandlers.RevokeUserSession)
+
+			// Impersonation (admin only)
+			r.Get("/me/impersonation", a.impersonationHandlers.GetCurrentImpersonation)
+			r.Post("/admin/impersonate/{userId}", a.impersonationHandlers.StartImpersonation)
+			r.Delete("/admin/impersonate/{userId}", a.impersonationHandlers.StopImpersonation)
+
+			// Networks the organization's users may connect from
+			r.Get("/admin/ip-allowlist", a.ipAllowlistHandlers.GetIPAllowlist)
+			r.Put("/admin/ip-allowlist", a.ipAllowlistHandlers.UpdateIPAllowlist)
+
+			// Organization-wide stats for the admin home page
+			r.Get("/admin/stats", a.adminStatsHandlers.GetStats)
+
+			// Organization settings that override the server's configured defaults
+			r.Get("/admin/settings", a.orgSettingsHandlers.GetSettings)
+			r.Put("/admin/settings", a.orgSettingsHandlers.UpdateSettings)
+
+			// Customizing and previewing the organization's emails
+			r.Get("/admin/email-templates", a.emailTemplateHandlers.ListEmailTemplates)
+			r.Get("/admin/email-templates/{key}/{language}", a.emailTemplateHandlers.GetEmailTemplate)
+			r.Put("/admin/email-templates/{key}/{language}", a.emailTemplateHandlers.UpdateEmailTemplate)
+			r.Delete("/admin/email-templates/{key}/{language}", a.emailTemplateHandlers.ResetEmailTemplate)
+			r.Post("/admin/email-templates/{key}/{language}/preview", a.emailTemplateHandlers.PreviewEmailTemplate)
+
+			// Restoring deleted records before they are purged
+			r.Post("/admin/users/{id}/restore", a.handlers.RestoreUser)
+			r.Post("/admin/tasks/{id}/restore", a.calendarHandlers.RestoreTask)
+			r.Post("/admin/meetings/{id}/restore", a.calendarHandlers.RestoreMeeting)
+
+			// Onboarding checklists
+			r.Get("/onboarding/templates", a.onboardingHandlers.GetTemplates)
+			r.Post("/onboarding/templates", a.onboardingHandlers.CreateTemplate)
+			r.Put("/onboarding/templates/{id}", a.onboardingHandlers.UpdateTemplate)
+			r.Delete("/onboarding/templates/{id}", a.onboardingHandlers.DeleteTemplate)
+			r.Get("/users/{id}/onboarding", a.onboardingHandlers.GetProgress)
+
 			// Avatar upload
 			r.Post("/users/{id}/avatar", a.avatarHandlers.UploadAvatar)
 			r.Post("/users/{id}/avatar/base64", a.avatarHandlers.UploadAvatarBase64)
@@ -469,28 +1171,94 @@ func (a *App) registerAPIRoutes(r chi.Router) {
 			// Invitations (admin only)
 			r.Get("/invitations", a.invitationHandlers.GetInvitations)
 			r.Post("/invitations", a.invitationHandlers.CreateInvitation)
+			r.Post("/invitations/bulk", a.invitationHandlers.BulkCreateInvitations)
 			r.Get("/invitations/{id}", a.invitationHandlers.GetInvitation)
 			r.Delete("/invitations/{id}", a.invitationHandlers.RevokeInvitation)
+			r.Get("/admin/invitation-domains", a.invitationHandlers.GetInvitationDomains)
+			r.Put("/admin/invitation-domains", a.invitationHandlers.UpdateInvitationDomains)
+
+			// Access requests from self-service signup (admin only)
+			r.Get("/admin/access-requests", a.accessRequestHandlers.ListAccessRequests)
+			r.Post("/admin/access-requests/{id}/approve", a.accessRequestHandlers.ApproveAccessRequest)
+			r.Post("/admin/access-requests/{id}/reject", a.accessRequestHandlers.RejectAccessRequest)
+
+			// Outgoing webhooks (admin only). They're also served at their original paths under /webhooks.
+			for _, prefix := range []string{"/admin/webhooks", "/webhooks"} {
+				r.Get(prefix, a.webhookHandlers.ListWebhooks)
+				r.Post(prefix, a.webhookHandlers.CreateWebhook)
+				r.Put(prefix+"/{id}", a.webhookHandlers.UpdateWebhook)
+				r.Delete(prefix+"/{id}", a.webhookHandlers.DeleteWebhook)
+				r.Get(prefix+"/{id}/deliveries", a.webhookHandlers.ListWebhookDeliveries)
+				r.Post(prefix+"/{id}/test", a.webhookHandlers.TestWebhook)
+			}
 
 			// Jira integration
 			r.Get("/jira/settings", a.jiraHandlers.GetJiraSettings)
+			r.Get("/jira/health", a.jiraHandlers.GetJiraHealth)
 			r.Put("/jira/settings", a.jiraHandlers.UpdateJiraSettings)
 			r.Delete("/jira/settings", a.jiraHandlers.DeleteJiraSettings)
 			r.Get("/jira/tasks", a.jiraHandlers.GetMyTasks)
-			r.Get("/jira/tasks/team", a.jiraHandlers.GetTeamTasks)
+			r.With(a.responseCache.Handler(a.cachePolicy("jira-team-tasks", events.UserChanged, events.JiraIssuesChanged, events.JiraTaskFiltersChanged))).
+				Get("/jira/tasks/team", a.jiraHandlers.GetTeamTasks)
 			r.Get("/jira/tasks/user/{userId}", a.jiraHandlers.GetUserTasks)
+			r.Get("/jira/worklogs/user/{userId}", a.jiraHandlers.GetUserWorklogs)
+			r.Get("/jira/task-filters", a.jiraHandlers.GetTaskFilters)
+			r.Put("/jira/task-filters", a.jiraHandlers.UpdateTaskFilters)
 			r.Get("/jira/projects", a.jiraHandlers.GetProjects)
 			r.Get("/jira/projects/{projectKey}/tasks", a.jiraHandlers.GetProjectTasks)
 			r.Get("/jira/epics", a.jiraHandlers.GetEpics)
+			r.Get("/jira/epics/{key}/progress", a.jiraHandlers.GetEpicProgress)
+			r.Post("/jira/issues", a.jiraHandlers.CreateIssue)
+			r.Get("/jira/issues/{issueKey}/transitions", a.jiraHandlers.GetIssueTransitions)
+			r.Post("/jira/issues/{issueKey}/transitions", a.jiraHandlers.TransitionIssue)
+			r.Post("/jira/issues/{issueKey}/comments", a.jiraHandlers.AddIssueComment)
+			r.Post("/jira/sprints/capacity-check", a.jiraHandlers.CheckSprintCapacity)
+			r.Get("/capacity", a.jiraHandlers.GetCapacity)
 			r.Get("/jira/oauth/authorize", a.jiraHandlers.GetOAuthAuthorizeURL)
+			r.Get("/jira/oauth/sites", a.jiraHandlers.GetPendingJiraSites)
+			r.Post("/jira/oauth/sites/select", a.jiraHandlers.SelectJiraSite)
+			r.Delete("/jira/oauth/sites", a.jiraHandlers.CancelPendingJiraConnection)
 			r.Get("/jira/users", a.jiraHandlers.GetJiraUsers)
 			r.Post("/jira/users/auto-match", a.jiraHandlers.AutoMatchJiraUsers)
+			r.Get("/jira/users/unmatched", a.jiraHandlers.GetUnmatchedJiraUsers)
 			r.Put("/jira/users/{userId}/mapping", a.jiraHandlers.UpdateUserJiraMapping)
 			r.Delete("/jira/disconnect", a.jiraHandlers.DisconnectJira)
 
+			// GitHub integration
+			r.Get("/github/settings", a.gitHubHandlers.GetGitHubSettings)
+			r.Put("/github/settings", a.gitHubHandlers.UpdateGitHubSettings)
+			r.Delete("/github/disconnect", a.gitHubHandlers.DisconnectGitHub)
+			r.Get("/github/oauth/authorize", a.gitHubHandlers.GetOAuthAuthorizeURL)
+			r.Put("/github/users/{userId}/mapping", a.gitHubHandlers.UpdateUserGitHubMapping)
+			r.Get("/github/pulls", a.gitHubHandlers.GetMyPullRequests)
+			r.Get("/github/pulls/team", a.gitHubHandlers.GetTeamPullRequests)
+
+			// Slack app integration
+			r.Get("/slack/settings", a.slackHandlers.GetSlackSettings)
+			r.Delete("/slack/disconnect", a.slackHandlers.DisconnectSlack)
+			r.Get("/slack/oauth/authorize", a.slackHandlers.GetOAuthAuthorizeURL)
+
+			// Linear integration
+			r.Get("/linear/settings", a.linearHandlers.GetLinearSettings)
+			r.Put("/linear/settings", a.linearHandlers.UpdateLinearSettings)
+			r.Delete("/linear/disconnect", a.linearHandlers.DisconnectLinear)
+			r.Get("/linear/users", a.linearHandlers.GetLinearUsers)
+			r.Put("/linear/users/{userId}/mapping", a.linearHandlers.UpdateUserLinearMapping)
+
+			// Team tasks from whichever task tracker is connected
+			r.Get("/workitems/tasks/team", a.workItemHandlers.GetTeamTasks)
+
 			// Org Chart Drafts (supervisor only)
 			r.Route("/orgchart", func(r chi.Router) {
-				r.Get("/tree", a.orgChartHandlers.GetOrgTree)
+				r.With(a.responseCache.Handler(a.cachePolicy("org-tree", events.UserChanged, events.SquadChanged, events.OrgChartPublished,
+					events.TaskCreated, events.TaskUpdated, events.TaskDeleted, events.TimeOffReviewed))).
+					Get("/tree", a.orgChartHandlers.GetOrgTree)
+				r.With(a.responseCache.Handler(a.cachePolicy("org-tree-children", events.UserChanged, events.SquadChanged, events.OrgChartPublished,
+					events.TaskCreated, events.TaskUpdated, events.TaskDeleted, events.TimeOffReviewed))).
+					Get("/tree/{userId}/children", a.orgChartHandlers.GetOrgTreeChildren)
+				r.Get("/export", a.orgChartHandlers.ExportOrgChart)
+				r.Get("/history", a.orgChartHandlers.GetOrgChartHistory)
+				r.Get("/snapshots/{id}", a.orgChartHandlers.GetOrgChartSnapshot)
 				r.Route("/drafts", func(r chi.Router) {
 					r.Post("/", a.orgChartHandlers.CreateDraft)
 					r.Get("/", a.orgChartHandlers.GetDrafts)
@@ -499,6 +1267,13 @@ func (a *App) registerAPIRoutes(r chi.Router) {
 					r.Delete("/{id}", a.orgChartHandlers.DeleteDraft)
 					r.Post("/{id}/changes", a.orgChartHandlers.AddChange)
 					r.Delete("/{id}/changes/{userId}", a.orgChartHandlers.RemoveChange)
+					r.Get("/{id}/preview", a.orgChartHandlers.PreviewDraft)
+					r.Get("/{id}/shares", a.orgChartHandlers.GetDraftShares)
+					r.Put("/{id}/shares", a.orgChartHandlers.ShareDraft)
+					r.Delete("/{id}/shares/{userId}", a.orgChartHandlers.UnshareDraft)
+					r.Get("/{id}/comments", a.orgChartHandlers.GetDraftComments)
+					r.Post("/{id}/comments", a.orgChartHandlers.AddDraftComment)
+					r.Post("/{id}/approve", a.orgChartHandlers.ApproveDraft)
 					r.Post("/{id}/publish", a.orgChartHandlers.PublishDraft)
 				})
 			})
@@ -506,25 +1281,70 @@ func (a *App) registerAPIRoutes(r chi.Router) {
 			// Calendar (tasks, meetings, events)
 			r.Route("/calendar", func(r chi.Router) {
 				r.Get("/events", a.calendarHandlers.GetEvents)
+				r.Get("/stream", a.calendarHandlers.StreamEvents)
+				r.Get("/suggest-times", a.calendarHandlers.SuggestMeetingTimes)
+
+				// Working hours and focus time
+				r.Get("/work-schedule", a.workScheduleHandlers.GetWorkSchedule)
+				r.Put("/work-schedule", a.workScheduleHandlers.UpdateWorkSchedule)
 
 				// Tasks
 				r.Route("/tasks", func(r chi.Router) {
+					r.Get("/", a.calendarHandlers.ListTasks)
 					r.Post("/", a.calendarHandlers.CreateTask)
+					r.Post("/bulk", a.calendarHandlers.BulkCreateTasks)
+					r.Post("/reassign", a.calendarHandlers.ReassignTasks)
 					r.Get("/{id}", a.calendarHandlers.GetTask)
 					r.Put("/{id}", a.calendarHandlers.UpdateTask)
 					r.Delete("/{id}", a.calendarHandlers.DeleteTask)
+
+					// Discussion and activity feed
+					r.Get("/{id}/comments", a.calendarHandlers.ListTaskComments)
+					r.Post("/{id}/comments", a.calendarHandlers.CreateTaskComment)
+					r.Put("/{id}/comments/{commentId}", a.calendarHandlers.UpdateTaskComment)
+					r.Delete("/{id}/comments/{commentId}", a.calendarHandlers.DeleteTaskComment)
+					r.Get("/{id}/activity", a.calendarHandlers.GetTaskActivity)
 				})
 
 				// Meetings
 				r.Route("/meetings", func(r chi.Router) {
 					r.Post("/", a.calendarHandlers.CreateMeeting)
+					r.Post("/check-conflicts", a.calendarHandlers.CheckMeetingConflicts)
+					r.Get("/export.ics", a.meetingICSHandlers.ExportMeetings)
+					r.Post("/import", a.meetingICSHandlers.ImportMeetings)
 					r.Get("/{id}", a.calendarHandlers.GetMeeting)
 					r.Put("/{id}", a.calendarHandlers.UpdateMeeting)
 					r.Delete("/{id}", a.calendarHandlers.DeleteMeeting)
+					r.Put("/{id}/occurrence", a.calendarHandlers.UpdateMeetingOccurrence)
+					r.Delete("/{id}/occurrence", a.calendarHandlers.CancelMeetingOccurrence)
 					r.Post("/{id}/respond", a.calendarHandlers.RespondToMeeting)
+
+					// Agenda, notes, and action items
+					r.Get("/{id}/agenda", a.calendarHandlers.GetMeetingAgenda)
+					r.Put("/{id}/agenda", a.calendarHandlers.UpdateMeetingAgenda)
+					r.Get("/{id}/notes", a.calendarHandlers.GetMeetingNotes)
+					r.Put("/{id}/notes", a.calendarHandlers.UpdateMeetingNotes)
+					r.Post("/{id}/action-items/convert", a.calendarHandlers.ConvertMeetingActionItems)
+
+					// Recordings and transcripts
+					r.Get("/{id}/attachments", a.meetingAttachmentHandlers.ListAttachments)
+					r.Post("/{id}/attachments", a.meetingAttachmentHandlers.UploadAttachment)
+					r.Post("/{id}/attachments/link", a.meetingAttachmentHandlers.LinkAttachment)
+					r.Get("/{id}/attachments/{attachmentId}", a.meetingAttachmentHandlers.GetAttachment)
+					r.Delete("/{id}/attachments/{attachmentId}", a.meetingAttachmentHandlers.DeleteAttachment)
 				})
 			})
 
+			// Global search
+			r.Get("/search", a.searchHandlers.Search)
+
+			// Background exports
+			r.Route("/exports", func(r chi.Router) {
+				r.Post("/", a.exportHandlers.CreateExport)
+				r.Get("/", a.exportHandlers.ListExports)
+				r.Get("/{id}", a.exportHandlers.GetExport)
+			})
+
 			// Time Off Requests
 			r.Route("/time-off", func(r chi.Router) {
 				r.Post("/", a.timeOffHandlers.Create)
@@ -537,12 +1357,42 @@ func (a *App) registerAPIRoutes(r chi.Router) {
 			})
 		})
 
+		// CSRF token for requests the browser authenticates with cookies
+		if a.csrf != nil {
+			r.Get("/csrf-token", a.csrf.IssueToken)
+		}
+
 		// Public invitation routes (for signup flow)
 		r.Get("/invitations/validate/{token}", a.invitationHandlers.ValidateInvitation)
 		r.Post("/invitations/accept/{token}", a.invitationHandlers.AcceptInvitation)
 
-		// Jira OAuth callback (must be public - called by Atlassian, not authenticated user)
-		r.Get("/jira/oauth/callback", a.jiraHandlers.HandleOAuthCallback)
+		// Self-service signup, for people signed in without an account
+		if a.Config.AccessRequestsEnabled {
+			r.Group(func(r chi.Router) {
+				r.Use(a.authMiddleware.AuthenticateIdentity)
+				r.Post("/access-requests", a.accessRequestHandlers.RequestAccess)
+				r.Get("/access-requests/mine", a.accessRequestHandlers.GetMyAccessRequest)
+			})
+		}
+
+		// Jira OAuth callback (must be public - Atlassian redirects the browser here, though the
+		// frontend relays it with the signed-in user's token, which must match the flow's)
+		r.With(a.authMiddleware.AuthenticateIfPresent).Get("/jira/oauth/callback", a.jiraHandlers.HandleOAuthCallback)
+
+		// Jira issue webhook (public - called by Jira and verified by its signature)
+		r.Post("/jira/webhook", a.jiraHandlers.HandleWebhook)
+
+		// Auth0 log stream (public - called by Auth0 and verified by the stream's token)
+		r.Post("/auth0/webhook", a.auth0WebhookHandlers.HandleLogStream)
+
+		// GitHub OAuth callback (must be public - called by GitHub, not authenticated user)
+		r.Get("/github/oauth/callback", a.gitHubHandlers.HandleOAuthCallback)
+
+		// Slack OAuth callback (must be public - called by Slack, not authenticated user)
+		r.Get("/slack/oauth/callback", a.slackHandlers.HandleOAuthCallback)
+
+		// Slack button clicks (public - called by Slack and verified by its signature)
+		r.Post("/slack/interactions", a.slackHandlers.HandleInteraction)
 	})
 }
 
@@ -554,6 +1404,8 @@ func (a *App) initServer() {
 		WriteTimeout: time.Duration(a.Config.WriteTimeout) * time.Second,
 		IdleTimeout:  time.Duration(a.Config.IdleTimeout) * time.Second,
 	}
+	// Shutdown waits for open requests, so end the event streams that would otherwise hold it up
+	a.Server.RegisterOnShutdown(func() { close(a.streamsDone) })
 }
 
 // Run starts the HTTP server
@@ -579,10 +1431,29 @@ func (a *App) Shutdown(ctx context.Context) error {
 		return err
 	}
 
-	// Close database connection
+	// Give background workers a bounded period to finish before their database connections go away
+	drainCtx, cancel := context.WithTimeout(ctx, time.Duration(a.Config.WorkerDrainTimeout)*time.Second)
+	defer cancel()
+	if err := a.workers.Stop(drainCtx); err != nil {
+		a.Logger.Warn("Background workers did not stop in time", "error", err)
+	} else {
+		a.Logger.Info("Background workers stopped")
+	}
+
+	// Close database connections
 	a.DB.Close()
+	if a.ReplicaDB != nil {
+		a.ReplicaDB.Close()
+	}
 	a.Logger.Info("Database connection closed")
 
+	// Send the spans of the last requests before exiting
+	if a.shutdownTracing != nil {
+		if err := a.shutdownTracing(ctx); err != nil {
+			a.Logger.Warn("Failed to flush traces", "error", err)
+		}
+	}
+
 	a.Logger.Info("Server exited gracefully")
 	return nil
 }
@@ -632,6 +1503,22 @@ func (a *App) buildDetailedHealthResponse(ctx context.Context) DetailedHealthRes
 		response.Status = "unhealthy"
 	}
 
+	// Read replica health check
+	if a.ReplicaDB != nil {
+		replicaCheck := a.checkPool(ctx, a.ReplicaDB)
+		response.Checks["database_replica"] = replicaCheck
+		if replicaCheck.Status != "healthy" {
+			response.Status = "unhealthy"
+		}
+	}
+
+	// Migration health check
+	migrationCheck := a.checkMigrations(ctx)
+	response.Checks["migrations"] = migrationCheck
+	if migrationCheck.Status != "healthy" {
+		response.Status = "unhealthy"
+	}
+
 	// System info
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
@@ -648,10 +1535,42 @@ func (a *App) buildDetailedHealthResponse(ctx context.Context) DetailedHealthRes
 	return response
 }
 
+// checkMigrations reports the database's schema version. A dirty version means a migration failed
+// partway and needs fixing by hand; pending migrations mean this instance's schema is out of date.
+// A database ahead of this build, as during a rolling deploy, is healthy.
+func (a *App) checkMigrations(ctx context.Context) HealthCheck {
+	status, err := database.GetMigrationStatus(ctx, a.DB)
+	if err != nil {
+		return HealthCheck{
+			Status:  "unhealthy",
+			Details: map[string]any{"error": "failed to read migration version"},
+		}
+	}
+
+	check := HealthCheck{
+		Status: "healthy",
+		Details: map[string]any{
+			"version": status.Version,
+			"latest":  status.Latest,
+			"dirty":   status.Dirty,
+			"pending": status.Pending,
+		},
+	}
+	if status.Dirty || status.Pending > 0 {
+		check.Status = "unhealthy"
+	}
+	return check
+}
+
 // checkDatabase performs a database health check
 func (a *App) checkDatabase(ctx context.Context) HealthCheck {
+	return a.checkPool(ctx, a.DB)
+}
+
+// checkPool pings a connection pool and reports its statistics
+func (a *App) checkPool(ctx context.Context, pool *pgxpool.Pool) HealthCheck {
 	start := time.Now()
-	err := a.DB.Ping(ctx)
+	err := pool.Ping(ctx)
 	latency := time.Since(start)
 
 	if err != nil {
@@ -665,7 +1584,7 @@ func (a *App) checkDatabase(ctx context.Context) HealthCheck {
 	}
 
 	// Get pool statistics
-	stats := a.DB.Stat()
+	stats := pool.Stat()
 
 	return HealthCheck{
 		Status:  "healthy",
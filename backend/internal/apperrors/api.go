@@ -0,0 +1,82 @@
+package apperrors
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// RequestIDHeader is the response header the request logger sets to the request's ID
+const RequestIDHeader = "X-Request-ID"
+
+// APIError is the body of every error response
+type APIError struct {
+	Message   string                 `json:"error"` // Named "error" for clients written before codes were added
+	Status    int                    `json:"status"`
+	Code      ErrorCode              `json:"code"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+}
+
+// ToAPIError converts any error to the body of its response. Errors other than AppErrors
+// become internal errors, so their messages aren't exposed.
+func ToAPIError(err error) *APIError {
+	apiErr := &APIError{
+		Message: GetUserMessage(err),
+		Status:  GetHTTPStatus(err),
+		Code:    GetErrorCode(err),
+	}
+
+	var appErr *AppError
+	if errors.As(err, &appErr) && (appErr.Field != "" || len(appErr.Details) > 0) {
+		apiErr.Details = make(map[string]interface{}, len(appErr.Details)+1)
+		for k, v := range appErr.Details {
+			apiErr.Details[k] = v
+		}
+		if appErr.Field != "" {
+			apiErr.Details["field"] = appErr.Field
+		}
+	}
+	return apiErr
+}
+
+// CodeForStatus returns the error code for a response that only has a status
+func CodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge:
+		return CodeValidationFailed
+	case http.StatusUnauthorized:
+		return CodeAuthRequired
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusTooManyRequests:
+		return CodeRateLimitExceeded
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return CodeServiceUnavailable
+	default:
+		return CodeInternalError
+	}
+}
+
+// Write writes err as an error response
+func Write(w http.ResponseWriter, err error) {
+	WriteAPIError(w, ToAPIError(err))
+}
+
+// WriteAPIError writes an error response, tagging it with the request's ID when the request
+// logger has set one so users can quote it in support requests
+func WriteAPIError(w http.ResponseWriter, apiErr *APIError) {
+	if apiErr.Code == "" {
+		apiErr.Code = CodeForStatus(apiErr.Status)
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = w.Header().Get(RequestIDHeader)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.Status)
+	_ = json.NewEncoder(w).Encode(apiErr)
+}
@@ -0,0 +1,110 @@
+package apperrors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestToAPIError(t *testing.T) {
+	t.Run("app error", func(t *testing.T) {
+		err := NewValidationErrorWithField("email", "Email is required")
+		apiErr := ToAPIError(fmt.Errorf("creating user: %w", err))
+
+		if apiErr.Message != "Email is required" || apiErr.Status != http.StatusBadRequest || apiErr.Code != CodeMissingField {
+			t.Errorf("unexpected API error: %+v", apiErr)
+		}
+		if apiErr.Details["field"] != "email" {
+			t.Errorf("Details = %v, want the field", apiErr.Details)
+		}
+	})
+
+	t.Run("other errors are internal", func(t *testing.T) {
+		apiErr := ToAPIError(errors.New("pq: password authentication failed"))
+
+		if apiErr.Message != "An internal error occurred" || apiErr.Status != http.StatusInternalServerError || apiErr.Code != CodeInternalError {
+			t.Errorf("unexpected API error: %+v", apiErr)
+		}
+		if apiErr.Details != nil {
+			t.Errorf("Details = %v, want none", apiErr.Details)
+		}
+	})
+
+	t.Run("rate limits", func(t *testing.T) {
+		if apiErr := ToAPIError(NewRateLimitError()); apiErr.Status != http.StatusTooManyRequests {
+			t.Errorf("Status = %d, want %d", apiErr.Status, http.StatusTooManyRequests)
+		}
+	})
+}
+
+func TestWriteAPIError(t *testing.T) {
+	rr := httptest.NewRecorder()
+	rr.Header().Set(RequestIDHeader, "req-7")
+	WriteAPIError(rr, &APIError{Message: "Task not found", Status: http.StatusNotFound})
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %s, want application/json", ct)
+	}
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	want := map[string]interface{}{"error": "Task not found", "status": float64(404), "code": "NOT_FOUND", "request_id": "req-7"}
+	for k, v := range want {
+		if body[k] != v {
+			t.Errorf("%s = %v, want %v", k, body[k], v)
+		}
+	}
+	if _, ok := body["details"]; ok {
+		t.Error("expected details to be omitted when empty")
+	}
+}
+
+func TestFromRepository(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   ErrorCode
+	}{
+		{"no rows", fmt.Errorf("failed to get meeting: %w", pgx.ErrNoRows), http.StatusNotFound, CodeNotFound},
+		{"not found message", errors.New("meeting not found"), http.StatusNotFound, CodeNotFound},
+		{"unique violation", fmt.Errorf("failed to create skill: %w", &pgconn.PgError{Code: "23505"}), http.StatusConflict, CodeAlreadyExists},
+		{"foreign key violation", &pgconn.PgError{Code: "23503"}, http.StatusBadRequest, CodeInvalidInput},
+		{"not null violation", &pgconn.PgError{Code: "23502", ColumnName: "title"}, http.StatusBadRequest, CodeMissingField},
+		{"check violation", &pgconn.PgError{Code: "23514"}, http.StatusBadRequest, CodeInvalidInput},
+		{"other database error", &pgconn.PgError{Code: "53300"}, http.StatusInternalServerError, CodeDatabaseError},
+		{"timeout", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusServiceUnavailable, CodeServiceUnavailable},
+		{"app error kept", NewForbiddenError(""), http.StatusForbidden, CodeForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := FromRepository(tt.err, "Meeting")
+			if appErr.HTTPStatus() != tt.wantStatus || appErr.Code != tt.wantCode {
+				t.Errorf("got %d %s, want %d %s", appErr.HTTPStatus(), appErr.Code, tt.wantStatus, tt.wantCode)
+			}
+			if appErr.HTTPStatus() >= http.StatusInternalServerError && !errors.Is(appErr, tt.err) {
+				t.Error("expected the repository error to be kept for logging")
+			}
+		})
+	}
+
+	if FromRepository(nil, "Meeting") != nil {
+		t.Error("FromRepository(nil) should return nil")
+	}
+	if msg := FromRepository(pgx.ErrNoRows, "Meeting").Message; msg != "Meeting not found" {
+		t.Errorf("Message = %q, want %q", msg, "Meeting not found")
+	}
+}
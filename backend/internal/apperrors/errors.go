@@ -24,6 +24,8 @@ const (
 	ErrorTypeInternal
 	// ErrorTypeServiceUnavailable indicates a dependency is unavailable
 	ErrorTypeServiceUnavailable
+	// ErrorTypeRateLimited indicates the client sent too many requests
+	ErrorTypeRateLimited
 )
 
 // ErrorCode represents a machine-readable error code
@@ -41,6 +43,15 @@ const (
 	CodeAdminRequired      ErrorCode = "ADMIN_REQUIRED"
 	CodeSupervisorRequired ErrorCode = "SUPERVISOR_REQUIRED"
 	CodeNotOwner           ErrorCode = "NOT_OWNER"
+	CodeReadOnly           ErrorCode = "READ_ONLY"
+	CodeGuestRestricted    ErrorCode = "GUEST_RESTRICTED"
+	CodePermissionDenied   ErrorCode = "PERMISSION_DENIED"
+	CodeIPNotAllowed       ErrorCode = "IP_NOT_ALLOWED"
+	CodeCSRFFailed         ErrorCode = "CSRF_FAILED"
+	CodeAccountDeleted     ErrorCode = "ACCOUNT_DELETED"
+	CodeAccountDeactivated ErrorCode = "ACCOUNT_DEACTIVATED"
+	CodeAccessRequired     ErrorCode = "ACCESS_REQUIRED" // Signed in without an account; access must be requested
+	CodeEmailUnverified    ErrorCode = "EMAIL_UNVERIFIED" // Signed in with an email the identity provider hasn't verified
 
 	// Resource errors
 	CodeNotFound      ErrorCode = "NOT_FOUND"
@@ -70,11 +81,12 @@ const (
 
 // AppError is the standard application error type
 type AppError struct {
-	Type    ErrorType // Error category
-	Code    ErrorCode // Machine-readable error code
-	Message string    // User-facing message
-	Err     error     // Internal error (not exposed to users)
-	Field   string    // Optional field name for validation errors
+	Type    ErrorType              // Error category
+	Code    ErrorCode              // Machine-readable error code
+	Message string                 // User-facing message
+	Err     error                  // Internal error (not exposed to users)
+	Field   string                 // Optional field name for validation errors
+	Details map[string]interface{} // Optional user-facing context, such as which limit was hit
 }
 
 // Error implements the error interface
@@ -105,6 +117,8 @@ func (e *AppError) HTTPStatus() int {
 		return http.StatusConflict
 	case ErrorTypeServiceUnavailable:
 		return http.StatusServiceUnavailable
+	case ErrorTypeRateLimited:
+		return http.StatusTooManyRequests
 	case ErrorTypeInternal:
 		fallthrough
 	default:
@@ -259,7 +273,7 @@ func NewJiraError(message string, err error) *AppError {
 // NewRateLimitError creates a rate limit error
 func NewRateLimitError() *AppError {
 	return &AppError{
-		Type:    ErrorTypeValidation, // Use validation since 429 is client-side handling
+		Type:    ErrorTypeRateLimited,
 		Code:    CodeRateLimitExceeded,
 		Message: "Too many requests, please try again later",
 	}
@@ -280,6 +294,7 @@ func Wrap(err error, message string) *AppError {
 			Message: message,
 			Err:     err,
 			Field:   appErr.Field,
+			Details: appErr.Details,
 		}
 	}
 
@@ -377,6 +392,8 @@ func defaultCodeForType(t ErrorType) ErrorCode {
 		return CodeConflict
 	case ErrorTypeServiceUnavailable:
 		return CodeServiceUnavailable
+	case ErrorTypeRateLimited:
+		return CodeRateLimitExceeded
 	default:
 		return CodeInternalError
 	}
@@ -0,0 +1,59 @@
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes mapped to client errors
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgNotNullViolation    = "23502"
+	pgCheckViolation      = "23514"
+	pgInvalidText         = "22P02"
+	pgStringTooLong       = "22001"
+)
+
+// FromRepository maps an error from a repository to the AppError it should be reported as.
+// resource names what was being read or written, e.g. "User". Missing rows become not found
+// errors, constraint violations become conflict or validation errors, and anything else an
+// internal error that keeps the repository error for logging. AppErrors are returned as-is.
+func FromRepository(err error, resource string) *AppError {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, pgx.ErrNoRows), strings.HasSuffix(err.Error(), "not found"):
+		notFound := NewNotFoundError(resource)
+		notFound.Err = err
+		return notFound
+	case errors.As(err, &pgErr):
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			conflict := NewAlreadyExistsError(resource)
+			conflict.Err = err
+			return conflict
+		case pgForeignKeyViolation:
+			return &AppError{Type: ErrorTypeValidation, Code: CodeInvalidInput, Message: resource + " refers to a record that doesn't exist", Err: err}
+		case pgNotNullViolation:
+			return &AppError{Type: ErrorTypeValidation, Code: CodeMissingField, Message: "Invalid " + strings.ToLower(resource) + ": a required field is missing", Field: pgErr.ColumnName, Err: err}
+		case pgCheckViolation, pgInvalidText, pgStringTooLong:
+			return &AppError{Type: ErrorTypeValidation, Code: CodeInvalidInput, Message: "Invalid " + strings.ToLower(resource), Err: err}
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &AppError{Type: ErrorTypeServiceUnavailable, Code: CodeServiceUnavailable, Message: "The request timed out, please try again", Err: err}
+	}
+	return NewDatabaseError("An internal error occurred", err)
+}
@@ -0,0 +1,103 @@
+package auth0
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Log event types that can revoke a user's access
+const (
+	// LogTypeUserDeleted is logged when a user is deleted from the Auth0 dashboard
+	LogTypeUserDeleted = "sdu"
+	// LogTypeManagementAPI is logged for each successful Management API call, including blocking
+	// and deleting users
+	LogTypeManagementAPI = "sapi"
+)
+
+const managementUsersPath = "/api/v2/users/"
+
+// LogEvent is an event delivered by an Auth0 log stream
+type LogEvent struct {
+	LogID string  `json:"log_id"`
+	Data  LogData `json:"data"`
+}
+
+// LogData is the Auth0 log entry of a log stream event
+type LogData struct {
+	Type    string     `json:"type"`
+	UserID  string     `json:"user_id"`
+	Details LogDetails `json:"details"`
+}
+
+// LogDetails holds the request a Management API log entry records
+type LogDetails struct {
+	Request struct {
+		Method string          `json:"method"`
+		Path   string          `json:"path"`
+		Body   json.RawMessage `json:"body"`
+	} `json:"request"`
+}
+
+// VerifyLogStreamToken reports whether the Authorization header of a log stream webhook holds the
+// token configured on the stream, sent either as is or as a bearer token
+func VerifyLogStreamToken(token, authorization string) bool {
+	if token == "" {
+		return false
+	}
+	given := strings.TrimPrefix(authorization, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// ParseLogEvents parses the body of a log stream webhook, which holds a JSON array of events, or a
+// single event
+func ParseLogEvents(body []byte) ([]LogEvent, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '{' {
+		var event LogEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode log event: %w", err)
+		}
+		return []LogEvent{event}, nil
+	}
+
+	var events []LogEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode log events: %w", err)
+	}
+	return events, nil
+}
+
+// RevokedUserID returns the ID of the user the event deleted or blocked, or "" if it didn't revoke
+// anyone's access
+func (e *LogEvent) RevokedUserID() string {
+	switch e.Data.Type {
+	case LogTypeUserDeleted:
+		return e.Data.UserID
+	case LogTypeManagementAPI:
+		req := e.Data.Details.Request
+		userID, ok := strings.CutPrefix(req.Path, managementUsersPath)
+		if !ok || userID == "" || strings.Contains(userID, "/") {
+			return ""
+		}
+		if unescaped, err := url.PathUnescape(userID); err == nil {
+			userID = unescaped
+		}
+
+		switch strings.ToUpper(req.Method) {
+		case "DELETE":
+			return userID
+		case "PATCH":
+			var body struct {
+				Blocked *bool `json:"blocked"`
+			}
+			if json.Unmarshal(req.Body, &body) == nil && body.Blocked != nil && *body.Blocked {
+				return userID
+			}
+		}
+	}
+	return ""
+}
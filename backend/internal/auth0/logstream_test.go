@@ -0,0 +1,56 @@
+package auth0
+
+import "testing"
+
+func TestParseLogEvents_RevokedUserID(t *testing.T) {
+	body := []byte(`[
+		{"log_id": "1", "data": {"type": "sdu", "user_id": "auth0|deleted"}},
+		{"log_id": "2", "data": {"type": "sapi", "details": {"request": {"method": "patch", "path": "/api/v2/users/auth0%7Cblocked", "body": {"blocked": true}}}}},
+		{"log_id": "3", "data": {"type": "sapi", "details": {"request": {"method": "patch", "path": "/api/v2/users/auth0%7Cunblocked", "body": {"blocked": false}}}}},
+		{"log_id": "4", "data": {"type": "sapi", "details": {"request": {"method": "patch", "path": "/api/v2/users/auth0%7Crenamed", "body": {"name": "Jane"}}}}},
+		{"log_id": "5", "data": {"type": "sapi", "details": {"request": {"method": "delete", "path": "/api/v2/users/google-oauth2%7C42"}}}},
+		{"log_id": "6", "data": {"type": "sapi", "details": {"request": {"method": "delete", "path": "/api/v2/users/auth0%7C1/roles"}}}},
+		{"log_id": "7", "data": {"type": "s", "user_id": "auth0|signed-in"}}
+	]`)
+
+	events, err := ParseLogEvents(body)
+	if err != nil {
+		t.Fatalf("ParseLogEvents() error = %v", err)
+	}
+	want := []string{"auth0|deleted", "auth0|blocked", "", "", "google-oauth2|42", "", ""}
+	if len(events) != len(want) {
+		t.Fatalf("ParseLogEvents() returned %d events, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if got := event.RevokedUserID(); got != want[i] {
+			t.Errorf("event %s RevokedUserID() = %q, want %q", event.LogID, got, want[i])
+		}
+	}
+
+	single, err := ParseLogEvents([]byte(`{"log_id": "8", "data": {"type": "sdu", "user_id": "auth0|single"}}`))
+	if err != nil || len(single) != 1 || single[0].RevokedUserID() != "auth0|single" {
+		t.Errorf("ParseLogEvents() of a single event = %+v, %v", single, err)
+	}
+
+	if _, err := ParseLogEvents([]byte(`not json`)); err == nil {
+		t.Error("ParseLogEvents() of invalid JSON error = nil, want error")
+	}
+}
+
+func TestVerifyLogStreamToken(t *testing.T) {
+	tests := []struct {
+		token, header string
+		want          bool
+	}{
+		{"secret", "secret", true},
+		{"secret", "Bearer secret", true},
+		{"secret", "Bearer other", false},
+		{"secret", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := VerifyLogStreamToken(tt.token, tt.header); got != tt.want {
+			t.Errorf("VerifyLogStreamToken(%q, %q) = %v, want %v", tt.token, tt.header, got, tt.want)
+		}
+	}
+}
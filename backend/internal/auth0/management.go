@@ -6,15 +6,22 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
 	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/tracing"
 )
 
+// ErrUserNotFound is returned when Auth0 has no user with the given ID, such as users who sign in
+// through another identity provider
+var ErrUserNotFound = errors.New("auth0 user not found")
+
 // ManagementClient provides methods to interact with Auth0 Management API
 type ManagementClient struct {
 	domain       string
@@ -82,7 +89,8 @@ func NewManagementClient(cfg *config.Config) *ManagementClient {
 		clientSecret: cfg.Auth0MgmtClientSecret,
 		dbConnection: cfg.Auth0DBConnection,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.Transport(nil),
 		},
 	}
 }
@@ -293,6 +301,45 @@ func (c *ManagementClient) DeleteUser(ctx context.Context, userID string) error
 	return nil
 }
 
+// SetBlocked blocks or unblocks a user in Auth0. Blocked users can't sign in, and Auth0 refuses to
+// issue them new tokens.
+func (c *ManagementClient) SetBlocked(ctx context.Context, userID string, blocked bool) error {
+	token, err := c.getAccessToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("https://%s/api/v2/users/%s", c.domain, url.PathEscape(userID))
+
+	body, err := json.Marshal(map[string]bool{"blocked": blocked})
+	if err != nil {
+		return fmt.Errorf("failed to marshal block request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrUserNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update user: status %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
 // GenerateSecurePassword generates a cryptographically secure random password
 func GenerateSecurePassword(length int) (string, error) {
 	if length < 12 {
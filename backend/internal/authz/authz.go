@@ -0,0 +1,309 @@
+// Package authz decides what users may do. Permissions pair an action, such as "timeoff:review",
+// with the scope of resources it applies to. Each built-in role grants a set of them, and custom
+// roles grant more on top. Handlers and services ask Can instead of checking roles, so who may do
+// what is defined in one place.
+package authz
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// Action is something a user can do, named "<resource>:<verb>"
+type Action string
+
+const (
+	// ActionWrite is needed for any change at all; viewers are read-only
+	ActionWrite Action = "data:write"
+	// ActionDirectoryView covers the employee directory, org chart, squads, and Jira data
+	ActionDirectoryView Action = "directory:view"
+	// ActionOrgWideView covers org-wide data such as the full org chart and everyone's time off
+	ActionOrgWideView Action = "org:view"
+
+	ActionUserView         Action = "user:view"
+	ActionUserViewInactive Action = "user:view_inactive"
+	ActionUserCreate       Action = "user:create"
+	ActionUserUpdate       Action = "user:update"
+	ActionUserChangeRole   Action = "user:change_role"
+	ActionUserDelete       Action = "user:delete"
+	// ActionUserDeactivate covers deactivating, offboarding, and reactivating users
+	ActionUserDeactivate Action = "user:deactivate"
+	// ActionUserImpersonate covers acting as another user to see what they see
+	ActionUserImpersonate Action = "user:impersonate"
+	// ActionUserHistoryView covers the history of changes made to a user's profile
+	ActionUserHistoryView Action = "user:view_history"
+
+	ActionTimeOffView   Action = "timeoff:view"
+	ActionTimeOffCreate Action = "timeoff:create"
+	ActionTimeOffReview Action = "timeoff:review"
+
+	// ActionTaskManage covers editing and deleting tasks and removing their comments; own scope
+	// covers the tasks the user created
+	ActionTaskManage Action = "task:manage"
+	// ActionMeetingManage covers editing, rescheduling, and deleting meetings and their notes; own
+	// scope covers the meetings the user created
+	ActionMeetingManage Action = "meeting:manage"
+	// ActionTeamView covers the views of a team as a whole, such as its milestones, goal rollup,
+	// and open work items, and the skill coverage of squads
+	ActionTeamView Action = "team:view"
+	// ActionGoalManage covers setting goals; own scope covers the user's goals, and team scope their
+	// direct reports', every squad's, and their department's
+	ActionGoalManage Action = "goal:manage"
+
+	ActionSquadManage      Action = "squad:manage"
+	ActionDepartmentManage Action = "department:manage"
+	ActionOrgChartView     Action = "orgchart:view"
+	ActionOrgChartManage   Action = "orgchart:manage"
+	ActionOrgChartPublish  Action = "orgchart:publish"
+	ActionOrgChartHistory  Action = "orgchart:history"
+
+	ActionInvitationManage Action = "invitation:manage"
+	ActionOnboardingView   Action = "onboarding:view"
+	ActionOnboardingManage Action = "onboarding:manage"
+	// ActionSkillCreate covers adding skills to the catalog; renaming and deleting them is ActionSkillManage
+	ActionSkillCreate       Action = "skill:create"
+	ActionSkillManage       Action = "skill:manage"
+	ActionCustomFieldManage Action = "customfield:manage"
+	ActionKudosReport       Action = "kudos:report"
+	// ActionManagerNoteWrite covers keeping private notes about users; team scope covers direct reports
+	ActionManagerNoteWrite Action = "managernote:write"
+	ActionManagerNoteAudit Action = "managernote:audit"
+	// ActionIntegrationView covers the Jira, GitHub, and Linear data shown in the dashboard, such as
+	// issues and pull requests
+	ActionIntegrationView Action = "integration:view"
+	// ActionIssueManage covers transitioning and commenting on Jira issues; own scope covers issues
+	// assigned to the user
+	ActionIssueManage Action = "issue:manage"
+	// ActionIntegrationManage covers connecting Jira, GitHub, Linear, and Slack and mapping their users
+	ActionIntegrationManage Action = "integration:manage"
+	ActionWebhookManage     Action = "webhook:manage"
+	ActionAuditView         Action = "audit:view"
+	ActionRoleManage        Action = "role:manage"
+	// ActionSessionManage covers viewing anyone's sessions and signing users out
+	ActionSessionManage Action = "session:manage"
+	// ActionSecurityManage covers the networks the organization's users may connect from
+	ActionSecurityManage Action = "security:manage"
+	// ActionDeletedRestore covers restoring deleted users, tasks, and meetings before they are purged
+	ActionDeletedRestore Action = "deleted:restore"
+	// ActionEmailTemplateManage covers customizing and previewing the organization's emails
+	ActionEmailTemplateManage Action = "emailtemplate:manage"
+	// ActionReportView covers the team analytics reports; team scope reports on the user's direct reports
+	ActionReportView Action = "report:view"
+	// ActionSettingsManage covers the organization's settings, such as invitation expiry and upload limits
+	ActionSettingsManage Action = "settings:manage"
+	// ActionStatsView covers the organization-wide stats on the admin home page
+	ActionStatsView Action = "stats:view"
+)
+
+// Actions lists every action, in the order they are documented
+var Actions = []Action{
+	ActionWrite, ActionDirectoryView, ActionOrgWideView,
+	ActionUserView, ActionUserViewInactive, ActionUserCreate, ActionUserUpdate, ActionUserChangeRole,
+	ActionUserDelete, ActionUserDeactivate, ActionUserImpersonate, ActionUserHistoryView,
+	ActionTimeOffView, ActionTimeOffCreate, ActionTimeOffReview,
+	ActionTaskManage, ActionMeetingManage, ActionTeamView, ActionGoalManage,
+	ActionSquadManage, ActionDepartmentManage,
+	ActionOrgChartView, ActionOrgChartManage, ActionOrgChartPublish, ActionOrgChartHistory,
+	ActionInvitationManage, ActionOnboardingView, ActionOnboardingManage, ActionSkillCreate, ActionSkillManage,
+	ActionCustomFieldManage, ActionKudosReport, ActionManagerNoteWrite, ActionManagerNoteAudit,
+	ActionIntegrationView, ActionIssueManage, ActionIntegrationManage, ActionWebhookManage, ActionAuditView,
+	ActionRoleManage, ActionSessionManage, ActionSecurityManage, ActionDeletedRestore,
+	ActionEmailTemplateManage, ActionReportView, ActionSettingsManage, ActionStatsView,
+}
+
+// Scope limits which resources a permission applies to
+type Scope string
+
+const (
+	// ScopeAll applies to every resource
+	ScopeAll Scope = "all"
+	// ScopeTeam applies to resources belonging to the user's direct reports
+	ScopeTeam Scope = "team"
+	// ScopeOwn applies to the user's own resources
+	ScopeOwn Scope = "own"
+)
+
+// Scopes lists every scope, broadest first
+var Scopes = []Scope{ScopeAll, ScopeTeam, ScopeOwn}
+
+// Permission allows an action on resources within a scope
+type Permission struct {
+	Action Action `json:"action"`
+	Scope  Scope  `json:"scope"`
+}
+
+// Resource describes what an action is performed on by the user it belongs to
+type Resource struct {
+	OwnerID      int64
+	SupervisorID *int64
+}
+
+// UserResource describes a user as the resource of an action
+func UserResource(user *models.User) *Resource {
+	return &Resource{OwnerID: user.ID, SupervisorID: user.SupervisorID}
+}
+
+// TimeOffResource describes a time-off request by its owner. Without the owner loaded, only
+// the owner and unscoped permissions apply.
+func TimeOffResource(request *models.TimeOffRequest) *Resource {
+	resource := &Resource{OwnerID: request.UserID}
+	if request.User != nil {
+		resource.SupervisorID = request.User.SupervisorID
+	}
+	return resource
+}
+
+// TaskResource describes a task by the user who created it
+func TaskResource(task *models.Task) *Resource {
+	return &Resource{OwnerID: task.CreatedByID}
+}
+
+// MeetingResource describes a meeting by the user who created it
+func MeetingResource(meeting *models.Meeting) *Resource {
+	return &Resource{OwnerID: meeting.CreatedByID}
+}
+
+// OrgChartDraftResource describes an org chart draft by the user who created it
+func OrgChartDraftResource(draft *models.OrgChartDraft) *Resource {
+	return &Resource{OwnerID: draft.CreatedByID}
+}
+
+// all grants each action for every resource
+func all(actions ...Action) []Permission {
+	return scoped(ScopeAll, actions...)
+}
+
+// scoped grants each action within a scope
+func scoped(scope Scope, actions ...Action) []Permission {
+	permissions := make([]Permission, len(actions))
+	for i, action := range actions {
+		permissions[i] = Permission{Action: action, Scope: scope}
+	}
+	return permissions
+}
+
+// rolePermissions are the permissions each built-in role grants
+var rolePermissions = map[models.Role][]Permission{
+	models.RoleAdmin: all(Actions...),
+	models.RoleSupervisor: concat(
+		all(ActionWrite, ActionDirectoryView, ActionSquadManage, ActionDepartmentManage, ActionOrgChartView,
+			ActionOnboardingView, ActionSkillCreate, ActionIntegrationView),
+		scoped(ScopeOwn, ActionUserView, ActionUserUpdate, ActionUserHistoryView, ActionTimeOffView,
+			ActionTimeOffCreate, ActionTaskManage, ActionMeetingManage, ActionGoalManage, ActionIssueManage,
+			ActionOrgChartManage),
+		scoped(ScopeTeam, ActionUserView, ActionUserCreate, ActionUserUpdate, ActionUserChangeRole, ActionUserDelete,
+			ActionUserDeactivate, ActionUserHistoryView, ActionTimeOffView, ActionTimeOffCreate, ActionTimeOffReview,
+			ActionTeamView, ActionGoalManage, ActionIssueManage, ActionManagerNoteWrite, ActionReportView),
+	),
+	models.RoleEmployee: concat(
+		all(ActionWrite, ActionDirectoryView),
+		scoped(ScopeOwn, ActionUserView, ActionUserUpdate, ActionTimeOffView, ActionTimeOffCreate, ActionTaskManage,
+			ActionMeetingManage, ActionGoalManage, ActionIssueManage),
+	),
+	models.RoleViewer: concat(
+		all(ActionDirectoryView, ActionOrgWideView, ActionOrgChartView, ActionUserView),
+		scoped(ScopeOwn, ActionTimeOffView),
+	),
+	models.RoleGuest: concat(
+		all(ActionWrite),
+		scoped(ScopeOwn, ActionUserView, ActionTimeOffView, ActionTimeOffCreate, ActionTaskManage, ActionMeetingManage,
+			ActionGoalManage, ActionIssueManage),
+	),
+}
+
+// concat joins groups of permissions
+func concat(groups ...[]Permission) []Permission {
+	var permissions []Permission
+	for _, group := range groups {
+		permissions = append(permissions, group...)
+	}
+	return permissions
+}
+
+// RolePermissions returns the permissions a built-in role grants
+func RolePermissions(role models.Role) []Permission {
+	return rolePermissions[role]
+}
+
+// Permissions returns the permissions a user holds through their built-in role and any custom
+// roles loaded onto them
+func Permissions(user *models.User) []Permission {
+	if user == nil {
+		return nil
+	}
+	permissions := rolePermissions[user.Role]
+	if len(user.CustomRoles) == 0 {
+		return permissions
+	}
+
+	permissions = slices.Clone(permissions)
+	for _, role := range user.CustomRoles {
+		for _, granted := range role.Permissions {
+			permission := Permission{Action: Action(granted.Action), Scope: Scope(granted.Scope)}
+			if !slices.Contains(permissions, permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return permissions
+}
+
+// ValidatePermission checks that a custom role permission names a known action and scope
+func ValidatePermission(permission models.RolePermission) error {
+	if !slices.Contains(Actions, Action(permission.Action)) {
+		return fmt.Errorf("unknown action %q", permission.Action)
+	}
+	if !slices.Contains(Scopes, Scope(permission.Scope)) {
+		return fmt.Errorf("unknown scope %q for %s", permission.Scope, permission.Action)
+	}
+	return nil
+}
+
+// Can reports whether the user may perform the action on the resource. A nil resource asks
+// whether the user may perform the action on anything, e.g. before listing what they can see.
+func Can(user *models.User, action Action, resource *Resource) bool {
+	for _, permission := range Permissions(user) {
+		if permission.Action != action {
+			continue
+		}
+		if resource == nil || permission.covers(user, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAll reports whether the user may perform the action on every resource
+func CanAll(user *models.User, action Action) bool {
+	return hasScope(user, action, ScopeAll)
+}
+
+// CanForOthers reports whether the user may perform the action on resources other than their own
+func CanForOthers(user *models.User, action Action) bool {
+	return hasScope(user, action, ScopeAll) || hasScope(user, action, ScopeTeam)
+}
+
+func hasScope(user *models.User, action Action, scope Scope) bool {
+	for _, permission := range Permissions(user) {
+		if permission.Action == action && permission.Scope == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// covers reports whether the permission applies to the resource for the user holding it
+func (p Permission) covers(user *models.User, resource *Resource) bool {
+	switch p.Scope {
+	case ScopeAll:
+		return true
+	case ScopeTeam:
+		return resource.SupervisorID != nil && *resource.SupervisorID == user.ID
+	case ScopeOwn:
+		return resource.OwnerID == user.ID
+	default:
+		return false
+	}
+}
@@ -0,0 +1,147 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestCan(t *testing.T) {
+	supervisorID := int64(2)
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	supervisor := &models.User{ID: supervisorID, Role: models.RoleSupervisor}
+	report := &models.User{ID: 3, Role: models.RoleEmployee, SupervisorID: &supervisorID}
+	other := &models.User{ID: 4, Role: models.RoleEmployee}
+	viewer := &models.User{ID: 5, Role: models.RoleViewer}
+	guest := &models.User{ID: 6, Role: models.RoleGuest}
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		action   Action
+		resource *Resource
+		want     bool
+	}{
+		{"admin can do anything", admin, ActionAuditView, nil, true},
+		{"admin can update anyone", admin, ActionUserUpdate, UserResource(other), true},
+		{"supervisor can update a direct report", supervisor, ActionUserUpdate, UserResource(report), true},
+		{"supervisor can update themselves", supervisor, ActionUserUpdate, UserResource(supervisor), true},
+		{"supervisor cannot update someone else's report", supervisor, ActionUserUpdate, UserResource(other), false},
+		{"supervisor can review a direct report's time off", supervisor, ActionTimeOffReview, TimeOffResource(&models.TimeOffRequest{UserID: report.ID, User: report}), true},
+		{"supervisor cannot review their own time off", supervisor, ActionTimeOffReview, UserResource(supervisor), false},
+		{"supervisor cannot change their own role", supervisor, ActionUserChangeRole, UserResource(supervisor), false},
+		{"supervisor cannot view the audit log", supervisor, ActionAuditView, nil, false},
+		{"employee can update themselves", report, ActionUserUpdate, UserResource(report), true},
+		{"employee cannot update others", report, ActionUserUpdate, UserResource(other), false},
+		{"employee cannot review time off", report, ActionTimeOffReview, nil, false},
+		{"time off without its owner loaded is only visible to its owner", supervisor, ActionTimeOffView, TimeOffResource(&models.TimeOffRequest{UserID: report.ID}), false},
+		{"employee can manage tasks they created", report, ActionTaskManage, TaskResource(&models.Task{CreatedByID: report.ID}), true},
+		{"employee cannot manage others' tasks", report, ActionTaskManage, TaskResource(&models.Task{CreatedByID: other.ID}), false},
+		{"admin can manage anyone's meetings", admin, ActionMeetingManage, MeetingResource(&models.Meeting{CreatedByID: other.ID}), true},
+		{"supervisor can view their team as a whole", supervisor, ActionTeamView, nil, true},
+		{"supervisor can manage their own org chart drafts", supervisor, ActionOrgChartManage, OrgChartDraftResource(&models.OrgChartDraft{CreatedByID: supervisor.ID}), true},
+		{"supervisor cannot manage others' org chart drafts", supervisor, ActionOrgChartManage, OrgChartDraftResource(&models.OrgChartDraft{CreatedByID: admin.ID}), false},
+		{"employee cannot see integration data", report, ActionIntegrationView, nil, false},
+		{"employee can set their own goals", report, ActionGoalManage, UserResource(report), true},
+		{"supervisor can set a direct report's goals", supervisor, ActionGoalManage, UserResource(report), true},
+		{"supervisor cannot set someone else's report's goals", supervisor, ActionGoalManage, UserResource(other), false},
+		{"supervisor can act on a direct report's issues", supervisor, ActionIssueManage, UserResource(report), true},
+		{"viewer can view anyone", viewer, ActionUserView, UserResource(other), true},
+		{"viewer cannot write", viewer, ActionWrite, nil, false},
+		{"guest cannot view the directory", guest, ActionDirectoryView, nil, false},
+		{"nil user can do nothing", nil, ActionUserView, nil, false},
+		{"unknown role can do nothing", &models.User{ID: 7, Role: "intern"}, ActionWrite, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Can(tt.user, tt.action, tt.resource); got != tt.want {
+				t.Errorf("Can() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanAllAndCanForOthers(t *testing.T) {
+	tests := []struct {
+		role          models.Role
+		action        Action
+		wantAll       bool
+		wantForOthers bool
+	}{
+		{models.RoleAdmin, ActionTimeOffReview, true, true},
+		{models.RoleSupervisor, ActionTimeOffReview, false, true},
+		{models.RoleEmployee, ActionTimeOffCreate, false, false},
+		{models.RoleViewer, ActionUserView, true, true},
+		{models.RoleSupervisor, ActionGoalManage, false, true},
+		{models.RoleEmployee, ActionIssueManage, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+" "+string(tt.action), func(t *testing.T) {
+			user := &models.User{ID: 1, Role: tt.role}
+			if got := CanAll(user, tt.action); got != tt.wantAll {
+				t.Errorf("CanAll() = %v, want %v", got, tt.wantAll)
+			}
+			if got := CanForOthers(user, tt.action); got != tt.wantForOthers {
+				t.Errorf("CanForOthers() = %v, want %v", got, tt.wantForOthers)
+			}
+		})
+	}
+}
+
+func TestRolePermissions_AdminHasEveryAction(t *testing.T) {
+	granted := make(map[Action]bool)
+	for _, permission := range RolePermissions(models.RoleAdmin) {
+		if permission.Scope == ScopeAll {
+			granted[permission.Action] = true
+		}
+	}
+	for _, action := range Actions {
+		if !granted[action] {
+			t.Errorf("admin is missing %s", action)
+		}
+	}
+}
+
+func TestPermissions_CustomRoles(t *testing.T) {
+	hr := models.CustomRole{Name: "HR", Permissions: []models.RolePermission{
+		{Action: string(ActionUserDeactivate), Scope: string(ScopeAll)},
+		{Action: string(ActionTimeOffView), Scope: string(ScopeOwn)}, // already granted by the employee role
+	}}
+	user := &models.User{ID: 1, Role: models.RoleEmployee, CustomRoles: []models.CustomRole{hr}}
+	other := &Resource{OwnerID: 2}
+
+	if !Can(user, ActionUserDeactivate, other) {
+		t.Error("custom role permission was not applied")
+	}
+	if Can(&models.User{ID: 1, Role: models.RoleEmployee}, ActionUserDeactivate, other) {
+		t.Error("employee without the custom role can deactivate users")
+	}
+	if got, want := len(Permissions(user)), len(RolePermissions(models.RoleEmployee))+1; got != want {
+		t.Errorf("len(Permissions()) = %d, want %d", got, want)
+	}
+	if len(RolePermissions(models.RoleEmployee)) != len(Permissions(&models.User{Role: models.RoleEmployee})) {
+		t.Error("custom roles leaked into the built-in role's permissions")
+	}
+}
+
+func TestValidatePermission(t *testing.T) {
+	tests := []struct {
+		permission models.RolePermission
+		wantErr    bool
+	}{
+		{models.RolePermission{Action: "user:deactivate", Scope: "all"}, false},
+		{models.RolePermission{Action: "timeoff:review", Scope: "team"}, false},
+		{models.RolePermission{Action: "user:fire", Scope: "all"}, true},
+		{models.RolePermission{Action: "user:view", Scope: "department"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.permission.Action+" "+tt.permission.Scope, func(t *testing.T) {
+			if err := ValidatePermission(tt.permission); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePermission() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
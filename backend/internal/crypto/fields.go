@@ -0,0 +1,166 @@
+// Package crypto encrypts designated columns, such as integration tokens, transparently: values
+// are encrypted as repositories write them and decrypted as they scan them, and keys can be
+// rotated without downtime.
+package crypto
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/smith-dallin/manager-dashboard/internal/secrets"
+)
+
+// Fields encrypts and decrypts column values with a keyring. A nil Fields stores values as
+// plaintext, and values stored as plaintext before encryption was enabled are read as-is.
+type Fields struct {
+	cipher  *secrets.Cipher
+	keyring *Keyring
+}
+
+// NewFields creates field encryption from base64-encoded 32-byte keys. New values are encrypted
+// with currentKey; previousKeys still decrypt values until they are rewritten. It returns nil
+// when currentKey is empty, leaving encryption disabled.
+func NewFields(currentKey string, previousKeys []string) (*Fields, error) {
+	if currentKey == "" {
+		return nil, nil
+	}
+	current, err := decodeKey(currentKey)
+	if err != nil {
+		return nil, err
+	}
+	previous := make([][]byte, 0, len(previousKeys))
+	for _, key := range previousKeys {
+		raw, err := decodeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, raw)
+	}
+	keyring, err := NewKeyring(current, previous...)
+	if err != nil {
+		return nil, err
+	}
+	return &Fields{cipher: secrets.NewCipher(keyring), keyring: keyring}, nil
+}
+
+func decodeKey(key string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key must be base64: %w", err)
+	}
+	return raw, nil
+}
+
+// Enabled reports whether values are encrypted
+func (f *Fields) Enabled() bool {
+	return f != nil
+}
+
+// Encrypt encrypts value. Empty values, and all values when encryption is disabled, are
+// returned unchanged.
+func (f *Fields) Encrypt(value string) (string, error) {
+	return f.getCipher().Encrypt(value)
+}
+
+// Decrypt decrypts a value from Encrypt. Plaintext values are returned unchanged.
+func (f *Fields) Decrypt(value string) (string, error) {
+	return f.getCipher().Decrypt(value)
+}
+
+// NeedsRewrite reports whether a stored value should be rewritten: it is plaintext, or was
+// encrypted with a key other than the current one
+func (f *Fields) NeedsRewrite(value string) bool {
+	if f == nil || value == "" {
+		return false
+	}
+	return !f.keyring.IsCurrent(value)
+}
+
+// Rewrite re-encrypts a stored value with the current key
+func (f *Fields) Rewrite(value string) (string, error) {
+	plaintext, err := f.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return f.Encrypt(plaintext)
+}
+
+func (f *Fields) getCipher() *secrets.Cipher {
+	if f == nil {
+		return nil
+	}
+	return f.cipher
+}
+
+// Value returns a query argument that stores value encrypted
+func (f *Fields) Value(value string) driver.Valuer {
+	return encryptedValue{fields: f, value: &value}
+}
+
+// ValuePtr returns a query argument that stores an optional value encrypted; nil stores NULL
+func (f *Fields) ValuePtr(value *string) driver.Valuer {
+	return encryptedValue{fields: f, value: value}
+}
+
+// Scan returns a scan destination that decrypts a column into dst
+func (f *Fields) Scan(dst *string) sql.Scanner {
+	return &encryptedScanner{fields: f, dst: dst}
+}
+
+// ScanPtr returns a scan destination that decrypts a nullable column into dst; NULL scans as nil
+func (f *Fields) ScanPtr(dst **string) sql.Scanner {
+	return &encryptedScanner{fields: f, ptr: dst}
+}
+
+type encryptedValue struct {
+	fields *Fields
+	value  *string
+}
+
+func (v encryptedValue) Value() (driver.Value, error) {
+	if v.value == nil {
+		return nil, nil
+	}
+	encrypted, err := v.fields.Encrypt(*v.value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt value: %w", err)
+	}
+	return encrypted, nil
+}
+
+type encryptedScanner struct {
+	fields *Fields
+	dst    *string
+	ptr    **string
+}
+
+func (s *encryptedScanner) Scan(src any) error {
+	var stored string
+	switch v := src.(type) {
+	case nil:
+		if s.ptr == nil {
+			return fmt.Errorf("cannot scan NULL into an encrypted string")
+		}
+		*s.ptr = nil
+		return nil
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into an encrypted string", src)
+	}
+
+	value, err := s.fields.Decrypt(stored)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	if s.ptr != nil {
+		*s.ptr = &value
+	} else {
+		*s.dst = value
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/secrets"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+func newTestFields(t *testing.T, current string, previous ...string) *Fields {
+	t.Helper()
+	f, err := NewFields(current, previous)
+	if err != nil {
+		t.Fatalf("NewFields() error = %v", err)
+	}
+	return f
+}
+
+func TestFields_ValueAndScan(t *testing.T) {
+	f := newTestFields(t, testKey(1))
+
+	stored, err := f.Value("jira-token").Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if s, ok := stored.(string); !ok || !secrets.IsEncrypted(s) || strings.Contains(s, "jira-token") {
+		t.Fatalf("Value() = %v, want an encrypted string", stored)
+	}
+
+	var token string
+	if err := f.Scan(&token).Scan(stored); err != nil || token != "jira-token" {
+		t.Errorf("Scan() = %q, %v", token, err)
+	}
+	var fromBytes string
+	if err := f.Scan(&fromBytes).Scan([]byte(stored.(string))); err != nil || fromBytes != "jira-token" {
+		t.Errorf("Scan([]byte) = %q, %v", fromBytes, err)
+	}
+
+	// Plaintext stored before encryption was enabled is read as-is
+	if err := f.Scan(&token).Scan("legacy"); err != nil || token != "legacy" {
+		t.Errorf("Scan(plaintext) = %q, %v", token, err)
+	}
+	if err := f.Scan(&token).Scan(nil); err == nil {
+		t.Error("Scan(NULL) into a string should fail")
+	}
+}
+
+func TestFields_Nullable(t *testing.T) {
+	f := newTestFields(t, testKey(1))
+
+	if stored, err := f.ValuePtr(nil).Value(); err != nil || stored != nil {
+		t.Errorf("ValuePtr(nil) = %v, %v, want NULL", stored, err)
+	}
+
+	token := "api-token"
+	stored, err := f.ValuePtr(&token).Value()
+	if err != nil {
+		t.Fatalf("ValuePtr() error = %v", err)
+	}
+	var scanned *string
+	if err := f.ScanPtr(&scanned).Scan(stored); err != nil || scanned == nil || *scanned != "api-token" {
+		t.Errorf("ScanPtr() = %v, %v", scanned, err)
+	}
+	if err := f.ScanPtr(&scanned).Scan(nil); err != nil || scanned != nil {
+		t.Errorf("ScanPtr(NULL) = %v, %v, want nil", scanned, err)
+	}
+}
+
+func TestFields_Disabled(t *testing.T) {
+	f := newTestFields(t, "")
+	if f.Enabled() {
+		t.Fatal("expected encryption to be disabled without a key")
+	}
+
+	if stored, err := f.Value("jira-token").Value(); err != nil || stored != "jira-token" {
+		t.Errorf("Value() = %v, %v, want plaintext", stored, err)
+	}
+	if f.NeedsRewrite("jira-token") {
+		t.Error("nothing should need rewriting without a key")
+	}
+
+	// Encrypted values can't be read once the key is gone
+	encrypted, _ := newTestFields(t, testKey(1)).Encrypt("jira-token")
+	var token string
+	if err := f.Scan(&token).Scan(encrypted); err == nil {
+		t.Error("expected scanning an encrypted value without a key to fail")
+	}
+}
+
+func TestFields_Rotation(t *testing.T) {
+	oldFields := newTestFields(t, testKey(1))
+	legacy, err := secrets.NewCipherFromKey(testKey(1))
+	if err != nil {
+		t.Fatalf("NewCipherFromKey() error = %v", err)
+	}
+
+	underOldKey, _ := oldFields.Encrypt("refresh-token")
+	// Values encrypted before the keyring existed don't name their key
+	untagged, _ := legacy.Encrypt("refresh-token")
+
+	rotated := newTestFields(t, testKey(2), testKey(1))
+	for name, stored := range map[string]string{"old key": underOldKey, "untagged": untagged, "plaintext": "refresh-token"} {
+		var token string
+		if err := rotated.Scan(&token).Scan(stored); err != nil || token != "refresh-token" {
+			t.Errorf("%s: Scan() = %q, %v", name, token, err)
+		}
+		if !rotated.NeedsRewrite(stored) {
+			t.Errorf("%s: expected the value to need rewriting", name)
+		}
+
+		rewritten, err := rotated.Rewrite(stored)
+		if err != nil {
+			t.Fatalf("%s: Rewrite() error = %v", name, err)
+		}
+		if rotated.NeedsRewrite(rewritten) {
+			t.Errorf("%s: rewritten value still needs rewriting", name)
+		}
+
+		// Once rewritten, the old key can be retired
+		if token, err := newTestFields(t, testKey(2)).Decrypt(rewritten); err != nil || token != "refresh-token" {
+			t.Errorf("%s: Decrypt() with only the new key = %q, %v", name, token, err)
+		}
+	}
+
+	if _, err := newTestFields(t, testKey(2)).Decrypt(underOldKey); err == nil {
+		t.Error("expected a retired key's values to be unreadable")
+	}
+}
+
+func TestNewFields_InvalidKey(t *testing.T) {
+	for name, keys := range map[string][2]string{
+		"not base64":         {"not base64!", ""},
+		"short key":          {base64.StdEncoding.EncodeToString([]byte("short")), ""},
+		"short previous key": {testKey(1), base64.StdEncoding.EncodeToString([]byte("short"))},
+	} {
+		var previous []string
+		if keys[1] != "" {
+			previous = []string{keys[1]}
+		}
+		if _, err := NewFields(keys[0], previous); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/smith-dallin/manager-dashboard/internal/secrets"
+)
+
+// keyIDSize is the size of the ID that names the key a data key was wrapped with
+const keyIDSize = 8
+
+// keyIDMarker starts wrapped data keys that carry a key ID. Keys wrapped before the keyring
+// existed start with a random nonce instead.
+const keyIDMarker byte = 0xfe
+
+// ErrUnknownKey is returned when a data key was wrapped with none of the keyring's keys
+var ErrUnknownKey = errors.New("data key was wrapped with an unknown key")
+
+// Keyring is a secrets.KeyWrapper that wraps data keys with its current key, naming it by ID,
+// and unwraps them with any of its keys, so values stay readable while a key is rotated out
+type Keyring struct {
+	currentID []byte
+	wrappers  map[string]*secrets.LocalKeyWrapper
+	ids       [][]byte // Current key first
+}
+
+// NewKeyring creates a keyring that wraps with current and still unwraps with previous. Each key
+// is a 32-byte AES-256 key.
+func NewKeyring(current []byte, previous ...[]byte) (*Keyring, error) {
+	k := &Keyring{wrappers: make(map[string]*secrets.LocalKeyWrapper)}
+	for _, key := range append([][]byte{current}, previous...) {
+		wrapper, err := secrets.NewLocalKeyWrapper(key)
+		if err != nil {
+			return nil, err
+		}
+		id := keyID(key)
+		if _, ok := k.wrappers[string(id)]; ok {
+			continue
+		}
+		k.wrappers[string(id)] = wrapper
+		k.ids = append(k.ids, id)
+	}
+	k.currentID = k.ids[0]
+	return k, nil
+}
+
+// keyID derives a key's ID from the key, so keys don't need to be named in config
+func keyID(key []byte) []byte {
+	sum := sha256.Sum256(key)
+	return sum[:keyIDSize]
+}
+
+// WrapKey encrypts a data key with the current key
+func (k *Keyring) WrapKey(dataKey []byte) ([]byte, error) {
+	wrapped, err := k.wrappers[string(k.currentID)].WrapKey(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	header := append([]byte{keyIDMarker}, k.currentID...)
+	return append(header, wrapped...), nil
+}
+
+// UnwrapKey decrypts a data key with the key named in it, or for keys wrapped before the keyring
+// existed, whichever key it was wrapped with
+func (k *Keyring) UnwrapKey(wrapped []byte) ([]byte, error) {
+	if id, rest, ok := splitKeyID(wrapped); ok {
+		if wrapper, ok := k.wrappers[string(id)]; ok {
+			if dataKey, err := wrapper.UnwrapKey(rest); err == nil {
+				return dataKey, nil
+			}
+		}
+	}
+	// AES-GCM authenticates, so only the right key succeeds
+	for _, id := range k.ids {
+		if dataKey, err := k.wrappers[string(id)].UnwrapKey(wrapped); err == nil {
+			return dataKey, nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// IsCurrent reports whether a value from secrets.Cipher.Encrypt was sealed under the current key
+func (k *Keyring) IsCurrent(value string) bool {
+	wrapped, err := secrets.WrappedKey(value)
+	if err != nil {
+		return false
+	}
+	id, _, ok := splitKeyID(wrapped)
+	return ok && bytes.Equal(id, k.currentID)
+}
+
+// splitKeyID splits a wrapped data key into the ID of the key that wrapped it and the key itself
+func splitKeyID(wrapped []byte) ([]byte, []byte, bool) {
+	if len(wrapped) <= 1+keyIDSize || wrapped[0] != keyIDMarker {
+		return nil, nil, false
+	}
+	return wrapped[1 : 1+keyIDSize], wrapped[1+keyIDSize:], true
+}
@@ -0,0 +1,185 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const accessRequestColumns = `id, org_id, auth0_id, email, first_name, last_name, message, status, reviewed_by_id, reviewed_at,
+	user_id, created_at, updated_at`
+
+type AccessRequestRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAccessRequestRepository(pool *pgxpool.Pool) *AccessRequestRepository {
+	return &AccessRequestRepository{pool: pool}
+}
+
+// scanAccessRequest scans a row of accessRequestColumns into an AccessRequest
+func scanAccessRequest(row pgx.Row) (*models.AccessRequest, error) {
+	var req models.AccessRequest
+	err := row.Scan(&req.ID, &req.OrgID, &req.Auth0ID, &req.Email, &req.FirstName, &req.LastName, &req.Message, &req.Status,
+		&req.ReviewedByID, &req.ReviewedAt, &req.UserID, &req.CreatedAt, &req.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// Create files a pending access request. Each person can only have one pending request.
+func (r *AccessRequestRepository) Create(ctx context.Context, req *models.AccessRequest) (*models.AccessRequest, error) {
+	ctx = withQueryName(ctx, "AccessRequestRepository.Create")
+	query := `
+		INSERT INTO access_requests (org_id, auth0_id, email, first_name, last_name, message)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + accessRequestColumns
+
+	created, err := scanAccessRequest(r.pool.QueryRow(ctx, query, req.OrgID, req.Auth0ID, req.Email, req.FirstName, req.LastName, req.Message))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access request: %w", err)
+	}
+	return created, nil
+}
+
+// GetByID retrieves an access request to the organization of ctx by ID
+func (r *AccessRequestRepository) GetByID(ctx context.Context, id int64) (*models.AccessRequest, error) {
+	ctx = withQueryName(ctx, "AccessRequestRepository.GetByID")
+	query := `SELECT ` + accessRequestColumns + ` FROM access_requests WHERE id = $1 AND ` + orgCondition("org_id", "$2")
+
+	req, err := scanAccessRequest(r.pool.QueryRow(ctx, query, id, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access request: %w", err)
+	}
+	return req, nil
+}
+
+// GetLatestByAuth0ID retrieves the most recent access request someone made
+func (r *AccessRequestRepository) GetLatestByAuth0ID(ctx context.Context, auth0ID string) (*models.AccessRequest, error) {
+	ctx = withQueryName(ctx, "AccessRequestRepository.GetLatestByAuth0ID")
+	query := `SELECT ` + accessRequestColumns + ` FROM access_requests WHERE auth0_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1`
+
+	req, err := scanAccessRequest(r.pool.QueryRow(ctx, query, auth0ID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access request: %w", err)
+	}
+	return req, nil
+}
+
+// List retrieves the access requests to the organization of ctx, oldest first, optionally only
+// those with a status
+func (r *AccessRequestRepository) List(ctx context.Context, status *models.AccessRequestStatus) ([]models.AccessRequest, error) {
+	ctx = withQueryName(ctx, "AccessRequestRepository.List")
+	query := `
+		SELECT ` + accessRequestColumns + `
+		FROM access_requests
+		WHERE ($1::text IS NULL OR status = $1) AND ` + orgCondition("org_id", "$2") + `
+		ORDER BY created_at, id`
+
+	rows, err := r.pool.Query(ctx, query, status, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []models.AccessRequest{}
+	for rows.Next() {
+		req, err := scanAccessRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan access request: %w", err)
+		}
+		requests = append(requests, *req)
+	}
+	return requests, rows.Err()
+}
+
+// Approve creates the account a pending access request asked for, set up as the approval says,
+// and marks the request approved
+func (r *AccessRequestRepository) Approve(ctx context.Context, id int64, approval *models.ApproveAccessRequestRequest, reviewerID int64) (*models.User, error) {
+	ctx = withQueryName(ctx, "AccessRequestRepository.Approve")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	req, err := scanAccessRequest(tx.QueryRow(ctx, `SELECT `+accessRequestColumns+` FROM access_requests
+		WHERE id = $1 AND status = 'pending' AND `+orgCondition("org_id", "$2")+` FOR UPDATE`, id, orgScope(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("pending access request not found")
+		}
+		return nil, fmt.Errorf("failed to get access request: %w", err)
+	}
+
+	userQuery := `
+		INSERT INTO users (auth0_id, email, first_name, last_name, role, title, department, supervisor_id, date_started, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), $9)
+		RETURNING ` + userColumns
+	user, err := scanUser(tx.QueryRow(ctx, userQuery, req.Auth0ID, req.Email, req.FirstName, req.LastName, approval.Role, approval.Title,
+		approval.Department, approval.SupervisorID, req.OrgID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user from access request: %w", err)
+	}
+
+	for _, squadID := range approval.SquadIDs {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO user_squads (user_id, squad_id)
+			SELECT $1, id FROM squads WHERE id = $2 AND org_id = $3
+			ON CONFLICT (user_id, squad_id) DO NOTHING
+		`, user.ID, squadID, req.OrgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to assign squad to user: %w", err)
+		}
+	}
+
+	// Start the user's history with what they were approved as
+	history := models.DiffUserHistory(&models.User{}, user)
+	if entry := models.SquadHistoryEntry(user.ID, nil, approval.SquadIDs); entry != nil {
+		history = append(history, *entry)
+	}
+	if err := recordUserHistory(ctx, tx, models.UserHistorySourceAccessRequest, &reviewerID, history); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE access_requests SET status = 'approved', reviewed_by_id = $2, reviewed_at = NOW(), user_id = $3, updated_at = NOW()
+		WHERE id = $1`, id, reviewerID, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve access request: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return user, nil
+}
+
+// Reject turns down a pending access request. Whoever made it can request access again.
+func (r *AccessRequestRepository) Reject(ctx context.Context, id int64, reviewerID int64) (*models.AccessRequest, error) {
+	ctx = withQueryName(ctx, "AccessRequestRepository.Reject")
+	query := `
+		UPDATE access_requests SET status = 'rejected', reviewed_by_id = $2, reviewed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'pending' AND ` + orgCondition("org_id", "$3") + `
+		RETURNING ` + accessRequestColumns
+
+	req, err := scanAccessRequest(r.pool.QueryRow(ctx, query, id, reviewerID, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("pending access request not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reject access request: %w", err)
+	}
+	return req, nil
+}
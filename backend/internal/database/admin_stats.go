@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// AdminStatsRepository aggregates the admin home page's stats
+type AdminStatsRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAdminStatsRepository creates a new admin stats repository
+func NewAdminStatsRepository(pool *pgxpool.Pool) *AdminStatsRepository {
+	return &AdminStatsRepository{pool: pool}
+}
+
+// Get aggregates the stats of the organization of ctx
+func (r *AdminStatsRepository) Get(ctx context.Context) (*models.AdminStats, error) {
+	ctx = withQueryName(ctx, "AdminStatsRepository.Get")
+	orgID := orgScope(ctx)
+	stats := &models.AdminStats{
+		Headcount: models.AdminHeadcount{
+			ByRole:       map[models.Role]int{},
+			ByDepartment: map[string]int{},
+		},
+		PendingTimeOff: models.PendingTimeOffCounts{ByType: map[models.TimeOffType]int{}},
+		GeneratedAt:    time.Now().UTC(),
+	}
+
+	// Each row counts a role or a department, told apart by which one is grouped
+	rows, err := r.pool.Query(ctx, `
+		SELECT GROUPING(role), role, COALESCE(department, ''), COUNT(*)
+		FROM users
+		WHERE is_active AND deleted_at IS NULL AND `+orgCondition("org_id", "$1")+`
+		GROUP BY GROUPING SETS ((role), (COALESCE(department, '')))`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get headcount: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var byDepartment, count int
+		var role *models.Role
+		var department string
+		if err := rows.Scan(&byDepartment, &role, &department, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan headcount: %w", err)
+		}
+		if byDepartment == 1 {
+			stats.Headcount.ByDepartment[department] = count
+			continue
+		}
+		stats.Headcount.ByRole[*role] = count
+		stats.Headcount.Total += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get headcount: %w", err)
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT request_type, COUNT(*)
+		FROM time_off_requests
+		WHERE status = 'pending' AND `+orgCondition("org_id", "$1")+`
+		GROUP BY request_type`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending time off: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var requestType models.TimeOffType
+		var count int
+		if err := rows.Scan(&requestType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan pending time off: %w", err)
+		}
+		stats.PendingTimeOff.ByType[requestType] = count
+		stats.PendingTimeOff.Total += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get pending time off: %w", err)
+	}
+
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM invitations
+			 WHERE status = 'pending' AND expires_at > NOW() AND ` + orgCondition("org_id", "$1") + `),
+			(SELECT COUNT(*) FROM users
+			 WHERE is_active AND deleted_at IS NULL AND COALESCE(jira_account_id, '') <> ''
+			   AND ` + orgCondition("org_id", "$1") + `),
+			attachments.count, attachments.bytes,
+			(SELECT COUNT(*) FROM export_jobs e
+			 JOIN users u ON u.id = e.requested_by_id
+			 WHERE e.status = 'completed' AND ` + orgCondition("u.org_id", "$1") + `)
+		FROM (
+			SELECT COUNT(*) AS count, COALESCE(SUM(a.size_bytes), 0) AS bytes
+			FROM meeting_attachments a
+			JOIN meetings m ON m.id = a.meeting_id
+			WHERE a.storage_key IS NOT NULL AND ` + orgCondition("m.org_id", "$1") + `
+		) attachments`
+	err = r.pool.QueryRow(ctx, query, orgID).Scan(
+		&stats.ActiveInvitations, &stats.JiraMapping.Mapped,
+		&stats.Storage.Attachments, &stats.Storage.AttachmentBytes, &stats.Storage.Exports,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin stats: %w", err)
+	}
+
+	stats.JiraMapping.Total = stats.Headcount.Total
+	if stats.JiraMapping.Total > 0 {
+		stats.JiraMapping.Percent = float64(stats.JiraMapping.Mapped) * 100 / float64(stats.JiraMapping.Total)
+	}
+	return stats, nil
+}
@@ -10,39 +10,58 @@ import (
 
 // CalendarRepository combines tasks, meetings, Jira issues, and time off into calendar events
 type CalendarRepository struct {
-	taskRepo    *TaskRepository
-	meetingRepo *MeetingRepository
-	timeOffRepo *TimeOffRepository
+	taskRepo         *TaskRepository
+	meetingRepo      *MeetingRepository
+	timeOffRepo      *TimeOffRepository
+	workScheduleRepo *WorkScheduleRepository
+	userRepo         *UserRepository
 }
 
-func NewCalendarRepository(taskRepo *TaskRepository, meetingRepo *MeetingRepository, timeOffRepo *TimeOffRepository) *CalendarRepository {
+func NewCalendarRepository(taskRepo *TaskRepository, meetingRepo *MeetingRepository, timeOffRepo *TimeOffRepository, workScheduleRepo *WorkScheduleRepository) *CalendarRepository {
 	return &CalendarRepository{
-		taskRepo:    taskRepo,
-		meetingRepo: meetingRepo,
-		timeOffRepo: timeOffRepo,
+		taskRepo:         taskRepo,
+		meetingRepo:      meetingRepo,
+		timeOffRepo:      timeOffRepo,
+		workScheduleRepo: workScheduleRepo,
 	}
 }
 
+// WithUsers adds team birthdays and work anniversaries to the calendar
+func (r *CalendarRepository) WithUsers(userRepo *UserRepository) *CalendarRepository {
+	r.userRepo = userRepo
+	return r
+}
+
+// HasMilestones reports whether team milestones are available
+func (r *CalendarRepository) HasMilestones() bool {
+	return r.userRepo != nil
+}
+
 // TimeOffRepo returns the underlying time off repository
 func (r *CalendarRepository) TimeOffRepo() *TimeOffRepository {
 	return r.timeOffRepo
 }
 
-// GetEvents retrieves all calendar events for a user within a date range
-// This combines tasks, meetings, and optionally Jira issues into a unified event list
-func (r *CalendarRepository) GetEvents(ctx context.Context, user *models.User, start, end time.Time, jiraIssues []models.JiraIssue) ([]models.CalendarEvent, error) {
-	var events []models.CalendarEvent
-
-	// Get tasks
+// GetTaskEvents returns the tasks visible to the user that are due within a date range, as all-day events.
+// Recurring tasks also show their upcoming occurrences, projected from the open instance.
+func (r *CalendarRepository) GetTaskEvents(ctx context.Context, user *models.User, start, end time.Time) ([]models.CalendarEvent, error) {
+	ctx = withQueryName(ctx, "CalendarRepository.GetTaskEvents")
 	tasks, err := r.taskRepo.GetVisibleTasks(ctx, user, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 
-	for i := range tasks {
-		task := &tasks[i]
+	expandedTasks := r.taskRepo.ExpandRecurringTasks(tasks, start, end)
+
+	events := make([]models.CalendarEvent, 0, len(expandedTasks))
+	for i := range expandedTasks {
+		task := &expandedTasks[i]
+		id := fmt.Sprintf("task-%d", task.ID)
+		if task.IsProjected {
+			id = fmt.Sprintf("task-%d-%s", task.ID, task.DueDate.Format("20060102"))
+		}
 		events = append(events, models.CalendarEvent{
-			ID:     fmt.Sprintf("task-%d", task.ID),
+			ID:     id,
 			Type:   models.CalendarEventTypeTask,
 			Title:  task.Title,
 			Start:  task.DueDate,
@@ -52,15 +71,21 @@ func (r *CalendarRepository) GetEvents(ctx context.Context, user *models.User, s
 		})
 	}
 
-	// Get meetings
+	return events, nil
+}
+
+// GetMeetingEvents returns the meeting occurrences visible to the user within a date range,
+// with recurring meetings expanded into one event per occurrence
+func (r *CalendarRepository) GetMeetingEvents(ctx context.Context, user *models.User, start, end time.Time) ([]models.CalendarEvent, error) {
+	ctx = withQueryName(ctx, "CalendarRepository.GetMeetingEvents")
 	meetings, err := r.meetingRepo.GetVisibleMeetings(ctx, user, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get meetings: %w", err)
 	}
 
-	// Expand recurring meetings
 	expandedMeetings := r.meetingRepo.ExpandRecurringMeetings(meetings, start, end)
 
+	events := make([]models.CalendarEvent, 0, len(expandedMeetings))
 	for i := range expandedMeetings {
 		meeting := &expandedMeetings[i]
 		events = append(events, models.CalendarEvent{
@@ -74,42 +99,73 @@ func (r *CalendarRepository) GetEvents(ctx context.Context, user *models.User, s
 		})
 	}
 
-	// Add Jira issues with due dates
+	return events, nil
+}
+
+// JiraEvents converts Jira issues with due dates in the range into all-day events
+func JiraEvents(jiraIssues []models.JiraIssue, start, end time.Time) []models.CalendarEvent {
+	events := []models.CalendarEvent{}
 	for i := range jiraIssues {
 		issue := &jiraIssues[i]
-		if issue.DueDate != nil {
-			// Only include issues with due dates in the range
-			if (issue.DueDate.After(start) || issue.DueDate.Equal(start)) &&
-				(issue.DueDate.Before(end) || issue.DueDate.Equal(end)) {
-				url := issue.URL
-				events = append(events, models.CalendarEvent{
-					ID:        fmt.Sprintf("jira-%s", issue.Key),
-					Type:      models.CalendarEventTypeJira,
-					Title:     fmt.Sprintf("[%s] %s", issue.Key, issue.Summary),
-					Start:     *issue.DueDate,
-					End:       nil, // Jira issues are all-day events
-					AllDay:    true,
-					URL:       &url,
-					JiraIssue: issue,
-				})
-			}
+		if issue.DueDate == nil || issue.DueDate.Before(start) || issue.DueDate.After(end) {
+			continue
+		}
+		url := issue.URL
+		events = append(events, models.CalendarEvent{
+			ID:        fmt.Sprintf("jira-%s", issue.Key),
+			Type:      models.CalendarEventTypeJira,
+			Title:     fmt.Sprintf("[%s] %s", issue.Key, issue.Summary),
+			Start:     *issue.DueDate,
+			End:       nil, // Jira issues are all-day events
+			AllDay:    true,
+			URL:       &url,
+			JiraIssue: issue,
+		})
+	}
+	return events
+}
+
+// GetFreeBusy returns the meeting occurrences and approved time off that overlap a window for the given users.
+// Meeting occurrences include every meeting the users organize or attend, regardless of who is asking.
+func (r *CalendarRepository) GetFreeBusy(ctx context.Context, userIDs []int64, start, end time.Time) ([]models.Meeting, []models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "CalendarRepository.GetFreeBusy")
+	meetings, err := r.meetingRepo.GetForUsersInRange(ctx, userIDs, start, end)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get meetings: %w", err)
+	}
+
+	// Expand from a day early so occurrences that start before the window but run into it are kept
+	var busy []models.Meeting
+	for _, occurrence := range r.meetingRepo.ExpandRecurringMeetings(meetings, start.AddDate(0, 0, -1), end) {
+		if occurrence.StartTime.Before(end) && occurrence.EndTime.After(start) {
+			busy = append(busy, occurrence)
 		}
 	}
 
-	// Add time off events if repository is available
+	var timeOff []models.TimeOffRequest
 	if r.timeOffRepo != nil {
-		timeOffEvents, err := r.GetTimeOffEvents(ctx, user, start, end)
+		// Time off is stored as dates, so compare against the start of the window's first day,
+		// a day early to allow for users whose day starts before UTC's
+		utcStart := start.UTC()
+		dayStart := time.Date(utcStart.Year(), utcStart.Month(), utcStart.Day()-1, 0, 0, 0, 0, time.UTC)
+		timeOff, err = r.timeOffRepo.GetApprovedForUsers(ctx, userIDs, dayStart, end)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get time off events: %w", err)
+			return nil, nil, fmt.Errorf("failed to get time off: %w", err)
 		}
-		events = append(events, timeOffEvents...)
 	}
 
-	return events, nil
+	return busy, timeOff, nil
+}
+
+// GetWorkSchedules returns the working hours and focus time of each of the given users, keyed by user ID
+func (r *CalendarRepository) GetWorkSchedules(ctx context.Context, userIDs []int64) (map[int64]*models.WorkSchedule, error) {
+	ctx = withQueryName(ctx, "CalendarRepository.GetWorkSchedules")
+	return r.workScheduleRepo.GetForUsers(ctx, userIDs)
 }
 
 // GetTimeOffEvents returns time off events for the current user and their team (if supervisor)
 func (r *CalendarRepository) GetTimeOffEvents(ctx context.Context, user *models.User, start, end time.Time) ([]models.CalendarEvent, error) {
+	ctx = withQueryName(ctx, "CalendarRepository.GetTimeOffEvents")
 	var events []models.CalendarEvent
 
 	// Get user's own approved time off
@@ -127,12 +183,12 @@ func (r *CalendarRepository) GetTimeOffEvents(ctx context.Context, user *models.
 		}
 	}
 
-	// If user is supervisor or admin, also get their team's time off
-	if user.IsSupervisorOrAdmin() {
+	// If user is supervisor, admin, or viewer, also get their team's time off
+	if user.IsSupervisorOrAdmin() || user.IsViewer() {
 		var teamTimeOff []models.TimeOffRequest
 		var err error
 
-		if user.IsAdmin() {
+		if user.IsAdmin() || user.IsViewer() {
 			// Admin: get all approved time off in date range
 			teamTimeOff, err = r.timeOffRepo.GetApprovedByDateRange(ctx, 0, start, end)
 			if err != nil {
@@ -159,6 +215,11 @@ func (r *CalendarRepository) GetTimeOffEvents(ctx context.Context, user *models.
 				if to.User != nil {
 					userName = to.User.FirstName + " " + to.User.LastName
 				}
+				// Viewers see availability only, not reasons or reviewer notes
+				if user.IsViewer() {
+					redacted := to.WithoutNotes()
+					to = &redacted
+				}
 				events = append(events, r.createTimeOffEvent(to, userName))
 			}
 		}
@@ -167,6 +228,41 @@ func (r *CalendarRepository) GetTimeOffEvents(ctx context.Context, user *models.
 	return events, nil
 }
 
+// GetMilestoneEvents returns the birthdays and work anniversaries of the user's team within a date
+// range, as all-day events. Admins see the whole organization and supervisors their direct reports;
+// other users have no team milestones.
+func (r *CalendarRepository) GetMilestoneEvents(ctx context.Context, user *models.User, start, end time.Time) ([]models.CalendarEvent, error) {
+	ctx = withQueryName(ctx, "CalendarRepository.GetMilestoneEvents")
+	var team []models.User
+	var err error
+	switch {
+	case user.IsAdmin():
+		team, err = r.userRepo.GetAll(ctx)
+	case user.IsSupervisor():
+		team, err = r.userRepo.GetDirectReportsBySupervisorID(ctx, user.ID)
+	default:
+		return []models.CalendarEvent{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team for milestones: %w", err)
+	}
+
+	milestones := models.MilestonesBetween(team, start, end)
+	events := make([]models.CalendarEvent, 0, len(milestones))
+	for i := range milestones {
+		m := &milestones[i]
+		events = append(events, models.CalendarEvent{
+			ID:        fmt.Sprintf("milestone-%s-%d-%s", m.Type, m.UserID, m.Date.Format("20060102")),
+			Type:      models.CalendarEventTypeMilestone,
+			Title:     m.Title(),
+			Start:     m.Date,
+			AllDay:    true,
+			Milestone: m,
+		})
+	}
+	return events, nil
+}
+
 // timeOffOverlaps checks if a time off request overlaps with a date range
 func (r *CalendarRepository) timeOffOverlaps(to *models.TimeOffRequest, start, end time.Time) bool {
 	// Time off overlaps if it starts before range ends AND ends after range starts
@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+const customFieldColumns = `id, key, label, field_type, options, required, position, created_at, updated_at`
+
+// CustomFieldRepository handles database operations for admin-defined profile fields and
+// users' values for them
+type CustomFieldRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCustomFieldRepository creates a new custom field repository
+func NewCustomFieldRepository(pool *pgxpool.Pool) *CustomFieldRepository {
+	return &CustomFieldRepository{pool: pool}
+}
+
+// scanCustomField scans a row into a CustomField
+func scanCustomField(row pgx.Row) (*models.CustomField, error) {
+	var field models.CustomField
+	err := row.Scan(
+		&field.ID, &field.Key, &field.Label, &field.Type, &field.Options,
+		&field.Required, &field.Position, &field.CreatedAt, &field.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(field.Options) == 0 {
+		field.Options = nil
+	}
+	return &field, nil
+}
+
+// GetAll retrieves every custom field of the organization in display order
+func (r *CustomFieldRepository) GetAll(ctx context.Context) ([]models.CustomField, error) {
+	ctx = withQueryName(ctx, "CustomFieldRepository.GetAll")
+	query := `SELECT ` + customFieldColumns + ` FROM custom_fields WHERE ` + orgCondition("org_id", "$1") + ` ORDER BY position, id`
+	rows, err := r.pool.Query(ctx, query, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := []models.CustomField{}
+	for rows.Next() {
+		field, err := scanCustomField(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom field: %w", err)
+		}
+		fields = append(fields, *field)
+	}
+	return fields, rows.Err()
+}
+
+// GetByID retrieves a custom field, or nil if it doesn't exist
+func (r *CustomFieldRepository) GetByID(ctx context.Context, id int64) (*models.CustomField, error) {
+	ctx = withQueryName(ctx, "CustomFieldRepository.GetByID")
+	query := `SELECT ` + customFieldColumns + ` FROM custom_fields WHERE id = $1 AND ` + orgCondition("org_id", "$2")
+	field, err := scanCustomField(r.pool.QueryRow(ctx, query, id, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom field: %w", err)
+	}
+	return field, nil
+}
+
+// GetByKey retrieves a custom field by its key, or nil if it doesn't exist
+func (r *CustomFieldRepository) GetByKey(ctx context.Context, key string) (*models.CustomField, error) {
+	ctx = withQueryName(ctx, "CustomFieldRepository.GetByKey")
+	query := `SELECT ` + customFieldColumns + ` FROM custom_fields WHERE key = $1 AND ` + orgCondition("org_id", "$2")
+	field, err := scanCustomField(r.pool.QueryRow(ctx, query, key, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom field by key: %w", err)
+	}
+	return field, nil
+}
+
+// Create creates a custom field in the organization ctx is scoped to
+func (r *CustomFieldRepository) Create(ctx context.Context, req *models.CreateCustomFieldRequest) (*models.CustomField, error) {
+	ctx = withQueryName(ctx, "CustomFieldRepository.Create")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		INSERT INTO custom_fields (key, label, field_type, options, required, position, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + customFieldColumns
+	field, err := scanCustomField(r.pool.QueryRow(ctx, query,
+		req.Key, req.Label, req.Type, optionsOrEmpty(req.Options), req.Required, req.Position,
+		orgID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create custom field: %w", err)
+	}
+	return field, nil
+}
+
+// Update replaces a custom field's label, options, required flag, and position, returning nil if
+// the field doesn't exist
+func (r *CustomFieldRepository) Update(ctx context.Context, id int64, req *models.UpdateCustomFieldRequest) (*models.CustomField, error) {
+	ctx = withQueryName(ctx, "CustomFieldRepository.Update")
+	query := `
+		UPDATE custom_fields
+		SET label = $2, options = $3, required = $4, position = $5, updated_at = NOW()
+		WHERE id = $1 AND ` + orgCondition("org_id", "$6") + `
+		RETURNING ` + customFieldColumns
+	field, err := scanCustomField(r.pool.QueryRow(ctx, query,
+		id, req.Label, optionsOrEmpty(req.Options), req.Required, req.Position, orgScope(ctx),
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update custom field: %w", err)
+	}
+	return field, nil
+}
+
+// Delete removes a custom field along with every user's value for it
+func (r *CustomFieldRepository) Delete(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "CustomFieldRepository.Delete")
+	result, err := r.pool.Exec(ctx, `DELETE FROM custom_fields WHERE id = $1 AND `+orgCondition("org_id", "$2"), id, orgScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("custom field not found")
+	}
+	return nil
+}
+
+// GetValuesForUsers retrieves the custom field values of multiple users in a single query.
+// Returns a map of userID -> field key -> value; users without values are left out.
+func (r *CustomFieldRepository) GetValuesForUsers(ctx context.Context, userIDs []int64) (map[int64]map[string]string, error) {
+	ctx = withQueryName(ctx, "CustomFieldRepository.GetValuesForUsers")
+	result := make(map[int64]map[string]string)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT v.user_id, f.key, v.value
+		FROM user_custom_values v
+		JOIN custom_fields f ON f.id = v.field_id
+		WHERE v.user_id = ANY($1) AND ` + orgCondition("f.org_id", "$2") + `
+	`
+	rows, err := r.pool.Query(ctx, query, userIDs, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom field values: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int64
+		var key, value string
+		if err := rows.Scan(&userID, &key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan custom field value: %w", err)
+		}
+		if result[userID] == nil {
+			result[userID] = make(map[string]string)
+		}
+		result[userID][key] = value
+	}
+	return result, rows.Err()
+}
+
+// SetUserValues sets a user's values for the given fields, keyed by field ID.
+// Empty values clear the field; fields not given, or not the organization's, are left alone.
+func (r *CustomFieldRepository) SetUserValues(ctx context.Context, userID int64, values map[int64]string) error {
+	ctx = withQueryName(ctx, "CustomFieldRepository.SetUserValues")
+	if len(values) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for fieldID, value := range values {
+		if value == "" {
+			batch.Queue(`DELETE FROM user_custom_values WHERE user_id = $1 AND field_id = $2`, userID, fieldID)
+			continue
+		}
+		batch.Queue(`
+			INSERT INTO user_custom_values (user_id, field_id, value)
+			SELECT $1, id, $3 FROM custom_fields WHERE id = $2 AND `+orgCondition("org_id", "$4")+`
+			ON CONFLICT (user_id, field_id) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+		`, userID, fieldID, value, orgScope(ctx))
+	}
+
+	// A batch runs in a single implicit transaction
+	br := r.pool.SendBatch(ctx, batch)
+	defer func() { _ = br.Close() }()
+	for range values {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("failed to set custom field value: %w", err)
+		}
+	}
+	return nil
+}
+
+// optionsOrEmpty keeps nil options from being stored as NULL
+func optionsOrEmpty(options []string) []string {
+	if options == nil {
+		return []string{}
+	}
+	return options
+}
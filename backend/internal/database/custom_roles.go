@@ -0,0 +1,190 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+const customRoleColumns = `cr.id, cr.name, cr.description, cr.permissions,
+	(SELECT COUNT(*) FROM user_custom_roles ucr WHERE ucr.role_id = cr.id),
+	cr.created_by_id, cr.created_at, cr.updated_at`
+
+// CustomRoleRepository handles database operations for custom roles and their assignment to users
+type CustomRoleRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCustomRoleRepository creates a new custom role repository
+func NewCustomRoleRepository(pool *pgxpool.Pool) *CustomRoleRepository {
+	return &CustomRoleRepository{pool: pool}
+}
+
+// scanCustomRole scans a row into a CustomRole
+func scanCustomRole(row pgx.Row) (*models.CustomRole, error) {
+	var role models.CustomRole
+	err := row.Scan(&role.ID, &role.Name, &role.Description, &role.Permissions, &role.UserCount,
+		&role.CreatedByID, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if role.Permissions == nil {
+		role.Permissions = []models.RolePermission{}
+	}
+	return &role, nil
+}
+
+// queryCustomRoles runs a query selecting customRoleColumns and scans every row
+func (r *CustomRoleRepository) queryCustomRoles(ctx context.Context, query string, args ...any) ([]models.CustomRole, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []models.CustomRole{}
+	for rows.Next() {
+		role, err := scanCustomRole(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom role: %w", err)
+		}
+		roles = append(roles, *role)
+	}
+	return roles, rows.Err()
+}
+
+// GetAll retrieves every custom role of the organization ordered by name
+func (r *CustomRoleRepository) GetAll(ctx context.Context) ([]models.CustomRole, error) {
+	ctx = withQueryName(ctx, "CustomRoleRepository.GetAll")
+	roles, err := r.queryCustomRoles(ctx, `SELECT `+customRoleColumns+` FROM custom_roles cr WHERE `+orgCondition("cr.org_id", "$1")+` ORDER BY LOWER(cr.name)`, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom roles: %w", err)
+	}
+	return roles, nil
+}
+
+// GetByID retrieves a custom role, or nil if it doesn't exist
+func (r *CustomRoleRepository) GetByID(ctx context.Context, id int64) (*models.CustomRole, error) {
+	ctx = withQueryName(ctx, "CustomRoleRepository.GetByID")
+	role, err := scanCustomRole(r.pool.QueryRow(ctx, `SELECT `+customRoleColumns+` FROM custom_roles cr WHERE cr.id = $1 AND `+orgCondition("cr.org_id", "$2"), id, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom role: %w", err)
+	}
+	return role, nil
+}
+
+// GetByName retrieves a custom role by name ignoring case, or nil if it doesn't exist
+func (r *CustomRoleRepository) GetByName(ctx context.Context, name string) (*models.CustomRole, error) {
+	ctx = withQueryName(ctx, "CustomRoleRepository.GetByName")
+	role, err := scanCustomRole(r.pool.QueryRow(ctx, `SELECT `+customRoleColumns+` FROM custom_roles cr WHERE LOWER(cr.name) = LOWER($1) AND `+orgCondition("cr.org_id", "$2"), name, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom role by name: %w", err)
+	}
+	return role, nil
+}
+
+// Create adds a custom role to the organization ctx is scoped to
+func (r *CustomRoleRepository) Create(ctx context.Context, req *models.CustomRoleRequest, createdByID int64) (*models.CustomRole, error) {
+	ctx = withQueryName(ctx, "CustomRoleRepository.Create")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var id int64
+	err = r.pool.QueryRow(ctx, `
+		INSERT INTO custom_roles (name, description, permissions, created_by_id, org_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		req.Name, req.Description, req.Permissions, createdByID, orgID,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create custom role: %w", err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Update replaces a custom role's name, description, and permissions, returning nil if it doesn't exist
+func (r *CustomRoleRepository) Update(ctx context.Context, id int64, req *models.CustomRoleRequest) (*models.CustomRole, error) {
+	ctx = withQueryName(ctx, "CustomRoleRepository.Update")
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE custom_roles SET name = $2, description = $3, permissions = $4, updated_at = NOW()
+		WHERE id = $1 AND `+orgCondition("org_id", "$5"),
+		id, req.Name, req.Description, req.Permissions, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update custom role: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, nil
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Delete removes a custom role, unassigning it from everyone who had it
+func (r *CustomRoleRepository) Delete(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "CustomRoleRepository.Delete")
+	if _, err := r.pool.Exec(ctx, `DELETE FROM custom_roles WHERE id = $1 AND `+orgCondition("org_id", "$2"), id, orgScope(ctx)); err != nil {
+		return fmt.Errorf("failed to delete custom role: %w", err)
+	}
+	return nil
+}
+
+// GetForUser retrieves the custom roles assigned to a user ordered by name
+func (r *CustomRoleRepository) GetForUser(ctx context.Context, userID int64) ([]models.CustomRole, error) {
+	ctx = withQueryName(ctx, "CustomRoleRepository.GetForUser")
+	roles, err := r.queryCustomRoles(ctx, `
+		SELECT `+customRoleColumns+`
+		FROM custom_roles cr
+		JOIN user_custom_roles assigned ON assigned.role_id = cr.id
+		WHERE assigned.user_id = $1 AND `+orgCondition("cr.org_id", "$2")+`
+		ORDER BY LOWER(cr.name)`, userID, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user's custom roles: %w", err)
+	}
+	return roles, nil
+}
+
+// SetForUser replaces the custom roles assigned to a user. Roles the user already had keep their
+// original assignment, and roles of other organizations are skipped.
+func (r *CustomRoleRepository) SetForUser(ctx context.Context, userID int64, roleIDs []int64, assignedByID int64) error {
+	ctx = withQueryName(ctx, "CustomRoleRepository.SetForUser")
+	// A nil slice would be sent as NULL, and NOT (role_id = ANY(NULL)) removes nothing
+	if roleIDs == nil {
+		roleIDs = []int64{}
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM user_custom_roles WHERE user_id = $1 AND NOT (role_id = ANY($2))`,
+		userID, roleIDs); err != nil {
+		return fmt.Errorf("failed to remove custom roles: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO user_custom_roles (user_id, role_id, assigned_by_id)
+		SELECT $1, cr.id, $3 FROM custom_roles cr
+		WHERE cr.id = ANY($2) AND `+orgCondition("cr.org_id", "$4")+`
+		ON CONFLICT (user_id, role_id) DO NOTHING`,
+		userID, roleIDs, assignedByID, orgScope(ctx)); err != nil {
+		return fmt.Errorf("failed to assign custom roles: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -3,12 +3,17 @@ package database
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -72,7 +77,22 @@ func Connect(databaseURL string, poolCfg *PoolConfig) (*pgxpool.Pool, error) {
 	return pool, nil
 }
 
-// RunMigrations runs all pending database migrations using golang-migrate
+// readPool returns the replica for reads that can tolerate replication lag, or the primary when no
+// replica is configured. Reads that must see a write the caller just made stay on the primary.
+func readPool(primary, replica *pgxpool.Pool) *pgxpool.Pool {
+	if replica != nil {
+		return replica
+	}
+	return primary
+}
+
+// migrationLockTimeout is how long a replica waits for another to finish migrating. It must
+// outlast the longest migration, or replicas starting during one fail and restart until it's done.
+const migrationLockTimeout = 15 * time.Minute
+
+// RunMigrations runs all pending database migrations using golang-migrate. Its postgres driver
+// holds an advisory lock while migrating, so replicas starting at the same time take turns: the
+// first applies the migrations and the rest wait, then find nothing left to do.
 func RunMigrations(databaseURL string) error {
 	// Create migration source from embedded filesystem
 	source, err := iofs.New(migrationsFS, "migrations")
@@ -86,6 +106,7 @@ func RunMigrations(databaseURL string) error {
 		return fmt.Errorf("failed to create migrate instance: %w", err)
 	}
 	defer func() { _, _ = m.Close() }()
+	m.LockTimeout = migrationLockTimeout
 
 	// Run migrations
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
@@ -155,3 +176,63 @@ func GetMigrationVersion(databaseURL string) (uint, bool, error) {
 
 	return version, dirty, nil
 }
+
+// MigrationStatus describes the schema version of the database against the migrations this build
+// embeds
+type MigrationStatus struct {
+	Version uint `json:"version"`
+	Latest  uint `json:"latest"`
+	Dirty   bool `json:"dirty"`
+	// Pending counts embedded migrations not yet applied
+	Pending int `json:"pending"`
+}
+
+// GetMigrationStatus reads the applied migration version through an existing pool, so it's cheap
+// enough for health checks
+func GetMigrationStatus(ctx context.Context, pool *pgxpool.Pool) (*MigrationStatus, error) {
+	versions, err := embeddedMigrationVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MigrationStatus{}
+	var version int64
+	err = pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &status.Dirty)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get migration version: %w", err)
+	}
+	status.Version = uint(version)
+
+	for _, v := range versions {
+		if v > status.Latest {
+			status.Latest = v
+		}
+		if v > status.Version {
+			status.Pending++
+		}
+	}
+	return status, nil
+}
+
+// embeddedMigrationVersions lists the versions of the embedded up migrations
+func embeddedMigrationVersions() ([]uint, error) {
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var versions []uint
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+		versions = append(versions, uint(version))
+	}
+	return versions, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
 )
 
 // DepartmentRepository handles database operations for departments
@@ -19,10 +20,11 @@ func NewDepartmentRepository(pool *pgxpool.Pool) *DepartmentRepository {
 	return &DepartmentRepository{pool: pool}
 }
 
-// GetAll retrieves all departments ordered by name
+// GetAll retrieves the organization's departments ordered by name
 func (r *DepartmentRepository) GetAll(ctx context.Context) ([]models.Department, error) {
-	query := `SELECT id, name, created_at, updated_at FROM departments ORDER BY name`
-	rows, err := r.pool.Query(ctx, query)
+	ctx = withQueryName(ctx, "DepartmentRepository.GetAll")
+	query := `SELECT id, name, created_at, updated_at FROM departments WHERE ` + orgCondition("org_id", "$1") + ` ORDER BY name`
+	rows, err := r.pool.Query(ctx, query, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get departments: %w", err)
 	}
@@ -40,10 +42,11 @@ func (r *DepartmentRepository) GetAll(ctx context.Context) ([]models.Department,
 	return departments, nil
 }
 
-// GetAllNames retrieves all department names ordered alphabetically
+// GetAllNames retrieves the organization's department names ordered alphabetically
 func (r *DepartmentRepository) GetAllNames(ctx context.Context) ([]string, error) {
-	query := `SELECT name FROM departments ORDER BY name`
-	rows, err := r.pool.Query(ctx, query)
+	ctx = withQueryName(ctx, "DepartmentRepository.GetAllNames")
+	query := `SELECT name FROM departments WHERE ` + orgCondition("org_id", "$1") + ` ORDER BY name`
+	rows, err := r.pool.Query(ctx, query, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get department names: %w", err)
 	}
@@ -62,9 +65,10 @@ func (r *DepartmentRepository) GetAllNames(ctx context.Context) ([]string, error
 
 // GetByID retrieves a department by its ID
 func (r *DepartmentRepository) GetByID(ctx context.Context, id int64) (*models.Department, error) {
-	query := `SELECT id, name, created_at, updated_at FROM departments WHERE id = $1`
+	ctx = withQueryName(ctx, "DepartmentRepository.GetByID")
+	query := `SELECT id, name, created_at, updated_at FROM departments WHERE id = $1 AND ` + orgCondition("org_id", "$2")
 	var dept models.Department
-	err := r.pool.QueryRow(ctx, query, id).Scan(&dept.ID, &dept.Name, &dept.CreatedAt, &dept.UpdatedAt)
+	err := r.pool.QueryRow(ctx, query, id, orgScope(ctx)).Scan(&dept.ID, &dept.Name, &dept.CreatedAt, &dept.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get department by ID: %w", err)
 	}
@@ -73,28 +77,35 @@ func (r *DepartmentRepository) GetByID(ctx context.Context, id int64) (*models.D
 
 // GetByName retrieves a department by its name
 func (r *DepartmentRepository) GetByName(ctx context.Context, name string) (*models.Department, error) {
-	query := `SELECT id, name, created_at, updated_at FROM departments WHERE name = $1`
+	ctx = withQueryName(ctx, "DepartmentRepository.GetByName")
+	query := `SELECT id, name, created_at, updated_at FROM departments WHERE name = $1 AND ` + orgCondition("org_id", "$2")
 	var dept models.Department
-	err := r.pool.QueryRow(ctx, query, name).Scan(&dept.ID, &dept.Name, &dept.CreatedAt, &dept.UpdatedAt)
+	err := r.pool.QueryRow(ctx, query, name, orgScope(ctx)).Scan(&dept.ID, &dept.Name, &dept.CreatedAt, &dept.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get department by name: %w", err)
 	}
 	return &dept, nil
 }
 
-// Create creates a new department
+// Create creates a new department in the organization ctx is scoped to
 func (r *DepartmentRepository) Create(ctx context.Context, name string) (*models.Department, error) {
-	query := `INSERT INTO departments (name) VALUES ($1) RETURNING id, name, created_at, updated_at`
+	ctx = withQueryName(ctx, "DepartmentRepository.Create")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `INSERT INTO departments (name, org_id) VALUES ($1, $2) RETURNING id, name, created_at, updated_at`
 	var dept models.Department
-	err := r.pool.QueryRow(ctx, query, name).Scan(&dept.ID, &dept.Name, &dept.CreatedAt, &dept.UpdatedAt)
+	err = r.pool.QueryRow(ctx, query, name, orgID).Scan(&dept.ID, &dept.Name, &dept.CreatedAt, &dept.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create department: %w", err)
 	}
 	return &dept, nil
 }
 
-// Delete removes a department by name and clears it from all users
+// Delete removes a department by name and clears it from all of the organization's users
 func (r *DepartmentRepository) Delete(ctx context.Context, name string) error {
+	ctx = withQueryName(ctx, "DepartmentRepository.Delete")
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -102,13 +113,13 @@ func (r *DepartmentRepository) Delete(ctx context.Context, name string) error {
 	defer func() { _ = tx.Rollback(ctx) }()
 
 	// Clear department from all users
-	_, err = tx.Exec(ctx, `UPDATE users SET department = '', updated_at = $1 WHERE department = $2`, time.Now(), name)
+	_, err = tx.Exec(ctx, `UPDATE users SET department = '', updated_at = $1 WHERE department = $2 AND `+orgCondition("org_id", "$3"), time.Now(), name, orgScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to clear department from users: %w", err)
 	}
 
 	// Delete from departments table
-	result, err := tx.Exec(ctx, `DELETE FROM departments WHERE name = $1`, name)
+	result, err := tx.Exec(ctx, `DELETE FROM departments WHERE name = $1 AND `+orgCondition("org_id", "$2"), name, orgScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to delete department: %w", err)
 	}
@@ -122,8 +133,9 @@ func (r *DepartmentRepository) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
-// Rename updates the name of a department and updates all users
+// Rename updates the name of a department and updates all of the organization's users
 func (r *DepartmentRepository) Rename(ctx context.Context, oldName, newName string) error {
+	ctx = withQueryName(ctx, "DepartmentRepository.Rename")
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -133,7 +145,7 @@ func (r *DepartmentRepository) Rename(ctx context.Context, oldName, newName stri
 	now := time.Now()
 
 	// Update department name in departments table
-	result, err := tx.Exec(ctx, `UPDATE departments SET name = $1, updated_at = $2 WHERE name = $3`, newName, now, oldName)
+	result, err := tx.Exec(ctx, `UPDATE departments SET name = $1, updated_at = $2 WHERE name = $3 AND `+orgCondition("org_id", "$4"), newName, now, oldName, orgScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to rename department: %w", err)
 	}
@@ -142,7 +154,7 @@ func (r *DepartmentRepository) Rename(ctx context.Context, oldName, newName stri
 	}
 
 	// Update all users with the old department name
-	_, err = tx.Exec(ctx, `UPDATE users SET department = $1, updated_at = $2 WHERE department = $3`, newName, now, oldName)
+	_, err = tx.Exec(ctx, `UPDATE users SET department = $1, updated_at = $2 WHERE department = $3 AND `+orgCondition("org_id", "$4"), newName, now, oldName, orgScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to update users with new department name: %w", err)
 	}
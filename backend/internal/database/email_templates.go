@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+const emailTemplateColumns = `template_key, language, subject, html_body, text_body, updated_by_id, updated_at`
+
+// EmailTemplateRepository handles organizations' overrides of the built-in email templates. It acts
+// on the overrides of the organization ctx is scoped to, failing with tenant.ErrNoOrg when
+// organizations are required and ctx isn't scoped to one.
+type EmailTemplateRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewEmailTemplateRepository creates a new email template repository
+func NewEmailTemplateRepository(pool *pgxpool.Pool) *EmailTemplateRepository {
+	return &EmailTemplateRepository{pool: pool}
+}
+
+// scanEmailTemplate scans a row of emailTemplateColumns into a customized EmailTemplate
+func scanEmailTemplate(row pgx.Row) (*models.EmailTemplate, error) {
+	t := models.EmailTemplate{Customized: true}
+	err := row.Scan(&t.Key, &t.Language, &t.Subject, &t.HTMLBody, &t.TextBody, &t.UpdatedByID, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Get returns the organization's override of an email in a language, or nil if it has none
+func (r *EmailTemplateRepository) Get(ctx context.Context, key models.EmailTemplateKey, language string) (*models.EmailTemplate, error) {
+	ctx = withQueryName(ctx, "EmailTemplateRepository.Get")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + emailTemplateColumns + ` FROM email_templates WHERE org_id = $1 AND template_key = $2 AND language = $3`
+	t, err := scanEmailTemplate(r.pool.QueryRow(ctx, query, orgID, key, language))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email template: %w", err)
+	}
+	return t, nil
+}
+
+// List returns all of the organization's overrides
+func (r *EmailTemplateRepository) List(ctx context.Context) ([]models.EmailTemplate, error) {
+	ctx = withQueryName(ctx, "EmailTemplateRepository.List")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + emailTemplateColumns + ` FROM email_templates WHERE org_id = $1 ORDER BY template_key, language`
+	rows, err := r.pool.Query(ctx, query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list email templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []models.EmailTemplate{}
+	for rows.Next() {
+		t, err := scanEmailTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan email template: %w", err)
+		}
+		templates = append(templates, *t)
+	}
+	return templates, rows.Err()
+}
+
+// Save creates or replaces the organization's override of t's email in t's language
+func (r *EmailTemplateRepository) Save(ctx context.Context, t *models.EmailTemplate) error {
+	ctx = withQueryName(ctx, "EmailTemplateRepository.Save")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO email_templates (org_id, template_key, language, subject, html_body, text_body, updated_by_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (org_id, template_key, language) DO UPDATE SET
+			subject = EXCLUDED.subject,
+			html_body = EXCLUDED.html_body,
+			text_body = EXCLUDED.text_body,
+			updated_by_id = EXCLUDED.updated_by_id,
+			updated_at = NOW()
+		RETURNING updated_at`
+	err = r.pool.QueryRow(ctx, query, orgID, t.Key, t.Language, t.Subject, t.HTMLBody, t.TextBody,
+		t.UpdatedByID).Scan(&t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save email template: %w", err)
+	}
+	t.Customized = true
+	return nil
+}
+
+// Delete removes the organization's override of an email in a language, restoring the built-in one
+func (r *EmailTemplateRepository) Delete(ctx context.Context, key models.EmailTemplateKey, language string) error {
+	ctx = withQueryName(ctx, "EmailTemplateRepository.Delete")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, `DELETE FROM email_templates WHERE org_id = $1 AND template_key = $2 AND language = $3`,
+		orgID, key, language)
+	if err != nil {
+		return fmt.Errorf("failed to delete email template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const exportJobColumns = `id, requested_by_id, type, format, filters, status, storage_key, file_name,
+	row_count, error, created_at, started_at, completed_at, expires_at`
+
+type ExportJobRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewExportJobRepository(pool *pgxpool.Pool) *ExportJobRepository {
+	return &ExportJobRepository{pool: pool}
+}
+
+// scanExportJob scans a row of exportJobColumns into an ExportJob
+func scanExportJob(row pgx.Row) (*models.ExportJob, error) {
+	var j models.ExportJob
+	var filters []byte
+	err := row.Scan(
+		&j.ID, &j.RequestedByID, &j.Type, &j.Format, &filters, &j.Status, &j.StorageKey, &j.FileName,
+		&j.RowCount, &j.Error, &j.CreatedAt, &j.StartedAt, &j.CompletedAt, &j.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filters, &j.Filters); err != nil {
+		return nil, fmt.Errorf("failed to decode export filters: %w", err)
+	}
+	return &j, nil
+}
+
+// Create queues a new export job
+func (r *ExportJobRepository) Create(ctx context.Context, requestedByID int64, req *models.CreateExportJobRequest) (*models.ExportJob, error) {
+	ctx = withQueryName(ctx, "ExportJobRepository.Create")
+	filters, err := json.Marshal(req.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export filters: %w", err)
+	}
+
+	query := `
+		INSERT INTO export_jobs (requested_by_id, type, format, filters)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + exportJobColumns
+
+	job, err := scanExportJob(r.pool.QueryRow(ctx, query, requestedByID, req.Type, req.Format, filters))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+	return job, nil
+}
+
+// GetByID retrieves an export job
+func (r *ExportJobRepository) GetByID(ctx context.Context, id int64) (*models.ExportJob, error) {
+	ctx = withQueryName(ctx, "ExportJobRepository.GetByID")
+	query := `SELECT ` + exportJobColumns + ` FROM export_jobs WHERE id = $1`
+
+	job, err := scanExportJob(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export job: %w", err)
+	}
+	return job, nil
+}
+
+// ListByUser retrieves a user's most recent export jobs
+func (r *ExportJobRepository) ListByUser(ctx context.Context, userID int64, limit int) ([]models.ExportJob, error) {
+	ctx = withQueryName(ctx, "ExportJobRepository.ListByUser")
+	query := `
+		SELECT ` + exportJobColumns + `
+		FROM export_jobs
+		WHERE requested_by_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`
+
+	return r.queryExportJobs(ctx, query, userID, limit)
+}
+
+// ClaimNext marks the oldest pending job as running and returns it, or nil if there is none.
+// Jobs left running longer than staleAfter (for example by a crashed worker) are claimed again.
+// SKIP LOCKED lets several workers poll the table without picking up the same job.
+func (r *ExportJobRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.ExportJob, error) {
+	ctx = withQueryName(ctx, "ExportJobRepository.ClaimNext")
+	query := `
+		UPDATE export_jobs
+		SET status = 'running', started_at = NOW()
+		WHERE id = (
+			SELECT id FROM export_jobs
+			WHERE status = 'pending'
+			OR (status = 'running' AND started_at < NOW() - $1 * INTERVAL '1 second')
+			ORDER BY created_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + exportJobColumns
+
+	job, err := scanExportJob(r.pool.QueryRow(ctx, query, int64(staleAfter.Seconds())))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim export job: %w", err)
+	}
+	return job, nil
+}
+
+// Complete records the generated file of a running job
+func (r *ExportJobRepository) Complete(ctx context.Context, id int64, storageKey, fileName string, rowCount int, expiresAt time.Time) error {
+	ctx = withQueryName(ctx, "ExportJobRepository.Complete")
+	query := `
+		UPDATE export_jobs
+		SET status = 'completed', storage_key = $2, file_name = $3, row_count = $4,
+			expires_at = $5, error = NULL, completed_at = NOW()
+		WHERE id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, id, storageKey, fileName, rowCount, expiresAt); err != nil {
+		return fmt.Errorf("failed to complete export job: %w", err)
+	}
+	return nil
+}
+
+// Fail records why a running job could not be completed
+func (r *ExportJobRepository) Fail(ctx context.Context, id int64, message string) error {
+	ctx = withQueryName(ctx, "ExportJobRepository.Fail")
+	query := `UPDATE export_jobs SET status = 'failed', error = $2, completed_at = NOW() WHERE id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, id, message); err != nil {
+		return fmt.Errorf("failed to fail export job: %w", err)
+	}
+	return nil
+}
+
+// ListExpired retrieves completed jobs whose files are past their retention period
+func (r *ExportJobRepository) ListExpired(ctx context.Context, limit int) ([]models.ExportJob, error) {
+	ctx = withQueryName(ctx, "ExportJobRepository.ListExpired")
+	query := `
+		SELECT ` + exportJobColumns + `
+		FROM export_jobs
+		WHERE status = 'completed' AND expires_at < NOW()
+		ORDER BY expires_at
+		LIMIT $1`
+
+	return r.queryExportJobs(ctx, query, limit)
+}
+
+// MarkExpired records that a job's file has been removed
+func (r *ExportJobRepository) MarkExpired(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "ExportJobRepository.MarkExpired")
+	query := `UPDATE export_jobs SET status = 'expired', storage_key = NULL WHERE id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to expire export job: %w", err)
+	}
+	return nil
+}
+
+// queryExportJobs runs a query selecting exportJobColumns
+func (r *ExportJobRepository) queryExportJobs(ctx context.Context, query string, args ...interface{}) ([]models.ExportJob, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query export jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []models.ExportJob{}
+	for rows.Next() {
+		job, err := scanExportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan export job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
@@ -0,0 +1,367 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// goalSelect selects goals along with their owner's display name
+const goalSelect = `
+	SELECT g.id, g.title, g.description, g.owner_type, COALESCE(g.owner_user_id, g.owner_squad_id),
+		g.owner_department, COALESCE(u.first_name || ' ' || u.last_name, s.name, g.owner_department, ''),
+		g.quarter, g.created_by_id, g.created_at, g.updated_at
+	FROM goals g
+	LEFT JOIN users u ON u.id = g.owner_user_id
+	LEFT JOIN squads s ON s.id = g.owner_squad_id`
+
+const keyResultColumns = `id, goal_id, title, start_value, target_value, current_value, unit, updated_at`
+
+const progressUpdateColumns = `p.id, p.key_result_id, p.value, p.note, p.author_id, p.created_at`
+
+type GoalRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewGoalRepository(pool *pgxpool.Pool) *GoalRepository {
+	return &GoalRepository{pool: pool}
+}
+
+// scanGoal scans a row of goalSelect into a Goal without its key results
+func scanGoal(row pgx.Row) (*models.Goal, error) {
+	var g models.Goal
+	err := row.Scan(&g.ID, &g.Title, &g.Description, &g.OwnerType, &g.OwnerID,
+		&g.OwnerDepartment, &g.OwnerName, &g.Quarter, &g.CreatedByID, &g.CreatedAt, &g.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	g.KeyResults = []models.KeyResult{}
+	return &g, nil
+}
+
+// scanKeyResult scans a row of keyResultColumns into a KeyResult
+func scanKeyResult(row pgx.Row) (*models.KeyResult, error) {
+	var kr models.KeyResult
+	err := row.Scan(&kr.ID, &kr.GoalID, &kr.Title, &kr.StartValue, &kr.TargetValue, &kr.CurrentValue, &kr.Unit, &kr.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	kr.ComputeProgress()
+	return &kr, nil
+}
+
+// List retrieves the goals matching the filter with their key results and progress, ordered by
+// quarter and title
+func (r *GoalRepository) List(ctx context.Context, filter models.GoalFilter) ([]models.Goal, error) {
+	ctx = withQueryName(ctx, "GoalRepository.List")
+	args := []any{orgScope(ctx)}
+	conditions := []string{orgCondition("g.org_id", "$1")}
+	if filter.Quarter != "" {
+		args = append(args, filter.Quarter)
+		conditions = append(conditions, fmt.Sprintf("g.quarter = $%d", len(args)))
+	}
+	if len(filter.UserIDs) > 0 || len(filter.SquadIDs) > 0 || len(filter.Departments) > 0 {
+		args = append(args, filter.UserIDs, filter.SquadIDs, filter.Departments)
+		conditions = append(conditions, fmt.Sprintf(
+			"(g.owner_user_id = ANY($%d) OR g.owner_squad_id = ANY($%d) OR g.owner_department = ANY($%d))",
+			len(args)-2, len(args)-1, len(args)))
+	}
+
+	query := goalSelect + ` WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY g.quarter DESC, g.title, g.id`
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+	defer rows.Close()
+
+	goals := []models.Goal{}
+	for rows.Next() {
+		goal, err := scanGoal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan goal: %w", err)
+		}
+		goals = append(goals, *goal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+
+	if err := r.loadKeyResults(ctx, goals); err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+// loadKeyResults batch loads the goals' key results and computes their progress
+func (r *GoalRepository) loadKeyResults(ctx context.Context, goals []models.Goal) error {
+	if len(goals) == 0 {
+		return nil
+	}
+
+	goalIDs := make([]int64, len(goals))
+	byID := make(map[int64]*models.Goal, len(goals))
+	for i := range goals {
+		goalIDs[i] = goals[i].ID
+		byID[goals[i].ID] = &goals[i]
+	}
+
+	query := `SELECT ` + keyResultColumns + ` FROM goal_key_results WHERE goal_id = ANY($1) ORDER BY goal_id, position, id`
+	rows, err := r.pool.Query(ctx, query, goalIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get key results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		kr, err := scanKeyResult(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan key result: %w", err)
+		}
+		if goal, ok := byID[kr.GoalID]; ok {
+			goal.KeyResults = append(goal.KeyResults, *kr)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get key results: %w", err)
+	}
+
+	for i := range goals {
+		goals[i].ComputeProgress()
+	}
+	return nil
+}
+
+// GetByID retrieves one of the organization's goals with its key results and progress
+func (r *GoalRepository) GetByID(ctx context.Context, id int64) (*models.Goal, error) {
+	ctx = withQueryName(ctx, "GoalRepository.GetByID")
+	query := goalSelect + ` WHERE g.id = $1 AND ` + orgCondition("g.org_id", "$2")
+	goal, err := scanGoal(r.pool.QueryRow(ctx, query, id, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal: %w", err)
+	}
+
+	goals := []models.Goal{*goal}
+	if err := r.loadKeyResults(ctx, goals); err != nil {
+		return nil, err
+	}
+	return &goals[0], nil
+}
+
+// goalOwnerArgs splits a goal request's owner into the user, squad, and department columns
+func goalOwnerArgs(req *models.GoalRequest) (userID, squadID *int64, department *string) {
+	switch req.OwnerType {
+	case models.GoalOwnerUser:
+		return req.OwnerID, nil, nil
+	case models.GoalOwnerSquad:
+		return nil, req.OwnerID, nil
+	}
+	return nil, nil, req.OwnerDepartment
+}
+
+// Create creates a goal and its key results. New key results start at their start value.
+func (r *GoalRepository) Create(ctx context.Context, req *models.GoalRequest, createdByID int64) (*models.Goal, error) {
+	ctx = withQueryName(ctx, "GoalRepository.Create")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	userID, squadID, department := goalOwnerArgs(req)
+	var id int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO goals (title, description, owner_type, owner_user_id, owner_squad_id, owner_department, quarter, created_by_id, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`,
+		req.Title, req.Description, req.OwnerType, userID, squadID, department, req.Quarter, createdByID,
+		orgID,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create goal: %w", err)
+	}
+
+	for i, kr := range req.KeyResults {
+		if err := insertKeyResult(ctx, tx, id, i, &kr); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+func insertKeyResult(ctx context.Context, tx pgx.Tx, goalID int64, position int, kr *models.KeyResultInput) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO goal_key_results (goal_id, position, title, start_value, target_value, current_value, unit)
+		VALUES ($1, $2, $3, $4, $5, $4, $6)`,
+		goalID, position, kr.Title, kr.StartValue, kr.TargetValue, kr.Unit)
+	if err != nil {
+		return fmt.Errorf("failed to add key result: %w", err)
+	}
+	return nil
+}
+
+// Update edits a goal and its key results. Key results with an ID keep their current value,
+// key results without one are added, and key results left out are deleted along with their
+// progress history. Callers check that the IDs belong to the goal.
+func (r *GoalRepository) Update(ctx context.Context, id int64, req *models.GoalRequest) (*models.Goal, error) {
+	ctx = withQueryName(ctx, "GoalRepository.Update")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	userID, squadID, department := goalOwnerArgs(req)
+	result, err := tx.Exec(ctx, `
+		UPDATE goals SET
+			title = $2, description = $3, owner_type = $4, owner_user_id = $5,
+			owner_squad_id = $6, owner_department = $7, quarter = $8, updated_at = NOW()
+		WHERE id = $1 AND `+orgCondition("org_id", "$9"),
+		id, req.Title, req.Description, req.OwnerType, userID, squadID, department, req.Quarter, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update goal: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, nil
+	}
+
+	keep := []int64{}
+	for _, kr := range req.KeyResults {
+		if kr.ID != nil {
+			keep = append(keep, *kr.ID)
+		}
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM goal_key_results WHERE goal_id = $1 AND NOT (id = ANY($2))`, id, keep); err != nil {
+		return nil, fmt.Errorf("failed to remove key results: %w", err)
+	}
+
+	for i, kr := range req.KeyResults {
+		if kr.ID == nil {
+			if err := insertKeyResult(ctx, tx, id, i, &kr); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		_, err := tx.Exec(ctx, `
+			UPDATE goal_key_results SET
+				position = $3, title = $4, start_value = $5, target_value = $6, unit = $7, updated_at = NOW()
+			WHERE id = $1 AND goal_id = $2`,
+			*kr.ID, id, i, kr.Title, kr.StartValue, kr.TargetValue, kr.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update key result: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Delete deletes a goal along with its key results and their progress history
+func (r *GoalRepository) Delete(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "GoalRepository.Delete")
+	if _, err := r.pool.Exec(ctx, `DELETE FROM goals WHERE id = $1 AND `+orgCondition("org_id", "$2"), id, orgScope(ctx)); err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	return nil
+}
+
+// GetKeyResult retrieves a key result of one of the organization's goals by ID
+func (r *GoalRepository) GetKeyResult(ctx context.Context, id int64) (*models.KeyResult, error) {
+	ctx = withQueryName(ctx, "GoalRepository.GetKeyResult")
+	query := `SELECT ` + keyResultColumns + ` FROM goal_key_results
+		WHERE id = $1 AND goal_id IN (SELECT id FROM goals WHERE ` + orgCondition("org_id", "$2") + `)`
+
+	kr, err := scanKeyResult(r.pool.QueryRow(ctx, query, id, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key result: %w", err)
+	}
+	return kr, nil
+}
+
+// RecordProgress sets a key result's current value and adds it to the key result's history
+func (r *GoalRepository) RecordProgress(ctx context.Context, keyResultID, authorID int64, req *models.ProgressUpdateRequest) (*models.GoalProgressUpdate, error) {
+	ctx = withQueryName(ctx, "GoalRepository.RecordProgress")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var update models.GoalProgressUpdate
+	err = tx.QueryRow(ctx, `
+		INSERT INTO goal_progress_updates AS p (key_result_id, value, note, author_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+progressUpdateColumns,
+		keyResultID, req.Value, req.Note, authorID,
+	).Scan(&update.ID, &update.KeyResultID, &update.Value, &update.Note, &update.AuthorID, &update.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record progress: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		WITH kr AS (
+			UPDATE goal_key_results SET current_value = $2, updated_at = NOW()
+			WHERE id = $1
+			RETURNING goal_id
+		)
+		UPDATE goals SET updated_at = NOW() WHERE id = (SELECT goal_id FROM kr)`,
+		keyResultID, req.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update key result: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &update, nil
+}
+
+// GetProgressUpdates retrieves the progress history of a goal's key results, newest first
+func (r *GoalRepository) GetProgressUpdates(ctx context.Context, goalID int64) ([]models.GoalProgressUpdate, error) {
+	ctx = withQueryName(ctx, "GoalRepository.GetProgressUpdates")
+	query := `
+		SELECT ` + progressUpdateColumns + `
+		FROM goal_progress_updates p
+		JOIN goal_key_results kr ON kr.id = p.key_result_id
+		JOIN goals g ON g.id = kr.goal_id
+		WHERE kr.goal_id = $1 AND ` + orgCondition("g.org_id", "$2") + `
+		ORDER BY p.created_at DESC, p.id DESC`
+
+	rows, err := r.pool.Query(ctx, query, goalID, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress updates: %w", err)
+	}
+	defer rows.Close()
+
+	updates := []models.GoalProgressUpdate{}
+	for rows.Next() {
+		var u models.GoalProgressUpdate
+		if err := rows.Scan(&u.ID, &u.KeyResultID, &u.Value, &u.Note, &u.AuthorID, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan progress update: %w", err)
+		}
+		updates = append(updates, u)
+	}
+	return updates, rows.Err()
+}
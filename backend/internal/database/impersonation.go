@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const impersonationSessionColumns = `id, admin_id, user_id, reason, started_at, expires_at, ended_at`
+
+// ImpersonationRepository stores the sessions in which admins impersonate users
+type ImpersonationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewImpersonationRepository creates a new impersonation repository
+func NewImpersonationRepository(pool *pgxpool.Pool) *ImpersonationRepository {
+	return &ImpersonationRepository{pool: pool}
+}
+
+func scanImpersonationSession(row pgx.Row) (*models.ImpersonationSession, error) {
+	var s models.ImpersonationSession
+	err := row.Scan(&s.ID, &s.AdminID, &s.UserID, &s.Reason, &s.StartedAt, &s.ExpiresAt, &s.EndedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Start ends the admin's active sessions and starts a new one, setting its ID and start time. An
+// admin impersonates one user at a time.
+func (r *ImpersonationRepository) Start(ctx context.Context, session *models.ImpersonationSession) error {
+	ctx = withQueryName(ctx, "ImpersonationRepository.Start")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE impersonation_sessions SET ended_at = NOW()
+		WHERE admin_id = $1 AND ended_at IS NULL AND expires_at > NOW()`,
+		session.AdminID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to end impersonation sessions: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO impersonation_sessions (admin_id, user_id, reason, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, started_at`,
+		session.AdminID, session.UserID, session.Reason, session.ExpiresAt,
+	).Scan(&session.ID, &session.StartedAt)
+	if err != nil {
+		return fmt.Errorf("failed to start impersonation session: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an impersonation session, or nil if it doesn't exist
+func (r *ImpersonationRepository) GetByID(ctx context.Context, id int64) (*models.ImpersonationSession, error) {
+	ctx = withQueryName(ctx, "ImpersonationRepository.GetByID")
+	query := `SELECT ` + impersonationSessionColumns + ` FROM impersonation_sessions WHERE id = $1`
+	session, err := scanImpersonationSession(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get impersonation session: %w", err)
+	}
+	return session, nil
+}
+
+// End ends the admin's active sessions impersonating the user and returns how many there were
+func (r *ImpersonationRepository) End(ctx context.Context, adminID, userID int64) (int64, error) {
+	ctx = withQueryName(ctx, "ImpersonationRepository.End")
+	result, err := r.pool.Exec(ctx, `
+		UPDATE impersonation_sessions SET ended_at = NOW()
+		WHERE admin_id = $1 AND user_id = $2 AND ended_at IS NULL AND expires_at > NOW()`,
+		adminID, userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to end impersonation session: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -15,16 +15,23 @@ import (
 
 // Column lists for consistent SELECT statements
 const (
-	invitationColumns = `id, email, role, department, squad_ids, token, invited_by_id, status, expires_at, accepted_at, created_at, updated_at`
+	invitationColumns = `id, email, role, department, squad_ids, token, invited_by_id, status, expires_at, accepted_at, created_at, updated_at,
+		access_expires_at, meeting_ids, org_id, language, supervisor_id, title, date_started`
 	// User columns for JOIN queries (prefixed with table alias)
 	invUserColumns = `u.id, COALESCE(u.auth0_id, ''), u.email, u.first_name, u.last_name, u.role, u.title,
 		u.department, u.avatar_url, u.supervisor_id, u.date_started, u.created_at, u.updated_at`
 )
 
 type InvitationRepository struct {
-	pool       *pgxpool.Pool
-	expiryDays int
-	logger     *logger.Logger
+	pool          *pgxpool.Pool
+	expiryDays    int
+	expirySetting InvitationExpirySetting
+	logger        *logger.Logger
+}
+
+// InvitationExpirySetting looks up how many days invitations from the organization of ctx last
+type InvitationExpirySetting interface {
+	InvitationExpiryDays(ctx context.Context) int
 }
 
 // NewInvitationRepository creates a new invitation repository
@@ -49,6 +56,13 @@ func NewInvitationRepositoryWithConfig(pool *pgxpool.Pool, expiryDays int) *Invi
 	}
 }
 
+// WithExpirySetting lets organizations change how long their invitations last from the
+// configured expiry
+func (r *InvitationRepository) WithExpirySetting(setting InvitationExpirySetting) *InvitationRepository {
+	r.expirySetting = setting
+	return r
+}
+
 // scanInvitation scans a row into an Invitation struct
 func scanInvitation(row pgx.Row) (*models.Invitation, error) {
 	var inv models.Invitation
@@ -56,6 +70,7 @@ func scanInvitation(row pgx.Row) (*models.Invitation, error) {
 	err := row.Scan(
 		&inv.ID, &inv.Email, &inv.Role, &department, &inv.SquadIDs, &inv.Token, &inv.InvitedByID,
 		&inv.Status, &inv.ExpiresAt, &inv.AcceptedAt, &inv.CreatedAt, &inv.UpdatedAt,
+		&inv.AccessExpiresAt, &inv.MeetingIDs, &inv.OrgID, &inv.Language, &inv.SupervisorID, &inv.Title, &inv.DateStarted,
 	)
 	if err != nil {
 		return nil, err
@@ -75,15 +90,21 @@ func generateToken() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-// Create creates a new invitation
-func (r *InvitationRepository) Create(ctx context.Context, req *models.CreateInvitationRequest, invitedByID int64) (*models.Invitation, error) {
+// Create creates a new invitation to join the inviter's organization. When email is set, the
+// invitation email is queued in the outbox in the same transaction.
+func (r *InvitationRepository) Create(ctx context.Context, req *models.CreateInvitationRequest, invitedByID int64, email *models.InvitationEmail) (*models.Invitation, error) {
+	ctx = withQueryName(ctx, "InvitationRepository.Create")
 	token, err := generateToken()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	// Use configurable expiry days
-	expiresAt := time.Now().Add(time.Duration(r.expiryDays) * 24 * time.Hour)
+	expiryDays := r.expiryDays
+	if r.expirySetting != nil {
+		expiryDays = r.expirySetting.InvitationExpiryDays(ctx)
+	}
+	expiresAt := time.Now().Add(time.Duration(expiryDays) * 24 * time.Hour)
 
 	// Handle optional department
 	var department *string
@@ -91,23 +112,55 @@ func (r *InvitationRepository) Create(ctx context.Context, req *models.CreateInv
 		department = &req.Department
 	}
 
+	language := req.Language
+	if language == "" {
+		language = models.DefaultLanguage
+	}
+
 	query := `
-		INSERT INTO invitations (email, role, department, squad_ids, token, invited_by_id, expires_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO invitations (email, role, department, squad_ids, token, invited_by_id, expires_at, access_expires_at, meeting_ids, org_id,
+			language, supervisor_id, title, date_started)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, (SELECT org_id FROM users WHERE id = $6), $10, $11, $12, $13)
 		RETURNING ` + invitationColumns
 
-	inv, err := scanInvitation(r.pool.QueryRow(ctx, query, req.Email, req.Role, department, req.SquadIDs, token, invitedByID, expiresAt))
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	inv, err := scanInvitation(tx.QueryRow(ctx, query, req.Email, req.Role, department, req.SquadIDs, token, invitedByID, expiresAt,
+		req.AccessExpiresAt, req.MeetingIDs, language, req.SupervisorID, req.Title, req.DateStarted))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create invitation: %w", err)
 	}
 
+	if email != nil {
+		err = enqueueOutbox(ctx, tx, models.OutboxKindInvitationEmail, models.InvitationEmailPayload{
+			InvitationID: inv.ID,
+			Email:        inv.Email,
+			Token:        inv.Token,
+			Role:         inv.Role,
+			InviterName:  email.InviterName,
+			OrgID:        inv.OrgID,
+			Language:     inv.Language,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit invitation: %w", err)
+	}
 	return inv, nil
 }
 
 // GetByID retrieves an invitation by ID
 func (r *InvitationRepository) GetByID(ctx context.Context, id int64) (*models.Invitation, error) {
-	query := `SELECT ` + invitationColumns + ` FROM invitations WHERE id = $1`
-	inv, err := scanInvitation(r.pool.QueryRow(ctx, query, id))
+	ctx = withQueryName(ctx, "InvitationRepository.GetByID")
+	query := `SELECT ` + invitationColumns + ` FROM invitations WHERE id = $1 AND ` + orgCondition("org_id", "$2")
+	inv, err := scanInvitation(r.pool.QueryRow(ctx, query, id, orgScope(ctx)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get invitation by ID: %w", err)
 	}
@@ -116,6 +169,7 @@ func (r *InvitationRepository) GetByID(ctx context.Context, id int64) (*models.I
 
 // GetByToken retrieves an invitation by token
 func (r *InvitationRepository) GetByToken(ctx context.Context, token string) (*models.Invitation, error) {
+	ctx = withQueryName(ctx, "InvitationRepository.GetByToken")
 	query := `SELECT ` + invitationColumns + ` FROM invitations WHERE token = $1`
 	inv, err := scanInvitation(r.pool.QueryRow(ctx, query, token))
 	if err != nil {
@@ -126,6 +180,7 @@ func (r *InvitationRepository) GetByToken(ctx context.Context, token string) (*m
 
 // GetByEmail retrieves pending invitations for an email
 func (r *InvitationRepository) GetByEmail(ctx context.Context, email string) (*models.Invitation, error) {
+	ctx = withQueryName(ctx, "InvitationRepository.GetByEmail")
 	query := `SELECT ` + invitationColumns + ` FROM invitations
 		WHERE email = $1 AND status = 'pending' AND expires_at > NOW()
 		ORDER BY created_at DESC LIMIT 1`
@@ -138,15 +193,17 @@ func (r *InvitationRepository) GetByEmail(ctx context.Context, email string) (*m
 
 // GetAll retrieves all invitations (for admin view)
 func (r *InvitationRepository) GetAll(ctx context.Context) ([]models.Invitation, error) {
+	ctx = withQueryName(ctx, "InvitationRepository.GetAll")
 	query := `
 		SELECT i.id, i.email, i.role, i.department, i.squad_ids, i.token, i.invited_by_id, i.status,
-		       i.expires_at, i.accepted_at, i.created_at, i.updated_at,
-		       ` + invUserColumns + `
+		       i.expires_at, i.accepted_at, i.created_at, i.updated_at, i.access_expires_at, i.meeting_ids, i.org_id,
+		       i.language, i.supervisor_id, i.title, i.date_started, ` + invUserColumns + `
 		FROM invitations i
 		JOIN users u ON i.invited_by_id = u.id
+		WHERE ` + orgCondition("i.org_id", "$1") + `
 		ORDER BY i.created_at DESC`
 
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := r.pool.Query(ctx, query, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all invitations: %w", err)
 	}
@@ -160,6 +217,7 @@ func (r *InvitationRepository) GetAll(ctx context.Context) ([]models.Invitation,
 		err := rows.Scan(
 			&inv.ID, &inv.Email, &inv.Role, &department, &inv.SquadIDs, &inv.Token, &inv.InvitedByID,
 			&inv.Status, &inv.ExpiresAt, &inv.AcceptedAt, &inv.CreatedAt, &inv.UpdatedAt,
+			&inv.AccessExpiresAt, &inv.MeetingIDs, &inv.OrgID, &inv.Language, &inv.SupervisorID, &inv.Title, &inv.DateStarted,
 			&invitedBy.ID, &invitedBy.Auth0ID, &invitedBy.Email, &invitedBy.FirstName,
 			&invitedBy.LastName, &invitedBy.Role, &invitedBy.Title, &invitedBy.Department,
 			&invitedBy.AvatarURL, &invitedBy.SupervisorID, &invitedBy.DateStarted,
@@ -180,8 +238,10 @@ func (r *InvitationRepository) GetAll(ctx context.Context) ([]models.Invitation,
 	return invitations, nil
 }
 
-// Accept marks an invitation as accepted and creates the user
+// Accept marks an invitation as accepted and creates the user in the organization they were invited to,
+// moving them there if signing in already created their account
 func (r *InvitationRepository) Accept(ctx context.Context, token string, auth0ID string, firstName string, lastName string) (*models.User, error) {
+	ctx = withQueryName(ctx, "InvitationRepository.Accept")
 	// Start transaction
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
@@ -192,9 +252,12 @@ func (r *InvitationRepository) Accept(ctx context.Context, token string, auth0ID
 	// Get and validate the invitation (including department and squad_ids)
 	var inv models.Invitation
 	var department *string
-	invQuery := `SELECT id, email, role, department, squad_ids, status, expires_at FROM invitations WHERE token = $1 FOR UPDATE`
+	invQuery := `SELECT id, email, role, department, squad_ids, status, expires_at, access_expires_at, meeting_ids, invited_by_id, org_id,
+		language, supervisor_id, title, date_started FROM invitations WHERE token = $1 FOR UPDATE`
 	err = tx.QueryRow(ctx, invQuery, token).Scan(
 		&inv.ID, &inv.Email, &inv.Role, &department, &inv.SquadIDs, &inv.Status, &inv.ExpiresAt,
+		&inv.AccessExpiresAt, &inv.MeetingIDs, &inv.InvitedByID, &inv.OrgID, &inv.Language,
+		&inv.SupervisorID, &inv.Title, &inv.DateStarted,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("invitation not found: %w", err)
@@ -215,24 +278,36 @@ func (r *InvitationRepository) Accept(ctx context.Context, token string, auth0ID
 		return nil, fmt.Errorf("invitation has expired")
 	}
 
-	// Create the user with the invited role and department
+	// Create the user with the invited role, department, reporting line, title, and start date (and
+	// expiry, for guests), speaking the language they were invited in. A supervisor who has since
+	// left the organization is dropped rather than failing the acceptance.
 	userQuery := `
-		INSERT INTO users (auth0_id, email, first_name, last_name, role, title, department, date_started)
-		VALUES ($1, $2, $3, $4, $5, '', $6, NOW())
+		INSERT INTO users (auth0_id, email, first_name, last_name, role, title, department, date_started, access_expires_at, org_id,
+			language, supervisor_id)
+		VALUES ($1, $2, $3, $4, $5, $10, $6, COALESCE($11, NOW()), $7, $8, $9,
+			(SELECT id FROM users WHERE id = $12 AND org_id = $8 AND deleted_at IS NULL))
 		ON CONFLICT (auth0_id) DO UPDATE SET
 			email = EXCLUDED.email,
 			role = EXCLUDED.role,
+			title = EXCLUDED.title,
 			department = EXCLUDED.department,
+			supervisor_id = EXCLUDED.supervisor_id,
+			date_started = COALESCE($11, users.date_started),
 			first_name = EXCLUDED.first_name,
 			last_name = EXCLUDED.last_name,
+			access_expires_at = EXCLUDED.access_expires_at,
+			org_id = EXCLUDED.org_id,
+			language = EXCLUDED.language,
 			updated_at = NOW()
 		RETURNING id, COALESCE(auth0_id, ''), email, first_name, last_name, role, title, department,
-				  avatar_url, supervisor_id, date_started, created_at, updated_at`
+				  avatar_url, supervisor_id, date_started, created_at, updated_at, access_expires_at, timezone, org_id, language`
 	var user models.User
-	err = tx.QueryRow(ctx, userQuery, auth0ID, inv.Email, firstName, lastName, inv.Role, inv.Department).Scan(
+	err = tx.QueryRow(ctx, userQuery, auth0ID, inv.Email, firstName, lastName, inv.Role, inv.Department, inv.AccessExpiresAt, inv.OrgID,
+		inv.Language, inv.Title, inv.DateStarted, inv.SupervisorID).Scan(
 		&user.ID, &user.Auth0ID, &user.Email, &user.FirstName, &user.LastName,
 		&user.Role, &user.Title, &user.Department, &user.AvatarURL, &user.SupervisorID,
-		&user.DateStarted, &user.CreatedAt, &user.UpdatedAt,
+		&user.DateStarted, &user.CreatedAt, &user.UpdatedAt, &user.AccessExpiresAt, &user.Timezone, &user.OrgID,
+		&user.Language,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user from invitation: %w", err)
@@ -243,15 +318,36 @@ func (r *InvitationRepository) Accept(ctx context.Context, token string, auth0ID
 		for _, squadID := range inv.SquadIDs {
 			_, err = tx.Exec(ctx, `
 				INSERT INTO user_squads (user_id, squad_id)
-				VALUES ($1, $2)
+				SELECT $1, id FROM squads WHERE id = $2 AND org_id = $3
 				ON CONFLICT (user_id, squad_id) DO NOTHING
-			`, user.ID, squadID)
+			`, user.ID, squadID, inv.OrgID)
 			if err != nil {
 				return nil, fmt.Errorf("failed to assign squad to user: %w", err)
 			}
 		}
 	}
 
+	// Add guests to the meetings they were invited to
+	for _, meetingID := range inv.MeetingIDs {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO meeting_attendees (meeting_id, user_id)
+			SELECT id, $2 FROM meetings WHERE id = $1 AND org_id = $3
+			ON CONFLICT (meeting_id, user_id) DO NOTHING
+		`, meetingID, user.ID, inv.OrgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add guest to meeting: %w", err)
+		}
+	}
+
+	// Start the user's history with what they were invited as
+	history := models.DiffUserHistory(&models.User{}, &user)
+	if entry := models.SquadHistoryEntry(user.ID, nil, inv.SquadIDs); entry != nil {
+		history = append(history, *entry)
+	}
+	if err := recordUserHistory(ctx, tx, models.UserHistorySourceInvitation, &inv.InvitedByID, history); err != nil {
+		return nil, err
+	}
+
 	// Mark invitation as accepted
 	_, err = tx.Exec(ctx, `UPDATE invitations SET status = 'accepted', accepted_at = NOW(), updated_at = NOW() WHERE id = $1`, inv.ID)
 	if err != nil {
@@ -268,11 +364,12 @@ func (r *InvitationRepository) Accept(ctx context.Context, token string, auth0ID
 
 // Revoke revokes an invitation
 func (r *InvitationRepository) Revoke(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "InvitationRepository.Revoke")
 	query := `
 		UPDATE invitations SET status = 'revoked', updated_at = NOW()
-		WHERE id = $1 AND status = 'pending'
+		WHERE id = $1 AND status = 'pending' AND ` + orgCondition("org_id", "$2") + `
 	`
-	result, err := r.pool.Exec(ctx, query, id)
+	result, err := r.pool.Exec(ctx, query, id, orgScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to revoke invitation: %w", err)
 	}
@@ -284,6 +381,7 @@ func (r *InvitationRepository) Revoke(ctx context.Context, id int64) error {
 
 // ExpirePending marks all expired pending invitations as expired
 func (r *InvitationRepository) ExpirePending(ctx context.Context) error {
+	ctx = withQueryName(ctx, "InvitationRepository.ExpirePending")
 	query := `
 		UPDATE invitations SET status = 'expired', updated_at = NOW()
 		WHERE status = 'pending' AND expires_at < NOW()
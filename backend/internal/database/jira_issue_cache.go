@@ -0,0 +1,204 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// JiraIssueCacheRepository stores a local copy of Jira issues. Each organization has its own, and
+// the repository acts on that of the organization ctx is scoped to.
+type JiraIssueCacheRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewJiraIssueCacheRepository creates a new JiraIssueCacheRepository
+func NewJiraIssueCacheRepository(pool *pgxpool.Pool) *JiraIssueCacheRepository {
+	return &JiraIssueCacheRepository{pool: pool}
+}
+
+// upsertJiraIssueQuery stores an issue unless the cached copy is newer
+const upsertJiraIssueQuery = `
+	INSERT INTO jira_issues_cache (issue_key, issue_id, assignee_account_id, resolved, data, jira_updated_at, org_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (org_id, issue_key) DO UPDATE SET
+		issue_id = EXCLUDED.issue_id,
+		assignee_account_id = EXCLUDED.assignee_account_id,
+		resolved = EXCLUDED.resolved,
+		data = EXCLUDED.data,
+		jira_updated_at = EXCLUDED.jira_updated_at,
+		synced_at = NOW()
+	WHERE jira_issues_cache.jira_updated_at <= EXCLUDED.jira_updated_at
+`
+
+// jiraIssueUpsertArgs returns the arguments for upsertJiraIssueQuery
+func jiraIssueUpsertArgs(orgID int64, issue *models.JiraIssue, resolved bool) ([]any, error) {
+	data, err := json.Marshal(issue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode jira issue: %w", err)
+	}
+	var assigneeAccountID *string
+	if issue.Assignee != nil && issue.Assignee.AccountID != "" {
+		assigneeAccountID = &issue.Assignee.AccountID
+	}
+	return []any{issue.Key, issue.ID, assigneeAccountID, resolved, data, issue.Updated, orgID}, nil
+}
+
+// Upsert stores an issue, ignoring copies older than the one already cached.
+// It reports whether the cache changed.
+func (r *JiraIssueCacheRepository) Upsert(ctx context.Context, issue *models.JiraIssue, resolved bool) (bool, error) {
+	ctx = withQueryName(ctx, "JiraIssueCacheRepository.Upsert")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return false, err
+	}
+	args, err := jiraIssueUpsertArgs(orgID, issue, resolved)
+	if err != nil {
+		return false, err
+	}
+	result, err := r.pool.Exec(ctx, upsertJiraIssueQuery, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to cache jira issue: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// Delete removes an issue from the cache and reports whether it was cached
+func (r *JiraIssueCacheRepository) Delete(ctx context.Context, issueKey string) (bool, error) {
+	ctx = withQueryName(ctx, "JiraIssueCacheRepository.Delete")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return false, err
+	}
+	result, err := r.pool.Exec(ctx, `DELETE FROM jira_issues_cache WHERE org_id = $1 AND issue_key = $2`, orgID, issueKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete cached jira issue: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// ListOpen returns the cached unresolved issues assigned to the given accounts, most recently updated first
+func (r *JiraIssueCacheRepository) ListOpen(ctx context.Context, accountIDs []string) ([]models.JiraIssue, error) {
+	ctx = withQueryName(ctx, "JiraIssueCacheRepository.ListOpen")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT data FROM jira_issues_cache
+		WHERE org_id = $1 AND assignee_account_id = ANY($2) AND NOT resolved
+		ORDER BY jira_updated_at DESC, issue_key
+	`
+
+	rows, err := r.pool.Query(ctx, query, orgID, accountIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached jira issues: %w", err)
+	}
+	defer rows.Close()
+
+	issues := []models.JiraIssue{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan cached jira issue: %w", err)
+		}
+		var issue models.JiraIssue
+		if err := json.Unmarshal(data, &issue); err != nil {
+			return nil, fmt.Errorf("failed to decode cached jira issue: %w", err)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, rows.Err()
+}
+
+// ReplaceOpenForAssignee stores a full fetch of an account's unresolved issues and records the sync time.
+// Cached issues still open for the account but missing from the fetch were resolved or reassigned, so they're dropped.
+func (r *JiraIssueCacheRepository) ReplaceOpenForAssignee(ctx context.Context, accountID string, issues []models.JiraIssue, syncedAt time.Time) error {
+	ctx = withQueryName(ctx, "JiraIssueCacheRepository.ReplaceOpenForAssignee")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	keys := make([]string, len(issues))
+	batch := &pgx.Batch{}
+	for i := range issues {
+		keys[i] = issues[i].Key
+		args, err := jiraIssueUpsertArgs(orgID, &issues[i], false)
+		if err != nil {
+			return err
+		}
+		batch.Queue(upsertJiraIssueQuery, args...)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to cache jira issues: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM jira_issues_cache
+		WHERE org_id = $1 AND assignee_account_id = $2 AND NOT resolved AND NOT (issue_key = ANY($3))`,
+		orgID, accountID, keys)
+	if err != nil {
+		return fmt.Errorf("failed to drop stale jira issues: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO jira_issue_sync (org_id, account_id, synced_at) VALUES ($1, $2, $3)
+		ON CONFLICT (org_id, account_id) DO UPDATE SET synced_at = EXCLUDED.synced_at`,
+		orgID, accountID, syncedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record jira sync: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetSyncTimes returns when each of the given accounts was last synced; unsynced accounts are omitted
+func (r *JiraIssueCacheRepository) GetSyncTimes(ctx context.Context, accountIDs []string) (map[string]time.Time, error) {
+	ctx = withQueryName(ctx, "JiraIssueCacheRepository.GetSyncTimes")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.pool.Query(ctx, `SELECT account_id, synced_at FROM jira_issue_sync WHERE org_id = $1 AND account_id = ANY($2)`, orgID, accountIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jira sync times: %w", err)
+	}
+	defer rows.Close()
+
+	syncTimes := make(map[string]time.Time)
+	for rows.Next() {
+		var accountID string
+		var syncedAt time.Time
+		if err := rows.Scan(&accountID, &syncedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan jira sync time: %w", err)
+		}
+		syncTimes[accountID] = syncedAt
+	}
+	return syncTimes, rows.Err()
+}
+
+// GetLastSyncTime returns when any account was last synced, or nil if none have been
+func (r *JiraIssueCacheRepository) GetLastSyncTime(ctx context.Context) (*time.Time, error) {
+	ctx = withQueryName(ctx, "JiraIssueCacheRepository.GetLastSyncTime")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var syncedAt *time.Time
+	if err := r.pool.QueryRow(ctx, `SELECT MAX(synced_at) FROM jira_issue_sync WHERE org_id = $1`, orgID).Scan(&syncedAt); err != nil {
+		return nil, fmt.Errorf("failed to get last jira sync time: %w", err)
+	}
+	return syncedAt, nil
+}
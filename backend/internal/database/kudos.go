@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// kudosColumns selects kudos from k along with the giver (f) and recipient (t)
+const kudosColumns = `k.id, k.from_user_id, f.first_name || ' ' || f.last_name, k.to_user_id,
+	t.first_name || ' ' || t.last_name, t.avatar_url, k.message, k.company_value, k.created_at`
+
+type KudosRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewKudosRepository(pool *pgxpool.Pool) *KudosRepository {
+	return &KudosRepository{pool: pool}
+}
+
+// scanKudos scans a row of kudosColumns into Kudos
+func scanKudos(row pgx.Row) (*models.Kudos, error) {
+	var k models.Kudos
+	err := row.Scan(&k.ID, &k.FromUserID, &k.FromName, &k.ToUserID,
+		&k.ToName, &k.ToAvatarURL, &k.Message, &k.CompanyValue, &k.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Create records kudos from one user to another
+func (r *KudosRepository) Create(ctx context.Context, fromUserID int64, req *models.KudosRequest) (*models.Kudos, error) {
+	ctx = withQueryName(ctx, "KudosRepository.Create")
+	query := `
+		WITH k AS (
+			INSERT INTO kudos (from_user_id, to_user_id, message, company_value)
+			VALUES ($1, $2, $3, $4)
+			RETURNING *
+		)
+		SELECT ` + kudosColumns + `
+		FROM k
+		JOIN users f ON f.id = k.from_user_id AND f.deleted_at IS NULL
+		JOIN users t ON t.id = k.to_user_id AND t.deleted_at IS NULL`
+
+	kudos, err := scanKudos(r.pool.QueryRow(ctx, query, fromUserID, req.ToUserID, req.Message, req.CompanyValue))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kudos: %w", err)
+	}
+	return kudos, nil
+}
+
+// ListFeed retrieves a page of kudos received by the given users, newest first, along with the
+// total number of them. A nil recipients list includes everyone in the organization's kudos.
+func (r *KudosRepository) ListFeed(ctx context.Context, recipientIDs []int64, limit, offset int) ([]models.Kudos, int, error) {
+	ctx = withQueryName(ctx, "KudosRepository.ListFeed")
+	where := orgCondition("t.org_id", "$1")
+	args := []any{orgScope(ctx)}
+	if recipientIDs != nil {
+		where += ` AND k.to_user_id = ANY($2)`
+		args = append(args, recipientIDs)
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM kudos k JOIN users t ON t.id = k.to_user_id WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count kudos: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT `+kudosColumns+`
+		FROM kudos k
+		JOIN users f ON f.id = k.from_user_id AND f.deleted_at IS NULL
+		JOIN users t ON t.id = k.to_user_id AND t.deleted_at IS NULL
+		WHERE %s
+		ORDER BY k.created_at DESC, k.id DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+
+	rows, err := r.pool.Query(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list kudos: %w", err)
+	}
+	defer rows.Close()
+
+	feed := []models.Kudos{}
+	for rows.Next() {
+		kudos, err := scanKudos(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan kudos: %w", err)
+		}
+		feed = append(feed, *kudos)
+	}
+	return feed, total, rows.Err()
+}
+
+// CountByDepartment counts the kudos each of the organization's departments' members received per
+// month since a time, newest month first. Recipients are grouped by their current department.
+func (r *KudosRepository) CountByDepartment(ctx context.Context, since time.Time) ([]models.KudosDepartmentCount, error) {
+	ctx = withQueryName(ctx, "KudosRepository.CountByDepartment")
+	query := `
+		SELECT TO_CHAR(DATE_TRUNC('month', k.created_at AT TIME ZONE 'UTC'), 'YYYY-MM') AS month,
+			COALESCE(NULLIF(t.department, ''), 'Unassigned') AS department,
+			COUNT(*)
+		FROM kudos k
+		JOIN users t ON t.id = k.to_user_id AND t.deleted_at IS NULL
+		WHERE k.created_at >= $1 AND ` + orgCondition("t.org_id", "$2") + `
+		GROUP BY 1, 2
+		ORDER BY 1 DESC, 2`
+
+	rows, err := r.pool.Query(ctx, query, since, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count kudos by department: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.KudosDepartmentCount{}
+	for rows.Next() {
+		var c models.KudosDepartmentCount
+		if err := rows.Scan(&c.Month, &c.Department, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan kudos count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
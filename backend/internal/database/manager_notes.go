@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const managerNoteColumns = `id, author_id, subject_id, meeting_id, body, created_at, updated_at`
+
+type ManagerNoteRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewManagerNoteRepository(pool *pgxpool.Pool) *ManagerNoteRepository {
+	return &ManagerNoteRepository{pool: pool}
+}
+
+// scanManagerNote scans a row of managerNoteColumns into a ManagerNote
+func scanManagerNote(row pgx.Row) (*models.ManagerNote, error) {
+	var n models.ManagerNote
+	if err := row.Scan(&n.ID, &n.AuthorID, &n.SubjectID, &n.MeetingID, &n.Body, &n.CreatedAt, &n.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// Create saves a note the author wrote about the subject
+func (r *ManagerNoteRepository) Create(ctx context.Context, authorID, subjectID int64, req *models.ManagerNoteRequest) (*models.ManagerNote, error) {
+	ctx = withQueryName(ctx, "ManagerNoteRepository.Create")
+	query := `
+		INSERT INTO manager_notes (author_id, subject_id, meeting_id, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + managerNoteColumns
+
+	note, err := scanManagerNote(r.pool.QueryRow(ctx, query, authorID, subjectID, req.MeetingID, req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manager note: %w", err)
+	}
+	return note, nil
+}
+
+// GetByID retrieves a manager note by ID
+func (r *ManagerNoteRepository) GetByID(ctx context.Context, id int64) (*models.ManagerNote, error) {
+	ctx = withQueryName(ctx, "ManagerNoteRepository.GetByID")
+	query := `SELECT ` + managerNoteColumns + ` FROM manager_notes WHERE id = $1`
+
+	note, err := scanManagerNote(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manager note: %w", err)
+	}
+	return note, nil
+}
+
+// ListByAuthor retrieves a page of the notes an author wrote about a subject, newest first,
+// along with the total number of them
+func (r *ManagerNoteRepository) ListByAuthor(ctx context.Context, authorID, subjectID int64, limit, offset int) ([]models.ManagerNote, int, error) {
+	ctx = withQueryName(ctx, "ManagerNoteRepository.ListByAuthor")
+	return r.list(ctx, `author_id = $1 AND subject_id = $2`, []any{authorID, subjectID}, limit, offset)
+}
+
+// ListBySubject retrieves a page of every author's notes about a subject, newest first, along
+// with the total number of them. It backs the admin audit and must not be exposed to supervisors.
+func (r *ManagerNoteRepository) ListBySubject(ctx context.Context, subjectID int64, limit, offset int) ([]models.ManagerNote, int, error) {
+	ctx = withQueryName(ctx, "ManagerNoteRepository.ListBySubject")
+	return r.list(ctx, `subject_id = $1`, []any{subjectID}, limit, offset)
+}
+
+func (r *ManagerNoteRepository) list(ctx context.Context, where string, args []any, limit, offset int) ([]models.ManagerNote, int, error) {
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM manager_notes WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count manager notes: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT `+managerNoteColumns+`
+		FROM manager_notes
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+
+	rows, err := r.pool.Query(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list manager notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []models.ManagerNote{}
+	for rows.Next() {
+		note, err := scanManagerNote(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan manager note: %w", err)
+		}
+		notes = append(notes, *note)
+	}
+	return notes, total, rows.Err()
+}
+
+// Update replaces a manager note's body and meeting link
+func (r *ManagerNoteRepository) Update(ctx context.Context, id int64, req *models.ManagerNoteRequest) (*models.ManagerNote, error) {
+	ctx = withQueryName(ctx, "ManagerNoteRepository.Update")
+	query := `
+		UPDATE manager_notes SET body = $2, meeting_id = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + managerNoteColumns
+
+	note, err := scanManagerNote(r.pool.QueryRow(ctx, query, id, req.Body, req.MeetingID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update manager note: %w", err)
+	}
+	return note, nil
+}
+
+// Delete deletes a manager note
+func (r *ManagerNoteRepository) Delete(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "ManagerNoteRepository.Delete")
+	if _, err := r.pool.Exec(ctx, `DELETE FROM manager_notes WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete manager note: %w", err)
+	}
+	return nil
+}
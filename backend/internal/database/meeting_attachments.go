@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const meetingAttachmentColumns = `id, meeting_id, occurrence_start, kind, url, storage_key, file_name,
+	content_type, size_bytes, uploaded_by_id, created_at`
+
+type MeetingAttachmentRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewMeetingAttachmentRepository(pool *pgxpool.Pool) *MeetingAttachmentRepository {
+	return &MeetingAttachmentRepository{pool: pool}
+}
+
+// scanMeetingAttachment scans a row of meetingAttachmentColumns into a MeetingAttachment
+func scanMeetingAttachment(row pgx.Row, extra ...interface{}) (*models.MeetingAttachment, error) {
+	var a models.MeetingAttachment
+	dest := []interface{}{
+		&a.ID, &a.MeetingID, &a.OccurrenceStart, &a.Kind, &a.URL, &a.StorageKey, &a.FileName,
+		&a.ContentType, &a.SizeBytes, &a.UploadedByID, &a.CreatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Create stores a new meeting attachment
+func (r *MeetingAttachmentRepository) Create(ctx context.Context, attachment *models.MeetingAttachment) (*models.MeetingAttachment, error) {
+	ctx = withQueryName(ctx, "MeetingAttachmentRepository.Create")
+	query := `
+		INSERT INTO meeting_attachments (meeting_id, occurrence_start, kind, url, storage_key, file_name,
+			content_type, size_bytes, transcript_text, uploaded_by_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + meetingAttachmentColumns
+
+	created, err := scanMeetingAttachment(r.pool.QueryRow(ctx, query,
+		attachment.MeetingID, attachment.OccurrenceStart, attachment.Kind, attachment.URL,
+		attachment.StorageKey, attachment.FileName, attachment.ContentType, attachment.SizeBytes,
+		attachment.TranscriptText, attachment.UploadedByID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create meeting attachment: %w", err)
+	}
+	created.TranscriptText = attachment.TranscriptText
+	return created, nil
+}
+
+// GetByID retrieves a meeting attachment including its transcript text
+func (r *MeetingAttachmentRepository) GetByID(ctx context.Context, id int64) (*models.MeetingAttachment, error) {
+	ctx = withQueryName(ctx, "MeetingAttachmentRepository.GetByID")
+	query := `SELECT ` + meetingAttachmentColumns + `, transcript_text FROM meeting_attachments WHERE id = $1`
+
+	var transcript *string
+	attachment, err := scanMeetingAttachment(r.pool.QueryRow(ctx, query, id), &transcript)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meeting attachment: %w", err)
+	}
+	attachment.TranscriptText = transcript
+	return attachment, nil
+}
+
+// ListByMeeting retrieves a meeting's attachments, optionally limited to one occurrence.
+// Transcript text is omitted; fetch a single attachment to read it.
+func (r *MeetingAttachmentRepository) ListByMeeting(ctx context.Context, meetingID int64, occurrenceStart *time.Time) ([]models.MeetingAttachment, error) {
+	ctx = withQueryName(ctx, "MeetingAttachmentRepository.ListByMeeting")
+	query := `
+		SELECT ` + meetingAttachmentColumns + `
+		FROM meeting_attachments
+		WHERE meeting_id = $1
+		AND ($2::timestamptz IS NULL OR occurrence_start = $2)
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.pool.Query(ctx, query, meetingID, occurrenceStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list meeting attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []models.MeetingAttachment{}
+	for rows.Next() {
+		attachment, err := scanMeetingAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan meeting attachment: %w", err)
+		}
+		attachments = append(attachments, *attachment)
+	}
+	return attachments, rows.Err()
+}
+
+// Delete deletes a meeting attachment
+func (r *MeetingAttachmentRepository) Delete(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "MeetingAttachmentRepository.Delete")
+	result, err := r.pool.Exec(ctx, `DELETE FROM meeting_attachments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete meeting attachment: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("meeting attachment not found")
+	}
+	return nil
+}
+
+// SearchTranscripts runs a full-text search over transcripts of meetings the user organized or attends
+func (r *MeetingAttachmentRepository) SearchTranscripts(ctx context.Context, userID int64, query string, limit int) ([]models.TranscriptSearchResult, error) {
+	ctx = withQueryName(ctx, "MeetingAttachmentRepository.SearchTranscripts")
+	sql := `
+		SELECT a.id, a.meeting_id, a.occurrence_start, a.kind, a.url, a.storage_key, a.file_name,
+			a.content_type, a.size_bytes, a.uploaded_by_id, a.created_at,
+			m.title,
+			ts_headline('english', a.transcript_text, q, 'MaxFragments=2, MaxWords=20, MinWords=5'),
+			ts_rank(a.search_vector, q)::float8 AS rank
+		FROM meeting_attachments a
+		JOIN meetings m ON a.meeting_id = m.id,
+			websearch_to_tsquery('english', $1) q
+		WHERE a.search_vector @@ q
+		AND m.deleted_at IS NULL
+		AND (
+			m.created_by_id = $2
+			OR EXISTS (SELECT 1 FROM meeting_attendees ma WHERE ma.meeting_id = m.id AND ma.user_id = $2)
+		)
+		ORDER BY rank DESC, a.created_at DESC
+		LIMIT $3`
+
+	rows, err := r.pool.Query(ctx, sql, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transcripts: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.TranscriptSearchResult{}
+	for rows.Next() {
+		var result models.TranscriptSearchResult
+		attachment, err := scanMeetingAttachment(rows, &result.MeetingTitle, &result.Snippet, &result.Rank)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transcript search result: %w", err)
+		}
+		result.Attachment = *attachment
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
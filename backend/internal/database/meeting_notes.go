@@ -0,0 +1,232 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const agendaItemColumns = `id, meeting_id, position, title, duration_minutes, created_at`
+
+const actionItemColumns = `id, meeting_id, description, assignee_id, due_date, task_id, created_at`
+
+type MeetingNotesRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewMeetingNotesRepository(pool *pgxpool.Pool) *MeetingNotesRepository {
+	return &MeetingNotesRepository{pool: pool}
+}
+
+// scanAgendaItems scans rows of agendaItemColumns into agenda items
+func scanAgendaItems(rows pgx.Rows) ([]models.MeetingAgendaItem, error) {
+	items := []models.MeetingAgendaItem{}
+	for rows.Next() {
+		var item models.MeetingAgendaItem
+		if err := rows.Scan(&item.ID, &item.MeetingID, &item.Position, &item.Title, &item.DurationMinutes, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// scanActionItems scans rows of actionItemColumns into action items
+func scanActionItems(rows pgx.Rows) ([]models.MeetingActionItem, error) {
+	items := []models.MeetingActionItem{}
+	for rows.Next() {
+		var item models.MeetingActionItem
+		if err := rows.Scan(&item.ID, &item.MeetingID, &item.Description, &item.AssigneeID, &item.DueDate, &item.TaskID, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetAgenda retrieves a meeting's agenda in order
+func (r *MeetingNotesRepository) GetAgenda(ctx context.Context, meetingID int64) ([]models.MeetingAgendaItem, error) {
+	ctx = withQueryName(ctx, "MeetingNotesRepository.GetAgenda")
+	query := `SELECT ` + agendaItemColumns + ` FROM meeting_agenda_items WHERE meeting_id = $1 ORDER BY position`
+
+	rows, err := r.pool.Query(ctx, query, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meeting agenda: %w", err)
+	}
+	defer rows.Close()
+
+	items, err := scanAgendaItems(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan agenda item: %w", err)
+	}
+	return items, nil
+}
+
+// ReplaceAgenda swaps a meeting's agenda for the given items, stored in the order given
+func (r *MeetingNotesRepository) ReplaceAgenda(ctx context.Context, meetingID int64, items []models.MeetingAgendaItemInput) ([]models.MeetingAgendaItem, error) {
+	ctx = withQueryName(ctx, "MeetingNotesRepository.ReplaceAgenda")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM meeting_agenda_items WHERE meeting_id = $1`, meetingID); err != nil {
+		return nil, fmt.Errorf("failed to clear meeting agenda: %w", err)
+	}
+
+	for i, item := range items {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO meeting_agenda_items (meeting_id, position, title, duration_minutes)
+			VALUES ($1, $2, $3, $4)`,
+			meetingID, i, item.Title, item.DurationMinutes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add agenda item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetAgenda(ctx, meetingID)
+}
+
+// GetNotes retrieves a meeting's notes and action items; meetings without saved notes get empty notes
+func (r *MeetingNotesRepository) GetNotes(ctx context.Context, meetingID int64) (*models.MeetingNotes, error) {
+	ctx = withQueryName(ctx, "MeetingNotesRepository.GetNotes")
+	notes := &models.MeetingNotes{MeetingID: meetingID}
+
+	var updatedAt time.Time
+	err := r.pool.QueryRow(ctx,
+		`SELECT notes, updated_by_id, updated_at FROM meeting_notes WHERE meeting_id = $1`, meetingID,
+	).Scan(&notes.Notes, &notes.UpdatedByID, &updatedAt)
+	switch {
+	case err == pgx.ErrNoRows:
+	case err != nil:
+		return nil, fmt.Errorf("failed to get meeting notes: %w", err)
+	default:
+		notes.UpdatedAt = &updatedAt
+	}
+
+	query := `SELECT ` + actionItemColumns + ` FROM meeting_action_items WHERE meeting_id = $1 ORDER BY id`
+	rows, err := r.pool.Query(ctx, query, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action items: %w", err)
+	}
+	defer rows.Close()
+
+	notes.ActionItems, err = scanActionItems(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan action item: %w", err)
+	}
+	return notes, nil
+}
+
+// SaveNotes stores a meeting's notes and replaces its unconverted action items.
+// Action items that already became tasks are left untouched.
+func (r *MeetingNotesRepository) SaveNotes(ctx context.Context, meetingID, authorID int64, req *models.UpdateMeetingNotesRequest) (*models.MeetingNotes, error) {
+	ctx = withQueryName(ctx, "MeetingNotesRepository.SaveNotes")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO meeting_notes (meeting_id, notes, updated_by_id, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (meeting_id) DO UPDATE SET
+			notes = EXCLUDED.notes,
+			updated_by_id = EXCLUDED.updated_by_id,
+			updated_at = NOW()`,
+		meetingID, req.Notes, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save meeting notes: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM meeting_action_items WHERE meeting_id = $1 AND task_id IS NULL`, meetingID); err != nil {
+		return nil, fmt.Errorf("failed to clear action items: %w", err)
+	}
+
+	for _, item := range req.ActionItems {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO meeting_action_items (meeting_id, description, assignee_id, due_date)
+			VALUES ($1, $2, $3, $4)`,
+			meetingID, item.Description, item.AssigneeID, item.DueDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add action item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetNotes(ctx, meetingID)
+}
+
+// ConvertActionItems turns the given action items into tasks assigned to their assignees, all in one
+// transaction. Each task links back to the meeting and is due on the action item's due date, or
+// defaultDueDate when it has none. Items already converted or without an assignee are skipped.
+func (r *MeetingNotesRepository) ConvertActionItems(ctx context.Context, meeting *models.Meeting, createdByID int64, itemIDs []int64, defaultDueDate time.Time) ([]models.Task, error) {
+	ctx = withQueryName(ctx, "MeetingNotesRepository.ConvertActionItems")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Lock the items so concurrent conversions can't create duplicate tasks
+	rows, err := tx.Query(ctx, `
+		SELECT `+actionItemColumns+`
+		FROM meeting_action_items
+		WHERE meeting_id = $1 AND id = ANY($2) AND task_id IS NULL AND assignee_id IS NOT NULL
+		ORDER BY id
+		FOR UPDATE`,
+		meeting.ID, itemIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action items: %w", err)
+	}
+	items, err := scanActionItems(rows)
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan action item: %w", err)
+	}
+
+	description := fmt.Sprintf("Action item from meeting: %s", meeting.Title)
+	tasks := make([]models.Task, 0, len(items))
+	for _, item := range items {
+		dueDate := defaultDueDate
+		if item.DueDate != nil {
+			dueDate = *item.DueDate
+		}
+
+		task, err := scanTask(tx.QueryRow(ctx, `
+			INSERT INTO tasks (title, description, due_date, all_day, created_by_id, assignment_type, assigned_user_id, meeting_id, org_id)
+			VALUES ($1, $2, $3, true, $4, $5, $6, $7, (SELECT org_id FROM meetings WHERE id = $7))
+			RETURNING `+taskColumns,
+			item.Description, description, dueDate, createdByID, models.AssignmentTypeUser, item.AssigneeID, meeting.ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create task from action item: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE meeting_action_items SET task_id = $2 WHERE id = $1`, item.ID, task.ID); err != nil {
+			return nil, fmt.Errorf("failed to link action item to task: %w", err)
+		}
+		if err := enqueueWebhooks(ctx, tx, events.TaskCreated, task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return tasks, nil
+}
@@ -2,17 +2,19 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 )
 
 const meetingColumns = `id, title, description, start_time, end_time, created_by_id,
 	recurrence_type, recurrence_interval, recurrence_end_date, recurrence_days_of_week,
-	recurrence_day_of_month, parent_meeting_id, created_at, updated_at`
+	recurrence_day_of_month, parent_meeting_id, original_start_time, is_cancelled, ical_uid, timezone, created_at, updated_at`
 
 type MeetingRepository struct {
 	pool *pgxpool.Pool
@@ -30,7 +32,7 @@ func scanMeeting(row pgx.Row) (*models.Meeting, error) {
 		&meeting.ID, &meeting.Title, &meeting.Description, &meeting.StartTime, &meeting.EndTime,
 		&meeting.CreatedByID, &recurrenceType, &meeting.RecurrenceInterval,
 		&meeting.RecurrenceEndDate, &meeting.RecurrenceDaysOfWeek, &meeting.RecurrenceDayOfMonth,
-		&meeting.ParentMeetingID, &meeting.CreatedAt, &meeting.UpdatedAt,
+		&meeting.ParentMeetingID, &meeting.OriginalStartTime, &meeting.IsCancelled, &meeting.ICalUID, &meeting.Timezone, &meeting.CreatedAt, &meeting.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -52,7 +54,7 @@ func scanMeetings(rows pgx.Rows) ([]models.Meeting, error) {
 			&meeting.ID, &meeting.Title, &meeting.Description, &meeting.StartTime, &meeting.EndTime,
 			&meeting.CreatedByID, &recurrenceType, &meeting.RecurrenceInterval,
 			&meeting.RecurrenceEndDate, &meeting.RecurrenceDaysOfWeek, &meeting.RecurrenceDayOfMonth,
-			&meeting.ParentMeetingID, &meeting.CreatedAt, &meeting.UpdatedAt,
+			&meeting.ParentMeetingID, &meeting.OriginalStartTime, &meeting.IsCancelled, &meeting.ICalUID, &meeting.Timezone, &meeting.CreatedAt, &meeting.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -68,6 +70,7 @@ func scanMeetings(rows pgx.Rows) ([]models.Meeting, error) {
 
 // Create creates a new meeting with attendees
 func (r *MeetingRepository) Create(ctx context.Context, req *models.CreateMeetingRequest, createdByID int64) (*models.Meeting, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.Create")
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start transaction: %w", err)
@@ -88,8 +91,10 @@ func (r *MeetingRepository) Create(ctx context.Context, req *models.CreateMeetin
 	query := `
 		INSERT INTO meetings (title, description, start_time, end_time, created_by_id,
 			recurrence_type, recurrence_interval, recurrence_end_date, recurrence_days_of_week,
-			recurrence_day_of_month)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			recurrence_day_of_month, ical_uid, timezone, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+			COALESCE($12, (SELECT timezone FROM users WHERE id = $5), 'UTC'),
+			(SELECT org_id FROM users WHERE id = $5))
 		RETURNING ` + meetingColumns
 
 	var meeting models.Meeting
@@ -97,12 +102,12 @@ func (r *MeetingRepository) Create(ctx context.Context, req *models.CreateMeetin
 	err = tx.QueryRow(ctx, query,
 		req.Title, req.Description, req.StartTime, req.EndTime, createdByID,
 		recurrenceType, recurrenceInterval, req.RecurrenceEndDate, req.RecurrenceDaysOfWeek,
-		req.RecurrenceDayOfMonth,
+		req.RecurrenceDayOfMonth, req.ICalUID, req.Timezone,
 	).Scan(
 		&meeting.ID, &meeting.Title, &meeting.Description, &meeting.StartTime, &meeting.EndTime,
 		&meeting.CreatedByID, &rtScan, &meeting.RecurrenceInterval,
 		&meeting.RecurrenceEndDate, &meeting.RecurrenceDaysOfWeek, &meeting.RecurrenceDayOfMonth,
-		&meeting.ParentMeetingID, &meeting.CreatedAt, &meeting.UpdatedAt,
+		&meeting.ParentMeetingID, &meeting.OriginalStartTime, &meeting.IsCancelled, &meeting.ICalUID, &meeting.Timezone, &meeting.CreatedAt, &meeting.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create meeting: %w", err)
@@ -123,47 +128,67 @@ func (r *MeetingRepository) Create(ctx context.Context, req *models.CreateMeetin
 		}
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	// Fetch attendees
-	meeting.Attendees, err = r.GetAttendees(ctx, meeting.ID)
+	meeting.Attendees, err = r.getAttendees(ctx, tx, meeting.ID)
 	if err != nil {
 		return nil, err
 	}
+	if err := enqueueWebhooks(ctx, tx, events.MeetingCreated, &meeting); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
 	return &meeting, nil
 }
 
 // GetByID retrieves a meeting by ID with attendees
 func (r *MeetingRepository) GetByID(ctx context.Context, id int64) (*models.Meeting, error) {
-	query := `SELECT ` + meetingColumns + ` FROM meetings WHERE id = $1`
-	meeting, err := scanMeeting(r.pool.QueryRow(ctx, query, id))
+	ctx = withQueryName(ctx, "MeetingRepository.GetByID")
+	return r.getByID(ctx, r.pool, id)
+}
+
+// getByID retrieves a meeting by ID with attendees, with the pool or a transaction
+func (r *MeetingRepository) getByID(ctx context.Context, q querier, id int64) (*models.Meeting, error) {
+	query := `SELECT ` + meetingColumns + ` FROM meetings WHERE id = $1 AND deleted_at IS NULL AND ` + orgCondition("org_id", "$2")
+	meeting, err := scanMeeting(q.QueryRow(ctx, query, id, orgScope(ctx)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get meeting by ID: %w", err)
 	}
 
-	meeting.Attendees, err = r.GetAttendees(ctx, id)
+	meeting.Attendees, err = r.getAttendees(ctx, q, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return meeting, nil
+	meetings := []models.Meeting{*meeting}
+	if err := r.loadExceptionStarts(ctx, q, meetings); err != nil {
+		return nil, err
+	}
+
+	return &meetings[0], nil
 }
 
 // GetAttendees retrieves attendees for a meeting
 func (r *MeetingRepository) GetAttendees(ctx context.Context, meetingID int64) ([]models.MeetingAttendee, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.GetAttendees")
+	return r.getAttendees(ctx, r.pool, meetingID)
+}
+
+// getAttendees retrieves attendees for a meeting with the pool or a transaction
+func (r *MeetingRepository) getAttendees(ctx context.Context, q querier, meetingID int64) ([]models.MeetingAttendee, error) {
 	query := `
 		SELECT a.id, a.meeting_id, a.user_id, a.response_status, a.created_at, a.updated_at,
 			u.id, COALESCE(u.auth0_id, ''), u.email, u.first_name, u.last_name, u.role, u.title,
 			u.department, u.avatar_url, u.supervisor_id, u.date_started, u.created_at, u.updated_at
 		FROM meeting_attendees a
-		JOIN users u ON a.user_id = u.id
+		JOIN users u ON a.user_id = u.id AND u.deleted_at IS NULL
 		WHERE a.meeting_id = $1
 		ORDER BY u.last_name, u.first_name`
 
-	rows, err := r.pool.Query(ctx, query, meetingID)
+	rows, err := q.Query(ctx, query, meetingID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get attendees: %w", err)
 	}
@@ -192,6 +217,7 @@ func (r *MeetingRepository) GetAttendees(ctx context.Context, meetingID int64) (
 
 // Update updates a meeting
 func (r *MeetingRepository) Update(ctx context.Context, id int64, req *models.UpdateMeetingRequest) (*models.Meeting, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.Update")
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start transaction: %w", err)
@@ -215,8 +241,9 @@ func (r *MeetingRepository) Update(ctx context.Context, id int64, req *models.Up
 			recurrence_end_date = COALESCE($8, recurrence_end_date),
 			recurrence_days_of_week = COALESCE($9, recurrence_days_of_week),
 			recurrence_day_of_month = COALESCE($10, recurrence_day_of_month),
+			timezone = COALESCE($11, timezone),
 			updated_at = NOW()
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL AND ` + orgCondition("org_id", "$12") + `
 		RETURNING ` + meetingColumns
 
 	var meeting models.Meeting
@@ -224,12 +251,12 @@ func (r *MeetingRepository) Update(ctx context.Context, id int64, req *models.Up
 	err = tx.QueryRow(ctx, query,
 		id, req.Title, req.Description, req.StartTime, req.EndTime,
 		recurrenceType, req.RecurrenceInterval, req.RecurrenceEndDate,
-		req.RecurrenceDaysOfWeek, req.RecurrenceDayOfMonth,
+		req.RecurrenceDaysOfWeek, req.RecurrenceDayOfMonth, req.Timezone, orgScope(ctx),
 	).Scan(
 		&meeting.ID, &meeting.Title, &meeting.Description, &meeting.StartTime, &meeting.EndTime,
 		&meeting.CreatedByID, &rtScan, &meeting.RecurrenceInterval,
 		&meeting.RecurrenceEndDate, &meeting.RecurrenceDaysOfWeek, &meeting.RecurrenceDayOfMonth,
-		&meeting.ParentMeetingID, &meeting.CreatedAt, &meeting.UpdatedAt,
+		&meeting.ParentMeetingID, &meeting.OriginalStartTime, &meeting.IsCancelled, &meeting.ICalUID, &meeting.Timezone, &meeting.CreatedAt, &meeting.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update meeting: %w", err)
@@ -259,33 +286,106 @@ func (r *MeetingRepository) Update(ctx context.Context, id int64, req *models.Up
 		}
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	// Fetch attendees
-	meeting.Attendees, err = r.GetAttendees(ctx, id)
+	meeting.Attendees, err = r.getAttendees(ctx, tx, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := enqueueWebhooks(ctx, tx, events.MeetingUpdated, &meeting); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
 	return &meeting, nil
 }
 
-// Delete deletes a meeting
+// Delete soft-deletes a meeting along with the exceptions of its series; they are hidden until
+// restored and purged once the retention period passes
 func (r *MeetingRepository) Delete(ctx context.Context, id int64) error {
-	result, err := r.pool.Exec(ctx, `DELETE FROM meetings WHERE id = $1`, id)
+	ctx = withQueryName(ctx, "MeetingRepository.Delete")
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete meeting: %w", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
 	}
-	if result.RowsAffected() == 0 {
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	meeting, err := r.getByID(ctx, tx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
 		return fmt.Errorf("meeting not found")
 	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE meetings SET deleted_at = NOW(), updated_at = NOW()
+		WHERE (id = $1 OR parent_meeting_id = $1) AND deleted_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete meeting: %w", err)
+	}
+	if err := enqueueWebhooks(ctx, tx, events.MeetingDeleted, meeting); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
+// Restore brings back a soft-deleted meeting and the exceptions deleted with it
+func (r *MeetingRepository) Restore(ctx context.Context, id int64) (*models.Meeting, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.Restore")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := tx.Exec(ctx, `
+		UPDATE meetings SET deleted_at = NULL, updated_at = NOW()
+		WHERE (id = $1 OR parent_meeting_id = $1)
+		AND deleted_at = (
+			SELECT deleted_at FROM meetings
+			WHERE id = $1 AND deleted_at IS NOT NULL AND `+orgCondition("org_id", "$2")+`
+		)`, id, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore meeting: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, fmt.Errorf("deleted meeting not found")
+	}
+
+	meeting, err := r.getByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := enqueueWebhooks(ctx, tx, events.MeetingCreated, meeting); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return meeting, nil
+}
+
+// PurgeDeleted permanently removes meetings soft-deleted before the cutoff, returning how many were removed
+func (r *MeetingRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.PurgeDeleted")
+	result, err := r.pool.Exec(ctx, `DELETE FROM meetings WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted meetings: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
 // RespondToMeeting updates an attendee's response
 func (r *MeetingRepository) RespondToMeeting(ctx context.Context, meetingID int64, userID int64, response models.ResponseStatus) error {
+	ctx = withQueryName(ctx, "MeetingRepository.RespondToMeeting")
 	result, err := r.pool.Exec(ctx, `
 		UPDATE meeting_attendees SET
 			response_status = $3,
@@ -303,17 +403,22 @@ func (r *MeetingRepository) RespondToMeeting(ctx context.Context, meetingID int6
 
 // GetByDateRange retrieves meetings within a date range for a user (as creator or attendee)
 func (r *MeetingRepository) GetByDateRange(ctx context.Context, userID int64, start, end time.Time) ([]models.Meeting, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.GetByDateRange")
 	query := `
 		SELECT DISTINCT m.id, m.title, m.description, m.start_time, m.end_time, m.created_by_id,
 			m.recurrence_type, m.recurrence_interval, m.recurrence_end_date, m.recurrence_days_of_week,
-			m.recurrence_day_of_month, m.parent_meeting_id, m.created_at, m.updated_at
+			m.recurrence_day_of_month, m.parent_meeting_id, m.original_start_time, m.is_cancelled, m.ical_uid, m.timezone, m.created_at, m.updated_at
 		FROM meetings m
 		LEFT JOIN meeting_attendees a ON m.id = a.meeting_id
-		WHERE m.start_time >= $1 AND m.start_time <= $2
+		WHERE ((m.start_time >= $1 AND m.start_time <= $2)
+			OR (m.recurrence_type IS NOT NULL AND m.start_time <= $2 AND (m.recurrence_end_date IS NULL OR m.recurrence_end_date >= $1)))
+		AND NOT m.is_cancelled
+		AND m.deleted_at IS NULL
 		AND (m.created_by_id = $3 OR a.user_id = $3)
+		AND ` + orgCondition("m.org_id", "$4") + `
 		ORDER BY m.start_time`
 
-	rows, err := r.pool.Query(ctx, query, start, end, userID)
+	rows, err := r.pool.Query(ctx, query, start, end, userID, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get meetings by date range: %w", err)
 	}
@@ -332,18 +437,27 @@ func (r *MeetingRepository) GetByDateRange(ctx context.Context, userID int64, st
 		}
 	}
 
+	if err := r.loadExceptionStarts(ctx, r.pool, meetings); err != nil {
+		return nil, err
+	}
+
 	return meetings, nil
 }
 
 // GetAllByDateRange retrieves all meetings within a date range (admin only)
 func (r *MeetingRepository) GetAllByDateRange(ctx context.Context, start, end time.Time) ([]models.Meeting, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.GetAllByDateRange")
 	query := `
 		SELECT ` + meetingColumns + `
 		FROM meetings
-		WHERE start_time >= $1 AND start_time <= $2
+		WHERE ((start_time >= $1 AND start_time <= $2)
+			OR (recurrence_type IS NOT NULL AND start_time <= $2 AND (recurrence_end_date IS NULL OR recurrence_end_date >= $1)))
+		AND NOT is_cancelled
+		AND deleted_at IS NULL
+		AND ` + orgCondition("org_id", "$3") + `
 		ORDER BY start_time`
 
-	rows, err := r.pool.Query(ctx, query, start, end)
+	rows, err := r.pool.Query(ctx, query, start, end, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all meetings by date range: %w", err)
 	}
@@ -362,11 +476,64 @@ func (r *MeetingRepository) GetAllByDateRange(ctx context.Context, start, end ti
 		}
 	}
 
+	if err := r.loadExceptionStarts(ctx, r.pool, meetings); err != nil {
+		return nil, err
+	}
+
+	return meetings, nil
+}
+
+// GetForUsersInRange retrieves meetings any of the users organize or attend that may occur within a date range.
+// Recurring series that started before the range are included so they can be expanded.
+func (r *MeetingRepository) GetForUsersInRange(ctx context.Context, userIDs []int64, start, end time.Time) ([]models.Meeting, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.GetForUsersInRange")
+	if len(userIDs) == 0 {
+		return []models.Meeting{}, nil
+	}
+
+	query := `
+		SELECT DISTINCT m.id, m.title, m.description, m.start_time, m.end_time, m.created_by_id,
+			m.recurrence_type, m.recurrence_interval, m.recurrence_end_date, m.recurrence_days_of_week,
+			m.recurrence_day_of_month, m.parent_meeting_id, m.original_start_time, m.is_cancelled, m.ical_uid, m.timezone, m.created_at, m.updated_at
+		FROM meetings m
+		LEFT JOIN meeting_attendees a ON m.id = a.meeting_id
+		WHERE (m.created_by_id = ANY($1) OR a.user_id = ANY($1))
+		AND m.start_time < $3
+		AND (m.end_time > $2 OR (m.recurrence_type IS NOT NULL AND (m.recurrence_end_date IS NULL OR m.recurrence_end_date >= $2)))
+		AND NOT m.is_cancelled
+		AND m.deleted_at IS NULL
+		AND ` + orgCondition("m.org_id", "$4") + `
+		ORDER BY m.start_time`
+
+	rows, err := r.pool.Query(ctx, query, userIDs, start, end, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meetings for users: %w", err)
+	}
+	defer rows.Close()
+
+	meetings, err := scanMeetings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan meetings: %w", err)
+	}
+
+	// Fetch attendees for each meeting
+	for i := range meetings {
+		meetings[i].Attendees, err = r.GetAttendees(ctx, meetings[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.loadExceptionStarts(ctx, r.pool, meetings); err != nil {
+		return nil, err
+	}
+
 	return meetings, nil
 }
 
 // GetVisibleMeetings retrieves all meetings visible to a user within a date range
 func (r *MeetingRepository) GetVisibleMeetings(ctx context.Context, user *models.User, start, end time.Time) ([]models.Meeting, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.GetVisibleMeetings")
 	// Admin sees all meetings
 	if user.IsAdmin() {
 		return r.GetAllByDateRange(ctx, start, end)
@@ -380,8 +547,12 @@ func (r *MeetingRepository) ExpandRecurringMeetings(meetings []models.Meeting, s
 	var expanded []models.Meeting
 
 	for _, meeting := range meetings {
+		if meeting.IsCancelled {
+			continue
+		}
+
 		if meeting.RecurrenceType == nil {
-			// Non-recurring meeting - just add if within range
+			// Non-recurring meeting (or an exception to a series) - just add if within range
 			if (meeting.StartTime.After(start) || meeting.StartTime.Equal(start)) &&
 				(meeting.StartTime.Before(end) || meeting.StartTime.Equal(end)) {
 				expanded = append(expanded, meeting)
@@ -400,7 +571,9 @@ func (r *MeetingRepository) ExpandRecurringMeetings(meetings []models.Meeting, s
 		}
 
 		for current.Before(recurrenceEnd) || current.Equal(recurrenceEnd) {
-			if (current.After(start) || current.Equal(start)) && (current.Before(end) || current.Equal(end)) {
+			// Occurrences that were edited or cancelled are replaced by their exception rows
+			if (current.After(start) || current.Equal(start)) && (current.Before(end) || current.Equal(end)) &&
+				!meeting.HasException(current) {
 				// Create occurrence
 				occurrence := meeting
 				occurrence.StartTime = current
@@ -409,14 +582,7 @@ func (r *MeetingRepository) ExpandRecurringMeetings(meetings []models.Meeting, s
 			}
 
 			// Calculate next occurrence
-			switch *meeting.RecurrenceType {
-			case models.RecurrenceTypeDaily:
-				current = current.AddDate(0, 0, meeting.RecurrenceInterval)
-			case models.RecurrenceTypeWeekly:
-				current = current.AddDate(0, 0, 7*meeting.RecurrenceInterval)
-			case models.RecurrenceTypeMonthly:
-				current = current.AddDate(0, meeting.RecurrenceInterval, 0)
-			}
+			current = meeting.NextOccurrence(current)
 		}
 	}
 
@@ -425,6 +591,7 @@ func (r *MeetingRepository) ExpandRecurringMeetings(meetings []models.Meeting, s
 
 // IsAttendee checks if a user is an attendee of a meeting
 func (r *MeetingRepository) IsAttendee(ctx context.Context, meetingID, userID int64) (bool, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.IsAttendee")
 	var exists bool
 	err := r.pool.QueryRow(ctx, `
 		SELECT EXISTS(SELECT 1 FROM meeting_attendees WHERE meeting_id = $1 AND user_id = $2)
@@ -434,3 +601,262 @@ func (r *MeetingRepository) IsAttendee(ctx context.Context, meetingID, userID in
 	}
 	return exists, nil
 }
+
+// loadExceptionStarts records which occurrences of each recurring meeting have been edited or cancelled
+func (r *MeetingRepository) loadExceptionStarts(ctx context.Context, q querier, meetings []models.Meeting) error {
+	var seriesIDs []int64
+	for i := range meetings {
+		if meetings[i].RecurrenceType != nil {
+			seriesIDs = append(seriesIDs, meetings[i].ID)
+		}
+	}
+	if len(seriesIDs) == 0 {
+		return nil
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT parent_meeting_id, original_start_time
+		FROM meetings
+		WHERE parent_meeting_id = ANY($1) AND original_start_time IS NOT NULL AND deleted_at IS NULL
+	`, seriesIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get meeting exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	exceptions := make(map[int64][]time.Time)
+	for rows.Next() {
+		var parentID int64
+		var originalStart time.Time
+		if err := rows.Scan(&parentID, &originalStart); err != nil {
+			return fmt.Errorf("failed to scan meeting exception: %w", err)
+		}
+		exceptions[parentID] = append(exceptions[parentID], originalStart)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get meeting exceptions: %w", err)
+	}
+
+	for i := range meetings {
+		meetings[i].ExceptionStarts = exceptions[meetings[i].ID]
+	}
+	return nil
+}
+
+// UpdateOccurrence edits a recurring meeting from a single occurrence.
+// Scope "this" stores an exception replacing only that occurrence; scope "following" splits the
+// series so the occurrence and everything after it becomes a new series with the changes applied.
+func (r *MeetingRepository) UpdateOccurrence(ctx context.Context, seriesID int64, req *models.UpdateMeetingOccurrenceRequest) (*models.Meeting, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.UpdateOccurrence")
+	series, err := r.GetByID(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Editing the first occurrence onwards is the same as editing the whole series
+	if req.Scope == models.OccurrenceScopeFollowing && !req.OccurrenceStart.After(series.StartTime) {
+		return r.Update(ctx, seriesID, &req.UpdateMeetingRequest)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	startTime := req.OccurrenceStart
+	if req.StartTime != nil {
+		startTime = *req.StartTime
+	}
+	endTime := startTime.Add(series.EndTime.Sub(series.StartTime))
+	if req.EndTime != nil {
+		endTime = *req.EndTime
+	}
+
+	var meeting *models.Meeting
+	if req.Scope == models.OccurrenceScopeThis {
+		query := `
+			INSERT INTO meetings (title, description, start_time, end_time, created_by_id,
+				parent_meeting_id, original_start_time, is_cancelled, timezone, org_id)
+			SELECT COALESCE($3, s.title), COALESCE($4, s.description), $5::timestamptz, $6::timestamptz,
+				s.created_by_id, s.id, $2::timestamptz, false, s.timezone, s.org_id
+			FROM meetings s
+			WHERE s.id = $1
+			ON CONFLICT (parent_meeting_id, original_start_time) WHERE parent_meeting_id IS NOT NULL DO UPDATE SET
+				title = COALESCE($3, meetings.title),
+				description = COALESCE($4, meetings.description),
+				start_time = CASE WHEN $7 THEN meetings.start_time ELSE EXCLUDED.start_time END,
+				end_time = CASE WHEN $8 THEN meetings.end_time ELSE EXCLUDED.end_time END,
+				is_cancelled = false,
+				deleted_at = NULL,
+				updated_at = NOW()
+			RETURNING ` + meetingColumns
+
+		// An existing exception keeps its previously edited times unless new ones are given
+		meeting, err = scanMeeting(tx.QueryRow(ctx, query,
+			seriesID, req.OccurrenceStart, req.Title, req.Description, startTime, endTime,
+			req.StartTime == nil, req.EndTime == nil,
+		))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save meeting exception: %w", err)
+		}
+	} else {
+		var recurrenceType *string
+		if req.RecurrenceType != nil {
+			s := string(*req.RecurrenceType)
+			recurrenceType = &s
+		}
+
+		query := `
+			INSERT INTO meetings (title, description, start_time, end_time, created_by_id,
+				recurrence_type, recurrence_interval, recurrence_end_date, recurrence_days_of_week,
+				recurrence_day_of_month, timezone, org_id)
+			SELECT COALESCE($2, s.title), COALESCE($3, s.description), $4::timestamptz, $5::timestamptz, s.created_by_id,
+				COALESCE($6, s.recurrence_type), COALESCE($7, s.recurrence_interval),
+				COALESCE($8, s.recurrence_end_date), COALESCE($9, s.recurrence_days_of_week),
+				COALESCE($10, s.recurrence_day_of_month), COALESCE($11, s.timezone), s.org_id
+			FROM meetings s
+			WHERE s.id = $1
+			RETURNING ` + meetingColumns
+
+		meeting, err = scanMeeting(tx.QueryRow(ctx, query,
+			seriesID, req.Title, req.Description, startTime, endTime,
+			recurrenceType, req.RecurrenceInterval, req.RecurrenceEndDate,
+			req.RecurrenceDaysOfWeek, req.RecurrenceDayOfMonth, req.Timezone,
+		))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create meeting series: %w", err)
+		}
+
+		if err := r.endSeriesBefore(ctx, tx, seriesID, req.OccurrenceStart); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(req.AttendeeIDs) > 0 {
+		_, err = tx.Exec(ctx, `DELETE FROM meeting_attendees WHERE meeting_id = $1`, meeting.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove existing attendees: %w", err)
+		}
+		for _, userID := range req.AttendeeIDs {
+			_, err = tx.Exec(ctx, `
+				INSERT INTO meeting_attendees (meeting_id, user_id, response_status)
+				VALUES ($1, $2, 'pending')
+			`, meeting.ID, userID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to add attendee: %w", err)
+			}
+		}
+	} else {
+		// New exceptions and series start with the series' attendees and their responses
+		_, err = tx.Exec(ctx, `
+			INSERT INTO meeting_attendees (meeting_id, user_id, response_status)
+			SELECT $1, user_id, response_status FROM meeting_attendees
+			WHERE meeting_id = $2
+			AND NOT EXISTS (SELECT 1 FROM meeting_attendees WHERE meeting_id = $1)
+		`, meeting.ID, seriesID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy attendees: %w", err)
+		}
+	}
+
+	meeting.Attendees, err = r.getAttendees(ctx, tx, meeting.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := enqueueWebhooks(ctx, tx, events.MeetingUpdated, meeting); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return meeting, nil
+}
+
+// CancelOccurrence cancels a recurring meeting from a single occurrence.
+// Scope "this" stores a cancelled exception for that occurrence; scope "following" ends the series
+// before the occurrence, or deletes the series when the occurrence is its first.
+func (r *MeetingRepository) CancelOccurrence(ctx context.Context, seriesID int64, occurrenceStart time.Time, scope models.OccurrenceScope) error {
+	ctx = withQueryName(ctx, "MeetingRepository.CancelOccurrence")
+	series, err := r.GetByID(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+
+	if scope == models.OccurrenceScopeFollowing && !occurrenceStart.After(series.StartTime) {
+		return r.Delete(ctx, seriesID)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if scope == models.OccurrenceScopeThis {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO meetings (title, description, start_time, end_time, created_by_id,
+				parent_meeting_id, original_start_time, is_cancelled, timezone, org_id)
+			SELECT s.title, s.description, $2::timestamptz, $2::timestamptz + (s.end_time - s.start_time),
+				s.created_by_id, s.id, $2::timestamptz, true, s.timezone, s.org_id
+			FROM meetings s
+			WHERE s.id = $1
+			ON CONFLICT (parent_meeting_id, original_start_time) WHERE parent_meeting_id IS NOT NULL DO UPDATE SET
+				is_cancelled = true,
+				deleted_at = NULL,
+				updated_at = NOW()
+		`, seriesID, occurrenceStart)
+		if err != nil {
+			return fmt.Errorf("failed to cancel meeting occurrence: %w", err)
+		}
+	} else if err := r.endSeriesBefore(ctx, tx, seriesID, occurrenceStart); err != nil {
+		return err
+	}
+
+	updated, err := r.getByID(ctx, tx, seriesID)
+	if err != nil {
+		return err
+	}
+	if err := enqueueWebhooks(ctx, tx, events.MeetingUpdated, updated); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// endSeriesBefore stops a recurring meeting just before an occurrence and drops exceptions from that occurrence on
+func (r *MeetingRepository) endSeriesBefore(ctx context.Context, tx pgx.Tx, seriesID int64, occurrenceStart time.Time) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE meetings SET recurrence_end_date = $2, updated_at = NOW()
+		WHERE id = $1
+	`, seriesID, occurrenceStart.Add(-time.Second))
+	if err != nil {
+		return fmt.Errorf("failed to end meeting series: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM meetings WHERE parent_meeting_id = $1 AND original_start_time >= $2
+	`, seriesID, occurrenceStart)
+	if err != nil {
+		return fmt.Errorf("failed to remove meeting exceptions: %w", err)
+	}
+	return nil
+}
+
+// HasICalUID checks if a user has already imported the iCalendar event with the given UID
+func (r *MeetingRepository) HasICalUID(ctx context.Context, createdByID int64, uid string) (bool, error) {
+	ctx = withQueryName(ctx, "MeetingRepository.HasICalUID")
+	var exists bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM meetings WHERE created_by_id = $1 AND ical_uid = $2 AND deleted_at IS NULL)
+	`, createdByID, uid).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check imported meeting: %w", err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,14 @@
+-- Holds OAuth tokens and the accessible Atlassian sites from a completed OAuth
+-- callback until an admin chooses which site to connect
+CREATE TABLE IF NOT EXISTS jira_pending_connections (
+    id BIGSERIAL PRIMARY KEY,
+    oauth_access_token TEXT NOT NULL,
+    oauth_refresh_token TEXT NOT NULL,
+    oauth_token_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    resources JSONB NOT NULL DEFAULT '[]',
+    configured_by_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_jira_pending_connections_expires_at ON jira_pending_connections(expires_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS meeting_attachments;
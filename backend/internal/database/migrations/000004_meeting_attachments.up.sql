@@ -0,0 +1,20 @@
+-- Recordings and transcripts attached to a meeting (optionally a single
+-- occurrence of a recurring meeting), either uploaded to storage or linked
+CREATE TABLE IF NOT EXISTS meeting_attachments (
+    id BIGSERIAL PRIMARY KEY,
+    meeting_id BIGINT NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    occurrence_start TIMESTAMP WITH TIME ZONE,
+    kind VARCHAR(20) NOT NULL,
+    url TEXT NOT NULL,
+    storage_key TEXT,
+    file_name VARCHAR(255),
+    content_type VARCHAR(255),
+    size_bytes BIGINT,
+    transcript_text TEXT,
+    search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', COALESCE(transcript_text, ''))) STORED,
+    uploaded_by_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_attachments_meeting_id ON meeting_attachments(meeting_id, occurrence_start);
+CREATE INDEX IF NOT EXISTS idx_meeting_attachments_search_vector ON meeting_attachments USING GIN(search_vector);
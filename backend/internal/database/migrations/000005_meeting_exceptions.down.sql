@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_meetings_parent_occurrence;
+ALTER TABLE meetings DROP COLUMN IF EXISTS is_cancelled;
+ALTER TABLE meetings DROP COLUMN IF EXISTS original_start_time;
@@ -0,0 +1,8 @@
+-- Exceptions to a recurring meeting are stored as meetings with parent_meeting_id set.
+-- original_start_time identifies which occurrence of the parent series they replace.
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS original_start_time TIMESTAMP WITH TIME ZONE;
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS is_cancelled BOOLEAN NOT NULL DEFAULT false;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_meetings_parent_occurrence
+    ON meetings(parent_meeting_id, original_start_time)
+    WHERE parent_meeting_id IS NOT NULL;
@@ -0,0 +1,6 @@
+-- UID of the iCalendar event a meeting was imported from, so re-importing the same invite is a no-op.
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS ical_uid TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_meetings_creator_ical_uid
+    ON meetings(created_by_id, ical_uid)
+    WHERE ical_uid IS NOT NULL;
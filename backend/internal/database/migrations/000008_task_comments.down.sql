@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_activity;
+DROP TABLE IF EXISTS task_comments;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_access_expires_at;
+ALTER TABLE invitations DROP COLUMN IF EXISTS meeting_ids;
+ALTER TABLE invitations DROP COLUMN IF EXISTS access_expires_at;
+ALTER TABLE users DROP COLUMN IF EXISTS access_expires_at;
@@ -0,0 +1,8 @@
+-- Guest (external collaborator) accounts lose access after this time
+ALTER TABLE users ADD COLUMN IF NOT EXISTS access_expires_at TIMESTAMP WITH TIME ZONE;
+
+-- Guest invitations carry the account expiry and the meetings the guest is added to on acceptance
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS access_expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS meeting_ids BIGINT[];
+
+CREATE INDEX IF NOT EXISTS idx_users_access_expires_at ON users(access_expires_at) WHERE access_expires_at IS NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE org_jira_settings DROP COLUMN IF EXISTS last_webhook_at;
+ALTER TABLE org_jira_settings DROP COLUMN IF EXISTS last_api_error_at;
+ALTER TABLE org_jira_settings DROP COLUMN IF EXISTS last_api_error;
+ALTER TABLE org_jira_settings DROP COLUMN IF EXISTS last_refresh_error_at;
+ALTER TABLE org_jira_settings DROP COLUMN IF EXISTS last_refresh_error;
+ALTER TABLE org_jira_settings DROP COLUMN IF EXISTS refresh_failures;
+ALTER TABLE org_jira_settings DROP COLUMN IF EXISTS last_refreshed_at;
@@ -0,0 +1,8 @@
+-- Track the health of the org-wide Jira connection so admins can see failures before users do
+ALTER TABLE org_jira_settings ADD COLUMN IF NOT EXISTS last_refreshed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE org_jira_settings ADD COLUMN IF NOT EXISTS refresh_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE org_jira_settings ADD COLUMN IF NOT EXISTS last_refresh_error TEXT;
+ALTER TABLE org_jira_settings ADD COLUMN IF NOT EXISTS last_refresh_error_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE org_jira_settings ADD COLUMN IF NOT EXISTS last_api_error TEXT;
+ALTER TABLE org_jira_settings ADD COLUMN IF NOT EXISTS last_api_error_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE org_jira_settings ADD COLUMN IF NOT EXISTS last_webhook_at TIMESTAMP WITH TIME ZONE;
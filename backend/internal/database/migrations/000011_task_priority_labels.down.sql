@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_tasks_labels;
+DROP INDEX IF EXISTS idx_tasks_priority;
+ALTER TABLE tasks DROP COLUMN IF EXISTS labels;
+ALTER TABLE tasks DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS meeting_action_items;
+DROP INDEX IF EXISTS idx_tasks_meeting_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS meeting_id;
+DROP TABLE IF EXISTS meeting_notes;
+DROP TABLE IF EXISTS meeting_agenda_items;
@@ -0,0 +1,37 @@
+-- Ordered agenda topics for a meeting
+CREATE TABLE IF NOT EXISTS meeting_agenda_items (
+    id BIGSERIAL PRIMARY KEY,
+    meeting_id BIGINT NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    duration_minutes INT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_agenda_items_meeting_id ON meeting_agenda_items(meeting_id, position);
+
+-- Post-meeting notes, one document per meeting
+CREATE TABLE IF NOT EXISTS meeting_notes (
+    meeting_id BIGINT PRIMARY KEY REFERENCES meetings(id) ON DELETE CASCADE,
+    notes TEXT NOT NULL DEFAULT '',
+    updated_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Tasks created from a meeting's action items link back to the meeting
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS meeting_id BIGINT REFERENCES meetings(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_meeting_id ON tasks(meeting_id) WHERE meeting_id IS NOT NULL;
+
+-- Follow-ups captured in meeting notes; task_id is set once converted to a task
+CREATE TABLE IF NOT EXISTS meeting_action_items (
+    id BIGSERIAL PRIMARY KEY,
+    meeting_id BIGINT NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    description VARCHAR(255) NOT NULL,
+    assignee_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    due_date TIMESTAMP WITH TIME ZONE,
+    task_id BIGINT REFERENCES tasks(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_action_items_meeting_id ON meeting_action_items(meeting_id, id);
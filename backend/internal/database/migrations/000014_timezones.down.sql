@@ -0,0 +1,2 @@
+ALTER TABLE meetings DROP COLUMN IF EXISTS timezone;
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,3 @@
+-- IANA timezone names. A meeting's timezone anchors its recurrence and defaults to the organizer's.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS user_work_schedules;
@@ -0,0 +1,10 @@
+-- Working hours and weekly no-meeting blocks, in the user's timezone.
+-- Users without a row get the default 9:00-17:00, Monday to Friday.
+CREATE TABLE IF NOT EXISTS user_work_schedules (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    start_minute INTEGER NOT NULL CHECK (start_minute >= 0 AND start_minute < 1440),
+    end_minute INTEGER NOT NULL CHECK (end_minute > start_minute AND end_minute <= 1440),
+    weekdays INTEGER[] NOT NULL,
+    focus_blocks JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tasks_recurring;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence_parent_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence_end_date;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence_interval;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence_type;
@@ -0,0 +1,8 @@
+-- Recurring tasks. Only the open instance of a series carries its recurrence; finishing it generates
+-- the next instance, which links back to the first task of the series.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_type VARCHAR(20);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_interval INT NOT NULL DEFAULT 1;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_end_date TIMESTAMP WITH TIME ZONE;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_parent_id BIGINT REFERENCES tasks(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_recurring ON tasks(due_date) WHERE recurrence_type IS NOT NULL;
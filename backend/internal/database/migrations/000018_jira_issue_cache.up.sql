@@ -0,0 +1,13 @@
+-- Local copy of Jira issues, kept current by Jira webhooks so team task views
+-- don't need to query Jira on every page load.
+CREATE TABLE IF NOT EXISTS jira_issues_cache (
+    issue_key VARCHAR(50) PRIMARY KEY,
+    issue_id VARCHAR(50) NOT NULL,
+    assignee_account_id VARCHAR(255),
+    resolved BOOLEAN NOT NULL DEFAULT false,
+    data JSONB NOT NULL,
+    jira_updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    synced_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_jira_issues_cache_assignee ON jira_issues_cache(assignee_account_id) WHERE NOT resolved;
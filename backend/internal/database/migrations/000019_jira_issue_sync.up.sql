@@ -0,0 +1,6 @@
+-- When each Jira account's open issues were last fully synced into jira_issues_cache.
+-- Accounts without a row haven't been synced, so their issues are fetched live.
+CREATE TABLE IF NOT EXISTS jira_issue_sync (
+    account_id VARCHAR(255) PRIMARY KEY,
+    synced_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,2 @@
+-- Named JQL filters admins define for the team task view
+ALTER TABLE org_jira_settings ADD COLUMN IF NOT EXISTS task_filters JSONB NOT NULL DEFAULT '[]';
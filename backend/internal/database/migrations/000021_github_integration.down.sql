@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_github_login;
+ALTER TABLE users DROP COLUMN IF EXISTS github_login;
+DROP TABLE IF EXISTS org_github_settings;
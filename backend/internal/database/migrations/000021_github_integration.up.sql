@@ -0,0 +1,18 @@
+-- Organization-wide GitHub App connection, authorized by an admin through OAuth.
+-- Pull request searches are scoped to org_login when it's set.
+CREATE TABLE IF NOT EXISTS org_github_settings (
+    id BIGSERIAL PRIMARY KEY,
+    oauth_access_token TEXT NOT NULL,
+    oauth_refresh_token TEXT NOT NULL DEFAULT '',
+    oauth_token_expires_at TIMESTAMP WITH TIME ZONE,
+    account_login VARCHAR(255) NOT NULL,
+    org_login VARCHAR(255),
+    configured_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Maps employees to their GitHub accounts, like jira_account_id does for Jira
+ALTER TABLE users ADD COLUMN IF NOT EXISTS github_login VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_users_github_login ON users(github_login);
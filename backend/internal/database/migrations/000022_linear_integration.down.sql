@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_linear_user_id;
+ALTER TABLE users DROP COLUMN IF EXISTS linear_user_id;
+DROP TABLE IF EXISTS org_linear_settings;
@@ -0,0 +1,15 @@
+-- Organization-wide Linear connection using a workspace API key set by an admin
+CREATE TABLE IF NOT EXISTS org_linear_settings (
+    id BIGSERIAL PRIMARY KEY,
+    api_key TEXT NOT NULL,
+    workspace_name VARCHAR(255) NOT NULL,
+    workspace_url_key VARCHAR(255) NOT NULL,
+    configured_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Maps employees to their Linear users, like jira_account_id does for Jira
+ALTER TABLE users ADD COLUMN IF NOT EXISTS linear_user_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_users_linear_user_id ON users(linear_user_id);
@@ -0,0 +1 @@
+ALTER TABLE org_jira_settings DROP COLUMN IF EXISTS user_match_report;
@@ -0,0 +1,2 @@
+-- Last Jira user auto-match report, so admins can finish mapping without rescanning Jira
+ALTER TABLE org_jira_settings ADD COLUMN IF NOT EXISTS user_match_report JSONB;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS org_chart_snapshots;
+DROP FUNCTION IF EXISTS prevent_org_chart_snapshot_update();
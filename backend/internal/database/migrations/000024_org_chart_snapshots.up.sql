@@ -0,0 +1,31 @@
+-- Immutable copy of the full reporting tree taken each time a draft is published,
+-- so the org can be viewed as of any past date
+CREATE TABLE IF NOT EXISTS org_chart_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    draft_id BIGINT REFERENCES org_chart_drafts(id) ON DELETE SET NULL,
+    draft_name VARCHAR(255) NOT NULL,
+    published_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    headcount INTEGER NOT NULL,
+    tree JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_chart_snapshots_created_at ON org_chart_snapshots(created_at DESC);
+
+-- Snapshots are history: only the foreign keys may change, when a draft or user is deleted
+CREATE OR REPLACE FUNCTION prevent_org_chart_snapshot_update() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.draft_name IS DISTINCT FROM OLD.draft_name
+        OR NEW.headcount IS DISTINCT FROM OLD.headcount
+        OR NEW.tree IS DISTINCT FROM OLD.tree
+        OR NEW.created_at IS DISTINCT FROM OLD.created_at THEN
+        RAISE EXCEPTION 'org chart snapshots are immutable';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS org_chart_snapshots_immutable ON org_chart_snapshots;
+CREATE TRIGGER org_chart_snapshots_immutable
+    BEFORE UPDATE ON org_chart_snapshots
+    FOR EACH ROW EXECUTE FUNCTION prevent_org_chart_snapshot_update();
@@ -0,0 +1,4 @@
+ALTER TABLE org_chart_drafts DROP COLUMN IF EXISTS approved_at;
+ALTER TABLE org_chart_drafts DROP COLUMN IF EXISTS approved_by_id;
+DROP TABLE IF EXISTS org_chart_draft_comments;
+DROP TABLE IF EXISTS org_chart_draft_shares;
@@ -0,0 +1,28 @@
+-- Supervisors and admins a draft's creator has shared it with
+CREATE TABLE IF NOT EXISTS org_chart_draft_shares (
+    draft_id BIGINT NOT NULL REFERENCES org_chart_drafts(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    permission VARCHAR(20) NOT NULL CHECK (permission IN ('viewer', 'editor')),
+    shared_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (draft_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_chart_draft_shares_user_id ON org_chart_draft_shares(user_id);
+
+-- Review discussion on individual draft changes
+CREATE TABLE IF NOT EXISTS org_chart_draft_comments (
+    id BIGSERIAL PRIMARY KEY,
+    draft_id BIGINT NOT NULL REFERENCES org_chart_drafts(id) ON DELETE CASCADE,
+    change_id BIGINT NOT NULL REFERENCES org_chart_draft_changes(id) ON DELETE CASCADE,
+    author_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_chart_draft_comments_draft_id ON org_chart_draft_comments(draft_id, created_at);
+
+-- Drafts must be approved by someone other than their creator before publishing.
+-- Changing a draft clears its approval.
+ALTER TABLE org_chart_drafts ADD COLUMN IF NOT EXISTS approved_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE org_chart_drafts ADD COLUMN IF NOT EXISTS approved_at TIMESTAMP WITH TIME ZONE;
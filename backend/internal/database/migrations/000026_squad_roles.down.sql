@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_squads_one_lead;
+ALTER TABLE user_squads DROP COLUMN IF EXISTS role;
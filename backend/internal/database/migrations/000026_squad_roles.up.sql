@@ -0,0 +1,6 @@
+-- Squad members are either the squad's lead or a regular member
+ALTER TABLE user_squads ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'member'
+    CHECK (role IN ('lead', 'member'));
+
+-- A squad has at most one lead
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_squads_one_lead ON user_squads(squad_id) WHERE role = 'lead';
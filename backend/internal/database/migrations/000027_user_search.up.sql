@@ -0,0 +1,13 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Whole-word matches on names, email, title, and department. The 'simple' configuration
+-- keeps names from being stemmed.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    to_tsvector('simple', first_name || ' ' || last_name || ' ' || email || ' ' || title || ' ' || department)
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING GIN(search_vector);
+
+-- Partial and misspelled matches on names and email
+CREATE INDEX IF NOT EXISTS idx_users_full_name_trgm ON users USING GIN((first_name || ' ' || last_name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING GIN(email gin_trgm_ops);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_custom_values;
+DROP TABLE IF EXISTS custom_fields;
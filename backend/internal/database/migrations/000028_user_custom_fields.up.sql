@@ -0,0 +1,23 @@
+-- Admin-defined profile fields shown for every user
+CREATE TABLE IF NOT EXISTS custom_fields (
+    id BIGSERIAL PRIMARY KEY,
+    key VARCHAR(64) NOT NULL UNIQUE,
+    label VARCHAR(100) NOT NULL,
+    field_type VARCHAR(20) NOT NULL CHECK (field_type IN ('text', 'date', 'select')),
+    options TEXT[] NOT NULL DEFAULT '{}', -- Allowed values of select fields
+    required BOOLEAN NOT NULL DEFAULT false,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Each user's value for a field; clearing a value deletes its row
+CREATE TABLE IF NOT EXISTS user_custom_values (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    field_id BIGINT NOT NULL REFERENCES custom_fields(id) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, field_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_custom_values_field_id ON user_custom_values(field_id);
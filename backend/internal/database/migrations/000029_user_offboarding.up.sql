@@ -0,0 +1,2 @@
+-- Set when a user is offboarded and cleared if they're reactivated
+ALTER TABLE users ADD COLUMN IF NOT EXISTS termination_date DATE;
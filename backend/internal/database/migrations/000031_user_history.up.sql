@@ -0,0 +1,14 @@
+-- Timeline of changes to each user's title, department, role, supervisor, and squads.
+-- Supervisor values are user IDs and squad values comma-separated squad IDs.
+CREATE TABLE IF NOT EXISTS user_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    field VARCHAR(20) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    source VARCHAR(20) NOT NULL,
+    changed_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_history_user_created ON user_history(user_id, created_at DESC);
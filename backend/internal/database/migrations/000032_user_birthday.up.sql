@@ -0,0 +1,2 @@
+-- Optional birthday shown in the team milestones feed
+ALTER TABLE users ADD COLUMN IF NOT EXISTS birthday DATE;
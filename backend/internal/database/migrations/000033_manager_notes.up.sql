@@ -0,0 +1,14 @@
+-- Private notes a supervisor keeps about a direct report, such as a 1:1 journal.
+-- Only the author can read them; admins may audit them when enabled.
+CREATE TABLE IF NOT EXISTS manager_notes (
+    id BIGSERIAL PRIMARY KEY,
+    author_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subject_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    meeting_id BIGINT REFERENCES meetings(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_manager_notes_author_subject ON manager_notes(author_id, subject_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_manager_notes_subject ON manager_notes(subject_id, created_at DESC);
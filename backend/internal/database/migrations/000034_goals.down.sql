@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS goal_progress_updates;
+DROP TABLE IF EXISTS goal_key_results;
+DROP TABLE IF EXISTS goals;
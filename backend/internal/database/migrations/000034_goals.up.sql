@@ -0,0 +1,49 @@
+-- Quarterly objectives owned by a user, squad, or department, measured by key results
+CREATE TABLE IF NOT EXISTS goals (
+    id BIGSERIAL PRIMARY KEY,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    owner_type VARCHAR(20) NOT NULL CHECK (owner_type IN ('user', 'squad', 'department')),
+    owner_user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    owner_squad_id BIGINT REFERENCES squads(id) ON DELETE CASCADE,
+    owner_department VARCHAR(100),
+    quarter VARCHAR(7) NOT NULL,
+    created_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (
+        (owner_type = 'user' AND owner_user_id IS NOT NULL) OR
+        (owner_type = 'squad' AND owner_squad_id IS NOT NULL) OR
+        (owner_type = 'department' AND owner_department IS NOT NULL)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_goals_quarter ON goals(quarter);
+CREATE INDEX IF NOT EXISTS idx_goals_owner_user ON goals(owner_user_id) WHERE owner_user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_goals_owner_squad ON goals(owner_squad_id) WHERE owner_squad_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS goal_key_results (
+    id BIGSERIAL PRIMARY KEY,
+    goal_id BIGINT NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    start_value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    target_value DOUBLE PRECISION NOT NULL,
+    current_value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    unit VARCHAR(20) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_goal_key_results_goal ON goal_key_results(goal_id, position);
+
+-- History of each key result's value
+CREATE TABLE IF NOT EXISTS goal_progress_updates (
+    id BIGSERIAL PRIMARY KEY,
+    key_result_id BIGINT NOT NULL REFERENCES goal_key_results(id) ON DELETE CASCADE,
+    value DOUBLE PRECISION NOT NULL,
+    note TEXT,
+    author_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_goal_progress_updates_key_result ON goal_progress_updates(key_result_id, created_at DESC);
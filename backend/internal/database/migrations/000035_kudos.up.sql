@@ -0,0 +1,13 @@
+-- Recognition one user gives another, optionally tagged with a company value
+CREATE TABLE IF NOT EXISTS kudos (
+    id BIGSERIAL PRIMARY KEY,
+    from_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    company_value VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (from_user_id <> to_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kudos_created ON kudos(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_kudos_to_user ON kudos(to_user_id, created_at DESC);
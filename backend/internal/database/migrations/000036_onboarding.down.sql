@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS onboarding_tasks;
+DROP TABLE IF EXISTS onboarding_template_items;
+DROP TABLE IF EXISTS onboarding_templates;
@@ -0,0 +1,31 @@
+-- Onboarding templates: ordered checklists instantiated as tasks when a user joins.
+-- A template applies to new users matching its department and role; a NULL matches anyone.
+CREATE TABLE IF NOT EXISTS onboarding_templates (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    department VARCHAR(100),
+    role VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS onboarding_template_items (
+    id BIGSERIAL PRIMARY KEY,
+    template_id BIGINT NOT NULL REFERENCES onboarding_templates(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    assignee VARCHAR(20) NOT NULL CHECK (assignee IN ('new_hire', 'supervisor', 'it')),
+    due_days INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_onboarding_template_items_template ON onboarding_template_items(template_id, position);
+
+-- Tasks created for a user's onboarding, so their progress can be tracked
+CREATE TABLE IF NOT EXISTS onboarding_tasks (
+    task_id BIGINT PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_onboarding_tasks_user ON onboarding_tasks(user_id);
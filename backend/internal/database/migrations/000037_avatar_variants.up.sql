@@ -0,0 +1,2 @@
+-- Resized copies of each user's avatar: [{"size": 32, "url": ..., "content_type": ..., "webp_url": ...}, ...]
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_variants JSONB;
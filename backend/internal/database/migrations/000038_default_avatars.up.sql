@@ -0,0 +1,2 @@
+-- Generated initials SVG or cached Gravatar shown for users who haven't uploaded an avatar
+ALTER TABLE users ADD COLUMN IF NOT EXISTS default_avatar_url TEXT;
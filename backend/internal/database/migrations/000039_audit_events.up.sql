@@ -0,0 +1,22 @@
+-- One row per mutating API request or GraphQL mutation
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    -- The authenticated user; when impersonating, on_behalf_of_id is the impersonated user
+    actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    actor_email VARCHAR(255) NOT NULL DEFAULT '',
+    on_behalf_of_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    -- "PUT /api/users/{id}" for REST requests, "mutation updateEmployee" for GraphQL
+    action VARCHAR(255) NOT NULL,
+    entity_type VARCHAR(100) NOT NULL DEFAULT '',
+    entity_id VARCHAR(100),
+    before JSONB,
+    after JSONB,
+    status INTEGER NOT NULL,
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    request_id VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity ON audit_events(entity_type, entity_id, created_at DESC);
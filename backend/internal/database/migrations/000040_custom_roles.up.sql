@@ -0,0 +1,23 @@
+-- Admin-defined roles granting permissions on top of a user's built-in role
+CREATE TABLE IF NOT EXISTS custom_roles (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    -- [{"action": "timeoff:review", "scope": "team"}, ...]
+    permissions JSONB NOT NULL DEFAULT '[]',
+    created_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custom_roles_name ON custom_roles(LOWER(name));
+
+CREATE TABLE IF NOT EXISTS user_custom_roles (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id BIGINT NOT NULL REFERENCES custom_roles(id) ON DELETE CASCADE,
+    assigned_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    assigned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_custom_roles_role ON user_custom_roles(role_id);
@@ -0,0 +1,35 @@
+-- One row per token a user has authenticated with, updated as the token is used
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- The token's jti claim, or a hash of the token when it has none
+    token_id VARCHAR(255) NOT NULL UNIQUE,
+    provider VARCHAR(100) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    issued_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user ON user_sessions(user_id, last_seen_at DESC);
+
+-- Denylist of revoked tokens. A row with a token_id revokes that token; a row with only a subject
+-- revokes every token issued to the subject before revoked_at. Rows are kept until expires_at, by
+-- when every token they revoke has expired anyway.
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    subject VARCHAR(255),
+    token_id VARCHAR(255),
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    revoked_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK (subject IS NOT NULL OR token_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_subject ON revoked_tokens(subject) WHERE token_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_token ON revoked_tokens(token_id) WHERE token_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
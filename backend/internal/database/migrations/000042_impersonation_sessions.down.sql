@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_events_impersonation;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS impersonation_session_id;
+DROP TABLE IF EXISTS impersonation_sessions;
@@ -0,0 +1,19 @@
+-- One row per time an admin starts impersonating a user
+CREATE TABLE IF NOT EXISTS impersonation_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    admin_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_admin ON impersonation_sessions(admin_id, started_at DESC);
+
+-- Actions taken while impersonating are tagged with the session they were taken in
+ALTER TABLE audit_events
+    ADD COLUMN IF NOT EXISTS impersonation_session_id BIGINT REFERENCES impersonation_sessions(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_impersonation ON audit_events(impersonation_session_id, created_at DESC)
+    WHERE impersonation_session_id IS NOT NULL;
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_squads_org_name;
+ALTER TABLE squads ADD CONSTRAINT squads_name_key UNIQUE (name);
+
+ALTER TABLE jira_pending_connections DROP COLUMN IF EXISTS org_id;
+ALTER TABLE org_jira_settings DROP COLUMN IF EXISTS org_id;
+ALTER TABLE invitations DROP COLUMN IF EXISTS org_id;
+ALTER TABLE time_off_requests DROP COLUMN IF EXISTS org_id;
+ALTER TABLE meetings DROP COLUMN IF EXISTS org_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS org_id;
+ALTER TABLE squads DROP COLUMN IF EXISTS org_id;
+ALTER TABLE users DROP COLUMN IF EXISTS org_id;
+
+DROP TABLE IF EXISTS organizations;
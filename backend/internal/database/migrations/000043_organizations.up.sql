@@ -0,0 +1,31 @@
+-- Companies sharing a hosted deployment. Everything that existed before is the default organization.
+CREATE TABLE IF NOT EXISTS organizations (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO organizations (id, name) VALUES (1, 'Default') ON CONFLICT (id) DO NOTHING;
+SELECT setval(pg_get_serial_sequence('organizations', 'id'), GREATEST((SELECT MAX(id) FROM organizations), 1));
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE squads ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE time_off_requests ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE org_jira_settings ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE jira_pending_connections ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+
+CREATE INDEX IF NOT EXISTS idx_users_org ON users(org_id);
+CREATE INDEX IF NOT EXISTS idx_squads_org ON squads(org_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_org ON tasks(org_id);
+CREATE INDEX IF NOT EXISTS idx_meetings_org ON meetings(org_id);
+CREATE INDEX IF NOT EXISTS idx_time_off_requests_org ON time_off_requests(org_id);
+CREATE INDEX IF NOT EXISTS idx_invitations_org ON invitations(org_id);
+CREATE INDEX IF NOT EXISTS idx_org_jira_settings_org ON org_jira_settings(org_id);
+CREATE INDEX IF NOT EXISTS idx_jira_pending_connections_org ON jira_pending_connections(org_id);
+
+-- Squad names only need to be unique within an organization
+ALTER TABLE squads DROP CONSTRAINT IF EXISTS squads_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_squads_org_name ON squads(org_id, name);
@@ -0,0 +1,2 @@
+-- IP addresses and CIDR ranges an organization's users may connect from; empty allows any network
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS ip_allowlist TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,17 @@
+-- Organization-wide Slack app connection, installed by an admin through OAuth. The bot DMs
+-- employees about their time off and supervisors about requests waiting for review.
+CREATE TABLE IF NOT EXISTS org_slack_settings (
+    id BIGSERIAL PRIMARY KEY,
+    org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id),
+    bot_access_token TEXT NOT NULL,
+    team_id VARCHAR(32) NOT NULL,
+    team_name VARCHAR(255) NOT NULL,
+    bot_user_id VARCHAR(32) NOT NULL DEFAULT '',
+    configured_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_slack_settings_org ON org_slack_settings(org_id);
+-- Interactive requests from Slack only carry the workspace, which identifies the organization
+CREATE UNIQUE INDEX IF NOT EXISTS idx_org_slack_settings_team ON org_slack_settings(team_id);
@@ -0,0 +1,9 @@
+-- Digest emails queued for supervisors, one per supervisor and period, so that restarts and
+-- multiple instances never send a period's digest twice
+CREATE TABLE IF NOT EXISTS supervisor_digests (
+    supervisor_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    frequency VARCHAR(10) NOT NULL,
+    period_start DATE NOT NULL,
+    queued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (supervisor_id, frequency, period_start)
+);
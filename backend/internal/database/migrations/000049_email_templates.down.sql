@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS email_templates;
+ALTER TABLE invitations DROP COLUMN IF EXISTS language;
+ALTER TABLE users DROP COLUMN IF EXISTS language;
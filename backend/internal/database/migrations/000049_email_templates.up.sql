@@ -0,0 +1,19 @@
+-- Language emails are sent to a user in, and to an invitee before they have an account; new users
+-- keep the language they were invited in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language VARCHAR(10) NOT NULL DEFAULT 'en';
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS language VARCHAR(10) NOT NULL DEFAULT 'en';
+
+-- Organizations' overrides of the built-in email templates, one per email and language
+CREATE TABLE IF NOT EXISTS email_templates (
+    id BIGSERIAL PRIMARY KEY,
+    org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id),
+    template_key VARCHAR(50) NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    subject TEXT NOT NULL,
+    html_body TEXT NOT NULL,
+    text_body TEXT NOT NULL,
+    updated_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (org_id, template_key, language)
+);
@@ -0,0 +1,16 @@
+-- Nightly per-user workload counts, so workload trends can be charted without querying Jira
+-- historically. Jira counts come from the local issue cache as of the snapshot.
+CREATE TABLE IF NOT EXISTS workload_snapshots (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    snapshot_date DATE NOT NULL,
+    org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id),
+    open_tasks INTEGER NOT NULL DEFAULT 0,
+    overdue_tasks INTEGER NOT NULL DEFAULT 0,
+    jira_open INTEGER NOT NULL DEFAULT 0,
+    jira_status_categories JSONB NOT NULL DEFAULT '{}',
+    jira_statuses JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, snapshot_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workload_snapshots_date ON workload_snapshots(snapshot_date);
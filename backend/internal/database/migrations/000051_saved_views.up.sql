@@ -0,0 +1,12 @@
+-- Named filters a user saved for a list, such as "My overdue tasks". Filters are the list's query
+-- parameters, applied server-side when the list is requested with ?view=
+CREATE TABLE IF NOT EXISTS saved_views (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    resource VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, resource, name)
+);
@@ -0,0 +1,10 @@
+-- Organizations' overrides of settings that otherwise come from the server's configuration,
+-- such as invitation expiry and upload size limits
+CREATE TABLE IF NOT EXISTS org_settings (
+    org_id BIGINT NOT NULL REFERENCES organizations(id),
+    setting_key VARCHAR(100) NOT NULL,
+    value TEXT NOT NULL,
+    updated_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (org_id, setting_key)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE invitations DROP COLUMN IF EXISTS date_started;
+ALTER TABLE invitations DROP COLUMN IF EXISTS title;
+ALTER TABLE invitations DROP COLUMN IF EXISTS supervisor_id;
@@ -0,0 +1,4 @@
+-- Invitations can set up the new user's reporting line, title, and start date, applied on acceptance
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS supervisor_id BIGINT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS title VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE invitations ADD COLUMN IF NOT EXISTS date_started TIMESTAMP WITH TIME ZONE;
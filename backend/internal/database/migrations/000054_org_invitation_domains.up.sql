@@ -0,0 +1,2 @@
+-- Email domains an organization's invitations are limited to; empty allows any domain
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS invitation_domains TEXT[] NOT NULL DEFAULT '{}';
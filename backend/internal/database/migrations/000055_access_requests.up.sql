@@ -0,0 +1,21 @@
+-- People who signed in without an account asking to join the organization their email domain
+-- belongs to. An admin approves them, creating their account, or rejects them.
+CREATE TABLE IF NOT EXISTS access_requests (
+    id BIGSERIAL PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    auth0_id VARCHAR(255) NOT NULL,
+    email VARCHAR(254) NOT NULL,
+    first_name VARCHAR(100) NOT NULL DEFAULT '',
+    last_name VARCHAR(100) NOT NULL DEFAULT '',
+    message TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One open request per person
+CREATE UNIQUE INDEX IF NOT EXISTS idx_access_requests_pending ON access_requests(auth0_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_access_requests_org_status ON access_requests(org_id, status, created_at);
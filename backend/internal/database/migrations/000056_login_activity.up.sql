@@ -0,0 +1,26 @@
+-- When each user last signed in (first used a new token) and last made a request
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP WITH TIME ZONE;
+
+-- One row per sign-in, kept after the session itself is cleaned up
+CREATE TABLE IF NOT EXISTS user_logins (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(100) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_logins_user ON user_logins(user_id, created_at DESC);
+
+-- Start from the sessions still on record
+INSERT INTO user_logins (user_id, provider, ip_address, user_agent, created_at)
+SELECT user_id, provider, ip_address, user_agent, created_at FROM user_sessions;
+
+UPDATE users u SET last_login_at = s.last_login_at, last_seen_at = s.last_seen_at
+FROM (
+    SELECT user_id, MAX(created_at) AS last_login_at, MAX(last_seen_at) AS last_seen_at
+    FROM user_sessions GROUP BY user_id
+) s
+WHERE s.user_id = u.id;
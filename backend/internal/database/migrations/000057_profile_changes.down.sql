@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS profile_change_requests;
+
+ALTER TABLE users DROP COLUMN IF EXISTS emergency_contact_relationship;
+ALTER TABLE users DROP COLUMN IF EXISTS emergency_contact_phone;
+ALTER TABLE users DROP COLUMN IF EXISTS emergency_contact_name;
+ALTER TABLE users DROP COLUMN IF EXISTS phone;
+ALTER TABLE users DROP COLUMN IF EXISTS pronouns;
+ALTER TABLE users DROP COLUMN IF EXISTS preferred_name;
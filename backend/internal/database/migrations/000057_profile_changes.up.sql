@@ -0,0 +1,26 @@
+-- Contact details employees keep up to date themselves
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pronouns VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS emergency_contact_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS emergency_contact_phone VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS emergency_contact_relationship VARCHAR(50) NOT NULL DEFAULT '';
+
+-- Title and department changes employees ask for, waiting for their supervisor to approve or
+-- reject them. A NULL field isn't being changed.
+CREATE TABLE IF NOT EXISTS profile_change_requests (
+    id BIGSERIAL PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255),
+    department VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One open request per user; asking again replaces it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_profile_change_requests_pending ON profile_change_requests(user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_profile_change_requests_org_status ON profile_change_requests(org_id, status, created_at);
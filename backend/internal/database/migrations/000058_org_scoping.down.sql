@@ -0,0 +1,21 @@
+DROP INDEX IF EXISTS idx_custom_roles_org_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custom_roles_name ON custom_roles(LOWER(name));
+DROP INDEX IF EXISTS idx_custom_fields_org_key;
+ALTER TABLE custom_fields ADD CONSTRAINT custom_fields_key_key UNIQUE (key);
+DROP INDEX IF EXISTS idx_skills_org_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_skills_name_lower ON skills(LOWER(name));
+DROP INDEX IF EXISTS idx_departments_org_name;
+ALTER TABLE departments ADD CONSTRAINT departments_name_key UNIQUE (name);
+
+DROP INDEX IF EXISTS idx_audit_events_org;
+DROP INDEX IF EXISTS idx_goals_org;
+DROP INDEX IF EXISTS idx_webhook_endpoints_org;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS org_id;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS org_id;
+ALTER TABLE custom_roles DROP COLUMN IF EXISTS org_id;
+ALTER TABLE custom_fields DROP COLUMN IF EXISTS org_id;
+ALTER TABLE skills DROP COLUMN IF EXISTS org_id;
+ALTER TABLE goals DROP COLUMN IF EXISTS org_id;
+ALTER TABLE webhook_endpoints DROP COLUMN IF EXISTS org_id;
+ALTER TABLE departments DROP COLUMN IF EXISTS org_id;
@@ -0,0 +1,32 @@
+-- Scope the remaining organization data to its organization
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE webhook_endpoints ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE goals ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE skills ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE custom_fields ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE custom_roles ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+
+-- Rows that belong to someone follow that person's organization
+UPDATE notifications n SET org_id = u.org_id FROM users u WHERE u.id = n.user_id;
+UPDATE audit_events a SET org_id = u.org_id FROM users u WHERE u.id = a.actor_id;
+UPDATE goals g SET org_id = COALESCE(
+    (SELECT org_id FROM users WHERE id = g.owner_user_id),
+    (SELECT org_id FROM squads WHERE id = g.owner_squad_id),
+    (SELECT org_id FROM users WHERE id = g.created_by_id),
+    g.org_id);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_org ON webhook_endpoints(org_id);
+CREATE INDEX IF NOT EXISTS idx_goals_org ON goals(org_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_org ON audit_events(org_id, created_at);
+
+-- Names and keys only need to be unique within an organization
+ALTER TABLE departments DROP CONSTRAINT IF EXISTS departments_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_departments_org_name ON departments(org_id, name);
+DROP INDEX IF EXISTS idx_skills_name_lower;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_skills_org_name ON skills(org_id, LOWER(name));
+ALTER TABLE custom_fields DROP CONSTRAINT IF EXISTS custom_fields_key_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custom_fields_org_key ON custom_fields(org_id, key);
+DROP INDEX IF EXISTS idx_custom_roles_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custom_roles_org_name ON custom_roles(org_id, LOWER(name));
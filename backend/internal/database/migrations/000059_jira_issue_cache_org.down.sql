@@ -0,0 +1,12 @@
+-- Only the default organization's issues fit the unscoped cache
+DELETE FROM jira_issue_sync WHERE org_id <> 1;
+ALTER TABLE jira_issue_sync DROP CONSTRAINT IF EXISTS jira_issue_sync_pkey;
+ALTER TABLE jira_issue_sync ADD PRIMARY KEY (account_id);
+ALTER TABLE jira_issue_sync DROP COLUMN IF EXISTS org_id;
+
+DELETE FROM jira_issues_cache WHERE org_id <> 1;
+DROP INDEX IF EXISTS idx_jira_issues_cache_assignee;
+CREATE INDEX IF NOT EXISTS idx_jira_issues_cache_assignee ON jira_issues_cache(assignee_account_id) WHERE NOT resolved;
+ALTER TABLE jira_issues_cache DROP CONSTRAINT IF EXISTS jira_issues_cache_pkey;
+ALTER TABLE jira_issues_cache ADD PRIMARY KEY (issue_key);
+ALTER TABLE jira_issues_cache DROP COLUMN IF EXISTS org_id;
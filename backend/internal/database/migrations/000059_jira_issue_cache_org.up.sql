@@ -0,0 +1,11 @@
+-- Each organization caches the issues of its own Jira site, whose keys and account IDs can
+-- collide with another site's
+ALTER TABLE jira_issues_cache ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE jira_issues_cache DROP CONSTRAINT IF EXISTS jira_issues_cache_pkey;
+ALTER TABLE jira_issues_cache ADD PRIMARY KEY (org_id, issue_key);
+DROP INDEX IF EXISTS idx_jira_issues_cache_assignee;
+CREATE INDEX IF NOT EXISTS idx_jira_issues_cache_assignee ON jira_issues_cache(org_id, assignee_account_id) WHERE NOT resolved;
+
+ALTER TABLE jira_issue_sync ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE jira_issue_sync DROP CONSTRAINT IF EXISTS jira_issue_sync_pkey;
+ALTER TABLE jira_issue_sync ADD PRIMARY KEY (org_id, account_id);
@@ -0,0 +1,2 @@
+-- Permanent URLs of uploaded files are not restored; they are signed on read
+SELECT 1;
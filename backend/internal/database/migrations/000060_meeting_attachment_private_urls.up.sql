@@ -0,0 +1,2 @@
+-- Uploaded files are served through short-lived signed URLs; drop the permanent ones stored at upload
+UPDATE meeting_attachments SET url = '' WHERE storage_key IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_org_chart_snapshots_org;
+DROP INDEX IF EXISTS idx_org_chart_drafts_org;
+
+ALTER TABLE org_chart_snapshots DROP COLUMN IF EXISTS org_id;
+ALTER TABLE org_chart_drafts DROP COLUMN IF EXISTS org_id;
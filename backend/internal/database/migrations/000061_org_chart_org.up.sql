@@ -0,0 +1,12 @@
+-- Org chart drafts and the snapshots published from them belong to an organization
+ALTER TABLE org_chart_drafts ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE org_chart_snapshots ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+
+UPDATE org_chart_drafts d SET org_id = u.org_id FROM users u WHERE u.id = d.created_by_id;
+UPDATE org_chart_snapshots s SET org_id = COALESCE(
+    (SELECT org_id FROM org_chart_drafts WHERE id = s.draft_id),
+    (SELECT org_id FROM users WHERE id = s.published_by_id),
+    s.org_id);
+
+CREATE INDEX IF NOT EXISTS idx_org_chart_drafts_org ON org_chart_drafts(org_id);
+CREATE INDEX IF NOT EXISTS idx_org_chart_snapshots_org ON org_chart_snapshots(org_id, created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_org_linear_settings_org;
+DROP INDEX IF EXISTS idx_org_github_settings_org;
+
+ALTER TABLE org_linear_settings DROP COLUMN IF EXISTS org_id;
+ALTER TABLE org_github_settings DROP COLUMN IF EXISTS org_id;
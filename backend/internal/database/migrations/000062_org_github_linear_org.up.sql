@@ -0,0 +1,15 @@
+-- Each organization connects its own GitHub account and Linear workspace
+ALTER TABLE org_github_settings ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+ALTER TABLE org_linear_settings ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+
+UPDATE org_github_settings s SET org_id = u.org_id FROM users u WHERE u.id = s.configured_by_id;
+UPDATE org_linear_settings s SET org_id = u.org_id FROM users u WHERE u.id = s.configured_by_id;
+
+-- Keep only the latest connection of each organization
+DELETE FROM org_github_settings s
+WHERE EXISTS (SELECT 1 FROM org_github_settings n WHERE n.org_id = s.org_id AND n.id > s.id);
+DELETE FROM org_linear_settings s
+WHERE EXISTS (SELECT 1 FROM org_linear_settings n WHERE n.org_id = s.org_id AND n.id > s.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_org_github_settings_org ON org_github_settings(org_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_org_linear_settings_org ON org_linear_settings(org_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_onboarding_templates_org;
+
+ALTER TABLE onboarding_templates DROP COLUMN IF EXISTS org_id;
@@ -0,0 +1,5 @@
+-- Onboarding templates belong to an organization and only onboard its users. Templates don't
+-- record who created them, so existing ones stay with the default organization.
+ALTER TABLE onboarding_templates ADD COLUMN IF NOT EXISTS org_id BIGINT NOT NULL DEFAULT 1 REFERENCES organizations(id);
+
+CREATE INDEX IF NOT EXISTS idx_onboarding_templates_org ON onboarding_templates(org_id);
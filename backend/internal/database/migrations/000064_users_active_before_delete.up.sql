@@ -0,0 +1,4 @@
+-- Deleting a user deactivates them, so remember whether they were active to restore them as they
+-- were. Users deleted before this column existed are restored inactive, since a deactivated user
+-- must not come back active.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS active_before_delete BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const notificationColumns = `id, user_id, type, actor_id, entity_type, entity_id, title, body, read_at, created_at`
+
+type NotificationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotificationRepository(pool *pgxpool.Pool) *NotificationRepository {
+	return &NotificationRepository{pool: pool}
+}
+
+// Create stores an in-app notification for its recipient, in the recipient's organization
+func (r *NotificationRepository) Create(ctx context.Context, n *models.Notification) (*models.Notification, error) {
+	ctx = withQueryName(ctx, "NotificationRepository.Create")
+	query := `
+		INSERT INTO notifications (user_id, type, actor_id, entity_type, entity_id, title, body, org_id)
+		SELECT $1, $2, $3, $4, $5, $6, $7, org_id FROM users WHERE id = $1
+		RETURNING ` + notificationColumns
+
+	var created models.Notification
+	err := r.pool.QueryRow(ctx, query,
+		n.UserID, n.Type, n.ActorID, n.EntityType, n.EntityID, n.Title, n.Body,
+	).Scan(
+		&created.ID, &created.UserID, &created.Type, &created.ActorID, &created.EntityType, &created.EntityID,
+		&created.Title, &created.Body, &created.ReadAt, &created.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notification: %w", err)
+	}
+	return &created, nil
+}
+
+// List returns a user's notifications, newest first, with the total matching count
+func (r *NotificationRepository) List(ctx context.Context, userID int64, unreadOnly bool, limit, offset int) ([]models.Notification, int, error) {
+	ctx = withQueryName(ctx, "NotificationRepository.List")
+	where := `user_id = $1 AND ` + orgCondition("org_id", "$2")
+	if unreadOnly {
+		where += ` AND read_at IS NULL`
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE `+where, userID, orgScope(ctx)).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE ` + where + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.pool.Query(ctx, query, userID, orgScope(ctx), limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		err := rows.Scan(
+			&n.ID, &n.UserID, &n.Type, &n.ActorID, &n.EntityType, &n.EntityID,
+			&n.Title, &n.Body, &n.ReadAt, &n.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, total, rows.Err()
+}
+
+// CountUnread counts a user's unread notifications
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	ctx = withQueryName(ctx, "NotificationRepository.CountUnread")
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL AND `+orgCondition("org_id", "$2"), userID, orgScope(ctx)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks one of a user's notifications as read. Marking a read notification again keeps
+// the original read time.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id int64) error {
+	ctx = withQueryName(ctx, "NotificationRepository.MarkRead")
+	result, err := r.pool.Exec(ctx, `
+		UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2 AND `+orgCondition("org_id", "$3")+`
+	`, id, userID, orgScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
+
+// MarkAllRead marks all of a user's unread notifications as read, returning how many changed
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	ctx = withQueryName(ctx, "NotificationRepository.MarkAllRead")
+	result, err := r.pool.Exec(ctx, `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL AND `+orgCondition("org_id", "$2"), userID, orgScope(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
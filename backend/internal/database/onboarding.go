@@ -0,0 +1,271 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+const onboardingTemplateColumns = `id, name, department, role, created_at, updated_at`
+
+const onboardingItemColumns = `id, template_id, position, title, description, assignee, due_days`
+
+// OnboardingRepository handles onboarding templates and the tasks created from them. Templates
+// belong to an organization and are limited to the one ctx is scoped to.
+type OnboardingRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOnboardingRepository creates a new onboarding repository
+func NewOnboardingRepository(pool *pgxpool.Pool) *OnboardingRepository {
+	return &OnboardingRepository{pool: pool}
+}
+
+// scanOnboardingTemplate scans a row of onboardingTemplateColumns into a template without its items
+func scanOnboardingTemplate(row pgx.Row) (*models.OnboardingTemplate, error) {
+	var t models.OnboardingTemplate
+	if err := row.Scan(&t.ID, &t.Name, &t.Department, &t.Role, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	t.Items = []models.OnboardingTemplateItem{}
+	return &t, nil
+}
+
+// ListTemplates retrieves the organization's onboarding templates with their items, ordered by name
+func (r *OnboardingRepository) ListTemplates(ctx context.Context) ([]models.OnboardingTemplate, error) {
+	ctx = withQueryName(ctx, "OnboardingRepository.ListTemplates")
+	query := `SELECT ` + onboardingTemplateColumns + ` FROM onboarding_templates
+		WHERE ` + orgCondition("org_id", "$1") + ` ORDER BY LOWER(name), id`
+	rows, err := r.pool.Query(ctx, query, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list onboarding templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []models.OnboardingTemplate{}
+	for rows.Next() {
+		t, err := scanOnboardingTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan onboarding template: %w", err)
+		}
+		templates = append(templates, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list onboarding templates: %w", err)
+	}
+
+	if err := r.loadItems(ctx, templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// loadItems batch loads the templates' items in order
+func (r *OnboardingRepository) loadItems(ctx context.Context, templates []models.OnboardingTemplate) error {
+	if len(templates) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(templates))
+	byID := make(map[int64]*models.OnboardingTemplate, len(templates))
+	for i := range templates {
+		ids[i] = templates[i].ID
+		byID[templates[i].ID] = &templates[i]
+	}
+
+	query := `SELECT ` + onboardingItemColumns + ` FROM onboarding_template_items
+		WHERE template_id = ANY($1) ORDER BY template_id, position, id`
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get onboarding template items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.OnboardingTemplateItem
+		err := rows.Scan(&item.ID, &item.TemplateID, &item.Position, &item.Title, &item.Description, &item.Assignee, &item.DueDays)
+		if err != nil {
+			return fmt.Errorf("failed to scan onboarding template item: %w", err)
+		}
+		if t, ok := byID[item.TemplateID]; ok {
+			t.Items = append(t.Items, item)
+		}
+	}
+	return rows.Err()
+}
+
+// GetTemplate retrieves an onboarding template with its items, or nil if it doesn't exist
+func (r *OnboardingRepository) GetTemplate(ctx context.Context, id int64) (*models.OnboardingTemplate, error) {
+	ctx = withQueryName(ctx, "OnboardingRepository.GetTemplate")
+	query := `SELECT ` + onboardingTemplateColumns + ` FROM onboarding_templates WHERE id = $1 AND ` + orgCondition("org_id", "$2")
+	t, err := scanOnboardingTemplate(r.pool.QueryRow(ctx, query, id, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get onboarding template: %w", err)
+	}
+
+	templates := []models.OnboardingTemplate{*t}
+	if err := r.loadItems(ctx, templates); err != nil {
+		return nil, err
+	}
+	return &templates[0], nil
+}
+
+// CreateTemplate creates an onboarding template and its items
+func (r *OnboardingRepository) CreateTemplate(ctx context.Context, req *models.OnboardingTemplateRequest) (*models.OnboardingTemplate, error) {
+	ctx = withQueryName(ctx, "OnboardingRepository.CreateTemplate")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var id int64
+	err = tx.QueryRow(ctx, `INSERT INTO onboarding_templates (name, department, role, org_id) VALUES ($1, $2, $3, $4) RETURNING id`,
+		req.Name, req.Department, req.Role, orgID).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create onboarding template: %w", err)
+	}
+	if err := insertOnboardingItems(ctx, tx, id, req.Items); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return r.GetTemplate(ctx, id)
+}
+
+// UpdateTemplate replaces an onboarding template and its items, returning nil if it doesn't exist.
+// Tasks already created from the template are left alone.
+func (r *OnboardingRepository) UpdateTemplate(ctx context.Context, id int64, req *models.OnboardingTemplateRequest) (*models.OnboardingTemplate, error) {
+	ctx = withQueryName(ctx, "OnboardingRepository.UpdateTemplate")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `UPDATE onboarding_templates SET name = $1, department = $2, role = $3, updated_at = $4
+		WHERE id = $5 AND ` + orgCondition("org_id", "$6")
+	tag, err := tx.Exec(ctx, query, req.Name, req.Department, req.Role, time.Now(), id, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update onboarding template: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, nil
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM onboarding_template_items WHERE template_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to clear onboarding template items: %w", err)
+	}
+	if err := insertOnboardingItems(ctx, tx, id, req.Items); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return r.GetTemplate(ctx, id)
+}
+
+// insertOnboardingItems inserts a template's items in order
+func insertOnboardingItems(ctx context.Context, tx pgx.Tx, templateID int64, items []models.OnboardingTemplateItemInput) error {
+	for i, item := range items {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO onboarding_template_items (template_id, position, title, description, assignee, due_days)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			templateID, i, item.Title, item.Description, item.Assignee, item.DueDays)
+		if err != nil {
+			return fmt.Errorf("failed to create onboarding template item %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// DeleteTemplate deletes an onboarding template. Tasks already created from it are left alone.
+func (r *OnboardingRepository) DeleteTemplate(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "OnboardingRepository.DeleteTemplate")
+	tag, err := r.pool.Exec(ctx, `DELETE FROM onboarding_templates WHERE id = $1 AND `+orgCondition("org_id", "$2"), id, orgScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete onboarding template: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("onboarding template not found")
+	}
+	return nil
+}
+
+// CreateTasks creates a user's onboarding tasks in one transaction. Users who already have
+// onboarding tasks are skipped, so a user is only onboarded once; nil is returned for them.
+func (r *OnboardingRepository) CreateTasks(ctx context.Context, userID, createdByID int64, reqs []models.CreateTaskRequest) ([]models.Task, error) {
+	ctx = withQueryName(ctx, "OnboardingRepository.CreateTasks")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Lock the user so concurrent signups can't both onboard them
+	var onboarded bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM onboarding_tasks WHERE user_id = u.id)
+		FROM users u WHERE u.id = $1 FOR UPDATE`, userID).Scan(&onboarded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check onboarding: %w", err)
+	}
+	if onboarded {
+		return nil, nil
+	}
+
+	tasks := make([]models.Task, 0, len(reqs))
+	for i := range reqs {
+		task, err := scanTask(tx.QueryRow(ctx, insertTaskQuery, taskInsertArgs(&reqs[i], createdByID)...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create onboarding task %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO onboarding_tasks (task_id, user_id) VALUES ($1, $2)`, task.ID, userID); err != nil {
+			return nil, fmt.Errorf("failed to link onboarding task %d: %w", i+1, err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return tasks, nil
+}
+
+// GetTasks retrieves the tasks created for a user's onboarding, ordered by due date
+func (r *OnboardingRepository) GetTasks(ctx context.Context, userID int64) ([]models.Task, error) {
+	ctx = withQueryName(ctx, "OnboardingRepository.GetTasks")
+	query := `SELECT ` + taskColumns + ` FROM tasks
+		WHERE id IN (SELECT task_id FROM onboarding_tasks WHERE user_id = $1)
+		AND deleted_at IS NULL
+		ORDER BY due_date, id`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get onboarding tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan onboarding tasks: %w", err)
+	}
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+	return tasks, rows.Err()
+}
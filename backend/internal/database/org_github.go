@@ -0,0 +1,151 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// OrgGitHubRepository handles organizations' GitHub connections. Each organization has its own,
+// and the repository acts on that of the organization ctx is scoped to, failing with
+// tenant.ErrNoOrg when organizations are required and ctx isn't scoped to one.
+type OrgGitHubRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOrgGitHubRepository creates a new OrgGitHubRepository
+func NewOrgGitHubRepository(pool *pgxpool.Pool) *OrgGitHubRepository {
+	return &OrgGitHubRepository{pool: pool}
+}
+
+// Get returns the organization's GitHub settings (there's only one per organization)
+func (r *OrgGitHubRepository) Get(ctx context.Context) (*models.OrgGitHubSettings, error) {
+	ctx = withQueryName(ctx, "OrgGitHubRepository.Get")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT id, org_id, oauth_access_token, oauth_refresh_token, oauth_token_expires_at,
+			account_login, org_login, configured_by_id, created_at, updated_at
+		FROM org_github_settings
+		WHERE org_id = $1
+	`
+
+	var settings models.OrgGitHubSettings
+	err = r.pool.QueryRow(ctx, query, orgID).Scan(
+		&settings.ID,
+		&settings.OrgID,
+		&settings.OAuthAccessToken,
+		&settings.OAuthRefreshToken,
+		&settings.OAuthTokenExpiresAt,
+		&settings.AccountLogin,
+		&settings.OrgLogin,
+		&settings.ConfiguredByID,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil // Not connected
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get org github settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// Save replaces the organization's GitHub settings.
+// The organization filter carries over when OrgLogin is nil so reconnecting keeps it.
+func (r *OrgGitHubRepository) Save(ctx context.Context, settings *models.OrgGitHubSettings) error {
+	ctx = withQueryName(ctx, "OrgGitHubRepository.Save")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	settings.OrgID = orgID
+	query := `
+		INSERT INTO org_github_settings (
+			org_id, oauth_access_token, oauth_refresh_token, oauth_token_expires_at,
+			account_login, org_login, configured_by_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		ON CONFLICT (org_id) DO UPDATE SET
+			oauth_access_token = EXCLUDED.oauth_access_token,
+			oauth_refresh_token = EXCLUDED.oauth_refresh_token,
+			oauth_token_expires_at = EXCLUDED.oauth_token_expires_at,
+			account_login = EXCLUDED.account_login,
+			org_login = COALESCE(EXCLUDED.org_login, org_github_settings.org_login),
+			configured_by_id = EXCLUDED.configured_by_id,
+			created_at = NOW(),
+			updated_at = NOW()
+		RETURNING id, org_login, created_at, updated_at
+	`
+
+	err = r.pool.QueryRow(ctx, query,
+		settings.OrgID,
+		settings.OAuthAccessToken,
+		settings.OAuthRefreshToken,
+		settings.OAuthTokenExpiresAt,
+		settings.AccountLogin,
+		settings.OrgLogin,
+		settings.ConfiguredByID,
+	).Scan(&settings.ID, &settings.OrgLogin, &settings.CreatedAt, &settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save org github settings: %w", err)
+	}
+	return nil
+}
+
+// UpdateTokens stores refreshed OAuth tokens. GitHub rotates refresh tokens, so the new one must be kept.
+func (r *OrgGitHubRepository) UpdateTokens(ctx context.Context, accessToken, refreshToken string, expiresAt *time.Time) error {
+	ctx = withQueryName(ctx, "OrgGitHubRepository.UpdateTokens")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE org_github_settings
+		SET oauth_access_token = $1, oauth_refresh_token = $2, oauth_token_expires_at = $3, updated_at = NOW()
+		WHERE org_id = $4
+	`
+
+	if _, err := r.pool.Exec(ctx, query, accessToken, refreshToken, expiresAt, orgID); err != nil {
+		return fmt.Errorf("failed to update github tokens: %w", err)
+	}
+	return nil
+}
+
+// UpdateOrgLogin sets the organization pull requests are searched in, or clears it when nil.
+// It returns false if GitHub isn't connected.
+func (r *OrgGitHubRepository) UpdateOrgLogin(ctx context.Context, orgLogin *string) (bool, error) {
+	ctx = withQueryName(ctx, "OrgGitHubRepository.UpdateOrgLogin")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return false, err
+	}
+	tag, err := r.pool.Exec(ctx, "UPDATE org_github_settings SET org_login = $1, updated_at = NOW() WHERE org_id = $2", orgLogin, orgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update github org: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Delete removes the organization's GitHub settings
+func (r *OrgGitHubRepository) Delete(ctx context.Context) error {
+	ctx = withQueryName(ctx, "OrgGitHubRepository.Delete")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := r.pool.Exec(ctx, "DELETE FROM org_github_settings WHERE org_id = $1", orgID); err != nil {
+		return fmt.Errorf("failed to delete org github settings: %w", err)
+	}
+	return nil
+}
@@ -2,17 +2,23 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/crypto"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
 )
 
-// OrgJiraRepository handles organization-wide Jira settings
+// OrgJiraRepository handles organization-wide Jira settings. Each organization has its own, and
+// the repository acts on those of the organization ctx is scoped to, failing with tenant.ErrNoOrg
+// when organizations are required and ctx isn't scoped to one.
 type OrgJiraRepository struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	fields *crypto.Fields
 }
 
 // NewOrgJiraRepository creates a new OrgJiraRepository
@@ -20,21 +26,36 @@ func NewOrgJiraRepository(pool *pgxpool.Pool) *OrgJiraRepository {
 	return &OrgJiraRepository{pool: pool}
 }
 
-// Get returns the organization Jira settings (there's only one)
+// WithEncryption encrypts OAuth tokens at rest with fields
+func (r *OrgJiraRepository) WithEncryption(fields *crypto.Fields) *OrgJiraRepository {
+	r.fields = fields
+	return r
+}
+
+// Get returns the organization Jira settings (there's only one per organization)
 func (r *OrgJiraRepository) Get(ctx context.Context) (*models.OrgJiraSettings, error) {
+	ctx = withQueryName(ctx, "OrgJiraRepository.Get")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := `
 		SELECT id, oauth_access_token, oauth_refresh_token, oauth_token_expires_at,
-			cloud_id, site_url, site_name, configured_by_id, created_at, updated_at
+			cloud_id, site_url, site_name, configured_by_id, created_at, updated_at,
+			last_refreshed_at, refresh_failures, last_refresh_error, last_refresh_error_at,
+			last_api_error, last_api_error_at, last_webhook_at, task_filters
 		FROM org_jira_settings
+		WHERE org_id = $1
 		ORDER BY id DESC
 		LIMIT 1
 	`
 
 	var settings models.OrgJiraSettings
-	err := r.pool.QueryRow(ctx, query).Scan(
+	var taskFilters []byte
+	err = r.pool.QueryRow(ctx, query, orgID).Scan(
 		&settings.ID,
-		&settings.OAuthAccessToken,
-		&settings.OAuthRefreshToken,
+		r.fields.Scan(&settings.OAuthAccessToken),
+		r.fields.Scan(&settings.OAuthRefreshToken),
 		&settings.OAuthTokenExpiresAt,
 		&settings.CloudID,
 		&settings.SiteURL,
@@ -42,6 +63,14 @@ func (r *OrgJiraRepository) Get(ctx context.Context) (*models.OrgJiraSettings, e
 		&settings.ConfiguredByID,
 		&settings.CreatedAt,
 		&settings.UpdatedAt,
+		&settings.LastRefreshedAt,
+		&settings.RefreshFailures,
+		&settings.LastRefreshError,
+		&settings.LastRefreshErrorAt,
+		&settings.LastAPIError,
+		&settings.LastAPIErrorAt,
+		&settings.LastWebhookAt,
+		&taskFilters,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -51,63 +80,395 @@ func (r *OrgJiraRepository) Get(ctx context.Context) (*models.OrgJiraSettings, e
 		return nil, fmt.Errorf("failed to get org jira settings: %w", err)
 	}
 
+	if err := json.Unmarshal(taskFilters, &settings.TaskFilters); err != nil {
+		return nil, fmt.Errorf("failed to decode jira task filters: %w", err)
+	}
 	return &settings, nil
 }
 
-// Save creates or updates the organization Jira settings
+// Save creates or updates the organization Jira settings.
+// Task filters carry over from the previous settings so reconnecting Jira keeps them.
 func (r *OrgJiraRepository) Save(ctx context.Context, settings *models.OrgJiraSettings) error {
-	// Delete any existing settings (we only want one)
-	_, err := r.pool.Exec(ctx, "DELETE FROM org_jira_settings")
+	ctx = withQueryName(ctx, "OrgJiraRepository.Save")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var taskFilters []byte
+	err = tx.QueryRow(ctx, "SELECT task_filters FROM org_jira_settings WHERE org_id = $1 ORDER BY id DESC LIMIT 1", orgID).Scan(&taskFilters)
+	if err == pgx.ErrNoRows {
+		taskFilters = []byte("[]")
+	} else if err != nil {
+		return fmt.Errorf("failed to get jira task filters: %w", err)
+	}
+
+	// Delete any existing settings (we only want one)
+	if _, err := tx.Exec(ctx, "DELETE FROM org_jira_settings WHERE org_id = $1", orgID); err != nil {
 		return fmt.Errorf("failed to clear old settings: %w", err)
 	}
 
 	query := `
 		INSERT INTO org_jira_settings (
 			oauth_access_token, oauth_refresh_token, oauth_token_expires_at,
-			cloud_id, site_url, site_name, configured_by_id, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+			cloud_id, site_url, site_name, configured_by_id, task_filters, org_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
 		RETURNING id, created_at, updated_at
 	`
 
-	err = r.pool.QueryRow(ctx, query,
-		settings.OAuthAccessToken,
-		settings.OAuthRefreshToken,
+	err = tx.QueryRow(ctx, query,
+		r.fields.Value(settings.OAuthAccessToken),
+		r.fields.Value(settings.OAuthRefreshToken),
 		settings.OAuthTokenExpiresAt,
 		settings.CloudID,
 		settings.SiteURL,
 		settings.SiteName,
 		settings.ConfiguredByID,
+		taskFilters,
+		orgID,
 	).Scan(&settings.ID, &settings.CreatedAt, &settings.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to save org jira settings: %w", err)
 	}
 
-	return nil
+	if err := json.Unmarshal(taskFilters, &settings.TaskFilters); err != nil {
+		return fmt.Errorf("failed to decode jira task filters: %w", err)
+	}
+
+	return tx.Commit(ctx)
 }
 
-// UpdateTokens updates the OAuth tokens (after refresh)
+// UpdateTokens updates the OAuth tokens (after refresh) and clears the refresh failure count
 // Must save the new refresh token since Atlassian uses refresh token rotation
 func (r *OrgJiraRepository) UpdateTokens(ctx context.Context, accessToken, refreshToken string, expiresAt time.Time) error {
+	ctx = withQueryName(ctx, "OrgJiraRepository.UpdateTokens")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
 	query := `
 		UPDATE org_jira_settings
-		SET oauth_access_token = $1, oauth_refresh_token = $2, oauth_token_expires_at = $3, updated_at = NOW()
+		SET oauth_access_token = $1, oauth_refresh_token = $2, oauth_token_expires_at = $3,
+			last_refreshed_at = NOW(), refresh_failures = 0, updated_at = NOW()
+		WHERE org_id = $4
 	`
 
-	_, err := r.pool.Exec(ctx, query, accessToken, refreshToken, expiresAt)
-	if err != nil {
+	if _, err := r.pool.Exec(ctx, query, r.fields.Value(accessToken), r.fields.Value(refreshToken), expiresAt, orgID); err != nil {
 		return fmt.Errorf("failed to update tokens: %w", err)
 	}
 
 	return nil
 }
 
+// RecordRefreshFailure stores a failed token refresh and returns the number of consecutive failures
+func (r *OrgJiraRepository) RecordRefreshFailure(ctx context.Context, message string) (int, error) {
+	ctx = withQueryName(ctx, "OrgJiraRepository.RecordRefreshFailure")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	query := `
+		UPDATE org_jira_settings
+		SET refresh_failures = refresh_failures + 1, last_refresh_error = $1, last_refresh_error_at = NOW()
+		WHERE org_id = $2
+		RETURNING refresh_failures
+	`
+
+	var failures int
+	err = r.pool.QueryRow(ctx, query, message, orgID).Scan(&failures)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to record jira refresh failure: %w", err)
+	}
+
+	return failures, nil
+}
+
+// RecordAPIError stores the most recent failed Jira API call
+func (r *OrgJiraRepository) RecordAPIError(ctx context.Context, message string) error {
+	ctx = withQueryName(ctx, "OrgJiraRepository.RecordAPIError")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE org_jira_settings SET last_api_error = $1, last_api_error_at = NOW() WHERE org_id = $2`
+
+	if _, err := r.pool.Exec(ctx, query, message, orgID); err != nil {
+		return fmt.Errorf("failed to record jira api error: %w", err)
+	}
+	return nil
+}
+
+// RecordWebhookDelivery notes that a webhook from Jira was just received
+func (r *OrgJiraRepository) RecordWebhookDelivery(ctx context.Context) error {
+	ctx = withQueryName(ctx, "OrgJiraRepository.RecordWebhookDelivery")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := r.pool.Exec(ctx, "UPDATE org_jira_settings SET last_webhook_at = NOW() WHERE org_id = $1", orgID); err != nil {
+		return fmt.Errorf("failed to record jira webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// UpdateTaskFilters replaces the named JQL filters for the team task view.
+// It returns false if Jira isn't connected.
+func (r *OrgJiraRepository) UpdateTaskFilters(ctx context.Context, filters []models.JiraTaskFilter) (bool, error) {
+	ctx = withQueryName(ctx, "OrgJiraRepository.UpdateTaskFilters")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return false, err
+	}
+	if filters == nil {
+		filters = []models.JiraTaskFilter{}
+	}
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode jira task filters: %w", err)
+	}
+
+	tag, err := r.pool.Exec(ctx, "UPDATE org_jira_settings SET task_filters = $1, updated_at = NOW() WHERE org_id = $2", data, orgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update jira task filters: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SaveUserMatchReport stores the latest Jira user auto-match report.
+// It returns false if Jira isn't connected.
+func (r *OrgJiraRepository) SaveUserMatchReport(ctx context.Context, report *models.JiraUserMatchReport) (bool, error) {
+	ctx = withQueryName(ctx, "OrgJiraRepository.SaveUserMatchReport")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return false, err
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode jira user match report: %w", err)
+	}
+
+	tag, err := r.pool.Exec(ctx, "UPDATE org_jira_settings SET user_match_report = $1 WHERE org_id = $2", data, orgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to save jira user match report: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetUserMatchReport returns the latest Jira user auto-match report, or nil if auto-match
+// hasn't run since Jira was connected
+func (r *OrgJiraRepository) GetUserMatchReport(ctx context.Context) (*models.JiraUserMatchReport, error) {
+	ctx = withQueryName(ctx, "OrgJiraRepository.GetUserMatchReport")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	err = r.pool.QueryRow(ctx, "SELECT user_match_report FROM org_jira_settings WHERE org_id = $1 ORDER BY id DESC LIMIT 1",
+		orgID).Scan(&data)
+	if err == pgx.ErrNoRows || (err == nil && data == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jira user match report: %w", err)
+	}
+
+	var report models.JiraUserMatchReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode jira user match report: %w", err)
+	}
+	return &report, nil
+}
+
 // Delete removes the organization Jira settings
 func (r *OrgJiraRepository) Delete(ctx context.Context) error {
-	_, err := r.pool.Exec(ctx, "DELETE FROM org_jira_settings")
+	ctx = withQueryName(ctx, "OrgJiraRepository.Delete")
+	orgID, err := tenant.ScopedOrgID(ctx)
 	if err != nil {
+		return err
+	}
+	if _, err := r.pool.Exec(ctx, "DELETE FROM org_jira_settings WHERE org_id = $1", orgID); err != nil {
 		return fmt.Errorf("failed to delete org jira settings: %w", err)
 	}
 	return nil
 }
+
+// SavePendingConnection stores tokens and sites awaiting site selection.
+// Any previous pending connection is replaced since only one OAuth flow can be in progress per organization.
+func (r *OrgJiraRepository) SavePendingConnection(ctx context.Context, pending *models.JiraPendingConnection) error {
+	ctx = withQueryName(ctx, "OrgJiraRepository.SavePendingConnection")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	sites, err := json.Marshal(pending.Sites)
+	if err != nil {
+		return fmt.Errorf("failed to encode jira sites: %w", err)
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, "DELETE FROM jira_pending_connections WHERE org_id = $1", orgID); err != nil {
+		return fmt.Errorf("failed to clear pending jira connections: %w", err)
+	}
+
+	query := `
+		INSERT INTO jira_pending_connections (
+			oauth_access_token, oauth_refresh_token, oauth_token_expires_at,
+			resources, configured_by_id, expires_at, org_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(ctx, query,
+		r.fields.Value(pending.OAuthAccessToken),
+		r.fields.Value(pending.OAuthRefreshToken),
+		pending.OAuthTokenExpiresAt,
+		sites,
+		pending.ConfiguredByID,
+		pending.ExpiresAt,
+		orgID,
+	).Scan(&pending.ID, &pending.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save pending jira connection: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetPendingConnection returns the unexpired pending connection, or nil if there is none
+func (r *OrgJiraRepository) GetPendingConnection(ctx context.Context) (*models.JiraPendingConnection, error) {
+	ctx = withQueryName(ctx, "OrgJiraRepository.GetPendingConnection")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT id, oauth_access_token, oauth_refresh_token, oauth_token_expires_at,
+			resources, configured_by_id, expires_at, created_at
+		FROM jira_pending_connections
+		WHERE expires_at > NOW() AND org_id = $1
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	var pending models.JiraPendingConnection
+	var sites []byte
+	err = r.pool.QueryRow(ctx, query, orgID).Scan(
+		&pending.ID,
+		r.fields.Scan(&pending.OAuthAccessToken),
+		r.fields.Scan(&pending.OAuthRefreshToken),
+		&pending.OAuthTokenExpiresAt,
+		&sites,
+		&pending.ConfiguredByID,
+		&pending.ExpiresAt,
+		&pending.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending jira connection: %w", err)
+	}
+
+	if err := json.Unmarshal(sites, &pending.Sites); err != nil {
+		return nil, fmt.Errorf("failed to decode jira sites: %w", err)
+	}
+	return &pending, nil
+}
+
+// DeletePendingConnection discards any pending connection
+func (r *OrgJiraRepository) DeletePendingConnection(ctx context.Context) error {
+	ctx = withQueryName(ctx, "OrgJiraRepository.DeletePendingConnection")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := r.pool.Exec(ctx, "DELETE FROM jira_pending_connections WHERE org_id = $1", orgID); err != nil {
+		return fmt.Errorf("failed to delete pending jira connection: %w", err)
+	}
+	return nil
+}
+
+// EncryptStoredTokens encrypts org and pending connection tokens stored in plaintext or with a
+// previous key, and returns how many rows were updated. It does nothing without encryption.
+func (r *OrgJiraRepository) EncryptStoredTokens(ctx context.Context) (int, error) {
+	ctx = withQueryName(ctx, "OrgJiraRepository.EncryptStoredTokens")
+	if !r.fields.Enabled() {
+		return 0, nil
+	}
+
+	updated := 0
+	for _, table := range []string{"org_jira_settings", "jira_pending_connections"} {
+		n, err := r.encryptTableTokens(ctx, table)
+		if err != nil {
+			return updated, err
+		}
+		updated += n
+	}
+	return updated, nil
+}
+
+// encryptTableTokens rewrites the oauth_access_token and oauth_refresh_token columns of a table
+// that aren't encrypted with the current key
+func (r *OrgJiraRepository) encryptTableTokens(ctx context.Context, table string) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Rows are locked so a concurrent token refresh can't be overwritten with the old token
+	rows, err := tx.Query(ctx, `SELECT id, oauth_access_token, oauth_refresh_token FROM `+table+` FOR UPDATE`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s tokens: %w", table, err)
+	}
+	type storedTokens struct {
+		id                        int64
+		accessToken, refreshToken string
+	}
+	var stale []storedTokens
+	for rows.Next() {
+		var t storedTokens
+		if err := rows.Scan(&t.id, &t.accessToken, &t.refreshToken); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan %s tokens: %w", table, err)
+		}
+		if r.fields.NeedsRewrite(t.accessToken) || r.fields.NeedsRewrite(t.refreshToken) {
+			stale = append(stale, t)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read %s tokens: %w", table, err)
+	}
+
+	for _, t := range stale {
+		accessToken, err := r.fields.Rewrite(t.accessToken)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt %s access token: %w", table, err)
+		}
+		refreshToken, err := r.fields.Rewrite(t.refreshToken)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt %s refresh token: %w", table, err)
+		}
+		if _, err := tx.Exec(ctx, `UPDATE `+table+` SET oauth_access_token = $2, oauth_refresh_token = $3 WHERE id = $1`, t.id, accessToken, refreshToken); err != nil {
+			return 0, fmt.Errorf("failed to encrypt %s tokens: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(stale), nil
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// OrgLinearRepository handles organizations' Linear connections. Each organization has its own,
+// and the repository acts on that of the organization ctx is scoped to, failing with
+// tenant.ErrNoOrg when organizations are required and ctx isn't scoped to one.
+type OrgLinearRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOrgLinearRepository creates a new OrgLinearRepository
+func NewOrgLinearRepository(pool *pgxpool.Pool) *OrgLinearRepository {
+	return &OrgLinearRepository{pool: pool}
+}
+
+// Get returns the organization's Linear settings (there's only one per organization)
+func (r *OrgLinearRepository) Get(ctx context.Context) (*models.OrgLinearSettings, error) {
+	ctx = withQueryName(ctx, "OrgLinearRepository.Get")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT id, org_id, api_key, workspace_name, workspace_url_key, configured_by_id, created_at, updated_at
+		FROM org_linear_settings
+		WHERE org_id = $1
+	`
+
+	var settings models.OrgLinearSettings
+	err = r.pool.QueryRow(ctx, query, orgID).Scan(
+		&settings.ID,
+		&settings.OrgID,
+		&settings.APIKey,
+		&settings.WorkspaceName,
+		&settings.WorkspaceURLKey,
+		&settings.ConfiguredByID,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil // Not connected
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get org linear settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// Save replaces the organization's Linear settings
+func (r *OrgLinearRepository) Save(ctx context.Context, settings *models.OrgLinearSettings) error {
+	ctx = withQueryName(ctx, "OrgLinearRepository.Save")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	settings.OrgID = orgID
+	query := `
+		INSERT INTO org_linear_settings (
+			org_id, api_key, workspace_name, workspace_url_key, configured_by_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (org_id) DO UPDATE SET
+			api_key = EXCLUDED.api_key,
+			workspace_name = EXCLUDED.workspace_name,
+			workspace_url_key = EXCLUDED.workspace_url_key,
+			configured_by_id = EXCLUDED.configured_by_id,
+			created_at = NOW(),
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`
+
+	err = r.pool.QueryRow(ctx, query,
+		settings.OrgID,
+		settings.APIKey,
+		settings.WorkspaceName,
+		settings.WorkspaceURLKey,
+		settings.ConfiguredByID,
+	).Scan(&settings.ID, &settings.CreatedAt, &settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save org linear settings: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the organization's Linear settings
+func (r *OrgLinearRepository) Delete(ctx context.Context) error {
+	ctx = withQueryName(ctx, "OrgLinearRepository.Delete")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := r.pool.Exec(ctx, "DELETE FROM org_linear_settings WHERE org_id = $1", orgID); err != nil {
+		return fmt.Errorf("failed to delete org linear settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/crypto"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// orgSlackColumns are the columns scanOrgSlackSettings reads, in order
+const orgSlackColumns = `id, org_id, bot_access_token, team_id, team_name, bot_user_id, configured_by_id, created_at, updated_at`
+
+// OrgSlackRepository handles organizations' Slack app installations. Each organization has its
+// own, and the repository acts on that of the organization ctx is scoped to, failing with
+// tenant.ErrNoOrg when organizations are required and ctx isn't scoped to one.
+type OrgSlackRepository struct {
+	pool   *pgxpool.Pool
+	fields *crypto.Fields
+}
+
+// NewOrgSlackRepository creates a new OrgSlackRepository
+func NewOrgSlackRepository(pool *pgxpool.Pool) *OrgSlackRepository {
+	return &OrgSlackRepository{pool: pool}
+}
+
+// WithEncryption encrypts bot tokens at rest with fields
+func (r *OrgSlackRepository) WithEncryption(fields *crypto.Fields) *OrgSlackRepository {
+	r.fields = fields
+	return r
+}
+
+// scanOrgSlackSettings scans a row of orgSlackColumns, returning nil when there's none
+func (r *OrgSlackRepository) scanOrgSlackSettings(row pgx.Row) (*models.OrgSlackSettings, error) {
+	var settings models.OrgSlackSettings
+	err := row.Scan(
+		&settings.ID,
+		&settings.OrgID,
+		r.fields.Scan(&settings.BotAccessToken),
+		&settings.TeamID,
+		&settings.TeamName,
+		&settings.BotUserID,
+		&settings.ConfiguredByID,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil // Not connected
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get org slack settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// Get returns the organization's Slack installation (there's only one per organization)
+func (r *OrgSlackRepository) Get(ctx context.Context) (*models.OrgSlackSettings, error) {
+	ctx = withQueryName(ctx, "OrgSlackRepository.Get")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + orgSlackColumns + ` FROM org_slack_settings WHERE org_id = $1 ORDER BY id DESC LIMIT 1`
+	return r.scanOrgSlackSettings(r.pool.QueryRow(ctx, query, orgID))
+}
+
+// GetByTeamID returns the installation in a Slack workspace, whichever organization it belongs
+// to. Interactive requests from Slack only identify the workspace.
+func (r *OrgSlackRepository) GetByTeamID(ctx context.Context, teamID string) (*models.OrgSlackSettings, error) {
+	ctx = withQueryName(ctx, "OrgSlackRepository.GetByTeamID")
+	query := `SELECT ` + orgSlackColumns + ` FROM org_slack_settings WHERE team_id = $1`
+	return r.scanOrgSlackSettings(r.pool.QueryRow(ctx, query, teamID))
+}
+
+// Save replaces the organization's Slack installation
+func (r *OrgSlackRepository) Save(ctx context.Context, settings *models.OrgSlackSettings) error {
+	ctx = withQueryName(ctx, "OrgSlackRepository.Save")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	settings.OrgID = orgID
+	if _, err := tx.Exec(ctx, "DELETE FROM org_slack_settings WHERE org_id = $1", settings.OrgID); err != nil {
+		return fmt.Errorf("failed to clear old settings: %w", err)
+	}
+
+	query := `
+		INSERT INTO org_slack_settings (
+			org_id, bot_access_token, team_id, team_name, bot_user_id, configured_by_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		settings.OrgID,
+		r.fields.Value(settings.BotAccessToken),
+		settings.TeamID,
+		settings.TeamName,
+		settings.BotUserID,
+		settings.ConfiguredByID,
+	).Scan(&settings.ID, &settings.CreatedAt, &settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save org slack settings: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delete removes the organization's Slack installation
+func (r *OrgSlackRepository) Delete(ctx context.Context) error {
+	ctx = withQueryName(ctx, "OrgSlackRepository.Delete")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := r.pool.Exec(ctx, "DELETE FROM org_slack_settings WHERE org_id = $1", orgID); err != nil {
+		return fmt.Errorf("failed to delete org slack settings: %w", err)
+	}
+	return nil
+}
+
+// EncryptStoredTokens encrypts bot tokens stored in plaintext or with a previous key, and returns
+// how many installations were updated. It does nothing without encryption.
+func (r *OrgSlackRepository) EncryptStoredTokens(ctx context.Context) (int, error) {
+	ctx = withQueryName(ctx, "OrgSlackRepository.EncryptStoredTokens")
+	if !r.fields.Enabled() {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `SELECT id, bot_access_token FROM org_slack_settings FOR UPDATE`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read slack tokens: %w", err)
+	}
+	stale := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var token string
+		if err := rows.Scan(&id, &token); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan slack token: %w", err)
+		}
+		if r.fields.NeedsRewrite(token) {
+			stale[id] = token
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read slack tokens: %w", err)
+	}
+
+	for id, token := range stale {
+		encrypted, err := r.fields.Rewrite(token)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt slack token: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `UPDATE org_slack_settings SET bot_access_token = $2 WHERE id = $1`, id, encrypted); err != nil {
+			return 0, fmt.Errorf("failed to encrypt slack token: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(stale), nil
+}
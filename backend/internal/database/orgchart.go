@@ -2,37 +2,68 @@ package database
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
 )
 
 // Column lists for consistent SELECT statements
 const (
-	draftColumns = `id, name, description, created_by_id, status, published_at, created_at, updated_at`
+	draftColumns = `id, name, description, created_by_id, status, published_at, approved_by_id, approved_at, created_at, updated_at`
 	// User columns for org tree (squads are loaded separately via SquadRepository)
 	orgUserColumns = `id, COALESCE(auth0_id, ''), email, first_name, last_name, role, title, department,
 		avatar_url, supervisor_id, date_started, created_at, updated_at`
+	snapshotColumns = `id, draft_id, draft_name, published_by_id, headcount, created_at`
 )
 
+// orgMembersQuery selects everyone in the org structure of the organization bound to $1, ordered
+// for consistent tree building. Guests are external collaborators, not part of the org structure.
+var orgMembersQuery = `SELECT ` + orgUserColumns + ` FROM users WHERE is_active = true AND role <> 'guest' AND ` + orgCondition("org_id", "$1") + `
+	ORDER BY supervisor_id NULLS FIRST, last_name, first_name`
+
 type OrgChartRepository struct {
 	pool      *pgxpool.Pool
 	squadRepo *SquadRepository
+
+	// Serve the org trees when set; drafts and publishing stay on the primary
+	replica      *pgxpool.Pool
+	replicaSquad *SquadRepository
 }
 
 func NewOrgChartRepository(pool *pgxpool.Pool, squadRepo *SquadRepository) *OrgChartRepository {
 	return &OrgChartRepository{pool: pool, squadRepo: squadRepo}
 }
 
+// WithReplica builds org trees, including their members' squads, from a read replica
+func (r *OrgChartRepository) WithReplica(replica *pgxpool.Pool) *OrgChartRepository {
+	r.replica = replica
+	if replica != nil {
+		r.replicaSquad = NewSquadRepository(replica)
+	}
+	return r
+}
+
+// treeSquads returns the squad repository org trees load squads from
+func (r *OrgChartRepository) treeSquads() *SquadRepository {
+	if r.replicaSquad != nil {
+		return r.replicaSquad
+	}
+	return r.squadRepo
+}
+
 // scanDraft scans a row into an OrgChartDraft struct
 func scanDraft(row pgx.Row) (*models.OrgChartDraft, error) {
 	var draft models.OrgChartDraft
 	err := row.Scan(
 		&draft.ID, &draft.Name, &draft.Description, &draft.CreatedByID,
-		&draft.Status, &draft.PublishedAt, &draft.CreatedAt, &draft.UpdatedAt,
+		&draft.Status, &draft.PublishedAt, &draft.ApprovedByID, &draft.ApprovedAt, &draft.CreatedAt, &draft.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -47,7 +78,7 @@ func scanDrafts(rows pgx.Rows) ([]models.OrgChartDraft, error) {
 		var draft models.OrgChartDraft
 		err := rows.Scan(
 			&draft.ID, &draft.Name, &draft.Description, &draft.CreatedByID,
-			&draft.Status, &draft.PublishedAt, &draft.CreatedAt, &draft.UpdatedAt,
+			&draft.Status, &draft.PublishedAt, &draft.ApprovedByID, &draft.ApprovedAt, &draft.CreatedAt, &draft.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -78,12 +109,17 @@ func scanOrgUsers(rows pgx.Rows) ([]models.User, error) {
 
 // CreateDraft creates a new org chart draft
 func (r *OrgChartRepository) CreateDraft(ctx context.Context, req *models.CreateDraftRequest, createdByID int64) (*models.OrgChartDraft, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.CreateDraft")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := `
-		INSERT INTO org_chart_drafts (name, description, created_by_id)
-		VALUES ($1, $2, $3)
+		INSERT INTO org_chart_drafts (name, description, created_by_id, org_id)
+		VALUES ($1, $2, $3, $4)
 		RETURNING ` + draftColumns
 
-	draft, err := scanDraft(r.pool.QueryRow(ctx, query, req.Name, req.Description, createdByID))
+	draft, err := scanDraft(r.pool.QueryRow(ctx, query, req.Name, req.Description, createdByID, orgID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create draft: %w", err)
 	}
@@ -92,9 +128,10 @@ func (r *OrgChartRepository) CreateDraft(ctx context.Context, req *models.Create
 
 // GetDraftByID retrieves a draft by ID with its changes
 func (r *OrgChartRepository) GetDraftByID(ctx context.Context, id int64) (*models.OrgChartDraft, error) {
-	query := `SELECT ` + draftColumns + ` FROM org_chart_drafts WHERE id = $1`
+	ctx = withQueryName(ctx, "OrgChartRepository.GetDraftByID")
+	query := `SELECT ` + draftColumns + ` FROM org_chart_drafts WHERE id = $1 AND ` + orgCondition("org_id", "$2")
 
-	draft, err := scanDraft(r.pool.QueryRow(ctx, query, id))
+	draft, err := scanDraft(r.pool.QueryRow(ctx, query, id, orgScope(ctx)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get draft by ID: %w", err)
 	}
@@ -111,9 +148,10 @@ func (r *OrgChartRepository) GetDraftByID(ctx context.Context, id int64) (*model
 
 // GetDraftsByCreator retrieves all drafts created by a user
 func (r *OrgChartRepository) GetDraftsByCreator(ctx context.Context, creatorID int64) ([]models.OrgChartDraft, error) {
-	query := `SELECT ` + draftColumns + ` FROM org_chart_drafts WHERE created_by_id = $1 ORDER BY updated_at DESC`
+	ctx = withQueryName(ctx, "OrgChartRepository.GetDraftsByCreator")
+	query := `SELECT ` + draftColumns + ` FROM org_chart_drafts WHERE created_by_id = $1 AND ` + orgCondition("org_id", "$2") + ` ORDER BY updated_at DESC`
 
-	rows, err := r.pool.Query(ctx, query, creatorID)
+	rows, err := r.pool.Query(ctx, query, creatorID, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get drafts by creator: %w", err)
 	}
@@ -126,11 +164,12 @@ func (r *OrgChartRepository) GetDraftsByCreator(ctx context.Context, creatorID i
 	return drafts, nil
 }
 
-// GetAllDrafts retrieves all drafts (admin only)
+// GetAllDrafts retrieves all of the organization's drafts (admin only)
 func (r *OrgChartRepository) GetAllDrafts(ctx context.Context) ([]models.OrgChartDraft, error) {
-	query := `SELECT ` + draftColumns + ` FROM org_chart_drafts ORDER BY updated_at DESC`
+	ctx = withQueryName(ctx, "OrgChartRepository.GetAllDrafts")
+	query := `SELECT ` + draftColumns + ` FROM org_chart_drafts WHERE ` + orgCondition("org_id", "$1") + ` ORDER BY updated_at DESC`
 
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := r.pool.Query(ctx, query, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all drafts: %w", err)
 	}
@@ -145,15 +184,16 @@ func (r *OrgChartRepository) GetAllDrafts(ctx context.Context) ([]models.OrgChar
 
 // UpdateDraft updates a draft's name and/or description
 func (r *OrgChartRepository) UpdateDraft(ctx context.Context, id int64, req *models.UpdateDraftRequest) (*models.OrgChartDraft, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.UpdateDraft")
 	query := `
 		UPDATE org_chart_drafts SET
 			name = COALESCE($2, name),
 			description = COALESCE($3, description),
 			updated_at = NOW()
-		WHERE id = $1 AND status = 'draft'
+		WHERE id = $1 AND status = 'draft' AND ` + orgCondition("org_id", "$4") + `
 		RETURNING ` + draftColumns
 
-	draft, err := scanDraft(r.pool.QueryRow(ctx, query, id, req.Name, req.Description))
+	draft, err := scanDraft(r.pool.QueryRow(ctx, query, id, req.Name, req.Description, orgScope(ctx)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to update draft: %w", err)
 	}
@@ -162,7 +202,8 @@ func (r *OrgChartRepository) UpdateDraft(ctx context.Context, id int64, req *mod
 
 // DeleteDraft deletes a draft (only if status is 'draft')
 func (r *OrgChartRepository) DeleteDraft(ctx context.Context, id int64) error {
-	result, err := r.pool.Exec(ctx, `DELETE FROM org_chart_drafts WHERE id = $1 AND status = 'draft'`, id)
+	ctx = withQueryName(ctx, "OrgChartRepository.DeleteDraft")
+	result, err := r.pool.Exec(ctx, `DELETE FROM org_chart_drafts WHERE id = $1 AND status = 'draft' AND `+orgCondition("org_id", "$2"), id, orgScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to delete draft: %w", err)
 	}
@@ -174,6 +215,7 @@ func (r *OrgChartRepository) DeleteDraft(ctx context.Context, id int64) error {
 
 // AddOrUpdateChange adds or updates a change in a draft
 func (r *OrgChartRepository) AddOrUpdateChange(ctx context.Context, draftID int64, req *models.AddDraftChangeRequest, userRepo repository.UserRepository) (*models.DraftChange, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.AddOrUpdateChange")
 	// First, get the current user data to store original values
 	user, err := userRepo.GetByID(ctx, req.UserID)
 	if err != nil {
@@ -232,11 +274,16 @@ func (r *OrgChartRepository) AddOrUpdateChange(ctx context.Context, draftID int6
 
 	change.User = user
 
+	if err := r.clearApproval(ctx, draftID); err != nil {
+		return nil, err
+	}
+
 	return &change, nil
 }
 
 // RemoveChange removes a change from a draft
 func (r *OrgChartRepository) RemoveChange(ctx context.Context, draftID int64, userID int64) error {
+	ctx = withQueryName(ctx, "OrgChartRepository.RemoveChange")
 	result, err := r.pool.Exec(ctx, `DELETE FROM org_chart_draft_changes WHERE draft_id = $1 AND user_id = $2`, draftID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to remove change: %w", err)
@@ -244,11 +291,21 @@ func (r *OrgChartRepository) RemoveChange(ctx context.Context, draftID int64, us
 	if result.RowsAffected() == 0 {
 		return fmt.Errorf("change not found")
 	}
+	return r.clearApproval(ctx, draftID)
+}
+
+// clearApproval withdraws a draft's approval after its changes are edited
+func (r *OrgChartRepository) clearApproval(ctx context.Context, draftID int64) error {
+	_, err := r.pool.Exec(ctx, `UPDATE org_chart_drafts SET approved_by_id = NULL, approved_at = NULL WHERE id = $1`, draftID)
+	if err != nil {
+		return fmt.Errorf("failed to clear draft approval: %w", err)
+	}
 	return nil
 }
 
 // GetDraftChanges retrieves all changes for a draft with user details
 func (r *OrgChartRepository) GetDraftChanges(ctx context.Context, draftID int64) ([]models.DraftChange, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.GetDraftChanges")
 	query := `
 		SELECT c.id, c.draft_id, c.user_id,
 		       c.original_supervisor_id, c.original_department, c.original_role, c.original_squad_ids,
@@ -319,54 +376,122 @@ func (r *OrgChartRepository) GetDraftChanges(ctx context.Context, draftID int64)
 	return changes, nil
 }
 
-// PublishDraft applies all changes and marks the draft as published
-func (r *OrgChartRepository) PublishDraft(ctx context.Context, draftID int64) error {
+// PublishDraft applies all changes, marks the draft as published, and snapshots the resulting org tree,
+// all in one transaction. The draft must be approved first. Changes that would leave a reporting loop, orphaned report, or demoted
+// supervisor are rejected, and any failure rolls everything back with a *models.DraftPublishError
+// naming the change at fault.
+func (r *OrgChartRepository) PublishDraft(ctx context.Context, draftID int64, publishedByID int64) error {
+	ctx = withQueryName(ctx, "OrgChartRepository.PublishDraft")
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
-	// Verify draft exists and is in draft status
+	// Verify draft exists, is in draft status, and has been approved
 	var status models.DraftStatus
-	err = tx.QueryRow(ctx, `SELECT status FROM org_chart_drafts WHERE id = $1 FOR UPDATE`, draftID).Scan(&status)
+	var approvedAt *time.Time
+	err = tx.QueryRow(ctx, `SELECT status, approved_at FROM org_chart_drafts WHERE id = $1 AND `+orgCondition("org_id", "$2")+` FOR UPDATE`,
+		draftID, orgScope(ctx)).Scan(&status, &approvedAt)
 	if err != nil {
 		return fmt.Errorf("draft not found: %w", err)
 	}
 	if status != models.DraftStatusDraft {
 		return fmt.Errorf("draft is not in draft status")
 	}
+	if approvedAt == nil {
+		return &models.DraftPublishError{Message: "Draft must be approved before publishing"}
+	}
 
 	// Get all changes
 	rows, err := tx.Query(ctx, `
-		SELECT user_id, new_supervisor_id, new_department, new_role, new_squad_ids
+		SELECT id, user_id, new_supervisor_id, new_department, new_role, new_squad_ids
 		FROM org_chart_draft_changes WHERE draft_id = $1
+		ORDER BY created_at, id
 	`, draftID)
 	if err != nil {
 		return fmt.Errorf("failed to get changes: %w", err)
 	}
 
-	type changeData struct {
-		userID          int64
-		newSupervisorID *int64
-		newDepartment   *string
-		newRole         *string
-		newSquadIDs     []int64
-	}
-	var changesToApply []changeData
-
+	var changes []models.DraftChange
 	for rows.Next() {
-		var c changeData
-		if err := rows.Scan(&c.userID, &c.newSupervisorID, &c.newDepartment, &c.newRole, &c.newSquadIDs); err != nil {
+		var c models.DraftChange
+		var newRole *string
+		if err := rows.Scan(&c.ID, &c.UserID, &c.NewSupervisorID, &c.NewDepartment, &newRole, &c.NewSquadIDs); err != nil {
 			rows.Close()
 			return fmt.Errorf("failed to scan change: %w", err)
 		}
-		changesToApply = append(changesToApply, c)
+		if newRole != nil {
+			role := models.Role(*newRole)
+			c.NewRole = &role
+		}
+		changes = append(changes, c)
 	}
 	rows.Close()
 
+	// Lock the org so the tree validated here is the one the changes are applied to
+	rows, err = tx.Query(ctx, orgMembersQuery+` FOR UPDATE`, orgScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get users: %w", err)
+	}
+	members, err := scanOrgUsers(rows)
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("failed to scan users: %w", err)
+	}
+
+	isMember := make(map[int64]bool, len(members))
+	for _, u := range members {
+		isMember[u.ID] = true
+	}
+	for _, c := range changes {
+		if !isMember[c.UserID] {
+			return &models.DraftPublishError{
+				ChangeID: &c.ID,
+				UserID:   &c.UserID,
+				Message:  fmt.Sprintf("User %d is no longer an active member of the org", c.UserID),
+			}
+		}
+	}
+	after := models.ApplyDraftChanges(members, changes)
+	if problems := models.FindOrgChartProblems(members, after); len(problems) > 0 {
+		return models.NewDraftProblemsError(changes, problems)
+	}
+
+	// Work out each affected user's history before anything is applied
+	var history []models.UserHistoryEntry
+	changed := make(map[int64]bool, len(changes))
+	newSquadIDs := make(map[int64][]int64)
+	var squadUserIDs []int64
+	for _, c := range changes {
+		changed[c.UserID] = true
+		if c.NewSquadIDs != nil {
+			if _, ok := newSquadIDs[c.UserID]; !ok {
+				squadUserIDs = append(squadUserIDs, c.UserID)
+			}
+			newSquadIDs[c.UserID] = c.NewSquadIDs
+		}
+	}
+	for i := range members {
+		if changed[members[i].ID] {
+			history = append(history, models.DiffUserHistory(&members[i], &after[i])...)
+		}
+	}
+	if len(squadUserIDs) > 0 {
+		squadsMap, err := r.squadRepo.GetByUserIDsWithTx(ctx, tx, squadUserIDs)
+		if err != nil {
+			return fmt.Errorf("failed to load user squads: %w", err)
+		}
+		for _, userID := range squadUserIDs {
+			current := &models.User{Squads: squadsMap[userID]}
+			if entry := models.SquadHistoryEntry(userID, current.SquadIDs(), newSquadIDs[userID]); entry != nil {
+				history = append(history, *entry)
+			}
+		}
+	}
+
 	// Apply each change to the users table
-	for _, c := range changesToApply {
+	for _, c := range changes {
 		_, err = tx.Exec(ctx, `
 			UPDATE users SET
 				supervisor_id = COALESCE($2, supervisor_id),
@@ -374,19 +499,33 @@ func (r *OrgChartRepository) PublishDraft(ctx context.Context, draftID int64) er
 				role = COALESCE($4, role),
 				updated_at = NOW()
 			WHERE id = $1
-		`, c.userID, c.newSupervisorID, c.newDepartment, c.newRole)
+		`, c.UserID, c.NewSupervisorID, c.NewDepartment, roleToString(c.NewRole))
 		if err != nil {
-			return fmt.Errorf("failed to apply change for user %d: %w", c.userID, err)
+			return &models.DraftPublishError{
+				ChangeID: &c.ID,
+				UserID:   &c.UserID,
+				Message:  fmt.Sprintf("Failed to apply change for user %d", c.UserID),
+				Err:      err,
+			}
 		}
 
 		// Apply squad changes within the same transaction
-		if c.newSquadIDs != nil {
-			if err := r.squadRepo.SetUserSquadsWithTx(ctx, tx, c.userID, c.newSquadIDs); err != nil {
-				return fmt.Errorf("failed to apply squad change for user %d: %w", c.userID, err)
+		if c.NewSquadIDs != nil {
+			if err := r.squadRepo.SetUserSquadsWithTx(ctx, tx, c.UserID, c.NewSquadIDs); err != nil {
+				return &models.DraftPublishError{
+					ChangeID: &c.ID,
+					UserID:   &c.UserID,
+					Message:  fmt.Sprintf("Failed to apply squad change for user %d", c.UserID),
+					Err:      err,
+				}
 			}
 		}
 	}
 
+	if err := recordUserHistory(ctx, tx, models.UserHistorySourceOrgChart, &publishedByID, history); err != nil {
+		return err
+	}
+
 	// Mark draft as published
 	_, err = tx.Exec(ctx, `
 		UPDATE org_chart_drafts SET
@@ -399,6 +538,10 @@ func (r *OrgChartRepository) PublishDraft(ctx context.Context, draftID int64) er
 		return fmt.Errorf("failed to mark draft as published: %w", err)
 	}
 
+	if err := r.snapshotOrgTree(ctx, tx, draftID, publishedByID); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -406,9 +549,256 @@ func (r *OrgChartRepository) PublishDraft(ctx context.Context, draftID int64) er
 	return nil
 }
 
+// ApproveDraft records that approverID approved a draft, or returns nil if it isn't in draft status
+func (r *OrgChartRepository) ApproveDraft(ctx context.Context, draftID int64, approverID int64) (*models.OrgChartDraft, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.ApproveDraft")
+	query := `
+		UPDATE org_chart_drafts SET
+			approved_by_id = $2,
+			approved_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'draft' AND ` + orgCondition("org_id", "$3") + `
+		RETURNING ` + draftColumns
+
+	draft, err := scanDraft(r.pool.QueryRow(ctx, query, draftID, approverID, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve draft: %w", err)
+	}
+	return draft, nil
+}
+
+// GetSharedDrafts retrieves the drafts shared with a user
+func (r *OrgChartRepository) GetSharedDrafts(ctx context.Context, userID int64) ([]models.OrgChartDraft, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.GetSharedDrafts")
+	query := `
+		SELECT ` + draftColumns + ` FROM org_chart_drafts
+		WHERE id IN (SELECT draft_id FROM org_chart_draft_shares WHERE user_id = $1) AND ` + orgCondition("org_id", "$2") + `
+		ORDER BY updated_at DESC`
+
+	rows, err := r.pool.Query(ctx, query, userID, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared drafts: %w", err)
+	}
+	defer rows.Close()
+
+	drafts, err := scanDrafts(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan draft: %w", err)
+	}
+	return drafts, nil
+}
+
+// GetDraftShares retrieves everyone a draft is shared with
+func (r *OrgChartRepository) GetDraftShares(ctx context.Context, draftID int64) ([]models.DraftShare, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.GetDraftShares")
+	rows, err := r.pool.Query(ctx, `
+		SELECT draft_id, user_id, permission, shared_by_id, created_at
+		FROM org_chart_draft_shares WHERE draft_id = $1 ORDER BY created_at
+	`, draftID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft shares: %w", err)
+	}
+	defer rows.Close()
+
+	shares := []models.DraftShare{}
+	for rows.Next() {
+		var share models.DraftShare
+		if err := rows.Scan(&share.DraftID, &share.UserID, &share.Permission, &share.SharedByID, &share.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan draft share: %w", err)
+		}
+		shares = append(shares, share)
+	}
+	return shares, rows.Err()
+}
+
+// GetDraftShare retrieves a user's share of a draft, or nil if it isn't shared with them
+func (r *OrgChartRepository) GetDraftShare(ctx context.Context, draftID int64, userID int64) (*models.DraftShare, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.GetDraftShare")
+	var share models.DraftShare
+	err := r.pool.QueryRow(ctx, `
+		SELECT draft_id, user_id, permission, shared_by_id, created_at
+		FROM org_chart_draft_shares WHERE draft_id = $1 AND user_id = $2
+	`, draftID, userID).Scan(&share.DraftID, &share.UserID, &share.Permission, &share.SharedByID, &share.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft share: %w", err)
+	}
+	return &share, nil
+}
+
+// ShareDraft shares a draft with a user, or changes the permission they already have
+func (r *OrgChartRepository) ShareDraft(ctx context.Context, draftID int64, req *models.ShareDraftRequest, sharedByID int64) (*models.DraftShare, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.ShareDraft")
+	var share models.DraftShare
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO org_chart_draft_shares (draft_id, user_id, permission, shared_by_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (draft_id, user_id) DO UPDATE SET permission = EXCLUDED.permission
+		RETURNING draft_id, user_id, permission, shared_by_id, created_at
+	`, draftID, req.UserID, req.Permission, sharedByID).Scan(&share.DraftID, &share.UserID, &share.Permission, &share.SharedByID, &share.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to share draft: %w", err)
+	}
+	return &share, nil
+}
+
+// UnshareDraft stops sharing a draft with a user
+func (r *OrgChartRepository) UnshareDraft(ctx context.Context, draftID int64, userID int64) error {
+	ctx = withQueryName(ctx, "OrgChartRepository.UnshareDraft")
+	result, err := r.pool.Exec(ctx, `DELETE FROM org_chart_draft_shares WHERE draft_id = $1 AND user_id = $2`, draftID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unshare draft: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("share not found")
+	}
+	return nil
+}
+
+// GetDraftComments retrieves the comments on a draft's changes, oldest first
+func (r *OrgChartRepository) GetDraftComments(ctx context.Context, draftID int64) ([]models.DraftComment, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.GetDraftComments")
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, draft_id, change_id, author_id, body, created_at
+		FROM org_chart_draft_comments WHERE draft_id = $1 ORDER BY created_at, id
+	`, draftID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []models.DraftComment{}
+	for rows.Next() {
+		var c models.DraftComment
+		if err := rows.Scan(&c.ID, &c.DraftID, &c.ChangeID, &c.AuthorID, &c.Body, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan draft comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+// AddDraftComment comments on one of a draft's changes, or returns nil if the change isn't in the draft
+func (r *OrgChartRepository) AddDraftComment(ctx context.Context, draftID int64, req *models.CreateDraftCommentRequest, authorID int64) (*models.DraftComment, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.AddDraftComment")
+	var c models.DraftComment
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO org_chart_draft_comments (draft_id, change_id, author_id, body)
+		SELECT draft_id, id, $3, $4 FROM org_chart_draft_changes WHERE id = $2 AND draft_id = $1
+		RETURNING id, draft_id, change_id, author_id, body, created_at
+	`, draftID, req.ChangeID, authorID, req.Body).Scan(&c.ID, &c.DraftID, &c.ChangeID, &c.AuthorID, &c.Body, &c.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to add draft comment: %w", err)
+	}
+	return &c, nil
+}
+
+// snapshotOrgTree records the full org tree as it stands within tx
+func (r *OrgChartRepository) snapshotOrgTree(ctx context.Context, tx pgx.Tx, draftID int64, publishedByID int64) error {
+	rows, err := tx.Query(ctx, orgMembersQuery, orgScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get users for snapshot: %w", err)
+	}
+	users, err := scanOrgUsers(rows)
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("failed to scan users for snapshot: %w", err)
+	}
+
+	trees := r.buildTreesFromUsers(users)
+	userIDs := make([]int64, len(users))
+	for i := range users {
+		userIDs[i] = users[i].ID
+	}
+	squadsMap, err := r.squadRepo.GetByUserIDsWithTx(ctx, tx, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to load squads for snapshot: %w", err)
+	}
+	for i := range trees {
+		assignSquadsToTree(&trees[i], squadsMap)
+	}
+
+	tree, err := json.Marshal(trees)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO org_chart_snapshots (draft_id, draft_name, published_by_id, headcount, tree, org_id)
+		SELECT id, name, $2, $3, $4, org_id FROM org_chart_drafts WHERE id = $1
+	`, draftID, publishedByID, len(users), tree)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetSnapshots returns the organization's snapshots without their trees, newest first, along with
+// how many there are.
+// With before set, only snapshots taken earlier are included, so the first is the org as it stood then.
+func (r *OrgChartRepository) GetSnapshots(ctx context.Context, before *time.Time, limit, offset int) ([]models.OrgChartSnapshot, int, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.GetSnapshots")
+	var total int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM org_chart_snapshots
+		WHERE ($1::timestamptz IS NULL OR created_at < $1) AND `+orgCondition("org_id", "$2"),
+		before, orgScope(ctx)).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count snapshots: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+snapshotColumns+` FROM org_chart_snapshots
+		WHERE ($1::timestamptz IS NULL OR created_at < $1) AND `+orgCondition("org_id", "$4")+`
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, before, limit, offset, orgScope(ctx))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []models.OrgChartSnapshot{}
+	for rows.Next() {
+		var s models.OrgChartSnapshot
+		if err := rows.Scan(&s.ID, &s.DraftID, &s.DraftName, &s.PublishedByID, &s.Headcount, &s.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, total, rows.Err()
+}
+
+// GetSnapshotByID returns a snapshot with its tree, or nil if there is none
+func (r *OrgChartRepository) GetSnapshotByID(ctx context.Context, id int64) (*models.OrgChartSnapshot, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.GetSnapshotByID")
+	var s models.OrgChartSnapshot
+	var tree []byte
+	err := r.pool.QueryRow(ctx, `SELECT `+snapshotColumns+`, tree FROM org_chart_snapshots WHERE id = $1 AND `+orgCondition("org_id", "$2"), id, orgScope(ctx)).
+		Scan(&s.ID, &s.DraftID, &s.DraftName, &s.PublishedByID, &s.Headcount, &s.CreatedAt, &tree)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	if err := json.Unmarshal(tree, &s.Tree); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return &s, nil
+}
+
 // GetOrgTree builds the organization tree for a supervisor
 // Uses a single recursive CTE query to fetch all descendants, then builds tree in memory
 func (r *OrgChartRepository) GetOrgTree(ctx context.Context, supervisorID int64) (*models.OrgTreeNode, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.GetOrgTree")
 	// Use recursive CTE to fetch all descendants in ONE query
 	query := `
 		WITH RECURSIVE org_tree AS (
@@ -429,7 +819,7 @@ func (r *OrgChartRepository) GetOrgTree(ctx context.Context, supervisorID int64)
 		ORDER BY depth, last_name, first_name
 	`
 
-	rows, err := r.pool.Query(ctx, query, supervisorID)
+	rows, err := readPool(r.pool, r.replica).Query(ctx, query, supervisorID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get org tree: %w", err)
 	}
@@ -461,10 +851,9 @@ func (r *OrgChartRepository) GetOrgTree(ctx context.Context, supervisorID int64)
 // GetFullOrgTree builds the full organization tree starting from top-level users (admin only)
 // Uses a single query to fetch ALL users, then builds multiple trees in memory
 func (r *OrgChartRepository) GetFullOrgTree(ctx context.Context) ([]models.OrgTreeNode, error) {
-	// Fetch ALL active users in ONE query, ordered for consistent tree building
-	query := `SELECT ` + orgUserColumns + ` FROM users WHERE is_active = true ORDER BY supervisor_id NULLS FIRST, last_name, first_name`
-
-	rows, err := r.pool.Query(ctx, query)
+	ctx = withQueryName(ctx, "OrgChartRepository.GetFullOrgTree")
+	// Fetch ALL active users in ONE query
+	rows, err := readPool(r.pool, r.replica).Query(ctx, orgMembersQuery, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all users: %w", err)
 	}
@@ -490,88 +879,134 @@ func (r *OrgChartRepository) GetFullOrgTree(ctx context.Context) ([]models.OrgTr
 	return trees, nil
 }
 
-// buildTreeFromUsers builds a single tree from a flat list of users (first user is root)
-// This eliminates N+1 queries by building the tree structure in memory
-func (r *OrgChartRepository) buildTreeFromUsers(users []models.User) *models.OrgTreeNode {
-	if len(users) == 0 {
-		return nil
-	}
+// GetScopedOrgTree builds the part of the org tree the scope asks for: the tree under one user or
+// the whole org, down to a number of levels. Nodes whose reports were cut off by the depth limit are
+// marked as truncated. Guests are never included.
+func (r *OrgChartRepository) GetScopedOrgTree(ctx context.Context, scope models.OrgTreeScope) ([]models.OrgTreeNode, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.GetScopedOrgTree")
+	// Only walk down as far as the depth limit, starting from the root or from everyone whose
+	// supervisor isn't a member
+	query := `
+		WITH RECURSIVE members AS (
+			SELECT id, supervisor_id FROM users
+			WHERE ($2 OR is_active = true) AND role <> 'guest' AND deleted_at IS NULL AND ` + orgCondition("org_id", "$1") + `
+		), org_tree AS (
+			SELECT id, 0 AS depth FROM members m
+			WHERE CASE WHEN $3::BIGINT IS NULL
+				THEN m.supervisor_id IS NULL OR m.supervisor_id NOT IN (SELECT id FROM members)
+				ELSE m.id = $3 END
+
+			UNION ALL
 
-	// Create a map of user ID -> tree node pointer for O(1) lookup
-	nodeMap := make(map[int64]*models.OrgTreeNode)
+			SELECT m.id, ot.depth + 1
+			FROM members m
+			INNER JOIN org_tree ot ON m.supervisor_id = ot.id
+			WHERE $4::INT IS NULL OR ot.depth < $4
+		)
+		SELECT ` + orgUserColumns + `
+		FROM users INNER JOIN org_tree USING (id)
+		ORDER BY depth, last_name, first_name
+	`
 
-	// First pass: create all nodes
-	for i := range users {
-		nodeMap[users[i].ID] = &models.OrgTreeNode{
-			User:     users[i],
-			Children: []models.OrgTreeNode{},
-		}
+	rows, err := readPool(r.pool, r.replica).Query(ctx, query, orgScope(ctx), scope.IncludeInactive, scope.RootID, scope.Depth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get org tree: %w", err)
 	}
+	defer rows.Close()
 
-	// The first user is the root (based on CTE ordering)
-	root := nodeMap[users[0].ID]
-
-	// Second pass: build parent-child relationships
-	for i := range users {
-		if users[i].SupervisorID != nil {
-			parentID := *users[i].SupervisorID
-			if parent, exists := nodeMap[parentID]; exists {
-				child := nodeMap[users[i].ID]
-				parent.Children = append(parent.Children, *child)
-			}
+	users, err := scanOrgUsers(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan users: %w", err)
+	}
+	if len(users) == 0 {
+		if scope.RootID != nil {
+			return nil, fmt.Errorf("user not found")
 		}
+		return []models.OrgTreeNode{}, nil
 	}
 
-	return root
+	trees := models.BuildOrgTrees(users, nil)
+	if scope.Depth != nil {
+		if err := r.markTruncated(ctx, trees, *scope.Depth, scope.IncludeInactive); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.loadSquadsForTrees(ctx, trees); err != nil {
+		return nil, err
+	}
+	return trees, nil
 }
 
-// buildTreesFromUsers builds multiple trees from a flat list of all users
-// Users with supervisor_id = NULL become root nodes of separate trees
-// This eliminates N+1 queries by building all tree structures in memory
-func (r *OrgChartRepository) buildTreesFromUsers(users []models.User) []models.OrgTreeNode {
-	if len(users) == 0 {
-		return []models.OrgTreeNode{}
+// markTruncated marks the nodes at the depth limit that have reports of their own
+func (r *OrgChartRepository) markTruncated(ctx context.Context, trees []models.OrgTreeNode, depth int, includeInactive bool) error {
+	var leaves []*models.OrgTreeNode
+	var walk func(node *models.OrgTreeNode, level int)
+	walk = func(node *models.OrgTreeNode, level int) {
+		if level == depth {
+			leaves = append(leaves, node)
+			return
+		}
+		for i := range node.Children {
+			walk(&node.Children[i], level+1)
+		}
+	}
+	for i := range trees {
+		walk(&trees[i], 0)
+	}
+	if len(leaves) == 0 {
+		return nil
 	}
 
-	// Create a map of user ID -> tree node pointer for O(1) lookup
-	nodeMap := make(map[int64]*models.OrgTreeNode)
+	ids := make([]int64, len(leaves))
+	for i, leaf := range leaves {
+		ids[i] = leaf.User.ID
+	}
+	rows, err := readPool(r.pool, r.replica).Query(ctx, `
+		SELECT DISTINCT supervisor_id FROM users
+		WHERE supervisor_id = ANY($1) AND ($2 OR is_active = true) AND role <> 'guest' AND deleted_at IS NULL`,
+		ids, includeInactive)
+	if err != nil {
+		return fmt.Errorf("failed to check for truncated reports: %w", err)
+	}
+	defer rows.Close()
 
-	// First pass: create all nodes
-	for i := range users {
-		nodeMap[users[i].ID] = &models.OrgTreeNode{
-			User:     users[i],
-			Children: []models.OrgTreeNode{},
+	truncated := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("failed to scan truncated reports: %w", err)
 		}
+		truncated[id] = true
 	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to check for truncated reports: %w", err)
+	}
+	for _, leaf := range leaves {
+		leaf.ChildrenTruncated = truncated[leaf.User.ID]
+	}
+	return nil
+}
 
-	// Track root nodes (users with no supervisor)
-	var roots []*models.OrgTreeNode
-
-	// Second pass: build parent-child relationships and identify roots
-	for i := range users {
-		node := nodeMap[users[i].ID]
-		if users[i].SupervisorID == nil {
-			// This is a root node
-			roots = append(roots, node)
-		} else {
-			// Attach to parent
-			parentID := *users[i].SupervisorID
-			if parent, exists := nodeMap[parentID]; exists {
-				parent.Children = append(parent.Children, *node)
-			} else {
-				// Parent not found (orphan) - treat as root
-				roots = append(roots, node)
-			}
-		}
+// buildTreeFromUsers builds a single tree from a flat list of users (first user is root)
+// This eliminates N+1 queries by building the tree structure in memory
+func (r *OrgChartRepository) buildTreeFromUsers(users []models.User) *models.OrgTreeNode {
+	if len(users) == 0 {
+		return nil
 	}
 
-	// Convert to value slice for return
-	trees := make([]models.OrgTreeNode, len(roots))
-	for i, root := range roots {
-		trees[i] = *root
+	// The first user is the root (based on CTE ordering), and everyone else descends from them
+	trees := models.BuildOrgTrees(users, nil)
+	if len(trees) == 0 {
+		return nil
 	}
+	return &trees[0]
+}
 
-	return trees
+// buildTreesFromUsers builds multiple trees from a flat list of all users
+// Users with supervisor_id = NULL, or whose supervisor isn't in the list, become root nodes of separate trees
+// This eliminates N+1 queries by building all tree structures in memory
+func (r *OrgChartRepository) buildTreesFromUsers(users []models.User) []models.OrgTreeNode {
+	return models.BuildOrgTrees(users, nil)
 }
 
 // loadSquadsForTree loads squads for all users in the tree
@@ -585,7 +1020,7 @@ func (r *OrgChartRepository) loadSquadsForTree(ctx context.Context, tree *models
 	}
 
 	// Batch load all squads
-	squadsMap, err := r.squadRepo.GetByUserIDs(ctx, userIDs)
+	squadsMap, err := r.treeSquads().GetByUserIDs(ctx, userIDs)
 	if err != nil {
 		return fmt.Errorf("failed to load squads for tree: %w", err)
 	}
@@ -609,7 +1044,7 @@ func (r *OrgChartRepository) loadSquadsForTrees(ctx context.Context, trees []mod
 	}
 
 	// Batch load all squads
-	squadsMap, err := r.squadRepo.GetByUserIDs(ctx, userIDs)
+	squadsMap, err := r.treeSquads().GetByUserIDs(ctx, userIDs)
 	if err != nil {
 		return fmt.Errorf("failed to load squads for trees: %w", err)
 	}
@@ -638,6 +1073,52 @@ func assignSquadsToTree(node *models.OrgTreeNode, squadsMap map[int64][]models.S
 	}
 }
 
+// GetMemberStats loads each user's open tasks, whether they are out of office today, and their task
+// completion for the quarter containing now, for rolling up into org tree aggregates.
+// Only tasks assigned directly to a user are counted.
+func (r *OrgChartRepository) GetMemberStats(ctx context.Context, userIDs []int64, now time.Time) (map[int64]models.OrgMemberStats, error) {
+	ctx = withQueryName(ctx, "OrgChartRepository.GetMemberStats")
+	if len(userIDs) == 0 {
+		return make(map[int64]models.OrgMemberStats), nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	quarterStart := time.Date(now.Year(), now.Month()-(now.Month()-1)%3, 1, 0, 0, 0, 0, time.UTC)
+	quarterEnd := quarterStart.AddDate(0, 3, 0)
+
+	query := `
+		SELECT u.id,
+			COUNT(t.id) FILTER (WHERE t.status IN ('pending', 'in_progress')),
+			COUNT(t.id) FILTER (WHERE t.status <> 'cancelled' AND t.due_date >= $3 AND t.due_date < $4),
+			COUNT(t.id) FILTER (WHERE t.status = 'completed' AND t.due_date >= $3 AND t.due_date < $4),
+			EXISTS (
+				SELECT 1 FROM time_off_requests o
+				WHERE o.user_id = u.id AND o.status = 'approved'
+					AND o.start_date <= $2 AND o.end_date >= $2
+			)
+		FROM users u
+		LEFT JOIN tasks t ON t.assignment_type = 'user' AND t.assigned_user_id = u.id AND t.deleted_at IS NULL
+		WHERE u.id = ANY($1)
+		GROUP BY u.id
+	`
+	rows, err := r.pool.Query(ctx, query, userIDs, today, quarterStart, quarterEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member stats: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int64]models.OrgMemberStats, len(userIDs))
+	for rows.Next() {
+		var userID int64
+		var stats models.OrgMemberStats
+		if err := rows.Scan(&userID, &stats.OpenTasks, &stats.QuarterTasks, &stats.QuarterCompleted, &stats.OutOfOffice); err != nil {
+			return nil, fmt.Errorf("failed to scan member stats: %w", err)
+		}
+		result[userID] = stats
+	}
+	return result, rows.Err()
+}
+
 // Helper function to convert Role pointer to string pointer
 func roleToString(r *models.Role) *string {
 	if r == nil {
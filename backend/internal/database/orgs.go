@@ -0,0 +1,175 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// orgScope returns the organization queries made in ctx are limited to, or nil when ctx acts
+// across every organization, as in background jobs. Contexts that do neither get an organization
+// that doesn't exist, so their queries find nothing. Bind it to a placeholder used with
+// orgCondition.
+func orgScope(ctx context.Context) *int64 {
+	if orgID, ok := tenant.OrgID(ctx); ok {
+		return &orgID
+	}
+	if tenant.IsAcrossOrgs(ctx) {
+		return nil
+	}
+	var none int64
+	return &none
+}
+
+// orgCondition limits column to the organization bound to arg, or matches every organization when
+// arg is NULL
+func orgCondition(column, arg string) string {
+	return "(" + arg + "::BIGINT IS NULL OR " + column + " = " + arg + ")"
+}
+
+// OrganizationRepository stores organization-wide settings
+type OrganizationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOrganizationRepository creates a new organization repository
+func NewOrganizationRepository(pool *pgxpool.Pool) *OrganizationRepository {
+	return &OrganizationRepository{pool: pool}
+}
+
+// GetIPAllowlist returns the IP addresses and CIDR ranges the organization's users may connect
+// from; an empty list allows any network
+func (r *OrganizationRepository) GetIPAllowlist(ctx context.Context, orgID int64) ([]string, error) {
+	ctx = withQueryName(ctx, "OrganizationRepository.GetIPAllowlist")
+	var allowlist []string
+	err := r.pool.QueryRow(ctx, `SELECT ip_allowlist FROM organizations WHERE id = $1`, orgID).Scan(&allowlist)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("organization not found")
+		}
+		return nil, fmt.Errorf("failed to get IP allowlist: %w", err)
+	}
+	return allowlist, nil
+}
+
+// UpdateIPAllowlist replaces the networks the organization's users may connect from
+func (r *OrganizationRepository) UpdateIPAllowlist(ctx context.Context, orgID int64, allowlist []string) error {
+	ctx = withQueryName(ctx, "OrganizationRepository.UpdateIPAllowlist")
+	if allowlist == nil {
+		allowlist = []string{}
+	}
+	tag, err := r.pool.Exec(ctx, `UPDATE organizations SET ip_allowlist = $2 WHERE id = $1`, orgID, allowlist)
+	if err != nil {
+		return fmt.Errorf("failed to update IP allowlist: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
+
+// GetInvitationDomains returns the email domains the organization's invitations are limited to; an
+// empty list allows any domain
+func (r *OrganizationRepository) GetInvitationDomains(ctx context.Context, orgID int64) ([]string, error) {
+	ctx = withQueryName(ctx, "OrganizationRepository.GetInvitationDomains")
+	var domains []string
+	err := r.pool.QueryRow(ctx, `SELECT invitation_domains FROM organizations WHERE id = $1`, orgID).Scan(&domains)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("organization not found")
+		}
+		return nil, fmt.Errorf("failed to get invitation domains: %w", err)
+	}
+	return domains, nil
+}
+
+// UpdateInvitationDomains replaces the email domains the organization's invitations are limited to
+func (r *OrganizationRepository) UpdateInvitationDomains(ctx context.Context, orgID int64, domains []string) error {
+	ctx = withQueryName(ctx, "OrganizationRepository.UpdateInvitationDomains")
+	if domains == nil {
+		domains = []string{}
+	}
+	tag, err := r.pool.Exec(ctx, `UPDATE organizations SET invitation_domains = $2 WHERE id = $1`, orgID, domains)
+	if err != nil {
+		return fmt.Errorf("failed to update invitation domains: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
+
+// GetByInvitationDomain returns the organization whose invitations are limited to domain, or nil
+// when none are. Should several list it, the oldest organization is returned.
+func (r *OrganizationRepository) GetByInvitationDomain(ctx context.Context, domain string) (*int64, error) {
+	ctx = withQueryName(ctx, "OrganizationRepository.GetByInvitationDomain")
+	var orgID int64
+	err := r.pool.QueryRow(ctx, `SELECT id FROM organizations WHERE $1 = ANY(invitation_domains) ORDER BY id LIMIT 1`,
+		strings.ToLower(domain)).Scan(&orgID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization by invitation domain: %w", err)
+	}
+	return &orgID, nil
+}
+
+// GetSettings returns the settings the organization has changed from the server's defaults
+func (r *OrganizationRepository) GetSettings(ctx context.Context, orgID int64) (map[models.OrgSettingKey]string, error) {
+	ctx = withQueryName(ctx, "OrganizationRepository.GetSettings")
+	rows, err := r.pool.Query(ctx, `SELECT setting_key, value FROM org_settings WHERE org_id = $1`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get org settings: %w", err)
+	}
+	defer rows.Close()
+
+	settings := make(map[models.OrgSettingKey]string)
+	for rows.Next() {
+		var key models.OrgSettingKey
+		var value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan org setting: %w", err)
+		}
+		settings[key] = value
+	}
+	return settings, rows.Err()
+}
+
+// UpdateSettings saves changes to the organization's settings in one transaction. A nil value
+// removes the organization's override, so the server's default applies again.
+func (r *OrganizationRepository) UpdateSettings(ctx context.Context, orgID int64, changes map[models.OrgSettingKey]*string, updatedByID int64) error {
+	ctx = withQueryName(ctx, "OrganizationRepository.UpdateSettings")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for key, value := range changes {
+		if value == nil {
+			_, err = tx.Exec(ctx, `DELETE FROM org_settings WHERE org_id = $1 AND setting_key = $2`, orgID, key)
+		} else {
+			_, err = tx.Exec(ctx, `
+				INSERT INTO org_settings (org_id, setting_key, value, updated_by_id)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (org_id, setting_key) DO UPDATE
+				SET value = EXCLUDED.value, updated_by_id = EXCLUDED.updated_by_id, updated_at = NOW()`,
+				orgID, key, *value, updatedByID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update org setting %s: %w", key, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit org settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+func TestOrgScope(t *testing.T) {
+	defer tenant.Require(false)
+
+	scope := func(ctx context.Context) string {
+		if orgID := orgScope(ctx); orgID != nil {
+			return string(rune('0' + *orgID))
+		}
+		return "all"
+	}
+
+	tests := []struct {
+		name     string
+		required bool
+		ctx      context.Context
+		want     string
+	}{
+		{"scoped", true, tenant.WithOrgID(context.Background(), 1), "1"},
+		{"another organization", true, tenant.WithOrgID(context.Background(), 2), "2"},
+		{"single organization", false, context.Background(), "all"},
+		{"unscoped with multi-tenancy fails closed", true, context.Background(), "0"},
+		{"explicitly across organizations", true, tenant.AcrossOrgs(context.Background()), "all"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant.Require(tt.required)
+			if got := scope(tt.ctx); got != tt.want {
+				t.Errorf("orgScope() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const outboxMessageColumns = `id, kind, payload, status, attempts, next_attempt_at, last_attempt_at, last_error,
+	created_at, sent_at`
+
+// OutboxRepository stores side effects waiting to be carried out by the outbox worker
+type OutboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(pool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{pool: pool}
+}
+
+// scanOutboxMessage scans a row of outboxMessageColumns into an OutboxMessage
+func scanOutboxMessage(row pgx.Row) (*models.OutboxMessage, error) {
+	var m models.OutboxMessage
+	var payload []byte
+	err := row.Scan(&m.ID, &m.Kind, &payload, &m.Status, &m.Attempts, &m.NextAttemptAt, &m.LastAttemptAt, &m.LastError,
+		&m.CreatedAt, &m.SentAt)
+	if err != nil {
+		return nil, err
+	}
+	m.Payload = payload
+	return &m, nil
+}
+
+// execer runs statements with the pool or a transaction
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// querier runs queries with the pool or a transaction
+type querier interface {
+	execer
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// enqueueOutbox saves a side effect with the pool or a transaction. Inside a transaction the
+// message is only sent if the change that caused it commits.
+func enqueueOutbox(ctx context.Context, q execer, kind models.OutboxKind, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox payload: %w", err)
+	}
+	if _, err := q.Exec(ctx, `INSERT INTO outbox_messages (kind, payload) VALUES ($1, $2)`, kind, body); err != nil {
+		return fmt.Errorf("failed to queue outbox message: %w", err)
+	}
+	return nil
+}
+
+// Enqueue saves a side effect that isn't tied to a transaction
+func (r *OutboxRepository) Enqueue(ctx context.Context, kind models.OutboxKind, payload any) error {
+	ctx = withQueryName(ctx, "OutboxRepository.Enqueue")
+	return enqueueOutbox(ctx, r.pool, kind, payload)
+}
+
+// ClaimNext marks the oldest due message as sending, counts the attempt, and returns it, or nil if
+// none are due. Messages left sending longer than staleAfter (for example by a crashed worker) are
+// claimed again. SKIP LOCKED lets several workers poll the table without sending the same message.
+func (r *OutboxRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.OutboxMessage, error) {
+	ctx = withQueryName(ctx, "OutboxRepository.ClaimNext")
+	query := `
+		UPDATE outbox_messages
+		SET status = 'sending', attempts = attempts + 1, last_attempt_at = NOW()
+		WHERE id = (
+			SELECT id FROM outbox_messages
+			WHERE (status = 'pending' AND next_attempt_at <= NOW())
+			OR (status = 'sending' AND last_attempt_at < NOW() - $1 * INTERVAL '1 second')
+			ORDER BY next_attempt_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxMessageColumns
+
+	message, err := scanOutboxMessage(r.pool.QueryRow(ctx, query, int64(staleAfter.Seconds())))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox message: %w", err)
+	}
+	return message, nil
+}
+
+// MarkSent records that a message's side effect was carried out
+func (r *OutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "OutboxRepository.MarkSent")
+	query := `UPDATE outbox_messages SET status = 'sent', last_error = NULL, sent_at = NOW() WHERE id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark outbox message sent: %w", err)
+	}
+	return nil
+}
+
+// ScheduleRetry records a failed attempt and queues the next one
+func (r *OutboxRepository) ScheduleRetry(ctx context.Context, id int64, message string, nextAttemptAt time.Time) error {
+	ctx = withQueryName(ctx, "OutboxRepository.ScheduleRetry")
+	query := `UPDATE outbox_messages SET status = 'pending', last_error = $2, next_attempt_at = $3 WHERE id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, id, message, nextAttemptAt); err != nil {
+		return fmt.Errorf("failed to schedule outbox retry: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt after which no more are made
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, message string) error {
+	ctx = withQueryName(ctx, "OutboxRepository.MarkFailed")
+	query := `UPDATE outbox_messages SET status = 'failed', last_error = $2 WHERE id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, id, message); err != nil {
+		return fmt.Errorf("failed to mark outbox message failed: %w", err)
+	}
+	return nil
+}
+
+// PurgeFinished removes sent and failed messages created before the given time
+func (r *OutboxRepository) PurgeFinished(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withQueryName(ctx, "OutboxRepository.PurgeFinished")
+	result, err := r.pool.Exec(ctx, `DELETE FROM outbox_messages WHERE status IN ('sent', 'failed') AND created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge outbox messages: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
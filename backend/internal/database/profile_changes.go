@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// profileChangeColumns are the columns of profile_change_requests, aliased p
+const profileChangeColumns = `p.id, p.org_id, p.user_id, p.title, p.department, p.status, p.reviewed_by_id, p.reviewed_at,
+	p.created_at, p.updated_at`
+
+// ProfileChangeRepository stores the title and department changes users ask for until they're reviewed
+type ProfileChangeRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewProfileChangeRepository creates a new profile change repository
+func NewProfileChangeRepository(pool *pgxpool.Pool) *ProfileChangeRepository {
+	return &ProfileChangeRepository{pool: pool}
+}
+
+// scanProfileChange scans a row of profileChangeColumns, followed by any extra destinations
+func scanProfileChange(row pgx.Row, extra ...any) (*models.ProfileChangeRequest, error) {
+	var req models.ProfileChangeRequest
+	dest := append([]any{&req.ID, &req.OrgID, &req.UserID, &req.Title, &req.Department, &req.Status,
+		&req.ReviewedByID, &req.ReviewedAt, &req.CreatedAt, &req.UpdatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// Propose files a pending change to the user's title and department, replacing the one they
+// already have pending
+func (r *ProfileChangeRepository) Propose(ctx context.Context, userID int64, title, department *string) (*models.ProfileChangeRequest, error) {
+	ctx = withQueryName(ctx, "ProfileChangeRepository.Propose")
+	query := `
+		INSERT INTO profile_change_requests AS p (org_id, user_id, title, department)
+		SELECT org_id, id, $2, $3 FROM users WHERE id = $1
+		ON CONFLICT (user_id) WHERE status = 'pending' DO UPDATE SET
+			title = EXCLUDED.title,
+			department = EXCLUDED.department,
+			updated_at = NOW()
+		RETURNING ` + profileChangeColumns
+
+	req, err := scanProfileChange(r.pool.QueryRow(ctx, query, userID, title, department))
+	if err != nil {
+		return nil, fmt.Errorf("failed to propose profile change: %w", err)
+	}
+	return req, nil
+}
+
+// GetByID retrieves a profile change request in the organization of ctx by ID
+func (r *ProfileChangeRepository) GetByID(ctx context.Context, id int64) (*models.ProfileChangeRequest, error) {
+	ctx = withQueryName(ctx, "ProfileChangeRepository.GetByID")
+	query := `SELECT ` + profileChangeColumns + ` FROM profile_change_requests p
+		WHERE p.id = $1 AND ` + orgCondition("p.org_id", "$2")
+
+	req, err := scanProfileChange(r.pool.QueryRow(ctx, query, id, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile change: %w", err)
+	}
+	return req, nil
+}
+
+// GetLatestByUser retrieves the most recent profile change the user asked for
+func (r *ProfileChangeRepository) GetLatestByUser(ctx context.Context, userID int64) (*models.ProfileChangeRequest, error) {
+	ctx = withQueryName(ctx, "ProfileChangeRepository.GetLatestByUser")
+	query := `SELECT ` + profileChangeColumns + ` FROM profile_change_requests p
+		WHERE p.user_id = $1 ORDER BY p.created_at DESC, p.id DESC LIMIT 1`
+
+	req, err := scanProfileChange(r.pool.QueryRow(ctx, query, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile change: %w", err)
+	}
+	return req, nil
+}
+
+// List retrieves the profile change requests in the organization of ctx, oldest first, optionally
+// only those with a status or from the direct reports of a supervisor
+func (r *ProfileChangeRepository) List(ctx context.Context, status *models.ProfileChangeStatus, supervisorID *int64) ([]models.ProfileChangeRequest, error) {
+	ctx = withQueryName(ctx, "ProfileChangeRepository.List")
+	query := `
+		SELECT ` + profileChangeColumns + `, u.first_name || ' ' || u.last_name, u.title, u.department
+		FROM profile_change_requests p
+		JOIN users u ON u.id = p.user_id
+		WHERE ($1::text IS NULL OR p.status = $1) AND ($2::BIGINT IS NULL OR u.supervisor_id = $2)
+		  AND ` + orgCondition("p.org_id", "$3") + `
+		ORDER BY p.created_at, p.id`
+
+	rows, err := r.pool.Query(ctx, query, status, supervisorID, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profile changes: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []models.ProfileChangeRequest{}
+	for rows.Next() {
+		var name, title, department string
+		req, err := scanProfileChange(rows, &name, &title, &department)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile change: %w", err)
+		}
+		req.UserName, req.CurrentTitle, req.CurrentDepartment = name, title, department
+		requests = append(requests, *req)
+	}
+	return requests, rows.Err()
+}
+
+// Approve applies a pending profile change to the user, recording it in their history, and marks
+// the request approved
+func (r *ProfileChangeRepository) Approve(ctx context.Context, id int64, reviewerID int64) (*models.User, error) {
+	ctx = withQueryName(ctx, "ProfileChangeRepository.Approve")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	req, err := scanProfileChange(tx.QueryRow(ctx, `SELECT `+profileChangeColumns+` FROM profile_change_requests p
+		WHERE p.id = $1 AND p.status = 'pending' AND `+orgCondition("p.org_id", "$2")+` FOR UPDATE`, id, orgScope(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("pending profile change not found")
+		}
+		return nil, fmt.Errorf("failed to get profile change: %w", err)
+	}
+
+	before, err := scanUser(tx.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1 FOR UPDATE`, req.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	user, err := scanUser(tx.QueryRow(ctx, `
+		UPDATE users SET title = COALESCE($2, title), department = COALESCE($3, department), updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+userColumns, req.UserID, req.Title, req.Department))
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply profile change: %w", err)
+	}
+	if err := recordUserHistory(ctx, tx, models.UserHistorySourceProfileChange, &reviewerID, models.DiffUserHistory(before, user)); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE profile_change_requests SET status = 'approved', reviewed_by_id = $2, reviewed_at = NOW(), updated_at = NOW()
+		WHERE id = $1`, id, reviewerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve profile change: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return user, nil
+}
+
+// Reject turns down a pending profile change. The user can ask again.
+func (r *ProfileChangeRepository) Reject(ctx context.Context, id int64, reviewerID int64) (*models.ProfileChangeRequest, error) {
+	ctx = withQueryName(ctx, "ProfileChangeRepository.Reject")
+	query := `
+		UPDATE profile_change_requests p SET status = 'rejected', reviewed_by_id = $2, reviewed_at = NOW(), updated_at = NOW()
+		WHERE p.id = $1 AND p.status = 'pending' AND ` + orgCondition("p.org_id", "$3") + `
+		RETURNING ` + profileChangeColumns
+
+	req, err := scanProfileChange(r.pool.QueryRow(ctx, query, id, reviewerID, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("pending profile change not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reject profile change: %w", err)
+	}
+	return req, nil
+}
@@ -0,0 +1,314 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// reportTeamCondition limits the users aliased u to the supervisor bound to $3 and their direct
+// reports, or matches everyone when $3 is NULL. Every report query binds $1 to the first day,
+// $2 to the day after the last, $3 to the team's supervisor, and $4 to the organization.
+const reportTeamCondition = `($3::BIGINT IS NULL OR u.id = $3 OR u.supervisor_id = $3)`
+
+// ReportRepository aggregates the team analytics reports
+type ReportRepository struct {
+	pool     *pgxpool.Pool
+	meetings *MeetingRepository // Expands recurring meetings the way the calendar does
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(pool *pgxpool.Pool) *ReportRepository {
+	return &ReportRepository{pool: pool, meetings: NewMeetingRepository(pool)}
+}
+
+// reportArgs returns the arguments every report query is bound to
+func reportArgs(ctx context.Context, q *models.ReportQuery) []any {
+	return []any{q.From, q.End(), q.TeamOf, orgScope(ctx)}
+}
+
+// TimeOffUsage totals approved time off starting in the period by department, quarter, and type
+func (r *ReportRepository) TimeOffUsage(ctx context.Context, q *models.ReportQuery) ([]models.TimeOffUsageRow, error) {
+	ctx = withQueryName(ctx, "ReportRepository.TimeOffUsage")
+	query := `
+		SELECT COALESCE(u.department, ''), to_char(t.start_date, 'YYYY-"Q"Q'), t.request_type,
+		       COUNT(*), SUM(t.end_date - t.start_date + 1)
+		FROM time_off_requests t
+		JOIN users u ON u.id = t.user_id
+		WHERE t.status = 'approved' AND t.start_date >= $1::date AND t.start_date < $2::date
+		  AND ` + reportTeamCondition + ` AND ` + orgCondition("t.org_id", "$4") + `
+		GROUP BY 1, 2, 3
+		ORDER BY 1, 2, 3`
+
+	rows, err := r.pool.Query(ctx, query, reportArgs(ctx, q)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time off usage: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.TimeOffUsageRow{}
+	for rows.Next() {
+		var row models.TimeOffUsageRow
+		if err := rows.Scan(&row.Department, &row.Quarter, &row.RequestType, &row.Requests, &row.Days); err != nil {
+			return nil, fmt.Errorf("failed to scan time off usage: %w", err)
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// ApprovalLatency measures how long each reviewer took over the time off they reviewed in the period
+func (r *ReportRepository) ApprovalLatency(ctx context.Context, q *models.ReportQuery) ([]models.ApprovalLatencyRow, error) {
+	ctx = withQueryName(ctx, "ReportRepository.ApprovalLatency")
+	query := `
+		SELECT t.reviewer_id, rv.first_name || ' ' || rv.last_name, COUNT(*),
+		       COUNT(*) FILTER (WHERE t.status = 'approved'), COUNT(*) FILTER (WHERE t.status = 'rejected'),
+		       AVG(EXTRACT(EPOCH FROM t.reviewed_at - t.created_at)) / 3600,
+		       MAX(EXTRACT(EPOCH FROM t.reviewed_at - t.created_at)) / 3600
+		FROM time_off_requests t
+		JOIN users u ON u.id = t.user_id
+		JOIN users rv ON rv.id = t.reviewer_id
+		WHERE t.status IN ('approved', 'rejected') AND t.reviewed_at >= $1 AND t.reviewed_at < $2
+		  AND ` + reportTeamCondition + ` AND ` + orgCondition("t.org_id", "$4") + `
+		GROUP BY t.reviewer_id, rv.first_name, rv.last_name
+		ORDER BY rv.first_name, rv.last_name, t.reviewer_id`
+
+	rows, err := r.pool.Query(ctx, query, reportArgs(ctx, q)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval latency: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.ApprovalLatencyRow{}
+	for rows.Next() {
+		var row models.ApprovalLatencyRow
+		err := rows.Scan(&row.ReviewerID, &row.ReviewerName, &row.Reviewed, &row.Approved, &row.Rejected,
+			&row.AverageHours, &row.MaxHours)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan approval latency: %w", err)
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// MeetingLoad counts the meetings starting in the period that each person was invited to and didn't
+// decline. One-off meetings and exceptions are totaled in SQL; recurring series are expanded with
+// MeetingRepository.ExpandRecurringMeetings, so every occurrence counts and edited or cancelled
+// ones aren't counted twice.
+func (r *ReportRepository) MeetingLoad(ctx context.Context, q *models.ReportQuery) ([]models.MeetingLoadRow, error) {
+	ctx = withQueryName(ctx, "ReportRepository.MeetingLoad")
+	args := reportArgs(ctx, q)
+	loads := make(map[int64]*models.MeetingLoadRow)
+
+	oneOffQuery := `
+		SELECT u.id, u.first_name || ' ' || u.last_name, COUNT(*),
+		       COALESCE(SUM(EXTRACT(EPOCH FROM m.end_time - m.start_time)), 0) / 3600
+		FROM meeting_attendees a
+		JOIN meetings m ON m.id = a.meeting_id
+		JOIN users u ON u.id = a.user_id
+		WHERE m.recurrence_type IS NULL AND NOT m.is_cancelled AND m.deleted_at IS NULL
+		  AND a.response_status <> 'declined' AND m.start_time >= $1 AND m.start_time < $2
+		  AND ` + reportTeamCondition + ` AND ` + orgCondition("m.org_id", "$4") + `
+		GROUP BY u.id, u.first_name, u.last_name`
+	rows, err := r.pool.Query(ctx, oneOffQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meeting load: %w", err)
+	}
+	for rows.Next() {
+		var row models.MeetingLoadRow
+		if err := rows.Scan(&row.UserID, &row.Name, &row.Meetings, &row.Hours); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan meeting load: %w", err)
+		}
+		loads[row.UserID] = &row
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get meeting load: %w", err)
+	}
+
+	seriesQuery := `
+		SELECT m.id, m.start_time, m.end_time, m.recurrence_type, m.recurrence_interval, m.recurrence_end_date,
+		       m.recurrence_days_of_week, m.recurrence_day_of_month, m.timezone,
+		       array_agg(u.id), array_agg(u.first_name || ' ' || u.last_name)
+		FROM meetings m
+		JOIN meeting_attendees a ON a.meeting_id = m.id
+		JOIN users u ON u.id = a.user_id
+		WHERE m.recurrence_type IS NOT NULL AND NOT m.is_cancelled AND m.deleted_at IS NULL
+		  AND a.response_status <> 'declined' AND m.start_time < $2
+		  AND (m.recurrence_end_date IS NULL OR m.recurrence_end_date >= $1)
+		  AND ` + reportTeamCondition + ` AND ` + orgCondition("m.org_id", "$4") + `
+		GROUP BY m.id`
+	rows, err = r.pool.Query(ctx, seriesQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurring meeting load: %w", err)
+	}
+	var series []models.Meeting
+	var attendeeIDs [][]int64
+	var attendeeNames [][]string
+	for rows.Next() {
+		var m models.Meeting
+		var recurrenceType string
+		var ids []int64
+		var names []string
+		err := rows.Scan(&m.ID, &m.StartTime, &m.EndTime, &recurrenceType, &m.RecurrenceInterval,
+			&m.RecurrenceEndDate, &m.RecurrenceDaysOfWeek, &m.RecurrenceDayOfMonth, &m.Timezone, &ids, &names)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan recurring meeting: %w", err)
+		}
+		rt := models.RecurrenceType(recurrenceType)
+		m.RecurrenceType = &rt
+		series = append(series, m)
+		attendeeIDs = append(attendeeIDs, ids)
+		attendeeNames = append(attendeeNames, names)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get recurring meeting load: %w", err)
+	}
+
+	if err := r.meetings.loadExceptionStarts(ctx, r.meetings.pool, series); err != nil {
+		return nil, err
+	}
+	// The expansion includes occurrences starting exactly at its end
+	last := q.End().Add(-1)
+	for i := range series {
+		occurrences := r.meetings.ExpandRecurringMeetings(series[i:i+1], q.From, last)
+		if len(occurrences) == 0 {
+			continue
+		}
+		hours := float64(len(occurrences)) * series[i].EndTime.Sub(series[i].StartTime).Hours()
+		for j, userID := range attendeeIDs[i] {
+			load, ok := loads[userID]
+			if !ok {
+				load = &models.MeetingLoadRow{UserID: userID, Name: attendeeNames[i][j]}
+				loads[userID] = load
+			}
+			load.Meetings += len(occurrences)
+			load.Hours += hours
+		}
+	}
+
+	result := make([]models.MeetingLoadRow, 0, len(loads))
+	for _, load := range loads {
+		result = append(result, *load)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Hours != result[j].Hours {
+			return result[i].Hours > result[j].Hours
+		}
+		return result[i].UserID < result[j].UserID
+	})
+	return result, nil
+}
+
+// JiraThroughput counts the cached Jira issues resolved in the period by each squad's members
+func (r *ReportRepository) JiraThroughput(ctx context.Context, q *models.ReportQuery) ([]models.JiraThroughputRow, error) {
+	ctx = withQueryName(ctx, "ReportRepository.JiraThroughput")
+	query := `
+		SELECT s.id, COALESCE(s.name, ''), COUNT(DISTINCT j.issue_key), COUNT(DISTINCT u.id)
+		FROM jira_issues_cache j
+		JOIN users u ON u.jira_account_id = j.assignee_account_id AND u.deleted_at IS NULL
+		LEFT JOIN user_squads us ON us.user_id = u.id
+		LEFT JOIN squads s ON s.id = us.squad_id
+		WHERE j.resolved
+		  AND COALESCE((j.data->>'resolved_at')::timestamptz, j.jira_updated_at) >= $1
+		  AND COALESCE((j.data->>'resolved_at')::timestamptz, j.jira_updated_at) < $2
+		  AND ` + reportTeamCondition + ` AND ` + orgCondition("u.org_id", "$4") + `
+		GROUP BY s.id, s.name
+		ORDER BY 3 DESC, 2`
+
+	rows, err := r.pool.Query(ctx, query, reportArgs(ctx, q)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jira throughput: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.JiraThroughputRow{}
+	for rows.Next() {
+		var row models.JiraThroughputRow
+		if err := rows.Scan(&row.SquadID, &row.Squad, &row.Resolved, &row.Assignees); err != nil {
+			return nil, fmt.Errorf("failed to scan jira throughput: %w", err)
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// Headcount counts each month's hires and departures and the headcount at its end. People without a
+// start date started when their account was created.
+func (r *ReportRepository) Headcount(ctx context.Context, q *models.ReportQuery) ([]models.HeadcountRow, error) {
+	ctx = withQueryName(ctx, "ReportRepository.Headcount")
+	query := `
+		WITH months AS (
+			SELECT generate_series(date_trunc('month', $1::date), date_trunc('month', $2::date - 1), INTERVAL '1 month')::date AS month
+		), people AS (
+			SELECT COALESCE(u.date_started, u.created_at)::date AS started,
+			       COALESCE(u.termination_date, u.deleted_at::date) AS departed
+			FROM users u
+			WHERE u.role <> 'guest' AND ` + reportTeamCondition + ` AND ` + orgCondition("u.org_id", "$4") + `
+		)
+		SELECT to_char(m.month, 'YYYY-MM'),
+		       COUNT(p.started) FILTER (WHERE p.started >= m.month AND p.started < m.month + INTERVAL '1 month'),
+		       COUNT(p.departed) FILTER (WHERE p.departed >= m.month AND p.departed < m.month + INTERVAL '1 month'),
+		       COUNT(p.started) FILTER (WHERE p.started < m.month + INTERVAL '1 month'
+		                                AND (p.departed IS NULL OR p.departed >= m.month + INTERVAL '1 month'))
+		FROM months m
+		LEFT JOIN people p ON true
+		GROUP BY m.month
+		ORDER BY m.month`
+
+	rows, err := r.pool.Query(ctx, query, reportArgs(ctx, q)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get headcount: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.HeadcountRow{}
+	for rows.Next() {
+		var row models.HeadcountRow
+		if err := rows.Scan(&row.Month, &row.Hires, &row.Departures, &row.Headcount); err != nil {
+			return nil, fmt.Errorf("failed to scan headcount: %w", err)
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// InactiveAccounts lists active accounts not seen in the query's inactive days before the end of
+// the period, longest inactive first. Accounts that never signed in count from when they were
+// created.
+func (r *ReportRepository) InactiveAccounts(ctx context.Context, q *models.ReportQuery) ([]models.InactiveAccountRow, error) {
+	ctx = withQueryName(ctx, "ReportRepository.InactiveAccounts")
+	query := `
+		SELECT u.id, u.first_name || ' ' || u.last_name, u.email, u.role, COALESCE(u.department, ''),
+		       u.last_login_at, u.last_seen_at,
+		       floor(extract(epoch FROM $2::timestamptz - COALESCE(u.last_seen_at, u.created_at)) / 86400)::int
+		FROM users u
+		WHERE u.is_active AND u.deleted_at IS NULL
+		  AND COALESCE(u.last_seen_at, u.created_at) < $2::timestamptz - make_interval(days => $5)
+		  AND ` + reportTeamCondition + ` AND ` + orgCondition("u.org_id", "$4") + `
+		ORDER BY COALESCE(u.last_seen_at, u.created_at), u.id`
+
+	rows, err := r.pool.Query(ctx, query, append(reportArgs(ctx, q), q.InactiveDays)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inactive accounts: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.InactiveAccountRow{}
+	for rows.Next() {
+		var row models.InactiveAccountRow
+		if err := rows.Scan(&row.UserID, &row.Name, &row.Email, &row.Role, &row.Department,
+			&row.LastLoginAt, &row.LastSeenAt, &row.DaysInactive); err != nil {
+			return nil, fmt.Errorf("failed to scan inactive account: %w", err)
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const savedViewColumns = `id, user_id, resource, name, filters, created_at, updated_at`
+
+type SavedViewRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSavedViewRepository(pool *pgxpool.Pool) *SavedViewRepository {
+	return &SavedViewRepository{pool: pool}
+}
+
+// scanSavedView scans a row of savedViewColumns into a SavedView
+func scanSavedView(row pgx.Row) (*models.SavedView, error) {
+	var v models.SavedView
+	if err := row.Scan(&v.ID, &v.UserID, &v.Resource, &v.Name, &v.Filters, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if v.Filters == nil {
+		v.Filters = map[string]string{}
+	}
+	return &v, nil
+}
+
+// Create saves a view for the user
+func (r *SavedViewRepository) Create(ctx context.Context, userID int64, req *models.SavedViewRequest) (*models.SavedView, error) {
+	ctx = withQueryName(ctx, "SavedViewRepository.Create")
+	query := `
+		INSERT INTO saved_views (user_id, resource, name, filters)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + savedViewColumns
+
+	view, err := scanSavedView(r.pool.QueryRow(ctx, query, userID, req.Resource, req.Name, req.Filters))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create saved view: %w", err)
+	}
+	return view, nil
+}
+
+// GetByID retrieves a saved view by ID
+func (r *SavedViewRepository) GetByID(ctx context.Context, id int64) (*models.SavedView, error) {
+	ctx = withQueryName(ctx, "SavedViewRepository.GetByID")
+	query := `SELECT ` + savedViewColumns + ` FROM saved_views WHERE id = $1`
+
+	view, err := scanSavedView(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved view: %w", err)
+	}
+	return view, nil
+}
+
+// ListByUser retrieves a user's saved views by name, optionally only those for one list
+func (r *SavedViewRepository) ListByUser(ctx context.Context, userID int64, resource *models.SavedViewResource) ([]models.SavedView, error) {
+	ctx = withQueryName(ctx, "SavedViewRepository.ListByUser")
+	query := `
+		SELECT ` + savedViewColumns + `
+		FROM saved_views
+		WHERE user_id = $1 AND ($2::text IS NULL OR resource = $2)
+		ORDER BY resource, LOWER(name), id`
+
+	rows, err := r.pool.Query(ctx, query, userID, resource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved views: %w", err)
+	}
+	defer rows.Close()
+
+	views := []models.SavedView{}
+	for rows.Next() {
+		view, err := scanSavedView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved view: %w", err)
+		}
+		views = append(views, *view)
+	}
+	return views, rows.Err()
+}
+
+// Update replaces a saved view's list, name, and filters
+func (r *SavedViewRepository) Update(ctx context.Context, id int64, req *models.SavedViewRequest) (*models.SavedView, error) {
+	ctx = withQueryName(ctx, "SavedViewRepository.Update")
+	query := `
+		UPDATE saved_views SET resource = $2, name = $3, filters = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + savedViewColumns
+
+	view, err := scanSavedView(r.pool.QueryRow(ctx, query, id, req.Resource, req.Name, req.Filters))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update saved view: %w", err)
+	}
+	return view, nil
+}
+
+// Delete deletes a saved view
+func (r *SavedViewRepository) Delete(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "SavedViewRepository.Delete")
+	if _, err := r.pool.Exec(ctx, `DELETE FROM saved_views WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete saved view: %w", err)
+	}
+	return nil
+}
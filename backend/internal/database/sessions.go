@@ -0,0 +1,214 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const userSessionColumns = `id, user_id, token_id, provider, ip_address, user_agent, issued_at, expires_at,
+	created_at, last_seen_at, revoked_at`
+
+// SessionRepository stores the tokens users authenticate with and the denylist of revoked ones
+type SessionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(pool *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{pool: pool}
+}
+
+func scanUserSession(row pgx.Row) (*models.UserSession, error) {
+	var s models.UserSession
+	err := row.Scan(&s.ID, &s.UserID, &s.TokenID, &s.Provider, &s.IPAddress, &s.UserAgent, &s.IssuedAt, &s.ExpiresAt,
+		&s.CreatedAt, &s.LastSeenAt, &s.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Touch records that a session's token was used, creating the session on its first use. The
+// user is marked seen, and a new session is recorded as a sign-in.
+func (r *SessionRepository) Touch(ctx context.Context, session *models.UserSession) error {
+	ctx = withQueryName(ctx, "SessionRepository.Touch")
+	// xmax is 0 only for rows the upsert inserted
+	query := `
+		WITH session AS (
+			INSERT INTO user_sessions (user_id, token_id, provider, ip_address, user_agent, issued_at, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (token_id) DO UPDATE SET
+				ip_address = EXCLUDED.ip_address,
+				user_agent = EXCLUDED.user_agent,
+				last_seen_at = NOW()
+			RETURNING ` + userSessionColumns + `, xmax = 0 AS created
+		), login AS (
+			INSERT INTO user_logins (user_id, provider, ip_address, user_agent)
+			SELECT user_id, provider, ip_address, user_agent FROM session WHERE created
+		), seen AS (
+			UPDATE users SET last_seen_at = NOW(),
+				last_login_at = CASE WHEN (SELECT created FROM session) THEN NOW() ELSE last_login_at END
+			WHERE id = $1
+		)
+		SELECT ` + userSessionColumns + ` FROM session`
+
+	touched, err := scanUserSession(r.pool.QueryRow(ctx, query,
+		session.UserID, session.TokenID, session.Provider, session.IPAddress, session.UserAgent,
+		session.IssuedAt, session.ExpiresAt,
+	))
+	if err != nil {
+		return fmt.Errorf("failed to record session: %w", err)
+	}
+	*session = *touched
+	return nil
+}
+
+// GetByID retrieves a session, or nil if it doesn't exist
+func (r *SessionRepository) GetByID(ctx context.Context, id int64) (*models.UserSession, error) {
+	ctx = withQueryName(ctx, "SessionRepository.GetByID")
+	query := `SELECT ` + userSessionColumns + ` FROM user_sessions WHERE id = $1`
+	session, err := scanUserSession(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return session, nil
+}
+
+// ListByUser retrieves a user's most recently used sessions
+func (r *SessionRepository) ListByUser(ctx context.Context, userID int64, limit int) ([]models.UserSession, error) {
+	ctx = withQueryName(ctx, "SessionRepository.ListByUser")
+	query := `SELECT ` + userSessionColumns + ` FROM user_sessions WHERE user_id = $1
+		ORDER BY last_seen_at DESC, id DESC LIMIT $2`
+	rows, err := r.pool.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.UserSession{}
+	for rows.Next() {
+		session, err := scanUserSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
+
+// ListLogins retrieves a user's most recent sign-ins
+func (r *SessionRepository) ListLogins(ctx context.Context, userID int64, limit int) ([]models.UserLogin, error) {
+	ctx = withQueryName(ctx, "SessionRepository.ListLogins")
+	query := `SELECT id, user_id, provider, ip_address, user_agent, created_at FROM user_logins WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC LIMIT $2`
+	rows, err := r.pool.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logins: %w", err)
+	}
+	defer rows.Close()
+
+	logins := []models.UserLogin{}
+	for rows.Next() {
+		var l models.UserLogin
+		if err := rows.Scan(&l.ID, &l.UserID, &l.Provider, &l.IPAddress, &l.UserAgent, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan login: %w", err)
+		}
+		logins = append(logins, l)
+	}
+	return logins, rows.Err()
+}
+
+// Revoke adds a token, or every token issued to a subject so far, to the denylist and marks the
+// user's matching sessions revoked
+func (r *SessionRepository) Revoke(ctx context.Context, revoked *models.RevokedToken) error {
+	ctx = withQueryName(ctx, "SessionRepository.Revoke")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO revoked_tokens (subject, token_id, user_id, revoked_by_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, revoked_at`,
+		revoked.Subject, revoked.TokenID, revoked.UserID, revoked.RevokedByID, revoked.ExpiresAt,
+	).Scan(&revoked.ID, &revoked.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	if revoked.TokenID != nil {
+		_, err = tx.Exec(ctx, `UPDATE user_sessions SET revoked_at = $2 WHERE token_id = $1 AND revoked_at IS NULL`,
+			*revoked.TokenID, revoked.RevokedAt)
+	} else if revoked.UserID != nil {
+		_, err = tx.Exec(ctx, `UPDATE user_sessions SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`,
+			*revoked.UserID, revoked.RevokedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark sessions revoked: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// IsTokenRevoked reports whether a token is on the denylist
+func (r *SessionRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	ctx = withQueryName(ctx, "SessionRepository.IsTokenRevoked")
+	var revoked bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE token_id = $1 AND expires_at > NOW())`,
+		tokenID,
+	).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	return revoked, nil
+}
+
+// GetSubjectRevokedAt returns when every token issued to a subject was last revoked, or nil if
+// they haven't been
+func (r *SessionRepository) GetSubjectRevokedAt(ctx context.Context, subject string) (*time.Time, error) {
+	ctx = withQueryName(ctx, "SessionRepository.GetSubjectRevokedAt")
+	var revokedAt *time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT MAX(revoked_at) FROM revoked_tokens
+		WHERE subject = $1 AND token_id IS NULL AND expires_at > NOW()`,
+		subject,
+	).Scan(&revokedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check subject revocation: %w", err)
+	}
+	return revokedAt, nil
+}
+
+// DeleteExpired removes denylist entries once their tokens have expired, sessions last used before
+// their cutoff, and sign-ins made before theirs
+func (r *SessionRepository) DeleteExpired(ctx context.Context, sessionsBefore, loginsBefore time.Time) (int64, error) {
+	ctx = withQueryName(ctx, "SessionRepository.DeleteExpired")
+	revoked, err := r.pool.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired revocations: %w", err)
+	}
+	sessions, err := r.pool.Exec(ctx, `DELETE FROM user_sessions WHERE last_seen_at < $1`, sessionsBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	logins, err := r.pool.Exec(ctx, `DELETE FROM user_logins WHERE created_at < $1`, loginsBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old logins: %w", err)
+	}
+	return revoked.RowsAffected() + sessions.RowsAffected() + logins.RowsAffected(), nil
+}
@@ -0,0 +1,269 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+const skillColumns = `id, name, category, created_at, updated_at`
+
+// SkillRepository handles database operations for the skills catalog and users' skill levels
+type SkillRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSkillRepository creates a new skill repository
+func NewSkillRepository(pool *pgxpool.Pool) *SkillRepository {
+	return &SkillRepository{pool: pool}
+}
+
+// scanSkill scans a row into a Skill
+func scanSkill(row pgx.Row) (*models.Skill, error) {
+	var skill models.Skill
+	if err := row.Scan(&skill.ID, &skill.Name, &skill.Category, &skill.CreatedAt, &skill.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &skill, nil
+}
+
+// GetAll retrieves the organization's skills catalog ordered by category and name
+func (r *SkillRepository) GetAll(ctx context.Context) ([]models.Skill, error) {
+	ctx = withQueryName(ctx, "SkillRepository.GetAll")
+	rows, err := r.pool.Query(ctx, `SELECT `+skillColumns+` FROM skills WHERE `+orgCondition("org_id", "$1")+` ORDER BY category, LOWER(name)`, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skills: %w", err)
+	}
+	defer rows.Close()
+
+	skills := []models.Skill{}
+	for rows.Next() {
+		skill, err := scanSkill(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan skill: %w", err)
+		}
+		skills = append(skills, *skill)
+	}
+	return skills, rows.Err()
+}
+
+// GetByID retrieves a skill, or nil if it doesn't exist
+func (r *SkillRepository) GetByID(ctx context.Context, id int64) (*models.Skill, error) {
+	ctx = withQueryName(ctx, "SkillRepository.GetByID")
+	skill, err := scanSkill(r.pool.QueryRow(ctx, `SELECT `+skillColumns+` FROM skills WHERE id = $1 AND `+orgCondition("org_id", "$2"), id, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skill: %w", err)
+	}
+	return skill, nil
+}
+
+// GetByName retrieves a skill by name ignoring case, or nil if it doesn't exist
+func (r *SkillRepository) GetByName(ctx context.Context, name string) (*models.Skill, error) {
+	ctx = withQueryName(ctx, "SkillRepository.GetByName")
+	skill, err := scanSkill(r.pool.QueryRow(ctx, `SELECT `+skillColumns+` FROM skills WHERE LOWER(name) = LOWER($1) AND `+orgCondition("org_id", "$2"), name, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skill by name: %w", err)
+	}
+	return skill, nil
+}
+
+// Create adds a skill to the catalog of the organization ctx is scoped to
+func (r *SkillRepository) Create(ctx context.Context, req *models.SkillRequest) (*models.Skill, error) {
+	ctx = withQueryName(ctx, "SkillRepository.Create")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `INSERT INTO skills (name, category, org_id) VALUES ($1, $2, $3) RETURNING ` + skillColumns
+	skill, err := scanSkill(r.pool.QueryRow(ctx, query, req.Name, req.Category, orgID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create skill: %w", err)
+	}
+	return skill, nil
+}
+
+// Update renames or recategorizes a skill, returning nil if it doesn't exist
+func (r *SkillRepository) Update(ctx context.Context, id int64, req *models.SkillRequest) (*models.Skill, error) {
+	ctx = withQueryName(ctx, "SkillRepository.Update")
+	query := `UPDATE skills SET name = $1, category = $2, updated_at = $3 WHERE id = $4 AND ` + orgCondition("org_id", "$5") + ` RETURNING ` + skillColumns
+	skill, err := scanSkill(r.pool.QueryRow(ctx, query, req.Name, req.Category, time.Now(), id, orgScope(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update skill: %w", err)
+	}
+	return skill, nil
+}
+
+// Delete removes a skill from the catalog and from everyone's profile
+func (r *SkillRepository) Delete(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "SkillRepository.Delete")
+	tag, err := r.pool.Exec(ctx, `DELETE FROM skills WHERE id = $1 AND `+orgCondition("org_id", "$2"), id, orgScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete skill: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("skill not found")
+	}
+	return nil
+}
+
+// GetUserSkills retrieves the skills on a user's profile, strongest first
+func (r *SkillRepository) GetUserSkills(ctx context.Context, userID int64) ([]models.UserSkill, error) {
+	ctx = withQueryName(ctx, "SkillRepository.GetUserSkills")
+	rows, err := r.pool.Query(ctx, `
+		SELECT s.id, s.name, s.category, us.level, us.updated_at
+		FROM user_skills us
+		JOIN skills s ON s.id = us.skill_id
+		WHERE us.user_id = $1 AND ` + orgCondition("s.org_id", "$2") + `
+		ORDER BY us.level DESC, LOWER(s.name)
+	`, userID, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user skills: %w", err)
+	}
+	defer rows.Close()
+
+	skills := []models.UserSkill{}
+	for rows.Next() {
+		var skill models.UserSkill
+		if err := rows.Scan(&skill.SkillID, &skill.Name, &skill.Category, &skill.Level, &skill.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user skill: %w", err)
+		}
+		skills = append(skills, skill)
+	}
+	return skills, rows.Err()
+}
+
+// SetUserSkills replaces every skill on a user's profile. Skills whose level is unchanged keep
+// their updated_at so it reflects when each level was last assessed. Skills outside the
+// organization's catalog are skipped.
+func (r *SkillRepository) SetUserSkills(ctx context.Context, userID int64, skills []models.UserSkillLevel) error {
+	ctx = withQueryName(ctx, "SkillRepository.SetUserSkills")
+	skillIDs := make([]int64, len(skills))
+	levels := make([]int16, len(skills))
+	for i, s := range skills {
+		skillIDs[i] = s.SkillID
+		levels[i] = int16(s.Level)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `DELETE FROM user_skills WHERE user_id = $1 AND NOT (skill_id = ANY($2))`, userID, skillIDs)
+	if err != nil {
+		return fmt.Errorf("failed to remove user skills: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_skills (user_id, skill_id, level)
+		SELECT $1, t.skill_id, t.level FROM unnest($2::bigint[], $3::smallint[]) AS t(skill_id, level)
+		JOIN skills s ON s.id = t.skill_id AND ` + orgCondition("s.org_id", "$4") + `
+		ON CONFLICT (user_id, skill_id) DO UPDATE SET
+			level = EXCLUDED.level,
+			updated_at = NOW()
+		WHERE user_skills.level <> EXCLUDED.level
+	`, userID, skillIDs, levels, orgScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to save user skills: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// FindExperts returns the organization's active users who know a skill whose name contains the
+// query, at minLevel or above. Exact name matches come first, then higher levels.
+func (r *SkillRepository) FindExperts(ctx context.Context, query string, minLevel models.SkillLevel) ([]models.SkillExpert, error) {
+	ctx = withQueryName(ctx, "SkillRepository.FindExperts")
+	rows, err := r.pool.Query(ctx, `
+		SELECT u.id, u.first_name, u.last_name, u.title, u.department, u.avatar_url, s.id, s.name, us.level
+		FROM skills s
+		JOIN user_skills us ON us.skill_id = s.id
+		JOIN users u ON u.id = us.user_id
+		WHERE s.name ILIKE '%' || $1 || '%' AND us.level >= $2 AND u.is_active = true AND ` + orgCondition("s.org_id", "$4") + `
+		ORDER BY LOWER(s.name) = LOWER($3) DESC, us.level DESC, u.last_name, u.first_name, s.name
+	`, likeEscaper.Replace(query), int16(minLevel), query, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find skill experts: %w", err)
+	}
+	defer rows.Close()
+
+	experts := []models.SkillExpert{}
+	for rows.Next() {
+		var e models.SkillExpert
+		err := rows.Scan(&e.UserID, &e.FirstName, &e.LastName, &e.Title, &e.Department, &e.AvatarURL,
+			&e.SkillID, &e.SkillName, &e.Level)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan skill expert: %w", err)
+		}
+		experts = append(experts, e)
+	}
+	return experts, rows.Err()
+}
+
+// GetSquadCoverage reports how the active members of a squad cover every catalog skill, weakest
+// coverage first. Returns nil if the squad doesn't exist.
+func (r *SkillRepository) GetSquadCoverage(ctx context.Context, squadID int64) (*models.SquadSkillCoverage, error) {
+	ctx = withQueryName(ctx, "SkillRepository.GetSquadCoverage")
+	coverage := models.SquadSkillCoverage{SquadID: squadID, Skills: []models.SkillCoverage{}}
+	err := r.pool.QueryRow(ctx, `
+		SELECT s.name, (
+			SELECT COUNT(*) FROM user_squads us JOIN users u ON u.id = us.user_id
+			WHERE us.squad_id = s.id AND u.is_active = true
+		)
+		FROM squads s WHERE s.id = $1 AND ` + orgCondition("s.org_id", "$2") + `
+	`, squadID, orgScope(ctx)).Scan(&coverage.SquadName, &coverage.MemberCount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get squad: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		WITH members AS (
+			SELECT us.user_id FROM user_squads us JOIN users u ON u.id = us.user_id
+			WHERE us.squad_id = $1 AND u.is_active = true
+		)
+		SELECT s.id, s.name, s.category,
+			COUNT(sk.user_id),
+			COUNT(sk.user_id) FILTER (WHERE sk.level >= $2),
+			COALESCE(MAX(sk.level), 0)
+		FROM skills s
+		LEFT JOIN user_skills sk ON sk.skill_id = s.id AND sk.user_id IN (SELECT user_id FROM members)
+		WHERE ` + orgCondition("s.org_id", "$3") + `
+		GROUP BY s.id
+		ORDER BY 5, 4, LOWER(s.name)
+	`, squadID, int16(models.SkillLevelAdvanced), orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get squad skill coverage: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.SkillCoverage
+		if err := rows.Scan(&c.SkillID, &c.Name, &c.Category, &c.Members, &c.Advanced, &c.TopLevel); err != nil {
+			return nil, fmt.Errorf("failed to scan skill coverage: %w", err)
+		}
+		coverage.Skills = append(coverage.Skills, c)
+	}
+	return &coverage, rows.Err()
+}
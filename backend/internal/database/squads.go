@@ -2,13 +2,26 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
 )
 
+// squadColumns selects a squad along with its lead, if it has one
+const squadColumns = `s.id, s.name,
+	(SELECT l.user_id FROM user_squads l WHERE l.squad_id = s.id AND l.role = 'lead'), s.created_at`
+
+// squadLeadMembersQuery selects the users in every squad led by the user bound to leadArg
+func squadLeadMembersQuery(leadArg string) string {
+	return `SELECT m.user_id FROM user_squads m
+		JOIN user_squads l ON l.squad_id = m.squad_id AND l.role = 'lead'
+		WHERE l.user_id = ` + leadArg
+}
+
 // SquadRepository handles database operations for squads
 type SquadRepository struct {
 	pool *pgxpool.Pool
@@ -21,8 +34,9 @@ func NewSquadRepository(pool *pgxpool.Pool) *SquadRepository {
 
 // GetAll retrieves all squads ordered by name
 func (r *SquadRepository) GetAll(ctx context.Context) ([]models.Squad, error) {
-	query := `SELECT id, name, created_at FROM squads ORDER BY name`
-	rows, err := r.pool.Query(ctx, query)
+	ctx = withQueryName(ctx, "SquadRepository.GetAll")
+	query := `SELECT ` + squadColumns + ` FROM squads s WHERE ` + orgCondition("s.org_id", "$1") + ` ORDER BY s.name`
+	rows, err := r.pool.Query(ctx, query, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get squads: %w", err)
 	}
@@ -31,7 +45,7 @@ func (r *SquadRepository) GetAll(ctx context.Context) ([]models.Squad, error) {
 	var squads []models.Squad
 	for rows.Next() {
 		var squad models.Squad
-		err := rows.Scan(&squad.ID, &squad.Name, &squad.CreatedAt)
+		err := rows.Scan(&squad.ID, &squad.Name, &squad.LeadID, &squad.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan squad: %w", err)
 		}
@@ -40,11 +54,15 @@ func (r *SquadRepository) GetAll(ctx context.Context) ([]models.Squad, error) {
 	return squads, nil
 }
 
-// GetByID retrieves a squad by its ID
+// GetByID retrieves a squad by its ID, or nil if it doesn't exist
 func (r *SquadRepository) GetByID(ctx context.Context, id int64) (*models.Squad, error) {
-	query := `SELECT id, name, created_at FROM squads WHERE id = $1`
+	ctx = withQueryName(ctx, "SquadRepository.GetByID")
+	query := `SELECT ` + squadColumns + ` FROM squads s WHERE s.id = $1 AND ` + orgCondition("s.org_id", "$2")
 	var squad models.Squad
-	err := r.pool.QueryRow(ctx, query, id).Scan(&squad.ID, &squad.Name, &squad.CreatedAt)
+	err := r.pool.QueryRow(ctx, query, id, orgScope(ctx)).Scan(&squad.ID, &squad.Name, &squad.LeadID, &squad.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get squad by ID: %w", err)
 	}
@@ -53,20 +71,26 @@ func (r *SquadRepository) GetByID(ctx context.Context, id int64) (*models.Squad,
 
 // GetByName retrieves a squad by its name
 func (r *SquadRepository) GetByName(ctx context.Context, name string) (*models.Squad, error) {
-	query := `SELECT id, name, created_at FROM squads WHERE name = $1`
+	ctx = withQueryName(ctx, "SquadRepository.GetByName")
+	query := `SELECT id, name, created_at FROM squads WHERE name = $1 AND ` + orgCondition("org_id", "$2")
 	var squad models.Squad
-	err := r.pool.QueryRow(ctx, query, name).Scan(&squad.ID, &squad.Name, &squad.CreatedAt)
+	err := r.pool.QueryRow(ctx, query, name, orgScope(ctx)).Scan(&squad.ID, &squad.Name, &squad.CreatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get squad by name: %w", err)
 	}
 	return &squad, nil
 }
 
-// Create creates a new squad
+// Create creates a new squad in the organization ctx is scoped to
 func (r *SquadRepository) Create(ctx context.Context, name string) (*models.Squad, error) {
-	query := `INSERT INTO squads (name) VALUES ($1) RETURNING id, name, created_at`
+	ctx = withQueryName(ctx, "SquadRepository.Create")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `INSERT INTO squads (name, org_id) VALUES ($1, $2) RETURNING id, name, created_at`
 	var squad models.Squad
-	err := r.pool.QueryRow(ctx, query, name).Scan(&squad.ID, &squad.Name, &squad.CreatedAt)
+	err = r.pool.QueryRow(ctx, query, name, orgID).Scan(&squad.ID, &squad.Name, &squad.CreatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create squad: %w", err)
 	}
@@ -75,8 +99,9 @@ func (r *SquadRepository) Create(ctx context.Context, name string) (*models.Squa
 
 // GetByUserID retrieves all squads for a given user
 func (r *SquadRepository) GetByUserID(ctx context.Context, userID int64) ([]models.Squad, error) {
+	ctx = withQueryName(ctx, "SquadRepository.GetByUserID")
 	query := `
-		SELECT s.id, s.name, s.created_at
+		SELECT s.id, s.name, us.role, s.created_at
 		FROM squads s
 		JOIN user_squads us ON us.squad_id = s.id
 		WHERE us.user_id = $1
@@ -91,7 +116,7 @@ func (r *SquadRepository) GetByUserID(ctx context.Context, userID int64) ([]mode
 	var squads []models.Squad
 	for rows.Next() {
 		var squad models.Squad
-		err := rows.Scan(&squad.ID, &squad.Name, &squad.CreatedAt)
+		err := rows.Scan(&squad.ID, &squad.Name, &squad.Role, &squad.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan squad: %w", err)
 		}
@@ -103,18 +128,32 @@ func (r *SquadRepository) GetByUserID(ctx context.Context, userID int64) ([]mode
 // GetByUserIDs retrieves all squads for multiple users in a single query
 // Returns a map of userID -> []Squad
 func (r *SquadRepository) GetByUserIDs(ctx context.Context, userIDs []int64) (map[int64][]models.Squad, error) {
+	ctx = withQueryName(ctx, "SquadRepository.GetByUserIDs")
+	return r.getByUserIDs(ctx, r.pool, userIDs)
+}
+
+// GetByUserIDsWithTx retrieves squads for multiple users within an existing transaction
+func (r *SquadRepository) GetByUserIDsWithTx(ctx context.Context, tx pgx.Tx, userIDs []int64) (map[int64][]models.Squad, error) {
+	ctx = withQueryName(ctx, "SquadRepository.GetByUserIDsWithTx")
+	return r.getByUserIDs(ctx, tx, userIDs)
+}
+
+// getByUserIDs is the internal implementation that works with the pool or a transaction
+func (r *SquadRepository) getByUserIDs(ctx context.Context, q interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}, userIDs []int64) (map[int64][]models.Squad, error) {
 	if len(userIDs) == 0 {
 		return make(map[int64][]models.Squad), nil
 	}
 
 	query := `
-		SELECT us.user_id, s.id, s.name, s.created_at
+		SELECT us.user_id, s.id, s.name, us.role, s.created_at
 		FROM squads s
 		JOIN user_squads us ON us.squad_id = s.id
 		WHERE us.user_id = ANY($1)
 		ORDER BY us.user_id, s.name
 	`
-	rows, err := r.pool.Query(ctx, query, userIDs)
+	rows, err := q.Query(ctx, query, userIDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get squads for users: %w", err)
 	}
@@ -124,7 +163,7 @@ func (r *SquadRepository) GetByUserIDs(ctx context.Context, userIDs []int64) (ma
 	for rows.Next() {
 		var userID int64
 		var squad models.Squad
-		err := rows.Scan(&userID, &squad.ID, &squad.Name, &squad.CreatedAt)
+		err := rows.Scan(&userID, &squad.ID, &squad.Name, &squad.Role, &squad.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan squad: %w", err)
 		}
@@ -133,8 +172,10 @@ func (r *SquadRepository) GetByUserIDs(ctx context.Context, userIDs []int64) (ma
 	return result, nil
 }
 
-// SetUserSquads sets the squads for a user (replaces all existing squad memberships)
+// SetUserSquads sets the squads for a user (replaces all existing squad memberships).
+// The user keeps their role in squads they remain in.
 func (r *SquadRepository) SetUserSquads(ctx context.Context, userID int64, squadIDs []int64) error {
+	ctx = withQueryName(ctx, "SquadRepository.SetUserSquads")
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -153,13 +194,18 @@ func (r *SquadRepository) SetUserSquads(ctx context.Context, userID int64, squad
 
 // SetUserSquadsWithTx sets the squads for a user within an existing transaction
 func (r *SquadRepository) SetUserSquadsWithTx(ctx context.Context, tx pgx.Tx, userID int64, squadIDs []int64) error {
+	ctx = withQueryName(ctx, "SquadRepository.SetUserSquadsWithTx")
 	return r.setUserSquadsWithExecutor(ctx, tx, userID, squadIDs)
 }
 
 // setUserSquadsWithExecutor is the internal implementation that works with any executor
 func (r *SquadRepository) setUserSquadsWithExecutor(ctx context.Context, tx pgx.Tx, userID int64, squadIDs []int64) error {
-	// Delete existing squad memberships
-	_, err := tx.Exec(ctx, `DELETE FROM user_squads WHERE user_id = $1`, userID)
+	// Delete memberships of squads the user is leaving; a nil slice would be sent as NULL and match nothing
+	keep := squadIDs
+	if keep == nil {
+		keep = []int64{}
+	}
+	_, err := tx.Exec(ctx, `DELETE FROM user_squads WHERE user_id = $1 AND NOT (squad_id = ANY($2))`, userID, keep)
 	if err != nil {
 		return fmt.Errorf("failed to delete existing squad memberships: %w", err)
 	}
@@ -183,8 +229,83 @@ func (r *SquadRepository) setUserSquadsWithExecutor(ctx context.Context, tx pgx.
 	return nil
 }
 
+// SetLead makes the user the lead of a squad, adding them to it if needed.
+// Any previous lead stays in the squad as a member.
+func (r *SquadRepository) SetLead(ctx context.Context, squadID, userID int64) error {
+	ctx = withQueryName(ctx, "SquadRepository.SetLead")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+		UPDATE user_squads SET role = 'member'
+		WHERE squad_id = $1 AND role = 'lead' AND user_id <> $2
+	`, squadID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to demote previous squad lead: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_squads (user_id, squad_id, role) VALUES ($1, $2, 'lead')
+		ON CONFLICT (user_id, squad_id) DO UPDATE SET role = 'lead'
+	`, userID, squadID)
+	if err != nil {
+		return fmt.Errorf("failed to set squad lead: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ClearLead makes a squad's lead, if it has one, a regular member
+func (r *SquadRepository) ClearLead(ctx context.Context, squadID int64) error {
+	ctx = withQueryName(ctx, "SquadRepository.ClearLead")
+	_, err := r.pool.Exec(ctx, `UPDATE user_squads SET role = 'member' WHERE squad_id = $1 AND role = 'lead'`, squadID)
+	if err != nil {
+		return fmt.Errorf("failed to clear squad lead: %w", err)
+	}
+	return nil
+}
+
+// GetLedSquadIDs retrieves the IDs of the squads a user leads
+func (r *SquadRepository) GetLedSquadIDs(ctx context.Context, userID int64) ([]int64, error) {
+	ctx = withQueryName(ctx, "SquadRepository.GetLedSquadIDs")
+	query := `SELECT squad_id FROM user_squads WHERE user_id = $1 AND role = 'lead' ORDER BY squad_id`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get led squad IDs: %w", err)
+	}
+	defer rows.Close()
+
+	var squadIDs []int64
+	for rows.Next() {
+		var squadID int64
+		if err := rows.Scan(&squadID); err != nil {
+			return nil, fmt.Errorf("failed to scan squad ID: %w", err)
+		}
+		squadIDs = append(squadIDs, squadID)
+	}
+	return squadIDs, nil
+}
+
+// IsSquadLeadOf reports whether leadID leads a squad that memberID belongs to
+func (r *SquadRepository) IsSquadLeadOf(ctx context.Context, leadID, memberID int64) (bool, error) {
+	ctx = withQueryName(ctx, "SquadRepository.IsSquadLeadOf")
+	query := `SELECT EXISTS (` + squadLeadMembersQuery("$1") + ` AND m.user_id = $2)`
+	var isLead bool
+	if err := r.pool.QueryRow(ctx, query, leadID, memberID).Scan(&isLead); err != nil {
+		return false, fmt.Errorf("failed to check squad lead: %w", err)
+	}
+	return isLead, nil
+}
+
 // GetSquadIDsByUserID retrieves squad IDs for a given user
 func (r *SquadRepository) GetSquadIDsByUserID(ctx context.Context, userID int64) ([]int64, error) {
+	ctx = withQueryName(ctx, "SquadRepository.GetSquadIDsByUserID")
 	query := `SELECT squad_id FROM user_squads WHERE user_id = $1 ORDER BY squad_id`
 	rows, err := r.pool.Query(ctx, query, userID)
 	if err != nil {
@@ -206,12 +327,13 @@ func (r *SquadRepository) GetSquadIDsByUserID(ctx context.Context, userID int64)
 
 // GetSquadsByIDs retrieves squads by their IDs
 func (r *SquadRepository) GetSquadsByIDs(ctx context.Context, squadIDs []int64) ([]models.Squad, error) {
+	ctx = withQueryName(ctx, "SquadRepository.GetSquadsByIDs")
 	if len(squadIDs) == 0 {
 		return []models.Squad{}, nil
 	}
 
-	query := `SELECT id, name, created_at FROM squads WHERE id = ANY($1) ORDER BY name`
-	rows, err := r.pool.Query(ctx, query, squadIDs)
+	query := `SELECT id, name, created_at FROM squads WHERE id = ANY($1) AND ` + orgCondition("org_id", "$2") + ` ORDER BY name`
+	rows, err := r.pool.Query(ctx, query, squadIDs, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get squads by IDs: %w", err)
 	}
@@ -231,7 +353,8 @@ func (r *SquadRepository) GetSquadsByIDs(ctx context.Context, squadIDs []int64)
 
 // Delete removes a squad by ID (user_squads entries cascade delete automatically)
 func (r *SquadRepository) Delete(ctx context.Context, id int64) error {
-	result, err := r.pool.Exec(ctx, `DELETE FROM squads WHERE id = $1`, id)
+	ctx = withQueryName(ctx, "SquadRepository.Delete")
+	result, err := r.pool.Exec(ctx, `DELETE FROM squads WHERE id = $1 AND `+orgCondition("org_id", "$2"), id, orgScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to delete squad: %w", err)
 	}
@@ -243,9 +366,10 @@ func (r *SquadRepository) Delete(ctx context.Context, id int64) error {
 
 // Rename updates the name of an existing squad
 func (r *SquadRepository) Rename(ctx context.Context, id int64, newName string) (*models.Squad, error) {
-	query := `UPDATE squads SET name = $1 WHERE id = $2 RETURNING id, name, created_at`
+	ctx = withQueryName(ctx, "SquadRepository.Rename")
+	query := `UPDATE squads SET name = $1 WHERE id = $2 AND ` + orgCondition("org_id", "$3") + ` RETURNING id, name, created_at`
 	var squad models.Squad
-	err := r.pool.QueryRow(ctx, query, newName, id).Scan(&squad.ID, &squad.Name, &squad.CreatedAt)
+	err := r.pool.QueryRow(ctx, query, newName, id, orgScope(ctx)).Scan(&squad.ID, &squad.Name, &squad.CreatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to rename squad: %w", err)
 	}
@@ -254,6 +378,7 @@ func (r *SquadRepository) Rename(ctx context.Context, id int64, newName string)
 
 // GetUsersBySquadID returns all active users in a specific squad
 func (r *SquadRepository) GetUsersBySquadID(ctx context.Context, squadID int64) ([]models.User, error) {
+	ctx = withQueryName(ctx, "SquadRepository.GetUsersBySquadID")
 	query := `
 		SELECT u.id, COALESCE(u.auth0_id, ''), u.email, u.first_name, u.last_name, u.role, u.title, u.department,
 			u.avatar_url, u.supervisor_id, u.date_started, u.is_active, u.created_at, u.updated_at, u.jira_account_id
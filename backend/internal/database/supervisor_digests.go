@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// SupervisorDigestRepository records which digests have been queued for supervisors
+type SupervisorDigestRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSupervisorDigestRepository creates a new supervisor digest repository
+func NewSupervisorDigestRepository(pool *pgxpool.Pool) *SupervisorDigestRepository {
+	return &SupervisorDigestRepository{pool: pool}
+}
+
+// Queue queues a supervisor's digest email for its period unless one already was, and reports
+// whether it queued it. The record and the email are saved together, so a digest is sent exactly
+// once however many instances try.
+func (r *SupervisorDigestRepository) Queue(ctx context.Context, payload *models.SupervisorDigestEmailPayload) (bool, error) {
+	ctx = withQueryName(ctx, "SupervisorDigestRepository.Queue")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO supervisor_digests (supervisor_id, frequency, period_start)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, payload.SupervisorID, payload.Digest.Frequency, payload.Digest.PeriodStart.Format(time.DateOnly))
+	if err != nil {
+		return false, fmt.Errorf("failed to record supervisor digest: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := enqueueOutbox(ctx, tx, models.OutboxKindSupervisorDigest, payload); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
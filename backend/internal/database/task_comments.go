@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const taskCommentColumns = `id, task_id, author_id, body, created_at, updated_at`
+
+const taskActivityColumns = `id, task_id, actor_id, from_status, to_status, created_at`
+
+type TaskCommentRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTaskCommentRepository(pool *pgxpool.Pool) *TaskCommentRepository {
+	return &TaskCommentRepository{pool: pool}
+}
+
+// scanTaskComment scans a row of taskCommentColumns into a TaskComment
+func scanTaskComment(row pgx.Row) (*models.TaskComment, error) {
+	var c models.TaskComment
+	if err := row.Scan(&c.ID, &c.TaskID, &c.AuthorID, &c.Body, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// scanTaskActivity scans a row of taskActivityColumns into a TaskActivity
+func scanTaskActivity(row pgx.Row) (*models.TaskActivity, error) {
+	var a models.TaskActivity
+	if err := row.Scan(&a.ID, &a.TaskID, &a.ActorID, &a.FromStatus, &a.ToStatus, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Create posts a comment on a task
+func (r *TaskCommentRepository) Create(ctx context.Context, taskID, authorID int64, body string) (*models.TaskComment, error) {
+	ctx = withQueryName(ctx, "TaskCommentRepository.Create")
+	query := `
+		INSERT INTO task_comments (task_id, author_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING ` + taskCommentColumns
+
+	comment, err := scanTaskComment(r.pool.QueryRow(ctx, query, taskID, authorID, body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task comment: %w", err)
+	}
+	return comment, nil
+}
+
+// GetByID retrieves a task comment by ID
+func (r *TaskCommentRepository) GetByID(ctx context.Context, id int64) (*models.TaskComment, error) {
+	ctx = withQueryName(ctx, "TaskCommentRepository.GetByID")
+	query := `SELECT ` + taskCommentColumns + ` FROM task_comments WHERE id = $1`
+
+	comment, err := scanTaskComment(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task comment: %w", err)
+	}
+	return comment, nil
+}
+
+// ListByTask retrieves a task's comments, oldest first
+func (r *TaskCommentRepository) ListByTask(ctx context.Context, taskID int64) ([]models.TaskComment, error) {
+	ctx = withQueryName(ctx, "TaskCommentRepository.ListByTask")
+	query := `
+		SELECT ` + taskCommentColumns + `
+		FROM task_comments
+		WHERE task_id = $1
+		ORDER BY created_at, id`
+
+	rows, err := r.pool.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []models.TaskComment{}
+	for rows.Next() {
+		comment, err := scanTaskComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task comment: %w", err)
+		}
+		comments = append(comments, *comment)
+	}
+	return comments, rows.Err()
+}
+
+// Update replaces the body of a task comment
+func (r *TaskCommentRepository) Update(ctx context.Context, id int64, body string) (*models.TaskComment, error) {
+	ctx = withQueryName(ctx, "TaskCommentRepository.Update")
+	query := `
+		UPDATE task_comments SET body = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + taskCommentColumns
+
+	comment, err := scanTaskComment(r.pool.QueryRow(ctx, query, id, body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task comment: %w", err)
+	}
+	return comment, nil
+}
+
+// Delete deletes a task comment
+func (r *TaskCommentRepository) Delete(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "TaskCommentRepository.Delete")
+	result, err := r.pool.Exec(ctx, `DELETE FROM task_comments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete task comment: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("task comment not found")
+	}
+	return nil
+}
+
+// RecordStatusChange adds a status change to a task's activity feed
+func (r *TaskCommentRepository) RecordStatusChange(ctx context.Context, taskID, actorID int64, from, to models.TaskStatus) (*models.TaskActivity, error) {
+	ctx = withQueryName(ctx, "TaskCommentRepository.RecordStatusChange")
+	query := `
+		INSERT INTO task_activity (task_id, actor_id, from_status, to_status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + taskActivityColumns
+
+	activity, err := scanTaskActivity(r.pool.QueryRow(ctx, query, taskID, actorID, from, to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record task status change: %w", err)
+	}
+	return activity, nil
+}
+
+// ListActivity retrieves a task's status changes, oldest first
+func (r *TaskCommentRepository) ListActivity(ctx context.Context, taskID int64) ([]models.TaskActivity, error) {
+	ctx = withQueryName(ctx, "TaskCommentRepository.ListActivity")
+	query := `
+		SELECT ` + taskActivityColumns + `
+		FROM task_activity
+		WHERE task_id = $1
+		ORDER BY created_at, id`
+
+	rows, err := r.pool.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task activity: %w", err)
+	}
+	defer rows.Close()
+
+	activity := []models.TaskActivity{}
+	for rows.Next() {
+		entry, err := scanTaskActivity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task activity: %w", err)
+		}
+		activity = append(activity, *entry)
+	}
+	return activity, rows.Err()
+}
@@ -3,16 +3,18 @@ package database
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 )
 
-const taskColumns = `id, title, description, status, due_date, start_time, end_time, all_day,
-	created_by_id, assignment_type, assigned_user_id, assigned_squad_id, assigned_department,
-	created_at, updated_at`
+const taskColumns = `id, title, description, status, priority, labels, due_date, start_time, end_time, all_day,
+	created_by_id, assignment_type, assigned_user_id, assigned_squad_id, assigned_department, meeting_id,
+	recurrence_type, recurrence_interval, recurrence_end_date, recurrence_parent_id, created_at, updated_at`
 
 type TaskRepository struct {
 	pool *pgxpool.Pool
@@ -25,16 +27,22 @@ func NewTaskRepository(pool *pgxpool.Pool) *TaskRepository {
 // scanTask scans a row into a Task struct
 func scanTask(row pgx.Row) (*models.Task, error) {
 	var task models.Task
+	var recurrenceType *string
 	err := row.Scan(
-		&task.ID, &task.Title, &task.Description, &task.Status, &task.DueDate,
+		&task.ID, &task.Title, &task.Description, &task.Status, &task.Priority, &task.Labels, &task.DueDate,
 		&task.StartTime, &task.EndTime, &task.AllDay,
 		&task.CreatedByID, &task.AssignmentType, &task.AssignedUserID,
-		&task.AssignedSquadID, &task.AssignedDepartment,
+		&task.AssignedSquadID, &task.AssignedDepartment, &task.MeetingID,
+		&recurrenceType, &task.RecurrenceInterval, &task.RecurrenceEndDate, &task.RecurrenceParentID,
 		&task.CreatedAt, &task.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if recurrenceType != nil {
+		rt := models.RecurrenceType(*recurrenceType)
+		task.RecurrenceType = &rt
+	}
 	return &task, nil
 }
 
@@ -43,49 +51,201 @@ func scanTasks(rows pgx.Rows) ([]models.Task, error) {
 	var tasks []models.Task
 	for rows.Next() {
 		var task models.Task
+		var recurrenceType *string
 		err := rows.Scan(
-			&task.ID, &task.Title, &task.Description, &task.Status, &task.DueDate,
+			&task.ID, &task.Title, &task.Description, &task.Status, &task.Priority, &task.Labels, &task.DueDate,
 			&task.StartTime, &task.EndTime, &task.AllDay,
 			&task.CreatedByID, &task.AssignmentType, &task.AssignedUserID,
-			&task.AssignedSquadID, &task.AssignedDepartment,
+			&task.AssignedSquadID, &task.AssignedDepartment, &task.MeetingID,
+			&recurrenceType, &task.RecurrenceInterval, &task.RecurrenceEndDate, &task.RecurrenceParentID,
 			&task.CreatedAt, &task.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if recurrenceType != nil {
+			rt := models.RecurrenceType(*recurrenceType)
+			task.RecurrenceType = &rt
+		}
 		tasks = append(tasks, task)
 	}
 	return tasks, nil
 }
 
-// Create creates a new task
-func (r *TaskRepository) Create(ctx context.Context, req *models.CreateTaskRequest, createdByID int64) (*models.Task, error) {
+// insertTaskQuery inserts a task from taskInsertArgs, in its creator's organization, and returns it
+const insertTaskQuery = `
+		INSERT INTO tasks (title, description, priority, labels, due_date, start_time, end_time, all_day,
+			created_by_id, assignment_type, assigned_user_id, assigned_squad_id, assigned_department,
+			recurrence_type, recurrence_interval, recurrence_end_date, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+			(SELECT org_id FROM users WHERE id = $9))
+		RETURNING ` + taskColumns
+
+// taskInsertArgs returns the arguments for insertTaskQuery
+func taskInsertArgs(req *models.CreateTaskRequest, createdByID int64) []interface{} {
 	// Default all_day to true if not specified
 	allDay := true
 	if req.AllDay != nil {
 		allDay = *req.AllDay
 	}
 
-	query := `
-		INSERT INTO tasks (title, description, due_date, start_time, end_time, all_day,
-			created_by_id, assignment_type, assigned_user_id, assigned_squad_id, assigned_department)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-		RETURNING ` + taskColumns
+	// Requests that skipped validation still get the column defaults
+	priority := req.Priority
+	if priority == "" {
+		priority = models.TaskPriorityMedium
+	}
+	labels := req.Labels
+	if labels == nil {
+		labels = []string{}
+	}
 
-	task, err := scanTask(r.pool.QueryRow(ctx, query,
-		req.Title, req.Description, req.DueDate, req.StartTime, req.EndTime, allDay,
+	var recurrenceType *string
+	recurrenceInterval := 1
+	if req.RecurrenceType != nil {
+		rt := string(*req.RecurrenceType)
+		recurrenceType = &rt
+		if req.RecurrenceInterval != nil {
+			recurrenceInterval = *req.RecurrenceInterval
+		}
+	}
+
+	return []interface{}{
+		req.Title, req.Description, priority, labels, req.DueDate, req.StartTime, req.EndTime, allDay,
 		createdByID, req.AssignmentType, req.AssignedUserID, req.AssignedSquadID, req.AssignedDepartment,
-	))
+		recurrenceType, recurrenceInterval, req.RecurrenceEndDate,
+	}
+}
+
+// Create creates a new task
+func (r *TaskRepository) Create(ctx context.Context, req *models.CreateTaskRequest, createdByID int64) (*models.Task, error) {
+	ctx = withQueryName(ctx, "TaskRepository.Create")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	task, err := scanTask(tx.QueryRow(ctx, insertTaskQuery, taskInsertArgs(req, createdByID)...))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
+	if err := enqueueWebhooks(ctx, tx, events.TaskCreated, task); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return task, nil
 }
 
+// CreateMany creates the tasks in one transaction, in order; if any insert fails none are created.
+// Their webhook deliveries are queued in the same transaction.
+func (r *TaskRepository) CreateMany(ctx context.Context, reqs []models.CreateTaskRequest, createdByID int64) ([]models.Task, error) {
+	ctx = withQueryName(ctx, "TaskRepository.CreateMany")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tasks := make([]models.Task, 0, len(reqs))
+	for i := range reqs {
+		task, err := scanTask(tx.QueryRow(ctx, insertTaskQuery, taskInsertArgs(&reqs[i], createdByID)...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create task %d: %w", i, err)
+		}
+		if err := enqueueWebhooks(ctx, tx, events.TaskCreated, task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return tasks, nil
+}
+
+// Reassign moves open tasks assigned to one user over to another in one transaction. The tasks are
+// locked and checked with models.ReassignmentErrors first; if anything is reported, or the new
+// assignee isn't an active user, nothing moves and the result carries the errors.
+func (r *TaskRepository) Reassign(ctx context.Context, req *models.ReassignTasksRequest, actor *models.User) (*models.BulkTaskResult, error) {
+	ctx = withQueryName(ctx, "TaskRepository.Reassign")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var targetActive bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND is_active AND `+orgCondition("org_id", "$2")+`)`,
+		req.ToUserID, orgScope(ctx)).Scan(&targetActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check new assignee: %w", err)
+	}
+	if !targetActive {
+		return &models.BulkTaskResult{
+			Tasks:  []models.Task{},
+			Errors: []models.BulkTaskError{{Error: "to_user_id must be an active user"}},
+		}, nil
+	}
+
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE assignment_type = 'user' AND assigned_user_id = $1
+		AND status IN ('pending', 'in_progress')
+		AND deleted_at IS NULL
+		AND ($2::bigint[] IS NULL OR id = ANY($2))
+		AND ` + orgCondition("org_id", "$3") + `
+		ORDER BY id
+		FOR UPDATE`
+	var taskIDs []int64
+	if len(req.TaskIDs) > 0 {
+		taskIDs = req.TaskIDs
+	}
+	rows, err := tx.Query(ctx, query, req.FromUserID, taskIDs, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open tasks: %w", err)
+	}
+	tasks, err := scanTasks(rows)
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan tasks: %w", err)
+	}
+
+	if errs := models.ReassignmentErrors(tasks, req.TaskIDs, actor); len(errs) > 0 {
+		return &models.BulkTaskResult{Tasks: []models.Task{}, Errors: errs}, nil
+	}
+
+	moved := make([]models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		updated, err := scanTask(tx.QueryRow(ctx, `
+			UPDATE tasks SET assigned_user_id = $2, updated_at = NOW()
+			WHERE id = $1
+			RETURNING `+taskColumns,
+			task.ID, req.ToUserID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to reassign task %d: %w", task.ID, err)
+		}
+		if err := enqueueWebhooks(ctx, tx, events.TaskUpdated, updated); err != nil {
+			return nil, err
+		}
+		moved = append(moved, *updated)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &models.BulkTaskResult{Tasks: moved, Errors: []models.BulkTaskError{}}, nil
+}
+
 // GetByID retrieves a task by ID
 func (r *TaskRepository) GetByID(ctx context.Context, id int64) (*models.Task, error) {
-	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1`
-	task, err := scanTask(r.pool.QueryRow(ctx, query, id))
+	ctx = withQueryName(ctx, "TaskRepository.GetByID")
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NULL AND ` + orgCondition("org_id", "$2")
+	task, err := scanTask(r.pool.QueryRow(ctx, query, id, orgScope(ctx)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get task by ID: %w", err)
 	}
@@ -94,6 +254,7 @@ func (r *TaskRepository) GetByID(ctx context.Context, id int64) (*models.Task, e
 
 // Update updates a task
 func (r *TaskRepository) Update(ctx context.Context, id int64, req *models.UpdateTaskRequest) (*models.Task, error) {
+	ctx = withQueryName(ctx, "TaskRepository.Update")
 	query := `
 		UPDATE tasks SET
 			title = COALESCE($2, title),
@@ -107,8 +268,13 @@ func (r *TaskRepository) Update(ctx context.Context, id int64, req *models.Updat
 			assigned_user_id = COALESCE($10, assigned_user_id),
 			assigned_squad_id = COALESCE($11, assigned_squad_id),
 			assigned_department = COALESCE($12, assigned_department),
+			priority = COALESCE($13, priority),
+			labels = COALESCE($14, labels),
+			recurrence_type = COALESCE($15, recurrence_type),
+			recurrence_interval = COALESCE($16, recurrence_interval),
+			recurrence_end_date = COALESCE($17, recurrence_end_date),
 			updated_at = NOW()
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL AND ` + orgCondition("org_id", "$18") + `
 		RETURNING ` + taskColumns
 
 	var status *string
@@ -122,31 +288,175 @@ func (r *TaskRepository) Update(ctx context.Context, id int64, req *models.Updat
 		assignmentType = &a
 	}
 
-	task, err := scanTask(r.pool.QueryRow(ctx, query,
+	var priority *string
+	if req.Priority != nil {
+		p := string(*req.Priority)
+		priority = &p
+	}
+	var recurrenceType *string
+	if req.RecurrenceType != nil {
+		rt := string(*req.RecurrenceType)
+		recurrenceType = &rt
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	task, err := scanTask(tx.QueryRow(ctx, query,
 		id, req.Title, req.Description, status, req.DueDate,
 		req.StartTime, req.EndTime, req.AllDay,
 		assignmentType, req.AssignedUserID, req.AssignedSquadID, req.AssignedDepartment,
+		priority, req.Labels, recurrenceType, req.RecurrenceInterval, req.RecurrenceEndDate,
+		orgScope(ctx),
 	))
 	if err != nil {
 		return nil, fmt.Errorf("failed to update task: %w", err)
 	}
+	if err := enqueueWebhooks(ctx, tx, events.TaskUpdated, task); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return task, nil
 }
 
-// Delete deletes a task
+// AdvanceRecurrence rolls a recurring series forward once its current instance is finished, generating
+// the instance that follows. The recurrence moves to the new instance so a series is only advanced once.
+// It returns the finished instance and the next one, which is nil once the series has ended; both are
+// nil when the task doesn't recur or was already advanced.
+func (r *TaskRepository) AdvanceRecurrence(ctx context.Context, id int64) (*models.Task, *models.Task, error) {
+	ctx = withQueryName(ctx, "TaskRepository.AdvanceRecurrence")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Lock the instance so concurrent updates can't generate duplicates
+	task, err := scanTask(tx.QueryRow(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = $1 AND recurrence_type IS NOT NULL AND deleted_at IS NULL FOR UPDATE`, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get recurring task: %w", err)
+	}
+
+	finished, err := scanTask(tx.QueryRow(ctx, `
+		UPDATE tasks SET recurrence_type = NULL, recurrence_end_date = NULL, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+taskColumns, id))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to finish recurring task: %w", err)
+	}
+	if err := enqueueWebhooks(ctx, tx, events.TaskUpdated, finished); err != nil {
+		return nil, nil, err
+	}
+
+	var next *models.Task
+	if instance := task.NextInstance(); instance != nil {
+		next, err = scanTask(tx.QueryRow(ctx, `
+			INSERT INTO tasks (title, description, priority, labels, due_date, start_time, end_time, all_day,
+				created_by_id, assignment_type, assigned_user_id, assigned_squad_id, assigned_department,
+				recurrence_type, recurrence_interval, recurrence_end_date, recurrence_parent_id, org_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+				(SELECT org_id FROM tasks WHERE id = $17))
+			RETURNING `+taskColumns,
+			instance.Title, instance.Description, instance.Priority, instance.Labels, instance.DueDate,
+			instance.StartTime, instance.EndTime, instance.AllDay, instance.CreatedByID, instance.AssignmentType,
+			instance.AssignedUserID, instance.AssignedSquadID, instance.AssignedDepartment,
+			string(*instance.RecurrenceType), instance.RecurrenceInterval, instance.RecurrenceEndDate, instance.RecurrenceParentID,
+		))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create next recurring task: %w", err)
+		}
+		if err := enqueueWebhooks(ctx, tx, events.TaskCreated, next); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return finished, next, nil
+}
+
+// Delete soft-deletes a task; it is hidden until restored and purged once the retention period passes
 func (r *TaskRepository) Delete(ctx context.Context, id int64) error {
-	result, err := r.pool.Exec(ctx, `DELETE FROM tasks WHERE id = $1`, id)
+	ctx = withQueryName(ctx, "TaskRepository.Delete")
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete task: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	if result.RowsAffected() == 0 {
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	task, err := scanTask(tx.QueryRow(ctx, `
+		UPDATE tasks SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND `+orgCondition("org_id", "$2")+`
+		RETURNING `+taskColumns, id, orgScope(ctx)))
+	if err == pgx.ErrNoRows {
 		return fmt.Errorf("task not found")
 	}
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	if err := enqueueWebhooks(ctx, tx, events.TaskDeleted, task); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
+// Restore brings back a soft-deleted task
+func (r *TaskRepository) Restore(ctx context.Context, id int64) (*models.Task, error) {
+	ctx = withQueryName(ctx, "TaskRepository.Restore")
+	query := `
+		UPDATE tasks SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL AND ` + orgCondition("org_id", "$2") + `
+		RETURNING ` + taskColumns
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	task, err := scanTask(tx.QueryRow(ctx, query, id, orgScope(ctx)))
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("deleted task not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore task: %w", err)
+	}
+	if err := enqueueWebhooks(ctx, tx, events.TaskCreated, task); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return task, nil
+}
+
+// PurgeDeleted permanently removes tasks soft-deleted before the cutoff, returning how many were removed
+func (r *TaskRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withQueryName(ctx, "TaskRepository.PurgeDeleted")
+	result, err := r.pool.Exec(ctx, `DELETE FROM tasks WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted tasks: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
 // GetByDateRange retrieves tasks within a date range for a user
 func (r *TaskRepository) GetByDateRange(ctx context.Context, userID int64, start, end time.Time) ([]models.Task, error) {
+	ctx = withQueryName(ctx, "TaskRepository.GetByDateRange")
 	query := `
 		SELECT ` + taskColumns + `
 		FROM tasks
@@ -155,9 +465,11 @@ func (r *TaskRepository) GetByDateRange(ctx context.Context, userID int64, start
 			created_by_id = $3
 			OR assigned_user_id = $3
 		)
+		AND deleted_at IS NULL
+		AND ` + orgCondition("org_id", "$4") + `
 		ORDER BY due_date`
 
-	rows, err := r.pool.Query(ctx, query, start, end, userID)
+	rows, err := r.pool.Query(ctx, query, start, end, userID, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks by date range: %w", err)
 	}
@@ -170,16 +482,54 @@ func (r *TaskRepository) GetByDateRange(ctx context.Context, userID int64, start
 	return tasks, nil
 }
 
+// CountOpenByAssigneeIDs counts pending and in-progress tasks assigned directly to multiple users in a
+// single query. Returns a map of userID -> count; users without open tasks are omitted
+func (r *TaskRepository) CountOpenByAssigneeIDs(ctx context.Context, userIDs []int64) (map[int64]int, error) {
+	ctx = withQueryName(ctx, "TaskRepository.CountOpenByAssigneeIDs")
+	result := make(map[int64]int)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT assigned_user_id, COUNT(*)
+		FROM tasks
+		WHERE assigned_user_id = ANY($1)
+		AND status IN ('pending', 'in_progress')
+		AND deleted_at IS NULL
+		AND ` + orgCondition("org_id", "$2") + `
+		GROUP BY assigned_user_id`
+
+	rows, err := r.pool.Query(ctx, query, userIDs, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int64
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan open task count: %w", err)
+		}
+		result[userID] = count
+	}
+	return result, rows.Err()
+}
+
 // GetByDateRangeForSquad retrieves tasks within a date range for a specific squad
 func (r *TaskRepository) GetByDateRangeForSquad(ctx context.Context, squadID int64, start, end time.Time) ([]models.Task, error) {
+	ctx = withQueryName(ctx, "TaskRepository.GetByDateRangeForSquad")
 	query := `
 		SELECT ` + taskColumns + `
 		FROM tasks
 		WHERE due_date >= $1 AND due_date <= $2
 		AND assigned_squad_id = $3
+		AND deleted_at IS NULL
+		AND ` + orgCondition("org_id", "$4") + `
 		ORDER BY due_date`
 
-	rows, err := r.pool.Query(ctx, query, start, end, squadID)
+	rows, err := r.pool.Query(ctx, query, start, end, squadID, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get squad tasks by date range: %w", err)
 	}
@@ -194,14 +544,17 @@ func (r *TaskRepository) GetByDateRangeForSquad(ctx context.Context, squadID int
 
 // GetByDateRangeForDepartment retrieves tasks within a date range for a department
 func (r *TaskRepository) GetByDateRangeForDepartment(ctx context.Context, department string, start, end time.Time) ([]models.Task, error) {
+	ctx = withQueryName(ctx, "TaskRepository.GetByDateRangeForDepartment")
 	query := `
 		SELECT ` + taskColumns + `
 		FROM tasks
 		WHERE due_date >= $1 AND due_date <= $2
 		AND assigned_department = $3
+		AND deleted_at IS NULL
+		AND ` + orgCondition("org_id", "$4") + `
 		ORDER BY due_date`
 
-	rows, err := r.pool.Query(ctx, query, start, end, department)
+	rows, err := r.pool.Query(ctx, query, start, end, department, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get department tasks by date range: %w", err)
 	}
@@ -214,15 +567,25 @@ func (r *TaskRepository) GetByDateRangeForDepartment(ctx context.Context, depart
 	return tasks, nil
 }
 
-// GetAllByDateRange retrieves all tasks within a date range (admin only)
+// taskDueInRange matches tasks due between $1 and $2, plus open recurring instances due before the
+// range whose later occurrences may fall inside it
+const taskDueInRange = `((due_date >= $1 AND due_date <= $2)
+		OR (recurrence_type IS NOT NULL AND due_date < $1 AND status IN ('pending', 'in_progress')
+			AND (recurrence_end_date IS NULL OR recurrence_end_date >= $1)))`
+
+// GetAllByDateRange retrieves all tasks within a date range (admin only), including open recurring
+// instances due earlier; ExpandRecurringTasks projects their occurrences into the range
 func (r *TaskRepository) GetAllByDateRange(ctx context.Context, start, end time.Time) ([]models.Task, error) {
+	ctx = withQueryName(ctx, "TaskRepository.GetAllByDateRange")
 	query := `
 		SELECT ` + taskColumns + `
 		FROM tasks
-		WHERE due_date >= $1 AND due_date <= $2
+		WHERE ` + taskDueInRange + `
+		AND deleted_at IS NULL
+		AND ` + orgCondition("org_id", "$3") + `
 		ORDER BY due_date`
 
-	rows, err := r.pool.Query(ctx, query, start, end)
+	rows, err := r.pool.Query(ctx, query, start, end, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all tasks by date range: %w", err)
 	}
@@ -235,8 +598,20 @@ func (r *TaskRepository) GetAllByDateRange(ctx context.Context, start, end time.
 	return tasks, nil
 }
 
-// GetVisibleTasks retrieves all tasks visible to a user within a date range
+// squadLeadTaskCondition matches the tasks a squad lead can see: those assigned to their squads or
+// to anyone in them. leadArg is the placeholder bound to the lead's user ID.
+func squadLeadTaskCondition(leadArg string) string {
+	return `(
+				(assignment_type = 'squad' AND assigned_squad_id IN (
+					SELECT squad_id FROM user_squads WHERE user_id = ` + leadArg + ` AND role = 'lead'))
+				OR assigned_user_id IN (` + squadLeadMembersQuery(leadArg) + `)
+			)`
+}
+
+// GetVisibleTasks retrieves all tasks visible to a user within a date range, including open recurring
+// instances due earlier; ExpandRecurringTasks projects their occurrences into the range
 func (r *TaskRepository) GetVisibleTasks(ctx context.Context, user *models.User, start, end time.Time) ([]models.Task, error) {
+	ctx = withQueryName(ctx, "TaskRepository.GetVisibleTasks")
 	// Admin sees all tasks
 	if user.IsAdmin() {
 		return r.GetAllByDateRange(ctx, start, end)
@@ -251,16 +626,19 @@ func (r *TaskRepository) GetVisibleTasks(ctx context.Context, user *models.User,
 	query := `
 		SELECT DISTINCT ` + taskColumns + `
 		FROM tasks
-		WHERE due_date >= $1 AND due_date <= $2
+		WHERE ` + taskDueInRange + `
 		AND (
 			created_by_id = $3
 			OR assigned_user_id = $3
 			OR (assignment_type = 'squad' AND assigned_squad_id = ANY($4))
 			OR (assignment_type = 'department' AND assigned_department = $5)
+			OR ` + squadLeadTaskCondition("$3") + `
 		)
+		AND deleted_at IS NULL
+		AND ` + orgCondition("org_id", "$6") + `
 		ORDER BY due_date`
 
-	rows, err := r.pool.Query(ctx, query, start, end, user.ID, squadIDs, user.Department)
+	rows, err := r.pool.Query(ctx, query, start, end, user.ID, squadIDs, user.Department, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get visible tasks: %w", err)
 	}
@@ -272,3 +650,101 @@ func (r *TaskRepository) GetVisibleTasks(ctx context.Context, user *models.User,
 	}
 	return tasks, nil
 }
+
+// ExpandRecurringTasks keeps the tasks due within a date range and adds the projected occurrences of
+// open recurring tasks that fall inside it
+func (r *TaskRepository) ExpandRecurringTasks(tasks []models.Task, start, end time.Time) []models.Task {
+	var expanded []models.Task
+	for i := range tasks {
+		task := &tasks[i]
+		if !task.DueDate.Before(start) && !task.DueDate.After(end) {
+			expanded = append(expanded, *task)
+		}
+		expanded = append(expanded, task.ProjectOccurrences(start, end)...)
+	}
+	return expanded
+}
+
+// taskPriorityRank orders priorities from least to most urgent for sorting
+const taskPriorityRank = `CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END`
+
+// taskSortColumn maps a sort field to its column, defaulting to due_date
+func taskSortColumn(field models.TaskSortField) string {
+	switch field {
+	case models.TaskSortPriority:
+		return taskPriorityRank
+	case models.TaskSortCreatedAt:
+		return "created_at"
+	case models.TaskSortUpdatedAt:
+		return "updated_at"
+	default:
+		return "due_date"
+	}
+}
+
+// List retrieves the tasks visible to a user that match the filter, regardless of date
+func (r *TaskRepository) List(ctx context.Context, user *models.User, filter models.TaskFilter) ([]models.Task, error) {
+	ctx = withQueryName(ctx, "TaskRepository.List")
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if orgID := orgScope(ctx); orgID != nil {
+		conditions = append(conditions, "org_id = "+arg(*orgID))
+	}
+
+	// Same visibility rules as GetVisibleTasks
+	if !user.IsAdmin() {
+		var squadIDs []int64
+		for _, squad := range user.Squads {
+			squadIDs = append(squadIDs, squad.ID)
+		}
+		userID := arg(user.ID)
+		conditions = append(conditions, `(
+			created_by_id = `+userID+`
+			OR assigned_user_id = `+userID+`
+			OR (assignment_type = 'squad' AND assigned_squad_id = ANY(`+arg(squadIDs)+`))
+			OR (assignment_type = 'department' AND assigned_department = `+arg(user.Department)+`)
+			OR `+squadLeadTaskCondition(userID)+`
+		)`)
+	}
+
+	if filter.Status != nil {
+		conditions = append(conditions, "status = "+arg(string(*filter.Status)))
+	}
+	if filter.Priority != nil {
+		conditions = append(conditions, "priority = "+arg(string(*filter.Priority)))
+	}
+	if filter.Label != nil {
+		conditions = append(conditions, arg(*filter.Label)+" = ANY(labels)")
+	}
+	if filter.AssigneeID != nil {
+		conditions = append(conditions, "assigned_user_id = "+arg(*filter.AssigneeID))
+	}
+	if filter.Overdue {
+		conditions = append(conditions, "due_date < CURRENT_DATE AND status NOT IN ('completed', 'cancelled')")
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks
+		WHERE ` + strings.Join(conditions, " AND ")
+	direction := "DESC"
+	if filter.SortAsc {
+		direction = "ASC"
+	}
+	query += fmt.Sprintf("\n\t\tORDER BY %s %s, id %s", taskSortColumn(filter.SortBy), direction, direction)
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan tasks: %w", err)
+	}
+	return tasks, nil
+}
@@ -2,14 +2,20 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 )
 
+const timeOffColumns = `id, user_id, start_date, end_date, request_type, reason, status, reviewer_id, reviewer_notes,
+	reviewed_at, created_at, updated_at`
+
 type TimeOffRepository struct {
 	db *pgxpool.Pool
 }
@@ -18,36 +24,59 @@ func NewTimeOffRepository(db *pgxpool.Pool) *TimeOffRepository {
 	return &TimeOffRepository{db: db}
 }
 
-// Create creates a new time off request
-func (r *TimeOffRepository) Create(ctx context.Context, userID int64, req *models.CreateTimeOffRequestInput) (*models.TimeOffRequest, error) {
-	startDate, _ := time.Parse("2006-01-02", req.StartDate)
-	endDate, _ := time.Parse("2006-01-02", req.EndDate)
-
+// scanTimeOff scans a row of timeOffColumns into a TimeOffRequest
+func scanTimeOff(row pgx.Row) (*models.TimeOffRequest, error) {
 	var timeOff models.TimeOffRequest
-	err := r.db.QueryRow(ctx, `
-		INSERT INTO time_off_requests (user_id, start_date, end_date, request_type, reason, status)
-		VALUES ($1, $2, $3, $4, $5, 'pending')
-		RETURNING id, user_id, start_date, end_date, request_type, reason, status, reviewer_id, reviewer_notes, reviewed_at, created_at, updated_at
-	`, userID, startDate, endDate, req.RequestType, req.Reason).Scan(
+	err := row.Scan(
 		&timeOff.ID, &timeOff.UserID, &timeOff.StartDate, &timeOff.EndDate,
 		&timeOff.RequestType, &timeOff.Reason, &timeOff.Status,
 		&timeOff.ReviewerID, &timeOff.ReviewerNotes, &timeOff.ReviewedAt,
 		&timeOff.CreatedAt, &timeOff.UpdatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create time off request: %w", err)
+		return nil, err
 	}
 	return &timeOff, nil
 }
 
+// Create creates a new time off request
+func (r *TimeOffRepository) Create(ctx context.Context, userID int64, req *models.CreateTimeOffRequestInput) (*models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.Create")
+	startDate, _ := time.Parse("2006-01-02", req.StartDate)
+	endDate, _ := time.Parse("2006-01-02", req.EndDate)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	timeOff, err := scanTimeOff(tx.QueryRow(ctx, `
+		INSERT INTO time_off_requests (user_id, start_date, end_date, request_type, reason, status, org_id)
+		VALUES ($1, $2, $3, $4, $5, 'pending', (SELECT org_id FROM users WHERE id = $1))
+		RETURNING `+timeOffColumns, userID, startDate, endDate, req.RequestType, req.Reason))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create time off request: %w", err)
+	}
+	if err := enqueueWebhooks(ctx, tx, events.TimeOffRequested, timeOff); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return timeOff, nil
+}
+
 // GetByID retrieves a time off request by ID
 func (r *TimeOffRepository) GetByID(ctx context.Context, id int64) (*models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetByID")
 	var timeOff models.TimeOffRequest
 	err := r.db.QueryRow(ctx, `
 		SELECT id, user_id, start_date, end_date, request_type, reason, status, reviewer_id, reviewer_notes, reviewed_at, created_at, updated_at
 		FROM time_off_requests
-		WHERE id = $1
-	`, id).Scan(
+		WHERE id = $1 AND `+orgCondition("org_id", "$2")+`
+	`, id, orgScope(ctx)).Scan(
 		&timeOff.ID, &timeOff.UserID, &timeOff.StartDate, &timeOff.EndDate,
 		&timeOff.RequestType, &timeOff.Reason, &timeOff.Status,
 		&timeOff.ReviewerID, &timeOff.ReviewerNotes, &timeOff.ReviewedAt,
@@ -64,6 +93,7 @@ func (r *TimeOffRepository) GetByID(ctx context.Context, id int64) (*models.Time
 
 // GetByIDWithUser retrieves a time off request by ID with user info
 func (r *TimeOffRepository) GetByIDWithUser(ctx context.Context, id int64) (*models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetByIDWithUser")
 	var timeOff models.TimeOffRequest
 	var user models.User
 	var reviewer models.User
@@ -73,16 +103,18 @@ func (r *TimeOffRepository) GetByIDWithUser(ctx context.Context, id int64) (*mod
 		SELECT
 			t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
 			t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
-			u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
+			u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url,
+			u.supervisor_id
 		FROM time_off_requests t
-		JOIN users u ON t.user_id = u.id
-		WHERE t.id = $1
-	`, id).Scan(
+		JOIN users u ON t.user_id = u.id AND u.deleted_at IS NULL
+		WHERE t.id = $1 AND `+orgCondition("t.org_id", "$2")+`
+	`, id, orgScope(ctx)).Scan(
 		&timeOff.ID, &timeOff.UserID, &timeOff.StartDate, &timeOff.EndDate,
 		&timeOff.RequestType, &timeOff.Reason, &timeOff.Status,
 		&reviewerID, &timeOff.ReviewerNotes, &timeOff.ReviewedAt,
 		&timeOff.CreatedAt, &timeOff.UpdatedAt,
 		&user.ID, &user.Email, &user.FirstName, &user.LastName, &user.Role, &user.Title, &user.Department, &user.AvatarURL,
+		&user.SupervisorID,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -113,6 +145,7 @@ func (r *TimeOffRepository) GetByIDWithUser(ctx context.Context, id int64) (*mod
 
 // GetByUserID retrieves time off requests for a user, optionally filtered by status
 func (r *TimeOffRepository) GetByUserID(ctx context.Context, userID int64, status *models.TimeOffStatus) ([]models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetByUserID")
 	var query string
 	var args []interface{}
 
@@ -160,13 +193,14 @@ func (r *TimeOffRepository) GetByUserID(ctx context.Context, userID int64, statu
 
 // GetPendingForSupervisor retrieves pending time off requests for a supervisor's direct reports
 func (r *TimeOffRepository) GetPendingForSupervisor(ctx context.Context, supervisorID int64) ([]models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetPendingForSupervisor")
 	rows, err := r.db.Query(ctx, `
 		SELECT
 			t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
 			t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
 			u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
 		FROM time_off_requests t
-		JOIN users u ON t.user_id = u.id
+		JOIN users u ON t.user_id = u.id AND u.deleted_at IS NULL
 		WHERE u.supervisor_id = $1 AND t.status = 'pending'
 		ORDER BY t.created_at ASC
 	`, supervisorID)
@@ -196,19 +230,53 @@ func (r *TimeOffRepository) GetPendingForSupervisor(ctx context.Context, supervi
 	return requests, nil
 }
 
+// CountPendingByUserIDs counts pending time off requests for multiple users in a single query
+// Returns a map of userID -> count; users without pending requests are omitted
+func (r *TimeOffRepository) CountPendingByUserIDs(ctx context.Context, userIDs []int64) (map[int64]int, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.CountPendingByUserIDs")
+	result := make(map[int64]int)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT user_id, COUNT(*)
+		FROM time_off_requests
+		WHERE user_id = ANY($1) AND status = 'pending'
+		AND `+orgCondition("org_id", "$2")+`
+		GROUP BY user_id
+	`, userIDs, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending time off requests: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int64
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan pending time off count: %w", err)
+		}
+		result[userID] = count
+	}
+	return result, rows.Err()
+}
+
 // GetAllApproved retrieves all approved time off requests (for admins viewing team time off)
 func (r *TimeOffRepository) GetAllApproved(ctx context.Context) ([]models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetAllApproved")
 	rows, err := r.db.Query(ctx, `
 		SELECT
 			t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
 			t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
 			u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
 		FROM time_off_requests t
-		JOIN users u ON t.user_id = u.id
+		JOIN users u ON t.user_id = u.id AND u.deleted_at IS NULL
 		WHERE t.status = 'approved'
 		AND t.end_date >= CURRENT_DATE
+		AND `+orgCondition("t.org_id", "$1")+`
 		ORDER BY t.start_date ASC
-	`)
+	`, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all approved time off requests: %w", err)
 	}
@@ -237,16 +305,18 @@ func (r *TimeOffRepository) GetAllApproved(ctx context.Context) ([]models.TimeOf
 
 // GetAllPending retrieves all pending time off requests (for admins)
 func (r *TimeOffRepository) GetAllPending(ctx context.Context) ([]models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetAllPending")
 	rows, err := r.db.Query(ctx, `
 		SELECT
 			t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
 			t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
 			u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
 		FROM time_off_requests t
-		JOIN users u ON t.user_id = u.id
+		JOIN users u ON t.user_id = u.id AND u.deleted_at IS NULL
 		WHERE t.status = 'pending'
+		AND `+orgCondition("t.org_id", "$1")+`
 		ORDER BY t.created_at ASC
-	`)
+	`, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all pending time off requests: %w", err)
 	}
@@ -275,39 +345,56 @@ func (r *TimeOffRepository) GetAllPending(ctx context.Context) ([]models.TimeOff
 
 // Review updates a time off request status (approve/reject)
 func (r *TimeOffRepository) Review(ctx context.Context, id int64, reviewerID int64, req *models.ReviewTimeOffRequestInput) error {
+	ctx = withQueryName(ctx, "TimeOffRepository.Review")
 	now := time.Now()
-	result, err := r.db.Exec(ctx, `
+	return r.changeStatus(ctx, events.TimeOffReviewed, "time off request not found or already reviewed", `
 		UPDATE time_off_requests
 		SET status = $1, reviewer_id = $2, reviewer_notes = $3, reviewed_at = $4, updated_at = $5
-		WHERE id = $6 AND status = 'pending'
-	`, req.Status, reviewerID, req.ReviewerNotes, now, now, id)
-	if err != nil {
-		return fmt.Errorf("failed to review time off request: %w", err)
-	}
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("time off request not found or already reviewed")
-	}
-	return nil
+		WHERE id = $6 AND status = 'pending' AND `+orgCondition("org_id", "$7")+`
+		RETURNING `+timeOffColumns,
+		req.Status, reviewerID, req.ReviewerNotes, now, now, id, orgScope(ctx))
 }
 
 // Cancel cancels a pending time off request
 func (r *TimeOffRepository) Cancel(ctx context.Context, id int64, userID int64) error {
-	result, err := r.db.Exec(ctx, `
+	ctx = withQueryName(ctx, "TimeOffRepository.Cancel")
+	return r.changeStatus(ctx, events.TimeOffCancelled, "time off request not found, not yours, or already processed", `
 		UPDATE time_off_requests
 		SET status = 'cancelled', updated_at = $1
 		WHERE id = $2 AND user_id = $3 AND status = 'pending'
-	`, time.Now(), id, userID)
+		RETURNING `+timeOffColumns,
+		time.Now(), id, userID)
+}
+
+// changeStatus runs an update of a pending request returning timeOffColumns, and queues webhooks
+// for the event in the same transaction. notFound is the error when no pending request matched.
+func (r *TimeOffRepository) changeStatus(ctx context.Context, event events.Type, notFound, query string, args ...any) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to cancel time off request: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("time off request not found, not yours, or already processed")
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	timeOff, err := scanTimeOff(tx.QueryRow(ctx, query, args...))
+	if err == pgx.ErrNoRows {
+		return errors.New(notFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update time off request: %w", err)
+	}
+	if err := enqueueWebhooks(ctx, tx, event, timeOff); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	return nil
 }
 
 // GetApprovedByDateRange retrieves approved time off requests for a user within a date range
 func (r *TimeOffRepository) GetApprovedByDateRange(ctx context.Context, userID int64, start, end time.Time) ([]models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetApprovedByDateRange")
 	rows, err := r.db.Query(ctx, `
 		SELECT id, user_id, start_date, end_date, request_type, reason, status, reviewer_id, reviewer_notes, reviewed_at, created_at, updated_at
 		FROM time_off_requests
@@ -342,6 +429,7 @@ func (r *TimeOffRepository) GetApprovedByDateRange(ctx context.Context, userID i
 
 // GetApprovedForUsers retrieves approved time off requests for multiple users within a date range
 func (r *TimeOffRepository) GetApprovedForUsers(ctx context.Context, userIDs []int64, start, end time.Time) ([]models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetApprovedForUsers")
 	if len(userIDs) == 0 {
 		return []models.TimeOffRequest{}, nil
 	}
@@ -352,7 +440,7 @@ func (r *TimeOffRepository) GetApprovedForUsers(ctx context.Context, userIDs []i
 			t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
 			u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
 		FROM time_off_requests t
-		JOIN users u ON t.user_id = u.id
+		JOIN users u ON t.user_id = u.id AND u.deleted_at IS NULL
 		WHERE t.user_id = ANY($1)
 		AND t.status = 'approved'
 		AND t.start_date <= $3
@@ -385,16 +473,18 @@ func (r *TimeOffRepository) GetApprovedForUsers(ctx context.Context, userIDs []i
 	return requests, nil
 }
 
-// GetTeamTimeOff retrieves approved time off for a supervisor's direct reports
+// GetTeamTimeOff retrieves approved time off for a supervisor's direct reports and, for squad leads,
+// everyone in the squads they lead
 func (r *TimeOffRepository) GetTeamTimeOff(ctx context.Context, supervisorID int64) ([]models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetTeamTimeOff")
 	rows, err := r.db.Query(ctx, `
 		SELECT
 			t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
 			t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
 			u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
 		FROM time_off_requests t
-		JOIN users u ON t.user_id = u.id
-		WHERE u.supervisor_id = $1
+		JOIN users u ON t.user_id = u.id AND u.deleted_at IS NULL
+		WHERE (u.supervisor_id = $1 OR (u.id <> $1 AND u.id IN (`+squadLeadMembersQuery("$1")+`)))
 		AND t.status = 'approved'
 		AND t.end_date >= CURRENT_DATE
 		ORDER BY t.start_date ASC
@@ -427,6 +517,7 @@ func (r *TimeOffRepository) GetTeamTimeOff(ctx context.Context, supervisorID int
 
 // GetApprovedFutureTimeOffByUser retrieves future approved time off for a specific user (for Jira impact calculation)
 func (r *TimeOffRepository) GetApprovedFutureTimeOffByUser(ctx context.Context, userID int64) ([]models.TimeOffRequest, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetApprovedFutureTimeOffByUser")
 	today := time.Now().Truncate(24 * time.Hour)
 	rows, err := r.db.Query(ctx, `
 		SELECT id, user_id, start_date, end_date, request_type, reason, status, reviewer_id, reviewer_notes, reviewed_at, created_at, updated_at
@@ -459,110 +550,98 @@ func (r *TimeOffRepository) GetApprovedFutureTimeOffByUser(ctx context.Context,
 	return requests, nil
 }
 
-// GetVisibleRequests returns time off requests visible to the user based on their role:
-// - Employees: only their own requests
-// - Supervisors: their own + their direct reports' requests
-// - Admins: their own + all users' requests (could be filtered to direct reports if preferred)
-func (r *TimeOffRepository) GetVisibleRequests(ctx context.Context, user *models.User, statusFilter *models.TimeOffStatus) ([]models.TimeOffRequest, error) {
-	var query string
-	var args []interface{}
+const timeOffWithUserColumns = `
+	t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
+	t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
+	u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url`
 
-	// Build status filter clause
-	statusClause := ""
-	if statusFilter != nil {
-		statusClause = " AND t.status = $2"
-	}
+// timeOffQuery assembles a filtered SELECT over time_off_requests joined with users
+type timeOffQuery struct {
+	conditions []string
+	args       []interface{}
+}
 
-	if user.Role == models.RoleEmployee {
-		// Employees see only their own
-		if statusFilter != nil {
-			query = `
-				SELECT
-					t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
-					t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
-					u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
-				FROM time_off_requests t
-				JOIN users u ON t.user_id = u.id
-				WHERE t.user_id = $1` + statusClause + `
-				ORDER BY t.start_date DESC
-			`
-			args = []interface{}{user.ID, *statusFilter}
-		} else {
-			query = `
-				SELECT
-					t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
-					t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
-					u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
-				FROM time_off_requests t
-				JOIN users u ON t.user_id = u.id
-				WHERE t.user_id = $1
-				ORDER BY t.start_date DESC
-			`
-			args = []interface{}{user.ID}
-		}
-	} else if user.Role == models.RoleSupervisor {
-		// Supervisors see their own + direct reports
-		if statusFilter != nil {
-			query = `
-				SELECT
-					t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
-					t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
-					u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
-				FROM time_off_requests t
-				JOIN users u ON t.user_id = u.id
-				WHERE (t.user_id = $1 OR u.supervisor_id = $1)` + statusClause + `
-				ORDER BY t.start_date DESC
-			`
-			args = []interface{}{user.ID, *statusFilter}
-		} else {
-			query = `
-				SELECT
-					t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
-					t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
-					u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
-				FROM time_off_requests t
-				JOIN users u ON t.user_id = u.id
-				WHERE (t.user_id = $1 OR u.supervisor_id = $1)
-				ORDER BY t.start_date DESC
-			`
-			args = []interface{}{user.ID}
-		}
-	} else {
-		// Admins see their own + direct reports (same as supervisor for now)
-		if statusFilter != nil {
-			query = `
-				SELECT
-					t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
-					t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
-					u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
-				FROM time_off_requests t
-				JOIN users u ON t.user_id = u.id
-				WHERE (t.user_id = $1 OR u.supervisor_id = $1)` + statusClause + `
-				ORDER BY t.start_date DESC
-			`
-			args = []interface{}{user.ID, *statusFilter}
-		} else {
-			query = `
-				SELECT
-					t.id, t.user_id, t.start_date, t.end_date, t.request_type, t.reason, t.status,
-					t.reviewer_id, t.reviewer_notes, t.reviewed_at, t.created_at, t.updated_at,
-					u.id, u.email, u.first_name, u.last_name, u.role, u.title, u.department, u.avatar_url
-				FROM time_off_requests t
-				JOIN users u ON t.user_id = u.id
-				WHERE (t.user_id = $1 OR u.supervisor_id = $1)
-				ORDER BY t.start_date DESC
-			`
-			args = []interface{}{user.ID}
+// arg registers a query argument and returns its positional placeholder
+func (q *timeOffQuery) arg(v interface{}) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// where adds a condition; conditions are ANDed together
+func (q *timeOffQuery) where(condition string) {
+	q.conditions = append(q.conditions, condition)
+}
+
+// applyFilter adds the filter's status, type, date range, and cursor conditions
+func (q *timeOffQuery) applyFilter(filter models.TimeOffFilter) {
+	if filter.UserID != nil {
+		q.where("t.user_id = " + q.arg(*filter.UserID))
+	}
+	if filter.Status != nil {
+		q.where("t.status = " + q.arg(*filter.Status))
+	}
+	if filter.RequestType != nil {
+		q.where("t.request_type = " + q.arg(*filter.RequestType))
+	}
+	if filter.From != nil {
+		q.where("t.end_date >= " + q.arg(*filter.From))
+	}
+	if filter.To != nil {
+		q.where("t.start_date <= " + q.arg(*filter.To))
+	}
+	if filter.Cursor != nil {
+		op := "<"
+		if filter.SortAsc {
+			op = ">"
 		}
+		q.where(fmt.Sprintf("(%s, t.id) %s (%s, %s)",
+			timeOffSortColumn(filter.SortBy), op, q.arg(filter.Cursor.SortValue), q.arg(filter.Cursor.ID)))
 	}
+}
 
-	rows, err := r.db.Query(ctx, query, args...)
+// build returns the final SQL for the given filter's ordering and limit
+func (q *timeOffQuery) build(filter models.TimeOffFilter) string {
+	query := `SELECT ` + timeOffWithUserColumns + `
+		FROM time_off_requests t
+		JOIN users u ON t.user_id = u.id AND u.deleted_at IS NULL`
+	if len(q.conditions) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(q.conditions, " AND ")
+	}
+
+	direction := "DESC"
+	if filter.SortAsc {
+		direction = "ASC"
+	}
+	sortColumn := timeOffSortColumn(filter.SortBy)
+	query += fmt.Sprintf("\n\t\tORDER BY %s %s, t.id %s", sortColumn, direction, direction)
+
+	// Fetch one extra row so callers can tell whether another page exists
+	if filter.Limit > 0 {
+		query += "\n\t\tLIMIT " + q.arg(filter.Limit+1)
+	}
+	return query
+}
+
+// timeOffSortColumn maps a sort field to its column, defaulting to start_date
+func timeOffSortColumn(field models.TimeOffSortField) string {
+	if field == models.TimeOffSortCreatedAt {
+		return "t.created_at"
+	}
+	return "t.start_date"
+}
+
+// queryTimeOffPage runs a built query and assembles a page, trimming the look-ahead row
+func (r *TimeOffRepository) queryTimeOffPage(ctx context.Context, q *timeOffQuery, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error) {
+	if orgID := orgScope(ctx); orgID != nil {
+		q.where("t.org_id = " + q.arg(*orgID))
+	}
+	rows, err := r.db.Query(ctx, q.build(filter), q.args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get visible time off requests: %w", err)
+		return nil, err
 	}
 	defer rows.Close()
 
-	var requests []models.TimeOffRequest
+	requests := []models.TimeOffRequest{}
 	for rows.Next() {
 		var timeOff models.TimeOffRequest
 		var reqUser models.User
@@ -580,8 +659,59 @@ func (r *TimeOffRepository) GetVisibleRequests(ctx context.Context, user *models
 		timeOff.User = &reqUser
 		requests = append(requests, timeOff)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return requests, nil
+	page := &models.TimeOffRequestPage{Data: requests}
+	if filter.Limit > 0 && len(requests) > filter.Limit {
+		page.Data = requests[:filter.Limit]
+		page.HasMore = true
+		last := page.Data[len(page.Data)-1]
+		cursor := models.TimeOffCursor{SortValue: last.StartDate, ID: last.ID}
+		if filter.SortBy == models.TimeOffSortCreatedAt {
+			cursor.SortValue = last.CreatedAt
+		}
+		next := cursor.Encode()
+		page.NextCursor = &next
+	}
+	return page, nil
+}
+
+// GetVisibleRequests returns time off requests visible to the user based on their role:
+// - Employees: only their own requests
+// - Supervisors and admins: their own + their direct reports' requests
+// Squad leads also see the requests of everyone in the squads they lead.
+func (r *TimeOffRepository) GetVisibleRequests(ctx context.Context, user *models.User, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetVisibleRequests")
+	q := &timeOffQuery{}
+	userID := q.arg(user.ID)
+	squadMembers := " OR t.user_id IN (" + squadLeadMembersQuery(userID) + ")"
+	if user.Role == models.RoleEmployee {
+		q.where("(t.user_id = " + userID + squadMembers + ")")
+	} else {
+		q.where("(t.user_id = " + userID + " OR u.supervisor_id = " + userID + squadMembers + ")")
+	}
+	q.applyFilter(filter)
+
+	page, err := r.queryTimeOffPage(ctx, q, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visible time off requests: %w", err)
+	}
+	return page, nil
+}
+
+// GetAllRequests returns time off requests across the whole organization (for admin audits)
+func (r *TimeOffRepository) GetAllRequests(ctx context.Context, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error) {
+	ctx = withQueryName(ctx, "TimeOffRepository.GetAllRequests")
+	q := &timeOffQuery{}
+	q.applyFilter(filter)
+
+	page, err := r.queryTimeOffPage(ctx, q, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all time off requests: %w", err)
+	}
+	return page, nil
 }
 
 // Business day calculation helpers
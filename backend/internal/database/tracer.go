@@ -2,16 +2,51 @@ package database
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// QueryTracer logs slow database queries for performance monitoring
+// QueryMetrics records query latency and outcome per logical query, named after the repository
+// method that ran it rather than the SQL, so that dashboards group queries the way the code does
+type QueryMetrics struct {
+	duration *prometheus.HistogramVec
+}
+
+// NewQueryMetrics creates and registers the query metrics
+func NewQueryMetrics(namespace string) *QueryMetrics {
+	return newQueryMetrics(prometheus.DefaultRegisterer, namespace)
+}
+
+func newQueryMetrics(registerer prometheus.Registerer, namespace string) *QueryMetrics {
+	return &QueryMetrics{
+		duration: promauto.With(registerer).NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "db_query_duration_seconds",
+				Help:      "Duration of database queries in seconds by repository and method; status is ok or error",
+				Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+			},
+			[]string{"repository", "query", "status"},
+		),
+	}
+}
+
+// QueryTracer logs slow database queries for performance monitoring and records a span for
+// every query, plus latency metrics when configured
 type QueryTracer struct {
 	log           *logger.Logger
 	slowThreshold time.Duration
+	metrics       *QueryMetrics
 }
 
 // NewQueryTracer creates a new query tracer
@@ -23,26 +58,68 @@ func NewQueryTracer(log *logger.Logger, slowThreshold time.Duration) *QueryTrace
 	}
 }
 
-// queryStartKey is the context key for storing query start time
+// WithMetrics records every query's latency in metrics
+func (t *QueryTracer) WithMetrics(metrics *QueryMetrics) *QueryTracer {
+	t.metrics = metrics
+	return t
+}
+
+// queryStartKey is the context key for storing the queryStart of a running query
 type queryStartKey struct{}
 
+// queryStart is what TraceQueryStart records for TraceQueryEnd
+type queryStart struct {
+	at         time.Time
+	repository string
+	query      string
+}
+
 // TraceQueryStart is called at the beginning of Query, QueryRow, and Exec calls
 func (t *QueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
-	return context.WithValue(ctx, queryStartKey{}, time.Now())
+	operation := sqlOperation(data.SQL)
+	repository, query := "unknown", strings.ToLower(operation)
+	if name, ok := ctx.Value(queryNameKey{}).(queryName); ok {
+		repository, query = name.repository, name.query
+	}
+	ctx, _ = tracing.Tracer().Start(ctx, "postgres "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(truncateSQL(data.SQL)),
+			attribute.String("db.query.caller", repository+"."+query),
+		),
+	)
+	return context.WithValue(ctx, queryStartKey{}, queryStart{at: time.Now(), repository: repository, query: query})
 }
 
 // TraceQueryEnd is called after Query, QueryRow, and Exec calls
 func (t *QueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
-	startTime, ok := ctx.Value(queryStartKey{}).(time.Time)
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
 	if !ok {
 		return
 	}
 
-	duration := time.Since(startTime)
+	duration := time.Since(start.at)
+	caller := start.repository + "." + start.query
+
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if t.metrics != nil {
+		status := "ok"
+		if data.Err != nil {
+			status = "error"
+		}
+		t.metrics.duration.WithLabelValues(start.repository, start.query, status).Observe(duration.Seconds())
+	}
 
 	// Always log errors
 	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
 		t.log.WithContext(ctx).Error("Database query failed",
+			"query", caller,
 			"sql", truncateSQL(data.CommandTag.String()),
 			"duration_ms", duration.Milliseconds(),
 			"error", data.Err.Error(),
@@ -50,9 +127,12 @@ func (t *QueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pg
 		return
 	}
 
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+
 	// Log slow queries at WARN level
 	if duration >= t.slowThreshold {
 		t.log.WithContext(ctx).Warn("Slow database query",
+			"query", caller,
 			"sql", truncateSQL(data.CommandTag.String()),
 			"duration_ms", duration.Milliseconds(),
 			"rows_affected", data.CommandTag.RowsAffected(),
@@ -62,12 +142,44 @@ func (t *QueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pg
 
 	// Log all queries at DEBUG level for development
 	t.log.WithContext(ctx).Debug("Database query completed",
+		"query", caller,
 		"sql", truncateSQL(data.CommandTag.String()),
 		"duration_ms", duration.Milliseconds(),
 		"rows_affected", data.CommandTag.RowsAffected(),
 	)
 }
 
+// queryNameKey is the context key for the name of the queries run with a context
+type queryNameKey struct{}
+
+// queryName is the repository and method that queries are labelled with
+type queryName struct {
+	repository string
+	query      string
+}
+
+// withQueryName names the queries run with ctx after a repository method, such as
+// "TimeOffRepository.GetVisibleRequests". Every exported repository method names its context
+// first, so a name that is already set is kept: helpers shared by several methods, and other
+// repositories' methods called along the way, are attributed to the method that called them.
+// Queries run with an unnamed context are labelled as unknown with their SQL operation.
+func withQueryName(ctx context.Context, name string) context.Context {
+	if _, ok := ctx.Value(queryNameKey{}).(queryName); ok {
+		return ctx
+	}
+	repository, query, _ := strings.Cut(name, ".")
+	return context.WithValue(ctx, queryNameKey{}, queryName{repository: repository, query: query})
+}
+
+// sqlOperation returns the statement's leading keyword, such as SELECT or UPDATE, to name its span
+func sqlOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
+
 // truncateSQL truncates long SQL statements for logging
 func truncateSQL(sql string) string {
 	const maxLen = 200
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+)
+
+// fakeRepository runs traced queries the way a repository method would
+type fakeRepository struct {
+	tracer *QueryTracer
+	other  *otherFakeRepository
+}
+
+func (r *fakeRepository) GetVisibleThings(ctx context.Context, err error) {
+	ctx = withQueryName(ctx, "fakeRepository.GetVisibleThings")
+	r.queryThings(ctx, err)
+}
+
+func (r *fakeRepository) GetAllThings(ctx context.Context) {
+	ctx = withQueryName(ctx, "fakeRepository.GetAllThings")
+	r.queryThings(ctx, nil)
+}
+
+// queryThings is shared by GetVisibleThings and GetAllThings, like queryTimeOffPage
+func (r *fakeRepository) queryThings(ctx context.Context, err error) {
+	ctx = r.tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	r.tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: err})
+}
+
+func (r *fakeRepository) UpdateThing(ctx context.Context) {
+	ctx = withQueryName(ctx, "fakeRepository.UpdateThing")
+	recordThing(ctx, r.tracer)
+	r.other.RecordOther(ctx)
+}
+
+// otherFakeRepository is another repository whose methods fakeRepository calls
+type otherFakeRepository struct {
+	tracer *QueryTracer
+}
+
+func (r *otherFakeRepository) RecordOther(ctx context.Context) {
+	ctx = withQueryName(ctx, "otherFakeRepository.RecordOther")
+	recordThing(ctx, r.tracer)
+}
+
+func recordThing(ctx context.Context, tracer *QueryTracer) {
+	ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "UPDATE things SET x = 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+}
+
+func TestQueryTracer_MetricsByRepositoryMethod(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	tracer := NewQueryTracer(logger.Default(), time.Hour).WithMetrics(newQueryMetrics(registry, "test"))
+	other := &otherFakeRepository{tracer: tracer}
+	repo := &fakeRepository{tracer: tracer, other: other}
+
+	repo.GetVisibleThings(context.Background(), nil)
+	repo.GetVisibleThings(context.Background(), nil)
+	repo.GetVisibleThings(context.Background(), errors.New("boom"))
+	repo.GetAllThings(context.Background())
+	repo.UpdateThing(context.Background())
+	other.RecordOther(context.Background())
+	recordThing(context.Background(), tracer)
+
+	histogram := tracer.metrics.duration
+	tests := []struct {
+		labels []string
+		want   int
+	}{
+		{[]string{"fakeRepository", "GetVisibleThings", "ok"}, 2},
+		{[]string{"fakeRepository", "GetVisibleThings", "error"}, 1},
+		// The shared helper is attributed to each method that called it
+		{[]string{"fakeRepository", "GetAllThings", "ok"}, 1},
+		// Including the queries of the other repository's method it called
+		{[]string{"fakeRepository", "UpdateThing", "ok"}, 2},
+		{[]string{"otherFakeRepository", "RecordOther", "ok"}, 1},
+		{[]string{"unknown", "update", "ok"}, 1},
+	}
+	for _, tt := range tests {
+		observer, err := histogram.GetMetricWithLabelValues(tt.labels...)
+		if err != nil {
+			t.Fatalf("GetMetricWithLabelValues(%v) error = %v", tt.labels, err)
+		}
+		if got := sampleCount(t, observer.(prometheus.Histogram)); got != tt.want {
+			t.Errorf("%v observed %d queries, want %d", tt.labels, got, tt.want)
+		}
+	}
+	if got := testutil.CollectAndCount(histogram); got != len(tests) {
+		t.Errorf("collected %d series, want %d", got, len(tests))
+	}
+}
+
+// sampleCount returns how many observations a histogram has recorded
+func sampleCount(t *testing.T, histogram prometheus.Histogram) int {
+	t.Helper()
+	var metric dto.Metric
+	if err := histogram.Write(&metric); err != nil {
+		t.Fatalf("failed to read histogram: %v", err)
+	}
+	return int(metric.GetHistogram().GetSampleCount())
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// UserHistoryRepository handles database operations for users' employment history
+type UserHistoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewUserHistoryRepository creates a new user history repository
+func NewUserHistoryRepository(pool *pgxpool.Pool) *UserHistoryRepository {
+	return &UserHistoryRepository{pool: pool}
+}
+
+// Record saves history entries for changes from the given source
+func (r *UserHistoryRepository) Record(ctx context.Context, source models.UserHistorySource, changedByID *int64, entries []models.UserHistoryEntry) error {
+	ctx = withQueryName(ctx, "UserHistoryRepository.Record")
+	return recordUserHistory(ctx, r.pool, source, changedByID, entries)
+}
+
+// recordUserHistory saves history entries with the pool or a transaction, so changes made inside
+// a transaction are only recorded if it commits
+func recordUserHistory(ctx context.Context, q interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}, source models.UserHistorySource, changedByID *int64, entries []models.UserHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, len(entries))
+	fields := make([]string, len(entries))
+	oldValues := make([]*string, len(entries))
+	newValues := make([]*string, len(entries))
+	for i, e := range entries {
+		userIDs[i] = e.UserID
+		fields[i] = string(e.Field)
+		oldValues[i] = e.OldValue
+		newValues[i] = e.NewValue
+	}
+
+	_, err := q.Exec(ctx, `
+		INSERT INTO user_history (user_id, field, old_value, new_value, source, changed_by_id)
+		SELECT user_id, field, old_value, new_value, $5, $6
+		FROM unnest($1::bigint[], $2::text[], $3::text[], $4::text[]) AS t(user_id, field, old_value, new_value)
+	`, userIDs, fields, oldValues, newValues, string(source), changedByID)
+	if err != nil {
+		return fmt.Errorf("failed to record user history: %w", err)
+	}
+	return nil
+}
+
+// GetByUserID retrieves a page of a user's history, newest first, along with the total number of entries
+func (r *UserHistoryRepository) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]models.UserHistoryEntry, int, error) {
+	ctx = withQueryName(ctx, "UserHistoryRepository.GetByUserID")
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM user_history WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count user history: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT h.id, h.user_id, h.field, h.old_value, h.new_value, h.source, h.changed_by_id,
+			COALESCE(c.first_name || ' ' || c.last_name, ''), h.created_at
+		FROM user_history h
+		LEFT JOIN users c ON c.id = h.changed_by_id
+		WHERE h.user_id = $1
+		ORDER BY h.created_at DESC, h.id DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get user history: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := scanUserHistory(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// scanUserHistory scans rows into history entries
+func scanUserHistory(rows pgx.Rows) ([]models.UserHistoryEntry, error) {
+	entries := []models.UserHistoryEntry{}
+	for rows.Next() {
+		var e models.UserHistoryEntry
+		err := rows.Scan(&e.ID, &e.UserID, &e.Field, &e.OldValue, &e.NewValue, &e.Source, &e.ChangedByID,
+			&e.ChangedByName, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user history: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -3,31 +3,51 @@ package database
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/crypto"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
 )
 
 // Column lists for consistent SELECT statements
 // Note: squad column is deprecated - squads are now loaded via user_squads junction table
 const (
 	userColumns = `id, COALESCE(auth0_id, ''), email, first_name, last_name, role, title, department,
-		avatar_url, supervisor_id, date_started, is_active, created_at, updated_at, jira_account_id`
+		avatar_url, supervisor_id, date_started, is_active, created_at, updated_at, jira_account_id,
+		access_expires_at, timezone, language, github_login, linear_user_id, termination_date, birthday, avatar_variants, default_avatar_url, org_id, deleted_at,
+		last_login_at, last_seen_at, preferred_name, pronouns, phone,
+		emergency_contact_name, emergency_contact_phone, emergency_contact_relationship`
 	userColumnsWithJira = userColumns + `, jira_domain, jira_email, jira_api_token,
 		jira_oauth_access_token, jira_oauth_refresh_token, jira_oauth_token_expires_at,
 		jira_cloud_id, jira_site_url`
 )
 
 type UserRepository struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	replica *pgxpool.Pool // Serves the full directory listings when set
+	fields  *crypto.Fields
 }
 
 func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
 	return &UserRepository{pool: pool}
 }
 
+// WithEncryption encrypts users' Jira API and OAuth tokens at rest with fields
+func (r *UserRepository) WithEncryption(fields *crypto.Fields) *UserRepository {
+	r.fields = fields
+	return r
+}
+
+// WithReplica serves the full user and supervisor listings from a read replica
+func (r *UserRepository) WithReplica(replica *pgxpool.Pool) *UserRepository {
+	r.replica = replica
+	return r
+}
+
 // scanUser scans a row into a User struct
 // Note: Squads are loaded separately via SquadRepository
 func scanUser(row pgx.Row) (*models.User, error) {
@@ -36,6 +56,10 @@ func scanUser(row pgx.Row) (*models.User, error) {
 		&user.ID, &user.Auth0ID, &user.Email, &user.FirstName, &user.LastName,
 		&user.Role, &user.Title, &user.Department, &user.AvatarURL, &user.SupervisorID,
 		&user.DateStarted, &user.IsActive, &user.CreatedAt, &user.UpdatedAt, &user.JiraAccountID,
+		&user.AccessExpiresAt, &user.Timezone, &user.Language, &user.GitHubLogin, &user.LinearUserID, &user.TerminationDate, &user.Birthday,
+		&user.AvatarVariants, &user.DefaultAvatarURL, &user.OrgID, &user.DeletedAt, &user.LastLoginAt, &user.LastSeenAt,
+		&user.PreferredName, &user.Pronouns, &user.Phone,
+		&user.EmergencyContact.Name, &user.EmergencyContact.Phone, &user.EmergencyContact.Relationship,
 	)
 	if err != nil {
 		return nil, err
@@ -43,16 +67,20 @@ func scanUser(row pgx.Row) (*models.User, error) {
 	return &user, nil
 }
 
-// scanUserWithJira scans a row into a User struct including Jira fields
+// scanUserWithJira scans a row into a User struct including Jira fields, decrypting tokens with fields
 // Note: Squads are loaded separately via SquadRepository
-func scanUserWithJira(row pgx.Row) (*models.User, error) {
+func scanUserWithJira(row pgx.Row, fields *crypto.Fields) (*models.User, error) {
 	var user models.User
 	err := row.Scan(
 		&user.ID, &user.Auth0ID, &user.Email, &user.FirstName, &user.LastName,
 		&user.Role, &user.Title, &user.Department, &user.AvatarURL, &user.SupervisorID,
 		&user.DateStarted, &user.IsActive, &user.CreatedAt, &user.UpdatedAt, &user.JiraAccountID,
-		&user.JiraDomain, &user.JiraEmail, &user.JiraAPIToken,
-		&user.JiraOAuthAccessToken, &user.JiraOAuthRefreshToken, &user.JiraOAuthTokenExpires,
+		&user.AccessExpiresAt, &user.Timezone, &user.Language, &user.GitHubLogin, &user.LinearUserID, &user.TerminationDate, &user.Birthday,
+		&user.AvatarVariants, &user.DefaultAvatarURL, &user.OrgID, &user.DeletedAt, &user.LastLoginAt, &user.LastSeenAt,
+		&user.PreferredName, &user.Pronouns, &user.Phone,
+		&user.EmergencyContact.Name, &user.EmergencyContact.Phone, &user.EmergencyContact.Relationship,
+		&user.JiraDomain, &user.JiraEmail, fields.ScanPtr(&user.JiraAPIToken),
+		fields.ScanPtr(&user.JiraOAuthAccessToken), fields.ScanPtr(&user.JiraOAuthRefreshToken), &user.JiraOAuthTokenExpires,
 		&user.JiraCloudID, &user.JiraSiteURL,
 	)
 	if err != nil {
@@ -71,6 +99,10 @@ func scanUsers(rows pgx.Rows) ([]models.User, error) {
 			&user.ID, &user.Auth0ID, &user.Email, &user.FirstName, &user.LastName,
 			&user.Role, &user.Title, &user.Department, &user.AvatarURL, &user.SupervisorID,
 			&user.DateStarted, &user.IsActive, &user.CreatedAt, &user.UpdatedAt, &user.JiraAccountID,
+			&user.AccessExpiresAt, &user.Timezone, &user.Language, &user.GitHubLogin, &user.LinearUserID, &user.TerminationDate, &user.Birthday,
+			&user.AvatarVariants, &user.DefaultAvatarURL, &user.OrgID, &user.DeletedAt, &user.LastLoginAt, &user.LastSeenAt,
+			&user.PreferredName, &user.Pronouns, &user.Phone,
+			&user.EmergencyContact.Name, &user.EmergencyContact.Phone, &user.EmergencyContact.Relationship,
 		)
 		if err != nil {
 			return nil, err
@@ -81,8 +113,9 @@ func scanUsers(rows pgx.Rows) ([]models.User, error) {
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
-	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
-	user, err := scanUser(r.pool.QueryRow(ctx, query, id))
+	ctx = withQueryName(ctx, "UserRepository.GetByID")
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND deleted_at IS NULL AND ` + orgCondition("org_id", "$2")
+	user, err := scanUser(r.pool.QueryRow(ctx, query, id, orgScope(ctx)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by ID: %w", err)
 	}
@@ -92,12 +125,13 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*models.User, e
 // GetByIDs retrieves multiple users by their IDs in a single query (batch loading)
 // This is used by dataloaders to prevent N+1 query problems
 func (r *UserRepository) GetByIDs(ctx context.Context, ids []int64) ([]models.User, error) {
+	ctx = withQueryName(ctx, "UserRepository.GetByIDs")
 	if len(ids) == 0 {
 		return []models.User{}, nil
 	}
 
-	query := `SELECT ` + userColumns + ` FROM users WHERE id = ANY($1)`
-	rows, err := r.pool.Query(ctx, query, ids)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ANY($1) AND deleted_at IS NULL AND ` + orgCondition("org_id", "$2")
+	rows, err := r.pool.Query(ctx, query, ids, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users by IDs: %w", err)
 	}
@@ -110,7 +144,10 @@ func (r *UserRepository) GetByIDs(ctx context.Context, ids []int64) ([]models.Us
 	return users, nil
 }
 
+// GetByAuth0ID retrieves a user by their identity provider subject, including a deleted user so
+// their sign-in can be refused rather than creating a new account
 func (r *UserRepository) GetByAuth0ID(ctx context.Context, auth0ID string) (*models.User, error) {
+	ctx = withQueryName(ctx, "UserRepository.GetByAuth0ID")
 	query := `SELECT ` + userColumns + ` FROM users WHERE auth0_id = $1`
 	user, err := scanUser(r.pool.QueryRow(ctx, query, auth0ID))
 	if err != nil {
@@ -119,7 +156,10 @@ func (r *UserRepository) GetByAuth0ID(ctx context.Context, auth0ID string) (*mod
 	return user, nil
 }
 
+// GetByEmail retrieves a user by email, including a deleted user since the address stays taken
+// until they are restored or purged
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx = withQueryName(ctx, "UserRepository.GetByEmail")
 	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
 	user, err := scanUser(r.pool.QueryRow(ctx, query, email))
 	if err != nil {
@@ -129,8 +169,10 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 }
 
 func (r *UserRepository) GetDirectReportsBySupervisorID(ctx context.Context, supervisorID int64) ([]models.User, error) {
-	query := `SELECT ` + userColumns + ` FROM users WHERE supervisor_id = $1 AND is_active = true ORDER BY last_name, first_name`
-	rows, err := r.pool.Query(ctx, query, supervisorID)
+	ctx = withQueryName(ctx, "UserRepository.GetDirectReportsBySupervisorID")
+	query := `SELECT ` + userColumns + ` FROM users WHERE supervisor_id = $1 AND is_active = true AND ` + orgCondition("org_id", "$2") + `
+		ORDER BY last_name, first_name`
+	rows, err := r.pool.Query(ctx, query, supervisorID, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get direct reports by supervisor ID: %w", err)
 	}
@@ -143,9 +185,39 @@ func (r *UserRepository) GetDirectReportsBySupervisorID(ctx context.Context, sup
 	return users, nil
 }
 
+// GetDirectReportsBySupervisorIDs retrieves the active direct reports of several supervisors in a single query
+// Returns a map of supervisorID -> []User
+func (r *UserRepository) GetDirectReportsBySupervisorIDs(ctx context.Context, supervisorIDs []int64) (map[int64][]models.User, error) {
+	ctx = withQueryName(ctx, "UserRepository.GetDirectReportsBySupervisorIDs")
+	if len(supervisorIDs) == 0 {
+		return make(map[int64][]models.User), nil
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE supervisor_id = ANY($1) AND is_active = true AND ` + orgCondition("org_id", "$2") + `
+		ORDER BY last_name, first_name`
+	rows, err := readPool(r.pool, r.replica).Query(ctx, query, supervisorIDs, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get direct reports by supervisor IDs: %w", err)
+	}
+	defer rows.Close()
+
+	users, err := scanUsers(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan direct report: %w", err)
+	}
+
+	result := make(map[int64][]models.User)
+	for _, user := range users {
+		result[*user.SupervisorID] = append(result[*user.SupervisorID], user)
+	}
+	return result, nil
+}
+
 func (r *UserRepository) GetAllSupervisors(ctx context.Context) ([]models.User, error) {
-	query := `SELECT ` + userColumns + ` FROM users WHERE role = 'supervisor' AND is_active = true ORDER BY last_name, first_name`
-	rows, err := r.pool.Query(ctx, query)
+	ctx = withQueryName(ctx, "UserRepository.GetAllSupervisors")
+	query := `SELECT ` + userColumns + ` FROM users WHERE role = 'supervisor' AND is_active = true AND ` + orgCondition("org_id", "$1") + `
+		ORDER BY last_name, first_name`
+	rows, err := readPool(r.pool, r.replica).Query(ctx, query, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get supervisors: %w", err)
 	}
@@ -159,8 +231,10 @@ func (r *UserRepository) GetAllSupervisors(ctx context.Context) ([]models.User,
 }
 
 func (r *UserRepository) GetAll(ctx context.Context) ([]models.User, error) {
-	query := `SELECT ` + userColumns + ` FROM users WHERE is_active = true ORDER BY role DESC, last_name, first_name`
-	rows, err := r.pool.Query(ctx, query)
+	ctx = withQueryName(ctx, "UserRepository.GetAll")
+	query := `SELECT ` + userColumns + ` FROM users WHERE is_active = true AND ` + orgCondition("org_id", "$1") + `
+		ORDER BY role DESC, last_name, first_name`
+	rows, err := readPool(r.pool, r.replica).Query(ctx, query, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all users: %w", err)
 	}
@@ -174,15 +248,22 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]models.User, error) {
 }
 
 func (r *UserRepository) Create(ctx context.Context, req *models.CreateUserRequest, auth0ID string) (*models.User, error) {
-	// Note: Squad is now handled separately via SquadRepository.SetUserSquads
+	ctx = withQueryName(ctx, "UserRepository.Create")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Note: Squad is now handled separately via SquadRepository.SetUserSquads.
+	// Users created before their first login have no Auth0 ID until they claim the account with ClaimAccount.
 	query := `
-		INSERT INTO users (auth0_id, email, first_name, last_name, role, title, department, avatar_url, supervisor_id, date_started)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, NOW()))
+		INSERT INTO users (auth0_id, email, first_name, last_name, role, title, department, avatar_url, supervisor_id, date_started, org_id)
+		VALUES (NULLIF($1, ''), $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, NOW()), $11)
 		RETURNING ` + userColumns
 
 	user, err := scanUser(r.pool.QueryRow(ctx, query,
 		auth0ID, req.Email, req.FirstName, req.LastName, req.Role,
 		req.Title, req.Department, req.AvatarURL, req.SupervisorID, req.DateStarted,
+		orgID,
 	))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
@@ -190,30 +271,36 @@ func (r *UserRepository) Create(ctx context.Context, req *models.CreateUserReque
 	return user, nil
 }
 
-func (r *UserRepository) CreateOrUpdate(ctx context.Context, auth0ID, email, firstName, lastName string) (*models.User, error) {
-	// First, check if user exists by email (handles invitation-created users)
-	existingByEmail, _ := r.GetByEmail(ctx, email)
-	if existingByEmail != nil {
-		// User exists with this email - update their auth0_id and name if needed
-		query := `
-			UPDATE users SET
-				auth0_id = $2,
-				first_name = CASE WHEN first_name = '' THEN $3 ELSE first_name END,
-				last_name = CASE WHEN last_name = '' THEN $4 ELSE last_name END,
-				updated_at = NOW()
-			WHERE id = $1
-			RETURNING ` + userColumns
-		user, err := scanUser(r.pool.QueryRow(ctx, query, existingByEmail.ID, auth0ID, firstName, lastName))
-		if err != nil {
-			return nil, fmt.Errorf("failed to update existing user: %w", err)
-		}
-		return user, nil
+// ClaimAccount links a signing-in user to an account made for them before they first signed in,
+// e.g. by an admin or SCIM. Accounts that have been signed in to are never relinked.
+func (r *UserRepository) ClaimAccount(ctx context.Context, id int64, auth0ID, firstName, lastName string) (*models.User, error) {
+	ctx = withQueryName(ctx, "UserRepository.ClaimAccount")
+	query := `
+		UPDATE users SET
+			auth0_id = $2,
+			first_name = CASE WHEN first_name = '' THEN $3 ELSE first_name END,
+			last_name = CASE WHEN last_name = '' THEN $4 ELSE last_name END,
+			updated_at = NOW()
+		WHERE id = $1 AND auth0_id IS NULL
+		RETURNING ` + userColumns
+	user, err := scanUser(r.pool.QueryRow(ctx, query, id, auth0ID, firstName, lastName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim user account: %w", err)
 	}
+	return user, nil
+}
 
-	// No existing user by email, try insert with ON CONFLICT for auth0_id
+// CreateOrUpdate creates an account for a signing-in user in the organization ctx is scoped to.
+// It never takes over an existing account with the same email.
+func (r *UserRepository) CreateOrUpdate(ctx context.Context, auth0ID, email, firstName, lastName string) (*models.User, error) {
+	ctx = withQueryName(ctx, "UserRepository.CreateOrUpdate")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := `
-		INSERT INTO users (auth0_id, email, first_name, last_name, role, title, department)
-		VALUES ($1, $2, $3, $4, 'employee', '', '')
+		INSERT INTO users (auth0_id, email, first_name, last_name, role, title, department, org_id)
+		VALUES ($1, $2, $3, $4, 'employee', '', '', $5)
 		ON CONFLICT (auth0_id) DO UPDATE SET
 			email = EXCLUDED.email,
 			first_name = EXCLUDED.first_name,
@@ -221,7 +308,7 @@ func (r *UserRepository) CreateOrUpdate(ctx context.Context, auth0ID, email, fir
 			updated_at = NOW()
 		RETURNING ` + userColumns
 
-	user, err := scanUser(r.pool.QueryRow(ctx, query, auth0ID, email, firstName, lastName))
+	user, err := scanUser(r.pool.QueryRow(ctx, query, auth0ID, email, firstName, lastName, orgID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create or update user: %w", err)
 	}
@@ -229,6 +316,7 @@ func (r *UserRepository) CreateOrUpdate(ctx context.Context, auth0ID, email, fir
 }
 
 func (r *UserRepository) Update(ctx context.Context, id int64, req *models.UpdateUserRequest) (*models.User, error) {
+	ctx = withQueryName(ctx, "UserRepository.Update")
 	// Note: Squad is now handled separately via SquadRepository.SetUserSquads
 	query := `
 		UPDATE users SET
@@ -238,13 +326,31 @@ func (r *UserRepository) Update(ctx context.Context, id int64, req *models.Updat
 			department = COALESCE($5, department),
 			supervisor_id = COALESCE($6, supervisor_id),
 			avatar_url = COALESCE($7, avatar_url),
+			avatar_variants = CASE WHEN $7::text IS NULL THEN avatar_variants ELSE NULL END,
+			-- Renamed users get new initials
+			default_avatar_url = CASE WHEN COALESCE($2, first_name) = first_name AND COALESCE($3, last_name) = last_name
+				THEN default_avatar_url END,
+			timezone = COALESCE($8, timezone),
+			birthday = CASE WHEN $9::text IS NULL THEN birthday ELSE NULLIF($9::text, '')::date END,
+			language = COALESCE($10, language),
+			preferred_name = COALESCE($12, preferred_name),
+			pronouns = COALESCE($13, pronouns),
+			phone = COALESCE($14, phone),
+			emergency_contact_name = COALESCE($15, emergency_contact_name),
+			emergency_contact_phone = COALESCE($16, emergency_contact_phone),
+			emergency_contact_relationship = COALESCE($17, emergency_contact_relationship),
 			updated_at = NOW()
-		WHERE id = $1
+		WHERE id = $1 AND ` + orgCondition("org_id", "$11") + `
 		RETURNING ` + userColumns
 
+	var contactName, contactPhone, contactRelationship *string
+	if c := req.EmergencyContact; c != nil {
+		contactName, contactPhone, contactRelationship = &c.Name, &c.Phone, &c.Relationship
+	}
 	user, err := scanUser(r.pool.QueryRow(ctx, query,
 		id, req.FirstName, req.LastName, req.Title, req.Department,
-		req.SupervisorID, req.AvatarURL,
+		req.SupervisorID, req.AvatarURL, req.Timezone, req.Birthday, req.Language, orgScope(ctx),
+		req.PreferredName, req.Pronouns, req.Phone, contactName, contactPhone, contactRelationship,
 	))
 	if err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
@@ -252,18 +358,132 @@ func (r *UserRepository) Update(ctx context.Context, id int64, req *models.Updat
 	return user, nil
 }
 
+// SetAvatar replaces a user's avatar with an uploaded one and its resized variants
+func (r *UserRepository) SetAvatar(ctx context.Context, id int64, avatarURL string, variants models.AvatarVariants) (*models.User, error) {
+	ctx = withQueryName(ctx, "UserRepository.SetAvatar")
+	query := `
+		UPDATE users SET avatar_url = $2, avatar_variants = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + userColumns
+
+	user, err := scanUser(r.pool.QueryRow(ctx, query, id, avatarURL, variants))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set avatar: %w", err)
+	}
+	return user, nil
+}
+
+// SetSupervisor changes who a user reports to. A nil supervisor leaves them without one.
+func (r *UserRepository) SetSupervisor(ctx context.Context, id int64, supervisorID *int64) (*models.User, error) {
+	ctx = withQueryName(ctx, "UserRepository.SetSupervisor")
+	query := `
+		UPDATE users SET supervisor_id = $2, updated_at = NOW()
+		WHERE id = $1 AND ` + orgCondition("org_id", "$3") + `
+		RETURNING ` + userColumns
+
+	user, err := scanUser(r.pool.QueryRow(ctx, query, id, supervisorID, orgScope(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set supervisor: %w", err)
+	}
+	return user, nil
+}
+
+// ListMissingDefaultAvatars retrieves up to limit users after afterID, in ID order, that have neither
+// an avatar nor a generated default
+func (r *UserRepository) ListMissingDefaultAvatars(ctx context.Context, afterID int64, limit int) ([]models.User, error) {
+	ctx = withQueryName(ctx, "UserRepository.ListMissingDefaultAvatars")
+	query := `SELECT ` + userColumns + ` FROM users
+		WHERE avatar_url IS NULL AND default_avatar_url IS NULL AND deleted_at IS NULL AND id > $1
+		ORDER BY id LIMIT $2`
+	rows, err := r.pool.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users missing default avatars: %w", err)
+	}
+	defer rows.Close()
+
+	users, err := scanUsers(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan user: %w", err)
+	}
+	return users, nil
+}
+
+// SetDefaultAvatar sets the generated avatar shown for a user who hasn't uploaded one
+func (r *UserRepository) SetDefaultAvatar(ctx context.Context, id int64, url string) error {
+	ctx = withQueryName(ctx, "UserRepository.SetDefaultAvatar")
+	_, err := r.pool.Exec(ctx, `UPDATE users SET default_avatar_url = $2 WHERE id = $1`, id, url)
+	if err != nil {
+		return fmt.Errorf("failed to set default avatar: %w", err)
+	}
+	return nil
+}
+
+// Delete soft-deletes a user: they are deactivated and hidden until restored, and purged once the
+// retention period passes
 func (r *UserRepository) Delete(ctx context.Context, id int64) error {
-	_, err := r.pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	ctx = withQueryName(ctx, "UserRepository.Delete")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := tx.Exec(ctx, `
+		UPDATE users SET deleted_at = NOW(), active_before_delete = is_active, is_active = false, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND `+orgCondition("org_id", "$2"), id, orgScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	// Direct reports move up to no supervisor, as when the user is deactivated
+	_, err = tx.Exec(ctx, `UPDATE users SET supervisor_id = NULL, updated_at = NOW() WHERE supervisor_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to clear supervisor from direct reports: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
+// Restore brings back a soft-deleted user, active only if they were active when deleted. Their
+// former direct reports stay unassigned.
+func (r *UserRepository) Restore(ctx context.Context, id int64) (*models.User, error) {
+	ctx = withQueryName(ctx, "UserRepository.Restore")
+	query := `
+		UPDATE users SET deleted_at = NULL, is_active = active_before_delete, active_before_delete = false, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL AND ` + orgCondition("org_id", "$2") + `
+		RETURNING ` + userColumns
+	user, err := scanUser(r.pool.QueryRow(ctx, query, id, orgScope(ctx)))
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("deleted user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore user: %w", err)
+	}
+	return user, nil
+}
+
+// PurgeDeleted permanently removes users soft-deleted before the cutoff, along with the records
+// that cascade from them, returning how many users were removed
+func (r *UserRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withQueryName(ctx, "UserRepository.PurgeDeleted")
+	result, err := r.pool.Exec(ctx, `DELETE FROM users WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted users: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
 // GetWithJiraCredentials returns a user with their Jira credentials
 func (r *UserRepository) GetWithJiraCredentials(ctx context.Context, id int64) (*models.User, error) {
-	query := `SELECT ` + userColumnsWithJira + ` FROM users WHERE id = $1`
-	user, err := scanUserWithJira(r.pool.QueryRow(ctx, query, id))
+	ctx = withQueryName(ctx, "UserRepository.GetWithJiraCredentials")
+	query := `SELECT ` + userColumnsWithJira + ` FROM users WHERE id = $1 AND deleted_at IS NULL AND ` + orgCondition("org_id", "$2")
+	user, err := scanUserWithJira(r.pool.QueryRow(ctx, query, id, orgScope(ctx)), r.fields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user with Jira credentials: %w", err)
 	}
@@ -272,6 +492,7 @@ func (r *UserRepository) GetWithJiraCredentials(ctx context.Context, id int64) (
 
 // UpdateJiraSettings updates the Jira integration settings for a user
 func (r *UserRepository) UpdateJiraSettings(ctx context.Context, id int64, req *models.UpdateJiraSettingsRequest) error {
+	ctx = withQueryName(ctx, "UserRepository.UpdateJiraSettings")
 	query := `
 		UPDATE users SET
 			jira_domain = $2,
@@ -280,7 +501,7 @@ func (r *UserRepository) UpdateJiraSettings(ctx context.Context, id int64, req *
 			updated_at = NOW()
 		WHERE id = $1
 	`
-	_, err := r.pool.Exec(ctx, query, id, req.JiraDomain, req.JiraEmail, req.JiraAPIToken)
+	_, err := r.pool.Exec(ctx, query, id, req.JiraDomain, req.JiraEmail, r.fields.Value(req.JiraAPIToken))
 	if err != nil {
 		return fmt.Errorf("failed to update Jira settings: %w", err)
 	}
@@ -289,6 +510,7 @@ func (r *UserRepository) UpdateJiraSettings(ctx context.Context, id int64, req *
 
 // ClearJiraSettings removes Jira credentials for a user (both legacy and OAuth)
 func (r *UserRepository) ClearJiraSettings(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "UserRepository.ClearJiraSettings")
 	query := `
 		UPDATE users SET
 			jira_domain = NULL,
@@ -311,6 +533,7 @@ func (r *UserRepository) ClearJiraSettings(ctx context.Context, id int64) error
 
 // SaveJiraOAuthTokens saves OAuth tokens for a user
 func (r *UserRepository) SaveJiraOAuthTokens(ctx context.Context, id int64, tokens *models.JiraOAuthTokens) error {
+	ctx = withQueryName(ctx, "UserRepository.SaveJiraOAuthTokens")
 	query := `
 		UPDATE users SET
 			jira_oauth_access_token = $2,
@@ -321,7 +544,7 @@ func (r *UserRepository) SaveJiraOAuthTokens(ctx context.Context, id int64, toke
 			updated_at = NOW()
 		WHERE id = $1
 	`
-	_, err := r.pool.Exec(ctx, query, id, tokens.AccessToken, tokens.RefreshToken, tokens.ExpiresAt, tokens.CloudID, tokens.SiteURL)
+	_, err := r.pool.Exec(ctx, query, id, r.fields.Value(tokens.AccessToken), r.fields.Value(tokens.RefreshToken), tokens.ExpiresAt, tokens.CloudID, tokens.SiteURL)
 	if err != nil {
 		return fmt.Errorf("failed to save Jira OAuth tokens: %w", err)
 	}
@@ -330,6 +553,7 @@ func (r *UserRepository) SaveJiraOAuthTokens(ctx context.Context, id int64, toke
 
 // UpdateJiraOAuthAccessToken updates only the access token and expiry (after refresh)
 func (r *UserRepository) UpdateJiraOAuthAccessToken(ctx context.Context, id int64, accessToken string, expiresAt time.Time) error {
+	ctx = withQueryName(ctx, "UserRepository.UpdateJiraOAuthAccessToken")
 	query := `
 		UPDATE users SET
 			jira_oauth_access_token = $2,
@@ -337,7 +561,7 @@ func (r *UserRepository) UpdateJiraOAuthAccessToken(ctx context.Context, id int6
 			updated_at = NOW()
 		WHERE id = $1
 	`
-	_, err := r.pool.Exec(ctx, query, id, accessToken, expiresAt)
+	_, err := r.pool.Exec(ctx, query, id, r.fields.Value(accessToken), expiresAt)
 	if err != nil {
 		return fmt.Errorf("failed to update Jira access token: %w", err)
 	}
@@ -346,6 +570,7 @@ func (r *UserRepository) UpdateJiraOAuthAccessToken(ctx context.Context, id int6
 
 // UpdateJiraAccountID updates the Jira account ID for a user
 func (r *UserRepository) UpdateJiraAccountID(ctx context.Context, id int64, jiraAccountID *string) error {
+	ctx = withQueryName(ctx, "UserRepository.UpdateJiraAccountID")
 	query := `
 		UPDATE users SET
 			jira_account_id = $2,
@@ -359,10 +584,43 @@ func (r *UserRepository) UpdateJiraAccountID(ctx context.Context, id int64, jira
 	return nil
 }
 
+// UpdateGitHubLogin links a user to a GitHub account, or unlinks them when githubLogin is nil
+func (r *UserRepository) UpdateGitHubLogin(ctx context.Context, id int64, githubLogin *string) error {
+	ctx = withQueryName(ctx, "UserRepository.UpdateGitHubLogin")
+	query := `
+		UPDATE users SET
+			github_login = $2,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, query, id, githubLogin)
+	if err != nil {
+		return fmt.Errorf("failed to update GitHub login: %w", err)
+	}
+	return nil
+}
+
+// UpdateLinearUserID links a user to a Linear user, or unlinks them when linearUserID is nil
+func (r *UserRepository) UpdateLinearUserID(ctx context.Context, id int64, linearUserID *string) error {
+	ctx = withQueryName(ctx, "UserRepository.UpdateLinearUserID")
+	query := `
+		UPDATE users SET
+			linear_user_id = $2,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, query, id, linearUserID)
+	if err != nil {
+		return fmt.Errorf("failed to update Linear user ID: %w", err)
+	}
+	return nil
+}
+
 // GetByJiraAccountID returns a user by their Jira account ID
 func (r *UserRepository) GetByJiraAccountID(ctx context.Context, jiraAccountID string) (*models.User, error) {
-	query := `SELECT ` + userColumns + ` FROM users WHERE jira_account_id = $1`
-	user, err := scanUser(r.pool.QueryRow(ctx, query, jiraAccountID))
+	ctx = withQueryName(ctx, "UserRepository.GetByJiraAccountID")
+	query := `SELECT ` + userColumns + ` FROM users WHERE jira_account_id = $1 AND deleted_at IS NULL AND ` + orgCondition("org_id", "$2")
+	user, err := scanUser(r.pool.QueryRow(ctx, query, jiraAccountID, orgScope(ctx)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by Jira account ID: %w", err)
 	}
@@ -372,8 +630,9 @@ func (r *UserRepository) GetByJiraAccountID(ctx context.Context, jiraAccountID s
 // GetAllSquads is deprecated - use SquadRepository.GetAll instead
 // This method is kept for backward compatibility during migration
 func (r *UserRepository) GetAllSquads(ctx context.Context) ([]string, error) {
-	query := `SELECT name FROM squads ORDER BY name`
-	rows, err := r.pool.Query(ctx, query)
+	ctx = withQueryName(ctx, "UserRepository.GetAllSquads")
+	query := `SELECT name FROM squads WHERE ` + orgCondition("org_id", "$1") + ` ORDER BY name`
+	rows, err := r.pool.Query(ctx, query, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all squads: %w", err)
 	}
@@ -392,8 +651,9 @@ func (r *UserRepository) GetAllSquads(ctx context.Context) ([]string, error) {
 
 // GetAllDepartments returns all unique department names from users
 func (r *UserRepository) GetAllDepartments(ctx context.Context) ([]string, error) {
-	query := `SELECT DISTINCT department FROM users WHERE department != '' ORDER BY department`
-	rows, err := r.pool.Query(ctx, query)
+	ctx = withQueryName(ctx, "UserRepository.GetAllDepartments")
+	query := `SELECT DISTINCT department FROM users WHERE department != '' AND ` + orgCondition("org_id", "$1") + ` ORDER BY department`
+	rows, err := r.pool.Query(ctx, query, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all departments: %w", err)
 	}
@@ -412,8 +672,9 @@ func (r *UserRepository) GetAllDepartments(ctx context.Context) ([]string, error
 
 // ClearDepartment clears the department field for all users with the given department name
 func (r *UserRepository) ClearDepartment(ctx context.Context, department string) error {
-	query := `UPDATE users SET department = '', updated_at = $1 WHERE department = $2`
-	_, err := r.pool.Exec(ctx, query, time.Now(), department)
+	ctx = withQueryName(ctx, "UserRepository.ClearDepartment")
+	query := `UPDATE users SET department = '', updated_at = $1 WHERE department = $2 AND ` + orgCondition("org_id", "$3")
+	_, err := r.pool.Exec(ctx, query, time.Now(), department, orgScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to clear department: %w", err)
 	}
@@ -426,6 +687,7 @@ func (r *UserRepository) ClearDepartment(ctx context.Context, department string)
 // - Unassigns tasks that were assigned to the user
 // - Clears the user's supervisor_id from their direct reports
 func (r *UserRepository) Deactivate(ctx context.Context, userID int64) error {
+	ctx = withQueryName(ctx, "UserRepository.Deactivate")
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -471,9 +733,92 @@ func (r *UserRepository) Deactivate(ctx context.Context, userID int64) error {
 	return nil
 }
 
-// Reactivate marks a user as active again
+// Offboard marks a user as inactive as of the termination date and hands off their work:
+// - Reassigns their open tasks to reassignTasksToID, or unassigns them if it's nil
+// - Cancels upcoming meetings they organize and ends their recurring series that are underway
+// - Revokes their Jira account mapping and personal Jira credentials
+// - Clears the user's supervisor_id from their direct reports
+// Unlike Deactivate, tasks they created and their time-off history are kept.
+func (r *UserRepository) Offboard(ctx context.Context, userID int64, reassignTasksToID *int64, terminationDate time.Time) (*models.OffboardResult, error) {
+	ctx = withQueryName(ctx, "UserRepository.Offboard")
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	now := time.Now()
+	result := &models.OffboardResult{TerminationDate: terminationDate, ReassignedToID: reassignTasksToID}
+
+	// 1. Hand off open tasks
+	tag, err := tx.Exec(ctx, `
+		UPDATE tasks SET assigned_user_id = $1, updated_at = $2
+		WHERE assigned_user_id = $3 AND status NOT IN ($4, $5)`,
+		reassignTasksToID, now, userID, string(models.TaskStatusCompleted), string(models.TaskStatusCancelled))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reassign user's tasks: %w", err)
+	}
+	result.TasksReassigned = int(tag.RowsAffected())
+
+	// 2. Cancel upcoming meetings, including edited occurrences of their series
+	tag, err = tx.Exec(ctx, `
+		UPDATE meetings SET is_cancelled = true, updated_at = $1
+		WHERE created_by_id = $2 AND start_time > $1 AND NOT is_cancelled`, now, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel user's meetings: %w", err)
+	}
+	result.MeetingsCancelled = int(tag.RowsAffected())
+
+	// 3. End recurring series that have already started so no further occurrences are generated
+	tag, err = tx.Exec(ctx, `
+		UPDATE meetings SET recurrence_end_date = $1, updated_at = $1
+		WHERE created_by_id = $2 AND parent_meeting_id IS NULL AND recurrence_type IS NOT NULL
+			AND start_time <= $1 AND NOT is_cancelled
+			AND (recurrence_end_date IS NULL OR recurrence_end_date > $1)`, now, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to end user's recurring meetings: %w", err)
+	}
+	result.SeriesEnded = int(tag.RowsAffected())
+
+	// 4. Clear supervisor_id for any direct reports
+	_, err = tx.Exec(ctx, `UPDATE users SET supervisor_id = NULL, updated_at = $1 WHERE supervisor_id = $2`, now, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear supervisor from direct reports: %w", err)
+	}
+
+	// 5. Revoke Jira access and mark the user as terminated
+	err = tx.QueryRow(ctx, `
+		UPDATE users u SET
+			is_active = false,
+			termination_date = $1,
+			jira_account_id = NULL,
+			jira_domain = NULL,
+			jira_email = NULL,
+			jira_api_token = NULL,
+			jira_oauth_access_token = NULL,
+			jira_oauth_refresh_token = NULL,
+			jira_oauth_token_expires_at = NULL,
+			jira_cloud_id = NULL,
+			jira_site_url = NULL,
+			updated_at = $2
+		FROM users old
+		WHERE u.id = $3 AND old.id = u.id
+		RETURNING old.jira_account_id IS NOT NULL`, terminationDate, now, userID).Scan(&result.JiraMappingRevoked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to offboard user: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// Reactivate marks a user as active again and clears any termination date
 func (r *UserRepository) Reactivate(ctx context.Context, userID int64) error {
-	_, err := r.pool.Exec(ctx, `UPDATE users SET is_active = true, updated_at = $1 WHERE id = $2`, time.Now(), userID)
+	ctx = withQueryName(ctx, "UserRepository.Reactivate")
+	_, err := r.pool.Exec(ctx, `UPDATE users SET is_active = true, termination_date = NULL, updated_at = $1 WHERE id = $2`, time.Now(), userID)
 	if err != nil {
 		return fmt.Errorf("failed to reactivate user: %w", err)
 	}
@@ -482,8 +827,9 @@ func (r *UserRepository) Reactivate(ctx context.Context, userID int64) error {
 
 // RenameDepartment renames a department by updating all users with the old department name to the new name
 func (r *UserRepository) RenameDepartment(ctx context.Context, oldName, newName string) error {
-	query := `UPDATE users SET department = $1, updated_at = $2 WHERE department = $3`
-	_, err := r.pool.Exec(ctx, query, newName, time.Now(), oldName)
+	ctx = withQueryName(ctx, "UserRepository.RenameDepartment")
+	query := `UPDATE users SET department = $1, updated_at = $2 WHERE department = $3 AND ` + orgCondition("org_id", "$4")
+	_, err := r.pool.Exec(ctx, query, newName, time.Now(), oldName, orgScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to rename department: %w", err)
 	}
@@ -492,8 +838,10 @@ func (r *UserRepository) RenameDepartment(ctx context.Context, oldName, newName
 
 // GetUsersByDepartment returns all active users in a specific department
 func (r *UserRepository) GetUsersByDepartment(ctx context.Context, department string) ([]models.User, error) {
-	query := `SELECT ` + userColumns + ` FROM users WHERE department = $1 AND is_active = true ORDER BY last_name, first_name`
-	rows, err := r.pool.Query(ctx, query, department)
+	ctx = withQueryName(ctx, "UserRepository.GetUsersByDepartment")
+	query := `SELECT ` + userColumns + ` FROM users WHERE department = $1 AND is_active = true AND ` + orgCondition("org_id", "$2") + `
+		ORDER BY last_name, first_name`
+	rows, err := r.pool.Query(ctx, query, department, orgScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users by department: %w", err)
 	}
@@ -505,3 +853,147 @@ func (r *UserRepository) GetUsersByDepartment(ctx context.Context, department st
 	}
 	return users, nil
 }
+
+// userFullName is the expression the full-name trigram index is built on
+const userFullName = `(first_name || ' ' || last_name)`
+
+// likeEscaper escapes LIKE wildcards so a search query matches them literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search returns a page of users matching the filter along with the total number of matches.
+// A query matches whole words in a user's name, email, title, or department, or part of their
+// name or email, including close misspellings of their name. Matches are ranked by relevance;
+// without a query users are ordered by name.
+func (r *UserRepository) Search(ctx context.Context, filter models.UserSearchFilter, limit, offset int) ([]models.User, int, error) {
+	ctx = withQueryName(ctx, "UserRepository.Search")
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if orgID := orgScope(ctx); orgID != nil {
+		conditions = append(conditions, "org_id = "+arg(*orgID))
+	}
+
+	orderBy := "last_name, first_name, id"
+	if filter.Query != "" {
+		q := arg(filter.Query)
+		pattern := arg("%" + likeEscaper.Replace(filter.Query) + "%")
+		conditions = append(conditions, `(
+			search_vector @@ websearch_to_tsquery('simple', `+q+`)
+			OR `+userFullName+` ILIKE `+pattern+`
+			OR email ILIKE `+pattern+`
+			OR `+userFullName+` % `+q+`
+		)`)
+		orderBy = `ts_rank(search_vector, websearch_to_tsquery('simple', ` + q + `))
+			+ GREATEST(similarity(` + userFullName + `, ` + q + `), similarity(email, ` + q + `)) DESC, ` + orderBy
+	}
+	if filter.Department != nil {
+		conditions = append(conditions, "department = "+arg(*filter.Department))
+	}
+	if filter.SquadID != nil {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM user_squads us WHERE us.user_id = users.id AND us.squad_id = "+arg(*filter.SquadID)+")")
+	}
+	if filter.Role != nil {
+		conditions = append(conditions, "role = "+arg(string(*filter.Role)))
+	}
+	if filter.Active != nil {
+		conditions = append(conditions, "is_active = "+arg(*filter.Active))
+	}
+
+	where := "\n\t\tWHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count user search results: %w", err)
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users` + where + `
+		ORDER BY ` + orderBy + `
+		LIMIT ` + arg(limit) + ` OFFSET ` + arg(offset)
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	users, err := scanUsers(rows)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to scan users: %w", err)
+	}
+	return users, total, nil
+}
+
+// EncryptStoredJiraTokens encrypts users' Jira API and OAuth tokens stored in plaintext or with
+// a previous key, and returns how many users were updated. It does nothing without encryption.
+func (r *UserRepository) EncryptStoredJiraTokens(ctx context.Context) (int, error) {
+	ctx = withQueryName(ctx, "UserRepository.EncryptStoredJiraTokens")
+	if !r.fields.Enabled() {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		SELECT id, jira_api_token, jira_oauth_access_token, jira_oauth_refresh_token
+		FROM users
+		WHERE jira_api_token IS NOT NULL OR jira_oauth_access_token IS NOT NULL OR jira_oauth_refresh_token IS NOT NULL
+		FOR UPDATE
+	`
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read Jira tokens: %w", err)
+	}
+	type storedTokens struct {
+		id     int64
+		tokens [3]*string // API, OAuth access, and OAuth refresh tokens
+	}
+	var stale []storedTokens
+	for rows.Next() {
+		var t storedTokens
+		if err := rows.Scan(&t.id, &t.tokens[0], &t.tokens[1], &t.tokens[2]); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan Jira tokens: %w", err)
+		}
+		for _, token := range t.tokens {
+			if token != nil && r.fields.NeedsRewrite(*token) {
+				stale = append(stale, t)
+				break
+			}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read Jira tokens: %w", err)
+	}
+
+	for _, t := range stale {
+		var rewritten [3]*string
+		for i, token := range t.tokens {
+			if token == nil {
+				continue
+			}
+			value, err := r.fields.Rewrite(*token)
+			if err != nil {
+				return 0, fmt.Errorf("failed to encrypt Jira token: %w", err)
+			}
+			rewritten[i] = &value
+		}
+		_, err := tx.Exec(ctx, `
+			UPDATE users SET jira_api_token = $2, jira_oauth_access_token = $3, jira_oauth_refresh_token = $4
+			WHERE id = $1`, t.id, rewritten[0], rewritten[1], rewritten[2])
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt Jira tokens: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(stale), nil
+}
@@ -0,0 +1,314 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+const webhookEndpointColumns = `id, url, description, secret, event_types, is_active, org_id, created_by_id, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, endpoint_id, event_type, payload, status, attempts, next_attempt_at,
+	last_attempt_at, response_status, last_error, created_at, delivered_at`
+
+type WebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{pool: pool}
+}
+
+// scanWebhookEndpoint scans a row of webhookEndpointColumns into a WebhookEndpoint
+func scanWebhookEndpoint(row pgx.Row) (*models.WebhookEndpoint, error) {
+	var e models.WebhookEndpoint
+	err := row.Scan(&e.ID, &e.URL, &e.Description, &e.Secret, &e.EventTypes, &e.IsActive, &e.OrgID, &e.CreatedByID, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// scanWebhookDelivery scans a row of webhookDeliveryColumns into a WebhookDelivery
+func scanWebhookDelivery(row pgx.Row) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	var payload []byte
+	err := row.Scan(
+		&d.ID, &d.EndpointID, &d.EventType, &payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.LastAttemptAt, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	return &d, nil
+}
+
+// generateWebhookSecret creates a random secret for signing deliveries
+func generateWebhookSecret() (string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+	return "whsec_" + token, nil
+}
+
+// ListEndpoints retrieves the organization's webhook endpoints, oldest first
+func (r *WebhookRepository) ListEndpoints(ctx context.Context) ([]models.WebhookEndpoint, error) {
+	ctx = withQueryName(ctx, "WebhookRepository.ListEndpoints")
+	rows, err := r.pool.Query(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints WHERE `+orgCondition("org_id", "$1")+` ORDER BY id`, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	endpoints := []models.WebhookEndpoint{}
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook endpoint: %w", err)
+		}
+		endpoints = append(endpoints, *endpoint)
+	}
+	return endpoints, rows.Err()
+}
+
+// GetEndpoint retrieves a webhook endpoint, or nil if it doesn't exist
+func (r *WebhookRepository) GetEndpoint(ctx context.Context, id int64) (*models.WebhookEndpoint, error) {
+	ctx = withQueryName(ctx, "WebhookRepository.GetEndpoint")
+	endpoint, err := scanWebhookEndpoint(r.pool.QueryRow(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints WHERE id = $1 AND `+orgCondition("org_id", "$2"), id, orgScope(ctx)))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+	return endpoint, nil
+}
+
+// CreateEndpoint registers a webhook endpoint with a newly generated signing secret
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, req *models.CreateWebhookEndpointRequest, createdByID int64) (*models.WebhookEndpoint, error) {
+	ctx = withQueryName(ctx, "WebhookRepository.CreateEndpoint")
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhook_endpoints (url, description, secret, event_types, created_by_id, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + webhookEndpointColumns
+
+	endpoint, err := scanWebhookEndpoint(r.pool.QueryRow(ctx, query, req.URL, req.Description, secret, req.EventTypes, createdByID, orgID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+	return endpoint, nil
+}
+
+// UpdateEndpoint updates a webhook endpoint, generating a new secret when asked to rotate it.
+// It returns nil if the endpoint doesn't exist.
+func (r *WebhookRepository) UpdateEndpoint(ctx context.Context, id int64, req *models.UpdateWebhookEndpointRequest) (*models.WebhookEndpoint, error) {
+	ctx = withQueryName(ctx, "WebhookRepository.UpdateEndpoint")
+	var secret *string
+	if req.RotateSecret {
+		s, err := generateWebhookSecret()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		secret = &s
+	}
+
+	query := `
+		UPDATE webhook_endpoints SET
+			url = COALESCE($2, url),
+			description = COALESCE($3, description),
+			event_types = COALESCE($4, event_types),
+			is_active = COALESCE($5, is_active),
+			secret = COALESCE($6, secret),
+			updated_at = NOW()
+		WHERE id = $1 AND ` + orgCondition("org_id", "$7") + `
+		RETURNING ` + webhookEndpointColumns
+
+	var eventTypes []string
+	if req.EventTypes != nil {
+		eventTypes = *req.EventTypes
+	}
+	endpoint, err := scanWebhookEndpoint(r.pool.QueryRow(ctx, query, id, req.URL, req.Description, eventTypes, req.IsActive, secret, orgScope(ctx)))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+	return endpoint, nil
+}
+
+// DeleteEndpoint removes a webhook endpoint along with its delivery log
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, id int64) error {
+	ctx = withQueryName(ctx, "WebhookRepository.DeleteEndpoint")
+	result, err := r.pool.Exec(ctx, `DELETE FROM webhook_endpoints WHERE id = $1 AND `+orgCondition("org_id", "$2"), id, orgScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("webhook endpoint not found")
+	}
+	return nil
+}
+
+// enqueueWebhooks queues a delivery of the event to every active endpoint of ctx's organization
+// subscribed to its type. Repositories call it in the transaction making the change, so the
+// deliveries are saved exactly when the change commits. Events webhooks can't subscribe to, or
+// changes made outside an organization, queue nothing.
+func enqueueWebhooks(ctx context.Context, q execer, eventType events.Type, payload any) error {
+	orgID, ok := tenant.OrgID(ctx)
+	if !ok || !models.ValidWebhookEventTypes[string(eventType)] {
+		return nil
+	}
+
+	body, err := json.Marshal(events.Event{Type: eventType, Payload: payload, OccurredAt: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+	_, err = q.Exec(ctx, `
+		INSERT INTO webhook_deliveries (endpoint_id, event_type, payload)
+		SELECT id, $1, $2
+		FROM webhook_endpoints
+		WHERE is_active AND (cardinality(event_types) = 0 OR $1 = ANY(event_types)) AND org_id = $3`,
+		string(eventType), body, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to queue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// EnqueueForEndpoint queues an event for a single endpoint, whatever it subscribes to, and returns the delivery
+func (r *WebhookRepository) EnqueueForEndpoint(ctx context.Context, endpointID int64, eventType string, payload []byte) (*models.WebhookDelivery, error) {
+	ctx = withQueryName(ctx, "WebhookRepository.EnqueueForEndpoint")
+	query := `
+		INSERT INTO webhook_deliveries (endpoint_id, event_type, payload)
+		VALUES ($1, $2, $3)
+		RETURNING ` + webhookDeliveryColumns
+
+	delivery, err := scanWebhookDelivery(r.pool.QueryRow(ctx, query, endpointID, eventType, payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// ClaimNextDelivery marks the oldest due delivery as sending, counts the attempt, and returns it, or nil
+// if none are due. Deliveries left sending longer than staleAfter (for example by a crashed worker) are
+// claimed again. SKIP LOCKED lets several workers poll the table without sending the same delivery.
+func (r *WebhookRepository) ClaimNextDelivery(ctx context.Context, staleAfter time.Duration) (*models.WebhookDelivery, error) {
+	ctx = withQueryName(ctx, "WebhookRepository.ClaimNextDelivery")
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'sending', attempts = attempts + 1, last_attempt_at = NOW()
+		WHERE id = (
+			SELECT id FROM webhook_deliveries
+			WHERE (status = 'pending' AND next_attempt_at <= NOW())
+			OR (status = 'sending' AND last_attempt_at < NOW() - $1 * INTERVAL '1 second')
+			ORDER BY next_attempt_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+
+	delivery, err := scanWebhookDelivery(r.pool.QueryRow(ctx, query, int64(staleAfter.Seconds())))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// MarkDelivered records a successful attempt
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	ctx = withQueryName(ctx, "WebhookRepository.MarkDelivered")
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', response_status = $2, last_error = NULL, delivered_at = NOW()
+		WHERE id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, id, responseStatus); err != nil {
+		return fmt.Errorf("failed to mark webhook delivered: %w", err)
+	}
+	return nil
+}
+
+// ScheduleRetry records a failed attempt and queues the next one
+func (r *WebhookRepository) ScheduleRetry(ctx context.Context, id int64, responseStatus *int, message string, nextAttemptAt time.Time) error {
+	ctx = withQueryName(ctx, "WebhookRepository.ScheduleRetry")
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', response_status = $2, last_error = $3, next_attempt_at = $4
+		WHERE id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, id, responseStatus, message, nextAttemptAt); err != nil {
+		return fmt.Errorf("failed to schedule webhook retry: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt after which no more are made
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id int64, responseStatus *int, message string) error {
+	ctx = withQueryName(ctx, "WebhookRepository.MarkFailed")
+	query := `UPDATE webhook_deliveries SET status = 'failed', response_status = $2, last_error = $3 WHERE id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, id, responseStatus, message); err != nil {
+		return fmt.Errorf("failed to mark webhook failed: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries retrieves an endpoint's most recent deliveries
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]models.WebhookDelivery, error) {
+	ctx = withQueryName(ctx, "WebhookRepository.ListDeliveries")
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE endpoint_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`
+
+	rows, err := r.pool.Query(ctx, query, endpointID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// PurgeDeliveries removes finished deliveries created before the cutoff and returns how many were removed
+func (r *WebhookRepository) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withQueryName(ctx, "WebhookRepository.PurgeDeliveries")
+	result, err := r.pool.Exec(ctx, `DELETE FROM webhook_deliveries WHERE status IN ('delivered', 'failed') AND created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge webhook deliveries: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// recordingExecer records the statements run through it instead of running them
+type recordingExecer struct {
+	args [][]any
+}
+
+func (e *recordingExecer) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	e.args = append(e.args, args)
+	return pgconn.CommandTag{}, nil
+}
+
+func TestEnqueueWebhooks(t *testing.T) {
+	orgCtx := tenant.WithOrgID(context.Background(), 2)
+
+	t.Run("queues the event for the organization", func(t *testing.T) {
+		exec := &recordingExecer{}
+		if err := enqueueWebhooks(orgCtx, exec, events.TaskCreated, map[string]int{"id": 7}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(exec.args) != 1 {
+			t.Fatalf("ran %d statements, want 1", len(exec.args))
+		}
+
+		args := exec.args[0]
+		if args[0] != "task.created" || args[2] != int64(2) {
+			t.Errorf("event type and organization = %v, %v", args[0], args[2])
+		}
+		var event struct {
+			Type    string         `json:"type"`
+			Payload map[string]int `json:"payload"`
+		}
+		if err := json.Unmarshal(args[1].([]byte), &event); err != nil || event.Type != "task.created" || event.Payload["id"] != 7 {
+			t.Errorf("body = %s", args[1])
+		}
+	})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		eventType events.Type
+	}{
+		{"directory changes aren't offered to webhooks", orgCtx, events.UserChanged},
+		{"changes made outside an organization", context.Background(), events.TaskCreated},
+		{"changes made across organizations", tenant.AcrossOrgs(context.Background()), events.MeetingUpdated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &recordingExecer{}
+			if err := enqueueWebhooks(tt.ctx, exec, tt.eventType, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(exec.args) != 0 {
+				t.Errorf("ran %d statements, want none", len(exec.args))
+			}
+		})
+	}
+}
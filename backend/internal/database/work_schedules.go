@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+type WorkScheduleRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWorkScheduleRepository(pool *pgxpool.Pool) *WorkScheduleRepository {
+	return &WorkScheduleRepository{pool: pool}
+}
+
+// GetForUsers returns the work schedule of each of the given users, keyed by user ID.
+// Users who haven't saved a schedule get the default one in their timezone.
+func (r *WorkScheduleRepository) GetForUsers(ctx context.Context, userIDs []int64) (map[int64]*models.WorkSchedule, error) {
+	ctx = withQueryName(ctx, "WorkScheduleRepository.GetForUsers")
+	rows, err := r.pool.Query(ctx, `
+		SELECT u.id, u.timezone, s.start_minute, s.end_minute, s.weekdays, s.focus_blocks, s.updated_at
+		FROM users u
+		LEFT JOIN user_work_schedules s ON s.user_id = u.id
+		WHERE u.id = ANY($1)
+	`, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make(map[int64]*models.WorkSchedule, len(userIDs))
+	for rows.Next() {
+		var userID int64
+		var timezone string
+		var startMinute, endMinute *int
+		var weekdays []int
+		var focusBlocks []byte
+		var updatedAt *time.Time
+		if err := rows.Scan(&userID, &timezone, &startMinute, &endMinute, &weekdays, &focusBlocks, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan work schedule: %w", err)
+		}
+
+		schedule := models.DefaultWorkSchedule(userID, timezone)
+		if updatedAt != nil {
+			schedule.StartMinute = *startMinute
+			schedule.EndMinute = *endMinute
+			schedule.Weekdays = weekdays
+			schedule.UpdatedAt = updatedAt
+			if err := json.Unmarshal(focusBlocks, &schedule.FocusBlocks); err != nil {
+				return nil, fmt.Errorf("failed to decode focus blocks: %w", err)
+			}
+		}
+		schedules[userID] = schedule
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get work schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// Get returns a user's work schedule, or the default schedule if they haven't saved one
+func (r *WorkScheduleRepository) Get(ctx context.Context, userID int64) (*models.WorkSchedule, error) {
+	ctx = withQueryName(ctx, "WorkScheduleRepository.Get")
+	schedules, err := r.GetForUsers(ctx, []int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	schedule, ok := schedules[userID]
+	if !ok {
+		return nil, fmt.Errorf("user %d not found", userID)
+	}
+	return schedule, nil
+}
+
+// Save replaces a user's working hours and focus blocks
+func (r *WorkScheduleRepository) Save(ctx context.Context, userID int64, req *models.UpdateWorkScheduleRequest) (*models.WorkSchedule, error) {
+	ctx = withQueryName(ctx, "WorkScheduleRepository.Save")
+	focusBlocks, err := json.Marshal(req.FocusBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode focus blocks: %w", err)
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO user_work_schedules (user_id, start_minute, end_minute, weekdays, focus_blocks, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			start_minute = EXCLUDED.start_minute,
+			end_minute = EXCLUDED.end_minute,
+			weekdays = EXCLUDED.weekdays,
+			focus_blocks = EXCLUDED.focus_blocks,
+			updated_at = NOW()
+	`, userID, req.StartMinute, req.EndMinute, req.Weekdays, focusBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save work schedule: %w", err)
+	}
+
+	return r.Get(ctx, userID)
+}
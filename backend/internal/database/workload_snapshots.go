@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// WorkloadSnapshotRepository records and reads people's daily workload snapshots
+type WorkloadSnapshotRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWorkloadSnapshotRepository creates a new workload snapshot repository
+func NewWorkloadSnapshotRepository(pool *pgxpool.Pool) *WorkloadSnapshotRepository {
+	return &WorkloadSnapshotRepository{pool: pool}
+}
+
+// Snapshot records the workload of every active user on the given day, replacing any snapshot
+// already taken that day, and returns how many users were recorded. Tasks due before the day are
+// overdue; Jira counts are the unresolved issues in the local cache assigned to each user.
+func (r *WorkloadSnapshotRepository) Snapshot(ctx context.Context, day time.Time) (int64, error) {
+	ctx = withQueryName(ctx, "WorkloadSnapshotRepository.Snapshot")
+	query := `
+		WITH open_tasks AS (
+			SELECT assigned_user_id AS user_id, COUNT(*) AS open,
+			       COUNT(*) FILTER (WHERE due_date < $1::date) AS overdue
+			FROM tasks
+			WHERE assigned_user_id IS NOT NULL AND deleted_at IS NULL AND status NOT IN ('completed', 'cancelled')
+			GROUP BY assigned_user_id
+		), jira_statuses AS (
+			SELECT assignee_account_id, COALESCE(data->>'status_category', '') AS category,
+			       COALESCE(data->>'status', '') AS status, COUNT(*) AS n
+			FROM jira_issues_cache
+			WHERE NOT resolved AND assignee_account_id IS NOT NULL
+			GROUP BY 1, 2, 3
+		), jira AS (
+			SELECT assignee_account_id, SUM(n) AS open, jsonb_object_agg(status, n) AS statuses
+			FROM (SELECT assignee_account_id, status, SUM(n) AS n FROM jira_statuses GROUP BY 1, 2) s
+			GROUP BY 1
+		), jira_categories AS (
+			SELECT assignee_account_id, jsonb_object_agg(category, n) AS categories
+			FROM (SELECT assignee_account_id, category, SUM(n) AS n FROM jira_statuses GROUP BY 1, 2) c
+			GROUP BY 1
+		)
+		INSERT INTO workload_snapshots (user_id, snapshot_date, org_id, open_tasks, overdue_tasks, jira_open,
+			jira_status_categories, jira_statuses)
+		SELECT u.id, $1::date, u.org_id, COALESCE(t.open, 0), COALESCE(t.overdue, 0), COALESCE(j.open, 0),
+		       COALESCE(jc.categories, '{}'), COALESCE(j.statuses, '{}')
+		FROM users u
+		LEFT JOIN open_tasks t ON t.user_id = u.id
+		LEFT JOIN jira j ON j.assignee_account_id = u.jira_account_id
+		LEFT JOIN jira_categories jc ON jc.assignee_account_id = u.jira_account_id
+		WHERE u.is_active = true AND u.deleted_at IS NULL AND ` + orgCondition("u.org_id", "$2") + `
+		ON CONFLICT (user_id, snapshot_date) DO UPDATE SET
+			open_tasks = EXCLUDED.open_tasks,
+			overdue_tasks = EXCLUDED.overdue_tasks,
+			jira_open = EXCLUDED.jira_open,
+			jira_status_categories = EXCLUDED.jira_status_categories,
+			jira_statuses = EXCLUDED.jira_statuses,
+			created_at = NOW()`
+
+	result, err := r.pool.Exec(ctx, query, day, orgScope(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to snapshot workloads: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ListForUsers returns the users' snapshots from the since day on, oldest first
+func (r *WorkloadSnapshotRepository) ListForUsers(ctx context.Context, userIDs []int64, since time.Time) ([]models.WorkloadSnapshot, error) {
+	ctx = withQueryName(ctx, "WorkloadSnapshotRepository.ListForUsers")
+	query := `
+		SELECT user_id, to_char(snapshot_date, 'YYYY-MM-DD'), open_tasks, overdue_tasks, jira_open,
+		       jira_status_categories, jira_statuses
+		FROM workload_snapshots
+		WHERE user_id = ANY($1) AND snapshot_date >= $2::date AND ` + orgCondition("org_id", "$3") + `
+		ORDER BY snapshot_date, user_id`
+
+	rows, err := r.pool.Query(ctx, query, userIDs, since, orgScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workload snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []models.WorkloadSnapshot{}
+	for rows.Next() {
+		var s models.WorkloadSnapshot
+		err := rows.Scan(&s.UserID, &s.Date, &s.OpenTasks, &s.OverdueTasks, &s.JiraOpen,
+			&s.JiraStatusCategories, &s.JiraStatuses)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workload snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, rows.Err()
+}
+
+// PurgeBefore removes snapshots taken before the given day
+func (r *WorkloadSnapshotRepository) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withQueryName(ctx, "WorkloadSnapshotRepository.PurgeBefore")
+	result, err := r.pool.Exec(ctx, `DELETE FROM workload_snapshots WHERE snapshot_date < $1::date`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge workload snapshots: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,155 @@
+// Package emailtemplates holds the built-in templates of the emails organizations can customize and
+// renders templates with an email's variables. Subjects and text bodies are text templates; HTML
+// bodies are HTML templates, so variables are escaped for the context they appear in.
+package emailtemplates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"maps"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+//go:embed templates/*.tmpl
+var builtIn embed.FS
+
+// dashboardURL is available to every email
+var dashboardURL = models.EmailTemplateVariable{Name: "DashboardURL", Description: "Address of the dashboard", Example: "https://dashboard.example.com"}
+
+// definitions are the customizable emails. Every one has a built-in template in each of
+// models.SupportedLanguages.
+var definitions = []models.EmailTemplateDefinition{
+	{
+		Key:         models.EmailTemplateInvitation,
+		Description: "Sent to someone invited to join the organization",
+		Variables: []models.EmailTemplateVariable{
+			{Name: "InviterName", Description: "Full name of the person who sent the invitation", Example: "Ada Lovelace"},
+			{Name: "Role", Description: "Role the invitee joins as", Example: "supervisor"},
+			{Name: "InviteLink", Description: "Link that accepts the invitation", Example: "https://dashboard.example.com/invite/example-token"},
+			dashboardURL,
+		},
+	},
+	{
+		Key:         models.EmailTemplateMeetingResponse,
+		Description: "Sent to a meeting's organizer when an attendee responds to their invite",
+		Variables: []models.EmailTemplateVariable{
+			{Name: "ResponderName", Description: "Full name of the attendee who responded", Example: "Grace Hopper"},
+			{Name: "Response", Description: "accepted, declined, or tentative", Example: "accepted"},
+			{Name: "MeetingTitle", Description: "Title of the meeting", Example: "Design sync"},
+			{Name: "StartTime", Description: "When the meeting starts, in UTC", Example: "Mon, Mar 9 at 15:00 UTC"},
+			{Name: "Accepted", Description: "How many attendees accepted", Example: "3"},
+			{Name: "Declined", Description: "How many attendees declined", Example: "1"},
+			{Name: "Tentative", Description: "How many attendees tentatively accepted", Example: "0"},
+			{Name: "Pending", Description: "How many attendees haven't responded", Example: "2"},
+			dashboardURL,
+		},
+	},
+}
+
+// Definitions returns the customizable emails
+func Definitions() []models.EmailTemplateDefinition {
+	defs := make([]models.EmailTemplateDefinition, len(definitions))
+	copy(defs, definitions)
+	return defs
+}
+
+// Lookup returns a customizable email's definition, or false if there's no such email
+func Lookup(key models.EmailTemplateKey) (*models.EmailTemplateDefinition, bool) {
+	for i := range definitions {
+		if definitions[i].Key == key {
+			def := definitions[i]
+			return &def, true
+		}
+	}
+	return nil, false
+}
+
+// Examples returns the example value of each of an email's variables
+func Examples(key models.EmailTemplateKey) map[string]string {
+	examples := make(map[string]string)
+	if def, ok := Lookup(key); ok {
+		for _, v := range def.Variables {
+			examples[v.Name] = v.Example
+		}
+	}
+	return examples
+}
+
+// Default returns an email's built-in template in a language, or false if either is unknown
+func Default(key models.EmailTemplateKey, language string) (*models.EmailTemplate, bool) {
+	if _, ok := Lookup(key); !ok {
+		return nil, false
+	}
+	var parts [3]string
+	for i, part := range []string{"subject", "html", "text"} {
+		data, err := builtIn.ReadFile(fmt.Sprintf("templates/%s.%s.%s.tmpl", key, language, part))
+		if err != nil {
+			return nil, false
+		}
+		parts[i] = string(data)
+	}
+	return &models.EmailTemplate{
+		Key:      key,
+		Language: language,
+		Subject:  strings.TrimSpace(parts[0]),
+		HTMLBody: parts[1],
+		TextBody: strings.TrimRight(parts[2], "\n"),
+	}, true
+}
+
+// Validate checks that a template parses and renders with its email's example variables
+func Validate(t *models.EmailTemplate) error {
+	_, err := Render(t, Examples(t.Key))
+	return err
+}
+
+// Render fills in a template with vars. Variables it doesn't have are left empty, and the subject
+// is kept to a single line.
+func Render(t *models.EmailTemplate, vars map[string]string) (*models.RenderedEmail, error) {
+	data := maps.Clone(vars)
+	if data == nil {
+		data = map[string]string{}
+	}
+
+	subject, err := renderText("subject", t.Subject, data)
+	if err != nil {
+		return nil, err
+	}
+	text, err := renderText("text body", t.TextBody, data)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := htmltemplate.New("html").Option("missingkey=zero").Parse(t.HTMLBody)
+	if err != nil {
+		return nil, fmt.Errorf("invalid html body: %w", err)
+	}
+	var html bytes.Buffer
+	if err := tmpl.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("invalid html body: %w", err)
+	}
+
+	return &models.RenderedEmail{
+		Subject: strings.Join(strings.Fields(subject), " "),
+		HTML:    html.String(),
+		Text:    text,
+	}, nil
+}
+
+// renderText fills in a text template
+func renderText(name, source string, data map[string]string) (string, error) {
+	tmpl, err := texttemplate.New(name).Option("missingkey=zero").Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", name, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,75 @@
+package emailtemplates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestDefault_EveryEmailInEveryLanguage(t *testing.T) {
+	for _, def := range Definitions() {
+		for _, language := range models.SupportedLanguages {
+			tmpl, ok := Default(def.Key, language)
+			if !ok {
+				t.Errorf("no built-in %s template in %s", def.Key, language)
+				continue
+			}
+			if err := Validate(tmpl); err != nil {
+				t.Errorf("built-in %s template in %s: %v", def.Key, language, err)
+			}
+		}
+	}
+
+	if _, ok := Default(models.EmailTemplateInvitation, "fr"); ok {
+		t.Error("Default() found a template in an unsupported language")
+	}
+	if _, ok := Default("carrier_pigeon", "en"); ok {
+		t.Error("Default() found a template for an unknown email")
+	}
+}
+
+func TestRender(t *testing.T) {
+	tmpl := &models.EmailTemplate{
+		Key:      models.EmailTemplateInvitation,
+		Subject:  "{{.InviterName}}\ninvited you{{.Missing}}",
+		HTMLBody: `<p>{{.InviterName}}</p><a href="{{.InviteLink}}">Join</a>`,
+		TextBody: "{{.InviterName}} invited you: {{.InviteLink}}",
+	}
+	rendered, err := Render(tmpl, map[string]string{
+		"InviterName": "<b>Ada</b>",
+		"InviteLink":  "javascript:alert(1)",
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if rendered.Subject != "<b>Ada</b> invited you" {
+		t.Errorf("Subject = %q, want one line with missing variables left empty", rendered.Subject)
+	}
+	if strings.Contains(rendered.HTML, "<b>") || strings.Contains(rendered.HTML, "javascript:") {
+		t.Errorf("HTML = %q, want variables escaped", rendered.HTML)
+	}
+	if rendered.Text != "<b>Ada</b> invited you: javascript:alert(1)" {
+		t.Errorf("Text = %q, want variables as is", rendered.Text)
+	}
+}
+
+func TestValidate_RejectsBrokenTemplates(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl models.EmailTemplate
+	}{
+		{"unclosed action", models.EmailTemplate{Subject: "{{.InviterName", HTMLBody: "<p></p>", TextBody: "text"}},
+		{"unknown function", models.EmailTemplate{Subject: "Hi", HTMLBody: "<p>{{shout .Role}}</p>", TextBody: "text"}},
+		{"field of a string", models.EmailTemplate{Subject: "Hi", HTMLBody: "<p></p>", TextBody: "{{.Role.Name}}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tmpl.Key = models.EmailTemplateInvitation
+			if err := Validate(&tt.tmpl); err == nil {
+				t.Error("Validate() = nil, want an error")
+			}
+		})
+	}
+}
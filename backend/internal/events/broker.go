@@ -0,0 +1,131 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// Type identifies the kind of change an event describes
+type Type string
+
+const (
+	TaskCreated      Type = "task.created"
+	TaskUpdated      Type = "task.updated"
+	TaskDeleted      Type = "task.deleted"
+	MeetingCreated   Type = "meeting.created"
+	MeetingUpdated   Type = "meeting.updated"
+	MeetingDeleted   Type = "meeting.deleted"
+	TimeOffRequested Type = "time_off.requested"
+	TimeOffReviewed  Type = "time_off.reviewed"
+	TimeOffCancelled Type = "time_off.cancelled"
+
+	// Directory changes carry no payload; they tell caches of derived views to refresh
+	UserChanged       Type = "user.changed"
+	SquadChanged      Type = "squad.changed"
+	OrgChartPublished Type = "org_chart.published"
+
+	// Jira issue changes carry the issue key; they come from Jira webhooks
+	JiraIssuesChanged Type = "jira.issues_changed"
+	// Jira task filter changes carry no payload
+	JiraTaskFiltersChanged Type = "jira.task_filters_changed"
+)
+
+// Event is a change notification published to subscribers
+type Event struct {
+	Type       Type        `json:"type"`
+	Payload    interface{} `json:"payload"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	// OrgID is the organization the change was made in, or 0 when it wasn't made in one, as
+	// by background jobs and Jira webhooks. Subscribers keep organizations' events apart by it.
+	OrgID int64 `json:"-"`
+}
+
+// DefaultBufferSize is the per-subscriber channel capacity
+const DefaultBufferSize = 32
+
+// Broker is a thread-safe in-process publish/subscribe hub.
+// Publishing never blocks: events are dropped for subscribers whose buffer is full.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]chan struct{} // Each subscriber's drop signal, if it asked for one
+	bufferSize  int
+}
+
+// NewBroker creates a broker with the given per-subscriber buffer size
+func NewBroker(bufferSize int) *Broker {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Broker{
+		subscribers: make(map[chan Event]chan struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe registers a new subscriber and returns its channel with an unsubscribe func
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	return b.subscribe(nil)
+}
+
+// SubscribeWithDrops is Subscribe for subscribers that must know when they miss events, such as
+// caches that would otherwise keep stale data. The second channel receives a value once events
+// have been dropped for the subscriber since it last received one.
+func (b *Broker) SubscribeWithDrops() (<-chan Event, <-chan struct{}, func()) {
+	dropped := make(chan struct{}, 1)
+	ch, unsubscribe := b.subscribe(dropped)
+	return ch, dropped, unsubscribe
+}
+
+func (b *Broker) subscribe(dropped chan struct{}) (<-chan Event, func()) {
+	ch := make(chan Event, b.bufferSize)
+
+	b.mu.Lock()
+	b.subscribers[ch] = dropped
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Publish delivers an event to all current subscribers, for the organization ctx is scoped to
+func (b *Broker) Publish(ctx context.Context, eventType Type, payload interface{}) {
+	if b == nil {
+		return
+	}
+
+	orgID, _ := tenant.OrgID(ctx)
+	event := Event{Type: eventType, Payload: payload, OccurredAt: time.Now(), OrgID: orgID}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch, dropped := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			if dropped != nil {
+				select {
+				case dropped <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// SubscriberCount returns the number of active subscribers
+func (b *Broker) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+func TestBroker_PublishDeliversToAllSubscribers(t *testing.T) {
+	b := NewBroker(4)
+
+	sub1, unsub1 := b.Subscribe()
+	defer unsub1()
+	sub2, unsub2 := b.Subscribe()
+	defer unsub2()
+
+	b.Publish(context.Background(), TaskCreated, "payload")
+
+	for i, sub := range []<-chan Event{sub1, sub2} {
+		select {
+		case ev := <-sub:
+			if ev.Type != TaskCreated {
+				t.Errorf("subscriber %d: expected %s, got %s", i, TaskCreated, ev.Type)
+			}
+			if ev.Payload != "payload" {
+				t.Errorf("subscriber %d: unexpected payload %v", i, ev.Payload)
+			}
+			if ev.OccurredAt.IsZero() {
+				t.Errorf("subscriber %d: expected OccurredAt to be set", i)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("subscriber %d: timed out waiting for event", i)
+		}
+	}
+}
+
+func TestBroker_Unsubscribe(t *testing.T) {
+	b := NewBroker(4)
+
+	sub, unsubscribe := b.Subscribe()
+	if b.SubscriberCount() != 1 {
+		t.Fatalf("expected 1 subscriber, got %d", b.SubscriberCount())
+	}
+
+	unsubscribe()
+	unsubscribe() // safe to call twice
+
+	if b.SubscriberCount() != 0 {
+		t.Errorf("expected 0 subscribers, got %d", b.SubscriberCount())
+	}
+	if _, ok := <-sub; ok {
+		t.Error("expected channel to be closed after unsubscribe")
+	}
+
+	// Publishing with no subscribers must not panic
+	b.Publish(context.Background(), MeetingDeleted, nil)
+}
+
+func TestBroker_PublishDoesNotBlockOnSlowSubscriber(t *testing.T) {
+	b := NewBroker(1)
+
+	sub, unsubscribe := b.Subscribe()
+	defer unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		b.Publish(context.Background(), TaskCreated, 1)
+		b.Publish(context.Background(), TaskUpdated, 2) // dropped: buffer full
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full subscriber buffer")
+	}
+
+	ev := <-sub
+	if ev.Type != TaskCreated {
+		t.Errorf("expected first event to be kept, got %s", ev.Type)
+	}
+	select {
+	case ev := <-sub:
+		t.Errorf("expected overflow event to be dropped, got %s", ev.Type)
+	default:
+	}
+}
+
+func TestBroker_SubscribeWithDropsSignalsDrops(t *testing.T) {
+	b := NewBroker(1)
+
+	sub, dropped, unsubscribe := b.SubscribeWithDrops()
+	defer unsubscribe()
+
+	b.Publish(context.Background(), TaskCreated, 1)
+	select {
+	case <-dropped:
+		t.Fatal("expected no drop signal while the buffer has room")
+	default:
+	}
+
+	b.Publish(context.Background(), TaskUpdated, 2) // dropped: buffer full
+	b.Publish(context.Background(), TaskDeleted, 3) // dropped too, signalled once
+	select {
+	case <-dropped:
+	default:
+		t.Fatal("expected a drop signal once the buffer overflowed")
+	}
+	select {
+	case <-dropped:
+		t.Error("expected drops since the last signal to be signalled once")
+	default:
+	}
+
+	if ev := <-sub; ev.Type != TaskCreated {
+		t.Errorf("expected first event to be kept, got %s", ev.Type)
+	}
+}
+
+func TestBroker_PublishRecordsOrganization(t *testing.T) {
+	b := NewBroker(4)
+	sub, unsubscribe := b.Subscribe()
+	defer unsubscribe()
+
+	b.Publish(tenant.WithOrgID(context.Background(), 7), TaskCreated, nil)
+	b.Publish(context.Background(), JiraIssuesChanged, "PROJ-1")
+
+	if ev := <-sub; ev.OrgID != 7 {
+		t.Errorf("expected organization 7, got %d", ev.OrgID)
+	}
+	if ev := <-sub; ev.OrgID != 0 {
+		t.Errorf("expected no organization outside one, got %d", ev.OrgID)
+	}
+}
+
+func TestBroker_NilIsNoop(t *testing.T) {
+	var b *Broker
+	b.Publish(context.Background(), TimeOffReviewed, nil)
+}
+
+func TestNewBroker_DefaultBufferSize(t *testing.T) {
+	b := NewBroker(0)
+	if b.bufferSize != DefaultBufferSize {
+		t.Errorf("expected buffer size %d, got %d", DefaultBufferSize, b.bufferSize)
+	}
+}
@@ -0,0 +1,163 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// apiURL is the GitHub REST API
+const apiURL = "https://api.github.com"
+
+// maxSearchResults is the most results GitHub's search API returns per page
+const maxSearchResults = 100
+
+// Client represents a GitHub API client authenticated as a GitHub App user
+type Client struct {
+	accessToken string
+	baseURL     string
+	httpClient  *http.Client
+}
+
+// NewClient creates a new GitHub client with an OAuth access token
+func NewClient(accessToken string) *Client {
+	return &Client{
+		accessToken: accessToken,
+		baseURL:     apiURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// WithBaseURL points the client at another API, such as a GitHub Enterprise Server instance
+func (c *Client) WithBaseURL(baseURL string) *Client {
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+	return c
+}
+
+// doRequest performs an authenticated GET request to the GitHub API
+func (c *Client) doRequest(path string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	return resp, nil
+}
+
+// GetAuthenticatedLogin returns the login of the account the token belongs to
+func (c *Client) GetAuthenticatedLogin() (string, error) {
+	resp, err := c.doRequest("/user")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to get user (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return user.Login, nil
+}
+
+// GetPullRequests returns open pull requests the login is assigned to or has been asked to
+// review, most recently updated first. When org is set only its repositories are searched.
+func (c *Client) GetPullRequests(login string, role models.GitHubPullRequestRole, org string, maxResults int) ([]models.GitHubPullRequest, error) {
+	qualifier := "assignee"
+	if role == models.GitHubPullRequestReviewer {
+		qualifier = "review-requested"
+	}
+	query := fmt.Sprintf("is:pr is:open archived:false %s:%s", qualifier, login)
+	if org != "" {
+		query += " org:" + org
+	}
+
+	params := url.Values{
+		"q":        {query},
+		"sort":     {"updated"},
+		"order":    {"desc"},
+		"per_page": {fmt.Sprintf("%d", min(max(maxResults, 1), maxSearchResults))},
+	}
+	resp, err := c.doRequest("/search/issues?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to search pull requests (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Items []searchItem `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	pullRequests := make([]models.GitHubPullRequest, 0, len(result.Items))
+	for _, item := range result.Items {
+		pullRequests = append(pullRequests, item.toPullRequest())
+	}
+	return pullRequests, nil
+}
+
+// searchItem is an issue search result, which for pull requests is the PR's issue view
+type searchItem struct {
+	Number        int       `json:"number"`
+	Title         string    `json:"title"`
+	HTMLURL       string    `json:"html_url"`
+	RepositoryURL string    `json:"repository_url"`
+	Draft         bool      `json:"draft"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	User          struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+func (i searchItem) toPullRequest() models.GitHubPullRequest {
+	labels := make([]string, len(i.Labels))
+	for j, l := range i.Labels {
+		labels[j] = l.Name
+	}
+	// repository_url is https://api.github.com/repos/{owner}/{name}
+	_, repository, _ := strings.Cut(i.RepositoryURL, "/repos/")
+	return models.GitHubPullRequest{
+		Number:     i.Number,
+		Title:      i.Title,
+		URL:        i.HTMLURL,
+		Repository: repository,
+		Author:     i.User.Login,
+		Draft:      i.Draft,
+		Labels:     labels,
+		Created:    i.CreatedAt,
+		Updated:    i.UpdatedAt,
+	}
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestClient_GetPullRequests(t *testing.T) {
+	var gotQuery, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query().Get("q")
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"items":[{
+			"number": 42,
+			"title": "Add login page",
+			"html_url": "https://github.com/acme/web/pull/42",
+			"repository_url": "https://api.github.com/repos/acme/web",
+			"draft": true,
+			"user": {"login": "octocat"},
+			"labels": [{"name": "frontend"}],
+			"created_at": "2024-03-01T10:00:00Z",
+			"updated_at": "2024-03-02T10:00:00Z"
+		}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token").WithBaseURL(server.URL + "/")
+
+	tests := []struct {
+		name      string
+		role      models.GitHubPullRequestRole
+		org       string
+		wantQuery string
+	}{
+		{"assigned in org", models.GitHubPullRequestAssignee, "acme", "is:pr is:open archived:false assignee:ada-l org:acme"},
+		{"review requests anywhere", models.GitHubPullRequestReviewer, "", "is:pr is:open archived:false review-requested:ada-l"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pullRequests, err := client.GetPullRequests("ada-l", tt.role, tt.org, 10)
+			if err != nil {
+				t.Fatalf("GetPullRequests() error = %v", err)
+			}
+			if gotQuery != tt.wantQuery {
+				t.Errorf("query = %q, want %q", gotQuery, tt.wantQuery)
+			}
+			if gotAuth != "Bearer token" {
+				t.Errorf("Authorization = %q", gotAuth)
+			}
+			if len(pullRequests) != 1 {
+				t.Fatalf("got %d pull requests, want 1", len(pullRequests))
+			}
+			pr := pullRequests[0]
+			if pr.Number != 42 || pr.Repository != "acme/web" || pr.Author != "octocat" || !pr.Draft || len(pr.Labels) != 1 || pr.Labels[0] != "frontend" {
+				t.Errorf("pull request = %+v", pr)
+			}
+		})
+	}
+}
+
+func TestClient_GetPullRequests_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient("token").WithBaseURL(server.URL).GetPullRequests("ada-l", models.GitHubPullRequestAssignee, "", 10)
+	if err == nil {
+		t.Error("GetPullRequests() error = nil, want an error for a 403")
+	}
+}
@@ -0,0 +1,125 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+)
+
+const (
+	// GitHub OAuth endpoints for GitHub App user authorization
+	authorizationURL = "https://github.com/login/oauth/authorize"
+	tokenURL         = "https://github.com/login/oauth/access_token"
+)
+
+// OAuthService handles the GitHub App user authorization flow
+type OAuthService struct {
+	clientID     string
+	clientSecret string
+	callbackURL  string
+	tokenURL     string
+	httpClient   *http.Client
+}
+
+// NewOAuthService creates a new GitHub OAuth service
+func NewOAuthService(cfg *config.Config) *OAuthService {
+	return &OAuthService{
+		clientID:     cfg.GitHubClientID,
+		clientSecret: cfg.GitHubClientSecret,
+		callbackURL:  cfg.GitHubCallbackURL,
+		tokenURL:     tokenURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// TokenResponse represents the OAuth token response from GitHub.
+// ExpiresIn is zero when the app has token expiration turned off.
+type TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in"` // seconds
+	TokenType        string `json:"token_type"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// GetAuthorizationURL returns the URL to redirect the user to for authorization.
+// GitHub Apps get their permissions from the app's settings, so no scope is requested.
+func (s *OAuthService) GetAuthorizationURL(state string) string {
+	params := url.Values{
+		"client_id":    {s.clientID},
+		"redirect_uri": {s.callbackURL},
+		"state":        {state},
+	}
+	return authorizationURL + "?" + params.Encode()
+}
+
+// ExchangeCode exchanges an authorization code for tokens
+func (s *OAuthService) ExchangeCode(code string) (*TokenResponse, error) {
+	return s.requestToken(url.Values{
+		"client_id":     {s.clientID},
+		"client_secret": {s.clientSecret},
+		"code":          {code},
+		"redirect_uri":  {s.callbackURL},
+	})
+}
+
+// RefreshAccessToken refreshes an expired access token
+func (s *OAuthService) RefreshAccessToken(refreshToken string) (*TokenResponse, error) {
+	return s.requestToken(url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {s.clientID},
+		"client_secret": {s.clientSecret},
+		"refresh_token": {refreshToken},
+	})
+}
+
+// requestToken posts to the token endpoint. GitHub reports OAuth errors with a 200 status
+// and an error field, so both are checked.
+func (s *OAuthService) requestToken(data url.Values) (*TokenResponse, error) {
+	req, err := http.NewRequest("POST", s.tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokenResp.Error != "" {
+		return nil, fmt.Errorf("token request failed: %s: %s", tokenResp.Error, tokenResp.ErrorDescription)
+	}
+
+	return &tokenResp, nil
+}
+
+// CalculateExpiry calculates the token expiry time from expires_in seconds, or nil for tokens that don't expire
+func CalculateExpiry(expiresIn int) *time.Time {
+	if expiresIn <= 0 {
+		return nil
+	}
+	expiry := time.Now().Add(time.Duration(expiresIn) * time.Second)
+	return &expiry
+}
@@ -0,0 +1,70 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+)
+
+func TestOAuthService_GetAuthorizationURL(t *testing.T) {
+	s := NewOAuthService(&config.Config{GitHubClientID: "Iv1.abc", GitHubCallbackURL: "https://dash.example.com/api/github/oauth/callback"})
+
+	u, err := url.Parse(s.GetAuthorizationURL("state-123"))
+	if err != nil {
+		t.Fatalf("invalid URL: %v", err)
+	}
+	q := u.Query()
+	if q.Get("client_id") != "Iv1.abc" || q.Get("state") != "state-123" || q.Get("redirect_uri") != "https://dash.example.com/api/github/oauth/callback" {
+		t.Errorf("authorization URL params = %v", q)
+	}
+}
+
+func TestOAuthService_ExchangeCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		wantErr    bool
+		wantExpiry bool
+	}{
+		{"expiring token", `{"access_token":"ghu_1","refresh_token":"ghr_1","expires_in":28800}`, false, true},
+		{"non-expiring token", `{"access_token":"ghu_1"}`, false, false},
+		{"error with 200 status", `{"error":"bad_verification_code","error_description":"The code passed is incorrect or expired."}`, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Accept") != "application/json" {
+					t.Errorf("Accept = %q", r.Header.Get("Accept"))
+				}
+				_ = r.ParseForm()
+				if r.PostForm.Get("code") != "code-1" {
+					t.Errorf("code = %q", r.PostForm.Get("code"))
+				}
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			s := NewOAuthService(&config.Config{GitHubClientID: "id", GitHubClientSecret: "secret"})
+			s.tokenURL = server.URL
+
+			resp, err := s.ExchangeCode("code-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExchangeCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), "bad_verification_code") {
+					t.Errorf("error = %v, want GitHub's error code", err)
+				}
+				return
+			}
+			if (CalculateExpiry(resp.ExpiresIn) != nil) != tt.wantExpiry {
+				t.Errorf("expiry = %v, want expiring = %v", CalculateExpiry(resp.ExpiresIn), tt.wantExpiry)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// AuditMutations records an audit event for every GraphQL mutation once it has run, keeping the
+// response data as the after state. Queries are sent as POST requests too, so the REST audit
+// middleware can't tell them apart; this runs on the parsed operation instead.
+func AuditMutations(recorder middleware.AuditRecorder) graphql.OperationMiddleware {
+	log := logger.Default().WithComponent("audit")
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		oc := graphql.GetOperationContext(ctx)
+		if oc.Operation == nil || oc.Operation.Operation != ast.Mutation {
+			return next(ctx)
+		}
+
+		var fields []*ast.Field
+		for _, sel := range oc.Operation.SelectionSet {
+			if field, ok := sel.(*ast.Field); ok {
+				fields = append(fields, field)
+			}
+		}
+
+		responses := next(ctx)
+		recorded := false
+		return func(ctx context.Context) *graphql.Response {
+			resp := responses(ctx)
+			if recorded || len(fields) == 0 {
+				return resp
+			}
+			recorded = true
+
+			names := make([]string, len(fields))
+			for i, field := range fields {
+				names[i] = field.Name
+			}
+			event := middleware.NewAuditEvent(ctx, "mutation "+strings.Join(names, ","))
+			event.EntityType = mutationEntity(fields[0].Name)
+			if id, ok := fields[0].ArgumentMap(oc.Variables)["id"]; ok && id != nil {
+				entityID := fmt.Sprint(id)
+				event.EntityID = &entityID
+			}
+			event.Status = http.StatusOK
+			if resp != nil && len(resp.Errors) > 0 {
+				event.Status = http.StatusUnprocessableEntity
+			} else if resp != nil {
+				event.After = resp.Data
+			}
+
+			if err := recorder.Create(context.WithoutCancel(ctx), event); err != nil {
+				log.LogError(ctx, "Failed to record audit event", err, "action", event.Action)
+			}
+			return resp
+		}
+	}
+}
+
+// mutationEntity derives the entity a mutation changes from its name, e.g. updateEmployee -> employee
+func mutationEntity(name string) string {
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			return strings.ToLower(name[i:i+1]) + name[i+1:]
+		}
+	}
+	return name
+}
@@ -2,10 +2,10 @@ package graph
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/graph-gophers/dataloader/v7"
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/repository"
 )
@@ -14,22 +14,39 @@ import (
 type Loaders struct {
 	// SupervisorLoader batches supervisor lookups by ID
 	SupervisorLoader *dataloader.Loader[int64, *models.User]
+	// SquadsLoader batches squad lookups by user ID
+	SquadsLoader *dataloader.Loader[int64, []models.Squad]
+	// DirectReportsLoader batches direct report lookups by supervisor ID
+	DirectReportsLoader *dataloader.Loader[int64, []models.User]
+	// PendingTimeOffCountLoader batches pending time off counts by user ID
+	PendingTimeOffCountLoader *dataloader.Loader[int64, int]
+	// OpenTaskCountLoader batches open task counts by assignee ID
+	OpenTaskCountLoader *dataloader.Loader[int64, int]
 }
 
 // loaderContextKey is the context key for storing loaders
 type loaderContextKey struct{}
 
 // NewLoaders creates a new set of dataloaders
-func NewLoaders(userRepo repository.UserRepository) *Loaders {
+func NewLoaders(userRepo repository.UserRepository, squadRepo repository.SquadRepository, timeOffRepo repository.TimeOffRepository, taskRepo repository.TaskRepository) *Loaders {
 	return &Loaders{
-		SupervisorLoader: dataloader.NewBatchedLoader(
-			newSupervisorBatchFunc(userRepo),
-			dataloader.WithWait[int64, *models.User](2*time.Millisecond),
-			dataloader.WithBatchCapacity[int64, *models.User](100),
-		),
+		SupervisorLoader:          newLoader(newSupervisorBatchFunc(userRepo)),
+		SquadsLoader:              newLoader(newGroupedBatchFunc(squadRepo.GetByUserIDs)),
+		DirectReportsLoader:       newLoader(newGroupedBatchFunc(userRepo.GetDirectReportsBySupervisorIDs)),
+		PendingTimeOffCountLoader: newLoader(newGroupedBatchFunc(timeOffRepo.CountPendingByUserIDs)),
+		OpenTaskCountLoader:       newLoader(newGroupedBatchFunc(taskRepo.CountOpenByAssigneeIDs)),
 	}
 }
 
+// newLoader creates a batched loader with the shared wait and batch size settings
+func newLoader[V any](batchFn dataloader.BatchFunc[int64, V]) *dataloader.Loader[int64, V] {
+	return dataloader.NewBatchedLoader(
+		batchFn,
+		dataloader.WithWait[int64, V](2*time.Millisecond),
+		dataloader.WithBatchCapacity[int64, V](100),
+	)
+}
+
 // ContextWithLoaders adds loaders to context
 func ContextWithLoaders(ctx context.Context, loaders *Loaders) context.Context {
 	return context.WithValue(ctx, loaderContextKey{}, loaders)
@@ -67,7 +84,7 @@ func newSupervisorBatchFunc(userRepo repository.UserRepository) dataloader.Batch
 				results[i] = &dataloader.Result[*models.User]{Data: user}
 			} else {
 				results[i] = &dataloader.Result[*models.User]{
-					Error: fmt.Errorf("supervisor not found: %d", key),
+					Error: apperrors.NewNotFoundError("Supervisor"),
 				}
 			}
 		}
@@ -76,6 +93,71 @@ func newSupervisorBatchFunc(userRepo repository.UserRepository) dataloader.Batch
 	}
 }
 
+// newGroupedBatchFunc returns a batch function for repository methods that look up many keys at
+// once and return a map. Keys missing from the map resolve to the zero value (no rows, zero count).
+func newGroupedBatchFunc[V any](fetch func(ctx context.Context, keys []int64) (map[int64]V, error)) dataloader.BatchFunc[int64, V] {
+	return func(ctx context.Context, keys []int64) []*dataloader.Result[V] {
+		values, err := fetch(ctx, keys)
+
+		// Return results in the same order as keys
+		results := make([]*dataloader.Result[V], len(keys))
+		for i, key := range keys {
+			if err != nil {
+				results[i] = &dataloader.Result[V]{Error: err}
+			} else {
+				results[i] = &dataloader.Result[V]{Data: values[key]}
+			}
+		}
+
+		return results
+	}
+}
+
+// loadOne looks up a single key with a batch repository method, for when loaders aren't in context
+func loadOne[V any](ctx context.Context, fetch func(ctx context.Context, keys []int64) (map[int64]V, error), key int64) (V, error) {
+	values, err := fetch(ctx, []int64{key})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return values[key], nil
+}
+
+// GetSquads loads a user's squads using the dataloader, falling back to direct query if loaders not in context
+func GetSquads(ctx context.Context, squadRepo repository.SquadRepository, userID int64) ([]models.Squad, error) {
+	if loaders := LoadersFromContext(ctx); loaders != nil && loaders.SquadsLoader != nil {
+		return loaders.SquadsLoader.Load(ctx, userID)()
+	}
+	return loadOne(ctx, squadRepo.GetByUserIDs, userID)
+}
+
+// GetDirectReports loads a supervisor's active direct reports using the dataloader, falling back to direct query
+// if loaders not in context
+func GetDirectReports(ctx context.Context, userRepo repository.UserRepository, supervisorID int64) ([]models.User, error) {
+	if loaders := LoadersFromContext(ctx); loaders != nil && loaders.DirectReportsLoader != nil {
+		return loaders.DirectReportsLoader.Load(ctx, supervisorID)()
+	}
+	return loadOne(ctx, userRepo.GetDirectReportsBySupervisorIDs, supervisorID)
+}
+
+// GetPendingTimeOffCount loads how many pending time off requests a user has using the dataloader, falling back
+// to direct query if loaders not in context
+func GetPendingTimeOffCount(ctx context.Context, timeOffRepo repository.TimeOffRepository, userID int64) (int, error) {
+	if loaders := LoadersFromContext(ctx); loaders != nil && loaders.PendingTimeOffCountLoader != nil {
+		return loaders.PendingTimeOffCountLoader.Load(ctx, userID)()
+	}
+	return loadOne(ctx, timeOffRepo.CountPendingByUserIDs, userID)
+}
+
+// GetOpenTaskCount loads how many open tasks are assigned to a user using the dataloader, falling back to direct
+// query if loaders not in context
+func GetOpenTaskCount(ctx context.Context, taskRepo repository.TaskRepository, userID int64) (int, error) {
+	if loaders := LoadersFromContext(ctx); loaders != nil && loaders.OpenTaskCountLoader != nil {
+		return loaders.OpenTaskCountLoader.Load(ctx, userID)()
+	}
+	return loadOne(ctx, taskRepo.CountOpenByAssigneeIDs, userID)
+}
+
 // GetSupervisor loads a supervisor using the dataloader, falling back to direct query if loaders not in context
 func GetSupervisor(ctx context.Context, userRepo repository.UserRepository, supervisorID int64) (*models.User, error) {
 	loaders := LoadersFromContext(ctx)
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestLoaders_BatchNestedEmployeeFields(t *testing.T) {
+	supervisorID := int64(1)
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.Users[1] = &models.User{ID: 1, Role: models.RoleSupervisor}
+	userRepo.Users[2] = &models.User{ID: 2, Role: models.RoleEmployee, SupervisorID: &supervisorID}
+	userRepo.Users[3] = &models.User{ID: 3, Role: models.RoleEmployee, SupervisorID: &supervisorID}
+	reportBatches := 0
+	userRepo.GetDirectReportsBySupervisorIDsFunc = func(ctx context.Context, supervisorIDs []int64) (map[int64][]models.User, error) {
+		reportBatches++
+		result := make(map[int64][]models.User)
+		for _, id := range supervisorIDs {
+			result[id], _ = userRepo.GetDirectReportsBySupervisorID(ctx, id)
+		}
+		return result, nil
+	}
+
+	squadRepo := mocks.NewMockSquadRepository()
+	squadRepo.Squads[10] = &models.Squad{ID: 10, Name: "Platform"}
+	squadRepo.UserSquads[2] = []int64{10}
+	squadBatches := 0
+	squadRepo.GetByUserIDsFunc = func(ctx context.Context, userIDs []int64) (map[int64][]models.Squad, error) {
+		squadBatches++
+		return map[int64][]models.Squad{2: {*squadRepo.Squads[10]}}, nil
+	}
+
+	timeOffRepo := mocks.NewMockTimeOffRepository()
+	timeOffRepo.Requests[1] = &models.TimeOffRequest{ID: 1, UserID: 2, Status: models.TimeOffStatusPending}
+	timeOffRepo.Requests[2] = &models.TimeOffRequest{ID: 2, UserID: 2, Status: models.TimeOffStatusApproved}
+
+	taskRepo := mocks.NewMockTaskRepository()
+	taskRepo.Tasks[1] = &models.Task{ID: 1, Status: models.TaskStatusInProgress, AssignedUserID: &supervisorID}
+	taskRepo.Tasks[2] = &models.Task{ID: 2, Status: models.TaskStatusCompleted, AssignedUserID: &supervisorID}
+
+	loaders := NewLoaders(userRepo, squadRepo, timeOffRepo, taskRepo)
+	ctx := ContextWithLoaders(context.Background(), loaders)
+
+	// Queue every key before resolving any, as gqlgen does when resolving a list of employees
+	ids := []int64{1, 2, 3}
+	squadThunks := make(map[int64]func() ([]models.Squad, error))
+	reportThunks := make(map[int64]func() ([]models.User, error))
+	for _, id := range ids {
+		squadThunks[id] = loaders.SquadsLoader.Load(ctx, id)
+		reportThunks[id] = loaders.DirectReportsLoader.Load(ctx, id)
+	}
+
+	for _, id := range ids {
+		squads, err := squadThunks[id]()
+		if err != nil {
+			t.Fatalf("squads for %d error = %v", id, err)
+		}
+		if want := len(squadRepo.UserSquads[id]); len(squads) != want {
+			t.Errorf("user %d has %d squads, want %d", id, len(squads), want)
+		}
+		reports, err := reportThunks[id]()
+		if err != nil {
+			t.Fatalf("direct reports for %d error = %v", id, err)
+		}
+		if want := map[int64]int{1: 2}[id]; len(reports) != want {
+			t.Errorf("user %d has %d direct reports, want %d", id, len(reports), want)
+		}
+	}
+	if squadBatches != 1 || reportBatches != 1 {
+		t.Errorf("squad batches = %d, report batches = %d, want 1 each", squadBatches, reportBatches)
+	}
+
+	pending, err := GetPendingTimeOffCount(ctx, timeOffRepo, 2)
+	if err != nil || pending != 1 {
+		t.Errorf("GetPendingTimeOffCount() = %d, %v, want 1", pending, err)
+	}
+	open, err := GetOpenTaskCount(ctx, taskRepo, 1)
+	if err != nil || open != 1 {
+		t.Errorf("GetOpenTaskCount() = %d, %v, want 1", open, err)
+	}
+	if none, err := GetOpenTaskCount(ctx, taskRepo, 3); err != nil || none != 0 {
+		t.Errorf("GetOpenTaskCount() for user without tasks = %d, %v, want 0", none, err)
+	}
+}
+
+func TestGetSquads_WithoutLoaders(t *testing.T) {
+	squadRepo := mocks.NewMockSquadRepository()
+	squadRepo.Squads[10] = &models.Squad{ID: 10, Name: "Platform"}
+	squadRepo.UserSquads[2] = []int64{10}
+
+	squads, err := GetSquads(context.Background(), squadRepo, 2)
+	if err != nil {
+		t.Fatalf("GetSquads() error = %v", err)
+	}
+	if len(squads) != 1 || squads[0].Name != "Platform" {
+		t.Errorf("GetSquads() = %v, want [Platform]", squads)
+	}
+}
@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrorPresenter reports resolver errors the way the REST API does: the message is the
+// user-facing one, and the code, status, details, and request ID go in the extensions. Errors
+// other than AppErrors are logged and reported as internal errors, so database errors aren't
+// exposed. Query parsing and validation errors pass through unchanged.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if gqlErr.Err == nil {
+		return gqlErr
+	}
+
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || appErr.HTTPStatus() >= http.StatusInternalServerError {
+		logger.FromContext(ctx).LogError(ctx, "GraphQL resolver error", err, "path", gqlErr.Path.String())
+	}
+
+	apiErr := apperrors.ToAPIError(err)
+	gqlErr.Message = apiErr.Message
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]interface{}{}
+	}
+	gqlErr.Extensions["code"] = apiErr.Code
+	gqlErr.Extensions["status"] = apiErr.Status
+	if apiErr.Details != nil {
+		gqlErr.Extensions["details"] = apiErr.Details
+	}
+	if requestID, ok := ctx.Value(logger.RequestIDKey).(string); ok {
+		gqlErr.Extensions["request_id"] = requestID
+	}
+	return gqlErr
+}
+
+// invalidID reports an ID argument or field that isn't a number
+func invalidID(field string, err error) error {
+	return &apperrors.AppError{
+		Type:    apperrors.ErrorTypeValidation,
+		Code:    apperrors.CodeInvalidFormat,
+		Message: "Invalid " + field,
+		Field:   field,
+		Err:     err,
+	}
+}
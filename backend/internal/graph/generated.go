@@ -49,22 +49,24 @@ type DirectiveRoot struct {
 
 type ComplexityRoot struct {
 	Employee struct {
-		Auth0ID       func(childComplexity int) int
-		AvatarURL     func(childComplexity int) int
-		CreatedAt     func(childComplexity int) int
-		DateStarted   func(childComplexity int) int
-		Department    func(childComplexity int) int
-		DirectReports func(childComplexity int) int
-		Email         func(childComplexity int) int
-		FirstName     func(childComplexity int) int
-		ID            func(childComplexity int) int
-		LastName      func(childComplexity int) int
-		Role          func(childComplexity int) int
-		Squads        func(childComplexity int) int
-		Supervisor    func(childComplexity int) int
-		SupervisorID  func(childComplexity int) int
-		Title         func(childComplexity int) int
-		UpdatedAt     func(childComplexity int) int
+		Auth0ID             func(childComplexity int) int
+		AvatarURL           func(childComplexity int) int
+		CreatedAt           func(childComplexity int) int
+		DateStarted         func(childComplexity int) int
+		Department          func(childComplexity int) int
+		DirectReports       func(childComplexity int) int
+		Email               func(childComplexity int) int
+		FirstName           func(childComplexity int) int
+		ID                  func(childComplexity int) int
+		LastName            func(childComplexity int) int
+		OpenTaskCount       func(childComplexity int) int
+		PendingTimeOffCount func(childComplexity int) int
+		Role                func(childComplexity int) int
+		Squads              func(childComplexity int) int
+		Supervisor          func(childComplexity int) int
+		SupervisorID        func(childComplexity int) int
+		Title               func(childComplexity int) int
+		UpdatedAt           func(childComplexity int) int
 	}
 
 	Mutation struct {
@@ -86,8 +88,13 @@ type ComplexityRoot struct {
 }
 
 type EmployeeResolver interface {
+	Squads(ctx context.Context, obj *Employee) ([]*Squad, error)
+
 	Supervisor(ctx context.Context, obj *Employee) (*Employee, error)
 	DirectReports(ctx context.Context, obj *Employee) ([]*Employee, error)
+
+	PendingTimeOffCount(ctx context.Context, obj *Employee) (*int, error)
+	OpenTaskCount(ctx context.Context, obj *Employee) (int, error)
 }
 type MutationResolver interface {
 	CreateEmployee(ctx context.Context, input CreateEmployeeInput) (*Employee, error)
@@ -179,6 +186,18 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.Employee.LastName(childComplexity), true
+	case "Employee.open_task_count":
+		if e.complexity.Employee.OpenTaskCount == nil {
+			break
+		}
+
+		return e.complexity.Employee.OpenTaskCount(childComplexity), true
+	case "Employee.pending_time_off_count":
+		if e.complexity.Employee.PendingTimeOffCount == nil {
+			break
+		}
+
+		return e.complexity.Employee.PendingTimeOffCount(childComplexity), true
 	case "Employee.role":
 		if e.complexity.Employee.Role == nil {
 			break
@@ -764,7 +783,7 @@ func (ec *executionContext) _Employee_squads(ctx context.Context, field graphql.
 		field,
 		ec.fieldContext_Employee_squads,
 		func(ctx context.Context) (any, error) {
-			return obj.Squads, nil
+			return ec.resolvers.Employee().Squads(ctx, obj)
 		},
 		nil,
 		ec.marshalNSquad2ᚕᚖgithubᚗcomᚋsmithᚑdallinᚋmanagerᚑdashboardᚋinternalᚋgraphᚐSquadᚄ,
@@ -777,8 +796,8 @@ func (ec *executionContext) fieldContext_Employee_squads(_ context.Context, fiel
 	fc = &graphql.FieldContext{
 		Object:     "Employee",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
 			case "id":
@@ -902,6 +921,10 @@ func (ec *executionContext) fieldContext_Employee_supervisor(_ context.Context,
 				return ec.fieldContext_Employee_direct_reports(ctx, field)
 			case "date_started":
 				return ec.fieldContext_Employee_date_started(ctx, field)
+			case "pending_time_off_count":
+				return ec.fieldContext_Employee_pending_time_off_count(ctx, field)
+			case "open_task_count":
+				return ec.fieldContext_Employee_open_task_count(ctx, field)
 			case "created_at":
 				return ec.fieldContext_Employee_created_at(ctx, field)
 			case "updated_at":
@@ -965,6 +988,10 @@ func (ec *executionContext) fieldContext_Employee_direct_reports(_ context.Conte
 				return ec.fieldContext_Employee_direct_reports(ctx, field)
 			case "date_started":
 				return ec.fieldContext_Employee_date_started(ctx, field)
+			case "pending_time_off_count":
+				return ec.fieldContext_Employee_pending_time_off_count(ctx, field)
+			case "open_task_count":
+				return ec.fieldContext_Employee_open_task_count(ctx, field)
 			case "created_at":
 				return ec.fieldContext_Employee_created_at(ctx, field)
 			case "updated_at":
@@ -1005,6 +1032,64 @@ func (ec *executionContext) fieldContext_Employee_date_started(_ context.Context
 	return fc, nil
 }
 
+func (ec *executionContext) _Employee_pending_time_off_count(ctx context.Context, field graphql.CollectedField, obj *Employee) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Employee_pending_time_off_count,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Employee().PendingTimeOffCount(ctx, obj)
+		},
+		nil,
+		ec.marshalOInt2ᚖint,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Employee_pending_time_off_count(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Employee",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Employee_open_task_count(ctx context.Context, field graphql.CollectedField, obj *Employee) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Employee_open_task_count,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Employee().OpenTaskCount(ctx, obj)
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Employee_open_task_count(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Employee",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Employee_created_at(ctx context.Context, field graphql.CollectedField, obj *Employee) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -1116,6 +1201,10 @@ func (ec *executionContext) fieldContext_Mutation_createEmployee(ctx context.Con
 				return ec.fieldContext_Employee_direct_reports(ctx, field)
 			case "date_started":
 				return ec.fieldContext_Employee_date_started(ctx, field)
+			case "pending_time_off_count":
+				return ec.fieldContext_Employee_pending_time_off_count(ctx, field)
+			case "open_task_count":
+				return ec.fieldContext_Employee_open_task_count(ctx, field)
 			case "created_at":
 				return ec.fieldContext_Employee_created_at(ctx, field)
 			case "updated_at":
@@ -1191,6 +1280,10 @@ func (ec *executionContext) fieldContext_Mutation_updateEmployee(ctx context.Con
 				return ec.fieldContext_Employee_direct_reports(ctx, field)
 			case "date_started":
 				return ec.fieldContext_Employee_date_started(ctx, field)
+			case "pending_time_off_count":
+				return ec.fieldContext_Employee_pending_time_off_count(ctx, field)
+			case "open_task_count":
+				return ec.fieldContext_Employee_open_task_count(ctx, field)
 			case "created_at":
 				return ec.fieldContext_Employee_created_at(ctx, field)
 			case "updated_at":
@@ -1306,6 +1399,10 @@ func (ec *executionContext) fieldContext_Query_employees(_ context.Context, fiel
 				return ec.fieldContext_Employee_direct_reports(ctx, field)
 			case "date_started":
 				return ec.fieldContext_Employee_date_started(ctx, field)
+			case "pending_time_off_count":
+				return ec.fieldContext_Employee_pending_time_off_count(ctx, field)
+			case "open_task_count":
+				return ec.fieldContext_Employee_open_task_count(ctx, field)
 			case "created_at":
 				return ec.fieldContext_Employee_created_at(ctx, field)
 			case "updated_at":
@@ -1370,6 +1467,10 @@ func (ec *executionContext) fieldContext_Query_employee(ctx context.Context, fie
 				return ec.fieldContext_Employee_direct_reports(ctx, field)
 			case "date_started":
 				return ec.fieldContext_Employee_date_started(ctx, field)
+			case "pending_time_off_count":
+				return ec.fieldContext_Employee_pending_time_off_count(ctx, field)
+			case "open_task_count":
+				return ec.fieldContext_Employee_open_task_count(ctx, field)
 			case "created_at":
 				return ec.fieldContext_Employee_created_at(ctx, field)
 			case "updated_at":
@@ -1444,6 +1545,10 @@ func (ec *executionContext) fieldContext_Query_me(_ context.Context, field graph
 				return ec.fieldContext_Employee_direct_reports(ctx, field)
 			case "date_started":
 				return ec.fieldContext_Employee_date_started(ctx, field)
+			case "pending_time_off_count":
+				return ec.fieldContext_Employee_pending_time_off_count(ctx, field)
+			case "open_task_count":
+				return ec.fieldContext_Employee_open_task_count(ctx, field)
 			case "created_at":
 				return ec.fieldContext_Employee_created_at(ctx, field)
 			case "updated_at":
@@ -3293,10 +3398,41 @@ func (ec *executionContext) _Employee(ctx context.Context, sel ast.SelectionSet,
 				atomic.AddUint32(&out.Invalids, 1)
 			}
 		case "squads":
-			out.Values[i] = ec._Employee_squads(ctx, field, obj)
-			if out.Values[i] == graphql.Null {
-				atomic.AddUint32(&out.Invalids, 1)
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Employee_squads(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
 			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 		case "avatar_url":
 			out.Values[i] = ec._Employee_avatar_url(ctx, field, obj)
 		case "supervisor_id":
@@ -3369,6 +3505,75 @@ func (ec *executionContext) _Employee(ctx context.Context, sel ast.SelectionSet,
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 		case "date_started":
 			out.Values[i] = ec._Employee_date_started(ctx, field, obj)
+		case "pending_time_off_count":
+			field := field
+
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Employee_pending_time_off_count(ctx, field, obj)
+				return res
+			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+		case "open_task_count":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Employee_open_task_count(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 		case "created_at":
 			out.Values[i] = ec._Employee_created_at(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -4049,6 +4254,22 @@ func (ec *executionContext) marshalNID2string(ctx context.Context, sel ast.Selec
 	return res
 }
 
+func (ec *executionContext) unmarshalNInt2int(ctx context.Context, v any) (int, error) {
+	res, err := graphql.UnmarshalInt(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNInt2int(ctx context.Context, sel ast.SelectionSet, v int) graphql.Marshaler {
+	_ = sel
+	res := graphql.MarshalInt(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+	}
+	return res
+}
+
 func (ec *executionContext) unmarshalNRole2githubᚗcomᚋsmithᚑdallinᚋmanagerᚑdashboardᚋinternalᚋgraphᚐRole(ctx context.Context, v any) (Role, error) {
 	tmp, err := graphql.UnmarshalString(v)
 	res := Role(tmp)
@@ -4548,6 +4769,24 @@ func (ec *executionContext) marshalOID2ᚖstring(ctx context.Context, sel ast.Se
 	return res
 }
 
+func (ec *executionContext) unmarshalOInt2ᚖint(ctx context.Context, v any) (*int, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := graphql.UnmarshalInt(v)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOInt2ᚖint(ctx context.Context, sel ast.SelectionSet, v *int) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	_ = sel
+	_ = ctx
+	res := graphql.MarshalInt(*v)
+	return res
+}
+
 func (ec *executionContext) unmarshalORole2ᚖgithubᚗcomᚋsmithᚑdallinᚋmanagerᚑdashboardᚋinternalᚋgraphᚐRole(ctx context.Context, v any) (*Role, error) {
 	if v == nil {
 		return nil, nil
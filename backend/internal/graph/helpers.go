@@ -3,6 +3,7 @@ package graph
 import (
 	"strconv"
 
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 )
 
@@ -13,15 +14,6 @@ func userToEmployee(u *models.User) *Employee {
 		supervisorID = &s
 	}
 
-	// Convert squads from models.Squad to graph.Squad
-	squads := make([]*Squad, len(u.Squads))
-	for i, s := range u.Squads {
-		squads[i] = &Squad{
-			ID:   strconv.FormatInt(s.ID, 10),
-			Name: s.Name,
-		}
-	}
-
 	return &Employee{
 		ID:           strconv.FormatInt(u.ID, 10),
 		Auth0ID:      u.Auth0ID,
@@ -31,7 +23,6 @@ func userToEmployee(u *models.User) *Employee {
 		Role:         Role(u.Role),
 		Title:        u.Title,
 		Department:   u.Department,
-		Squads:       squads,
 		AvatarURL:    u.AvatarURL,
 		SupervisorID: supervisorID,
 		DateStarted:  u.DateStarted,
@@ -39,3 +30,33 @@ func userToEmployee(u *models.User) *Employee {
 		UpdatedAt:    u.UpdatedAt,
 	}
 }
+
+// squadsToGraph converts squads from models.Squad to graph.Squad
+func squadsToGraph(modelSquads []models.Squad) []*Squad {
+	squads := make([]*Squad, len(modelSquads))
+	for i, s := range modelSquads {
+		squads[i] = &Squad{
+			ID:   strconv.FormatInt(s.ID, 10),
+			Name: s.Name,
+		}
+	}
+	return squads
+}
+
+// employeeResource describes an employee for authorization checks
+func employeeResource(e *Employee) (*authz.Resource, error) {
+	employeeID, err := strconv.ParseInt(e.ID, 10, 64)
+	if err != nil {
+		return nil, invalidID("id", err)
+	}
+
+	resource := &authz.Resource{OwnerID: employeeID}
+	if e.SupervisorID != nil {
+		supervisorID, err := strconv.ParseInt(*e.SupervisorID, 10, 64)
+		if err != nil {
+			return nil, invalidID("supervisor_id", err)
+		}
+		resource.SupervisorID = &supervisorID
+	}
+	return resource, nil
+}
@@ -37,7 +37,6 @@ type Employee struct {
 	Role         Role       `json:"role"`
 	Title        string     `json:"title"`
 	Department   string     `json:"department"`
-	Squads       []*Squad   `json:"squads"`
 	AvatarURL    *string    `json:"avatar_url,omitempty"`
 	SupervisorID *string    `json:"supervisor_id,omitempty"`
 	DateStarted  *time.Time `json:"date_started,omitempty"`
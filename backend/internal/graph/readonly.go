@@ -0,0 +1,31 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ReadOnlyMutationGuard rejects mutations from users the authorizer marks read-only.
+// GraphQL queries are sent as POST requests, so the REST write-access middleware can't be used here.
+func ReadOnlyMutationGuard(authz middleware.WriteAuthorizer) graphql.OperationMiddleware {
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		oc := graphql.GetOperationContext(ctx)
+		if oc.Operation == nil || oc.Operation.Operation != ast.Mutation {
+			return next(ctx)
+		}
+
+		user := middleware.GetUserFromContext(ctx)
+		if user == nil {
+			return next(ctx)
+		}
+
+		if err := authz.CanModify(user); err != nil {
+			return graphql.OneShot(&graphql.Response{Errors: gqlerror.List{ErrorPresenter(ctx, err)}})
+		}
+		return next(ctx)
+	}
+}
@@ -3,6 +3,7 @@ package graph
 import (
 	"github.com/smith-dallin/manager-dashboard/internal/auth0"
 	"github.com/smith-dallin/manager-dashboard/internal/database"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
 	"github.com/smith-dallin/manager-dashboard/internal/logger"
 	"github.com/smith-dallin/manager-dashboard/internal/services"
 )
@@ -14,13 +15,17 @@ import (
 type Resolver struct {
 	UserRepo        *database.UserRepository
 	SquadRepo       *database.SquadRepository
+	TimeOffRepo     *database.TimeOffRepository
+	TaskRepo        *database.TaskRepository
 	OrgJiraRepo     *database.OrgJiraRepository
 	Auth0Client     *auth0.ManagementClient
 	FrontendURL     string
 	EmployeeService *services.EmployeeService
+	Broker          *events.Broker             // Optional; notified when employee mutations change the directory
+	Auth0Sync       *services.Auth0SyncService // Optional; blocks deleted employees in Auth0
 }
 
-func NewResolver(userRepo *database.UserRepository, squadRepo *database.SquadRepository, orgJiraRepo *database.OrgJiraRepository, auth0Client *auth0.ManagementClient, emailService *services.EmailService, frontendURL string, log *logger.Logger) *Resolver {
+func NewResolver(userRepo *database.UserRepository, squadRepo *database.SquadRepository, timeOffRepo *database.TimeOffRepository, taskRepo *database.TaskRepository, orgJiraRepo *database.OrgJiraRepository, auth0Client *auth0.ManagementClient, emailService *services.EmailService, frontendURL string, log *logger.Logger) *Resolver {
 	// Create auth0 adapter for EmployeeService
 	// Important: Only create the adapter if auth0Client is not nil to avoid
 	// the Go nil interface issue where a nil pointer stored in an interface
@@ -36,6 +41,8 @@ func NewResolver(userRepo *database.UserRepository, squadRepo *database.SquadRep
 	return &Resolver{
 		UserRepo:        userRepo,
 		SquadRepo:       squadRepo,
+		TimeOffRepo:     timeOffRepo,
+		TaskRepo:        taskRepo,
 		OrgJiraRepo:     orgJiraRepo,
 		Auth0Client:     auth0Client,
 		FrontendURL:     frontendURL,
@@ -7,14 +7,32 @@ package graph
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
 	"github.com/smith-dallin/manager-dashboard/internal/middleware"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/services"
 )
 
+// Squads is the resolver for the squads field.
+// Uses dataloader to batch squad queries and prevent N+1 problem
+func (r *employeeResolver) Squads(ctx context.Context, obj *Employee) ([]*Squad, error) {
+	employeeID, err := strconv.ParseInt(obj.ID, 10, 64)
+	if err != nil {
+		return nil, invalidID("id", err)
+	}
+
+	squads, err := GetSquads(ctx, r.SquadRepo, employeeID)
+	if err != nil {
+		return nil, apperrors.FromRepository(err, "Squad")
+	}
+
+	return squadsToGraph(squads), nil
+}
+
 // Supervisor is the resolver for the supervisor field.
 // Uses dataloader to batch supervisor queries and prevent N+1 problem
 func (r *employeeResolver) Supervisor(ctx context.Context, obj *Employee) (*Employee, error) {
@@ -24,13 +42,13 @@ func (r *employeeResolver) Supervisor(ctx context.Context, obj *Employee) (*Empl
 
 	supervisorID, err := strconv.ParseInt(*obj.SupervisorID, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid supervisor ID: %w", err)
+		return nil, invalidID("supervisor_id", err)
 	}
 
 	// Use dataloader for batched loading (falls back to direct query if loaders not in context)
 	user, err := GetSupervisor(ctx, r.UserRepo, supervisorID)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.FromRepository(err, "Supervisor")
 	}
 
 	return userToEmployee(user), nil
@@ -40,12 +58,13 @@ func (r *employeeResolver) Supervisor(ctx context.Context, obj *Employee) (*Empl
 func (r *employeeResolver) DirectReports(ctx context.Context, obj *Employee) ([]*Employee, error) {
 	employeeID, err := strconv.ParseInt(obj.ID, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid employee ID: %w", err)
+		return nil, invalidID("id", err)
 	}
 
-	users, err := r.UserRepo.GetDirectReportsBySupervisorID(ctx, employeeID)
+	// Use dataloader for batched loading (falls back to direct query if loaders not in context)
+	users, err := GetDirectReports(ctx, r.UserRepo, employeeID)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.FromRepository(err, "Employee")
 	}
 
 	employees := make([]*Employee, len(users))
@@ -56,15 +75,57 @@ func (r *employeeResolver) DirectReports(ctx context.Context, obj *Employee) ([]
 	return employees, nil
 }
 
+// PendingTimeOffCount is the resolver for the pending_time_off_count field.
+// Uses dataloader to batch count queries and prevent N+1 problem
+func (r *employeeResolver) PendingTimeOffCount(ctx context.Context, obj *Employee) (*int, error) {
+	currentUser := middleware.GetUserFromContext(ctx)
+	if currentUser == nil {
+		return nil, apperrors.NewUnauthorizedError("")
+	}
+
+	resource, err := employeeResource(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nested employees (e.g. a supervisor) may be visible without their time off being visible
+	if !authz.Can(currentUser, authz.ActionTimeOffView, resource) {
+		return nil, nil
+	}
+
+	count, err := GetPendingTimeOffCount(ctx, r.TimeOffRepo, resource.OwnerID)
+	if err != nil {
+		return nil, apperrors.FromRepository(err, "Time off request")
+	}
+
+	return &count, nil
+}
+
+// OpenTaskCount is the resolver for the open_task_count field.
+// Uses dataloader to batch count queries and prevent N+1 problem
+func (r *employeeResolver) OpenTaskCount(ctx context.Context, obj *Employee) (int, error) {
+	employeeID, err := strconv.ParseInt(obj.ID, 10, 64)
+	if err != nil {
+		return 0, invalidID("id", err)
+	}
+
+	count, err := GetOpenTaskCount(ctx, r.TaskRepo, employeeID)
+	if err != nil {
+		return 0, apperrors.FromRepository(err, "Task")
+	}
+
+	return count, nil
+}
+
 // CreateEmployee is the resolver for the createEmployee field.
 func (r *mutationResolver) CreateEmployee(ctx context.Context, input CreateEmployeeInput) (*Employee, error) {
 	// Check authorization - only supervisors and admins can create employees
 	currentUser := middleware.GetUserFromContext(ctx)
 	if currentUser == nil {
-		return nil, fmt.Errorf("unauthorized")
+		return nil, apperrors.NewUnauthorizedError("")
 	}
-	if currentUser.Role != models.RoleSupervisor && currentUser.Role != models.RoleAdmin {
-		return nil, fmt.Errorf("forbidden: only supervisors and admins can create employees")
+	if !authz.Can(currentUser, authz.ActionUserCreate, nil) {
+		return nil, apperrors.NewForbiddenError("Forbidden: only supervisors and admins can create employees")
 	}
 
 	// Convert supervisor ID from string to int64
@@ -72,20 +133,23 @@ func (r *mutationResolver) CreateEmployee(ctx context.Context, input CreateEmplo
 	if input.SupervisorID != nil {
 		id, err := strconv.ParseInt(*input.SupervisorID, 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("invalid supervisor ID: %w", err)
+			return nil, invalidID("supervisor_id", err)
 		}
 		supervisorID = &id
 	} else {
 		// Default to current user as supervisor
 		supervisorID = &currentUser.ID
 	}
+	if !authz.Can(currentUser, authz.ActionUserCreate, &authz.Resource{SupervisorID: supervisorID}) {
+		return nil, apperrors.NewForbiddenError("Forbidden: can only create employees reporting to you")
+	}
 
 	// Convert squad IDs from string to int64
 	var squadIDs []int64
 	for _, squadIDStr := range input.SquadIDs {
 		squadID, err := strconv.ParseInt(squadIDStr, 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("invalid squad ID %s: %w", squadIDStr, err)
+			return nil, invalidID("squad_ids", err)
 		}
 		squadIDs = append(squadIDs, squadID)
 	}
@@ -105,8 +169,9 @@ func (r *mutationResolver) CreateEmployee(ctx context.Context, input CreateEmplo
 
 	result, err := r.EmployeeService.CreateEmployee(ctx, serviceInput)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.FromRepository(err, "Employee")
 	}
+	r.Broker.Publish(ctx, events.UserChanged, nil)
 
 	return userToEmployee(result.User), nil
 }
@@ -116,30 +181,23 @@ func (r *mutationResolver) UpdateEmployee(ctx context.Context, id string, input
 	// Check authorization
 	currentUser := middleware.GetUserFromContext(ctx)
 	if currentUser == nil {
-		return nil, fmt.Errorf("unauthorized")
+		return nil, apperrors.NewUnauthorizedError("")
 	}
 
 	employeeID, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid employee ID: %w", err)
+		return nil, invalidID("id", err)
 	}
 
 	// Get the target user
 	targetUser, err := r.UserRepo.GetByID(ctx, employeeID)
 	if err != nil {
-		return nil, fmt.Errorf("employee not found: %w", err)
+		return nil, apperrors.FromRepository(err, "Employee")
 	}
 
 	// Check permissions
-	if currentUser.Role == models.RoleEmployee && currentUser.ID != employeeID {
-		return nil, fmt.Errorf("forbidden: employees can only update themselves")
-	}
-
-	if currentUser.Role == models.RoleSupervisor &&
-		targetUser.SupervisorID != nil &&
-		*targetUser.SupervisorID != currentUser.ID &&
-		targetUser.ID != currentUser.ID {
-		return nil, fmt.Errorf("forbidden: can only update your direct reports")
+	if !authz.Can(currentUser, authz.ActionUserUpdate, authz.UserResource(targetUser)) {
+		return nil, apperrors.NewForbiddenError("Forbidden: can only update yourself or your direct reports")
 	}
 
 	// Convert input to UpdateUserRequest
@@ -147,13 +205,16 @@ func (r *mutationResolver) UpdateEmployee(ctx context.Context, id string, input
 	if input.Role != nil {
 		r := models.Role(*input.Role)
 		role = &r
+		if r != targetUser.Role && !authz.Can(currentUser, authz.ActionUserChangeRole, authz.UserResource(targetUser)) {
+			return nil, apperrors.NewForbiddenError("Forbidden: cannot change role")
+		}
 	}
 
 	var supervisorID *int64
 	if input.SupervisorID != nil {
 		id, err := strconv.ParseInt(*input.SupervisorID, 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("invalid supervisor ID: %w", err)
+			return nil, invalidID("supervisor_id", err)
 		}
 		supervisorID = &id
 	}
@@ -163,7 +224,7 @@ func (r *mutationResolver) UpdateEmployee(ctx context.Context, id string, input
 	for _, squadIDStr := range input.SquadIDs {
 		squadID, err := strconv.ParseInt(squadIDStr, 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("invalid squad ID %s: %w", squadIDStr, err)
+			return nil, invalidID("squad_ids", err)
 		}
 		squadIDs = append(squadIDs, squadID)
 	}
@@ -180,42 +241,48 @@ func (r *mutationResolver) UpdateEmployee(ctx context.Context, id string, input
 
 	user, err := r.UserRepo.Update(ctx, employeeID, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update employee: %w", err)
+		return nil, apperrors.FromRepository(err, "Employee")
 	}
+	r.Broker.Publish(ctx, events.UserChanged, nil)
 
 	return userToEmployee(user), nil
 }
 
 // DeleteEmployee is the resolver for the deleteEmployee field.
 func (r *mutationResolver) DeleteEmployee(ctx context.Context, id string) (bool, error) {
-	// Check authorization - only supervisors can delete employees
+	// Check authorization - only admins and supervisors can delete employees
 	currentUser := middleware.GetUserFromContext(ctx)
 	if currentUser == nil {
-		return false, fmt.Errorf("unauthorized")
+		return false, apperrors.NewUnauthorizedError("")
 	}
-	if currentUser.Role != models.RoleSupervisor {
-		return false, fmt.Errorf("forbidden: only supervisors can delete employees")
+	if !authz.Can(currentUser, authz.ActionUserDelete, nil) {
+		return false, apperrors.NewForbiddenError("Forbidden: only admins and supervisors can delete employees")
 	}
 
 	employeeID, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		return false, fmt.Errorf("invalid employee ID: %w", err)
+		return false, invalidID("id", err)
 	}
 
 	// Get the target user to check permissions
 	targetUser, err := r.UserRepo.GetByID(ctx, employeeID)
 	if err != nil {
-		return false, fmt.Errorf("employee not found: %w", err)
+		return false, apperrors.FromRepository(err, "Employee")
 	}
 
 	// Check if the target is a direct report of this supervisor
-	if targetUser.SupervisorID == nil || *targetUser.SupervisorID != currentUser.ID {
-		return false, fmt.Errorf("forbidden: can only delete your own direct reports")
+	if targetUser.ID == currentUser.ID {
+		return false, apperrors.NewValidationError("Cannot delete yourself")
+	}
+	if !authz.Can(currentUser, authz.ActionUserDelete, authz.UserResource(targetUser)) {
+		return false, apperrors.NewForbiddenError("Forbidden: can only delete your own direct reports")
 	}
 
 	if err := r.UserRepo.Delete(ctx, employeeID); err != nil {
-		return false, fmt.Errorf("failed to delete employee: %w", err)
+		return false, apperrors.FromRepository(err, "Employee")
 	}
+	r.Auth0Sync.Block(ctx, targetUser)
+	r.Broker.Publish(ctx, events.UserChanged, nil)
 
 	return true, nil
 }
@@ -224,13 +291,16 @@ func (r *mutationResolver) DeleteEmployee(ctx context.Context, id string) (bool,
 func (r *queryResolver) Employees(ctx context.Context) ([]*Employee, error) {
 	currentUser := middleware.GetUserFromContext(ctx)
 	if currentUser == nil {
-		return nil, fmt.Errorf("unauthorized")
+		return nil, apperrors.NewUnauthorizedError("")
 	}
 
 	var users []models.User
 	var err error
 
-	if currentUser.Role == models.RoleSupervisor {
+	if authz.CanAll(currentUser, authz.ActionUserView) {
+		// Admins and viewers see the whole org
+		users, err = r.UserRepo.GetAll(ctx)
+	} else if authz.CanForOthers(currentUser, authz.ActionUserView) {
 		// Supervisors see their direct reports
 		users, err = r.UserRepo.GetDirectReportsBySupervisorID(ctx, currentUser.ID)
 	} else {
@@ -239,7 +309,7 @@ func (r *queryResolver) Employees(ctx context.Context) ([]*Employee, error) {
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch employees: %w", err)
+		return nil, apperrors.FromRepository(err, "Employee")
 	}
 
 	employees := make([]*Employee, len(users))
@@ -254,30 +324,27 @@ func (r *queryResolver) Employees(ctx context.Context) ([]*Employee, error) {
 func (r *queryResolver) Employee(ctx context.Context, id string) (*Employee, error) {
 	currentUser := middleware.GetUserFromContext(ctx)
 	if currentUser == nil {
-		return nil, fmt.Errorf("unauthorized")
+		return nil, apperrors.NewUnauthorizedError("")
 	}
 
 	employeeID, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid employee ID: %w", err)
+		return nil, invalidID("id", err)
 	}
 
 	// Employees can only view themselves
-	if currentUser.Role == models.RoleEmployee && currentUser.ID != employeeID {
-		return nil, fmt.Errorf("forbidden")
+	if currentUser.ID != employeeID && !authz.CanForOthers(currentUser, authz.ActionUserView) {
+		return nil, apperrors.NewForbiddenError("")
 	}
 
 	user, err := r.UserRepo.GetByID(ctx, employeeID)
 	if err != nil {
-		return nil, fmt.Errorf("employee not found: %w", err)
+		return nil, apperrors.FromRepository(err, "Employee")
 	}
 
 	// Supervisors can only view their own direct reports
-	if currentUser.Role == models.RoleSupervisor &&
-		user.SupervisorID != nil &&
-		*user.SupervisorID != currentUser.ID &&
-		user.ID != currentUser.ID {
-		return nil, fmt.Errorf("forbidden")
+	if !authz.Can(currentUser, authz.ActionUserView, authz.UserResource(user)) {
+		return nil, apperrors.NewForbiddenError("")
 	}
 
 	return userToEmployee(user), nil
@@ -287,7 +354,7 @@ func (r *queryResolver) Employee(ctx context.Context, id string) (*Employee, err
 func (r *queryResolver) Me(ctx context.Context) (*Employee, error) {
 	currentUser := middleware.GetUserFromContext(ctx)
 	if currentUser == nil {
-		return nil, fmt.Errorf("unauthorized")
+		return nil, apperrors.NewUnauthorizedError("")
 	}
 
 	return userToEmployee(currentUser), nil
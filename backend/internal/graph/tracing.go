@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/smith-dallin/manager-dashboard/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceResponses records a span for each GraphQL operation, named after its type and operation
+// name, that parents the spans of its resolvers. The HTTP span only shows POST /graphql, which
+// says nothing about which query was slow.
+func TraceResponses(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	name := "graphql"
+	var attrs []attribute.KeyValue
+	if oc := graphql.GetOperationContext(ctx); oc.Operation != nil {
+		name += " " + string(oc.Operation.Operation)
+		attrs = append(attrs, attribute.String("graphql.operation.type", string(oc.Operation.Operation)))
+		if oc.OperationName != "" {
+			name += " " + oc.OperationName
+			attrs = append(attrs, attribute.String("graphql.operation.name", oc.OperationName))
+		}
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+	defer span.End()
+
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetStatus(codes.Error, resp.Errors.Error())
+	}
+	return resp
+}
+
+// TraceResolvers records a span for each field backed by a resolver. Fields read straight off a
+// struct cost nothing and are skipped so traces stay readable.
+func TraceResolvers(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, fc.Object+"."+fc.Field.Name,
+		trace.WithAttributes(attribute.String("graphql.field.path", fc.Path().String())))
+	defer span.End()
+
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
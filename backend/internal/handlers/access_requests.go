@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// AccessRequestHandlers let people who sign in without an account ask to join the organization
+// their email domain belongs to, and admins approve or reject them
+type AccessRequestHandlers struct {
+	requestRepo repository.AccessRequestRepository
+	orgRepo     repository.OrganizationRepository
+	userRepo    repository.UserRepository
+	onboarding  *services.OnboardingService
+	logger      *logger.Logger
+}
+
+// NewAccessRequestHandlers creates a new access request handlers instance
+func NewAccessRequestHandlers(requestRepo repository.AccessRequestRepository, orgRepo repository.OrganizationRepository, userRepo repository.UserRepository) *AccessRequestHandlers {
+	return &AccessRequestHandlers{
+		requestRepo: requestRepo,
+		orgRepo:     orgRepo,
+		userRepo:    userRepo,
+		logger:      logger.Default().WithComponent("access-request-handlers"),
+	}
+}
+
+// RequestAccess godoc
+// @Summary Request access
+// @Description Asks to join the organization whose invitation domains include the signed-in email's domain. Only for people signed in without an account, when self-service signup is enabled.
+// @Tags Access Requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateAccessRequestRequest true "Access request"
+// @Success 201 {object} models.AccessRequest "Pending access request"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Email domain can't request access"
+// @Failure 409 {object} map[string]interface{} "Already has an account or a pending request"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /access-requests [post]
+func (h *AccessRequestHandlers) RequestAccess(w http.ResponseWriter, r *http.Request) {
+	ident := middleware.GetIdentityFromContext(r.Context())
+	if ident == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.CreateAccessRequestRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	at := strings.LastIndex(ident.Email, "@")
+	if at < 0 {
+		respondError(w, http.StatusBadRequest, "Your sign-in has no email address to request access with")
+		return
+	}
+	if user, _ := h.userRepo.GetByAuth0ID(r.Context(), ident.Subject); user != nil {
+		respondError(w, http.StatusConflict, "You already have an account")
+		return
+	}
+	if user, _ := h.userRepo.GetByEmail(r.Context(), ident.Email); user != nil {
+		respondError(w, http.StatusConflict, "An account already exists for your email")
+		return
+	}
+
+	domain := strings.ToLower(ident.Email[at+1:])
+	orgID, err := h.orgRepo.GetByInvitationDomain(r.Context(), domain)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to find organization for access request", err, "domain", domain)
+		respondError(w, http.StatusInternalServerError, "Failed to request access")
+		return
+	}
+	if orgID == nil {
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Access can't be requested with an email at %s; ask an admin for an invitation", domain))
+		return
+	}
+
+	firstName, lastName, _ := strings.Cut(strings.TrimSpace(ident.Name), " ")
+	request, err := h.requestRepo.Create(r.Context(), &models.AccessRequest{
+		OrgID:     *orgID,
+		Auth0ID:   ident.Subject,
+		Email:     ident.Email,
+		FirstName: firstName,
+		LastName:  strings.TrimSpace(lastName),
+		Message:   req.Message,
+	})
+	if err != nil {
+		respondRepositoryError(w, r, err, "Access request")
+		return
+	}
+
+	h.logger.Info("Access requested", "access_request_id", request.ID, "org_id", request.OrgID, "email", request.Email)
+	respondJSON(w, http.StatusCreated, request)
+}
+
+// GetMyAccessRequest godoc
+// @Summary Get my access request
+// @Description Returns the signed-in person's most recent access request, so they can see whether it's been reviewed
+// @Tags Access Requests
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.AccessRequest "Access request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "No access request"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /access-requests/mine [get]
+func (h *AccessRequestHandlers) GetMyAccessRequest(w http.ResponseWriter, r *http.Request) {
+	ident := middleware.GetIdentityFromContext(r.Context())
+	if ident == nil {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	request, err := h.requestRepo.GetLatestByAuth0ID(r.Context(), ident.Subject)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get access request", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch access request")
+		return
+	}
+	if request == nil {
+		respondError(w, http.StatusNotFound, "Access request not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, request)
+}
+
+// ListAccessRequests godoc
+// @Summary List access requests
+// @Description Returns the organization's access requests, oldest first. Admin only.
+// @Tags Access Requests
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Only requests with this status" Enums(pending, approved, rejected)
+// @Success 200 {array} models.AccessRequest "Access requests"
+// @Failure 400 {object} map[string]interface{} "Invalid status"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden - admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/access-requests [get]
+func (h *AccessRequestHandlers) ListAccessRequests(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionInvitationManage) == nil {
+		return
+	}
+
+	var status *models.AccessRequestStatus
+	if v := r.URL.Query().Get("status"); v != "" {
+		s := models.AccessRequestStatus(v)
+		if !models.ValidAccessRequestStatuses[s] {
+			respondError(w, http.StatusBadRequest, "status must be 'pending', 'approved', or 'rejected'")
+			return
+		}
+		status = &s
+	}
+
+	requests, err := h.requestRepo.List(r.Context(), status)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list access requests", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch access requests")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, requests)
+}
+
+// ApproveAccessRequest godoc
+// @Summary Approve an access request
+// @Description Creates the requester's account with the given role, department, title, supervisor, and squads. Employees report to the approver unless a supervisor is given. Admin only.
+// @Tags Access Requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Access request ID"
+// @Param request body models.ApproveAccessRequestRequest true "Account details"
+// @Success 200 {object} models.UserResponse "Created user"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden - admin access required"
+// @Failure 404 {object} map[string]interface{} "Access request not found"
+// @Failure 409 {object} map[string]interface{} "Already reviewed, or the account already exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/access-requests/{id}/approve [post]
+func (h *AccessRequestHandlers) ApproveAccessRequest(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionInvitationManage)
+	if currentUser == nil {
+		return
+	}
+
+	request := h.getPendingRequest(w, r)
+	if request == nil {
+		return
+	}
+
+	var req models.ApproveAccessRequestRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+	if req.Role == models.RoleEmployee && req.SupervisorID == nil {
+		req.SupervisorID = &currentUser.ID
+	}
+	if req.SupervisorID != nil && !canSupervise(r, h.userRepo, *req.SupervisorID) {
+		respondError(w, http.StatusBadRequest, "Supervisor must be a supervisor or admin in your organization")
+		return
+	}
+
+	user, err := h.requestRepo.Approve(r.Context(), request.ID, &req, currentUser.ID)
+	if err != nil {
+		respondRepositoryError(w, r, err, "Access request")
+		return
+	}
+
+	h.logger.Audit(r.Context(), logger.AuditEvent{
+		Action:     logger.AuditActionCreate,
+		Resource:   "user_from_access_request",
+		ResourceID: fmt.Sprintf("%d", user.ID),
+		ActorID:    currentUser.ID,
+		ActorEmail: currentUser.Email,
+		Result:     logger.AuditResultSuccess,
+		Details: map[string]any{
+			"access_request_id": request.ID,
+			"email":             user.Email,
+			"role":              user.Role,
+		},
+	})
+
+	h.onboarding.Start(r.Context(), user)
+	respondJSON(w, http.StatusOK, user.ToUserResponse())
+}
+
+// RejectAccessRequest godoc
+// @Summary Reject an access request
+// @Description Turns down a pending access request. The requester can request access again. Admin only.
+// @Tags Access Requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Access request ID"
+// @Success 200 {object} models.AccessRequest "Rejected access request"
+// @Failure 400 {object} map[string]interface{} "Invalid access request ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden - admin access required"
+// @Failure 404 {object} map[string]interface{} "Access request not found"
+// @Failure 409 {object} map[string]interface{} "Already reviewed"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/access-requests/{id}/reject [post]
+func (h *AccessRequestHandlers) RejectAccessRequest(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionInvitationManage)
+	if currentUser == nil {
+		return
+	}
+
+	request := h.getPendingRequest(w, r)
+	if request == nil {
+		return
+	}
+
+	rejected, err := h.requestRepo.Reject(r.Context(), request.ID, currentUser.ID)
+	if err != nil {
+		respondRepositoryError(w, r, err, "Access request")
+		return
+	}
+
+	h.logger.Info("Access request rejected", "access_request_id", rejected.ID, "rejected_by", currentUser.ID)
+	respondJSON(w, http.StatusOK, rejected)
+}
+
+// getPendingRequest loads the access request in the URL, writing a 404 if it doesn't exist and a
+// 409 if it has already been reviewed
+func (h *AccessRequestHandlers) getPendingRequest(w http.ResponseWriter, r *http.Request) *models.AccessRequest {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid access request ID")
+		return nil
+	}
+
+	request, err := h.requestRepo.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get access request", err, "access_request_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch access request")
+		return nil
+	}
+	if request == nil {
+		respondError(w, http.StatusNotFound, "Access request not found")
+		return nil
+	}
+	if request.Status != models.AccessRequestStatusPending {
+		respondError(w, http.StatusConflict, "Access request has already been "+string(request.Status))
+		return nil
+	}
+	return request
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/identity"
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func ctxWithIdentity(ident *identity.Identity) context.Context {
+	return context.WithValue(context.Background(), middleware.IdentityContextKey, ident)
+}
+
+func TestRequestAccess(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Auth0ID: "auth0|existing", Email: "existing@acme.com", Role: models.RoleAdmin, OrgID: 1})
+
+	orgRepo := mocks.NewMockOrganizationRepository()
+	orgRepo.InvitationDomains[1] = []string{"acme.com"}
+
+	tests := []struct {
+		name     string
+		ident    *identity.Identity
+		body     string
+		wantCode int
+	}{
+		{"matching domain", &identity.Identity{Subject: "auth0|new", Email: "Jane@Acme.com", Name: "Jane Doe"}, `{"message":"I'm on the platform team"}`, http.StatusCreated},
+		{"domain without an organization", &identity.Identity{Subject: "auth0|other", Email: "jane@example.com"}, `{}`, http.StatusForbidden},
+		{"subdomain", &identity.Identity{Subject: "auth0|sub", Email: "jane@eu.acme.com"}, `{}`, http.StatusForbidden},
+		{"already has an account", &identity.Identity{Subject: "auth0|existing", Email: "existing@acme.com"}, `{}`, http.StatusConflict},
+		{"email already has an account", &identity.Identity{Subject: "auth0|relinked", Email: "existing@acme.com"}, `{}`, http.StatusConflict},
+		{"message too long", &identity.Identity{Subject: "auth0|long", Email: "long@acme.com"}, `{"message":"` + strings.Repeat("x", models.MaxAccessRequestMessageLength+1) + `"}`, http.StatusBadRequest},
+		{"not signed in", nil, `{}`, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestRepo := mocks.NewMockAccessRequestRepository()
+			h := NewAccessRequestHandlers(requestRepo, orgRepo, userRepo)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/access-requests", strings.NewReader(tt.body))
+			if tt.ident != nil {
+				req = req.WithContext(ctxWithIdentity(tt.ident))
+			}
+			rr := httptest.NewRecorder()
+			h.RequestAccess(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("RequestAccess() status = %d, want %d: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if tt.wantCode != http.StatusCreated {
+				if len(requestRepo.Requests) != 0 {
+					t.Errorf("RequestAccess() created %d requests, want none", len(requestRepo.Requests))
+				}
+				return
+			}
+
+			var got models.AccessRequest
+			if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.OrgID != 1 || got.Status != models.AccessRequestStatusPending || got.FirstName != "Jane" || got.LastName != "Doe" {
+				t.Errorf("RequestAccess() = %+v, want a pending request to org 1 from Jane Doe", got)
+			}
+		})
+	}
+}
+
+func TestGetMyAccessRequest(t *testing.T) {
+	requestRepo := mocks.NewMockAccessRequestRepository()
+	requestRepo.AddRequest(&models.AccessRequest{ID: 1, OrgID: 1, Auth0ID: "auth0|new", Status: models.AccessRequestStatusRejected})
+	requestRepo.AddRequest(&models.AccessRequest{ID: 2, OrgID: 1, Auth0ID: "auth0|new", Status: models.AccessRequestStatusPending})
+	h := NewAccessRequestHandlers(requestRepo, mocks.NewMockOrganizationRepository(), mocks.NewMockUserRepository())
+
+	do := func(subject string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/access-requests/mine", nil)
+		req = req.WithContext(ctxWithIdentity(&identity.Identity{Subject: subject}))
+		rr := httptest.NewRecorder()
+		h.GetMyAccessRequest(rr, req)
+		return rr
+	}
+
+	rr := do("auth0|new")
+	var got models.AccessRequest
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != 2 {
+		t.Errorf("GetMyAccessRequest() ID = %d, want the latest request 2", got.ID)
+	}
+
+	if rr := do("auth0|nobody"); rr.Code != http.StatusNotFound {
+		t.Errorf("GetMyAccessRequest() without a request status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestAccessRequestReview(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}
+	employee := &models.User{ID: 2, Role: models.RoleEmployee, OrgID: 1}
+
+	setup := func() (*AccessRequestHandlers, *mocks.MockAccessRequestRepository) {
+		userRepo := mocks.NewMockUserRepository()
+		userRepo.AddUser(admin)
+		userRepo.AddUser(employee)
+		requestRepo := mocks.NewMockAccessRequestRepository()
+		requestRepo.AddRequest(&models.AccessRequest{ID: 1, OrgID: 1, Auth0ID: "auth0|new", Email: "new@acme.com", Status: models.AccessRequestStatusPending})
+		requestRepo.AddRequest(&models.AccessRequest{ID: 2, OrgID: 1, Auth0ID: "auth0|old", Email: "old@acme.com", Status: models.AccessRequestStatusRejected})
+		return NewAccessRequestHandlers(requestRepo, mocks.NewMockOrganizationRepository(), userRepo), requestRepo
+	}
+
+	do := func(handler http.HandlerFunc, currentUser *models.User, target, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), currentUser), "id", id))
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+
+	t.Run("list", func(t *testing.T) {
+		h, _ := setup()
+		rr := do(h.ListAccessRequests, admin, "/api/admin/access-requests?status=pending", "", "")
+		var requests []models.AccessRequest
+		if err := json.NewDecoder(rr.Body).Decode(&requests); err != nil {
+			t.Fatal(err)
+		}
+		if len(requests) != 1 || requests[0].ID != 1 {
+			t.Errorf("ListAccessRequests() = %+v, want the pending request", requests)
+		}
+
+		if rr := do(h.ListAccessRequests, admin, "/api/admin/access-requests?status=open", "", ""); rr.Code != http.StatusBadRequest {
+			t.Errorf("ListAccessRequests() with invalid status = %d, want %d", rr.Code, http.StatusBadRequest)
+		}
+		if rr := do(h.ListAccessRequests, employee, "/api/admin/access-requests", "", ""); rr.Code != http.StatusForbidden {
+			t.Errorf("ListAccessRequests() as employee = %d, want %d", rr.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			currentUser    *models.User
+			id             string
+			body           string
+			wantCode       int
+			wantSupervisor *int64
+		}{
+			{name: "employee reports to the approver", currentUser: admin, id: "1", body: `{"role":"employee","title":"Engineer"}`, wantCode: http.StatusOK, wantSupervisor: &admin.ID},
+			{name: "supervisor without a supervisor", currentUser: admin, id: "1", body: `{"role":"supervisor"}`, wantCode: http.StatusOK},
+			{name: "supervisor must supervise", currentUser: admin, id: "1", body: `{"role":"employee","supervisor_id":2}`, wantCode: http.StatusBadRequest},
+			{name: "invalid role", currentUser: admin, id: "1", body: `{"role":"owner"}`, wantCode: http.StatusBadRequest},
+			{name: "already reviewed", currentUser: admin, id: "2", body: `{"role":"employee"}`, wantCode: http.StatusConflict},
+			{name: "missing", currentUser: admin, id: "99", body: `{"role":"employee"}`, wantCode: http.StatusNotFound},
+			{name: "not an admin", currentUser: employee, id: "1", body: `{"role":"employee"}`, wantCode: http.StatusForbidden},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				h, requestRepo := setup()
+				rr := do(h.ApproveAccessRequest, tt.currentUser, "/api/admin/access-requests/"+tt.id+"/approve", tt.id, tt.body)
+				if rr.Code != tt.wantCode {
+					t.Fatalf("ApproveAccessRequest() status = %d, want %d: %s", rr.Code, tt.wantCode, rr.Body.String())
+				}
+				if tt.wantCode != http.StatusOK {
+					if len(requestRepo.Users) != 0 {
+						t.Errorf("ApproveAccessRequest() created %d users, want none", len(requestRepo.Users))
+					}
+					return
+				}
+
+				if len(requestRepo.Users) != 1 {
+					t.Fatalf("ApproveAccessRequest() created %d users, want 1", len(requestRepo.Users))
+				}
+				user := requestRepo.Users[0]
+				if user.Email != "new@acme.com" {
+					t.Errorf("user email = %q, want new@acme.com", user.Email)
+				}
+				if (user.SupervisorID == nil) != (tt.wantSupervisor == nil) || (user.SupervisorID != nil && *user.SupervisorID != *tt.wantSupervisor) {
+					t.Errorf("user supervisor = %v, want %v", user.SupervisorID, tt.wantSupervisor)
+				}
+				if requestRepo.Requests[1].Status != models.AccessRequestStatusApproved {
+					t.Errorf("request status = %q, want approved", requestRepo.Requests[1].Status)
+				}
+			})
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		h, requestRepo := setup()
+		if rr := do(h.RejectAccessRequest, employee, "/api/admin/access-requests/1/reject", "1", ""); rr.Code != http.StatusForbidden {
+			t.Errorf("RejectAccessRequest() as employee = %d, want %d", rr.Code, http.StatusForbidden)
+		}
+		if rr := do(h.RejectAccessRequest, admin, "/api/admin/access-requests/1/reject", "1", ""); rr.Code != http.StatusOK {
+			t.Fatalf("RejectAccessRequest() status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+		if requestRepo.Requests[1].Status != models.AccessRequestStatusRejected {
+			t.Errorf("request status = %q, want rejected", requestRepo.Requests[1].Status)
+		}
+		if rr := do(h.RejectAccessRequest, admin, "/api/admin/access-requests/1/reject", "1", ""); rr.Code != http.StatusConflict {
+			t.Errorf("RejectAccessRequest() twice = %d, want %d", rr.Code, http.StatusConflict)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// AdminStatsHandlers serve the stats on the admin home page
+type AdminStatsHandlers struct {
+	stats  *services.AdminStatsService
+	logger *logger.Logger
+}
+
+// NewAdminStatsHandlers creates a new admin stats handlers instance
+func NewAdminStatsHandlers(stats *services.AdminStatsService) *AdminStatsHandlers {
+	return &AdminStatsHandlers{
+		stats:  stats,
+		logger: logger.Default().WithComponent("admin-stats-handlers"),
+	}
+}
+
+// GetStats godoc
+// @Summary Get the admin home page stats
+// @Description Returns the organization's active headcount by role and department, active invitations, pending time off by type, how many active users are mapped to Jira, and storage usage. Stats are cached for up to 30 seconds. Requires stats:view.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.AdminStats "Stats"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/stats [get]
+func (h *AdminStatsHandlers) GetStats(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionStatsView)
+	if currentUser == nil {
+		return
+	}
+
+	stats, err := h.stats.Get(r.Context(), currentUser.OrgID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get admin stats", err, "org_id", currentUser.OrgID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch stats")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, stats)
+}
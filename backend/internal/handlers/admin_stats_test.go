@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestAdminStatsHandlers_GetStats(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		expectedStatus int
+	}{
+		{"admin", &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}, http.StatusOK},
+		{"supervisor", &models.User{ID: 2, Role: models.RoleSupervisor, OrgID: 1}, http.StatusForbidden},
+		{"employee", &models.User{ID: 3, Role: models.RoleEmployee, OrgID: 1}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockAdminStatsRepository()
+			repo.Stats = models.AdminStats{
+				Headcount:   models.AdminHeadcount{Total: 2, ByRole: map[models.Role]int{models.RoleAdmin: 1, models.RoleEmployee: 1}},
+				JiraMapping: models.JiraMappingCoverage{Mapped: 1, Total: 2, Percent: 50},
+			}
+			h := NewAdminStatsHandlers(services.NewAdminStatsService(repo))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/stats", nil)
+			req = req.WithContext(ctxWithUser(tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.GetStats(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				if repo.Calls != 0 {
+					t.Errorf("repository called %d times, want none", repo.Calls)
+				}
+				return
+			}
+
+			var stats models.AdminStats
+			if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if stats.Headcount.ByRole[models.RoleEmployee] != 1 || stats.JiraMapping.Percent != 50 {
+				t.Errorf("stats = %+v, want the repository's stats", stats)
+			}
+		})
+	}
+}
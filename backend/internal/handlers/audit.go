@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// AuditHandlers serves the audit log of mutating requests
+type AuditHandlers struct {
+	auditRepo repository.AuditEventRepository
+	logger    *logger.Logger
+}
+
+// NewAuditHandlers creates a new audit handlers instance
+func NewAuditHandlers(auditRepo repository.AuditEventRepository) *AuditHandlers {
+	return &AuditHandlers{
+		auditRepo: auditRepo,
+		logger:    logger.Default().WithComponent("audit-handlers"),
+	}
+}
+
+// GetEvents godoc
+// @Summary List audit events
+// @Description Returns recorded mutating requests and GraphQL mutations, newest first, with who made them and the before and after state of the entity. Admin only.
+// @Tags Audit
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int false "Only events by this actor"
+// @Param impersonation_session_id query int false "Only events taken in this impersonation session"
+// @Param entity_type query string false "Only events on this entity type, e.g. users"
+// @Param entity_id query string false "Only events on this entity ID"
+// @Param from query string false "Earliest date (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "Last date, inclusive (YYYY-MM-DD), or end time (RFC 3339)"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Success 200 {object} PaginatedResponse "Audit events"
+// @Failure 400 {object} map[string]interface{} "Invalid query"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /audit-events [get]
+func (h *AuditHandlers) GetEvents(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionAuditView) == nil {
+		return
+	}
+
+	filter, err := parseAuditEventFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid query: %v", err))
+		return
+	}
+
+	p := parsePagination(r)
+	events, total, err := h.auditRepo.List(r.Context(), filter, p.PerPage, p.Offset)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list audit events", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch audit events")
+		return
+	}
+
+	respondPaginated(w, events, total, p)
+}
+
+// parseAuditEventFilter builds an AuditEventFilter from query parameters. A date-only to covers
+// the whole day.
+func parseAuditEventFilter(r *http.Request) (models.AuditEventFilter, error) {
+	q := r.URL.Query()
+	filter := models.AuditEventFilter{
+		EntityType: q.Get("entity_type"),
+		EntityID:   q.Get("entity_id"),
+	}
+
+	if u := q.Get("user_id"); u != "" {
+		id, err := strconv.ParseInt(u, 10, 64)
+		if err != nil || id < 1 {
+			return filter, fmt.Errorf("user_id must be a positive integer")
+		}
+		filter.ActorID = &id
+	}
+	if s := q.Get("impersonation_session_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || id < 1 {
+			return filter, fmt.Errorf("impersonation_session_id must be a positive integer")
+		}
+		filter.ImpersonationSessionID = &id
+	}
+	if from := q.Get("from"); from != "" {
+		parsed, _, err := parseAuditTime(from)
+		if err != nil {
+			return filter, fmt.Errorf("from must use YYYY-MM-DD or RFC 3339")
+		}
+		filter.From = &parsed
+	}
+	if to := q.Get("to"); to != "" {
+		parsed, dateOnly, err := parseAuditTime(to)
+		if err != nil {
+			return filter, fmt.Errorf("to must use YYYY-MM-DD or RFC 3339")
+		}
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		filter.To = &parsed
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+
+	return filter, nil
+}
+
+// parseAuditTime parses a date or an RFC 3339 timestamp, reporting whether it was a date
+func parseAuditTime(s string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	return t, false, err
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestAuditHandlers_GetEvents(t *testing.T) {
+	adminID, employeeID := int64(1), int64(2)
+	userEntity, squadEntity := "7", "3"
+	auditRepo := mocks.NewMockAuditEventRepository()
+	for _, event := range []models.AuditEvent{
+		{ActorID: &adminID, Action: "PUT /api/users/{id}", EntityType: "users", EntityID: &userEntity, CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{ActorID: &adminID, Action: "DELETE /api/squads/{id}", EntityType: "squads", EntityID: &squadEntity, CreatedAt: time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)},
+		{ActorID: &employeeID, Action: "POST /api/kudos", EntityType: "kudos", CreatedAt: time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)},
+	} {
+		_ = auditRepo.Create(context.Background(), &event)
+	}
+	h := NewAuditHandlers(auditRepo)
+
+	get := func(user *models.User, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-events"+query, nil)
+		req = req.WithContext(ctxWithUser(user))
+		rr := httptest.NewRecorder()
+		h.GetEvents(rr, req)
+		return rr
+	}
+	admin := &models.User{ID: adminID, Role: models.RoleAdmin}
+
+	if rr := get(&models.User{ID: 5, Role: models.RoleSupervisor}, ""); rr.Code != http.StatusForbidden {
+		t.Errorf("supervisor status = %d, want %d", rr.Code, http.StatusForbidden)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		query   string
+		wantIDs []int64
+	}{
+		{"all events, newest first", "", []int64{3, 2, 1}},
+		{"by actor", "?user_id=1", []int64{2, 1}},
+		{"by entity", "?entity_type=users&entity_id=7", []int64{1}},
+		{"date range is inclusive", "?from=2026-03-02&to=2026-03-02", []int64{2}},
+		{"timestamp range", "?from=2026-03-01T13:00:00Z", []int64{3, 2}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := get(admin, tt.query)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rr.Code, rr.Body.String())
+			}
+			var resp struct {
+				Data []models.AuditEvent `json:"data"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			var ids []int64
+			for _, event := range resp.Data {
+				ids = append(ids, event.ID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Fatalf("ids = %v, want %v", ids, tt.wantIDs)
+				}
+			}
+		})
+	}
+
+	for _, query := range []string{"?user_id=abc", "?from=yesterday", "?from=2026-03-05&to=2026-03-01"} {
+		if rr := get(admin, query); rr.Code != http.StatusBadRequest {
+			t.Errorf("%s status = %d, want %d", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/auth0"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// maxAuth0LogStreamBytes caps the size of an incoming Auth0 log stream batch
+const maxAuth0LogStreamBytes = 5 << 20
+
+// WithAuth0Sync blocks users in Auth0 when they're deactivated, offboarded, or deleted, and
+// unblocks them when they're reactivated or restored
+func (h *Handlers) WithAuth0Sync(auth0Sync *services.Auth0SyncService) *Handlers {
+	h.auth0Sync = auth0Sync
+	return h
+}
+
+// WithAuth0Sync blocks users in Auth0 when the identity provider deactivates them, and unblocks
+// them when it reactivates them
+func (h *SCIMHandlers) WithAuth0Sync(auth0Sync *services.Auth0SyncService) *SCIMHandlers {
+	h.auth0Sync = auth0Sync
+	return h
+}
+
+// Auth0WebhookHandlers receive the Auth0 log stream, deactivating users who are deleted or blocked
+// in Auth0
+type Auth0WebhookHandlers struct {
+	token     string
+	auth0Sync *services.Auth0SyncService
+	logger    *logger.Logger
+}
+
+// NewAuth0WebhookHandlers creates a new Auth0 webhook handlers instance. token is the authorization
+// token configured on the log stream; deliveries are rejected without it.
+func NewAuth0WebhookHandlers(token string, auth0Sync *services.Auth0SyncService) *Auth0WebhookHandlers {
+	return &Auth0WebhookHandlers{
+		token:     token,
+		auth0Sync: auth0Sync,
+		logger:    logger.Default().WithComponent("auth0-webhook-handlers"),
+	}
+}
+
+// HandleLogStream godoc
+// @Summary Receive Auth0 log events
+// @Description Receives a batch from an Auth0 custom webhook log stream. Users deleted or blocked in Auth0 are deactivated in the dashboard; other events are ignored. Authenticated with the token configured on the stream.
+// @Tags Webhooks
+// @Accept json
+// @Success 204 "Events processed"
+// @Failure 400 {object} map[string]interface{} "Invalid payload"
+// @Failure 401 {object} map[string]interface{} "Invalid token"
+// @Failure 404 {object} map[string]interface{} "Auth0 webhooks are not configured"
+// @Failure 413 {object} map[string]interface{} "Payload too large"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /auth0/webhook [post]
+func (h *Auth0WebhookHandlers) HandleLogStream(w http.ResponseWriter, r *http.Request) {
+	if h.token == "" || h.auth0Sync == nil {
+		respondError(w, http.StatusNotFound, "Auth0 webhooks are not configured")
+		return
+	}
+	if !auth0.VerifyLogStreamToken(h.token, r.Header.Get("Authorization")) {
+		respondError(w, http.StatusUnauthorized, "Invalid webhook token")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAuth0LogStreamBytes))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "Webhook payload is too large")
+		return
+	}
+	logEvents, err := auth0.ParseLogEvents(body)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid webhook payload")
+		return
+	}
+
+	for _, event := range logEvents {
+		auth0ID := event.RevokedUserID()
+		if auth0ID == "" {
+			continue
+		}
+		user, err := h.auth0Sync.Revoked(r.Context(), auth0ID)
+		if err != nil {
+			// A failure response makes Auth0 retry the batch; users already deactivated are skipped
+			h.logger.LogError(r.Context(), "Failed to deactivate user revoked in Auth0", err, "log_id", event.LogID)
+			respondError(w, http.StatusInternalServerError, "Failed to process webhook")
+			return
+		}
+		if user != nil {
+			h.logger.Audit(r.Context(), logger.AuditEvent{
+				Action:     logger.AuditActionUpdate,
+				Resource:   "user_deactivated_by_auth0",
+				ResourceID: auth0ID,
+				Result:     logger.AuditResultSuccess,
+				Details:    map[string]any{"user_id": user.ID, "log_id": event.LogID, "log_type": event.Data.Type},
+			})
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
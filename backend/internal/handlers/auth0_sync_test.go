@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// fakeAuth0Blocker records the blocked state set for each Auth0 user
+type fakeAuth0Blocker struct {
+	blocked map[string]bool
+}
+
+func (f *fakeAuth0Blocker) SetBlocked(ctx context.Context, userID string, blocked bool) error {
+	f.blocked[userID] = blocked
+	return nil
+}
+
+func TestAuth0WebhookHandlers_HandleLogStream(t *testing.T) {
+	const token = "log-stream-token"
+	batch := `[
+		{"log_id": "1", "data": {"type": "sdu", "user_id": "auth0|deleted"}},
+		{"log_id": "2", "data": {"type": "sapi", "details": {"request": {"method": "patch", "path": "/api/v2/users/auth0%7Cblocked", "body": {"blocked": true}}}}},
+		{"log_id": "3", "data": {"type": "s", "user_id": "auth0|signed-in"}}
+	]`
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		body          string
+		wantCode      int
+		wantInactive  []int64
+	}{
+		{"deactivates deleted and blocked users", token, "Bearer " + token, batch, http.StatusNoContent, []int64{1, 2}},
+		{"invalid token", token, "Bearer wrong", batch, http.StatusUnauthorized, nil},
+		{"invalid payload", token, token, `{"log_id":`, http.StatusBadRequest, nil},
+		{"not configured", "", "", batch, http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewMockUserRepository()
+			userRepo.AddUser(&models.User{ID: 1, Auth0ID: "auth0|deleted", IsActive: true})
+			userRepo.AddUser(&models.User{ID: 2, Auth0ID: "auth0|blocked", IsActive: true})
+			userRepo.AddUser(&models.User{ID: 3, Auth0ID: "auth0|signed-in", IsActive: true})
+			auth0Sync := services.NewAuth0SyncService(&fakeAuth0Blocker{blocked: map[string]bool{}}, userRepo, nil)
+			h := NewAuth0WebhookHandlers(tt.token, auth0Sync)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth0/webhook", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", tt.authorization)
+			rr := httptest.NewRecorder()
+			h.HandleLogStream(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("HandleLogStream() status = %d, want %d: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			var inactive []int64
+			for _, id := range []int64{1, 2, 3} {
+				if !userRepo.Users[id].IsActive {
+					inactive = append(inactive, id)
+				}
+			}
+			if fmt.Sprint(inactive) != fmt.Sprint(tt.wantInactive) {
+				t.Errorf("deactivated users = %v, want %v", inactive, tt.wantInactive)
+			}
+		})
+	}
+}
+
+func TestUserLifecycle_Auth0Sync(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin, IsActive: true}
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(admin)
+	userRepo.AddUser(&models.User{ID: 2, Auth0ID: "auth0|2", Role: models.RoleEmployee, IsActive: true})
+	client := &fakeAuth0Blocker{blocked: map[string]bool{}}
+	h := New(userRepo, mocks.NewMockSquadRepository(), nil).
+		WithAuth0Sync(services.NewAuth0SyncService(client, userRepo, nil))
+
+	do := func(handler http.HandlerFunc, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), admin), "id", "2"))
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+
+	if rr := do(h.DeactivateUser, "/api/users/2/deactivate"); rr.Code != http.StatusNoContent {
+		t.Fatalf("DeactivateUser() status = %d: %s", rr.Code, rr.Body.String())
+	}
+	if blocked, ok := client.blocked["auth0|2"]; !ok || !blocked {
+		t.Error("DeactivateUser() didn't block the user in Auth0")
+	}
+
+	if rr := do(h.ReactivateUser, "/api/users/2/reactivate"); rr.Code != http.StatusOK {
+		t.Fatalf("ReactivateUser() status = %d: %s", rr.Code, rr.Body.String())
+	}
+	if client.blocked["auth0|2"] {
+		t.Error("ReactivateUser() didn't unblock the user in Auth0")
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,6 +19,7 @@ type AvatarHandlers struct {
 	userRepo      repository.UserRepository
 	avatarService *services.AvatarService
 	maxSizeMB     int
+	settings      *services.OrgSettingsService
 	logger        *logger.Logger
 }
 
@@ -39,6 +41,20 @@ func NewAvatarHandlersWithConfig(userRepo repository.UserRepository, avatarServi
 	}
 }
 
+// WithSettings lets organizations change the maximum avatar size from the configured one
+func (h *AvatarHandlers) WithSettings(settings *services.OrgSettingsService) *AvatarHandlers {
+	h.settings = settings
+	return h
+}
+
+// maxUploadMB returns the largest avatar the current user's organization accepts, in MB
+func (h *AvatarHandlers) maxUploadMB(r *http.Request) int {
+	if h.settings == nil {
+		return h.maxSizeMB
+	}
+	return h.settings.AvatarMaxSizeMB(r.Context())
+}
+
 // checkAvatarPermission verifies the current user can upload an avatar for the target user
 func (h *AvatarHandlers) checkAvatarPermission(w http.ResponseWriter, r *http.Request, targetUserID int64) (*models.User, bool) {
 	currentUser := requireAuth(w, r)
@@ -54,7 +70,7 @@ func (h *AvatarHandlers) checkAvatarPermission(w http.ResponseWriter, r *http.Re
 	// Supervisors/admins can upload avatars for their direct reports
 	targetUser, err := h.userRepo.GetByID(r.Context(), targetUserID)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "User not found")
+		respondRepositoryError(w, r, err, "User")
 		return nil, false
 	}
 
@@ -79,9 +95,10 @@ func (h *AvatarHandlers) UploadAvatar(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse multipart form with configurable max size
-	maxBytes := int64(h.maxSizeMB) << 20
+	maxSizeMB := h.maxUploadMB(r)
+	maxBytes := int64(maxSizeMB) << 20
 	if err := r.ParseMultipartForm(maxBytes); err != nil {
-		respondError(w, http.StatusBadRequest, fmt.Sprintf("File too large (max %dMB)", h.maxSizeMB))
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("File too large (max %dMB)", maxSizeMB))
 		return
 	}
 
@@ -108,7 +125,7 @@ func (h *AvatarHandlers) UploadAvatar(w http.ResponseWriter, r *http.Request) {
 
 	// Validate size
 	if len(fileBytes) > int(maxBytes) {
-		respondError(w, http.StatusBadRequest, fmt.Sprintf("Image too large (max %dMB)", h.maxSizeMB))
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Image too large (max %dMB)", maxSizeMB))
 		return
 	}
 
@@ -119,20 +136,20 @@ func (h *AvatarHandlers) UploadAvatar(w http.ResponseWriter, r *http.Request) {
 		Extension:   h.avatarService.GetExtensionFromContentType(contentType),
 	}
 
-	// Upload using the avatar service
-	avatarURL, err := h.avatarService.Upload(r.Context(), id, img)
+	// Resize and upload using the avatar service
+	avatarURL, variants, err := h.avatarService.Upload(r.Context(), id, img)
+	if errors.Is(err, services.ErrInvalidImage) {
+		respondError(w, http.StatusBadRequest, "Invalid image format: please upload a valid JPEG, PNG, GIF, or WebP image")
+		return
+	}
 	if err != nil {
 		respondError(w, http.StatusServiceUnavailable, "Image upload is temporarily unavailable. Please try again later.")
 		return
 	}
 
-	// Update user's avatar URL
-	req := &models.UpdateUserRequest{
-		AvatarURL: &avatarURL,
-	}
-
-	user, err := h.userRepo.Update(r.Context(), id, req)
-	if err != nil {
+	// Update user's avatar URL and variants
+	user, err := h.userRepo.SetAvatar(r.Context(), id, avatarURL, variants)
+	if err != nil || user == nil {
 		respondError(w, http.StatusInternalServerError, "Failed to update avatar URL")
 		return
 	}
@@ -168,21 +185,21 @@ func (h *AvatarHandlers) UploadAvatarBase64(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Upload using the avatar service
-	avatarURL, err := h.avatarService.Upload(r.Context(), id, img)
+	// Resize and upload using the avatar service
+	avatarURL, variants, err := h.avatarService.Upload(r.Context(), id, img)
+	if errors.Is(err, services.ErrInvalidImage) {
+		respondError(w, http.StatusBadRequest, "Invalid image format: please upload a valid JPEG, PNG, GIF, or WebP image")
+		return
+	}
 	if err != nil {
 		h.logger.LogError(r.Context(), "Avatar upload (base64) failed", err, "user_id", id)
 		respondError(w, http.StatusServiceUnavailable, "Image upload is temporarily unavailable. Please try again later.")
 		return
 	}
 
-	// Update user's avatar URL
-	updateReq := &models.UpdateUserRequest{
-		AvatarURL: &avatarURL,
-	}
-
-	user, err := h.userRepo.Update(r.Context(), id, updateReq)
-	if err != nil {
+	// Update user's avatar URL and variants
+	user, err := h.userRepo.SetAvatar(r.Context(), id, avatarURL, variants)
+	if err != nil || user == nil {
 		respondError(w, http.StatusInternalServerError, "Failed to update avatar URL")
 		return
 	}
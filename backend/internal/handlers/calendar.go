@@ -2,33 +2,120 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/repository"
 	"github.com/smith-dallin/manager-dashboard/internal/services"
 )
 
+// calendarStreamHeartbeat is how often an idle SSE stream sends a keep-alive comment
+const calendarStreamHeartbeat = 25 * time.Second
+
 type CalendarHandlers struct {
 	bffService  *services.CalendarBFFService
 	taskRepo    repository.TaskRepository
 	meetingRepo repository.MeetingRepository
+	commentRepo repository.TaskCommentRepository
+	notesRepo   repository.MeetingNotesRepository
+	squadRepo   repository.SquadRepository
+	savedViews  repository.SavedViewRepository
+	notifier    *services.NotificationService
+	broker      *events.Broker
+	shutdown    <-chan struct{} // Closed when the server shuts down; nil streams until the client leaves
+	logger      *logger.Logger
 }
 
 func NewCalendarHandlers(
 	bffService *services.CalendarBFFService,
 	taskRepo repository.TaskRepository,
 	meetingRepo repository.MeetingRepository,
+) *CalendarHandlers {
+	return NewCalendarHandlersWithEvents(bffService, taskRepo, meetingRepo, nil)
+}
+
+// NewCalendarHandlersWithEvents creates calendar handlers that publish changes to the given broker
+func NewCalendarHandlersWithEvents(
+	bffService *services.CalendarBFFService,
+	taskRepo repository.TaskRepository,
+	meetingRepo repository.MeetingRepository,
+	broker *events.Broker,
+) *CalendarHandlers {
+	return NewCalendarHandlersWithComments(bffService, taskRepo, meetingRepo, nil, broker)
+}
+
+// NewCalendarHandlersWithComments creates calendar handlers that also serve task comments and
+// record task status changes in the activity feed
+func NewCalendarHandlersWithComments(
+	bffService *services.CalendarBFFService,
+	taskRepo repository.TaskRepository,
+	meetingRepo repository.MeetingRepository,
+	commentRepo repository.TaskCommentRepository,
+	broker *events.Broker,
+) *CalendarHandlers {
+	return NewCalendarHandlersWithNotes(bffService, taskRepo, meetingRepo, commentRepo, nil, broker)
+}
+
+// NewCalendarHandlersWithNotes creates calendar handlers that also serve meeting agendas, notes,
+// and action items
+func NewCalendarHandlersWithNotes(
+	bffService *services.CalendarBFFService,
+	taskRepo repository.TaskRepository,
+	meetingRepo repository.MeetingRepository,
+	commentRepo repository.TaskCommentRepository,
+	notesRepo repository.MeetingNotesRepository,
+	broker *events.Broker,
+) *CalendarHandlers {
+	return NewCalendarHandlersWithNotifications(bffService, taskRepo, meetingRepo, commentRepo, notesRepo, nil, broker)
+}
+
+// NewCalendarHandlersWithNotifications creates calendar handlers that also notify attendees when
+// they're invited to meetings, and organizers when attendees respond
+func NewCalendarHandlersWithNotifications(
+	bffService *services.CalendarBFFService,
+	taskRepo repository.TaskRepository,
+	meetingRepo repository.MeetingRepository,
+	commentRepo repository.TaskCommentRepository,
+	notesRepo repository.MeetingNotesRepository,
+	notifier *services.NotificationService,
+	broker *events.Broker,
 ) *CalendarHandlers {
 	return &CalendarHandlers{
 		bffService:  bffService,
 		taskRepo:    taskRepo,
 		meetingRepo: meetingRepo,
+		commentRepo: commentRepo,
+		notesRepo:   notesRepo,
+		notifier:    notifier,
+		broker:      broker,
+		logger:      logger.Default().WithComponent("calendar-handlers"),
 	}
 }
 
-// GetEvents returns all calendar events (tasks, meetings, jira issues) within a date range
+// WithShutdown ends open event streams once shutdown is closed. The server waits for every
+// request to finish before shutting down, and a stream otherwise lasts as long as the client.
+func (h *CalendarHandlers) WithShutdown(shutdown <-chan struct{}) *CalendarHandlers {
+	h.shutdown = shutdown
+	return h
+}
+
+// WithSquadLeads lets squad leads view tasks assigned to their squads or anyone in them
+func (h *CalendarHandlers) WithSquadLeads(squadRepo repository.SquadRepository) *CalendarHandlers {
+	h.squadRepo = squadRepo
+	return h
+}
+
+// GetEvents returns calendar events (tasks, meetings, jira issues, time off) within a date range of
+// at most MaxCalendarWindowDays, optionally paginated per source with limit and cursor.
 // This endpoint uses the BFF service to aggregate data from multiple sources
 func (h *CalendarHandlers) GetEvents(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
@@ -67,11 +154,24 @@ func (h *CalendarHandlers) GetEvents(w http.ResponseWriter, r *http.Request) {
 		end = time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, time.UTC)
 	}
 
+	if err := models.ValidateCalendarWindow(start, end); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, cursor, err := parseCalendarPage(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Use BFF service to aggregate all calendar data
 	response, err := h.bffService.GetCalendarEvents(r.Context(), services.CalendarEventsRequest{
-		User:  currentUser,
-		Start: start,
-		End:   end,
+		User:   currentUser,
+		Start:  start,
+		End:    end,
+		Limit:  limit,
+		Cursor: cursor,
 	})
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to fetch calendar events")
@@ -81,6 +181,100 @@ func (h *CalendarHandlers) GetEvents(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, response)
 }
 
+// parseCalendarPage reads the optional per-source limit and cursor; without either, every event in the window is returned
+func parseCalendarPage(r *http.Request) (int, models.CalendarCursor, error) {
+	q := r.URL.Query()
+	if !q.Has("limit") && !q.Has("cursor") {
+		return 0, nil, nil
+	}
+
+	limit := DefaultPerPage
+	if l := q.Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 {
+			return 0, nil, fmt.Errorf("limit must be a positive integer")
+		}
+		limit = min(parsed, MaxPerPage)
+	}
+
+	var cursor models.CalendarCursor
+	if c := q.Get("cursor"); c != "" {
+		var err error
+		cursor, err = models.DecodeCalendarCursor(c)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	return limit, cursor, nil
+}
+
+// StreamEvents pushes task, meeting, and time off changes visible to the user over SSE
+func (h *CalendarHandlers) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	if h.broker == nil {
+		respondError(w, http.StatusServiceUnavailable, "Calendar streaming is not enabled")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The stream outlives the server write timeout, so lift the deadline for this response
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	sub, unsubscribe := h.broker.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprint(w, ": connected\n\n"); err != nil {
+		return
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(calendarStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.shutdown:
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-sub:
+			if !ok {
+				return
+			}
+			if !h.canViewEvent(currentUser, event) {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
 // CreateTask creates a new task
 func (h *CalendarHandlers) CreateTask(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
@@ -99,9 +293,71 @@ func (h *CalendarHandlers) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.broker.Publish(r.Context(), events.TaskCreated, task)
 	respondJSON(w, http.StatusCreated, task)
 }
 
+// BulkCreateTasks creates up to models.MaxBulkTasks tasks at once, e.g. when planning a sprint.
+// Every task is validated first and the batch is created in one transaction; if any task is invalid,
+// none are created and the response lists the errors by index.
+func (h *CalendarHandlers) BulkCreateTasks(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.BulkCreateTasksRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	if errs := req.ItemErrors(); len(errs) > 0 {
+		respondJSON(w, http.StatusBadRequest, models.BulkTaskResult{Tasks: []models.Task{}, Errors: errs})
+		return
+	}
+
+	tasks, err := h.taskRepo.CreateMany(r.Context(), req.Tasks, currentUser.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create tasks")
+		return
+	}
+
+	for i := range tasks {
+		h.broker.Publish(r.Context(), events.TaskCreated, &tasks[i])
+	}
+	respondJSON(w, http.StatusCreated, models.BulkTaskResult{Tasks: tasks, Errors: []models.BulkTaskError{}})
+}
+
+// ReassignTasks moves open tasks assigned to one user over to another, optionally limited to
+// task_ids. Only a task's creator or an admin may move it. The move is all-or-nothing: if any task
+// can't be moved, none are and the response lists the errors by task ID.
+func (h *CalendarHandlers) ReassignTasks(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.ReassignTasksRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	result, err := h.taskRepo.Reassign(r.Context(), &req, currentUser)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to reassign tasks")
+		return
+	}
+	if len(result.Errors) > 0 {
+		respondJSON(w, http.StatusBadRequest, result)
+		return
+	}
+
+	for i := range result.Tasks {
+		h.broker.Publish(r.Context(), events.TaskUpdated, &result.Tasks[i])
+	}
+	respondJSON(w, http.StatusOK, result)
+}
+
 // GetTask retrieves a task by ID
 func (h *CalendarHandlers) GetTask(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
@@ -117,12 +373,12 @@ func (h *CalendarHandlers) GetTask(w http.ResponseWriter, r *http.Request) {
 
 	task, err := h.taskRepo.GetByID(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "Task not found")
+		respondRepositoryError(w, r, err, "Task")
 		return
 	}
 
-	// Check visibility - user must be creator, assignee, or admin
-	if !h.canViewTask(currentUser, task) {
+	// Check visibility - user must be creator, assignee, squad lead, or admin
+	if !h.canViewTask(currentUser, task) && !h.leadsTaskSquad(r.Context(), currentUser, task) {
 		respondError(w, http.StatusForbidden, "Forbidden: you don't have permission to view this task")
 		return
 	}
@@ -130,7 +386,95 @@ func (h *CalendarHandlers) GetTask(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, task)
 }
 
-// UpdateTask updates a task
+// ListTasks returns the tasks visible to the current user, regardless of date.
+// Supports status, priority, label, assignee (user ID), and overdue=true filters,
+// and sort (due_date, priority, created_at, updated_at) with order (asc or desc).
+// Results are soonest-due first by default; other sorts default to descending.
+// view applies one of the user's saved views, under any filters given explicitly.
+// format=csv or xlsx downloads the list instead, limited to the optional columns.
+func (h *CalendarHandlers) ListTasks(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+	if !applySavedView(w, r, h.savedViews, currentUser, models.SavedViewResourceTasks) {
+		return
+	}
+
+	filter, err := parseTaskFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid query: %v", err))
+		return
+	}
+	download, ok := parseTableDownload(w, r, services.TaskExportColumns)
+	if !ok {
+		return
+	}
+
+	tasks, err := h.taskRepo.List(r.Context(), currentUser, filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch tasks")
+		return
+	}
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+
+	if download != nil {
+		download.respond(w, r, "tasks", tasks)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tasks)
+}
+
+// parseTaskFilter builds a TaskFilter from query parameters
+func parseTaskFilter(r *http.Request) (models.TaskFilter, error) {
+	q := r.URL.Query()
+	var filter models.TaskFilter
+
+	if s := q.Get("status"); s != "" {
+		status := models.TaskStatus(s)
+		filter.Status = &status
+	}
+	if p := q.Get("priority"); p != "" {
+		priority := models.TaskPriority(p)
+		filter.Priority = &priority
+	}
+	if l := q.Get("label"); l != "" {
+		filter.Label = &l
+	}
+	if a := q.Get("assignee"); a != "" {
+		assigneeID, err := strconv.ParseInt(a, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("assignee must be a user ID")
+		}
+		filter.AssigneeID = &assigneeID
+	}
+	if o := q.Get("overdue"); o != "" {
+		overdue, err := strconv.ParseBool(o)
+		if err != nil {
+			return filter, fmt.Errorf("overdue must be true or false")
+		}
+		filter.Overdue = overdue
+	}
+
+	filter.SortBy = models.TaskSortField(q.Get("sort"))
+	switch q.Get("order") {
+	case "":
+		filter.SortAsc = filter.SortBy == "" || filter.SortBy == models.TaskSortDueDate
+	case "asc":
+		filter.SortAsc = true
+	case "desc":
+	default:
+		return filter, fmt.Errorf("order must be 'asc' or 'desc'")
+	}
+
+	return filter, filter.Validate()
+}
+
+// UpdateTask updates a task. Completing or cancelling an instance of a recurring task generates the
+// next instance.
 func (h *CalendarHandlers) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
 	if currentUser == nil {
@@ -145,12 +489,11 @@ func (h *CalendarHandlers) UpdateTask(w http.ResponseWriter, r *http.Request) {
 
 	task, err := h.taskRepo.GetByID(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "Task not found")
+		respondRepositoryError(w, r, err, "Task")
 		return
 	}
 
-	// Only creator or admin can update
-	if task.CreatedByID != currentUser.ID && !currentUser.IsAdmin() {
+	if !authz.Can(currentUser, authz.ActionTaskManage, authz.TaskResource(task)) {
 		respondError(w, http.StatusForbidden, "Forbidden: not task creator")
 		return
 	}
@@ -160,12 +503,34 @@ func (h *CalendarHandlers) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	previousStatus := task.Status
 	updatedTask, err := h.taskRepo.Update(r.Context(), id, &req)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to update task")
 		return
 	}
 
+	if updatedTask.Status != previousStatus && h.commentRepo != nil {
+		if _, err := h.commentRepo.RecordStatusChange(r.Context(), id, currentUser.ID, previousStatus, updatedTask.Status); err != nil {
+			h.logger.LogError(r.Context(), "Failed to record task status change", err, "task_id", id)
+		}
+	}
+
+	// Finishing an instance of a recurring task generates the next one
+	var nextTask *models.Task
+	if previousStatus.IsOpen() && !updatedTask.IsOpen() && updatedTask.RecurrenceType != nil {
+		finished, next, err := h.taskRepo.AdvanceRecurrence(r.Context(), id)
+		if err != nil {
+			h.logger.LogError(r.Context(), "Failed to create next recurring task", err, "task_id", id)
+		} else if finished != nil {
+			updatedTask, nextTask = finished, next
+		}
+	}
+
+	h.broker.Publish(r.Context(), events.TaskUpdated, updatedTask)
+	if nextTask != nil {
+		h.broker.Publish(r.Context(), events.TaskCreated, nextTask)
+	}
 	respondJSON(w, http.StatusOK, updatedTask)
 }
 
@@ -184,12 +549,11 @@ func (h *CalendarHandlers) DeleteTask(w http.ResponseWriter, r *http.Request) {
 
 	task, err := h.taskRepo.GetByID(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "Task not found")
+		respondRepositoryError(w, r, err, "Task")
 		return
 	}
 
-	// Only creator or admin can delete
-	if task.CreatedByID != currentUser.ID && !currentUser.IsAdmin() {
+	if !authz.Can(currentUser, authz.ActionTaskManage, authz.TaskResource(task)) {
 		respondError(w, http.StatusForbidden, "Forbidden: not task creator")
 		return
 	}
@@ -199,9 +563,33 @@ func (h *CalendarHandlers) DeleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.broker.Publish(r.Context(), events.TaskDeleted, task)
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// RestoreTask restores a deleted task before it is purged (requires deleted:restore)
+func (h *CalendarHandlers) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionDeletedRestore) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	task, err := h.taskRepo.Restore(r.Context(), id)
+	if err != nil {
+		respondRepositoryError(w, r, err, "Deleted task")
+		return
+	}
+
+	h.broker.Publish(r.Context(), events.TaskCreated, task)
+	respondJSON(w, http.StatusOK, task)
+}
+
 // CreateMeeting creates a new meeting
 func (h *CalendarHandlers) CreateMeeting(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
@@ -220,7 +608,110 @@ func (h *CalendarHandlers) CreateMeeting(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, meeting)
+	h.broker.Publish(r.Context(), events.MeetingCreated, meeting)
+	h.notifier.NotifyMeetingInvite(r.Context(), meeting, currentUser, attendeeIDs(meeting.Attendees))
+
+	// Warnings are advisory, so failing to load schedules doesn't fail the request
+	warnings := []models.SchedulingWarning{}
+	if h.bffService != nil {
+		if loaded, err := h.bffService.MeetingSchedulingWarnings(r.Context(), meeting); err != nil {
+			h.logger.LogError(r.Context(), "Failed to check attendee work schedules", err, "meeting_id", meeting.ID)
+		} else {
+			warnings = loaded
+		}
+	}
+
+	respondJSON(w, http.StatusCreated, models.CreateMeetingResponse{Meeting: meeting, Warnings: warnings})
+}
+
+// SuggestMeetingTimes proposes meeting slots that suit the organizer and every attendee's working
+// hours, focus time, meetings, and time off. The window defaults to the next 7 days.
+func (h *CalendarHandlers) SuggestMeetingTimes(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	req, err := parseSuggestMeetingTimes(r, time.Now())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.bffService.SuggestMeetingTimes(r.Context(), currentUser, req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to suggest meeting times")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// parseSuggestMeetingTimes builds a SuggestMeetingTimesRequest from query parameters
+func parseSuggestMeetingTimes(r *http.Request, now time.Time) (*models.SuggestMeetingTimesRequest, error) {
+	q := r.URL.Query()
+	req := &models.SuggestMeetingTimesRequest{
+		Start: now,
+		End:   now.AddDate(0, 0, 7),
+	}
+
+	duration, err := strconv.Atoi(q.Get("duration_minutes"))
+	if err != nil {
+		return nil, fmt.Errorf("duration_minutes is required")
+	}
+	req.Duration = time.Duration(duration) * time.Minute
+
+	if ids := q.Get("attendee_ids"); ids != "" {
+		for _, id := range strings.Split(ids, ",") {
+			attendeeID, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("attendee_ids must be a comma-separated list of user IDs")
+			}
+			req.AttendeeIDs = append(req.AttendeeIDs, attendeeID)
+		}
+	}
+	if s := q.Get("start"); s != "" {
+		if req.Start, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, fmt.Errorf("start must be an RFC3339 time")
+		}
+		if q.Get("end") == "" {
+			req.End = req.Start.AddDate(0, 0, 7)
+		}
+	}
+	if e := q.Get("end"); e != "" {
+		if req.End, err = time.Parse(time.RFC3339, e); err != nil {
+			return nil, fmt.Errorf("end must be an RFC3339 time")
+		}
+	}
+	if l := q.Get("limit"); l != "" {
+		if req.Limit, err = strconv.Atoi(l); err != nil {
+			return nil, fmt.Errorf("limit must be a positive integer")
+		}
+	}
+
+	return req, req.Validate()
+}
+
+// CheckMeetingConflicts returns each attendee's overlapping meetings and approved time off
+// for a proposed meeting window, so organizers can pick a time before creating the meeting
+func (h *CalendarHandlers) CheckMeetingConflicts(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.CheckMeetingConflictsRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	response, err := h.bffService.CheckMeetingConflicts(r.Context(), currentUser, &req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to check meeting conflicts")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response)
 }
 
 // GetMeeting retrieves a meeting by ID
@@ -238,7 +729,7 @@ func (h *CalendarHandlers) GetMeeting(w http.ResponseWriter, r *http.Request) {
 
 	meeting, err := h.meetingRepo.GetByID(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "Meeting not found")
+		respondRepositoryError(w, r, err, "Meeting")
 		return
 	}
 
@@ -266,12 +757,11 @@ func (h *CalendarHandlers) UpdateMeeting(w http.ResponseWriter, r *http.Request)
 
 	meeting, err := h.meetingRepo.GetByID(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "Meeting not found")
+		respondRepositoryError(w, r, err, "Meeting")
 		return
 	}
 
-	// Only creator or admin can update
-	if meeting.CreatedByID != currentUser.ID && !currentUser.IsAdmin() {
+	if !authz.Can(currentUser, authz.ActionMeetingManage, authz.MeetingResource(meeting)) {
 		respondError(w, http.StatusForbidden, "Forbidden: not meeting creator")
 		return
 	}
@@ -287,9 +777,35 @@ func (h *CalendarHandlers) UpdateMeeting(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	h.broker.Publish(r.Context(), events.MeetingUpdated, updatedMeeting)
+	h.notifier.NotifyMeetingInvite(r.Context(), updatedMeeting, currentUser, addedAttendeeIDs(meeting.Attendees, updatedMeeting.Attendees))
 	respondJSON(w, http.StatusOK, updatedMeeting)
 }
 
+// attendeeIDs returns the user IDs of a meeting's attendees
+func attendeeIDs(attendees []models.MeetingAttendee) []int64 {
+	ids := make([]int64, len(attendees))
+	for i, a := range attendees {
+		ids[i] = a.UserID
+	}
+	return ids
+}
+
+// addedAttendeeIDs returns the user IDs attending after an update who weren't attending before
+func addedAttendeeIDs(before, after []models.MeetingAttendee) []int64 {
+	existing := make(map[int64]bool, len(before))
+	for _, a := range before {
+		existing[a.UserID] = true
+	}
+	var added []int64
+	for _, a := range after {
+		if !existing[a.UserID] {
+			added = append(added, a.UserID)
+		}
+	}
+	return added
+}
+
 // DeleteMeeting deletes a meeting
 func (h *CalendarHandlers) DeleteMeeting(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
@@ -305,12 +821,11 @@ func (h *CalendarHandlers) DeleteMeeting(w http.ResponseWriter, r *http.Request)
 
 	meeting, err := h.meetingRepo.GetByID(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "Meeting not found")
+		respondRepositoryError(w, r, err, "Meeting")
 		return
 	}
 
-	// Only creator or admin can delete
-	if meeting.CreatedByID != currentUser.ID && !currentUser.IsAdmin() {
+	if !authz.Can(currentUser, authz.ActionMeetingManage, authz.MeetingResource(meeting)) {
 		respondError(w, http.StatusForbidden, "Forbidden: not meeting creator")
 		return
 	}
@@ -320,9 +835,138 @@ func (h *CalendarHandlers) DeleteMeeting(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	h.broker.Publish(r.Context(), events.MeetingDeleted, meeting)
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// RestoreMeeting restores a deleted meeting, with the exceptions of its series deleted along with
+// it, before it is purged (requires deleted:restore)
+func (h *CalendarHandlers) RestoreMeeting(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionDeletedRestore) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	meeting, err := h.meetingRepo.Restore(r.Context(), id)
+	if err != nil {
+		respondRepositoryError(w, r, err, "Deleted meeting")
+		return
+	}
+
+	h.broker.Publish(r.Context(), events.MeetingCreated, meeting)
+	respondJSON(w, http.StatusOK, meeting)
+}
+
+// UpdateMeetingOccurrence edits a single occurrence, or an occurrence and all following ones, of a recurring meeting
+func (h *CalendarHandlers) UpdateMeetingOccurrence(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	series := h.getEditableSeries(w, r, currentUser)
+	if series == nil {
+		return
+	}
+
+	var req models.UpdateMeetingOccurrenceRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+	if !series.OccursAt(req.OccurrenceStart) {
+		respondError(w, http.StatusBadRequest, "occurrence_start does not match an occurrence of this meeting")
+		return
+	}
+
+	meeting, err := h.meetingRepo.UpdateOccurrence(r.Context(), series.ID, &req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update meeting occurrence")
+		return
+	}
+
+	h.broker.Publish(r.Context(), events.MeetingUpdated, meeting)
+	respondJSON(w, http.StatusOK, meeting)
+}
+
+// CancelMeetingOccurrence cancels a single occurrence, or an occurrence and all following ones, of a recurring meeting.
+// The occurrence is given by the occurrence_start (RFC 3339) and scope ("this" or "following") query parameters.
+func (h *CalendarHandlers) CancelMeetingOccurrence(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	series := h.getEditableSeries(w, r, currentUser)
+	if series == nil {
+		return
+	}
+
+	req := models.CancelMeetingOccurrenceRequest{
+		Scope: models.OccurrenceScope(r.URL.Query().Get("scope")),
+	}
+	if req.Scope == "" {
+		req.Scope = models.OccurrenceScopeThis
+	}
+	if raw := r.URL.Query().Get("occurrence_start"); raw != "" {
+		occurrenceStart, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid occurrence_start format (use RFC3339)")
+			return
+		}
+		req.OccurrenceStart = occurrenceStart
+	}
+	if !validateRequest(w, &req) {
+		return
+	}
+	if !series.OccursAt(req.OccurrenceStart) {
+		respondError(w, http.StatusBadRequest, "occurrence_start does not match an occurrence of this meeting")
+		return
+	}
+
+	if err := h.meetingRepo.CancelOccurrence(r.Context(), series.ID, req.OccurrenceStart, req.Scope); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to cancel meeting occurrence")
+		return
+	}
+
+	h.broker.Publish(r.Context(), events.MeetingUpdated, series)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getEditableSeries loads the recurring meeting named by the id URL parameter and checks the user may edit it.
+// It writes an error response and returns nil if the meeting is missing, not recurring, or not editable.
+func (h *CalendarHandlers) getEditableSeries(w http.ResponseWriter, r *http.Request, user *models.User) *models.Meeting {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid meeting ID")
+		return nil
+	}
+
+	meeting, err := h.meetingRepo.GetByID(r.Context(), id)
+	if err != nil {
+		respondRepositoryError(w, r, err, "Meeting")
+		return nil
+	}
+
+	if !authz.Can(user, authz.ActionMeetingManage, authz.MeetingResource(meeting)) {
+		respondError(w, http.StatusForbidden, "Forbidden: not meeting creator")
+		return nil
+	}
+
+	if meeting.RecurrenceType == nil {
+		respondError(w, http.StatusBadRequest, "Meeting is not recurring")
+		return nil
+	}
+
+	return meeting
+}
+
 // RespondToMeeting updates the user's response to a meeting invitation
 func (h *CalendarHandlers) RespondToMeeting(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
@@ -352,23 +996,37 @@ func (h *CalendarHandlers) RespondToMeeting(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	meeting, err := h.meetingRepo.GetByID(r.Context(), id)
+	if err != nil || meeting == nil {
+		respondError(w, http.StatusNotFound, "Meeting not found")
+		return
+	}
+
 	if err := h.meetingRepo.RespondToMeeting(r.Context(), id, currentUser.ID, req.Response); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to update response")
 		return
 	}
 
+	// Tell the organizer about changed responses, with the tally as it now stands
+	changed := false
+	for i := range meeting.Attendees {
+		attendee := &meeting.Attendees[i]
+		if attendee.UserID == currentUser.ID && attendee.ResponseStatus != req.Response {
+			attendee.ResponseStatus = req.Response
+			changed = true
+		}
+	}
+	if changed {
+		h.notifier.NotifyMeetingResponse(r.Context(), meeting, currentUser, req.Response)
+	}
+
 	respondJSON(w, http.StatusOK, map[string]string{"status": "updated"})
 }
 
 // canViewTask checks if a user can view a task
 func (h *CalendarHandlers) canViewTask(user *models.User, task *models.Task) bool {
-	// Admin can see all
-	if user.IsAdmin() {
-		return true
-	}
-
-	// Creator can see their own tasks
-	if task.CreatedByID == user.ID {
+	// Whoever may manage the task, such as its creator, can see it
+	if authz.Can(user, authz.ActionTaskManage, authz.TaskResource(task)) {
 		return true
 	}
 
@@ -398,15 +1056,25 @@ func (h *CalendarHandlers) canViewTask(user *models.User, task *models.Task) boo
 	return false
 }
 
-// canViewMeeting checks if a user can view a meeting
-func (h *CalendarHandlers) canViewMeeting(ctx context.Context, user *models.User, meeting *models.Meeting) bool {
-	// Admin can see all
-	if user.IsAdmin() {
+// leadsTaskSquad reports whether the task is assigned to a squad the user leads or to someone in one
+func (h *CalendarHandlers) leadsTaskSquad(ctx context.Context, user *models.User, task *models.Task) bool {
+	if h.squadRepo == nil {
+		return false
+	}
+	if task.AssignedUserID != nil && leadsSquadOf(ctx, h.squadRepo, user, *task.AssignedUserID) {
 		return true
 	}
+	if task.AssignmentType == models.AssignmentTypeSquad && task.AssignedSquadID != nil {
+		squadIDs, err := h.squadRepo.GetLedSquadIDs(ctx, user.ID)
+		return err == nil && slices.Contains(squadIDs, *task.AssignedSquadID)
+	}
+	return false
+}
 
-	// Creator can see their own meetings
-	if meeting.CreatedByID == user.ID {
+// canViewMeeting checks if a user can view a meeting
+func (h *CalendarHandlers) canViewMeeting(ctx context.Context, user *models.User, meeting *models.Meeting) bool {
+	// Whoever may manage the meeting, such as its creator, can see it
+	if authz.Can(user, authz.ActionMeetingManage, authz.MeetingResource(meeting)) {
 		return true
 	}
 
@@ -418,3 +1086,30 @@ func (h *CalendarHandlers) canViewMeeting(ctx context.Context, user *models.User
 
 	return false
 }
+
+// canViewEvent checks if a user may receive a streamed calendar event.
+// It works from the event payload alone so no queries run per subscriber.
+func (h *CalendarHandlers) canViewEvent(user *models.User, event events.Event) bool {
+	// Nobody, admins included, sees changes made in another organization
+	if event.OrgID != user.OrgID {
+		return false
+	}
+	switch payload := event.Payload.(type) {
+	case *models.Task:
+		return h.canViewTask(user, payload)
+	case *models.Meeting:
+		if authz.Can(user, authz.ActionMeetingManage, authz.MeetingResource(payload)) {
+			return true
+		}
+		for _, attendee := range payload.Attendees {
+			if attendee.UserID == user.ID {
+				return true
+			}
+		}
+		return false
+	case *models.TimeOffRequest:
+		return canViewTimeOffRequest(user, payload)
+	default:
+		return false
+	}
+}
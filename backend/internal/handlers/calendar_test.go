@@ -1,15 +1,21 @@
 package handlers
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/smith-dallin/manager-dashboard/internal/events"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
 )
 
 func TestCalendarHandlers_CreateTask_Authorization(t *testing.T) {
@@ -59,6 +65,112 @@ func TestCalendarHandlers_CreateTask_Authorization(t *testing.T) {
 	}
 }
 
+func TestCalendarHandlers_BulkCreateTasks(t *testing.T) {
+	valid := `{"title":"Plan sprint","due_date":"2024-01-15T00:00:00Z","assignment_type":"user","assigned_user_id":2}`
+	tests := []struct {
+		name           string
+		requestBody    string
+		expectedStatus int
+		expectedTasks  int
+		expectedErrors []int // Indexes reported as invalid
+	}{
+		{"creates every task", `{"tasks":[` + valid + `,` + valid + `]}`, http.StatusCreated, 2, nil},
+		{"reports each invalid task and creates none", `{"tasks":[` + valid + `,{"title":"","due_date":"2024-01-15T00:00:00Z","assignment_type":"user","assigned_user_id":2},{"title":"No assignee","due_date":"2024-01-15T00:00:00Z","assignment_type":"user"}]}`, http.StatusBadRequest, 0, []int{1, 2}},
+		{"empty batch", `{"tasks":[]}`, http.StatusBadRequest, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := mocks.NewMockTaskRepository()
+			h := NewCalendarHandlers(nil, taskRepo, mocks.NewMockMeetingRepository())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/calendar/tasks/bulk", bytes.NewBufferString(tt.requestBody))
+			req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleSupervisor}))
+			rr := httptest.NewRecorder()
+			h.BulkCreateTasks(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("BulkCreateTasks() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			if len(taskRepo.Tasks) != tt.expectedTasks {
+				t.Errorf("created %d tasks, want %d", len(taskRepo.Tasks), tt.expectedTasks)
+			}
+			if tt.expectedErrors == nil {
+				return
+			}
+			var result models.BulkTaskResult
+			if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(result.Errors) != len(tt.expectedErrors) {
+				t.Fatalf("errors = %+v, want indexes %v", result.Errors, tt.expectedErrors)
+			}
+			for i, e := range result.Errors {
+				if e.Index == nil || *e.Index != tt.expectedErrors[i] {
+					t.Errorf("error %d = %+v, want index %d", i, e, tt.expectedErrors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarHandlers_ReassignTasks(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		requestBody    string
+		expectedStatus int
+		expectedMoved  []int64
+	}{
+		{"creator moves their open tasks", &models.User{ID: 1, Role: models.RoleSupervisor}, `{"from_user_id":2,"to_user_id":3}`, http.StatusOK, []int64{1, 2}},
+		{"selected tasks only", &models.User{ID: 1, Role: models.RoleSupervisor}, `{"from_user_id":2,"to_user_id":3,"task_ids":[2]}`, http.StatusOK, []int64{2}},
+		{"completed task can't be moved", &models.User{ID: 1, Role: models.RoleSupervisor}, `{"from_user_id":2,"to_user_id":3,"task_ids":[1,4]}`, http.StatusBadRequest, nil},
+		{"someone else's task blocks the move", &models.User{ID: 5, Role: models.RoleSupervisor}, `{"from_user_id":2,"to_user_id":3}`, http.StatusBadRequest, nil},
+		{"admin moves any open task", &models.User{ID: 9, Role: models.RoleAdmin}, `{"from_user_id":2,"to_user_id":3}`, http.StatusOK, []int64{1, 2}},
+		{"same user", &models.User{ID: 1, Role: models.RoleSupervisor}, `{"from_user_id":2,"to_user_id":2}`, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := mocks.NewMockTaskRepository()
+			assignee := int64(2)
+			for _, task := range []*models.Task{
+				{ID: 1, Status: models.TaskStatusPending},
+				{ID: 2, Status: models.TaskStatusInProgress},
+				{ID: 4, Status: models.TaskStatusCompleted},
+			} {
+				task.CreatedByID = 1
+				task.AssignmentType = models.AssignmentTypeUser
+				task.AssignedUserID = &assignee
+				taskRepo.Tasks[task.ID] = task
+			}
+			h := NewCalendarHandlers(nil, taskRepo, mocks.NewMockMeetingRepository())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/calendar/tasks/reassign", bytes.NewBufferString(tt.requestBody))
+			req = req.WithContext(ctxWithUserFrom(req.Context(), tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.ReassignTasks(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("ReassignTasks() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			moved := map[int64]bool{}
+			for _, id := range tt.expectedMoved {
+				moved[id] = true
+			}
+			for id, task := range taskRepo.Tasks {
+				want := int64(2)
+				if moved[id] {
+					want = 3
+				}
+				if *task.AssignedUserID != want {
+					t.Errorf("task %d assigned to %d, want %d", id, *task.AssignedUserID, want)
+				}
+			}
+		})
+	}
+}
+
 func TestCalendarHandlers_GetTask_Authorization(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -296,6 +408,52 @@ func TestCalendarHandlers_UpdateTask_AdminCanUpdate(t *testing.T) {
 	}
 }
 
+func TestCalendarHandlers_UpdateTask_AdvancesRecurrence(t *testing.T) {
+	taskRepo := mocks.NewMockTaskRepository()
+	weekly := models.RecurrenceTypeWeekly
+	due := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+	taskRepo.Tasks[1] = &models.Task{ID: 1, Title: "Submit timesheet", Status: models.TaskStatusPending, DueDate: due,
+		CreatedByID: 1, RecurrenceType: &weekly, RecurrenceInterval: 1}
+	taskRepo.NextID = 2
+	broker := events.NewBroker(4)
+	published, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+	h := NewCalendarHandlersWithEvents(nil, taskRepo, mocks.NewMockMeetingRepository(), broker)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/calendar/tasks/1", bytes.NewBufferString(`{"status":"completed"}`))
+	req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleEmployee}), "id", "1"))
+	rr := httptest.NewRecorder()
+	h.UpdateTask(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("UpdateTask() status = %v, body = %s", rr.Code, rr.Body.String())
+	}
+	var finished models.Task
+	if err := json.Unmarshal(rr.Body.Bytes(), &finished); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if finished.RecurrenceType != nil {
+		t.Errorf("finished instance still recurs: %+v", finished)
+	}
+	next, ok := taskRepo.Tasks[2]
+	if !ok || !next.DueDate.Equal(due.AddDate(0, 0, 7)) || next.Status != models.TaskStatusPending || next.RecurrenceType == nil {
+		t.Fatalf("next instance = %+v, want a pending task due a week later", next)
+	}
+
+	var types []events.Type
+	for len(types) < 2 {
+		select {
+		case event := <-published:
+			types = append(types, event.Type)
+		case <-time.After(time.Second):
+			t.Fatalf("published %v, want an update and a create", types)
+		}
+	}
+	if types[0] != events.TaskUpdated || types[1] != events.TaskCreated {
+		t.Errorf("published %v, want the update then the next instance", types)
+	}
+}
+
 func TestCalendarHandlers_DeleteTask_Authorization(t *testing.T) {
 	userID := int64(1)
 	otherUserID := int64(2)
@@ -365,6 +523,182 @@ func TestCalendarHandlers_DeleteTask_Authorization(t *testing.T) {
 	}
 }
 
+func TestCalendarHandlers_RestoreTask(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	creator := &models.User{ID: 2, Role: models.RoleEmployee}
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		taskID         string
+		expectedStatus int
+	}{
+		{"creator cannot restore", creator, "1", http.StatusForbidden},
+		{"admin restores deleted task", admin, "1", http.StatusOK},
+		{"task that isn't deleted", admin, "2", http.StatusNotFound},
+		{"invalid ID", admin, "abc", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := mocks.NewMockTaskRepository()
+			taskRepo.AddTask(&models.Task{ID: 1, Title: "Deleted", CreatedByID: creator.ID, DueDate: time.Now()})
+			taskRepo.AddTask(&models.Task{ID: 2, Title: "Kept", CreatedByID: creator.ID, DueDate: time.Now()})
+			_ = taskRepo.Delete(context.Background(), 1)
+
+			h := NewCalendarHandlers(nil, taskRepo, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/tasks/"+tt.taskID+"/restore", nil)
+			req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), tt.currentUser), "id", tt.taskID))
+
+			rr := httptest.NewRecorder()
+			h.RestoreTask(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("RestoreTask() status = %v, want %v: %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			_, restored := taskRepo.Tasks[1]
+			if restored != (rr.Code == http.StatusOK) {
+				t.Errorf("task restored = %v after status %d", restored, rr.Code)
+			}
+		})
+	}
+}
+
+func TestCalendarHandlers_ListTasks(t *testing.T) {
+	userID := int64(1)
+	otherUserID := int64(2)
+	now := time.Now()
+
+	taskRepo := mocks.NewMockTaskRepository()
+	taskRepo.AddTask(&models.Task{ID: 1, Title: "Overdue", CreatedByID: userID, Status: models.TaskStatusPending,
+		Priority: models.TaskPriorityHigh, Labels: []string{"release"}, DueDate: now.AddDate(0, 0, -3)})
+	taskRepo.AddTask(&models.Task{ID: 2, Title: "Done late", CreatedByID: userID, Status: models.TaskStatusCompleted,
+		Priority: models.TaskPriorityLow, DueDate: now.AddDate(0, 0, -5)})
+	taskRepo.AddTask(&models.Task{ID: 3, Title: "Assigned to me", CreatedByID: otherUserID, AssignedUserID: &userID, Status: models.TaskStatusInProgress,
+		Priority: models.TaskPriorityUrgent, Labels: []string{"release", "docs"}, DueDate: now.AddDate(0, 0, 2)})
+	taskRepo.AddTask(&models.Task{ID: 4, Title: "Someone else's", CreatedByID: otherUserID, Status: models.TaskStatusPending,
+		Priority: models.TaskPriorityMedium, DueDate: now.AddDate(0, 0, 1)})
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedIDs    []int64
+	}{
+		{name: "visible tasks soonest due first", query: "", expectedStatus: http.StatusOK, expectedIDs: []int64{2, 1, 3}},
+		{name: "overdue excludes finished tasks", query: "?overdue=true", expectedStatus: http.StatusOK, expectedIDs: []int64{1}},
+		{name: "label filter is case-insensitive", query: "?label=Release", expectedStatus: http.StatusOK, expectedIDs: []int64{1, 3}},
+		{name: "priority filter", query: "?priority=urgent", expectedStatus: http.StatusOK, expectedIDs: []int64{3}},
+		{name: "assignee filter", query: "?assignee=1", expectedStatus: http.StatusOK, expectedIDs: []int64{3}},
+		{name: "status filter", query: "?status=completed", expectedStatus: http.StatusOK, expectedIDs: []int64{2}},
+		{name: "sort by priority defaults to most urgent first", query: "?sort=priority", expectedStatus: http.StatusOK, expectedIDs: []int64{3, 1, 2}},
+		{name: "invalid priority", query: "?priority=critical", expectedStatus: http.StatusBadRequest},
+		{name: "invalid sort", query: "?sort=title", expectedStatus: http.StatusBadRequest},
+		{name: "invalid assignee", query: "?assignee=me", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCalendarHandlers(nil, taskRepo, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/calendar/tasks"+tt.query, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: userID, Role: models.RoleEmployee}))
+
+			rr := httptest.NewRecorder()
+			h.ListTasks(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("ListTasks() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var tasks []models.Task
+			if err := json.Unmarshal(rr.Body.Bytes(), &tasks); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			ids := make([]int64, len(tasks))
+			for i, task := range tasks {
+				ids[i] = task.ID
+			}
+			if len(ids) != len(tt.expectedIDs) {
+				t.Fatalf("task IDs = %v, want %v", ids, tt.expectedIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.expectedIDs[i] {
+					t.Fatalf("task IDs = %v, want %v", ids, tt.expectedIDs)
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarHandlers_ListTasks_Download(t *testing.T) {
+	userID := int64(1)
+	otherUserID := int64(2)
+	dueDate := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	taskRepo := mocks.NewMockTaskRepository()
+	taskRepo.AddTask(&models.Task{ID: 1, Title: "=SUM(A1)", CreatedByID: userID, Status: models.TaskStatusPending,
+		Priority: models.TaskPriorityHigh, DueDate: dueDate})
+	taskRepo.AddTask(&models.Task{ID: 2, Title: "Someone else's", CreatedByID: otherUserID, Status: models.TaskStatusPending,
+		Priority: models.TaskPriorityMedium, DueDate: dueDate})
+
+	tests := []struct {
+		name            string
+		query           string
+		expectedStatus  int
+		expectedType    string
+		expectedContent string
+	}{
+		{
+			name:            "csv of visible tasks with selected columns",
+			query:           "?format=csv&columns=title,id",
+			expectedStatus:  http.StatusOK,
+			expectedType:    "text/csv; charset=utf-8",
+			expectedContent: "title,id\n'=SUM(A1),1\n",
+		},
+		{
+			name:           "xlsx",
+			query:          "?format=xlsx",
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		},
+		{name: "unknown column", query: "?format=csv&columns=id,secret", expectedStatus: http.StatusBadRequest},
+		{name: "unknown format", query: "?format=pdf", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCalendarHandlers(nil, taskRepo, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/calendar/tasks"+tt.query, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: userID, Role: models.RoleEmployee}))
+
+			rr := httptest.NewRecorder()
+			h.ListTasks(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("ListTasks() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if got := rr.Header().Get("Content-Type"); got != tt.expectedType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.expectedType)
+			}
+			if got := rr.Header().Get("Content-Disposition"); !strings.HasPrefix(got, `attachment; filename="tasks-`) {
+				t.Errorf("Content-Disposition = %q, want a tasks attachment", got)
+			}
+			if tt.expectedContent != "" && rr.Body.String() != tt.expectedContent {
+				t.Errorf("body = %q, want %q", rr.Body.String(), tt.expectedContent)
+			}
+		})
+	}
+}
+
 func TestCalendarHandlers_CreateMeeting_Authorization(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -411,6 +745,114 @@ func TestCalendarHandlers_CreateMeeting_Authorization(t *testing.T) {
 	}
 }
 
+func TestParseSuggestMeetingTimes(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{name: "defaults to the next week", query: "duration_minutes=30&attendee_ids=2,3"},
+		{name: "explicit window", query: "duration_minutes=60&start=2026-03-09T00:00:00Z&end=2026-03-10T00:00:00Z&limit=10"},
+		{name: "missing duration", query: "attendee_ids=2", wantErr: true},
+		{name: "duration too short", query: "duration_minutes=5", wantErr: true},
+		{name: "invalid attendee", query: "duration_minutes=30&attendee_ids=2,bob", wantErr: true},
+		{name: "window too long", query: "duration_minutes=30&start=2026-03-01T00:00:00Z&end=2026-04-01T00:00:00Z", wantErr: true},
+		{name: "limit too high", query: "duration_minutes=30&limit=500", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/calendar/suggest-times?"+tt.query, nil)
+			req, err := parseSuggestMeetingTimes(r, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSuggestMeetingTimes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (req.Limit == 0 || !req.End.After(req.Start)) {
+				t.Errorf("request = %+v", req)
+			}
+		})
+	}
+}
+
+func TestCalendarHandlers_CheckMeetingConflicts_Validation(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		requestBody    string
+		expectedStatus int
+	}{
+		{
+			name:           "unauthenticated cannot check conflicts",
+			currentUser:    nil,
+			requestBody:    `{"start_time":"2024-01-15T10:00:00Z","end_time":"2024-01-15T11:00:00Z","attendee_ids":[2]}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "end before start is rejected",
+			currentUser:    &models.User{ID: 1, Role: models.RoleEmployee},
+			requestBody:    `{"start_time":"2024-01-15T11:00:00Z","end_time":"2024-01-15T10:00:00Z","attendee_ids":[2]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing start is rejected",
+			currentUser:    &models.User{ID: 1, Role: models.RoleEmployee},
+			requestBody:    `{"end_time":"2024-01-15T10:00:00Z","attendee_ids":[2]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCalendarHandlers(nil, nil, mocks.NewMockMeetingRepository())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/calendar/meetings/check-conflicts", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.currentUser != nil {
+				req = req.WithContext(ctxWithUserFrom(req.Context(), tt.currentUser))
+			}
+
+			rr := httptest.NewRecorder()
+			h.CheckMeetingConflicts(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("CheckMeetingConflicts() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestCalendarHandlers_GetEvents_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"end before start", "?start=2024-03-01T00:00:00Z&end=2024-02-01T00:00:00Z"},
+		{"window too wide", "?start=2024-01-01T00:00:00Z&end=2024-06-01T00:00:00Z"},
+		{"non-numeric limit", "?start=2024-03-01T00:00:00Z&end=2024-03-31T00:00:00Z&limit=ten"},
+		{"zero limit", "?start=2024-03-01T00:00:00Z&end=2024-03-31T00:00:00Z&limit=0"},
+		{"malformed cursor", "?start=2024-03-01T00:00:00Z&end=2024-03-31T00:00:00Z&cursor=not-a-cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCalendarHandlers(nil, nil, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/calendar/events"+tt.query, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleEmployee}))
+
+			rr := httptest.NewRecorder()
+			h.GetEvents(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("GetEvents() status = %v, want %v, body = %s", rr.Code, http.StatusBadRequest, rr.Body.String())
+			}
+		})
+	}
+}
+
 func TestCalendarHandlers_GetMeeting_Success(t *testing.T) {
 	userID := int64(1)
 	meetingID := int64(1)
@@ -665,6 +1107,207 @@ func TestCalendarHandlers_DeleteMeeting_Authorization(t *testing.T) {
 	}
 }
 
+func TestCalendarHandlers_RestoreMeeting(t *testing.T) {
+	meetingRepo := mocks.NewMockMeetingRepository()
+	meetingRepo.AddMeeting(&models.Meeting{ID: 1, Title: "Standup", StartTime: time.Now(), EndTime: time.Now().Add(time.Hour), CreatedByID: 2})
+	_ = meetingRepo.Delete(context.Background(), 1)
+
+	h := NewCalendarHandlers(nil, nil, meetingRepo)
+	restore := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/meetings/1/restore", nil)
+		req = req.WithContext(chiCtxWithID(ctxWithUser(&models.User{ID: 1, Role: models.RoleAdmin}), "id", "1"))
+		rr := httptest.NewRecorder()
+		h.RestoreMeeting(rr, req)
+		return rr
+	}
+
+	if rr := restore(); rr.Code != http.StatusOK {
+		t.Fatalf("RestoreMeeting() status = %v, want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if _, err := meetingRepo.GetByID(context.Background(), 1); err != nil {
+		t.Errorf("restored meeting not found: %v", err)
+	}
+	if rr := restore(); rr.Code != http.StatusNotFound {
+		t.Errorf("restoring twice status = %v, want %v", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestCalendarHandlers_UpdateMeetingOccurrence(t *testing.T) {
+	creatorID := int64(1)
+	weekly := models.RecurrenceTypeWeekly
+	seriesStart := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		meetingID      string
+		requestBody    string
+		expectedStatus int
+	}{
+		{
+			name:           "unauthenticated cannot update occurrence",
+			meetingID:      "1",
+			requestBody:    `{"occurrence_start":"2026-03-09T15:00:00Z","scope":"this","title":"Moved"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "non-creator cannot update occurrence",
+			currentUser:    &models.User{ID: 2, Role: models.RoleEmployee},
+			meetingID:      "1",
+			requestBody:    `{"occurrence_start":"2026-03-09T15:00:00Z","scope":"this","title":"Moved"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "non-recurring meeting is rejected",
+			currentUser:    &models.User{ID: creatorID, Role: models.RoleEmployee},
+			meetingID:      "2",
+			requestBody:    `{"occurrence_start":"2026-03-09T15:00:00Z","scope":"this","title":"Moved"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "occurrence not in series is rejected",
+			currentUser:    &models.User{ID: creatorID, Role: models.RoleEmployee},
+			meetingID:      "1",
+			requestBody:    `{"occurrence_start":"2026-03-10T15:00:00Z","scope":"this","title":"Moved"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "single occurrence cannot change recurrence",
+			currentUser:    &models.User{ID: creatorID, Role: models.RoleEmployee},
+			meetingID:      "1",
+			requestBody:    `{"occurrence_start":"2026-03-09T15:00:00Z","scope":"this","recurrence_type":"daily"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "creator can update single occurrence",
+			currentUser:    &models.User{ID: creatorID, Role: models.RoleEmployee},
+			meetingID:      "1",
+			requestBody:    `{"occurrence_start":"2026-03-09T15:00:00Z","scope":"this","title":"Moved"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "admin can update following occurrences",
+			currentUser:    &models.User{ID: 3, Role: models.RoleAdmin},
+			meetingID:      "1",
+			requestBody:    `{"occurrence_start":"2026-03-16T15:00:00Z","scope":"following","recurrence_type":"daily"}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meetingRepo := mocks.NewMockMeetingRepository()
+			meetingRepo.AddMeeting(&models.Meeting{
+				ID:                 1,
+				Title:              "Weekly Sync",
+				StartTime:          seriesStart,
+				EndTime:            seriesStart.Add(30 * time.Minute),
+				CreatedByID:        creatorID,
+				RecurrenceType:     &weekly,
+				RecurrenceInterval: 1,
+			})
+			meetingRepo.AddMeeting(&models.Meeting{
+				ID:          2,
+				Title:       "One-off",
+				StartTime:   seriesStart,
+				EndTime:     seriesStart.Add(time.Hour),
+				CreatedByID: creatorID,
+			})
+
+			h := NewCalendarHandlers(nil, nil, meetingRepo)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/calendar/meetings/"+tt.meetingID+"/occurrence", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			ctx := req.Context()
+			if tt.currentUser != nil {
+				ctx = ctxWithUserFrom(ctx, tt.currentUser)
+			}
+			ctx = chiCtxWithID(ctx, "id", tt.meetingID)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			h.UpdateMeetingOccurrence(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("UpdateMeetingOccurrence() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestCalendarHandlers_CancelMeetingOccurrence(t *testing.T) {
+	creatorID := int64(1)
+	daily := models.RecurrenceTypeDaily
+	seriesStart := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		wantException  bool
+		wantEnded      bool
+	}{
+		{
+			name:           "missing occurrence_start",
+			query:          "scope=this",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid scope",
+			query:          "occurrence_start=2026-03-04T15:00:00Z&scope=all",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "scope defaults to this occurrence",
+			query:          "occurrence_start=2026-03-04T15:00:00Z",
+			expectedStatus: http.StatusNoContent,
+			wantException:  true,
+		},
+		{
+			name:           "cancel following occurrences ends the series",
+			query:          "occurrence_start=2026-03-04T15:00:00Z&scope=following",
+			expectedStatus: http.StatusNoContent,
+			wantEnded:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meetingRepo := mocks.NewMockMeetingRepository()
+			series := &models.Meeting{
+				ID:                 1,
+				Title:              "Standup",
+				StartTime:          seriesStart,
+				EndTime:            seriesStart.Add(15 * time.Minute),
+				CreatedByID:        creatorID,
+				RecurrenceType:     &daily,
+				RecurrenceInterval: 1,
+			}
+			meetingRepo.AddMeeting(series)
+
+			h := NewCalendarHandlers(nil, nil, meetingRepo)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/calendar/meetings/1/occurrence?"+tt.query, nil)
+			ctx := ctxWithUserFrom(req.Context(), &models.User{ID: creatorID, Role: models.RoleEmployee})
+			ctx = chiCtxWithID(ctx, "id", "1")
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			h.CancelMeetingOccurrence(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("CancelMeetingOccurrence() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			if got := series.HasException(seriesStart.AddDate(0, 0, 2)); got != tt.wantException {
+				t.Errorf("HasException() = %v, want %v", got, tt.wantException)
+			}
+			if got := series.RecurrenceEndDate != nil; got != tt.wantEnded {
+				t.Errorf("series ended = %v, want %v", got, tt.wantEnded)
+			}
+		})
+	}
+}
+
 func TestCalendarHandlers_RespondToMeeting_Success(t *testing.T) {
 	creatorID := int64(1)
 	attendeeID := int64(2)
@@ -703,6 +1346,62 @@ func TestCalendarHandlers_RespondToMeeting_Success(t *testing.T) {
 	}
 }
 
+func TestCalendarHandlers_RespondToMeeting_NotifiesOrganizer(t *testing.T) {
+	meetingRepo := mocks.NewMockMeetingRepository()
+	startTime := time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)
+	meetingRepo.AddMeeting(&models.Meeting{
+		ID:          1,
+		Title:       "Roadmap review",
+		StartTime:   startTime,
+		EndTime:     startTime.Add(time.Hour),
+		CreatedByID: 1,
+		Attendees: []models.MeetingAttendee{
+			{UserID: 2, ResponseStatus: models.ResponseStatusPending},
+			{UserID: 3, ResponseStatus: models.ResponseStatusDeclined},
+		},
+	})
+	meetingRepo.AddAttendee(1, 2, models.ResponseStatusPending)
+
+	notificationRepo := mocks.NewMockNotificationRepository()
+	notifier := services.NewNotificationService(notificationRepo, mocks.NewMockUserRepository(), nil)
+	h := NewCalendarHandlersWithNotifications(nil, nil, meetingRepo, nil, nil, notifier, nil)
+	attendee := &models.User{ID: 2, FirstName: "Ada", LastName: "Lovelace", Role: models.RoleEmployee}
+
+	respond := func(response string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/calendar/meetings/1/respond", bytes.NewBufferString(`{"response":"`+response+`"}`))
+		ctx := ctxWithUserFrom(req.Context(), attendee)
+		req = req.WithContext(chiCtxWithID(ctx, "id", "1"))
+		rr := httptest.NewRecorder()
+		h.RespondToMeeting(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("RespondToMeeting() status = %v, body = %s", rr.Code, rr.Body.String())
+		}
+	}
+
+	respond("accepted")
+
+	if len(notificationRepo.Notifications) != 1 {
+		t.Fatalf("recorded %d notifications, want 1", len(notificationRepo.Notifications))
+	}
+	n := notificationRepo.Notifications[0]
+	if n.UserID != 1 || n.Type != models.NotificationTypeMeetingResponse || *n.EntityID != 1 || *n.ActorID != 2 {
+		t.Errorf("notification = %+v", n)
+	}
+	if n.Title != "Ada Lovelace accepted Roadmap review" {
+		t.Errorf("title = %q", n.Title)
+	}
+	if !strings.Contains(n.Body, "1 accepted, 1 declined, 0 tentative, 0 awaiting") {
+		t.Errorf("body = %q, want the running tally", n.Body)
+	}
+
+	// Repeating the same response doesn't notify again
+	respond("accepted")
+	if len(notificationRepo.Notifications) != 1 {
+		t.Errorf("recorded %d notifications after an unchanged response, want 1", len(notificationRepo.Notifications))
+	}
+}
+
 func TestCalendarHandlers_RespondToMeeting_NotAttendee(t *testing.T) {
 	creatorID := int64(1)
 	nonAttendeeID := int64(3)
@@ -813,3 +1512,174 @@ func TestCalendarHandlers_canViewTask_AdminCanSeeAll(t *testing.T) {
 		t.Error("canViewTask() should return true for admin")
 	}
 }
+
+func TestCalendarHandlers_canViewEvent(t *testing.T) {
+	supervisorID := int64(9)
+	employee := &models.User{ID: 2, Role: models.RoleEmployee}
+	supervisor := &models.User{ID: supervisorID, Role: models.RoleSupervisor}
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		event    events.Event
+		expected bool
+	}{
+		{
+			name:     "admin does not receive another organization's task event",
+			user:     &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 2},
+			event:    events.Event{Type: events.TaskCreated, Payload: &models.Task{ID: 1, CreatedByID: 3}, OrgID: 1},
+			expected: false,
+		},
+		{
+			name:     "admin does not receive another organization's meeting event",
+			user:     &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 2},
+			event:    events.Event{Type: events.MeetingCreated, Payload: &models.Meeting{ID: 1, CreatedByID: 3}, OrgID: 1},
+			expected: false,
+		},
+		{
+			name:     "task creator receives task event",
+			user:     employee,
+			event:    events.Event{Type: events.TaskCreated, Payload: &models.Task{ID: 1, CreatedByID: 2}},
+			expected: true,
+		},
+		{
+			name:     "unrelated user does not receive task event",
+			user:     employee,
+			event:    events.Event{Type: events.TaskCreated, Payload: &models.Task{ID: 1, CreatedByID: 3}},
+			expected: false,
+		},
+		{
+			name: "attendee receives meeting event",
+			user: employee,
+			event: events.Event{Type: events.MeetingUpdated, Payload: &models.Meeting{
+				ID: 1, CreatedByID: 3, Attendees: []models.MeetingAttendee{{UserID: 2}},
+			}},
+			expected: true,
+		},
+		{
+			name:     "non-attendee does not receive meeting event",
+			user:     employee,
+			event:    events.Event{Type: events.MeetingDeleted, Payload: &models.Meeting{ID: 1, CreatedByID: 3}},
+			expected: false,
+		},
+		{
+			name: "supervisor receives direct report's time off decision",
+			user: supervisor,
+			event: events.Event{Type: events.TimeOffReviewed, Payload: &models.TimeOffRequest{
+				ID: 1, UserID: 2, User: &models.User{ID: 2, SupervisorID: &supervisorID},
+			}},
+			expected: true,
+		},
+		{
+			name:     "other employee does not receive time off decision",
+			user:     &models.User{ID: 5, Role: models.RoleEmployee},
+			event:    events.Event{Type: events.TimeOffReviewed, Payload: &models.TimeOffRequest{ID: 1, UserID: 2}},
+			expected: false,
+		},
+	}
+
+	h := NewCalendarHandlers(nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.canViewEvent(tt.user, tt.event); got != tt.expected {
+				t.Errorf("canViewEvent() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCalendarHandlers_StreamEvents(t *testing.T) {
+	broker := events.NewBroker(events.DefaultBufferSize)
+	h := NewCalendarHandlersWithEvents(nil, mocks.NewMockTaskRepository(), mocks.NewMockMeetingRepository(), broker)
+	user := &models.User{ID: 1, Role: models.RoleEmployee, OrgID: 1}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.StreamEvents(w, r.WithContext(ctxWithUserFrom(r.Context(), user)))
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for broker.SubscriberCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Invisible events, and those of other organizations, must be skipped; the visible one is delivered
+	org := tenant.WithOrgID(context.Background(), user.OrgID)
+	broker.Publish(org, events.TaskCreated, &models.Task{ID: 7, CreatedByID: 99})
+	broker.Publish(tenant.WithOrgID(context.Background(), 2), events.TaskCreated, &models.Task{ID: 6, CreatedByID: user.ID})
+	broker.Publish(org, events.TaskCreated, &models.Task{ID: 8, CreatedByID: user.ID})
+
+	reader := bufio.NewReader(resp.Body)
+	var eventLine, dataLine string
+	for dataLine == "" {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read stream: %v", err)
+		}
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventLine = strings.TrimSpace(strings.TrimPrefix(line, "event: "))
+		case strings.HasPrefix(line, "data: "):
+			dataLine = strings.TrimSpace(strings.TrimPrefix(line, "data: "))
+		}
+	}
+
+	if eventLine != string(events.TaskCreated) {
+		t.Errorf("event = %q, want %q", eventLine, events.TaskCreated)
+	}
+
+	var payload struct {
+		Type    events.Type `json:"type"`
+		Payload models.Task `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(dataLine), &payload); err != nil {
+		t.Fatalf("failed to decode event data: %v", err)
+	}
+	if payload.Payload.ID != 8 {
+		t.Errorf("received task ID = %d, want 8", payload.Payload.ID)
+	}
+}
+
+func TestCalendarHandlers_StreamEvents_Shutdown(t *testing.T) {
+	shutdown := make(chan struct{})
+	h := NewCalendarHandlersWithEvents(nil, mocks.NewMockTaskRepository(), mocks.NewMockMeetingRepository(), events.NewBroker(events.DefaultBufferSize)).
+		WithShutdown(shutdown)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar/stream", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleEmployee}))
+	done := make(chan struct{})
+	go func() {
+		h.StreamEvents(httptest.NewRecorder(), req)
+		close(done)
+	}()
+
+	close(shutdown)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream stayed open after shutdown")
+	}
+}
+
+func TestCalendarHandlers_StreamEvents_Disabled(t *testing.T) {
+	h := NewCalendarHandlers(nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar/stream", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleEmployee}))
+	rr := httptest.NewRecorder()
+	h.StreamEvents(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("StreamEvents() status = %v, want %v", rr.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// WithCustomFields serves the admin-defined custom field schema and includes users' values for it
+func (h *Handlers) WithCustomFields(customFieldRepo repository.CustomFieldRepository) *Handlers {
+	h.customFieldRepo = customFieldRepo
+	h.userService.WithCustomFields(customFieldRepo)
+	return h
+}
+
+// GetCustomFields godoc
+// @Summary List custom profile fields
+// @Description Returns the admin-defined profile fields in display order
+// @Tags Custom Fields
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.CustomField "Custom fields"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /custom-fields [get]
+func (h *Handlers) GetCustomFields(w http.ResponseWriter, r *http.Request) {
+	if requireAuth(w, r) == nil {
+		return
+	}
+
+	fields, err := h.customFieldRepo.GetAll(r.Context())
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get custom fields", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch custom fields")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, fields)
+}
+
+// CreateCustomField godoc
+// @Summary Create a custom profile field
+// @Description Adds a text, date, or select field to every user's profile. Admin only.
+// @Tags Custom Fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CreateCustomFieldRequest true "Field definition"
+// @Success 201 {object} models.CustomField "Created field"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 409 {object} map[string]interface{} "Key already in use"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /custom-fields [post]
+func (h *Handlers) CreateCustomField(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionCustomFieldManage) == nil {
+		return
+	}
+
+	var req models.CreateCustomFieldRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	existing, err := h.customFieldRepo.GetByKey(r.Context(), req.Key)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to check custom field key", err, "key", req.Key)
+		respondError(w, http.StatusInternalServerError, "Failed to create custom field")
+		return
+	}
+	if existing != nil {
+		respondError(w, http.StatusConflict, "A custom field with this key already exists")
+		return
+	}
+
+	field, err := h.customFieldRepo.Create(r.Context(), &req)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to create custom field", err, "key", req.Key)
+		respondError(w, http.StatusInternalServerError, "Failed to create custom field")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, field)
+}
+
+// UpdateCustomField godoc
+// @Summary Update a custom profile field
+// @Description Replaces a field's label, options, required flag, and position. Its key and type can't change. Admin only.
+// @Tags Custom Fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Field ID"
+// @Param body body models.UpdateCustomFieldRequest true "Field changes"
+// @Success 200 {object} models.CustomField "Updated field"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Field not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /custom-fields/{id} [put]
+func (h *Handlers) UpdateCustomField(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionCustomFieldManage) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid custom field ID")
+		return
+	}
+
+	var req models.UpdateCustomFieldRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	field, err := h.customFieldRepo.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get custom field", err, "field_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to update custom field")
+		return
+	}
+	if field == nil {
+		respondError(w, http.StatusNotFound, "Custom field not found")
+		return
+	}
+	if err := req.ValidateFor(field); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := h.customFieldRepo.Update(r.Context(), id, &req)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to update custom field", err, "field_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to update custom field")
+		return
+	}
+	if updated == nil {
+		respondError(w, http.StatusNotFound, "Custom field not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteCustomField godoc
+// @Summary Delete a custom profile field
+// @Description Removes a field along with every user's value for it. Admin only.
+// @Tags Custom Fields
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Field ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Field not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /custom-fields/{id} [delete]
+func (h *Handlers) DeleteCustomField(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionCustomFieldManage) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid custom field ID")
+		return
+	}
+
+	field, err := h.customFieldRepo.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get custom field", err, "field_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to delete custom field")
+		return
+	}
+	if field == nil {
+		respondError(w, http.StatusNotFound, "Custom field not found")
+		return
+	}
+
+	if err := h.customFieldRepo.Delete(r.Context(), id); err != nil {
+		h.logger.LogError(r.Context(), "Failed to delete custom field", err, "field_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to delete custom field")
+		return
+	}
+
+	// Cached users carry values for the deleted field
+	h.InvalidateUserCache(r.Context())
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Custom field deleted successfully"})
+}
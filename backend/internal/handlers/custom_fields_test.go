@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestCustomFieldHandlers_CRUD(t *testing.T) {
+	customFieldRepo := mocks.NewMockCustomFieldRepository()
+	h := New(mocks.NewMockUserRepository(), mocks.NewMockSquadRepository(), nil).WithCustomFields(customFieldRepo)
+
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	employee := &models.User{ID: 2, Role: models.RoleEmployee}
+
+	do := func(handler http.HandlerFunc, method string, user *models.User, id string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/custom-fields", bytes.NewBufferString(body))
+		ctx := ctxWithUserFrom(req.Context(), user)
+		if id != "" {
+			ctx = chiCtxWithID(ctx, "id", id)
+		}
+		rr := httptest.NewRecorder()
+		handler(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	createBody := `{"key":"shirt_size","label":"Shirt size","type":"select","options":["S","M","L"]}`
+
+	if rr := do(h.CreateCustomField, http.MethodPost, employee, "", createBody); rr.Code != http.StatusForbidden {
+		t.Errorf("CreateCustomField() as employee status = %d, want %d", rr.Code, http.StatusForbidden)
+	}
+
+	rr := do(h.CreateCustomField, http.MethodPost, admin, "", createBody)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("CreateCustomField() status = %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+	var field models.CustomField
+	if err := json.NewDecoder(rr.Body).Decode(&field); err != nil {
+		t.Fatal(err)
+	}
+
+	if rr := do(h.CreateCustomField, http.MethodPost, admin, "", createBody); rr.Code != http.StatusConflict {
+		t.Errorf("CreateCustomField() duplicate key status = %d, want %d", rr.Code, http.StatusConflict)
+	}
+	if rr := do(h.CreateCustomField, http.MethodPost, admin, "", `{"key":"size","label":"Size","type":"select"}`); rr.Code != http.StatusBadRequest {
+		t.Errorf("CreateCustomField() select without options status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	rr = do(h.GetCustomFields, http.MethodGet, employee, "", "")
+	var fields []models.CustomField
+	if err := json.NewDecoder(rr.Body).Decode(&fields); err != nil {
+		t.Fatal(err)
+	}
+	if rr.Code != http.StatusOK || len(fields) != 1 {
+		t.Errorf("GetCustomFields() status = %d, fields = %d, want 200 and 1", rr.Code, len(fields))
+	}
+
+	id := "1"
+	if rr := do(h.UpdateCustomField, http.MethodPut, admin, id, `{"label":"T-shirt size","options":["S","M","L","XL"]}`); rr.Code != http.StatusOK {
+		t.Errorf("UpdateCustomField() status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if got := customFieldRepo.Fields[field.ID]; got.Label != "T-shirt size" || len(got.Options) != 4 {
+		t.Errorf("UpdateCustomField() stored %+v", got)
+	}
+	if rr := do(h.UpdateCustomField, http.MethodPut, admin, id, `{"label":"T-shirt size"}`); rr.Code != http.StatusBadRequest {
+		t.Errorf("UpdateCustomField() removing all options status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+	if rr := do(h.UpdateCustomField, http.MethodPut, admin, "99", `{"label":"Missing"}`); rr.Code != http.StatusNotFound {
+		t.Errorf("UpdateCustomField() missing field status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+
+	if rr := do(h.DeleteCustomField, http.MethodDelete, employee, id, ""); rr.Code != http.StatusForbidden {
+		t.Errorf("DeleteCustomField() as employee status = %d, want %d", rr.Code, http.StatusForbidden)
+	}
+	if rr := do(h.DeleteCustomField, http.MethodDelete, admin, id, ""); rr.Code != http.StatusOK {
+		t.Errorf("DeleteCustomField() status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	if rr := do(h.DeleteCustomField, http.MethodDelete, admin, id, ""); rr.Code != http.StatusNotFound {
+		t.Errorf("DeleteCustomField() twice status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestUpdateUser_CustomFields(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Role: models.RoleEmployee})
+	customFieldRepo := mocks.NewMockCustomFieldRepository()
+	customFieldRepo.Fields[1] = &models.CustomField{ID: 1, Key: "start_date", Type: models.CustomFieldTypeDate}
+	h := New(userRepo, mocks.NewMockSquadRepository(), nil).WithCustomFields(customFieldRepo)
+
+	update := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/users/1", bytes.NewBufferString(body))
+		ctx := chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[1]), "id", "1")
+		rr := httptest.NewRecorder()
+		h.UpdateUser(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	rr := update(`{"custom_fields":{"start_date":"2024-03-01"}}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("UpdateUser() status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var resp models.UserResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.CustomFields["start_date"] != "2024-03-01" {
+		t.Errorf("UpdateUser() custom_fields = %v, want start_date 2024-03-01", resp.CustomFields)
+	}
+
+	for _, body := range []string{
+		`{"custom_fields":{"start_date":"March 1st"}}`,
+		`{"custom_fields":{"favorite_color":"blue"}}`,
+	} {
+		if rr := update(body); rr.Code != http.StatusBadRequest {
+			t.Errorf("UpdateUser(%s) status = %d, want %d", body, rr.Code, http.StatusBadRequest)
+		}
+	}
+	if got := customFieldRepo.Values[1][1]; got != "2024-03-01" {
+		t.Errorf("stored start_date = %q after rejected updates, want 2024-03-01", got)
+	}
+}
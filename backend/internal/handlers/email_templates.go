@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// EmailTemplateHandlers manage an organization's customized invitation and notification emails
+type EmailTemplateHandlers struct {
+	templates *services.EmailTemplateService
+	logger    *logger.Logger
+}
+
+// NewEmailTemplateHandlers creates a new email template handlers instance
+func NewEmailTemplateHandlers(templates *services.EmailTemplateService) *EmailTemplateHandlers {
+	return &EmailTemplateHandlers{
+		templates: templates,
+		logger:    logger.Default().WithComponent("email-template-handlers"),
+	}
+}
+
+// templateParams reads the email and language a request is for
+func templateParams(r *http.Request) (models.EmailTemplateKey, string) {
+	return models.EmailTemplateKey(chi.URLParam(r, "key")), chi.URLParam(r, "language")
+}
+
+// respondEmailTemplateError responds to an email template service error
+func (h *EmailTemplateHandlers) respondEmailTemplateError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, services.ErrUnknownEmailTemplate):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidEmailTemplate):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.LogError(r.Context(), msg, err)
+		respondError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// ListEmailTemplates godoc
+// @Summary List customizable emails
+// @Description Returns the emails the organization can customize, the variables each can use, and the languages the organization has customized each in. Requires emailtemplate:manage.
+// @Tags Email Templates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.EmailTemplateDefinition "Customizable emails"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/email-templates [get]
+func (h *EmailTemplateHandlers) ListEmailTemplates(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionEmailTemplateManage) == nil {
+		return
+	}
+
+	defs, err := h.templates.List(r.Context())
+	if err != nil {
+		h.respondEmailTemplateError(w, r, err, "Failed to fetch email templates")
+		return
+	}
+	respondJSON(w, http.StatusOK, defs)
+}
+
+// GetEmailTemplate godoc
+// @Summary Get an email template
+// @Description Returns the template an email is sent with in a language: the organization's customized one, or the built-in one. Requires emailtemplate:manage.
+// @Tags Email Templates
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Email" Enums(invitation, meeting_response)
+// @Param language path string true "Language" Enums(en, es)
+// @Success 200 {object} models.EmailTemplate "Email template"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Unknown email or language"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/email-templates/{key}/{language} [get]
+func (h *EmailTemplateHandlers) GetEmailTemplate(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionEmailTemplateManage) == nil {
+		return
+	}
+
+	key, language := templateParams(r)
+	if err := models.ValidateLanguage(language); err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	t, err := h.templates.Get(r.Context(), key, language)
+	if err != nil {
+		h.respondEmailTemplateError(w, r, err, "Failed to fetch email template")
+		return
+	}
+	respondJSON(w, http.StatusOK, t)
+}
+
+// UpdateEmailTemplate godoc
+// @Summary Customize an email template
+// @Description Replaces the organization's template for an email in a language. Subjects and bodies are Go templates that can use the email's variables, such as {{.InviterName}}; variables are escaped in the HTML body. Requires emailtemplate:manage.
+// @Tags Email Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Email" Enums(invitation, meeting_response)
+// @Param language path string true "Language" Enums(en, es)
+// @Param request body models.UpdateEmailTemplateRequest true "Template"
+// @Success 200 {object} models.EmailTemplate "Email template"
+// @Failure 400 {object} map[string]interface{} "Invalid template"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Unknown email or language"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/email-templates/{key}/{language} [put]
+func (h *EmailTemplateHandlers) UpdateEmailTemplate(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionEmailTemplateManage)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.UpdateEmailTemplateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if !validateRequest(w, &req) {
+		return
+	}
+
+	key, language := templateParams(r)
+	t, err := h.templates.Save(r.Context(), key, language, &req, currentUser.ID)
+	if err != nil {
+		h.respondEmailTemplateError(w, r, err, "Failed to save email template")
+		return
+	}
+
+	h.logger.Info("Email template customized", "org_id", currentUser.OrgID, "template", key, "language", language, "updated_by", currentUser.ID)
+	respondJSON(w, http.StatusOK, t)
+}
+
+// ResetEmailTemplate godoc
+// @Summary Reset an email template
+// @Description Removes the organization's customized template for an email in a language, so the built-in one is sent again. Returns the built-in template. Requires emailtemplate:manage.
+// @Tags Email Templates
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Email" Enums(invitation, meeting_response)
+// @Param language path string true "Language" Enums(en, es)
+// @Success 200 {object} models.EmailTemplate "Built-in email template"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Unknown email or language"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/email-templates/{key}/{language} [delete]
+func (h *EmailTemplateHandlers) ResetEmailTemplate(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionEmailTemplateManage)
+	if currentUser == nil {
+		return
+	}
+
+	key, language := templateParams(r)
+	t, err := h.templates.Reset(r.Context(), key, language)
+	if err != nil {
+		h.respondEmailTemplateError(w, r, err, "Failed to reset email template")
+		return
+	}
+
+	h.logger.Info("Email template reset", "org_id", currentUser.OrgID, "template", key, "language", language, "updated_by", currentUser.ID)
+	respondJSON(w, http.StatusOK, t)
+}
+
+// PreviewEmailTemplate godoc
+// @Summary Preview an email
+// @Description Renders an email in a language with its example variables, overridden by any given variables. Pass a template to preview unsaved changes; otherwise the template in use is rendered. Nothing is sent or saved. Requires emailtemplate:manage.
+// @Tags Email Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Email" Enums(invitation, meeting_response)
+// @Param language path string true "Language" Enums(en, es)
+// @Param request body models.PreviewEmailTemplateRequest false "Template and variables"
+// @Success 200 {object} models.RenderedEmail "Rendered email"
+// @Failure 400 {object} map[string]interface{} "Invalid template"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Unknown email or language"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/email-templates/{key}/{language}/preview [post]
+func (h *EmailTemplateHandlers) PreviewEmailTemplate(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionEmailTemplateManage) == nil {
+		return
+	}
+
+	var req models.PreviewEmailTemplateRequest
+	if r.ContentLength != 0 && !decodeJSON(w, r, &req) {
+		return
+	}
+	if !validateRequest(w, &req) {
+		return
+	}
+
+	key, language := templateParams(r)
+	rendered, err := h.templates.Preview(r.Context(), key, language, &req)
+	if err != nil {
+		h.respondEmailTemplateError(w, r, err, "Failed to preview email")
+		return
+	}
+	respondJSON(w, http.StatusOK, rendered)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestEmailTemplateHandlers_UpdateEmailTemplate(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}
+	valid := `{"subject":"Join {{.InviterName}}","html_body":"<p>{{.InviteLink}}</p>","text_body":"{{.InviteLink}}"}`
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		key            string
+		language       string
+		body           string
+		expectedStatus int
+	}{
+		{"customizes template", admin, "invitation", "es", valid, http.StatusOK},
+		{"rejects template syntax errors", admin, "invitation", "en", `{"subject":"{{.InviterName","html_body":"<p></p>","text_body":"text"}`, http.StatusBadRequest},
+		{"rejects missing bodies", admin, "invitation", "en", `{"subject":"Hi"}`, http.StatusBadRequest},
+		{"unknown email", admin, "carrier_pigeon", "en", valid, http.StatusNotFound},
+		{"unsupported language", admin, "invitation", "fr", valid, http.StatusNotFound},
+		{"requires emailtemplate:manage", &models.User{ID: 2, Role: models.RoleSupervisor, OrgID: 1}, "invitation", "en", valid, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockEmailTemplateRepository()
+			h := NewEmailTemplateHandlers(services.NewEmailTemplateService(repo))
+
+			req := httptest.NewRequest(http.MethodPut, "/api/admin/email-templates/"+tt.key+"/"+tt.language, strings.NewReader(tt.body))
+			ctx := chiCtxWithParams(ctxWithUser(tt.currentUser), map[string]string{"key": tt.key, "language": tt.language})
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			h.UpdateEmailTemplate(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				if len(repo.Templates) != 0 {
+					t.Errorf("stored %d templates, want none", len(repo.Templates))
+				}
+				return
+			}
+			var saved models.EmailTemplate
+			if err := json.Unmarshal(rr.Body.Bytes(), &saved); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !saved.Customized || saved.Language != "es" || saved.UpdatedByID == nil || *saved.UpdatedByID != admin.ID {
+				t.Errorf("unexpected template: %+v", saved)
+			}
+		})
+	}
+}
+
+func TestEmailTemplateHandlers_PreviewEmailTemplate(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}
+	h := NewEmailTemplateHandlers(services.NewEmailTemplateService(mocks.NewMockEmailTemplateRepository()))
+
+	body := `{"variables":{"InviterName":"<script>alert(1)</script>"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/email-templates/invitation/en/preview", strings.NewReader(body))
+	req = req.WithContext(chiCtxWithParams(ctxWithUser(admin), map[string]string{"key": "invitation", "language": "en"}))
+	rr := httptest.NewRecorder()
+	h.PreviewEmailTemplate(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var rendered models.RenderedEmail
+	if err := json.Unmarshal(rr.Body.Bytes(), &rendered); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if strings.Contains(rendered.HTML, "<script>") || !strings.Contains(rendered.Text, "<script>alert(1)</script>") {
+		t.Errorf("unexpected preview: %+v", rendered)
+	}
+}
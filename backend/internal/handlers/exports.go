@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// ExportHandlers handles background export jobs
+type ExportHandlers struct {
+	exportService *services.ExportService
+	logger        *logger.Logger
+}
+
+// NewExportHandlers creates a new export handlers instance
+func NewExportHandlers(exportService *services.ExportService) *ExportHandlers {
+	return &ExportHandlers{
+		exportService: exportService,
+		logger:        logger.Default().WithComponent("export-handlers"),
+	}
+}
+
+// CreateExport queues an export of the data the current user can see.
+// The response is the pending job; poll GetExport until it completes to get a download URL.
+func (h *ExportHandlers) CreateExport(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.CreateExportJobRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job, err := h.exportService.Create(r.Context(), currentUser, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrExportStorageNotConfigured) {
+			respondError(w, http.StatusServiceUnavailable, "Exports are not available")
+			return
+		}
+		h.logger.LogError(r.Context(), "Failed to create export job", err, "user_id", currentUser.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to create export")
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/api/exports/%d", job.ID))
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// ListExports returns the current user's recent export jobs
+func (h *ExportHandlers) ListExports(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	jobs, err := h.exportService.List(r.Context(), currentUser)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch exports")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, jobs)
+}
+
+// GetExport returns the status of one of the current user's export jobs.
+// Completed jobs include a signed download_url that expires after a few minutes; poll again for a new one.
+func (h *ExportHandlers) GetExport(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid export ID")
+		return
+	}
+
+	job, err := h.exportService.Get(r.Context(), currentUser, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrExportJobNotFound):
+			respondError(w, http.StatusNotFound, "Export not found")
+		case errors.Is(err, services.ErrExportStorageNotConfigured):
+			respondError(w, http.StatusServiceUnavailable, "Exports are not available")
+		default:
+			h.logger.LogError(r.Context(), "Failed to get export job", err, "export_id", id)
+			respondError(w, http.StatusInternalServerError, "Failed to fetch export")
+		}
+		return
+	}
+
+	if !job.IsFinished() {
+		// Hint for pollers; the worker usually picks jobs up within a few seconds
+		w.Header().Set("Retry-After", "2")
+	}
+	respondJSON(w, http.StatusOK, job)
+}
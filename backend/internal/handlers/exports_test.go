@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+	"github.com/smith-dallin/manager-dashboard/internal/storage"
+)
+
+// SignedURL lets fakeStorage hand out private download links
+func (s *fakeStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.GetURL(key) + "?signature=test", nil
+}
+
+func setupExportTest(store storage.Storage) (*ExportHandlers, *mocks.MockExportJobRepository) {
+	jobRepo := mocks.NewMockExportJobRepository()
+	userRepo := mocks.NewMockUserRepository()
+	userService := services.NewUserService(userRepo, mocks.NewMockSquadRepository())
+	service := services.NewExportService(jobRepo, userService, mocks.NewMockTimeOffRepository(), userRepo, store, "acme", time.Hour, time.Minute)
+	return NewExportHandlers(service), jobRepo
+}
+
+func TestExportHandlers_CreateExport(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		body           string
+		noStorage      bool
+		expectedStatus int
+	}{
+		{
+			name:           "unauthenticated",
+			body:           `{"type":"employees"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unknown type",
+			currentUser:    &models.User{ID: 1, Role: models.RoleAdmin},
+			body:           `{"type":"payroll"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "filter that doesn't apply to the type",
+			currentUser:    &models.User{ID: 1, Role: models.RoleAdmin},
+			body:           `{"type":"employees","filters":{"status":"approved"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "storage without signed URLs",
+			currentUser:    &models.User{ID: 1, Role: models.RoleAdmin},
+			body:           `{"type":"employees"}`,
+			noStorage:      true,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "queues the job",
+			currentUser:    &models.User{ID: 1, Role: models.RoleAdmin},
+			body:           `{"type":"time_off","format":"json","filters":{"from":"2026-01-01","to":"2026-03-31"}}`,
+			expectedStatus: http.StatusAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var store storage.Storage = newFakeStorage()
+			if tt.noStorage {
+				store = nil
+			}
+			h, jobRepo := setupExportTest(store)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/exports", strings.NewReader(tt.body))
+			if tt.currentUser != nil {
+				req = req.WithContext(ctxWithUserFrom(req.Context(), tt.currentUser))
+			}
+
+			rr := httptest.NewRecorder()
+			h.CreateExport(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Code != http.StatusAccepted {
+				return
+			}
+
+			var job models.ExportJob
+			if err := json.Unmarshal(rr.Body.Bytes(), &job); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if job.Status != models.ExportJobStatusPending || job.Type != models.ExportTypeTimeOff || job.Format != models.ExportFormatJSON {
+				t.Errorf("job = %+v", job)
+			}
+			if rr.Header().Get("Location") != "/api/exports/1" {
+				t.Errorf("Location = %q", rr.Header().Get("Location"))
+			}
+			if len(jobRepo.Jobs) != 1 {
+				t.Errorf("stored %d jobs, want 1", len(jobRepo.Jobs))
+			}
+		})
+	}
+}
+
+func TestExportHandlers_GetExport(t *testing.T) {
+	h, jobRepo := setupExportTest(newFakeStorage())
+	key := "tenants/acme/users/1/exports/2-1.csv"
+	future := time.Now().Add(time.Hour)
+	jobRepo.AddJob(&models.ExportJob{ID: 1, RequestedByID: 1, Status: models.ExportJobStatusRunning})
+	jobRepo.AddJob(&models.ExportJob{ID: 2, RequestedByID: 1, Status: models.ExportJobStatusCompleted, StorageKey: &key, ExpiresAt: &future})
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		id             string
+		expectedStatus int
+		wantURL        bool
+		wantRetry      bool
+	}{
+		{
+			name:           "running job asks the client to poll again",
+			currentUser:    &models.User{ID: 1},
+			id:             "1",
+			expectedStatus: http.StatusOK,
+			wantRetry:      true,
+		},
+		{
+			name:           "completed job includes a signed download URL",
+			currentUser:    &models.User{ID: 1},
+			id:             "2",
+			expectedStatus: http.StatusOK,
+			wantURL:        true,
+		},
+		{
+			name:           "another user's job is not found",
+			currentUser:    &models.User{ID: 2, Role: models.RoleAdmin},
+			id:             "2",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid id",
+			currentUser:    &models.User{ID: 1},
+			id:             "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/exports/"+tt.id, nil)
+			ctx := ctxWithUserFrom(req.Context(), tt.currentUser)
+			req = req.WithContext(chiCtxWithID(ctx, "id", tt.id))
+
+			rr := httptest.NewRecorder()
+			h.GetExport(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if (rr.Header().Get("Retry-After") != "") != tt.wantRetry {
+				t.Errorf("Retry-After = %q, want set: %v", rr.Header().Get("Retry-After"), tt.wantRetry)
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			var job models.ExportJob
+			if err := json.Unmarshal(rr.Body.Bytes(), &job); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if tt.wantURL != (job.DownloadURL != nil) {
+				t.Errorf("DownloadURL = %v, want set: %v", job.DownloadURL, tt.wantURL)
+			}
+			if tt.wantURL && !strings.Contains(*job.DownloadURL, "signature=") {
+				t.Errorf("DownloadURL = %s, want a signed URL", *job.DownloadURL)
+			}
+		})
+	}
+}
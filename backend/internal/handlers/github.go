@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/github"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/oauth"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// maxConcurrentGitHubRequests limits how many employees' pull requests are searched at once;
+// GitHub's search API allows 30 requests a minute per user
+const maxConcurrentGitHubRequests = 3
+
+// GitHubHandlers serves the organization-wide GitHub App connection and pull request views
+type GitHubHandlers struct {
+	userRepo      repository.UserRepository
+	orgGitHubRepo repository.OrgGitHubRepository
+	timeOffRepo   repository.TimeOffRepository
+	oauthService  *github.OAuthService
+	stateStore    oauth.StateStore
+	stateBinder   OAuthStateBinder
+	frontendURL   string
+	apiURL        string
+	logger        *logger.Logger
+}
+
+// NewGitHubHandlers creates GitHub handlers. oauthService is nil when the GitHub App isn't configured.
+func NewGitHubHandlers(userRepo repository.UserRepository, orgGitHubRepo repository.OrgGitHubRepository, timeOffRepo repository.TimeOffRepository, oauthService *github.OAuthService, stateStore oauth.StateStore, frontendURL, apiURL string, log *logger.Logger) *GitHubHandlers {
+	return &GitHubHandlers{
+		userRepo:      userRepo,
+		orgGitHubRepo: orgGitHubRepo,
+		timeOffRepo:   timeOffRepo,
+		oauthService:  oauthService,
+		stateStore:    stateStore,
+		frontendURL:   frontendURL,
+		apiURL:        apiURL,
+		logger:        log.WithComponent("github_handlers"),
+	}
+}
+
+// WithOAuthStateBinding ties each OAuth flow to the browser that started it, rejecting callbacks
+// from any other
+func (h *GitHubHandlers) WithOAuthStateBinding(binder OAuthStateBinder) *GitHubHandlers {
+	h.stateBinder = binder
+	return h
+}
+
+// getGitHubClient returns a client for the org-wide GitHub connection along with its settings,
+// refreshing the access token first if it has expired
+func (h *GitHubHandlers) getGitHubClient(ctx context.Context) (*github.Client, *models.OrgGitHubSettings, error) {
+	settings, err := h.orgGitHubRepo.Get(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get GitHub settings: %w", err)
+	}
+	if settings == nil {
+		return nil, nil, fmt.Errorf("github is not configured for this organization")
+	}
+
+	accessToken := settings.OAuthAccessToken
+	if settings.IsTokenExpired() {
+		if h.oauthService == nil || settings.OAuthRefreshToken == "" {
+			return nil, nil, fmt.Errorf("cannot refresh expired GitHub token")
+		}
+
+		tokenResp, err := h.oauthService.RefreshAccessToken(settings.OAuthRefreshToken)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to refresh token: %w", err)
+		}
+
+		// GitHub rotates refresh tokens, so the new one must be saved
+		if err := h.orgGitHubRepo.UpdateTokens(ctx, tokenResp.AccessToken, tokenResp.RefreshToken, github.CalculateExpiry(tokenResp.ExpiresIn)); err != nil {
+			h.logger.WithContext(ctx).Warn("Failed to save refreshed GitHub tokens", "error", err)
+		}
+		accessToken = tokenResp.AccessToken
+	}
+
+	client := github.NewClient(accessToken)
+	if h.apiURL != "" {
+		client.WithBaseURL(h.apiURL)
+	}
+	return client, settings, nil
+}
+
+// GetGitHubSettings returns the organization-wide GitHub connection status
+// Available to all authenticated users to check connection status
+func (h *GitHubHandlers) GetGitHubSettings(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	settings, err := h.orgGitHubRepo.Get(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get GitHub settings")
+		return
+	}
+
+	response := map[string]interface{}{
+		"oauth_enabled":  h.oauthService != nil,
+		"org_configured": settings != nil,
+		"github_login":   currentUser.GitHubLogin,
+		"can_configure":  authz.Can(currentUser, authz.ActionIntegrationManage, nil),
+	}
+	if settings != nil {
+		response["account_login"] = settings.AccountLogin
+		response["org_login"] = settings.OrgLogin
+		response["configured_by_id"] = settings.ConfiguredByID
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// UpdateGitHubSettings sets the GitHub organization pull requests are searched in (admin only)
+func (h *GitHubHandlers) UpdateGitHubSettings(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	var req models.UpdateGitHubSettingsRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	updated, err := h.orgGitHubRepo.UpdateOrgLogin(r.Context(), req.OrgLogin)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to update GitHub settings", err)
+		respondError(w, http.StatusInternalServerError, "Failed to update GitHub settings")
+		return
+	}
+	if !updated {
+		respondError(w, http.StatusNotFound, "GitHub is not connected")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"org_login": req.OrgLogin,
+	})
+}
+
+// DisconnectGitHub removes the organization-wide GitHub connection (admin only)
+func (h *GitHubHandlers) DisconnectGitHub(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	if err := h.orgGitHubRepo.Delete(r.Context()); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to disconnect GitHub")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetOAuthAuthorizeURL returns the URL to redirect the user to for GitHub App authorization
+// Only admins can configure the organization-wide GitHub connection
+func (h *GitHubHandlers) GetOAuthAuthorizeURL(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionIntegrationManage)
+	if currentUser == nil {
+		return
+	}
+
+	if h.oauthService == nil {
+		respondError(w, http.StatusServiceUnavailable, "GitHub OAuth is not configured")
+		return
+	}
+
+	state, err := h.stateStore.Create(r.Context(), currentUser.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to generate state")
+		return
+	}
+	if h.stateBinder != nil {
+		h.stateBinder.BindState(w, state)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"authorization_url": h.oauthService.GetAuthorizationURL(state),
+	})
+}
+
+// HandleOAuthCallback handles the OAuth callback from GitHub and saves the tokens as the
+// organization-wide GitHub connection
+func (h *GitHubHandlers) HandleOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	redirectURL := h.frontendURL + "/settings"
+
+	redirectWithError := func(errMsg string) {
+		http.Redirect(w, r, redirectURL+"?github_error="+errMsg, http.StatusFound)
+	}
+
+	params, errMsg := validateOAuthCallback(r.Context(), r, h.stateStore, h.stateBinder)
+	if errMsg != "" {
+		redirectWithError(errMsg)
+		return
+	}
+
+	if h.oauthService == nil {
+		redirectWithError("oauth_not_configured")
+		return
+	}
+
+	// GitHub calls back without the admin's token, so connect GitHub for the organization of the
+	// admin who started the flow
+	ctx := r.Context()
+	admin, err := h.userRepo.GetByID(ctx, params.userID)
+	if err != nil || admin == nil {
+		redirectWithError("state_validation_failed")
+		return
+	}
+	ctx = tenant.WithOrgID(ctx, admin.OrgID)
+
+	tokenResp, err := h.oauthService.ExchangeCode(params.code)
+	if err != nil {
+		h.logger.LogError(ctx, "GitHub token exchange failed", err)
+		redirectWithError("token_exchange_failed")
+		return
+	}
+
+	client := github.NewClient(tokenResp.AccessToken)
+	if h.apiURL != "" {
+		client.WithBaseURL(h.apiURL)
+	}
+	login, err := client.GetAuthenticatedLogin()
+	if err != nil {
+		h.logger.LogError(ctx, "Failed to get GitHub account", err)
+		redirectWithError("account_failed")
+		return
+	}
+
+	settings := &models.OrgGitHubSettings{
+		OAuthAccessToken:    tokenResp.AccessToken,
+		OAuthRefreshToken:   tokenResp.RefreshToken,
+		OAuthTokenExpiresAt: github.CalculateExpiry(tokenResp.ExpiresIn),
+		AccountLogin:        login,
+		ConfiguredByID:      &params.userID,
+	}
+	if err := h.orgGitHubRepo.Save(ctx, settings); err != nil {
+		h.logger.LogError(ctx, "Failed to save GitHub settings", err)
+		redirectWithError("save_failed")
+		return
+	}
+
+	http.Redirect(w, r, redirectURL+"?github_connected=true", http.StatusFound)
+}
+
+// UpdateUserGitHubMapping links an employee to their GitHub account (admin only)
+func (h *GitHubHandlers) UpdateUserGitHubMapping(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "userId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req models.UpdateUserGitHubMappingRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if err := h.userRepo.UpdateGitHubLogin(r.Context(), userID, req.GitHubLogin); err != nil {
+		h.logger.LogError(r.Context(), "Failed to update GitHub mapping", err, "user_id", userID)
+		respondError(w, http.StatusInternalServerError, "Failed to update GitHub mapping")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":      true,
+		"github_login": req.GitHubLogin,
+	})
+}
+
+// PullRequestWithRole is an open pull request and how its employee is involved in it
+type PullRequestWithRole struct {
+	models.GitHubPullRequest
+	Role models.GitHubPullRequestRole `json:"role"`
+}
+
+// TeamPullRequest is an open pull request with employee info, mirroring TeamTask.
+// OnTimeOff flags employees who are out today, so their reviews and PRs may stall.
+type TeamPullRequest struct {
+	PullRequestWithRole
+	Employee  TeamTaskEmployee `json:"employee"`
+	OnTimeOff bool             `json:"on_time_off"`
+}
+
+// withRoles flattens an account's GitHub work into pull requests tagged with their role
+func withRoles(work services.GitHubWork, maxPerRole int) []PullRequestWithRole {
+	pullRequests := []PullRequestWithRole{}
+	for _, pr := range work.Assigned[:min(len(work.Assigned), maxPerRole)] {
+		pullRequests = append(pullRequests, PullRequestWithRole{GitHubPullRequest: pr, Role: models.GitHubPullRequestAssignee})
+	}
+	for _, pr := range work.ReviewRequested[:min(len(work.ReviewRequested), maxPerRole)] {
+		pullRequests = append(pullRequests, PullRequestWithRole{GitHubPullRequest: pr, Role: models.GitHubPullRequestReviewer})
+	}
+	return pullRequests
+}
+
+// parseMaxPerRole reads ?max_per_user, the most pull requests returned per role for each employee
+func parseMaxPerRole(r *http.Request) int {
+	maxPerRole := 20
+	if maxStr := r.URL.Query().Get("max_per_user"); maxStr != "" {
+		if m, err := strconv.Atoi(maxStr); err == nil && m > 0 && m <= 50 {
+			maxPerRole = m
+		}
+	}
+	return maxPerRole
+}
+
+// GetMyPullRequests returns the open pull requests the current user is assigned to or asked to review
+// Uses the org-wide GitHub connection and the user's github_login mapping
+func (h *GitHubHandlers) GetMyPullRequests(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireGitHubAccess(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	if currentUser.GitHubLogin == nil || *currentUser.GitHubLogin == "" {
+		respondJSON(w, http.StatusOK, []PullRequestWithRole{})
+		return
+	}
+
+	client, settings, err := h.getGitHubClient(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to connect to GitHub")
+		return
+	}
+
+	login := *currentUser.GitHubLogin
+	maxPerRole := parseMaxPerRole(r)
+	work, ok := services.FetchGitHubWork(r.Context(), client, []string{login}, orgLogin(settings), maxPerRole, 1)[login]
+	if !ok {
+		respondError(w, http.StatusBadGateway, "Failed to fetch pull requests from GitHub")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, withRoles(work, maxPerRole))
+}
+
+// GetTeamPullRequests returns open pull requests for all of a supervisor's direct reports
+// Only supervisors and admins can access this endpoint
+func (h *GitHubHandlers) GetTeamPullRequests(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireGitHubAccess(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	directReports, err := teamMembers(r.Context(), h.userRepo, currentUser)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to fetch direct reports", err, "user_id", currentUser.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch direct reports")
+		return
+	}
+
+	var usersWithGitHub []models.User
+	for _, user := range directReports {
+		if user.GitHubLogin != nil && *user.GitHubLogin != "" {
+			usersWithGitHub = append(usersWithGitHub, user)
+		}
+	}
+
+	if len(usersWithGitHub) == 0 {
+		respondJSON(w, http.StatusOK, []TeamPullRequest{})
+		return
+	}
+
+	client, settings, err := h.getGitHubClient(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to connect to GitHub")
+		return
+	}
+
+	logins := make([]string, len(usersWithGitHub))
+	for i, u := range usersWithGitHub {
+		logins[i] = *u.GitHubLogin
+	}
+	maxPerRole := parseMaxPerRole(r)
+	workByLogin := services.FetchGitHubWork(r.Context(), client, logins, orgLogin(settings), maxPerRole, maxConcurrentGitHubRequests)
+
+	teamPullRequests := []TeamPullRequest{}
+	for _, user := range usersWithGitHub {
+		work, ok := workByLogin[*user.GitHubLogin]
+		if !ok {
+			// Already logged by FetchGitHubWork
+			continue
+		}
+
+		onTimeOff := false
+		if h.timeOffRepo != nil {
+			timeOff, err := h.timeOffRepo.GetApprovedFutureTimeOffByUser(r.Context(), user.ID)
+			if err != nil {
+				h.logger.WithContext(r.Context()).Warn("Failed to fetch time off for user", "user_id", user.ID, "error", err)
+			}
+			onTimeOff = services.OnTimeOffToday(timeOff, time.Now(), user.Location())
+		}
+
+		employee := TeamTaskEmployee{
+			ID:        user.ID,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Email:     user.Email,
+			AvatarURL: user.AvatarURL,
+		}
+		for _, pr := range withRoles(work, maxPerRole) {
+			teamPullRequests = append(teamPullRequests, TeamPullRequest{
+				PullRequestWithRole: pr,
+				Employee:            employee,
+				OnTimeOff:           onTimeOff,
+			})
+		}
+	}
+
+	respondJSON(w, http.StatusOK, teamPullRequests)
+}
+
+// orgLogin returns the organization searches are limited to, or "" for none
+func orgLogin(settings *models.OrgGitHubSettings) string {
+	if settings.OrgLogin == nil {
+		return ""
+	}
+	return *settings.OrgLogin
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/oauth"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+// newGitHubSearchServer answers pull request searches with one result per query,
+// titled after the query's assignee or review-requested qualifier
+func newGitHubSearchServer(t *testing.T, queries *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/issues" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query().Get("q")
+		*queries = append(*queries, q)
+		var title string
+		for _, field := range strings.Fields(q) {
+			if strings.HasPrefix(field, "assignee:") || strings.HasPrefix(field, "review-requested:") {
+				title = field
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []map[string]interface{}{{
+				"number":         7,
+				"title":          title,
+				"html_url":       "https://github.com/acme/web/pull/7",
+				"repository_url": "https://api.github.com/repos/acme/web",
+				"user":           map[string]string{"login": "octocat"},
+				"labels":         []map[string]string{{"name": "bug"}},
+				"created_at":     "2024-03-01T10:00:00Z",
+				"updated_at":     "2024-03-02T10:00:00Z",
+			}},
+		})
+	}))
+}
+
+func TestGitHubHandlers_GetTeamPullRequests(t *testing.T) {
+	var queries []string
+	server := newGitHubSearchServer(t, &queries)
+	defer server.Close()
+
+	supervisorID := int64(1)
+	mapped, away := "ada-l", "grace-h"
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.Users[2] = &models.User{ID: 2, FirstName: "Ada", SupervisorID: &supervisorID, IsActive: true, GitHubLogin: &mapped}
+	userRepo.Users[3] = &models.User{ID: 3, FirstName: "Grace", SupervisorID: &supervisorID, IsActive: true, GitHubLogin: &away}
+	userRepo.Users[4] = &models.User{ID: 4, FirstName: "Alan", SupervisorID: &supervisorID, IsActive: true}
+
+	now := time.Now()
+	timeOffRepo := mocks.NewMockTimeOffRepository()
+	timeOffRepo.GetApprovedFutureTimeOffByUserFunc = func(ctx context.Context, userID int64) ([]models.TimeOffRequest, error) {
+		if userID != 3 {
+			return nil, nil
+		}
+		return []models.TimeOffRequest{{UserID: 3, Status: models.TimeOffStatusApproved, StartDate: now.AddDate(0, 0, -1), EndDate: now.AddDate(0, 0, 1)}}, nil
+	}
+
+	org := "acme"
+	gitHubRepo := mocks.NewMockOrgGitHubRepository()
+	gitHubRepo.Settings = &models.OrgGitHubSettings{OAuthAccessToken: "token", AccountLogin: "admin", OrgLogin: &org}
+
+	h := NewGitHubHandlers(userRepo, gitHubRepo, timeOffRepo, nil, nil, "", server.URL, logger.Default())
+
+	supervisor := &models.User{ID: supervisorID, Role: models.RoleSupervisor}
+	req := httptest.NewRequest(http.MethodGet, "/api/github/pulls/team", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), supervisor))
+	rr := httptest.NewRecorder()
+
+	h.GetTeamPullRequests(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var pullRequests []TeamPullRequest
+	if err := json.NewDecoder(rr.Body).Decode(&pullRequests); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(pullRequests) != 4 {
+		t.Fatalf("got %d pull requests, want assigned and review requests for two mapped reports", len(pullRequests))
+	}
+	for _, pr := range pullRequests {
+		wantTitle := "assignee:" + *userRepo.Users[pr.Employee.ID].GitHubLogin
+		if pr.Role == models.GitHubPullRequestReviewer {
+			wantTitle = "review-requested:" + *userRepo.Users[pr.Employee.ID].GitHubLogin
+		}
+		if pr.Title != wantTitle {
+			t.Errorf("%s pull request for %s has title %q, want %q", pr.Role, pr.Employee.FirstName, pr.Title, wantTitle)
+		}
+		if pr.Repository != "acme/web" || len(pr.Labels) != 1 {
+			t.Errorf("pull request = %+v, want repository acme/web with its label", pr.GitHubPullRequest)
+		}
+		if pr.OnTimeOff != (pr.Employee.ID == 3) {
+			t.Errorf("%s on_time_off = %v", pr.Employee.FirstName, pr.OnTimeOff)
+		}
+	}
+
+	for _, q := range queries {
+		if !strings.Contains(q, "org:acme") {
+			t.Errorf("query %q isn't limited to the org", q)
+		}
+	}
+}
+
+func TestGitHubHandlers_GetMyPullRequests(t *testing.T) {
+	var queries []string
+	server := newGitHubSearchServer(t, &queries)
+	defer server.Close()
+
+	login := "ada-l"
+	gitHubRepo := mocks.NewMockOrgGitHubRepository()
+	gitHubRepo.Settings = &models.OrgGitHubSettings{OAuthAccessToken: "token", AccountLogin: "admin"}
+	h := NewGitHubHandlers(mocks.NewMockUserRepository(), gitHubRepo, nil, nil, nil, "", server.URL, logger.Default())
+
+	tests := []struct {
+		name           string
+		user           *models.User
+		expectedStatus int
+		wantCount      int
+	}{
+		{"mapped supervisor", &models.User{ID: 1, Role: models.RoleSupervisor, GitHubLogin: &login}, http.StatusOK, 2},
+		{"unmapped supervisor", &models.User{ID: 2, Role: models.RoleSupervisor}, http.StatusOK, 0},
+		{"employee", &models.User{ID: 3, Role: models.RoleEmployee, GitHubLogin: &login}, http.StatusForbidden, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/github/pulls", nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), tt.user))
+			rr := httptest.NewRecorder()
+
+			h.GetMyPullRequests(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+			var pullRequests []PullRequestWithRole
+			if err := json.NewDecoder(rr.Body).Decode(&pullRequests); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(pullRequests) != tt.wantCount {
+				t.Errorf("got %d pull requests, want %d", len(pullRequests), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestGitHubHandlers_UpdateUserGitHubMapping(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	login := func(s string) *string { return &s }
+
+	tests := []struct {
+		name           string
+		user           *models.User
+		userID         string
+		body           string
+		expectedStatus int
+		wantLogin      *string
+	}{
+		{"links an account", admin, "2", `{"github_login":" @ada-l "}`, http.StatusOK, login("ada-l")},
+		{"clears the link", admin, "2", `{"github_login":""}`, http.StatusOK, nil},
+		{"invalid login", admin, "2", `{"github_login":"not valid"}`, http.StatusBadRequest, login("old-login")},
+		{"unknown user", admin, "99", `{"github_login":"ada-l"}`, http.StatusNotFound, login("old-login")},
+		{"not an admin", &models.User{ID: 3, Role: models.RoleSupervisor}, "2", `{"github_login":"ada-l"}`, http.StatusForbidden, login("old-login")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := "old-login"
+			userRepo := mocks.NewMockUserRepository()
+			userRepo.Users[2] = &models.User{ID: 2, GitHubLogin: &old}
+			h := NewGitHubHandlers(userRepo, mocks.NewMockOrgGitHubRepository(), nil, nil, nil, "", "", logger.Default())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/github/users/"+tt.userID+"/mapping", strings.NewReader(tt.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("userId", tt.userID)
+			req = req.WithContext(ctxWithUserFrom(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), tt.user))
+			rr := httptest.NewRecorder()
+
+			h.UpdateUserGitHubMapping(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			got := userRepo.Users[2].GitHubLogin
+			if (got == nil) != (tt.wantLogin == nil) || (got != nil && *got != *tt.wantLogin) {
+				t.Errorf("github_login = %v, want %v", got, tt.wantLogin)
+			}
+		})
+	}
+}
+
+func TestGitHubHandlers_UpdateGitHubSettings_NotConnected(t *testing.T) {
+	h := NewGitHubHandlers(mocks.NewMockUserRepository(), mocks.NewMockOrgGitHubRepository(), nil, nil, nil, "", "", logger.Default())
+
+	req := httptest.NewRequest(http.MethodPut, "/api/github/settings", strings.NewReader(`{"org_login":"acme"}`))
+	req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleAdmin}))
+	rr := httptest.NewRecorder()
+
+	h.UpdateGitHubSettings(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestGitHubHandlers_HandleOAuthCallback_InvalidState(t *testing.T) {
+	gitHubRepo := mocks.NewMockOrgGitHubRepository()
+	h := NewGitHubHandlers(mocks.NewMockUserRepository(), gitHubRepo, nil, nil, oauth.NewMemoryStateStore(time.Minute), "https://dash.example.com", "", logger.Default())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/github/oauth/callback?code=abc&state=forged", nil)
+	rr := httptest.NewRecorder()
+
+	h.HandleOAuthCallback(rr, req)
+
+	if rr.Code != http.StatusFound {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusFound)
+	}
+	if got := rr.Header().Get("Location"); got != "https://dash.example.com/settings?github_error=invalid_state" {
+		t.Errorf("redirect = %q", got)
+	}
+	if gitHubRepo.Settings != nil {
+		t.Error("settings were saved for a forged state")
+	}
+}
+
+func TestGitHubHandlers_HandleOAuthCallback_StateFromAnotherBrowser(t *testing.T) {
+	gitHubRepo := mocks.NewMockOrgGitHubRepository()
+	stateStore := oauth.NewMemoryStateStore(time.Minute)
+	csrf := middleware.NewCSRF(middleware.CSRFConfig{Secret: []byte("test-secret"), CookieName: "api_csrf"})
+	h := NewGitHubHandlers(mocks.NewMockUserRepository(), gitHubRepo, nil, nil, stateStore, "https://dash.example.com", "", logger.Default()).
+		WithOAuthStateBinding(csrf)
+
+	state, _ := stateStore.Create(context.Background(), 1)
+	req := httptest.NewRequest(http.MethodGet, "/api/github/oauth/callback?code=abc&state="+state, nil)
+	rr := httptest.NewRecorder()
+
+	h.HandleOAuthCallback(rr, req)
+
+	if got := rr.Header().Get("Location"); got != "https://dash.example.com/settings?github_error=state_mismatch" {
+		t.Errorf("redirect = %q", got)
+	}
+	if _, err := stateStore.Validate(context.Background(), state); err != nil {
+		t.Error("a forged callback used up the admin's state")
+	}
+}
@@ -0,0 +1,501 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// GoalHandlers handles goals, their key results, and progress rollups
+type GoalHandlers struct {
+	goalRepo  repository.GoalRepository
+	userRepo  repository.UserRepository
+	squadRepo repository.SquadRepository
+	logger    *logger.Logger
+}
+
+// NewGoalHandlers creates a new goal handlers instance
+func NewGoalHandlers(goalRepo repository.GoalRepository, userRepo repository.UserRepository, squadRepo repository.SquadRepository) *GoalHandlers {
+	return &GoalHandlers{
+		goalRepo:  goalRepo,
+		userRepo:  userRepo,
+		squadRepo: squadRepo,
+		logger:    logger.Default().WithComponent("goal-handlers"),
+	}
+}
+
+// GetGoals godoc
+// @Summary List goals
+// @Description Returns goals with their key results and progress, optionally filtered by quarter and owner
+// @Tags Goals
+// @Produce json
+// @Security BearerAuth
+// @Param quarter query string false "Quarter, such as 2025-Q3"
+// @Param owner_type query string false "user, squad, or department"
+// @Param owner_id query int false "User or squad ID"
+// @Param owner_department query string false "Department name"
+// @Success 200 {array} models.Goal "Goals"
+// @Failure 400 {object} map[string]interface{} "Invalid filter"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /goals [get]
+func (h *GoalHandlers) GetGoals(w http.ResponseWriter, r *http.Request) {
+	if requireAuth(w, r) == nil {
+		return
+	}
+
+	q := r.URL.Query()
+	var filter models.GoalFilter
+	if filter.Quarter = q.Get("quarter"); filter.Quarter != "" {
+		if err := models.ValidateQuarter(filter.Quarter); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	switch ownerType := models.GoalOwnerType(q.Get("owner_type")); ownerType {
+	case "":
+	case models.GoalOwnerUser, models.GoalOwnerSquad:
+		ownerID, err := strconv.ParseInt(q.Get("owner_id"), 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "owner_id is required for user and squad owners")
+			return
+		}
+		if ownerType == models.GoalOwnerUser {
+			filter.UserIDs = []int64{ownerID}
+		} else {
+			filter.SquadIDs = []int64{ownerID}
+		}
+	case models.GoalOwnerDepartment:
+		dept := q.Get("owner_department")
+		if dept == "" {
+			respondError(w, http.StatusBadRequest, "owner_department is required for department owners")
+			return
+		}
+		filter.Departments = []string{dept}
+	default:
+		respondError(w, http.StatusBadRequest, "owner_type must be 'user', 'squad', or 'department'")
+		return
+	}
+
+	goals, err := h.goalRepo.List(r.Context(), filter)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list goals", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch goals")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, goals)
+}
+
+// GetGoal godoc
+// @Summary Get a goal
+// @Description Returns a goal with its key results and progress
+// @Tags Goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Goal ID"
+// @Success 200 {object} models.Goal "Goal"
+// @Failure 400 {object} map[string]interface{} "Invalid goal ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Goal not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /goals/{id} [get]
+func (h *GoalHandlers) GetGoal(w http.ResponseWriter, r *http.Request) {
+	if requireAuth(w, r) == nil {
+		return
+	}
+
+	goal := h.getGoal(w, r)
+	if goal == nil {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, goal)
+}
+
+// CreateGoal godoc
+// @Summary Create a goal
+// @Description Creates a goal with its key results. Users can set their own goals; supervisors can also set goals for their reports, squads, and their department. Admins can set any goal.
+// @Tags Goals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.GoalRequest true "Goal"
+// @Success 201 {object} models.Goal "Created goal"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /goals [post]
+func (h *GoalHandlers) CreateGoal(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.GoalRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+	for _, kr := range req.KeyResults {
+		if kr.ID != nil {
+			respondError(w, http.StatusBadRequest, "New goals can't reference existing key results")
+			return
+		}
+	}
+	if !h.requireOwnerAccess(w, r, currentUser, req.OwnerType, req.OwnerID, req.OwnerDepartment) {
+		return
+	}
+
+	goal, err := h.goalRepo.Create(r.Context(), &req, currentUser.ID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to create goal", err)
+		respondError(w, http.StatusInternalServerError, "Failed to create goal")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, goal)
+}
+
+// UpdateGoal godoc
+// @Summary Update a goal
+// @Description Replaces a goal's details and key results. Key results sent with their ID keep their progress; key results left out are deleted.
+// @Tags Goals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Goal ID"
+// @Param request body models.GoalRequest true "Goal"
+// @Success 200 {object} models.Goal "Updated goal"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Goal not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /goals/{id} [put]
+func (h *GoalHandlers) UpdateGoal(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	goal := h.getGoal(w, r)
+	if goal == nil {
+		return
+	}
+	if !h.requireOwnerAccess(w, r, currentUser, goal.OwnerType, goal.OwnerID, goal.OwnerDepartment) {
+		return
+	}
+
+	var req models.GoalRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+	for _, kr := range req.KeyResults {
+		if kr.ID != nil && !slices.ContainsFunc(goal.KeyResults, func(existing models.KeyResult) bool { return existing.ID == *kr.ID }) {
+			respondError(w, http.StatusBadRequest, "Key result does not belong to this goal")
+			return
+		}
+	}
+	// Moving a goal to a new owner also needs access to that owner
+	if !h.requireOwnerAccess(w, r, currentUser, req.OwnerType, req.OwnerID, req.OwnerDepartment) {
+		return
+	}
+
+	updated, err := h.goalRepo.Update(r.Context(), goal.ID, &req)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to update goal", err, "goal_id", goal.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to update goal")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteGoal godoc
+// @Summary Delete a goal
+// @Description Deletes a goal along with its key results and progress history
+// @Tags Goals
+// @Security BearerAuth
+// @Param id path int true "Goal ID"
+// @Success 204 "Goal deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid goal ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Goal not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /goals/{id} [delete]
+func (h *GoalHandlers) DeleteGoal(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	goal := h.getGoal(w, r)
+	if goal == nil {
+		return
+	}
+	if !h.requireOwnerAccess(w, r, currentUser, goal.OwnerType, goal.OwnerID, goal.OwnerDepartment) {
+		return
+	}
+
+	if err := h.goalRepo.Delete(r.Context(), goal.ID); err != nil {
+		h.logger.LogError(r.Context(), "Failed to delete goal", err, "goal_id", goal.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to delete goal")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RecordProgress godoc
+// @Summary Record key result progress
+// @Description Sets a key result's current value and adds it to the goal's progress history
+// @Tags Goals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Key result ID"
+// @Param request body models.ProgressUpdateRequest true "New value"
+// @Success 201 {object} models.Goal "Goal with updated progress"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Key result not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /key-results/{id}/progress [post]
+func (h *GoalHandlers) RecordProgress(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid key result ID")
+		return
+	}
+	kr, err := h.goalRepo.GetKeyResult(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get key result", err, "key_result_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch key result")
+		return
+	}
+	if kr == nil {
+		respondError(w, http.StatusNotFound, "Key result not found")
+		return
+	}
+	goal, err := h.goalRepo.GetByID(r.Context(), kr.GoalID)
+	if err != nil || goal == nil {
+		h.logger.LogError(r.Context(), "Failed to get goal", err, "goal_id", kr.GoalID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch goal")
+		return
+	}
+	if !h.requireOwnerAccess(w, r, currentUser, goal.OwnerType, goal.OwnerID, goal.OwnerDepartment) {
+		return
+	}
+
+	var req models.ProgressUpdateRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	if _, err := h.goalRepo.RecordProgress(r.Context(), kr.ID, currentUser.ID, &req); err != nil {
+		h.logger.LogError(r.Context(), "Failed to record goal progress", err, "key_result_id", kr.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to record progress")
+		return
+	}
+
+	updated, err := h.goalRepo.GetByID(r.Context(), goal.ID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get goal", err, "goal_id", goal.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch goal")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, updated)
+}
+
+// GetGoalProgress godoc
+// @Summary Get a goal's progress history
+// @Description Returns the recorded values of a goal's key results, newest first
+// @Tags Goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Goal ID"
+// @Success 200 {array} models.GoalProgressUpdate "Progress updates"
+// @Failure 400 {object} map[string]interface{} "Invalid goal ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Goal not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /goals/{id}/progress [get]
+func (h *GoalHandlers) GetGoalProgress(w http.ResponseWriter, r *http.Request) {
+	if requireAuth(w, r) == nil {
+		return
+	}
+
+	goal := h.getGoal(w, r)
+	if goal == nil {
+		return
+	}
+
+	updates, err := h.goalRepo.GetProgressUpdates(r.Context(), goal.ID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get goal progress", err, "goal_id", goal.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch progress")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updates)
+}
+
+// GetGoalRollup godoc
+// @Summary Get goal progress by owner
+// @Description Summarizes a quarter's goals by owner for dashboards. Admins see every owner; supervisors see themselves, their direct reports, those people's squads, and their departments.
+// @Tags Goals
+// @Produce json
+// @Security BearerAuth
+// @Param quarter query string false "Quarter, such as 2025-Q3; defaults to the current quarter"
+// @Success 200 {array} models.GoalRollup "Progress by owner"
+// @Failure 400 {object} map[string]interface{} "Invalid quarter"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /goals/rollup [get]
+func (h *GoalHandlers) GetGoalRollup(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionTeamView)
+	if currentUser == nil {
+		return
+	}
+
+	filter := models.GoalFilter{Quarter: r.URL.Query().Get("quarter")}
+	if filter.Quarter == "" {
+		filter.Quarter = models.QuarterOf(time.Now().In(currentUser.Location()))
+	} else if err := models.ValidateQuarter(filter.Quarter); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !authz.CanAll(currentUser, authz.ActionTeamView) {
+		if err := h.scopeToTeam(r.Context(), currentUser, &filter); err != nil {
+			h.logger.LogError(r.Context(), "Failed to load team for goal rollup", err)
+			respondError(w, http.StatusInternalServerError, "Failed to fetch goal rollup")
+			return
+		}
+	}
+
+	goals, err := h.goalRepo.List(r.Context(), filter)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list goals for rollup", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch goal rollup")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.RollupGoals(goals))
+}
+
+// scopeToTeam limits a goal filter to a supervisor, their direct reports, the squads those
+// people are in or the supervisor leads, and their departments
+func (h *GoalHandlers) scopeToTeam(ctx context.Context, supervisor *models.User, filter *models.GoalFilter) error {
+	reports, err := h.userRepo.GetDirectReportsBySupervisorID(ctx, supervisor.ID)
+	if err != nil {
+		return err
+	}
+	team := append([]models.User{*supervisor}, reports...)
+
+	for _, u := range team {
+		filter.UserIDs = append(filter.UserIDs, u.ID)
+		if u.Department != "" && !slices.Contains(filter.Departments, u.Department) {
+			filter.Departments = append(filter.Departments, u.Department)
+		}
+	}
+
+	squadsByUser, err := h.squadRepo.GetByUserIDs(ctx, filter.UserIDs)
+	if err != nil {
+		return err
+	}
+	led, err := h.squadRepo.GetLedSquadIDs(ctx, supervisor.ID)
+	if err != nil {
+		return err
+	}
+	filter.SquadIDs = append(filter.SquadIDs, led...)
+	for _, squads := range squadsByUser {
+		for _, s := range squads {
+			if !slices.Contains(filter.SquadIDs, s.ID) {
+				filter.SquadIDs = append(filter.SquadIDs, s.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// getGoal loads the goal in the URL, responding with an error if it can't
+func (h *GoalHandlers) getGoal(w http.ResponseWriter, r *http.Request) *models.Goal {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid goal ID")
+		return nil
+	}
+
+	goal, err := h.goalRepo.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get goal", err, "goal_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch goal")
+		return nil
+	}
+	if goal == nil {
+		respondError(w, http.StatusNotFound, "Goal not found")
+		return nil
+	}
+	return goal
+}
+
+// requireOwnerAccess checks that a goal owner exists and that the user may manage its goals, as
+// authz.ActionGoalManage describes; squad leads also manage their squads'. It responds with an
+// error and returns false if not.
+func (h *GoalHandlers) requireOwnerAccess(w http.ResponseWriter, r *http.Request, user *models.User, ownerType models.GoalOwnerType, ownerID *int64, department *string) bool {
+	ctx := r.Context()
+	allowed := authz.CanAll(user, authz.ActionGoalManage)
+
+	switch ownerType {
+	case models.GoalOwnerUser:
+		owner, err := h.userRepo.GetByID(ctx, *ownerID)
+		if err != nil || owner == nil {
+			respondError(w, http.StatusBadRequest, "Goal owner not found")
+			return false
+		}
+		allowed = allowed || authz.Can(user, authz.ActionGoalManage, authz.UserResource(owner))
+	case models.GoalOwnerSquad:
+		squad, err := h.squadRepo.GetByID(ctx, *ownerID)
+		if err != nil || squad == nil {
+			respondError(w, http.StatusBadRequest, "Goal owner not found")
+			return false
+		}
+		allowed = allowed || authz.CanForOthers(user, authz.ActionGoalManage)
+		if !allowed {
+			led, err := h.squadRepo.GetLedSquadIDs(ctx, user.ID)
+			if err != nil {
+				h.logger.LogError(ctx, "Failed to get led squads", err, "user_id", user.ID)
+				respondError(w, http.StatusInternalServerError, "Failed to check goal access")
+				return false
+			}
+			allowed = slices.Contains(led, squad.ID)
+		}
+	case models.GoalOwnerDepartment:
+		allowed = allowed || (authz.CanForOthers(user, authz.ActionGoalManage) && user.Department == *department)
+	}
+
+	if !allowed {
+		respondError(w, http.StatusForbidden, "Forbidden: you can't manage this owner's goals")
+		return false
+	}
+	return true
+}
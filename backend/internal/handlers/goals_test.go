@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestGoalHandlers(t *testing.T) {
+	supervisorID := int64(2)
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, Role: models.RoleSupervisor, Department: "Engineering", IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, Role: models.RoleEmployee, Department: "Engineering", SupervisorID: &supervisorID, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 4, Role: models.RoleEmployee, Department: "Sales", IsActive: true})
+	squadRepo := mocks.NewMockSquadRepository()
+	squadRepo.Squads[7] = &models.Squad{ID: 7, Name: "Platform"}
+	squadRepo.Squads[8] = &models.Squad{ID: 8, Name: "Growth"}
+	squadRepo.UserSquads[3] = []int64{7}
+	goalRepo := mocks.NewMockGoalRepository()
+	h := NewGoalHandlers(goalRepo, userRepo, squadRepo)
+
+	do := func(handler http.HandlerFunc, method string, currentUserID int64, id, query, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/goals"+query, strings.NewReader(body))
+		req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[currentUserID]), "id", id))
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+	goalBody := func(owner string) string {
+		return `{"title":"Improve reliability","quarter":"2025-Q3",` + owner +
+			`,"key_results":[{"title":"Uptime","start_value":99,"target_value":99.9,"unit":"%"},{"title":"Incidents closed","target_value":4}]}`
+	}
+
+	t.Run("create", func(t *testing.T) {
+		for _, tt := range []struct {
+			name          string
+			currentUserID int64
+			owner         string
+			want          int
+		}{
+			{"own goal", 3, `"owner_type":"user","owner_id":3`, http.StatusCreated},
+			{"supervisor for report", 2, `"owner_type":"user","owner_id":3`, http.StatusCreated},
+			{"supervisor for squad", 2, `"owner_type":"squad","owner_id":7`, http.StatusCreated},
+			{"supervisor for own department", 2, `"owner_type":"department","owner_department":"Engineering"`, http.StatusCreated},
+			{"admin for any department", 1, `"owner_type":"department","owner_department":"Sales"`, http.StatusCreated},
+			{"employee for someone else", 4, `"owner_type":"user","owner_id":3`, http.StatusForbidden},
+			{"employee for squad", 3, `"owner_type":"squad","owner_id":7`, http.StatusForbidden},
+			{"supervisor for other department", 2, `"owner_type":"department","owner_department":"Sales"`, http.StatusForbidden},
+			{"missing squad", 2, `"owner_type":"squad","owner_id":99`, http.StatusBadRequest},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				if rr := do(h.CreateGoal, http.MethodPost, tt.currentUserID, "", "", goalBody(tt.owner)); rr.Code != tt.want {
+					t.Errorf("CreateGoal() status = %d, want %d: %s", rr.Code, tt.want, rr.Body.String())
+				}
+			})
+		}
+	})
+
+	t.Run("record progress", func(t *testing.T) {
+		if rr := do(h.RecordProgress, http.MethodPost, 4, "2", "", `{"value":2}`); rr.Code != http.StatusForbidden {
+			t.Errorf("RecordProgress() by unrelated employee status = %d, want %d", rr.Code, http.StatusForbidden)
+		}
+		rr := do(h.RecordProgress, http.MethodPost, 3, "2", "", `{"value":2,"note":"Two down"}`)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("RecordProgress() status = %d: %s", rr.Code, rr.Body.String())
+		}
+		var goal models.Goal
+		if err := json.NewDecoder(rr.Body).Decode(&goal); err != nil {
+			t.Fatal(err)
+		}
+		if goal.Progress != 0.25 || goal.KeyResults[1].CurrentValue != 2 {
+			t.Errorf("goal after progress = %+v, want 0.25 progress", goal)
+		}
+		if rr := do(h.RecordProgress, http.MethodPost, 3, "99", "", `{"value":1}`); rr.Code != http.StatusNotFound {
+			t.Errorf("RecordProgress() for missing key result status = %d, want %d", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("update keeps progress of existing key results", func(t *testing.T) {
+		body := `{"title":"Improve reliability","quarter":"2025-Q3","owner_type":"user","owner_id":3,` +
+			`"key_results":[{"id":2,"title":"Incidents closed","target_value":8}]}`
+		rr := do(h.UpdateGoal, http.MethodPut, 3, "1", "", body)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("UpdateGoal() status = %d: %s", rr.Code, rr.Body.String())
+		}
+		goal := goalRepo.Goals[1]
+		if len(goal.KeyResults) != 1 || goal.KeyResults[0].CurrentValue != 2 || goal.Progress != 0.25 {
+			t.Errorf("goal after update = %+v", goal)
+		}
+
+		foreign := `{"title":"x","quarter":"2025-Q3","owner_type":"user","owner_id":3,"key_results":[{"id":5,"title":"x","target_value":1}]}`
+		if rr := do(h.UpdateGoal, http.MethodPut, 3, "1", "", foreign); rr.Code != http.StatusBadRequest {
+			t.Errorf("UpdateGoal() with another goal's key result status = %d, want %d", rr.Code, http.StatusBadRequest)
+		}
+		moved := `{"title":"x","quarter":"2025-Q3","owner_type":"user","owner_id":4,"key_results":[{"title":"x","target_value":1}]}`
+		if rr := do(h.UpdateGoal, http.MethodPut, 3, "1", "", moved); rr.Code != http.StatusForbidden {
+			t.Errorf("UpdateGoal() moving to another owner status = %d, want %d", rr.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("list and progress history", func(t *testing.T) {
+		var goals []models.Goal
+		rr := do(h.GetGoals, http.MethodGet, 4, "", "?owner_type=squad&owner_id=7", "")
+		if err := json.NewDecoder(rr.Body).Decode(&goals); err != nil {
+			t.Fatal(err)
+		}
+		if len(goals) != 1 || goals[0].OwnerType != models.GoalOwnerSquad {
+			t.Errorf("GetGoals() for squad = %+v", goals)
+		}
+		if rr := do(h.GetGoals, http.MethodGet, 4, "", "?quarter=2025", ""); rr.Code != http.StatusBadRequest {
+			t.Errorf("GetGoals() with invalid quarter status = %d, want %d", rr.Code, http.StatusBadRequest)
+		}
+
+		var updates []models.GoalProgressUpdate
+		rr = do(h.GetGoalProgress, http.MethodGet, 4, "1", "", "")
+		if err := json.NewDecoder(rr.Body).Decode(&updates); err != nil {
+			t.Fatal(err)
+		}
+		if len(updates) != 1 || updates[0].Value != 2 || *updates[0].Note != "Two down" {
+			t.Errorf("GetGoalProgress() = %+v", updates)
+		}
+	})
+
+	t.Run("rollup", func(t *testing.T) {
+		rollup := func(currentUserID int64) []models.GoalRollup {
+			rr := do(h.GetGoalRollup, http.MethodGet, currentUserID, "", "?quarter=2025-Q3", "")
+			if rr.Code != http.StatusOK {
+				t.Fatalf("GetGoalRollup() status = %d: %s", rr.Code, rr.Body.String())
+			}
+			var rollups []models.GoalRollup
+			if err := json.NewDecoder(rr.Body).Decode(&rollups); err != nil {
+				t.Fatal(err)
+			}
+			return rollups
+		}
+
+		// The supervisor sees their department, their report's squad, and their report, but not Sales
+		if got := rollup(2); len(got) != 3 {
+			t.Errorf("GetGoalRollup() as supervisor = %+v, want 3 owners", got)
+		}
+		if got := rollup(1); len(got) != 4 {
+			t.Errorf("GetGoalRollup() as admin = %+v, want 4 owners", got)
+		}
+		if rr := do(h.GetGoalRollup, http.MethodGet, 3, "", "", ""); rr.Code != http.StatusForbidden {
+			t.Errorf("GetGoalRollup() as employee status = %d, want %d", rr.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if rr := do(h.DeleteGoal, http.MethodDelete, 4, "1", "", ""); rr.Code != http.StatusForbidden {
+			t.Errorf("DeleteGoal() by unrelated employee status = %d, want %d", rr.Code, http.StatusForbidden)
+		}
+		if rr := do(h.DeleteGoal, http.MethodDelete, 2, "1", "", ""); rr.Code != http.StatusNoContent || goalRepo.Goals[1] != nil {
+			t.Errorf("DeleteGoal() status = %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
 	"github.com/smith-dallin/manager-dashboard/internal/cache"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
 	"github.com/smith-dallin/manager-dashboard/internal/logger"
 	"github.com/smith-dallin/manager-dashboard/internal/middleware"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
@@ -17,20 +23,30 @@ import (
 
 // Cache key prefixes
 const (
-	cacheKeyAllUsers     = "users:all"
-	cacheKeySupervisors  = "users:supervisors"
-	cacheKeyAllSquads    = "squads:all"
-	cacheKeyEmployees    = "users:employees:"
+	cacheKeyAllUsers    = "users:all"
+	cacheKeyUsersETag   = "users:all:etag"
+	cacheKeySupervisors = "users:supervisors"
+	cacheKeyAllSquads   = "squads:all"
+	cacheKeySquadsETag  = "squads:all:etag"
+	cacheKeyEmployees   = "users:employees:"
 )
 
 // Handlers handles user-related HTTP requests
 type Handlers struct {
-	userRepo       repository.UserRepository
-	squadRepo      repository.SquadRepository
-	departmentRepo repository.DepartmentRepository
-	userService    *services.UserService
-	cache          *cache.Cache
-	logger         *logger.Logger
+	userRepo        repository.UserRepository
+	squadRepo       repository.SquadRepository
+	departmentRepo  repository.DepartmentRepository
+	customFieldRepo repository.CustomFieldRepository
+	historyRepo     repository.UserHistoryRepository
+	savedViews      repository.SavedViewRepository
+	profileChanges  repository.ProfileChangeRepository
+	userService     *services.UserService
+	notifications   *services.NotificationService
+	onboarding      *services.OnboardingService
+	auth0Sync       *services.Auth0SyncService
+	cache           *cache.Cache
+	broker          *events.Broker
+	logger          *logger.Logger
 }
 
 // New creates a new user handlers instance
@@ -44,6 +60,13 @@ func New(userRepo repository.UserRepository, squadRepo repository.SquadRepositor
 	}
 }
 
+// NewWithEvents creates a new user handlers instance that publishes directory changes to the given broker
+func NewWithEvents(userRepo repository.UserRepository, squadRepo repository.SquadRepository, departmentRepo repository.DepartmentRepository, broker *events.Broker) *Handlers {
+	h := New(userRepo, squadRepo, departmentRepo)
+	h.broker = broker
+	return h
+}
+
 // NewWithCache creates a new user handlers instance with caching support
 func NewWithCache(userRepo repository.UserRepository, squadRepo repository.SquadRepository, c *cache.Cache) *Handlers {
 	return &Handlers{
@@ -66,22 +89,33 @@ func NewWithLogger(userRepo repository.UserRepository, squadRepo repository.Squa
 	}
 }
 
-// InvalidateUserCache clears user-related cache entries
-func (h *Handlers) InvalidateUserCache() {
+// WithNotifications notifies supervisors about profile change requests and reassigned reports, and
+// users about reviewed profile changes
+func (h *Handlers) WithNotifications(notifications *services.NotificationService) *Handlers {
+	h.notifications = notifications
+	return h
+}
+
+// InvalidateUserCache clears user-related cache entries and announces the change
+func (h *Handlers) InvalidateUserCache(ctx context.Context) {
+	h.broker.Publish(ctx, events.UserChanged, nil)
 	if h.cache == nil {
 		return
 	}
 	h.cache.Delete(cacheKeyAllUsers)
+	h.cache.Delete(cacheKeyUsersETag)
 	h.cache.Delete(cacheKeySupervisors)
 	h.cache.DeletePrefix(cacheKeyEmployees)
 }
 
-// InvalidateSquadCache clears squad-related cache entries
-func (h *Handlers) InvalidateSquadCache() {
+// InvalidateSquadCache clears squad-related cache entries and announces the change
+func (h *Handlers) InvalidateSquadCache(ctx context.Context) {
+	h.broker.Publish(ctx, events.SquadChanged, nil)
 	if h.cache == nil {
 		return
 	}
 	h.cache.Delete(cacheKeyAllSquads)
+	h.cache.Delete(cacheKeySquadsETag)
 }
 
 // GetCurrentUser godoc
@@ -109,7 +143,7 @@ func (h *Handlers) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, http.StatusOK, userWithSquads.ToUserResponse())
+	respondJSON(w, http.StatusOK, userWithSquads.ToUserResponse().WithEmergencyContact(userWithSquads))
 }
 
 // GetEmployees godoc
@@ -121,7 +155,10 @@ func (h *Handlers) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page" default(50)
+// @Param format query string false "Download as a file instead of JSON" Enums(csv, xlsx)
+// @Param columns query string false "Comma-separated columns to download, defaults to all"
 // @Success 200 {array} models.User "List of employees"
+// @Failure 400 {object} map[string]interface{} "Invalid format or columns"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /employees [get]
@@ -131,6 +168,11 @@ func (h *Handlers) GetEmployees(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	download, ok := parseTableDownload(w, r, services.UserExportColumns)
+	if !ok {
+		return
+	}
+
 	// Use service to get employees with squads loaded based on role
 	employees, err := h.userService.GetEmployeesForUser(r.Context(), user)
 	if err != nil {
@@ -142,6 +184,11 @@ func (h *Handlers) GetEmployees(w http.ResponseWriter, r *http.Request) {
 	// Convert to response DTOs to avoid exposing sensitive fields
 	employeeResponses := models.ToUserResponses(employees)
 
+	if download != nil {
+		download.respond(w, r, "employees", employeeResponses)
+		return
+	}
+
 	// Support optional pagination
 	if shouldPaginate(r) {
 		p := parsePagination(r)
@@ -162,19 +209,33 @@ func (h *Handlers) GetEmployees(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page" default(50)
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param format query string false "Download as a file instead of JSON" Enums(csv, xlsx)
+// @Param columns query string false "Comma-separated columns to download, defaults to all"
 // @Success 200 {array} models.User "List of users"
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]interface{} "Invalid format or columns"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /users [get]
 func (h *Handlers) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 	var users []models.User
+	var etag string
 	var err error
 
-	// Try cache first
+	download, ok := parseTableDownload(w, r, services.UserExportColumns)
+	if !ok {
+		return
+	}
+
+	// Try cache first. The ETag is cached beside the list, so InvalidateUserCache drops both.
 	if h.cache != nil {
 		if cached, found := h.cache.Get(cacheKeyAllUsers); found {
 			users = cached.([]models.User)
 		}
+		if cached, found := h.cache.Get(cacheKeyUsersETag); found {
+			etag = cached.(string)
+		}
 	}
 
 	// Fetch from database if not cached
@@ -190,6 +251,22 @@ func (h *Handlers) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Downloads skip the ETag, which identifies the JSON representation
+	if download != nil {
+		download.respond(w, r, "users", models.ToUserResponses(users))
+		return
+	}
+
+	if etag == "" {
+		etag = usersETag(users)
+		if h.cache != nil {
+			h.cache.Set(cacheKeyUsersETag, etag)
+		}
+	}
+	if notModified(w, r, etag) {
+		return
+	}
+
 	// Convert to response DTOs to avoid exposing sensitive fields
 	userResponses := models.ToUserResponses(users)
 
@@ -204,9 +281,93 @@ func (h *Handlers) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, userResponses)
 }
 
+// SearchUsers godoc
+// @Summary Search users
+// @Description Returns users whose name, email, title, or department match q, most relevant first, optionally filtered by department, squad, role, and active status. Without q, matching users are ordered by name. Only admins can search inactive users.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Search text"
+// @Param department query string false "Department name"
+// @Param squad query int false "Squad ID"
+// @Param role query string false "Role"
+// @Param active query bool false "Active status" default(true)
+// @Param view query int false "Saved view to apply under the filters given"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(50)
+// @Success 200 {object} PaginatedResponse "Matching users"
+// @Failure 400 {object} map[string]interface{} "Invalid query"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Saved view not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/search [get]
+func (h *Handlers) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+	if !applySavedView(w, r, h.savedViews, currentUser, models.SavedViewResourceTeamMembers) {
+		return
+	}
+
+	filter, err := parseUserSearchFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid query: %v", err))
+		return
+	}
+	if !*filter.Active && !authz.Can(currentUser, authz.ActionUserViewInactive, nil) {
+		respondError(w, http.StatusForbidden, "Forbidden: only admins can search inactive users")
+		return
+	}
+
+	p := parsePagination(r)
+	users, total, err := h.userService.Search(r.Context(), filter, p.PerPage, p.Offset)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to search users", err, "query", filter.Query)
+		respondError(w, http.StatusInternalServerError, "Failed to search users")
+		return
+	}
+
+	respondPaginated(w, models.ToUserResponses(users), total, p)
+}
+
+// parseUserSearchFilter builds a UserSearchFilter from query parameters. Only active users are
+// searched unless active is given.
+func parseUserSearchFilter(r *http.Request) (models.UserSearchFilter, error) {
+	q := r.URL.Query()
+	filter := models.UserSearchFilter{Query: q.Get("q")}
+
+	if v := q.Get("department"); v != "" {
+		filter.Department = &v
+	}
+	if v := q.Get("squad"); v != "" {
+		squadID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("squad must be a squad ID")
+		}
+		filter.SquadID = &squadID
+	}
+	if v := q.Get("role"); v != "" {
+		role := models.Role(v)
+		filter.Role = &role
+	}
+	active := true
+	if v := q.Get("active"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("active must be true or false")
+		}
+		active = parsed
+	}
+	filter.Active = &active
+
+	return filter, filter.Validate()
+}
+
 // GetUserByID godoc
 // @Summary Get user by ID
-// @Description Returns a specific user by their ID. Employees can only view themselves, supervisors can view their direct reports.
+// @Description Returns a specific user by their ID. Employees can only view themselves, supervisors can view their direct reports. Users with session:manage also see when the user last signed in and was last seen.
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -230,31 +391,37 @@ func (h *Handlers) GetUserByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Employees can only view themselves
-	if currentUser.Role == models.RoleEmployee && currentUser.ID != id {
-		respondError(w, http.StatusForbidden, "Forbidden: employees can only view their own profile")
+	// Users who may only view themselves don't learn whether anyone else exists
+	if currentUser.ID != id && !authz.CanForOthers(currentUser, authz.ActionUserView) {
+		respondError(w, http.StatusForbidden, "Forbidden: you can only view your own profile")
 		return
 	}
 
 	// Use service to get user with squads loaded
 	user, err := h.userService.GetByID(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "User not found")
+		respondRepositoryError(w, r, err, "User")
 		return
 	}
 
-	// Supervisors can only view their own direct reports
-	if currentUser.Role == models.RoleSupervisor && user.SupervisorID != nil && *user.SupervisorID != currentUser.ID && user.ID != currentUser.ID {
+	if !authz.Can(currentUser, authz.ActionUserView, authz.UserResource(user)) {
 		respondError(w, http.StatusForbidden, "Forbidden: supervisors can only view their own direct reports")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, user.ToUserResponse())
+	resp := user.ToUserResponse()
+	if authz.Can(currentUser, authz.ActionSessionManage, nil) {
+		resp = resp.WithLoginActivity(user)
+	}
+	if authz.Can(currentUser, authz.ActionUserUpdate, authz.UserResource(user)) {
+		resp = resp.WithEmergencyContact(user)
+	}
+	respondJSON(w, http.StatusOK, resp)
 }
 
 // CreateUser godoc
 // @Summary Create a new user
-// @Description Creates a new user. Only supervisors can create users, and they will be assigned as the supervisor.
+// @Description Creates a new user reporting to the current user unless a supervisor is given. Supervisors can only create their own direct reports; admins can create anyone.
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -268,8 +435,8 @@ func (h *Handlers) GetUserByID(w http.ResponseWriter, r *http.Request) {
 // @Router /users [post]
 func (h *Handlers) CreateUser(w http.ResponseWriter, r *http.Request) {
 	currentUser := middleware.GetUserFromContext(r.Context())
-	if currentUser == nil || currentUser.Role != models.RoleSupervisor {
-		respondError(w, http.StatusForbidden, "Forbidden: only supervisors can create users")
+	if currentUser == nil || !authz.Can(currentUser, authz.ActionUserCreate, nil) {
+		respondError(w, http.StatusForbidden, "Forbidden: only admins and supervisors can create users")
 		return
 	}
 
@@ -282,6 +449,10 @@ func (h *Handlers) CreateUser(w http.ResponseWriter, r *http.Request) {
 	if req.SupervisorID == nil {
 		req.SupervisorID = &currentUser.ID
 	}
+	if !authz.Can(currentUser, authz.ActionUserCreate, &authz.Resource{SupervisorID: req.SupervisorID}) {
+		respondError(w, http.StatusForbidden, "Forbidden: can only create users reporting to you")
+		return
+	}
 
 	user, err := h.userRepo.Create(r.Context(), &req, "")
 	if err != nil {
@@ -289,12 +460,14 @@ func (h *Handlers) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.onboarding.Start(r.Context(), user)
+	h.InvalidateUserCache(r.Context())
 	respondJSON(w, http.StatusCreated, user.ToUserResponse())
 }
 
 // UpdateUser godoc
 // @Summary Update a user
-// @Description Updates a user's profile. Employees can update themselves (limited fields), supervisors can update their direct reports.
+// @Description Updates a user's profile. Employees can update themselves (limited fields), supervisors can update their direct reports. Users who can't update everyone can't change their own title or department directly: the change is saved as a pending profile change request for their supervisor, returned as pending_profile_change. Supervisors are changed with POST /users/{id}/supervisor; supervisor_id is only accepted here unchanged.
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -323,19 +496,15 @@ func (h *Handlers) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	// Check permissions
 	targetUser, err := h.userRepo.GetByID(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "User not found")
+		respondRepositoryError(w, r, err, "User")
 		return
 	}
+	middleware.SetAuditBefore(r.Context(), targetUser)
 
 	// Employees can only update themselves (limited fields)
 	// Supervisors can update their direct reports
-	if currentUser.Role == models.RoleEmployee && currentUser.ID != id {
-		respondError(w, http.StatusForbidden, "Forbidden: employees can only update their own profile")
-		return
-	}
-
-	if currentUser.Role == models.RoleSupervisor && targetUser.SupervisorID != nil && *targetUser.SupervisorID != currentUser.ID && targetUser.ID != currentUser.ID {
-		respondError(w, http.StatusForbidden, "Forbidden: supervisors can only update their own direct reports")
+	if !authz.Can(currentUser, authz.ActionUserUpdate, authz.UserResource(targetUser)) {
+		respondError(w, http.StatusForbidden, "Forbidden: can only update your own profile or your direct reports'")
 		return
 	}
 
@@ -344,14 +513,36 @@ func (h *Handlers) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Employees cannot change their own role
-	if currentUser.Role == models.RoleEmployee && req.Role != nil {
+	// Employees cannot change roles, and supervisors only their direct reports'
+	if req.Role != nil && *req.Role != targetUser.Role && !authz.Can(currentUser, authz.ActionUserChangeRole, authz.UserResource(targetUser)) {
 		respondError(w, http.StatusForbidden, "Forbidden: cannot change role")
 		return
 	}
 
-	// Use service to update user and squads
-	user, err := h.userService.Update(r.Context(), id, &req)
+	// Supervisors are changed through ReassignSupervisor, which checks the new reporting line
+	if req.SupervisorID != nil {
+		if targetUser.SupervisorID == nil || *req.SupervisorID != *targetUser.SupervisorID {
+			respondError(w, http.StatusBadRequest, "Use POST /users/{id}/supervisor to change a user's supervisor")
+			return
+		}
+		req.SupervisorID = nil
+	}
+
+	var pendingChange *models.ProfileChangeRequest
+	if h.needsProfileChangeReview(currentUser, targetUser) {
+		if pendingChange, err = h.proposeProfileChange(r.Context(), targetUser, &req); err != nil {
+			h.logger.LogError(r.Context(), "Failed to request profile change", err, "user_id", id)
+			respondError(w, http.StatusInternalServerError, "Failed to request profile change")
+			return
+		}
+	}
+
+	// Use service to update user, squads, and custom fields
+	user, err := h.userService.Update(r.Context(), id, &req, currentUser.ID)
+	if errors.Is(err, services.ErrInvalidCustomField) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		h.logger.LogError(r.Context(), "Failed to update user", err, "user_id", id)
 		respondError(w, http.StatusInternalServerError, "Failed to update user")
@@ -359,14 +550,16 @@ func (h *Handlers) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Invalidate user cache on successful update
-	h.InvalidateUserCache()
+	h.InvalidateUserCache(r.Context())
 
-	respondJSON(w, http.StatusOK, user.ToUserResponse())
+	resp := user.ToUserResponse().WithEmergencyContact(user)
+	resp.PendingProfileChange = pendingChange
+	respondJSON(w, http.StatusOK, resp)
 }
 
 // DeleteUser godoc
 // @Summary Delete a user
-// @Description Deletes a user. Supervisors can only delete their own direct reports.
+// @Description Deletes a user. Admins can delete anyone but themselves; supervisors can only delete their own direct reports. Deleted users are deactivated, their direct reports are unassigned, and they can be restored until the retention period passes.
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -387,19 +580,24 @@ func (h *Handlers) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	currentUser := middleware.GetUserFromContext(r.Context())
-	if currentUser == nil || currentUser.Role != models.RoleSupervisor {
-		respondError(w, http.StatusForbidden, "Forbidden: only supervisors can delete users")
+	if currentUser == nil || !authz.Can(currentUser, authz.ActionUserDelete, nil) {
+		respondError(w, http.StatusForbidden, "Forbidden: only admins and supervisors can delete users")
+		return
+	}
+	if currentUser.ID == id {
+		respondError(w, http.StatusBadRequest, "Cannot delete yourself")
 		return
 	}
 
 	// Check if the user is a direct report of this supervisor
 	targetUser, err := h.userRepo.GetByID(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "User not found")
+		respondRepositoryError(w, r, err, "User")
 		return
 	}
+	middleware.SetAuditBefore(r.Context(), targetUser)
 
-	if targetUser.SupervisorID == nil || *targetUser.SupervisorID != currentUser.ID {
+	if !authz.Can(currentUser, authz.ActionUserDelete, authz.UserResource(targetUser)) {
 		respondError(w, http.StatusForbidden, "Forbidden: Can only delete your own direct reports")
 		return
 	}
@@ -408,13 +606,53 @@ func (h *Handlers) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to delete user")
 		return
 	}
+	h.auth0Sync.Block(r.Context(), targetUser)
 
 	// Invalidate user cache on successful delete
-	h.InvalidateUserCache()
+	h.InvalidateUserCache(r.Context())
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// RestoreUser godoc
+// @Summary Restore a deleted user
+// @Description Restores a deleted user, active only if they were active when deleted, before the retention period passes and they are purged. Their former direct reports stay unassigned. Requires deleted:restore.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.UserResponse "Restored user"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Deleted user not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/users/{id}/restore [post]
+func (h *Handlers) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionDeletedRestore) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.userRepo.Restore(r.Context(), id)
+	if err != nil {
+		respondRepositoryError(w, r, err, "Deleted user")
+		return
+	}
+	if user.IsActive {
+		h.auth0Sync.Unblock(r.Context(), user)
+	}
+
+	h.InvalidateUserCache(r.Context())
+
+	respondJSON(w, http.StatusOK, user.ToUserResponse())
+}
+
 // DeactivateUser godoc
 // @Summary Deactivate a user
 // @Description Deactivates a user (soft delete). Admins can deactivate anyone except themselves. Supervisors can deactivate their direct reports.
@@ -443,7 +681,7 @@ func (h *Handlers) DeactivateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Only admins and supervisors can deactivate users
-	if currentUser.Role == models.RoleEmployee {
+	if !authz.Can(currentUser, authz.ActionUserDeactivate, nil) {
 		respondError(w, http.StatusForbidden, "Forbidden: only admins and supervisors can deactivate users")
 		return
 	}
@@ -457,9 +695,10 @@ func (h *Handlers) DeactivateUser(w http.ResponseWriter, r *http.Request) {
 	// Get the target user
 	targetUser, err := h.userRepo.GetByID(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "User not found")
+		respondRepositoryError(w, r, err, "User")
 		return
 	}
+	middleware.SetAuditBefore(r.Context(), targetUser)
 
 	// Check if user is already inactive
 	if !targetUser.IsActive {
@@ -468,11 +707,9 @@ func (h *Handlers) DeactivateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Supervisors can only deactivate their direct reports
-	if currentUser.Role == models.RoleSupervisor {
-		if targetUser.SupervisorID == nil || *targetUser.SupervisorID != currentUser.ID {
-			respondError(w, http.StatusForbidden, "Forbidden: Can only deactivate your own direct reports")
-			return
-		}
+	if !authz.Can(currentUser, authz.ActionUserDeactivate, authz.UserResource(targetUser)) {
+		respondError(w, http.StatusForbidden, "Forbidden: Can only deactivate your own direct reports")
+		return
 	}
 
 	// Deactivate the user (this also cleans up tasks, time-off, etc.)
@@ -481,13 +718,169 @@ func (h *Handlers) DeactivateUser(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to deactivate user")
 		return
 	}
+	h.auth0Sync.Block(r.Context(), targetUser)
 
 	// Invalidate user cache on successful deactivation
-	h.InvalidateUserCache()
+	h.InvalidateUserCache(r.Context())
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// requireLifecycleManager checks the current user may offboard or reactivate the target: admins can
+// manage anyone except themselves and supervisors their direct reports, like DeactivateUser
+func requireLifecycleManager(w http.ResponseWriter, currentUser, targetUser *models.User, action string) bool {
+	if !authz.Can(currentUser, authz.ActionUserDeactivate, nil) {
+		respondError(w, http.StatusForbidden, "Forbidden: only admins and supervisors can "+action+" users")
+		return false
+	}
+	if currentUser.ID == targetUser.ID {
+		respondError(w, http.StatusBadRequest, "Cannot "+action+" yourself")
+		return false
+	}
+	if !authz.Can(currentUser, authz.ActionUserDeactivate, authz.UserResource(targetUser)) {
+		respondError(w, http.StatusForbidden, "Forbidden: Can only "+action+" your own direct reports")
+		return false
+	}
+	return true
+}
+
+// OffboardUser godoc
+// @Summary Offboard a user
+// @Description Deactivates a departing user and hands off their work: open tasks go to the chosen user (default: their supervisor), upcoming meetings they organize are cancelled, and their Jira mapping is revoked. Unlike deactivation, their task and time-off history is kept.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param body body models.OffboardUserRequest true "Termination date and task handoff"
+// @Success 200 {object} models.OffboardResult "Summary of the cleanup"
+// @Failure 400 {object} map[string]interface{} "Invalid request, user already inactive, or cannot offboard yourself"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/offboard [post]
+func (h *Handlers) OffboardUser(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	targetUser, err := h.userRepo.GetByID(r.Context(), id)
+	if err != nil {
+		respondRepositoryError(w, r, err, "User")
+		return
+	}
+	middleware.SetAuditBefore(r.Context(), targetUser)
+	if !requireLifecycleManager(w, currentUser, targetUser, "offboard") {
+		return
+	}
+	if !targetUser.IsActive {
+		respondError(w, http.StatusBadRequest, "User is already inactive")
+		return
+	}
+
+	var req models.OffboardUserRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Tasks go to the chosen user, or the departing user's supervisor if they're still active
+	reassignTo := req.ReassignTasksToID
+	if reassignTo != nil {
+		assignee, err := h.userRepo.GetByID(r.Context(), *reassignTo)
+		if err != nil || !assignee.IsActive || assignee.ID == id {
+			respondError(w, http.StatusBadRequest, "Tasks must be reassigned to another active user")
+			return
+		}
+	} else if targetUser.SupervisorID != nil {
+		if supervisor, err := h.userRepo.GetByID(r.Context(), *targetUser.SupervisorID); err == nil && supervisor.IsActive {
+			reassignTo = &supervisor.ID
+		}
+	}
+
+	result, err := h.userRepo.Offboard(r.Context(), id, reassignTo, req.Date())
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to offboard user", err, "user_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to offboard user")
+		return
+	}
+	h.auth0Sync.Block(r.Context(), targetUser)
+
+	h.InvalidateUserCache(r.Context())
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// ReactivateUser godoc
+// @Summary Reactivate a user
+// @Description Restores a deactivated or offboarded user and clears their termination date. Work handed off when they left isn't returned to them.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.UserResponse "Reactivated user"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID, user already active, or cannot reactivate yourself"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/reactivate [post]
+func (h *Handlers) ReactivateUser(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	targetUser, err := h.userRepo.GetByID(r.Context(), id)
+	if err != nil {
+		respondRepositoryError(w, r, err, "User")
+		return
+	}
+	middleware.SetAuditBefore(r.Context(), targetUser)
+	if !requireLifecycleManager(w, currentUser, targetUser, "reactivate") {
+		return
+	}
+	if targetUser.IsActive {
+		respondError(w, http.StatusBadRequest, "User is already active")
+		return
+	}
+
+	if err := h.userRepo.Reactivate(r.Context(), id); err != nil {
+		h.logger.LogError(r.Context(), "Failed to reactivate user", err, "user_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to reactivate user")
+		return
+	}
+	h.auth0Sync.Unblock(r.Context(), targetUser)
+
+	h.InvalidateUserCache(r.Context())
+
+	user, err := h.userService.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get reactivated user", err, "user_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to get user")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user.ToUserResponse())
+}
+
 func (h *Handlers) GetSupervisors(w http.ResponseWriter, r *http.Request) {
 	var supervisors []models.User
 	var err error
@@ -532,19 +925,33 @@ func (h *Handlers) GetSupervisors(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page" default(50)
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param format query string false "Download as a file instead of JSON" Enums(csv, xlsx)
+// @Param columns query string false "Comma-separated columns to download, defaults to all"
 // @Success 200 {array} models.Squad "List of squads"
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]interface{} "Invalid format or columns"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /squads [get]
 func (h *Handlers) GetSquads(w http.ResponseWriter, r *http.Request) {
 	var squads []models.Squad
+	var etag string
 	var err error
 
-	// Try cache first
+	download, ok := parseTableDownload(w, r, services.SquadExportColumns)
+	if !ok {
+		return
+	}
+
+	// Try cache first. The ETag is cached beside the list, so InvalidateSquadCache drops both.
 	if h.cache != nil {
 		if cached, found := h.cache.Get(cacheKeyAllSquads); found {
 			squads = cached.([]models.Squad)
 		}
+		if cached, found := h.cache.Get(cacheKeySquadsETag); found {
+			etag = cached.(string)
+		}
 	}
 
 	// Fetch from database if not cached
@@ -560,6 +967,21 @@ func (h *Handlers) GetSquads(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if download != nil {
+		download.respond(w, r, "squads", squads)
+		return
+	}
+
+	if etag == "" {
+		etag = squadsETag(squads)
+		if h.cache != nil {
+			h.cache.Set(cacheKeySquadsETag, etag)
+		}
+	}
+	if notModified(w, r, etag) {
+		return
+	}
+
 	// Support optional pagination
 	if shouldPaginate(r) {
 		p := parsePagination(r)
@@ -571,6 +993,33 @@ func (h *Handlers) GetSquads(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, squads)
 }
 
+// usersETag identifies a version of the user list by its size, latest updated_at, and squad
+// memberships, which change without touching the user row
+func usersETag(users []models.User) string {
+	var latest time.Time
+	b := newETagBuilder().add(len(users))
+	for _, u := range users {
+		if u.UpdatedAt.After(latest) {
+			latest = u.UpdatedAt
+		}
+		b.add(u.ID)
+		for _, s := range u.Squads {
+			b.add(s.ID, s.Name, s.Role)
+		}
+	}
+	return b.add(latest).weak()
+}
+
+// squadsETag identifies a version of the squad list. Squads have no updated_at, so names and leads
+// are hashed directly.
+func squadsETag(squads []models.Squad) string {
+	b := newETagBuilder().add(len(squads))
+	for _, s := range squads {
+		b.add(s.ID, s.Name, s.LeadID, s.CreatedAt)
+	}
+	return b.weak()
+}
+
 // CreateSquad godoc
 // @Summary Create a new squad
 // @Description Creates a new squad/team. Only admins and supervisors can create squads.
@@ -586,17 +1035,11 @@ func (h *Handlers) GetSquads(w http.ResponseWriter, r *http.Request) {
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /squads [post]
 func (h *Handlers) CreateSquad(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireAuth(w, r)
+	currentUser := requirePermission(w, r, authz.ActionSquadManage)
 	if currentUser == nil {
 		return
 	}
 
-	// Only admins and supervisors can create squads
-	if currentUser.Role == models.RoleEmployee {
-		respondError(w, http.StatusForbidden, "Forbidden: only admins and supervisors can create squads")
-		return
-	}
-
 	var req struct {
 		Name string `json:"name"`
 	}
@@ -618,7 +1061,7 @@ func (h *Handlers) CreateSquad(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Invalidate squad cache on successful create
-	h.InvalidateSquadCache()
+	h.InvalidateSquadCache(r.Context())
 
 	respondJSON(w, http.StatusCreated, squad)
 }
@@ -653,17 +1096,11 @@ func (h *Handlers) GetDepartments(w http.ResponseWriter, r *http.Request) {
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /departments [post]
 func (h *Handlers) CreateDepartment(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireAuth(w, r)
+	currentUser := requirePermission(w, r, authz.ActionDepartmentManage)
 	if currentUser == nil {
 		return
 	}
 
-	// Only admins and supervisors can create departments
-	if currentUser.Role == models.RoleEmployee {
-		respondError(w, http.StatusForbidden, "Forbidden: only admins and supervisors can create departments")
-		return
-	}
-
 	var req struct {
 		Name string `json:"name"`
 	}
@@ -696,17 +1133,11 @@ func (h *Handlers) CreateDepartment(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handlers) DeleteSquad(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireAuth(w, r)
+	currentUser := requirePermission(w, r, authz.ActionSquadManage)
 	if currentUser == nil {
 		return
 	}
 
-	// Only admins and supervisors can delete squads
-	if currentUser.Role == models.RoleEmployee {
-		respondError(w, http.StatusForbidden, "Forbidden: only admins and supervisors can delete squads")
-		return
-	}
-
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -721,23 +1152,17 @@ func (h *Handlers) DeleteSquad(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Invalidate squad cache on successful delete
-	h.InvalidateSquadCache()
+	h.InvalidateSquadCache(r.Context())
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Squad deleted successfully"})
 }
 
 func (h *Handlers) DeleteDepartment(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireAuth(w, r)
+	currentUser := requirePermission(w, r, authz.ActionDepartmentManage)
 	if currentUser == nil {
 		return
 	}
 
-	// Only admins and supervisors can delete departments
-	if currentUser.Role == models.RoleEmployee {
-		respondError(w, http.StatusForbidden, "Forbidden: only admins and supervisors can delete departments")
-		return
-	}
-
 	department := chi.URLParam(r, "name")
 	if department == "" {
 		respondError(w, http.StatusBadRequest, "Department name is required")
@@ -750,6 +1175,7 @@ func (h *Handlers) DeleteDepartment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.InvalidateUserCache(r.Context())
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Department deleted successfully"})
 }
 
@@ -769,17 +1195,11 @@ func (h *Handlers) DeleteDepartment(w http.ResponseWriter, r *http.Request) {
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /departments/{name} [put]
 func (h *Handlers) RenameDepartment(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireAuth(w, r)
+	currentUser := requirePermission(w, r, authz.ActionDepartmentManage)
 	if currentUser == nil {
 		return
 	}
 
-	// Only admins and supervisors can rename departments
-	if currentUser.Role == models.RoleEmployee {
-		respondError(w, http.StatusForbidden, "Forbidden: only admins and supervisors can rename departments")
-		return
-	}
-
 	oldName := chi.URLParam(r, "name")
 	if oldName == "" {
 		respondError(w, http.StatusBadRequest, "Department name is required")
@@ -807,7 +1227,7 @@ func (h *Handlers) RenameDepartment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Invalidate user cache since department field changed
-	h.InvalidateUserCache()
+	h.InvalidateUserCache(r.Context())
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Department renamed successfully"})
 }
@@ -862,17 +1282,11 @@ func (h *Handlers) GetUsersByDepartment(w http.ResponseWriter, r *http.Request)
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /squads/{id} [put]
 func (h *Handlers) RenameSquad(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireAuth(w, r)
+	currentUser := requirePermission(w, r, authz.ActionSquadManage)
 	if currentUser == nil {
 		return
 	}
 
-	// Only admins and supervisors can rename squads
-	if currentUser.Role == models.RoleEmployee {
-		respondError(w, http.StatusForbidden, "Forbidden: only admins and supervisors can rename squads")
-		return
-	}
-
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -902,7 +1316,7 @@ func (h *Handlers) RenameSquad(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Invalidate squad cache on successful rename
-	h.InvalidateSquadCache()
+	h.InvalidateSquadCache(r.Context())
 
 	respondJSON(w, http.StatusOK, squad)
 }
@@ -942,6 +1356,110 @@ func (h *Handlers) GetUsersBySquad(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, models.ToUserResponses(users))
 }
 
+// SetSquadLead godoc
+// @Summary Set a squad's lead
+// @Description Makes an active user the squad's lead, adding them to the squad if needed. The previous lead stays on as a member. Squad leads can view their squad's tasks and time off.
+// @Tags Squads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Squad ID"
+// @Param body body models.SetSquadLeadRequest true "New lead"
+// @Success 200 {object} models.Squad "Updated squad"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Squad not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /squads/{id}/lead [put]
+func (h *Handlers) SetSquadLead(w http.ResponseWriter, r *http.Request) {
+	squad := h.requireManagedSquad(w, r)
+	if squad == nil {
+		return
+	}
+
+	var req models.SetSquadLeadRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	lead, err := h.userRepo.GetByID(r.Context(), req.UserID)
+	if err != nil || lead == nil || !lead.IsActive {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.squadRepo.SetLead(r.Context(), squad.ID, lead.ID); err != nil {
+		h.logger.LogError(r.Context(), "Failed to set squad lead", err, "squad_id", squad.ID, "user_id", lead.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to set squad lead")
+		return
+	}
+
+	// Membership changed as well as the lead
+	h.InvalidateSquadCache(r.Context())
+	h.InvalidateUserCache(r.Context())
+
+	squad.LeadID = &lead.ID
+	respondJSON(w, http.StatusOK, squad)
+}
+
+// ClearSquadLead godoc
+// @Summary Remove a squad's lead
+// @Description Makes the squad's lead a regular member
+// @Tags Squads
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Squad ID"
+// @Success 200 {object} models.Squad "Updated squad"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Squad not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /squads/{id}/lead [delete]
+func (h *Handlers) ClearSquadLead(w http.ResponseWriter, r *http.Request) {
+	squad := h.requireManagedSquad(w, r)
+	if squad == nil {
+		return
+	}
+
+	if err := h.squadRepo.ClearLead(r.Context(), squad.ID); err != nil {
+		h.logger.LogError(r.Context(), "Failed to clear squad lead", err, "squad_id", squad.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to remove squad lead")
+		return
+	}
+
+	h.InvalidateSquadCache(r.Context())
+
+	squad.LeadID = nil
+	respondJSON(w, http.StatusOK, squad)
+}
+
+// requireManagedSquad loads the squad from the URL for a user who may manage squads
+func (h *Handlers) requireManagedSquad(w http.ResponseWriter, r *http.Request) *models.Squad {
+	if requirePermission(w, r, authz.ActionSquadManage) == nil {
+		return nil
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid squad ID")
+		return nil
+	}
+
+	squad, err := h.squadRepo.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get squad", err, "squad_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to get squad")
+		return nil
+	}
+	if squad == nil {
+		respondError(w, http.StatusNotFound, "Squad not found")
+		return nil
+	}
+	return squad
+}
+
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
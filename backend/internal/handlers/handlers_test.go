@@ -4,12 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/cache"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
 )
 
 // Note: ctxWithUser and ctxWithUserFrom helpers are defined in helpers_test.go
@@ -18,7 +23,7 @@ func TestGetCurrentUser(t *testing.T) {
 	// Test only the unauthorized case since the authenticated case requires a database connection
 	// to load squads. Testing authenticated flow should be done with integration tests.
 	t.Run("returns unauthorized when no user in context", func(t *testing.T) {
-		h := New(nil, nil)
+		h := New(nil, nil, nil)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
 		rr := httptest.NewRecorder()
@@ -164,7 +169,7 @@ func TestCreateUser_Authorization(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			h := New(nil, nil)
+			h := New(nil, nil, nil)
 
 			body := `{"email":"new@example.com","first_name":"New","last_name":"User","role":"employee","department":"Engineering"}`
 			req := httptest.NewRequest(http.MethodPost, "/api/users", bytes.NewBufferString(body))
@@ -234,7 +239,7 @@ func TestDeleteUser_Authorization(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			h := New(nil, nil)
+			h := New(nil, nil, nil)
 
 			req := httptest.NewRequest(http.MethodDelete, "/api/users/3", nil)
 
@@ -263,8 +268,99 @@ func TestDeleteUser_Authorization(t *testing.T) {
 	_ = supervisorID
 }
 
+func TestRestoreUser(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 3, Email: "ada@example.com", Role: models.RoleEmployee, IsActive: true})
+	_ = userRepo.Delete(context.Background(), 3)
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		expectedStatus int
+	}{
+		{"supervisor cannot restore users", &models.User{ID: 1, Role: models.RoleSupervisor}, http.StatusForbidden},
+		{"admin restores deleted user", &models.User{ID: 1, Role: models.RoleAdmin}, http.StatusOK},
+		{"user is no longer deleted", &models.User{ID: 1, Role: models.RoleAdmin}, http.StatusNotFound},
+	}
+
+	h := New(userRepo, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/users/3/restore", nil)
+			req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), tt.currentUser), "id", "3"))
+
+			rr := httptest.NewRecorder()
+			h.RestoreUser(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("RestoreUser() status = %v, want %v: %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+		})
+	}
+
+	if user := userRepo.Users[3]; user == nil || !user.IsActive || user.IsDeleted() {
+		t.Errorf("user = %+v, want restored and active", user)
+	}
+}
+
+func TestRestoreUser_KeepsDeactivatedUserInactive(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 3, Email: "ada@example.com", Role: models.RoleEmployee, IsActive: false})
+	_ = userRepo.Delete(context.Background(), 3)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/3/restore", nil)
+	req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleAdmin}), "id", "3"))
+	rr := httptest.NewRecorder()
+	New(userRepo, nil, nil).RestoreUser(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("RestoreUser() status = %v, want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if user := userRepo.Users[3]; user == nil || user.IsActive || user.IsDeleted() {
+		t.Errorf("user = %+v, want restored and still deactivated", user)
+	}
+}
+
+func TestGetAllUsers_ETag(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 3, Email: "ada@example.com", Role: models.RoleEmployee, IsActive: true, UpdatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)})
+	c := cache.New(time.Minute, time.Minute)
+	t.Cleanup(c.Stop)
+	h := NewWithCache(userRepo, mocks.NewMockSquadRepository(), c)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		h.GetAllUsers(rr, req)
+		return rr
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("first request: status = %d, ETag = %q", first.Code, etag)
+	}
+
+	if rr := get(etag); rr.Code != http.StatusNotModified || rr.Body.Len() != 0 {
+		t.Errorf("matching If-None-Match: status = %d, body = %q, want 304 with no body", rr.Code, rr.Body.String())
+	}
+
+	userRepo.Users[3].UpdatedAt = userRepo.Users[3].UpdatedAt.Add(time.Minute)
+	h.InvalidateUserCache(context.Background())
+	rr := get(etag)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("after update: status = %d, want 200", rr.Code)
+	}
+	if rr.Header().Get("ETag") == etag {
+		t.Error("after update: ETag did not change")
+	}
+}
+
 func TestUpdateUser_InvalidID(t *testing.T) {
-	h := New(nil, nil)
+	h := New(nil, nil, nil)
 
 	req := httptest.NewRequest(http.MethodPut, "/api/users/invalid", nil)
 
@@ -331,3 +427,212 @@ func TestUploadAvatarBase64_InvalidFormat(t *testing.T) {
 		t.Errorf("UploadAvatarBase64() with invalid format status = %v, want %v", rr.Code, http.StatusBadRequest)
 	}
 }
+
+func TestSquadLead(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.Users[5] = &models.User{ID: 5, Role: models.RoleEmployee, IsActive: true}
+	userRepo.Users[6] = &models.User{ID: 6, Role: models.RoleEmployee, IsActive: false}
+	squadRepo := mocks.NewMockSquadRepository()
+	squadRepo.AddSquad(&models.Squad{ID: 10, Name: "Platform"})
+	h := New(userRepo, squadRepo, nil)
+
+	supervisor := &models.User{ID: 1, Role: models.RoleSupervisor}
+	setLead := func(user *models.User, squadID string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/squads/"+squadID+"/lead", bytes.NewBufferString(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", squadID)
+		req = req.WithContext(context.WithValue(ctxWithUserFrom(req.Context(), user), chi.RouteCtxKey, rctx))
+		rr := httptest.NewRecorder()
+		h.SetSquadLead(rr, req)
+		return rr
+	}
+
+	tests := []struct {
+		name    string
+		user    *models.User
+		squadID string
+		body    string
+		want    int
+	}{
+		{"employee", &models.User{ID: 5, Role: models.RoleEmployee}, "10", `{"user_id":5}`, http.StatusForbidden},
+		{"missing user", supervisor, "10", `{}`, http.StatusBadRequest},
+		{"inactive user", supervisor, "10", `{"user_id":6}`, http.StatusBadRequest},
+		{"unknown squad", supervisor, "99", `{"user_id":5}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := setLead(tt.user, tt.squadID, tt.body); rr.Code != tt.want {
+				t.Errorf("SetSquadLead() status = %d, want %d: %s", rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+
+	rr := setLead(supervisor, "10", `{"user_id":5}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("SetSquadLead() status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	var squad models.Squad
+	if err := json.NewDecoder(rr.Body).Decode(&squad); err != nil {
+		t.Fatal(err)
+	}
+	if squad.LeadID == nil || *squad.LeadID != 5 {
+		t.Errorf("lead_id = %v, want 5", squad.LeadID)
+	}
+	if got := squadRepo.UserSquads[5]; len(got) != 1 || got[0] != 10 {
+		t.Errorf("lead's squads = %v, want [10]", got)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/squads/10/lead", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "10")
+	req = req.WithContext(context.WithValue(ctxWithUserFrom(req.Context(), supervisor), chi.RouteCtxKey, rctx))
+	rr = httptest.NewRecorder()
+	h.ClearSquadLead(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("ClearSquadLead() status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	if squadRepo.Squads[10].LeadID != nil {
+		t.Errorf("lead_id = %v after clearing, want nil", *squadRepo.Squads[10].LeadID)
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Role: models.RoleEmployee, Department: "Engineering", IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, FirstName: "Grace", LastName: "Hopper", Email: "grace@example.com", Role: models.RoleSupervisor, Department: "Engineering", IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, FirstName: "Adam", LastName: "Smith", Email: "adam@example.com", Role: models.RoleEmployee, Department: "Finance", IsActive: false})
+	h := New(userRepo, mocks.NewMockSquadRepository(), nil)
+
+	employee := &models.User{ID: 1, Role: models.RoleEmployee}
+	admin := &models.User{ID: 9, Role: models.RoleAdmin}
+
+	tests := []struct {
+		name    string
+		user    *models.User
+		query   string
+		want    int
+		wantIDs []int64
+	}{
+		{"query", employee, "?q=ada", http.StatusOK, []int64{1}},
+		{"department filter", employee, "?department=Engineering", http.StatusOK, []int64{1, 2}},
+		{"role filter", employee, "?q=a&role=supervisor", http.StatusOK, []int64{2}},
+		{"inactive as admin", admin, "?q=ada&active=false", http.StatusOK, []int64{3}},
+		{"inactive as employee", employee, "?active=false", http.StatusForbidden, nil},
+		{"invalid role", employee, "?role=owner", http.StatusBadRequest, nil},
+		{"invalid squad", employee, "?squad=abc", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/users/search"+tt.query, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), tt.user))
+			rr := httptest.NewRecorder()
+			h.SearchUsers(rr, req)
+
+			if rr.Code != tt.want {
+				t.Fatalf("SearchUsers() status = %d, want %d: %s", rr.Code, tt.want, rr.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data       []models.UserResponse `json:"data"`
+				Pagination PaginationMetadata    `json:"pagination"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			var ids []int64
+			for _, u := range resp.Data {
+				ids = append(ids, u.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			if resp.Pagination.Total != len(tt.wantIDs) {
+				t.Errorf("total = %d, want %d", resp.Pagination.Total, len(tt.wantIDs))
+			}
+		})
+	}
+}
+
+func TestOffboardAndReactivateUser(t *testing.T) {
+	supervisorID := int64(2)
+	jiraAccountID := "jira-3"
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, Role: models.RoleSupervisor, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, Role: models.RoleEmployee, SupervisorID: &supervisorID, JiraAccountID: &jiraAccountID, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 4, Role: models.RoleEmployee, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 5, Role: models.RoleEmployee, IsActive: false})
+	h := New(userRepo, mocks.NewMockSquadRepository(), nil)
+
+	do := func(handler http.HandlerFunc, currentUserID int64, id string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/users/"+id, bytes.NewBufferString(body))
+		ctx := chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[currentUserID]), "id", id)
+		rr := httptest.NewRecorder()
+		handler(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	rejected := []struct {
+		name          string
+		currentUserID int64
+		id            string
+		body          string
+		want          int
+	}{
+		{"employee", 4, "3", `{}`, http.StatusForbidden},
+		{"supervisor of someone else's report", 2, "4", `{}`, http.StatusForbidden},
+		{"yourself", 1, "1", `{}`, http.StatusBadRequest},
+		{"already inactive", 1, "5", `{}`, http.StatusBadRequest},
+		{"future termination date", 1, "3", `{"termination_date":"2999-01-01"}`, http.StatusBadRequest},
+		{"inactive assignee", 1, "3", `{"reassign_tasks_to_id":5}`, http.StatusBadRequest},
+		{"assignee is the departing user", 1, "3", `{"reassign_tasks_to_id":3}`, http.StatusBadRequest},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := do(h.OffboardUser, tt.currentUserID, tt.id, tt.body); rr.Code != tt.want {
+				t.Errorf("OffboardUser() status = %d, want %d: %s", rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+
+	rr := do(h.OffboardUser, 2, "3", `{"termination_date":"2024-06-30"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("OffboardUser() status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var result models.OffboardResult
+	if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.ReassignedToID == nil || *result.ReassignedToID != supervisorID {
+		t.Errorf("tasks reassigned to %v, want the supervisor %d", result.ReassignedToID, supervisorID)
+	}
+	if !result.JiraMappingRevoked {
+		t.Error("expected the Jira mapping to be revoked")
+	}
+	offboarded := userRepo.Users[3]
+	if offboarded.IsActive || offboarded.JiraAccountID != nil || offboarded.TerminationDate == nil || offboarded.TerminationDate.Format("2006-01-02") != "2024-06-30" {
+		t.Errorf("offboarded user = %+v", offboarded)
+	}
+
+	if rr := do(h.ReactivateUser, 4, "3", ""); rr.Code != http.StatusForbidden {
+		t.Errorf("ReactivateUser() as employee status = %d, want %d", rr.Code, http.StatusForbidden)
+	}
+	if rr := do(h.ReactivateUser, 1, "4", ""); rr.Code != http.StatusBadRequest {
+		t.Errorf("ReactivateUser() active user status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+	rr = do(h.ReactivateUser, 2, "3", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("ReactivateUser() status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var reactivated models.UserResponse
+	if err := json.NewDecoder(rr.Body).Decode(&reactivated); err != nil {
+		t.Fatal(err)
+	}
+	if !reactivated.IsActive || reactivated.TerminationDate != nil {
+		t.Errorf("reactivated user is_active = %v, termination_date = %v", reactivated.IsActive, reactivated.TerminationDate)
+	}
+}
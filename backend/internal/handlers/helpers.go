@@ -1,14 +1,24 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/fnv"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
 	"github.com/smith-dallin/manager-dashboard/internal/middleware"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
 )
 
 // parseIDParam parses an ID from URL parameters
@@ -27,45 +37,56 @@ func requireAuth(w http.ResponseWriter, r *http.Request) *models.User {
 	return user
 }
 
-// requireSupervisor ensures the current user is a supervisor or admin
-func requireSupervisor(w http.ResponseWriter, r *http.Request) *models.User {
+// requirePermission ensures the current user holds a permission for the action, in any scope.
+// Handlers acting on a specific resource still check it with authz.Can.
+func requirePermission(w http.ResponseWriter, r *http.Request, action authz.Action) *models.User {
 	user := requireAuth(w, r)
 	if user == nil {
 		return nil
 	}
-	if !user.IsSupervisorOrAdmin() {
-		respondErrorWithCode(w, http.StatusForbidden, string(apperrors.CodeSupervisorRequired), "Forbidden: supervisor access required")
+	if !authz.Can(user, action, nil) {
+		respondErrorWithCode(w, http.StatusForbidden, string(apperrors.CodePermissionDenied), "Forbidden: missing permission "+string(action))
 		return nil
 	}
 	return user
 }
 
-// requireAdmin ensures the current user is an admin
-func requireAdmin(w http.ResponseWriter, r *http.Request) *models.User {
+// requireJiraAccess ensures the current user may see Jira data
+func requireJiraAccess(w http.ResponseWriter, r *http.Request) *models.User {
 	user := requireAuth(w, r)
 	if user == nil {
 		return nil
 	}
-	if !user.IsAdmin() {
-		respondErrorWithCode(w, http.StatusForbidden, string(apperrors.CodeAdminRequired), "Forbidden: admin access required")
+	if !authz.Can(user, authz.ActionIntegrationView, nil) {
+		respondErrorWithCode(w, http.StatusForbidden, string(apperrors.CodeSupervisorRequired), "Jira integration is only available for supervisors and admins")
 		return nil
 	}
 	return user
 }
 
-// requireJiraAccess ensures the current user has access to Jira integration (supervisor or admin)
-func requireJiraAccess(w http.ResponseWriter, r *http.Request) *models.User {
+// requireGitHubAccess ensures the current user may see GitHub data
+func requireGitHubAccess(w http.ResponseWriter, r *http.Request) *models.User {
 	user := requireAuth(w, r)
 	if user == nil {
 		return nil
 	}
-	if !user.IsSupervisorOrAdmin() {
-		respondErrorWithCode(w, http.StatusForbidden, string(apperrors.CodeSupervisorRequired), "Jira integration is only available for supervisors and admins")
+	if !authz.Can(user, authz.ActionIntegrationView, nil) {
+		respondErrorWithCode(w, http.StatusForbidden, string(apperrors.CodeSupervisorRequired), "GitHub integration is only available for supervisors and admins")
 		return nil
 	}
 	return user
 }
 
+// leadsSquadOf reports whether the user leads a squad the member belongs to.
+// It's false when squadRepo is nil or the lookup fails.
+func leadsSquadOf(ctx context.Context, squadRepo repository.SquadRepository, user *models.User, memberID int64) bool {
+	if squadRepo == nil || user.ID == memberID {
+		return false
+	}
+	isLead, err := squadRepo.IsSquadLeadOf(ctx, user.ID, memberID)
+	return err == nil && isLead
+}
+
 // decodeJSON decodes JSON request body into the provided struct
 func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
 	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
@@ -82,29 +103,41 @@ type Validator interface {
 
 func validateRequest(w http.ResponseWriter, v Validator) bool {
 	if err := v.Validate(); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request: please check all required fields")
+		apperrors.WriteAPIError(w, &apperrors.APIError{
+			Message: "Invalid request: please check all required fields",
+			Status:  http.StatusBadRequest,
+			Code:    apperrors.CodeValidationFailed,
+			Details: map[string]interface{}{"reason": err.Error()},
+		})
 		return false
 	}
 	return true
 }
 
-// respondError sends an error response with the given status code
+// respondError sends an error response with the given status code and the default code for it
 func respondError(w http.ResponseWriter, status int, message string) {
 	respondErrorWithCode(w, status, "", message)
 }
 
 // respondErrorWithCode sends an error response with a machine-readable error code
 func respondErrorWithCode(w http.ResponseWriter, status int, code string, message string) {
-	response := map[string]interface{}{
-		"error":   message,
-		"status":  status,
-	}
-	if code != "" {
-		response["code"] = code
+	apperrors.WriteAPIError(w, &apperrors.APIError{Message: message, Status: status, Code: apperrors.ErrorCode(code)})
+}
+
+// respondAppError sends the error response for err; errors other than AppErrors are reported as
+// internal errors without their message
+func respondAppError(w http.ResponseWriter, err error) {
+	apperrors.Write(w, err)
+}
+
+// respondRepositoryError sends the error response for an error from a repository acting on
+// resource, e.g. a 404 for a missing row or a 409 for a duplicate. Internal errors are logged.
+func respondRepositoryError(w http.ResponseWriter, r *http.Request, err error, resource string) {
+	appErr := apperrors.FromRepository(err, resource)
+	if appErr.HTTPStatus() >= http.StatusInternalServerError {
+		logger.FromContext(r.Context()).LogError(r.Context(), "Repository error", err, "resource", resource)
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(response)
+	respondAppError(w, appErr)
 }
 
 // Pagination holds pagination parameters
@@ -208,3 +241,96 @@ func paginateSlice[T any](items []T, p Pagination) ([]T, int) {
 func shouldPaginate(r *http.Request) bool {
 	return r.URL.Query().Has("page") || r.URL.Query().Has("per_page")
 }
+
+// tableDownload is a list requested as a file (?format=csv or xlsx) rather than JSON
+type tableDownload[T any] struct {
+	format  models.TableFormat
+	columns []services.ExportColumn[T]
+}
+
+// parseTableDownload reads the format and columns (comma-separated, defaulting to all) query
+// parameters. It returns nil when the list was requested as JSON, and writes a 400 and returns
+// false for an unknown format or column.
+func parseTableDownload[T any](w http.ResponseWriter, r *http.Request, all []services.ExportColumn[T]) (*tableDownload[T], bool) {
+	format := models.TableFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		return nil, true
+	}
+	if !models.ValidTableFormats[format] {
+		respondError(w, http.StatusBadRequest, "format must be 'csv' or 'xlsx'")
+		return nil, false
+	}
+
+	var names []string
+	if c := r.URL.Query().Get("columns"); c != "" {
+		names = strings.Split(c, ",")
+	}
+	columns, err := services.SelectExportColumns(all, names)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid columns: %v", err))
+		return nil, false
+	}
+	return &tableDownload[T]{format: format, columns: columns}, true
+}
+
+// respond streams rows as an attachment named after the list and today's date. Rows are written
+// after the status, so a failure part way through can only be logged.
+func (d *tableDownload[T]) respond(w http.ResponseWriter, r *http.Request, name string, rows []T) {
+	w.Header().Set("Content-Type", services.TableContentType(d.format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, name, time.Now().Format("2006-01-02"), d.format))
+	w.WriteHeader(http.StatusOK)
+
+	tw, err := services.NewTableWriter(w, d.format)
+	if err == nil {
+		err = services.WriteTable(tw, d.columns, rows)
+	}
+	if err != nil {
+		logger.FromContext(r.Context()).LogError(r.Context(), "Failed to stream table download", err, "list", name, "format", d.format)
+	}
+}
+
+// etagBuilder hashes the values that identify a version of a response, such as the IDs and
+// updated_at timestamps of the rows it lists
+type etagBuilder struct {
+	h hash.Hash64
+}
+
+func newETagBuilder() *etagBuilder {
+	return &etagBuilder{h: fnv.New64a()}
+}
+
+// add mixes values into the tag. Times are added by their instant, so the same row read twice
+// always hashes the same.
+func (b *etagBuilder) add(values ...interface{}) *etagBuilder {
+	for _, v := range values {
+		switch v := v.(type) {
+		case time.Time:
+			fmt.Fprintf(b.h, "%d|", v.UnixNano())
+		case *int64:
+			if v == nil {
+				fmt.Fprint(b.h, "nil|")
+			} else {
+				fmt.Fprintf(b.h, "%d|", *v)
+			}
+		default:
+			fmt.Fprintf(b.h, "%v|", v)
+		}
+	}
+	return b
+}
+
+// weak returns the weak entity tag for everything added so far
+func (b *etagBuilder) weak() string {
+	return fmt.Sprintf(`W/"%x"`, b.h.Sum64())
+}
+
+// notModified sets the ETag header and, when the client already has that version (If-None-Match),
+// writes 304 Not Modified. Returns true if the response has been written.
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	if !middleware.ETagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
@@ -3,10 +3,18 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
 	"github.com/smith-dallin/manager-dashboard/internal/middleware"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 )
@@ -344,7 +352,8 @@ func TestRequireAuth(t *testing.T) {
 	})
 }
 
-func TestRequireSupervisor(t *testing.T) {
+// A permission held for the team only is enough, as for supervisors
+func TestRequirePermission_TeamScope(t *testing.T) {
 	tests := []struct {
 		name           string
 		user           *models.User
@@ -385,7 +394,7 @@ func TestRequireSupervisor(t *testing.T) {
 			}
 			rr := httptest.NewRecorder()
 
-			user := requireSupervisor(rr, req)
+			user := requirePermission(rr, req, authz.ActionTeamView)
 
 			if tt.expectedUser && user == nil {
 				t.Error("expected user, got nil")
@@ -400,7 +409,7 @@ func TestRequireSupervisor(t *testing.T) {
 	}
 }
 
-func TestRequireAdmin(t *testing.T) {
+func TestRequirePermission(t *testing.T) {
 	tests := []struct {
 		name           string
 		user           *models.User
@@ -441,7 +450,7 @@ func TestRequireAdmin(t *testing.T) {
 			}
 			rr := httptest.NewRecorder()
 
-			user := requireAdmin(rr, req)
+			user := requirePermission(rr, req, authz.ActionAuditView)
 
 			if tt.expectedUser && user == nil {
 				t.Error("expected user, got nil")
@@ -568,3 +577,124 @@ func TestRespondJSON_Helpers(t *testing.T) {
 		t.Errorf("response = %+v, want {Name:test Count:42}", response)
 	}
 }
+
+func TestRespondError_APIError(t *testing.T) {
+	rr := httptest.NewRecorder()
+	rr.Header().Set("X-Request-ID", "req-42")
+	respondError(rr, http.StatusNotFound, "Meeting not found")
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+	var body apperrors.APIError
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Message != "Meeting not found" || body.Status != http.StatusNotFound ||
+		body.Code != apperrors.CodeNotFound || body.RequestID != "req-42" {
+		t.Errorf("unexpected error body: %+v", body)
+	}
+}
+
+func TestRespondRepositoryError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   apperrors.ErrorCode
+	}{
+		{"missing row", fmt.Errorf("failed to get user by ID: %w", pgx.ErrNoRows), http.StatusNotFound, apperrors.CodeNotFound},
+		{"duplicate", &pgconn.PgError{Code: "23505"}, http.StatusConflict, apperrors.CodeAlreadyExists},
+		{"database failure", errors.New("connection refused"), http.StatusInternalServerError, apperrors.CodeDatabaseError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			rr := httptest.NewRecorder()
+			respondRepositoryError(rr, req, tt.err, "User")
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.expectedStatus)
+			}
+			var body apperrors.APIError
+			if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if body.Code != tt.expectedCode {
+				t.Errorf("code = %s, want %s", body.Code, tt.expectedCode)
+			}
+			if strings.Contains(body.Message, "connection refused") {
+				t.Errorf("repository error leaked into the response: %q", body.Message)
+			}
+		})
+	}
+}
+
+func TestValidateRequest_Details(t *testing.T) {
+	rr := httptest.NewRecorder()
+	if validateRequest(rr, &models.CreateUserRequest{}) {
+		t.Fatal("expected an empty request to be invalid")
+	}
+
+	var body apperrors.APIError
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Code != apperrors.CodeValidationFailed || body.Details["reason"] == nil {
+		t.Errorf("unexpected error body: %+v", body)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	etag := newETagBuilder().add(int64(7), time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)).weak()
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{"no header", "", false},
+		{"same tag", etag, true},
+		{"strong form of the tag", strings.TrimPrefix(etag, "W/"), true},
+		{"tag in a list", `W/"other", ` + etag, true},
+		{"wildcard", "*", true},
+		{"different tag", `W/"other"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rr := httptest.NewRecorder()
+
+			if got := notModified(rr, req, etag); got != tt.want {
+				t.Errorf("notModified() = %v, want %v", got, tt.want)
+			}
+			if rr.Header().Get("ETag") != etag {
+				t.Errorf("ETag header = %q, want %q", rr.Header().Get("ETag"), etag)
+			}
+			if tt.want && rr.Code != http.StatusNotModified {
+				t.Errorf("status = %d, want 304", rr.Code)
+			}
+		})
+	}
+}
+
+func TestETagBuilder(t *testing.T) {
+	at := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	lead := int64(4)
+
+	base := newETagBuilder().add(1, at, &lead).weak()
+	if again := newETagBuilder().add(1, at.In(time.FixedZone("EST", -5*3600)), &lead).weak(); again != base {
+		t.Errorf("same instant in another zone: %s, want %s", again, base)
+	}
+	if changed := newETagBuilder().add(1, at.Add(time.Second), &lead).weak(); changed == base {
+		t.Error("later updated_at produced the same tag")
+	}
+	if noLead := newETagBuilder().add(1, at, (*int64)(nil)).weak(); noLead == base {
+		t.Error("nil pointer produced the same tag")
+	}
+}
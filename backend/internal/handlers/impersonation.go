@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// impersonationDuration is how long an impersonation session lasts before the admin must start
+// another
+const impersonationDuration = time.Hour
+
+// ImpersonationHandlers lets admins act as another user to reproduce what they see
+type ImpersonationHandlers struct {
+	impersonationRepo repository.ImpersonationRepository
+	userRepo          repository.UserRepository
+	logger            *logger.Logger
+}
+
+// NewImpersonationHandlers creates a new impersonation handlers instance
+func NewImpersonationHandlers(impersonationRepo repository.ImpersonationRepository, userRepo repository.UserRepository) *ImpersonationHandlers {
+	return &ImpersonationHandlers{
+		impersonationRepo: impersonationRepo,
+		userRepo:          userRepo,
+		logger:            logger.Default().WithComponent("impersonation-handlers"),
+	}
+}
+
+// ImpersonationResponse describes an impersonation session: who is acting as whom, until when,
+// and the header that makes a request part of it
+type ImpersonationResponse struct {
+	Session models.ImpersonationSession `json:"session"`
+	Admin   *models.User                `json:"admin"`
+	User    *models.User                `json:"user"`
+	// Requests sent with this header set to the session ID act as the user
+	Header string `json:"header"`
+}
+
+// CurrentImpersonationResponse says whether the current request impersonates someone
+type CurrentImpersonationResponse struct {
+	Impersonating bool                   `json:"impersonating"`
+	Impersonation *ImpersonationResponse `json:"impersonation,omitempty"`
+}
+
+// requireImpersonator returns the authenticated admin, looking past any impersonation so an admin
+// acting as someone can still start or end sessions
+func requireImpersonator(w http.ResponseWriter, r *http.Request) *models.User {
+	admin := middleware.GetRealUserFromContext(r.Context())
+	if admin == nil {
+		admin = requireAuth(w, r)
+		if admin == nil {
+			return nil
+		}
+	}
+	if !authz.Can(admin, authz.ActionUserImpersonate, nil) {
+		respondErrorWithCode(w, http.StatusForbidden, string(apperrors.CodePermissionDenied), "Forbidden: missing permission "+string(authz.ActionUserImpersonate))
+		return nil
+	}
+	return admin
+}
+
+// StartImpersonation godoc
+// @Summary Start impersonating a user
+// @Description Starts a session, lasting an hour, in which requests sent with the X-Impersonation-Session-Id header act as the user, to reproduce what they see. The admin is recorded as the actor of every change made in it, and its audit events carry the session ID. Starting a session ends the admin's previous one. Admin only.
+// @Tags Impersonation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path int true "User ID"
+// @Param body body models.StartImpersonationRequest false "Why the user is being impersonated"
+// @Success 201 {object} ImpersonationResponse "Impersonation session"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/impersonate/{userId} [post]
+func (h *ImpersonationHandlers) StartImpersonation(w http.ResponseWriter, r *http.Request) {
+	admin := requireImpersonator(w, r)
+	if admin == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "userId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	if userID == admin.ID {
+		respondError(w, http.StatusBadRequest, "Cannot impersonate yourself")
+		return
+	}
+
+	var req models.StartImpersonationRequest
+	if r.ContentLength != 0 && !decodeJSON(w, r, &req) {
+		return
+	}
+	if !validateRequest(w, &req) {
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	session := &models.ImpersonationSession{
+		AdminID:   admin.ID,
+		UserID:    user.ID,
+		Reason:    req.Reason,
+		ExpiresAt: time.Now().Add(impersonationDuration),
+	}
+	if err := h.impersonationRepo.Start(r.Context(), session); err != nil {
+		h.logger.LogError(r.Context(), "Failed to start impersonation", err, "user_id", userID)
+		respondError(w, http.StatusInternalServerError, "Failed to start impersonation")
+		return
+	}
+
+	h.logger.Info("Impersonation started", "session_id", session.ID, "admin_id", admin.ID, "user_id", user.ID)
+	respondJSON(w, http.StatusCreated, ImpersonationResponse{
+		Session: *session,
+		Admin:   admin,
+		User:    user,
+		Header:  middleware.ImpersonationSessionHeader,
+	})
+}
+
+// StopImpersonation godoc
+// @Summary Stop impersonating a user
+// @Description Ends the admin's active impersonation session for the user; requests still sending its ID are rejected. Admin only.
+// @Tags Impersonation
+// @Security BearerAuth
+// @Param userId path int true "User ID"
+// @Success 204 "Impersonation ended"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "No active impersonation session"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/impersonate/{userId} [delete]
+func (h *ImpersonationHandlers) StopImpersonation(w http.ResponseWriter, r *http.Request) {
+	admin := requireImpersonator(w, r)
+	if admin == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "userId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	ended, err := h.impersonationRepo.End(r.Context(), admin.ID, userID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to end impersonation", err, "user_id", userID)
+		respondError(w, http.StatusInternalServerError, "Failed to stop impersonation")
+		return
+	}
+	if ended == 0 {
+		respondError(w, http.StatusNotFound, "No active impersonation session")
+		return
+	}
+
+	h.logger.Info("Impersonation stopped", "admin_id", admin.ID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCurrentImpersonation godoc
+// @Summary Get the current impersonation
+// @Description Returns whether the request acts as another user and, if so, who is impersonating whom and until when
+// @Tags Impersonation
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} CurrentImpersonationResponse "Current impersonation"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /me/impersonation [get]
+func (h *ImpersonationHandlers) GetCurrentImpersonation(w http.ResponseWriter, r *http.Request) {
+	user := requireAuth(w, r)
+	if user == nil {
+		return
+	}
+
+	response := CurrentImpersonationResponse{Impersonating: middleware.IsImpersonating(r.Context())}
+	if session := middleware.GetImpersonationSessionFromContext(r.Context()); session != nil {
+		response.Impersonation = &ImpersonationResponse{
+			Session: *session,
+			Admin:   middleware.GetRealUserFromContext(r.Context()),
+			User:    user,
+			Header:  middleware.ImpersonationSessionHeader,
+		}
+	}
+	respondJSON(w, http.StatusOK, response)
+}
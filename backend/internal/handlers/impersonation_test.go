@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+// newImpersonationTestHandlers sets up an admin (1), an employee (2), and a supervisor (3)
+func newImpersonationTestHandlers() (*ImpersonationHandlers, *mocks.MockImpersonationRepository) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, FirstName: "Ada", Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, FirstName: "Hal", Role: models.RoleEmployee, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, FirstName: "Sue", Role: models.RoleSupervisor, IsActive: true})
+
+	repo := mocks.NewMockImpersonationRepository()
+	return NewImpersonationHandlers(repo, userRepo), repo
+}
+
+func TestImpersonationHandlers_StartImpersonation(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		targetID       string
+		body           string
+		expectedStatus int
+	}{
+		{"admin impersonates employee", &models.User{ID: 1, Role: models.RoleAdmin}, "2", `{"reason":"Ticket 123"}`, http.StatusCreated},
+		{"admin impersonates without a reason", &models.User{ID: 1, Role: models.RoleAdmin}, "2", "", http.StatusCreated},
+		{"supervisor forbidden", &models.User{ID: 3, Role: models.RoleSupervisor}, "2", "", http.StatusForbidden},
+		{"admin impersonates self", &models.User{ID: 1, Role: models.RoleAdmin}, "1", "", http.StatusBadRequest},
+		{"missing user", &models.User{ID: 1, Role: models.RoleAdmin}, "99", "", http.StatusNotFound},
+		{"reason too long", &models.User{ID: 1, Role: models.RoleAdmin}, "2", `{"reason":"` + strings.Repeat("x", 501) + `"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, repo := newImpersonationTestHandlers()
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/impersonate/"+tt.targetID, strings.NewReader(tt.body))
+			req = req.WithContext(ctxWithUserFrom(chiCtxWithID(req.Context(), "userId", tt.targetID), tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.StartImpersonation(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+
+			var resp ImpersonationResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Session.AdminID != 1 || resp.Session.UserID != 2 || resp.Header != middleware.ImpersonationSessionHeader {
+				t.Errorf("response = %+v, want a session of admin 1 impersonating user 2", resp)
+			}
+			if !repo.Sessions[resp.Session.ID].IsActive() {
+				t.Error("expected the session to be active")
+			}
+		})
+	}
+}
+
+func TestImpersonationHandlers_StartImpersonation_EndsPreviousSession(t *testing.T) {
+	h, repo := newImpersonationTestHandlers()
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	for _, target := range []string{"2", "3"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/impersonate/"+target, nil)
+		req = req.WithContext(ctxWithUserFrom(chiCtxWithID(req.Context(), "userId", target), admin))
+		rr := httptest.NewRecorder()
+		h.StartImpersonation(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+	}
+
+	if repo.Sessions[1].IsActive() || !repo.Sessions[2].IsActive() {
+		t.Error("expected only the latest session to be active")
+	}
+}
+
+func TestImpersonationHandlers_StartImpersonation_WhileImpersonating(t *testing.T) {
+	h, _ := newImpersonationTestHandlers()
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	employee := &models.User{ID: 2, Role: models.RoleEmployee}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/impersonate/3", nil)
+	ctx := context.WithValue(chiCtxWithID(req.Context(), "userId", "3"), middleware.RealUserContextKey, admin)
+	req = req.WithContext(ctxWithUserFrom(ctx, employee))
+	rr := httptest.NewRecorder()
+	h.StartImpersonation(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Errorf("expected the admin, not the impersonated employee, to be checked; got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestImpersonationHandlers_StopImpersonation(t *testing.T) {
+	h, repo := newImpersonationTestHandlers()
+	_ = repo.Start(context.Background(), &models.ImpersonationSession{AdminID: 1, UserID: 2, ExpiresAt: time.Now().Add(time.Hour)})
+
+	stop := func(target string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/admin/impersonate/"+target, nil)
+		req = req.WithContext(ctxWithUserFrom(chiCtxWithID(req.Context(), "userId", target), &models.User{ID: 1, Role: models.RoleAdmin}))
+		rr := httptest.NewRecorder()
+		h.StopImpersonation(rr, req)
+		return rr.Code
+	}
+
+	if code := stop("2"); code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", code)
+	}
+	if repo.Sessions[1].IsActive() {
+		t.Error("expected the session to be ended")
+	}
+	if code := stop("2"); code != http.StatusNotFound {
+		t.Errorf("expected status 404 stopping an ended session, got %d", code)
+	}
+}
+
+func TestImpersonationHandlers_GetCurrentImpersonation(t *testing.T) {
+	h, _ := newImpersonationTestHandlers()
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	employee := &models.User{ID: 2, Role: models.RoleEmployee}
+	session := &models.ImpersonationSession{ID: 5, AdminID: 1, UserID: 2, ExpiresAt: time.Now().Add(time.Hour)}
+
+	ctx := context.WithValue(ctxWithUser(employee), middleware.RealUserContextKey, admin)
+	ctx = context.WithValue(ctx, middleware.ImpersonationContextKey, true)
+	ctx = context.WithValue(ctx, middleware.ImpersonationSessionContextKey, session)
+	req := httptest.NewRequest(http.MethodGet, "/api/me/impersonation", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+	h.GetCurrentImpersonation(rr, req)
+
+	var resp CurrentImpersonationResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !resp.Impersonating || resp.Impersonation == nil || resp.Impersonation.Admin.ID != 1 || resp.Impersonation.User.ID != 2 {
+		t.Errorf("response = %+v, want admin 1 impersonating user 2", resp)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/me/impersonation", nil).WithContext(ctxWithUser(employee))
+	rr = httptest.NewRecorder()
+	h.GetCurrentImpersonation(rr, req)
+	resp = CurrentImpersonationResponse{}
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	if resp.Impersonating || resp.Impersonation != nil {
+		t.Errorf("response = %+v, want no impersonation", resp)
+	}
+}
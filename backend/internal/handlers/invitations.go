@@ -1,11 +1,12 @@
 package handlers
 
 import (
-	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
 	"github.com/smith-dallin/manager-dashboard/internal/logger"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/repository"
@@ -16,6 +17,9 @@ type InvitationHandlers struct {
 	invitationRepo repository.InvitationRepository
 	userRepo       repository.UserRepository
 	emailService   *services.EmailService
+	onboarding     *services.OnboardingService
+	notifications  *services.NotificationService
+	orgRepo        repository.OrganizationRepository
 	logger         *logger.Logger
 }
 
@@ -37,9 +41,21 @@ func NewInvitationHandlersWithLogger(invitationRepo repository.InvitationReposit
 	}
 }
 
+// WithNotifications tells inviters in-app when their invitations are accepted
+func (h *InvitationHandlers) WithNotifications(notifications *services.NotificationService) *InvitationHandlers {
+	h.notifications = notifications
+	return h
+}
+
+// WithInvitationDomains limits invitations to the email domains each organization configures
+func (h *InvitationHandlers) WithInvitationDomains(orgRepo repository.OrganizationRepository) *InvitationHandlers {
+	h.orgRepo = orgRepo
+	return h
+}
+
 // CreateInvitation godoc
 // @Summary Create a new invitation
-// @Description Creates a new invitation to onboard a user. Admin only.
+// @Description Creates a new invitation to onboard a user. Employees can be invited with their supervisor, title, start date, and squads, which they start with once they accept. If the organization limits invitations to some email domains, only guests can be invited from others. Admin only.
 // @Tags Invitations
 // @Accept json
 // @Produce json
@@ -53,7 +69,7 @@ func NewInvitationHandlersWithLogger(invitationRepo repository.InvitationReposit
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /invitations [post]
 func (h *InvitationHandlers) CreateInvitation(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireAdmin(w, r)
+	currentUser := requirePermission(w, r, authz.ActionInvitationManage)
 	if currentUser == nil {
 		return
 	}
@@ -63,32 +79,129 @@ func (h *InvitationHandlers) CreateInvitation(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Validate the request
-	if err := req.Validate(); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid invitation: please check all required fields")
+	domains, err := h.invitationDomains(r, currentUser)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create invitation")
+		return
+	}
+
+	invitation, inviteErr := h.invite(r, currentUser, &req, domains)
+	if inviteErr != nil {
+		respondError(w, inviteErr.status, inviteErr.message)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, invitation.ToInvitationResponse())
+}
+
+// BulkCreateInvitations godoc
+// @Summary Invite many people at once
+// @Description Invites every email with the same role and starting details, e.g. to onboard a whole team. Each email is invited on its own: the response reports every email in order, and invitations that succeed are kept when others fail. Admin only.
+// @Tags Invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.BulkInvitationRequest true "Invitations"
+// @Success 200 {object} models.BulkInvitationResponse "Result for each email"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden - admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /invitations/bulk [post]
+func (h *InvitationHandlers) BulkCreateInvitations(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionInvitationManage)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.BulkInvitationRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
 		return
 	}
 
+	domains, err := h.invitationDomains(r, currentUser)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create invitations")
+		return
+	}
+
+	resp := models.BulkInvitationResponse{Results: make([]models.BulkInvitationResult, 0, len(req.Emails))}
+	for _, email := range req.Emails {
+		invReq := req.Invitation(email)
+		result := models.BulkInvitationResult{Email: invReq.Email}
+		invitation, inviteErr := h.invite(r, currentUser, &invReq, domains)
+		if inviteErr != nil {
+			result.Error = inviteErr.message
+			resp.Failed++
+		} else {
+			result.Invitation = invitation.ToInvitationResponse()
+			resp.Created++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	h.logger.WithContext(r.Context()).Info("Bulk invitations created",
+		"created", resp.Created,
+		"failed", resp.Failed,
+	)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// inviteError is why an invitation couldn't be created, and the status to respond with
+type inviteError struct {
+	status  int
+	message string
+}
+
+// invite creates an invitation from currentUser. domains are the email domains the organization
+// limits invitations to.
+func (h *InvitationHandlers) invite(r *http.Request, currentUser *models.User, req *models.CreateInvitationRequest, domains []string) (*models.Invitation, *inviteError) {
+	if err := req.Validate(); err != nil {
+		return nil, &inviteError{http.StatusBadRequest, "Invalid invitation: " + err.Error()}
+	}
+
+	// Guests are external collaborators, so only members of the organization are limited to its domains
+	if req.Role != models.RoleGuest && !models.EmailInDomains(req.Email, domains) {
+		return nil, &inviteError{http.StatusBadRequest, "Invitations are limited to emails at " + strings.Join(domains, ", ")}
+	}
+
+	// Employees report to whoever invited them unless another supervisor is chosen
+	if req.Role == models.RoleEmployee && req.SupervisorID == nil {
+		req.SupervisorID = &currentUser.ID
+	}
+	if req.SupervisorID != nil && !canSupervise(r, h.userRepo, *req.SupervisorID) {
+		return nil, &inviteError{http.StatusBadRequest, "Invalid invitation: supervisor must be a supervisor or admin in your organization"}
+	}
+
 	// Check if user already exists with this email
 	existingUser, _ := h.userRepo.GetByEmail(r.Context(), req.Email)
 	if existingUser != nil {
-		respondError(w, http.StatusConflict, "A user with this email already exists")
-		return
+		return nil, &inviteError{http.StatusConflict, "A user with this email already exists"}
 	}
 
 	// Check if there's already a pending invitation for this email
 	existingInvitation, _ := h.invitationRepo.GetByEmail(r.Context(), req.Email)
 	if existingInvitation != nil {
-		respondError(w, http.StatusConflict, "A pending invitation already exists for this email")
-		return
+		return nil, &inviteError{http.StatusConflict, "A pending invitation already exists for this email"}
+	}
+
+	// Invitees are emailed in the inviter's language unless another is chosen
+	if req.Language == "" {
+		req.Language = currentUser.Language
+	}
+	if req.Language == "" {
+		req.Language = models.DefaultLanguage
 	}
 
-	// Create the invitation
-	invitation, err := h.invitationRepo.Create(r.Context(), &req, currentUser.ID)
+	// Create the invitation, queueing its email in the same transaction so it is never lost
+	var email *models.InvitationEmail
+	if h.emailService != nil {
+		email = &models.InvitationEmail{InviterName: fmt.Sprintf("%s %s", currentUser.FirstName, currentUser.LastName)}
+	}
+	invitation, err := h.invitationRepo.Create(r.Context(), req, currentUser.ID, email)
 	if err != nil {
 		h.logger.AuditFailure(r.Context(), logger.AuditActionCreate, "invitation", req.Email, currentUser.ID, currentUser.Email, err.Error())
-		respondError(w, http.StatusInternalServerError, "Failed to create invitation")
-		return
+		return nil, &inviteError{http.StatusInternalServerError, "Failed to create invitation"}
 	}
 
 	// Audit log: invitation created
@@ -102,37 +215,104 @@ func (h *InvitationHandlers) CreateInvitation(w http.ResponseWriter, r *http.Req
 		Details: map[string]any{
 			"invitee_email": req.Email,
 			"invitee_role":  req.Role,
+			"supervisor_id": req.SupervisorID,
 		},
 	})
 
-	// Send invitation email asynchronously (don't block API response)
-	// Use context.Background() since the email should complete independently of the HTTP request.
-	// The request context would be cancelled when the response is sent, causing premature cancellation.
-	if h.emailService != nil {
-		inviterName := fmt.Sprintf("%s %s", currentUser.FirstName, currentUser.LastName)
-		email := req.Email
-		token := invitation.Token
-		role := string(req.Role)
-		invitationID := invitation.ID
-		// Preserve request ID for log correlation in the background goroutine
-		asyncLogger := h.logger.WithContext(r.Context())
-		go func() {
-			if err := h.emailService.SendInvitation(context.Background(), email, token, role, inviterName); err != nil {
-				asyncLogger.Error("Failed to send invitation email",
-					"email", email,
-					"invitation_id", invitationID,
-					"error", err,
-				)
-			} else {
-				asyncLogger.Info("Invitation email sent",
-					"email", email,
-					"invitation_id", invitationID,
-				)
-			}
-		}()
+	// The outbox worker sends the email and retries it if Resend is unavailable
+	if email != nil {
+		h.logger.WithContext(r.Context()).Info("Invitation email queued",
+			"email", req.Email,
+			"invitation_id", invitation.ID,
+		)
 	}
 
-	respondJSON(w, http.StatusCreated, invitation.ToInvitationResponse())
+	return invitation, nil
+}
+
+// canSupervise reports whether a new user can report to the user with the ID: a supervisor or
+// admin in the current organization
+func canSupervise(r *http.Request, userRepo repository.UserRepository, id int64) bool {
+	supervisor, err := userRepo.GetByID(r.Context(), id)
+	return err == nil && supervisor != nil && supervisor.IsSupervisorOrAdmin()
+}
+
+// invitationDomains returns the email domains the current user's organization limits invitations
+// to, or nil when invitations aren't limited
+func (h *InvitationHandlers) invitationDomains(r *http.Request, currentUser *models.User) ([]string, error) {
+	if h.orgRepo == nil {
+		return nil, nil
+	}
+	domains, err := h.orgRepo.GetInvitationDomains(r.Context(), currentUser.OrgID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get invitation domains", err, "org_id", currentUser.OrgID)
+		return nil, err
+	}
+	return domains, nil
+}
+
+// GetInvitationDomains godoc
+// @Summary Get the organization's invitation domains
+// @Description Returns the email domains invitations are limited to. An empty list allows any domain. Guest invitations aren't limited. Admin only.
+// @Tags Invitations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.InvitationDomains "Invitation domains"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden - admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/invitation-domains [get]
+func (h *InvitationHandlers) GetInvitationDomains(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionInvitationManage)
+	if currentUser == nil {
+		return
+	}
+
+	domains, err := h.invitationDomains(r, currentUser)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch invitation domains")
+		return
+	}
+	if domains == nil {
+		domains = []string{}
+	}
+
+	respondJSON(w, http.StatusOK, models.InvitationDomains{Domains: domains})
+}
+
+// UpdateInvitationDomains godoc
+// @Summary Replace the organization's invitation domains
+// @Description Limits invitations to emails at the given domains; an empty list allows any domain. Invitations already sent aren't affected. Admin only.
+// @Tags Invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateInvitationDomainsRequest true "Invitation domains"
+// @Success 200 {object} models.InvitationDomains "Invitation domains"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden - admin access required"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/invitation-domains [put]
+func (h *InvitationHandlers) UpdateInvitationDomains(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionInvitationManage)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.UpdateInvitationDomainsRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	if err := h.orgRepo.UpdateInvitationDomains(r.Context(), currentUser.OrgID, req.Domains); err != nil {
+		h.logger.LogError(r.Context(), "Failed to update invitation domains", err, "org_id", currentUser.OrgID)
+		respondError(w, http.StatusInternalServerError, "Failed to update invitation domains")
+		return
+	}
+
+	h.logger.Info("Invitation domains updated", "org_id", currentUser.OrgID, "domains", len(req.Domains), "updated_by", currentUser.ID)
+	respondJSON(w, http.StatusOK, models.InvitationDomains{Domains: req.Domains})
 }
 
 // GetInvitations godoc
@@ -150,7 +330,7 @@ func (h *InvitationHandlers) CreateInvitation(w http.ResponseWriter, r *http.Req
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /invitations [get]
 func (h *InvitationHandlers) GetInvitations(w http.ResponseWriter, r *http.Request) {
-	if requireAdmin(w, r) == nil {
+	if requirePermission(w, r, authz.ActionInvitationManage) == nil {
 		return
 	}
 
@@ -195,7 +375,7 @@ func (h *InvitationHandlers) GetInvitations(w http.ResponseWriter, r *http.Reque
 // @Failure 404 {object} map[string]interface{} "Invitation not found"
 // @Router /invitations/{id} [get]
 func (h *InvitationHandlers) GetInvitation(w http.ResponseWriter, r *http.Request) {
-	if requireAdmin(w, r) == nil {
+	if requirePermission(w, r, authz.ActionInvitationManage) == nil {
 		return
 	}
 
@@ -207,7 +387,7 @@ func (h *InvitationHandlers) GetInvitation(w http.ResponseWriter, r *http.Reques
 
 	invitation, err := h.invitationRepo.GetByID(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "Invitation not found")
+		respondRepositoryError(w, r, err, "Invitation")
 		return
 	}
 
@@ -228,7 +408,7 @@ func (h *InvitationHandlers) GetInvitation(w http.ResponseWriter, r *http.Reques
 // @Failure 403 {object} map[string]interface{} "Forbidden - admin access required"
 // @Router /invitations/{id} [delete]
 func (h *InvitationHandlers) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireAdmin(w, r)
+	currentUser := requirePermission(w, r, authz.ActionInvitationManage)
 	if currentUser == nil {
 		return
 	}
@@ -338,6 +518,9 @@ func (h *InvitationHandlers) AcceptInvitation(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Look up who sent the invitation before accepting it; only the accept result decides validity
+	invitation, _ := h.invitationRepo.GetByToken(r.Context(), token)
+
 	user, err := h.invitationRepo.Accept(r.Context(), token, req.Auth0ID, req.FirstName, req.LastName)
 	if err != nil {
 		// Log failed accept attempt (we don't have user ID since they're not created yet)
@@ -361,5 +544,9 @@ func (h *InvitationHandlers) AcceptInvitation(w http.ResponseWriter, r *http.Req
 		},
 	})
 
+	h.onboarding.Start(r.Context(), user)
+	if invitation != nil {
+		h.notifications.NotifyInvitationAccepted(r.Context(), invitation, user)
+	}
 	respondJSON(w, http.StatusOK, user.ToUserResponse())
 }
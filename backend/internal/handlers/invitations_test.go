@@ -85,7 +85,6 @@ func TestCreateInvitation_AdminSuccess(t *testing.T) {
 	h := NewInvitationHandlers(invRepo, userRepo, nil)
 
 	admin := &models.User{ID: 1, Role: models.RoleAdmin}
-	// Note: Only admin and supervisor roles can be invited per validation rules
 	body := `{"email":"newuser@example.com","role":"supervisor"}`
 	req := httptest.NewRequest(http.MethodPost, "/invitations", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -111,6 +110,52 @@ func TestCreateInvitation_AdminSuccess(t *testing.T) {
 	}
 }
 
+func TestCreateInvitation_Employee(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+
+	tests := []struct {
+		name               string
+		body               string
+		expectedStatus     int
+		expectedSupervisor int64
+	}{
+		{"reports to the inviter by default", `{"email":"dev@example.com","role":"employee","title":"Engineer"}`, http.StatusCreated, 1},
+		{"reports to the chosen supervisor", `{"email":"dev@example.com","role":"employee","supervisor_id":2,"squad_ids":[4]}`, http.StatusCreated, 2},
+		{"supervisor is an employee", `{"email":"dev@example.com","role":"employee","supervisor_id":3}`, http.StatusBadRequest, 0},
+		{"supervisor doesn't exist", `{"email":"dev@example.com","role":"employee","supervisor_id":99}`, http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invRepo := mocks.NewMockInvitationRepository()
+			userRepo := mocks.NewMockUserRepository()
+			userRepo.AddUser(admin)
+			userRepo.AddUser(&models.User{ID: 2, Role: models.RoleSupervisor})
+			userRepo.AddUser(&models.User{ID: 3, Role: models.RoleEmployee})
+			h := NewInvitationHandlers(invRepo, userRepo, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/invitations", bytes.NewBufferString(tt.body))
+			req = req.WithContext(ctxWithUser(admin))
+			rr := httptest.NewRecorder()
+			h.CreateInvitation(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("CreateInvitation() status = %d, want %d: %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			var response models.InvitationResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.SupervisorID == nil || *response.SupervisorID != tt.expectedSupervisor {
+				t.Errorf("response.SupervisorID = %v, want %d", response.SupervisorID, tt.expectedSupervisor)
+			}
+		})
+	}
+}
+
 func TestCreateInvitation_DuplicateEmail(t *testing.T) {
 	invRepo := mocks.NewMockInvitationRepository()
 	userRepo := mocks.NewMockUserRepository()
@@ -591,3 +636,116 @@ func TestAcceptInvitation(t *testing.T) {
 		}
 	})
 }
+
+func TestBulkCreateInvitations(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}
+	invRepo := mocks.NewMockInvitationRepository()
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(admin)
+	userRepo.AddUser(&models.User{ID: 2, Email: "taken@example.com", Role: models.RoleEmployee, OrgID: 1})
+	orgRepo := mocks.NewMockOrganizationRepository()
+	orgRepo.InvitationDomains[1] = []string{"example.com"}
+	h := NewInvitationHandlers(invRepo, userRepo, nil).WithInvitationDomains(orgRepo)
+
+	body := `{"role":"employee","title":"Engineer","emails":["a@example.com","b@other.io","taken@example.com","a@example.com","not-an-email"]}`
+	req := httptest.NewRequest(http.MethodPost, "/invitations/bulk", bytes.NewBufferString(body))
+	req = req.WithContext(ctxWithUser(admin))
+	rr := httptest.NewRecorder()
+	h.BulkCreateInvitations(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("BulkCreateInvitations() status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var resp models.BulkInvitationResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Created != 1 || resp.Failed != 4 || len(resp.Results) != 5 {
+		t.Fatalf("response = %+v, want 1 created and 4 failed", resp)
+	}
+	if resp.Results[0].Invitation == nil || resp.Results[0].Invitation.Title != "Engineer" {
+		t.Errorf("first result = %+v, want an invitation with the shared title", resp.Results[0])
+	}
+	for _, result := range resp.Results[1:] {
+		if result.Invitation != nil || result.Error == "" {
+			t.Errorf("result for %s = %+v, want an error", result.Email, result)
+		}
+	}
+	if len(invRepo.Invitations) != 1 {
+		t.Errorf("created %d invitations, want 1", len(invRepo.Invitations))
+	}
+}
+
+func TestCreateInvitation_DomainRestriction(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}
+	nextWeek := time.Now().AddDate(0, 0, 7).Format(time.RFC3339)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"allowed domain", `{"email":"lead@example.com","role":"supervisor"}`, http.StatusCreated},
+		{"other domain", `{"email":"lead@other.io","role":"supervisor"}`, http.StatusBadRequest},
+		{"guests aren't limited", `{"email":"guest@other.io","role":"guest","access_expires_at":"` + nextWeek + `"}`, http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgRepo := mocks.NewMockOrganizationRepository()
+			orgRepo.InvitationDomains[1] = []string{"example.com"}
+			h := NewInvitationHandlers(mocks.NewMockInvitationRepository(), mocks.NewMockUserRepository(), nil).WithInvitationDomains(orgRepo)
+
+			req := httptest.NewRequest(http.MethodPost, "/invitations", bytes.NewBufferString(tt.body))
+			req = req.WithContext(ctxWithUser(admin))
+			rr := httptest.NewRecorder()
+			h.CreateInvitation(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("CreateInvitation() status = %d, want %d: %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestInvitationDomains(t *testing.T) {
+	orgRepo := mocks.NewMockOrganizationRepository()
+	h := NewInvitationHandlers(mocks.NewMockInvitationRepository(), mocks.NewMockUserRepository(), nil).WithInvitationDomains(orgRepo)
+	admin := &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/invitation-domains", bytes.NewBufferString(`{"domains":["@Example.com"]}`))
+	req = req.WithContext(ctxWithUser(&models.User{ID: 2, Role: models.RoleSupervisor, OrgID: 1}))
+	rr := httptest.NewRecorder()
+	h.UpdateInvitationDomains(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("UpdateInvitationDomains() as supervisor status = %d, want %d", rr.Code, http.StatusForbidden)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/admin/invitation-domains", bytes.NewBufferString(`{"domains":["example..com"]}`))
+	req = req.WithContext(ctxWithUser(admin))
+	rr = httptest.NewRecorder()
+	h.UpdateInvitationDomains(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("UpdateInvitationDomains() with invalid domain status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/admin/invitation-domains", bytes.NewBufferString(`{"domains":["@Example.com"]}`))
+	req = req.WithContext(ctxWithUser(admin))
+	rr = httptest.NewRecorder()
+	h.UpdateInvitationDomains(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("UpdateInvitationDomains() status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/invitation-domains", nil)
+	req = req.WithContext(ctxWithUser(admin))
+	rr = httptest.NewRecorder()
+	h.GetInvitationDomains(rr, req)
+	var domains models.InvitationDomains
+	if err := json.NewDecoder(rr.Body).Decode(&domains); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(domains.Domains) != 1 || domains.Domains[0] != "example.com" {
+		t.Errorf("GetInvitationDomains() = %v, want [example.com]", domains.Domains)
+	}
+}
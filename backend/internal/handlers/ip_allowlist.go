@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// IPAllowlistHandlers manage the networks an organization's users may connect from
+type IPAllowlistHandlers struct {
+	orgRepo repository.OrganizationRepository
+	logger  *logger.Logger
+}
+
+// NewIPAllowlistHandlers creates a new IP allowlist handlers instance
+func NewIPAllowlistHandlers(orgRepo repository.OrganizationRepository) *IPAllowlistHandlers {
+	return &IPAllowlistHandlers{
+		orgRepo: orgRepo,
+		logger:  logger.Default().WithComponent("ip-allowlist-handlers"),
+	}
+}
+
+// GetIPAllowlist godoc
+// @Summary Get the organization's IP allowlist
+// @Description Returns the IP addresses and CIDR ranges the organization's users may connect from. An empty list allows any network. Requires security:manage.
+// @Tags Security
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.IPAllowlist "IP allowlist"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/ip-allowlist [get]
+func (h *IPAllowlistHandlers) GetIPAllowlist(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionSecurityManage)
+	if currentUser == nil {
+		return
+	}
+
+	entries, err := h.orgRepo.GetIPAllowlist(r.Context(), currentUser.OrgID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get IP allowlist", err, "org_id", currentUser.OrgID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch IP allowlist")
+		return
+	}
+	if entries == nil {
+		entries = []string{}
+	}
+
+	respondJSON(w, http.StatusOK, models.IPAllowlist{Entries: entries})
+}
+
+// UpdateIPAllowlist godoc
+// @Summary Replace the organization's IP allowlist
+// @Description Limits the networks the organization's users may connect from to the given IP addresses and CIDR ranges; an empty list allows any network. The list must include the network the request comes from, so admins can't lock themselves out. Changes apply within a minute. Requires security:manage.
+// @Tags Security
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateIPAllowlistRequest true "IP allowlist"
+// @Success 200 {object} models.IPAllowlist "IP allowlist"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/ip-allowlist [put]
+func (h *IPAllowlistHandlers) UpdateIPAllowlist(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionSecurityManage)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.UpdateIPAllowlistRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if !validateRequest(w, &req) {
+		return
+	}
+
+	allowlist, err := middleware.ParseIPAllowlist(req.Entries)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if ip := middleware.ClientIP(r); !allowlist.Allows(ip) {
+		respondError(w, http.StatusBadRequest, "The allowlist must include the network you are connecting from ("+ip+")")
+		return
+	}
+
+	entries := req.Entries
+	if entries == nil {
+		entries = []string{}
+	}
+	if err := h.orgRepo.UpdateIPAllowlist(r.Context(), currentUser.OrgID, entries); err != nil {
+		h.logger.LogError(r.Context(), "Failed to update IP allowlist", err, "org_id", currentUser.OrgID)
+		respondError(w, http.StatusInternalServerError, "Failed to update IP allowlist")
+		return
+	}
+
+	h.logger.Info("IP allowlist updated", "org_id", currentUser.OrgID, "entries", len(entries), "updated_by", currentUser.ID)
+	respondJSON(w, http.StatusOK, models.IPAllowlist{Entries: entries})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestIPAllowlistHandlers_GetIPAllowlist(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		expectedStatus int
+	}{
+		{"admin", &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}, http.StatusOK},
+		{"supervisor", &models.User{ID: 2, Role: models.RoleSupervisor, OrgID: 1}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgRepo := mocks.NewMockOrganizationRepository()
+			orgRepo.IPAllowlists[1] = []string{"203.0.113.0/24"}
+			h := NewIPAllowlistHandlers(orgRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/ip-allowlist", nil)
+			req = req.WithContext(ctxWithUser(tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.GetIPAllowlist(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var allowlist models.IPAllowlist
+			if err := json.Unmarshal(rr.Body.Bytes(), &allowlist); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(allowlist.Entries) != 1 || allowlist.Entries[0] != "203.0.113.0/24" {
+				t.Errorf("unexpected entries: %v", allowlist.Entries)
+			}
+		})
+	}
+}
+
+func TestIPAllowlistHandlers_UpdateIPAllowlist(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		body           string
+		expectedStatus int
+		expectedStored []string
+	}{
+		{"sets allowlist", admin, `{"entries":["203.0.113.0/24"," 198.51.100.7 "]}`, http.StatusOK, []string{"203.0.113.0/24", "198.51.100.7"}},
+		{"clears allowlist", admin, `{"entries":[]}`, http.StatusOK, []string{}},
+		{"rejects invalid entry", admin, `{"entries":["203.0.113.0/33"]}`, http.StatusBadRequest, nil},
+		{"rejects blank entry", admin, `{"entries":[" "]}`, http.StatusBadRequest, nil},
+		{"rejects locking the admin out", admin, `{"entries":["198.51.100.0/24"]}`, http.StatusBadRequest, nil},
+		{"requires security:manage", &models.User{ID: 2, Role: models.RoleSupervisor, OrgID: 1}, `{"entries":[]}`, http.StatusForbidden, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgRepo := mocks.NewMockOrganizationRepository()
+			h := NewIPAllowlistHandlers(orgRepo)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/admin/ip-allowlist", strings.NewReader(tt.body))
+			req.RemoteAddr = "203.0.113.5:4321"
+			req = req.WithContext(ctxWithUser(tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.UpdateIPAllowlist(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			stored := orgRepo.IPAllowlists[1]
+			if tt.expectedStored == nil {
+				if len(stored) != 0 {
+					t.Errorf("expected allowlist unchanged, got %v", stored)
+				}
+				return
+			}
+			if strings.Join(stored, ",") != strings.Join(tt.expectedStored, ",") {
+				t.Errorf("stored %v, want %v", stored, tt.expectedStored)
+			}
+		})
+	}
+}
@@ -2,43 +2,68 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
 	"github.com/smith-dallin/manager-dashboard/internal/database"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
 	"github.com/smith-dallin/manager-dashboard/internal/jira"
 	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/oauth"
 	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
 )
 
 // maxConcurrentJiraRequests is the default limit for concurrent Jira API requests
 // to avoid overwhelming the Jira API rate limits
 const maxConcurrentJiraRequests = 5
 
+// jiraPendingConnectionTTL is how long an admin has to pick a site after completing OAuth
+const jiraPendingConnectionTTL = 30 * time.Minute
+
+// maxJiraWebhookBytes caps the size of an incoming Jira webhook payload
+const maxJiraWebhookBytes = 5 << 20
+
 type JiraHandlers struct {
 	userRepo             repository.UserRepository
 	orgJiraRepo          repository.OrgJiraRepository
 	timeOffRepo          repository.TimeOffRepository
 	oauthService         *jira.OAuthService
 	stateStore           oauth.StateStore
+	stateBinder          OAuthStateBinder
 	frontendURL          string
 	maxUsersPagination   int
 	maxConcurrentAPIReqs int
+	sprintCapacity       *services.SprintCapacityService
+	health               *services.JiraHealthService
+	webhookSecret        string
+	issueCache           repository.JiraIssueCacheRepository
+	broker               *events.Broker
+	issueSync            *services.JiraIssueSyncService
+	worklogs             *services.WorklogService
+	capacity             *services.CapacityService
+	epicProgress         *services.EpicProgressService
+	breakers             *jira.CircuitBreakers
+	callStats            *jira.CallStats
 	logger               *logger.Logger
 }
 
 func NewJiraHandlers(userRepo repository.UserRepository, orgJiraRepo repository.OrgJiraRepository, timeOffRepo repository.TimeOffRepository, oauthService *jira.OAuthService, stateStore oauth.StateStore, frontendURL string, log *logger.Logger) *JiraHandlers {
-	return NewJiraHandlersWithConfig(userRepo, orgJiraRepo, timeOffRepo, oauthService, stateStore, frontendURL, 1000, maxConcurrentJiraRequests, log)
+	return NewJiraHandlersWithConfig(userRepo, orgJiraRepo, timeOffRepo, oauthService, stateStore, frontendURL, 1000, maxConcurrentJiraRequests, nil, nil, log)
 }
 
 // NewJiraHandlersWithConfig creates Jira handlers with custom configuration
-func NewJiraHandlersWithConfig(userRepo repository.UserRepository, orgJiraRepo repository.OrgJiraRepository, timeOffRepo repository.TimeOffRepository, oauthService *jira.OAuthService, stateStore oauth.StateStore, frontendURL string, maxUsersPagination int, maxConcurrentAPIReqs int, log *logger.Logger) *JiraHandlers {
+func NewJiraHandlersWithConfig(userRepo repository.UserRepository, orgJiraRepo repository.OrgJiraRepository, timeOffRepo repository.TimeOffRepository, oauthService *jira.OAuthService, stateStore oauth.StateStore, frontendURL string, maxUsersPagination int, maxConcurrentAPIReqs int, sprintCapacity *services.SprintCapacityService, health *services.JiraHealthService, log *logger.Logger) *JiraHandlers {
 	if maxUsersPagination <= 0 {
 		maxUsersPagination = 1000
 	}
@@ -54,10 +79,65 @@ func NewJiraHandlersWithConfig(userRepo repository.UserRepository, orgJiraRepo r
 		frontendURL:          frontendURL,
 		maxUsersPagination:   maxUsersPagination,
 		maxConcurrentAPIReqs: maxConcurrentAPIReqs,
+		sprintCapacity:       sprintCapacity,
+		health:               health,
+		breakers:             jira.NewCircuitBreakers(jira.DefaultBreakerThreshold, jira.DefaultBreakerCooldown),
+		callStats:            jira.NewCallStats(jira.DefaultStatsWindow),
 		logger:               log.WithComponent("jira_handlers"),
 	}
 }
 
+// WithWebhookIngestion enables the Jira webhook endpoint. Issue events signed with the secret are
+// stored in the issue cache and announced on the broker so cached team task views refresh.
+func (h *JiraHandlers) WithWebhookIngestion(secret string, issueCache repository.JiraIssueCacheRepository, broker *events.Broker) *JiraHandlers {
+	h.webhookSecret = secret
+	h.issueCache = issueCache
+	h.broker = broker
+	return h
+}
+
+// WithOAuthStateBinding ties each OAuth flow to the browser that started it, rejecting callbacks
+// from any other
+func (h *JiraHandlers) WithOAuthStateBinding(binder OAuthStateBinder) *JiraHandlers {
+	h.stateBinder = binder
+	return h
+}
+
+// WithIssueSync serves my and team tasks from the local issue cache, fetching live only on a cache
+// miss or an explicit refresh
+func (h *JiraHandlers) WithIssueSync(issueSync *services.JiraIssueSyncService) *JiraHandlers {
+	h.issueSync = issueSync
+	return h
+}
+
+// WithWorklogs enables worklog summaries comparing logged Jira time with capacity
+func (h *JiraHandlers) WithWorklogs(worklogs *services.WorklogService) *JiraHandlers {
+	h.worklogs = worklogs
+	return h
+}
+
+// WithCapacity enables capacity plans comparing people's availability with their assigned Jira work
+func (h *JiraHandlers) WithCapacity(capacity *services.CapacityService) *JiraHandlers {
+	h.capacity = capacity
+	return h
+}
+
+// WithEpicProgress enables epic progress rollups with completion forecasts
+func (h *JiraHandlers) WithEpicProgress(epicProgress *services.EpicProgressService) *JiraHandlers {
+	h.epicProgress = epicProgress
+	return h
+}
+
+// ConnectJira returns the Jira connection of the organization ctx is scoped to as an issue source
+// for the sync worker
+func (h *JiraHandlers) ConnectJira(ctx context.Context) (services.JiraIssueSource, error) {
+	client, err := h.getJiraClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
 // getJiraClient gets org-wide Jira settings and returns a Jira client.
 // If the token is expired, it attempts to refresh it automatically.
 func (h *JiraHandlers) getJiraClient(ctx context.Context) (*jira.Client, error) {
@@ -70,19 +150,37 @@ func (h *JiraHandlers) getJiraClient(ctx context.Context) (*jira.Client, error)
 		return nil, fmt.Errorf("jira is not configured for this organization")
 	}
 
+	accessToken, err := h.accessToken(ctx, orgSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	// The org is connected to a single site, so every client of the org shares its breaker
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client := jira.NewOAuthClient(accessToken, orgSettings.CloudID, orgSettings.SiteURL).
+		WithCircuitBreaker(h.breakers.For(orgID)).WithCallStats(h.callStats).WithContext(ctx)
+	return client.OnError(func(err error) { h.health.RecordAPIError(ctx, err) }), nil
+}
+
+// accessToken returns the org's Jira access token, refreshing it first if it has expired
+func (h *JiraHandlers) accessToken(ctx context.Context, orgSettings *models.OrgJiraSettings) (string, error) {
 	accessToken := orgSettings.OAuthAccessToken
 
 	// Check if token is expired and refresh if needed
 	if orgSettings.IsTokenExpired() {
 		if h.oauthService == nil {
-			return nil, fmt.Errorf("cannot refresh token: OAuth service not configured")
+			return "", fmt.Errorf("cannot refresh token: OAuth service not configured")
 		}
 
 		h.logger.WithContext(ctx).Info("Jira OAuth token expired, attempting to refresh")
 
 		tokenResp, err := h.oauthService.RefreshAccessToken(orgSettings.OAuthRefreshToken)
 		if err != nil {
-			return nil, fmt.Errorf("failed to refresh token: %w", err)
+			h.health.RecordRefreshFailure(ctx, err)
+			return "", fmt.Errorf("failed to refresh token: %w", err)
 		}
 
 		// Update both access and refresh tokens in the database
@@ -98,7 +196,155 @@ func (h *JiraHandlers) getJiraClient(ctx context.Context) (*jira.Client, error)
 		accessToken = tokenResp.AccessToken
 	}
 
-	return jira.NewOAuthClient(accessToken, orgSettings.CloudID, orgSettings.SiteURL), nil
+	return accessToken, nil
+}
+
+// HandleWebhook ingests issue created, updated, and deleted events from a Jira webhook into the
+// local issue cache. It's public, so every request must be signed with the configured secret.
+func (h *JiraHandlers) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.webhookSecret == "" || h.issueCache == nil {
+		respondError(w, http.StatusNotFound, "Jira webhooks are not configured")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxJiraWebhookBytes))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "Webhook payload is too large")
+		return
+	}
+	if !jira.VerifyWebhookSignature(h.webhookSecret, body, r.Header.Get(jira.WebhookSignatureHeader)) {
+		respondError(w, http.StatusUnauthorized, "Invalid webhook signature")
+		return
+	}
+
+	// The webhook secret is the deployment's, so the issues belong to the default organization
+	ctx := tenant.WithOrgID(r.Context(), tenant.DefaultOrgID)
+	orgSettings, err := h.orgJiraRepo.Get(ctx)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get Jira settings")
+		return
+	}
+	if orgSettings == nil {
+		respondError(w, http.StatusNotFound, "Jira is not configured for this organization")
+		return
+	}
+	if err := h.orgJiraRepo.RecordWebhookDelivery(ctx); err != nil {
+		h.logger.WithContext(ctx).Warn("Failed to record Jira webhook delivery", "error", err)
+	}
+
+	event, err := jira.ParseWebhookEvent(body, orgSettings.SiteURL)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid webhook payload")
+		return
+	}
+
+	var changed bool
+	switch event.Event {
+	case jira.WebhookIssueCreated, jira.WebhookIssueUpdated:
+		changed, err = h.issueCache.Upsert(ctx, &event.Issue, event.Resolved)
+	case jira.WebhookIssueDeleted:
+		changed, err = h.issueCache.Delete(ctx, event.Issue.Key)
+	default:
+		// Other events may be enabled on the webhook; they don't affect the cache
+	}
+	if err != nil {
+		// A failure response makes Jira retry the delivery
+		h.logger.LogError(ctx, "Failed to update Jira issue cache", err, "issue_key", event.Issue.Key)
+		respondError(w, http.StatusInternalServerError, "Failed to process webhook")
+		return
+	}
+
+	if changed {
+		h.broker.Publish(ctx, events.JiraIssuesChanged, event.Issue.Key)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetJiraHealth reports token expiry, refresh and API failures, and webhook recency for the
+// organization-wide Jira connection, along with live diagnostics: it verifies the stored tokens
+// and their scopes with Atlassian, and reports the last issue sync and recent API error rate (admin only)
+func (h *JiraHandlers) GetJiraHealth(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	orgSettings, err := h.orgJiraRepo.Get(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get Jira settings")
+		return
+	}
+
+	now := time.Now()
+	health := services.BuildJiraHealth(orgSettings, now)
+	if orgSettings == nil {
+		respondJSON(w, http.StatusOK, health)
+		return
+	}
+
+	lastSyncAt, err := h.issueSync.LastSyncedAt(r.Context())
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to get last Jira sync time", "error", err)
+	}
+	calls, failures := h.callStats.Snapshot()
+	orgID, _ := tenant.ScopedOrgID(r.Context())
+	services.ApplyJiraDiagnostics(health, services.JiraDiagnostics{
+		TokenCheck:  h.verifyJiraTokens(r.Context(), orgSettings, now),
+		APICalls:    services.NewJiraAPICallStats(h.callStats.Window(), calls, failures),
+		CircuitOpen: h.breakers.For(orgID).Open(),
+		LastSyncAt:  lastSyncAt,
+	})
+
+	respondJSON(w, http.StatusOK, health)
+}
+
+// verifyJiraTokens checks the stored tokens with Atlassian, refreshing them first if they've
+// expired, and compares the scopes granted on the connected site with those requested
+func (h *JiraHandlers) verifyJiraTokens(ctx context.Context, orgSettings *models.OrgJiraSettings, now time.Time) *models.JiraTokenCheck {
+	check := &models.JiraTokenCheck{Scopes: []string{}, MissingScopes: []string{}, CheckedAt: now}
+	if h.oauthService == nil {
+		check.Error = "Jira OAuth is not configured"
+		return check
+	}
+
+	accessToken, err := h.accessToken(ctx, orgSettings)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	resources, err := h.oauthService.GetAccessibleResources(accessToken)
+	if err != nil {
+		h.health.RecordAPIError(ctx, err)
+		check.Error = err.Error()
+		return check
+	}
+
+	var site *jira.AccessibleResource
+	for i := range resources {
+		if resources[i].ID == orgSettings.CloudID {
+			site = &resources[i]
+		}
+	}
+	if site == nil {
+		check.Error = "the tokens no longer have access to the connected Jira site"
+		return check
+	}
+
+	check.Verified = true
+	if site.Scopes != nil {
+		check.Scopes = site.Scopes
+	}
+	granted := make(map[string]bool, len(site.Scopes))
+	for _, scope := range site.Scopes {
+		granted[scope] = true
+	}
+	for _, scope := range jira.Scopes {
+		// offline_access applies to the refresh token, not the site
+		if scope != "offline_access" && !granted[scope] {
+			check.MissingScopes = append(check.MissingScopes, scope)
+		}
+	}
+	return check
 }
 
 // GetJiraSettings returns the organization-wide Jira settings
@@ -129,7 +375,7 @@ func (h *JiraHandlers) GetJiraSettings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// For admins, also check if they have permission to configure
-	response["can_configure"] = currentUser.IsAdmin()
+	response["can_configure"] = authz.Can(currentUser, authz.ActionIntegrationManage, nil)
 
 	respondJSON(w, http.StatusOK, response)
 }
@@ -137,7 +383,7 @@ func (h *JiraHandlers) GetJiraSettings(w http.ResponseWriter, r *http.Request) {
 // GetOAuthAuthorizeURL returns the URL to redirect the user to for Jira OAuth authorization
 // Only admins can configure the organization-wide Jira connection
 func (h *JiraHandlers) GetOAuthAuthorizeURL(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireAdmin(w, r)
+	currentUser := requirePermission(w, r, authz.ActionIntegrationManage)
 	if currentUser == nil {
 		return
 	}
@@ -153,6 +399,9 @@ func (h *JiraHandlers) GetOAuthAuthorizeURL(w http.ResponseWriter, r *http.Reque
 		respondError(w, http.StatusInternalServerError, "Failed to generate state")
 		return
 	}
+	if h.stateBinder != nil {
+		h.stateBinder.BindState(w, state)
+	}
 
 	// Get authorization URL
 	authURL := h.oauthService.GetAuthorizationURL(state)
@@ -162,6 +411,13 @@ func (h *JiraHandlers) GetOAuthAuthorizeURL(w http.ResponseWriter, r *http.Reque
 	})
 }
 
+// OAuthStateBinder ties OAuth flows to the browser that started them, so a callback forged with
+// another flow's code and state is rejected
+type OAuthStateBinder interface {
+	BindState(w http.ResponseWriter, state string)
+	VerifyState(r *http.Request, state string) bool
+}
+
 // oauthCallbackParams holds the validated OAuth callback parameters
 type oauthCallbackParams struct {
 	code   string
@@ -169,9 +425,11 @@ type oauthCallbackParams struct {
 	userID int64
 }
 
-// validateOAuthCallback validates the OAuth callback parameters and state.
+// validateOAuthCallback validates the OAuth callback parameters and state. A callback relayed by
+// the frontend server for a signed-in user must carry state issued to that user; any other must
+// come from the browser that started the flow.
 // Returns the validated params or an error string for the redirect.
-func (h *JiraHandlers) validateOAuthCallback(ctx context.Context, r *http.Request) (*oauthCallbackParams, string) {
+func validateOAuthCallback(ctx context.Context, r *http.Request, stateStore oauth.StateStore, binder OAuthStateBinder) (*oauthCallbackParams, string) {
 	// Guard: Check for OAuth error from provider
 	if errorParam := r.URL.Query().Get("error"); errorParam != "" {
 		errorDesc := r.URL.Query().Get("error_description")
@@ -186,8 +444,14 @@ func (h *JiraHandlers) validateOAuthCallback(ctx context.Context, r *http.Reques
 		return nil, "missing_parameters"
 	}
 
+	// Guard: Check before using up the state, so a forged callback can't spoil the real one
+	relayedFor := middleware.GetUserFromContext(ctx)
+	if relayedFor == nil && binder != nil && !binder.VerifyState(r, state) {
+		return nil, "state_mismatch"
+	}
+
 	// Guard: Validate state and get userID
-	userID, err := h.stateStore.Validate(ctx, state)
+	userID, err := stateStore.Validate(ctx, state)
 	if err != nil {
 		if err == oauth.ErrStateNotFound || err == oauth.ErrStateExpired {
 			return nil, "invalid_state"
@@ -195,6 +459,12 @@ func (h *JiraHandlers) validateOAuthCallback(ctx context.Context, r *http.Reques
 		return nil, "state_validation_failed"
 	}
 
+	// Guard: The state was issued to whoever started the flow, so another user's browser can't be
+	// sent to the relay to complete it
+	if relayedFor != nil && relayedFor.ID != userID {
+		return nil, "state_mismatch"
+	}
+
 	return &oauthCallbackParams{code: code, state: state, userID: userID}, ""
 }
 
@@ -227,7 +497,8 @@ func (h *JiraHandlers) exchangeAndGetResources(code string) (*jira.TokenResponse
 }
 
 // HandleOAuthCallback handles the OAuth callback from Atlassian
-// Saves the OAuth tokens as organization-wide Jira settings and redirects to frontend
+// The accessible sites are held as a pending connection for the admin to review and choose from,
+// even when there's only one, so nothing is connected without the admin confirming the site.
 func (h *JiraHandlers) HandleOAuthCallback(w http.ResponseWriter, r *http.Request) {
 	redirectURL := h.frontendURL + "/settings"
 
@@ -236,12 +507,22 @@ func (h *JiraHandlers) HandleOAuthCallback(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Validate callback parameters and state
-	params, errMsg := h.validateOAuthCallback(r.Context(), r)
+	params, errMsg := validateOAuthCallback(r.Context(), r, h.stateStore, h.stateBinder)
 	if errMsg != "" {
 		redirectWithError(errMsg)
 		return
 	}
 
+	// Atlassian calls back without the admin's token, so connect Jira for the organization of the
+	// admin who started the flow
+	ctx := r.Context()
+	admin, err := h.userRepo.GetByID(ctx, params.userID)
+	if err != nil || admin == nil {
+		redirectWithError("state_validation_failed")
+		return
+	}
+	ctx = tenant.WithOrgID(ctx, admin.OrgID)
+
 	// Exchange code for tokens and get accessible resources
 	tokenResp, resources, errMsg := h.exchangeAndGetResources(params.code)
 	if errMsg != "" {
@@ -249,27 +530,123 @@ func (h *JiraHandlers) HandleOAuthCallback(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Use the first available site (in a real app, you might let the user choose)
-	resource := resources[0]
+	sites := make([]models.JiraSite, len(resources))
+	for i, res := range resources {
+		sites[i] = models.JiraSite{
+			ID:        res.ID,
+			Name:      res.Name,
+			URL:       res.URL,
+			Scopes:    res.Scopes,
+			AvatarURL: res.AvatarURL,
+		}
+	}
 
-	// Save tokens to org-wide settings
-	orgSettings := &models.OrgJiraSettings{
+	pending := &models.JiraPendingConnection{
 		OAuthAccessToken:    tokenResp.AccessToken,
 		OAuthRefreshToken:   tokenResp.RefreshToken,
 		OAuthTokenExpiresAt: jira.CalculateExpiry(tokenResp.ExpiresIn),
-		CloudID:             resource.ID,
-		SiteURL:             resource.URL,
-		SiteName:            &resource.Name,
+		Sites:               sites,
 		ConfiguredByID:      params.userID,
+		ExpiresAt:           time.Now().Add(jiraPendingConnectionTTL),
 	}
 
-	if err := h.orgJiraRepo.Save(r.Context(), orgSettings); err != nil {
+	// Hold the tokens until an admin picks which site to connect
+	if err := h.orgJiraRepo.SavePendingConnection(ctx, pending); err != nil {
 		redirectWithError("save_failed")
 		return
 	}
 
-	// Redirect to settings page with success
-	http.Redirect(w, r, redirectURL+"?jira_connected=true", http.StatusFound)
+	http.Redirect(w, r, redirectURL+"?jira_select_site=true", http.StatusFound)
+}
+
+// pendingToOrgSettings builds org-wide settings from a pending connection and the chosen site
+func pendingToOrgSettings(pending *models.JiraPendingConnection, site *models.JiraSite) *models.OrgJiraSettings {
+	siteName := site.Name
+	return &models.OrgJiraSettings{
+		OAuthAccessToken:    pending.OAuthAccessToken,
+		OAuthRefreshToken:   pending.OAuthRefreshToken,
+		OAuthTokenExpiresAt: pending.OAuthTokenExpiresAt,
+		CloudID:             site.ID,
+		SiteURL:             site.URL,
+		SiteName:            &siteName,
+		ConfiguredByID:      pending.ConfiguredByID,
+	}
+}
+
+// GetPendingJiraSites returns the Atlassian sites awaiting selection after OAuth (admin only).
+// A lone site still needs selecting, though clients may preselect it.
+func (h *JiraHandlers) GetPendingJiraSites(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	pending, err := h.orgJiraRepo.GetPendingConnection(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get pending Jira sites")
+		return
+	}
+	if pending == nil {
+		respondError(w, http.StatusNotFound, "No Jira connection is awaiting site selection")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pending)
+}
+
+// SelectJiraSite connects the chosen pending site as the organization's Jira settings (admin only).
+// An organization connects one Atlassian site at a time; selecting a site replaces any connected
+// before, so connecting another site means going through OAuth again.
+func (h *JiraHandlers) SelectJiraSite(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	var req models.SelectJiraSiteRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	pending, err := h.orgJiraRepo.GetPendingConnection(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get pending Jira sites")
+		return
+	}
+	if pending == nil {
+		respondError(w, http.StatusNotFound, "No Jira connection is awaiting site selection")
+		return
+	}
+
+	site := pending.FindSite(req.CloudID)
+	if site == nil {
+		respondError(w, http.StatusBadRequest, "Selected site is not accessible with this authorization")
+		return
+	}
+
+	orgSettings := pendingToOrgSettings(pending, site)
+	if err := h.orgJiraRepo.Save(r.Context(), orgSettings); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to save Jira settings")
+		return
+	}
+
+	if err := h.orgJiraRepo.DeletePendingConnection(r.Context()); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to clear pending Jira connection", "error", err)
+	}
+
+	respondJSON(w, http.StatusOK, orgSettings)
+}
+
+// CancelPendingJiraConnection discards tokens awaiting site selection (admin only)
+func (h *JiraHandlers) CancelPendingJiraConnection(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	if err := h.orgJiraRepo.DeletePendingConnection(r.Context()); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to cancel pending Jira connection")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // UpdateJiraSettings updates the current user's Jira credentials
@@ -291,7 +668,7 @@ func (h *JiraHandlers) UpdateJiraSettings(w http.ResponseWriter, r *http.Request
 	}
 
 	// Test the credentials before saving
-	client := jira.NewClient(req.JiraDomain, req.JiraEmail, req.JiraAPIToken)
+	client := jira.NewClient(req.JiraDomain, req.JiraEmail, req.JiraAPIToken).WithContext(r.Context())
 	if err := client.TestConnection(); err != nil {
 		respondError(w, http.StatusBadRequest, "Invalid Jira credentials: unable to connect to Jira")
 		return
@@ -331,6 +708,15 @@ func (h *JiraHandlers) DeleteJiraSettings(w http.ResponseWriter, r *http.Request
 type JiraIssueWithTimeOff struct {
 	models.JiraIssue
 	TimeOffImpact *models.TimeOffImpact `json:"time_off_impact,omitempty"`
+	SyncedAt      *time.Time            `json:"synced_at,omitempty"` // When the cached copy was synced; omitted when fetched live
+}
+
+// syncTimePtr returns when an account's issues were synced, or nil if they were fetched live
+func syncTimePtr(syncTimes map[string]time.Time, accountID string) *time.Time {
+	if t, ok := syncTimes[accountID]; ok {
+		return &t
+	}
+	return nil
 }
 
 // GetMyTasks returns Jira issues assigned to the current user
@@ -357,19 +743,20 @@ func (h *JiraHandlers) GetMyTasks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Get Jira client with automatic token refresh
-	client, err := h.getJiraClient(r.Context())
+	// Serve from the issue cache unless it's never been synced or a refresh was asked for
+	accountID := *currentUser.JiraAccountID
+	issuesByAccount, syncTimes, err := h.issueSync.Load(r.Context(), h.ConnectJira, []string{accountID}, r.URL.Query().Get("refresh") == "true", 1)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to connect to Jira")
 		return
 	}
-
-	// Fetch issues by the user's Jira account ID
-	issues, err := client.GetIssuesByAccountID(*currentUser.JiraAccountID, maxResults)
-	if err != nil {
+	issues, ok := issuesByAccount[accountID]
+	if !ok {
 		respondError(w, http.StatusInternalServerError, "Failed to fetch Jira issues")
 		return
 	}
+	issues = issues[:min(len(issues), maxResults)]
+	syncedAt := syncTimePtr(syncTimes, accountID)
 
 	// Get user's approved time off for impact calculation
 	var timeOffRequests []models.TimeOffRequest
@@ -388,6 +775,7 @@ func (h *JiraHandlers) GetMyTasks(w http.ResponseWriter, r *http.Request) {
 		result[i] = JiraIssueWithTimeOff{
 			JiraIssue:     issue,
 			TimeOffImpact: database.CalculateTimeOffImpact(issue.DueDate, timeOffRequests),
+			SyncedAt:      syncedAt,
 		}
 	}
 
@@ -486,6 +874,263 @@ func (h *JiraHandlers) GetEpics(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, epics)
 }
 
+// GetEpicProgress rolls up an epic's child issues by status and assignee and estimates when
+// the rest will be done, allowing for the assignees' time off
+func (h *JiraHandlers) GetEpicProgress(w http.ResponseWriter, r *http.Request) {
+	if requireJiraAccess(w, r) == nil {
+		return
+	}
+
+	if h.epicProgress == nil {
+		respondError(w, http.StatusServiceUnavailable, "Epic progress is not available")
+		return
+	}
+
+	epicKey := strings.ToUpper(chi.URLParam(r, "key"))
+	if !models.IsJiraIssueKey(epicKey) {
+		respondError(w, http.StatusBadRequest, "Invalid Jira issue key")
+		return
+	}
+
+	client, err := h.getJiraClient(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to connect to Jira")
+		return
+	}
+
+	progress, err := h.epicProgress.Progress(r.Context(), client, epicKey)
+	switch {
+	case errors.Is(err, jira.ErrIssueNotFound):
+		respondError(w, http.StatusNotFound, "Epic not found")
+		return
+	case errors.Is(err, services.ErrNotAnEpic):
+		respondError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	case err != nil:
+		h.logger.LogError(r.Context(), "Failed to build epic progress", err, "epic_key", epicKey)
+		respondError(w, http.StatusBadGateway, "Failed to fetch epic from Jira")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, progress)
+}
+
+// CreateIssue creates a Jira issue via the org-wide connection and returns its key and URL.
+// An assignee must be the current user or someone they manage, and must be linked to a Jira account.
+// Only supervisors and admins can access Jira features
+func (h *JiraHandlers) CreateIssue(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireJiraAccess(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.CreateJiraIssueRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := jira.CreateIssueInput{
+		ProjectKey:  req.ProjectKey,
+		IssueType:   req.IssueType,
+		Summary:     req.Summary,
+		Description: req.Description,
+	}
+	if req.AssigneeID != nil {
+		assignee, err := h.userRepo.GetByID(r.Context(), *req.AssigneeID)
+		if err != nil || assignee == nil {
+			respondError(w, http.StatusBadRequest, "Assignee not found")
+			return
+		}
+		if assignee.ID != currentUser.ID && !currentUser.CanManage(assignee) {
+			respondError(w, http.StatusForbidden, "You can only assign issues to yourself or your direct reports")
+			return
+		}
+		if assignee.JiraAccountID == nil || *assignee.JiraAccountID == "" {
+			respondError(w, http.StatusBadRequest, "Assignee is not linked to a Jira account")
+			return
+		}
+		input.AssigneeAccountID = *assignee.JiraAccountID
+	}
+
+	// Get Jira client with automatic token refresh
+	client, err := h.getJiraClient(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to connect to Jira")
+		return
+	}
+
+	issue, err := client.CreateIssue(input)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to create Jira issue", err, "project_key", req.ProjectKey, "user_id", currentUser.ID)
+		respondError(w, http.StatusBadGateway, "Failed to create Jira issue")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, issue)
+}
+
+// canActOnJiraIssue reports whether the user may transition or comment on an issue, as
+// authz.ActionIssueManage allows for the dashboard user mapped to its assignee
+func (h *JiraHandlers) canActOnJiraIssue(ctx context.Context, user *models.User, issue *models.JiraIssue) (bool, error) {
+	if authz.CanAll(user, authz.ActionIssueManage) {
+		return true, nil
+	}
+	if issue.Assignee == nil || issue.Assignee.AccountID == "" {
+		return false, nil
+	}
+	accountID := issue.Assignee.AccountID
+	if user.JiraAccountID != nil && *user.JiraAccountID == accountID {
+		return authz.Can(user, authz.ActionIssueManage, authz.UserResource(user)), nil
+	}
+	if !authz.CanForOthers(user, authz.ActionIssueManage) {
+		return false, nil
+	}
+
+	reports, err := h.userRepo.GetDirectReportsBySupervisorID(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	for i := range reports {
+		report := &reports[i]
+		if report.JiraAccountID != nil && *report.JiraAccountID == accountID {
+			return authz.Can(user, authz.ActionIssueManage, authz.UserResource(report)), nil
+		}
+	}
+	return false, nil
+}
+
+// requireActionableJiraIssue loads the issue named in the URL and verifies the user may act on it
+func (h *JiraHandlers) requireActionableJiraIssue(w http.ResponseWriter, r *http.Request, user *models.User) (*jira.Client, *models.JiraIssue) {
+	issueKey := strings.ToUpper(chi.URLParam(r, "issueKey"))
+	if !models.IsJiraIssueKey(issueKey) {
+		respondError(w, http.StatusBadRequest, "Invalid Jira issue key")
+		return nil, nil
+	}
+
+	// Get Jira client with automatic token refresh
+	client, err := h.getJiraClient(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to connect to Jira")
+		return nil, nil
+	}
+
+	issue, err := client.GetIssue(issueKey)
+	if errors.Is(err, jira.ErrIssueNotFound) {
+		respondError(w, http.StatusNotFound, "Jira issue not found")
+		return nil, nil
+	}
+	if err != nil {
+		respondError(w, http.StatusBadGateway, "Failed to fetch Jira issue")
+		return nil, nil
+	}
+
+	allowed, err := h.canActOnJiraIssue(r.Context(), user, issue)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to check permissions")
+		return nil, nil
+	}
+	if !allowed {
+		respondError(w, http.StatusForbidden, "Only the issue's assignee, their supervisor, or an admin can update it")
+		return nil, nil
+	}
+
+	return client, issue
+}
+
+// GetIssueTransitions returns the workflow transitions available on an issue
+func (h *JiraHandlers) GetIssueTransitions(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	client, issue := h.requireActionableJiraIssue(w, r, currentUser)
+	if issue == nil {
+		return
+	}
+
+	transitions, err := client.GetTransitions(issue.Key)
+	if err != nil {
+		respondError(w, http.StatusBadGateway, "Failed to fetch Jira transitions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, transitions)
+}
+
+// TransitionIssue moves an issue through a workflow transition and returns the updated issue
+func (h *JiraHandlers) TransitionIssue(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.TransitionJiraIssueRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	client, issue := h.requireActionableJiraIssue(w, r, currentUser)
+	if issue == nil {
+		return
+	}
+
+	if err := client.TransitionIssue(issue.Key, req.TransitionID); err != nil {
+		h.logger.LogError(r.Context(), "Failed to transition Jira issue", err, "issue_key", issue.Key, "user_id", currentUser.ID)
+		respondError(w, http.StatusBadGateway, "Failed to transition Jira issue")
+		return
+	}
+
+	updated, err := client.GetIssue(issue.Key)
+	if err != nil {
+		// The transition went through; the caller can refetch the issue
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// AddIssueComment comments on an issue. The org-wide connection posts every comment,
+// so the text is prefixed with the dashboard user's name.
+func (h *JiraHandlers) AddIssueComment(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.AddJiraCommentRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	client, issue := h.requireActionableJiraIssue(w, r, currentUser)
+	if issue == nil {
+		return
+	}
+
+	text := fmt.Sprintf("%s %s:\n%s", currentUser.FirstName, currentUser.LastName, req.Body)
+	comment, err := client.AddComment(issue.Key, text)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to comment on Jira issue", err, "issue_key", issue.Key, "user_id", currentUser.ID)
+		respondError(w, http.StatusBadGateway, "Failed to add Jira comment")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, comment)
+}
+
 // GetUserTasks returns Jira issues for a specific user using org-wide Jira connection
 // Uses the user's jira_account_id to query issues assigned to them
 func (h *JiraHandlers) GetUserTasks(w http.ResponseWriter, r *http.Request) {
@@ -503,7 +1148,7 @@ func (h *JiraHandlers) GetUserTasks(w http.ResponseWriter, r *http.Request) {
 	// Get target user
 	targetUser, err := h.userRepo.GetByID(r.Context(), userID)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "User not found")
+		respondRepositoryError(w, r, err, "User")
 		return
 	}
 
@@ -564,6 +1209,212 @@ func (h *JiraHandlers) GetUserTasks(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, result)
 }
 
+// GetUserWorklogs summarizes the time a user logged in Jira per issue and per day against their
+// capacity after holidays and time off. start and end are inclusive YYYY-MM-DD dates and default
+// to the last two weeks.
+func (h *JiraHandlers) GetUserWorklogs(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireJiraAccess(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	if h.worklogs == nil {
+		respondError(w, http.StatusServiceUnavailable, "Worklog summaries are not available")
+		return
+	}
+
+	userID, err := parseIDParam(r, "userId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	targetUser, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || targetUser == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if targetUser.ID != currentUser.ID && !currentUser.CanManage(targetUser) {
+		respondError(w, http.StatusForbidden, "You don't have permission to view this user's worklogs")
+		return
+	}
+
+	if targetUser.JiraAccountID == nil || *targetUser.JiraAccountID == "" {
+		respondError(w, http.StatusBadRequest, "User is not linked to a Jira account")
+		return
+	}
+
+	start, end, errMsg := parseWorklogRange(r)
+	if errMsg != "" {
+		respondError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	client, err := h.getJiraClient(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to connect to Jira")
+		return
+	}
+
+	summary, err := h.worklogs.Summarize(r.Context(), targetUser, client, start, end)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to summarize Jira worklogs", err, "user_id", targetUser.ID)
+		respondError(w, http.StatusBadGateway, "Failed to fetch worklogs from Jira")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, summary)
+}
+
+// GetCapacity compares each person's available business days, after company holidays and approved
+// time off, with the story points of the open Jira issues assigned to them and due by the end date,
+// flagging overallocated people. squad selects a squad; without it the plan covers the current user
+// and their direct reports. start and end are inclusive YYYY-MM-DD dates and default to the next
+// two weeks.
+func (h *JiraHandlers) GetCapacity(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireJiraAccess(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	if h.capacity == nil {
+		respondError(w, http.StatusServiceUnavailable, "Capacity planning is not available")
+		return
+	}
+
+	var squadID *int64
+	if squadStr := r.URL.Query().Get("squad"); squadStr != "" {
+		id, err := strconv.ParseInt(squadStr, 10, 64)
+		if err != nil || id <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid squad ID")
+			return
+		}
+		squadID = &id
+	}
+
+	start, end, errMsg := parseCapacityRange(r)
+	if errMsg != "" {
+		respondError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	client, err := h.getJiraClient(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to connect to Jira")
+		return
+	}
+
+	plan, err := h.capacity.Plan(r.Context(), currentUser, client, squadID, start, end, h.maxConcurrentAPIReqs)
+	switch {
+	case errors.Is(err, services.ErrSquadNotFound):
+		respondError(w, http.StatusNotFound, "Squad not found")
+		return
+	case errors.Is(err, services.ErrSquadAccessDenied):
+		respondError(w, http.StatusForbidden, "Forbidden: "+err.Error())
+		return
+	case err != nil:
+		h.logger.LogError(r.Context(), "Failed to build capacity plan", err)
+		respondError(w, http.StatusBadGateway, "Failed to fetch issues from Jira")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, plan)
+}
+
+// parseWorklogRange reads the inclusive start and end dates for a worklog summary,
+// returning an error message when they're invalid
+func parseWorklogRange(r *http.Request) (time.Time, time.Time, string) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return parseDateRange(r, today.AddDate(0, 0, -13), today, models.MaxWorklogWindowDays)
+}
+
+// parseCapacityRange reads the inclusive start and end dates for a capacity plan, defaulting to
+// the next two weeks, returning an error message when they're invalid
+func parseCapacityRange(r *http.Request) (time.Time, time.Time, string) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return parseDateRange(r, today, today.AddDate(0, 0, 13), models.MaxCapacityWindowDays)
+}
+
+// parseDateRange reads inclusive start and end dates spanning at most maxDays, falling back to
+// the given defaults, returning an error message when they're invalid
+func parseDateRange(r *http.Request, start, end time.Time, maxDays int) (time.Time, time.Time, string) {
+	if startStr := r.URL.Query().Get("start"); startStr != "" {
+		parsed, err := time.Parse("2006-01-02", startStr)
+		if err != nil {
+			return start, end, "Invalid start date format (use YYYY-MM-DD)"
+		}
+		start = parsed
+	}
+	if endStr := r.URL.Query().Get("end"); endStr != "" {
+		parsed, err := time.Parse("2006-01-02", endStr)
+		if err != nil {
+			return start, end, "Invalid end date format (use YYYY-MM-DD)"
+		}
+		end = parsed
+	}
+
+	if end.Before(start) {
+		return start, end, "end must not be before start"
+	}
+	if end.Sub(start) >= time.Duration(maxDays)*24*time.Hour {
+		return start, end, fmt.Sprintf("date range cannot exceed %d days", maxDays)
+	}
+	return start, end, ""
+}
+
+// GetTaskFilters returns the org's named JQL filters for the team task view
+func (h *JiraHandlers) GetTaskFilters(w http.ResponseWriter, r *http.Request) {
+	if requireJiraAccess(w, r) == nil {
+		return
+	}
+
+	orgSettings, err := h.orgJiraRepo.Get(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get Jira settings")
+		return
+	}
+
+	filters := []models.JiraTaskFilter{}
+	if orgSettings != nil && orgSettings.TaskFilters != nil {
+		filters = orgSettings.TaskFilters
+	}
+	respondJSON(w, http.StatusOK, filters)
+}
+
+// UpdateTaskFilters replaces the org's named JQL filters for the team task view (admin only)
+func (h *JiraHandlers) UpdateTaskFilters(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	var req models.UpdateJiraTaskFiltersRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Filters == nil {
+		req.Filters = []models.JiraTaskFilter{}
+	}
+
+	updated, err := h.orgJiraRepo.UpdateTaskFilters(r.Context(), req.Filters)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to update Jira task filters", err)
+		respondError(w, http.StatusInternalServerError, "Failed to update task filters")
+		return
+	}
+	if !updated {
+		respondError(w, http.StatusNotFound, "Jira is not connected")
+		return
+	}
+
+	h.broker.Publish(r.Context(), events.JiraTaskFiltersChanged, nil)
+	respondJSON(w, http.StatusOK, req.Filters)
+}
+
 // JiraUserWithMapping extends JiraUser with mapping info
 type JiraUserWithMapping struct {
 	models.JiraUser
@@ -573,7 +1424,7 @@ type JiraUserWithMapping struct {
 
 // GetJiraUsers returns all Jira users for mapping to employees (admin only)
 func (h *JiraHandlers) GetJiraUsers(w http.ResponseWriter, r *http.Request) {
-	if requireAdmin(w, r) == nil {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
 		return
 	}
 
@@ -621,7 +1472,7 @@ func (h *JiraHandlers) GetJiraUsers(w http.ResponseWriter, r *http.Request) {
 
 // AutoMatchJiraUsers attempts to match Jira users to employees by email (admin only)
 func (h *JiraHandlers) AutoMatchJiraUsers(w http.ResponseWriter, r *http.Request) {
-	if requireAdmin(w, r) == nil {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
 		return
 	}
 
@@ -656,7 +1507,8 @@ func (h *JiraHandlers) AutoMatchJiraUsers(w http.ResponseWriter, r *http.Request
 
 	// Match employees to Jira users by email
 	matched := 0
-	for _, emp := range employees {
+	for i := range employees {
+		emp := &employees[i]
 		// Skip if already matched
 		if emp.JiraAccountID != nil && *emp.JiraAccountID != "" {
 			continue
@@ -667,20 +1519,50 @@ func (h *JiraHandlers) AutoMatchJiraUsers(w http.ResponseWriter, r *http.Request
 			if err := h.userRepo.UpdateJiraAccountID(r.Context(), emp.ID, &jiraUser.AccountID); err != nil {
 				continue // Skip errors, continue matching others
 			}
+			emp.JiraAccountID = &jiraUser.AccountID
 			matched++
 		}
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"matched": matched,
-		"total_employees": len(employees),
-		"total_jira_users": len(jiraUsers),
-	})
+	// Keep the leftovers so admins can finish mapping without rescanning Jira
+	report := services.BuildJiraUserMatchReport(employees, jiraUsers, matched, time.Now())
+	if _, err := h.orgJiraRepo.SaveUserMatchReport(r.Context(), report); err != nil {
+		h.logger.LogError(r.Context(), "Failed to save Jira user match report", err)
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// GetUnmatchedJiraUsers returns the employees and Jira accounts left unmatched by the last
+// auto-match, with likely matches for each employee, minus any mapped since (admin only)
+func (h *JiraHandlers) GetUnmatchedJiraUsers(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	report, err := h.orgJiraRepo.GetUserMatchReport(r.Context())
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get Jira user match report", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get unmatched users")
+		return
+	}
+	if report == nil {
+		respondError(w, http.StatusNotFound, "No match report yet. Run auto-match first.")
+		return
+	}
+
+	employees, err := h.userRepo.GetAll(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch employees")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, services.RefreshJiraUserMatchReport(report, employees))
 }
 
 // UpdateUserJiraMapping manually sets a user's Jira account ID (admin only)
 func (h *JiraHandlers) UpdateUserJiraMapping(w http.ResponseWriter, r *http.Request) {
-	if requireAdmin(w, r) == nil {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
 		return
 	}
 
@@ -710,7 +1592,7 @@ func (h *JiraHandlers) UpdateUserJiraMapping(w http.ResponseWriter, r *http.Requ
 
 // DisconnectJira removes the organization-wide Jira connection (admin only)
 func (h *JiraHandlers) DisconnectJira(w http.ResponseWriter, r *http.Request) {
-	if requireAdmin(w, r) == nil {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
 		return
 	}
 
@@ -731,14 +1613,16 @@ type TeamTaskEmployee struct {
 	AvatarURL *string `json:"avatar_url"`
 }
 
-// TeamTask represents a Jira issue with employee info and time off impact
+// TeamTask represents a Jira issue, or another tracker's work item, with employee info and time off impact
 type TeamTask struct {
 	models.JiraIssue
 	Employee      TeamTaskEmployee      `json:"employee"`
 	TimeOffImpact *models.TimeOffImpact `json:"time_off_impact,omitempty"`
+	SyncedAt      *time.Time            `json:"synced_at,omitempty"` // When the cached copy was synced; omitted when fetched live
 }
 
-// GetTeamTasks returns Jira issues for all of a supervisor's direct reports
+// GetTeamTasks returns Jira issues for all of a supervisor's direct reports, optionally narrowed
+// by one of the org's named task filters (?filter=name)
 // Only supervisors and admins can access this endpoint
 func (h *JiraHandlers) GetTeamTasks(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireJiraAccess(w, r)
@@ -746,16 +1630,7 @@ func (h *JiraHandlers) GetTeamTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get direct reports for the supervisor (admins get all users)
-	var directReports []models.User
-	var err error
-
-	if currentUser.IsAdmin() {
-		directReports, err = h.userRepo.GetAll(r.Context())
-	} else {
-		directReports, err = h.userRepo.GetDirectReportsBySupervisorID(r.Context(), currentUser.ID)
-	}
-
+	directReports, err := teamMembers(r.Context(), h.userRepo, currentUser)
 	if err != nil {
 		h.logger.WithContext(r.Context()).Error("Failed to fetch direct reports", "user_id", currentUser.ID, "error", err)
 		respondError(w, http.StatusInternalServerError, "Failed to fetch direct reports")
@@ -775,95 +1650,95 @@ func (h *JiraHandlers) GetTeamTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse max results per user
-	maxPerUser := 20
-	if maxStr := r.URL.Query().Get("max_per_user"); maxStr != "" {
-		if m, err := strconv.Atoi(maxStr); err == nil && m > 0 && m <= 50 {
-			maxPerUser = m
+	maxPerUser := parseMaxPerUser(r)
+
+	// A named filter narrows the view with admin-defined JQL
+	var filter *models.JiraTaskFilter
+	if name := r.URL.Query().Get("filter"); name != "" {
+		orgSettings, err := h.orgJiraRepo.Get(r.Context())
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get Jira settings")
+			return
+		}
+		var ok bool
+		if orgSettings != nil {
+			filter, ok = orgSettings.TaskFilter(name)
+		}
+		if !ok {
+			respondError(w, http.StatusBadRequest, "Unknown task filter")
+			return
 		}
 	}
 
-	// Get Jira client with automatic token refresh
-	client, err := h.getJiraClient(r.Context())
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to connect to Jira")
-		return
+	accountIDs := make([]string, len(usersWithJira))
+	for i, u := range usersWithJira {
+		accountIDs[i] = *u.JiraAccountID
 	}
 
-	// Pre-fetch time off for all users (batch query)
-	userTimeOffMap := make(map[int64][]models.TimeOffRequest)
-	if h.timeOffRepo != nil {
-		// Fetch time off for all users in the date range (now to far future for impact calc)
-		for _, u := range usersWithJira {
-			timeOff, err := h.timeOffRepo.GetApprovedFutureTimeOffByUser(r.Context(), u.ID)
-			if err != nil {
-				h.logger.WithContext(r.Context()).Warn("Failed to fetch time off for user", "user_id", u.ID, "error", err)
-				continue
-			}
-			userTimeOffMap[u.ID] = timeOff
+	// Serve from the issue cache; accounts that have never been synced, or all of them on refresh,
+	// are fetched live with bounded parallelism to avoid overwhelming Jira API rate limits.
+	// The cache can't evaluate filter JQL, so filtered views are always fetched live.
+	var issuesByAccount map[string][]models.JiraIssue
+	var syncTimes map[string]time.Time
+	if filter != nil {
+		client, err := h.getJiraClient(r.Context())
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to connect to Jira")
+			return
+		}
+		issuesByAccount = services.FetchFilteredJiraIssues(r.Context(), client, accountIDs, filter.JQL, maxPerUser, h.maxConcurrentAPIReqs)
+	} else {
+		issuesByAccount, syncTimes, err = h.issueSync.Load(r.Context(), h.ConnectJira, accountIDs, r.URL.Query().Get("refresh") == "true", h.maxConcurrentAPIReqs)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to connect to Jira")
+			return
 		}
 	}
 
-	// Fetch tasks for each direct report concurrently with bounded parallelism
-	// to avoid overwhelming Jira API rate limits
-	type userTasks struct {
-		user   models.User
-		issues []models.JiraIssue
-		err    error
-	}
-
-	results := make(chan userTasks, len(usersWithJira))
-	var wg sync.WaitGroup
-
-	// Semaphore to limit concurrent Jira API requests
-	semaphore := make(chan struct{}, h.maxConcurrentAPIReqs)
-
-	for _, user := range usersWithJira {
-		wg.Add(1)
-		go func(u models.User) {
-			defer wg.Done()
-			// Acquire semaphore slot (blocks if at capacity)
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }() // Release slot when done
+	teamTasks := buildTeamTasks(r.Context(), h.timeOffRepo, h.logger, usersWithJira, func(u *models.User) string { return *u.JiraAccountID }, issuesByAccount, syncTimes, maxPerUser)
+	respondJSON(w, http.StatusOK, teamTasks)
+}
 
-			issues, err := client.GetIssuesByAccountID(*u.JiraAccountID, maxPerUser)
-			results <- userTasks{user: u, issues: issues, err: err}
-		}(user)
+// CheckSprintCapacity compares a squad's sprint scope with its capacity and flags over-commitment.
+// Capacity is working days minus company holidays and approved time off.
+func (h *JiraHandlers) CheckSprintCapacity(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireJiraAccess(w, r)
+	if currentUser == nil {
+		return
 	}
 
-	// Close results channel when all goroutines complete
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect all tasks with employee info and time off impact
-	var teamTasks []TeamTask
-	for result := range results {
-		if result.err != nil {
-			h.logger.WithContext(r.Context()).Error("Failed to fetch Jira issues for user", "user_id", result.user.ID, "error", result.err)
-			continue
-		}
+	if h.sprintCapacity == nil {
+		respondError(w, http.StatusServiceUnavailable, "Sprint capacity checks are not available")
+		return
+	}
 
-		employee := TeamTaskEmployee{
-			ID:        result.user.ID,
-			FirstName: result.user.FirstName,
-			LastName:  result.user.LastName,
-			Email:     result.user.Email,
-			AvatarURL: result.user.AvatarURL,
-		}
+	var req models.SprintCapacityCheckRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
 
-		// Get time off for this user
-		userTimeOff := userTimeOffMap[result.user.ID]
+	client, err := h.getJiraClient(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to connect to Jira")
+		return
+	}
 
-		for _, issue := range result.issues {
-			teamTasks = append(teamTasks, TeamTask{
-				JiraIssue:     issue,
-				Employee:      employee,
-				TimeOffImpact: database.CalculateTimeOffImpact(issue.DueDate, userTimeOff),
-			})
-		}
+	report, err := h.sprintCapacity.Check(r.Context(), currentUser, client, &req)
+	switch {
+	case errors.Is(err, services.ErrSquadNotFound):
+		respondError(w, http.StatusNotFound, "Squad not found")
+		return
+	case errors.Is(err, services.ErrSquadAccessDenied):
+		respondError(w, http.StatusForbidden, "Forbidden: "+err.Error())
+		return
+	case errors.Is(err, services.ErrNoUpcomingSprint), errors.Is(err, services.ErrSprintNotScheduled):
+		respondError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	case err != nil:
+		h.logger.WithContext(r.Context()).Error("Failed to check sprint capacity", "squad_id", req.SquadID, "error", err)
+		respondError(w, http.StatusBadGateway, "Failed to fetch sprint from Jira")
+		return
 	}
 
-	respondJSON(w, http.StatusOK, teamTasks)
+	respondJSON(w, http.StatusOK, report)
 }
@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/oauth"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestJiraHandlers_canActOnJiraIssue(t *testing.T) {
+	supervisorID := int64(2)
+	assigneeAccount, otherAccount := "acct-assignee", "acct-other"
+
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.Users[3] = &models.User{ID: 3, Role: models.RoleEmployee, SupervisorID: &supervisorID, JiraAccountID: &assigneeAccount}
+	h := NewJiraHandlers(userRepo, nil, nil, nil, nil, "", logger.Default())
+
+	assigned := &models.JiraIssue{Key: "WEB-1", Assignee: &models.JiraUser{AccountID: assigneeAccount}}
+	unassigned := &models.JiraIssue{Key: "WEB-2"}
+
+	tests := []struct {
+		name  string
+		user  *models.User
+		issue *models.JiraIssue
+		want  bool
+	}{
+		{"admin", &models.User{ID: 1, Role: models.RoleAdmin}, unassigned, true},
+		{"mapped assignee", userRepo.Users[3], assigned, true},
+		{"assignee's supervisor", &models.User{ID: 2, Role: models.RoleSupervisor}, assigned, true},
+		{"another supervisor", &models.User{ID: 4, Role: models.RoleSupervisor}, assigned, false},
+		{"another employee", &models.User{ID: 5, Role: models.RoleEmployee, JiraAccountID: &otherAccount}, assigned, false},
+		{"unassigned issue", &models.User{ID: 2, Role: models.RoleSupervisor}, unassigned, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := h.canActOnJiraIssue(context.Background(), tt.user, tt.issue)
+			if err != nil {
+				t.Fatalf("canActOnJiraIssue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("canActOnJiraIssue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJiraHandlers_HandleWebhook(t *testing.T) {
+	const secret = "s3cret"
+	updated := `{"webhookEvent":"jira:issue_updated","issue":{"id":"1","key":"WEB-1","fields":{"summary":"Fix login","updated":"2024-03-04T15:00:00.000+0000"}}}`
+	deleted := `{"webhookEvent":"jira:issue_deleted","issue":{"id":"2","key":"WEB-2","fields":{}}}`
+
+	tests := []struct {
+		name           string
+		body           string
+		signature      string
+		expectedStatus int
+		wantCached     []string
+		wantPublished  bool
+	}{
+		{"updated issue is cached", updated, signJiraWebhookBody(secret, updated), http.StatusNoContent, []string{"WEB-1", "WEB-2"}, true},
+		{"deleted issue is removed", deleted, signJiraWebhookBody(secret, deleted), http.StatusNoContent, nil, true},
+		{"bad signature", updated, signJiraWebhookBody("other", updated), http.StatusUnauthorized, []string{"WEB-2"}, false},
+		{"unsigned", updated, "", http.StatusUnauthorized, []string{"WEB-2"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgJiraRepo := mocks.NewMockOrgJiraRepository()
+			orgJiraRepo.Settings = &models.OrgJiraSettings{SiteURL: "https://acme.atlassian.net"}
+			cache := mocks.NewMockJiraIssueCacheRepository()
+			cache.Issues["WEB-2"] = mocks.CachedJiraIssue{Issue: models.JiraIssue{Key: "WEB-2"}}
+			broker := events.NewBroker(4)
+			published, unsubscribe := broker.Subscribe()
+			defer unsubscribe()
+			h := NewJiraHandlers(mocks.NewMockUserRepository(), orgJiraRepo, nil, nil, nil, "", logger.Default()).
+				WithWebhookIngestion(secret, cache, broker)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/jira/webhook", strings.NewReader(tt.body))
+			if tt.signature != "" {
+				req.Header.Set("X-Hub-Signature", tt.signature)
+			}
+			rr := httptest.NewRecorder()
+			h.HandleWebhook(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			for _, key := range tt.wantCached {
+				if _, ok := cache.Issues[key]; !ok {
+					t.Errorf("%s missing from cache", key)
+				}
+			}
+			if len(cache.Issues) != len(tt.wantCached) {
+				t.Errorf("cache holds %d issues, want %d", len(cache.Issues), len(tt.wantCached))
+			}
+			if (len(published) == 1) != tt.wantPublished {
+				t.Errorf("published %d events, want published = %v", len(published), tt.wantPublished)
+			}
+			if tt.wantPublished && orgJiraRepo.Settings.LastWebhookAt == nil {
+				t.Error("webhook delivery wasn't recorded for Jira health")
+			}
+		})
+	}
+}
+
+func signJiraWebhookBody(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestJiraHandlers_HandleOAuthCallback_Relayed(t *testing.T) {
+	tests := []struct {
+		name      string
+		relayedBy int64
+		want      string
+	}{
+		// The state was checked; there's no Atlassian to exchange the code with
+		{"for the user who started the flow", 1, "oauth_not_configured"},
+		{"for another user", 2, "state_mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateStore := oauth.NewMemoryStateStore(time.Minute)
+			csrf := middleware.NewCSRF(middleware.CSRFConfig{Secret: []byte("test-secret"), CookieName: "api_csrf"})
+			userRepo := mocks.NewMockUserRepository()
+			userRepo.AddUser(&models.User{ID: 1, OrgID: 1, Role: models.RoleAdmin})
+			h := NewJiraHandlers(userRepo, mocks.NewMockOrgJiraRepository(), nil, nil, stateStore, "https://dash.example.com", logger.Default()).
+				WithOAuthStateBinding(csrf)
+
+			// The frontend server relays the callback without the browser's cookies
+			state, _ := stateStore.Create(context.Background(), 1)
+			req := httptest.NewRequest(http.MethodGet, "/api/jira/oauth/callback?code=abc&state="+state, nil)
+			req = req.WithContext(ctxWithUser(&models.User{ID: tt.relayedBy, OrgID: 1, Role: models.RoleAdmin}))
+			rr := httptest.NewRecorder()
+
+			h.HandleOAuthCallback(rr, req)
+
+			if got := rr.Header().Get("Location"); got != "https://dash.example.com/settings?jira_error="+tt.want {
+				t.Errorf("redirect = %q", got)
+			}
+		})
+	}
+}
+
+func TestJiraHandlers_GetTeamTasks_ServesFromCache(t *testing.T) {
+	supervisor := &models.User{ID: 1, Role: models.RoleSupervisor}
+	accounts := []string{"acct-ada", "acct-alan"}
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.Users[2] = &models.User{ID: 2, FirstName: "Ada", SupervisorID: &supervisor.ID, JiraAccountID: &accounts[0]}
+	userRepo.Users[3] = &models.User{ID: 3, FirstName: "Alan", SupervisorID: &supervisor.ID, JiraAccountID: &accounts[1]}
+
+	syncedAt := time.Now().Add(-5 * time.Minute).Truncate(time.Second)
+	cache := mocks.NewMockJiraIssueCacheRepository()
+	for i, key := range []string{"WEB-1", "WEB-2", "WEB-3"} {
+		issue := models.JiraIssue{Key: key, Assignee: &models.JiraUser{AccountID: accounts[0]}, Updated: syncedAt.Add(-time.Duration(i) * time.Hour)}
+		_, _ = cache.Upsert(context.Background(), &issue, false)
+	}
+	cache.SyncTimes[accounts[0]] = syncedAt
+	cache.SyncTimes[accounts[1]] = syncedAt
+
+	// No org Jira settings: any live fetch would fail
+	h := NewJiraHandlers(userRepo, mocks.NewMockOrgJiraRepository(), nil, nil, nil, "", logger.Default()).
+		WithIssueSync(services.NewJiraIssueSyncService(cache, userRepo, 15*time.Minute))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/jira/team-tasks?max_per_user=2", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), supervisor))
+	rr := httptest.NewRecorder()
+	h.GetTeamTasks(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var tasks []TeamTask
+	if err := json.Unmarshal(rr.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Key != "WEB-1" || tasks[1].Key != "WEB-2" {
+		t.Fatalf("tasks = %+v, want Ada's two most recently updated issues", tasks)
+	}
+	if tasks[0].SyncedAt == nil || !tasks[0].SyncedAt.Equal(syncedAt) {
+		t.Errorf("synced_at = %v, want %v", tasks[0].SyncedAt, syncedAt)
+	}
+
+	// A refresh has to go to Jira, which isn't connected
+	req = httptest.NewRequest(http.MethodGet, "/api/jira/team-tasks?refresh=true", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), supervisor))
+	rr = httptest.NewRecorder()
+	h.GetTeamTasks(rr, req)
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("refresh without Jira: expected status 500, got %d", rr.Code)
+	}
+}
+
+func TestJiraHandlers_GetUserWorklogs(t *testing.T) {
+	supervisor := &models.User{ID: 1, Role: models.RoleSupervisor}
+	otherSupervisorID := int64(9)
+	account := "acct-ada"
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.Users[2] = &models.User{ID: 2, SupervisorID: &supervisor.ID, JiraAccountID: &account}
+	userRepo.Users[3] = &models.User{ID: 3, SupervisorID: &supervisor.ID}
+	userRepo.Users[4] = &models.User{ID: 4, SupervisorID: &otherSupervisorID, JiraAccountID: &account}
+
+	tests := []struct {
+		name           string
+		userID         string
+		query          string
+		expectedStatus int
+	}{
+		{"someone else's report", "4", "", http.StatusForbidden},
+		{"unknown user", "99", "", http.StatusNotFound},
+		{"not linked to Jira", "3", "", http.StatusBadRequest},
+		{"malformed date", "2", "?start=03/02/2026", http.StatusBadRequest},
+		{"end before start", "2", "?start=2026-03-10&end=2026-03-02", http.StatusBadRequest},
+		{"range too long", "2", "?start=2026-01-01&end=2026-06-30", http.StatusBadRequest},
+		{"valid range without a Jira connection", "2", "?start=2026-03-02&end=2026-03-08", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewJiraHandlers(userRepo, mocks.NewMockOrgJiraRepository(), nil, nil, nil, "", logger.Default()).
+				WithWorklogs(services.NewWorklogService(mocks.NewMockTimeOffRepository(), &config.Config{}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/jira/worklogs/user/"+tt.userID+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("userId", tt.userID)
+			req = req.WithContext(ctxWithUserFrom(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), supervisor))
+			rr := httptest.NewRecorder()
+			h.GetUserWorklogs(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestJiraHandlers_GetCapacity(t *testing.T) {
+	tests := []struct {
+		name           string
+		user           *models.User
+		query          string
+		expectedStatus int
+	}{
+		{"employees are forbidden", &models.User{ID: 2, Role: models.RoleEmployee}, "", http.StatusForbidden},
+		{"invalid squad", &models.User{ID: 1, Role: models.RoleSupervisor}, "?squad=abc", http.StatusBadRequest},
+		{"end before start", &models.User{ID: 1, Role: models.RoleSupervisor}, "?start=2026-03-10&end=2026-03-02", http.StatusBadRequest},
+		{"range too long", &models.User{ID: 1, Role: models.RoleSupervisor}, "?start=2026-01-01&end=2026-06-30", http.StatusBadRequest},
+		{"valid range without a Jira connection", &models.User{ID: 1, Role: models.RoleSupervisor}, "?squad=3&start=2026-03-02&end=2026-03-13", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewJiraHandlers(mocks.NewMockUserRepository(), mocks.NewMockOrgJiraRepository(), nil, nil, nil, "", logger.Default()).
+				WithCapacity(services.NewCapacityService(mocks.NewMockSquadRepository(), mocks.NewMockUserRepository(), mocks.NewMockTimeOffRepository(), &config.Config{}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/capacity"+tt.query, nil)
+			req = req.WithContext(ctxWithUser(tt.user))
+			rr := httptest.NewRecorder()
+			h.GetCapacity(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestJiraHandlers_UpdateTaskFilters(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		connected      bool
+		body           string
+		expectedStatus int
+	}{
+		{"admin saves filters", admin, true, `{"filters":[{"name":"Exclude Done","jql":"status != Done"}]}`, http.StatusOK},
+		{"supervisors can't edit filters", &models.User{ID: 2, Role: models.RoleSupervisor}, true, `{"filters":[]}`, http.StatusForbidden},
+		{"invalid filter", admin, true, `{"filters":[{"name":"Bugs","jql":"type = Bug ORDER BY key"}]}`, http.StatusBadRequest},
+		{"jira not connected", admin, false, `{"filters":[]}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgJiraRepo := mocks.NewMockOrgJiraRepository()
+			if tt.connected {
+				orgJiraRepo.Settings = &models.OrgJiraSettings{TaskFilters: []models.JiraTaskFilter{}}
+			}
+			broker := events.NewBroker(4)
+			published, unsubscribe := broker.Subscribe()
+			defer unsubscribe()
+			h := NewJiraHandlers(mocks.NewMockUserRepository(), orgJiraRepo, nil, nil, nil, "", logger.Default()).
+				WithWebhookIngestion("", nil, broker)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/jira/task-filters", strings.NewReader(tt.body))
+			req = req.WithContext(ctxWithUserFrom(req.Context(), tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.UpdateTaskFilters(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			saved := rr.Code == http.StatusOK
+			if saved && (len(orgJiraRepo.Settings.TaskFilters) != 1 || len(published) != 1) {
+				t.Errorf("filters = %v with %d events published, want the filter saved and announced", orgJiraRepo.Settings.TaskFilters, len(published))
+			}
+			if !saved && len(published) != 0 {
+				t.Error("rejected update published an event")
+			}
+		})
+	}
+}
+
+func TestJiraHandlers_GetTeamTasks_UnknownFilter(t *testing.T) {
+	supervisor := &models.User{ID: 1, Role: models.RoleSupervisor}
+	account := "acct-ada"
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.Users[2] = &models.User{ID: 2, SupervisorID: &supervisor.ID, JiraAccountID: &account}
+	orgJiraRepo := mocks.NewMockOrgJiraRepository()
+	orgJiraRepo.Settings = &models.OrgJiraSettings{TaskFilters: []models.JiraTaskFilter{{Name: "Exclude Done", JQL: "status != Done"}}}
+	h := NewJiraHandlers(userRepo, orgJiraRepo, nil, nil, nil, "", logger.Default())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/jira/tasks/team?filter=Only+bugs", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), supervisor))
+	rr := httptest.NewRecorder()
+	h.GetTeamTasks(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestJiraHandlers_GetJiraHealth_Diagnostics(t *testing.T) {
+	orgJiraRepo := mocks.NewMockOrgJiraRepository()
+	orgJiraRepo.Settings = &models.OrgJiraSettings{CloudID: "cloud123", SiteURL: "https://acme.atlassian.net", OAuthTokenExpiresAt: time.Now().Add(time.Hour)}
+	syncedAt := time.Now().Add(-5 * time.Minute).Truncate(time.Second)
+	cacheRepo := mocks.NewMockJiraIssueCacheRepository()
+	cacheRepo.SyncTimes["acct-ada"] = syncedAt
+
+	// Without OAuth configured the stored tokens can't be verified
+	h := NewJiraHandlers(mocks.NewMockUserRepository(), orgJiraRepo, nil, nil, nil, "", logger.Default()).
+		WithIssueSync(services.NewJiraIssueSyncService(cacheRepo, nil, time.Hour))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/jira/health", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleAdmin}))
+	rr := httptest.NewRecorder()
+	h.GetJiraHealth(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	var health models.JiraHealth
+	if err := json.NewDecoder(rr.Body).Decode(&health); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if health.Status != models.JiraHealthFailing {
+		t.Errorf("Status = %s, want failing", health.Status)
+	}
+	if health.TokenCheck == nil || health.TokenCheck.Verified || health.TokenCheck.Error == "" {
+		t.Errorf("TokenCheck = %+v, want an unverified check with an error", health.TokenCheck)
+	}
+	if health.LastSyncAt == nil || !health.LastSyncAt.Equal(syncedAt) {
+		t.Errorf("LastSyncAt = %v, want %v", health.LastSyncAt, syncedAt)
+	}
+	if health.APICalls == nil || health.APICalls.WindowMinutes != 60 {
+		t.Errorf("APICalls = %+v, want an hour of call stats", health.APICalls)
+	}
+}
+
+func TestJiraHandlers_GetUnmatchedJiraUsers(t *testing.T) {
+	orgJiraRepo := mocks.NewMockOrgJiraRepository()
+	orgJiraRepo.Settings = &models.OrgJiraSettings{CloudID: "cloud123"}
+	userRepo := mocks.NewMockUserRepository()
+	h := NewJiraHandlers(userRepo, orgJiraRepo, nil, nil, nil, "", logger.Default())
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/jira/users/unmatched", nil)
+		req = req.WithContext(ctxWithUserFrom(req.Context(), admin))
+		rr := httptest.NewRecorder()
+		h.GetUnmatchedJiraUsers(rr, req)
+		return rr
+	}
+
+	if rr := get(); rr.Code != http.StatusNotFound {
+		t.Fatalf("before auto-match status = %d, want 404", rr.Code)
+	}
+
+	userRepo.Users[2] = &models.User{ID: 2, FirstName: "Grace", LastName: "Hopper", IsActive: true}
+	userRepo.Users[3] = &models.User{ID: 3, FirstName: "Alan", LastName: "Turing", IsActive: true}
+	employees := []models.User{*userRepo.Users[2], *userRepo.Users[3]}
+	jiraUsers := []models.JiraUser{{AccountID: "acct-grace", DisplayName: "Grace Hopper"}, {AccountID: "acct-alan", DisplayName: "Alan Turing"}}
+	if _, err := orgJiraRepo.SaveUserMatchReport(context.Background(), services.BuildJiraUserMatchReport(employees, jiraUsers, 0, time.Now())); err != nil {
+		t.Fatalf("SaveUserMatchReport: %v", err)
+	}
+
+	// Grace is mapped by hand after the report was saved
+	graceAccount := "acct-grace"
+	userRepo.Users[2].JiraAccountID = &graceAccount
+
+	rr := get()
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	var report models.JiraUserMatchReport
+	if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(report.UnmatchedEmployees) != 1 || report.UnmatchedEmployees[0].ID != 3 {
+		t.Fatalf("UnmatchedEmployees = %+v, want only Alan", report.UnmatchedEmployees)
+	}
+	if s := report.UnmatchedEmployees[0].Suggestions; len(s) != 1 || s[0].AccountID != "acct-alan" {
+		t.Errorf("Suggestions = %+v, want acct-alan", s)
+	}
+	if len(report.UnmatchedJiraUsers) != 1 || report.UnmatchedJiraUsers[0].AccountID != "acct-alan" {
+		t.Errorf("UnmatchedJiraUsers = %+v, want only acct-alan", report.UnmatchedJiraUsers)
+	}
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// KudosHandlers handles recognition between users
+type KudosHandlers struct {
+	kudosRepo     repository.KudosRepository
+	userRepo      repository.UserRepository
+	notifications *services.NotificationService
+	companyValues []string
+	logger        *logger.Logger
+}
+
+// NewKudosHandlers creates a new kudos handlers instance; notifications may be nil
+func NewKudosHandlers(kudosRepo repository.KudosRepository, userRepo repository.UserRepository, notifications *services.NotificationService) *KudosHandlers {
+	return &KudosHandlers{
+		kudosRepo:     kudosRepo,
+		userRepo:      userRepo,
+		notifications: notifications,
+		logger:        logger.Default().WithComponent("kudos-handlers"),
+	}
+}
+
+// WithCompanyValues restricts the values kudos can be tagged with. Any tag is accepted when empty.
+func (h *KudosHandlers) WithCompanyValues(values []string) *KudosHandlers {
+	h.companyValues = values
+	return h
+}
+
+// GiveKudos godoc
+// @Summary Give kudos
+// @Description Recognizes another user, optionally tagged with a company value. The recipient is notified in-app.
+// @Tags Kudos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.KudosRequest true "Kudos"
+// @Success 201 {object} models.Kudos "Created kudos"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /kudos [post]
+func (h *KudosHandlers) GiveKudos(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+	if currentUser.IsGuest() {
+		respondError(w, http.StatusForbidden, "Forbidden: guests cannot give kudos")
+		return
+	}
+
+	var req models.KudosRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+	if req.ToUserID == currentUser.ID {
+		respondError(w, http.StatusBadRequest, "You cannot give kudos to yourself")
+		return
+	}
+	if req.CompanyValue != nil && len(h.companyValues) > 0 {
+		value, ok := h.matchCompanyValue(*req.CompanyValue)
+		if !ok {
+			respondError(w, http.StatusBadRequest, "company_value must be one of: "+strings.Join(h.companyValues, ", "))
+			return
+		}
+		req.CompanyValue = &value
+	}
+
+	recipient, err := h.userRepo.GetByID(r.Context(), req.ToUserID)
+	if err != nil || recipient == nil || !recipient.IsActive || recipient.IsGuest() {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	kudos, err := h.kudosRepo.Create(r.Context(), currentUser.ID, &req)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to create kudos", err, "to_user_id", req.ToUserID)
+		respondError(w, http.StatusInternalServerError, "Failed to give kudos")
+		return
+	}
+
+	h.notifications.NotifyKudos(r.Context(), kudos, currentUser)
+	respondJSON(w, http.StatusCreated, kudos)
+}
+
+// matchCompanyValue finds the configured company value matching a tag, ignoring case
+func (h *KudosHandlers) matchCompanyValue(tag string) (string, bool) {
+	for _, value := range h.companyValues {
+		if strings.EqualFold(value, tag) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// GetFeed godoc
+// @Summary Get the kudos feed
+// @Description Returns kudos received by the current user's team, newest first. The team is the user, their supervisor, peers who share that supervisor, and their direct reports. Admins and viewers see everyone's kudos.
+// @Tags Kudos
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(50)
+// @Success 200 {object} PaginatedResponse "Kudos"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /kudos [get]
+func (h *KudosHandlers) GetFeed(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+	if currentUser.IsGuest() {
+		respondError(w, http.StatusForbidden, "Forbidden: guests cannot view kudos")
+		return
+	}
+
+	var recipientIDs []int64
+	if !authz.CanAll(currentUser, authz.ActionOrgWideView) {
+		ids, err := h.teamIDs(r.Context(), currentUser)
+		if err != nil {
+			h.logger.LogError(r.Context(), "Failed to load team for kudos feed", err)
+			respondError(w, http.StatusInternalServerError, "Failed to fetch kudos")
+			return
+		}
+		recipientIDs = ids
+	}
+
+	p := parsePagination(r)
+	feed, total, err := h.kudosRepo.ListFeed(r.Context(), recipientIDs, p.PerPage, p.Offset)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list kudos", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch kudos")
+		return
+	}
+
+	respondPaginated(w, feed, total, p)
+}
+
+// teamIDs returns the user, their supervisor and peers, and their direct reports
+func (h *KudosHandlers) teamIDs(ctx context.Context, user *models.User) ([]int64, error) {
+	ids := []int64{user.ID}
+	if user.SupervisorID != nil {
+		ids = append(ids, *user.SupervisorID)
+		peers, err := h.userRepo.GetDirectReportsBySupervisorID(ctx, *user.SupervisorID)
+		if err != nil {
+			return nil, err
+		}
+		for _, peer := range peers {
+			if peer.ID != user.ID {
+				ids = append(ids, peer.ID)
+			}
+		}
+	}
+
+	reports, err := h.userRepo.GetDirectReportsBySupervisorID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, report := range reports {
+		ids = append(ids, report.ID)
+	}
+	return ids, nil
+}
+
+// GetDepartmentCounts godoc
+// @Summary Get kudos counts by department
+// @Description Returns how many kudos each department's members received per month, newest month first (admin only)
+// @Tags Kudos
+// @Produce json
+// @Security BearerAuth
+// @Param months query int false "Number of months including the current one, up to 24" default(6)
+// @Success 200 {array} models.KudosDepartmentCount "Monthly counts"
+// @Failure 400 {object} map[string]interface{} "Invalid months"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /kudos/departments [get]
+func (h *KudosHandlers) GetDepartmentCounts(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionKudosReport) == nil {
+		return
+	}
+
+	months := models.DefaultKudosMonths
+	if v := r.URL.Query().Get("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > models.MaxKudosMonths {
+			respondError(w, http.StatusBadRequest, "months must be between 1 and "+strconv.Itoa(models.MaxKudosMonths))
+			return
+		}
+		months = n
+	}
+
+	now := time.Now().UTC()
+	since := time.Date(now.Year(), now.Month()-time.Month(months-1), 1, 0, 0, 0, 0, time.UTC)
+	counts, err := h.kudosRepo.CountByDepartment(r.Context(), since)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to count kudos by department", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch kudos counts")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, counts)
+}
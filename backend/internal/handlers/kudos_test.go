@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestKudosHandlers(t *testing.T) {
+	supervisorID := int64(2)
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, Role: models.RoleSupervisor, FirstName: "Sam", LastName: "Lee", IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, Role: models.RoleEmployee, SupervisorID: &supervisorID, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 4, Role: models.RoleEmployee, SupervisorID: &supervisorID, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 5, Role: models.RoleEmployee, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 6, Role: models.RoleEmployee, IsActive: false})
+	kudosRepo := mocks.NewMockKudosRepository()
+	kudosRepo.Departments[3] = "Engineering"
+	notificationRepo := mocks.NewMockNotificationRepository()
+	h := NewKudosHandlers(kudosRepo, userRepo, services.NewNotificationService(notificationRepo, userRepo, nil)).
+		WithCompanyValues([]string{"Ownership", "Curiosity"})
+
+	do := func(handler http.HandlerFunc, method string, currentUserID int64, query, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/kudos"+query, strings.NewReader(body))
+		req = req.WithContext(ctxWithUserFrom(req.Context(), userRepo.Users[currentUserID]))
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+
+	t.Run("give", func(t *testing.T) {
+		for _, tt := range []struct {
+			name          string
+			currentUserID int64
+			body          string
+			want          int
+		}{
+			{"to a peer", 4, `{"to_user_id":3,"message":"Great demo","company_value":"ownership"}`, http.StatusCreated},
+			{"to their manager", 3, `{"to_user_id":2,"message":"Thanks for the support"}`, http.StatusCreated},
+			{"across the org", 2, `{"to_user_id":5,"message":"Nice fix"}`, http.StatusCreated},
+			{"to self", 3, `{"to_user_id":3,"message":"Me"}`, http.StatusBadRequest},
+			{"unknown value", 3, `{"to_user_id":4,"message":"Hi","company_value":"Speed"}`, http.StatusBadRequest},
+			{"inactive recipient", 3, `{"to_user_id":6,"message":"Hi"}`, http.StatusNotFound},
+			{"missing recipient", 3, `{"to_user_id":99,"message":"Hi"}`, http.StatusNotFound},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				if rr := do(h.GiveKudos, http.MethodPost, tt.currentUserID, "", tt.body); rr.Code != tt.want {
+					t.Errorf("GiveKudos() status = %d, want %d: %s", rr.Code, tt.want, rr.Body.String())
+				}
+			})
+		}
+
+		if got := *kudosRepo.Kudos[0].CompanyValue; got != "Ownership" {
+			t.Errorf("company value = %q, want the configured spelling", got)
+		}
+		if len(notificationRepo.Notifications) != 3 {
+			t.Errorf("notifications = %d, want 3", len(notificationRepo.Notifications))
+		}
+	})
+
+	t.Run("feed", func(t *testing.T) {
+		feed := func(currentUserID int64) []models.Kudos {
+			rr := do(h.GetFeed, http.MethodGet, currentUserID, "", "")
+			if rr.Code != http.StatusOK {
+				t.Fatalf("GetFeed() status = %d: %s", rr.Code, rr.Body.String())
+			}
+			var resp struct {
+				Data []models.Kudos `json:"data"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			return resp.Data
+		}
+
+		// The employee sees kudos for their peer and manager, but not for someone outside the team
+		if got := feed(4); len(got) != 2 || got[0].ToUserID != 2 {
+			t.Errorf("GetFeed() as employee = %+v, want 2 newest first", got)
+		}
+		if got := feed(5); len(got) != 1 {
+			t.Errorf("GetFeed() as unrelated employee = %+v, want only their own kudos", got)
+		}
+		if got := feed(1); len(got) != 3 {
+			t.Errorf("GetFeed() as admin = %+v, want all 3", got)
+		}
+	})
+
+	t.Run("department counts", func(t *testing.T) {
+		if rr := do(h.GetDepartmentCounts, http.MethodGet, 2, "", ""); rr.Code != http.StatusForbidden {
+			t.Errorf("GetDepartmentCounts() as supervisor status = %d, want %d", rr.Code, http.StatusForbidden)
+		}
+		if rr := do(h.GetDepartmentCounts, http.MethodGet, 1, "?months=25", ""); rr.Code != http.StatusBadRequest {
+			t.Errorf("GetDepartmentCounts() with too many months status = %d, want %d", rr.Code, http.StatusBadRequest)
+		}
+
+		rr := do(h.GetDepartmentCounts, http.MethodGet, 1, "?months=1", "")
+		var counts []models.KudosDepartmentCount
+		if err := json.NewDecoder(rr.Body).Decode(&counts); err != nil {
+			t.Fatal(err)
+		}
+		if len(counts) != 2 || counts[0].Department != "Engineering" || counts[1].Count != 2 {
+			t.Errorf("GetDepartmentCounts() = %+v", counts)
+		}
+	})
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/linear"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// LinearHandlers serves the organization-wide Linear connection and user mapping
+type LinearHandlers struct {
+	userRepo      repository.UserRepository
+	orgLinearRepo repository.OrgLinearRepository
+	apiURL        string // Overrides the Linear API, for tests
+	logger        *logger.Logger
+}
+
+// NewLinearHandlers creates Linear handlers
+func NewLinearHandlers(userRepo repository.UserRepository, orgLinearRepo repository.OrgLinearRepository, log *logger.Logger) *LinearHandlers {
+	return &LinearHandlers{
+		userRepo:      userRepo,
+		orgLinearRepo: orgLinearRepo,
+		logger:        log.WithComponent("linear_handlers"),
+	}
+}
+
+// newClient creates a Linear client for an API key
+func (h *LinearHandlers) newClient(apiKey string) *linear.Client {
+	client := linear.NewClient(apiKey)
+	if h.apiURL != "" {
+		client.WithBaseURL(h.apiURL)
+	}
+	return client
+}
+
+// getLinearClient returns a client for the org-wide Linear connection
+func (h *LinearHandlers) getLinearClient(ctx context.Context) (*linear.Client, error) {
+	settings, err := h.orgLinearRepo.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Linear settings: %w", err)
+	}
+	if settings == nil {
+		return nil, fmt.Errorf("linear is not configured for this organization")
+	}
+	return h.newClient(settings.APIKey), nil
+}
+
+// ConnectLinear returns a work item source for the org-wide Linear connection
+func (h *LinearHandlers) ConnectLinear(ctx context.Context) (services.WorkItemSource, error) {
+	return h.getLinearClient(ctx)
+}
+
+// GetLinearSettings returns the organization-wide Linear connection status
+// Available to all authenticated users to check connection status
+func (h *LinearHandlers) GetLinearSettings(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	settings, err := h.orgLinearRepo.Get(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get Linear settings")
+		return
+	}
+
+	response := map[string]interface{}{
+		"org_configured": settings != nil,
+		"linear_user_id": currentUser.LinearUserID,
+		"can_configure":  authz.Can(currentUser, authz.ActionIntegrationManage, nil),
+	}
+	if settings != nil {
+		response["workspace_name"] = settings.WorkspaceName
+		response["workspace_url_key"] = settings.WorkspaceURLKey
+		response["configured_by_id"] = settings.ConfiguredByID
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// UpdateLinearSettings connects Linear with a workspace API key, checking the key first (admin only)
+func (h *LinearHandlers) UpdateLinearSettings(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionIntegrationManage)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.UpdateLinearSettingsRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	org, err := h.newClient(req.APIKey).GetOrganization()
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Linear API key check failed", "error", err)
+		respondError(w, http.StatusBadRequest, "Linear rejected the API key")
+		return
+	}
+
+	settings := &models.OrgLinearSettings{
+		APIKey:          req.APIKey,
+		WorkspaceName:   org.Name,
+		WorkspaceURLKey: org.URLKey,
+		ConfiguredByID:  &currentUser.ID,
+	}
+	if err := h.orgLinearRepo.Save(r.Context(), settings); err != nil {
+		h.logger.LogError(r.Context(), "Failed to save Linear settings", err)
+		respondError(w, http.StatusInternalServerError, "Failed to save Linear settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// DisconnectLinear removes the organization-wide Linear connection (admin only)
+func (h *LinearHandlers) DisconnectLinear(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	if err := h.orgLinearRepo.Delete(r.Context()); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to disconnect Linear")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetLinearUsers returns the members of the connected Linear workspace for mapping (admin only)
+func (h *LinearHandlers) GetLinearUsers(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	client, err := h.getLinearClient(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to connect to Linear")
+		return
+	}
+
+	users, err := client.GetUsers()
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to fetch Linear users", err)
+		respondError(w, http.StatusBadGateway, "Failed to fetch users from Linear")
+		return
+	}
+	if users == nil {
+		users = []models.LinearUser{}
+	}
+
+	respondJSON(w, http.StatusOK, users)
+}
+
+// UpdateUserLinearMapping links an employee to their Linear user (admin only)
+func (h *LinearHandlers) UpdateUserLinearMapping(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "userId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req models.UpdateUserLinearMappingRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if err := h.userRepo.UpdateLinearUserID(r.Context(), userID, req.LinearUserID); err != nil {
+		h.logger.LogError(r.Context(), "Failed to update Linear mapping", err, "user_id", userID)
+		respondError(w, http.StatusInternalServerError, "Failed to update Linear mapping")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":        true,
+		"linear_user_id": req.LinearUserID,
+	})
+}
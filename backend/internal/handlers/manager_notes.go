@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// ManagerNoteHandlers handles supervisors' private notes about their reports.
+// Notes are only ever visible to their author; admins can audit them when that's enabled.
+type ManagerNoteHandlers struct {
+	noteRepo    repository.ManagerNoteRepository
+	userRepo    repository.UserRepository
+	meetingRepo repository.MeetingRepository
+	adminAudit  bool
+	logger      *logger.Logger
+}
+
+// NewManagerNoteHandlers creates a new manager note handlers instance
+func NewManagerNoteHandlers(noteRepo repository.ManagerNoteRepository, userRepo repository.UserRepository, meetingRepo repository.MeetingRepository) *ManagerNoteHandlers {
+	return &ManagerNoteHandlers{
+		noteRepo:    noteRepo,
+		userRepo:    userRepo,
+		meetingRepo: meetingRepo,
+		logger:      logger.Default().WithComponent("manager-note-handlers"),
+	}
+}
+
+// WithAdminAudit lets admins read every author's notes about a user. Each audit is logged.
+func (h *ManagerNoteHandlers) WithAdminAudit(enabled bool) *ManagerNoteHandlers {
+	h.adminAudit = enabled
+	return h
+}
+
+// GetNotes godoc
+// @Summary List my notes about a user
+// @Description Returns the current user's private notes about a report, newest first. Notes written by anyone else are never included.
+// @Tags Manager Notes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(50)
+// @Success 200 {object} PaginatedResponse "Notes"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/notes [get]
+func (h *ManagerNoteHandlers) GetNotes(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionManagerNoteWrite)
+	if currentUser == nil {
+		return
+	}
+
+	subjectID, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Authors keep their notes after a report moves teams, so no management check is needed here
+	p := parsePagination(r)
+	notes, total, err := h.noteRepo.ListByAuthor(r.Context(), currentUser.ID, subjectID, p.PerPage, p.Offset)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list manager notes", err, "subject_id", subjectID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch notes")
+		return
+	}
+
+	respondPaginated(w, notes, total, p)
+}
+
+// CreateNote godoc
+// @Summary Write a note about a user
+// @Description Saves a private note about a report the current user manages, optionally linked to a meeting both of them are in
+// @Tags Manager Notes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body models.ManagerNoteRequest true "Note"
+// @Success 201 {object} models.ManagerNote "Created note"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/notes [post]
+func (h *ManagerNoteHandlers) CreateNote(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionManagerNoteWrite)
+	if currentUser == nil {
+		return
+	}
+
+	subjectID, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	subject, err := h.userRepo.GetByID(r.Context(), subjectID)
+	if err != nil || subject == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if subject.ID == currentUser.ID || !authz.Can(currentUser, authz.ActionManagerNoteWrite, authz.UserResource(subject)) {
+		respondError(w, http.StatusForbidden, "Forbidden: notes can only be written about your reports")
+		return
+	}
+
+	var req models.ManagerNoteRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+	if msg := h.checkMeeting(r.Context(), req.MeetingID, currentUser.ID, subject.ID); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	note, err := h.noteRepo.Create(r.Context(), currentUser.ID, subject.ID, &req)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to create manager note", err, "subject_id", subject.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to create note")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, note)
+}
+
+// UpdateNote godoc
+// @Summary Edit a note
+// @Description Replaces the body and meeting link of one of the current user's notes
+// @Tags Manager Notes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Note ID"
+// @Param request body models.ManagerNoteRequest true "Note"
+// @Success 200 {object} models.ManagerNote "Updated note"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Note not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /notes/{id} [put]
+func (h *ManagerNoteHandlers) UpdateNote(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	note := h.getOwnNote(w, r, currentUser)
+	if note == nil {
+		return
+	}
+
+	var req models.ManagerNoteRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+	if msg := h.checkMeeting(r.Context(), req.MeetingID, currentUser.ID, note.SubjectID); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	updated, err := h.noteRepo.Update(r.Context(), note.ID, &req)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to update manager note", err, "note_id", note.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to update note")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteNote godoc
+// @Summary Delete a note
+// @Description Deletes one of the current user's notes
+// @Tags Manager Notes
+// @Security BearerAuth
+// @Param id path int true "Note ID"
+// @Success 204 "Note deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid note ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Note not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /notes/{id} [delete]
+func (h *ManagerNoteHandlers) DeleteNote(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	note := h.getOwnNote(w, r, currentUser)
+	if note == nil {
+		return
+	}
+
+	if err := h.noteRepo.Delete(r.Context(), note.ID); err != nil {
+		h.logger.LogError(r.Context(), "Failed to delete manager note", err, "note_id", note.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to delete note")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AuditNotes godoc
+// @Summary Audit notes about a user
+// @Description Returns every author's notes about a user, newest first. Admin only, available when manager note auditing is enabled, and recorded in the audit log.
+// @Tags Manager Notes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(50)
+// @Success 200 {object} PaginatedResponse "Notes"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/notes/audit [get]
+func (h *ManagerNoteHandlers) AuditNotes(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionManagerNoteAudit)
+	if currentUser == nil {
+		return
+	}
+
+	subjectID, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.adminAudit {
+		h.logger.AuditDenied(r.Context(), logger.AuditActionView, "manager_notes", fmt.Sprintf("%d", subjectID), currentUser.ID, currentUser.Email, "manager note auditing is disabled")
+		respondError(w, http.StatusForbidden, "Manager note auditing is disabled")
+		return
+	}
+
+	p := parsePagination(r)
+	notes, total, err := h.noteRepo.ListBySubject(r.Context(), subjectID, p.PerPage, p.Offset)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to audit manager notes", err, "subject_id", subjectID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch notes")
+		return
+	}
+
+	h.logger.Audit(r.Context(), logger.AuditEvent{
+		Action:     logger.AuditActionView,
+		Resource:   "manager_notes",
+		ResourceID: fmt.Sprintf("%d", subjectID),
+		ActorID:    currentUser.ID,
+		ActorEmail: currentUser.Email,
+		TargetID:   &subjectID,
+		Result:     logger.AuditResultSuccess,
+		Details: map[string]any{
+			"page":       p.Page,
+			"note_count": len(notes),
+		},
+	})
+
+	respondPaginated(w, notes, total, p)
+}
+
+// getOwnNote loads the note in the URL, responding 404 unless the current user wrote it so
+// other people's notes can't be discovered
+func (h *ManagerNoteHandlers) getOwnNote(w http.ResponseWriter, r *http.Request, currentUser *models.User) *models.ManagerNote {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid note ID")
+		return nil
+	}
+
+	note, err := h.noteRepo.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get manager note", err, "note_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch note")
+		return nil
+	}
+	if note == nil || note.AuthorID != currentUser.ID {
+		respondError(w, http.StatusNotFound, "Note not found")
+		return nil
+	}
+	return note
+}
+
+// checkMeeting returns why a note can't be linked to a meeting, or "" if it can. Both the
+// author and the subject must have been in the meeting.
+func (h *ManagerNoteHandlers) checkMeeting(ctx context.Context, meetingID *int64, authorID, subjectID int64) string {
+	if meetingID == nil {
+		return ""
+	}
+	meeting, err := h.meetingRepo.GetByID(ctx, *meetingID)
+	if err != nil || meeting == nil {
+		return "Meeting not found"
+	}
+	for _, userID := range []int64{authorID, subjectID} {
+		if meeting.CreatedByID == userID {
+			continue
+		}
+		if ok, err := h.meetingRepo.IsAttendee(ctx, meeting.ID, userID); err != nil || !ok {
+			return "Notes can only be linked to meetings with both you and the user"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestManagerNoteHandlers(t *testing.T) {
+	supervisorID := int64(2)
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, Role: models.RoleSupervisor, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, Role: models.RoleEmployee, SupervisorID: &supervisorID, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 4, Role: models.RoleSupervisor, IsActive: true})
+	meetingRepo := mocks.NewMockMeetingRepository()
+	meetingRepo.Meetings[10] = &models.Meeting{ID: 10, CreatedByID: 2}
+	meetingRepo.Attendees[10] = []models.MeetingAttendee{{MeetingID: 10, UserID: 3}}
+	meetingRepo.Meetings[11] = &models.Meeting{ID: 11, CreatedByID: 2}
+	noteRepo := mocks.NewMockManagerNoteRepository()
+	h := NewManagerNoteHandlers(noteRepo, userRepo, meetingRepo)
+
+	do := func(handler http.HandlerFunc, method string, currentUserID int64, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/notes", strings.NewReader(body))
+		req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[currentUserID]), "id", id))
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+
+	t.Run("create", func(t *testing.T) {
+		for _, tt := range []struct {
+			name          string
+			currentUserID int64
+			subjectID     string
+			body          string
+			want          int
+		}{
+			{"direct report", 2, "3", `{"body":"Wants to lead the next project"}`, http.StatusCreated},
+			{"linked to their 1:1", 2, "3", `{"body":"Discussed growth","meeting_id":10}`, http.StatusCreated},
+			{"meeting without the report", 2, "3", `{"body":"x","meeting_id":11}`, http.StatusBadRequest},
+			{"missing meeting", 2, "3", `{"body":"x","meeting_id":99}`, http.StatusBadRequest},
+			{"empty body", 2, "3", `{"body":"  "}`, http.StatusBadRequest},
+			{"someone else's report", 4, "3", `{"body":"x"}`, http.StatusForbidden},
+			{"themselves", 2, "2", `{"body":"x"}`, http.StatusForbidden},
+			{"employee", 3, "3", `{"body":"x"}`, http.StatusForbidden},
+			{"missing user", 2, "99", `{"body":"x"}`, http.StatusNotFound},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				if rr := do(h.CreateNote, http.MethodPost, tt.currentUserID, tt.subjectID, tt.body); rr.Code != tt.want {
+					t.Errorf("CreateNote() status = %d, want %d: %s", rr.Code, tt.want, rr.Body.String())
+				}
+			})
+		}
+	})
+
+	// An admin's note about the same report stays private to the admin
+	if rr := do(h.CreateNote, http.MethodPost, 1, "3", `{"body":"Admin note"}`); rr.Code != http.StatusCreated {
+		t.Fatalf("CreateNote() as admin status = %d: %s", rr.Code, rr.Body.String())
+	}
+
+	t.Run("list only shows the author's notes", func(t *testing.T) {
+		var resp struct {
+			Data       []models.ManagerNote `json:"data"`
+			Pagination PaginationMetadata   `json:"pagination"`
+		}
+		rr := do(h.GetNotes, http.MethodGet, 2, "3", "")
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Pagination.Total != 2 || resp.Data[0].Body != "Discussed growth" {
+			t.Errorf("GetNotes() = %+v, want the supervisor's 2 notes newest first", resp)
+		}
+		for _, note := range resp.Data {
+			if note.AuthorID != 2 {
+				t.Errorf("GetNotes() returned note %d by user %d", note.ID, note.AuthorID)
+			}
+		}
+
+		rr = do(h.GetNotes, http.MethodGet, 4, "3", "")
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Pagination.Total != 0 {
+			t.Errorf("GetNotes() for another supervisor = %+v, want none", resp)
+		}
+	})
+
+	t.Run("only the author can edit or delete", func(t *testing.T) {
+		if rr := do(h.UpdateNote, http.MethodPut, 4, "1", `{"body":"x"}`); rr.Code != http.StatusNotFound {
+			t.Errorf("UpdateNote() by another supervisor status = %d, want %d", rr.Code, http.StatusNotFound)
+		}
+		if rr := do(h.DeleteNote, http.MethodDelete, 1, "1", ""); rr.Code != http.StatusNotFound {
+			t.Errorf("DeleteNote() by an admin status = %d, want %d", rr.Code, http.StatusNotFound)
+		}
+		if rr := do(h.UpdateNote, http.MethodPut, 2, "1", `{"body":"Edited","meeting_id":10}`); rr.Code != http.StatusOK || noteRepo.Notes[1].Body != "Edited" {
+			t.Errorf("UpdateNote() status = %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr := do(h.DeleteNote, http.MethodDelete, 2, "1", ""); rr.Code != http.StatusNoContent || noteRepo.Notes[1] != nil {
+			t.Errorf("DeleteNote() status = %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("admin audit", func(t *testing.T) {
+		if rr := do(h.AuditNotes, http.MethodGet, 1, "3", ""); rr.Code != http.StatusForbidden {
+			t.Errorf("AuditNotes() while disabled status = %d, want %d", rr.Code, http.StatusForbidden)
+		}
+
+		h.WithAdminAudit(true)
+		if rr := do(h.AuditNotes, http.MethodGet, 2, "3", ""); rr.Code != http.StatusForbidden {
+			t.Errorf("AuditNotes() as supervisor status = %d, want %d", rr.Code, http.StatusForbidden)
+		}
+		rr := do(h.AuditNotes, http.MethodGet, 1, "3", "")
+		var resp struct {
+			Pagination PaginationMetadata `json:"pagination"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if rr.Code != http.StatusOK || resp.Pagination.Total != 2 {
+			t.Errorf("AuditNotes() status = %d, total = %d, want every author's notes", rr.Code, resp.Pagination.Total)
+		}
+	})
+}
@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// MeetingAttachmentHandlers handles meeting recording and transcript requests.
+// Access is limited to the meeting's organizer and attendees.
+type MeetingAttachmentHandlers struct {
+	meetingRepo       repository.MeetingRepository
+	attachmentRepo    repository.MeetingAttachmentRepository
+	attachmentService *services.MeetingAttachmentService
+	maxSizeMB         int
+	settings          *services.OrgSettingsService
+	logger            *logger.Logger
+}
+
+// truncateTranscript makes transcript text valid UTF-8 and cuts it to MaxTranscriptLength bytes
+// without splitting a character
+func truncateTranscript(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	if len(text) <= models.MaxTranscriptLength {
+		return text
+	}
+	cut := models.MaxTranscriptLength
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut]
+}
+
+// NewMeetingAttachmentHandlers creates a new meeting attachment handlers instance
+func NewMeetingAttachmentHandlers(
+	meetingRepo repository.MeetingRepository,
+	attachmentRepo repository.MeetingAttachmentRepository,
+	attachmentService *services.MeetingAttachmentService,
+) *MeetingAttachmentHandlers {
+	return NewMeetingAttachmentHandlersWithConfig(meetingRepo, attachmentRepo, attachmentService, 10) // Default 10MB
+}
+
+// NewMeetingAttachmentHandlersWithConfig creates a new meeting attachment handlers instance with custom max size
+func NewMeetingAttachmentHandlersWithConfig(
+	meetingRepo repository.MeetingRepository,
+	attachmentRepo repository.MeetingAttachmentRepository,
+	attachmentService *services.MeetingAttachmentService,
+	maxSizeMB int,
+) *MeetingAttachmentHandlers {
+	if maxSizeMB <= 0 {
+		maxSizeMB = 10
+	}
+	return &MeetingAttachmentHandlers{
+		meetingRepo:       meetingRepo,
+		attachmentRepo:    attachmentRepo,
+		attachmentService: attachmentService,
+		maxSizeMB:         maxSizeMB,
+		logger:            logger.Default().WithComponent("meeting-attachments"),
+	}
+}
+
+// WithSettings lets organizations change the maximum attachment size from the configured one
+func (h *MeetingAttachmentHandlers) WithSettings(settings *services.OrgSettingsService) *MeetingAttachmentHandlers {
+	h.settings = settings
+	return h
+}
+
+// maxUploadMB returns the largest attachment the current user's organization accepts, in MB
+func (h *MeetingAttachmentHandlers) maxUploadMB(r *http.Request) int {
+	if h.settings == nil {
+		return h.maxSizeMB
+	}
+	return h.settings.MeetingAttachmentMaxSizeMB(r.Context())
+}
+
+// requireMeetingParticipant loads the meeting from the URL and verifies the user organized or attends it
+func (h *MeetingAttachmentHandlers) requireMeetingParticipant(w http.ResponseWriter, r *http.Request, user *models.User) *models.Meeting {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid meeting ID")
+		return nil
+	}
+
+	meeting, err := h.meetingRepo.GetByID(r.Context(), id)
+	if err != nil || meeting == nil {
+		respondError(w, http.StatusNotFound, "Meeting not found")
+		return nil
+	}
+
+	if meeting.CreatedByID == user.ID {
+		return meeting
+	}
+
+	isAttendee, err := h.meetingRepo.IsAttendee(r.Context(), meeting.ID, user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to check attendee status")
+		return nil
+	}
+	if !isAttendee {
+		respondError(w, http.StatusForbidden, "Forbidden: only meeting attendees can access recordings and transcripts")
+		return nil
+	}
+
+	return meeting
+}
+
+// isMeetingOccurrence checks that the given start time is an actual occurrence of the meeting
+func (h *MeetingAttachmentHandlers) isMeetingOccurrence(meeting *models.Meeting, occurrenceStart time.Time) bool {
+	if meeting.RecurrenceType == nil {
+		return meeting.StartTime.Equal(occurrenceStart)
+	}
+	for _, occurrence := range h.meetingRepo.ExpandRecurringMeetings([]models.Meeting{*meeting}, occurrenceStart, occurrenceStart) {
+		if occurrence.StartTime.Equal(occurrenceStart) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOccurrenceStart parses an optional RFC3339 occurrence start and validates it against the meeting
+func (h *MeetingAttachmentHandlers) parseOccurrenceStart(w http.ResponseWriter, meeting *models.Meeting, value string) (*time.Time, bool) {
+	if value == "" {
+		return nil, true
+	}
+	occurrenceStart, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid occurrence_start format (use RFC3339)")
+		return nil, false
+	}
+	if !h.isMeetingOccurrence(meeting, occurrenceStart) {
+		respondError(w, http.StatusBadRequest, "occurrence_start does not match an occurrence of this meeting")
+		return nil, false
+	}
+	return &occurrenceStart, true
+}
+
+// ListAttachments returns recordings and transcripts for a meeting, optionally for one occurrence
+func (h *MeetingAttachmentHandlers) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	meeting := h.requireMeetingParticipant(w, r, currentUser)
+	if meeting == nil {
+		return
+	}
+
+	occurrenceStart, ok := h.parseOccurrenceStart(w, meeting, r.URL.Query().Get("occurrence_start"))
+	if !ok {
+		return
+	}
+
+	attachments, err := h.attachmentRepo.ListByMeeting(r.Context(), meeting.ID, occurrenceStart)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch meeting attachments")
+		return
+	}
+
+	signing := make([]*models.MeetingAttachment, len(attachments))
+	for i := range attachments {
+		signing[i] = &attachments[i]
+	}
+	if !h.signURLs(w, r, signing...) {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, attachments)
+}
+
+// GetAttachment returns a single attachment including its transcript text
+func (h *MeetingAttachmentHandlers) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	meeting := h.requireMeetingParticipant(w, r, currentUser)
+	if meeting == nil {
+		return
+	}
+
+	attachment := h.loadAttachment(w, r, meeting)
+	if attachment == nil {
+		return
+	}
+	if !h.signURLs(w, r, attachment) {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, attachment)
+}
+
+// UploadAttachment handles a recording or transcript upload via multipart form
+func (h *MeetingAttachmentHandlers) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	meeting := h.requireMeetingParticipant(w, r, currentUser)
+	if meeting == nil {
+		return
+	}
+
+	maxSizeMB := h.maxUploadMB(r)
+	maxBytes := int64(maxSizeMB) << 20
+	if err := r.ParseMultipartForm(maxBytes); err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("File too large (max %dMB)", maxSizeMB))
+		return
+	}
+
+	occurrenceStart, ok := h.parseOccurrenceStart(w, meeting, r.FormValue("occurrence_start"))
+	if !ok {
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "No file uploaded")
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	fileName := filepath.Base(header.Filename)
+	contentType := header.Header.Get("Content-Type")
+
+	var kind models.MeetingAttachmentKind
+	switch {
+	case services.IsTranscriptFile(fileName, contentType):
+		kind = models.MeetingAttachmentKindTranscript
+	case services.IsRecordingFile(contentType):
+		kind = models.MeetingAttachmentKindRecording
+	default:
+		respondError(w, http.StatusBadRequest, "File must be an audio/video recording or a .txt, .vtt, or .srt transcript")
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to read file")
+		return
+	}
+	if len(data) > int(maxBytes) {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("File too large (max %dMB)", maxSizeMB))
+		return
+	}
+
+	attachment := &models.MeetingAttachment{
+		MeetingID:       meeting.ID,
+		OccurrenceStart: occurrenceStart,
+		Kind:            kind,
+		FileName:        &fileName,
+		ContentType:     &contentType,
+		UploadedByID:    currentUser.ID,
+	}
+	size := int64(len(data))
+	attachment.SizeBytes = &size
+
+	if kind == models.MeetingAttachmentKindTranscript {
+		text := truncateTranscript(services.ExtractTranscriptText(data))
+		attachment.TranscriptText = &text
+	}
+
+	key, err := h.attachmentService.Upload(r.Context(), meeting.ID, currentUser.ID, fileName, contentType, data)
+	if err != nil {
+		respondError(w, http.StatusServiceUnavailable, "File upload is temporarily unavailable. Please try again later.")
+		return
+	}
+	attachment.StorageKey = &key
+
+	created, err := h.attachmentRepo.Create(r.Context(), attachment)
+	if err != nil {
+		// Don't leave an orphaned file behind
+		if delErr := h.attachmentService.Delete(r.Context(), key); delErr != nil {
+			h.logger.LogError(r.Context(), "Failed to clean up orphaned meeting attachment", delErr, "key", key)
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to save meeting attachment")
+		return
+	}
+	if !h.signURLs(w, r, created) {
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, created)
+}
+
+// signURLs gives uploaded attachments a short-lived download URL; the stored files are private
+func (h *MeetingAttachmentHandlers) signURLs(w http.ResponseWriter, r *http.Request, attachments ...*models.MeetingAttachment) bool {
+	if err := h.attachmentService.SignURLs(r.Context(), attachments...); err != nil {
+		h.logger.LogError(r.Context(), "Failed to sign meeting attachment URLs", err)
+		respondError(w, http.StatusServiceUnavailable, "File downloads are temporarily unavailable. Please try again later.")
+		return false
+	}
+	return true
+}
+
+// LinkAttachment attaches an externally hosted recording or transcript to a meeting
+func (h *MeetingAttachmentHandlers) LinkAttachment(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	meeting := h.requireMeetingParticipant(w, r, currentUser)
+	if meeting == nil {
+		return
+	}
+
+	var req models.LinkMeetingAttachmentRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	if req.OccurrenceStart != nil && !h.isMeetingOccurrence(meeting, *req.OccurrenceStart) {
+		respondError(w, http.StatusBadRequest, "occurrence_start does not match an occurrence of this meeting")
+		return
+	}
+
+	created, err := h.attachmentRepo.Create(r.Context(), &models.MeetingAttachment{
+		MeetingID:       meeting.ID,
+		OccurrenceStart: req.OccurrenceStart,
+		Kind:            req.Kind,
+		URL:             req.URL,
+		FileName:        req.FileName,
+		TranscriptText:  req.TranscriptText,
+		UploadedByID:    currentUser.ID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to save meeting attachment")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, created)
+}
+
+// DeleteAttachment removes an attachment; only the uploader or the meeting organizer may delete
+func (h *MeetingAttachmentHandlers) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	meeting := h.requireMeetingParticipant(w, r, currentUser)
+	if meeting == nil {
+		return
+	}
+
+	attachment := h.loadAttachment(w, r, meeting)
+	if attachment == nil {
+		return
+	}
+
+	if attachment.UploadedByID != currentUser.ID && meeting.CreatedByID != currentUser.ID {
+		respondError(w, http.StatusForbidden, "Forbidden: only the uploader or meeting organizer can delete this attachment")
+		return
+	}
+
+	if err := h.attachmentRepo.Delete(r.Context(), attachment.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to delete meeting attachment")
+		return
+	}
+
+	if attachment.IsUploaded() {
+		if err := h.attachmentService.Delete(r.Context(), *attachment.StorageKey); err != nil {
+			h.logger.LogError(r.Context(), "Failed to delete meeting attachment file", err, "key", *attachment.StorageKey)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadAttachment fetches the attachment from the URL and checks it belongs to the meeting
+func (h *MeetingAttachmentHandlers) loadAttachment(w http.ResponseWriter, r *http.Request, meeting *models.Meeting) *models.MeetingAttachment {
+	attachmentID, err := parseIDParam(r, "attachmentId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid attachment ID")
+		return nil
+	}
+
+	attachment, err := h.attachmentRepo.GetByID(r.Context(), attachmentID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch meeting attachment")
+		return nil
+	}
+	if attachment == nil || attachment.MeetingID != meeting.ID {
+		respondError(w, http.StatusNotFound, "Attachment not found")
+		return nil
+	}
+
+	return attachment
+}
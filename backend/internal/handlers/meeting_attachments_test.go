@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// fakeStorage records uploads and deletes in memory
+type fakeStorage struct {
+	uploaded map[string][]byte
+	deleted  []string
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{uploaded: make(map[string][]byte)}
+}
+
+func (s *fakeStorage) Upload(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	s.uploaded[key] = data
+	return s.GetURL(key), nil
+}
+
+func (s *fakeStorage) Delete(ctx context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func (s *fakeStorage) GetURL(key string) string {
+	return "https://files.example.com/" + key
+}
+
+func (s *fakeStorage) KeyFromURL(url string) (string, bool) {
+	return strings.CutPrefix(url, "https://files.example.com/")
+}
+
+// chiCtxWithParams adds multiple URL params to a request context
+func chiCtxWithParams(ctx context.Context, params map[string]string) context.Context {
+	rctx := chi.NewRouteContext()
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	return context.WithValue(ctx, chi.RouteCtxKey, rctx)
+}
+
+// setupMeetingAttachmentTest creates a meeting organized by user 1 with user 2 attending
+func setupMeetingAttachmentTest() (*MeetingAttachmentHandlers, *mocks.MockMeetingRepository, *mocks.MockMeetingAttachmentRepository, *fakeStorage) {
+	meetingRepo := mocks.NewMockMeetingRepository()
+	start := time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)
+	meetingRepo.AddMeeting(&models.Meeting{
+		ID:          1,
+		Title:       "Planning",
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		CreatedByID: 1,
+	})
+	meetingRepo.AddAttendee(1, 2, models.ResponseStatusAccepted)
+
+	attachmentRepo := mocks.NewMockMeetingAttachmentRepository()
+	store := newFakeStorage()
+	h := NewMeetingAttachmentHandlers(meetingRepo, attachmentRepo, services.NewMeetingAttachmentService(store, "acme", time.Minute))
+	return h, meetingRepo, attachmentRepo, store
+}
+
+func TestMeetingAttachmentHandlers_ListAttachments_Access(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		meetingID      string
+		expectedStatus int
+	}{
+		{
+			name:           "organizer can list",
+			currentUser:    &models.User{ID: 1, Role: models.RoleEmployee},
+			meetingID:      "1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "attendee can list",
+			currentUser:    &models.User{ID: 2, Role: models.RoleEmployee},
+			meetingID:      "1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "non-attendee is forbidden",
+			currentUser:    &models.User{ID: 3, Role: models.RoleEmployee},
+			meetingID:      "1",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "admin who did not attend is forbidden",
+			currentUser:    &models.User{ID: 4, Role: models.RoleAdmin},
+			meetingID:      "1",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "unknown meeting returns not found",
+			currentUser:    &models.User{ID: 1, Role: models.RoleEmployee},
+			meetingID:      "99",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _ := setupMeetingAttachmentTest()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/calendar/meetings/"+tt.meetingID+"/attachments", nil)
+			ctx := ctxWithUserFrom(req.Context(), tt.currentUser)
+			req = req.WithContext(chiCtxWithID(ctx, "id", tt.meetingID))
+
+			rr := httptest.NewRecorder()
+			h.ListAttachments(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("ListAttachments() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestMeetingAttachmentHandlers_UploadAttachment_Transcript(t *testing.T) {
+	h, _, attachmentRepo, store := setupMeetingAttachmentTest()
+
+	vtt := "WEBVTT\n\n1\n00:00:01.000 --> 00:00:03.000\nLet's review the roadmap.\n"
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	partHeader := textproto.MIMEHeader{}
+	partHeader.Set("Content-Disposition", `form-data; name="file"; filename="planning.vtt"`)
+	partHeader.Set("Content-Type", "text/vtt")
+	part, _ := writer.CreatePart(partHeader)
+	_, _ = part.Write([]byte(vtt))
+	_ = writer.WriteField("occurrence_start", "2024-03-04T15:00:00Z")
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/calendar/meetings/1/attachments", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	ctx := ctxWithUserFrom(req.Context(), &models.User{ID: 2, Role: models.RoleEmployee})
+	req = req.WithContext(chiCtxWithID(ctx, "id", "1"))
+
+	rr := httptest.NewRecorder()
+	h.UploadAttachment(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("UploadAttachment() status = %v, want %v, body = %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+	if len(store.uploaded) != 1 {
+		t.Fatalf("expected 1 uploaded file, got %d", len(store.uploaded))
+	}
+
+	saved := attachmentRepo.Attachments[1]
+	if saved == nil {
+		t.Fatal("expected attachment to be saved")
+	}
+	if saved.Kind != models.MeetingAttachmentKindTranscript {
+		t.Errorf("kind = %q, want transcript", saved.Kind)
+	}
+	if saved.TranscriptText == nil || *saved.TranscriptText != "Let's review the roadmap." {
+		t.Errorf("unexpected transcript text: %v", saved.TranscriptText)
+	}
+	if saved.OccurrenceStart == nil || !saved.OccurrenceStart.Equal(time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected occurrence_start: %v", saved.OccurrenceStart)
+	}
+	if saved.StorageKey == nil || !strings.HasPrefix(*saved.StorageKey, "tenants/acme/users/2/meetings/1/") {
+		t.Fatalf("unexpected storage key: %v", saved.StorageKey)
+	}
+	if saved.URL != "" {
+		t.Errorf("stored url = %q, want none for a private upload", saved.URL)
+	}
+
+	var created models.MeetingAttachment
+	if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.URL != "https://files.example.com/"+*saved.StorageKey+"?signature=test" {
+		t.Errorf("response url = %q, want a signed link", created.URL)
+	}
+}
+
+func TestMeetingAttachmentHandlers_GetAttachment_SignsURL(t *testing.T) {
+	h, _, attachmentRepo, _ := setupMeetingAttachmentTest()
+	key := "tenants/acme/users/2/meetings/1/123.mp4"
+	attachmentRepo.AddAttachment(&models.MeetingAttachment{
+		ID: 1, MeetingID: 1, Kind: models.MeetingAttachmentKindRecording,
+		StorageKey: &key, UploadedByID: 2,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar/meetings/1/attachments/1", nil)
+	ctx := ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleEmployee})
+	req = req.WithContext(chiCtxWithParams(ctx, map[string]string{"id": "1", "attachmentId": "1"}))
+
+	rr := httptest.NewRecorder()
+	h.GetAttachment(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GetAttachment() status = %v, want %v, body = %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var got models.MeetingAttachment
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.URL != "https://files.example.com/"+key+"?signature=test" {
+		t.Errorf("url = %q, want a signed link", got.URL)
+	}
+}
+
+func TestTruncateTranscript(t *testing.T) {
+	// A three-byte character straddles the limit
+	long := strings.Repeat("a", models.MaxTranscriptLength-1) + "€" + "tail"
+	got := truncateTranscript(long)
+	if len(got) != models.MaxTranscriptLength-1 || !utf8.ValidString(got) {
+		t.Errorf("truncated to %d bytes (valid UTF-8: %v), want %d", len(got), utf8.ValidString(got), models.MaxTranscriptLength-1)
+	}
+
+	if got := truncateTranscript("caf\xc3 ok"); got != "caf ok" {
+		t.Errorf("truncateTranscript() = %q, want invalid bytes dropped", got)
+	}
+	if got := truncateTranscript("short"); got != "short" {
+		t.Errorf("truncateTranscript() = %q, want short text unchanged", got)
+	}
+}
+
+func TestMeetingAttachmentHandlers_UploadAttachment_UnsupportedType(t *testing.T) {
+	h, _, _, store := setupMeetingAttachmentTest()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	partHeader := textproto.MIMEHeader{}
+	partHeader.Set("Content-Disposition", `form-data; name="file"; filename="slides.pdf"`)
+	partHeader.Set("Content-Type", "application/pdf")
+	part, _ := writer.CreatePart(partHeader)
+	_, _ = part.Write([]byte("%PDF"))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/calendar/meetings/1/attachments", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	ctx := ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleEmployee})
+	req = req.WithContext(chiCtxWithID(ctx, "id", "1"))
+
+	rr := httptest.NewRecorder()
+	h.UploadAttachment(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("UploadAttachment() status = %v, want %v", rr.Code, http.StatusBadRequest)
+	}
+	if len(store.uploaded) != 0 {
+		t.Error("expected nothing to be uploaded")
+	}
+}
+
+func TestMeetingAttachmentHandlers_LinkAttachment(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		expectedStatus int
+	}{
+		{
+			name:           "links a recording",
+			requestBody:    `{"kind":"recording","url":"https://zoom.example.com/rec/abc"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "links a transcript for an occurrence",
+			requestBody:    `{"kind":"transcript","url":"https://docs.example.com/t","occurrence_start":"2024-03-04T15:00:00Z","transcript_text":"budget review"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "rejects a time that is not an occurrence",
+			requestBody:    `{"kind":"recording","url":"https://zoom.example.com/rec/abc","occurrence_start":"2024-03-05T15:00:00Z"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "rejects a non-http url",
+			requestBody:    `{"kind":"recording","url":"ftp://example.com/rec"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _ := setupMeetingAttachmentTest()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/calendar/meetings/1/attachments/link", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			ctx := ctxWithUserFrom(req.Context(), &models.User{ID: 2, Role: models.RoleEmployee})
+			req = req.WithContext(chiCtxWithID(ctx, "id", "1"))
+
+			rr := httptest.NewRecorder()
+			h.LinkAttachment(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("LinkAttachment() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestMeetingAttachmentHandlers_DeleteAttachment(t *testing.T) {
+	key := "meetings/1/123.vtt"
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		expectedStatus int
+	}{
+		{
+			name:           "uploader can delete",
+			currentUser:    &models.User{ID: 2, Role: models.RoleEmployee},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "organizer can delete",
+			currentUser:    &models.User{ID: 1, Role: models.RoleEmployee},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "other attendee cannot delete",
+			currentUser:    &models.User{ID: 5, Role: models.RoleEmployee},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, meetingRepo, attachmentRepo, store := setupMeetingAttachmentTest()
+			meetingRepo.AddAttendee(1, 5, models.ResponseStatusAccepted)
+			attachmentRepo.AddAttachment(&models.MeetingAttachment{
+				ID: 1, MeetingID: 1, Kind: models.MeetingAttachmentKindTranscript,
+				StorageKey: &key, UploadedByID: 2,
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/calendar/meetings/1/attachments/1", nil)
+			ctx := ctxWithUserFrom(req.Context(), tt.currentUser)
+			req = req.WithContext(chiCtxWithParams(ctx, map[string]string{"id": "1", "attachmentId": "1"}))
+
+			rr := httptest.NewRecorder()
+			h.DeleteAttachment(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("DeleteAttachment() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			if tt.expectedStatus == http.StatusNoContent && (len(store.deleted) != 1 || store.deleted[0] != key) {
+				t.Errorf("expected stored file %q to be deleted, got %v", key, store.deleted)
+			}
+		})
+	}
+}
+
+func TestSearchHandlers_Search(t *testing.T) {
+	transcript := "we agreed to cut the Q3 budget"
+	attachmentRepo := mocks.NewMockMeetingAttachmentRepository()
+	attachmentRepo.AddAttachment(&models.MeetingAttachment{ID: 1, MeetingID: 1, Kind: models.MeetingAttachmentKindTranscript, TranscriptText: &transcript})
+	attachmentRepo.AddAttachment(&models.MeetingAttachment{ID: 2, MeetingID: 2, Kind: models.MeetingAttachmentKindTranscript, TranscriptText: &transcript})
+	attachmentRepo.VisibleMeetingIDs[1] = []int64{1}
+
+	h := NewSearchHandlers(attachmentRepo, services.NewMeetingAttachmentService(newFakeStorage(), "acme", time.Minute))
+
+	t.Run("requires a query", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+		req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleEmployee}))
+		rr := httptest.NewRecorder()
+		h.Search(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Search() status = %v, want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("returns only transcripts of meetings the user attended", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/search?q=budget", nil)
+		req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleEmployee}))
+		rr := httptest.NewRecorder()
+		h.Search(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Search() status = %v, want %v", rr.Code, http.StatusOK)
+		}
+
+		var resp models.SearchResponse
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(resp.Transcripts) != 1 || resp.Transcripts[0].Attachment.MeetingID != 1 {
+			t.Errorf("expected only meeting 1 transcript, got %+v", resp.Transcripts)
+		}
+	})
+}
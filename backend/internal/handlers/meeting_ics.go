@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// maxICSImportBytes caps the size of an uploaded .ics file
+const maxICSImportBytes = 1 << 20
+
+// MeetingICSHandlers handles exporting meetings to and importing meetings from .ics files
+type MeetingICSHandlers struct {
+	icsService *services.MeetingICSService
+	broker     *events.Broker
+}
+
+// NewMeetingICSHandlers creates a new meeting iCalendar handlers instance that publishes imported meetings to the given broker
+func NewMeetingICSHandlers(icsService *services.MeetingICSService, broker *events.Broker) *MeetingICSHandlers {
+	return &MeetingICSHandlers{
+		icsService: icsService,
+		broker:     broker,
+	}
+}
+
+// ExportMeetings downloads the current user's meetings as an .ics file.
+// The optional start and end query parameters (RFC3339) default to the last 30 days through the next year.
+func (h *MeetingICSHandlers) ExportMeetings(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	now := time.Now()
+	start := now.AddDate(0, 0, -30)
+	end := now.AddDate(1, 0, 0)
+	if startStr := r.URL.Query().Get("start"); startStr != "" {
+		var err error
+		start, err = time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid start date format (use RFC3339)")
+			return
+		}
+	}
+	if endStr := r.URL.Query().Get("end"); endStr != "" {
+		var err error
+		end, err = time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid end date format (use RFC3339)")
+			return
+		}
+	}
+	if !end.After(start) {
+		respondError(w, http.StatusBadRequest, "end must be after start")
+		return
+	}
+
+	data, err := h.icsService.Export(r.Context(), currentUser, start, end)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to export meetings")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="meetings.ics"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// ImportMeetings creates meetings from an uploaded .ics file.
+// The file may be sent as the "file" field of a multipart form or as a raw text/calendar body.
+func (h *MeetingICSHandlers) ImportMeetings(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxICSImportBytes+(64<<10)) // Leave room for multipart framing
+	var data []byte
+	var err error
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxICSImportBytes); err != nil {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("File too large (max %dMB)", maxICSImportBytes>>20))
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "No file uploaded")
+			return
+		}
+		defer func() { _ = file.Close() }()
+		data, err = io.ReadAll(file)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to read file")
+			return
+		}
+	} else {
+		data, err = io.ReadAll(r.Body)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("File too large (max %dMB)", maxICSImportBytes>>20))
+			return
+		}
+	}
+	if len(data) > maxICSImportBytes {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("File too large (max %dMB)", maxICSImportBytes>>20))
+		return
+	}
+
+	result, err := h.icsService.Import(r.Context(), currentUser, data)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidICS) || errors.Is(err, services.ErrTooManyICSEvents) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to import meetings")
+		return
+	}
+
+	for i := range result.Imported {
+		h.broker.Publish(r.Context(), events.MeetingCreated, &result.Imported[i])
+	}
+	respondJSON(w, http.StatusOK, result)
+}
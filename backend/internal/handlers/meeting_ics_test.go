@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestMeetingICSHandlers_ImportMeetings(t *testing.T) {
+	validCalendar := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a@example.com\r\nSUMMARY:Sync\r\n" +
+		"DTSTART:20260302T160000Z\r\nDTEND:20260302T170000Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		body           string
+		expectedStatus int
+		wantImported   int
+	}{
+		{
+			name:           "unauthenticated",
+			body:           validCalendar,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "not a calendar",
+			currentUser:    &models.User{ID: 1, Role: models.RoleEmployee},
+			body:           "hello",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "imports raw text/calendar body",
+			currentUser:    &models.User{ID: 1, Role: models.RoleEmployee},
+			body:           validCalendar,
+			expectedStatus: http.StatusOK,
+			wantImported:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewMeetingICSService(mocks.NewMockMeetingRepository(), mocks.NewMockUserRepository())
+			h := NewMeetingICSHandlers(service, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/calendar/meetings/import", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/calendar")
+			if tt.currentUser != nil {
+				req = req.WithContext(ctxWithUserFrom(req.Context(), tt.currentUser))
+			}
+
+			rr := httptest.NewRecorder()
+			h.ImportMeetings(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("ImportMeetings() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var result models.ICSImportResult
+			if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(result.Imported) != tt.wantImported {
+				t.Errorf("imported %d meetings, want %d", len(result.Imported), tt.wantImported)
+			}
+		})
+	}
+}
+
+func TestMeetingICSHandlers_ExportMeetings(t *testing.T) {
+	service := services.NewMeetingICSService(mocks.NewMockMeetingRepository(), mocks.NewMockUserRepository())
+	h := NewMeetingICSHandlers(service, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar/meetings/export.ics?start=2026-03-01T00:00:00Z&end=2026-02-01T00:00:00Z", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1}))
+	rr := httptest.NewRecorder()
+	h.ExportMeetings(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("reversed range status = %v, want %v", rr.Code, http.StatusBadRequest)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/calendar/meetings/export.ics", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1}))
+	rr = httptest.NewRecorder()
+	h.ExportMeetings(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("ExportMeetings() status = %v, want %v", rr.Code, http.StatusOK)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("Content-Type = %q, want text/calendar", ct)
+	}
+	if !strings.HasPrefix(rr.Body.String(), "BEGIN:VCALENDAR") {
+		t.Errorf("unexpected body: %s", rr.Body.String())
+	}
+}
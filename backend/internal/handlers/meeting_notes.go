@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// actionItemDefaultDueDays is how far out converted action items without a due date are due
+const actionItemDefaultDueDays = 7
+
+// requireMeetingViewer loads the meeting from the URL and verifies the user can see it
+func (h *CalendarHandlers) requireMeetingViewer(w http.ResponseWriter, r *http.Request, user *models.User) *models.Meeting {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid meeting ID")
+		return nil
+	}
+
+	meeting, err := h.meetingRepo.GetByID(r.Context(), id)
+	if err != nil || meeting == nil {
+		respondError(w, http.StatusNotFound, "Meeting not found")
+		return nil
+	}
+
+	if !h.canViewMeeting(r.Context(), user, meeting) {
+		respondError(w, http.StatusForbidden, "Forbidden: you don't have permission to view this meeting")
+		return nil
+	}
+
+	return meeting
+}
+
+// isMeetingParticipant reports whether the user organizes or is invited to the meeting
+func isMeetingParticipant(meeting *models.Meeting, userID int64) bool {
+	if meeting.CreatedByID == userID {
+		return true
+	}
+	for _, a := range meeting.Attendees {
+		if a.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMeetingAgenda returns a meeting's agenda in discussion order
+func (h *CalendarHandlers) GetMeetingAgenda(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	meeting := h.requireMeetingViewer(w, r, currentUser)
+	if meeting == nil {
+		return
+	}
+
+	agenda, err := h.notesRepo.GetAgenda(r.Context(), meeting.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch agenda")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, agenda)
+}
+
+// UpdateMeetingAgenda replaces a meeting's agenda; only users who may manage the meeting, such as
+// its organizer, may edit it
+func (h *CalendarHandlers) UpdateMeetingAgenda(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	meeting := h.requireMeetingViewer(w, r, currentUser)
+	if meeting == nil {
+		return
+	}
+
+	if !authz.Can(currentUser, authz.ActionMeetingManage, authz.MeetingResource(meeting)) {
+		respondError(w, http.StatusForbidden, "Forbidden: only the organizer can edit the agenda")
+		return
+	}
+
+	var req models.UpdateMeetingAgendaRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	agenda, err := h.notesRepo.ReplaceAgenda(r.Context(), meeting.ID, req.Items)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update agenda")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, agenda)
+}
+
+// GetMeetingNotes returns a meeting's notes and action items
+func (h *CalendarHandlers) GetMeetingNotes(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	meeting := h.requireMeetingViewer(w, r, currentUser)
+	if meeting == nil {
+		return
+	}
+
+	notes, err := h.notesRepo.GetNotes(r.Context(), meeting.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch meeting notes")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, notes)
+}
+
+// UpdateMeetingNotes saves a meeting's notes and action items; anyone who can see the meeting may edit them.
+// Action items may only be assigned to the organizer or attendees.
+func (h *CalendarHandlers) UpdateMeetingNotes(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	meeting := h.requireMeetingViewer(w, r, currentUser)
+	if meeting == nil {
+		return
+	}
+
+	var req models.UpdateMeetingNotesRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	for i, item := range req.ActionItems {
+		if item.AssigneeID != nil && !isMeetingParticipant(meeting, *item.AssigneeID) {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("Action item %d: assignee must be the organizer or an attendee", i+1))
+			return
+		}
+	}
+
+	notes, err := h.notesRepo.SaveNotes(r.Context(), meeting.ID, currentUser.ID, &req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to save meeting notes")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, notes)
+}
+
+// ConvertMeetingActionItems turns action items into tasks assigned to their assignees in one call.
+// Each task links back to the meeting; action items without a due date are due in a week.
+func (h *CalendarHandlers) ConvertMeetingActionItems(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	meeting := h.requireMeetingViewer(w, r, currentUser)
+	if meeting == nil {
+		return
+	}
+
+	var req models.ConvertActionItemsRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	notes, err := h.notesRepo.GetNotes(r.Context(), meeting.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch meeting notes")
+		return
+	}
+
+	itemIDs, message := selectActionItems(notes.ActionItems, req.ActionItemIDs)
+	if message != "" {
+		respondError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	now := time.Now().UTC()
+	defaultDueDate := time.Date(now.Year(), now.Month(), now.Day()+actionItemDefaultDueDays, 0, 0, 0, 0, time.UTC)
+
+	tasks, err := h.notesRepo.ConvertActionItems(r.Context(), meeting, currentUser.ID, itemIDs, defaultDueDate)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create tasks from action items")
+		return
+	}
+
+	for i := range tasks {
+		h.broker.Publish(r.Context(), events.TaskCreated, &tasks[i])
+	}
+	respondJSON(w, http.StatusCreated, tasks)
+}
+
+// selectActionItems picks the action items to convert, or explains why the request can't be honored.
+// With no requested IDs, every unconverted action item with an assignee is selected.
+func selectActionItems(items []models.MeetingActionItem, requested []int64) ([]int64, string) {
+	byID := make(map[int64]*models.MeetingActionItem, len(items))
+	for i := range items {
+		byID[items[i].ID] = &items[i]
+	}
+
+	var itemIDs []int64
+	if len(requested) == 0 {
+		for _, item := range items {
+			if !item.IsConverted() && item.AssigneeID != nil {
+				itemIDs = append(itemIDs, item.ID)
+			}
+		}
+		if len(itemIDs) == 0 {
+			return nil, "No assigned action items are waiting to be converted"
+		}
+		return itemIDs, ""
+	}
+
+	for _, id := range requested {
+		item, ok := byID[id]
+		switch {
+		case !ok:
+			return nil, fmt.Sprintf("Action item %d not found", id)
+		case item.IsConverted():
+			return nil, fmt.Sprintf("Action item %d has already been converted to a task", id)
+		case item.AssigneeID == nil:
+			return nil, fmt.Sprintf("Action item %d needs an assignee before it can become a task", id)
+		}
+		itemIDs = append(itemIDs, id)
+	}
+	return itemIDs, ""
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+// setupMeetingNotesTest creates a meeting organized by user 1 with user 2 attending
+func setupMeetingNotesTest() (*CalendarHandlers, *mocks.MockMeetingNotesRepository) {
+	meetingRepo := mocks.NewMockMeetingRepository()
+	start := time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)
+	meetingRepo.AddMeeting(&models.Meeting{
+		ID:          1,
+		Title:       "Sprint planning",
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		CreatedByID: 1,
+		Attendees:   []models.MeetingAttendee{{MeetingID: 1, UserID: 2, ResponseStatus: models.ResponseStatusAccepted}},
+	})
+	meetingRepo.AddAttendee(1, 2, models.ResponseStatusAccepted)
+
+	notesRepo := mocks.NewMockMeetingNotesRepository()
+	h := NewCalendarHandlersWithNotes(nil, nil, meetingRepo, nil, notesRepo, nil)
+	return h, notesRepo
+}
+
+func meetingNotesRequest(method, path, body string, user *models.User) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	ctx := ctxWithUserFrom(req.Context(), user)
+	return req.WithContext(chiCtxWithID(ctx, "id", "1"))
+}
+
+func TestCalendarHandlers_UpdateMeetingAgenda(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		body           string
+		expectedStatus int
+	}{
+		{"organizer can set the agenda", &models.User{ID: 1, Role: models.RoleEmployee}, `{"items":[{"title":" Demo "},{"title":"Retro","duration_minutes":15}]}`, http.StatusOK},
+		{"attendee can't edit the agenda", &models.User{ID: 2, Role: models.RoleEmployee}, `{"items":[{"title":"Demo"}]}`, http.StatusForbidden},
+		{"uninvited user", &models.User{ID: 3, Role: models.RoleEmployee}, `{"items":[{"title":"Demo"}]}`, http.StatusForbidden},
+		{"blank title", &models.User{ID: 1, Role: models.RoleEmployee}, `{"items":[{"title":"  "}]}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, notesRepo := setupMeetingNotesTest()
+
+			rr := httptest.NewRecorder()
+			h.UpdateMeetingAgenda(rr, meetingNotesRequest(http.MethodPut, "/api/calendar/meetings/1/agenda", tt.body, tt.currentUser))
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+			agenda := notesRepo.Agendas[1]
+			if len(agenda) != 2 || agenda[0].Title != "Demo" || agenda[1].Position != 1 || *agenda[1].DurationMinutes != 15 {
+				t.Errorf("agenda = %+v", agenda)
+			}
+		})
+	}
+}
+
+func TestCalendarHandlers_UpdateMeetingNotes(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		body           string
+		expectedStatus int
+	}{
+		{"attendee can take notes", &models.User{ID: 2, Role: models.RoleEmployee}, `{"notes":"Shipped v2","action_items":[{"description":"Write changelog","assignee_id":1}]}`, http.StatusOK},
+		{"assignee must be in the meeting", &models.User{ID: 1, Role: models.RoleEmployee}, `{"notes":"","action_items":[{"description":"Write changelog","assignee_id":3}]}`, http.StatusBadRequest},
+		{"uninvited user", &models.User{ID: 3, Role: models.RoleEmployee}, `{"notes":"hi"}`, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, notesRepo := setupMeetingNotesTest()
+
+			rr := httptest.NewRecorder()
+			h.UpdateMeetingNotes(rr, meetingNotesRequest(http.MethodPut, "/api/calendar/meetings/1/notes", tt.body, tt.currentUser))
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				if notes := notesRepo.Notes[1]; notes != nil && len(notes.ActionItems) != 0 {
+					t.Errorf("rejected request stored action items: %+v", notes.ActionItems)
+				}
+				return
+			}
+			var notes models.MeetingNotes
+			if err := json.Unmarshal(rr.Body.Bytes(), &notes); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if notes.Notes != "Shipped v2" || len(notes.ActionItems) != 1 || *notes.UpdatedByID != 2 {
+				t.Errorf("notes = %+v", notes)
+			}
+		})
+	}
+}
+
+func TestCalendarHandlers_ConvertMeetingActionItems(t *testing.T) {
+	organizer, attendee := int64(1), int64(2)
+	due := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedTasks  int
+	}{
+		{"converts every assigned item", `{}`, http.StatusCreated, 2},
+		{"converts selected items", `{"action_item_ids":[2]}`, http.StatusCreated, 1},
+		{"item without an assignee", `{"action_item_ids":[3]}`, http.StatusBadRequest, 0},
+		{"item already converted", `{"action_item_ids":[4]}`, http.StatusBadRequest, 0},
+		{"unknown item", `{"action_item_ids":[99]}`, http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, notesRepo := setupMeetingNotesTest()
+			taskID := int64(50)
+			notesRepo.AddActionItem(models.MeetingActionItem{ID: 1, MeetingID: 1, Description: "Write changelog", AssigneeID: &attendee, DueDate: &due})
+			notesRepo.AddActionItem(models.MeetingActionItem{ID: 2, MeetingID: 1, Description: "Book the demo room", AssigneeID: &organizer})
+			notesRepo.AddActionItem(models.MeetingActionItem{ID: 3, MeetingID: 1, Description: "Someone should check logs"})
+			notesRepo.AddActionItem(models.MeetingActionItem{ID: 4, MeetingID: 1, Description: "Done already", AssigneeID: &attendee, TaskID: &taskID})
+
+			rr := httptest.NewRecorder()
+			h.ConvertMeetingActionItems(rr, meetingNotesRequest(http.MethodPost, "/api/calendar/meetings/1/action-items/convert", tt.body, &models.User{ID: 2, Role: models.RoleEmployee}))
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if len(notesRepo.Tasks) != tt.expectedTasks {
+				t.Fatalf("created %d tasks, want %d", len(notesRepo.Tasks), tt.expectedTasks)
+			}
+			for _, task := range notesRepo.Tasks {
+				if task.MeetingID == nil || *task.MeetingID != 1 || task.CreatedByID != 2 || task.AssignmentType != models.AssignmentTypeUser {
+					t.Errorf("task = %+v", task)
+				}
+				if task.Title == "Write changelog" && !task.DueDate.Equal(due) {
+					t.Errorf("due date = %v, want the action item's %v", task.DueDate, due)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// GetTeamMilestones godoc
+// @Summary Get upcoming team birthdays and work anniversaries
+// @Description Returns the birthdays and work anniversaries of the current user's team from today through the given number of days, soonest first. Admins see the whole organization and supervisors their direct reports.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Days ahead to include" default(30)
+// @Success 200 {array} models.TeamMilestone "Upcoming milestones"
+// @Failure 400 {object} map[string]interface{} "Invalid days"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /team/milestones [get]
+func (h *Handlers) GetTeamMilestones(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionTeamView)
+	if currentUser == nil {
+		return
+	}
+
+	days := models.DefaultMilestoneDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > models.MaxMilestoneDays {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("days must be between 1 and %d", models.MaxMilestoneDays))
+			return
+		}
+		days = parsed
+	}
+
+	team, err := h.userService.GetEmployeesForUser(r.Context(), currentUser)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get team for milestones", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch team milestones")
+		return
+	}
+
+	// "Today" is the current user's calendar date
+	today := time.Now().In(currentUser.Location())
+	respondJSON(w, http.StatusOK, models.MilestonesBetween(team, today, today.AddDate(0, 0, days)))
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestGetTeamMilestones(t *testing.T) {
+	supervisorID := int64(2)
+	today := time.Now().UTC()
+	birthday := time.Date(1990, today.Month(), today.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 3)
+	started := time.Date(today.Year()-2, today.Month(), today.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 10)
+
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, Role: models.RoleSupervisor, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, Role: models.RoleEmployee, SupervisorID: &supervisorID, IsActive: true, DateStarted: &started})
+	userRepo.AddUser(&models.User{ID: 4, Role: models.RoleEmployee, IsActive: true})
+	h := New(userRepo, mocks.NewMockSquadRepository(), nil)
+
+	get := func(currentUserID int64, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/team/milestones"+query, nil)
+		req = req.WithContext(ctxWithUserFrom(req.Context(), userRepo.Users[currentUserID]))
+		rr := httptest.NewRecorder()
+		h.GetTeamMilestones(rr, req)
+		return rr
+	}
+
+	// Employees set their own birthday
+	req := httptest.NewRequest(http.MethodPut, "/api/users/3", strings.NewReader(`{"birthday":"`+birthday.Format("2006-01-02")+`"}`))
+	req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[3]), "id", "3"))
+	rr := httptest.NewRecorder()
+	h.UpdateUser(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("UpdateUser() status = %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = get(2, "?days=30")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GetTeamMilestones() status = %d: %s", rr.Code, rr.Body.String())
+	}
+	var milestones []models.TeamMilestone
+	if err := json.NewDecoder(rr.Body).Decode(&milestones); err != nil {
+		t.Fatal(err)
+	}
+	if len(milestones) != 2 || milestones[0].Type != models.MilestoneTypeBirthday || milestones[1].Type != models.MilestoneTypeWorkAnniversary || milestones[1].Years != 2 {
+		t.Errorf("GetTeamMilestones() = %+v, want a birthday then a 2 year anniversary", milestones)
+	}
+
+	if rr := get(2, "?days=5"); !strings.Contains(rr.Body.String(), `"birthday"`) || strings.Contains(rr.Body.String(), "work_anniversary") {
+		t.Errorf("GetTeamMilestones() within 5 days = %s", rr.Body.String())
+	}
+	if rr := get(2, "?days=400"); rr.Code != http.StatusBadRequest {
+		t.Errorf("GetTeamMilestones() with too many days status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+	if rr := get(4, ""); rr.Code != http.StatusForbidden {
+		t.Errorf("GetTeamMilestones() as employee status = %d, want %d", rr.Code, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// NotificationHandlers serves the current user's in-app notifications
+type NotificationHandlers struct {
+	notificationRepo repository.NotificationRepository
+	logger           *logger.Logger
+}
+
+// NewNotificationHandlers creates a new notification handlers instance
+func NewNotificationHandlers(notificationRepo repository.NotificationRepository) *NotificationHandlers {
+	return &NotificationHandlers{
+		notificationRepo: notificationRepo,
+		logger:           logger.Default().WithComponent("notification-handlers"),
+	}
+}
+
+// GetNotifications godoc
+// @Summary List notifications
+// @Description Returns the current user's notifications, newest first. With unread=true only unread notifications are returned.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Only unread notifications"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(50)
+// @Success 200 {object} PaginatedResponse "Notifications"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /notifications [get]
+func (h *NotificationHandlers) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	unreadOnly := false
+	if u := r.URL.Query().Get("unread"); u != "" {
+		parsed, err := strconv.ParseBool(u)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "unread must be true or false")
+			return
+		}
+		unreadOnly = parsed
+	}
+
+	p := parsePagination(r)
+	notifications, total, err := h.notificationRepo.List(r.Context(), currentUser.ID, unreadOnly, p.PerPage, p.Offset)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list notifications", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch notifications")
+		return
+	}
+
+	respondPaginated(w, notifications, total, p)
+}
+
+// GetUnreadCount godoc
+// @Summary Count unread notifications
+// @Description Returns how many of the current user's notifications are unread, for badges.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.UnreadNotificationCount "Unread count"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandlers) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	count, err := h.notificationRepo.CountUnread(r.Context(), currentUser.ID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to count unread notifications", err)
+		respondError(w, http.StatusInternalServerError, "Failed to count unread notifications")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.UnreadNotificationCount{UnreadCount: count})
+}
+
+// MarkRead godoc
+// @Summary Mark a notification read
+// @Description Marks one of the current user's notifications as read. Marking it again has no effect.
+// @Tags Notifications
+// @Security BearerAuth
+// @Param id path int true "Notification ID"
+// @Success 204 "Marked read"
+// @Failure 400 {object} map[string]interface{} "Invalid notification ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Notification not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandlers) MarkRead(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	// Other users' notifications are reported as not found
+	if err := h.notificationRepo.MarkRead(r.Context(), currentUser.ID, id); err != nil {
+		respondRepositoryError(w, r, err, "Notification")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications read
+// @Description Marks all of the current user's unread notifications as read and returns how many changed.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]int64 "Number of notifications marked read"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /notifications/read-all [post]
+func (h *NotificationHandlers) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	marked, err := h.notificationRepo.MarkAllRead(r.Context(), currentUser.ID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to mark notifications read", err)
+		respondError(w, http.StatusInternalServerError, "Failed to mark notifications read")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int64{"marked": marked})
+}
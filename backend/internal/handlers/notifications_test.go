@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestNotificationHandlers(t *testing.T) {
+	me := &models.User{ID: 1, Role: models.RoleEmployee}
+	notificationRepo := mocks.NewMockNotificationRepository()
+	for _, n := range []models.Notification{
+		{UserID: 1, Type: models.NotificationTypeKudos, Title: "Sam gave you kudos"},
+		{UserID: 2, Type: models.NotificationTypeKudos, Title: "Someone else's"},
+		{UserID: 1, Type: models.NotificationTypeMeetingInvite, Title: "Sam invited you to Design sync"},
+	} {
+		_, _ = notificationRepo.Create(context.Background(), &n)
+	}
+	h := NewNotificationHandlers(notificationRepo)
+
+	do := func(handler http.HandlerFunc, method, target, id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		ctx := ctxWithUserFrom(req.Context(), me)
+		if id != "" {
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+		}
+		rr := httptest.NewRecorder()
+		handler(rr, req.WithContext(ctx))
+		return rr
+	}
+	list := func(query string) []models.Notification {
+		rr := do(h.GetNotifications, http.MethodGet, "/api/notifications"+query, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GetNotifications() status = %d: %s", rr.Code, rr.Body.String())
+		}
+		var resp struct {
+			Data []models.Notification `json:"data"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data
+	}
+	unread := func() int {
+		rr := do(h.GetUnreadCount, http.MethodGet, "/api/notifications/unread-count", "")
+		var resp models.UnreadNotificationCount
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.UnreadCount
+	}
+
+	// Only the current user's notifications are listed, newest first
+	if got := list(""); len(got) != 2 || got[0].ID != 3 || got[1].ID != 1 {
+		t.Errorf("GetNotifications() = %+v, want notifications 3 and 1", got)
+	}
+	if got := unread(); got != 2 {
+		t.Errorf("unread count = %d, want 2", got)
+	}
+
+	if rr := do(h.MarkRead, http.MethodPost, "/api/notifications/3/read", "3"); rr.Code != http.StatusNoContent {
+		t.Errorf("MarkRead() status = %d, want %d", rr.Code, http.StatusNoContent)
+	}
+	if got := list("?unread=true"); len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("unread notifications = %+v, want notification 1", got)
+	}
+
+	// Other users' notifications can't be marked read
+	if rr := do(h.MarkRead, http.MethodPost, "/api/notifications/2/read", "2"); rr.Code != http.StatusNotFound {
+		t.Errorf("MarkRead() on another user's notification status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+	if rr := do(h.GetNotifications, http.MethodGet, "/api/notifications?unread=maybe", ""); rr.Code != http.StatusBadRequest {
+		t.Errorf("GetNotifications() with invalid unread status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	rr := do(h.MarkAllRead, http.MethodPost, "/api/notifications/read-all", "")
+	var marked map[string]int64
+	if err := json.NewDecoder(rr.Body).Decode(&marked); err != nil || marked["marked"] != 1 {
+		t.Errorf("MarkAllRead() = %v, %v, want 1 marked", marked, err)
+	}
+	if got := unread(); got != 0 {
+		t.Errorf("unread count after marking all read = %d, want 0", got)
+	}
+	if notificationRepo.Notifications[1].ReadAt != nil {
+		t.Error("another user's notification was marked read")
+	}
+}
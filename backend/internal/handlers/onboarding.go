@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// WithOnboarding creates onboarding tasks for users created through these handlers
+func (h *Handlers) WithOnboarding(onboarding *services.OnboardingService) *Handlers {
+	h.onboarding = onboarding
+	return h
+}
+
+// WithOnboarding creates onboarding tasks for users who accept an invitation
+func (h *InvitationHandlers) WithOnboarding(onboarding *services.OnboardingService) *InvitationHandlers {
+	h.onboarding = onboarding
+	return h
+}
+
+// WithOnboarding creates onboarding tasks for users whose access request is approved
+func (h *AccessRequestHandlers) WithOnboarding(onboarding *services.OnboardingService) *AccessRequestHandlers {
+	h.onboarding = onboarding
+	return h
+}
+
+// OnboardingHandlers handles onboarding templates and users' onboarding progress
+type OnboardingHandlers struct {
+	onboardingRepo repository.OnboardingRepository
+	userRepo       repository.UserRepository
+	onboarding     *services.OnboardingService
+	logger         *logger.Logger
+}
+
+// NewOnboardingHandlers creates a new onboarding handlers instance
+func NewOnboardingHandlers(onboardingRepo repository.OnboardingRepository, userRepo repository.UserRepository, onboarding *services.OnboardingService) *OnboardingHandlers {
+	return &OnboardingHandlers{
+		onboardingRepo: onboardingRepo,
+		userRepo:       userRepo,
+		onboarding:     onboarding,
+		logger:         logger.Default().WithComponent("onboarding-handlers"),
+	}
+}
+
+// GetTemplates godoc
+// @Summary List onboarding templates
+// @Description Returns every onboarding template with its checklist items
+// @Tags Onboarding
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.OnboardingTemplate "Templates"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /onboarding/templates [get]
+func (h *OnboardingHandlers) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionOnboardingView) == nil {
+		return
+	}
+
+	templates, err := h.onboardingRepo.ListTemplates(r.Context())
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list onboarding templates", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch onboarding templates")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, templates)
+}
+
+// CreateTemplate godoc
+// @Summary Create an onboarding template
+// @Description Creates an ordered checklist that becomes tasks for new users matching its department and role. Admin only.
+// @Tags Onboarding
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.OnboardingTemplateRequest true "Template"
+// @Success 201 {object} models.OnboardingTemplate "Created template"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /onboarding/templates [post]
+func (h *OnboardingHandlers) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionOnboardingManage) == nil {
+		return
+	}
+
+	var req models.OnboardingTemplateRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	template, err := h.onboardingRepo.CreateTemplate(r.Context(), &req)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to create onboarding template", err)
+		respondError(w, http.StatusInternalServerError, "Failed to create onboarding template")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, template)
+}
+
+// UpdateTemplate godoc
+// @Summary Replace an onboarding template
+// @Description Replaces a template and its checklist items. Tasks already created from it are unchanged. Admin only.
+// @Tags Onboarding
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Param request body models.OnboardingTemplateRequest true "Template"
+// @Success 200 {object} models.OnboardingTemplate "Updated template"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Template not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /onboarding/templates/{id} [put]
+func (h *OnboardingHandlers) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionOnboardingManage) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	var req models.OnboardingTemplateRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	template, err := h.onboardingRepo.UpdateTemplate(r.Context(), id, &req)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to update onboarding template", err, "template_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to update onboarding template")
+		return
+	}
+	if template == nil {
+		respondError(w, http.StatusNotFound, "Onboarding template not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, template)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete an onboarding template
+// @Description Deletes a template. Tasks already created from it are unchanged. Admin only.
+// @Tags Onboarding
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Success 200 {object} map[string]string "Template deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid template ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Template not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /onboarding/templates/{id} [delete]
+func (h *OnboardingHandlers) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionOnboardingManage) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	template, err := h.onboardingRepo.GetTemplate(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get onboarding template", err, "template_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to delete onboarding template")
+		return
+	}
+	if template == nil {
+		respondError(w, http.StatusNotFound, "Onboarding template not found")
+		return
+	}
+
+	if err := h.onboardingRepo.DeleteTemplate(r.Context(), id); err != nil {
+		h.logger.LogError(r.Context(), "Failed to delete onboarding template", err, "template_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to delete onboarding template")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Onboarding template deleted successfully"})
+}
+
+// GetProgress godoc
+// @Summary Get a user's onboarding progress
+// @Description Returns the tasks created for a user's onboarding and how many are completed. Available to the user, their managers, and admins.
+// @Tags Onboarding
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.OnboardingProgress "Onboarding progress"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/onboarding [get]
+func (h *OnboardingHandlers) GetProgress(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if user.ID != currentUser.ID && !currentUser.CanManage(user) {
+		respondError(w, http.StatusForbidden, "Forbidden: you can only view onboarding for yourself or your reports")
+		return
+	}
+
+	progress, err := h.onboarding.Progress(r.Context(), user.ID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get onboarding progress", err, "user_id", user.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch onboarding progress")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, progress)
+}
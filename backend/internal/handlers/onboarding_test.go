@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestOnboardingHandlers(t *testing.T) {
+	supervisorID := int64(2)
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, Role: models.RoleSupervisor, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, Role: models.RoleEmployee, SupervisorID: &supervisorID, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 4, Role: models.RoleEmployee, IsActive: true})
+	onboardingRepo := mocks.NewMockOnboardingRepository()
+	onboarding := services.NewOnboardingService(onboardingRepo, "IT")
+	h := NewOnboardingHandlers(onboardingRepo, userRepo, onboarding)
+
+	do := func(handler http.HandlerFunc, method string, currentUserID int64, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/onboarding", strings.NewReader(body))
+		req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[currentUserID]), "id", id))
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+
+	t.Run("templates", func(t *testing.T) {
+		body := `{"name":"Everyone","items":[{"title":"Sign the handbook","assignee":"new_hire"},{"title":"Welcome lunch","assignee":"supervisor","due_days":3}]}`
+		if rr := do(h.CreateTemplate, http.MethodPost, 2, "", body); rr.Code != http.StatusForbidden {
+			t.Errorf("CreateTemplate() as supervisor status = %d, want %d", rr.Code, http.StatusForbidden)
+		}
+		if rr := do(h.CreateTemplate, http.MethodPost, 1, "", body); rr.Code != http.StatusCreated {
+			t.Fatalf("CreateTemplate() status = %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr := do(h.UpdateTemplate, http.MethodPut, 1, "99", body); rr.Code != http.StatusNotFound {
+			t.Errorf("UpdateTemplate() for missing template status = %d, want %d", rr.Code, http.StatusNotFound)
+		}
+
+		var templates []models.OnboardingTemplate
+		rr := do(h.GetTemplates, http.MethodGet, 2, "", "")
+		if err := json.NewDecoder(rr.Body).Decode(&templates); err != nil {
+			t.Fatal(err)
+		}
+		if len(templates) != 1 || len(templates[0].Items) != 2 {
+			t.Errorf("GetTemplates() = %+v", templates)
+		}
+	})
+
+	t.Run("creating a user starts onboarding", func(t *testing.T) {
+		users := New(userRepo, mocks.NewMockSquadRepository(), nil).WithOnboarding(onboarding)
+		req := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(
+			`{"email":"new@example.com","first_name":"New","last_name":"Hire","role":"employee"}`))
+		req = req.WithContext(ctxWithUserFrom(req.Context(), userRepo.Users[2]))
+		rr := httptest.NewRecorder()
+		users.CreateUser(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("CreateUser() status = %d: %s", rr.Code, rr.Body.String())
+		}
+		var created models.UserResponse
+		if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(onboardingRepo.Tasks[created.ID]); got != 2 {
+			t.Errorf("onboarding tasks = %d, want 2", got)
+		}
+	})
+
+	t.Run("progress", func(t *testing.T) {
+		onboardingRepo.Tasks[3] = []models.Task{{ID: 10, Status: models.TaskStatusCompleted}, {ID: 11, Status: models.TaskStatusPending}}
+
+		for _, tt := range []struct {
+			name          string
+			currentUserID int64
+			want          int
+		}{
+			{"self", 3, http.StatusOK},
+			{"supervisor", 2, http.StatusOK},
+			{"admin", 1, http.StatusOK},
+			{"unrelated employee", 4, http.StatusForbidden},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				if rr := do(h.GetProgress, http.MethodGet, tt.currentUserID, "3", ""); rr.Code != tt.want {
+					t.Errorf("GetProgress() status = %d, want %d", rr.Code, tt.want)
+				}
+			})
+		}
+
+		var progress models.OnboardingProgress
+		rr := do(h.GetProgress, http.MethodGet, 3, "3", "")
+		if err := json.NewDecoder(rr.Body).Decode(&progress); err != nil {
+			t.Fatal(err)
+		}
+		if progress.Total != 2 || progress.Completed != 1 || progress.Progress != 0.5 {
+			t.Errorf("GetProgress() = %+v", progress)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// OrgSettingsHandlers manage the settings admins can change for their organization without a redeploy
+type OrgSettingsHandlers struct {
+	settings *services.OrgSettingsService
+	logger   *logger.Logger
+}
+
+// NewOrgSettingsHandlers creates a new organization settings handlers instance
+func NewOrgSettingsHandlers(settings *services.OrgSettingsService) *OrgSettingsHandlers {
+	return &OrgSettingsHandlers{
+		settings: settings,
+		logger:   logger.Default().WithComponent("org-settings-handlers"),
+	}
+}
+
+// GetSettings godoc
+// @Summary Get the organization's settings
+// @Description Returns every organization setting with its value, the server's default, and the values it accepts. Requires settings:manage.
+// @Tags Settings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.OrgSetting "Settings"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/settings [get]
+func (h *OrgSettingsHandlers) GetSettings(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionSettingsManage)
+	if currentUser == nil {
+		return
+	}
+
+	settings, err := h.settings.List(r.Context(), currentUser.OrgID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get org settings", err, "org_id", currentUser.OrgID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateSettings godoc
+// @Summary Change the organization's settings
+// @Description Changes the given settings; a null value resets a setting to the server's default. Changes apply within a minute. Requires settings:manage.
+// @Tags Settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateOrgSettingsRequest true "Settings"
+// @Success 200 {array} models.OrgSetting "Settings"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/settings [put]
+func (h *OrgSettingsHandlers) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionSettingsManage)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.UpdateOrgSettingsRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	settings, err := h.settings.Update(r.Context(), currentUser.OrgID, &req, currentUser.ID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to update org settings", err, "org_id", currentUser.OrgID)
+		respondError(w, http.StatusInternalServerError, "Failed to update settings")
+		return
+	}
+
+	h.logger.Info("Org settings updated", "org_id", currentUser.OrgID, "settings", len(req.Settings), "updated_by", currentUser.ID)
+	respondJSON(w, http.StatusOK, settings)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestOrgSettingsHandlers_GetSettings(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		expectedStatus int
+	}{
+		{"admin", &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}, http.StatusOK},
+		{"supervisor", &models.User{ID: 2, Role: models.RoleSupervisor, OrgID: 1}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgRepo := mocks.NewMockOrganizationRepository()
+			orgRepo.Settings[1][models.OrgSettingAvatarMaxSizeMB] = "10"
+			h := NewOrgSettingsHandlers(services.NewOrgSettingsService(orgRepo, map[models.OrgSettingKey]int{
+				models.OrgSettingAvatarMaxSizeMB: 5,
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/settings", nil)
+			req = req.WithContext(ctxWithUser(tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.GetSettings(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var settings []models.OrgSetting
+			if err := json.Unmarshal(rr.Body.Bytes(), &settings); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			for _, setting := range settings {
+				if setting.Key == models.OrgSettingAvatarMaxSizeMB && (setting.Value != 10 || setting.Default != 5 || !setting.Overridden) {
+					t.Errorf("avatar setting = %+v, want 10 overriding 5", setting)
+				}
+			}
+		})
+	}
+}
+
+func TestOrgSettingsHandlers_UpdateSettings(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		body           string
+		expectedStatus int
+		expectedStored map[models.OrgSettingKey]string
+	}{
+		{"valid", admin, `{"settings":{"invitation_expiry_days":30}}`, http.StatusOK,
+			map[models.OrgSettingKey]string{models.OrgSettingInvitationExpiryDays: "30", models.OrgSettingAvatarMaxSizeMB: "10"}},
+		{"reset", admin, `{"settings":{"avatar_max_size_mb":null}}`, http.StatusOK,
+			map[models.OrgSettingKey]string{}},
+		{"unknown setting", admin, `{"settings":{"rate_limit":10}}`, http.StatusBadRequest, nil},
+		{"out of range", admin, `{"settings":{"invitation_expiry_days":365}}`, http.StatusBadRequest, nil},
+		{"employee", &models.User{ID: 2, Role: models.RoleEmployee, OrgID: 1}, `{"settings":{"invitation_expiry_days":30}}`, http.StatusForbidden, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgRepo := mocks.NewMockOrganizationRepository()
+			orgRepo.Settings[1][models.OrgSettingAvatarMaxSizeMB] = "10"
+			h := NewOrgSettingsHandlers(services.NewOrgSettingsService(orgRepo, map[models.OrgSettingKey]int{}))
+
+			req := httptest.NewRequest(http.MethodPut, "/api/admin/settings", strings.NewReader(tt.body))
+			req = req.WithContext(ctxWithUser(tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.UpdateSettings(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStored == nil {
+				return
+			}
+			if fmt.Sprint(orgRepo.Settings[1]) != fmt.Sprint(tt.expectedStored) {
+				t.Errorf("stored settings = %v, want %v", orgRepo.Settings[1], tt.expectedStored)
+			}
+		})
+	}
+}
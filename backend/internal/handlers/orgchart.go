@@ -1,27 +1,104 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
 )
 
 type OrgChartHandlers struct {
-	orgChartRepo repository.OrgChartRepository
-	userRepo     repository.UserRepository
+	orgChartRepo  repository.OrgChartRepository
+	userRepo      repository.UserRepository
+	authz         *services.AuthorizationService
+	broker        *events.Broker
+	notifications *services.NotificationService
 }
 
 func NewOrgChartHandlers(orgChartRepo repository.OrgChartRepository, userRepo repository.UserRepository) *OrgChartHandlers {
 	return &OrgChartHandlers{
 		orgChartRepo: orgChartRepo,
 		userRepo:     userRepo,
+		authz:        services.NewAuthorizationService(userRepo),
 	}
 }
 
-// CreateDraft creates a new org chart draft (supervisor only)
+// NewOrgChartHandlersWithEvents creates org chart handlers that announce published drafts on the given broker
+func NewOrgChartHandlersWithEvents(orgChartRepo repository.OrgChartRepository, userRepo repository.UserRepository, broker *events.Broker) *OrgChartHandlers {
+	h := NewOrgChartHandlers(orgChartRepo, userRepo)
+	h.broker = broker
+	return h
+}
+
+// WithNotifications tells people in-app when a published draft changes their place in the org chart
+func (h *OrgChartHandlers) WithNotifications(notifications *services.NotificationService) *OrgChartHandlers {
+	h.notifications = notifications
+	return h
+}
+
+// draftAccess is what a user may do with a draft, each level including the ones before it
+type draftAccess int
+
+const (
+	draftAccessNone  draftAccess = iota
+	draftAccessView              // Shared as a viewer: view and comment
+	draftAccessEdit              // Shared as an editor: also change and approve
+	draftAccessOwner             // Manages the draft, like its creator: also share, publish, and delete
+)
+
+// draftAccessFor returns what the user may do with a draft
+func (h *OrgChartHandlers) draftAccessFor(r *http.Request, draft *models.OrgChartDraft, user *models.User) (draftAccess, error) {
+	if authz.Can(user, authz.ActionOrgChartManage, authz.OrgChartDraftResource(draft)) {
+		return draftAccessOwner, nil
+	}
+	share, err := h.orgChartRepo.GetDraftShare(r.Context(), draft.ID, user.ID)
+	if err != nil || share == nil {
+		return draftAccessNone, err
+	}
+	if share.Permission == models.DraftPermissionEditor {
+		return draftAccessEdit, nil
+	}
+	return draftAccessView, nil
+}
+
+// requireDraft loads the draft named by the id URL parameter, responding with an error and
+// returning nil when it doesn't exist or the user lacks the needed access
+func (h *OrgChartHandlers) requireDraft(w http.ResponseWriter, r *http.Request, user *models.User, need draftAccess) *models.OrgChartDraft {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid draft ID")
+		return nil
+	}
+
+	draft, err := h.orgChartRepo.GetDraftByID(r.Context(), id)
+	if err != nil {
+		respondRepositoryError(w, r, err, "Draft")
+		return nil
+	}
+
+	access, err := h.draftAccessFor(r, draft, user)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to check draft access")
+		return nil
+	}
+	if access < need {
+		respondError(w, http.StatusForbidden, "Forbidden: not draft owner")
+		return nil
+	}
+	return draft
+}
+
+// CreateDraft creates a new org chart draft
 func (h *OrgChartHandlers) CreateDraft(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireSupervisor(w, r)
+	currentUser := requirePermission(w, r, authz.ActionOrgChartManage)
 	if currentUser == nil {
 		return
 	}
@@ -40,9 +117,10 @@ func (h *OrgChartHandlers) CreateDraft(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusCreated, draft)
 }
 
-// GetDrafts returns all drafts for the current user (supervisor) or all drafts (admin)
+// GetDrafts returns the current user's drafts and those shared with them, or every draft for
+// users who may manage them all
 func (h *OrgChartHandlers) GetDrafts(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireSupervisor(w, r)
+	currentUser := requirePermission(w, r, authz.ActionOrgChartManage)
 	if currentUser == nil {
 		return
 	}
@@ -50,11 +128,15 @@ func (h *OrgChartHandlers) GetDrafts(w http.ResponseWriter, r *http.Request) {
 	var drafts []models.OrgChartDraft
 	var err error
 
-	// Admins can see all drafts, supervisors only see their own
-	if currentUser.IsAdmin() {
+	if authz.CanAll(currentUser, authz.ActionOrgChartManage) {
 		drafts, err = h.orgChartRepo.GetAllDrafts(r.Context())
 	} else {
 		drafts, err = h.orgChartRepo.GetDraftsByCreator(r.Context(), currentUser.ID)
+		if err == nil {
+			var shared []models.OrgChartDraft
+			shared, err = h.orgChartRepo.GetSharedDrafts(r.Context(), currentUser.ID)
+			drafts = append(drafts, shared...)
+		}
 	}
 
 	if err != nil {
@@ -69,164 +151,201 @@ func (h *OrgChartHandlers) GetDrafts(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, drafts)
 }
 
-// GetDraft returns a single draft with its changes (owner or admin)
+// GetDraft returns a single draft with its changes (owner, admin, or shared)
 func (h *OrgChartHandlers) GetDraft(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
 	if currentUser == nil {
 		return
 	}
 
-	id, err := parseIDParam(r, "id")
-	if err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid draft ID")
+	draft := h.requireDraft(w, r, currentUser, draftAccessView)
+	if draft == nil {
 		return
 	}
 
-	draft, err := h.orgChartRepo.GetDraftByID(r.Context(), id)
-	if err != nil {
-		respondError(w, http.StatusNotFound, "Draft not found")
+	respondJSON(w, http.StatusOK, draft)
+}
+
+// UpdateDraft updates a draft's name/description (owner, admin, or editor)
+func (h *OrgChartHandlers) UpdateDraft(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
 		return
 	}
 
-	// Only draft owner or admin can view
-	if draft.CreatedByID != currentUser.ID && !currentUser.IsAdmin() {
-		respondError(w, http.StatusForbidden, "Forbidden: not draft owner")
+	draft := h.requireDraft(w, r, currentUser, draftAccessEdit)
+	if draft == nil {
 		return
 	}
 
-	respondJSON(w, http.StatusOK, draft)
+	var req models.UpdateDraftRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	updatedDraft, err := h.orgChartRepo.UpdateDraft(r.Context(), draft.ID, &req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update draft")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updatedDraft)
 }
 
-// UpdateDraft updates a draft's name/description (owner or admin)
-func (h *OrgChartHandlers) UpdateDraft(w http.ResponseWriter, r *http.Request) {
+// DeleteDraft deletes a draft (owner or admin)
+func (h *OrgChartHandlers) DeleteDraft(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
 	if currentUser == nil {
 		return
 	}
 
-	id, err := parseIDParam(r, "id")
-	if err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid draft ID")
+	draft := h.requireDraft(w, r, currentUser, draftAccessOwner)
+	if draft == nil {
 		return
 	}
 
-	// Check ownership or admin
-	draft, err := h.orgChartRepo.GetDraftByID(r.Context(), id)
-	if err != nil {
-		respondError(w, http.StatusNotFound, "Draft not found")
+	if err := h.orgChartRepo.DeleteDraft(r.Context(), draft.ID); err != nil {
+		respondError(w, http.StatusBadRequest, "Failed to delete draft")
 		return
 	}
-	if draft.CreatedByID != currentUser.ID && !currentUser.IsAdmin() {
-		respondError(w, http.StatusForbidden, "Forbidden: not draft owner")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddChange adds or updates a change in a draft (owner, admin, or editor, must be able to manage target user)
+func (h *OrgChartHandlers) AddChange(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
 		return
 	}
 
-	var req models.UpdateDraftRequest
+	draft := h.requireDraft(w, r, currentUser, draftAccessEdit)
+	if draft == nil {
+		return
+	}
+
+	var req models.AddDraftChangeRequest
 	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
 		return
 	}
 
-	updatedDraft, err := h.orgChartRepo.UpdateDraft(r.Context(), id, &req)
+	// Verify the user is a direct report of the current supervisor
+	targetUser, err := h.userRepo.GetByID(r.Context(), req.UserID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to update draft")
+		respondRepositoryError(w, r, err, "User")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, updatedDraft)
+	if !currentUser.CanManage(targetUser) {
+		respondError(w, http.StatusForbidden, "Forbidden: can only modify direct reports")
+		return
+	}
+
+	change, err := h.orgChartRepo.AddOrUpdateChange(r.Context(), draft.ID, &req, h.userRepo)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to add change")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, change)
 }
 
-// DeleteDraft deletes a draft (owner or admin)
-func (h *OrgChartHandlers) DeleteDraft(w http.ResponseWriter, r *http.Request) {
+// RemoveChange removes a change from a draft (owner, admin, or editor)
+func (h *OrgChartHandlers) RemoveChange(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
 	if currentUser == nil {
 		return
 	}
 
-	id, err := parseIDParam(r, "id")
-	if err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid draft ID")
+	draft := h.requireDraft(w, r, currentUser, draftAccessEdit)
+	if draft == nil {
 		return
 	}
 
-	// Check ownership or admin
-	draft, err := h.orgChartRepo.GetDraftByID(r.Context(), id)
+	userID, err := parseIDParam(r, "userId")
 	if err != nil {
-		respondError(w, http.StatusNotFound, "Draft not found")
-		return
-	}
-	if draft.CreatedByID != currentUser.ID && !currentUser.IsAdmin() {
-		respondError(w, http.StatusForbidden, "Forbidden: not draft owner")
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
 		return
 	}
 
-	if err := h.orgChartRepo.DeleteDraft(r.Context(), id); err != nil {
-		respondError(w, http.StatusBadRequest, "Failed to delete draft")
+	if err := h.orgChartRepo.RemoveChange(r.Context(), draft.ID, userID); err != nil {
+		respondError(w, http.StatusBadRequest, "Failed to remove change from draft")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// AddChange adds or updates a change in a draft (owner or admin, must be able to manage target user)
-func (h *OrgChartHandlers) AddChange(w http.ResponseWriter, r *http.Request) {
+// GetDraftShares lists who a draft is shared with (owner, admin, or shared)
+func (h *OrgChartHandlers) GetDraftShares(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
 	if currentUser == nil {
 		return
 	}
 
-	draftID, err := parseIDParam(r, "id")
-	if err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid draft ID")
+	draft := h.requireDraft(w, r, currentUser, draftAccessView)
+	if draft == nil {
 		return
 	}
 
-	// Check ownership or admin
-	draft, err := h.orgChartRepo.GetDraftByID(r.Context(), draftID)
+	shares, err := h.orgChartRepo.GetDraftShares(r.Context(), draft.ID)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "Draft not found")
+		respondError(w, http.StatusInternalServerError, "Failed to fetch draft shares")
 		return
 	}
-	if draft.CreatedByID != currentUser.ID && !currentUser.IsAdmin() {
-		respondError(w, http.StatusForbidden, "Forbidden: not draft owner")
+
+	respondJSON(w, http.StatusOK, shares)
+}
+
+// ShareDraft shares a draft with another supervisor or admin as a viewer or editor (owner or admin)
+func (h *OrgChartHandlers) ShareDraft(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
 		return
 	}
 
-	var req models.AddDraftChangeRequest
+	draft := h.requireDraft(w, r, currentUser, draftAccessOwner)
+	if draft == nil {
+		return
+	}
+
+	var req models.ShareDraftRequest
 	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
 		return
 	}
 
-	// Verify the user is a direct report of the current supervisor
-	targetUser, err := h.userRepo.GetByID(r.Context(), req.UserID)
-	if err != nil {
+	user, err := h.userRepo.GetByID(r.Context(), req.UserID)
+	if err != nil || user == nil {
 		respondError(w, http.StatusNotFound, "User not found")
 		return
 	}
-
-	if !currentUser.CanManage(targetUser) {
-		respondError(w, http.StatusForbidden, "Forbidden: can only modify direct reports")
+	if !authz.Can(user, authz.ActionOrgChartManage, nil) || !user.IsActive {
+		respondError(w, http.StatusBadRequest, "Drafts can only be shared with active users who can manage the org chart")
+		return
+	}
+	if user.ID == draft.CreatedByID {
+		respondError(w, http.StatusBadRequest, "Draft creators already have full access")
 		return
 	}
 
-	change, err := h.orgChartRepo.AddOrUpdateChange(r.Context(), draftID, &req, h.userRepo)
+	share, err := h.orgChartRepo.ShareDraft(r.Context(), draft.ID, &req, currentUser.ID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to add change")
+		respondError(w, http.StatusInternalServerError, "Failed to share draft")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, change)
+	respondJSON(w, http.StatusOK, share)
 }
 
-// RemoveChange removes a change from a draft (owner or admin)
-func (h *OrgChartHandlers) RemoveChange(w http.ResponseWriter, r *http.Request) {
+// UnshareDraft stops sharing a draft with a user (owner or admin)
+func (h *OrgChartHandlers) UnshareDraft(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
 	if currentUser == nil {
 		return
 	}
 
-	draftID, err := parseIDParam(r, "id")
-	if err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid draft ID")
+	draft := h.requireDraft(w, r, currentUser, draftAccessOwner)
+	if draft == nil {
 		return
 	}
 
@@ -236,80 +355,439 @@ func (h *OrgChartHandlers) RemoveChange(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Check ownership or admin
-	draft, err := h.orgChartRepo.GetDraftByID(r.Context(), draftID)
+	if err := h.orgChartRepo.UnshareDraft(r.Context(), draft.ID, userID); err != nil {
+		respondError(w, http.StatusNotFound, "Share not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDraftComments returns the review comments on a draft's changes (owner, admin, or shared)
+func (h *OrgChartHandlers) GetDraftComments(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	draft := h.requireDraft(w, r, currentUser, draftAccessView)
+	if draft == nil {
+		return
+	}
+
+	comments, err := h.orgChartRepo.GetDraftComments(r.Context(), draft.ID)
 	if err != nil {
-		respondError(w, http.StatusNotFound, "Draft not found")
+		respondError(w, http.StatusInternalServerError, "Failed to fetch draft comments")
 		return
 	}
-	if draft.CreatedByID != currentUser.ID && !currentUser.IsAdmin() {
-		respondError(w, http.StatusForbidden, "Forbidden: not draft owner")
+
+	respondJSON(w, http.StatusOK, comments)
+}
+
+// AddDraftComment comments on one of a draft's changes (owner, admin, or shared)
+func (h *OrgChartHandlers) AddDraftComment(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
 		return
 	}
 
-	if err := h.orgChartRepo.RemoveChange(r.Context(), draftID, userID); err != nil {
-		respondError(w, http.StatusBadRequest, "Failed to remove change from draft")
+	draft := h.requireDraft(w, r, currentUser, draftAccessView)
+	if draft == nil {
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	var req models.CreateDraftCommentRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	comment, err := h.orgChartRepo.AddDraftComment(r.Context(), draft.ID, &req, currentUser.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to add comment")
+		return
+	}
+	if comment == nil {
+		respondError(w, http.StatusNotFound, "Change not found in this draft")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, comment)
 }
 
-// PublishDraft publishes a draft, applying all changes (owner or admin)
-func (h *OrgChartHandlers) PublishDraft(w http.ResponseWriter, r *http.Request) {
+// ApproveDraft approves a draft for publishing. Anyone with edit access other than the draft's
+// creator may approve it; editing the draft's changes afterwards withdraws the approval.
+func (h *OrgChartHandlers) ApproveDraft(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
 	if currentUser == nil {
 		return
 	}
 
-	id, err := parseIDParam(r, "id")
+	draft := h.requireDraft(w, r, currentUser, draftAccessEdit)
+	if draft == nil {
+		return
+	}
+	if draft.CreatedByID == currentUser.ID {
+		respondError(w, http.StatusForbidden, "Drafts must be approved by someone other than their creator")
+		return
+	}
+
+	approved, err := h.orgChartRepo.ApproveDraft(r.Context(), draft.ID, currentUser.ID)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid draft ID")
+		respondError(w, http.StatusInternalServerError, "Failed to approve draft")
+		return
+	}
+	if approved == nil {
+		respondError(w, http.StatusBadRequest, "Only drafts that haven't been published can be approved")
 		return
 	}
 
-	// Check ownership or admin
-	draft, err := h.orgChartRepo.GetDraftByID(r.Context(), id)
+	respondJSON(w, http.StatusOK, approved)
+}
+
+// PreviewDraft returns the org tree a draft would produce, how each changed user would move, and any
+// problems publishing would cause (owner, admin, or shared)
+func (h *OrgChartHandlers) PreviewDraft(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	draft := h.requireDraft(w, r, currentUser, draftAccessView)
+	if draft == nil {
+		return
+	}
+
+	users, err := h.userRepo.GetAll(r.Context())
 	if err != nil {
-		respondError(w, http.StatusNotFound, "Draft not found")
+		respondError(w, http.StatusInternalServerError, "Failed to fetch users")
 		return
 	}
-	if draft.CreatedByID != currentUser.ID && !currentUser.IsAdmin() {
-		respondError(w, http.StatusForbidden, "Forbidden: not draft owner")
+	// Guests are external collaborators, not part of the org structure
+	var members []models.User
+	for _, u := range users {
+		if u.IsActive && !u.IsGuest() {
+			members = append(members, u)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, services.PreviewOrgChartDraft(draft.ID, members, draft.Changes))
+}
+
+// PublishDraft publishes an approved draft, applying all changes (owner or admin)
+func (h *OrgChartHandlers) PublishDraft(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	draft := h.requireDraft(w, r, currentUser, draftAccessOwner)
+	if draft == nil {
 		return
 	}
 
-	if err := h.orgChartRepo.PublishDraft(r.Context(), id); err != nil {
+	if err := h.orgChartRepo.PublishDraft(r.Context(), draft.ID, currentUser.ID); err != nil {
+		var publishErr *models.DraftPublishError
+		if errors.As(err, &publishErr) {
+			// Nothing was applied; say which change to fix
+			respondJSON(w, http.StatusUnprocessableEntity, publishErr)
+			return
+		}
 		respondError(w, http.StatusBadRequest, "Failed to publish draft")
 		return
 	}
+	h.broker.Publish(r.Context(), events.OrgChartPublished, nil)
+	h.notifications.NotifyOrgChartPublished(r.Context(), draft, currentUser)
 
 	respondJSON(w, http.StatusOK, map[string]string{"status": "published"})
 }
 
-// GetOrgTree returns the org chart tree for the current supervisor or full org tree for admins
-func (h *OrgChartHandlers) GetOrgTree(w http.ResponseWriter, r *http.Request) {
-	currentUser := requireSupervisor(w, r)
+// GetOrgChartHistory lists the snapshots taken each time a draft was published, newest first (admin only).
+// With as_of=YYYY-MM-DD only snapshots taken by the end of that day are listed, so the first is the org as of then.
+func (h *OrgChartHandlers) GetOrgChartHistory(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionOrgChartHistory) == nil {
+		return
+	}
+
+	var before *time.Time
+	if asOf := r.URL.Query().Get("as_of"); asOf != "" {
+		parsed, err := time.Parse("2006-01-02", asOf)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "as_of must use YYYY-MM-DD")
+			return
+		}
+		nextDay := parsed.AddDate(0, 0, 1)
+		before = &nextDay
+	}
+
+	p := parsePagination(r)
+	snapshots, total, err := h.orgChartRepo.GetSnapshots(r.Context(), before, p.PerPage, p.Offset)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch org chart history")
+		return
+	}
+
+	respondPaginated(w, snapshots, total, p)
+}
+
+// GetOrgChartSnapshot returns a snapshot with the full org tree as it stood when it was taken (admin only)
+func (h *OrgChartHandlers) GetOrgChartSnapshot(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionOrgChartHistory) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid snapshot ID")
+		return
+	}
+
+	snapshot, err := h.orgChartRepo.GetSnapshotByID(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch snapshot")
+		return
+	}
+	if snapshot == nil {
+		respondError(w, http.StatusNotFound, "Snapshot not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, snapshot)
+}
+
+// ExportOrgChart downloads the current org chart as a flattened reporting list (format=csv or json)
+// or a Graphviz graph (format=dot). Admins and viewers get the whole org, supervisors their subtree.
+func (h *OrgChartHandlers) ExportOrgChart(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
 	if currentUser == nil {
 		return
 	}
 
-	// Admins get the full org tree, supervisors get their subtree
-	if currentUser.IsAdmin() {
-		trees, err := h.orgChartRepo.GetFullOrgTree(r.Context())
+	if err := h.authz.CanViewOrgChart(currentUser); err != nil {
+		respondErrorWithCode(w, http.StatusForbidden, string(apperrors.CodeSupervisorRequired), "Forbidden: supervisor access required")
+		return
+	}
+
+	format := models.OrgChartExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = models.OrgChartExportCSV
+	}
+
+	var trees []models.OrgTreeNode
+	if h.authz.CanViewOrgWide(currentUser) == nil {
+		var err error
+		if trees, err = h.orgChartRepo.GetFullOrgTree(r.Context()); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get org tree")
+			return
+		}
+	} else {
+		tree, err := h.orgChartRepo.GetOrgTree(r.Context(), currentUser.ID)
 		if err != nil {
 			respondError(w, http.StatusInternalServerError, "Failed to get org tree")
 			return
 		}
+		trees = []models.OrgTreeNode{*tree}
+	}
+
+	data, contentType, err := services.ExportOrgChart(trees, format)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="org-chart-%s.%s"`, time.Now().Format("2006-01-02"), format))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// GetOrgTree returns the org chart tree for the current supervisor or full org tree for admins and viewers.
+// The include query parameter (e.g. include=headcount,open_tasks,out_of_office,goal_progress) adds
+// aggregates rolled up over each node's subtree. Responses carry a weak ETag so clients can revalidate
+// with If-None-Match.
+//
+// root returns the tree under one user instead; supervisors can only choose themselves or their
+// reports. depth limits how many levels of reports are loaded, marking nodes with more as
+// children_truncated so clients can load them from GetOrgTreeChildren, and aggregates then only
+// cover the loaded levels. include_inactive=true also returns deactivated users, for admins.
+func (h *OrgChartHandlers) GetOrgTree(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	if err := h.authz.CanViewOrgChart(currentUser); err != nil {
+		respondErrorWithCode(w, http.StatusForbidden, string(apperrors.CodeSupervisorRequired), "Forbidden: supervisor access required")
+		return
+	}
+
+	scope, ok := h.parseOrgTreeScope(w, r, currentUser)
+	if !ok {
+		return
+	}
+
+	// Admins and viewers get the full org tree, supervisors get their subtree
+	if scope.RootID == nil && h.authz.CanViewOrgWide(currentUser) != nil {
+		scope.RootID = &currentUser.ID
+	}
+	trees, ok := h.loadOrgTree(w, r, currentUser, scope)
+	if !ok {
+		return
+	}
+	if scope.RootID == nil {
 		respondJSON(w, http.StatusOK, trees)
 		return
 	}
+	respondJSON(w, http.StatusOK, trees[0])
+}
 
-	tree, err := h.orgChartRepo.GetOrgTree(r.Context(), currentUser.ID)
+// GetOrgTreeChildren returns a user's direct reports as org tree nodes, for loading the parts of
+// the tree GetOrgTree cut off at its depth limit. depth is how many levels of reports to load below
+// them (default 0), and include and include_inactive work as they do for GetOrgTree. Supervisors can
+// only load their own and their reports' children.
+func (h *OrgChartHandlers) GetOrgTreeChildren(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "userId")
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to get org tree")
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	if err := h.authz.CanViewOrgChart(currentUser); err != nil {
+		respondErrorWithCode(w, http.StatusForbidden, string(apperrors.CodeSupervisorRequired), "Forbidden: supervisor access required")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, tree)
+	scope, ok := h.parseOrgTreeScope(w, r, currentUser)
+	if !ok {
+		return
+	}
+	depth := 1
+	if scope.Depth != nil {
+		depth += *scope.Depth
+	}
+	scope.RootID, scope.Depth = &userID, &depth
+
+	trees, ok := h.loadOrgTree(w, r, currentUser, scope)
+	if !ok {
+		return
+	}
+	respondJSON(w, http.StatusOK, trees[0].Children)
+}
+
+// parseOrgTreeScope reads the root, depth, and include_inactive query parameters, writing a 400 if
+// they're invalid and a 403 if the current user can't see inactive users but asked to
+func (h *OrgChartHandlers) parseOrgTreeScope(w http.ResponseWriter, r *http.Request, currentUser *models.User) (models.OrgTreeScope, bool) {
+	var scope models.OrgTreeScope
+	q := r.URL.Query()
+	if v := q.Get("root"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			respondError(w, http.StatusBadRequest, "root must be a user ID")
+			return scope, false
+		}
+		scope.RootID = &id
+	}
+	if v := q.Get("depth"); v != "" {
+		depth, err := strconv.Atoi(v)
+		if err != nil || depth < 0 {
+			respondError(w, http.StatusBadRequest, "depth must be a whole number of levels")
+			return scope, false
+		}
+		scope.Depth = &depth
+	}
+	if v := q.Get("include_inactive"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "include_inactive must be true or false")
+			return scope, false
+		}
+		scope.IncludeInactive = include
+	}
+	if scope.IncludeInactive && !authz.Can(currentUser, authz.ActionUserViewInactive, nil) {
+		respondError(w, http.StatusForbidden, "Forbidden: only admins can view inactive users")
+		return scope, false
+	}
+	return scope, true
+}
+
+// loadOrgTree loads the trees in the scope with the aggregates asked for by the include query
+// parameter, writing the error response or a 304 if the client's copy is current. A rooted scope
+// returns exactly one tree.
+func (h *OrgChartHandlers) loadOrgTree(w http.ResponseWriter, r *http.Request, currentUser *models.User, scope models.OrgTreeScope) ([]models.OrgTreeNode, bool) {
+	opts, err := models.ParseOrgTreeOptions(r.URL.Query().Get("include"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if scope.RootID != nil {
+		if err := h.authz.CanViewOrgSubtree(r.Context(), currentUser, *scope.RootID); err != nil {
+			respondAppError(w, err)
+			return nil, false
+		}
+	}
+
+	trees, err := h.orgChartRepo.GetScopedOrgTree(r.Context(), scope)
+	if err != nil {
+		respondRepositoryError(w, r, err, "User")
+		return nil, false
+	}
+	roots := make([]*models.OrgTreeNode, len(trees))
+	for i := range trees {
+		roots[i] = &trees[i]
+	}
+	if err := services.LoadOrgTreeMetrics(r.Context(), h.orgChartRepo, roots, opts, time.Now()); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to compute org tree metrics")
+		return nil, false
+	}
+	if notModified(w, r, orgTreeETag(roots)) {
+		return nil, false
+	}
+	return trees, true
+}
+
+// orgTreeETag identifies a version of an org tree by its shape, the latest updated_at of its users
+// and pending changes, and the metrics computed for it
+func orgTreeETag(roots []*models.OrgTreeNode) string {
+	var latest time.Time
+	b := newETagBuilder()
+	var walk func(node *models.OrgTreeNode)
+	walk = func(node *models.OrgTreeNode) {
+		if node.User.UpdatedAt.After(latest) {
+			latest = node.User.UpdatedAt
+		}
+		b.add(node.User.ID, len(node.Children), node.ChildrenTruncated)
+		for _, s := range node.User.Squads {
+			b.add(s.ID, s.Name)
+		}
+		if change := node.PendingChange; change != nil {
+			b.add(change.ID)
+			if change.UpdatedAt.After(latest) {
+				latest = change.UpdatedAt
+			}
+		}
+		if m := node.Metrics; m != nil {
+			b.add(derefInt(m.Headcount), derefInt(m.OpenTasks), derefInt(m.OutOfOffice))
+			if m.GoalProgress != nil {
+				b.add(*m.GoalProgress)
+			}
+		}
+		for i := range node.Children {
+			walk(&node.Children[i])
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+	return b.add(len(roots), latest).weak()
+}
+
+// derefInt returns *v, or -1 for nil so a missing metric hashes differently from zero
+func derefInt(v *int) int {
+	if v == nil {
+		return -1
+	}
+	return *v
 }
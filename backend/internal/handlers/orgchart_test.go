@@ -0,0 +1,486 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestOrgChartHandlers_PublishDraft_RecordsSnapshot(t *testing.T) {
+	repo := mocks.NewMockOrgChartRepository()
+	repo.Trees = []models.OrgTreeNode{{User: models.User{ID: 1, FirstName: "Ada"}}}
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	draft, _ := repo.CreateDraft(context.Background(), &models.CreateDraftRequest{Name: "Q3 reorg"}, admin.ID)
+	_, _ = repo.ApproveDraft(context.Background(), draft.ID, 2)
+	h := NewOrgChartHandlers(repo, mocks.NewMockUserRepository())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/orgchart/drafts/1/publish", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(ctxWithUserFrom(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), admin))
+	rr := httptest.NewRecorder()
+	h.PublishDraft(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("publish status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/orgchart/history", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), admin))
+	rr = httptest.NewRecorder()
+	h.GetOrgChartHistory(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("history status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	var history struct {
+		Data       []models.OrgChartSnapshot `json:"data"`
+		Pagination PaginationMetadata        `json:"pagination"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&history); err != nil {
+		t.Fatalf("decode history: %v", err)
+	}
+	if len(history.Data) != 1 || history.Data[0].DraftName != draft.Name || history.Data[0].Tree != nil {
+		t.Fatalf("history = %+v, want one snapshot of %q without its tree", history.Data, draft.Name)
+	}
+	if history.Data[0].PublishedByID == nil || *history.Data[0].PublishedByID != admin.ID {
+		t.Errorf("PublishedByID = %v, want %d", history.Data[0].PublishedByID, admin.ID)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/orgchart/snapshots/2", nil)
+	rctx = chi.NewRouteContext()
+	rctx.URLParams.Add("id", "2")
+	req = req.WithContext(ctxWithUserFrom(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), admin))
+	rr = httptest.NewRecorder()
+	h.GetOrgChartSnapshot(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("snapshot status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	var snapshot models.OrgChartSnapshot
+	if err := json.NewDecoder(rr.Body).Decode(&snapshot); err != nil {
+		t.Fatalf("decode snapshot: %v", err)
+	}
+	if len(snapshot.Tree) != 1 || snapshot.Tree[0].User.FirstName != "Ada" {
+		t.Errorf("Tree = %+v, want the published org", snapshot.Tree)
+	}
+}
+
+func TestOrgChartHandlers_GetOrgChartHistory(t *testing.T) {
+	repo := mocks.NewMockOrgChartRepository()
+	jan := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	repo.Snapshots[1] = &models.OrgChartSnapshot{ID: 1, DraftName: "January", CreatedAt: jan}
+	repo.Snapshots[2] = &models.OrgChartSnapshot{ID: 2, DraftName: "March", CreatedAt: jan.AddDate(0, 2, 0)}
+	h := NewOrgChartHandlers(repo, mocks.NewMockUserRepository())
+
+	tests := []struct {
+		name       string
+		user       *models.User
+		query      string
+		wantStatus int
+		wantFirst  string
+		wantTotal  int
+	}{
+		{"newest first", &models.User{ID: 1, Role: models.RoleAdmin}, "", http.StatusOK, "March", 2},
+		{"as of a past date", &models.User{ID: 1, Role: models.RoleAdmin}, "?as_of=2026-01-15", http.StatusOK, "January", 1},
+		{"bad date", &models.User{ID: 1, Role: models.RoleAdmin}, "?as_of=15/01/2026", http.StatusBadRequest, "", 0},
+		{"supervisor", &models.User{ID: 2, Role: models.RoleSupervisor}, "", http.StatusForbidden, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/orgchart/history"+tt.query, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), tt.user))
+			rr := httptest.NewRecorder()
+			h.GetOrgChartHistory(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var history struct {
+				Data       []models.OrgChartSnapshot `json:"data"`
+				Pagination PaginationMetadata        `json:"pagination"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&history); err != nil {
+				t.Fatalf("decode history: %v", err)
+			}
+			if history.Pagination.Total != tt.wantTotal || history.Data[0].DraftName != tt.wantFirst {
+				t.Errorf("got %d snapshots starting with %q, want %d starting with %q",
+					history.Pagination.Total, history.Data[0].DraftName, tt.wantTotal, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestOrgChartHandlers_PreviewDraft(t *testing.T) {
+	repo := mocks.NewMockOrgChartRepository()
+	userRepo := mocks.NewMockUserRepository()
+	supervisorID := int64(1)
+	admin := &models.User{ID: 1, FirstName: "Ada", Role: models.RoleAdmin, IsActive: true}
+	userRepo.Users[1] = admin
+	userRepo.Users[2] = &models.User{ID: 2, FirstName: "Grace", Role: models.RoleSupervisor, SupervisorID: &supervisorID, IsActive: true}
+	userRepo.Users[3] = &models.User{ID: 3, FirstName: "Alan", Role: models.RoleEmployee, SupervisorID: &supervisorID, IsActive: true}
+	userRepo.Users[4] = &models.User{ID: 4, FirstName: "Guest", Role: models.RoleGuest, IsActive: true}
+
+	draft, _ := repo.CreateDraft(context.Background(), &models.CreateDraftRequest{Name: "Reorg"}, admin.ID)
+	newSupervisor := int64(2)
+	if _, err := repo.AddOrUpdateChange(context.Background(), draft.ID, &models.AddDraftChangeRequest{UserID: 3, NewSupervisorID: &newSupervisor}, userRepo); err != nil {
+		t.Fatalf("AddOrUpdateChange: %v", err)
+	}
+	h := NewOrgChartHandlers(repo, userRepo)
+
+	preview := func(user *models.User) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/orgchart/drafts/1/preview", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "1")
+		req = req.WithContext(ctxWithUserFrom(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), user))
+		rr := httptest.NewRecorder()
+		h.PreviewDraft(rr, req)
+		return rr
+	}
+
+	if rr := preview(&models.User{ID: 2, Role: models.RoleSupervisor}); rr.Code != http.StatusForbidden {
+		t.Errorf("non-owner status = %d, want 403", rr.Code)
+	}
+
+	rr := preview(admin)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	var result models.OrgChartDraftPreview
+	if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+		t.Fatalf("decode preview: %v", err)
+	}
+	if !result.Valid || len(result.Diff) != 1 || result.Diff[0].UserID != 3 {
+		t.Errorf("preview = %+v, want a valid draft moving Alan", result)
+	}
+	if len(result.Tree) != 1 || len(result.Tree[0].Children) != 1 || len(result.Tree[0].Children[0].Children) != 1 {
+		t.Errorf("Tree = %+v, want Ada > Grace > Alan without the guest", result.Tree)
+	}
+}
+
+func TestOrgChartHandlers_PublishDraft_ReportsFailedChange(t *testing.T) {
+	repo := mocks.NewMockOrgChartRepository()
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	_, _ = repo.CreateDraft(context.Background(), &models.CreateDraftRequest{Name: "Loop"}, admin.ID)
+	changeID, userID := int64(7), int64(3)
+	repo.PublishDraftFunc = func(ctx context.Context, draftID int64, publishedByID int64) error {
+		return fmt.Errorf("publish: %w", &models.DraftPublishError{
+			ChangeID: &changeID,
+			UserID:   &userID,
+			Message:  "Reporting chain loops back on itself: Grace → Alan → Grace",
+			Problems: []models.OrgChartProblem{{Kind: models.OrgChartProblemCycle, UserIDs: []int64{2, 3}}},
+		})
+	}
+	h := NewOrgChartHandlers(repo, mocks.NewMockUserRepository())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/orgchart/drafts/1/publish", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(ctxWithUserFrom(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), admin))
+	rr := httptest.NewRecorder()
+	h.PublishDraft(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422: %s", rr.Code, rr.Body.String())
+	}
+	var body models.DraftPublishError
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.ChangeID == nil || *body.ChangeID != changeID || body.Message == "" || len(body.Problems) != 1 {
+		t.Errorf("body = %+v, want change %d with its problem", body, changeID)
+	}
+	if len(repo.Snapshots) != 0 {
+		t.Error("a failed publish recorded a snapshot")
+	}
+}
+
+func TestOrgChartHandlers_ReviewWorkflow(t *testing.T) {
+	repo := mocks.NewMockOrgChartRepository()
+	userRepo := mocks.NewMockUserRepository()
+	adminID := int64(1)
+	owner := &models.User{ID: 2, FirstName: "Grace", Role: models.RoleSupervisor, SupervisorID: &adminID, IsActive: true}
+	reviewer := &models.User{ID: 3, FirstName: "Alan", Role: models.RoleSupervisor, IsActive: true}
+	outsider := &models.User{ID: 4, FirstName: "Edsger", Role: models.RoleSupervisor, IsActive: true}
+	report := &models.User{ID: 5, FirstName: "Barbara", Role: models.RoleEmployee, SupervisorID: &owner.ID, IsActive: true}
+	for _, u := range []*models.User{owner, reviewer, outsider, report} {
+		userRepo.Users[u.ID] = u
+	}
+	draft, _ := repo.CreateDraft(context.Background(), &models.CreateDraftRequest{Name: "Reorg"}, owner.ID)
+	newDepartment := "Platform"
+	change, _ := repo.AddOrUpdateChange(context.Background(), draft.ID, &models.AddDraftChangeRequest{UserID: report.ID, NewDepartment: &newDepartment}, userRepo)
+	h := NewOrgChartHandlers(repo, userRepo)
+
+	call := func(handler http.HandlerFunc, method string, user *models.User, body string, params map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/orgchart/drafts/1", strings.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", fmt.Sprint(draft.ID))
+		for k, v := range params {
+			rctx.URLParams.Add(k, v)
+		}
+		req = req.WithContext(ctxWithUserFrom(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), user))
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+	comment := fmt.Sprintf(`{"change_id": %d, "body": "Does Barbara know?"}`, change.ID)
+
+	if rr := call(h.GetDraft, http.MethodGet, outsider, "", nil); rr.Code != http.StatusForbidden {
+		t.Errorf("outsider view status = %d, want 403", rr.Code)
+	}
+	if rr := call(h.ShareDraft, http.MethodPut, owner, `{"user_id": 5, "permission": "editor"}`, nil); rr.Code != http.StatusBadRequest {
+		t.Errorf("sharing with an employee status = %d, want 400", rr.Code)
+	}
+	if rr := call(h.ShareDraft, http.MethodPut, owner, `{"user_id": 3, "permission": "viewer"}`, nil); rr.Code != http.StatusOK {
+		t.Fatalf("share status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+
+	// Viewers can comment but not approve
+	if rr := call(h.AddDraftComment, http.MethodPost, reviewer, comment, nil); rr.Code != http.StatusCreated {
+		t.Errorf("viewer comment status = %d, want 201: %s", rr.Code, rr.Body.String())
+	}
+	if rr := call(h.ApproveDraft, http.MethodPost, reviewer, "", nil); rr.Code != http.StatusForbidden {
+		t.Errorf("viewer approve status = %d, want 403", rr.Code)
+	}
+	if rr := call(h.AddDraftComment, http.MethodPost, reviewer, `{"change_id": 999, "body": "?"}`, nil); rr.Code != http.StatusNotFound {
+		t.Errorf("comment on another draft's change status = %d, want 404", rr.Code)
+	}
+
+	// Creators can't approve their own drafts, and publishing waits for approval
+	if rr := call(h.ApproveDraft, http.MethodPost, owner, "", nil); rr.Code != http.StatusForbidden {
+		t.Errorf("self-approve status = %d, want 403", rr.Code)
+	}
+	if rr := call(h.PublishDraft, http.MethodPost, owner, "", nil); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("unapproved publish status = %d, want 422", rr.Code)
+	}
+
+	if rr := call(h.ShareDraft, http.MethodPut, owner, `{"user_id": 3, "permission": "editor"}`, nil); rr.Code != http.StatusOK {
+		t.Fatalf("upgrade share status = %d, want 200", rr.Code)
+	}
+	if rr := call(h.ApproveDraft, http.MethodPost, reviewer, "", nil); rr.Code != http.StatusOK {
+		t.Fatalf("editor approve status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+
+	// Editing the draft withdraws the approval
+	if rr := call(h.RemoveChange, http.MethodDelete, owner, "", map[string]string{"userId": "5"}); rr.Code != http.StatusNoContent {
+		t.Fatalf("remove change status = %d, want 204", rr.Code)
+	}
+	if rr := call(h.PublishDraft, http.MethodPost, owner, "", nil); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("publish after edit status = %d, want 422", rr.Code)
+	}
+	_ = call(h.ApproveDraft, http.MethodPost, reviewer, "", nil)
+	if rr := call(h.PublishDraft, http.MethodPost, owner, "", nil); rr.Code != http.StatusOK {
+		t.Errorf("approved publish status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+
+	rr := call(h.GetDraftComments, http.MethodGet, reviewer, "", nil)
+	var comments []models.DraftComment
+	if err := json.NewDecoder(rr.Body).Decode(&comments); err != nil {
+		t.Fatalf("decode comments: %v", err)
+	}
+	if len(comments) != 1 || comments[0].AuthorID != reviewer.ID {
+		t.Errorf("comments = %+v, want the reviewer's comment", comments)
+	}
+}
+
+func TestOrgChartHandlers_ExportOrgChart(t *testing.T) {
+	repo := mocks.NewMockOrgChartRepository()
+	adaID := int64(1)
+	repo.Trees = models.BuildOrgTrees([]models.User{
+		{ID: 1, FirstName: "Ada", LastName: "Lovelace"},
+		{ID: 2, FirstName: "Grace", LastName: "Hopper", SupervisorID: &adaID},
+	}, nil)
+	h := NewOrgChartHandlers(repo, mocks.NewMockUserRepository())
+
+	tests := []struct {
+		name       string
+		user       *models.User
+		query      string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{"csv by default", &models.User{ID: 9, Role: models.RoleAdmin}, "", http.StatusOK, "text/csv", "2,Grace,Hopper,,,,,,1,Ada Lovelace,1"},
+		{"dot", &models.User{ID: 9, Role: models.RoleViewer}, "?format=dot", http.StatusOK, "text/vnd.graphviz", "u1 -> u2;"},
+		{"supervisor subtree", &models.User{ID: 1, Role: models.RoleSupervisor}, "?format=json", http.StatusOK, "application/json", `"supervisor_name":"Ada Lovelace"`},
+		{"unsupported format", &models.User{ID: 9, Role: models.RoleAdmin}, "?format=png", http.StatusBadRequest, "", ""},
+		{"employee", &models.User{ID: 2, Role: models.RoleEmployee}, "", http.StatusForbidden, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/orgchart/export"+tt.query, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), tt.user))
+			rr := httptest.NewRecorder()
+			h.ExportOrgChart(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.wantType) {
+				t.Errorf("Content-Type = %s, want %s", ct, tt.wantType)
+			}
+			if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, "attachment; filename=\"org-chart-") {
+				t.Errorf("Content-Disposition = %s, want an org-chart attachment", cd)
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", rr.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestOrgChartHandlers_GetOrgTree_Scope(t *testing.T) {
+	adaID, graceID := int64(1), int64(2)
+	users := []models.User{
+		{ID: 1, FirstName: "Ada", Role: models.RoleSupervisor},
+		{ID: 5, FirstName: "Bob", Role: models.RoleSupervisor},
+		{ID: 2, FirstName: "Grace", Role: models.RoleSupervisor, SupervisorID: &adaID},
+		{ID: 4, FirstName: "Linus", Role: models.RoleEmployee, SupervisorID: &adaID},
+		{ID: 3, FirstName: "Alan", Role: models.RoleEmployee, SupervisorID: &graceID},
+	}
+	repo := mocks.NewMockOrgChartRepository()
+	repo.Trees = models.BuildOrgTrees(users, nil)
+	userRepo := mocks.NewMockUserRepository()
+	for i := range users {
+		userRepo.AddUser(&users[i])
+	}
+	h := NewOrgChartHandlers(repo, userRepo)
+	admin := &models.User{ID: 9, Role: models.RoleAdmin}
+
+	do := func(handler http.HandlerFunc, user *models.User, path string, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		ctx := ctxWithUserFrom(req.Context(), user)
+		if userID != "" {
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("userId", userID)
+			ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+		}
+		rr := httptest.NewRecorder()
+		handler(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	t.Run("tree", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			user       *models.User
+			query      string
+			wantStatus int
+			wantRoots  []int64 // Top-level user IDs; a single root is returned as an object
+			wantObject bool
+		}{
+			{name: "whole org", user: admin, wantStatus: http.StatusOK, wantRoots: []int64{1, 5}},
+			{name: "rooted", user: admin, query: "?root=2", wantStatus: http.StatusOK, wantRoots: []int64{2}, wantObject: true},
+			{name: "supervisor's own subtree", user: &users[0], wantStatus: http.StatusOK, wantRoots: []int64{1}, wantObject: true},
+			{name: "supervisor's indirect report", user: &users[0], query: "?root=3", wantStatus: http.StatusOK, wantRoots: []int64{3}, wantObject: true},
+			{name: "another supervisor's report", user: &users[1], query: "?root=2", wantStatus: http.StatusForbidden},
+			{name: "missing root", user: admin, query: "?root=99", wantStatus: http.StatusNotFound},
+			{name: "negative depth", user: admin, query: "?depth=-1", wantStatus: http.StatusBadRequest},
+			{name: "invalid include_inactive", user: admin, query: "?include_inactive=maybe", wantStatus: http.StatusBadRequest},
+			{name: "inactive users as a supervisor", user: &users[0], query: "?include_inactive=true", wantStatus: http.StatusForbidden},
+			{name: "inactive users as an admin", user: admin, query: "?include_inactive=true", wantStatus: http.StatusOK, wantRoots: []int64{1, 5}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rr := do(h.GetOrgTree, tt.user, "/api/orgchart/tree"+tt.query, "")
+				if rr.Code != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+				}
+				if tt.wantStatus != http.StatusOK {
+					return
+				}
+				var trees []models.OrgTreeNode
+				if tt.wantObject {
+					var tree models.OrgTreeNode
+					if err := json.NewDecoder(rr.Body).Decode(&tree); err != nil {
+						t.Fatal(err)
+					}
+					trees = []models.OrgTreeNode{tree}
+				} else if err := json.NewDecoder(rr.Body).Decode(&trees); err != nil {
+					t.Fatal(err)
+				}
+				var roots []int64
+				for _, tree := range trees {
+					roots = append(roots, tree.User.ID)
+				}
+				if fmt.Sprint(roots) != fmt.Sprint(tt.wantRoots) {
+					t.Errorf("roots = %v, want %v", roots, tt.wantRoots)
+				}
+			})
+		}
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		rr := do(h.GetOrgTree, admin, "/api/orgchart/tree?root=1&depth=1", "")
+		var tree models.OrgTreeNode
+		if err := json.NewDecoder(rr.Body).Decode(&tree); err != nil {
+			t.Fatal(err)
+		}
+		if len(tree.Children) != 2 || tree.ChildrenTruncated {
+			t.Fatalf("root = %+v, want two loaded children", tree)
+		}
+		for _, child := range tree.Children {
+			if len(child.Children) != 0 || child.ChildrenTruncated != (child.User.ID == 2) {
+				t.Errorf("child %d children = %d, truncated = %v; want only Grace truncated", child.User.ID, len(child.Children), child.ChildrenTruncated)
+			}
+		}
+	})
+
+	t.Run("children", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			user          *models.User
+			userID        string
+			query         string
+			wantStatus    int
+			wantChildren  []int64
+			wantTruncated bool
+		}{
+			{name: "direct reports", user: admin, userID: "1", wantStatus: http.StatusOK, wantChildren: []int64{2, 4}, wantTruncated: true},
+			{name: "with their reports", user: admin, userID: "1", query: "?depth=1", wantStatus: http.StatusOK, wantChildren: []int64{2, 4}},
+			{name: "supervisor's report", user: &users[0], userID: "2", wantStatus: http.StatusOK, wantChildren: []int64{3}},
+			{name: "no reports", user: admin, userID: "3", wantStatus: http.StatusOK, wantChildren: []int64{}},
+			{name: "another supervisor's report", user: &users[1], userID: "1", wantStatus: http.StatusForbidden},
+			{name: "employee", user: &users[3], userID: "4", wantStatus: http.StatusForbidden},
+			{name: "invalid user ID", user: admin, userID: "abc", wantStatus: http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rr := do(h.GetOrgTreeChildren, tt.user, "/api/orgchart/tree/"+tt.userID+"/children"+tt.query, tt.userID)
+				if rr.Code != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+				}
+				if tt.wantStatus != http.StatusOK {
+					return
+				}
+				var children []models.OrgTreeNode
+				if err := json.NewDecoder(rr.Body).Decode(&children); err != nil {
+					t.Fatal(err)
+				}
+				ids := []int64{}
+				truncated := false
+				for _, child := range children {
+					ids = append(ids, child.User.ID)
+					truncated = truncated || child.ChildrenTruncated
+				}
+				if fmt.Sprint(ids) != fmt.Sprint(tt.wantChildren) || truncated != tt.wantTruncated {
+					t.Errorf("children = %v (truncated %v), want %v (truncated %v)", ids, truncated, tt.wantChildren, tt.wantTruncated)
+				}
+			})
+		}
+	})
+}
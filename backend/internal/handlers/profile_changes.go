@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/sanitize"
+)
+
+// WithProfileChanges routes the title and department changes users make to themselves, unless
+// they can update everyone, into a queue for their supervisor instead of applying them
+func (h *Handlers) WithProfileChanges(profileChanges repository.ProfileChangeRepository) *Handlers {
+	h.profileChanges = profileChanges
+	return h
+}
+
+// needsProfileChangeReview reports whether the current user's update to the target user asks for
+// title and department changes rather than making them
+func (h *Handlers) needsProfileChangeReview(currentUser, targetUser *models.User) bool {
+	return h.profileChanges != nil && currentUser.ID == targetUser.ID && !authz.CanAll(currentUser, authz.ActionUserUpdate)
+}
+
+// proposeProfileChange takes the title and department changes out of the update and files them for
+// review. It returns the pending request, or nil if the update doesn't change either.
+func (h *Handlers) proposeProfileChange(ctx context.Context, user *models.User, req *models.UpdateUserRequest) (*models.ProfileChangeRequest, error) {
+	title, department := req.Title, req.Department
+	req.Title, req.Department = nil, nil
+	if title != nil && *title == user.Title {
+		title = nil
+	}
+	if department != nil {
+		sanitized := sanitize.Name(*department, models.MaxDepartmentLength)
+		department = &sanitized
+		if sanitized == user.Department {
+			department = nil
+		}
+	}
+	if title == nil && department == nil {
+		return nil, nil
+	}
+
+	change, err := h.profileChanges.Propose(ctx, user.ID, title, department)
+	if err != nil {
+		return nil, err
+	}
+	h.notifications.NotifyProfileChangeRequested(ctx, change, user)
+	h.logger.Info("Profile change requested", "profile_change_id", change.ID, "user_id", user.ID)
+	return change, nil
+}
+
+// GetMyProfileChange godoc
+// @Summary Get my profile change request
+// @Description Returns the current user's most recent title or department change request, so they can see whether it's been reviewed
+// @Tags Profile Changes
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ProfileChangeRequest "Profile change request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "No profile change request"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /me/profile-change [get]
+func (h *Handlers) GetMyProfileChange(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	change, err := h.profileChanges.GetLatestByUser(r.Context(), currentUser.ID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get profile change", err, "user_id", currentUser.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch profile change")
+		return
+	}
+	if change == nil {
+		respondError(w, http.StatusNotFound, "Profile change request not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, change)
+}
+
+// ListProfileChanges godoc
+// @Summary List profile change requests
+// @Description Returns the title and department changes users asked for, oldest first, with each user's current title and department. Admins see everyone's; supervisors see their direct reports'.
+// @Tags Profile Changes
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Only requests with this status" Enums(pending, approved, rejected)
+// @Success 200 {array} models.ProfileChangeRequest "Profile change requests"
+// @Failure 400 {object} map[string]interface{} "Invalid status"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /profile-changes [get]
+func (h *Handlers) ListProfileChanges(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+	if !authz.CanForOthers(currentUser, authz.ActionUserUpdate) {
+		respondError(w, http.StatusForbidden, "Forbidden: only supervisors and admins review profile changes")
+		return
+	}
+
+	var status *models.ProfileChangeStatus
+	if v := r.URL.Query().Get("status"); v != "" {
+		s := models.ProfileChangeStatus(v)
+		if !models.ValidProfileChangeStatuses[s] {
+			respondError(w, http.StatusBadRequest, "status must be 'pending', 'approved', or 'rejected'")
+			return
+		}
+		status = &s
+	}
+	var supervisorID *int64
+	if !authz.CanAll(currentUser, authz.ActionUserUpdate) {
+		supervisorID = &currentUser.ID
+	}
+
+	changes, err := h.profileChanges.List(r.Context(), status, supervisorID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list profile changes", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch profile changes")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, changes)
+}
+
+// ApproveProfileChange godoc
+// @Summary Approve a profile change request
+// @Description Applies the title and department change to the user and records it in their history. Supervisors can approve their direct reports' requests; admins anyone's but their own.
+// @Tags Profile Changes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Profile change request ID"
+// @Success 200 {object} models.UserResponse "Updated user"
+// @Failure 400 {object} map[string]interface{} "Invalid profile change request ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Profile change request not found"
+// @Failure 409 {object} map[string]interface{} "Already reviewed"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /profile-changes/{id}/approve [post]
+func (h *Handlers) ApproveProfileChange(w http.ResponseWriter, r *http.Request) {
+	currentUser, change := h.getReviewableProfileChange(w, r)
+	if change == nil {
+		return
+	}
+
+	user, err := h.profileChanges.Approve(r.Context(), change.ID, currentUser.ID)
+	if err != nil {
+		respondRepositoryError(w, r, err, "Profile change request")
+		return
+	}
+	change.Status = models.ProfileChangeStatusApproved
+	h.notifications.NotifyProfileChangeReviewed(r.Context(), change, currentUser)
+	h.InvalidateUserCache(r.Context())
+
+	h.logger.Audit(r.Context(), logger.AuditEvent{
+		Action:     logger.AuditActionUpdate,
+		Resource:   "profile_change_approved",
+		ResourceID: fmt.Sprintf("%d", user.ID),
+		ActorID:    currentUser.ID,
+		ActorEmail: currentUser.Email,
+		Result:     logger.AuditResultSuccess,
+		Details: map[string]any{
+			"profile_change_id": change.ID,
+			"title":             user.Title,
+			"department":        user.Department,
+		},
+	})
+
+	respondJSON(w, http.StatusOK, user.ToUserResponse())
+}
+
+// RejectProfileChange godoc
+// @Summary Reject a profile change request
+// @Description Turns down a title and department change. The user can ask again. Supervisors can reject their direct reports' requests; admins anyone's but their own.
+// @Tags Profile Changes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Profile change request ID"
+// @Success 200 {object} models.ProfileChangeRequest "Rejected profile change request"
+// @Failure 400 {object} map[string]interface{} "Invalid profile change request ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Profile change request not found"
+// @Failure 409 {object} map[string]interface{} "Already reviewed"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /profile-changes/{id}/reject [post]
+func (h *Handlers) RejectProfileChange(w http.ResponseWriter, r *http.Request) {
+	currentUser, change := h.getReviewableProfileChange(w, r)
+	if change == nil {
+		return
+	}
+
+	rejected, err := h.profileChanges.Reject(r.Context(), change.ID, currentUser.ID)
+	if err != nil {
+		respondRepositoryError(w, r, err, "Profile change request")
+		return
+	}
+	h.notifications.NotifyProfileChangeReviewed(r.Context(), rejected, currentUser)
+
+	h.logger.Info("Profile change rejected", "profile_change_id", rejected.ID, "rejected_by", currentUser.ID)
+	respondJSON(w, http.StatusOK, rejected)
+}
+
+// getReviewableProfileChange loads the profile change request in the URL, writing a 404 if it
+// doesn't exist, a 409 if it has already been reviewed, and a 403 unless the current user can
+// update the user who asked and isn't them
+func (h *Handlers) getReviewableProfileChange(w http.ResponseWriter, r *http.Request) (*models.User, *models.ProfileChangeRequest) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return nil, nil
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid profile change request ID")
+		return nil, nil
+	}
+	change, err := h.profileChanges.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get profile change", err, "profile_change_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch profile change")
+		return nil, nil
+	}
+	if change == nil {
+		respondError(w, http.StatusNotFound, "Profile change request not found")
+		return nil, nil
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), change.UserID)
+	if err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "Profile change request not found")
+		return nil, nil
+	}
+	if user.ID == currentUser.ID || !authz.Can(currentUser, authz.ActionUserUpdate, authz.UserResource(user)) {
+		respondError(w, http.StatusForbidden, "Forbidden: only the user's supervisor or an admin can review their profile changes")
+		return nil, nil
+	}
+	if change.Status != models.ProfileChangeStatusPending {
+		respondError(w, http.StatusConflict, "Profile change request has already been reviewed")
+		return nil, nil
+	}
+	return currentUser, change
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestProfileChanges(t *testing.T) {
+	supervisorID := int64(2)
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, Role: models.RoleSupervisor, FirstName: "Sam", LastName: "Lee", IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, Role: models.RoleEmployee, SupervisorID: &supervisorID, Title: "Engineer", Department: "Engineering", IsActive: true})
+	userRepo.AddUser(&models.User{ID: 4, Role: models.RoleEmployee, IsActive: true})
+	profileChangeRepo := mocks.NewMockProfileChangeRepository(userRepo)
+	notificationRepo := mocks.NewMockNotificationRepository()
+	h := New(userRepo, mocks.NewMockSquadRepository(), nil).
+		WithProfileChanges(profileChangeRepo).
+		WithNotifications(services.NewNotificationService(notificationRepo, userRepo, nil))
+
+	do := func(handler http.HandlerFunc, method string, currentUserID int64, path, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		ctx := ctxWithUserFrom(req.Context(), userRepo.Users[currentUserID])
+		if id != "" {
+			ctx = chiCtxWithID(ctx, "id", id)
+		}
+		rr := httptest.NewRecorder()
+		handler(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	t.Run("self update", func(t *testing.T) {
+		rr := do(h.UpdateUser, http.MethodPut, 3, "/api/users/3", "3",
+			`{"title":"Senior Engineer","pronouns":"she/her","phone":"+1 555 0100","emergency_contact":{"name":"Max","phone":"555-0101","relationship":"Partner"}}`)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("UpdateUser() status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+		var resp models.UserResponse
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Title != "Engineer" || resp.Pronouns != "she/her" || resp.Phone != "+1 555 0100" {
+			t.Errorf("UpdateUser() title = %q, pronouns = %q, phone = %q, want title unchanged and contact details applied", resp.Title, resp.Pronouns, resp.Phone)
+		}
+		if resp.EmergencyContact == nil || resp.EmergencyContact.Name != "Max" {
+			t.Errorf("UpdateUser() emergency_contact = %+v, want Max", resp.EmergencyContact)
+		}
+		if resp.PendingProfileChange == nil || *resp.PendingProfileChange.Title != "Senior Engineer" || resp.PendingProfileChange.Department != nil {
+			t.Fatalf("UpdateUser() pending_profile_change = %+v, want title Senior Engineer", resp.PendingProfileChange)
+		}
+		if len(notificationRepo.Notifications) != 1 || notificationRepo.Notifications[0].UserID != supervisorID {
+			t.Errorf("notifications = %+v, want one to the supervisor", notificationRepo.Notifications)
+		}
+
+		// Resubmitting the current department doesn't ask for anything
+		rr = do(h.UpdateUser, http.MethodPut, 4, "/api/users/4", "4", `{"department":""}`)
+		if rr.Code != http.StatusOK || strings.Contains(rr.Body.String(), "pending_profile_change") {
+			t.Errorf("UpdateUser() unchanged department = %d %s, want 200 without a pending change", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("emergency contact visibility", func(t *testing.T) {
+		for _, tt := range []struct {
+			name          string
+			currentUserID int64
+			want          bool
+		}{
+			{"supervisor", 2, true},
+			{"admin", 1, true},
+			{"peer", 4, false},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				rr := do(h.GetUserByID, http.MethodGet, tt.currentUserID, "/api/users/3", "3", "")
+				if got := strings.Contains(rr.Body.String(), "emergency_contact"); got != tt.want {
+					t.Errorf("GetUserByID() includes emergency_contact = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		for _, tt := range []struct {
+			name          string
+			currentUserID int64
+			query         string
+			wantStatus    int
+			wantCount     int
+		}{
+			{"admin", 1, "?status=pending", http.StatusOK, 1},
+			{"supervisor", 2, "", http.StatusOK, 1},
+			{"employee", 3, "", http.StatusForbidden, 0},
+			{"invalid status", 1, "?status=maybe", http.StatusBadRequest, 0},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				rr := do(h.ListProfileChanges, http.MethodGet, tt.currentUserID, "/api/profile-changes"+tt.query, "", "")
+				if rr.Code != tt.wantStatus {
+					t.Fatalf("ListProfileChanges() status = %d, want %d", rr.Code, tt.wantStatus)
+				}
+				if rr.Code != http.StatusOK {
+					return
+				}
+				var changes []models.ProfileChangeRequest
+				if err := json.NewDecoder(rr.Body).Decode(&changes); err != nil {
+					t.Fatal(err)
+				}
+				if len(changes) != tt.wantCount {
+					t.Errorf("ListProfileChanges() returned %d, want %d", len(changes), tt.wantCount)
+				}
+			})
+		}
+
+		// Supervisors only see their direct reports' requests
+		profileChangeRepo.Propose(t.Context(), 4, nil, nil)
+		rr := do(h.ListProfileChanges, http.MethodGet, 2, "/api/profile-changes", "", "")
+		var changes []models.ProfileChangeRequest
+		if err := json.NewDecoder(rr.Body).Decode(&changes); err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != 1 || changes[0].UserID != 3 {
+			t.Errorf("ListProfileChanges() for supervisor = %+v, want only user 3's", changes)
+		}
+	})
+
+	t.Run("review", func(t *testing.T) {
+		for _, tt := range []struct {
+			name          string
+			currentUserID int64
+			id            string
+			want          int
+		}{
+			{"by the requester", 3, "1", http.StatusForbidden},
+			{"by another employee", 4, "1", http.StatusForbidden},
+			{"missing", 2, "99", http.StatusNotFound},
+			{"invalid ID", 2, "abc", http.StatusBadRequest},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				if rr := do(h.ApproveProfileChange, http.MethodPost, tt.currentUserID, "/api/profile-changes/"+tt.id+"/approve", tt.id, ""); rr.Code != tt.want {
+					t.Errorf("ApproveProfileChange() status = %d, want %d", rr.Code, tt.want)
+				}
+			})
+		}
+
+		rr := do(h.ApproveProfileChange, http.MethodPost, 2, "/api/profile-changes/1/approve", "1", "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("ApproveProfileChange() status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+		if got := userRepo.Users[3].Title; got != "Senior Engineer" {
+			t.Errorf("title after approval = %q, want Senior Engineer", got)
+		}
+		if rr := do(h.RejectProfileChange, http.MethodPost, 2, "/api/profile-changes/1/reject", "1", ""); rr.Code != http.StatusConflict {
+			t.Errorf("RejectProfileChange() after approval status = %d, want %d", rr.Code, http.StatusConflict)
+		}
+
+		rr = do(h.GetMyProfileChange, http.MethodGet, 3, "/api/me/profile-change", "", "")
+		var change models.ProfileChangeRequest
+		if err := json.NewDecoder(rr.Body).Decode(&change); err != nil {
+			t.Fatal(err)
+		}
+		if change.Status != models.ProfileChangeStatusApproved {
+			t.Errorf("GetMyProfileChange() status = %q, want approved", change.Status)
+		}
+
+		do(h.UpdateUser, http.MethodPut, 3, "/api/users/3", "3", `{"department":"Platform"}`)
+		rr = do(h.RejectProfileChange, http.MethodPost, 1, "/api/profile-changes/3/reject", "3", "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("RejectProfileChange() status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+		if got := userRepo.Users[3].Department; got != "Engineering" {
+			t.Errorf("department after rejection = %q, want Engineering", got)
+		}
+	})
+
+	t.Run("no request", func(t *testing.T) {
+		if rr := do(h.GetMyProfileChange, http.MethodGet, 1, "/api/me/profile-change", "", ""); rr.Code != http.StatusNotFound {
+			t.Errorf("GetMyProfileChange() status = %d, want %d", rr.Code, http.StatusNotFound)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// ReportHandlers serve the team analytics reports
+type ReportHandlers struct {
+	reports *services.ReportService
+	logger  *logger.Logger
+}
+
+// NewReportHandlers creates a new report handlers instance
+func NewReportHandlers(reports *services.ReportService) *ReportHandlers {
+	return &ReportHandlers{
+		reports: reports,
+		logger:  logger.Default().WithComponent("report-handlers"),
+	}
+}
+
+// GetReport godoc
+// @Summary Get a team analytics report
+// @Description Returns a report over whole days (UTC) as JSON or a CSV download. Requires report:view: admins report on the whole organization, supervisors on themselves and their direct reports.
+// @Tags Reports
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param report path string true "Report" Enums(time-off-usage, approval-latency, meeting-load, jira-throughput, headcount, inactive-accounts)
+// @Param from query string false "First day (YYYY-MM-DD), defaults to a year before to"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to today"
+// @Param format query string false "Output format" Enums(json, csv) default(json)
+// @Param days query int false "Days unseen before an account is inactive, for inactive-accounts" default(30)
+// @Success 200 {object} models.Report "Report"
+// @Failure 400 {object} map[string]interface{} "Invalid dates or format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Unknown report"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /reports/{report} [get]
+func (h *ReportHandlers) GetReport(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionReportView)
+	if currentUser == nil {
+		return
+	}
+
+	format := models.ReportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = models.ReportFormatJSON
+	}
+	if format != models.ReportFormatJSON && format != models.ReportFormatCSV {
+		respondError(w, http.StatusBadRequest, "format must be 'json' or 'csv'")
+		return
+	}
+
+	q, err := models.ParseReportQuery(r.URL.Query().Get("from"), r.URL.Query().Get("to"), time.Now())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := q.SetInactiveDays(r.URL.Query().Get("days")); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !authz.CanAll(currentUser, authz.ActionReportView) {
+		q.TeamOf = &currentUser.ID
+	}
+
+	kind := models.ReportKind(chi.URLParam(r, "report"))
+	report, table, err := h.reports.Run(r.Context(), kind, q)
+	if errors.Is(err, services.ErrUnknownReport) {
+		respondError(w, http.StatusNotFound, "Report not found")
+		return
+	}
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to run report", err, "report", kind)
+		respondError(w, http.StatusInternalServerError, "Failed to run report")
+		return
+	}
+
+	if format == models.ReportFormatJSON {
+		respondJSON(w, http.StatusOK, report)
+		return
+	}
+	data, err := services.EncodeReportCSV(table)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to encode report")
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s-to-%s.csv"`, kind, report.From, report.To))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
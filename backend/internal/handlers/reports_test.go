@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestReportHandlers_GetReport(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}
+	supervisor := &models.User{ID: 2, Role: models.RoleSupervisor, OrgID: 1}
+	employee := &models.User{ID: 3, Role: models.RoleEmployee, OrgID: 1}
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		report         string
+		query          string
+		expectedStatus int
+		expectedTeamOf *int64
+	}{
+		{"admin reports on the organization", admin, "headcount", "", http.StatusOK, nil},
+		{"supervisor reports on their team", supervisor, "meeting-load", "?from=2026-01-01&to=2026-01-31", http.StatusOK, &supervisor.ID},
+		{"employee is forbidden", employee, "headcount", "", http.StatusForbidden, nil},
+		{"unknown report", admin, "burndown", "", http.StatusNotFound, nil},
+		{"invalid dates", admin, "headcount", "?from=2026-02-01&to=2026-01-01", http.StatusBadRequest, nil},
+		{"invalid format", admin, "headcount", "?format=xlsx", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockReportRepository()
+			h := NewReportHandlers(services.NewReportService(repo))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/reports/"+tt.report+tt.query, nil)
+			req = req.WithContext(chiCtxWithParams(ctxWithUser(tt.currentUser), map[string]string{"report": tt.report}))
+			rr := httptest.NewRecorder()
+			h.GetReport(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if len(repo.Queries) != 1 {
+				t.Fatalf("ran %d queries, want 1", len(repo.Queries))
+			}
+			teamOf := repo.Queries[0].TeamOf
+			if (teamOf == nil) != (tt.expectedTeamOf == nil) || (teamOf != nil && *teamOf != *tt.expectedTeamOf) {
+				t.Errorf("TeamOf = %v, want %v", teamOf, tt.expectedTeamOf)
+			}
+			var report models.Report
+			if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if string(report.Report) != tt.report {
+				t.Errorf("report = %q, want %q", report.Report, tt.report)
+			}
+		})
+	}
+}
+
+func TestReportHandlers_GetReportCSV(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin, OrgID: 1}
+	repo := mocks.NewMockReportRepository()
+	repo.HeadcountRows = []models.HeadcountRow{{Month: "2026-01", Hires: 2, Departures: 1, Headcount: 40}}
+	h := NewReportHandlers(services.NewReportService(repo))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/reports/headcount?from=2026-01-01&to=2026-01-31&format=csv", nil)
+	req = req.WithContext(chiCtxWithParams(ctxWithUser(admin), map[string]string{"report": "headcount"}))
+	rr := httptest.NewRecorder()
+	h.GetReport(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Content-Type = %q, want text/csv", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, `filename="headcount-2026-01-01-to-2026-01-31.csv"`) {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	if want := "month,hires,departures,headcount\n2026-01,2,1,40\n"; rr.Body.String() != want {
+		t.Errorf("body = %q, want %q", rr.Body.String(), want)
+	}
+}
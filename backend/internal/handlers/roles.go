@@ -0,0 +1,416 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// RoleHandlers manages custom roles, their assignment to users, and the permissions catalog
+type RoleHandlers struct {
+	roleRepo repository.CustomRoleRepository
+	userRepo repository.UserRepository
+	logger   *logger.Logger
+}
+
+// NewRoleHandlers creates a new role handlers instance
+func NewRoleHandlers(roleRepo repository.CustomRoleRepository, userRepo repository.UserRepository) *RoleHandlers {
+	return &RoleHandlers{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+		logger:   logger.Default().WithComponent("role-handlers"),
+	}
+}
+
+// PermissionCatalog lists what custom roles can be built from and what the built-in roles grant
+type PermissionCatalog struct {
+	Actions    []authz.Action                     `json:"actions"`
+	Scopes     []authz.Scope                      `json:"scopes"`
+	RoleGrants map[models.Role][]authz.Permission `json:"role_grants"`
+}
+
+// UserPermissionsResponse is a user's roles and the permissions they add up to
+type UserPermissionsResponse struct {
+	Role        models.Role         `json:"role"`
+	CustomRoles []models.CustomRole `json:"custom_roles"`
+	Permissions []authz.Permission  `json:"permissions"`
+}
+
+// validateRolePermissions rejects custom role permissions with unknown actions or scopes
+func validateRolePermissions(w http.ResponseWriter, req *models.CustomRoleRequest) bool {
+	for _, permission := range req.Permissions {
+		if err := authz.ValidatePermission(permission); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return false
+		}
+	}
+	return true
+}
+
+// GetPermissionCatalog godoc
+// @Summary List permissions
+// @Description Returns every action and scope custom roles can grant, and the permissions each built-in role grants
+// @Tags Roles
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} PermissionCatalog "Permission catalog"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /permissions [get]
+func (h *RoleHandlers) GetPermissionCatalog(w http.ResponseWriter, r *http.Request) {
+	if requireAuth(w, r) == nil {
+		return
+	}
+
+	grants := make(map[models.Role][]authz.Permission)
+	for _, role := range []models.Role{models.RoleAdmin, models.RoleSupervisor, models.RoleEmployee, models.RoleViewer, models.RoleGuest} {
+		grants[role] = authz.RolePermissions(role)
+	}
+
+	respondJSON(w, http.StatusOK, PermissionCatalog{
+		Actions:    authz.Actions,
+		Scopes:     authz.Scopes,
+		RoleGrants: grants,
+	})
+}
+
+// GetMyPermissions godoc
+// @Summary Get my permissions
+// @Description Returns the current user's built-in role, custom roles, and the permissions they add up to
+// @Tags Roles
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} UserPermissionsResponse "Current user's permissions"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /me/permissions [get]
+func (h *RoleHandlers) GetMyPermissions(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	customRoles := currentUser.CustomRoles
+	if customRoles == nil {
+		customRoles = []models.CustomRole{}
+	}
+
+	respondJSON(w, http.StatusOK, UserPermissionsResponse{
+		Role:        currentUser.Role,
+		CustomRoles: customRoles,
+		Permissions: middleware.GetPermissionsFromContext(r.Context()),
+	})
+}
+
+// GetRoles godoc
+// @Summary List custom roles
+// @Description Returns every custom role with its permissions and how many users hold it. Admin only.
+// @Tags Roles
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.CustomRole "Custom roles"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /roles [get]
+func (h *RoleHandlers) GetRoles(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionRoleManage) == nil {
+		return
+	}
+
+	roles, err := h.roleRepo.GetAll(r.Context())
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get custom roles", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch roles")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, roles)
+}
+
+// CreateRole godoc
+// @Summary Create a custom role
+// @Description Defines a role, such as "HR", composed of permissions that apply on top of a user's built-in role. Admin only.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CustomRoleRequest true "Role"
+// @Success 201 {object} models.CustomRole "Created role"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 409 {object} map[string]interface{} "Role already exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /roles [post]
+func (h *RoleHandlers) CreateRole(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionRoleManage)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.CustomRoleRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) || !validateRolePermissions(w, &req) {
+		return
+	}
+
+	existing, err := h.roleRepo.GetByName(r.Context(), req.Name)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to check role name", err, "name", req.Name)
+		respondError(w, http.StatusInternalServerError, "Failed to create role")
+		return
+	}
+	if existing != nil {
+		respondError(w, http.StatusConflict, "A role with this name already exists")
+		return
+	}
+
+	role, err := h.roleRepo.Create(r.Context(), &req, currentUser.ID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to create custom role", err, "name", req.Name)
+		respondError(w, http.StatusInternalServerError, "Failed to create role")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, role)
+}
+
+// UpdateRole godoc
+// @Summary Update a custom role
+// @Description Replaces a custom role's name, description, and permissions for everyone who holds it. Admin only.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Param body body models.CustomRoleRequest true "Role"
+// @Success 200 {object} models.CustomRole "Updated role"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 409 {object} map[string]interface{} "Another role has this name"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /roles/{id} [put]
+func (h *RoleHandlers) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionRoleManage) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid role ID")
+		return
+	}
+
+	var req models.CustomRoleRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) || !validateRolePermissions(w, &req) {
+		return
+	}
+
+	existing, err := h.roleRepo.GetByName(r.Context(), req.Name)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to check role name", err, "name", req.Name)
+		respondError(w, http.StatusInternalServerError, "Failed to update role")
+		return
+	}
+	if existing != nil && existing.ID != id {
+		respondError(w, http.StatusConflict, "A role with this name already exists")
+		return
+	}
+
+	before, err := h.roleRepo.GetByID(r.Context(), id)
+	if err == nil && before != nil {
+		middleware.SetAuditBefore(r.Context(), before)
+	}
+
+	role, err := h.roleRepo.Update(r.Context(), id, &req)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to update custom role", err, "role_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to update role")
+		return
+	}
+	if role == nil {
+		respondError(w, http.StatusNotFound, "Role not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, role)
+}
+
+// DeleteRole godoc
+// @Summary Delete a custom role
+// @Description Deletes a custom role and removes it from everyone who holds it. Admin only.
+// @Tags Roles
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /roles/{id} [delete]
+func (h *RoleHandlers) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionRoleManage) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid role ID")
+		return
+	}
+
+	role, err := h.roleRepo.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get custom role", err, "role_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to delete role")
+		return
+	}
+	if role == nil {
+		respondError(w, http.StatusNotFound, "Role not found")
+		return
+	}
+	middleware.SetAuditBefore(r.Context(), role)
+
+	if err := h.roleRepo.Delete(r.Context(), id); err != nil {
+		h.logger.LogError(r.Context(), "Failed to delete custom role", err, "role_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to delete role")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Role deleted"})
+}
+
+// GetUserRoles godoc
+// @Summary Get a user's roles
+// @Description Returns a user's built-in role, custom roles, and the permissions they add up to. Users can see their own; seeing anyone else's is admin only.
+// @Tags Roles
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} UserPermissionsResponse "User's permissions"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/roles [get]
+func (h *RoleHandlers) GetUserRoles(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	if userID != currentUser.ID && requirePermission(w, r, authz.ActionRoleManage) == nil {
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	customRoles, err := h.roleRepo.GetForUser(r.Context(), userID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get user's custom roles", err, "user_id", userID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch roles")
+		return
+	}
+	user.CustomRoles = customRoles
+
+	respondJSON(w, http.StatusOK, UserPermissionsResponse{
+		Role:        user.Role,
+		CustomRoles: customRoles,
+		Permissions: authz.Permissions(user),
+	})
+}
+
+// SetUserRoles godoc
+// @Summary Assign custom roles to a user
+// @Description Replaces the custom roles a user holds. An empty list removes them all. Admin only.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param body body models.AssignCustomRolesRequest true "Role IDs"
+// @Success 200 {object} UserPermissionsResponse "User's updated permissions"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/roles [put]
+func (h *RoleHandlers) SetUserRoles(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionRoleManage)
+	if currentUser == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req models.AssignCustomRolesRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	for _, roleID := range req.RoleIDs {
+		role, err := h.roleRepo.GetByID(r.Context(), roleID)
+		if err != nil {
+			h.logger.LogError(r.Context(), "Failed to get custom role", err, "role_id", roleID)
+			respondError(w, http.StatusInternalServerError, "Failed to assign roles")
+			return
+		}
+		if role == nil {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("Role %d not found", roleID))
+			return
+		}
+	}
+
+	before, err := h.roleRepo.GetForUser(r.Context(), userID)
+	if err == nil {
+		middleware.SetAuditBefore(r.Context(), before)
+	}
+
+	if err := h.roleRepo.SetForUser(r.Context(), userID, req.RoleIDs, currentUser.ID); err != nil {
+		h.logger.LogError(r.Context(), "Failed to assign custom roles", err, "user_id", userID)
+		respondError(w, http.StatusInternalServerError, "Failed to assign roles")
+		return
+	}
+
+	customRoles, err := h.roleRepo.GetForUser(r.Context(), userID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get user's custom roles", err, "user_id", userID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch roles")
+		return
+	}
+	user.CustomRoles = customRoles
+
+	respondJSON(w, http.StatusOK, UserPermissionsResponse{
+		Role:        user.Role,
+		CustomRoles: customRoles,
+		Permissions: authz.Permissions(user),
+	})
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+// newRoleTestHandlers sets up an admin (1) and an employee (2), with an "HR" custom role that
+// lets its holders deactivate anyone
+func newRoleTestHandlers() (*RoleHandlers, *mocks.MockCustomRoleRepository, *mocks.MockUserRepository) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, FirstName: "Ada", LastName: "Admin", Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, FirstName: "Hal", LastName: "Human", Role: models.RoleEmployee, IsActive: true})
+
+	roleRepo := mocks.NewMockCustomRoleRepository()
+	_, _ = roleRepo.Create(context.Background(), &models.CustomRoleRequest{
+		Name:        "HR",
+		Permissions: []models.RolePermission{{Action: string(authz.ActionUserDeactivate), Scope: string(authz.ScopeAll)}},
+	}, 1)
+
+	return NewRoleHandlers(roleRepo, userRepo), roleRepo, userRepo
+}
+
+func TestRoleHandlers_CreateRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		role           models.Role
+		body           string
+		expectedStatus int
+	}{
+		{"admin creates role", models.RoleAdmin, `{"name":"Read-only Exec","permissions":[{"action":"org:view","scope":"all"}]}`, http.StatusCreated},
+		{"supervisor forbidden", models.RoleSupervisor, `{"name":"Exec","permissions":[{"action":"org:view","scope":"all"}]}`, http.StatusForbidden},
+		{"duplicate name ignoring case", models.RoleAdmin, `{"name":"hr","permissions":[{"action":"org:view","scope":"all"}]}`, http.StatusConflict},
+		{"no permissions", models.RoleAdmin, `{"name":"Empty","permissions":[]}`, http.StatusBadRequest},
+		{"unknown action", models.RoleAdmin, `{"name":"Exec","permissions":[{"action":"org:rule","scope":"all"}]}`, http.StatusBadRequest},
+		{"unknown scope", models.RoleAdmin, `{"name":"Exec","permissions":[{"action":"org:view","scope":"galaxy"}]}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _ := newRoleTestHandlers()
+			req := httptest.NewRequest(http.MethodPost, "/api/roles", strings.NewReader(tt.body))
+			req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 9, Role: tt.role}))
+			rr := httptest.NewRecorder()
+			h.CreateRole(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestRoleHandlers_SetUserRoles(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUserID  int64
+		targetID       string
+		body           string
+		expectedStatus int
+	}{
+		{"admin assigns role", 1, "2", `{"role_ids":[1]}`, http.StatusOK},
+		{"admin clears roles", 1, "2", `{"role_ids":[]}`, http.StatusOK},
+		{"employee assigns own role", 2, "2", `{"role_ids":[1]}`, http.StatusForbidden},
+		{"unknown role", 1, "2", `{"role_ids":[99]}`, http.StatusBadRequest},
+		{"missing user", 1, "99", `{"role_ids":[1]}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, userRepo := newRoleTestHandlers()
+			req := httptest.NewRequest(http.MethodPut, "/api/users/"+tt.targetID+"/roles", strings.NewReader(tt.body))
+			ctx := chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[tt.currentUserID]), "id", tt.targetID)
+			rr := httptest.NewRecorder()
+			h.SetUserRoles(rr, req.WithContext(ctx))
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestRoleHandlers_GetUserRoles(t *testing.T) {
+	h, roleRepo, userRepo := newRoleTestHandlers()
+	roleRepo.Assignments[2] = []int64{1}
+
+	get := func(currentUserID int64, targetID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/users/"+targetID+"/roles", nil)
+		ctx := chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[currentUserID]), "id", targetID)
+		rr := httptest.NewRecorder()
+		h.GetUserRoles(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	if rr := get(2, "1"); rr.Code != http.StatusForbidden {
+		t.Errorf("employee viewing admin's roles: status = %d, want %d", rr.Code, http.StatusForbidden)
+	}
+
+	rr := get(2, "2")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp UserPermissionsResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.CustomRoles) != 1 || resp.CustomRoles[0].Name != "HR" {
+		t.Errorf("custom roles = %v, want HR", resp.CustomRoles)
+	}
+	want := authz.Permission{Action: authz.ActionUserDeactivate, Scope: authz.ScopeAll}
+	found := false
+	for _, permission := range resp.Permissions {
+		found = found || permission == want
+	}
+	if !found {
+		t.Errorf("permissions %v are missing %v from the HR role", resp.Permissions, want)
+	}
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// SavedViewHandlers handles users' saved list filters. Views are private to the user who saved them.
+type SavedViewHandlers struct {
+	viewRepo repository.SavedViewRepository
+	logger   *logger.Logger
+}
+
+// NewSavedViewHandlers creates a new saved view handlers instance
+func NewSavedViewHandlers(viewRepo repository.SavedViewRepository) *SavedViewHandlers {
+	return &SavedViewHandlers{
+		viewRepo: viewRepo,
+		logger:   logger.Default().WithComponent("saved-view-handlers"),
+	}
+}
+
+// WithSavedViews applies the current user's saved views to the team member search (?view=)
+func (h *Handlers) WithSavedViews(viewRepo repository.SavedViewRepository) *Handlers {
+	h.savedViews = viewRepo
+	return h
+}
+
+// WithSavedViews applies the current user's saved views to the task list (?view=)
+func (h *CalendarHandlers) WithSavedViews(viewRepo repository.SavedViewRepository) *CalendarHandlers {
+	h.savedViews = viewRepo
+	return h
+}
+
+// WithSavedViews applies the current user's saved views to the time off list (?view=)
+func (h *TimeOffHandlers) WithSavedViews(viewRepo repository.SavedViewRepository) *TimeOffHandlers {
+	h.savedViews = viewRepo
+	return h
+}
+
+// ListViews godoc
+// @Summary List my saved views
+// @Description Returns the current user's saved views, optionally only those for one list
+// @Tags Saved Views
+// @Produce json
+// @Security BearerAuth
+// @Param resource query string false "List the views filter" Enums(tasks, time_off, team_members)
+// @Success 200 {array} models.SavedView "Saved views"
+// @Failure 400 {object} map[string]interface{} "Invalid resource"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /saved-views [get]
+func (h *SavedViewHandlers) ListViews(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	var resource *models.SavedViewResource
+	if v := r.URL.Query().Get("resource"); v != "" {
+		res := models.SavedViewResource(v)
+		if _, ok := models.SavedViewFilterKeys[res]; !ok {
+			respondError(w, http.StatusBadRequest, "resource must be 'tasks', 'time_off', or 'team_members'")
+			return
+		}
+		resource = &res
+	}
+
+	views, err := h.viewRepo.ListByUser(r.Context(), currentUser.ID, resource)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list saved views", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch saved views")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, views)
+}
+
+// CreateView godoc
+// @Summary Save a view
+// @Description Saves named filters for a list. Filters are the list's query parameters and are checked the same way the list checks them.
+// @Tags Saved Views
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SavedViewRequest true "View"
+// @Success 201 {object} models.SavedView "Created view"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "A view with that name already exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /saved-views [post]
+func (h *SavedViewHandlers) CreateView(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.SavedViewRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) || !checkSavedViewFilters(w, &req) {
+		return
+	}
+
+	view, err := h.viewRepo.Create(r.Context(), currentUser.ID, &req)
+	if err != nil {
+		respondRepositoryError(w, r, err, "Saved view")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, view)
+}
+
+// UpdateView godoc
+// @Summary Edit a saved view
+// @Description Replaces the list, name, and filters of one of the current user's saved views
+// @Tags Saved Views
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "View ID"
+// @Param request body models.SavedViewRequest true "View"
+// @Success 200 {object} models.SavedView "Updated view"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "View not found"
+// @Failure 409 {object} map[string]interface{} "A view with that name already exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /saved-views/{id} [put]
+func (h *SavedViewHandlers) UpdateView(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	view := h.getOwnView(w, r, currentUser)
+	if view == nil {
+		return
+	}
+
+	var req models.SavedViewRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) || !checkSavedViewFilters(w, &req) {
+		return
+	}
+
+	updated, err := h.viewRepo.Update(r.Context(), view.ID, &req)
+	if err != nil {
+		respondRepositoryError(w, r, err, "Saved view")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteView godoc
+// @Summary Delete a saved view
+// @Description Deletes one of the current user's saved views
+// @Tags Saved Views
+// @Security BearerAuth
+// @Param id path int true "View ID"
+// @Success 204 "View deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid view ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "View not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /saved-views/{id} [delete]
+func (h *SavedViewHandlers) DeleteView(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	view := h.getOwnView(w, r, currentUser)
+	if view == nil {
+		return
+	}
+
+	if err := h.viewRepo.Delete(r.Context(), view.ID); err != nil {
+		h.logger.LogError(r.Context(), "Failed to delete saved view", err, "view_id", view.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to delete saved view")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getOwnView loads the view in the URL, writing a 404 unless the current user saved it
+func (h *SavedViewHandlers) getOwnView(w http.ResponseWriter, r *http.Request, currentUser *models.User) *models.SavedView {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid view ID")
+		return nil
+	}
+
+	view, err := h.viewRepo.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get saved view", err, "view_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch saved view")
+		return nil
+	}
+	if view == nil || view.UserID != currentUser.ID {
+		respondError(w, http.StatusNotFound, "Saved view not found")
+		return nil
+	}
+	return view
+}
+
+// checkSavedViewFilters parses a view's filters the way its list parses its query, so a view
+// that saves can always be applied. Writes a 400 and returns false if they're invalid.
+func checkSavedViewFilters(w http.ResponseWriter, req *models.SavedViewRequest) bool {
+	view := models.SavedView{Filters: req.Filters}
+	r := &http.Request{URL: &url.URL{RawQuery: view.Query().Encode()}}
+
+	var err error
+	switch req.Resource {
+	case models.SavedViewResourceTasks:
+		_, err = parseTaskFilter(r)
+	case models.SavedViewResourceTimeOff:
+		_, err = parseTimeOffFilter(r)
+		if scope := models.TimeOffScope(req.Filters["scope"]); err == nil && scope != "" && !models.ValidTimeOffScopes[scope] {
+			err = fmt.Errorf("scope must be 'all', 'team', or 'mine'")
+		}
+	case models.SavedViewResourceTeamMembers:
+		_, err = parseUserSearchFilter(r)
+	}
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid filters: %v", err))
+		return false
+	}
+	return true
+}
+
+// applySavedView adds the filters of the saved view named by the view query parameter to the
+// request's query. Parameters given explicitly take precedence over the view's. Only the current
+// user's views of the resource apply, and the list enforces its usual access rules on the result.
+// Writes a 404 and returns false if the view can't be applied.
+func applySavedView(w http.ResponseWriter, r *http.Request, viewRepo repository.SavedViewRepository, currentUser *models.User, resource models.SavedViewResource) bool {
+	q := r.URL.Query()
+	raw := q.Get("view")
+	if raw == "" {
+		return true
+	}
+
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid view ID")
+		return false
+	}
+	var view *models.SavedView
+	if viewRepo != nil {
+		view, err = viewRepo.GetByID(r.Context(), id)
+		if err != nil {
+			logger.FromContext(r.Context()).LogError(r.Context(), "Failed to get saved view", err, "view_id", id)
+			respondError(w, http.StatusInternalServerError, "Failed to fetch saved view")
+			return false
+		}
+	}
+	if view == nil || view.UserID != currentUser.ID || view.Resource != resource {
+		respondError(w, http.StatusNotFound, "Saved view not found")
+		return false
+	}
+
+	for key, value := range view.Filters {
+		if !q.Has(key) {
+			q.Set(key, value)
+		}
+	}
+	q.Del("view")
+	r.URL.RawQuery = q.Encode()
+	return true
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestSavedViewHandlers(t *testing.T) {
+	owner := &models.User{ID: 1, Role: models.RoleEmployee}
+	other := &models.User{ID: 2, Role: models.RoleEmployee}
+	viewRepo := mocks.NewMockSavedViewRepository()
+	h := NewSavedViewHandlers(viewRepo)
+
+	do := func(handler http.HandlerFunc, method string, currentUser *models.User, target, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), currentUser), "id", id))
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+
+	t.Run("create", func(t *testing.T) {
+		for _, tt := range []struct {
+			name string
+			body string
+			want int
+		}{
+			{"tasks", `{"resource":"tasks","name":"My overdue tasks","filters":{"overdue":"true","sort":"priority"}}`, http.StatusCreated},
+			{"time off", `{"resource":"time_off","name":"Vacations","filters":{"type":"vacation","scope":"team"}}`, http.StatusCreated},
+			{"team members", `{"resource":"team_members","name":"Platform squad","filters":{"squad":"3"}}`, http.StatusCreated},
+			{"unknown filter", `{"resource":"tasks","name":"x","filters":{"scope":"team"}}`, http.StatusBadRequest},
+			{"invalid filter value", `{"resource":"tasks","name":"x","filters":{"priority":"critical"}}`, http.StatusBadRequest},
+			{"invalid scope", `{"resource":"time_off","name":"x","filters":{"scope":"everyone"}}`, http.StatusBadRequest},
+			{"invalid squad", `{"resource":"team_members","name":"x","filters":{"squad":"platform"}}`, http.StatusBadRequest},
+			{"missing name", `{"resource":"tasks"}`, http.StatusBadRequest},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				if rr := do(h.CreateView, http.MethodPost, owner, "/api/saved-views", "", tt.body); rr.Code != tt.want {
+					t.Errorf("CreateView() status = %d, want %d: %s", rr.Code, tt.want, rr.Body.String())
+				}
+			})
+		}
+	})
+
+	t.Run("list only shows the user's views", func(t *testing.T) {
+		var views []models.SavedView
+		rr := do(h.ListViews, http.MethodGet, owner, "/api/saved-views?resource=tasks", "", "")
+		if err := json.NewDecoder(rr.Body).Decode(&views); err != nil {
+			t.Fatal(err)
+		}
+		if len(views) != 1 || views[0].Name != "My overdue tasks" {
+			t.Errorf("ListViews() = %+v, want the one task view", views)
+		}
+
+		rr = do(h.ListViews, http.MethodGet, other, "/api/saved-views", "", "")
+		if err := json.NewDecoder(rr.Body).Decode(&views); err != nil {
+			t.Fatal(err)
+		}
+		if len(views) != 0 {
+			t.Errorf("ListViews() as another user = %+v, want none", views)
+		}
+
+		if rr := do(h.ListViews, http.MethodGet, owner, "/api/saved-views?resource=meetings", "", ""); rr.Code != http.StatusBadRequest {
+			t.Errorf("ListViews() with unknown resource status = %d, want %d", rr.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("only the owner can edit or delete", func(t *testing.T) {
+		body := `{"resource":"tasks","name":"Overdue","filters":{"overdue":"true"}}`
+		if rr := do(h.UpdateView, http.MethodPut, other, "/api/saved-views/1", "1", body); rr.Code != http.StatusNotFound {
+			t.Errorf("UpdateView() as another user status = %d, want %d", rr.Code, http.StatusNotFound)
+		}
+		if rr := do(h.DeleteView, http.MethodDelete, other, "/api/saved-views/1", "1", ""); rr.Code != http.StatusNotFound {
+			t.Errorf("DeleteView() as another user status = %d, want %d", rr.Code, http.StatusNotFound)
+		}
+		if rr := do(h.UpdateView, http.MethodPut, owner, "/api/saved-views/1", "1", body); rr.Code != http.StatusOK {
+			t.Errorf("UpdateView() status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+		if viewRepo.Views[1].Name != "Overdue" {
+			t.Errorf("view name = %q, want Overdue", viewRepo.Views[1].Name)
+		}
+		if rr := do(h.DeleteView, http.MethodDelete, owner, "/api/saved-views/2", "2", ""); rr.Code != http.StatusNoContent {
+			t.Errorf("DeleteView() status = %d, want %d", rr.Code, http.StatusNoContent)
+		}
+		if _, ok := viewRepo.Views[2]; ok {
+			t.Error("DeleteView() kept the view")
+		}
+	})
+}
+
+func TestCalendarHandlers_ListTasks_SavedView(t *testing.T) {
+	userID := int64(1)
+	now := time.Now()
+
+	taskRepo := mocks.NewMockTaskRepository()
+	taskRepo.AddTask(&models.Task{ID: 1, Title: "Overdue", CreatedByID: userID, Status: models.TaskStatusPending,
+		Priority: models.TaskPriorityHigh, DueDate: now.AddDate(0, 0, -3)})
+	taskRepo.AddTask(&models.Task{ID: 2, Title: "Upcoming", CreatedByID: userID, Status: models.TaskStatusPending,
+		Priority: models.TaskPriorityLow, DueDate: now.AddDate(0, 0, 2)})
+
+	viewRepo := mocks.NewMockSavedViewRepository()
+	viewRepo.AddView(&models.SavedView{ID: 1, UserID: userID, Resource: models.SavedViewResourceTasks, Filters: map[string]string{"priority": "high"}})
+	viewRepo.AddView(&models.SavedView{ID: 2, UserID: 2, Resource: models.SavedViewResourceTasks, Filters: map[string]string{"priority": "low"}})
+	viewRepo.AddView(&models.SavedView{ID: 3, UserID: userID, Resource: models.SavedViewResourceTimeOff, Filters: map[string]string{"type": "vacation"}})
+
+	h := NewCalendarHandlers(nil, taskRepo, nil).WithSavedViews(viewRepo)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedIDs    []int64
+	}{
+		{name: "view filters apply", query: "?view=1", expectedStatus: http.StatusOK, expectedIDs: []int64{1}},
+		{name: "explicit filters take precedence", query: "?view=1&priority=low", expectedStatus: http.StatusOK, expectedIDs: []int64{2}},
+		{name: "another user's view", query: "?view=2", expectedStatus: http.StatusNotFound},
+		{name: "view of another list", query: "?view=3", expectedStatus: http.StatusNotFound},
+		{name: "missing view", query: "?view=99", expectedStatus: http.StatusNotFound},
+		{name: "invalid view", query: "?view=mine", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/calendar/tasks"+tt.query, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: userID, Role: models.RoleEmployee}))
+
+			rr := httptest.NewRecorder()
+			h.ListTasks(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("ListTasks() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var tasks []models.Task
+			if err := json.Unmarshal(rr.Body.Bytes(), &tasks); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			ids := make([]int64, len(tasks))
+			for i, task := range tasks {
+				ids[i] = task.ID
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.expectedIDs) {
+				t.Errorf("task IDs = %v, want %v", ids, tt.expectedIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,631 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/sanitize"
+	"github.com/smith-dallin/manager-dashboard/internal/scim"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+const (
+	// scimDefaultPageSize is how many resources a list returns when the client doesn't say
+	scimDefaultPageSize = 100
+	// scimMaxPageSize caps how many resources a list returns
+	scimMaxPageSize = 500
+)
+
+// SCIMHandlers serves the SCIM 2.0 endpoints identity providers such as Okta and Azure AD use to
+// provision employees as Users and squads as Groups. They live under /scim/v2 rather than /api,
+// authenticate with the SCIM token instead of a user session, and speak SCIM's JSON and errors.
+type SCIMHandlers struct {
+	userRepo   repository.UserRepository
+	squadRepo  repository.SquadRepository
+	onboarding *services.OnboardingService
+	auth0Sync  *services.Auth0SyncService
+	broker     *events.Broker
+	logger     *logger.Logger
+}
+
+// NewSCIMHandlers creates a new SCIM handlers instance that announces directory changes to the broker
+func NewSCIMHandlers(userRepo repository.UserRepository, squadRepo repository.SquadRepository, broker *events.Broker) *SCIMHandlers {
+	return &SCIMHandlers{
+		userRepo:  userRepo,
+		squadRepo: squadRepo,
+		broker:    broker,
+		logger:    logger.Default().WithComponent("scim-handlers"),
+	}
+}
+
+// WithOnboarding starts onboarding checklists for provisioned users
+func (h *SCIMHandlers) WithOnboarding(onboarding *services.OnboardingService) *SCIMHandlers {
+	h.onboarding = onboarding
+	return h
+}
+
+// scimBaseURL is the /scim/v2 root resource locations are built from
+func scimBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/scim/v2"
+}
+
+// scimPage parses SCIM's 1-based startIndex and count query parameters
+func scimPage(r *http.Request) (startIndex, count int) {
+	startIndex, count = 1, scimDefaultPageSize
+	if v, err := strconv.Atoi(r.URL.Query().Get("startIndex")); err == nil && v > 1 {
+		startIndex = v
+	}
+	if v, err := strconv.Atoi(r.URL.Query().Get("count")); err == nil {
+		count = min(max(v, 0), scimMaxPageSize)
+	}
+	return startIndex, count
+}
+
+// paginate returns the page of items starting at the 1-based startIndex
+func paginate[T any](items []T, startIndex, count int) []T {
+	start := min(startIndex-1, len(items))
+	return items[start:min(start+count, len(items))]
+}
+
+// decodeSCIM decodes a SCIM request body, writing a SCIM error if it is malformed
+func decodeSCIM(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		scim.WriteError(w, http.StatusBadRequest, scim.ErrInvalidSyntax, "Invalid request body")
+		return false
+	}
+	return true
+}
+
+// writeSCIMError writes err as a SCIM error, logging anything that isn't a client error
+func (h *SCIMHandlers) writeSCIMError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	var scimErr *scim.Error
+	if errors.As(err, &scimErr) {
+		status, _ := strconv.Atoi(scimErr.Status)
+		scim.WriteError(w, status, scimErr.ScimType, scimErr.Detail)
+		return
+	}
+	h.logger.LogError(r.Context(), msg, err)
+	scim.WriteError(w, http.StatusInternalServerError, "", msg)
+}
+
+// parseSCIMID parses the {id} URL parameter, writing a 404 if it can't name a resource
+func parseSCIMID(w http.ResponseWriter, r *http.Request, resourceType string) (int64, bool) {
+	id, ok := scim.ParseID(chi.URLParam(r, "id"))
+	if !ok {
+		scim.WriteError(w, http.StatusNotFound, "", resourceType+" not found")
+	}
+	return id, ok
+}
+
+// GetServiceProviderConfig describes which optional SCIM features are supported
+func (h *SCIMHandlers) GetServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	unsupported := map[string]bool{"supported": false}
+	scim.WriteJSON(w, http.StatusOK, map[string]any{
+		"schemas":        []string{scim.SchemaServiceProviderConfig},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": scimMaxPageSize},
+		"changePassword": unsupported,
+		"sort":           unsupported,
+		"etag":           unsupported,
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "The SCIM token configured on the server",
+			"primary":     true,
+		}},
+	})
+}
+
+// GetUsers lists users, including deactivated ones, or finds one with a userName filter
+func (h *SCIMHandlers) GetUsers(w http.ResponseWriter, r *http.Request) {
+	startIndex, count := scimPage(r)
+	filter, err := scim.ParseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to list users", err)
+		return
+	}
+
+	var users []models.User
+	var total int
+	switch {
+	case filter == nil:
+		users, total, err = h.userRepo.Search(r.Context(), models.UserSearchFilter{}, count, startIndex-1)
+		if err != nil {
+			h.writeSCIMError(w, r, "Failed to list users", err)
+			return
+		}
+	case filter.Is("userName") || filter.Is("emails.value") || filter.Is(`emails[type eq "work"].value`):
+		if user, err := h.userRepo.GetByEmail(r.Context(), filter.Value); err == nil && user != nil {
+			total = 1
+			users = paginate([]models.User{*user}, startIndex, count)
+		}
+	default:
+		scim.WriteError(w, http.StatusBadRequest, scim.ErrInvalidFilter, "Users can only be filtered by userName")
+		return
+	}
+
+	userIDs := make([]int64, len(users))
+	for i := range users {
+		userIDs[i] = users[i].ID
+	}
+	squads, err := h.squadRepo.GetByUserIDs(r.Context(), userIDs)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to list users", err)
+		return
+	}
+
+	resources := make([]scim.User, len(users))
+	for i := range users {
+		resources[i] = scim.FromUser(&users[i], squads[users[i].ID], scimBaseURL(r))
+	}
+	scim.WriteJSON(w, http.StatusOK, scim.NewListResponse(resources, total, startIndex))
+}
+
+// GetUser returns a user
+func (h *SCIMHandlers) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.loadUser(w, r)
+	if !ok {
+		return
+	}
+	h.respondUser(w, r, http.StatusOK, user)
+}
+
+// CreateUser provisions an employee. They sign in with the identity provider, and their account
+// is linked to the user by email on their first login.
+func (h *SCIMHandlers) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var resource scim.User
+	if !decodeSCIM(w, r, &resource) {
+		return
+	}
+
+	req, err := resource.CreateRequest()
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to create user", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		scim.WriteError(w, http.StatusBadRequest, scim.ErrInvalidValue, err.Error())
+		return
+	}
+	if existing, err := h.userRepo.GetByEmail(r.Context(), req.Email); err == nil && existing != nil {
+		scim.WriteError(w, http.StatusConflict, scim.ErrUniqueness, "A user with this userName already exists")
+		return
+	}
+	if err := h.checkManager(r.Context(), req.SupervisorID, 0); err != nil {
+		h.writeSCIMError(w, r, "Failed to create user", err)
+		return
+	}
+
+	user, err := h.userRepo.Create(r.Context(), req, "")
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to create user", err)
+		return
+	}
+	if resource.IsActive() {
+		h.onboarding.Start(r.Context(), user)
+	} else if err := h.userRepo.Deactivate(r.Context(), user.ID); err != nil {
+		h.writeSCIMError(w, r, "Failed to deactivate user", err)
+		return
+	}
+	h.broker.Publish(r.Context(), events.UserChanged, nil)
+
+	user, err = h.userRepo.GetByID(r.Context(), user.ID)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to get user", err)
+		return
+	}
+	h.respondUser(w, r, http.StatusCreated, user)
+}
+
+// ReplaceUser replaces a user's profile and, through active, deactivates or reactivates them
+func (h *SCIMHandlers) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.loadUser(w, r)
+	if !ok {
+		return
+	}
+	var resource scim.User
+	if !decodeSCIM(w, r, &resource) {
+		return
+	}
+	h.applyUser(w, r, user, &resource)
+}
+
+// PatchUser applies changes to a user. Identity providers deactivate users by patching active.
+func (h *SCIMHandlers) PatchUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.loadUser(w, r)
+	if !ok {
+		return
+	}
+	var patch scim.PatchRequest
+	if !decodeSCIM(w, r, &patch) {
+		return
+	}
+
+	resource := scim.FromUser(user, nil, scimBaseURL(r))
+	if err := scim.ApplyUserPatch(&resource, patch.Operations); err != nil {
+		h.writeSCIMError(w, r, "Failed to update user", err)
+		return
+	}
+	h.applyUser(w, r, user, &resource)
+}
+
+// DeleteUser deactivates a user. Their history is kept, so they can be reactivated later.
+func (h *SCIMHandlers) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.loadUser(w, r)
+	if !ok {
+		return
+	}
+	if user.IsActive {
+		if err := h.userRepo.Deactivate(r.Context(), user.ID); err != nil {
+			h.writeSCIMError(w, r, "Failed to deactivate user", err)
+			return
+		}
+		h.auth0Sync.Block(r.Context(), user)
+		h.broker.Publish(r.Context(), events.UserChanged, nil)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadUser loads the user named by the {id} URL parameter, writing a 404 if there is none
+func (h *SCIMHandlers) loadUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	id, ok := parseSCIMID(w, r, "User")
+	if !ok {
+		return nil, false
+	}
+	user, err := h.userRepo.GetByID(r.Context(), id)
+	if err != nil || user == nil {
+		scim.WriteError(w, http.StatusNotFound, "", "User not found")
+		return nil, false
+	}
+	return user, true
+}
+
+// applyUser updates a user to match the resource, using the same Deactivate and Reactivate flows
+// as the dashboard when active changes
+func (h *SCIMHandlers) applyUser(w http.ResponseWriter, r *http.Request, user *models.User, resource *scim.User) {
+	// Users are matched to their identity provider account by email, so it is fixed once provisioned
+	if !strings.EqualFold(resource.UserName, user.Email) || !strings.EqualFold(resource.Email(), user.Email) {
+		scim.WriteError(w, http.StatusBadRequest, scim.ErrMutability, "userName cannot be changed")
+		return
+	}
+	req, err := resource.UpdateRequest()
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to update user", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		scim.WriteError(w, http.StatusBadRequest, scim.ErrInvalidValue, err.Error())
+		return
+	}
+	if err := h.checkManager(r.Context(), req.SupervisorID, user.ID); err != nil {
+		h.writeSCIMError(w, r, "Failed to update user", err)
+		return
+	}
+
+	if !user.IsActive && resource.IsActive() {
+		if err := h.userRepo.Reactivate(r.Context(), user.ID); err != nil {
+			h.writeSCIMError(w, r, "Failed to reactivate user", err)
+			return
+		}
+		h.auth0Sync.Unblock(r.Context(), user)
+	}
+	if _, err := h.userRepo.Update(r.Context(), user.ID, req); err != nil {
+		h.writeSCIMError(w, r, "Failed to update user", err)
+		return
+	}
+	if user.IsActive && !resource.IsActive() {
+		if err := h.userRepo.Deactivate(r.Context(), user.ID); err != nil {
+			h.writeSCIMError(w, r, "Failed to deactivate user", err)
+			return
+		}
+		h.auth0Sync.Block(r.Context(), user)
+	}
+	h.broker.Publish(r.Context(), events.UserChanged, nil)
+
+	updated, err := h.userRepo.GetByID(r.Context(), user.ID)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to get user", err)
+		return
+	}
+	h.respondUser(w, r, http.StatusOK, updated)
+}
+
+// checkManager checks that a provisioned manager exists and isn't the user themselves
+func (h *SCIMHandlers) checkManager(ctx context.Context, supervisorID *int64, userID int64) error {
+	if supervisorID == nil {
+		return nil
+	}
+	if *supervisorID == userID {
+		return &scim.Error{Status: "400", ScimType: scim.ErrInvalidValue, Detail: "A user cannot be their own manager"}
+	}
+	if manager, err := h.userRepo.GetByID(ctx, *supervisorID); err != nil || manager == nil {
+		return &scim.Error{Status: "400", ScimType: scim.ErrInvalidValue, Detail: "manager not found"}
+	}
+	return nil
+}
+
+// respondUser writes a user and their squads as a SCIM User
+func (h *SCIMHandlers) respondUser(w http.ResponseWriter, r *http.Request, status int, user *models.User) {
+	squads, err := h.squadRepo.GetByUserID(r.Context(), user.ID)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to get user's squads", err)
+		return
+	}
+	scim.WriteJSON(w, status, scim.FromUser(user, squads, scimBaseURL(r)))
+}
+
+// GetGroups lists squads or finds one with a displayName filter
+func (h *SCIMHandlers) GetGroups(w http.ResponseWriter, r *http.Request) {
+	startIndex, count := scimPage(r)
+	filter, err := scim.ParseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to list groups", err)
+		return
+	}
+	if filter != nil && !filter.Is("displayName") {
+		scim.WriteError(w, http.StatusBadRequest, scim.ErrInvalidFilter, "Groups can only be filtered by displayName")
+		return
+	}
+
+	squads, err := h.squadRepo.GetAll(r.Context())
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to list groups", err)
+		return
+	}
+	if filter != nil {
+		squads = slices.DeleteFunc(squads, func(squad models.Squad) bool {
+			return !strings.EqualFold(squad.Name, filter.Value)
+		})
+	}
+
+	page := paginate(squads, startIndex, count)
+	resources := make([]scim.Group, len(page))
+	for i := range page {
+		members, err := h.squadRepo.GetUsersBySquadID(r.Context(), page[i].ID)
+		if err != nil {
+			h.writeSCIMError(w, r, "Failed to list group members", err)
+			return
+		}
+		resources[i] = scim.FromSquad(&page[i], members, scimBaseURL(r))
+	}
+	scim.WriteJSON(w, http.StatusOK, scim.NewListResponse(resources, len(squads), startIndex))
+}
+
+// GetGroup returns a squad and its members
+func (h *SCIMHandlers) GetGroup(w http.ResponseWriter, r *http.Request) {
+	squad, ok := h.loadSquad(w, r)
+	if !ok {
+		return
+	}
+	h.respondGroup(w, r, http.StatusOK, squad)
+}
+
+// CreateGroup creates a squad with the group's members
+func (h *SCIMHandlers) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var resource scim.Group
+	if !decodeSCIM(w, r, &resource) {
+		return
+	}
+
+	name, err := h.squadName(r.Context(), resource.DisplayName, 0)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to create group", err)
+		return
+	}
+	memberIDs, err := h.memberIDs(r.Context(), resource.Members)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to create group", err)
+		return
+	}
+
+	squad, err := h.squadRepo.Create(r.Context(), name)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to create group", err)
+		return
+	}
+	if err := h.setMembers(r.Context(), squad.ID, nil, memberIDs); err != nil {
+		h.writeSCIMError(w, r, "Failed to add group members", err)
+		return
+	}
+	h.broker.Publish(r.Context(), events.SquadChanged, nil)
+	h.respondGroup(w, r, http.StatusCreated, squad)
+}
+
+// ReplaceGroup renames a squad and replaces its members
+func (h *SCIMHandlers) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	squad, ok := h.loadSquad(w, r)
+	if !ok {
+		return
+	}
+	var resource scim.Group
+	if !decodeSCIM(w, r, &resource) {
+		return
+	}
+	h.applyGroup(w, r, squad, &resource)
+}
+
+// PatchGroup renames a squad or adds and removes members
+func (h *SCIMHandlers) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	squad, ok := h.loadSquad(w, r)
+	if !ok {
+		return
+	}
+	var patch scim.PatchRequest
+	if !decodeSCIM(w, r, &patch) {
+		return
+	}
+
+	members, err := h.squadRepo.GetUsersBySquadID(r.Context(), squad.ID)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to get group members", err)
+		return
+	}
+	resource := scim.FromSquad(squad, members, scimBaseURL(r))
+	if err := scim.ApplyGroupPatch(&resource, patch.Operations); err != nil {
+		h.writeSCIMError(w, r, "Failed to update group", err)
+		return
+	}
+	h.applyGroup(w, r, squad, &resource)
+}
+
+// DeleteGroup deletes a squad; its members stay in the directory
+func (h *SCIMHandlers) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	squad, ok := h.loadSquad(w, r)
+	if !ok {
+		return
+	}
+	if err := h.squadRepo.Delete(r.Context(), squad.ID); err != nil {
+		h.writeSCIMError(w, r, "Failed to delete group", err)
+		return
+	}
+	h.broker.Publish(r.Context(), events.SquadChanged, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadSquad loads the squad named by the {id} URL parameter, writing a 404 if there is none
+func (h *SCIMHandlers) loadSquad(w http.ResponseWriter, r *http.Request) (*models.Squad, bool) {
+	id, ok := parseSCIMID(w, r, "Group")
+	if !ok {
+		return nil, false
+	}
+	squad, err := h.squadRepo.GetByID(r.Context(), id)
+	if err != nil || squad == nil {
+		scim.WriteError(w, http.StatusNotFound, "", "Group not found")
+		return nil, false
+	}
+	return squad, true
+}
+
+// applyGroup renames a squad and updates its members to match the resource
+func (h *SCIMHandlers) applyGroup(w http.ResponseWriter, r *http.Request, squad *models.Squad, resource *scim.Group) {
+	name, err := h.squadName(r.Context(), resource.DisplayName, squad.ID)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to update group", err)
+		return
+	}
+	memberIDs, err := h.memberIDs(r.Context(), resource.Members)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to update group", err)
+		return
+	}
+	current, err := h.squadRepo.GetUsersBySquadID(r.Context(), squad.ID)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to get group members", err)
+		return
+	}
+	currentIDs := make([]int64, len(current))
+	for i := range current {
+		currentIDs[i] = current[i].ID
+	}
+
+	if name != squad.Name {
+		if squad, err = h.squadRepo.Rename(r.Context(), squad.ID, name); err != nil {
+			h.writeSCIMError(w, r, "Failed to rename group", err)
+			return
+		}
+	}
+	if err := h.setMembers(r.Context(), squad.ID, currentIDs, memberIDs); err != nil {
+		h.writeSCIMError(w, r, "Failed to update group members", err)
+		return
+	}
+	h.broker.Publish(r.Context(), events.SquadChanged, nil)
+	h.respondGroup(w, r, http.StatusOK, squad)
+}
+
+// squadName validates a group's displayName as the name of the squad with squadID, which is 0
+// for a new squad
+func (h *SCIMHandlers) squadName(ctx context.Context, displayName string, squadID int64) (string, error) {
+	name, validationErr := sanitize.SanitizeAndValidate(displayName, "displayName", 1, 100)
+	if validationErr != "" {
+		return "", &scim.Error{Status: "400", ScimType: scim.ErrInvalidValue, Detail: validationErr}
+	}
+	squads, err := h.squadRepo.GetAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, squad := range squads {
+		if squad.ID != squadID && strings.EqualFold(squad.Name, name) {
+			return "", &scim.Error{Status: "409", ScimType: scim.ErrUniqueness, Detail: "A group with this displayName already exists"}
+		}
+	}
+	return name, nil
+}
+
+// memberIDs resolves group members to the IDs of existing users
+func (h *SCIMHandlers) memberIDs(ctx context.Context, members []scim.Value) ([]int64, error) {
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, ok := scim.ParseID(member.Value)
+		if ok {
+			user, err := h.userRepo.GetByID(ctx, id)
+			ok = err == nil && user != nil
+		}
+		if !ok {
+			return nil, &scim.Error{Status: "400", ScimType: scim.ErrInvalidValue, Detail: fmt.Sprintf("member %q not found", member.Value)}
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// setMembers adds users to and removes users from a squad so its members become memberIDs
+func (h *SCIMHandlers) setMembers(ctx context.Context, squadID int64, currentIDs, memberIDs []int64) error {
+	for _, userID := range currentIDs {
+		if slices.Contains(memberIDs, userID) {
+			continue
+		}
+		squadIDs, err := h.squadRepo.GetSquadIDsByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		squadIDs = slices.DeleteFunc(squadIDs, func(id int64) bool { return id == squadID })
+		if err := h.squadRepo.SetUserSquads(ctx, userID, squadIDs); err != nil {
+			return err
+		}
+	}
+	for _, userID := range memberIDs {
+		if slices.Contains(currentIDs, userID) {
+			continue
+		}
+		squadIDs, err := h.squadRepo.GetSquadIDsByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		// Deactivated members aren't listed as current but keep their squads
+		if slices.Contains(squadIDs, squadID) {
+			continue
+		}
+		if err := h.squadRepo.SetUserSquads(ctx, userID, append(squadIDs, squadID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// respondGroup writes a squad and its members as a SCIM Group
+func (h *SCIMHandlers) respondGroup(w http.ResponseWriter, r *http.Request, status int, squad *models.Squad) {
+	members, err := h.squadRepo.GetUsersBySquadID(r.Context(), squad.ID)
+	if err != nil {
+		h.writeSCIMError(w, r, "Failed to get group members", err)
+		return
+	}
+	scim.WriteJSON(w, status, scim.FromSquad(squad, members, scimBaseURL(r)))
+}
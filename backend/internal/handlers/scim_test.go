@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/scim"
+)
+
+// newSCIMTestHandlers sets up a supervisor (1) and an employee (2) in the Platform squad (1)
+func newSCIMTestHandlers() (*SCIMHandlers, *mocks.MockUserRepository, *mocks.MockSquadRepository) {
+	supervisorID := int64(1)
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Email: "sam@example.com", FirstName: "Sam", LastName: "Super", Role: models.RoleSupervisor, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, Email: "eve@example.com", FirstName: "Eve", LastName: "Employee", Role: models.RoleEmployee, SupervisorID: &supervisorID, IsActive: true})
+
+	squadRepo := mocks.NewMockSquadRepository()
+	squadRepo.AddSquad(&models.Squad{ID: 1, Name: "Platform"})
+	squadRepo.UserSquads[2] = []int64{1}
+	squadRepo.GetUsersBySquadIDFunc = func(ctx context.Context, squadID int64) ([]models.User, error) {
+		var members []models.User
+		for userID, squadIDs := range squadRepo.UserSquads {
+			if slices.Contains(squadIDs, squadID) {
+				members = append(members, *userRepo.Users[userID])
+			}
+		}
+		slices.SortFunc(members, func(a, b models.User) int { return int(a.ID - b.ID) })
+		return members, nil
+	}
+
+	return NewSCIMHandlers(userRepo, squadRepo, nil), userRepo, squadRepo
+}
+
+// serveSCIM calls a SCIM handler, with id as the {id} URL parameter when it isn't empty
+func serveSCIM(handler http.HandlerFunc, method, target, id, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if id != "" {
+		req = req.WithContext(chiCtxWithID(req.Context(), "id", id))
+	}
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+	return rr
+}
+
+func TestSCIMHandlers_CreateUser(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedType   string
+	}{
+		{"provisions employee", `{"userName":"ada@example.com","name":{"givenName":"Ada","familyName":"Lovelace"},"title":"Engineer","urn:ietf:params:scim:schemas:extension:enterprise:2.0:User":{"department":"R&D","manager":{"value":"1"}},"active":true}`, http.StatusCreated, ""},
+		{"existing userName", `{"userName":"eve@example.com","name":{"givenName":"Eve","familyName":"Again"}}`, http.StatusConflict, scim.ErrUniqueness},
+		{"missing name", `{"userName":"ada@example.com"}`, http.StatusBadRequest, scim.ErrInvalidValue},
+		{"unknown manager", `{"userName":"ada@example.com","name":{"givenName":"Ada","familyName":"Lovelace"},"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User":{"manager":{"value":"99"}}}`, http.StatusBadRequest, scim.ErrInvalidValue},
+		{"malformed body", `{"userName":`, http.StatusBadRequest, scim.ErrInvalidSyntax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _ := newSCIMTestHandlers()
+			rr := serveSCIM(h.CreateUser, http.MethodPost, "/scim/v2/Users", "", tt.body)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if got := rr.Header().Get("Content-Type"); got != scim.ContentType {
+				t.Errorf("Content-Type = %q, want %q", got, scim.ContentType)
+			}
+			if tt.expectedType != "" {
+				var resp scim.Error
+				_ = json.NewDecoder(rr.Body).Decode(&resp)
+				if resp.ScimType != tt.expectedType {
+					t.Errorf("scimType = %q, want %q", resp.ScimType, tt.expectedType)
+				}
+			}
+		})
+	}
+}
+
+func TestSCIMHandlers_CreateUser_Inactive(t *testing.T) {
+	h, userRepo, _ := newSCIMTestHandlers()
+	rr := serveSCIM(h.CreateUser, http.MethodPost, "/scim/v2/Users", "",
+		`{"userName":"ada@example.com","name":{"givenName":"Ada","familyName":"Lovelace"},"active":false}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rr.Code, rr.Body.String())
+	}
+	if user := userRepo.ByEmail["ada@example.com"]; user == nil || user.IsActive {
+		t.Errorf("user = %+v, want an inactive user", user)
+	}
+}
+
+func TestSCIMHandlers_PatchUser(t *testing.T) {
+	h, userRepo, _ := newSCIMTestHandlers()
+
+	rr := serveSCIM(h.PatchUser, http.MethodPatch, "/scim/v2/Users/2", "2",
+		`{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"Replace","path":"active","value":"False"},{"op":"replace","path":"title","value":"Staff Engineer"}]}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("deactivate status = %d: %s", rr.Code, rr.Body.String())
+	}
+	if user := userRepo.Users[2]; user.IsActive || user.Title != "Staff Engineer" {
+		t.Errorf("user active %v title %q, want deactivated Staff Engineer", user.IsActive, user.Title)
+	}
+	var resource scim.User
+	if err := json.NewDecoder(rr.Body).Decode(&resource); err != nil || resource.IsActive() {
+		t.Errorf("response active = %v (%v), want false", resource.IsActive(), err)
+	}
+
+	rr = serveSCIM(h.PatchUser, http.MethodPatch, "/scim/v2/Users/2", "2", `{"Operations":[{"op":"replace","value":{"active":true}}]}`)
+	if rr.Code != http.StatusOK || !userRepo.Users[2].IsActive {
+		t.Errorf("reactivate status = %d, active = %v", rr.Code, userRepo.Users[2].IsActive)
+	}
+
+	rr = serveSCIM(h.PatchUser, http.MethodPatch, "/scim/v2/Users/2", "2", `{"Operations":[{"op":"replace","path":"userName","value":"eve@new.example.com"}]}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("userName change status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	rr = serveSCIM(h.PatchUser, http.MethodPatch, "/scim/v2/Users/99", "99", `{"Operations":[]}`)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("missing user status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestSCIMHandlers_DeleteUser(t *testing.T) {
+	h, userRepo, _ := newSCIMTestHandlers()
+	rr := serveSCIM(h.DeleteUser, http.MethodDelete, "/scim/v2/Users/2", "2", "")
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("status = %d: %s", rr.Code, rr.Body.String())
+	}
+	if userRepo.Users[2] == nil || userRepo.Users[2].IsActive {
+		t.Error("user was not deactivated and kept")
+	}
+}
+
+func TestSCIMHandlers_GetUsers(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedTotal  int
+	}{
+		{"all users", "", http.StatusOK, 2},
+		{"by userName", `?filter=userName+eq+"eve@example.com"`, http.StatusOK, 1},
+		{"unknown userName", `?filter=userName+eq+"nobody@example.com"`, http.StatusOK, 0},
+		{"unsupported attribute", `?filter=title+eq+"Engineer"`, http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _ := newSCIMTestHandlers()
+			rr := serveSCIM(h.GetUsers, http.MethodGet, "/scim/v2/Users"+tt.query, "", "")
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+			var resp scim.ListResponse
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.TotalResults != tt.expectedTotal {
+				t.Errorf("totalResults = %d, want %d", resp.TotalResults, tt.expectedTotal)
+			}
+		})
+	}
+}
+
+func TestSCIMHandlers_Groups(t *testing.T) {
+	h, _, squadRepo := newSCIMTestHandlers()
+
+	rr := serveSCIM(h.CreateGroup, http.MethodPost, "/scim/v2/Groups", "", `{"displayName":"Data","members":[{"value":"1"},{"value":"2"}]}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", rr.Code, rr.Body.String())
+	}
+	var group scim.Group
+	if err := json.NewDecoder(rr.Body).Decode(&group); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if group.ID != "2" || len(group.Members) != 2 {
+		t.Errorf("group = %+v, want squad 2 with two members", group)
+	}
+	if !slices.Equal(squadRepo.UserSquads[2], []int64{1, 2}) {
+		t.Errorf("employee's squads = %v, want [1 2]", squadRepo.UserSquads[2])
+	}
+
+	rr = serveSCIM(h.CreateGroup, http.MethodPost, "/scim/v2/Groups", "", `{"displayName":"platform"}`)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("duplicate create status = %d, want %d", rr.Code, http.StatusConflict)
+	}
+
+	rr = serveSCIM(h.PatchGroup, http.MethodPatch, "/scim/v2/Groups/2", "2",
+		`{"Operations":[{"op":"remove","path":"members[value eq \"2\"]"},{"op":"replace","path":"displayName","value":"Data Eng"}]}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("patch status = %d: %s", rr.Code, rr.Body.String())
+	}
+	if !slices.Equal(squadRepo.UserSquads[2], []int64{1}) {
+		t.Errorf("employee's squads = %v, want [1]", squadRepo.UserSquads[2])
+	}
+	if squadRepo.Squads[2].Name != "Data Eng" {
+		t.Errorf("squad name = %q, want Data Eng", squadRepo.Squads[2].Name)
+	}
+
+	rr = serveSCIM(h.PatchGroup, http.MethodPatch, "/scim/v2/Groups/2", "2", `{"Operations":[{"op":"add","path":"members","value":[{"value":"99"}]}]}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("unknown member status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	rr = serveSCIM(h.DeleteGroup, http.MethodDelete, "/scim/v2/Groups/2", "2", "")
+	if rr.Code != http.StatusNoContent || squadRepo.Squads[2] != nil {
+		t.Errorf("delete status = %d, squad = %v", rr.Code, squadRepo.Squads[2])
+	}
+}
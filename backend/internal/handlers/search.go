@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// DefaultSearchLimit is the number of results returned per source when no limit is given
+const DefaultSearchLimit = 20
+
+// SearchHandlers handles the global search endpoint
+type SearchHandlers struct {
+	attachmentRepo    repository.MeetingAttachmentRepository
+	attachmentService *services.MeetingAttachmentService
+}
+
+// NewSearchHandlers creates a new search handlers instance. The attachment service signs download
+// URLs for uploaded transcripts in the results.
+func NewSearchHandlers(attachmentRepo repository.MeetingAttachmentRepository, attachmentService *services.MeetingAttachmentService) *SearchHandlers {
+	return &SearchHandlers{
+		attachmentRepo:    attachmentRepo,
+		attachmentService: attachmentService,
+	}
+}
+
+// Search runs a query across searchable sources the current user can access.
+// Meeting transcripts are only searched for meetings the user organized or attends.
+func (h *SearchHandlers) Search(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		respondError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	if len(query) > 200 {
+		respondError(w, http.StatusBadRequest, "q must be 200 characters or less")
+		return
+	}
+
+	limit := DefaultSearchLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 {
+			respondError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(parsed, MaxPerPage)
+	}
+
+	transcripts, err := h.attachmentRepo.SearchTranscripts(r.Context(), currentUser.ID, query, limit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to search")
+		return
+	}
+
+	signing := make([]*models.MeetingAttachment, len(transcripts))
+	for i := range transcripts {
+		signing[i] = &transcripts[i].Attachment
+	}
+	if err := h.attachmentService.SignURLs(r.Context(), signing...); err != nil {
+		respondError(w, http.StatusServiceUnavailable, "Search is temporarily unavailable. Please try again later.")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.SearchResponse{
+		Query:       query,
+		Transcripts: transcripts,
+	})
+}
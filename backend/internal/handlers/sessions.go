@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// SessionHandlers lists users' sessions and signs users out
+type SessionHandlers struct {
+	sessionService *services.SessionService
+	userRepo       repository.UserRepository
+	logger         *logger.Logger
+}
+
+// NewSessionHandlers creates a new session handlers instance
+func NewSessionHandlers(sessionService *services.SessionService, userRepo repository.UserRepository) *SessionHandlers {
+	return &SessionHandlers{
+		sessionService: sessionService,
+		userRepo:       userRepo,
+		logger:         logger.Default().WithComponent("session-handlers"),
+	}
+}
+
+// GetUserSessions godoc
+// @Summary List a user's sessions
+// @Description Returns the tokens a user has recently authenticated with, most recently used first, with the IP address and user agent they were last used from. Users can list their own; listing anyone else's requires session:manage.
+// @Tags Sessions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} models.UserSession "Sessions"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/sessions [get]
+func (h *SessionHandlers) GetUserSessions(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	if userID != currentUser.ID && requirePermission(w, r, authz.ActionSessionManage) == nil {
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	sessions, err := h.sessionService.List(r.Context(), userID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list sessions", err, "user_id", userID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch sessions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sessions)
+}
+
+// GetUserLogins godoc
+// @Summary Get a user's sign-in activity
+// @Description Returns when a user last signed in and was last seen, with their recent sign-ins, most recent first, and the IP address and user agent of each. Users can get their own; getting anyone else's requires session:manage.
+// @Tags Sessions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.UserLoginActivity "Sign-in activity"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/logins [get]
+func (h *SessionHandlers) GetUserLogins(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	if userID != currentUser.ID && requirePermission(w, r, authz.ActionSessionManage) == nil {
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	activity, err := h.sessionService.LoginActivity(r.Context(), user)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to list logins", err, "user_id", userID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch sign-ins")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, activity)
+}
+
+// RevokeUserSessions godoc
+// @Summary Sign a user out everywhere
+// @Description Revokes every token issued to the user so far, so their next request is rejected and they must sign in again. Requires session:manage.
+// @Tags Sessions
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 204 "Signed out"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/sessions [delete]
+func (h *SessionHandlers) RevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionSessionManage)
+	if currentUser == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if err := h.sessionService.RevokeAll(r.Context(), user, currentUser.ID); err != nil {
+		if errors.Is(err, services.ErrUserNeverSignedIn) {
+			respondError(w, http.StatusBadRequest, "User has never signed in")
+			return
+		}
+		h.logger.LogError(r.Context(), "Failed to revoke sessions", err, "user_id", userID)
+		respondError(w, http.StatusInternalServerError, "Failed to sign user out")
+		return
+	}
+
+	h.logger.Info("User signed out everywhere", "user_id", userID, "revoked_by", currentUser.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeUserSession godoc
+// @Summary Sign a user out of a session
+// @Description Revokes the token of one of the user's sessions. Requires session:manage.
+// @Tags Sessions
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param sessionId path int true "Session ID"
+// @Success 204 "Signed out"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Session not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/sessions/{sessionId} [delete]
+func (h *SessionHandlers) RevokeUserSession(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionSessionManage)
+	if currentUser == nil {
+		return
+	}
+
+	userID, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	sessionID, err := parseIDParam(r, "sessionId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	session, err := h.sessionService.GetSession(r.Context(), userID, sessionID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get session", err, "session_id", sessionID)
+		respondError(w, http.StatusInternalServerError, "Failed to sign user out")
+		return
+	}
+	if session == nil {
+		respondError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	if session.RevokedAt == nil {
+		if err := h.sessionService.RevokeSession(r.Context(), session, currentUser.ID); err != nil {
+			h.logger.LogError(r.Context(), "Failed to revoke session", err, "session_id", sessionID)
+			respondError(w, http.StatusInternalServerError, "Failed to sign user out")
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
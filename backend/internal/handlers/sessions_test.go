@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/identity"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// newSessionTestHandlers sets up an admin (1), an employee (2) with a session, and a user (3) who
+// has never signed in
+func newSessionTestHandlers() (*SessionHandlers, *mocks.MockSessionRepository) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Auth0ID: "auth0|admin", Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, Auth0ID: "auth0|hal", Role: models.RoleEmployee, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, Role: models.RoleEmployee, IsActive: true})
+
+	sessionRepo := mocks.NewMockSessionRepository()
+	service := services.NewSessionService(sessionRepo, time.Hour)
+	_ = service.Touch(context.Background(), 2, &identity.Identity{Subject: "auth0|hal", TokenID: "laptop", IssuedAt: time.Now()}, "10.0.0.1", "Firefox")
+
+	return NewSessionHandlers(service, userRepo), sessionRepo
+}
+
+func TestSessionHandlers_GetUserSessions(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		targetID       string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{"admin lists employee sessions", &models.User{ID: 1, Role: models.RoleAdmin}, "2", http.StatusOK, 1},
+		{"employee lists own sessions", &models.User{ID: 2, Role: models.RoleEmployee}, "2", http.StatusOK, 1},
+		{"employee lists another user's sessions", &models.User{ID: 3, Role: models.RoleEmployee}, "2", http.StatusForbidden, 0},
+		{"supervisor lists another user's sessions", &models.User{ID: 4, Role: models.RoleSupervisor}, "2", http.StatusForbidden, 0},
+		{"missing user", &models.User{ID: 1, Role: models.RoleAdmin}, "99", http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newSessionTestHandlers()
+			req := httptest.NewRequest(http.MethodGet, "/api/users/"+tt.targetID+"/sessions", nil)
+			req = req.WithContext(ctxWithUserFrom(chiCtxWithID(req.Context(), "id", tt.targetID), tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.GetUserSessions(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var sessions []models.UserSession
+			if err := json.Unmarshal(rr.Body.Bytes(), &sessions); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(sessions) != tt.expectedCount {
+				t.Errorf("expected %d sessions, got %d", tt.expectedCount, len(sessions))
+			}
+		})
+	}
+}
+
+func TestSessionHandlers_GetUserLogins(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		targetID       string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{"admin gets employee sign-ins", &models.User{ID: 1, Role: models.RoleAdmin}, "2", http.StatusOK, 1},
+		{"employee gets own sign-ins", &models.User{ID: 2, Role: models.RoleEmployee}, "2", http.StatusOK, 1},
+		{"user never signed in", &models.User{ID: 1, Role: models.RoleAdmin}, "3", http.StatusOK, 0},
+		{"employee gets another user's sign-ins", &models.User{ID: 3, Role: models.RoleEmployee}, "2", http.StatusForbidden, 0},
+		{"missing user", &models.User{ID: 1, Role: models.RoleAdmin}, "99", http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newSessionTestHandlers()
+			req := httptest.NewRequest(http.MethodGet, "/api/users/"+tt.targetID+"/logins", nil)
+			req = req.WithContext(ctxWithUserFrom(chiCtxWithID(req.Context(), "id", tt.targetID), tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.GetUserLogins(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var activity models.UserLoginActivity
+			if err := json.Unmarshal(rr.Body.Bytes(), &activity); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(activity.Logins) != tt.expectedCount {
+				t.Errorf("expected %d sign-ins, got %d", tt.expectedCount, len(activity.Logins))
+			}
+		})
+	}
+}
+
+func TestSessionHandlers_RevokeUserSessions(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		targetID       string
+		expectedStatus int
+	}{
+		{"admin signs employee out", &models.User{ID: 1, Role: models.RoleAdmin}, "2", http.StatusNoContent},
+		{"employee signs self out", &models.User{ID: 2, Role: models.RoleEmployee}, "2", http.StatusForbidden},
+		{"user never signed in", &models.User{ID: 1, Role: models.RoleAdmin}, "3", http.StatusBadRequest},
+		{"missing user", &models.User{ID: 1, Role: models.RoleAdmin}, "99", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, sessionRepo := newSessionTestHandlers()
+			req := httptest.NewRequest(http.MethodDelete, "/api/users/"+tt.targetID+"/sessions", nil)
+			req = req.WithContext(ctxWithUserFrom(chiCtxWithID(req.Context(), "id", tt.targetID), tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.RevokeUserSessions(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus == http.StatusNoContent {
+				if len(sessionRepo.Revoked) != 1 || *sessionRepo.Revoked[0].Subject != "auth0|hal" {
+					t.Errorf("revoked = %+v, want the employee's subject revoked", sessionRepo.Revoked)
+				}
+				if sessionRepo.Sessions[1].RevokedAt == nil {
+					t.Error("expected the employee's session to be marked revoked")
+				}
+			}
+		})
+	}
+}
+
+func TestSessionHandlers_RevokeUserSession(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		sessionID      string
+		expectedStatus int
+	}{
+		{"admin revokes session", "2", "1", http.StatusNoContent},
+		{"session of another user", "3", "1", http.StatusNotFound},
+		{"missing session", "2", "99", http.StatusNotFound},
+		{"invalid session ID", "2", "abc", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, sessionRepo := newSessionTestHandlers()
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.userID)
+			rctx.URLParams.Add("sessionId", tt.sessionID)
+			req := httptest.NewRequest(http.MethodDelete, "/api/users/"+tt.userID+"/sessions/"+tt.sessionID, nil)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctxWithUserFrom(ctx, &models.User{ID: 1, Role: models.RoleAdmin}))
+			rr := httptest.NewRecorder()
+			h.RevokeUserSession(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus == http.StatusNoContent {
+				if len(sessionRepo.Revoked) != 1 || *sessionRepo.Revoked[0].TokenID != "laptop" {
+					t.Errorf("revoked = %+v, want the session's token revoked", sessionRepo.Revoked)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// SkillHandlers handles the skills catalog, users' skill levels, and skill searches
+type SkillHandlers struct {
+	skillRepo repository.SkillRepository
+	userRepo  repository.UserRepository
+	logger    *logger.Logger
+}
+
+// NewSkillHandlers creates a new skill handlers instance
+func NewSkillHandlers(skillRepo repository.SkillRepository, userRepo repository.UserRepository) *SkillHandlers {
+	return &SkillHandlers{
+		skillRepo: skillRepo,
+		userRepo:  userRepo,
+		logger:    logger.Default().WithComponent("skill-handlers"),
+	}
+}
+
+// GetSkills godoc
+// @Summary List skills
+// @Description Returns the skills catalog ordered by category and name
+// @Tags Skills
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Skill "Skills"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /skills [get]
+func (h *SkillHandlers) GetSkills(w http.ResponseWriter, r *http.Request) {
+	if requireAuth(w, r) == nil {
+		return
+	}
+
+	skills, err := h.skillRepo.GetAll(r.Context())
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get skills", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch skills")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, skills)
+}
+
+// CreateSkill godoc
+// @Summary Add a skill to the catalog
+// @Description Supervisors and admins can add skills people can then list on their profiles
+// @Tags Skills
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.SkillRequest true "Skill"
+// @Success 201 {object} models.Skill "Created skill"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 409 {object} map[string]interface{} "Skill already exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /skills [post]
+func (h *SkillHandlers) CreateSkill(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionSkillCreate) == nil {
+		return
+	}
+
+	var req models.SkillRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	existing, err := h.skillRepo.GetByName(r.Context(), req.Name)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to check skill name", err, "name", req.Name)
+		respondError(w, http.StatusInternalServerError, "Failed to create skill")
+		return
+	}
+	if existing != nil {
+		respondError(w, http.StatusConflict, "A skill with this name already exists")
+		return
+	}
+
+	skill, err := h.skillRepo.Create(r.Context(), &req)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to create skill", err, "name", req.Name)
+		respondError(w, http.StatusInternalServerError, "Failed to create skill")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, skill)
+}
+
+// UpdateSkill godoc
+// @Summary Rename a skill
+// @Description Changes a skill's name or category everywhere it's listed. Admin only.
+// @Tags Skills
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Skill ID"
+// @Param body body models.SkillRequest true "Skill"
+// @Success 200 {object} models.Skill "Updated skill"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Skill not found"
+// @Failure 409 {object} map[string]interface{} "Another skill has this name"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /skills/{id} [put]
+func (h *SkillHandlers) UpdateSkill(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionSkillManage) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid skill ID")
+		return
+	}
+
+	var req models.SkillRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	existing, err := h.skillRepo.GetByName(r.Context(), req.Name)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to check skill name", err, "name", req.Name)
+		respondError(w, http.StatusInternalServerError, "Failed to update skill")
+		return
+	}
+	if existing != nil && existing.ID != id {
+		respondError(w, http.StatusConflict, "A skill with this name already exists")
+		return
+	}
+
+	skill, err := h.skillRepo.Update(r.Context(), id, &req)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to update skill", err, "skill_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to update skill")
+		return
+	}
+	if skill == nil {
+		respondError(w, http.StatusNotFound, "Skill not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, skill)
+}
+
+// DeleteSkill godoc
+// @Summary Delete a skill
+// @Description Removes a skill from the catalog and from everyone's profile. Admin only.
+// @Tags Skills
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Skill ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Skill not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /skills/{id} [delete]
+func (h *SkillHandlers) DeleteSkill(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionSkillManage) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid skill ID")
+		return
+	}
+
+	skill, err := h.skillRepo.GetByID(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get skill", err, "skill_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to delete skill")
+		return
+	}
+	if skill == nil {
+		respondError(w, http.StatusNotFound, "Skill not found")
+		return
+	}
+
+	if err := h.skillRepo.Delete(r.Context(), id); err != nil {
+		h.logger.LogError(r.Context(), "Failed to delete skill", err, "skill_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to delete skill")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Skill deleted successfully"})
+}
+
+// FindExperts godoc
+// @Summary Find who knows a skill
+// @Description Returns active users with a skill whose name contains the query, exact matches and higher levels first
+// @Tags Skills
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Skill name or part of it"
+// @Param min_level query int false "Minimum level from 1 (beginner) to 4 (expert)" default(1)
+// @Success 200 {array} models.SkillExpert "Users who know the skill"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /skills/experts [get]
+func (h *SkillHandlers) FindExperts(w http.ResponseWriter, r *http.Request) {
+	if requireAuth(w, r) == nil {
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		respondError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	if len(query) > models.MaxSkillNameLength {
+		respondError(w, http.StatusBadRequest, "q is too long")
+		return
+	}
+
+	minLevel := models.SkillLevelBeginner
+	if v := r.URL.Query().Get("min_level"); v != "" {
+		level, err := strconv.Atoi(v)
+		if err != nil || !models.SkillLevel(level).IsValid() {
+			respondError(w, http.StatusBadRequest, "min_level must be between 1 (beginner) and 4 (expert)")
+			return
+		}
+		minLevel = models.SkillLevel(level)
+	}
+
+	experts, err := h.skillRepo.FindExperts(r.Context(), query, minLevel)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to find skill experts", err, "query", query)
+		respondError(w, http.StatusInternalServerError, "Failed to search skills")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, experts)
+}
+
+// GetUserSkills godoc
+// @Summary Get a user's skills
+// @Description Returns the skills on a user's profile, strongest first
+// @Tags Skills
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} models.UserSkill "User's skills"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/skills [get]
+func (h *SkillHandlers) GetUserSkills(w http.ResponseWriter, r *http.Request) {
+	if requireAuth(w, r) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if user, err := h.userRepo.GetByID(r.Context(), id); err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	skills, err := h.skillRepo.GetUserSkills(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get user skills", err, "user_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch skills")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, skills)
+}
+
+// SetUserSkills godoc
+// @Summary Set a user's skills
+// @Description Replaces every skill on a user's profile. Users can set their own; supervisors can set their direct reports' and admins anyone's.
+// @Tags Skills
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param body body models.SetUserSkillsRequest true "Skills and levels"
+// @Success 200 {array} models.UserSkill "User's skills"
+// @Failure 400 {object} map[string]interface{} "Invalid request or unknown skill"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/skills [put]
+func (h *SkillHandlers) SetUserSkills(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	targetUser, err := h.userRepo.GetByID(r.Context(), id)
+	if err != nil || targetUser == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if currentUser.ID != id && !currentUser.CanManage(targetUser) {
+		respondError(w, http.StatusForbidden, "Forbidden: can only set your own or your direct reports' skills")
+		return
+	}
+
+	var req models.SetUserSkillsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	catalog, err := h.skillRepo.GetAll(r.Context())
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get skills", err)
+		respondError(w, http.StatusInternalServerError, "Failed to save skills")
+		return
+	}
+	known := make(map[int64]bool, len(catalog))
+	for _, skill := range catalog {
+		known[skill.ID] = true
+	}
+	for _, s := range req.Skills {
+		if !known[s.SkillID] {
+			respondError(w, http.StatusBadRequest, "Unknown skill: "+strconv.FormatInt(s.SkillID, 10))
+			return
+		}
+	}
+
+	if err := h.skillRepo.SetUserSkills(r.Context(), id, req.Skills); err != nil {
+		h.logger.LogError(r.Context(), "Failed to set user skills", err, "user_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to save skills")
+		return
+	}
+
+	skills, err := h.skillRepo.GetUserSkills(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get user skills", err, "user_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch skills")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, skills)
+}
+
+// GetSquadSkillCoverage godoc
+// @Summary Get a squad's skills coverage
+// @Description Reports how the squad's active members cover every catalog skill, weakest coverage first, for planning work. Supervisors and admins only.
+// @Tags Skills
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Squad ID"
+// @Success 200 {object} models.SquadSkillCoverage "Skills coverage"
+// @Failure 400 {object} map[string]interface{} "Invalid squad ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Squad not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /squads/{id}/skills [get]
+func (h *SkillHandlers) GetSquadSkillCoverage(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionTeamView) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid squad ID")
+		return
+	}
+
+	coverage, err := h.skillRepo.GetSquadCoverage(r.Context(), id)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get squad skill coverage", err, "squad_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch skills coverage")
+		return
+	}
+	if coverage == nil {
+		respondError(w, http.StatusNotFound, "Squad not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, coverage)
+}
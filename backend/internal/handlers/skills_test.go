@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+// newSkillTestHandlers sets up a supervisor (2) with two reports (3 and 4) in squad 1, an
+// unrelated employee (5), and an admin (1), with Kubernetes and Go in the catalog
+func newSkillTestHandlers() (*SkillHandlers, *mocks.MockSkillRepository, *mocks.MockUserRepository) {
+	supervisorID := int64(2)
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, FirstName: "Ada", LastName: "Admin", Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, FirstName: "Sam", LastName: "Super", Role: models.RoleSupervisor, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, FirstName: "Kim", LastName: "Kube", Role: models.RoleEmployee, SupervisorID: &supervisorID, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 4, FirstName: "Gil", LastName: "Gopher", Role: models.RoleEmployee, SupervisorID: &supervisorID, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 5, FirstName: "Eve", LastName: "Else", Role: models.RoleEmployee, IsActive: true})
+
+	skillRepo := mocks.NewMockSkillRepository()
+	skillRepo.Users = userRepo.Users
+	skillRepo.Squads[1] = &models.Squad{ID: 1, Name: "Platform"}
+	skillRepo.SquadMembers[1] = []int64{3, 4}
+	_, _ = skillRepo.Create(context.Background(), &models.SkillRequest{Name: "Kubernetes", Category: "Infrastructure"})
+	_, _ = skillRepo.Create(context.Background(), &models.SkillRequest{Name: "Go", Category: "Languages"})
+
+	return NewSkillHandlers(skillRepo, userRepo), skillRepo, userRepo
+}
+
+func TestSkillHandlers_CreateSkill(t *testing.T) {
+	tests := []struct {
+		name           string
+		role           models.Role
+		body           string
+		expectedStatus int
+	}{
+		{"supervisor adds skill", models.RoleSupervisor, `{"name":"Terraform","category":"Infrastructure"}`, http.StatusCreated},
+		{"employee forbidden", models.RoleEmployee, `{"name":"Terraform"}`, http.StatusForbidden},
+		{"duplicate name ignoring case", models.RoleAdmin, `{"name":"kubernetes"}`, http.StatusConflict},
+		{"missing name", models.RoleAdmin, `{"category":"Infrastructure"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _ := newSkillTestHandlers()
+			req := httptest.NewRequest(http.MethodPost, "/api/skills", strings.NewReader(tt.body))
+			req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 9, Role: tt.role}))
+			rr := httptest.NewRecorder()
+			h.CreateSkill(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestSkillHandlers_SetUserSkills(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUserID  int64
+		targetID       string
+		body           string
+		expectedStatus int
+	}{
+		{"own skills", 5, "5", `{"skills":[{"skill_id":1,"level":2}]}`, http.StatusOK},
+		{"supervisor sets report's skills", 2, "3", `{"skills":[{"skill_id":1,"level":4}]}`, http.StatusOK},
+		{"admin sets anyone's skills", 1, "5", `{"skills":[]}`, http.StatusOK},
+		{"employee sets someone else's skills", 5, "3", `{"skills":[{"skill_id":1,"level":2}]}`, http.StatusForbidden},
+		{"supervisor sets non-report's skills", 2, "5", `{"skills":[{"skill_id":1,"level":2}]}`, http.StatusForbidden},
+		{"unknown skill", 5, "5", `{"skills":[{"skill_id":99,"level":2}]}`, http.StatusBadRequest},
+		{"level out of range", 5, "5", `{"skills":[{"skill_id":1,"level":5}]}`, http.StatusBadRequest},
+		{"duplicate skill", 5, "5", `{"skills":[{"skill_id":1,"level":2},{"skill_id":1,"level":3}]}`, http.StatusBadRequest},
+		{"missing user", 1, "99", `{"skills":[]}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, userRepo := newSkillTestHandlers()
+			req := httptest.NewRequest(http.MethodPut, "/api/users/"+tt.targetID+"/skills", strings.NewReader(tt.body))
+			ctx := chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[tt.currentUserID]), "id", tt.targetID)
+			rr := httptest.NewRecorder()
+			h.SetUserSkills(rr, req.WithContext(ctx))
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestSkillHandlers_FindExperts(t *testing.T) {
+	h, skillRepo, userRepo := newSkillTestHandlers()
+	skillRepo.Levels[3] = map[int64]models.SkillLevel{1: models.SkillLevelExpert}
+	skillRepo.Levels[4] = map[int64]models.SkillLevel{1: models.SkillLevelBeginner, 2: models.SkillLevelExpert}
+	skillRepo.Levels[5] = map[int64]models.SkillLevel{1: models.SkillLevelAdvanced}
+	userRepo.Users[5].IsActive = false
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedUsers  []int64
+	}{
+		{"partial name", "?q=kube", http.StatusOK, []int64{3, 4}},
+		{"minimum level", "?q=kubernetes&min_level=3", http.StatusOK, []int64{3}},
+		{"no match", "?q=rust", http.StatusOK, nil},
+		{"missing query", "", http.StatusBadRequest, nil},
+		{"invalid level", "?q=go&min_level=9", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/skills/experts"+tt.query, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), userRepo.Users[2]))
+			rr := httptest.NewRecorder()
+			h.FindExperts(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+			var experts []models.SkillExpert
+			if err := json.Unmarshal(rr.Body.Bytes(), &experts); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			var ids []int64
+			for _, e := range experts {
+				ids = append(ids, e.UserID)
+			}
+			if len(ids) != len(tt.expectedUsers) {
+				t.Fatalf("experts = %v, want %v", ids, tt.expectedUsers)
+			}
+			for i := range ids {
+				if ids[i] != tt.expectedUsers[i] {
+					t.Errorf("experts = %v, want %v", ids, tt.expectedUsers)
+				}
+			}
+		})
+	}
+}
+
+func TestSkillHandlers_GetSquadSkillCoverage(t *testing.T) {
+	h, skillRepo, userRepo := newSkillTestHandlers()
+	skillRepo.Levels[3] = map[int64]models.SkillLevel{1: models.SkillLevelExpert}
+	skillRepo.Levels[4] = map[int64]models.SkillLevel{1: models.SkillLevelBeginner}
+
+	get := func(userID int64, squadID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/squads/"+squadID+"/skills", nil)
+		ctx := chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[userID]), "id", squadID)
+		rr := httptest.NewRecorder()
+		h.GetSquadSkillCoverage(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	if rr := get(3, "1"); rr.Code != http.StatusForbidden {
+		t.Errorf("employee: expected status 403, got %d", rr.Code)
+	}
+	if rr := get(2, "99"); rr.Code != http.StatusNotFound {
+		t.Errorf("missing squad: expected status 404, got %d", rr.Code)
+	}
+
+	rr := get(2, "1")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var coverage models.SquadSkillCoverage
+	if err := json.Unmarshal(rr.Body.Bytes(), &coverage); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if coverage.MemberCount != 2 || len(coverage.Skills) != 2 {
+		t.Fatalf("coverage = %+v, want 2 members and 2 skills", coverage)
+	}
+	// The gap comes first
+	if gap := coverage.Skills[0]; gap.Name != "Go" || gap.Members != 0 || gap.TopLevel != 0 {
+		t.Errorf("first skill = %+v, want uncovered Go", gap)
+	}
+	if k8s := coverage.Skills[1]; k8s.Members != 2 || k8s.Advanced != 1 || k8s.TopLevel != models.SkillLevelExpert {
+		t.Errorf("Kubernetes coverage = %+v, want 2 members, 1 advanced, top level expert", k8s)
+	}
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/oauth"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+	"github.com/smith-dallin/manager-dashboard/internal/slack"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// maxSlackInteractionBytes caps the size of an interactive request from Slack
+const maxSlackInteractionBytes = 1 << 20
+
+// TimeOffReviewer reviews time off requests on behalf of a user, as TimeOffHandlers.ReviewAs does
+type TimeOffReviewer interface {
+	ReviewAs(ctx context.Context, reviewer *models.User, id int64, req *models.ReviewTimeOffRequestInput) (*models.TimeOffRequest, error)
+}
+
+// SlackHandlers serves organizations' Slack app installations and the app's Approve and Reject
+// buttons
+type SlackHandlers struct {
+	userRepo      repository.UserRepository
+	orgSlackRepo  repository.OrgSlackRepository
+	slackApp      *services.SlackAppService
+	oauthService  *slack.OAuthService
+	stateStore    oauth.StateStore
+	stateBinder   OAuthStateBinder
+	signingSecret string
+	reviewer      TimeOffReviewer
+	frontendURL   string
+	logger        *logger.Logger
+}
+
+// NewSlackHandlers creates Slack handlers. oauthService is nil when the Slack app isn't configured.
+func NewSlackHandlers(userRepo repository.UserRepository, orgSlackRepo repository.OrgSlackRepository, slackApp *services.SlackAppService, oauthService *slack.OAuthService, stateStore oauth.StateStore, frontendURL string, log *logger.Logger) *SlackHandlers {
+	return &SlackHandlers{
+		userRepo:     userRepo,
+		orgSlackRepo: orgSlackRepo,
+		slackApp:     slackApp,
+		oauthService: oauthService,
+		stateStore:   stateStore,
+		frontendURL:  frontendURL,
+		logger:       log.WithComponent("slack_handlers"),
+	}
+}
+
+// WithOAuthStateBinding ties each OAuth flow to the browser that started it, rejecting callbacks
+// from any other
+func (h *SlackHandlers) WithOAuthStateBinding(binder OAuthStateBinder) *SlackHandlers {
+	h.stateBinder = binder
+	return h
+}
+
+// WithInteractivity accepts button clicks signed with the app's signing secret and reviews time
+// off through reviewer. Clicks are rejected without a secret.
+func (h *SlackHandlers) WithInteractivity(signingSecret string, reviewer TimeOffReviewer) *SlackHandlers {
+	h.signingSecret = signingSecret
+	h.reviewer = reviewer
+	return h
+}
+
+// GetSlackSettings returns the organization's Slack connection status
+// Available to all authenticated users to check connection status
+func (h *SlackHandlers) GetSlackSettings(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	settings, err := h.orgSlackRepo.Get(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get Slack settings")
+		return
+	}
+
+	response := map[string]interface{}{
+		"oauth_enabled":         h.oauthService != nil,
+		"interactivity_enabled": h.signingSecret != "" && h.reviewer != nil,
+		"org_configured":        settings != nil,
+		"can_configure":         authz.Can(currentUser, authz.ActionIntegrationManage, nil),
+	}
+	if settings != nil {
+		response["team_id"] = settings.TeamID
+		response["team_name"] = settings.TeamName
+		response["configured_by_id"] = settings.ConfiguredByID
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// DisconnectSlack removes the organization's Slack connection (admin only). Messages already
+// queued fail without being sent.
+func (h *SlackHandlers) DisconnectSlack(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionIntegrationManage) == nil {
+		return
+	}
+
+	if err := h.orgSlackRepo.Delete(r.Context()); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to disconnect Slack")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetOAuthAuthorizeURL returns the URL to redirect the user to for installing the Slack app
+// Only admins can connect the organization's workspace
+func (h *SlackHandlers) GetOAuthAuthorizeURL(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionIntegrationManage)
+	if currentUser == nil {
+		return
+	}
+
+	if h.oauthService == nil {
+		respondError(w, http.StatusServiceUnavailable, "Slack OAuth is not configured")
+		return
+	}
+
+	state, err := h.stateStore.Create(r.Context(), currentUser.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to generate state")
+		return
+	}
+	if h.stateBinder != nil {
+		h.stateBinder.BindState(w, state)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"authorization_url": h.oauthService.GetAuthorizationURL(state),
+	})
+}
+
+// HandleOAuthCallback handles the OAuth callback from Slack and saves the bot token as the
+// organization's Slack connection
+func (h *SlackHandlers) HandleOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	redirectURL := h.frontendURL + "/settings"
+
+	redirectWithError := func(errMsg string) {
+		http.Redirect(w, r, redirectURL+"?slack_error="+errMsg, http.StatusFound)
+	}
+
+	params, errMsg := validateOAuthCallback(r.Context(), r, h.stateStore, h.stateBinder)
+	if errMsg != "" {
+		redirectWithError(errMsg)
+		return
+	}
+
+	if h.oauthService == nil {
+		redirectWithError("oauth_not_configured")
+		return
+	}
+
+	// Slack calls back without the admin's token, so connect Slack for the organization of the
+	// admin who started the flow
+	ctx := r.Context()
+	admin, err := h.userRepo.GetByID(ctx, params.userID)
+	if err != nil || admin == nil {
+		redirectWithError("state_validation_failed")
+		return
+	}
+	ctx = tenant.WithOrgID(ctx, admin.OrgID)
+
+	tokenResp, err := h.oauthService.ExchangeCode(params.code)
+	if err != nil {
+		h.logger.LogError(ctx, "Slack token exchange failed", err)
+		redirectWithError("token_exchange_failed")
+		return
+	}
+
+	settings := &models.OrgSlackSettings{
+		BotAccessToken: tokenResp.AccessToken,
+		TeamID:         tokenResp.Team.ID,
+		TeamName:       tokenResp.Team.Name,
+		BotUserID:      tokenResp.BotUserID,
+		ConfiguredByID: &params.userID,
+	}
+	if err := h.orgSlackRepo.Save(ctx, settings); err != nil {
+		h.logger.LogError(ctx, "Failed to save Slack settings", err)
+		redirectWithError("save_failed")
+		return
+	}
+
+	http.Redirect(w, r, redirectURL+"?slack_connected=true", http.StatusFound)
+}
+
+// HandleInteraction handles Approve and Reject clicks in the app's time off messages. Slack signs
+// the request; the clicker is matched to a dashboard user by their Slack email and reviews the
+// request with that user's permissions. The outcome, or why the review failed, replaces the
+// message so its buttons can't be clicked twice.
+func (h *SlackHandlers) HandleInteraction(w http.ResponseWriter, r *http.Request) {
+	if h.signingSecret == "" || h.reviewer == nil {
+		respondError(w, http.StatusNotFound, "Slack interactivity is not configured")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSlackInteractionBytes))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "Interaction payload is too large")
+		return
+	}
+	if !slack.VerifyRequestSignature(h.signingSecret, body, r.Header.Get(slack.TimestampHeader), r.Header.Get(slack.SignatureHeader), time.Now()) {
+		respondError(w, http.StatusUnauthorized, "Invalid request signature")
+		return
+	}
+
+	interaction, err := slack.ParseInteraction(body)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid interaction payload")
+		return
+	}
+
+	var status models.TimeOffStatus
+	switch interaction.ActionID {
+	case slack.ActionApproveTimeOff:
+		status = models.TimeOffStatusApproved
+	case slack.ActionRejectTimeOff:
+		status = models.TimeOffStatusRejected
+	default:
+		// Not one of our buttons; acknowledge it so Slack doesn't show an error
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	settings, err := h.orgSlackRepo.GetByTeamID(r.Context(), interaction.TeamID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get Slack settings")
+		return
+	}
+	if settings == nil {
+		respondError(w, http.StatusNotFound, "Slack is not connected for this workspace")
+		return
+	}
+	ctx := tenant.WithOrgID(r.Context(), settings.OrgID)
+	client := h.slackApp.Client(settings)
+
+	reply := h.reviewFromSlack(ctx, client, interaction, status)
+	if err := client.RespondToAction(ctx, interaction.ResponseURL, slack.Message{Text: reply}); err != nil {
+		h.logger.LogError(ctx, "Failed to respond to Slack interaction", err)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// reviewFromSlack reviews the request a button was clicked for as the dashboard user with the
+// clicker's email, and returns the text to replace the message with
+func (h *SlackHandlers) reviewFromSlack(ctx context.Context, client *slack.Client, interaction *slack.Interaction, status models.TimeOffStatus) string {
+	id, err := strconv.ParseInt(interaction.Value, 10, 64)
+	if err != nil {
+		return "This time off request couldn't be found."
+	}
+
+	email, err := client.GetUserEmail(ctx, interaction.UserID)
+	if err != nil {
+		h.logger.LogError(ctx, "Failed to get Slack user", err, "slack_user_id", interaction.UserID)
+		return "Your Slack account couldn't be checked. Review this request in the dashboard instead."
+	}
+	reviewer, err := h.userRepo.GetByEmail(ctx, email)
+	if err != nil || reviewer == nil || !reviewer.IsActive {
+		return "Your Slack email doesn't match an active dashboard user, so you can't review requests from Slack."
+	}
+
+	updated, err := h.reviewer.ReviewAs(ctx, reviewer, id, &models.ReviewTimeOffRequestInput{Status: status})
+	if err != nil {
+		if apperrors.GetHTTPStatus(err) >= http.StatusInternalServerError {
+			h.logger.LogError(ctx, "Failed to review time off from Slack", err, "time_off_request_id", id)
+		}
+		return "This request couldn't be reviewed: " + apperrors.GetUserMessage(err)
+	}
+
+	if updated == nil || updated.User == nil {
+		return fmt.Sprintf("You %s this time off request.", status)
+	}
+	return fmt.Sprintf("You %s %s %s's %s request for %s to %s.", updated.Status,
+		updated.User.FirstName, updated.User.LastName, strings.ReplaceAll(string(updated.RequestType), "_", " "),
+		updated.StartDate.Format("Jan 2, 2006"), updated.EndDate.Format("Jan 2, 2006"))
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// slackInteractionRequest builds a signed click on a time off review button
+func slackInteractionRequest(secret, slackUserID, actionID string, requestID int64, responseURL string) *http.Request {
+	payload := fmt.Sprintf(`{"type":"block_actions","team":{"id":"T1"},"user":{"id":%q},"response_url":%q,"actions":[{"action_id":%q,"value":"%d"}]}`,
+		slackUserID, responseURL, actionID, requestID)
+	body := url.Values{"payload": {payload}}.Encode()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/slack/interactions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestSlackHandlers_HandleInteraction(t *testing.T) {
+	var replies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users.info":
+			emails := map[string]string{"USUP": "grace@example.com", "UPEER": "alan@example.com", "UNEW": "new@example.com"}
+			_, _ = fmt.Fprintf(w, `{"ok":true,"user":{"profile":{"email":%q}}}`, emails[r.URL.Query().Get("user")])
+		case "/respond":
+			var reply struct {
+				ReplaceOriginal bool   `json:"replace_original"`
+				Text            string `json:"text"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&reply)
+			if !reply.ReplaceOriginal {
+				t.Error("reply doesn't replace the message")
+			}
+			replies = append(replies, reply.Text)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	supervisorID := int64(1)
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.Users[1] = &models.User{ID: 1, Role: models.RoleSupervisor, FirstName: "Grace", Email: "grace@example.com", IsActive: true}
+	userRepo.Users[2] = &models.User{ID: 2, Role: models.RoleEmployee, FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", IsActive: true, SupervisorID: &supervisorID}
+	userRepo.Users[3] = &models.User{ID: 3, Role: models.RoleSupervisor, FirstName: "Alan", Email: "alan@example.com", IsActive: true}
+	for _, user := range userRepo.Users {
+		userRepo.ByEmail[user.Email] = user
+	}
+
+	start := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	timeOffRepo := mocks.NewMockTimeOffRepository()
+	timeOffRepo.Requests[9] = &models.TimeOffRequest{ID: 9, UserID: 2, User: userRepo.Users[2], RequestType: "vacation", Status: models.TimeOffStatusPending, StartDate: start, EndDate: start.AddDate(0, 0, 4)}
+
+	slackRepo := mocks.NewMockOrgSlackRepository()
+	slackRepo.Settings[1] = &models.OrgSlackSettings{OrgID: 1, BotAccessToken: "xoxb-1", TeamID: "T1"}
+	outbox := mocks.NewMockOutboxRepository()
+	slackApp := services.NewSlackAppService(slackRepo, userRepo, outbox).WithAPIURL(server.URL)
+
+	timeOff := NewTimeOffHandlers(timeOffRepo, userRepo).WithSlack(slackApp)
+	h := NewSlackHandlers(userRepo, slackRepo, slackApp, nil, nil, "", logger.Default()).
+		WithInteractivity("s3cret", timeOff)
+
+	click := func(req *http.Request) int {
+		rr := httptest.NewRecorder()
+		h.HandleInteraction(rr, req)
+		return rr.Code
+	}
+
+	// Requests Slack didn't sign are rejected before anything is looked up
+	if code := click(slackInteractionRequest("wrong", "USUP", "approve_time_off", 9, server.URL+"/respond")); code != http.StatusUnauthorized {
+		t.Errorf("unsigned click status = %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	// Another supervisor can't review someone else's report, and neither can strangers
+	for _, slackUserID := range []string{"UPEER", "UNEW"} {
+		if code := click(slackInteractionRequest("s3cret", slackUserID, "approve_time_off", 9, server.URL+"/respond")); code != http.StatusOK {
+			t.Errorf("click by %s status = %d, want %d", slackUserID, code, http.StatusOK)
+		}
+	}
+	if timeOffRepo.Requests[9].Status != models.TimeOffStatusPending {
+		t.Fatalf("request status = %s after unauthorized clicks, want pending", timeOffRepo.Requests[9].Status)
+	}
+
+	if code := click(slackInteractionRequest("s3cret", "USUP", "reject_time_off", 9, server.URL+"/respond")); code != http.StatusOK {
+		t.Errorf("supervisor click status = %d, want %d", code, http.StatusOK)
+	}
+	if got := timeOffRepo.Requests[9]; got.Status != models.TimeOffStatusRejected || got.ReviewerID == nil || *got.ReviewerID != 1 {
+		t.Errorf("request = %+v, want rejected by the supervisor", got)
+	}
+
+	if len(replies) != 3 {
+		t.Fatalf("replies = %q, want 3", replies)
+	}
+	if !strings.Contains(replies[0], "can only review direct reports") || !strings.Contains(replies[1], "doesn't match an active dashboard user") {
+		t.Errorf("refusal replies = %q", replies[:2])
+	}
+	if want := "You rejected Ada Lovelace's vacation request for Jul 1, 2024 to Jul 5, 2024."; replies[2] != want {
+		t.Errorf("reply = %q, want %q", replies[2], want)
+	}
+	// The requester hears about the decision in Slack too
+	if len(outbox.Messages) != 1 {
+		t.Errorf("queued %d Slack messages, want the requester's", len(outbox.Messages))
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/middleware"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// ReassignSupervisor godoc
+// @Summary Change a user's supervisor
+// @Description Moves a user to another supervisor, or leaves them without one if supervisor_id is null. The supervisor must be an active supervisor or admin and can't already report to the user, directly or indirectly. Moves across departments are allowed but come back with a warning. The change is recorded in the user's history and both their previous and new supervisors are notified. Supervisors can move their direct reports; admins anyone.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body models.ReassignSupervisorRequest true "New supervisor"
+// @Success 200 {object} models.SupervisorReassignmentResponse "Updated user and any warnings"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID or supervisor"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 409 {object} map[string]interface{} "Supervisor already reports to the user"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/supervisor [post]
+func (h *Handlers) ReassignSupervisor(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	targetUser, err := h.userRepo.GetByID(r.Context(), id)
+	if err != nil {
+		respondRepositoryError(w, r, err, "User")
+		return
+	}
+	if targetUser == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	middleware.SetAuditBefore(r.Context(), targetUser)
+
+	// Employees can't choose their own supervisor, and supervisors can only move their direct reports
+	if (targetUser.ID == currentUser.ID && !authz.CanAll(currentUser, authz.ActionUserUpdate)) ||
+		!authz.Can(currentUser, authz.ActionUserUpdate, authz.UserResource(targetUser)) {
+		respondError(w, http.StatusForbidden, "Forbidden: can only change your direct reports' supervisor")
+		return
+	}
+
+	var req models.ReassignSupervisorRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	result, err := h.userService.ReassignSupervisor(r.Context(), id, req.SupervisorID, currentUser.ID)
+	switch {
+	case errors.Is(err, services.ErrInvalidSupervisor):
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, services.ErrSupervisorCycle):
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		respondRepositoryError(w, r, err, "User")
+		return
+	}
+
+	h.InvalidateUserCache(r.Context())
+	h.notifications.NotifySupervisorChanged(r.Context(), result.User, result.PreviousSupervisorID, currentUser)
+
+	h.logger.Audit(r.Context(), logger.AuditEvent{
+		Action:     logger.AuditActionUpdate,
+		Resource:   "user_supervisor",
+		ResourceID: fmt.Sprintf("%d", id),
+		ActorID:    currentUser.ID,
+		ActorEmail: currentUser.Email,
+		Result:     logger.AuditResultSuccess,
+		Details: map[string]any{
+			"previous_supervisor_id": result.PreviousSupervisorID,
+			"supervisor_id":          req.SupervisorID,
+			"warnings":               result.Warnings,
+		},
+	})
+
+	respondJSON(w, http.StatusOK, models.SupervisorReassignmentResponse{
+		User:                 result.User.ToUserResponse(),
+		PreviousSupervisorID: result.PreviousSupervisorID,
+		Warnings:             result.Warnings,
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestReassignSupervisor(t *testing.T) {
+	id := func(v int64) *int64 { return &v }
+
+	setup := func() (*Handlers, *mocks.MockUserRepository, *mocks.MockNotificationRepository) {
+		userRepo := mocks.NewMockUserRepository()
+		userRepo.AddUser(&models.User{ID: 1, Role: models.RoleAdmin, IsActive: true})
+		userRepo.AddUser(&models.User{ID: 2, Role: models.RoleSupervisor, Department: "Engineering", SupervisorID: id(1), IsActive: true})
+		userRepo.AddUser(&models.User{ID: 3, Role: models.RoleSupervisor, Department: "Sales", SupervisorID: id(2), IsActive: true})
+		userRepo.AddUser(&models.User{ID: 4, Role: models.RoleEmployee, Department: "Engineering", SupervisorID: id(2), IsActive: true})
+		notificationRepo := mocks.NewMockNotificationRepository()
+		h := New(userRepo, mocks.NewMockSquadRepository(), nil).
+			WithNotifications(services.NewNotificationService(notificationRepo, userRepo, nil))
+		return h, userRepo, notificationRepo
+	}
+
+	do := func(h *Handlers, userRepo *mocks.MockUserRepository, currentUserID int64, userID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/users/"+userID+"/supervisor", strings.NewReader(body))
+		ctx := chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[currentUserID]), "id", userID)
+		rr := httptest.NewRecorder()
+		h.ReassignSupervisor(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	tests := []struct {
+		name           string
+		currentUserID  int64
+		userID         string
+		body           string
+		wantCode       int
+		wantSupervisor *int64
+		wantNotified   []int64
+	}{
+		{name: "supervisor moves a direct report", currentUserID: 2, userID: "4", body: `{"supervisor_id":3}`, wantCode: http.StatusOK, wantSupervisor: id(3), wantNotified: []int64{3}},
+		{name: "admin moves someone", currentUserID: 1, userID: "4", body: `{"supervisor_id":1}`, wantCode: http.StatusOK, wantSupervisor: id(1), wantNotified: []int64{2}},
+		{name: "admin clears a supervisor", currentUserID: 1, userID: "4", body: `{"supervisor_id":null}`, wantCode: http.StatusOK, wantNotified: []int64{2}},
+		{name: "employee moves themselves", currentUserID: 4, userID: "4", body: `{"supervisor_id":3}`, wantCode: http.StatusForbidden, wantSupervisor: id(2)},
+		{name: "supervisor moves someone else's report", currentUserID: 3, userID: "4", body: `{"supervisor_id":3}`, wantCode: http.StatusForbidden, wantSupervisor: id(2)},
+		{name: "to an employee", currentUserID: 1, userID: "3", body: `{"supervisor_id":4}`, wantCode: http.StatusBadRequest, wantSupervisor: id(2)},
+		{name: "to their own report", currentUserID: 1, userID: "2", body: `{"supervisor_id":3}`, wantCode: http.StatusConflict, wantSupervisor: id(1)},
+		{name: "missing user", currentUserID: 1, userID: "99", body: `{"supervisor_id":1}`, wantCode: http.StatusNotFound},
+		{name: "invalid ID", currentUserID: 1, userID: "abc", body: `{"supervisor_id":1}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, userRepo, notificationRepo := setup()
+			rr := do(h, userRepo, tt.currentUserID, tt.userID, tt.body)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("ReassignSupervisor() status = %d, want %d: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if rr.Code == http.StatusOK {
+				var resp models.SupervisorReassignmentResponse
+				if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+					t.Fatal(err)
+				}
+				if resp.User == nil || resp.PreviousSupervisorID == nil || *resp.PreviousSupervisorID != 2 {
+					t.Errorf("ReassignSupervisor() response = %+v, want the user and previous supervisor 2", resp)
+				}
+			}
+
+			userID, _ := strconv.ParseInt(tt.userID, 10, 64)
+			if user, ok := userRepo.Users[userID]; ok {
+				got := user.SupervisorID
+				if (got == nil) != (tt.wantSupervisor == nil) || (got != nil && *got != *tt.wantSupervisor) {
+					t.Errorf("supervisor = %v, want %v", got, tt.wantSupervisor)
+				}
+			}
+
+			var notified []int64
+			for _, n := range notificationRepo.Notifications {
+				notified = append(notified, n.UserID)
+			}
+			if !slices.Equal(notified, tt.wantNotified) {
+				t.Errorf("notified %v, want %v", notified, tt.wantNotified)
+			}
+		})
+	}
+
+	t.Run("cross-department warning", func(t *testing.T) {
+		h, userRepo, _ := setup()
+		rr := do(h, userRepo, 2, "4", `{"supervisor_id":3}`)
+		if !strings.Contains(rr.Body.String(), "is in Sales") {
+			t.Errorf("ReassignSupervisor() = %s, want a department warning", rr.Body.String())
+		}
+	})
+
+	t.Run("update user", func(t *testing.T) {
+		h, userRepo, _ := setup()
+		update := func(body string) int {
+			req := httptest.NewRequest(http.MethodPut, "/api/users/4", strings.NewReader(body))
+			ctx := chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[1]), "id", "4")
+			rr := httptest.NewRecorder()
+			h.UpdateUser(rr, req.WithContext(ctx))
+			return rr.Code
+		}
+		if code := update(`{"supervisor_id":3}`); code != http.StatusBadRequest {
+			t.Errorf("UpdateUser() changing supervisor status = %d, want %d", code, http.StatusBadRequest)
+		}
+		if code := update(`{"supervisor_id":2,"title":"Engineer"}`); code != http.StatusOK {
+			t.Errorf("UpdateUser() with unchanged supervisor status = %d, want %d", code, http.StatusOK)
+		}
+		if got := *userRepo.Users[4].SupervisorID; got != 2 {
+			t.Errorf("supervisor = %d after UpdateUser, want 2", got)
+		}
+	})
+}
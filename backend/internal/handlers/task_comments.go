@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// requireTaskViewer loads the task from the URL and verifies the user can see it
+func (h *CalendarHandlers) requireTaskViewer(w http.ResponseWriter, r *http.Request, user *models.User) *models.Task {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid task ID")
+		return nil
+	}
+
+	task, err := h.taskRepo.GetByID(r.Context(), id)
+	if err != nil || task == nil {
+		respondError(w, http.StatusNotFound, "Task not found")
+		return nil
+	}
+
+	if !h.canViewTask(user, task) && !h.leadsTaskSquad(r.Context(), user, task) {
+		respondError(w, http.StatusForbidden, "Forbidden: you don't have permission to view this task")
+		return nil
+	}
+
+	return task
+}
+
+// loadTaskComment fetches the comment from the URL and checks it belongs to the task
+func (h *CalendarHandlers) loadTaskComment(w http.ResponseWriter, r *http.Request, task *models.Task) *models.TaskComment {
+	commentID, err := parseIDParam(r, "commentId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid comment ID")
+		return nil
+	}
+
+	comment, err := h.commentRepo.GetByID(r.Context(), commentID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch comment")
+		return nil
+	}
+	if comment == nil || comment.TaskID != task.ID {
+		respondError(w, http.StatusNotFound, "Comment not found")
+		return nil
+	}
+
+	return comment
+}
+
+// ListTaskComments returns a task's discussion thread, oldest first
+func (h *CalendarHandlers) ListTaskComments(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	task := h.requireTaskViewer(w, r, currentUser)
+	if task == nil {
+		return
+	}
+
+	comments, err := h.commentRepo.ListByTask(r.Context(), task.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch comments")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, comments)
+}
+
+// CreateTaskComment posts a comment on a task; anyone who can see the task may comment
+func (h *CalendarHandlers) CreateTaskComment(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	task := h.requireTaskViewer(w, r, currentUser)
+	if task == nil {
+		return
+	}
+
+	var req models.TaskCommentRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	comment, err := h.commentRepo.Create(r.Context(), task.ID, currentUser.ID, req.Body)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to post comment")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, comment)
+}
+
+// UpdateTaskComment edits a comment; only its author may edit it
+func (h *CalendarHandlers) UpdateTaskComment(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	task := h.requireTaskViewer(w, r, currentUser)
+	if task == nil {
+		return
+	}
+
+	comment := h.loadTaskComment(w, r, task)
+	if comment == nil {
+		return
+	}
+
+	if comment.AuthorID != currentUser.ID {
+		respondError(w, http.StatusForbidden, "Forbidden: only the author can edit this comment")
+		return
+	}
+
+	var req models.TaskCommentRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	updated, err := h.commentRepo.Update(r.Context(), comment.ID, req.Body)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update comment")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteTaskComment removes a comment; the author, the task creator, or an admin may delete it
+func (h *CalendarHandlers) DeleteTaskComment(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	task := h.requireTaskViewer(w, r, currentUser)
+	if task == nil {
+		return
+	}
+
+	comment := h.loadTaskComment(w, r, task)
+	if comment == nil {
+		return
+	}
+
+	if comment.AuthorID != currentUser.ID && !authz.Can(currentUser, authz.ActionTaskManage, authz.TaskResource(task)) {
+		respondError(w, http.StatusForbidden, "Forbidden: only the author or task creator can delete this comment")
+		return
+	}
+
+	if err := h.commentRepo.Delete(r.Context(), comment.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to delete comment")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTaskActivity returns a task's status changes, oldest first
+func (h *CalendarHandlers) GetTaskActivity(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	task := h.requireTaskViewer(w, r, currentUser)
+	if task == nil {
+		return
+	}
+
+	activity, err := h.commentRepo.ListActivity(r.Context(), task.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch task activity")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, activity)
+}
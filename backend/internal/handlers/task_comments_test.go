@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+// setupTaskCommentTest creates a task created by user 1 and assigned to user 2
+func setupTaskCommentTest() (*CalendarHandlers, *mocks.MockTaskRepository, *mocks.MockTaskCommentRepository) {
+	taskRepo := mocks.NewMockTaskRepository()
+	assignee := int64(2)
+	taskRepo.AddTask(&models.Task{
+		ID:             1,
+		Title:          "Write release notes",
+		Status:         models.TaskStatusPending,
+		DueDate:        time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		CreatedByID:    1,
+		AssignmentType: models.AssignmentTypeUser,
+		AssignedUserID: &assignee,
+	})
+	commentRepo := mocks.NewMockTaskCommentRepository()
+	h := NewCalendarHandlersWithComments(nil, taskRepo, nil, commentRepo, nil)
+	return h, taskRepo, commentRepo
+}
+
+func TestCalendarHandlers_CreateTaskComment(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		taskID         string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "assignee can comment",
+			currentUser:    &models.User{ID: 2, Role: models.RoleEmployee},
+			taskID:         "1",
+			body:           `{"body":"  Draft is up for review  "}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "user who can't see the task",
+			currentUser:    &models.User{ID: 3, Role: models.RoleEmployee},
+			taskID:         "1",
+			body:           `{"body":"hello"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "empty body",
+			currentUser:    &models.User{ID: 1, Role: models.RoleEmployee},
+			taskID:         "1",
+			body:           `{"body":"   "}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown task",
+			currentUser:    &models.User{ID: 1, Role: models.RoleEmployee},
+			taskID:         "99",
+			body:           `{"body":"hello"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, commentRepo := setupTaskCommentTest()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/calendar/tasks/"+tt.taskID+"/comments", strings.NewReader(tt.body))
+			ctx := ctxWithUserFrom(req.Context(), tt.currentUser)
+			req = req.WithContext(chiCtxWithID(ctx, "id", tt.taskID))
+
+			rr := httptest.NewRecorder()
+			h.CreateTaskComment(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Code != http.StatusCreated {
+				if len(commentRepo.Comments) != 0 {
+					t.Errorf("stored %d comments, want 0", len(commentRepo.Comments))
+				}
+				return
+			}
+
+			var comment models.TaskComment
+			if err := json.Unmarshal(rr.Body.Bytes(), &comment); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if comment.TaskID != 1 || comment.AuthorID != tt.currentUser.ID || comment.Body != "Draft is up for review" {
+				t.Errorf("comment = %+v", comment)
+			}
+		})
+	}
+}
+
+func TestCalendarHandlers_UpdateAndDeleteTaskComment(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		currentUser    *models.User
+		commentID      string
+		expectedStatus int
+	}{
+		{"author can edit", http.MethodPut, &models.User{ID: 2, Role: models.RoleEmployee}, "1", http.StatusOK},
+		{"task creator can't edit someone else's comment", http.MethodPut, &models.User{ID: 1, Role: models.RoleEmployee}, "1", http.StatusForbidden},
+		{"comment on another task", http.MethodPut, &models.User{ID: 2, Role: models.RoleEmployee}, "2", http.StatusNotFound},
+		{"task creator can delete", http.MethodDelete, &models.User{ID: 1, Role: models.RoleEmployee}, "1", http.StatusNoContent},
+		{"admin can delete", http.MethodDelete, &models.User{ID: 5, Role: models.RoleAdmin}, "1", http.StatusNoContent},
+		{"user who can't see the task", http.MethodDelete, &models.User{ID: 3, Role: models.RoleEmployee}, "1", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, taskRepo, commentRepo := setupTaskCommentTest()
+			taskRepo.AddTask(&models.Task{ID: 2, CreatedByID: 2, AssignmentType: models.AssignmentTypeUser})
+			commentRepo.AddComment(&models.TaskComment{ID: 1, TaskID: 1, AuthorID: 2, Body: "first"})
+			commentRepo.AddComment(&models.TaskComment{ID: 2, TaskID: 2, AuthorID: 2, Body: "elsewhere"})
+
+			req := httptest.NewRequest(tt.method, "/api/calendar/tasks/1/comments/"+tt.commentID, strings.NewReader(`{"body":"edited"}`))
+			ctx := ctxWithUserFrom(req.Context(), tt.currentUser)
+			req = req.WithContext(chiCtxWithParams(ctx, map[string]string{"id": "1", "commentId": tt.commentID}))
+
+			rr := httptest.NewRecorder()
+			if tt.method == http.MethodPut {
+				h.UpdateTaskComment(rr, req)
+			} else {
+				h.DeleteTaskComment(rr, req)
+			}
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			switch {
+			case tt.expectedStatus == http.StatusOK && commentRepo.Comments[1].Body != "edited":
+				t.Errorf("body = %q, want edited", commentRepo.Comments[1].Body)
+			case tt.expectedStatus == http.StatusNoContent && commentRepo.Comments[1] != nil:
+				t.Error("comment was not deleted")
+			case tt.expectedStatus >= 400 && (commentRepo.Comments[1] == nil || commentRepo.Comments[1].Body != "first"):
+				t.Error("comment was changed by a rejected request")
+			}
+		})
+	}
+}
+
+func TestCalendarHandlers_UpdateTask_RecordsStatusChange(t *testing.T) {
+	h, _, commentRepo := setupTaskCommentTest()
+	creator := &models.User{ID: 1, Role: models.RoleEmployee}
+
+	update := func(body string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPut, "/api/calendar/tasks/1", strings.NewReader(body))
+		ctx := ctxWithUserFrom(req.Context(), creator)
+		req = req.WithContext(chiCtxWithID(ctx, "id", "1"))
+		rr := httptest.NewRecorder()
+		h.UpdateTask(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("UpdateTask status = %d: %s", rr.Code, rr.Body.String())
+		}
+	}
+
+	update(`{"title":"Write the release notes"}`)
+	update(`{"status":"in_progress"}`)
+	update(`{"status":"in_progress"}`)
+	update(`{"status":"completed"}`)
+
+	// Only actual status transitions show up in the feed
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar/tasks/1/activity", nil)
+	ctx := ctxWithUserFrom(req.Context(), &models.User{ID: 2, Role: models.RoleEmployee})
+	req = req.WithContext(chiCtxWithID(ctx, "id", "1"))
+	rr := httptest.NewRecorder()
+	h.GetTaskActivity(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GetTaskActivity status = %d: %s", rr.Code, rr.Body.String())
+	}
+	var activity []models.TaskActivity
+	if err := json.Unmarshal(rr.Body.Bytes(), &activity); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(activity) != 2 {
+		t.Fatalf("activity = %+v, want 2 status changes", activity)
+	}
+	if activity[0].FromStatus != models.TaskStatusPending || activity[0].ToStatus != models.TaskStatusInProgress {
+		t.Errorf("first change = %s -> %s", activity[0].FromStatus, activity[0].ToStatus)
+	}
+	if activity[1].ToStatus != models.TaskStatusCompleted || *activity[1].ActorID != creator.ID {
+		t.Errorf("second change = %+v", activity[1])
+	}
+	if len(commentRepo.Activity) != 2 {
+		t.Errorf("recorded %d changes, want 2", len(commentRepo.Activity))
+	}
+}
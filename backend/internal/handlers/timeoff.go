@@ -1,25 +1,62 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
 )
 
 type TimeOffHandlers struct {
-	timeOffRepo repository.TimeOffRepository
-	userRepo    repository.UserRepository
+	timeOffRepo   repository.TimeOffRepository
+	userRepo      repository.UserRepository
+	squadRepo     repository.SquadRepository
+	savedViews    repository.SavedViewRepository
+	broker        *events.Broker
+	notifications *services.NotificationService
+	slack         *services.SlackAppService
 }
 
 func NewTimeOffHandlers(timeOffRepo repository.TimeOffRepository, userRepo repository.UserRepository) *TimeOffHandlers {
+	return NewTimeOffHandlersWithEvents(timeOffRepo, userRepo, nil)
+}
+
+// NewTimeOffHandlersWithEvents creates time off handlers that publish request lifecycle changes to the given broker
+func NewTimeOffHandlersWithEvents(timeOffRepo repository.TimeOffRepository, userRepo repository.UserRepository, broker *events.Broker) *TimeOffHandlers {
 	return &TimeOffHandlers{
 		timeOffRepo: timeOffRepo,
 		userRepo:    userRepo,
+		broker:      broker,
 	}
 }
 
+// WithSquadLeads lets squad leads view the time off of everyone in the squads they lead
+func (h *TimeOffHandlers) WithSquadLeads(squadRepo repository.SquadRepository) *TimeOffHandlers {
+	h.squadRepo = squadRepo
+	return h
+}
+
+// WithNotifications tells requesters in-app when their requests are reviewed
+func (h *TimeOffHandlers) WithNotifications(notifications *services.NotificationService) *TimeOffHandlers {
+	h.notifications = notifications
+	return h
+}
+
+// WithSlack DMs supervisors about new requests and requesters about decisions through the
+// organization's Slack app
+func (h *TimeOffHandlers) WithSlack(slack *services.SlackAppService) *TimeOffHandlers {
+	h.slack = slack
+	return h
+}
+
 // Create creates a new time off request
 func (h *TimeOffHandlers) Create(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
@@ -39,7 +76,7 @@ func (h *TimeOffHandlers) Create(w http.ResponseWriter, r *http.Request) {
 	// If user_id is provided, this is a supervisor/admin creating for another user
 	if req.UserID != nil && *req.UserID != currentUser.ID {
 		// Only supervisors and admins can create time off for others
-		if !currentUser.IsSupervisorOrAdmin() {
+		if !authz.CanForOthers(currentUser, authz.ActionTimeOffCreate) {
 			respondError(w, http.StatusForbidden, "Forbidden: only supervisors and admins can create time off for others")
 			return
 		}
@@ -53,11 +90,9 @@ func (h *TimeOffHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// For supervisors, verify the target is their direct report
-		if !currentUser.IsAdmin() {
-			if targetUser.SupervisorID == nil || *targetUser.SupervisorID != currentUser.ID {
-				respondError(w, http.StatusForbidden, "Forbidden: can only create time off for direct reports")
-				return
-			}
+		if !authz.Can(currentUser, authz.ActionTimeOffCreate, authz.UserResource(targetUser)) {
+			respondError(w, http.StatusForbidden, "Forbidden: can only create time off for direct reports")
+			return
 		}
 
 		targetUserID = *req.UserID
@@ -71,8 +106,9 @@ func (h *TimeOffHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// If auto_approve is set and requester is supervisor/admin creating for another user
-	if req.AutoApprove && req.UserID != nil && *req.UserID != currentUser.ID && currentUser.IsSupervisorOrAdmin() {
+	// If auto_approve is set and requester could review the request they created for another user
+	if req.AutoApprove && req.UserID != nil && *req.UserID != currentUser.ID &&
+		authz.Can(currentUser, authz.ActionTimeOffReview, authz.UserResource(targetUser)) {
 		approveReq := &models.ReviewTimeOffRequestInput{
 			Status: models.TimeOffStatusApproved,
 		}
@@ -88,40 +124,147 @@ func (h *TimeOffHandlers) Create(w http.ResponseWriter, r *http.Request) {
 	// Add user info to response
 	timeOff.User = targetUser
 
+	h.broker.Publish(r.Context(), events.TimeOffRequested, timeOff)
+	h.slack.NotifyTimeOffRequested(r.Context(), timeOff, targetUser)
+	if timeOff.Status == models.TimeOffStatusApproved {
+		h.broker.Publish(r.Context(), events.TimeOffReviewed, timeOff)
+		h.slack.NotifyTimeOffReviewed(r.Context(), timeOff, currentUser)
+	}
+
 	respondJSON(w, http.StatusCreated, timeOff)
 }
 
-// GetMyRequests returns time off requests visible to the current user:
-// - Employees: only their own requests
-// - Supervisors/Admins: their own + their direct reports' requests
+// GetMyRequests returns time off requests visible to the current user.
+// The optional scope parameter selects the audience and is enforced by role:
+// - mine: only the current user's requests (any role)
+// - team: their own + their direct reports' requests (supervisors and admins; the default)
+// - all: every request in the organization (admins only)
+// Employees default to mine.
+//
+// Supports status, type, from/to date range, sort/order, and cursor pagination
+// via limit/cursor. Pages hold DefaultPerPage requests unless limit asks for up to MaxPerPage.
+// view applies one of the user's saved views, under any filters given explicitly.
+// format=csv or xlsx downloads every matching request instead, limited to the optional
+// columns, unless limit is given.
 func (h *TimeOffHandlers) GetMyRequests(w http.ResponseWriter, r *http.Request) {
 	currentUser := requireAuth(w, r)
 	if currentUser == nil {
 		return
 	}
+	if !applySavedView(w, r, h.savedViews, currentUser, models.SavedViewResourceTimeOff) {
+		return
+	}
 
-	// Optional status filter
-	var statusFilter *models.TimeOffStatus
-	statusStr := r.URL.Query().Get("status")
-	if statusStr != "" {
-		status := models.TimeOffStatus(statusStr)
-		if models.ValidTimeOffStatuses[status] {
-			statusFilter = &status
-		}
+	filter, err := parseTimeOffFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid query: %v", err))
+		return
+	}
+	download, ok := parseTableDownload(w, r, services.TimeOffExportColumns)
+	if !ok {
+		return
+	}
+	if download != nil && !r.URL.Query().Has("limit") {
+		filter.Limit = 0
+	}
+
+	scope := models.TimeOffScope(r.URL.Query().Get("scope"))
+	if scope != "" && !models.ValidTimeOffScopes[scope] {
+		respondError(w, http.StatusBadRequest, "Invalid scope: must be 'all', 'team', or 'mine'")
+		return
 	}
 
-	// Use GetVisibleRequests which returns role-appropriate results
-	requests, err := h.timeOffRepo.GetVisibleRequests(r.Context(), currentUser, statusFilter)
+	var page *models.TimeOffRequestPage
+	switch scope {
+	case models.TimeOffScopeAll:
+		if !authz.CanAll(currentUser, authz.ActionTimeOffView) {
+			respondError(w, http.StatusForbidden, "Forbidden: admin access required for scope=all")
+			return
+		}
+		page, err = h.timeOffRepo.GetAllRequests(r.Context(), filter)
+	case models.TimeOffScopeMine:
+		filter.UserID = &currentUser.ID
+		page, err = h.timeOffRepo.GetAllRequests(r.Context(), filter)
+	case models.TimeOffScopeTeam:
+		if !authz.CanForOthers(currentUser, authz.ActionTimeOffView) {
+			respondError(w, http.StatusForbidden, "Forbidden: supervisor or admin access required for scope=team")
+			return
+		}
+		page, err = h.timeOffRepo.GetVisibleRequests(r.Context(), currentUser, filter)
+	default:
+		page, err = h.timeOffRepo.GetVisibleRequests(r.Context(), currentUser, filter)
+	}
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to fetch time off requests")
 		return
 	}
 
-	if requests == nil {
-		requests = []models.TimeOffRequest{}
+	if page.Data == nil {
+		page.Data = []models.TimeOffRequest{}
 	}
 
-	respondJSON(w, http.StatusOK, requests)
+	if download != nil {
+		download.respond(w, r, "time-off", page.Data)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, page)
+}
+
+// parseTimeOffFilter builds a TimeOffFilter from query parameters
+func parseTimeOffFilter(r *http.Request) (models.TimeOffFilter, error) {
+	q := r.URL.Query()
+	var filter models.TimeOffFilter
+
+	if s := q.Get("status"); s != "" {
+		status := models.TimeOffStatus(s)
+		filter.Status = &status
+	}
+	if t := q.Get("type"); t != "" {
+		requestType := models.TimeOffType(t)
+		filter.RequestType = &requestType
+	}
+	if from := q.Get("from"); from != "" {
+		parsed, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return filter, fmt.Errorf("from must use YYYY-MM-DD")
+		}
+		filter.From = &parsed
+	}
+	if to := q.Get("to"); to != "" {
+		parsed, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return filter, fmt.Errorf("to must use YYYY-MM-DD")
+		}
+		filter.To = &parsed
+	}
+
+	filter.SortBy = models.TimeOffSortField(q.Get("sort"))
+	switch q.Get("order") {
+	case "", "desc":
+	case "asc":
+		filter.SortAsc = true
+	default:
+		return filter, fmt.Errorf("order must be 'asc' or 'desc'")
+	}
+
+	filter.Limit = DefaultPerPage
+	if l := q.Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 {
+			return filter, fmt.Errorf("limit must be a positive integer")
+		}
+		filter.Limit = min(parsed, MaxPerPage)
+	}
+	if c := q.Get("cursor"); c != "" {
+		cursor, err := models.DecodeTimeOffCursor(c)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, filter.Validate()
 }
 
 // GetByID returns a specific time off request
@@ -147,8 +290,8 @@ func (h *TimeOffHandlers) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check permission: owner, supervisor, or admin
-	if !h.canViewTimeOff(currentUser, timeOff) {
+	// Check permission: owner, supervisor, squad lead, or admin
+	if !h.canViewTimeOff(currentUser, timeOff) && !leadsSquadOf(r.Context(), h.squadRepo, currentUser, timeOff.UserID) {
 		respondError(w, http.StatusForbidden, "Forbidden: you don't have permission to view this time off request")
 		return
 	}
@@ -175,6 +318,12 @@ func (h *TimeOffHandlers) Cancel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.broker != nil {
+		if timeOff, err := h.timeOffRepo.GetByID(r.Context(), id); err == nil && timeOff != nil {
+			h.broker.Publish(r.Context(), events.TimeOffCancelled, timeOff)
+		}
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -186,15 +335,20 @@ func (h *TimeOffHandlers) GetPending(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Only supervisors and admins can view pending requests
-	if !currentUser.IsSupervisorOrAdmin() {
+	if !authz.Can(currentUser, authz.ActionTimeOffReview, nil) {
 		respondError(w, http.StatusForbidden, "Forbidden: supervisor or admin access required")
 		return
 	}
 
+	download, ok := parseTableDownload(w, r, services.TimeOffExportColumns)
+	if !ok {
+		return
+	}
+
 	var requests []models.TimeOffRequest
 	var err error
 
-	if currentUser.IsAdmin() {
+	if authz.CanAll(currentUser, authz.ActionTimeOffReview) {
 		// Admin sees all pending
 		requests, err = h.timeOffRepo.GetAllPending(r.Context())
 	} else {
@@ -211,6 +365,11 @@ func (h *TimeOffHandlers) GetPending(w http.ResponseWriter, r *http.Request) {
 		requests = []models.TimeOffRequest{}
 	}
 
+	if download != nil {
+		download.respond(w, r, "pending-time-off", requests)
+		return
+	}
+
 	// Support optional pagination
 	if shouldPaginate(r) {
 		p := parsePagination(r)
@@ -229,57 +388,83 @@ func (h *TimeOffHandlers) Review(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Only supervisors and admins can review
-	if !currentUser.IsSupervisorOrAdmin() {
-		respondError(w, http.StatusForbidden, "Forbidden: supervisor or admin access required")
-		return
-	}
-
 	id, err := parseIDParam(r, "id")
 	if err != nil {
 		respondError(w, http.StatusBadRequest, "Invalid time off request ID")
 		return
 	}
 
-	// Get the request to check authorization
-	timeOff, err := h.timeOffRepo.GetByIDWithUser(r.Context(), id)
+	if err := h.authorizeReview(r.Context(), currentUser, id); err != nil {
+		respondAppError(w, err)
+		return
+	}
+
+	var req models.ReviewTimeOffRequestInput
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	updated, err := h.applyReview(r.Context(), currentUser, id, &req)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch time off request")
+		respondAppError(w, err)
 		return
 	}
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// ReviewAs approves or rejects a time off request on behalf of reviewer, with the same checks
+// and announcements as Review. It's used by reviews made outside the dashboard, such as from
+// Slack, and returns AppErrors.
+func (h *TimeOffHandlers) ReviewAs(ctx context.Context, reviewer *models.User, id int64, req *models.ReviewTimeOffRequestInput) (*models.TimeOffRequest, error) {
+	if err := h.authorizeReview(ctx, reviewer, id); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, apperrors.NewValidationError(err.Error())
+	}
+	return h.applyReview(ctx, reviewer, id, req)
+}
+
+// authorizeReview checks that reviewer may review the request: admins can review any, and
+// supervisors only their direct reports'
+func (h *TimeOffHandlers) authorizeReview(ctx context.Context, reviewer *models.User, id int64) error {
+	if !authz.Can(reviewer, authz.ActionTimeOffReview, nil) {
+		return apperrors.NewForbiddenError("Forbidden: supervisor or admin access required")
+	}
+
+	timeOff, err := h.timeOffRepo.GetByIDWithUser(ctx, id)
+	if err != nil {
+		return apperrors.NewInternalError("Failed to fetch time off request", err)
+	}
 	if timeOff == nil {
-		respondError(w, http.StatusNotFound, "Time off request not found")
-		return
+		return apperrors.NewNotFoundError("Time off request")
 	}
 
-	// Check if supervisor can review this request (only their direct reports)
-	if !currentUser.IsAdmin() {
-		// Get the requesting user to check supervisor
-		requestingUser, err := h.userRepo.GetByID(r.Context(), timeOff.UserID)
+	if !authz.CanAll(reviewer, authz.ActionTimeOffReview) {
+		requestingUser, err := h.userRepo.GetByID(ctx, timeOff.UserID)
 		if err != nil || requestingUser == nil {
-			respondError(w, http.StatusInternalServerError, "Failed to verify authorization")
-			return
+			return apperrors.NewInternalError("Failed to verify authorization", err)
 		}
-		if requestingUser.SupervisorID == nil || *requestingUser.SupervisorID != currentUser.ID {
-			respondError(w, http.StatusForbidden, "Forbidden: can only review direct reports' requests")
-			return
+		if !authz.Can(reviewer, authz.ActionTimeOffReview, authz.UserResource(requestingUser)) {
+			return apperrors.NewForbiddenError("Forbidden: can only review direct reports' requests")
 		}
 	}
+	return nil
+}
 
-	var req models.ReviewTimeOffRequestInput
-	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
-		return
+// applyReview records an authorized review and announces it, returning the updated request
+func (h *TimeOffHandlers) applyReview(ctx context.Context, reviewer *models.User, id int64, req *models.ReviewTimeOffRequestInput) (*models.TimeOffRequest, error) {
+	if err := h.timeOffRepo.Review(ctx, id, reviewer.ID, req); err != nil {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Failed to review time off request: %v", err))
 	}
 
-	err = h.timeOffRepo.Review(r.Context(), id, currentUser.ID, &req)
-	if err != nil {
-		respondError(w, http.StatusBadRequest, fmt.Sprintf("Failed to review time off request: %v", err))
-		return
+	updated, _ := h.timeOffRepo.GetByIDWithUser(ctx, id)
+	if updated != nil {
+		h.broker.Publish(ctx, events.TimeOffReviewed, updated)
+		h.notifications.NotifyTimeOffReviewed(ctx, updated, reviewer)
+		h.slack.NotifyTimeOffReviewed(ctx, updated, reviewer)
 	}
-
-	// Return updated request
-	updated, _ := h.timeOffRepo.GetByIDWithUser(r.Context(), id)
-	respondJSON(w, http.StatusOK, updated)
+	return updated, nil
 }
 
 // GetTeamTimeOff returns approved time off for supervisor's team
@@ -289,20 +474,25 @@ func (h *TimeOffHandlers) GetTeamTimeOff(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Only supervisors and admins can view team time off
-	if !currentUser.IsSupervisorOrAdmin() {
-		respondError(w, http.StatusForbidden, "Forbidden: supervisor or admin access required")
+	// Only supervisors, squad leads, and admins can view team time off
+	if !authz.CanForOthers(currentUser, authz.ActionTimeOffView) && !h.leadsAnySquad(r, currentUser) {
+		respondError(w, http.StatusForbidden, "Forbidden: supervisor, squad lead, or admin access required")
+		return
+	}
+
+	download, ok := parseTableDownload(w, r, services.TimeOffExportColumns)
+	if !ok {
 		return
 	}
 
 	var requests []models.TimeOffRequest
 	var err error
 
-	if currentUser.IsAdmin() {
+	if authz.CanAll(currentUser, authz.ActionTimeOffView) {
 		// Admin can see all approved time off
 		requests, err = h.timeOffRepo.GetAllApproved(r.Context())
 	} else {
-		// Supervisor sees only direct reports
+		// Supervisors see their direct reports, and squad leads their squads
 		requests, err = h.timeOffRepo.GetTeamTimeOff(r.Context(), currentUser.ID)
 	}
 
@@ -315,6 +505,11 @@ func (h *TimeOffHandlers) GetTeamTimeOff(w http.ResponseWriter, r *http.Request)
 		requests = []models.TimeOffRequest{}
 	}
 
+	if download != nil {
+		download.respond(w, r, "team-time-off", requests)
+		return
+	}
+
 	// Support optional pagination
 	if shouldPaginate(r) {
 		p := parsePagination(r)
@@ -326,22 +521,21 @@ func (h *TimeOffHandlers) GetTeamTimeOff(w http.ResponseWriter, r *http.Request)
 	respondJSON(w, http.StatusOK, requests)
 }
 
-// canViewTimeOff checks if a user can view a time off request
-func (h *TimeOffHandlers) canViewTimeOff(user *models.User, timeOff *models.TimeOffRequest) bool {
-	// Admin can see all
-	if user.IsAdmin() {
-		return true
-	}
-
-	// Owner can see their own
-	if timeOff.UserID == user.ID {
-		return true
+// leadsAnySquad reports whether the user leads at least one squad
+func (h *TimeOffHandlers) leadsAnySquad(r *http.Request, user *models.User) bool {
+	if h.squadRepo == nil {
+		return false
 	}
+	squadIDs, err := h.squadRepo.GetLedSquadIDs(r.Context(), user.ID)
+	return err == nil && len(squadIDs) > 0
+}
 
-	// Supervisor can see their direct reports' requests
-	if user.IsSupervisor() && timeOff.User != nil && timeOff.User.SupervisorID != nil {
-		return *timeOff.User.SupervisorID == user.ID
-	}
+// canViewTimeOff checks if a user can view a time off request
+func (h *TimeOffHandlers) canViewTimeOff(user *models.User, timeOff *models.TimeOffRequest) bool {
+	return canViewTimeOffRequest(user, timeOff)
+}
 
-	return false
+// canViewTimeOffRequest reports whether the user is the owner, the owner's supervisor, or an admin
+func canViewTimeOffRequest(user *models.User, timeOff *models.TimeOffRequest) bool {
+	return authz.Can(user, authz.ActionTimeOffView, authz.TimeOffResource(timeOff))
 }
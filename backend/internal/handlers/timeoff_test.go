@@ -871,13 +871,13 @@ func TestTimeOffHandlers_GetMyRequests_Success(t *testing.T) {
 		t.Errorf("GetMyRequests() status = %v, want %v, body = %s", rr.Code, http.StatusOK, rr.Body.String())
 	}
 
-	var response []models.TimeOffRequest
+	var response models.TimeOffRequestPage
 	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
 		t.Fatalf("Failed to unmarshal response: %v", err)
 	}
 
-	if len(response) != 2 {
-		t.Errorf("GetMyRequests() returned %d requests, want 2", len(response))
+	if len(response.Data) != 2 {
+		t.Errorf("GetMyRequests() returned %d requests, want 2", len(response.Data))
 	}
 }
 
@@ -885,14 +885,14 @@ func TestTimeOffHandlers_GetMyRequests_WithStatusFilter(t *testing.T) {
 	userID := int64(1)
 
 	timeOffRepo := mocks.NewMockTimeOffRepository()
-	timeOffRepo.GetVisibleRequestsFunc = func(ctx context.Context, user *models.User, status *models.TimeOffStatus) ([]models.TimeOffRequest, error) {
+	timeOffRepo.GetVisibleRequestsFunc = func(ctx context.Context, user *models.User, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error) {
 		var requests []models.TimeOffRequest
 		for _, req := range timeOffRepo.Requests {
-			if status == nil || req.Status == *status {
+			if filter.Status == nil || req.Status == *filter.Status {
 				requests = append(requests, *req)
 			}
 		}
-		return requests, nil
+		return &models.TimeOffRequestPage{Data: requests}, nil
 	}
 
 	timeOffRepo.AddRequest(&models.TimeOffRequest{
@@ -927,13 +927,191 @@ func TestTimeOffHandlers_GetMyRequests_WithStatusFilter(t *testing.T) {
 		t.Errorf("GetMyRequests() status = %v, want %v, body = %s", rr.Code, http.StatusOK, rr.Body.String())
 	}
 
-	var response []models.TimeOffRequest
+	var response models.TimeOffRequestPage
 	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
 		t.Fatalf("Failed to unmarshal response: %v", err)
 	}
 
-	if len(response) != 1 {
-		t.Errorf("GetMyRequests() returned %d requests, want 1", len(response))
+	if len(response.Data) != 1 {
+		t.Errorf("GetMyRequests() returned %d requests, want 1", len(response.Data))
+	}
+}
+
+func TestTimeOffHandlers_GetMyRequests_CursorPagination(t *testing.T) {
+	next := models.TimeOffCursor{SortValue: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), ID: 7}.Encode()
+
+	var gotFilter models.TimeOffFilter
+	timeOffRepo := mocks.NewMockTimeOffRepository()
+	timeOffRepo.GetVisibleRequestsFunc = func(ctx context.Context, user *models.User, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error) {
+		gotFilter = filter
+		return &models.TimeOffRequestPage{
+			Data:       []models.TimeOffRequest{{ID: 8, UserID: user.ID}},
+			NextCursor: &next,
+			HasMore:    true,
+		}, nil
+	}
+
+	h := NewTimeOffHandlers(timeOffRepo, nil)
+	user := &models.User{ID: 1, Role: models.RoleSupervisor}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/time-off?limit=1&cursor="+next+"&type=sick&from=2024-01-01&to=2024-12-31&sort=created_at&order=asc", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), user))
+
+	rr := httptest.NewRecorder()
+	h.GetMyRequests(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GetMyRequests() status = %v, want %v, body = %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	if gotFilter.Limit != 1 {
+		t.Errorf("filter.Limit = %d, want 1", gotFilter.Limit)
+	}
+	if gotFilter.Cursor == nil || gotFilter.Cursor.ID != 7 {
+		t.Errorf("filter.Cursor = %+v, want ID 7", gotFilter.Cursor)
+	}
+	if gotFilter.RequestType == nil || *gotFilter.RequestType != models.TimeOffTypeSick {
+		t.Errorf("filter.RequestType = %v, want sick", gotFilter.RequestType)
+	}
+	if gotFilter.SortBy != models.TimeOffSortCreatedAt || !gotFilter.SortAsc {
+		t.Errorf("filter sort = %s asc=%v, want created_at asc", gotFilter.SortBy, gotFilter.SortAsc)
+	}
+
+	var response models.TimeOffRequestPage
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if !response.HasMore || response.NextCursor == nil || *response.NextCursor != next {
+		t.Errorf("GetMyRequests() page = %+v, want has_more with next cursor", response)
+	}
+}
+
+func TestTimeOffHandlers_GetMyRequests_PageSize(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "defaults without limit", query: "", want: DefaultPerPage},
+		{name: "defaults with only a cursor", query: "cursor=" + models.TimeOffCursor{SortValue: time.Now(), ID: 3}.Encode(), want: DefaultPerPage},
+		{name: "uses the limit given", query: "limit=10", want: 10},
+		{name: "caps the limit", query: "limit=5000", want: MaxPerPage},
+		{name: "downloads every request", query: "format=csv", want: 0},
+		{name: "downloads up to the limit given", query: "format=csv&limit=10", want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFilter models.TimeOffFilter
+			timeOffRepo := mocks.NewMockTimeOffRepository()
+			timeOffRepo.GetVisibleRequestsFunc = func(ctx context.Context, user *models.User, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error) {
+				gotFilter = filter
+				return &models.TimeOffRequestPage{}, nil
+			}
+
+			h := NewTimeOffHandlers(timeOffRepo, nil)
+			req := httptest.NewRequest(http.MethodGet, "/api/time-off?"+tt.query, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleSupervisor}))
+
+			rr := httptest.NewRecorder()
+			h.GetMyRequests(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("GetMyRequests() status = %v, want %v, body = %s", rr.Code, http.StatusOK, rr.Body.String())
+			}
+			if gotFilter.Limit != tt.want {
+				t.Errorf("filter.Limit = %d, want %d", gotFilter.Limit, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeOffHandlers_GetMyRequests_Scope(t *testing.T) {
+	tests := []struct {
+		name           string
+		role           models.Role
+		scope          string
+		expectedStatus int
+		expectedRepo   string
+	}{
+		{name: "admin can view all", role: models.RoleAdmin, scope: "all", expectedStatus: http.StatusOK, expectedRepo: "all"},
+		{name: "supervisor cannot view all", role: models.RoleSupervisor, scope: "all", expectedStatus: http.StatusForbidden},
+		{name: "employee cannot view all", role: models.RoleEmployee, scope: "all", expectedStatus: http.StatusForbidden},
+		{name: "supervisor can view team", role: models.RoleSupervisor, scope: "team", expectedStatus: http.StatusOK, expectedRepo: "visible"},
+		{name: "employee cannot view team", role: models.RoleEmployee, scope: "team", expectedStatus: http.StatusForbidden},
+		{name: "employee can view mine", role: models.RoleEmployee, scope: "mine", expectedStatus: http.StatusOK, expectedRepo: "all"},
+		{name: "default scope uses visibility rules", role: models.RoleAdmin, scope: "", expectedStatus: http.StatusOK, expectedRepo: "visible"},
+		{name: "unknown scope rejected", role: models.RoleAdmin, scope: "org", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{ID: 5, Role: tt.role}
+
+			var calledRepo string
+			var gotFilter models.TimeOffFilter
+			timeOffRepo := mocks.NewMockTimeOffRepository()
+			timeOffRepo.GetAllRequestsFunc = func(ctx context.Context, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error) {
+				calledRepo = "all"
+				gotFilter = filter
+				return &models.TimeOffRequestPage{}, nil
+			}
+			timeOffRepo.GetVisibleRequestsFunc = func(ctx context.Context, u *models.User, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error) {
+				calledRepo = "visible"
+				gotFilter = filter
+				return &models.TimeOffRequestPage{}, nil
+			}
+
+			h := NewTimeOffHandlers(timeOffRepo, nil)
+			req := httptest.NewRequest(http.MethodGet, "/api/time-off?scope="+tt.scope, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), user))
+
+			rr := httptest.NewRecorder()
+			h.GetMyRequests(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("GetMyRequests() status = %v, want %v, body = %s", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+			if calledRepo != tt.expectedRepo {
+				t.Errorf("GetMyRequests() used %q repository method, want %q", calledRepo, tt.expectedRepo)
+			}
+			if tt.scope == "mine" && (gotFilter.UserID == nil || *gotFilter.UserID != user.ID) {
+				t.Errorf("scope=mine filter.UserID = %v, want %d", gotFilter.UserID, user.ID)
+			}
+		})
+	}
+}
+
+func TestTimeOffHandlers_GetMyRequests_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "invalid status", query: "status=bogus"},
+		{name: "invalid type", query: "type=bogus"},
+		{name: "malformed from date", query: "from=01-01-2024"},
+		{name: "to before from", query: "from=2024-02-01&to=2024-01-01"},
+		{name: "invalid sort", query: "sort=reason"},
+		{name: "invalid order", query: "order=sideways"},
+		{name: "non-numeric limit", query: "limit=abc"},
+		{name: "malformed cursor", query: "cursor=not-a-cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewTimeOffHandlers(mocks.NewMockTimeOffRepository(), nil)
+			user := &models.User{ID: 1, Role: models.RoleEmployee}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/time-off?"+tt.query, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), user))
+
+			rr := httptest.NewRecorder()
+			h.GetMyRequests(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("GetMyRequests() status = %v, want %v", rr.Code, http.StatusBadRequest)
+			}
+		})
 	}
 }
 
@@ -999,3 +1177,55 @@ func TestTimeOffHandlers_Create_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeOffHandlers_SquadLeadAccess(t *testing.T) {
+	leadID, memberID, outsiderID := int64(1), int64(2), int64(3)
+
+	timeOffRepo := mocks.NewMockTimeOffRepository()
+	timeOffRepo.AddRequest(&models.TimeOffRequest{
+		ID:     1,
+		UserID: memberID,
+		Status: models.TimeOffStatusApproved,
+		User:   &models.User{ID: memberID, Role: models.RoleEmployee},
+	})
+
+	squadRepo := mocks.NewMockSquadRepository()
+	squadRepo.AddSquad(&models.Squad{ID: 10, Name: "Platform"})
+	squadRepo.AssignUserToSquad(memberID, 10)
+	if err := squadRepo.SetLead(context.Background(), 10, leadID); err != nil {
+		t.Fatalf("SetLead() error = %v", err)
+	}
+
+	h := NewTimeOffHandlers(timeOffRepo, nil).WithSquadLeads(squadRepo)
+
+	tests := []struct {
+		name   string
+		userID int64
+		want   int
+	}{
+		{"squad lead", leadID, http.StatusOK},
+		{"outside the squad", outsiderID, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{ID: tt.userID, Role: models.RoleEmployee}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/time-off/1", nil)
+			req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), user), "id", "1"))
+			rr := httptest.NewRecorder()
+			h.GetByID(rr, req)
+			if rr.Code != tt.want {
+				t.Errorf("GetByID() status = %v, want %v", rr.Code, tt.want)
+			}
+
+			req = httptest.NewRequest(http.MethodGet, "/api/time-off/team", nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), user))
+			rr = httptest.NewRecorder()
+			h.GetTeamTimeOff(rr, req)
+			if rr.Code != tt.want {
+				t.Errorf("GetTeamTimeOff() status = %v, want %v", rr.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// WithHistory records changes to users in their employment history and serves it
+func (h *Handlers) WithHistory(historyRepo repository.UserHistoryRepository) *Handlers {
+	h.historyRepo = historyRepo
+	h.userService.WithHistory(historyRepo)
+	return h
+}
+
+// GetUserHistory godoc
+// @Summary Get a user's employment history
+// @Description Returns changes to a user's title, department, role, supervisor, and squads, newest first. Admins can view anyone's history and supervisors their own and their direct reports'.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Success 200 {object} PaginatedResponse "History entries"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/history [get]
+func (h *Handlers) GetUserHistory(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionUserHistoryView)
+	if currentUser == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	targetUser, err := h.userRepo.GetByID(r.Context(), id)
+	if err != nil || targetUser == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if !authz.Can(currentUser, authz.ActionUserHistoryView, authz.UserResource(targetUser)) {
+		respondError(w, http.StatusForbidden, "Forbidden: supervisors can only view their direct reports' history")
+		return
+	}
+
+	p := parsePagination(r)
+	entries, total, err := h.historyRepo.GetByUserID(r.Context(), id, p.PerPage, p.Offset)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get user history", err, "user_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch user history")
+		return
+	}
+
+	respondPaginated(w, entries, total, p)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestUserHistory(t *testing.T) {
+	supervisorID := int64(2)
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, Role: models.RoleSupervisor, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, Role: models.RoleEmployee, Title: "Engineer", Department: "Engineering", SupervisorID: &supervisorID, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 4, Role: models.RoleEmployee, IsActive: true})
+	historyRepo := mocks.NewMockUserHistoryRepository()
+	h := New(userRepo, mocks.NewMockSquadRepository(), nil).WithHistory(historyRepo)
+
+	// Promote user 3 as their supervisor
+	req := httptest.NewRequest(http.MethodPut, "/api/users/3", strings.NewReader(`{"title":"Senior Engineer","department":"Engineering"}`))
+	req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[2]), "id", "3"))
+	rr := httptest.NewRecorder()
+	h.UpdateUser(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("UpdateUser() status = %d: %s", rr.Code, rr.Body.String())
+	}
+
+	get := func(currentUserID int64, id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/users/"+id+"/history", nil)
+		req = req.WithContext(chiCtxWithID(ctxWithUserFrom(req.Context(), userRepo.Users[currentUserID]), "id", id))
+		rr := httptest.NewRecorder()
+		h.GetUserHistory(rr, req)
+		return rr
+	}
+
+	for _, tt := range []struct {
+		name          string
+		currentUserID int64
+		id            string
+		want          int
+	}{
+		{"supervisor of the user", 2, "3", http.StatusOK},
+		{"admin", 1, "3", http.StatusOK},
+		{"the employee themselves", 3, "3", http.StatusForbidden},
+		{"supervisor of someone else", 2, "4", http.StatusForbidden},
+		{"missing user", 1, "99", http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := get(tt.currentUserID, tt.id); rr.Code != tt.want {
+				t.Errorf("GetUserHistory() status = %d, want %d: %s", rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+
+	var resp struct {
+		Data       []models.UserHistoryEntry `json:"data"`
+		Pagination PaginationMetadata        `json:"pagination"`
+	}
+	if err := json.NewDecoder(get(1, "3").Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Pagination.Total != 1 || len(resp.Data) != 1 {
+		t.Fatalf("history = %+v, want only the title change", resp.Data)
+	}
+	entry := resp.Data[0]
+	if entry.Field != models.UserHistoryTitle || *entry.OldValue != "Engineer" || *entry.NewValue != "Senior Engineer" ||
+		entry.Source != models.UserHistorySourceProfile || entry.ChangedByID == nil || *entry.ChangedByID != supervisorID {
+		t.Errorf("history entry = %+v", entry)
+	}
+}
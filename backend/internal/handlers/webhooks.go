@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+const (
+	// defaultWebhookDeliveryLimit and maxWebhookDeliveryLimit bound how much of the delivery log is listed
+	defaultWebhookDeliveryLimit = 50
+	maxWebhookDeliveryLimit     = 200
+)
+
+// WebhookHandlers lets admins manage the endpoints that receive lifecycle event callbacks
+type WebhookHandlers struct {
+	webhookRepo    repository.WebhookRepository
+	webhookService *services.WebhookService // Queues test deliveries; nil disables test-firing
+	logger         *logger.Logger
+}
+
+// NewWebhookHandlers creates a new webhook handlers instance
+func NewWebhookHandlers(webhookRepo repository.WebhookRepository) *WebhookHandlers {
+	return &WebhookHandlers{
+		webhookRepo: webhookRepo,
+		logger:      logger.Default().WithComponent("webhooks"),
+	}
+}
+
+// WithService enables test-firing endpoints through the delivery worker
+func (h *WebhookHandlers) WithService(webhookService *services.WebhookService) *WebhookHandlers {
+	h.webhookService = webhookService
+	return h
+}
+
+// ListWebhooks returns every webhook endpoint. Admin only.
+func (h *WebhookHandlers) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionWebhookManage) == nil {
+		return
+	}
+
+	endpoints, err := h.webhookRepo.ListEndpoints(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch webhooks")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, endpoints)
+}
+
+// CreateWebhook registers a webhook endpoint. Admin only.
+// The response includes the signing secret, which isn't shown again unless it's rotated.
+func (h *WebhookHandlers) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionWebhookManage)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.CreateWebhookEndpointRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	endpoint, err := h.webhookRepo.CreateEndpoint(r.Context(), &req, currentUser.ID)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to create webhook endpoint", err, "user_id", currentUser.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to create webhook")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, models.WebhookEndpointWithSecret{WebhookEndpoint: endpoint, Secret: endpoint.Secret})
+}
+
+// UpdateWebhook changes a webhook endpoint's URL, subscriptions, or status, or rotates its secret. Admin only.
+func (h *WebhookHandlers) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionWebhookManage) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	var req models.UpdateWebhookEndpointRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	endpoint, err := h.webhookRepo.UpdateEndpoint(r.Context(), id, &req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update webhook")
+		return
+	}
+	if endpoint == nil {
+		respondError(w, http.StatusNotFound, "Webhook not found")
+		return
+	}
+
+	if req.RotateSecret {
+		respondJSON(w, http.StatusOK, models.WebhookEndpointWithSecret{WebhookEndpoint: endpoint, Secret: endpoint.Secret})
+		return
+	}
+	respondJSON(w, http.StatusOK, endpoint)
+}
+
+// DeleteWebhook removes a webhook endpoint and its delivery log. Admin only.
+func (h *WebhookHandlers) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionWebhookManage) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	if err := h.webhookRepo.DeleteEndpoint(r.Context(), id); err != nil {
+		respondError(w, http.StatusNotFound, "Webhook not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries returns an endpoint's most recent deliveries, newest first. Admin only.
+// The optional limit parameter defaults to 50 and is capped at 200.
+func (h *WebhookHandlers) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if requirePermission(w, r, authz.ActionWebhookManage) == nil {
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	limit := defaultWebhookDeliveryLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			respondError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = min(limit, maxWebhookDeliveryLimit)
+	}
+
+	endpoint, err := h.webhookRepo.GetEndpoint(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch webhook")
+		return
+	}
+	if endpoint == nil {
+		respondError(w, http.StatusNotFound, "Webhook not found")
+		return
+	}
+
+	deliveries, err := h.webhookRepo.ListDeliveries(r.Context(), id, limit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch webhook deliveries")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, deliveries)
+}
+
+// TestWebhook queues a signed webhook.test event for an endpoint, whatever it subscribes to. Admin only.
+// The response is the queued delivery; its outcome shows in the endpoint's delivery log.
+func (h *WebhookHandlers) TestWebhook(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionWebhookManage)
+	if currentUser == nil {
+		return
+	}
+	if h.webhookService == nil {
+		respondError(w, http.StatusServiceUnavailable, "Webhook deliveries are not available")
+		return
+	}
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	endpoint, err := h.webhookRepo.GetEndpoint(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch webhook")
+		return
+	}
+	if endpoint == nil {
+		respondError(w, http.StatusNotFound, "Webhook not found")
+		return
+	}
+	if !endpoint.IsActive {
+		respondError(w, http.StatusConflict, "Webhook is disabled; enable it before sending a test")
+		return
+	}
+
+	delivery, err := h.webhookService.SendTest(r.Context(), endpoint)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to queue webhook test", err, "webhook_id", id, "user_id", currentUser.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to send test")
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, delivery)
+}
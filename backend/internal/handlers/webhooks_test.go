@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestWebhookHandlers_CreateWebhook(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		body           string
+		expectedStatus int
+	}{
+		{"admin registers an endpoint", admin, `{"url":"https://hooks.example.com/tasks","event_types":["task.created","task.created"]}`, http.StatusCreated},
+		{"supervisors can't register endpoints", &models.User{ID: 2, Role: models.RoleSupervisor}, `{"url":"https://hooks.example.com"}`, http.StatusForbidden},
+		{"relative url", admin, `{"url":"/hooks"}`, http.StatusBadRequest},
+		{"cloud metadata address", admin, `{"url":"http://169.254.169.254/latest/meta-data"}`, http.StatusBadRequest},
+		{"private address", admin, `{"url":"http://10.0.0.5/hooks"}`, http.StatusBadRequest},
+		{"localhost", admin, `{"url":"http://localhost:8080/hooks"}`, http.StatusBadRequest},
+		{"unknown event type", admin, `{"url":"https://hooks.example.com","event_types":["user.changed"]}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockWebhookRepository()
+			h := NewWebhookHandlers(repo)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/webhooks", strings.NewReader(tt.body))
+			req = req.WithContext(ctxWithUserFrom(req.Context(), tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.CreateWebhook(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Code != http.StatusCreated {
+				if len(repo.Endpoints) != 0 {
+					t.Errorf("rejected request stored an endpoint")
+				}
+				return
+			}
+			var created struct {
+				EventTypes []string `json:"event_types"`
+				Secret     string   `json:"secret"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if created.Secret == "" || len(created.EventTypes) != 1 {
+				t.Errorf("created = %+v, want the secret and deduplicated event types", created)
+			}
+		})
+	}
+}
+
+func TestWebhookHandlers_ListWebhooks_HidesSecrets(t *testing.T) {
+	repo := mocks.NewMockWebhookRepository()
+	repo.AddEndpoint(&models.WebhookEndpoint{ID: 1, URL: "https://hooks.example.com", Secret: "whsec_hidden", IsActive: true})
+	h := NewWebhookHandlers(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/webhooks", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1, Role: models.RoleAdmin}))
+	rr := httptest.NewRecorder()
+	h.ListWebhooks(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if strings.Contains(rr.Body.String(), "whsec_hidden") {
+		t.Errorf("listing exposed the signing secret: %s", rr.Body.String())
+	}
+}
+
+func TestWebhookHandlers_TestWebhook(t *testing.T) {
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		id             string
+		expectedStatus int
+	}{
+		{"queues a test delivery", admin, "1", http.StatusAccepted},
+		{"disabled endpoint", admin, "2", http.StatusConflict},
+		{"unknown endpoint", admin, "99", http.StatusNotFound},
+		{"supervisors can't test endpoints", &models.User{ID: 2, Role: models.RoleSupervisor}, "1", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockWebhookRepository()
+			repo.AddEndpoint(&models.WebhookEndpoint{ID: 1, URL: "https://hooks.example.com", IsActive: true})
+			repo.AddEndpoint(&models.WebhookEndpoint{ID: 2, URL: "https://hooks.example.com/off"})
+			h := NewWebhookHandlers(repo).WithService(services.NewWebhookService(repo))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/webhooks/"+tt.id+"/test", nil)
+			req = req.WithContext(chiCtxWithParams(ctxWithUser(tt.currentUser), map[string]string{"id": tt.id}))
+			rr := httptest.NewRecorder()
+			h.TestWebhook(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			wantQueued := 0
+			if tt.expectedStatus == http.StatusAccepted {
+				wantQueued = 1
+			}
+			if len(repo.Deliveries) != wantQueued {
+				t.Errorf("queued %d deliveries, want %d", len(repo.Deliveries), wantQueued)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/database"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// WorkItemHandlers serves team task views from whichever task tracker the org has connected
+type WorkItemHandlers struct {
+	userRepo             repository.UserRepository
+	timeOffRepo          repository.TimeOffRepository
+	providers            []services.WorkItemProvider
+	maxConcurrentAPIReqs int
+	logger               *logger.Logger
+}
+
+// NewWorkItemHandlers creates work item handlers. When no provider is requested the first
+// connected one, in the order given, is used.
+func NewWorkItemHandlers(userRepo repository.UserRepository, timeOffRepo repository.TimeOffRepository, maxConcurrentAPIReqs int, log *logger.Logger, providers ...services.WorkItemProvider) *WorkItemHandlers {
+	if maxConcurrentAPIReqs <= 0 {
+		maxConcurrentAPIReqs = maxConcurrentJiraRequests
+	}
+	return &WorkItemHandlers{
+		userRepo:             userRepo,
+		timeOffRepo:          timeOffRepo,
+		providers:            providers,
+		maxConcurrentAPIReqs: maxConcurrentAPIReqs,
+		logger:               log.WithComponent("work_item_handlers"),
+	}
+}
+
+// provider returns the provider named by ?provider, or the first connected one.
+// It writes an error response and returns nil if there isn't one.
+func (h *WorkItemHandlers) provider(w http.ResponseWriter, r *http.Request) services.WorkItemProvider {
+	name := r.URL.Query().Get("provider")
+	for _, p := range h.providers {
+		if name != "" && p.Name() != name {
+			continue
+		}
+		connected, err := p.Connected(r.Context())
+		if err != nil {
+			h.logger.LogError(r.Context(), "Failed to check task tracker connection", err, "provider", p.Name())
+			respondError(w, http.StatusInternalServerError, "Failed to get task tracker settings")
+			return nil
+		}
+		if connected {
+			return p
+		}
+		if name != "" {
+			respondError(w, http.StatusNotFound, "That task tracker is not connected")
+			return nil
+		}
+	}
+	if name != "" {
+		respondError(w, http.StatusBadRequest, "Unknown task tracker")
+		return nil
+	}
+	respondError(w, http.StatusNotFound, "No task tracker is connected")
+	return nil
+}
+
+// GetTeamTasks returns open work items for all of a supervisor's direct reports from the
+// connected task tracker (?provider=jira|linear), in the same shape as the Jira team tasks view
+// Only supervisors and admins can access this endpoint
+func (h *WorkItemHandlers) GetTeamTasks(w http.ResponseWriter, r *http.Request) {
+	currentUser := requirePermission(w, r, authz.ActionTeamView)
+	if currentUser == nil {
+		return
+	}
+
+	provider := h.provider(w, r)
+	if provider == nil {
+		return
+	}
+
+	directReports, err := teamMembers(r.Context(), h.userRepo, currentUser)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to fetch direct reports", err, "user_id", currentUser.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch direct reports")
+		return
+	}
+
+	// Filter to only those mapped to an account in the tracker
+	var mappedUsers []models.User
+	var accountIDs []string
+	for _, user := range directReports {
+		if id := provider.AccountID(&user); id != "" {
+			mappedUsers = append(mappedUsers, user)
+			accountIDs = append(accountIDs, id)
+		}
+	}
+
+	if len(mappedUsers) == 0 {
+		respondJSON(w, http.StatusOK, []TeamTask{})
+		return
+	}
+
+	itemsByAccount, syncTimes, err := provider.Load(r.Context(), accountIDs, r.URL.Query().Get("refresh") == "true", h.maxConcurrentAPIReqs)
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to load work items", err, "provider", provider.Name())
+		respondError(w, http.StatusInternalServerError, "Failed to connect to task tracker")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, buildTeamTasks(r.Context(), h.timeOffRepo, h.logger, mappedUsers, provider.AccountID, itemsByAccount, syncTimes, parseMaxPerUser(r)))
+}
+
+// teamMembers returns the user's direct reports, or every user for those who may view every team
+func teamMembers(ctx context.Context, userRepo repository.UserRepository, currentUser *models.User) ([]models.User, error) {
+	if authz.CanAll(currentUser, authz.ActionTeamView) {
+		return userRepo.GetAll(ctx)
+	}
+	return userRepo.GetDirectReportsBySupervisorID(ctx, currentUser.ID)
+}
+
+// parseMaxPerUser reads ?max_per_user, the most tasks returned for each employee
+func parseMaxPerUser(r *http.Request) int {
+	maxPerUser := 20
+	if maxStr := r.URL.Query().Get("max_per_user"); maxStr != "" {
+		if m, err := strconv.Atoi(maxStr); err == nil && m > 0 && m <= 50 {
+			maxPerUser = m
+		}
+	}
+	return maxPerUser
+}
+
+// buildTeamTasks pairs each user's work items with their employee info and how their approved
+// time off affects each item's due date. Users whose items failed to load are skipped.
+func buildTeamTasks(ctx context.Context, timeOffRepo repository.TimeOffRepository, log *logger.Logger, users []models.User, accountID func(*models.User) string, itemsByAccount map[string][]models.JiraIssue, syncTimes map[string]time.Time, maxPerUser int) []TeamTask {
+	teamTasks := []TeamTask{}
+	for _, user := range users {
+		id := accountID(&user)
+		items, ok := itemsByAccount[id]
+		if !ok {
+			// Already logged when loading
+			continue
+		}
+
+		var userTimeOff []models.TimeOffRequest
+		if timeOffRepo != nil {
+			timeOff, err := timeOffRepo.GetApprovedFutureTimeOffByUser(ctx, user.ID)
+			if err != nil {
+				log.WithContext(ctx).Warn("Failed to fetch time off for user", "user_id", user.ID, "error", err)
+			}
+			userTimeOff = timeOff
+		}
+
+		employee := TeamTaskEmployee{
+			ID:        user.ID,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Email:     user.Email,
+			AvatarURL: user.AvatarURL,
+		}
+		syncedAt := syncTimePtr(syncTimes, id)
+
+		for _, item := range items[:min(len(items), maxPerUser)] {
+			teamTasks = append(teamTasks, TeamTask{
+				JiraIssue:     item,
+				Employee:      employee,
+				TimeOffImpact: database.CalculateTimeOffImpact(item.DueDate, userTimeOff),
+				SyncedAt:      syncedAt,
+			})
+		}
+	}
+	return teamTasks
+}
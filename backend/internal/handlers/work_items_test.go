@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// newLinearIssuesServer answers assigned issue queries with one issue per user, due dueDate
+func newLinearIssuesServer(t *testing.T, dueDate time.Time) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		userID, _ := body.Variables["userId"].(string)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"issues": map[string]interface{}{
+					"nodes": []map[string]interface{}{{
+						"id":         "issue-" + userID,
+						"identifier": "ENG-" + userID,
+						"title":      "Work for " + userID,
+						"url":        "https://linear.app/acme/issue/ENG-" + userID,
+						"createdAt":  "2024-03-01T10:00:00Z",
+						"updatedAt":  "2024-03-02T10:00:00Z",
+						"dueDate":    dueDate.Format("2006-01-02"),
+						"state":      map[string]string{"name": "Todo", "type": "unstarted"},
+						"team":       map[string]string{"id": "team-1", "key": "ENG", "name": "Engineering"},
+						"assignee":   map[string]string{"id": userID, "name": userID},
+						"labels":     map[string]interface{}{"nodes": []interface{}{}},
+					}},
+				},
+			},
+		})
+	}))
+}
+
+func TestWorkItemHandlers_GetTeamTasks_Linear(t *testing.T) {
+	now := time.Now()
+	server := newLinearIssuesServer(t, now.AddDate(0, 0, 14))
+	defer server.Close()
+
+	supervisorID := int64(1)
+	ada, grace := "lin-ada", "lin-grace"
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.Users[2] = &models.User{ID: 2, FirstName: "Ada", SupervisorID: &supervisorID, IsActive: true, LinearUserID: &ada}
+	userRepo.Users[3] = &models.User{ID: 3, FirstName: "Grace", SupervisorID: &supervisorID, IsActive: true, LinearUserID: &grace}
+	userRepo.Users[4] = &models.User{ID: 4, FirstName: "Alan", SupervisorID: &supervisorID, IsActive: true}
+
+	timeOffRepo := mocks.NewMockTimeOffRepository()
+	timeOffRepo.GetApprovedFutureTimeOffByUserFunc = func(ctx context.Context, userID int64) ([]models.TimeOffRequest, error) {
+		if userID != 3 {
+			return nil, nil
+		}
+		return []models.TimeOffRequest{{UserID: 3, Status: models.TimeOffStatusApproved, StartDate: now, EndDate: now.AddDate(0, 0, 13)}}, nil
+	}
+
+	linearRepo := mocks.NewMockOrgLinearRepository()
+	linearRepo.Settings = &models.OrgLinearSettings{APIKey: "lin_api_key", WorkspaceName: "Acme"}
+	linearHandlers := NewLinearHandlers(userRepo, linearRepo, logger.Default())
+	linearHandlers.apiURL = server.URL
+
+	// Jira is listed first but isn't connected, so Linear is used
+	h := NewWorkItemHandlers(userRepo, timeOffRepo, 2, logger.Default(),
+		services.NewJiraWorkItemProvider(mocks.NewMockOrgJiraRepository(), nil, nil),
+		services.NewLinearWorkItemProvider(linearRepo, linearHandlers.ConnectLinear))
+
+	supervisor := &models.User{ID: supervisorID, Role: models.RoleSupervisor}
+	req := httptest.NewRequest(http.MethodGet, "/api/workitems/tasks/team", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), supervisor))
+	rr := httptest.NewRecorder()
+
+	h.GetTeamTasks(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var tasks []TeamTask
+	if err := json.NewDecoder(rr.Body).Decode(&tasks); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("got %d tasks, want 2 (unmapped users skipped)", len(tasks))
+	}
+	for _, task := range tasks {
+		switch task.Employee.ID {
+		case 2:
+			if task.Key != "ENG-lin-ada" || task.TimeOffImpact != nil {
+				t.Errorf("Ada's task = %+v", task)
+			}
+		case 3:
+			if task.Key != "ENG-lin-grace" || task.TimeOffImpact == nil {
+				t.Errorf("Grace's task should carry time off impact: %+v", task)
+			}
+		default:
+			t.Errorf("unexpected employee %d", task.Employee.ID)
+		}
+		if task.SyncedAt != nil {
+			t.Errorf("live Linear tasks shouldn't have a sync time")
+		}
+	}
+}
+
+func TestWorkItemHandlers_GetTeamTasks_Provider(t *testing.T) {
+	linearRepo := mocks.NewMockOrgLinearRepository()
+	h := NewWorkItemHandlers(mocks.NewMockUserRepository(), nil, 0, logger.Default(),
+		services.NewJiraWorkItemProvider(mocks.NewMockOrgJiraRepository(), nil, nil),
+		services.NewLinearWorkItemProvider(linearRepo, nil))
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"nothing connected", "", http.StatusNotFound},
+		{"requested tracker not connected", "?provider=linear", http.StatusNotFound},
+		{"unknown tracker", "?provider=asana", http.StatusBadRequest},
+	}
+
+	supervisor := &models.User{ID: 1, Role: models.RoleSupervisor}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/workitems/tasks/team"+tt.query, nil)
+			req = req.WithContext(ctxWithUserFrom(req.Context(), supervisor))
+			rr := httptest.NewRecorder()
+
+			h.GetTeamTasks(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// WorkScheduleHandlers handles the current user's working hours and focus time
+type WorkScheduleHandlers struct {
+	scheduleRepo repository.WorkScheduleRepository
+}
+
+// NewWorkScheduleHandlers creates a new work schedule handlers instance
+func NewWorkScheduleHandlers(scheduleRepo repository.WorkScheduleRepository) *WorkScheduleHandlers {
+	return &WorkScheduleHandlers{
+		scheduleRepo: scheduleRepo,
+	}
+}
+
+// GetWorkSchedule returns the current user's working hours and focus blocks, or the defaults if unset
+func (h *WorkScheduleHandlers) GetWorkSchedule(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	schedule, err := h.scheduleRepo.Get(r.Context(), currentUser.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch work schedule")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, schedule)
+}
+
+// UpdateWorkSchedule replaces the current user's working hours and focus blocks.
+// Times are minutes after midnight in the user's timezone.
+func (h *WorkScheduleHandlers) UpdateWorkSchedule(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	var req models.UpdateWorkScheduleRequest
+	if !decodeJSON(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	schedule, err := h.scheduleRepo.Save(r.Context(), currentUser.ID, &req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to save work schedule")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, schedule)
+}
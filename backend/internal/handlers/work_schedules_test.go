@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestWorkScheduleHandlers_GetWorkSchedule_Default(t *testing.T) {
+	h := NewWorkScheduleHandlers(mocks.NewMockWorkScheduleRepository())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar/work-schedule", nil)
+	req = req.WithContext(ctxWithUserFrom(req.Context(), &models.User{ID: 1}))
+	rr := httptest.NewRecorder()
+	h.GetWorkSchedule(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var schedule models.WorkSchedule
+	if err := json.Unmarshal(rr.Body.Bytes(), &schedule); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if schedule.StartMinute != 9*60 || schedule.EndMinute != 17*60 || len(schedule.Weekdays) != 5 || schedule.UpdatedAt != nil {
+		t.Errorf("schedule = %+v, want the unsaved default", schedule)
+	}
+}
+
+func TestWorkScheduleHandlers_UpdateWorkSchedule(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		body           string
+		expectedStatus int
+	}{
+		{"saves the schedule", &models.User{ID: 1}, `{"start_minute":480,"end_minute":960,"weekdays":[1,2,3,4],"focus_blocks":[{"weekday":5,"start_minute":540,"end_minute":720,"label":" Deep work "}]}`, http.StatusOK},
+		{"hours out of order", &models.User{ID: 1}, `{"start_minute":960,"end_minute":480,"weekdays":[1]}`, http.StatusBadRequest},
+		{"unauthenticated", nil, `{"start_minute":480,"end_minute":960,"weekdays":[1]}`, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduleRepo := mocks.NewMockWorkScheduleRepository()
+			h := NewWorkScheduleHandlers(scheduleRepo)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/calendar/work-schedule", strings.NewReader(tt.body))
+			if tt.currentUser != nil {
+				req = req.WithContext(ctxWithUserFrom(req.Context(), tt.currentUser))
+			}
+			rr := httptest.NewRecorder()
+			h.UpdateWorkSchedule(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			saved, ok := scheduleRepo.Schedules[1]
+			if rr.Code != http.StatusOK {
+				if ok {
+					t.Errorf("rejected request saved a schedule: %+v", saved)
+				}
+				return
+			}
+			if !ok || saved.StartMinute != 480 || len(saved.FocusBlocks) != 1 || saved.FocusBlocks[0].Label != "Deep work" {
+				t.Errorf("saved schedule = %+v", saved)
+			}
+		})
+	}
+}
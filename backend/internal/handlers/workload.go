@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+// WorkloadHandlers serve workload trends from the daily workload snapshots
+type WorkloadHandlers struct {
+	snapshots *services.WorkloadSnapshotService
+	userRepo  repository.UserRepository
+	logger    *logger.Logger
+}
+
+// NewWorkloadHandlers creates a new workload handlers instance
+func NewWorkloadHandlers(snapshots *services.WorkloadSnapshotService, userRepo repository.UserRepository) *WorkloadHandlers {
+	return &WorkloadHandlers{
+		snapshots: snapshots,
+		userRepo:  userRepo,
+		logger:    logger.Default().WithComponent("workload-handlers"),
+	}
+}
+
+// GetWorkloadTrend godoc
+// @Summary Get workload trends
+// @Description Returns daily snapshots of open and overdue tasks and unresolved Jira issues by status, per person and in total. Supervisors and admins get their direct reports by default; others get themselves. user_id selects one person: yourself or someone you manage.
+// @Tags Workload
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int false "Person to get the trend for"
+// @Param days query int false "Days to look back, today included" default(90)
+// @Success 200 {object} models.WorkloadTrend "Workload trend"
+// @Failure 400 {object} map[string]interface{} "Invalid user ID or days"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /workload/trend [get]
+func (h *WorkloadHandlers) GetWorkloadTrend(w http.ResponseWriter, r *http.Request) {
+	currentUser := requireAuth(w, r)
+	if currentUser == nil {
+		return
+	}
+
+	days := models.DefaultWorkloadTrendDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 1 || parsed > models.MaxWorkloadTrendDays {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("days must be between 1 and %d", models.MaxWorkloadTrendDays))
+			return
+		}
+		days = parsed
+	}
+
+	var users []models.User
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		userID, err := strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		target, err := h.userRepo.GetByID(r.Context(), userID)
+		if err != nil || target == nil {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		if target.ID != currentUser.ID && !authz.Can(currentUser, authz.ActionTeamView, authz.UserResource(target)) {
+			respondError(w, http.StatusForbidden, "You don't have permission to view this user's workload")
+			return
+		}
+		users = []models.User{*target}
+	} else if authz.CanForOthers(currentUser, authz.ActionTeamView) {
+		reports, err := h.userRepo.GetDirectReportsBySupervisorID(r.Context(), currentUser.ID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to fetch direct reports")
+			return
+		}
+		users = reports
+	} else {
+		users = []models.User{*currentUser}
+	}
+
+	trend, err := h.snapshots.Trend(r.Context(), users, days, time.Now())
+	if err != nil {
+		h.logger.LogError(r.Context(), "Failed to get workload trend", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch workload trend")
+		return
+	}
+	respondJSON(w, http.StatusOK, trend)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/services"
+)
+
+func TestWorkloadHandlers_GetWorkloadTrend(t *testing.T) {
+	supervisorID := int64(1)
+	supervisor := &models.User{ID: supervisorID, Role: models.RoleSupervisor}
+	employee := &models.User{ID: 2, Role: models.RoleEmployee, SupervisorID: &supervisorID}
+	other := &models.User{ID: 3, Role: models.RoleEmployee}
+
+	tests := []struct {
+		name           string
+		currentUser    *models.User
+		query          string
+		expectedStatus int
+		expectedUsers  []int64
+	}{
+		{"supervisor gets their direct reports", supervisor, "", http.StatusOK, []int64{2}},
+		{"supervisor gets a report", supervisor, "?user_id=2&days=30", http.StatusOK, []int64{2}},
+		{"employee gets themselves", employee, "", http.StatusOK, []int64{2}},
+		{"someone else's workload", employee, "?user_id=3", http.StatusForbidden, nil},
+		{"unknown user", supervisor, "?user_id=99", http.StatusNotFound, nil},
+		{"too many days", supervisor, "?days=1000", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewMockUserRepository()
+			for _, u := range []*models.User{supervisor, employee, other} {
+				userRepo.Users[u.ID] = u
+			}
+			snapshots := mocks.NewMockWorkloadSnapshotRepository()
+			snapshots.Snapshots = []models.WorkloadSnapshot{
+				{UserID: 2, Date: time.Now().UTC().Format("2006-01-02"), OpenTasks: 4},
+				{UserID: 3, Date: time.Now().UTC().Format("2006-01-02"), OpenTasks: 7},
+			}
+			h := NewWorkloadHandlers(services.NewWorkloadSnapshotService(snapshots, 6), userRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/workload/trend"+tt.query, nil)
+			req = req.WithContext(ctxWithUser(tt.currentUser))
+			rr := httptest.NewRecorder()
+			h.GetWorkloadTrend(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var trend models.WorkloadTrend
+			if err := json.Unmarshal(rr.Body.Bytes(), &trend); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			var userIDs []int64
+			for _, u := range trend.Users {
+				userIDs = append(userIDs, u.UserID)
+			}
+			if len(userIDs) != len(tt.expectedUsers) || userIDs[0] != tt.expectedUsers[0] {
+				t.Errorf("users = %v, want %v", userIDs, tt.expectedUsers)
+			}
+			if len(trend.Totals) != 1 || trend.Totals[0].OpenTasks != 4 {
+				t.Errorf("totals = %+v, want only user 2's open tasks", trend.Totals)
+			}
+		})
+	}
+}
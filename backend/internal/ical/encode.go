@@ -0,0 +1,130 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding
+const maxLineOctets = 75
+
+// partStats maps attendee responses to iCalendar participation statuses
+var partStats = map[models.ResponseStatus]string{
+	models.ResponseStatusPending:   "NEEDS-ACTION",
+	models.ResponseStatusAccepted:  "ACCEPTED",
+	models.ResponseStatusDeclined:  "DECLINED",
+	models.ResponseStatusTentative: "TENTATIVE",
+}
+
+// Encode writes meetings as an iCalendar document.
+// Meetings without an imported UID get one of the form meeting-{id}@{uidDomain}.
+// Edited or cancelled occurrences of a series are excluded from it with EXDATE;
+// edited occurrences are exported as meetings of their own.
+func Encode(meetings []models.Meeting, calendarName, uidDomain string) []byte {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//Tava Team Dashboard//Meetings//EN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if calendarName != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(calendarName))
+	}
+
+	for i := range meetings {
+		m := &meetings[i]
+		if m.IsCancelled {
+			continue
+		}
+
+		uid := fmt.Sprintf("meeting-%d@%s", m.ID, uidDomain)
+		if m.ICalUID != nil && *m.ICalUID != "" {
+			uid = *m.ICalUID
+		}
+
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+uid)
+		writeLine(&b, "DTSTAMP:"+formatUTC(m.UpdatedAt))
+		writeLine(&b, "DTSTART"+formatMeetingTime(m, m.StartTime))
+		writeLine(&b, "DTEND"+formatMeetingTime(m, m.EndTime))
+		writeLine(&b, "SUMMARY:"+escapeText(m.Title))
+		if m.Description != nil && *m.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(*m.Description))
+		}
+		if m.RecurrenceType != nil {
+			writeLine(&b, "RRULE:"+formatRRule(m))
+
+			exceptions := append([]time.Time(nil), m.ExceptionStarts...)
+			sort.Slice(exceptions, func(i, j int) bool { return exceptions[i].Before(exceptions[j]) })
+			for _, start := range exceptions {
+				writeLine(&b, "EXDATE"+formatMeetingTime(m, start))
+			}
+		}
+		for _, attendee := range m.Attendees {
+			if attendee.User == nil || attendee.User.Email == "" {
+				continue
+			}
+			name := strings.TrimSpace(attendee.User.FirstName + " " + attendee.User.LastName)
+			partStat, ok := partStats[attendee.ResponseStatus]
+			if !ok {
+				partStat = "NEEDS-ACTION"
+			}
+			writeLine(&b, fmt.Sprintf(`ATTENDEE;CN="%s";PARTSTAT=%s:mailto:%s`,
+				strings.ReplaceAll(name, `"`, "'"), partStat, attendee.User.Email))
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// formatUTC formats a time as an iCalendar UTC DATE-TIME
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// formatMeetingTime formats a DATE-TIME property value, including the leading parameters and colon.
+// Recurring meetings outside UTC are written in local time with a TZID so clients keep occurrences
+// at the same wall-clock time across DST changes, as the meeting's own expansion does.
+func formatMeetingTime(m *models.Meeting, t time.Time) string {
+	loc := m.Location()
+	if m.RecurrenceType == nil || loc == time.UTC {
+		return ":" + formatUTC(t)
+	}
+	return ";TZID=" + loc.String() + ":" + t.In(loc).Format("20060102T150405")
+}
+
+// escapeText escapes a TEXT value
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// writeLine writes a content line, folding it at 75 octets without splitting UTF-8 characters
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines begin with a space, which counts toward the limit
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
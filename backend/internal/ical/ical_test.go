@@ -0,0 +1,248 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const sampleCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Example//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:weekly-sync@example.com\r\n" +
+	"SUMMARY:Weekly sync\\, platform team\r\n" +
+	"DESCRIPTION:Agenda:\\n1. Roadmap\r\n" +
+	"DTSTART;TZID=America/Denver:20260302T090000\r\n" +
+	"DURATION:PT30M\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=4\r\n" +
+	"ORGANIZER;CN=Ada:mailto:Ada@Example.com\r\n" +
+	"ATTENDEE;CN=\"Turing, Alan\";PARTSTAT=ACCEPTED:mailto:alan@example.com\r\n" +
+	"ATTENDEE;CN=Grace Hop\r\n" +
+	" per:mailto:grace@example.com\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"TRIGGER:-PT10M\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:offsite@example.com\r\n" +
+	"SUMMARY:Offsite\r\n" +
+	"DTSTART;VALUE=DATE:20260310\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:broken@example.com\r\n" +
+	"SUMMARY:Broken\r\n" +
+	"DTSTART;TZID=Nowhere/Special:20260310T090000\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	events, err := Parse([]byte(sampleCalendar))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Parse() returned %d events, want 3", len(events))
+	}
+
+	sync := events[0]
+	denver, _ := time.LoadLocation("America/Denver")
+	if !sync.Start.Equal(time.Date(2026, 3, 2, 9, 0, 0, 0, denver)) {
+		t.Errorf("Start = %v, want 9am Denver", sync.Start)
+	}
+	if sync.End.Sub(sync.Start) != 30*time.Minute {
+		t.Errorf("duration = %v, want 30m", sync.End.Sub(sync.Start))
+	}
+	if sync.Summary != "Weekly sync, platform team" || sync.Description != "Agenda:\n1. Roadmap" {
+		t.Errorf("Summary/Description not unescaped: %q / %q", sync.Summary, sync.Description)
+	}
+	if sync.OrganizerEmail != "ada@example.com" {
+		t.Errorf("OrganizerEmail = %q", sync.OrganizerEmail)
+	}
+	if strings.Join(sync.AttendeeEmails, ",") != "alan@example.com,grace@example.com" {
+		t.Errorf("AttendeeEmails = %v", sync.AttendeeEmails)
+	}
+
+	offsite := events[1]
+	if !offsite.AllDay || offsite.End.Sub(offsite.Start) != 24*time.Hour {
+		t.Errorf("all-day event = %+v, want one full day", offsite)
+	}
+
+	if events[2].Err == nil {
+		t.Error("expected an error for an unknown time zone")
+	}
+}
+
+func TestParse_NotCalendar(t *testing.T) {
+	if _, err := Parse([]byte("hello")); err == nil {
+		t.Error("expected error for non-iCalendar input")
+	}
+	if _, err := Parse([]byte("BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:x\n")); err == nil {
+		t.Error("expected error for unterminated event")
+	}
+}
+
+func TestEvent_MeetingRequest(t *testing.T) {
+	start := time.Date(2026, 3, 2, 16, 0, 0, 0, time.UTC) // A Monday
+
+	tests := []struct {
+		name     string
+		rrule    string
+		wantErr  bool
+		wantType models.RecurrenceType
+		wantEnd  *time.Time
+		check    func(t *testing.T, req *models.CreateMeetingRequest)
+	}{
+		{
+			name:     "weekly with count",
+			rrule:    "FREQ=WEEKLY;BYDAY=MO;COUNT=4",
+			wantType: models.RecurrenceTypeWeekly,
+			wantEnd:  ptrTime(start.AddDate(0, 0, 21)),
+			check: func(t *testing.T, req *models.CreateMeetingRequest) {
+				if len(req.RecurrenceDaysOfWeek) != 1 || req.RecurrenceDaysOfWeek[0] != int(time.Monday) {
+					t.Errorf("RecurrenceDaysOfWeek = %v, want [1]", req.RecurrenceDaysOfWeek)
+				}
+			},
+		},
+		{
+			name:     "daily every other day until a date",
+			rrule:    "FREQ=DAILY;INTERVAL=2;UNTIL=20260320",
+			wantType: models.RecurrenceTypeDaily,
+			wantEnd:  ptrTime(time.Date(2026, 3, 20, 23, 59, 59, 0, time.UTC)),
+			check: func(t *testing.T, req *models.CreateMeetingRequest) {
+				if *req.RecurrenceInterval != 2 {
+					t.Errorf("RecurrenceInterval = %d, want 2", *req.RecurrenceInterval)
+				}
+			},
+		},
+		{
+			name:     "monthly on start day",
+			rrule:    "FREQ=MONTHLY;BYMONTHDAY=2",
+			wantType: models.RecurrenceTypeMonthly,
+			check: func(t *testing.T, req *models.CreateMeetingRequest) {
+				if req.RecurrenceDayOfMonth == nil || *req.RecurrenceDayOfMonth != 2 {
+					t.Errorf("RecurrenceDayOfMonth = %v, want 2", req.RecurrenceDayOfMonth)
+				}
+			},
+		},
+		{name: "yearly unsupported", rrule: "FREQ=YEARLY", wantErr: true},
+		{name: "several weekdays unsupported", rrule: "FREQ=WEEKLY;BYDAY=MO,WE", wantErr: true},
+		{name: "monthly by weekday unsupported", rrule: "FREQ=MONTHLY;BYDAY=2TU", wantErr: true},
+		{name: "unknown rule part", rrule: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+		{name: "excessive count", rrule: "FREQ=DAILY;COUNT=100000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := Event{UID: "abc", Summary: "Sync", Start: start, End: start.Add(time.Hour), RRule: tt.rrule}
+			req, err := event.MeetingRequest()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MeetingRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if req.ICalUID == nil || *req.ICalUID != "abc" {
+				t.Errorf("ICalUID = %v, want abc", req.ICalUID)
+			}
+			if req.RecurrenceType == nil || *req.RecurrenceType != tt.wantType {
+				t.Errorf("RecurrenceType = %v, want %s", req.RecurrenceType, tt.wantType)
+			}
+			if tt.wantEnd != nil && (req.RecurrenceEndDate == nil || !req.RecurrenceEndDate.Equal(*tt.wantEnd)) {
+				t.Errorf("RecurrenceEndDate = %v, want %v", req.RecurrenceEndDate, tt.wantEnd)
+			}
+			if tt.check != nil {
+				tt.check(t, req)
+			}
+			if err := req.Validate(); err != nil {
+				t.Errorf("converted request fails validation: %v", err)
+			}
+		})
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	weekly := models.RecurrenceTypeWeekly
+	start := time.Date(2026, 3, 2, 16, 0, 0, 0, time.UTC)
+	until := start.AddDate(0, 2, 0)
+	description := "Notes; see doc, section 2\nBring laptops"
+	meetings := []models.Meeting{
+		{
+			ID:                 7,
+			Title:              "Weekly sync with a title long enough that the SUMMARY line must be folded — twice",
+			Description:        &description,
+			StartTime:          start,
+			EndTime:            start.Add(30 * time.Minute),
+			RecurrenceType:     &weekly,
+			RecurrenceInterval: 2,
+			RecurrenceEndDate:  &until,
+			ExceptionStarts:    []time.Time{start.AddDate(0, 0, 14)},
+			Attendees: []models.MeetingAttendee{
+				{ResponseStatus: models.ResponseStatusAccepted, User: &models.User{FirstName: "Alan", LastName: "Turing", Email: "alan@example.com"}},
+			},
+		},
+		{ID: 8, Title: "Cancelled", StartTime: start, EndTime: start.Add(time.Hour), IsCancelled: true},
+	}
+
+	data := Encode(meetings, "Ada - Meetings", "example.test")
+	for _, line := range strings.Split(string(data), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line exceeds %d octets: %q", maxLineOctets, line)
+		}
+	}
+	if !strings.Contains(string(data), "EXDATE:20260316T160000Z") {
+		t.Errorf("expected EXDATE for the exception:\n%s", data)
+	}
+
+	events, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Parse() returned %d events, want 1 (cancelled meetings are skipped)", len(events))
+	}
+	event := events[0]
+	if event.UID != "meeting-7@example.test" {
+		t.Errorf("UID = %q", event.UID)
+	}
+	if event.Summary != meetings[0].Title || event.Description != description {
+		t.Errorf("text did not round trip: %q / %q", event.Summary, event.Description)
+	}
+	if event.RRule != "FREQ=WEEKLY;INTERVAL=2;UNTIL=20260502T160000Z" {
+		t.Errorf("RRule = %q", event.RRule)
+	}
+	if len(event.AttendeeEmails) != 1 || event.AttendeeEmails[0] != "alan@example.com" {
+		t.Errorf("AttendeeEmails = %v", event.AttendeeEmails)
+	}
+}
+
+func TestEncode_RecurringMeetingTimezone(t *testing.T) {
+	weekly := models.RecurrenceTypeWeekly
+	start := time.Date(2026, 3, 2, 16, 0, 0, 0, time.UTC)
+	meetings := []models.Meeting{{
+		ID: 9, Title: "Standup", StartTime: start, EndTime: start.Add(15 * time.Minute),
+		RecurrenceType: &weekly, RecurrenceInterval: 1, Timezone: "America/Denver",
+	}}
+
+	data := Encode(meetings, "", "example.test")
+	if !strings.Contains(string(data), "DTSTART;TZID=America/Denver:20260302T090000") {
+		t.Fatalf("expected DTSTART in the meeting's timezone:\n%s", data)
+	}
+
+	events, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	req, err := events[0].MeetingRequest()
+	if err != nil {
+		t.Fatalf("MeetingRequest() error = %v", err)
+	}
+	if !req.StartTime.Equal(start) || req.Timezone == nil || *req.Timezone != "America/Denver" {
+		t.Errorf("start = %v in %v, want %v in America/Denver", req.StartTime, req.Timezone, start)
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
@@ -0,0 +1,290 @@
+// Package ical reads and writes the subset of iCalendar (RFC 5545) needed to
+// exchange meetings with external calendar tools.
+package ical
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Resolve TZID parameters even where the host has no zoneinfo database
+)
+
+// Event is a VEVENT read from an iCalendar document
+type Event struct {
+	UID            string
+	Summary        string
+	Description    string
+	Start          time.Time
+	End            time.Time
+	AllDay         bool
+	RRule          string
+	RecurrenceID   *time.Time // Set when the event overrides one occurrence of a recurring event
+	Status         string
+	OrganizerEmail string
+	AttendeeEmails []string
+	Err            error // Why the event could not be read, if it couldn't
+}
+
+// property is a single unfolded content line
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// durationPattern matches the RFC 5545 DURATION value forms used for events
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// Parse reads every VEVENT in an iCalendar document.
+// Events that can't be interpreted are returned with Err set so callers can report them.
+func Parse(data []byte) ([]Event, error) {
+	lines := unfold(strings.TrimSpace(strings.TrimPrefix(string(data), "\ufeff")))
+	if len(lines) == 0 || !strings.EqualFold(strings.TrimSpace(lines[0]), "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("not an iCalendar document")
+	}
+
+	var events []Event
+	var current []property
+	inEvent := false
+	nested := 0 // Depth of components inside the event, such as VALARM
+
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseContentLine(line)
+		if err != nil {
+			if inEvent {
+				current = append(current, property{name: "X-INVALID", value: err.Error()})
+			}
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && !inEvent:
+			inEvent = true
+			current = nil
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT") && inEvent && nested == 0:
+			events = append(events, buildEvent(current))
+			inEvent = false
+		case inEvent && prop.name == "BEGIN":
+			nested++
+		case inEvent && prop.name == "END":
+			nested--
+		case inEvent && nested == 0:
+			current = append(current, prop)
+		}
+	}
+
+	if inEvent {
+		return nil, fmt.Errorf("unterminated VEVENT")
+	}
+	return events, nil
+}
+
+// unfold joins continuation lines and splits the document into content lines
+func unfold(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	raw := strings.Split(data, "\n")
+	lines := make([]string, 0, len(raw))
+	for _, line := range raw {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	return lines
+}
+
+// parseContentLine splits "NAME;PARAM=value:VALUE" into its parts, honoring quoted parameter values
+func parseContentLine(line string) (property, error) {
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("malformed line %q", line)
+	}
+
+	prop := property{params: make(map[string]string), value: line[colon+1:]}
+	parts := splitUnquoted(line[:colon], ';')
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			continue
+		}
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// splitUnquoted splits s on sep, ignoring separators inside double quotes
+func splitUnquoted(s string, sep rune) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i, r := range s {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == sep && !inQuotes {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// buildEvent interprets the properties of a single VEVENT
+func buildEvent(props []property) Event {
+	var event Event
+	var duration *time.Duration
+	hasEnd := false
+
+	for _, prop := range props {
+		var err error
+		switch prop.name {
+		case "X-INVALID":
+			err = fmt.Errorf("%s", prop.value)
+		case "UID":
+			event.UID = prop.value
+		case "SUMMARY":
+			event.Summary = unescapeText(prop.value)
+		case "DESCRIPTION":
+			event.Description = unescapeText(prop.value)
+		case "DTSTART":
+			event.Start, event.AllDay, err = parseDateTime(prop.value, prop.params)
+		case "DTEND":
+			event.End, _, err = parseDateTime(prop.value, prop.params)
+			hasEnd = true
+		case "DURATION":
+			var d time.Duration
+			d, err = parseDuration(prop.value)
+			duration = &d
+		case "RRULE":
+			event.RRule = prop.value
+		case "RECURRENCE-ID":
+			var t time.Time
+			t, _, err = parseDateTime(prop.value, prop.params)
+			event.RecurrenceID = &t
+		case "STATUS":
+			event.Status = strings.ToUpper(prop.value)
+		case "ORGANIZER":
+			event.OrganizerEmail = mailtoAddress(prop.value)
+		case "ATTENDEE":
+			if email := mailtoAddress(prop.value); email != "" {
+				event.AttendeeEmails = append(event.AttendeeEmails, email)
+			}
+		}
+		if err != nil && event.Err == nil {
+			event.Err = fmt.Errorf("invalid %s: %w", prop.name, err)
+		}
+	}
+
+	if event.Err != nil {
+		return event
+	}
+	if event.Start.IsZero() {
+		event.Err = fmt.Errorf("missing DTSTART")
+		return event
+	}
+
+	// RFC 5545: without DTEND or DURATION an event lasts one day if all-day and is instantaneous otherwise
+	switch {
+	case hasEnd:
+	case duration != nil:
+		event.End = event.Start.Add(*duration)
+	case event.AllDay:
+		event.End = event.Start.AddDate(0, 0, 1)
+	default:
+		event.End = event.Start
+	}
+	return event
+}
+
+// parseDateTime parses DATE and DATE-TIME values, applying any TZID parameter
+func parseDateTime(value string, params map[string]string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		var err error
+		loc, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown time zone %q", tzid)
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseDuration parses an RFC 5545 DURATION such as PT1H30M or P1D
+func parseDuration(value string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(value)
+	if m == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("unrecognized duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(m[i+2])
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// mailtoAddress extracts the lowercased address from a mailto: URI
+func mailtoAddress(value string) string {
+	if len(value) < 7 || !strings.EqualFold(value[:7], "mailto:") {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(value[7:]))
+}
+
+// unescapeText reverses TEXT value escaping
+func unescapeText(value string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,181 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// maxRecurrenceCount bounds COUNT so converting it to an end date stays cheap
+const maxRecurrenceCount = 5000
+
+// weekdayCodes maps RFC 5545 BYDAY codes to time.Weekday values, which recurrence_days_of_week uses
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// frequencies maps RFC 5545 FREQ values to the recurrence types meetings support
+var frequencies = map[string]models.RecurrenceType{
+	"DAILY":   models.RecurrenceTypeDaily,
+	"WEEKLY":  models.RecurrenceTypeWeekly,
+	"MONTHLY": models.RecurrenceTypeMonthly,
+}
+
+// MeetingRequest converts the event into a request to create a meeting.
+// Recurrence rules are mapped onto the meeting recurrence fields; rules that can't be
+// represented there (yearly, several weekdays, "second Tuesday", ...) return an error.
+func (e *Event) MeetingRequest() (*models.CreateMeetingRequest, error) {
+	if e.Err != nil {
+		return nil, e.Err
+	}
+
+	req := &models.CreateMeetingRequest{
+		Title:     strings.TrimSpace(e.Summary),
+		StartTime: e.Start,
+		EndTime:   e.End,
+	}
+	if req.Title == "" {
+		req.Title = "(No title)"
+	}
+	if len(req.Title) > 255 {
+		req.Title = strings.ToValidUTF8(req.Title[:255], "")
+	}
+	if e.Description != "" {
+		description := e.Description
+		req.Description = &description
+	}
+	if e.UID != "" {
+		uid := e.UID
+		req.ICalUID = &uid
+	}
+
+	// Events written with a TZID keep it so recurrences stay at the same local time
+	if loc := e.Start.Location(); loc != time.UTC && loc != time.Local {
+		timezone := loc.String()
+		req.Timezone = &timezone
+	}
+
+	if e.RRule != "" {
+		if err := applyRRule(req, e.RRule); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// applyRRule fills the recurrence fields of req from an RRULE value
+func applyRRule(req *models.CreateMeetingRequest, rrule string) error {
+	rule := make(map[string]string)
+	for _, part := range strings.Split(rrule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return fmt.Errorf("malformed RRULE %q", rrule)
+		}
+		rule[strings.ToUpper(key)] = strings.ToUpper(value)
+	}
+
+	for key := range rule {
+		switch key {
+		case "FREQ", "INTERVAL", "UNTIL", "COUNT", "BYDAY", "BYMONTHDAY", "WKST":
+		default:
+			return fmt.Errorf("unsupported recurrence rule part %s", key)
+		}
+	}
+
+	recurrenceType, ok := frequencies[rule["FREQ"]]
+	if !ok {
+		return fmt.Errorf("unsupported recurrence frequency %q", rule["FREQ"])
+	}
+	req.RecurrenceType = &recurrenceType
+
+	interval := 1
+	if raw, ok := rule["INTERVAL"]; ok {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid recurrence interval %q", raw)
+		}
+		interval = n
+	}
+	req.RecurrenceInterval = &interval
+
+	// Meetings repeat on the weekday and day of month of their first occurrence
+	switch recurrenceType {
+	case models.RecurrenceTypeWeekly:
+		if raw, ok := rule["BYDAY"]; ok {
+			day, ok := weekdayCodes[raw]
+			if !ok {
+				return fmt.Errorf("unsupported weekly recurrence on %q", raw)
+			}
+			if day != req.StartTime.Weekday() {
+				return fmt.Errorf("weekly recurrence must fall on the first occurrence's weekday")
+			}
+		}
+		req.RecurrenceDaysOfWeek = []int{int(req.StartTime.Weekday())}
+	case models.RecurrenceTypeMonthly:
+		if _, ok := rule["BYDAY"]; ok {
+			return fmt.Errorf("monthly recurrence by weekday is not supported")
+		}
+		if raw, ok := rule["BYMONTHDAY"]; ok && raw != strconv.Itoa(req.StartTime.Day()) {
+			return fmt.Errorf("monthly recurrence must fall on the first occurrence's day of month")
+		}
+		day := req.StartTime.Day()
+		req.RecurrenceDayOfMonth = &day
+	default:
+		if _, ok := rule["BYDAY"]; ok {
+			return fmt.Errorf("daily recurrence limited to weekdays is not supported")
+		}
+	}
+
+	if raw, ok := rule["UNTIL"]; ok {
+		until, allDay, err := parseDateTime(raw, nil)
+		if err != nil {
+			return fmt.Errorf("invalid recurrence end %q", raw)
+		}
+		if allDay {
+			// A date-only UNTIL includes occurrences on that day
+			until = until.AddDate(0, 0, 1).Add(-time.Second)
+		}
+		req.RecurrenceEndDate = &until
+	} else if raw, ok := rule["COUNT"]; ok {
+		count, err := strconv.Atoi(raw)
+		if err != nil || count < 1 || count > maxRecurrenceCount {
+			return fmt.Errorf("invalid recurrence count %q", raw)
+		}
+		series := models.Meeting{StartTime: req.StartTime, RecurrenceType: req.RecurrenceType, RecurrenceInterval: interval}
+		last := req.StartTime
+		for i := 1; i < count; i++ {
+			last = series.NextOccurrence(last)
+		}
+		req.RecurrenceEndDate = &last
+	}
+
+	return nil
+}
+
+// formatRRule builds the RRULE value for a recurring meeting
+func formatRRule(m *models.Meeting) string {
+	var freq string
+	for code, recurrenceType := range frequencies {
+		if recurrenceType == *m.RecurrenceType {
+			freq = code
+		}
+	}
+
+	parts := []string{"FREQ=" + freq}
+	if m.RecurrenceInterval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", m.RecurrenceInterval))
+	}
+	if m.RecurrenceEndDate != nil {
+		parts = append(parts, "UNTIL="+formatUTC(*m.RecurrenceEndDate))
+	}
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,231 @@
+// Package identity validates the bearer tokens users sign in with. Each Provider trusts one OpenID
+// Connect issuer, such as Auth0, Okta, Azure AD, or a broker that signs users in with SAML and
+// issues OIDC tokens for them, and a Verifier sends each token to the provider that issued it.
+package identity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/auth0/go-jwt-middleware/v2/jwks"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/smith-dallin/manager-dashboard/internal/tracing"
+)
+
+// defaultJWKSCacheTTL is how long signing keys are cached when the provider doesn't say
+const defaultJWKSCacheTTL = 5 * time.Minute
+
+// ErrUntrustedIssuer is returned for tokens issued by a provider that isn't configured
+var ErrUntrustedIssuer = errors.New("token issuer is not trusted")
+
+// Claims are the profile claims read from tokens, beyond the registered ones
+type Claims struct {
+	Email         string       `json:"email"`
+	EmailVerified verifiedFlag `json:"email_verified"`
+	Name          string       `json:"name"`
+	GivenName     string       `json:"given_name"`
+	FamilyName    string       `json:"family_name"`
+}
+
+// verifiedFlag reads email_verified, which some providers, such as Cognito, send as a string
+type verifiedFlag bool
+
+func (f *verifiedFlag) UnmarshalJSON(data []byte) error {
+	*f = verifiedFlag(strings.Trim(string(data), `"`) == "true")
+	return nil
+}
+
+// Validate satisfies validator.CustomClaims; profile claims are optional
+func (c Claims) Validate(ctx context.Context) error {
+	return nil
+}
+
+// Identity is who a validated token was issued to
+type Identity struct {
+	// Subject identifies the user across sign-ins. It is stored as the user's auth0_id.
+	Subject string
+	Email   string
+	// EmailVerified is whether the provider vouches that the user owns Email. Only then may they
+	// claim an account made for it.
+	EmailVerified bool
+	Name          string
+	Provider      string
+	// TokenID identifies the token for revocation: its jti claim, or a hash of the token when the
+	// provider doesn't set one
+	TokenID   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	Claims    *validator.ValidatedClaims
+}
+
+// ProviderConfig configures a trusted OIDC provider
+type ProviderConfig struct {
+	// Name identifies the provider in logs and errors, e.g. "auth0" or "okta"
+	Name string
+	// IssuerURL must equal the iss claim of the provider's tokens exactly
+	IssuerURL string
+	Audience  string
+	// JWKSURL overrides discovering the signing keys from the issuer's OpenID configuration
+	JWKSURL      string
+	JWKSCacheTTL time.Duration
+	// SubjectPrefix namespaces the provider's subjects, so two providers can't sign in as the
+	// same user. Auth0 subjects already carry their connection ("auth0|...") and need none.
+	SubjectPrefix string
+}
+
+// Provider validates tokens issued by one OIDC provider
+type Provider struct {
+	name          string
+	issuer        string
+	subjectPrefix string
+	validator     *validator.Validator
+}
+
+// NewProvider creates a provider that fetches and caches the issuer's signing keys
+func NewProvider(cfg ProviderConfig) (*Provider, error) {
+	issuerURL, err := url.Parse(cfg.IssuerURL)
+	if err != nil || issuerURL.Scheme == "" || issuerURL.Host == "" {
+		return nil, fmt.Errorf("invalid issuer URL for %s: %q", cfg.Name, cfg.IssuerURL)
+	}
+
+	opts := []jwks.ProviderOption{jwks.WithCustomClient(&http.Client{Transport: tracing.Transport(nil)})}
+	if cfg.JWKSURL != "" {
+		jwksURL, err := url.Parse(cfg.JWKSURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS URL for %s: %w", cfg.Name, err)
+		}
+		opts = append(opts, jwks.WithCustomJWKSURI(jwksURL))
+	}
+	cacheTTL := cfg.JWKSCacheTTL
+	if cacheTTL <= 0 {
+		cacheTTL = defaultJWKSCacheTTL
+	}
+	keys := jwks.NewCachingProvider(issuerURL, cacheTTL, opts...)
+
+	jwtValidator, err := validator.New(
+		keys.KeyFunc,
+		validator.RS256,
+		cfg.IssuerURL,
+		[]string{cfg.Audience},
+		validator.WithCustomClaims(func() validator.CustomClaims {
+			return &Claims{}
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWT validator for %s: %w", cfg.Name, err)
+	}
+
+	return &Provider{
+		name:          cfg.Name,
+		issuer:        cfg.IssuerURL,
+		subjectPrefix: cfg.SubjectPrefix,
+		validator:     jwtValidator,
+	}, nil
+}
+
+// Name returns the provider's name
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Verify validates a token's signature, issuer, audience, and expiry
+func (p *Provider) Verify(ctx context.Context, token string) (*Identity, error) {
+	result, err := p.validator.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	validated, ok := result.(*validator.ValidatedClaims)
+	if !ok {
+		return nil, errors.New("invalid claims")
+	}
+	claims, ok := validated.CustomClaims.(*Claims)
+	if !ok {
+		claims = &Claims{}
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = strings.TrimSpace(claims.GivenName + " " + claims.FamilyName)
+	}
+	registered := validated.RegisteredClaims
+	tokenID := registered.ID
+	if tokenID == "" {
+		sum := sha256.Sum256([]byte(token))
+		tokenID = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return &Identity{
+		Subject:       p.subjectPrefix + registered.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          name,
+		Provider:      p.name,
+		TokenID:       tokenID,
+		IssuedAt:      unixTime(registered.IssuedAt),
+		ExpiresAt:     unixTime(registered.Expiry),
+		Claims:        validated,
+	}, nil
+}
+
+// unixTime converts a NumericDate claim, leaving it zero when the claim is absent
+func unixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+// Verifier validates tokens from any of several providers
+type Verifier struct {
+	providers map[string]*Provider // by issuer
+}
+
+// NewVerifier creates a verifier trusting each provider's issuer
+func NewVerifier(providers ...*Provider) *Verifier {
+	v := &Verifier{providers: make(map[string]*Provider, len(providers))}
+	for _, provider := range providers {
+		v.providers[provider.issuer] = provider
+	}
+	return v
+}
+
+// Verify validates a token with the provider that issued it
+func (v *Verifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	issuer, err := unverifiedIssuer(token)
+	if err != nil {
+		return nil, err
+	}
+	provider, ok := v.providers[issuer]
+	if !ok {
+		return nil, ErrUntrustedIssuer
+	}
+	return provider.Verify(ctx, token)
+}
+
+// unverifiedIssuer reads a token's iss claim without checking its signature, only to pick the
+// provider that will
+func unverifiedIssuer(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("malformed token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("malformed token")
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", errors.New("malformed token")
+	}
+	return claims.Issuer, nil
+}
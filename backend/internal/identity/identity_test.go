@@ -0,0 +1,236 @@
+package identity
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testKeyID = "test-key"
+
+// testIssuer serves a JWKS for a freshly generated key and signs tokens with it
+type testIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	issuer := &testIssuer{key: key}
+	issuer.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": testKeyID,
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *testIssuer) url() string {
+	return i.server.URL + "/"
+}
+
+func (i *testIssuer) provider(t *testing.T, name, prefix string) *Provider {
+	t.Helper()
+	provider, err := NewProvider(ProviderConfig{
+		Name:          name,
+		IssuerURL:     i.url(),
+		Audience:      "dashboard",
+		JWKSURL:       i.server.URL + "/jwks.json",
+		SubjectPrefix: prefix,
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	return provider
+}
+
+func (i *testIssuer) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": testKeyID})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (i *testIssuer) claims(subject string) map[string]any {
+	return map[string]any{
+		"iss":   i.url(),
+		"aud":   "dashboard",
+		"sub":   subject,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"email": "ada@example.com",
+	}
+}
+
+func TestProvider_Verify(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := issuer.provider(t, "okta", "okta|")
+
+	claims := issuer.claims("00u123")
+	claims["given_name"] = "Ada"
+	claims["family_name"] = "Lovelace"
+
+	ident, err := provider.Verify(context.Background(), issuer.sign(t, claims))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if ident.Subject != "okta|00u123" {
+		t.Errorf("Subject = %q, want okta|00u123", ident.Subject)
+	}
+	if ident.Email != "ada@example.com" {
+		t.Errorf("Email = %q, want ada@example.com", ident.Email)
+	}
+	if ident.Name != "Ada Lovelace" {
+		t.Errorf("Name = %q, want the given and family names", ident.Name)
+	}
+	if ident.Provider != "okta" {
+		t.Errorf("Provider = %q, want okta", ident.Provider)
+	}
+	if !strings.HasPrefix(ident.TokenID, "sha256:") {
+		t.Errorf("TokenID = %q, want a hash of the token without a jti", ident.TokenID)
+	}
+	if ident.IssuedAt.IsZero() || ident.ExpiresAt.IsZero() {
+		t.Errorf("IssuedAt = %v, ExpiresAt = %v, want both set", ident.IssuedAt, ident.ExpiresAt)
+	}
+	if ident.EmailVerified {
+		t.Error("EmailVerified = true, want false without an email_verified claim")
+	}
+
+	claims["jti"] = "token-1"
+	ident, err = provider.Verify(context.Background(), issuer.sign(t, claims))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if ident.TokenID != "token-1" {
+		t.Errorf("TokenID = %q, want the jti", ident.TokenID)
+	}
+
+	for _, verified := range []any{true, "true"} {
+		claims["email_verified"] = verified
+		ident, err = provider.Verify(context.Background(), issuer.sign(t, claims))
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if !ident.EmailVerified {
+			t.Errorf("EmailVerified = false, want true for email_verified %#v", verified)
+		}
+	}
+}
+
+func TestProvider_VerifyRejectsInvalidTokens(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := issuer.provider(t, "okta", "okta|")
+
+	wrongAudience := issuer.claims("00u123")
+	wrongAudience["aud"] = "another-app"
+	expired := issuer.claims("00u123")
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	otherIssuer := newTestIssuer(t)
+	forged := otherIssuer.claims("00u123")
+	forged["iss"] = issuer.url()
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong audience", issuer.sign(t, wrongAudience)},
+		{"expired", issuer.sign(t, expired)},
+		{"signed by another key", otherIssuer.sign(t, forged)},
+		{"malformed", "not-a-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := provider.Verify(context.Background(), tt.token); err == nil {
+				t.Error("Verify() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestVerifier_DispatchesByIssuer(t *testing.T) {
+	auth0 := newTestIssuer(t)
+	broker := newTestIssuer(t)
+	verifier := NewVerifier(auth0.provider(t, "auth0", ""), broker.provider(t, "saml", "saml|"))
+
+	ident, err := verifier.Verify(context.Background(), auth0.sign(t, auth0.claims("auth0|abc")))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if ident.Subject != "auth0|abc" || ident.Provider != "auth0" {
+		t.Errorf("got %s from %s, want auth0|abc from auth0", ident.Subject, ident.Provider)
+	}
+
+	ident, err = verifier.Verify(context.Background(), broker.sign(t, broker.claims("ada")))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if ident.Subject != "saml|ada" || ident.Provider != "saml" {
+		t.Errorf("got %s from %s, want saml|ada from saml", ident.Subject, ident.Provider)
+	}
+}
+
+func TestVerifier_RejectsUntrustedIssuer(t *testing.T) {
+	trusted := newTestIssuer(t)
+	untrusted := newTestIssuer(t)
+	verifier := NewVerifier(trusted.provider(t, "auth0", ""))
+
+	_, err := verifier.Verify(context.Background(), untrusted.sign(t, untrusted.claims("mallory")))
+	if !errors.Is(err, ErrUntrustedIssuer) {
+		t.Errorf("Verify() error = %v, want ErrUntrustedIssuer", err)
+	}
+}
+
+func TestNewProvider_InvalidIssuer(t *testing.T) {
+	if _, err := NewProvider(ProviderConfig{Name: "okta", IssuerURL: "not a url", Audience: "dashboard"}); err == nil {
+		t.Error("NewProvider() error = nil, want an error")
+	}
+}
+
+func TestUnverifiedIssuer(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"https://idp.example.com/"}`))
+	issuer, err := unverifiedIssuer(strings.Join([]string{"e30", payload, "sig"}, "."))
+	if err != nil || issuer != "https://idp.example.com/" {
+		t.Errorf("unverifiedIssuer() = %q, %v", issuer, err)
+	}
+	if _, err := unverifiedIssuer("a.b"); err == nil {
+		t.Error("unverifiedIssuer() error = nil for a malformed token")
+	}
+}
+
+func TestClaims_Validate(t *testing.T) {
+	claims := &Claims{Email: "test@example.com", Name: "Test User"}
+	if err := claims.Validate(context.Background()); err != nil {
+		t.Errorf("Claims.Validate() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,122 @@
+package jira
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling Jira while the circuit breaker is open
+var ErrCircuitOpen = errors.New("jira is unavailable after repeated failures, try again shortly")
+
+const (
+	// DefaultBreakerThreshold is how many consecutive failures open the circuit
+	DefaultBreakerThreshold = 5
+	// DefaultBreakerCooldown is how long the circuit stays open before a trial request
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// CircuitBreaker stops calls to a Jira site after repeated failures so a slow or
+// unavailable site fails fast instead of tying up every caller until it times out.
+// Once the cooldown passes a single trial request is let through; its outcome
+// closes the circuit or opens it for another cooldown. A nil breaker allows everything.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	trial     bool
+	now       func() time.Time
+}
+
+// NewCircuitBreaker creates a circuit breaker that opens after threshold consecutive failures
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = DefaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Open reports whether calls are currently being rejected
+func (b *CircuitBreaker) Open() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.threshold && (b.trial || b.now().Before(b.openUntil))
+}
+
+// allow returns ErrCircuitOpen if a call shouldn't be made
+func (b *CircuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.trial || b.now().Before(b.openUntil) {
+		return ErrCircuitOpen
+	}
+	b.trial = true
+	return nil
+}
+
+// record counts the outcome of a call that allow let through
+func (b *CircuitBreaker) record(failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// CircuitBreakers keeps a circuit breaker per organization, since each connects to its own Jira
+// site and one failing site shouldn't stop calls to another
+type CircuitBreakers struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	breakers  map[int64]*CircuitBreaker
+}
+
+// NewCircuitBreakers creates per-organization circuit breakers that open after threshold
+// consecutive failures
+func NewCircuitBreakers(threshold int, cooldown time.Duration) *CircuitBreakers {
+	return &CircuitBreakers{
+		threshold: threshold,
+		cooldown:  cooldown,
+		breakers:  make(map[int64]*CircuitBreaker),
+	}
+}
+
+// For returns the organization's circuit breaker, creating it on first use
+func (b *CircuitBreakers) For(orgID int64) *CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	breaker, ok := b.breakers[orgID]
+	if !ok {
+		breaker = NewCircuitBreaker(b.threshold, b.cooldown)
+		b.breakers[orgID] = breaker
+	}
+	return breaker
+}
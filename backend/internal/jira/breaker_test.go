@@ -0,0 +1,79 @@
+package jira
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(3, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+
+	// A success resets the failure count
+	breaker.record(true)
+	breaker.record(true)
+	breaker.record(false)
+	breaker.record(true)
+	breaker.record(true)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("allow() after non-consecutive failures = %v, want nil", err)
+	}
+
+	breaker.record(true)
+	if !breaker.Open() {
+		t.Fatal("breaker should open after three consecutive failures")
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow() while open = %v, want ErrCircuitOpen", err)
+	}
+
+	// After the cooldown a single trial request is let through
+	now = now.Add(31 * time.Second)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("allow() after cooldown = %v, want nil", err)
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second allow() during the trial = %v, want ErrCircuitOpen", err)
+	}
+
+	// A failed trial opens the circuit for another cooldown
+	breaker.record(true)
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow() after failed trial = %v, want ErrCircuitOpen", err)
+	}
+
+	// A successful trial closes it
+	now = now.Add(31 * time.Second)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("allow() after second cooldown = %v, want nil", err)
+	}
+	breaker.record(false)
+	if breaker.Open() {
+		t.Error("breaker should close after a successful trial")
+	}
+}
+
+func TestCircuitBreaker_Nil(t *testing.T) {
+	var breaker *CircuitBreaker
+	breaker.record(true)
+	if err := breaker.allow(); err != nil || breaker.Open() {
+		t.Errorf("nil breaker allow() = %v, open = %v, want it to allow everything", err, breaker.Open())
+	}
+}
+
+func TestCircuitBreakers_PerOrganization(t *testing.T) {
+	breakers := NewCircuitBreakers(1, time.Minute)
+	if breakers.For(1) != breakers.For(1) {
+		t.Error("expected an organization to keep its breaker")
+	}
+
+	breakers.For(1).record(true)
+	if !breakers.For(1).Open() {
+		t.Error("expected the failing organization's breaker to open")
+	}
+	if breakers.For(2).Open() {
+		t.Error("expected another organization's breaker to stay closed")
+	}
+}
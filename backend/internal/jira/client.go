@@ -2,14 +2,24 @@ package jira
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tracing"
+)
+
+const (
+	// maxRateLimitRetries is how many times a rate-limited request is retried
+	maxRateLimitRetries = 2
+	// maxRetryAfter is the longest Retry-After delay that's waited out; longer ones fail straight away
+	maxRetryAfter = 10 * time.Second
 )
 
 // AuthType represents the type of authentication used
@@ -33,6 +43,10 @@ type Client struct {
 	// Common
 	authType   AuthType
 	httpClient *http.Client
+	onError    func(error)
+	breaker    *CircuitBreaker
+	stats      *CallStats
+	ctx        context.Context
 }
 
 // NewClient creates a new Jira client with Basic auth (legacy)
@@ -43,7 +57,8 @@ func NewClient(domain, email, apiToken string) *Client {
 		apiToken: apiToken,
 		authType: AuthTypeBasic,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.Transport(nil),
 		},
 	}
 }
@@ -56,7 +71,8 @@ func NewOAuthClient(accessToken, cloudID, siteURL string) *Client {
 		siteURL:     siteURL,
 		authType:    AuthTypeOAuth,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.Transport(nil),
 		},
 	}
 }
@@ -74,6 +90,32 @@ func NewClientFromUser(user *models.User) (*Client, error) {
 	return nil, fmt.Errorf("user does not have Jira configured")
 }
 
+// OnError registers a callback for requests that fail in a way that points at the
+// connection rather than the request (network errors, auth failures, rate limits, server errors)
+func (c *Client) OnError(hook func(error)) *Client {
+	c.onError = hook
+	return c
+}
+
+// WithCircuitBreaker shares a circuit breaker with the client, typically one per Jira site
+func (c *Client) WithCircuitBreaker(breaker *CircuitBreaker) *Client {
+	c.breaker = breaker
+	return c
+}
+
+// WithCallStats shares call stats with the client, typically one per Jira site
+func (c *Client) WithCallStats(stats *CallStats) *Client {
+	c.stats = stats
+	return c
+}
+
+// WithContext sends the client's requests with ctx, so they are cancelled with it and traced as
+// part of the request that made them
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c.ctx = ctx
+	return c
+}
+
 // baseURL returns the base URL for API calls
 func (c *Client) baseURL() string {
 	if c.authType == AuthTypeOAuth {
@@ -99,25 +141,94 @@ func (c *Client) authHeader() string {
 	return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
 }
 
-// doRequest performs an authenticated request to Jira
+// doRequest performs an authenticated request to Jira. Rate-limited requests are retried
+// after Jira's Retry-After delay, and requests are refused while the circuit breaker is open.
 func (c *Client) doRequest(method, path string, body io.Reader) (*http.Response, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+
 	url := c.baseURL() + path
 
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	// The body is buffered so a rate-limited request can be sent again
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			c.breaker.record(false)
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
 	}
 
-	req.Header.Set("Authorization", c.authHeader())
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+	for attempt := 0; ; attempt++ {
+		var reqBody io.Reader
+		if payload != nil {
+			reqBody = bytes.NewReader(payload)
+		}
+		ctx := c.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			c.breaker.record(false)
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", c.authHeader())
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			err = fmt.Errorf("failed to execute request: %w", err)
+			c.breaker.record(true)
+			c.stats.record(true)
+			c.reportError(err)
+			return nil, err
+		}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRateLimitRetries {
+			if wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok && wait <= maxRetryAfter {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+				time.Sleep(wait)
+				continue
+			}
+		}
+
+		failed := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		c.breaker.record(failed)
+		// Auth failures don't trip the breaker, but they count against the connection's health
+		failed = failed || resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden
+		c.stats.record(failed)
+		if failed {
+			c.reportError(fmt.Errorf("%s %s returned status %d", method, path, resp.StatusCode))
+		}
+
+		return resp, nil
 	}
+}
 
-	return resp, nil
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// reportError passes a connection-level failure to the OnError callback, if any
+func (c *Client) reportError(err error) {
+	if c.onError != nil {
+		c.onError(err)
+	}
 }
 
 // TestConnection tests if the Jira credentials are valid
@@ -143,7 +254,7 @@ func (c *Client) searchIssues(jql string, maxResults int) ([]models.JiraIssue, e
 	reqBody := map[string]interface{}{
 		"jql":        jql,
 		"maxResults": maxResults,
-		"fields":     []string{"summary", "description", "status", "priority", "issuetype", "assignee", "reporter", "project", "parent", "created", "updated", "duedate", "labels", "customfield_10015"},
+		"fields":     []string{"summary", "description", "status", "priority", "issuetype", "assignee", "reporter", "project", "parent", "created", "updated", "duedate", "labels", "resolutiondate", "customfield_10015"},
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
@@ -220,9 +331,18 @@ func (c *Client) GetProjects() ([]models.JiraProject, error) {
 
 // GetIssuesByAccountID returns issues assigned to a specific Jira account
 func (c *Client) GetIssuesByAccountID(accountID string, maxResults int) ([]models.JiraIssue, error) {
+	return c.GetFilteredIssuesByAccountID(accountID, "", maxResults)
+}
+
+// GetFilteredIssuesByAccountID returns issues assigned to a specific Jira account that also match
+// filterJQL, a JQL clause without ORDER BY. An empty filter matches every unresolved issue.
+func (c *Client) GetFilteredIssuesByAccountID(accountID, filterJQL string, maxResults int) ([]models.JiraIssue, error) {
 	// Use accountId() function for Jira Cloud compatibility and proper quoting
-	jql := fmt.Sprintf("assignee = accountId(\"%s\") AND resolution = Unresolved ORDER BY updated DESC", accountID)
-	return c.searchIssues(jql, maxResults)
+	jql := fmt.Sprintf("assignee = accountId(\"%s\") AND resolution = Unresolved", accountID)
+	if filterJQL != "" {
+		jql += " AND (" + filterJQL + ")"
+	}
+	return c.searchIssues(jql+" ORDER BY updated DESC", maxResults)
 }
 
 // GetAllUsers returns all users searchable in Jira
@@ -375,6 +495,12 @@ func (c *Client) convertIssues(issues []jiraIssue) []models.JiraIssue {
 			}
 		}
 
+		result[i].StatusCategory = statusCategory(issue.Fields.Status)
+		if !issue.Fields.Resolved.IsZero() {
+			resolvedAt := issue.Fields.Resolved.Time
+			result[i].ResolvedAt = &resolvedAt
+		}
+
 		// Due date
 		if issue.Fields.DueDate != "" {
 			if t, err := time.Parse("2006-01-02", issue.Fields.DueDate); err == nil {
@@ -510,6 +636,8 @@ type jiraFields struct {
 	StartDate   string          `json:"customfield_10015"`
 	DueDate     string          `json:"duedate"`
 	Labels      []string        `json:"labels"`
+	Resolution  *jiraStatus     `json:"resolution"` // Only read from webhook payloads
+	Resolved    JiraTime        `json:"resolutiondate"`
 }
 
 type jiraParent struct {
@@ -523,7 +651,28 @@ type jiraParentFields struct {
 }
 
 type jiraStatus struct {
-	Name string `json:"name"`
+	Name           string              `json:"name"`
+	StatusCategory *jiraStatusCategory `json:"statusCategory"`
+}
+
+type jiraStatusCategory struct {
+	Key string `json:"key"` // new, indeterminate, or done
+}
+
+// statusCategory maps a Jira status category key to ours
+func statusCategory(status jiraStatus) string {
+	if status.StatusCategory == nil {
+		return ""
+	}
+	switch status.StatusCategory.Key {
+	case "new":
+		return models.JiraStatusCategoryToDo
+	case "indeterminate":
+		return models.JiraStatusCategoryInProgress
+	case "done":
+		return models.JiraStatusCategoryDone
+	}
+	return ""
 }
 
 type jiraPriority struct {
@@ -3,10 +3,15 @@ package jira
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
 )
 
 func TestNewClient(t *testing.T) {
@@ -535,6 +540,23 @@ func TestClient_convertIssues(t *testing.T) {
 	}
 }
 
+func TestClient_convertIssues_StatusCategoryAndResolution(t *testing.T) {
+	resolved := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	client := NewOAuthClient("token", "cloud123", "https://site.atlassian.net")
+
+	result := client.convertIssues([]jiraIssue{
+		{Key: "PROJ-1", Fields: jiraFields{Status: jiraStatus{Name: "Shipped", StatusCategory: &jiraStatusCategory{Key: "done"}}, Resolved: JiraTime{Time: resolved}}},
+		{Key: "PROJ-2", Fields: jiraFields{Status: jiraStatus{Name: "Backlog", StatusCategory: &jiraStatusCategory{Key: "new"}}}},
+	})
+
+	if result[0].StatusCategory != models.JiraStatusCategoryDone || result[0].ResolvedAt == nil || !result[0].ResolvedAt.Equal(resolved) {
+		t.Errorf("resolved issue = %+v", result[0])
+	}
+	if result[1].StatusCategory != models.JiraStatusCategoryToDo || result[1].ResolvedAt != nil {
+		t.Errorf("open issue = %+v", result[1])
+	}
+}
+
 func TestClient_convertIssues_StringDescription(t *testing.T) {
 	client := NewOAuthClient("token", "cloud123", "https://site.atlassian.net")
 
@@ -594,3 +616,130 @@ func TestClient_convertIssues_ADFDescription(t *testing.T) {
 		t.Errorf("Description = %q, want %q", result[0].Description, "ADF formatted text\n")
 	}
 }
+
+// roundTripFunc lets a test answer requests without a server
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestClient_OnError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantReport bool
+	}{
+		{"success", http.StatusOK, false},
+		{"missing issue", http.StatusNotFound, false},
+		{"expired token", http.StatusUnauthorized, true},
+		{"rate limited", http.StatusTooManyRequests, true},
+		{"server error", http.StatusBadGateway, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reported []error
+			client := NewOAuthClient("token", "cloud123", "").OnError(func(err error) { reported = append(reported, err) })
+			client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				rec := httptest.NewRecorder()
+				rec.WriteHeader(tt.status)
+				return rec.Result(), nil
+			})}
+
+			_ = client.TestConnection()
+
+			if (len(reported) == 1) != tt.wantReport {
+				t.Errorf("reported %v, want report = %v", reported, tt.wantReport)
+			}
+		})
+	}
+}
+
+func TestClient_doRequest_RetriesRateLimited(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		wantCalls  int
+		wantStatus int
+	}{
+		{"retries after the delay", "0", 2, http.StatusOK},
+		{"gives up on long delays", "120", 1, http.StatusTooManyRequests},
+		{"gives up without a delay", "", 1, http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			client := NewOAuthClient("token", "cloud123", "")
+			client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				calls++
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != `{"jql":"x"}` {
+					t.Errorf("attempt %d body = %q", calls, body)
+				}
+				rec := httptest.NewRecorder()
+				if calls == 1 {
+					if tt.retryAfter != "" {
+						rec.Header().Set("Retry-After", tt.retryAfter)
+					}
+					rec.WriteHeader(http.StatusTooManyRequests)
+				} else {
+					rec.WriteHeader(http.StatusOK)
+				}
+				return rec.Result(), nil
+			})}
+
+			resp, err := client.doRequest("POST", "/rest/api/3/search/jql", strings.NewReader(`{"jql":"x"}`))
+			if err != nil {
+				t.Fatalf("doRequest() error = %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if calls != tt.wantCalls || resp.StatusCode != tt.wantStatus {
+				t.Errorf("calls = %d, status = %d, want %d calls and status %d", calls, resp.StatusCode, tt.wantCalls, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestClient_doRequest_CircuitBreaker(t *testing.T) {
+	breaker := NewCircuitBreaker(2, time.Minute)
+	calls := 0
+	status := http.StatusServiceUnavailable
+	client := NewOAuthClient("token", "cloud123", "").WithCircuitBreaker(breaker)
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(status)
+		return rec.Result(), nil
+	})}
+
+	_ = client.TestConnection()
+	_ = client.TestConnection()
+	if err := client.TestConnection(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("third call error = %v, want ErrCircuitOpen", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 while the circuit is open", calls)
+	}
+}
+
+func TestClient_doRequest_RecordsCallStats(t *testing.T) {
+	stats := NewCallStats(time.Hour)
+	statuses := []int{http.StatusOK, http.StatusUnauthorized, http.StatusNotFound}
+	client := NewOAuthClient("token", "cloud123", "").WithCallStats(stats)
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(statuses[0])
+		statuses = statuses[1:]
+		return rec.Result(), nil
+	})}
+
+	for range 3 {
+		_ = client.TestConnection()
+	}
+
+	// Auth failures count against the connection; a missing resource doesn't
+	if calls, failures := stats.Snapshot(); calls != 3 || failures != 1 {
+		t.Errorf("Snapshot() = %d, %d, want 3, 1", calls, failures)
+	}
+}
@@ -0,0 +1,52 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// maxEstimatedIssues caps how many of an account's open issues are read for capacity planning
+const maxEstimatedIssues = 200
+
+// GetEstimatedIssuesByAccountID returns the unresolved issues assigned to an account that are due on
+// or before dueBy, overdue ones included, with their story point estimates
+func (c *Client) GetEstimatedIssuesByAccountID(accountID string, dueBy time.Time, storyPointsField string) ([]models.JiraSprintIssue, error) {
+	if storyPointsField == "" {
+		storyPointsField = DefaultStoryPointsField
+	}
+
+	reqBody := map[string]interface{}{
+		"jql": fmt.Sprintf("assignee = accountId(\"%s\") AND resolution = Unresolved AND duedate <= \"%s\" ORDER BY duedate ASC",
+			accountID, dueBy.Format("2006-01-02")),
+		"maxResults": maxEstimatedIssues,
+		"fields":     []string{"summary", "status", "assignee", "duedate", storyPointsField},
+	}
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest("POST", "/rest/api/3/search/jql", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch issues (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result jiraSprintIssueList
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return convertSprintIssues(result.Issues, storyPointsField), nil
+}
@@ -0,0 +1,47 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_GetEstimatedIssuesByAccountID(t *testing.T) {
+	client := NewOAuthClient("token", "cloud123", "")
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		var body struct {
+			JQL    string   `json:"jql"`
+			Fields []string `json:"fields"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if !strings.Contains(body.JQL, `accountId("acct-7")`) || !strings.Contains(body.JQL, `duedate <= "2026-03-13"`) {
+			t.Errorf("unexpected JQL: %s", body.JQL)
+		}
+		if !strings.Contains(strings.Join(body.Fields, ","), "customfield_10026") {
+			t.Errorf("fields = %v, want the story points field", body.Fields)
+		}
+		_, _ = rec.WriteString(`{"issues":[
+			{"key":"WEB-1","fields":{"summary":"Login","status":{"name":"To Do"},"duedate":"2026-03-10","customfield_10026":3}},
+			{"key":"WEB-2","fields":{"summary":"Logout","status":{"name":"To Do"},"duedate":null}}
+		]}`)
+		return rec.Result(), nil
+	})}
+
+	issues, err := client.GetEstimatedIssuesByAccountID("acct-7", time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC), "customfield_10026")
+	if err != nil {
+		t.Fatalf("GetEstimatedIssuesByAccountID() error = %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	if i := issues[0]; i.StoryPoints == nil || *i.StoryPoints != 3 || i.DueDate == nil || i.DueDate.Format("2006-01-02") != "2026-03-10" {
+		t.Errorf("issue 0 = %+v, want 3 points due 2026-03-10", i)
+	}
+	if issues[1].DueDate != nil || issues[1].StoryPoints != nil {
+		t.Errorf("issue 1 = %+v, want no due date or estimate", issues[1])
+	}
+}
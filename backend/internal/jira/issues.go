@@ -0,0 +1,223 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// ErrIssueNotFound is returned when an issue doesn't exist or the connection can't see it
+var ErrIssueNotFound = errors.New("jira issue not found")
+
+// CreateIssueInput describes an issue to create in Jira
+type CreateIssueInput struct {
+	ProjectKey        string
+	IssueType         string
+	Summary           string
+	Description       string
+	AssigneeAccountID string // Left unassigned when empty
+}
+
+// CreateIssue creates an issue and returns its key and browse URL
+func (c *Client) CreateIssue(input CreateIssueInput) (*models.CreatedJiraIssue, error) {
+	fields := map[string]interface{}{
+		"project":   map[string]string{"key": input.ProjectKey},
+		"issuetype": map[string]string{"name": input.IssueType},
+		"summary":   input.Summary,
+	}
+	if input.Description != "" {
+		fields["description"] = textToADF(input.Description)
+	}
+	if input.AssigneeAccountID != "" {
+		fields["assignee"] = map[string]string{"accountId": input.AssigneeAccountID}
+	}
+
+	bodyBytes, err := json.Marshal(map[string]interface{}{"fields": fields})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest("POST", "/rest/api/3/issue", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create issue (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var created struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &models.CreatedJiraIssue{
+		ID:  created.ID,
+		Key: created.Key,
+		URL: fmt.Sprintf("%s/browse/%s", c.browseURL(), created.Key),
+	}, nil
+}
+
+// GetIssue returns a single issue by key
+func (c *Client) GetIssue(issueKey string) (*models.JiraIssue, error) {
+	path := fmt.Sprintf("/rest/api/3/issue/%s?fields=summary,status,priority,issuetype,assignee,reporter,project,created,updated,duedate,labels,resolutiondate", url.PathEscape(issueKey))
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrIssueNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch issue (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var issue jiraIssue
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &c.convertIssues([]jiraIssue{issue})[0], nil
+}
+
+// maxEpicIssues caps how many child issues are read from a single epic
+const maxEpicIssues = 500
+
+// GetEpicIssues returns the child issues of an epic, resolved or not, oldest first
+func (c *Client) GetEpicIssues(epicKey string) ([]models.JiraIssue, error) {
+	return c.searchIssues(fmt.Sprintf("parent = \"%s\" ORDER BY created ASC", epicKey), maxEpicIssues)
+}
+
+// GetTransitions returns the workflow transitions currently available on an issue
+func (c *Client) GetTransitions(issueKey string) ([]models.JiraTransition, error) {
+	resp, err := c.doRequest("GET", fmt.Sprintf("/rest/api/3/issue/%s/transitions", url.PathEscape(issueKey)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrIssueNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch transitions (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Transitions []struct {
+			ID   string     `json:"id"`
+			Name string     `json:"name"`
+			To   jiraStatus `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	transitions := make([]models.JiraTransition, len(result.Transitions))
+	for i, t := range result.Transitions {
+		transitions[i] = models.JiraTransition{ID: t.ID, Name: t.Name, ToStatus: t.To.Name}
+	}
+	return transitions, nil
+}
+
+// TransitionIssue moves an issue through the given workflow transition
+func (c *Client) TransitionIssue(issueKey, transitionID string) error {
+	bodyBytes, err := json.Marshal(map[string]interface{}{"transition": map[string]string{"id": transitionID}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest("POST", fmt.Sprintf("/rest/api/3/issue/%s/transitions", url.PathEscape(issueKey)), bytes.NewReader(bodyBytes))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrIssueNotFound
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to transition issue (status %d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// AddComment posts a plain-text comment on an issue
+func (c *Client) AddComment(issueKey, text string) (*models.JiraComment, error) {
+	bodyBytes, err := json.Marshal(map[string]interface{}{"body": textToADF(text)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.doRequest("POST", fmt.Sprintf("/rest/api/3/issue/%s/comment", url.PathEscape(issueKey)), bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrIssueNotFound
+	}
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to add comment (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var created struct {
+		ID      string                 `json:"id"`
+		Body    map[string]interface{} `json:"body"`
+		Author  *jiraUser              `json:"author"`
+		Created JiraTime               `json:"created"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	comment := &models.JiraComment{
+		ID:      created.ID,
+		Body:    strings.TrimSuffix(extractTextFromADF(created.Body), "\n"),
+		Created: created.Created.Time,
+	}
+	if created.Author != nil {
+		comment.Author = &models.JiraUser{
+			AccountID:   created.Author.AccountID,
+			DisplayName: created.Author.DisplayName,
+			AvatarURL:   getAvatarURL(created.Author.AvatarURLs),
+		}
+	}
+	return comment, nil
+}
+
+// textToADF wraps plain text in an Atlassian Document Format document, one paragraph per line
+func textToADF(text string) map[string]interface{} {
+	paragraphs := []interface{}{}
+	for _, line := range strings.Split(text, "\n") {
+		content := []interface{}{}
+		if line != "" {
+			content = append(content, map[string]interface{}{"type": "text", "text": line})
+		}
+		paragraphs = append(paragraphs, map[string]interface{}{"type": "paragraph", "content": content})
+	}
+	return map[string]interface{}{
+		"type":    "doc",
+		"version": 1,
+		"content": paragraphs,
+	}
+}
@@ -0,0 +1,146 @@
+package jira
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_CreateIssue(t *testing.T) {
+	var sent map[string]map[string]interface{}
+	client := NewOAuthClient("token", "cloud123", "https://acme.atlassian.net")
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method != "POST" || r.URL.Path != "/ex/jira/cloud123/rest/api/3/issue" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &sent); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(http.StatusCreated)
+		_, _ = rec.WriteString(`{"id":"10042","key":"WEB-42","self":"https://api.atlassian.com/..."}`)
+		return rec.Result(), nil
+	})}
+
+	issue, err := client.CreateIssue(CreateIssueInput{
+		ProjectKey:        "WEB",
+		IssueType:         "Bug",
+		Summary:           "Login button misaligned",
+		Description:       "Steps:\nOpen the login page",
+		AssigneeAccountID: "acct-7",
+	})
+	if err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+
+	if issue.Key != "WEB-42" || issue.URL != "https://acme.atlassian.net/browse/WEB-42" {
+		t.Errorf("issue = %+v", issue)
+	}
+	fields := sent["fields"]
+	if fields["summary"] != "Login button misaligned" {
+		t.Errorf("summary = %v", fields["summary"])
+	}
+	if assignee, _ := fields["assignee"].(map[string]interface{}); assignee["accountId"] != "acct-7" {
+		t.Errorf("assignee = %v", fields["assignee"])
+	}
+	description, _ := fields["description"].(map[string]interface{})
+	if got := extractTextFromADF(description); got != "Steps:\nOpen the login page\n" {
+		t.Errorf("description round-trips as %q", got)
+	}
+}
+
+func TestClient_CreateIssue_Rejected(t *testing.T) {
+	client := NewOAuthClient("token", "cloud123", "")
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(http.StatusBadRequest)
+		_, _ = rec.WriteString(`{"errors":{"issuetype":"Specify a valid issue type"}}`)
+		return rec.Result(), nil
+	})}
+
+	if _, err := client.CreateIssue(CreateIssueInput{ProjectKey: "WEB", IssueType: "Saga", Summary: "x"}); err == nil {
+		t.Error("CreateIssue() error = nil, want the Jira validation error")
+	}
+}
+
+func TestClient_TransitionIssue(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{"transitioned", http.StatusNoContent, nil},
+		{"missing issue", http.StatusNotFound, ErrIssueNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent map[string]map[string]string
+			client := NewOAuthClient("token", "cloud123", "")
+			client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				if r.URL.Path != "/ex/jira/cloud123/rest/api/3/issue/WEB-42/transitions" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				_ = json.NewDecoder(r.Body).Decode(&sent)
+				rec := httptest.NewRecorder()
+				rec.WriteHeader(tt.status)
+				return rec.Result(), nil
+			})}
+
+			err := client.TransitionIssue("WEB-42", "31")
+			if err != tt.wantErr {
+				t.Fatalf("TransitionIssue() error = %v, want %v", err, tt.wantErr)
+			}
+			if sent["transition"]["id"] != "31" {
+				t.Errorf("sent %v", sent)
+			}
+		})
+	}
+}
+
+func TestClient_AddComment(t *testing.T) {
+	client := NewOAuthClient("token", "cloud123", "")
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method != "POST" || r.URL.Path != "/ex/jira/cloud123/rest/api/3/issue/WEB-42/comment" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(http.StatusCreated)
+		// Echo the submitted document back the way Jira does
+		_, _ = rec.WriteString(`{"id":"900","author":{"accountId":"bot","displayName":"Dashboard"},"created":"2024-03-04T15:00:00.000+0000",` + string(body[1:]))
+		return rec.Result(), nil
+	})}
+
+	comment, err := client.AddComment("WEB-42", "Ada Lovelace:\nDeployed to staging")
+	if err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if comment.ID != "900" || comment.Body != "Ada Lovelace:\nDeployed to staging" || comment.Author.DisplayName != "Dashboard" {
+		t.Errorf("comment = %+v", comment)
+	}
+}
+
+func TestClient_GetFilteredIssuesByAccountID(t *testing.T) {
+	var sent struct {
+		JQL string `json:"jql"`
+	}
+	client := NewOAuthClient("token", "cloud123", "")
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		rec := httptest.NewRecorder()
+		_, _ = rec.WriteString(`{"issues":[]}`)
+		return rec.Result(), nil
+	})}
+
+	if _, err := client.GetFilteredIssuesByAccountID("acct-7", "status != Done OR priority = High", 20); err != nil {
+		t.Fatalf("GetFilteredIssuesByAccountID() error = %v", err)
+	}
+	want := `assignee = accountId("acct-7") AND resolution = Unresolved AND (status != Done OR priority = High) ORDER BY updated DESC`
+	if sent.JQL != want {
+		t.Errorf("jql = %q, want %q", sent.JQL, want)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/tracing"
 )
 
 const (
@@ -21,6 +22,17 @@ const (
 	resourcesURL     = "https://api.atlassian.com/oauth/token/accessible-resources"
 )
 
+// Scopes are the OAuth scopes requested when connecting Jira
+var Scopes = []string{
+	"read:jira-work",
+	"read:jira-user",
+	"read:board-scope:jira-software",
+	"read:sprint:jira-software",
+	"read:issue-details:jira",
+	"read:jql:jira",
+	"offline_access",
+}
+
 // OAuthService handles Jira OAuth 2.0 (3LO) flow
 type OAuthService struct {
 	clientID     string
@@ -36,7 +48,8 @@ func NewOAuthService(cfg *config.Config) *OAuthService {
 		clientSecret: cfg.JiraClientSecret,
 		callbackURL:  cfg.JiraCallbackURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.Transport(nil),
 		},
 	}
 }
@@ -73,7 +86,7 @@ func (s *OAuthService) GetAuthorizationURL(state string) string {
 	params := url.Values{
 		"audience":      {"api.atlassian.com"},
 		"client_id":     {s.clientID},
-		"scope":         {"read:jira-work read:jira-user offline_access"},
+		"scope":         {strings.Join(Scopes, " ")},
 		"redirect_uri":  {s.callbackURL},
 		"state":         {state},
 		"response_type": {"code"},
@@ -0,0 +1,188 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// DefaultStoryPointsField is the "Story point estimate" custom field on Jira Cloud software projects
+const DefaultStoryPointsField = "customfield_10016"
+
+// maxSprintIssues caps how many issues are read from a single sprint
+const maxSprintIssues = 500
+
+// GetSprint returns a sprint by ID using the Jira Agile API
+func (c *Client) GetSprint(sprintID int) (*models.JiraSprint, error) {
+	resp, err := c.doRequest("GET", fmt.Sprintf("/rest/agile/1.0/sprint/%d", sprintID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch sprint (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var sprint jiraSprint
+	if err := json.NewDecoder(resp.Body).Decode(&sprint); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	result := sprint.toModel()
+	return &result, nil
+}
+
+// GetBoardSprints returns a board's sprints, optionally filtered by state (future, active, closed)
+func (c *Client) GetBoardSprints(boardID int, state string) ([]models.JiraSprint, error) {
+	path := fmt.Sprintf("/rest/agile/1.0/board/%d/sprint", boardID)
+	if state != "" {
+		path += "?state=" + url.QueryEscape(state)
+	}
+
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch board sprints (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result jiraSprintList
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	sprints := make([]models.JiraSprint, len(result.Values))
+	for i, s := range result.Values {
+		sprints[i] = s.toModel()
+	}
+	return sprints, nil
+}
+
+// GetSprintIssues returns the issues committed to a sprint with their story point estimates
+func (c *Client) GetSprintIssues(sprintID int, storyPointsField string) ([]models.JiraSprintIssue, error) {
+	if storyPointsField == "" {
+		storyPointsField = DefaultStoryPointsField
+	}
+
+	params := url.Values{
+		"fields":     {"summary,status,assignee," + storyPointsField},
+		"maxResults": {fmt.Sprintf("%d", maxSprintIssues)},
+	}
+	path := fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue?%s", sprintID, params.Encode())
+
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch sprint issues (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result jiraSprintIssueList
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return convertSprintIssues(result.Issues, storyPointsField), nil
+}
+
+// convertSprintIssues maps raw sprint issues to our model, reading story points from the configured field
+func convertSprintIssues(issues []jiraSprintIssue, storyPointsField string) []models.JiraSprintIssue {
+	result := make([]models.JiraSprintIssue, 0, len(issues))
+	for _, issue := range issues {
+		converted := models.JiraSprintIssue{Key: issue.Key}
+
+		var summary string
+		if raw, ok := issue.Fields["summary"]; ok {
+			_ = json.Unmarshal(raw, &summary)
+		}
+		converted.Summary = summary
+
+		var status jiraStatus
+		if raw, ok := issue.Fields["status"]; ok {
+			_ = json.Unmarshal(raw, &status)
+		}
+		converted.Status = status.Name
+
+		var assignee *jiraUser
+		if raw, ok := issue.Fields["assignee"]; ok {
+			_ = json.Unmarshal(raw, &assignee)
+		}
+		if assignee != nil {
+			converted.AssigneeAccountID = &assignee.AccountID
+		}
+
+		var points *float64
+		if raw, ok := issue.Fields[storyPointsField]; ok {
+			_ = json.Unmarshal(raw, &points)
+		}
+		converted.StoryPoints = points
+
+		var dueDate string
+		if raw, ok := issue.Fields["duedate"]; ok {
+			_ = json.Unmarshal(raw, &dueDate)
+		}
+		if t, err := time.Parse("2006-01-02", dueDate); err == nil {
+			converted.DueDate = &t
+		}
+
+		result = append(result, converted)
+	}
+	return result
+}
+
+// Jira Agile API response types
+
+type jiraSprint struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	State     string   `json:"state"`
+	Goal      string   `json:"goal"`
+	StartDate JiraTime `json:"startDate"`
+	EndDate   JiraTime `json:"endDate"`
+}
+
+func (s jiraSprint) toModel() models.JiraSprint {
+	sprint := models.JiraSprint{
+		ID:    s.ID,
+		Name:  s.Name,
+		State: s.State,
+		Goal:  s.Goal,
+	}
+	if !s.StartDate.IsZero() {
+		start := s.StartDate.Time
+		sprint.StartDate = &start
+	}
+	if !s.EndDate.IsZero() {
+		end := s.EndDate.Time
+		sprint.EndDate = &end
+	}
+	return sprint
+}
+
+type jiraSprintList struct {
+	Values []jiraSprint `json:"values"`
+}
+
+type jiraSprintIssue struct {
+	Key    string                     `json:"key"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+type jiraSprintIssueList struct {
+	Issues []jiraSprintIssue `json:"issues"`
+}
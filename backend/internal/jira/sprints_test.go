@@ -0,0 +1,71 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConvertSprintIssues(t *testing.T) {
+	raw := `{"issues": [
+		{"key": "PROJ-1", "fields": {"summary": "Estimated", "status": {"name": "To Do"}, "assignee": {"accountId": "abc"}, "customfield_10016": 5}},
+		{"key": "PROJ-2", "fields": {"summary": "Unestimated", "status": {"name": "To Do"}, "assignee": null, "customfield_10016": null}},
+		{"key": "PROJ-3", "fields": {"summary": "Other field", "status": {"name": "Done"}, "customfield_10026": 3}}
+	]}`
+
+	var list jiraSprintIssueList
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	issues := convertSprintIssues(list.Issues, "customfield_10016")
+	if len(issues) != 3 {
+		t.Fatalf("got %d issues, want 3", len(issues))
+	}
+
+	if issues[0].Summary != "Estimated" || issues[0].Status != "To Do" {
+		t.Errorf("issue 0 = %+v, want summary and status parsed", issues[0])
+	}
+	if issues[0].AssigneeAccountID == nil || *issues[0].AssigneeAccountID != "abc" {
+		t.Errorf("issue 0 assignee = %v, want abc", issues[0].AssigneeAccountID)
+	}
+	if issues[0].StoryPoints == nil || *issues[0].StoryPoints != 5 {
+		t.Errorf("issue 0 points = %v, want 5", issues[0].StoryPoints)
+	}
+
+	if issues[1].AssigneeAccountID != nil {
+		t.Errorf("issue 1 assignee = %v, want nil", *issues[1].AssigneeAccountID)
+	}
+	if issues[1].StoryPoints != nil {
+		t.Errorf("issue 1 points = %v, want nil", *issues[1].StoryPoints)
+	}
+
+	// Points in a different custom field are ignored
+	if issues[2].StoryPoints != nil {
+		t.Errorf("issue 2 points = %v, want nil", *issues[2].StoryPoints)
+	}
+}
+
+func TestJiraSprint_toModel(t *testing.T) {
+	raw := `{"id": 7, "name": "Sprint 7", "state": "future", "startDate": "2026-03-02T09:00:00.000Z", "endDate": "2026-03-16T09:00:00.000Z"}`
+
+	var sprint jiraSprint
+	if err := json.Unmarshal([]byte(raw), &sprint); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	model := sprint.toModel()
+	if model.ID != 7 || model.Name != "Sprint 7" || model.State != "future" {
+		t.Errorf("toModel() = %+v", model)
+	}
+	if model.StartDate == nil || model.EndDate == nil {
+		t.Fatal("expected start and end dates")
+	}
+
+	var unscheduled jiraSprint
+	if err := json.Unmarshal([]byte(`{"id": 8, "name": "Sprint 8", "state": "future"}`), &unscheduled); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if m := unscheduled.toModel(); m.StartDate != nil || m.EndDate != nil {
+		t.Errorf("unscheduled sprint should have nil dates, got %+v", m)
+	}
+}
@@ -0,0 +1,85 @@
+package jira
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultStatsWindow is how far back CallStats counts calls
+const DefaultStatsWindow = time.Hour
+
+// CallStats counts Jira API calls and failures over a sliding window, typically one per
+// Jira site, so health checks can report recent error rates. Calls are kept in per-minute
+// buckets. A nil CallStats records nothing.
+type CallStats struct {
+	mu      sync.Mutex
+	buckets []callBucket
+	now     func() time.Time
+}
+
+// callBucket holds the calls made during one minute
+type callBucket struct {
+	minute   int64
+	calls    int
+	failures int
+}
+
+// NewCallStats creates call stats covering the given window, rounded up to whole minutes
+func NewCallStats(window time.Duration) *CallStats {
+	if window <= 0 {
+		window = DefaultStatsWindow
+	}
+	minutes := int((window + time.Minute - 1) / time.Minute)
+	return &CallStats{
+		buckets: make([]callBucket, minutes),
+		now:     time.Now,
+	}
+}
+
+// Window returns how far back calls are counted
+func (s *CallStats) Window() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return time.Duration(len(s.buckets)) * time.Minute
+}
+
+// Snapshot returns how many calls were made within the window and how many of them failed
+func (s *CallStats) Snapshot() (calls, failures int) {
+	if s == nil {
+		return 0, 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	oldest := s.minute() - int64(len(s.buckets)) + 1
+	for _, b := range s.buckets {
+		if b.minute >= oldest {
+			calls += b.calls
+			failures += b.failures
+		}
+	}
+	return calls, failures
+}
+
+// record counts a finished call
+func (s *CallStats) record(failed bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	minute := s.minute()
+	b := &s.buckets[minute%int64(len(s.buckets))]
+	if b.minute != minute {
+		*b = callBucket{minute: minute}
+	}
+	b.calls++
+	if failed {
+		b.failures++
+	}
+}
+
+// minute returns the current minute since the epoch
+func (s *CallStats) minute() int64 {
+	return s.now().Unix() / 60
+}
@@ -0,0 +1,42 @@
+package jira
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCallStats(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	stats := NewCallStats(90 * time.Second)
+	stats.now = func() time.Time { return now }
+
+	if stats.Window() != 2*time.Minute {
+		t.Errorf("Window() = %v, want the window rounded up to 2m", stats.Window())
+	}
+
+	stats.record(false)
+	stats.record(true)
+	now = now.Add(time.Minute)
+	stats.record(false)
+	if calls, failures := stats.Snapshot(); calls != 3 || failures != 1 {
+		t.Errorf("Snapshot() = %d, %d, want 3, 1", calls, failures)
+	}
+
+	// The first minute's calls fall out of the window
+	now = now.Add(time.Minute)
+	if calls, failures := stats.Snapshot(); calls != 1 || failures != 0 {
+		t.Errorf("Snapshot() after a minute = %d, %d, want 1, 0", calls, failures)
+	}
+
+	// A reused bucket starts over
+	stats.record(true)
+	if calls, failures := stats.Snapshot(); calls != 2 || failures != 1 {
+		t.Errorf("Snapshot() after reusing a bucket = %d, %d, want 2, 1", calls, failures)
+	}
+
+	var nilStats *CallStats
+	nilStats.record(true)
+	if calls, _ := nilStats.Snapshot(); calls != 0 {
+		t.Errorf("nil Snapshot() calls = %d, want 0", calls)
+	}
+}
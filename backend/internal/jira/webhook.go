@@ -0,0 +1,69 @@
+package jira
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// Issue webhook events sent by Jira
+const (
+	WebhookIssueCreated = "jira:issue_created"
+	WebhookIssueUpdated = "jira:issue_updated"
+	WebhookIssueDeleted = "jira:issue_deleted"
+)
+
+// WebhookSignatureHeader carries the HMAC-SHA256 of the body when the Jira webhook has a secret
+const WebhookSignatureHeader = "X-Hub-Signature"
+
+// WebhookEvent is an issue event delivered by a Jira webhook
+type WebhookEvent struct {
+	Event    string
+	Issue    models.JiraIssue
+	Resolved bool
+}
+
+// VerifyWebhookSignature reports whether the signature header matches the body.
+// Jira signs with "sha256=" followed by the hex HMAC-SHA256 of the body using the webhook secret.
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	if secret == "" {
+		return false
+	}
+	expected, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	given, err := hex.DecodeString(expected)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(given, mac.Sum(nil))
+}
+
+// ParseWebhookEvent decodes an issue webhook payload. Issue URLs point at the given site.
+func ParseWebhookEvent(body []byte, siteURL string) (*WebhookEvent, error) {
+	var payload struct {
+		WebhookEvent string     `json:"webhookEvent"`
+		Issue        *jiraIssue `json:"issue"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook: %w", err)
+	}
+	if payload.Issue == nil || payload.Issue.Key == "" {
+		return nil, fmt.Errorf("webhook %q has no issue", payload.WebhookEvent)
+	}
+
+	site := &Client{authType: AuthTypeOAuth, siteURL: siteURL}
+	return &WebhookEvent{
+		Event:    payload.WebhookEvent,
+		Issue:    site.convertIssues([]jiraIssue{*payload.Issue})[0],
+		Resolved: payload.Issue.Fields.Resolution != nil,
+	}, nil
+}
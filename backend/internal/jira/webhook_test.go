@@ -0,0 +1,74 @@
+package jira
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func signJiraWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"webhookEvent":"jira:issue_updated"}`)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		want      bool
+	}{
+		{"valid", "s3cret", signJiraWebhook("s3cret", body), true},
+		{"wrong secret", "s3cret", signJiraWebhook("other", body), false},
+		{"missing prefix", "s3cret", signJiraWebhook("s3cret", body)[len("sha256="):], false},
+		{"not hex", "s3cret", "sha256=zz", false},
+		{"no secret configured", "", signJiraWebhook("", body), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyWebhookSignature(tt.secret, body, tt.signature); got != tt.want {
+				t.Errorf("VerifyWebhookSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWebhookEvent(t *testing.T) {
+	body := []byte(`{
+		"webhookEvent": "jira:issue_updated",
+		"issue": {
+			"id": "10042",
+			"key": "WEB-42",
+			"fields": {
+				"summary": "Fix login",
+				"status": {"name": "Done"},
+				"issuetype": {"name": "Bug"},
+				"assignee": {"accountId": "acct-7", "displayName": "Ada"},
+				"project": {"id": "1", "key": "WEB", "name": "Website"},
+				"resolution": {"name": "Fixed"},
+				"updated": "2024-03-04T15:00:00.000+0000"
+			}
+		}
+	}`)
+
+	event, err := ParseWebhookEvent(body, "https://acme.atlassian.net")
+	if err != nil {
+		t.Fatalf("ParseWebhookEvent() error = %v", err)
+	}
+	if event.Event != WebhookIssueUpdated || !event.Resolved {
+		t.Errorf("event = %q, resolved = %v", event.Event, event.Resolved)
+	}
+	issue := event.Issue
+	if issue.Key != "WEB-42" || issue.Status != "Done" || issue.Assignee.AccountID != "acct-7" || issue.URL != "https://acme.atlassian.net/browse/WEB-42" {
+		t.Errorf("issue = %+v", issue)
+	}
+
+	if _, err := ParseWebhookEvent([]byte(`{"webhookEvent":"jira:issue_updated"}`), ""); err == nil {
+		t.Error("ParseWebhookEvent() without an issue should fail")
+	}
+}
@@ -0,0 +1,91 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const (
+	// maxWorklogIssues caps how many issues are read when collecting an account's worklogs
+	maxWorklogIssues = 200
+	// maxIssueWorklogs is the most worklogs Jira returns for one issue in a single request
+	maxIssueWorklogs = 5000
+)
+
+// GetWorklogsByAccountID returns the worklogs an account started in [start, end).
+// Jira has no cross-issue worklog search, so it finds the issues the account logged
+// time on in the range and then reads each issue's worklogs.
+func (c *Client) GetWorklogsByAccountID(accountID string, start, end time.Time) ([]models.JiraWorklog, error) {
+	// worklogDate is day-granular, so the range is widened to whole days and trimmed per worklog below
+	jql := fmt.Sprintf("worklogAuthor = accountId(\"%s\") AND worklogDate >= \"%s\" AND worklogDate <= \"%s\" ORDER BY key",
+		accountID, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	issues, err := c.searchIssues(jql, maxWorklogIssues)
+	if err != nil {
+		return nil, err
+	}
+
+	worklogs := []models.JiraWorklog{}
+	for _, issue := range issues {
+		issueWorklogs, err := c.getIssueWorklogs(issue, accountID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		worklogs = append(worklogs, issueWorklogs...)
+	}
+	return worklogs, nil
+}
+
+// getIssueWorklogs returns an issue's worklogs by the account started in [start, end)
+func (c *Client) getIssueWorklogs(issue models.JiraIssue, accountID string, start, end time.Time) ([]models.JiraWorklog, error) {
+	params := url.Values{
+		"startedAfter":  {fmt.Sprintf("%d", start.UnixMilli()-1)},
+		"startedBefore": {fmt.Sprintf("%d", end.UnixMilli())},
+		"maxResults":    {fmt.Sprintf("%d", maxIssueWorklogs)},
+	}
+	resp, err := c.doRequest("GET", fmt.Sprintf("/rest/api/3/issue/%s/worklog?%s", url.PathEscape(issue.Key), params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch worklogs for %s (status %d): %s", issue.Key, resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Worklogs []struct {
+			ID               string    `json:"id"`
+			Author           *jiraUser `json:"author"`
+			Started          JiraTime  `json:"started"`
+			TimeSpentSeconds int       `json:"timeSpentSeconds"`
+		} `json:"worklogs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	worklogs := []models.JiraWorklog{}
+	for _, w := range result.Worklogs {
+		if w.Author == nil || w.Author.AccountID != accountID {
+			continue
+		}
+		if w.Started.Before(start) || !w.Started.Before(end) {
+			continue
+		}
+		worklogs = append(worklogs, models.JiraWorklog{
+			ID:               w.ID,
+			IssueKey:         issue.Key,
+			IssueSummary:     issue.Summary,
+			Started:          w.Started.Time,
+			TimeSpentSeconds: w.TimeSpentSeconds,
+		})
+	}
+	return worklogs, nil
+}
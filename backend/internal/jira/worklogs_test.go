@@ -0,0 +1,43 @@
+package jira
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_GetWorklogsByAccountID(t *testing.T) {
+	client := NewOAuthClient("token", "cloud123", "")
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		switch r.URL.Path {
+		case "/ex/jira/cloud123/rest/api/3/search/jql":
+			_, _ = rec.WriteString(`{"issues":[{"key":"WEB-1","fields":{"summary":"Login"}}]}`)
+		case "/ex/jira/cloud123/rest/api/3/issue/WEB-1/worklog":
+			_, _ = rec.WriteString(`{"worklogs":[
+				{"id":"1","author":{"accountId":"acct-7"},"started":"2026-03-02T09:00:00.000-0700","timeSpentSeconds":3600},
+				{"id":"2","author":{"accountId":"acct-8"},"started":"2026-03-02T10:00:00.000-0700","timeSpentSeconds":7200},
+				{"id":"3","author":{"accountId":"acct-7"},"started":"2026-03-09T09:00:00.000-0700","timeSpentSeconds":1800}
+			]}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			rec.WriteHeader(http.StatusNotFound)
+		}
+		return rec.Result(), nil
+	})}
+
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	worklogs, err := client.GetWorklogsByAccountID("acct-7", start, start.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("GetWorklogsByAccountID() error = %v", err)
+	}
+
+	// Other authors' worklogs and ones outside the range are dropped
+	if len(worklogs) != 1 {
+		t.Fatalf("got %d worklogs, want 1: %+v", len(worklogs), worklogs)
+	}
+	if w := worklogs[0]; w.ID != "1" || w.IssueKey != "WEB-1" || w.IssueSummary != "Login" || w.TimeSpentSeconds != 3600 {
+		t.Errorf("worklog = %+v", w)
+	}
+}
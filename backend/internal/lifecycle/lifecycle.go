@@ -0,0 +1,92 @@
+// Package lifecycle tracks the application's background workers so that shutdown can stop them
+// and wait for in-flight work to finish before database connections close.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// Group runs named background workers that share a context cancelled by Stop.
+// The zero value is not usable; create one with NewGroup.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	running map[string]int
+}
+
+// NewGroup creates a group whose workers run until Stop is called. Workers act across every
+// organization.
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(tenant.AcrossOrgs(context.Background()))
+	return &Group{
+		ctx:     ctx,
+		cancel:  cancel,
+		running: make(map[string]int),
+	}
+}
+
+// Context returns the context workers run under. It is cancelled when Stop is called.
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Go runs fn in its own goroutine under the group's context. fn must return promptly once the
+// context is cancelled; name identifies it in the error Stop returns if it doesn't.
+func (g *Group) Go(name string, fn func(ctx context.Context)) {
+	g.mu.Lock()
+	g.running[name]++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			g.mu.Lock()
+			if g.running[name]--; g.running[name] == 0 {
+				delete(g.running, name)
+			}
+			g.mu.Unlock()
+		}()
+		fn(g.ctx)
+	}()
+}
+
+// Stop cancels the workers' context and waits for them to return. If ctx ends first, Stop gives
+// up waiting and returns an error naming the workers still running.
+func (g *Group) Stop(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("background workers still running after %w: %s", ctx.Err(), strings.Join(g.Running(), ", "))
+	}
+}
+
+// Running returns the names of the workers that haven't returned yet, sorted
+func (g *Group) Running() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	names := make([]string, 0, len(g.running))
+	for name := range g.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,48 @@
+package lifecycle
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroup_StopWaitsForWorkers(t *testing.T) {
+	g := NewGroup()
+	finished := make(chan struct{})
+	g.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond) // Finishing in-flight work
+		close(finished)
+	})
+
+	if err := g.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Error("Stop() returned before the worker finished")
+	}
+	if running := g.Running(); len(running) != 0 {
+		t.Errorf("Running() = %v, want none", running)
+	}
+}
+
+func TestGroup_StopGivesUpAfterDeadline(t *testing.T) {
+	g := NewGroup()
+	release := make(chan struct{})
+	defer close(release)
+	g.Go("stuck", func(ctx context.Context) { <-release })
+	g.Go("prompt", func(ctx context.Context) { <-ctx.Done() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := g.Stop(ctx)
+	if err == nil {
+		t.Fatal("Stop() error = nil, want the workers still running")
+	}
+	if !strings.Contains(err.Error(), "stuck") || strings.Contains(err.Error(), "prompt") {
+		t.Errorf("Stop() error = %q, want only the stuck worker named", err)
+	}
+}
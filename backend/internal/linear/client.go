@@ -0,0 +1,320 @@
+package linear
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// apiURL is Linear's GraphQL API
+const apiURL = "https://api.linear.app/graphql"
+
+// maxPageSize is the most nodes Linear returns per page
+const maxPageSize = 250
+
+// Client represents a Linear API client authenticated with a workspace API key
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Linear client with a personal or workspace API key
+func NewClient(apiKey string) *Client {
+	return &Client{
+		apiKey:  apiKey,
+		baseURL: apiURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// WithBaseURL points the client at another GraphQL endpoint
+func (c *Client) WithBaseURL(baseURL string) *Client {
+	c.baseURL = baseURL
+	return c
+}
+
+// graphQLError is an error reported in a GraphQL response body
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// query runs a GraphQL query and decodes its data into out.
+// Linear reports most failures, including bad API keys, as errors in the response body.
+func (c *Client) query(query string, variables map[string]interface{}, out interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode query: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.baseURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	// API keys are sent as-is, without a Bearer prefix
+	req.Header.Set("Authorization", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("linear request failed (status %d): %s", resp.StatusCode, string(body))
+		}
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("linear request failed (status %d): %s", resp.StatusCode, strings.Join(messages, "; "))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("linear request failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// Organization is the Linear workspace an API key belongs to
+type Organization struct {
+	Name   string
+	URLKey string
+}
+
+// GetOrganization returns the workspace the API key belongs to, which also verifies the key
+func (c *Client) GetOrganization() (*Organization, error) {
+	var data struct {
+		Organization struct {
+			Name   string `json:"name"`
+			URLKey string `json:"urlKey"`
+		} `json:"organization"`
+	}
+	if err := c.query(`query { organization { name urlKey } }`, nil, &data); err != nil {
+		return nil, err
+	}
+	return &Organization{Name: data.Organization.Name, URLKey: data.Organization.URLKey}, nil
+}
+
+// GetUsers returns the members of the workspace
+func (c *Client) GetUsers() ([]models.LinearUser, error) {
+	const usersQuery = `query Users($first: Int!, $after: String) {
+		users(first: $first, after: $after) {
+			nodes { id name displayName email active }
+			pageInfo { hasNextPage endCursor }
+		}
+	}`
+
+	var users []models.LinearUser
+	var after *string
+	for {
+		var data struct {
+			Users struct {
+				Nodes []struct {
+					ID          string `json:"id"`
+					Name        string `json:"name"`
+					DisplayName string `json:"displayName"`
+					Email       string `json:"email"`
+					Active      bool   `json:"active"`
+				} `json:"nodes"`
+				PageInfo pageInfo `json:"pageInfo"`
+			} `json:"users"`
+		}
+		if err := c.query(usersQuery, map[string]interface{}{"first": maxPageSize, "after": after}, &data); err != nil {
+			return nil, err
+		}
+		for _, u := range data.Users.Nodes {
+			users = append(users, models.LinearUser{
+				ID:          u.ID,
+				Name:        u.Name,
+				DisplayName: u.DisplayName,
+				Email:       u.Email,
+				Active:      u.Active,
+			})
+		}
+		if !data.Users.PageInfo.HasNextPage {
+			return users, nil
+		}
+		after = &data.Users.PageInfo.EndCursor
+	}
+}
+
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// GetIssuesByAccountID returns the open issues assigned to a Linear user, most recently
+// updated first, in the same shape as Jira issues so task views work with either tracker
+func (c *Client) GetIssuesByAccountID(userID string, maxResults int) ([]models.JiraIssue, error) {
+	const issuesQuery = `query AssignedIssues($userId: ID!, $first: Int!) {
+		issues(
+			first: $first
+			orderBy: updatedAt
+			filter: {
+				assignee: { id: { eq: $userId } }
+				state: { type: { nin: ["completed", "canceled"] } }
+			}
+		) {
+			nodes {
+				id identifier title description url priorityLabel
+				createdAt updatedAt startedAt completedAt dueDate
+				state { name type }
+				team { id key name }
+				assignee { id name displayName email avatarUrl }
+				creator { id name displayName email avatarUrl }
+				parent { identifier title }
+				labels { nodes { name } }
+			}
+		}
+	}`
+
+	var data struct {
+		Issues struct {
+			Nodes []issueNode `json:"nodes"`
+		} `json:"issues"`
+	}
+	variables := map[string]interface{}{
+		"userId": userID,
+		"first":  min(max(maxResults, 1), maxPageSize),
+	}
+	if err := c.query(issuesQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	issues := make([]models.JiraIssue, 0, len(data.Issues.Nodes))
+	for _, node := range data.Issues.Nodes {
+		issues = append(issues, node.toIssue())
+	}
+	return issues, nil
+}
+
+type issueNode struct {
+	ID            string     `json:"id"`
+	Identifier    string     `json:"identifier"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	URL           string     `json:"url"`
+	PriorityLabel string     `json:"priorityLabel"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	StartedAt     *time.Time `json:"startedAt"`
+	CompletedAt   *time.Time `json:"completedAt"`
+	DueDate       string     `json:"dueDate"`
+	State         struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"state"`
+	Team struct {
+		ID   string `json:"id"`
+		Key  string `json:"key"`
+		Name string `json:"name"`
+	} `json:"team"`
+	Assignee *linearUser `json:"assignee"`
+	Creator  *linearUser `json:"creator"`
+	Parent   *struct {
+		Identifier string `json:"identifier"`
+		Title      string `json:"title"`
+	} `json:"parent"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+type linearUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Email       string `json:"email"`
+	AvatarURL   string `json:"avatarUrl"`
+}
+
+func (u *linearUser) toJiraUser() *models.JiraUser {
+	if u == nil {
+		return nil
+	}
+	return &models.JiraUser{
+		AccountID:   u.ID,
+		DisplayName: u.Name,
+		Email:       u.Email,
+		AvatarURL:   u.AvatarURL,
+	}
+}
+
+// toIssue maps a Linear issue onto the Jira issue shape. Teams stand in for projects
+// and parent issues for epics.
+func (n issueNode) toIssue() models.JiraIssue {
+	issue := models.JiraIssue{
+		ID:             n.ID,
+		Key:            n.Identifier,
+		Summary:        n.Title,
+		Description:    n.Description,
+		Status:         n.State.Name,
+		StatusCategory: statusCategory(n.State.Type),
+		Priority:       n.PriorityLabel,
+		IssueType:      "Issue",
+		Assignee:       n.Assignee.toJiraUser(),
+		Reporter:       n.Creator.toJiraUser(),
+		Project:        models.JiraProject{ID: n.Team.ID, Key: n.Team.Key, Name: n.Team.Name},
+		Created:        n.CreatedAt,
+		Updated:        n.UpdatedAt,
+		StartDate:      n.StartedAt,
+		ResolvedAt:     n.CompletedAt,
+		URL:            n.URL,
+	}
+	if n.Parent != nil {
+		issue.Epic = &models.JiraEpicLink{Key: n.Parent.Identifier, Summary: n.Parent.Title}
+	}
+	if n.DueDate != "" {
+		if t, err := time.Parse("2006-01-02", n.DueDate); err == nil {
+			issue.DueDate = &t
+		}
+	}
+	for _, label := range n.Labels.Nodes {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	return issue
+}
+
+// statusCategory maps a Linear workflow state type to our status categories
+func statusCategory(stateType string) string {
+	switch stateType {
+	case "backlog", "unstarted", "triage":
+		return models.JiraStatusCategoryToDo
+	case "started":
+		return models.JiraStatusCategoryInProgress
+	case "completed", "canceled":
+		return models.JiraStatusCategoryDone
+	}
+	return ""
+}
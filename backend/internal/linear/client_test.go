@@ -0,0 +1,124 @@
+package linear
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestClient_GetIssuesByAccountID(t *testing.T) {
+	var gotAuth string
+	var gotVariables map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotVariables = body.Variables
+		_, _ = w.Write([]byte(`{"data":{"issues":{"nodes":[{
+			"id": "abc-123",
+			"identifier": "ENG-42",
+			"title": "Add login page",
+			"url": "https://linear.app/acme/issue/ENG-42",
+			"priorityLabel": "High",
+			"createdAt": "2024-03-01T10:00:00Z",
+			"updatedAt": "2024-03-02T10:00:00Z",
+			"dueDate": "2024-03-15",
+			"state": {"name": "In Review", "type": "started"},
+			"team": {"id": "team-1", "key": "ENG", "name": "Engineering"},
+			"assignee": {"id": "user-1", "name": "Ada Lovelace", "email": "ada@example.com"},
+			"parent": {"identifier": "ENG-1", "title": "Auth"},
+			"labels": {"nodes": [{"name": "frontend"}]}
+		}]}}}`))
+	}))
+	defer server.Close()
+
+	issues, err := NewClient("lin_api_key").WithBaseURL(server.URL).GetIssuesByAccountID("user-1", 500)
+	if err != nil {
+		t.Fatalf("GetIssuesByAccountID() error = %v", err)
+	}
+	if gotAuth != "lin_api_key" {
+		t.Errorf("Authorization = %q, want the bare API key", gotAuth)
+	}
+	if gotVariables["userId"] != "user-1" || gotVariables["first"] != float64(maxPageSize) {
+		t.Errorf("variables = %v", gotVariables)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1", len(issues))
+	}
+
+	issue := issues[0]
+	if issue.Key != "ENG-42" || issue.Summary != "Add login page" || issue.Priority != "High" {
+		t.Errorf("issue = %+v", issue)
+	}
+	if issue.Status != "In Review" || issue.StatusCategory != models.JiraStatusCategoryInProgress {
+		t.Errorf("status = %q (%q)", issue.Status, issue.StatusCategory)
+	}
+	if issue.Project.Key != "ENG" || issue.Epic == nil || issue.Epic.Key != "ENG-1" {
+		t.Errorf("project = %+v, epic = %+v", issue.Project, issue.Epic)
+	}
+	if issue.Assignee == nil || issue.Assignee.AccountID != "user-1" || issue.Assignee.DisplayName != "Ada Lovelace" {
+		t.Errorf("assignee = %+v", issue.Assignee)
+	}
+	if issue.DueDate == nil || issue.DueDate.Format("2006-01-02") != "2024-03-15" {
+		t.Errorf("due date = %v", issue.DueDate)
+	}
+	if len(issue.Labels) != 1 || issue.Labels[0] != "frontend" {
+		t.Errorf("labels = %v", issue.Labels)
+	}
+}
+
+func TestClient_GraphQLErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errors":[{"message":"Authentication required, not authenticated"}]}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient("bad").WithBaseURL(server.URL).GetOrganization()
+	if err == nil || !strings.Contains(err.Error(), "Authentication required") {
+		t.Errorf("GetOrganization() error = %v, want the GraphQL error message", err)
+	}
+}
+
+func TestClient_GetUsers_Paginates(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			_, _ = w.Write([]byte(`{"data":{"users":{"nodes":[{"id":"u1","name":"Ada","active":true}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"users":{"nodes":[{"id":"u2","name":"Grace","active":false}],"pageInfo":{"hasNextPage":false}}}}`))
+	}))
+	defer server.Close()
+
+	users, err := NewClient("lin_api_key").WithBaseURL(server.URL).GetUsers()
+	if err != nil {
+		t.Fatalf("GetUsers() error = %v", err)
+	}
+	if calls != 2 || len(users) != 2 || users[1].ID != "u2" || users[1].Active {
+		t.Errorf("calls = %d, users = %+v", calls, users)
+	}
+}
+
+func TestStatusCategory(t *testing.T) {
+	tests := map[string]string{
+		"backlog":   models.JiraStatusCategoryToDo,
+		"unstarted": models.JiraStatusCategoryToDo,
+		"started":   models.JiraStatusCategoryInProgress,
+		"completed": models.JiraStatusCategoryDone,
+		"canceled":  models.JiraStatusCategoryDone,
+		"unknown":   "",
+	}
+	for stateType, want := range tests {
+		if got := statusCategory(stateType); got != want {
+			t.Errorf("statusCategory(%q) = %q, want %q", stateType, got, want)
+		}
+	}
+}
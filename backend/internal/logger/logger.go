@@ -7,6 +7,8 @@ import (
 	"os"
 	"runtime"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Logger wraps slog.Logger with additional convenience methods
@@ -129,7 +131,11 @@ func (l *Logger) WithGroup(name string) *Logger {
 func (l *Logger) WithContext(ctx context.Context) *Logger {
 	// Extract request ID if present in context
 	if reqID := ctx.Value(RequestIDKey); reqID != nil {
-		return l.With("request_id", reqID)
+		l = l.With("request_id", reqID)
+	}
+	// Link the log line to its trace when the request is being traced
+	if sc := trace.SpanContextFromContext(ctx); sc.IsSampled() {
+		l = l.With("trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
 	}
 	return l
 }
@@ -259,6 +265,7 @@ const (
 	AuditActionRevoke  AuditAction = "revoke"
 	AuditActionConnect AuditAction = "connect"
 	AuditActionPublish AuditAction = "publish"
+	AuditActionView    AuditAction = "view"
 )
 
 // AuditResult represents the result of an auditable action
@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// AuditContextKey holds the audit state of the current request
+const AuditContextKey contextKey = "audit"
+
+// maxAuditBodyBytes bounds the response body kept as an audit event's after state; larger
+// responses are recorded without it
+const maxAuditBodyBytes = 64 << 10
+
+// AuditRecorder stores audit events
+type AuditRecorder interface {
+	Create(ctx context.Context, event *models.AuditEvent) error
+}
+
+// auditState carries what an audit event needs from the HTTP request, plus the before state a
+// handler recorded
+type auditState struct {
+	ip     string
+	before json.RawMessage
+}
+
+// AuditContext keeps the client IP in the context so audit events recorded below the HTTP layer,
+// such as for GraphQL mutations, can include it. AuditRequests does this itself.
+func AuditContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), AuditContextKey, &auditState{ip: ClientIP(r)})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ClientIP returns the client IP of a request without the port RemoteAddr carries
+func ClientIP(r *http.Request) string {
+	ip := getIP(r)
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		return host
+	}
+	return ip
+}
+
+// SetAuditBefore records the state of the entity a request is about to change. Handlers call it
+// once they have loaded the entity; outside an audited request it does nothing.
+func SetAuditBefore(ctx context.Context, v any) {
+	state, ok := ctx.Value(AuditContextKey).(*auditState)
+	if !ok {
+		return
+	}
+	if data, err := json.Marshal(v); err == nil {
+		state.before = data
+	}
+}
+
+// NewAuditEvent starts an audit event for the current request with the actor, client IP, request
+// ID, and any recorded before state filled in
+func NewAuditEvent(ctx context.Context, action string) *models.AuditEvent {
+	event := &models.AuditEvent{Action: action}
+	if actor := GetRealUserFromContext(ctx); actor != nil {
+		event.ActorID = &actor.ID
+		event.ActorEmail = actor.Email
+	} else if actor := GetUserFromContext(ctx); actor != nil {
+		event.ActorID = &actor.ID
+		event.ActorEmail = actor.Email
+	}
+	if IsImpersonating(ctx) {
+		if user := GetUserFromContext(ctx); user != nil {
+			event.OnBehalfOfID = &user.ID
+		}
+		if session := GetImpersonationSessionFromContext(ctx); session != nil {
+			event.ImpersonationSessionID = &session.ID
+		}
+	}
+	if state, ok := ctx.Value(AuditContextKey).(*auditState); ok {
+		event.IPAddress = state.ip
+		event.Before = state.before
+	}
+	if requestID, ok := ctx.Value(logger.RequestIDKey).(string); ok {
+		event.RequestID = requestID
+	}
+	return event
+}
+
+// auditWriter captures the status and the start of the body of a response
+type auditWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *auditWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.truncated {
+		if w.body.Len()+len(b) > maxAuditBodyBytes {
+			w.truncated = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter
+func (w *auditWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// AuditRequests records an audit event for every POST, PUT, PATCH, and DELETE request once it has
+// been handled, keeping successful JSON responses as the after state. Must run after Authenticate.
+// Recording failures are logged and never fail the request.
+func AuditRequests(recorder AuditRecorder) func(http.Handler) http.Handler {
+	log := logger.Default().WithComponent("audit")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), AuditContextKey, &auditState{ip: ClientIP(r)})
+			aw := &auditWriter{ResponseWriter: w}
+			next.ServeHTTP(aw, r.WithContext(ctx))
+
+			pattern := r.URL.Path
+			rctx := chi.RouteContext(ctx)
+			if rctx != nil && rctx.RoutePattern() != "" {
+				pattern = rctx.RoutePattern()
+			}
+
+			event := NewAuditEvent(ctx, r.Method+" "+pattern)
+			event.Status = aw.status
+			if event.Status == 0 {
+				event.Status = http.StatusOK
+			}
+			if event.Status < 300 && !aw.truncated && json.Valid(aw.body.Bytes()) {
+				event.After = json.RawMessage(aw.body.Bytes())
+			}
+			event.EntityType, event.EntityID = auditEntity(pattern, rctx, event.After)
+
+			// The request may have been cancelled by the client once the response was written
+			if err := recorder.Create(context.WithoutCancel(ctx), event); err != nil {
+				log.LogError(ctx, "Failed to record audit event", err, "action", event.Action)
+			}
+		})
+	}
+}
+
+// auditEntity works out what a request changed from its route: the collection before the last
+// URL parameter and that parameter's value, as in /api/users/{id}/offboard. Routes without
+// parameters, such as creates, use their last segment and the id in the response.
+func auditEntity(pattern string, rctx *chi.Context, after json.RawMessage) (string, *string) {
+	segments := strings.Split(strings.Trim(pattern, "/"), "/")
+	for i := len(segments) - 1; i > 0; i-- {
+		param, ok := strings.CutPrefix(segments[i], "{")
+		if !ok || strings.HasPrefix(segments[i-1], "{") {
+			continue
+		}
+		// Patterns like {id:[0-9]+} carry a regexp after the name
+		param, _, _ = strings.Cut(strings.TrimSuffix(param, "}"), ":")
+		if rctx == nil {
+			return segments[i-1], nil
+		}
+		id := rctx.URLParam(param)
+		return segments[i-1], &id
+	}
+
+	entityType := segments[len(segments)-1]
+	var body struct {
+		ID json.Number `json:"id"`
+	}
+	if len(after) > 0 && json.Unmarshal(after, &body) == nil && body.ID != "" {
+		id := body.ID.String()
+		return entityType, &id
+	}
+	return entityType, nil
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// auditLog keeps recorded audit events in memory
+type auditLog struct {
+	events []*models.AuditEvent
+}
+
+func (l *auditLog) Create(ctx context.Context, event *models.AuditEvent) error {
+	l.events = append(l.events, event)
+	return nil
+}
+
+func TestAuditRequests(t *testing.T) {
+	admin := &models.User{ID: 1, Email: "admin@example.com", Role: models.RoleAdmin}
+	target := &models.User{ID: 7, Email: "employee@example.com", FirstName: "Old"}
+
+	log := &auditLog{}
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), UserContextKey, admin)
+			ctx = context.WithValue(ctx, logger.RequestIDKey, "req-1")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	r.Use(AuditRequests(log))
+	r.Get("/api/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	r.Put("/api/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		SetAuditBefore(r.Context(), target)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":7,"first_name":"New"}`))
+	})
+	r.Post("/api/kudos", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":42}`))
+	})
+	r.Delete("/api/squads/{id}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"forbidden"}`, http.StatusForbidden)
+	})
+
+	send := func(method, path string) {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "203.0.113.5:4321"
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	send(http.MethodGet, "/api/users/7")
+	if len(log.events) != 0 {
+		t.Fatalf("GET recorded %d events, want 0", len(log.events))
+	}
+
+	send(http.MethodPut, "/api/users/7")
+	send(http.MethodPost, "/api/kudos")
+	send(http.MethodDelete, "/api/squads/3")
+	if len(log.events) != 3 {
+		t.Fatalf("recorded %d events, want 3", len(log.events))
+	}
+
+	update := log.events[0]
+	if update.Action != "PUT /api/users/{id}" || update.EntityType != "users" || update.EntityID == nil || *update.EntityID != "7" {
+		t.Errorf("update event = %q %q %v, want PUT /api/users/{id} users 7", update.Action, update.EntityType, update.EntityID)
+	}
+	if update.ActorID == nil || *update.ActorID != admin.ID || update.ActorEmail != admin.Email {
+		t.Errorf("update actor = %v %q, want admin", update.ActorID, update.ActorEmail)
+	}
+	if update.IPAddress != "203.0.113.5" || update.RequestID != "req-1" || update.Status != http.StatusOK {
+		t.Errorf("update ip, request ID, status = %q %q %d", update.IPAddress, update.RequestID, update.Status)
+	}
+	if string(update.After) != `{"id":7,"first_name":"New"}` {
+		t.Errorf("update after = %s", update.After)
+	}
+	if len(update.Before) == 0 {
+		t.Error("update before was not recorded")
+	}
+
+	create := log.events[1]
+	if create.EntityType != "kudos" || create.EntityID == nil || *create.EntityID != "42" || create.Status != http.StatusCreated {
+		t.Errorf("create event = %q %v %d, want kudos 42 201", create.EntityType, create.EntityID, create.Status)
+	}
+
+	denied := log.events[2]
+	if denied.Status != http.StatusForbidden || denied.After != nil || denied.EntityType != "squads" || *denied.EntityID != "3" {
+		t.Errorf("denied event = %d %s %q %v, want 403 without after state", denied.Status, denied.After, denied.EntityType, denied.EntityID)
+	}
+}
+
+func TestNewAuditEvent_Impersonation(t *testing.T) {
+	admin := &models.User{ID: 1, Email: "admin@example.com", Role: models.RoleAdmin}
+	employee := &models.User{ID: 7, Email: "employee@example.com"}
+
+	ctx := context.WithValue(context.Background(), UserContextKey, employee)
+	ctx = context.WithValue(ctx, RealUserContextKey, admin)
+	ctx = context.WithValue(ctx, ImpersonationContextKey, true)
+
+	event := NewAuditEvent(ctx, "mutation updateEmployee")
+	if event.ActorID == nil || *event.ActorID != admin.ID {
+		t.Errorf("ActorID = %v, want the admin", event.ActorID)
+	}
+	if event.OnBehalfOfID == nil || *event.OnBehalfOfID != employee.ID {
+		t.Errorf("OnBehalfOfID = %v, want the impersonated user", event.OnBehalfOfID)
+	}
+	if event.ImpersonationSessionID != nil {
+		t.Errorf("ImpersonationSessionID = %v, want none without a session", event.ImpersonationSessionID)
+	}
+
+	ctx = context.WithValue(ctx, ImpersonationSessionContextKey, &models.ImpersonationSession{ID: 5, AdminID: admin.ID, UserID: employee.ID})
+	event = NewAuditEvent(ctx, "mutation updateEmployee")
+	if event.ImpersonationSessionID == nil || *event.ImpersonationSessionID != 5 {
+		t.Errorf("ImpersonationSessionID = %v, want the impersonation session", event.ImpersonationSessionID)
+	}
+}
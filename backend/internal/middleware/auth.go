@@ -2,17 +2,18 @@ package middleware
 
 import (
 	"context"
-	"fmt"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
-	"github.com/auth0/go-jwt-middleware/v2/jwks"
-	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
+	"github.com/smith-dallin/manager-dashboard/internal/cache"
 	"github.com/smith-dallin/manager-dashboard/internal/database"
+	"github.com/smith-dallin/manager-dashboard/internal/identity"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
 )
 
 type contextKey string
@@ -22,140 +23,389 @@ const (
 	RealUserContextKey      contextKey = "real_user"      // The actual authenticated user
 	ClaimsContextKey        contextKey = "claims"
 	ImpersonationContextKey contextKey = "is_impersonating"
+	PermissionsContextKey   contextKey = "permissions" // The effective user's permissions, including custom roles
+	// The impersonation session a request is made in
+	ImpersonationSessionContextKey contextKey = "impersonation_session"
+	// Who signed in, for requests that don't need an account, such as requesting one
+	IdentityContextKey contextKey = "identity"
 )
 
-type CustomClaims struct {
-	Email string `json:"email"`
-	Name  string `json:"name"`
+const (
+	// ImpersonationSessionHeader carries the ID of the impersonation session a request is made in
+	ImpersonationSessionHeader = "X-Impersonation-Session-Id"
+	// ImpersonatedUserHeader and ImpersonatedByHeader mark responses to impersonated requests with
+	// the user acted as and the admin acting
+	ImpersonatedUserHeader = "X-Impersonated-User-Id"
+	ImpersonatedByHeader   = "X-Impersonated-By"
+)
+
+// TokenVerifier validates a bearer token and returns who it was issued to
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*identity.Identity, error)
+}
+
+// SessionTracker checks tokens against the denylist of revoked ones and records the sessions
+// they belong to
+type SessionTracker interface {
+	IsRevoked(ctx context.Context, ident *identity.Identity) (bool, error)
+	Touch(ctx context.Context, userID int64, ident *identity.Identity, ipAddress, userAgent string) error
+}
+
+// UserStore looks up the users tokens are issued to and gives first-time sign-ins their accounts
+type UserStore interface {
+	GetByID(ctx context.Context, id int64) (*models.User, error)
+	GetByAuth0ID(ctx context.Context, auth0ID string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	ClaimAccount(ctx context.Context, id int64, auth0ID, firstName, lastName string) (*models.User, error)
+	CreateOrUpdate(ctx context.Context, auth0ID, email, firstName, lastName string) (*models.User, error)
 }
 
-func (c CustomClaims) Validate(ctx context.Context) error {
-	return nil
+// SignupInvitationStore looks up the pending invitation to an email
+type SignupInvitationStore interface {
+	GetByEmail(ctx context.Context, email string) (*models.Invitation, error)
+}
+
+// SignupDomainStore finds the organization whose invitations are limited to an email domain
+type SignupDomainStore interface {
+	GetByInvitationDomain(ctx context.Context, domain string) (*int64, error)
+}
+
+// ImpersonationStore looks up the sessions in which admins impersonate users
+type ImpersonationStore interface {
+	GetByID(ctx context.Context, id int64) (*models.ImpersonationSession, error)
 }
 
 type AuthMiddleware struct {
-	validator      *validator.Validator
-	userRepository *database.UserRepository
+	verifier       TokenVerifier
+	userRepository UserStore
+	customRoles    *database.CustomRoleRepository
+	sessions       SessionTracker
+	impersonation  ImpersonationStore
+	ipAllowlists   *orgIPAllowlists
+	invitations    SignupInvitationStore
+	signupDomains  SignupDomainStore
+	// Accounts are only created for people who sign in without one when this is false
+	signupApproval bool
 }
 
-func NewAuthMiddleware(domain, audience string, userRepo *database.UserRepository, jwksCacheTTLMinutes int) (*AuthMiddleware, error) {
-	issuerURL, err := url.Parse("https://" + domain + "/")
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse issuer URL: %w", err)
+// NewAuthMiddleware creates middleware that authenticates requests with tokens accepted by the
+// verifier, such as an identity.Verifier trusting Auth0, a generic OIDC provider, or a SAML broker
+func NewAuthMiddleware(verifier TokenVerifier, userRepo UserStore) *AuthMiddleware {
+	return &AuthMiddleware{
+		verifier:       verifier,
+		userRepository: userRepo,
+	}
+}
+
+// WithCustomRoles loads each request's custom roles so their permissions apply on top of the
+// user's built-in role
+func (m *AuthMiddleware) WithCustomRoles(repo *database.CustomRoleRepository) *AuthMiddleware {
+	m.customRoles = repo
+	return m
+}
+
+// WithSessions rejects revoked tokens and records each user's recent sessions
+func (m *AuthMiddleware) WithSessions(tracker SessionTracker) *AuthMiddleware {
+	m.sessions = tracker
+	return m
+}
+
+// WithImpersonation lets admins act as another user within an impersonation session, named by
+// the X-Impersonation-Session-Id header
+func (m *AuthMiddleware) WithImpersonation(store ImpersonationStore) *AuthMiddleware {
+	m.impersonation = store
+	return m
+}
+
+// WithIPAllowlists rejects users connecting from outside the networks their organization allows,
+// recording each attempt. Changes to an allowlist apply within a minute.
+func (m *AuthMiddleware) WithIPAllowlists(store OrgIPAllowlistStore, recorder AuditRecorder) *AuthMiddleware {
+	m.ipAllowlists = &orgIPAllowlists{
+		store:    store,
+		recorder: recorder,
+		cache:    cache.New(orgIPAllowlistTTL, orgIPAllowlistTTL),
+	}
+	return m
+}
+
+// WithSignupOrgs lets people sign up without an account on servers shared by several
+// organizations, in the organization that invited them or whose invitation domains include
+// their email's. Everyone else is turned away, as there's no default organization to put them in.
+func (m *AuthMiddleware) WithSignupOrgs(invitations SignupInvitationStore, domains SignupDomainStore) *AuthMiddleware {
+	m.invitations = invitations
+	m.signupDomains = domains
+	return m
+}
+
+// WithSignupApproval stops giving accounts to people who sign in without one. They're turned
+// away unless an account was already made for their email, e.g. by an admin or SCIM, and can
+// request access instead.
+func (m *AuthMiddleware) WithSignupApproval() *AuthMiddleware {
+	m.signupApproval = true
+	return m
+}
+
+// verify checks the request's bearer token, writing a 401 and returning nil unless it's valid
+// and hasn't been revoked
+func (m *AuthMiddleware) verify(w http.ResponseWriter, r *http.Request) *identity.Identity {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		writeAppError(w, apperrors.NewUnauthorizedError("Authorization header required"))
+		return nil
 	}
 
-	// Use configurable JWKS cache TTL (default 5 minutes if not specified)
-	jwksCacheTTL := time.Duration(jwksCacheTTLMinutes) * time.Minute
-	if jwksCacheTTL <= 0 {
-		jwksCacheTTL = 5 * time.Minute
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		writeAppError(w, apperrors.NewUnauthorizedErrorWithCode(apperrors.CodeInvalidToken, "Invalid authorization header format"))
+		return nil
 	}
-	provider := jwks.NewCachingProvider(issuerURL, jwksCacheTTL)
 
-	jwtValidator, err := validator.New(
-		provider.KeyFunc,
-		validator.RS256,
-		issuerURL.String(),
-		[]string{audience},
-		validator.WithCustomClaims(func() validator.CustomClaims {
-			return &CustomClaims{}
-		}),
-	)
+	token := parts[1]
+
+	ident, err := m.verifier.Verify(r.Context(), token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create JWT validator: %w", err)
+		writeAppError(w, apperrors.NewUnauthorizedErrorWithCode(apperrors.CodeInvalidToken, "Invalid token: "+err.Error()))
+		return nil
 	}
 
-	return &AuthMiddleware{
-		validator:      jwtValidator,
-		userRepository: userRepo,
-	}, nil
+	if m.sessions != nil {
+		revoked, err := m.sessions.IsRevoked(r.Context(), ident)
+		if err != nil {
+			logger.Default().WithComponent("auth").LogError(r.Context(), "Failed to check token revocation", err)
+			writeAppError(w, apperrors.NewInternalError("Failed to check token", err))
+			return nil
+		}
+		if revoked {
+			writeAppError(w, apperrors.NewUnauthorizedErrorWithCode(apperrors.CodeInvalidToken, "Token has been revoked"))
+			return nil
+		}
+	}
+	return ident
 }
 
-func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
+// AuthenticateIdentity only checks the request's token, for routes used by people who may not
+// have an account yet. The identity is available from GetIdentityFromContext.
+func (m *AuthMiddleware) AuthenticateIdentity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ident := m.verify(w, r)
+		if ident == nil {
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), IdentityContextKey, ident)))
+	})
+}
+
+// AuthenticateIfPresent authenticates requests with a bearer token like Authenticate, and lets
+// the rest through anonymously. It's for public routes the frontend server also relays for
+// signed-in users.
+func (m *AuthMiddleware) AuthenticateIfPresent(next http.Handler) http.Handler {
+	authenticated := m.Authenticate(next)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Authorization header required", http.StatusUnauthorized)
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
 			return
 		}
+		authenticated.ServeHTTP(w, r)
+	})
+}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ident := m.verify(w, r)
+		if ident == nil {
 			return
 		}
 
-		token := parts[1]
+		auth0ID := ident.Subject
 
-		claims, err := m.validator.ValidateToken(r.Context(), token)
+		// Try to get user from database, create if doesn't exist
+		user, err := m.userRepository.GetByAuth0ID(r.Context(), auth0ID)
 		if err != nil {
-			http.Error(w, "Invalid token: "+err.Error(), http.StatusUnauthorized)
+			user, err = m.signUp(r.Context(), ident)
+			if err != nil {
+				writeAppError(w, err)
+				return
+			}
+		}
+
+		// Deleted users keep their row until it is purged, so they must not sign in again
+		if user.IsDeleted() {
+			writeAppError(w, apperrors.NewForbiddenErrorWithCode(apperrors.CodeAccountDeleted, "Account has been deleted"))
 			return
 		}
 
-		validatedClaims, ok := claims.(*validator.ValidatedClaims)
-		if !ok {
-			http.Error(w, "Invalid claims", http.StatusUnauthorized)
+		// Deactivated users are blocked in Auth0 too, but tokens issued before then stay valid
+		if !user.IsActive {
+			writeAppError(w, apperrors.NewForbiddenErrorWithCode(apperrors.CodeAccountDeactivated, "Account has been deactivated"))
 			return
 		}
 
-		customClaims, ok := validatedClaims.CustomClaims.(*CustomClaims)
-		if !ok {
-			customClaims = &CustomClaims{}
+		// Guest accounts stop working once their access window closes
+		if user.HasAccessExpired() {
+			writeAppError(w, apperrors.NewForbiddenErrorWithCode(apperrors.CodeGuestRestricted, "Guest access has expired"))
+			return
 		}
 
-		auth0ID := validatedClaims.RegisteredClaims.Subject
+		if m.ipAllowlists != nil && !m.ipAllowlists.check(w, r, user) {
+			return
+		}
 
-		// Try to get user from database, create if doesn't exist
-		user, err := m.userRepository.GetByAuth0ID(r.Context(), auth0ID)
-		if err != nil {
-			// User doesn't exist, create them
-			firstName, lastName := parseName(customClaims.Name)
-			user, err = m.userRepository.CreateOrUpdate(r.Context(), auth0ID, customClaims.Email, firstName, lastName)
-			if err != nil {
-				http.Error(w, "Failed to create user", http.StatusInternalServerError)
-				return
+		if m.sessions != nil {
+			if err := m.sessions.Touch(r.Context(), user.ID, ident, ClientIP(r), r.UserAgent()); err != nil {
+				logger.Default().WithComponent("auth").Warn("Failed to record session", "user_id", user.ID, "error", err)
 			}
 		}
 
-		// Store the real authenticated user
+		// Store the real authenticated user, and keep the request to their organization's data;
+		// impersonation lookups are scoped too, so admins can't act as users of another organization
 		ctx := context.WithValue(r.Context(), RealUserContextKey, user)
-		ctx = context.WithValue(ctx, ClaimsContextKey, validatedClaims)
+		ctx = context.WithValue(ctx, ClaimsContextKey, ident.Claims)
+		ctx = tenant.WithOrgID(ctx, user.OrgID)
 
-		// Check for impersonation header (admin only)
 		effectiveUser := user
 		isImpersonating := false
-		if impersonateHeader := r.Header.Get("X-Impersonate-User-Id"); impersonateHeader != "" {
-			// Only admins can impersonate
-			if user.Role == models.RoleAdmin {
-				impersonateID, err := strconv.ParseInt(impersonateHeader, 10, 64)
-				if err == nil && impersonateID != user.ID {
-					impersonatedUser, err := m.userRepository.GetByID(r.Context(), impersonateID)
-					if err == nil {
-						effectiveUser = impersonatedUser
-						isImpersonating = true
-					}
-				}
+		if sessionHeader := r.Header.Get(ImpersonationSessionHeader); sessionHeader != "" && m.impersonation != nil {
+			// Fail rather than fall back to the admin, who would otherwise make changes as
+			// themselves believing they were acting as the user
+			session, impersonatedUser := m.resolveImpersonation(ctx, user, sessionHeader)
+			if session == nil {
+				writeAppError(w, apperrors.NewForbiddenError("Impersonation session is invalid or has ended"))
+				return
+			}
+			effectiveUser = impersonatedUser
+			isImpersonating = true
+			ctx = context.WithValue(ctx, ImpersonationSessionContextKey, session)
+		}
+		if isImpersonating {
+			w.Header().Set(ImpersonatedUserHeader, strconv.FormatInt(effectiveUser.ID, 10))
+			w.Header().Set(ImpersonatedByHeader, strconv.FormatInt(user.ID, 10))
+		}
+
+		if m.customRoles != nil {
+			customRoles, err := m.customRoles.GetForUser(ctx, effectiveUser.ID)
+			if err != nil {
+				// Fall back to the built-in role rather than locking the user out
+				logger.Default().WithComponent("auth").Warn("Failed to load custom roles", "user_id", effectiveUser.ID, "error", err)
+			} else {
+				effectiveUser.CustomRoles = customRoles
 			}
 		}
 
 		ctx = context.WithValue(ctx, UserContextKey, effectiveUser)
 		ctx = context.WithValue(ctx, ImpersonationContextKey, isImpersonating)
+		ctx = context.WithValue(ctx, PermissionsContextKey, authz.Permissions(effectiveUser))
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// signUp gives someone signing in for the first time their account. They claim an account made
+// for their email before they first signed in, but only once their provider has verified it. An
+// account that has been signed in to is never relinked by email, or any trusted issuer could take
+// it over by presenting its email.
+func (m *AuthMiddleware) signUp(ctx context.Context, ident *identity.Identity) (*models.User, error) {
+	firstName, lastName := parseName(ident.Name)
+
+	var existing *models.User
+	if ident.Email != "" {
+		existing, _ = m.userRepository.GetByEmail(ctx, ident.Email)
+	}
+	if existing != nil {
+		if existing.Auth0ID != "" {
+			return nil, apperrors.NewConflictError("An account already uses this email: sign in the way you did before")
+		}
+		if !ident.EmailVerified {
+			return nil, apperrors.NewForbiddenErrorWithCode(apperrors.CodeEmailUnverified, "Verify your email address before signing in")
+		}
+		user, err := m.userRepository.ClaimAccount(ctx, existing.ID, ident.Subject, firstName, lastName)
+		if err != nil {
+			// Claimed by a concurrent sign-in
+			return nil, apperrors.NewConflictError("An account already uses this email: sign in the way you did before")
+		}
+		return user, nil
+	}
+
+	// With signup approval, only accounts made for the user's email are claimed
+	if m.signupApproval {
+		return nil, apperrors.NewForbiddenErrorWithCode(apperrors.CodeAccessRequired, "No account: request access or ask an admin for an invitation")
+	}
+	if tenant.IsRequired() {
+		orgID, err := m.signupOrg(ctx, ident)
+		if err != nil {
+			return nil, err
+		}
+		ctx = tenant.WithOrgID(ctx, orgID)
+	}
+	user, err := m.userRepository.CreateOrUpdate(ctx, ident.Subject, ident.Email, firstName, lastName)
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to create user", err)
+	}
+	return user, nil
+}
+
+// signupOrg returns the organization someone signing up on a server shared by several
+// organizations joins: the one that invited them, whose invitation fills in their account once
+// accepted, or else the one whose invitation domains include their email's. Their provider must
+// have verified the email, and without either they're turned away.
+func (m *AuthMiddleware) signupOrg(ctx context.Context, ident *identity.Identity) (int64, error) {
+	noAccount := apperrors.NewForbiddenErrorWithCode(apperrors.CodeAccessRequired, "No account: ask an admin for an invitation")
+	at := strings.LastIndex(ident.Email, "@")
+	if m.invitations == nil || m.signupDomains == nil || at < 0 {
+		return 0, noAccount
+	}
+	if !ident.EmailVerified {
+		return 0, apperrors.NewForbiddenErrorWithCode(apperrors.CodeEmailUnverified, "Verify your email address before signing in")
+	}
+
+	// GetByEmail fails when there's no pending invitation
+	if invitation, _ := m.invitations.GetByEmail(ctx, ident.Email); invitation != nil {
+		return invitation.OrgID, nil
+	}
+
+	orgID, err := m.signupDomains.GetByInvitationDomain(ctx, strings.ToLower(ident.Email[at+1:]))
+	if err != nil {
+		return 0, apperrors.NewInternalError("Failed to look up organization", err)
+	}
+	if orgID == nil {
+		return 0, noAccount
+	}
+	return *orgID, nil
+}
+
+// resolveImpersonation returns the impersonation session named by the header and the user it
+// impersonates, or nil unless the session is active and was started by this user, who may still
+// impersonate
+func (m *AuthMiddleware) resolveImpersonation(ctx context.Context, user *models.User, sessionHeader string) (*models.ImpersonationSession, *models.User) {
+	sessionID, err := strconv.ParseInt(sessionHeader, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+	session, err := m.impersonation.GetByID(ctx, sessionID)
+	if err != nil {
+		logger.Default().WithComponent("auth").LogError(ctx, "Failed to get impersonation session", err, "session_id", sessionID)
+		return nil, nil
+	}
+	if session == nil || session.AdminID != user.ID || !session.IsActive() || !authz.Can(user, authz.ActionUserImpersonate, nil) {
+		return nil, nil
+	}
+	impersonatedUser, err := m.userRepository.GetByID(ctx, session.UserID)
+	if err != nil || impersonatedUser == nil {
+		return nil, nil
+	}
+	return session, impersonatedUser
+}
+
 func (m *AuthMiddleware) RequireRole(role models.Role) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			user := GetUserFromContext(r.Context())
 			if user == nil {
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				writeAppError(w, apperrors.NewUnauthorizedError(""))
 				return
 			}
 
 			if user.Role != role {
-				http.Error(w, "Forbidden: insufficient permissions", http.StatusForbidden)
+				writeAppError(w, apperrors.NewForbiddenErrorWithCode(apperrors.CodePermissionDenied, "Forbidden: insufficient permissions"))
 				return
 			}
 
@@ -173,6 +423,15 @@ func GetUserFromContext(ctx context.Context) *models.User {
 	return user
 }
 
+// GetIdentityFromContext returns who signed in on routes that use AuthenticateIdentity
+func GetIdentityFromContext(ctx context.Context) *identity.Identity {
+	ident, ok := ctx.Value(IdentityContextKey).(*identity.Identity)
+	if !ok {
+		return nil
+	}
+	return ident
+}
+
 // GetRealUserFromContext returns the actual authenticated user (ignoring impersonation)
 func GetRealUserFromContext(ctx context.Context) *models.User {
 	user, ok := ctx.Value(RealUserContextKey).(*models.User)
@@ -188,6 +447,25 @@ func IsImpersonating(ctx context.Context) bool {
 	return ok && isImpersonating
 }
 
+// GetImpersonationSessionFromContext returns the impersonation session the request is made in,
+// or nil when it isn't made in one
+func GetImpersonationSessionFromContext(ctx context.Context) *models.ImpersonationSession {
+	session, ok := ctx.Value(ImpersonationSessionContextKey).(*models.ImpersonationSession)
+	if !ok {
+		return nil
+	}
+	return session
+}
+
+// GetPermissionsFromContext returns the effective user's permissions, falling back to those of
+// the user in context when the auth middleware did not store them
+func GetPermissionsFromContext(ctx context.Context) []authz.Permission {
+	if permissions, ok := ctx.Value(PermissionsContextKey).([]authz.Permission); ok {
+		return permissions
+	}
+	return authz.Permissions(GetUserFromContext(ctx))
+}
+
 func parseName(name string) (string, string) {
 	parts := strings.SplitN(strings.TrimSpace(name), " ", 2)
 	if len(parts) == 2 {
@@ -2,9 +2,17 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/identity"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
 )
 
 func TestGetUserFromContext(t *testing.T) {
@@ -119,14 +127,312 @@ func TestContextKey(t *testing.T) {
 	}
 }
 
-func TestCustomClaims_Validate(t *testing.T) {
-	claims := &CustomClaims{
-		Email: "test@example.com",
-		Name:  "Test User",
+// tokenIdentities verifies tokens by looking up who each was issued to
+type tokenIdentities map[string]*identity.Identity
+
+func (v tokenIdentities) Verify(ctx context.Context, token string) (*identity.Identity, error) {
+	if ident, ok := v[token]; ok {
+		return ident, nil
 	}
+	return nil, errors.New("invalid token")
+}
+
+type userStore struct {
+	users  []*models.User
+	nextID int64
+}
+
+func (s *userStore) find(match func(*models.User) bool) (*models.User, error) {
+	for _, user := range s.users {
+		if match(user) {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
 
-	err := claims.Validate(context.Background())
+func (s *userStore) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	return s.find(func(u *models.User) bool { return u.ID == id })
+}
+
+func (s *userStore) GetByAuth0ID(ctx context.Context, auth0ID string) (*models.User, error) {
+	return s.find(func(u *models.User) bool { return u.Auth0ID == auth0ID })
+}
+
+func (s *userStore) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.find(func(u *models.User) bool { return u.Email == email })
+}
+
+func (s *userStore) ClaimAccount(ctx context.Context, id int64, auth0ID, firstName, lastName string) (*models.User, error) {
+	user, err := s.find(func(u *models.User) bool { return u.ID == id && u.Auth0ID == "" })
 	if err != nil {
-		t.Errorf("CustomClaims.Validate() error = %v, want nil", err)
+		return nil, err
+	}
+	user.Auth0ID = auth0ID
+	return user, nil
+}
+
+func (s *userStore) CreateOrUpdate(ctx context.Context, auth0ID, email, firstName, lastName string) (*models.User, error) {
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.nextID++
+	user := &models.User{ID: 100 + s.nextID, Auth0ID: auth0ID, Email: email, FirstName: firstName, LastName: lastName, IsActive: true, OrgID: orgID}
+	s.users = append(s.users, user)
+	return user, nil
+}
+
+func TestAuthenticate_SignUp(t *testing.T) {
+	tests := []struct {
+		name           string
+		ident          identity.Identity
+		signupApproval bool
+		expectedStatus int
+		expectedCode   apperrors.ErrorCode
+		expectedUserID int64
+	}{
+		{
+			name:           "second issuer presents a signed-in user's email",
+			ident:          identity.Identity{Subject: "okta|00u123", Email: "ada@example.com", EmailVerified: true, Provider: "okta"},
+			expectedStatus: http.StatusConflict,
+			expectedCode:   apperrors.CodeConflict,
+		},
+		{
+			name:           "claims an account made before the first sign-in",
+			ident:          identity.Identity{Subject: "auth0|grace", Email: "grace@example.com", EmailVerified: true},
+			expectedStatus: http.StatusOK,
+			expectedUserID: 2,
+		},
+		{
+			name:           "unverified email can't claim an account",
+			ident:          identity.Identity{Subject: "auth0|grace", Email: "grace@example.com"},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   apperrors.CodeEmailUnverified,
+		},
+		{
+			name:           "new email gets an account",
+			ident:          identity.Identity{Subject: "auth0|alan", Email: "alan@example.com"},
+			expectedStatus: http.StatusOK,
+			expectedUserID: 101,
+		},
+		{
+			name:           "new email turned away with signup approval",
+			ident:          identity.Identity{Subject: "auth0|alan", Email: "alan@example.com"},
+			signupApproval: true,
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   apperrors.CodeAccessRequired,
+		},
+		{
+			name:           "signup approval still lets accounts be claimed",
+			ident:          identity.Identity{Subject: "auth0|grace", Email: "grace@example.com", EmailVerified: true},
+			signupApproval: true,
+			expectedStatus: http.StatusOK,
+			expectedUserID: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &userStore{users: []*models.User{
+				{ID: 1, Auth0ID: "auth0|ada", Email: "ada@example.com", IsActive: true},
+				{ID: 2, Email: "grace@example.com", IsActive: true},
+			}}
+			m := NewAuthMiddleware(tokenIdentities{"token": &tt.ident}, store)
+			if tt.signupApproval {
+				m.WithSignupApproval()
+			}
+
+			var signedIn *models.User
+			handler := m.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				signedIn = GetUserFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			req.Header.Set("Authorization", "Bearer token")
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedCode != "" && !strings.Contains(rr.Body.String(), string(tt.expectedCode)) {
+				t.Errorf("expected code %s, got %s", tt.expectedCode, rr.Body.String())
+			}
+			if tt.expectedUserID != 0 && (signedIn == nil || signedIn.ID != tt.expectedUserID || signedIn.Auth0ID != tt.ident.Subject) {
+				t.Errorf("expected to sign in as user %d with %s, got %+v", tt.expectedUserID, tt.ident.Subject, signedIn)
+			}
+			if ada := store.users[0]; ada.Auth0ID != "auth0|ada" {
+				t.Errorf("existing account relinked to %s", ada.Auth0ID)
+			}
+		})
+	}
+}
+
+type signupInvitations map[string]int64
+
+func (s signupInvitations) GetByEmail(ctx context.Context, email string) (*models.Invitation, error) {
+	if orgID, ok := s[email]; ok {
+		return &models.Invitation{Email: email, OrgID: orgID}, nil
+	}
+	return nil, errors.New("invitation not found")
+}
+
+type signupDomains map[string]int64
+
+func (s signupDomains) GetByInvitationDomain(ctx context.Context, domain string) (*int64, error) {
+	if orgID, ok := s[domain]; ok {
+		return &orgID, nil
+	}
+	return nil, nil
+}
+
+func TestAuthenticate_SignUpMultiTenant(t *testing.T) {
+	tenant.Require(true)
+	defer tenant.Require(false)
+
+	tests := []struct {
+		name           string
+		ident          identity.Identity
+		noSignupOrgs   bool
+		expectedStatus int
+		expectedCode   apperrors.ErrorCode
+		expectedOrgID  int64
+	}{
+		{
+			name:           "invited email joins the inviting organization",
+			ident:          identity.Identity{Subject: "auth0|alan", Email: "alan@gmail.com", EmailVerified: true},
+			expectedStatus: http.StatusOK,
+			expectedOrgID:  3,
+		},
+		{
+			name:           "email at an organization's domain joins it",
+			ident:          identity.Identity{Subject: "auth0|katherine", Email: "katherine@Acme.com", EmailVerified: true},
+			expectedStatus: http.StatusOK,
+			expectedOrgID:  2,
+		},
+		{
+			name:           "uninvited email is turned away rather than joining the default organization",
+			ident:          identity.Identity{Subject: "auth0|mallory", Email: "mallory@gmail.com", EmailVerified: true},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   apperrors.CodeAccessRequired,
+		},
+		{
+			name:           "unverified email can't join by invitation",
+			ident:          identity.Identity{Subject: "auth0|alan", Email: "alan@gmail.com"},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   apperrors.CodeEmailUnverified,
+		},
+		{
+			name:           "nobody signs up without a way to find their organization",
+			ident:          identity.Identity{Subject: "auth0|katherine", Email: "katherine@acme.com", EmailVerified: true},
+			noSignupOrgs:   true,
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   apperrors.CodeAccessRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &userStore{}
+			m := NewAuthMiddleware(tokenIdentities{"token": &tt.ident}, store)
+			if !tt.noSignupOrgs {
+				m.WithSignupOrgs(signupInvitations{"alan@gmail.com": 3}, signupDomains{"acme.com": 2})
+			}
+
+			var signedIn *models.User
+			handler := m.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				signedIn = GetUserFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			req.Header.Set("Authorization", "Bearer token")
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedCode != "" && !strings.Contains(rr.Body.String(), string(tt.expectedCode)) {
+				t.Errorf("expected code %s, got %s", tt.expectedCode, rr.Body.String())
+			}
+			if tt.expectedOrgID != 0 && (signedIn == nil || signedIn.OrgID != tt.expectedOrgID) {
+				t.Errorf("expected to sign up in organization %d, got %+v", tt.expectedOrgID, signedIn)
+			}
+			if tt.expectedStatus != http.StatusOK && len(store.users) != 0 {
+				t.Errorf("expected no account to be created, got %+v", store.users[0])
+			}
+		})
+	}
+}
+
+// impersonationSessions looks up impersonation sessions by ID
+type impersonationSessions map[int64]*models.ImpersonationSession
+
+func (s impersonationSessions) GetByID(ctx context.Context, id int64) (*models.ImpersonationSession, error) {
+	return s[id], nil
+}
+
+func TestAuthenticate_Impersonation(t *testing.T) {
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		expectedStatus int
+		expectedUserID int64
+	}{
+		{
+			name:           "session acts as the user",
+			headers:        map[string]string{ImpersonationSessionHeader: "5"},
+			expectedStatus: http.StatusOK,
+			expectedUserID: 2,
+		},
+		{
+			name:           "ended session is refused",
+			headers:        map[string]string{ImpersonationSessionHeader: "6"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "user ID header without a session is ignored",
+			headers:        map[string]string{"X-Impersonate-User-Id": "2"},
+			expectedStatus: http.StatusOK,
+			expectedUserID: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &userStore{users: []*models.User{
+				{ID: 1, Auth0ID: "auth0|ada", Email: "ada@example.com", Role: models.RoleAdmin, IsActive: true},
+				{ID: 2, Auth0ID: "auth0|grace", Email: "grace@example.com", Role: models.RoleEmployee, IsActive: true},
+			}}
+			ended := time.Now()
+			sessions := impersonationSessions{
+				5: {ID: 5, AdminID: 1, UserID: 2, ExpiresAt: time.Now().Add(time.Hour)},
+				6: {ID: 6, AdminID: 1, UserID: 2, ExpiresAt: time.Now().Add(time.Hour), EndedAt: &ended},
+			}
+			m := NewAuthMiddleware(tokenIdentities{"token": {Subject: "auth0|ada", Email: "ada@example.com"}}, store).
+				WithImpersonation(sessions)
+
+			var effective *models.User
+			handler := m.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				effective = GetUserFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			req.Header.Set("Authorization", "Bearer token")
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectedUserID != 0 && (effective == nil || effective.ID != tt.expectedUserID) {
+				t.Errorf("expected to act as user %d, got %+v", tt.expectedUserID, effective)
+			}
+			impersonating := rr.Header().Get(ImpersonatedUserHeader) != ""
+			if want := tt.expectedUserID == 2; impersonating != want {
+				t.Errorf("impersonation headers set = %v, want %v", impersonating, want)
+			}
+		})
 	}
 }
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// clientIPContextKey holds the clientAddress ResolveClientIP found for a request
+const clientIPContextKey contextKey = "client_ip"
+
+// TrustedProxies is the networks of the load balancers and CDNs in front of the server. Only
+// requests they pass on can say who the client is with X-Forwarded-For, X-Real-IP, or a country
+// header; anyone else could set those to whatever they like.
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses IP addresses and CIDR ranges, like ParseIPAllowlist. An empty list
+// trusts no one.
+func ParseTrustedProxies(entries []string) (TrustedProxies, error) {
+	prefixes, err := ParseIPAllowlist(entries)
+	return TrustedProxies(prefixes), err
+}
+
+// trusts reports whether ip belongs to a trusted proxy
+func (p TrustedProxies) trusts(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientAddress is who a request came from
+type clientAddress struct {
+	ip string
+	// The request reached the server through a trusted proxy, so headers the proxy sets about the
+	// client, such as its country, can be believed
+	viaProxy bool
+}
+
+// resolve finds the client of a request. Requests from anyone but a trusted proxy come from their
+// peer address. Otherwise the client is the rightmost X-Forwarded-For hop that isn't a trusted
+// proxy, since each proxy appends the address it received the request from and only the hops the
+// proxies added can be believed. X-Real-IP is used when there's no X-Forwarded-For.
+func (p TrustedProxies) resolve(r *http.Request) clientAddress {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+	if !p.trusts(peer) {
+		return clientAddress{ip: peer}
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !p.trusts(hops[i]) {
+			return clientAddress{ip: hops[i], viaProxy: true}
+		}
+	}
+	if len(hops) > 0 {
+		// Every hop is a proxy, so the leftmost is the closest thing to a client
+		return clientAddress{ip: hops[0], viaProxy: true}
+	}
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		return clientAddress{ip: realIP, viaProxy: true}
+	}
+	return clientAddress{ip: peer, viaProxy: true}
+}
+
+// ResolveClientIP works out who each request came from, believing forwarding headers only from
+// the trusted proxies. It runs first so rate limiting, IP restrictions, and audit logs all see the
+// same client.
+func ResolveClientIP(proxies TrustedProxies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), clientIPContextKey, proxies.resolve(r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// clientAddressOf returns who a request came from. Outside ResolveClientIP no proxy is trusted.
+func clientAddressOf(r *http.Request) clientAddress {
+	if addr, ok := r.Context().Value(clientIPContextKey).(clientAddress); ok {
+		return addr
+	}
+	return TrustedProxies(nil).resolve(r)
+}
+
+// getIP returns the client IP of a request
+func getIP(r *http.Request) string {
+	return clientAddressOf(r).ip
+}
+
+// viaTrustedProxy reports whether a request reached the server through a trusted proxy
+func viaTrustedProxy(r *http.Request) bool {
+	return clientAddressOf(r).viaProxy
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "2001:db8::1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		peer         string
+		forwardedFor []string
+		realIP       string
+		wantIP       string
+		wantViaProxy bool
+	}{
+		{name: "direct", peer: "203.0.113.5:4321", wantIP: "203.0.113.5"},
+		{name: "untrusted peer's headers ignored", peer: "203.0.113.5:4321", forwardedFor: []string{"198.51.100.7"}, realIP: "198.51.100.8", wantIP: "203.0.113.5"},
+		{name: "through a proxy", peer: "10.0.0.1:4321", forwardedFor: []string{"203.0.113.5"}, wantIP: "203.0.113.5", wantViaProxy: true},
+		{name: "client-supplied hops skipped", peer: "10.0.0.1:4321", forwardedFor: []string{"198.51.100.7, 203.0.113.5"}, wantIP: "203.0.113.5", wantViaProxy: true},
+		{name: "proxy chain", peer: "10.0.0.1:4321", forwardedFor: []string{"198.51.100.7, 203.0.113.5, 10.0.0.2"}, wantIP: "203.0.113.5", wantViaProxy: true},
+		{name: "repeated headers", peer: "10.0.0.1:4321", forwardedFor: []string{"198.51.100.7", "203.0.113.5"}, wantIP: "203.0.113.5", wantViaProxy: true},
+		{name: "only proxies", peer: "10.0.0.1:4321", forwardedFor: []string{"10.0.0.3, 10.0.0.2"}, wantIP: "10.0.0.3", wantViaProxy: true},
+		{name: "X-Real-IP from a proxy", peer: "10.0.0.1:4321", realIP: "203.0.113.5", wantIP: "203.0.113.5", wantViaProxy: true},
+		{name: "proxy without headers", peer: "10.0.0.1:4321", wantIP: "10.0.0.1", wantViaProxy: true},
+		{name: "IPv6 proxy", peer: "[2001:db8::1]:4321", forwardedFor: []string{"203.0.113.5"}, wantIP: "203.0.113.5", wantViaProxy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIP string
+			var gotViaProxy bool
+			handler := ResolveClientIP(proxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotIP, gotViaProxy = ClientIP(r), viaTrustedProxy(r)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			req.RemoteAddr = tt.peer
+			for _, v := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if gotIP != tt.wantIP || gotViaProxy != tt.wantViaProxy {
+				t.Errorf("client = %s (via proxy %v), want %s (via proxy %v)", gotIP, gotViaProxy, tt.wantIP, tt.wantViaProxy)
+			}
+		})
+	}
+
+	// Without the middleware no proxy is trusted
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	req.RemoteAddr = "203.0.113.5:4321"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+	if ip := ClientIP(req); ip != "203.0.113.5" {
+		t.Errorf("ClientIP() without ResolveClientIP = %s, want the peer", ip)
+	}
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+)
+
+// CSRFHeader carries the CSRF token on requests a browser authenticates with cookies
+const CSRFHeader = "X-CSRF-Token"
+
+const (
+	// csrfTokenTTL is how long a CSRF token cookie lasts
+	csrfTokenTTL = 12 * time.Hour
+	// csrfOAuthStateTTL is how long a browser has to complete an OAuth flow it started
+	csrfOAuthStateTTL = 10 * time.Minute
+)
+
+// CSRFConfig configures CSRF protection
+type CSRFConfig struct {
+	// Secret signs tokens and OAuth state. A signed token only proves this server issued it: tokens
+	// aren't tied to a session or user, so a sibling subdomain able to set cookies could plant one
+	// it fetched itself.
+	Secret      []byte
+	CookieName  string
+	Secure      bool     // Only send the cookies over HTTPS
+	ExemptPaths []string // Path prefixes, such as signed webhooks, that don't need a token
+}
+
+// CSRF protects requests a browser authenticates with cookies from cross-site forgery, using
+// signed double-submit tokens: the token is set in a cookie and must be echoed in the
+// X-CSRF-Token header. Requests with a bearer token are exempt, since browsers never attach one
+// on their own.
+type CSRF struct {
+	secret      []byte
+	cookieName  string
+	secure      bool
+	exemptPaths []string
+}
+
+// NewCSRF creates CSRF protection
+func NewCSRF(cfg CSRFConfig) *CSRF {
+	return &CSRF{
+		secret:      cfg.Secret,
+		cookieName:  cfg.CookieName,
+		secure:      cfg.Secure,
+		exemptPaths: cfg.ExemptPaths,
+	}
+}
+
+// Protect rejects POST, PUT, PATCH, and DELETE requests without a bearer token unless their
+// X-CSRF-Token header matches their CSRF cookie
+func (c *CSRF) Protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+		if hasBearerToken(r) || c.isExempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cookie, err := r.Cookie(c.cookieName)
+		header := r.Header.Get(CSRFHeader)
+		if err != nil || header == "" || !c.validToken(cookie.Value) ||
+			!hmac.Equal([]byte(header), []byte(cookie.Value)) {
+			writeAppError(w, apperrors.NewForbiddenErrorWithCode(apperrors.CodeCSRFFailed, "Missing or invalid CSRF token"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IssueToken responds with the browser's CSRF token to send in the X-CSRF-Token header, setting
+// the cookie it is checked against unless the browser already has a valid one
+func (c *CSRF) IssueToken(w http.ResponseWriter, r *http.Request) {
+	token := ""
+	if cookie, err := r.Cookie(c.cookieName); err == nil && c.validToken(cookie.Value) {
+		token = cookie.Value
+	} else {
+		nonce := make([]byte, 32)
+		if _, err := rand.Read(nonce); err != nil {
+			writeAppError(w, apperrors.NewInternalError("Failed to generate CSRF token", err))
+			return
+		}
+		value := base64.RawURLEncoding.EncodeToString(nonce)
+		token = value + "." + c.sign("token", value)
+		c.setCookie(w, c.cookieName, token, csrfTokenTTL)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(map[string]string{"csrf_token": token})
+}
+
+// BindState ties an OAuth flow to the browser that started it, so VerifyState can reject a
+// callback an attacker forges with their own code and state
+func (c *CSRF) BindState(w http.ResponseWriter, state string) {
+	c.setCookie(w, c.cookieName+"_oauth", c.sign("oauth", state), csrfOAuthStateTTL)
+}
+
+// VerifyState reports whether an OAuth callback comes from the browser that started the flow.
+// A bearer token doesn't exempt a callback: anyone's browser can be sent to a relaying frontend.
+func (c *CSRF) VerifyState(r *http.Request, state string) bool {
+	cookie, err := r.Cookie(c.cookieName + "_oauth")
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(cookie.Value), []byte(c.sign("oauth", state)))
+}
+
+// validToken reports whether token is one IssueToken signed
+func (c *CSRF) validToken(token string) bool {
+	value, signature, ok := strings.Cut(token, ".")
+	return ok && value != "" && hmac.Equal([]byte(signature), []byte(c.sign("token", value)))
+}
+
+// sign returns the signature of value for one purpose, so a signature for one can't be used
+// as another
+func (c *CSRF) sign(purpose, value string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(purpose + ":" + value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (c *CSRF) setCookie(w http.ResponseWriter, name, value string, ttl time.Duration) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   c.secure,
+		// Lax so the OAuth state cookie survives the provider redirecting back
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (c *CSRF) isExempt(path string) bool {
+	for _, prefix := range c.exemptPaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBearerToken reports whether the request authenticates with a bearer token rather than
+// cookies
+func hasBearerToken(r *http.Request) bool {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	return ok && strings.EqualFold(scheme, "bearer") && token != ""
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+)
+
+func newTestCSRF() *CSRF {
+	return NewCSRF(CSRFConfig{
+		Secret:      []byte("test-secret"),
+		CookieName:  "api_csrf",
+		ExemptPaths: []string{"/api/jira/webhook"},
+	})
+}
+
+// issueCSRFToken returns a token and the cookie IssueToken set for it
+func issueCSRFToken(t *testing.T, csrf *CSRF) (string, *http.Cookie) {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	csrf.IssueToken(rr, httptest.NewRequest(http.MethodGet, "/api/csrf-token", nil))
+
+	var body struct {
+		Token string `json:"csrf_token"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || body.Token == "" {
+		t.Fatalf("expected a token, got %q", rr.Body.String())
+	}
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value != body.Token || !cookies[0].HttpOnly {
+		t.Fatalf("expected an HTTP-only cookie holding the token, got %+v", cookies)
+	}
+	return body.Token, cookies[0]
+}
+
+func TestCSRF_Protect(t *testing.T) {
+	csrf := newTestCSRF()
+	token, cookie := issueCSRFToken(t, csrf)
+	otherToken, _ := issueCSRFToken(t, csrf)
+	forged := &http.Cookie{Name: "api_csrf", Value: "planted.signature"}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		cookie         *http.Cookie
+		header         string
+		bearer         bool
+		expectedStatus int
+	}{
+		{"matching token", http.MethodPost, "/api/things", cookie, token, false, http.StatusOK},
+		{"missing header", http.MethodPost, "/api/things", cookie, "", false, http.StatusForbidden},
+		{"missing cookie", http.MethodPost, "/api/things", nil, token, false, http.StatusForbidden},
+		{"another browser's token", http.MethodDelete, "/api/things", cookie, otherToken, false, http.StatusForbidden},
+		{"unsigned cookie", http.MethodPut, "/api/things", forged, forged.Value, false, http.StatusForbidden},
+		{"bearer token exempt", http.MethodPost, "/api/things", nil, "", true, http.StatusOK},
+		{"exempt path", http.MethodPost, "/api/jira/webhook", nil, "", false, http.StatusOK},
+		{"safe method", http.MethodGet, "/api/things", nil, "", false, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := csrf.Protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			if tt.header != "" {
+				req.Header.Set(CSRFHeader, tt.header)
+			}
+			if tt.bearer {
+				req.Header.Set("Authorization", "Bearer token")
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+			if tt.expectedStatus == http.StatusForbidden {
+				var body map[string]interface{}
+				_ = json.Unmarshal(rr.Body.Bytes(), &body)
+				if body["code"] != string(apperrors.CodeCSRFFailed) {
+					t.Errorf("code = %v, want %s", body["code"], apperrors.CodeCSRFFailed)
+				}
+			}
+		})
+	}
+}
+
+func TestCSRF_IssueTokenReusesValidCookie(t *testing.T) {
+	csrf := newTestCSRF()
+	token, cookie := issueCSRFToken(t, csrf)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/csrf-token", nil)
+	req.AddCookie(cookie)
+	rr := httptest.NewRecorder()
+	csrf.IssueToken(rr, req)
+
+	var body struct {
+		Token string `json:"csrf_token"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &body)
+	if body.Token != token {
+		t.Errorf("expected the existing token to be reused")
+	}
+	if len(rr.Result().Cookies()) != 0 {
+		t.Errorf("expected no new cookie")
+	}
+}
+
+func TestCSRF_VerifyState(t *testing.T) {
+	csrf := newTestCSRF()
+	rr := httptest.NewRecorder()
+	csrf.BindState(rr, "state-1")
+	bound := rr.Result().Cookies()[0]
+
+	callback := func(state string, cookie *http.Cookie, bearer bool) bool {
+		req := httptest.NewRequest(http.MethodGet, "/api/github/oauth/callback?state="+state, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		if bearer {
+			req.Header.Set("Authorization", "Bearer token")
+		}
+		return csrf.VerifyState(req, state)
+	}
+
+	if !callback("state-1", bound, false) {
+		t.Error("expected the browser that started the flow to be accepted")
+	}
+	if callback("state-2", bound, false) {
+		t.Error("expected another flow's state to be rejected")
+	}
+	if callback("state-1", nil, false) {
+		t.Error("expected a browser without the cookie to be rejected")
+	}
+	if callback("state-1", nil, true) {
+		t.Error("expected a bearer token not to stand in for the cookie")
+	}
+}
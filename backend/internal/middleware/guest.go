@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// DirectoryAuthorizer decides whether a user may see data beyond their own calendar
+type DirectoryAuthorizer interface {
+	CanViewDirectory(user *models.User) error
+}
+
+// routePattern matches requests by method and path; patterns use path.Match syntax
+type routePattern struct {
+	method  string
+	pattern string
+}
+
+// guestRoutes lists everything a guest can reach: their profile, their calendar, the meetings
+// and tasks on it (the handlers check they are invited or assigned), and their notifications
+var guestRoutes = []routePattern{
+	{http.MethodGet, "/api/me"},
+	{http.MethodGet, "/api/calendar/events"},
+	{http.MethodGet, "/api/calendar/stream"},
+	{http.MethodGet, "/api/calendar/meetings/*"},
+	{http.MethodGet, "/api/calendar/meetings/*/agenda"},
+	{http.MethodGet, "/api/calendar/meetings/*/notes"},
+	{http.MethodGet, "/api/calendar/meetings/*/attachments"},
+	{http.MethodGet, "/api/calendar/meetings/*/attachments/*"},
+	{http.MethodPost, "/api/calendar/meetings/*/respond"},
+	{http.MethodGet, "/api/calendar/tasks"},
+	{http.MethodGet, "/api/calendar/tasks/*"},
+	{http.MethodGet, "/api/calendar/tasks/*/comments"},
+	{http.MethodGet, "/api/calendar/tasks/*/activity"},
+	{http.MethodGet, "/api/notifications"},
+	{http.MethodGet, "/api/notifications/unread-count"},
+	{http.MethodPost, "/api/notifications/read-all"},
+	{http.MethodPost, "/api/notifications/*/read"},
+}
+
+// RestrictGuests limits users the authorizer keeps out of the directory to guestRoutes.
+// Must run after Authenticate so the user is in the context.
+func RestrictGuests(authz DirectoryAuthorizer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := GetUserFromContext(r.Context())
+			if user == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err := authz.CanViewDirectory(user)
+			if err == nil || isGuestRoute(r.Method, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			writeAppError(w, err)
+		})
+	}
+}
+
+// isGuestRoute reports whether the request matches one of guestRoutes
+func isGuestRoute(method, urlPath string) bool {
+	return matchesRoute(guestRoutes, method, urlPath)
+}
+
+// matchesRoute reports whether the request matches one of routes
+func matchesRoute(routes []routePattern, method, urlPath string) bool {
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	for _, route := range routes {
+		if route.method != method {
+			continue
+		}
+		if ok, _ := path.Match(route.pattern, urlPath); ok {
+			return true
+		}
+	}
+	return false
+}
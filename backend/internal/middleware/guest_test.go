@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// guestsRestricted keeps guests out of the directory, mirroring the authorization service
+type guestsRestricted struct{}
+
+func (guestsRestricted) CanViewDirectory(user *models.User) error {
+	if user.IsGuest() {
+		return apperrors.NewForbiddenErrorWithCode(apperrors.CodeGuestRestricted, "Guests can only access meetings they are invited to")
+	}
+	return nil
+}
+
+func TestRestrictGuests(t *testing.T) {
+	guest := &models.User{ID: 1, Role: models.RoleGuest}
+	employee := &models.User{ID: 2, Role: models.RoleEmployee}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		user       *models.User
+		wantStatus int
+	}{
+		{name: "guest can load their profile", method: http.MethodGet, path: "/api/me", user: guest, wantStatus: http.StatusOK},
+		{name: "guest can load their calendar", method: http.MethodGet, path: "/api/calendar/events", user: guest, wantStatus: http.StatusOK},
+		{name: "guest can view a meeting", method: http.MethodGet, path: "/api/calendar/meetings/7", user: guest, wantStatus: http.StatusOK},
+		{name: "guest can download a meeting attachment", method: http.MethodGet, path: "/api/calendar/meetings/7/attachments/3", user: guest, wantStatus: http.StatusOK},
+		{name: "guest can respond to a meeting", method: http.MethodPost, path: "/api/calendar/meetings/7/respond", user: guest, wantStatus: http.StatusOK},
+		{name: "guest can list their notifications", method: http.MethodGet, path: "/api/notifications", user: guest, wantStatus: http.StatusOK},
+		{name: "guest can mark a notification read", method: http.MethodPost, path: "/api/notifications/4/read", user: guest, wantStatus: http.StatusOK},
+		{name: "guest cannot edit a meeting", method: http.MethodPut, path: "/api/calendar/meetings/7", user: guest, wantStatus: http.StatusForbidden},
+		{name: "guest cannot list employees", method: http.MethodGet, path: "/api/employees", user: guest, wantStatus: http.StatusForbidden},
+		{name: "guest cannot view the org chart", method: http.MethodGet, path: "/api/orgchart/tree", user: guest, wantStatus: http.StatusForbidden},
+		{name: "guest cannot query graphql", method: http.MethodPost, path: "/graphql", user: guest, wantStatus: http.StatusForbidden},
+		{name: "employee is unrestricted", method: http.MethodGet, path: "/api/employees", user: employee, wantStatus: http.StatusOK},
+		{name: "unauthenticated passes through", method: http.MethodGet, path: "/api/employees", user: nil, wantStatus: http.StatusOK},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RestrictGuests(guestsRestricted{})(next)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), UserContextKey, tt.user))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if rr.Code == http.StatusForbidden {
+				var body map[string]interface{}
+				if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if body["code"] != string(apperrors.CodeGuestRestricted) {
+					t.Errorf("code = %v, want %s", body["code"], apperrors.CodeGuestRestricted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/cache"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// orgIPAllowlistTTL is how long an organization's allowlist is cached, and so how long a change
+// to it takes to apply
+const orgIPAllowlistTTL = time.Minute
+
+// IPAllowlist is the networks requests may come from. An empty allowlist allows every network.
+type IPAllowlist []netip.Prefix
+
+// ParseIPAllowlist parses IP addresses, such as 203.0.113.7, and CIDR ranges, such as
+// 203.0.113.0/24
+func ParseIPAllowlist(entries []string) (IPAllowlist, error) {
+	allowlist := make(IPAllowlist, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid IP address %q", entry)
+			}
+			allowlist = append(allowlist, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR range %q", entry)
+		}
+		allowlist = append(allowlist, prefix.Masked())
+	}
+	return allowlist, nil
+}
+
+// Allows reports whether ip is within the allowlist. Unparseable IPs are only allowed by an
+// empty allowlist.
+func (l IPAllowlist) Allows(ip string) bool {
+	if len(l) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range l {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// IPRestrictions limits the networks and countries every request may come from
+type IPRestrictions struct {
+	Allowlist IPAllowlist
+	// ISO 3166 country codes requests may come from; any country when empty
+	AllowedCountries []string
+	// Header a CDN or load balancer in front of the server sets to the client's country code
+	CountryHeader string
+	// Paths, such as health checks, served from anywhere
+	SkipPaths []string
+}
+
+// allows reports whether a request may be served, and if not, why
+func (rs IPRestrictions) allows(r *http.Request) (bool, string) {
+	if slices.Contains(rs.SkipPaths, r.URL.Path) {
+		return true, ""
+	}
+	if !rs.Allowlist.Allows(ClientIP(r)) {
+		return false, "ip"
+	}
+	if len(rs.AllowedCountries) > 0 {
+		// Requests without a country fail closed, since the header may be missing because they
+		// bypassed the proxy that sets it. Only a trusted proxy's country is believed.
+		var country string
+		if viaTrustedProxy(r) {
+			country = strings.ToUpper(strings.TrimSpace(r.Header.Get(rs.CountryHeader)))
+		}
+		if !slices.Contains(rs.AllowedCountries, country) {
+			return false, "country"
+		}
+	}
+	return true, ""
+}
+
+// RestrictIPs rejects requests from outside the allowed networks and countries with a 403 and
+// records each in the audit log. It runs before Authenticate, so it also guards the public
+// endpoints.
+func RestrictIPs(restrictions IPRestrictions, recorder AuditRecorder) func(http.Handler) http.Handler {
+	for i, country := range restrictions.AllowedCountries {
+		restrictions.AllowedCountries[i] = strings.ToUpper(country)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ok, reason := restrictions.allows(r); !ok {
+				blockRequest(w, r, recorder, reason)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// OrgIPAllowlistStore looks up the networks each organization's users may connect from
+type OrgIPAllowlistStore interface {
+	GetIPAllowlist(ctx context.Context, orgID int64) ([]string, error)
+}
+
+// orgIPAllowlists enforces organizations' allowlists, caching them so that doesn't cost a query
+// per request
+type orgIPAllowlists struct {
+	store    OrgIPAllowlistStore
+	recorder AuditRecorder
+	cache    *cache.Cache
+}
+
+// allows reports whether users of the organization may connect from ip
+func (a *orgIPAllowlists) allows(ctx context.Context, orgID int64, ip string) (bool, error) {
+	cached, err := a.cache.GetOrSet(strconv.FormatInt(orgID, 10), func() (interface{}, error) {
+		entries, err := a.store.GetIPAllowlist(ctx, orgID)
+		if err != nil {
+			return nil, err
+		}
+		return ParseIPAllowlist(entries)
+	})
+	if err != nil {
+		return false, err
+	}
+	return cached.(IPAllowlist).Allows(ip), nil
+}
+
+// check rejects the request unless the user's organization allows the network it comes from,
+// reporting whether it may continue
+func (a *orgIPAllowlists) check(w http.ResponseWriter, r *http.Request, user *models.User) bool {
+	allowed, err := a.allows(r.Context(), user.OrgID, ClientIP(r))
+	if err != nil {
+		// Fail closed: the allowlist exists to keep everyone else out
+		logger.Default().WithComponent("auth").LogError(r.Context(), "Failed to check IP allowlist", err, "org_id", user.OrgID)
+		writeAppError(w, apperrors.NewInternalError("Failed to check IP allowlist", err))
+		return false
+	}
+	if !allowed {
+		ctx := context.WithValue(r.Context(), RealUserContextKey, user)
+		blockRequest(w, r.WithContext(ctx), a.recorder, "org_ip")
+		return false
+	}
+	return true
+}
+
+// blockRequest responds to a request from a disallowed network or country, and records the
+// attempt with the IP it came from as the entity
+func blockRequest(w http.ResponseWriter, r *http.Request, recorder AuditRecorder, reason string) {
+	ip := ClientIP(r)
+	logger.Default().WithComponent("ip-restrictions").Warn("Blocked request", "ip", ip, "reason", reason, "path", r.URL.Path)
+
+	if recorder != nil {
+		event := NewAuditEvent(r.Context(), "blocked "+r.Method+" "+r.URL.Path)
+		event.Status = http.StatusForbidden
+		event.IPAddress = ip
+		event.EntityType = "ip_address"
+		event.EntityID = &ip
+		if err := recorder.Create(context.WithoutCancel(r.Context()), event); err != nil {
+			logger.Default().WithComponent("ip-restrictions").LogError(r.Context(), "Failed to record blocked request", err, "ip", ip)
+		}
+	}
+
+	writeAppError(w, apperrors.NewForbiddenErrorWithCode(apperrors.CodeIPNotAllowed, "Access from your network or location is not allowed"))
+}
@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/cache"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestParseIPAllowlist(t *testing.T) {
+	allowlist, err := ParseIPAllowlist([]string{"203.0.113.0/24", " 198.51.100.7 ", "2001:db8::/32"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"203.0.113.42", true},
+		{"198.51.100.7", true},
+		{"198.51.100.8", false},
+		{"::ffff:203.0.113.9", true},
+		{"2001:db8::1", true},
+		{"2001:db9::1", false},
+		{"not-an-ip", false},
+	}
+	for _, tt := range tests {
+		if got := allowlist.Allows(tt.ip); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+
+	if !IPAllowlist(nil).Allows("not-an-ip") {
+		t.Error("expected an empty allowlist to allow everything")
+	}
+	for _, entry := range []string{"203.0.113.0/33", "example.com", ""} {
+		if _, err := ParseIPAllowlist([]string{entry}); err == nil {
+			t.Errorf("expected %q to be rejected", entry)
+		}
+	}
+}
+
+func TestRestrictIPs(t *testing.T) {
+	allowlist, _ := ParseIPAllowlist([]string{"203.0.113.0/24"})
+	restrictions := IPRestrictions{
+		Allowlist:        allowlist,
+		AllowedCountries: []string{"us", "CA"},
+		CountryHeader:    "CF-IPCountry",
+		SkipPaths:        []string{"/health"},
+	}
+
+	proxies, _ := ParseTrustedProxies([]string{"10.0.0.0/8"})
+
+	tests := []struct {
+		name           string
+		path           string
+		peer           string
+		forwardedFor   string
+		country        string
+		expectedStatus int
+		expectedIP     string
+	}{
+		{"allowed network and country", "/api/me", "10.0.0.1", "203.0.113.5", "US", http.StatusOK, ""},
+		{"country header is case-insensitive", "/api/me", "10.0.0.1", "203.0.113.5", "ca", http.StatusOK, ""},
+		{"network not allowed", "/api/me", "10.0.0.1", "198.51.100.5", "US", http.StatusForbidden, "198.51.100.5"},
+		{"country not allowed", "/api/me", "10.0.0.1", "203.0.113.5", "FR", http.StatusForbidden, "203.0.113.5"},
+		{"missing country", "/api/me", "10.0.0.1", "203.0.113.5", "", http.StatusForbidden, "203.0.113.5"},
+		{"health checks skipped", "/health", "10.0.0.1", "198.51.100.5", "", http.StatusOK, ""},
+		{"spoofed client before the proxy's hop", "/api/me", "10.0.0.1", "203.0.113.5, 198.51.100.5", "US", http.StatusForbidden, "198.51.100.5"},
+		{"spoofed headers from an untrusted peer", "/api/me", "198.51.100.5", "203.0.113.5", "US", http.StatusForbidden, "198.51.100.5"},
+		{"allowed peer without a proxy", "/api/me", "203.0.113.5", "", "", http.StatusForbidden, "203.0.113.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &auditLog{}
+			handler := ResolveClientIP(proxies)(RestrictIPs(restrictions, log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = tt.peer + ":4321"
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.country != "" {
+				req.Header.Set("CF-IPCountry", tt.country)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+			if tt.expectedStatus == http.StatusOK {
+				if len(log.events) != 0 {
+					t.Errorf("expected no audit events, got %d", len(log.events))
+				}
+				return
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("expected JSON error body: %v", err)
+			}
+			if body["code"] != string(apperrors.CodeIPNotAllowed) {
+				t.Errorf("code = %v, want %s", body["code"], apperrors.CodeIPNotAllowed)
+			}
+			if len(log.events) != 1 {
+				t.Fatalf("expected 1 audit event, got %d", len(log.events))
+			}
+			event := log.events[0]
+			if event.Action != "blocked GET /api/me" || event.Status != http.StatusForbidden || event.IPAddress != tt.expectedIP {
+				t.Errorf("unexpected audit event: %+v", event)
+			}
+		})
+	}
+}
+
+// orgAllowlists serves organizations' allowlists from memory, counting lookups
+type orgAllowlists struct {
+	entries map[int64][]string
+	lookups int
+	err     error
+}
+
+func (s *orgAllowlists) GetIPAllowlist(ctx context.Context, orgID int64) ([]string, error) {
+	s.lookups++
+	return s.entries[orgID], s.err
+}
+
+func TestOrgIPAllowlists_Check(t *testing.T) {
+	store := &orgAllowlists{entries: map[int64][]string{1: {}, 2: {"203.0.113.0/24"}}}
+	log := &auditLog{}
+	allowlists := &orgIPAllowlists{store: store, recorder: log, cache: cache.New(time.Minute, 0)}
+
+	check := func(user *models.User, ip string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		req.RemoteAddr = ip + ":4321"
+		rr := httptest.NewRecorder()
+		if allowlists.check(rr, req, user) {
+			return http.StatusOK
+		}
+		return rr.Code
+	}
+
+	defaultOrgUser := &models.User{ID: 1, Email: "a@example.com", OrgID: 1}
+	restrictedUser := &models.User{ID: 2, Email: "b@example.com", OrgID: 2}
+
+	if status := check(defaultOrgUser, "198.51.100.5"); status != http.StatusOK {
+		t.Errorf("expected an organization without an allowlist to allow any network, got %d", status)
+	}
+	if status := check(restrictedUser, "203.0.113.5"); status != http.StatusOK {
+		t.Errorf("expected allowed network to pass, got %d", status)
+	}
+	if status := check(restrictedUser, "198.51.100.5"); status != http.StatusForbidden {
+		t.Errorf("expected network outside the allowlist to be forbidden, got %d", status)
+	}
+
+	// Without a trusted proxy in front, forwarding headers are the client's word for it
+	spoofed := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	spoofed.RemoteAddr = "198.51.100.5:4321"
+	spoofed.Header.Set("X-Forwarded-For", "203.0.113.5")
+	spoofed.Header.Set("X-Real-IP", "203.0.113.5")
+	rr := httptest.NewRecorder()
+	if allowlists.check(rr, spoofed, restrictedUser) || rr.Code != http.StatusForbidden {
+		t.Errorf("expected a spoofed X-Forwarded-For from an untrusted peer to be forbidden, got %d", rr.Code)
+	}
+	if store.lookups != 2 {
+		t.Errorf("expected one lookup per organization, got %d", store.lookups)
+	}
+	if len(log.events) != 2 || log.events[0].ActorID == nil || *log.events[0].ActorID != restrictedUser.ID {
+		t.Errorf("expected the blocked attempt to be recorded with the user as actor, got %+v", log.events)
+	}
+
+	failing := &orgIPAllowlists{store: &orgAllowlists{err: errors.New("db down")}, cache: cache.New(time.Minute, 0)}
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	rr = httptest.NewRecorder()
+	if failing.check(rr, req, restrictedUser) || rr.Code != http.StatusInternalServerError {
+		t.Errorf("expected lookup failures to fail closed, got %d", rr.Code)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
 	"github.com/smith-dallin/manager-dashboard/internal/logger"
 )
 
@@ -102,7 +103,7 @@ func RecoveryLogger(log *logger.Logger) func(http.Handler) http.Handler {
 			defer func() {
 				if recovered := recover(); recovered != nil {
 					log.LogPanic(r.Context(), recovered)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					writeAppError(w, apperrors.NewInternalError("Internal Server Error", nil))
 				}
 			}()
 			next.ServeHTTP(w, r)
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// WriteAuthorizer decides whether a user may make changes
+type WriteAuthorizer interface {
+	CanModify(user *models.User) error
+}
+
+// personalRoutes only change the caller's own state, so read-only users may still call them
+var personalRoutes = []routePattern{
+	{http.MethodPost, "/api/notifications/read-all"},
+	{http.MethodPost, "/api/notifications/*/read"},
+}
+
+// RequireWriteAccess rejects state-changing requests from users the authorizer marks read-only.
+// Must run after Authenticate so the user is in the context.
+func RequireWriteAccess(authz WriteAuthorizer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			if matchesRoute(personalRoutes, r.Method, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user := GetUserFromContext(r.Context())
+			if user == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := authz.CanModify(user); err != nil {
+				writeAppError(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeAppError writes an error response in the same JSON shape the handlers use
+func writeAppError(w http.ResponseWriter, err error) {
+	apperrors.Write(w, err)
+}
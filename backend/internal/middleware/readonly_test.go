@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// viewerReadOnly treats viewers as read-only, mirroring the authorization service
+type viewerReadOnly struct{}
+
+func (viewerReadOnly) CanModify(user *models.User) error {
+	if user.IsViewer() {
+		return apperrors.NewForbiddenErrorWithCode(apperrors.CodeReadOnly, "Viewers have read-only access")
+	}
+	return nil
+}
+
+func TestRequireWriteAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		user       *models.User
+		wantStatus int
+	}{
+		{name: "viewer can read", method: http.MethodGet, user: &models.User{ID: 1, Role: models.RoleViewer}, wantStatus: http.StatusOK},
+		{name: "viewer cannot create", method: http.MethodPost, user: &models.User{ID: 1, Role: models.RoleViewer}, wantStatus: http.StatusForbidden},
+		{name: "viewer cannot update", method: http.MethodPut, user: &models.User{ID: 1, Role: models.RoleViewer}, wantStatus: http.StatusForbidden},
+		{name: "viewer cannot delete", method: http.MethodDelete, user: &models.User{ID: 1, Role: models.RoleViewer}, wantStatus: http.StatusForbidden},
+		{name: "viewer can mark their notifications read", method: http.MethodPost, path: "/api/notifications/read-all", user: &models.User{ID: 1, Role: models.RoleViewer}, wantStatus: http.StatusOK},
+		{name: "employee can write", method: http.MethodPost, user: &models.User{ID: 1, Role: models.RoleEmployee}, wantStatus: http.StatusOK},
+		{name: "unauthenticated passes through", method: http.MethodPost, user: nil, wantStatus: http.StatusOK},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RequireWriteAccess(viewerReadOnly{})(next)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path == "" {
+				path = "/api/users"
+			}
+			req := httptest.NewRequest(tt.method, path, nil)
+			if tt.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), UserContextKey, tt.user))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body = %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/cache"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
+)
+
+// responseRefreshTimeout bounds a background revalidation of a stale response
+const responseRefreshTimeout = 30 * time.Second
+
+// CachePolicy declares how responses from one read endpoint are cached
+type CachePolicy struct {
+	Name         string        // Namespace for the endpoint's cached responses
+	TTL          time.Duration // How long a response is served as fresh
+	StaleTTL     time.Duration // How long after TTL a response is still served while it is refreshed in the background
+	InvalidateOn []events.Type // Domain events that discard every cached response for the endpoint
+}
+
+// cachedResponse is a successful response held in the cache
+type cachedResponse struct {
+	header     http.Header
+	body       []byte
+	freshUntil time.Time
+}
+
+// ResponseCache caches successful GET responses per user and per URL.
+// Stale responses are served immediately while a single background request refreshes them,
+// and entries are dropped when a domain event the policy listens for is published.
+type ResponseCache struct {
+	store   *cache.Cache
+	workers *lifecycle.Group // Runs background refreshes, so shutdown waits for them
+
+	mu          sync.Mutex
+	generations map[string]uint64        // Per policy; bumped on invalidation so in-flight results for older data are discarded
+	refreshing  map[string]bool          // Keys with a background refresh in progress
+	listeners   map[events.Type][]string // Policy names to invalidate per event type
+}
+
+// NewResponseCache creates a response cache backed by the given store, refreshing stale responses
+// as workers
+func NewResponseCache(store *cache.Cache, workers *lifecycle.Group) *ResponseCache {
+	return &ResponseCache{
+		store:       store,
+		workers:     workers,
+		generations: make(map[string]uint64),
+		refreshing:  make(map[string]bool),
+		listeners:   make(map[events.Type][]string),
+	}
+}
+
+// Handler returns middleware caching the wrapped endpoint according to policy.
+// Requests without an authenticated user, non-GET requests, and non-200 responses are never cached.
+// Clients can send "Cache-Control: no-cache" to force a fresh response, and get 304 Not Modified when
+// If-None-Match names the ETag the handler set on the response.
+func (c *ResponseCache) Handler(policy CachePolicy) func(http.Handler) http.Handler {
+	c.mu.Lock()
+	for _, eventType := range policy.InvalidateOn {
+		c.listeners[eventType] = append(c.listeners[eventType], policy.Name)
+	}
+	if _, ok := c.generations[policy.Name]; !ok {
+		c.generations[policy.Name] = 0 // So that InvalidateAll knows of the policy
+	}
+	c.mu.Unlock()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := GetUserFromContext(r.Context())
+			if r.Method != http.MethodGet || user == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Handlers tailor responses to the caller, so entries never cross users or roles
+			key := fmt.Sprintf("%s:%d:%s:%s", policy.Name, user.ID, user.Role, r.URL.RequestURI())
+
+			bypass := strings.Contains(r.Header.Get("Cache-Control"), "no-cache")
+			if value, found := c.store.Get(key); found && !bypass {
+				entry := value.(*cachedResponse)
+				if time.Now().Before(entry.freshUntil) {
+					writeCachedResponse(w, r, entry, "HIT")
+					return
+				}
+				writeCachedResponse(w, r, entry, "STALE")
+				c.refresh(policy, key, next, r)
+				return
+			}
+
+			generation := c.generation(policy.Name)
+			rec := newBufferedResponse()
+			next.ServeHTTP(rec, unconditional(r.Context(), r))
+			c.save(policy, key, generation, rec)
+
+			rec.header.Set("X-Cache", "MISS")
+			if rec.status == http.StatusOK && notModified(r, rec.header) {
+				rec.status = http.StatusNotModified
+				rec.body.Reset()
+			}
+			rec.writeTo(w)
+		})
+	}
+}
+
+// Invalidate discards every cached response for the named policy
+func (c *ResponseCache) Invalidate(name string) {
+	c.mu.Lock()
+	c.generations[name]++
+	c.mu.Unlock()
+	c.store.DeletePrefix(name + ":")
+}
+
+// InvalidateAll discards every cached response
+func (c *ResponseCache) InvalidateAll() {
+	c.mu.Lock()
+	for name := range c.generations {
+		c.generations[name]++
+	}
+	c.mu.Unlock()
+	c.store.Clear()
+}
+
+// Listen invalidates policies as their events are published, until the workers are stopped. If
+// the broker drops events because they arrive faster than they're handled, every cached response
+// is discarded, since any of them may have been invalidated by the events missed.
+func (c *ResponseCache) Listen(broker *events.Broker) {
+	if broker == nil {
+		return
+	}
+	sub, dropped, unsubscribe := broker.SubscribeWithDrops()
+	c.workers.Go("response-cache-invalidation", func(ctx context.Context) {
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-dropped:
+				c.InvalidateAll()
+			case event, ok := <-sub:
+				if !ok {
+					return
+				}
+				c.mu.Lock()
+				names := c.listeners[event.Type]
+				c.mu.Unlock()
+				for _, name := range names {
+					c.Invalidate(name)
+				}
+			}
+		}
+	})
+}
+
+// refresh re-runs the request as a worker, at most once per key at a time
+func (c *ResponseCache) refresh(policy CachePolicy, key string, next http.Handler, r *http.Request) {
+	c.mu.Lock()
+	if c.refreshing[key] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[key] = true
+	generation := c.generations[policy.Name]
+	c.mu.Unlock()
+
+	// Keep the request's values (such as the user) but not its cancellation, which ends with the
+	// response; stopping the workers cancels it instead
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), responseRefreshTimeout)
+	req := unconditional(ctx, r)
+
+	c.workers.Go("response-cache-refresh", func(workerCtx context.Context) {
+		defer cancel()
+		defer context.AfterFunc(workerCtx, cancel)()
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, key)
+			c.mu.Unlock()
+		}()
+		if workerCtx.Err() != nil {
+			return
+		}
+
+		rec := newBufferedResponse()
+		next.ServeHTTP(rec, req)
+		c.save(policy, key, generation, rec)
+	})
+}
+
+// save caches a successful response unless the policy was invalidated while it was computed
+func (c *ResponseCache) save(policy CachePolicy, key string, generation uint64, rec *bufferedResponse) {
+	if rec.status != http.StatusOK {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generations[policy.Name] != generation {
+		return
+	}
+	entry := &cachedResponse{
+		header:     rec.header.Clone(),
+		body:       rec.body.Bytes(),
+		freshUntil: time.Now().Add(policy.TTL),
+	}
+	c.store.SetWithTTL(key, entry, policy.TTL+policy.StaleTTL)
+}
+
+// generation returns the current invalidation generation of a policy
+func (c *ResponseCache) generation(name string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generations[name]
+}
+
+// writeCachedResponse replays a cached response, or just its headers if the client already has it
+func writeCachedResponse(w http.ResponseWriter, r *http.Request, entry *cachedResponse, status string) {
+	for name, values := range entry.header {
+		w.Header()[name] = values
+	}
+	w.Header().Set("X-Cache", status)
+	if notModified(r, entry.header) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(entry.body)
+}
+
+// unconditional copies r without If-None-Match, so the handler always produces a full response
+// that can be cached
+func unconditional(ctx context.Context, r *http.Request) *http.Request {
+	req := r.Clone(ctx)
+	req.Header.Del("If-None-Match")
+	return req
+}
+
+// notModified reports whether the client already holds the response version named by header's ETag
+func notModified(r *http.Request, header http.Header) bool {
+	etag := header.Get("ETag")
+	return etag != "" && ETagMatches(r.Header.Get("If-None-Match"), etag)
+}
+
+// ETagMatches reports whether an If-None-Match header value names etag, using weak comparison
+func ETagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedResponse captures a handler's response so it can be cached before it is sent
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+// writeTo sends the captured response to the client
+func (b *bufferedResponse) writeTo(w http.ResponseWriter) {
+	for name, values := range b.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(b.status)
+	_, _ = w.Write(b.body.Bytes())
+}
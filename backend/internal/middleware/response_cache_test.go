@@ -0,0 +1,307 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/cache"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// countingHandler responds with how many times it has been called
+type countingHandler struct {
+	calls  atomic.Int32
+	status int
+	served chan struct{}
+}
+
+func newCountingHandler(status int) *countingHandler {
+	return &countingHandler{status: status, served: make(chan struct{}, 10)}
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := h.calls.Add(1)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(h.status)
+	fmt.Fprintf(w, `{"call":%d}`, n)
+	select {
+	case h.served <- struct{}{}:
+	default:
+	}
+}
+
+func cachedGet(t *testing.T, handler http.Handler, user *models.User, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if user != nil {
+		req = req.WithContext(context.WithValue(req.Context(), UserContextKey, user))
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func newTestResponseCache(t *testing.T) (*ResponseCache, *lifecycle.Group) {
+	store := cache.New(time.Minute, time.Minute)
+	t.Cleanup(store.Stop)
+	workers := lifecycle.NewGroup()
+	t.Cleanup(func() { _ = workers.Stop(context.Background()) })
+	return NewResponseCache(store, workers), workers
+}
+
+func TestResponseCache_HitAndMiss(t *testing.T) {
+	rc, _ := newTestResponseCache(t)
+	next := newCountingHandler(http.StatusOK)
+	handler := rc.Handler(CachePolicy{Name: "tree", TTL: time.Minute})(next)
+	alice := &models.User{ID: 1, Role: models.RoleSupervisor}
+	bob := &models.User{ID: 2, Role: models.RoleSupervisor}
+
+	steps := []struct {
+		name      string
+		user      *models.User
+		path      string
+		wantCache string
+		wantBody  string
+	}{
+		{"first request", alice, "/tree", "MISS", `{"call":1}`},
+		{"repeat request", alice, "/tree", "HIT", `{"call":1}`},
+		{"different query", alice, "/tree?depth=2", "MISS", `{"call":2}`},
+		{"different user", bob, "/tree", "MISS", `{"call":3}`},
+		{"unauthenticated", nil, "/tree", "", `{"call":4}`},
+	}
+
+	for _, step := range steps {
+		rr := cachedGet(t, handler, step.user, step.path)
+		if rr.Header().Get("X-Cache") != step.wantCache {
+			t.Errorf("%s: X-Cache = %q, want %q", step.name, rr.Header().Get("X-Cache"), step.wantCache)
+		}
+		if rr.Body.String() != step.wantBody {
+			t.Errorf("%s: body = %s, want %s", step.name, rr.Body.String(), step.wantBody)
+		}
+		if rr.Header().Get("Content-Type") != "application/json" {
+			t.Errorf("%s: Content-Type = %q", step.name, rr.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestResponseCache_NotModified(t *testing.T) {
+	rc, _ := newTestResponseCache(t)
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") != "" {
+			t.Error("handler saw If-None-Match; cached responses must be complete")
+		}
+		w.Header().Set("ETag", `W/"v1"`)
+		fmt.Fprint(w, `{"tree":[]}`)
+	})
+	handler := rc.Handler(CachePolicy{Name: "tree", TTL: time.Minute})(next)
+	user := &models.User{ID: 1}
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/tree", nil)
+		req = req.WithContext(context.WithValue(req.Context(), UserContextKey, user))
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	steps := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+		wantCache   string
+		wantBody    string
+	}{
+		{"miss with current tag", `W/"v1"`, http.StatusNotModified, "MISS", ""},
+		{"hit without tag", "", http.StatusOK, "HIT", `{"tree":[]}`},
+		{"hit with current tag", `W/"v1"`, http.StatusNotModified, "HIT", ""},
+		{"hit with old tag", `W/"v0"`, http.StatusOK, "HIT", `{"tree":[]}`},
+	}
+
+	for _, step := range steps {
+		rr := get(step.ifNoneMatch)
+		if rr.Code != step.wantStatus || rr.Header().Get("X-Cache") != step.wantCache || rr.Body.String() != step.wantBody {
+			t.Errorf("%s: status = %d, X-Cache = %q, body = %q; want %d, %q, %q", step.name,
+				rr.Code, rr.Header().Get("X-Cache"), rr.Body.String(), step.wantStatus, step.wantCache, step.wantBody)
+		}
+		if rr.Header().Get("ETag") != `W/"v1"` {
+			t.Errorf("%s: ETag = %q", step.name, rr.Header().Get("ETag"))
+		}
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
+
+func TestResponseCache_DoesNotCacheErrors(t *testing.T) {
+	rc, _ := newTestResponseCache(t)
+	next := newCountingHandler(http.StatusInternalServerError)
+	handler := rc.Handler(CachePolicy{Name: "tree", TTL: time.Minute})(next)
+	user := &models.User{ID: 1}
+
+	for i := 0; i < 2; i++ {
+		rr := cachedGet(t, handler, user, "/tree")
+		if rr.Code != http.StatusInternalServerError || rr.Header().Get("X-Cache") != "MISS" {
+			t.Errorf("request %d: status = %d, X-Cache = %q", i, rr.Code, rr.Header().Get("X-Cache"))
+		}
+	}
+	if next.calls.Load() != 2 {
+		t.Errorf("handler called %d times, want 2", next.calls.Load())
+	}
+}
+
+func TestResponseCache_StaleWhileRevalidate(t *testing.T) {
+	rc, _ := newTestResponseCache(t)
+	next := newCountingHandler(http.StatusOK)
+	handler := rc.Handler(CachePolicy{Name: "tree", TTL: 10 * time.Millisecond, StaleTTL: time.Minute})(next)
+	user := &models.User{ID: 1}
+
+	cachedGet(t, handler, user, "/tree")
+	<-next.served
+	time.Sleep(20 * time.Millisecond)
+
+	// The stale copy is served straight away while the handler runs again in the background
+	rr := cachedGet(t, handler, user, "/tree")
+	if rr.Header().Get("X-Cache") != "STALE" || rr.Body.String() != `{"call":1}` {
+		t.Fatalf("X-Cache = %q, body = %s, want the stale response", rr.Header().Get("X-Cache"), rr.Body.String())
+	}
+
+	select {
+	case <-next.served:
+	case <-time.After(time.Second):
+		t.Fatal("stale response was not refreshed")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		rr = cachedGet(t, handler, user, "/tree")
+		if rr.Header().Get("X-Cache") == "HIT" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("refreshed response was not cached, X-Cache = %q", rr.Header().Get("X-Cache"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if rr.Body.String() != `{"call":2}` {
+		t.Errorf("body = %s, want the refreshed response", rr.Body.String())
+	}
+}
+
+func TestResponseCache_NoCacheBypass(t *testing.T) {
+	rc, _ := newTestResponseCache(t)
+	next := newCountingHandler(http.StatusOK)
+	handler := rc.Handler(CachePolicy{Name: "tree", TTL: time.Minute})(next)
+	user := &models.User{ID: 1}
+
+	cachedGet(t, handler, user, "/tree")
+
+	req := httptest.NewRequest(http.MethodGet, "/tree", nil)
+	req.Header.Set("Cache-Control", "no-cache")
+	req = req.WithContext(context.WithValue(req.Context(), UserContextKey, user))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Header().Get("X-Cache") != "MISS" || rr.Body.String() != `{"call":2}` {
+		t.Errorf("X-Cache = %q, body = %s, want a fresh response", rr.Header().Get("X-Cache"), rr.Body.String())
+	}
+}
+
+func TestResponseCache_InvalidatedByEvents(t *testing.T) {
+	rc, _ := newTestResponseCache(t)
+	tree := rc.Handler(CachePolicy{Name: "tree", TTL: time.Minute, InvalidateOn: []events.Type{events.OrgChartPublished}})(newCountingHandler(http.StatusOK))
+	tasks := rc.Handler(CachePolicy{Name: "tasks", TTL: time.Minute, InvalidateOn: []events.Type{events.UserChanged}})(newCountingHandler(http.StatusOK))
+	user := &models.User{ID: 1}
+
+	broker := events.NewBroker(events.DefaultBufferSize)
+	rc.Listen(broker)
+
+	cachedGet(t, tree, user, "/tree")
+	cachedGet(t, tasks, user, "/tasks")
+
+	broker.Publish(context.Background(), events.OrgChartPublished, nil)
+
+	deadline := time.Now().Add(time.Second)
+	for cachedGet(t, tree, user, "/tree").Header().Get("X-Cache") != "MISS" {
+		if time.Now().After(deadline) {
+			t.Fatal("org tree was not invalidated")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Policies not listening for the event keep their entries
+	if got := cachedGet(t, tasks, user, "/tasks").Header().Get("X-Cache"); got != "HIT" {
+		t.Errorf("tasks X-Cache = %q, want HIT", got)
+	}
+}
+
+func TestResponseCache_InvalidateAll(t *testing.T) {
+	rc, _ := newTestResponseCache(t)
+	tree := rc.Handler(CachePolicy{Name: "tree", TTL: time.Minute})(newCountingHandler(http.StatusOK))
+	tasks := rc.Handler(CachePolicy{Name: "tasks", TTL: time.Minute, InvalidateOn: []events.Type{events.UserChanged}})(newCountingHandler(http.StatusOK))
+	user := &models.User{ID: 1}
+
+	cachedGet(t, tree, user, "/tree")
+	cachedGet(t, tasks, user, "/tasks")
+
+	rc.InvalidateAll()
+
+	for name, handler := range map[string]http.Handler{"/tree": tree, "/tasks": tasks} {
+		if got := cachedGet(t, handler, user, name).Header().Get("X-Cache"); got != "MISS" {
+			t.Errorf("%s X-Cache = %q, want MISS", name, got)
+		}
+	}
+	if gen := rc.generation("tree"); gen != 1 {
+		t.Errorf("tree generation = %d, want 1 so in-flight refreshes are discarded", gen)
+	}
+}
+
+func TestResponseCache_StopWaitsForRefresh(t *testing.T) {
+	rc, workers := newTestResponseCache(t)
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := calls.Add(1); n > 1 {
+			close(started)
+			<-release
+		}
+		fmt.Fprint(w, `{}`)
+	})
+	handler := rc.Handler(CachePolicy{Name: "tree", TTL: 10 * time.Millisecond, StaleTTL: time.Minute})(next)
+	user := &models.User{ID: 1}
+
+	cachedGet(t, handler, user, "/tree")
+	time.Sleep(20 * time.Millisecond)
+	if got := cachedGet(t, handler, user, "/tree").Header().Get("X-Cache"); got != "STALE" {
+		t.Fatalf("X-Cache = %q, want STALE", got)
+	}
+	<-started
+
+	if running := workers.Running(); len(running) != 1 || running[0] != "response-cache-refresh" {
+		t.Errorf("Running() = %v, want the refresh tracked", running)
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- workers.Stop(context.Background()) }()
+	select {
+	case <-stopped:
+		t.Fatal("Stop() returned while a refresh was in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-stopped; err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/smith-dallin/manager-dashboard/internal/scim"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// SCIMAuth admits requests bearing the SCIM token configured on the identity provider. SCIM
+// clients are services rather than users, so no user is put in the request context. The token is
+// the deployment's, so provisioning acts in the default organization.
+func SCIMAuth(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+				scim.WriteError(w, http.StatusUnauthorized, "", "Invalid SCIM token")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(tenant.WithOrgID(r.Context(), tenant.DefaultOrgID)))
+		})
+	}
+}
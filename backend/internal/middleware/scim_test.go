@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+func TestSCIMAuth(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	tests := []struct {
+		name           string
+		token          string
+		header         string
+		expectedStatus int
+	}{
+		{"valid token", "s3cret", "Bearer s3cret", http.StatusOK},
+		{"wrong token", "s3cret", "Bearer guess", http.StatusUnauthorized},
+		{"missing header", "s3cret", "", http.StatusUnauthorized},
+		{"no token configured", "", "Bearer ", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/scim/v2/Users", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			SCIMAuth(tt.token)(ok).ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestSCIMAuth_ScopesToDefaultOrg(t *testing.T) {
+	var orgID int64
+	var scoped bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgID, scoped = tenant.OrgID(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/scim/v2/Users", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	SCIMAuth("s3cret")(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	if !scoped || orgID != tenant.DefaultOrgID {
+		t.Errorf("expected request scoped to organization %d, got %d (scoped=%v)", tenant.DefaultOrgID, orgID, scoped)
+	}
+}
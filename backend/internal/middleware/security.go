@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
 	"golang.org/x/time/rate"
 )
 
@@ -157,7 +158,7 @@ func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
 
 		if !limiter.Allow() {
 			w.Header().Set("Retry-After", "1")
-			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			writeAppError(w, apperrors.NewRateLimitError())
 			return
 		}
 
@@ -165,29 +166,6 @@ func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
 	})
 }
 
-// getIP extracts the real client IP from the request
-func getIP(r *http.Request) string {
-	// Check X-Forwarded-For header first (for proxies/load balancers)
-	// X-Forwarded-For can contain multiple IPs: "client, proxy1, proxy2"
-	// We take only the first (leftmost) IP which is the original client
-	forwarded := r.Header.Get("X-Forwarded-For")
-	if forwarded != "" {
-		// Split by comma and take the first IP, trimming whitespace
-		if idx := strings.Index(forwarded, ","); idx != -1 {
-			return strings.TrimSpace(forwarded[:idx])
-		}
-		return strings.TrimSpace(forwarded)
-	}
-
-	// Check X-Real-IP header
-	realIP := r.Header.Get("X-Real-IP")
-	if realIP != "" {
-		return strings.TrimSpace(realIP)
-	}
-
-	return r.RemoteAddr
-}
-
 // RequestSizeLimiter limits the maximum request body size
 func RequestSizeLimiter(maxBytes int64) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -309,7 +287,7 @@ func (erl *EndpointRateLimiter) Limit(next http.Handler) http.Handler {
 				erl.onRateLimited(r)
 			}
 			w.Header().Set("Retry-After", "1")
-			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			writeAppError(w, apperrors.NewRateLimitError())
 			return
 		}
 
@@ -324,6 +302,7 @@ var (
 		// Invitations - very sensitive, limit to 10 requests/minute
 		{PathPrefix: "/api/invitations", Method: "POST", RPS: rate.Limit(10.0 / 60.0), Burst: 3},
 		{PathPrefix: "/api/invitations", Method: "DELETE", RPS: rate.Limit(10.0 / 60.0), Burst: 3},
+		{PathPrefix: "/api/access-requests", Method: "POST", RPS: rate.Limit(5.0 / 60.0), Burst: 2},
 
 		// User management - sensitive operations
 		{PathPrefix: "/api/users", Method: "POST", RPS: rate.Limit(20.0 / 60.0), Burst: 5},
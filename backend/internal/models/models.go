@@ -1,8 +1,15 @@
 package models
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -13,13 +20,17 @@ const (
 	RoleAdmin      Role = "admin"
 	RoleSupervisor Role = "supervisor"
 	RoleEmployee   Role = "employee"
+	RoleViewer     Role = "viewer" // Read-only access to org-wide data, e.g. executives or finance
+	RoleGuest      Role = "guest"  // External collaborator who only sees meetings they're invited to
 )
 
-// ValidRoles contains all valid role values
+// ValidRoles contains all valid role values.
+// Guests are left out because they can only be created from an invitation that sets their expiry.
 var ValidRoles = map[Role]bool{
 	RoleAdmin:      true,
 	RoleSupervisor: true,
 	RoleEmployee:   true,
+	RoleViewer:     true,
 }
 
 // Validation constants
@@ -28,15 +39,88 @@ const (
 	MaxDepartmentLength = 100
 	MaxSquadLength      = 100
 	MaxEmailLength      = 254
+	MaxTimezoneLength   = 64
+	MaxPronounsLength   = 50
+	MaxPhoneLength      = 50
+)
+
+// DefaultTimezone is used for users and meetings without a timezone
+const DefaultTimezone = "UTC"
+
+// ValidateTimezone checks that name is an IANA timezone such as "America/Denver"
+func ValidateTimezone(name string) error {
+	if name == "" || name == "Local" || len(name) > MaxTimezoneLength {
+		return fmt.Errorf("invalid timezone %q", name)
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("invalid timezone %q", name)
+	}
+	return nil
+}
+
+// DefaultLanguage is used for users and invitations without a language
+const DefaultLanguage = "en"
+
+// SupportedLanguages are the languages emails can be sent in, as ISO 639-1 codes
+var SupportedLanguages = []string{"en", "es"}
+
+// ValidateLanguage checks that code is one of SupportedLanguages
+func ValidateLanguage(code string) error {
+	if !slices.Contains(SupportedLanguages, code) {
+		return fmt.Errorf("language must be one of %s", strings.Join(SupportedLanguages, ", "))
+	}
+	return nil
+}
+
+// LocationOrUTC loads an IANA timezone, falling back to UTC for empty or unknown names
+func LocationOrUTC(name string) *time.Location {
+	if name == "" || name == "Local" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LocalDayStart returns the instant a calendar date begins in loc. Dates are stored as midnight UTC.
+func LocalDayStart(date time.Time, loc *time.Location) time.Time {
+	date = date.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+}
+
+// SquadRole is a user's role within a squad
+type SquadRole string
+
+const (
+	SquadRoleLead   SquadRole = "lead"
+	SquadRoleMember SquadRole = "member"
 )
 
 // Squad represents a team/squad that users can belong to
 type Squad struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	LeadID *int64 `json:"lead_id,omitempty"` // Set when listing squads
+	// Role is the user's role in the squad, set when loading a user's squads
+	Role      SquadRole `json:"role,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// SetSquadLeadRequest makes a user the lead of a squad, adding them to it if needed
+type SetSquadLeadRequest struct {
+	UserID int64 `json:"user_id"`
+}
+
+// Validate validates the SetSquadLeadRequest
+func (r *SetSquadLeadRequest) Validate() error {
+	if r.UserID <= 0 {
+		return fmt.Errorf("user_id is required")
+	}
+	return nil
+}
+
 // Department represents a department in the organization
 type Department struct {
 	ID        int64     `json:"id"`
@@ -61,6 +145,36 @@ type User struct {
 	IsActive     bool       `json:"is_active"`
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at"`
+	// Guest accounts stop working after this time
+	AccessExpiresAt *time.Time `json:"access_expires_at,omitempty"`
+	// IANA timezone used for the user's local times and all-day boundaries
+	Timezone string `json:"timezone"`
+	// Language the user's emails are sent in; one of SupportedLanguages
+	Language string `json:"language"`
+	// Last working day of an offboarded user
+	TerminationDate *time.Time `json:"termination_date,omitempty"`
+	// Optional; only the month and day are used for milestones
+	Birthday *time.Time `json:"birthday,omitempty"`
+	// Resized copies of the avatar; empty for avatars set before uploads were resized
+	AvatarVariants AvatarVariants `json:"avatar_variants,omitempty"`
+	// Generated initials or Gravatar image shown when the user has no avatar
+	DefaultAvatarURL *string `json:"default_avatar_url,omitempty"`
+	// Values of admin-defined custom fields, keyed by field key
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+	// Admin-defined roles granting permissions on top of Role; loaded per request by the auth middleware
+	CustomRoles []CustomRole `json:"custom_roles,omitempty"`
+	// Organization the user belongs to; a hosted deployment serves several
+	OrgID int64 `json:"org_id"`
+	// Set when the user is soft-deleted; they can be restored until purged
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// When the user last signed in and last made a request; nil if they never have
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	LastSeenAt  *time.Time `json:"last_seen_at,omitempty"`
+	// Contact details the user keeps up to date themselves; empty when not given
+	PreferredName    string           `json:"preferred_name,omitempty"`
+	Pronouns         string           `json:"pronouns,omitempty"`
+	Phone            string           `json:"phone,omitempty"`
+	EmergencyContact EmergencyContact `json:"-"` // Exposed through UserResponse.WithEmergencyContact
 	// Jira integration fields (legacy API token auth)
 	JiraDomain   *string `json:"jira_domain,omitempty"`
 	JiraEmail    *string `json:"jira_email,omitempty"`
@@ -73,6 +187,15 @@ type User struct {
 	JiraSiteURL           *string    `json:"jira_site_url,omitempty"`
 	// Jira account matching (for org-wide Jira connection)
 	JiraAccountID *string `json:"jira_account_id,omitempty"`
+	// GitHub account matching (for org-wide GitHub connection)
+	GitHubLogin *string `json:"github_login,omitempty"`
+	// Linear user matching (for org-wide Linear connection)
+	LinearUserID *string `json:"linear_user_id,omitempty"`
+}
+
+// Location returns the user's timezone, defaulting to UTC
+func (u *User) Location() *time.Location {
+	return LocationOrUTC(u.Timezone)
 }
 
 // HasJiraConfigured checks if user has Jira credentials configured (either OAuth or legacy API token)
@@ -135,6 +258,69 @@ type UpdateUserRequest struct {
 	SquadIDs     []int64 `json:"squad_ids,omitempty"`
 	SupervisorID *int64  `json:"supervisor_id,omitempty"`
 	AvatarURL    *string `json:"avatar_url,omitempty"`
+	Timezone     *string `json:"timezone,omitempty"`
+	Language     *string `json:"language,omitempty"`
+	// YYYY-MM-DD; an empty string clears the birthday
+	Birthday *string `json:"birthday,omitempty"`
+	// Custom field values by field key; an empty value clears the field.
+	// Values are validated against the field schema by the user service.
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+	// Contact details; an empty string clears one. Users can always change their own.
+	PreferredName *string `json:"preferred_name,omitempty"`
+	Pronouns      *string `json:"pronouns,omitempty"`
+	Phone         *string `json:"phone,omitempty"`
+	// Replaces the whole emergency contact; an empty contact clears it
+	EmergencyContact *EmergencyContact `json:"emergency_contact,omitempty"`
+}
+
+// EmergencyContact is who to call if something happens to a user. Only the user, whoever can
+// update them, and admins see it.
+type EmergencyContact struct {
+	Name         string `json:"name"`
+	Phone        string `json:"phone"`
+	Relationship string `json:"relationship"`
+}
+
+// Validate validates the EmergencyContact
+func (c *EmergencyContact) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Phone = strings.TrimSpace(c.Phone)
+	c.Relationship = strings.TrimSpace(c.Relationship)
+	if len(c.Name) > MaxNameLength {
+		return fmt.Errorf("emergency contact name must be less than %d characters", MaxNameLength)
+	}
+	if err := validatePhone(c.Phone); err != nil {
+		return fmt.Errorf("emergency contact %w", err)
+	}
+	if len(c.Relationship) > MaxPronounsLength {
+		return fmt.Errorf("emergency contact relationship must be less than %d characters", MaxPronounsLength)
+	}
+	if c.Phone != "" && c.Name == "" {
+		return fmt.Errorf("emergency contact name is required with a phone number")
+	}
+	return nil
+}
+
+// validatePhone checks that phone is empty or looks like a phone number: digits with optional
+// spaces, dashes, dots, parentheses, and a leading +
+func validatePhone(phone string) error {
+	if len(phone) > MaxPhoneLength {
+		return fmt.Errorf("phone must be less than %d characters", MaxPhoneLength)
+	}
+	digits := 0
+	for i, c := range phone {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '+' && i == 0, c == ' ', c == '-', c == '.', c == '(', c == ')':
+		default:
+			return fmt.Errorf("phone may only contain digits, spaces, dashes, dots, parentheses, and a leading +")
+		}
+	}
+	if phone != "" && digits < 3 {
+		return fmt.Errorf("phone must contain at least 3 digits")
+	}
+	return nil
 }
 
 // IsAdmin checks if the user has admin role
@@ -147,6 +333,26 @@ func (u *User) IsSupervisor() bool {
 	return u.Role == RoleSupervisor
 }
 
+// IsViewer checks if the user has the read-only viewer role
+func (u *User) IsViewer() bool {
+	return u.Role == RoleViewer
+}
+
+// IsGuest checks if the user is an external guest collaborator
+func (u *User) IsGuest() bool {
+	return u.Role == RoleGuest
+}
+
+// IsDeleted checks if the user has been soft-deleted
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// HasAccessExpired checks if the user's access expiry date has passed
+func (u *User) HasAccessExpired() bool {
+	return u.AccessExpiresAt != nil && !time.Now().Before(*u.AccessExpiresAt)
+}
+
 // IsSupervisorOrAdmin checks if user has supervisor or admin role
 func (u *User) IsSupervisorOrAdmin() bool {
 	return u.Role == RoleSupervisor || u.Role == RoleAdmin
@@ -247,11 +453,388 @@ func (r *UpdateUserRequest) Validate() error {
 		}
 	}
 
+	if r.Timezone != nil {
+		*r.Timezone = strings.TrimSpace(*r.Timezone)
+		if err := ValidateTimezone(*r.Timezone); err != nil {
+			return err
+		}
+	}
+
+	if r.Language != nil {
+		if err := ValidateLanguage(*r.Language); err != nil {
+			return err
+		}
+	}
+
+	if r.Birthday != nil {
+		*r.Birthday = strings.TrimSpace(*r.Birthday)
+		if *r.Birthday != "" {
+			date, err := time.Parse("2006-01-02", *r.Birthday)
+			if err != nil {
+				return fmt.Errorf("birthday must be in YYYY-MM-DD format")
+			}
+			if date.After(time.Now()) {
+				return fmt.Errorf("birthday can't be in the future")
+			}
+		}
+	}
+
+	if r.PreferredName != nil {
+		*r.PreferredName = strings.TrimSpace(*r.PreferredName)
+		if len(*r.PreferredName) > MaxNameLength {
+			return fmt.Errorf("preferred name must be less than %d characters", MaxNameLength)
+		}
+	}
+	if r.Pronouns != nil {
+		*r.Pronouns = strings.TrimSpace(*r.Pronouns)
+		if len(*r.Pronouns) > MaxPronounsLength {
+			return fmt.Errorf("pronouns must be less than %d characters", MaxPronounsLength)
+		}
+	}
+	if r.Phone != nil {
+		*r.Phone = strings.TrimSpace(*r.Phone)
+		if err := validatePhone(*r.Phone); err != nil {
+			return err
+		}
+	}
+	if r.EmergencyContact != nil {
+		if err := r.EmergencyContact.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// SquadIDs are validated at the repository level
 
 	return nil
 }
 
+// OffboardUserRequest controls how a departing user's work is handed off
+type OffboardUserRequest struct {
+	// YYYY-MM-DD; defaults to today. Can't be in the future since offboarding deactivates immediately.
+	TerminationDate string `json:"termination_date,omitempty"`
+	// Receives the user's open tasks; defaults to their supervisor, or leaves the tasks unassigned
+	ReassignTasksToID *int64 `json:"reassign_tasks_to_id,omitempty"`
+}
+
+// Validate validates the OffboardUserRequest
+func (r *OffboardUserRequest) Validate() error {
+	r.TerminationDate = strings.TrimSpace(r.TerminationDate)
+	if r.TerminationDate != "" {
+		date, err := time.Parse("2006-01-02", r.TerminationDate)
+		if err != nil {
+			return fmt.Errorf("termination_date must be in YYYY-MM-DD format")
+		}
+		if date.After(time.Now()) {
+			return fmt.Errorf("termination_date can't be in the future")
+		}
+	}
+	if r.ReassignTasksToID != nil && *r.ReassignTasksToID <= 0 {
+		return fmt.Errorf("reassign_tasks_to_id must be a valid user ID")
+	}
+	return nil
+}
+
+// Date returns the termination date, defaulting to today
+func (r *OffboardUserRequest) Date() time.Time {
+	if date, err := time.Parse("2006-01-02", r.TerminationDate); err == nil {
+		return date
+	}
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// OffboardResult summarizes the cleanup done when a user was offboarded
+type OffboardResult struct {
+	TerminationDate    time.Time `json:"termination_date"`
+	TasksReassigned    int       `json:"tasks_reassigned"`
+	ReassignedToID     *int64    `json:"reassigned_to_id,omitempty"` // Nil when the tasks were left unassigned
+	MeetingsCancelled  int       `json:"meetings_cancelled"`
+	SeriesEnded        int       `json:"series_ended"` // Recurring meetings already underway, ended as of now
+	JiraMappingRevoked bool      `json:"jira_mapping_revoked"`
+}
+
+// UserHistoryField is a user attribute whose changes are kept in their history
+type UserHistoryField string
+
+const (
+	UserHistoryTitle      UserHistoryField = "title"
+	UserHistoryDepartment UserHistoryField = "department"
+	UserHistoryRole       UserHistoryField = "role"
+	UserHistorySupervisor UserHistoryField = "supervisor" // Values are user IDs
+	UserHistorySquads     UserHistoryField = "squads"     // Values are comma-separated squad IDs
+)
+
+// UserHistorySource is where a change to a user came from
+type UserHistorySource string
+
+const (
+	UserHistorySourceProfile    UserHistorySource = "profile"    // Edited directly
+	UserHistorySourceOrgChart   UserHistorySource = "org_chart"  // A published org chart draft
+	UserHistorySourceInvitation UserHistorySource = "invitation" // Starting values from an accepted invitation
+	// Starting values from an approved access request
+	UserHistorySourceAccessRequest UserHistorySource = "access_request"
+	// A title or department change the user asked for, approved by their supervisor
+	UserHistorySourceProfileChange UserHistorySource = "profile_change"
+)
+
+// UserHistoryEntry records one change to a user. A nil value means the field was empty.
+type UserHistoryEntry struct {
+	ID            int64             `json:"id"`
+	UserID        int64             `json:"user_id"`
+	Field         UserHistoryField  `json:"field"`
+	OldValue      *string           `json:"old_value"`
+	NewValue      *string           `json:"new_value"`
+	Source        UserHistorySource `json:"source"`
+	ChangedByID   *int64            `json:"changed_by_id,omitempty"`
+	ChangedByName string            `json:"changed_by_name,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// historyValue returns nil for an empty value
+func historyValue(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// DiffUserHistory returns an entry for each change to title, department, role, or supervisor between
+// before and after. Squads aren't always loaded, so callers compare them with SquadHistoryEntry.
+func DiffUserHistory(before, after *User) []UserHistoryEntry {
+	var entries []UserHistoryEntry
+	add := func(field UserHistoryField, oldValue, newValue string) {
+		if oldValue != newValue {
+			entries = append(entries, UserHistoryEntry{
+				UserID: after.ID, Field: field, OldValue: historyValue(oldValue), NewValue: historyValue(newValue),
+			})
+		}
+	}
+	supervisor := func(u *User) string {
+		if u.SupervisorID == nil {
+			return ""
+		}
+		return strconv.FormatInt(*u.SupervisorID, 10)
+	}
+
+	add(UserHistoryTitle, before.Title, after.Title)
+	add(UserHistoryDepartment, before.Department, after.Department)
+	add(UserHistoryRole, string(before.Role), string(after.Role))
+	add(UserHistorySupervisor, supervisor(before), supervisor(after))
+	return entries
+}
+
+// SquadHistoryEntry returns an entry if a user's squads changed, ignoring order, or nil if they didn't
+func SquadHistoryEntry(userID int64, before, after []int64) *UserHistoryEntry {
+	format := func(ids []int64) string {
+		sorted := slices.Clone(ids)
+		slices.Sort(sorted)
+		sorted = slices.Compact(sorted)
+		parts := make([]string, len(sorted))
+		for i, id := range sorted {
+			parts[i] = strconv.FormatInt(id, 10)
+		}
+		return strings.Join(parts, ",")
+	}
+
+	oldValue, newValue := format(before), format(after)
+	if oldValue == newValue {
+		return nil
+	}
+	return &UserHistoryEntry{UserID: userID, Field: UserHistorySquads, OldValue: historyValue(oldValue), NewValue: historyValue(newValue)}
+}
+
+// SquadIDs returns the IDs of the user's loaded squads
+func (u *User) SquadIDs() []int64 {
+	ids := make([]int64, len(u.Squads))
+	for i, squad := range u.Squads {
+		ids[i] = squad.ID
+	}
+	return ids
+}
+
+// CustomFieldType is the kind of value a custom field holds
+type CustomFieldType string
+
+const (
+	CustomFieldTypeText   CustomFieldType = "text"
+	CustomFieldTypeDate   CustomFieldType = "date" // YYYY-MM-DD
+	CustomFieldTypeSelect CustomFieldType = "select"
+)
+
+// Custom field limits
+const (
+	MaxCustomFieldKeyLength   = 64
+	MaxCustomFieldLabelLength = 100
+	MaxCustomFieldOptions     = 50
+	MaxCustomFieldValueLength = 500
+)
+
+// customFieldKeyPattern matches keys like cost_center
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// CustomField is an admin-defined profile field every user can have a value for
+type CustomField struct {
+	ID        int64           `json:"id"`
+	Key       string          `json:"key"`
+	Label     string          `json:"label"`
+	Type      CustomFieldType `json:"type"`
+	Options   []string        `json:"options,omitempty"` // Allowed values of select fields
+	Required  bool            `json:"required"`
+	Position  int             `json:"position"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// NormalizeValue trims a value and checks it suits the field: dates must be YYYY-MM-DD and
+// select values one of the field's options. An empty value clears the field unless it's required.
+func (f *CustomField) NormalizeValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		if f.Required {
+			return "", fmt.Errorf("%s is required", f.Key)
+		}
+		return "", nil
+	}
+
+	switch f.Type {
+	case CustomFieldTypeDate:
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "", fmt.Errorf("%s must be a date in YYYY-MM-DD format", f.Key)
+		}
+	case CustomFieldTypeSelect:
+		if !slices.Contains(f.Options, value) {
+			return "", fmt.Errorf("%s must be one of: %s", f.Key, strings.Join(f.Options, ", "))
+		}
+	default:
+		if len(value) > MaxCustomFieldValueLength {
+			return "", fmt.Errorf("%s must be %d characters or less", f.Key, MaxCustomFieldValueLength)
+		}
+	}
+	return value, nil
+}
+
+// ValidateCustomFieldValues checks values keyed by field key against the fields and returns
+// the normalized values keyed by field ID. Empty values mean the field should be cleared.
+func ValidateCustomFieldValues(fields []CustomField, values map[string]string) (map[int64]string, error) {
+	byKey := make(map[string]*CustomField, len(fields))
+	for i := range fields {
+		byKey[fields[i].Key] = &fields[i]
+	}
+
+	normalized := make(map[int64]string, len(values))
+	for key, value := range values {
+		field, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown custom field: %s", key)
+		}
+		v, err := field.NormalizeValue(value)
+		if err != nil {
+			return nil, err
+		}
+		normalized[field.ID] = v
+	}
+	return normalized, nil
+}
+
+// CreateCustomFieldRequest defines a new custom field. Required fields can't be cleared once
+// set, but users created before the field was added have no value until someone sets one.
+type CreateCustomFieldRequest struct {
+	Key      string          `json:"key"`
+	Label    string          `json:"label"`
+	Type     CustomFieldType `json:"type"`
+	Options  []string        `json:"options,omitempty"`
+	Required bool            `json:"required"`
+	Position int             `json:"position"`
+}
+
+// Validate validates the CreateCustomFieldRequest
+func (r *CreateCustomFieldRequest) Validate() error {
+	r.Key = strings.TrimSpace(r.Key)
+	if r.Key == "" {
+		return fmt.Errorf("key is required")
+	}
+	if len(r.Key) > MaxCustomFieldKeyLength || !customFieldKeyPattern.MatchString(r.Key) {
+		return fmt.Errorf("key must be at most %d lowercase letters, digits, or underscores, starting with a letter", MaxCustomFieldKeyLength)
+	}
+	var err error
+	if r.Label, err = validateCustomFieldLabel(r.Label); err != nil {
+		return err
+	}
+	switch r.Type {
+	case CustomFieldTypeText, CustomFieldTypeDate, CustomFieldTypeSelect:
+	default:
+		return fmt.Errorf("type must be 'text', 'date', or 'select'")
+	}
+	r.Options, err = validateCustomFieldOptions(r.Type, r.Options)
+	return err
+}
+
+// UpdateCustomFieldRequest replaces a custom field's label, options, required flag, and position.
+// A field's key and type can't change.
+type UpdateCustomFieldRequest struct {
+	Label    string   `json:"label"`
+	Options  []string `json:"options,omitempty"`
+	Required bool     `json:"required"`
+	Position int      `json:"position"`
+}
+
+// Validate validates the UpdateCustomFieldRequest
+func (r *UpdateCustomFieldRequest) Validate() error {
+	var err error
+	r.Label, err = validateCustomFieldLabel(r.Label)
+	return err
+}
+
+// ValidateFor checks the options suit the type of the field being updated
+func (r *UpdateCustomFieldRequest) ValidateFor(field *CustomField) error {
+	var err error
+	r.Options, err = validateCustomFieldOptions(field.Type, r.Options)
+	return err
+}
+
+// validateCustomFieldLabel trims a custom field label and checks its length
+func validateCustomFieldLabel(label string) (string, error) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return "", fmt.Errorf("label is required")
+	}
+	if len(label) > MaxCustomFieldLabelLength {
+		return "", fmt.Errorf("label must be %d characters or less", MaxCustomFieldLabelLength)
+	}
+	return label, nil
+}
+
+// validateCustomFieldOptions trims options and checks select fields have distinct, non-empty ones
+// and other fields have none
+func validateCustomFieldOptions(fieldType CustomFieldType, options []string) ([]string, error) {
+	if fieldType != CustomFieldTypeSelect {
+		if len(options) > 0 {
+			return nil, fmt.Errorf("options are only allowed for select fields")
+		}
+		return nil, nil
+	}
+
+	if len(options) == 0 {
+		return nil, fmt.Errorf("select fields need at least one option")
+	}
+	if len(options) > MaxCustomFieldOptions {
+		return nil, fmt.Errorf("select fields can have at most %d options", MaxCustomFieldOptions)
+	}
+	trimmed := make([]string, len(options))
+	for i, option := range options {
+		trimmed[i] = strings.TrimSpace(option)
+		if trimmed[i] == "" {
+			return nil, fmt.Errorf("options can't be empty")
+		}
+		if len(trimmed[i]) > MaxCustomFieldValueLength {
+			return nil, fmt.Errorf("options must be %d characters or less", MaxCustomFieldValueLength)
+		}
+		if slices.Contains(trimmed[:i], trimmed[i]) {
+			return nil, fmt.Errorf("duplicate option: %s", trimmed[i])
+		}
+	}
+	return trimmed, nil
+}
+
 // InvitationStatus represents the status of an invitation
 type InvitationStatus string
 
@@ -277,14 +860,35 @@ type Invitation struct {
 	AcceptedAt  *time.Time       `json:"accepted_at,omitempty"`
 	CreatedAt   time.Time        `json:"created_at"`
 	UpdatedAt   time.Time        `json:"updated_at"`
+	// Guest invitations only
+	AccessExpiresAt *time.Time `json:"access_expires_at,omitempty"` // When the guest account stops working
+	MeetingIDs      []int64    `json:"meeting_ids,omitempty"`       // Meetings the guest attends once they accept
+	// Organization the invitee joins: the inviter's
+	OrgID int64 `json:"org_id"`
+	// Language the invitation email is sent in, which the new user keeps
+	Language string `json:"language"`
+	// Starting details the new user is created with; not for guests
+	SupervisorID *int64     `json:"supervisor_id,omitempty"`
+	Title        string     `json:"title,omitempty"`
+	DateStarted  *time.Time `json:"date_started,omitempty"`
 }
 
+// MaxGuestAccessDays is the longest a guest account can be granted access for
+const MaxGuestAccessDays = 365
+
 // CreateInvitationRequest represents a request to create an invitation
 type CreateInvitationRequest struct {
-	Email      string  `json:"email"`
-	Role       Role    `json:"role"`
-	Department string  `json:"department,omitempty"`
-	SquadIDs   []int64 `json:"squad_ids,omitempty"`
+	Email           string     `json:"email"`
+	Role            Role       `json:"role"`
+	Department      string     `json:"department,omitempty"`
+	SquadIDs        []int64    `json:"squad_ids,omitempty"`
+	AccessExpiresAt *time.Time `json:"access_expires_at,omitempty"` // Required for guests
+	MeetingIDs      []int64    `json:"meeting_ids,omitempty"`       // Guests only
+	Language        string     `json:"language,omitempty"`          // Defaults to the inviter's language
+	// Not for guests. Employees report to the inviter unless a supervisor is given.
+	SupervisorID *int64     `json:"supervisor_id,omitempty"`
+	Title        string     `json:"title,omitempty"`
+	DateStarted  *time.Time `json:"date_started,omitempty"` // Defaults to when the invitation is accepted
 }
 
 // Validate validates the CreateInvitationRequest
@@ -300,676 +904,5457 @@ func (r *CreateInvitationRequest) Validate() error {
 		return fmt.Errorf("invalid email format")
 	}
 
-	// Role validation - only admin and supervisor can be invited
-	if r.Role != RoleAdmin && r.Role != RoleSupervisor {
-		return fmt.Errorf("can only invite admin or supervisor roles")
+	if r.Language != "" {
+		if err := ValidateLanguage(r.Language); err != nil {
+			return err
+		}
+	}
+
+	// Role validation - any role can be invited
+	if !ValidRoles[r.Role] && r.Role != RoleGuest {
+		return fmt.Errorf("can only invite admin, supervisor, employee, viewer, or guest roles")
+	}
+
+	r.Title = strings.TrimSpace(r.Title)
+	if len(r.Title) > 255 {
+		return fmt.Errorf("title must be less than 255 characters")
+	}
+
+	if r.Role != RoleGuest {
+		if r.AccessExpiresAt != nil || len(r.MeetingIDs) > 0 {
+			return fmt.Errorf("access_expires_at and meeting_ids only apply to guest invitations")
+		}
+		return nil
+	}
+
+	// Guests are outside the org structure and always expire
+	if r.Department != "" || len(r.SquadIDs) > 0 {
+		return fmt.Errorf("guests cannot belong to a department or squad")
+	}
+	if r.SupervisorID != nil || r.Title != "" || r.DateStarted != nil {
+		return fmt.Errorf("guests cannot have a supervisor, title, or start date")
+	}
+	if r.AccessExpiresAt == nil {
+		return fmt.Errorf("access_expires_at is required for guests")
+	}
+	now := time.Now()
+	if !r.AccessExpiresAt.After(now) {
+		return fmt.Errorf("access_expires_at must be in the future")
+	}
+	if r.AccessExpiresAt.After(now.AddDate(0, 0, MaxGuestAccessDays)) {
+		return fmt.Errorf("access_expires_at must be within %d days", MaxGuestAccessDays)
 	}
 
 	return nil
 }
 
-// UpdateJiraSettingsRequest represents a request to update Jira settings
-type UpdateJiraSettingsRequest struct {
-	JiraDomain   string `json:"jira_domain"`
-	JiraEmail    string `json:"jira_email"`
-	JiraAPIToken string `json:"jira_api_token"`
+// MaxBulkInvitations caps how many people a single bulk request may invite
+const MaxBulkInvitations = 200
+
+// BulkInvitationRequest invites many people at once. Everyone is invited with the same role and
+// starting details; each email is otherwise handled as its own invitation.
+type BulkInvitationRequest struct {
+	Emails          []string   `json:"emails"`
+	Role            Role       `json:"role"`
+	Department      string     `json:"department,omitempty"`
+	SquadIDs        []int64    `json:"squad_ids,omitempty"`
+	AccessExpiresAt *time.Time `json:"access_expires_at,omitempty"`
+	MeetingIDs      []int64    `json:"meeting_ids,omitempty"`
+	Language        string     `json:"language,omitempty"`
+	SupervisorID    *int64     `json:"supervisor_id,omitempty"`
+	Title           string     `json:"title,omitempty"`
+	DateStarted     *time.Time `json:"date_started,omitempty"`
 }
 
-// Validate validates the UpdateJiraSettingsRequest
-func (r *UpdateJiraSettingsRequest) Validate() error {
-	if r.JiraDomain == "" {
-		return fmt.Errorf("jira_domain is required")
-	}
-	if r.JiraEmail == "" {
-		return fmt.Errorf("jira_email is required")
-	}
-	if _, err := mail.ParseAddress(r.JiraEmail); err != nil {
-		return fmt.Errorf("invalid jira_email format")
+// Validate checks the batch size; each invitation is validated when it's created
+func (r *BulkInvitationRequest) Validate() error {
+	if len(r.Emails) == 0 {
+		return fmt.Errorf("emails is required")
 	}
-	if r.JiraAPIToken == "" {
-		return fmt.Errorf("jira_api_token is required")
+	if len(r.Emails) > MaxBulkInvitations {
+		return fmt.Errorf("at most %d people can be invited at once", MaxBulkInvitations)
 	}
 	return nil
 }
 
-// JiraIssue represents a Jira issue/task
-type JiraIssue struct {
-	ID          string            `json:"id"`
-	Key         string            `json:"key"`
-	Summary     string            `json:"summary"`
-	Description string            `json:"description,omitempty"`
-	Status      string            `json:"status"`
-	Priority    string            `json:"priority,omitempty"`
-	IssueType   string            `json:"issue_type"`
-	Assignee    *JiraUser         `json:"assignee,omitempty"`
-	Reporter    *JiraUser         `json:"reporter,omitempty"`
-	Project     JiraProject       `json:"project"`
-	Epic        *JiraEpicLink     `json:"epic,omitempty"`
-	Created     time.Time         `json:"created"`
-	Updated     time.Time         `json:"updated"`
-	StartDate   *time.Time        `json:"start_date,omitempty"`
-	DueDate     *time.Time        `json:"due_date,omitempty"`
-	Labels      []string          `json:"labels,omitempty"`
-	URL         string            `json:"url"`
+// Invitation returns the request to invite one of the emails
+func (r *BulkInvitationRequest) Invitation(email string) CreateInvitationRequest {
+	return CreateInvitationRequest{
+		Email:           strings.TrimSpace(email),
+		Role:            r.Role,
+		Department:      r.Department,
+		SquadIDs:        r.SquadIDs,
+		AccessExpiresAt: r.AccessExpiresAt,
+		MeetingIDs:      r.MeetingIDs,
+		Language:        r.Language,
+		SupervisorID:    r.SupervisorID,
+		Title:           r.Title,
+		DateStarted:     r.DateStarted,
+	}
 }
 
-// JiraEpicLink represents a link to a parent epic
-type JiraEpicLink struct {
-	Key     string `json:"key"`
-	Summary string `json:"summary"`
+// BulkInvitationResult is the outcome of inviting one email of a bulk request. Invitations that
+// failed have an error and no invitation.
+type BulkInvitationResult struct {
+	Email      string              `json:"email"`
+	Invitation *InvitationResponse `json:"invitation,omitempty"`
+	Error      string              `json:"error,omitempty"`
 }
 
-// JiraUser represents a Jira user
-type JiraUser struct {
-	AccountID   string `json:"account_id"`
-	DisplayName string `json:"display_name"`
-	Email       string `json:"email,omitempty"`
-	AvatarURL   string `json:"avatar_url,omitempty"`
+// BulkInvitationResponse reports every email of a bulk request in the order they were given.
+// Bulk invitations aren't all-or-nothing: the invitations that succeeded are kept.
+type BulkInvitationResponse struct {
+	Created int                    `json:"created"`
+	Failed  int                    `json:"failed"`
+	Results []BulkInvitationResult `json:"results"`
 }
 
-// JiraProject represents a Jira project
-type JiraProject struct {
-	ID   string `json:"id"`
-	Key  string `json:"key"`
-	Name string `json:"name"`
+// MaxInvitationDomains limits the email domains an organization can restrict invitations to
+const MaxInvitationDomains = 50
+
+// invitationDomainPattern matches a hostname such as example.com
+var invitationDomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// InvitationDomains is the email domains an organization's invitations are limited to. An empty
+// list allows any domain. Guests are external collaborators, so their invitations aren't limited.
+type InvitationDomains struct {
+	Domains []string `json:"domains"`
 }
 
-// DraftStatus represents the status of an org chart draft
-type DraftStatus string
+// UpdateInvitationDomainsRequest replaces an organization's invitation domains; an empty list
+// removes the restriction
+type UpdateInvitationDomainsRequest struct {
+	Domains []string `json:"domains"`
+}
+
+// Validate validates the UpdateInvitationDomainsRequest, normalizing each domain to lower case
+// without a leading @
+func (r *UpdateInvitationDomainsRequest) Validate() error {
+	if len(r.Domains) > MaxInvitationDomains {
+		return fmt.Errorf("domains must have %d items or less", MaxInvitationDomains)
+	}
+	seen := make(map[string]bool, len(r.Domains))
+	domains := make([]string, 0, len(r.Domains))
+	for _, domain := range r.Domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "@")
+		if len(domain) > 253 || !invitationDomainPattern.MatchString(domain) {
+			return fmt.Errorf("invalid domain: %q", domain)
+		}
+		if !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+	r.Domains = domains
+	return nil
+}
+
+// EmailInDomains reports whether email belongs to one of domains. Subdomains don't match, so
+// allowing example.com doesn't allow mail.example.com. Any email matches an empty list.
+func EmailInDomains(email string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	return slices.Contains(domains, strings.ToLower(email[at+1:]))
+}
+
+// AccessRequestStatus represents where an access request is in review
+type AccessRequestStatus string
 
 const (
-	DraftStatusDraft     DraftStatus = "draft"
-	DraftStatusPublished DraftStatus = "published"
-	DraftStatusArchived  DraftStatus = "archived"
+	AccessRequestStatusPending  AccessRequestStatus = "pending"
+	AccessRequestStatusApproved AccessRequestStatus = "approved"
+	AccessRequestStatusRejected AccessRequestStatus = "rejected"
 )
 
-// OrgChartDraft represents a draft of organizational changes
-type OrgChartDraft struct {
-	ID          int64        `json:"id"`
-	Name        string       `json:"name"`
-	Description *string      `json:"description,omitempty"`
-	CreatedByID int64        `json:"created_by_id"`
-	CreatedBy   *User        `json:"created_by,omitempty"`
-	Status      DraftStatus  `json:"status"`
-	PublishedAt *time.Time   `json:"published_at,omitempty"`
-	CreatedAt   time.Time    `json:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at"`
-	Changes     []DraftChange `json:"changes,omitempty"`
+// ValidAccessRequestStatuses contains all valid access request statuses
+var ValidAccessRequestStatuses = map[AccessRequestStatus]bool{
+	AccessRequestStatusPending:  true,
+	AccessRequestStatusApproved: true,
+	AccessRequestStatusRejected: true,
 }
 
-// DraftChange represents a single change within a draft
-type DraftChange struct {
-	ID                   int64     `json:"id"`
-	DraftID              int64     `json:"draft_id"`
-	UserID               int64     `json:"user_id"`
-	User                 *User     `json:"user,omitempty"`
-	OriginalSupervisorID *int64    `json:"original_supervisor_id,omitempty"`
-	OriginalDepartment   *string   `json:"original_department,omitempty"`
-	OriginalRole         *Role     `json:"original_role,omitempty"`
-	OriginalSquadIDs     []int64   `json:"original_squad_ids,omitempty"`
-	NewSupervisorID      *int64    `json:"new_supervisor_id,omitempty"`
-	NewDepartment        *string   `json:"new_department,omitempty"`
-	NewRole              *Role     `json:"new_role,omitempty"`
-	NewSquadIDs          []int64   `json:"new_squad_ids,omitempty"`
-	CreatedAt            time.Time `json:"created_at"`
-	UpdatedAt            time.Time `json:"updated_at"`
+// MaxAccessRequestMessageLength limits the note people leave when requesting access
+const MaxAccessRequestMessageLength = 1000
+
+// AccessRequest is someone who signed in without an account asking to join the organization
+// their email domain belongs to. It waits for an admin, who approves it with the account's role.
+type AccessRequest struct {
+	ID           int64               `json:"id"`
+	OrgID        int64               `json:"org_id"`
+	Auth0ID      string              `json:"-"`
+	Email        string              `json:"email"`
+	FirstName    string              `json:"first_name"`
+	LastName     string              `json:"last_name"`
+	Message      string              `json:"message,omitempty"`
+	Status       AccessRequestStatus `json:"status"`
+	ReviewedByID *int64              `json:"reviewed_by_id,omitempty"`
+	ReviewedAt   *time.Time          `json:"reviewed_at,omitempty"`
+	UserID       *int64              `json:"user_id,omitempty"` // The account made on approval
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
 }
 
-// CreateDraftRequest represents a request to create an org chart draft
-type CreateDraftRequest struct {
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
+// CreateAccessRequestRequest represents a request to join without an invitation. Who is asking
+// comes from their sign-in.
+type CreateAccessRequestRequest struct {
+	Message string `json:"message,omitempty"`
 }
 
-// Validate validates the CreateDraftRequest
-func (r *CreateDraftRequest) Validate() error {
-	r.Name = strings.TrimSpace(r.Name)
-	if r.Name == "" {
-		return fmt.Errorf("name is required")
-	}
-	if len(r.Name) > 255 {
-		return fmt.Errorf("name must be less than 255 characters")
+// Validate validates the CreateAccessRequestRequest
+func (r *CreateAccessRequestRequest) Validate() error {
+	r.Message = strings.TrimSpace(r.Message)
+	if len(r.Message) > MaxAccessRequestMessageLength {
+		return fmt.Errorf("message must be less than %d characters", MaxAccessRequestMessageLength)
 	}
 	return nil
 }
 
-// UpdateDraftRequest represents a request to update an org chart draft
-type UpdateDraftRequest struct {
-	Name        *string `json:"name,omitempty"`
-	Description *string `json:"description,omitempty"`
+// ProfileChangeStatus represents where a profile change request is in review
+type ProfileChangeStatus string
+
+const (
+	ProfileChangeStatusPending  ProfileChangeStatus = "pending"
+	ProfileChangeStatusApproved ProfileChangeStatus = "approved"
+	ProfileChangeStatusRejected ProfileChangeStatus = "rejected"
+)
+
+// ValidProfileChangeStatuses contains all valid profile change request statuses
+var ValidProfileChangeStatuses = map[ProfileChangeStatus]bool{
+	ProfileChangeStatusPending:  true,
+	ProfileChangeStatusApproved: true,
+	ProfileChangeStatusRejected: true,
 }
 
-// Validate validates the UpdateDraftRequest
-func (r *UpdateDraftRequest) Validate() error {
-	if r.Name != nil {
-		*r.Name = strings.TrimSpace(*r.Name)
-		if *r.Name == "" {
-			return fmt.Errorf("name cannot be empty")
-		}
-		if len(*r.Name) > 255 {
-			return fmt.Errorf("name must be less than 255 characters")
-		}
+// ReassignSupervisorRequest moves a user to another supervisor. A null supervisor_id leaves them
+// without one.
+type ReassignSupervisorRequest struct {
+	SupervisorID *int64 `json:"supervisor_id"`
+}
+
+// SupervisorReassignmentResponse is a user after moving them to another supervisor
+type SupervisorReassignmentResponse struct {
+	User                 *UserResponse `json:"user"`
+	PreviousSupervisorID *int64        `json:"previous_supervisor_id,omitempty"`
+	// Things about the move worth double-checking that didn't stop it, such as the supervisor
+	// being in another department
+	Warnings []string `json:"warnings"`
+}
+
+// ProfileChangeRequest is a title or department change a user asked for, which waits for their
+// supervisor, or an admin if they have none. A nil field isn't being changed.
+type ProfileChangeRequest struct {
+	ID           int64               `json:"id"`
+	OrgID        int64               `json:"org_id"`
+	UserID       int64               `json:"user_id"`
+	Title        *string             `json:"title,omitempty"`
+	Department   *string             `json:"department,omitempty"`
+	Status       ProfileChangeStatus `json:"status"`
+	ReviewedByID *int64              `json:"reviewed_by_id,omitempty"`
+	ReviewedAt   *time.Time          `json:"reviewed_at,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	// Who asked, and their title and department now; filled in when listing requests
+	UserName          string `json:"user_name,omitempty"`
+	CurrentTitle      string `json:"current_title,omitempty"`
+	CurrentDepartment string `json:"current_department,omitempty"`
+}
+
+// ApproveAccessRequestRequest sets up the account an approved access request gets. Employees
+// report to the approver unless a supervisor is given.
+type ApproveAccessRequestRequest struct {
+	Role         Role    `json:"role"`
+	Department   string  `json:"department,omitempty"`
+	Title        string  `json:"title,omitempty"`
+	SupervisorID *int64  `json:"supervisor_id,omitempty"`
+	SquadIDs     []int64 `json:"squad_ids,omitempty"`
+}
+
+// Validate validates the ApproveAccessRequestRequest
+func (r *ApproveAccessRequestRequest) Validate() error {
+	if !ValidRoles[r.Role] {
+		return fmt.Errorf("role must be 'admin', 'supervisor', 'employee', or 'viewer'")
+	}
+	r.Department = strings.TrimSpace(r.Department)
+	if len(r.Department) > MaxDepartmentLength {
+		return fmt.Errorf("department must be less than %d characters", MaxDepartmentLength)
+	}
+	r.Title = strings.TrimSpace(r.Title)
+	if len(r.Title) > 255 {
+		return fmt.Errorf("title must be less than 255 characters")
 	}
 	return nil
 }
 
-// AddDraftChangeRequest represents a request to add/update a change in a draft
-type AddDraftChangeRequest struct {
-	UserID          int64   `json:"user_id"`
-	NewSupervisorID *int64  `json:"new_supervisor_id,omitempty"`
-	NewDepartment   *string `json:"new_department,omitempty"`
-	NewRole         *Role   `json:"new_role,omitempty"`
-	NewSquadIDs     []int64 `json:"new_squad_ids,omitempty"`
+// UpdateJiraSettingsRequest represents a request to update Jira settings
+type UpdateJiraSettingsRequest struct {
+	JiraDomain   string `json:"jira_domain"`
+	JiraEmail    string `json:"jira_email"`
+	JiraAPIToken string `json:"jira_api_token"`
 }
 
-// Validate validates the AddDraftChangeRequest
-func (r *AddDraftChangeRequest) Validate() error {
-	if r.UserID <= 0 {
-		return fmt.Errorf("user_id is required")
+// Validate validates the UpdateJiraSettingsRequest
+func (r *UpdateJiraSettingsRequest) Validate() error {
+	if r.JiraDomain == "" {
+		return fmt.Errorf("jira_domain is required")
 	}
-	// At least one change should be specified
-	if r.NewSupervisorID == nil && r.NewDepartment == nil && r.NewRole == nil && len(r.NewSquadIDs) == 0 {
-		return fmt.Errorf("at least one change (supervisor, department, role, or squad) is required")
+	if r.JiraEmail == "" {
+		return fmt.Errorf("jira_email is required")
 	}
-	// Validate role if provided
-	if r.NewRole != nil && !ValidRoles[*r.NewRole] {
-		return fmt.Errorf("invalid role: must be 'admin', 'supervisor', or 'employee'")
+	if _, err := mail.ParseAddress(r.JiraEmail); err != nil {
+		return fmt.Errorf("invalid jira_email format")
 	}
-	// Validate department length if provided
-	if r.NewDepartment != nil && len(*r.NewDepartment) > MaxDepartmentLength {
-		return fmt.Errorf("department must be less than %d characters", MaxDepartmentLength)
+	if r.JiraAPIToken == "" {
+		return fmt.Errorf("jira_api_token is required")
 	}
-	// SquadIDs are validated at the repository level
 	return nil
 }
 
-// OrgTreeNode represents a node in the organization tree
-type OrgTreeNode struct {
-	User          User           `json:"user"`
-	Children      []OrgTreeNode  `json:"children"`
-	PendingChange *DraftChange   `json:"pending_change,omitempty"`
+// JiraIssue represents a Jira issue/task. It's also the shape work items from other
+// trackers, such as Linear, are returned in, so task views don't depend on the tracker.
+type JiraIssue struct {
+	ID             string        `json:"id"`
+	Key            string        `json:"key"`
+	Summary        string        `json:"summary"`
+	Description    string        `json:"description,omitempty"`
+	Status         string        `json:"status"`
+	StatusCategory string        `json:"status_category,omitempty"` // to_do, in_progress, or done
+	Priority       string        `json:"priority,omitempty"`
+	IssueType      string        `json:"issue_type"`
+	Assignee       *JiraUser     `json:"assignee,omitempty"`
+	Reporter       *JiraUser     `json:"reporter,omitempty"`
+	Project        JiraProject   `json:"project"`
+	Epic           *JiraEpicLink `json:"epic,omitempty"`
+	Created        time.Time     `json:"created"`
+	Updated        time.Time     `json:"updated"`
+	StartDate      *time.Time    `json:"start_date,omitempty"`
+	DueDate        *time.Time    `json:"due_date,omitempty"`
+	Labels         []string      `json:"labels,omitempty"`
+	ResolvedAt     *time.Time    `json:"resolved_at,omitempty"`
+	URL            string        `json:"url"`
 }
 
-// ============================================================================
-// Calendar Types
-// ============================================================================
-
-// TaskStatus represents the status of a task
-type TaskStatus string
-
+// Jira status categories, which group each workflow's statuses
 const (
-	TaskStatusPending    TaskStatus = "pending"
-	TaskStatusInProgress TaskStatus = "in_progress"
-	TaskStatusCompleted  TaskStatus = "completed"
-	TaskStatusCancelled  TaskStatus = "cancelled"
+	JiraStatusCategoryToDo       = "to_do"
+	JiraStatusCategoryInProgress = "in_progress"
+	JiraStatusCategoryDone       = "done"
 )
 
-// ValidTaskStatuses contains all valid task status values
-var ValidTaskStatuses = map[TaskStatus]bool{
-	TaskStatusPending:    true,
-	TaskStatusInProgress: true,
-	TaskStatusCompleted:  true,
-	TaskStatusCancelled:  true,
+// JiraEpicLink represents a link to a parent epic
+type JiraEpicLink struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
 }
 
-// AssignmentType represents how a task is assigned
-type AssignmentType string
-
-const (
-	AssignmentTypeUser       AssignmentType = "user"
-	AssignmentTypeSquad      AssignmentType = "squad"
-	AssignmentTypeDepartment AssignmentType = "department"
-)
-
-// ValidAssignmentTypes contains all valid assignment type values
-var ValidAssignmentTypes = map[AssignmentType]bool{
-	AssignmentTypeUser:       true,
-	AssignmentTypeSquad:      true,
-	AssignmentTypeDepartment: true,
+// JiraUser represents a Jira user
+type JiraUser struct {
+	AccountID   string `json:"account_id"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
 }
 
-// RecurrenceType represents meeting recurrence patterns
-type RecurrenceType string
-
-const (
-	RecurrenceTypeDaily   RecurrenceType = "daily"
-	RecurrenceTypeWeekly  RecurrenceType = "weekly"
-	RecurrenceTypeMonthly RecurrenceType = "monthly"
-)
+// JiraProject represents a Jira project
+type JiraProject struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
 
-// ValidRecurrenceTypes contains all valid recurrence type values
-var ValidRecurrenceTypes = map[RecurrenceType]bool{
-	RecurrenceTypeDaily:   true,
-	RecurrenceTypeWeekly:  true,
-	RecurrenceTypeMonthly: true,
+// JiraSprint represents a sprint on a Jira Software board
+type JiraSprint struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	Goal      string     `json:"goal,omitempty"`
+	StartDate *time.Time `json:"start_date,omitempty"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
 }
 
-// ResponseStatus represents meeting invitation response
-type ResponseStatus string
+// JiraSprintIssue represents an issue committed to a sprint, or an open issue counted towards
+// someone's capacity
+type JiraSprintIssue struct {
+	Key               string     `json:"key"`
+	Summary           string     `json:"summary"`
+	Status            string     `json:"status"`
+	AssigneeAccountID *string    `json:"assignee_account_id,omitempty"`
+	StoryPoints       *float64   `json:"story_points,omitempty"`
+	DueDate           *time.Time `json:"due_date,omitempty"`
+}
 
 const (
-	ResponseStatusPending   ResponseStatus = "pending"
-	ResponseStatusAccepted  ResponseStatus = "accepted"
-	ResponseStatusDeclined  ResponseStatus = "declined"
-	ResponseStatusTentative ResponseStatus = "tentative"
+	MaxJiraSummaryLength     = 255
+	MaxJiraDescriptionLength = 32000
 )
 
-// ValidResponseStatuses contains all valid response status values
-var ValidResponseStatuses = map[ResponseStatus]bool{
-	ResponseStatusPending:   true,
-	ResponseStatusAccepted:  true,
-	ResponseStatusDeclined:  true,
-	ResponseStatusTentative: true,
-}
-
-// Task represents a calendar task or event
-type Task struct {
-	ID                 int64          `json:"id"`
-	Title              string         `json:"title"`
-	Description        *string        `json:"description,omitempty"`
-	Status             TaskStatus     `json:"status"`
-	DueDate            time.Time      `json:"due_date"`
-	StartTime          *time.Time     `json:"start_time,omitempty"`
-	EndTime            *time.Time     `json:"end_time,omitempty"`
-	AllDay             bool           `json:"all_day"`
-	CreatedByID        int64          `json:"created_by_id"`
-	CreatedBy          *User          `json:"created_by,omitempty"`
-	AssignmentType     AssignmentType `json:"assignment_type"`
-	AssignedUserID     *int64         `json:"assigned_user_id,omitempty"`
-	AssignedUser       *User          `json:"assigned_user,omitempty"`
-	AssignedSquadID    *int64         `json:"assigned_squad_id,omitempty"`
-	AssignedSquad      *Squad         `json:"assigned_squad,omitempty"`
-	AssignedDepartment *string        `json:"assigned_department,omitempty"`
-	CreatedAt          time.Time      `json:"created_at"`
-	UpdatedAt          time.Time      `json:"updated_at"`
+// isJiraProjectKey reports whether s looks like a Jira project key such as "PROJ" or "WEB2"
+func isJiraProjectKey(s string) bool {
+	if s == "" || len(s) > 10 || s[0] < 'A' || s[0] > 'Z' {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '_' {
+			return false
+		}
+	}
+	return true
 }
 
-// CreateTaskRequest represents a request to create a task or event
-type CreateTaskRequest struct {
-	Title              string         `json:"title"`
-	Description        *string        `json:"description,omitempty"`
-	DueDate            time.Time      `json:"due_date"`
-	StartTime          *time.Time     `json:"start_time,omitempty"`
-	EndTime            *time.Time     `json:"end_time,omitempty"`
-	AllDay             *bool          `json:"all_day,omitempty"`
-	AssignmentType     AssignmentType `json:"assignment_type"`
-	AssignedUserID     *int64         `json:"assigned_user_id,omitempty"`
-	AssignedSquadID    *int64         `json:"assigned_squad_id,omitempty"`
-	AssignedDepartment *string        `json:"assigned_department,omitempty"`
+// CreateJiraIssueRequest represents a request to create a Jira issue from the dashboard
+type CreateJiraIssueRequest struct {
+	ProjectKey  string `json:"project_key"`
+	IssueType   string `json:"issue_type"` // Defaults to "Task"
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	AssigneeID  *int64 `json:"assignee_id,omitempty"` // Dashboard user; must be linked to a Jira account
 }
 
-// Validate validates the CreateTaskRequest
-func (r *CreateTaskRequest) Validate() error {
-	r.Title = strings.TrimSpace(r.Title)
-	if r.Title == "" {
-		return fmt.Errorf("title is required")
+// Validate validates the CreateJiraIssueRequest
+func (r *CreateJiraIssueRequest) Validate() error {
+	r.ProjectKey = strings.ToUpper(strings.TrimSpace(r.ProjectKey))
+	if !isJiraProjectKey(r.ProjectKey) {
+		return fmt.Errorf("project_key must be a valid Jira project key")
 	}
-	if len(r.Title) > 255 {
-		return fmt.Errorf("title must be less than 255 characters")
+	r.IssueType = strings.TrimSpace(r.IssueType)
+	if r.IssueType == "" {
+		r.IssueType = "Task"
 	}
-	if r.DueDate.IsZero() {
-		return fmt.Errorf("due_date is required")
+	if len(r.IssueType) > 100 {
+		return fmt.Errorf("issue_type must be less than 100 characters")
 	}
-	if !ValidAssignmentTypes[r.AssignmentType] {
-		return fmt.Errorf("invalid assignment_type: must be 'user', 'squad', or 'department'")
+	r.Summary = strings.TrimSpace(r.Summary)
+	if r.Summary == "" {
+		return fmt.Errorf("summary is required")
 	}
-	// Validate assignment based on type
-	switch r.AssignmentType {
-	case AssignmentTypeUser:
-		if r.AssignedUserID == nil {
-			return fmt.Errorf("assigned_user_id is required when assignment_type is 'user'")
-		}
-	case AssignmentTypeSquad:
-		if r.AssignedSquadID == nil {
-			return fmt.Errorf("assigned_squad_id is required when assignment_type is 'squad'")
-		}
-	case AssignmentTypeDepartment:
-		if r.AssignedDepartment == nil || *r.AssignedDepartment == "" {
-			return fmt.Errorf("assigned_department is required when assignment_type is 'department'")
-		}
+	if strings.ContainsAny(r.Summary, "\r\n") {
+		return fmt.Errorf("summary must be a single line")
+	}
+	if len(r.Summary) > MaxJiraSummaryLength {
+		return fmt.Errorf("summary must be less than %d characters", MaxJiraSummaryLength)
+	}
+	if len(r.Description) > MaxJiraDescriptionLength {
+		return fmt.Errorf("description must be less than %d characters", MaxJiraDescriptionLength)
+	}
+	if r.AssigneeID != nil && *r.AssigneeID <= 0 {
+		return fmt.Errorf("assignee_id must be positive")
 	}
 	return nil
 }
 
-// UpdateTaskRequest represents a request to update a task or event
-type UpdateTaskRequest struct {
-	Title              *string         `json:"title,omitempty"`
-	Description        *string         `json:"description,omitempty"`
-	Status             *TaskStatus     `json:"status,omitempty"`
-	DueDate            *time.Time      `json:"due_date,omitempty"`
-	StartTime          *time.Time      `json:"start_time,omitempty"`
-	EndTime            *time.Time      `json:"end_time,omitempty"`
-	AllDay             *bool           `json:"all_day,omitempty"`
-	AssignmentType     *AssignmentType `json:"assignment_type,omitempty"`
-	AssignedUserID     *int64          `json:"assigned_user_id,omitempty"`
-	AssignedSquadID    *int64          `json:"assigned_squad_id,omitempty"`
-	AssignedDepartment *string         `json:"assigned_department,omitempty"`
+// CreatedJiraIssue identifies an issue created through the dashboard
+type CreatedJiraIssue struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+	URL string `json:"url"`
 }
 
-// Validate validates the UpdateTaskRequest
-func (r *UpdateTaskRequest) Validate() error {
-	if r.Title != nil {
+// IsJiraIssueKey reports whether s looks like a Jira issue key such as "WEB-42"
+func IsJiraIssueKey(s string) bool {
+	i := strings.LastIndexByte(s, '-')
+	if i <= 0 || i == len(s)-1 {
+		return false
+	}
+	for _, c := range s[i+1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return isJiraProjectKey(s[:i])
+}
+
+// JiraTransition is a workflow transition currently available on an issue
+type JiraTransition struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ToStatus string `json:"to_status"`
+}
+
+// TransitionJiraIssueRequest represents a request to move an issue through its workflow
+type TransitionJiraIssueRequest struct {
+	TransitionID string `json:"transition_id"`
+}
+
+// Validate validates the TransitionJiraIssueRequest
+func (r *TransitionJiraIssueRequest) Validate() error {
+	r.TransitionID = strings.TrimSpace(r.TransitionID)
+	if r.TransitionID == "" {
+		return fmt.Errorf("transition_id is required")
+	}
+	if _, err := strconv.Atoi(r.TransitionID); err != nil {
+		return fmt.Errorf("transition_id must be numeric")
+	}
+	return nil
+}
+
+// MaxJiraCommentLength caps comments posted through the dashboard
+const MaxJiraCommentLength = 32000
+
+// AddJiraCommentRequest represents a request to comment on an issue
+type AddJiraCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// Validate validates the AddJiraCommentRequest
+func (r *AddJiraCommentRequest) Validate() error {
+	r.Body = strings.TrimSpace(r.Body)
+	if r.Body == "" {
+		return fmt.Errorf("body is required")
+	}
+	if len(r.Body) > MaxJiraCommentLength {
+		return fmt.Errorf("body must be less than %d characters", MaxJiraCommentLength)
+	}
+	return nil
+}
+
+// JiraComment is a comment on a Jira issue
+type JiraComment struct {
+	ID      string    `json:"id"`
+	Body    string    `json:"body"`
+	Author  *JiraUser `json:"author,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// EpicProgress rolls up an epic's child issues and forecasts when the rest will be done
+type EpicProgress struct {
+	Epic                JiraIssue              `json:"epic"`
+	TotalIssues         int                    `json:"total_issues"`
+	ToDoIssues          int                    `json:"to_do_issues"`
+	InProgressIssues    int                    `json:"in_progress_issues"`
+	DoneIssues          int                    `json:"done_issues"`
+	PercentComplete     float64                `json:"percent_complete"`
+	StatusCounts        []EpicStatusCount      `json:"status_counts"`
+	Assignees           []EpicAssigneeProgress `json:"assignees"`
+	ThroughputPerDay    float64                `json:"throughput_per_day"` // Issues resolved per working day recently
+	EstimatedCompletion *time.Time             `json:"estimated_completion,omitempty"`
+	TimeOffDays         int                    `json:"time_off_days"`      // Contributor days off before the estimate
+	OnTrack             *bool                  `json:"on_track,omitempty"` // Set when the epic has a due date and an estimate
+	Warnings            []string               `json:"warnings"`
+}
+
+// EpicStatusCount is how many of an epic's issues are in one status
+type EpicStatusCount struct {
+	Status   string `json:"status"`
+	Category string `json:"category,omitempty"`
+	Count    int    `json:"count"`
+}
+
+// EpicAssigneeProgress is one assignee's share of an epic. Unassigned issues have no account ID.
+type EpicAssigneeProgress struct {
+	AccountID     string         `json:"account_id,omitempty"`
+	DisplayName   string         `json:"display_name"`
+	UserID        *int64         `json:"user_id,omitempty"`
+	TotalIssues   int            `json:"total_issues"`
+	DoneIssues    int            `json:"done_issues"`
+	Remaining     int            `json:"remaining"`
+	TimeOffImpact *TimeOffImpact `json:"time_off_impact,omitempty"`
+}
+
+// Limits for admin-defined Jira task filters
+const (
+	MaxJiraTaskFilters       = 20
+	MaxJiraTaskFilterNameLen = 50
+	MaxJiraTaskFilterJQLLen  = 1000
+)
+
+// JiraTaskFilter is a named JQL clause that narrows the team task view, e.g. "status != Done".
+// It's combined with the assignee and unresolved conditions, so it can't order results itself.
+type JiraTaskFilter struct {
+	Name string `json:"name"`
+	JQL  string `json:"jql"`
+}
+
+// JiraUserMatchReport is the outcome of matching employees to Jira accounts by email.
+// It's kept so admins can finish mapping by hand without rescanning every Jira user.
+type JiraUserMatchReport struct {
+	GeneratedAt        time.Time               `json:"generated_at"`
+	Matched            int                     `json:"matched"` // Newly matched by email in this run
+	TotalEmployees     int                     `json:"total_employees"`
+	TotalJiraUsers     int                     `json:"total_jira_users"`
+	UnmatchedEmployees []JiraUnmatchedEmployee `json:"unmatched_employees"`
+	UnmatchedJiraUsers []JiraUser              `json:"unmatched_jira_users"` // Accounts no employee is mapped to
+}
+
+// JiraUnmatchedEmployee is an active employee with no Jira account, with likely accounts by name
+type JiraUnmatchedEmployee struct {
+	ID          int64                     `json:"id"`
+	FirstName   string                    `json:"first_name"`
+	LastName    string                    `json:"last_name"`
+	Email       string                    `json:"email"`
+	Suggestions []JiraUserMatchSuggestion `json:"suggestions"`
+}
+
+// JiraUserMatchSuggestion is an unmatched Jira account whose name resembles an employee's
+type JiraUserMatchSuggestion struct {
+	JiraUser
+	Score float64 `json:"score"` // Name similarity from 0 to 1
+}
+
+// UpdateJiraTaskFiltersRequest replaces the org's Jira task filters
+type UpdateJiraTaskFiltersRequest struct {
+	Filters []JiraTaskFilter `json:"filters"`
+}
+
+// Validate validates the UpdateJiraTaskFiltersRequest
+func (r *UpdateJiraTaskFiltersRequest) Validate() error {
+	if len(r.Filters) > MaxJiraTaskFilters {
+		return fmt.Errorf("at most %d filters are allowed", MaxJiraTaskFilters)
+	}
+	seen := make(map[string]bool, len(r.Filters))
+	for i := range r.Filters {
+		f := &r.Filters[i]
+		f.Name = strings.TrimSpace(f.Name)
+		f.JQL = strings.TrimSpace(f.JQL)
+		if f.Name == "" {
+			return fmt.Errorf("filter name is required")
+		}
+		if len(f.Name) > MaxJiraTaskFilterNameLen {
+			return fmt.Errorf("filter name must be %d characters or less", MaxJiraTaskFilterNameLen)
+		}
+		if seen[strings.ToLower(f.Name)] {
+			return fmt.Errorf("filter %q is defined more than once", f.Name)
+		}
+		seen[strings.ToLower(f.Name)] = true
+		if f.JQL == "" {
+			return fmt.Errorf("filter %q needs a JQL clause", f.Name)
+		}
+		if len(f.JQL) > MaxJiraTaskFilterJQLLen {
+			return fmt.Errorf("filter %q JQL must be %d characters or less", f.Name, MaxJiraTaskFilterJQLLen)
+		}
+		if strings.Contains(strings.ToLower(f.JQL), "order by") {
+			return fmt.Errorf("filter %q can't include ORDER BY", f.Name)
+		}
+	}
+	return nil
+}
+
+// JiraWorklog is time an account logged against a Jira issue
+type JiraWorklog struct {
+	ID               string    `json:"id"`
+	IssueKey         string    `json:"issue_key"`
+	IssueSummary     string    `json:"issue_summary"`
+	Started          time.Time `json:"started"`
+	TimeSpentSeconds int       `json:"time_spent_seconds"`
+}
+
+// MaxWorklogWindowDays caps the date range a single worklog summary may span
+const MaxWorklogWindowDays = 92
+
+// WorklogSummary compares an employee's logged Jira time with their capacity over a date range
+type WorklogSummary struct {
+	UserID             int64               `json:"user_id"`
+	FirstName          string              `json:"first_name"`
+	LastName           string              `json:"last_name"`
+	Start              string              `json:"start"` // YYYY-MM-DD, inclusive
+	End                string              `json:"end"`   // YYYY-MM-DD, inclusive
+	HoursPerDay        float64             `json:"hours_per_day"`
+	WorkingDays        int                 `json:"working_days"`
+	TimeOffDays        int                 `json:"time_off_days"`
+	CapacityHours      float64             `json:"capacity_hours"`
+	LoggedHours        float64             `json:"logged_hours"`
+	UtilizationPercent float64             `json:"utilization_percent"`
+	Issues             []IssueWorklogTotal `json:"issues"`
+	Days               []DailyWorklogTotal `json:"days"`
+}
+
+// IssueWorklogTotal is the time logged against one issue in a worklog summary
+type IssueWorklogTotal struct {
+	IssueKey     string  `json:"issue_key"`
+	IssueSummary string  `json:"issue_summary"`
+	Hours        float64 `json:"hours"`
+}
+
+// DailyWorklogTotal is the time logged and available on one day of a worklog summary
+type DailyWorklogTotal struct {
+	Date          string  `json:"date"` // YYYY-MM-DD
+	LoggedHours   float64 `json:"logged_hours"`
+	CapacityHours float64 `json:"capacity_hours"`
+	Holiday       bool    `json:"holiday,omitempty"`
+	TimeOff       bool    `json:"time_off,omitempty"`
+}
+
+// SprintCapacityCheckRequest represents a request to compare sprint scope against squad capacity
+type SprintCapacityCheckRequest struct {
+	SquadID      int64    `json:"squad_id"`
+	BoardID      *int     `json:"board_id,omitempty"`
+	SprintID     *int     `json:"sprint_id,omitempty"`
+	PointsPerDay *float64 `json:"points_per_day,omitempty"`
+	NotifySlack  bool     `json:"notify_slack"`
+}
+
+// Validate validates the SprintCapacityCheckRequest
+func (r *SprintCapacityCheckRequest) Validate() error {
+	if r.SquadID <= 0 {
+		return fmt.Errorf("squad_id is required")
+	}
+	if r.BoardID == nil && r.SprintID == nil {
+		return fmt.Errorf("board_id or sprint_id is required")
+	}
+	if r.BoardID != nil && *r.BoardID <= 0 {
+		return fmt.Errorf("board_id must be positive")
+	}
+	if r.SprintID != nil && *r.SprintID <= 0 {
+		return fmt.Errorf("sprint_id must be positive")
+	}
+	if r.PointsPerDay != nil && *r.PointsPerDay <= 0 {
+		return fmt.Errorf("points_per_day must be greater than 0")
+	}
+	return nil
+}
+
+// MemberSprintCapacity represents one squad member's availability and committed work for a sprint
+type MemberSprintCapacity struct {
+	UserID          int64   `json:"user_id"`
+	FirstName       string  `json:"first_name"`
+	LastName        string  `json:"last_name"`
+	JiraAccountID   *string `json:"jira_account_id,omitempty"`
+	AvailableDays   int     `json:"available_days"`
+	TimeOffDays     int     `json:"time_off_days"`
+	CapacityPoints  float64 `json:"capacity_points"`
+	CommittedPoints float64 `json:"committed_points"`
+	OverCommitted   bool    `json:"over_committed"`
+}
+
+// SprintCapacityReport compares a sprint's committed scope with the squad's computed capacity
+type SprintCapacityReport struct {
+	Sprint            JiraSprint             `json:"sprint"`
+	SquadID           int64                  `json:"squad_id"`
+	SquadName         string                 `json:"squad_name"`
+	WorkingDays       int                    `json:"working_days"`
+	Holidays          []string               `json:"holidays"`
+	PointsPerDay      float64                `json:"points_per_day"`
+	Members           []MemberSprintCapacity `json:"members"`
+	CapacityDays      int                    `json:"capacity_days"`
+	CapacityPoints    float64                `json:"capacity_points"`
+	CommittedPoints   float64                `json:"committed_points"`
+	UnestimatedIssues int                    `json:"unestimated_issues"`
+	UnassignedPoints  float64                `json:"unassigned_points"`
+	LoadPercent       float64                `json:"load_percent"`
+	OverCommitted     bool                   `json:"over_committed"`
+	Warnings          []string               `json:"warnings"`
+	SlackNotified     bool                   `json:"slack_notified"`
+}
+
+// MaxCapacityWindowDays caps the date range a capacity plan may span
+const MaxCapacityWindowDays = 92
+
+// PersonCapacity compares the business days someone is available over a date range with the
+// estimates of the open Jira issues assigned to them and due by its end
+type PersonCapacity struct {
+	UserID            int64             `json:"user_id"`
+	FirstName         string            `json:"first_name"`
+	LastName          string            `json:"last_name"`
+	JiraAccountID     *string           `json:"jira_account_id,omitempty"`
+	AvailableDays     int               `json:"available_days"`
+	TimeOffDays       int               `json:"time_off_days"`
+	CapacityPoints    float64           `json:"capacity_points"`
+	AssignedPoints    float64           `json:"assigned_points"`
+	AssignedDays      float64           `json:"assigned_days"` // Assigned points at the plan's points per day
+	UnestimatedIssues int               `json:"unestimated_issues"`
+	LoadPercent       float64           `json:"load_percent"`
+	Overallocated     bool              `json:"overallocated"`
+	Issues            []JiraSprintIssue `json:"issues"`
+}
+
+// CapacityPlan is the capacity of a squad, or of a supervisor's team, over a date range. Start and
+// End are inclusive YYYY-MM-DD dates.
+type CapacityPlan struct {
+	SquadID       *int64           `json:"squad_id,omitempty"`
+	SquadName     string           `json:"squad_name,omitempty"`
+	Start         string           `json:"start"`
+	End           string           `json:"end"`
+	WorkingDays   int              `json:"working_days"`
+	Holidays      []string         `json:"holidays"`
+	PointsPerDay  float64          `json:"points_per_day"`
+	People        []PersonCapacity `json:"people"`
+	Overallocated int              `json:"overallocated"` // How many people are overallocated
+	Warnings      []string         `json:"warnings"`
+}
+
+// DraftStatus represents the status of an org chart draft
+type DraftStatus string
+
+const (
+	DraftStatusDraft     DraftStatus = "draft"
+	DraftStatusPublished DraftStatus = "published"
+	DraftStatusArchived  DraftStatus = "archived"
+)
+
+// OrgChartDraft represents a draft of organizational changes
+
+type OrgChartDraft struct {
+	ID           int64         `json:"id"`
+	Name         string        `json:"name"`
+	Description  *string       `json:"description,omitempty"`
+	CreatedByID  int64         `json:"created_by_id"`
+	CreatedBy    *User         `json:"created_by,omitempty"`
+	Status       DraftStatus   `json:"status"`
+	PublishedAt  *time.Time    `json:"published_at,omitempty"`
+	ApprovedByID *int64        `json:"approved_by_id,omitempty"`
+	ApprovedAt   *time.Time    `json:"approved_at,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	Changes      []DraftChange `json:"changes,omitempty"`
+}
+
+// DraftChange represents a single change within a draft
+type DraftChange struct {
+	ID                   int64     `json:"id"`
+	DraftID              int64     `json:"draft_id"`
+	UserID               int64     `json:"user_id"`
+	User                 *User     `json:"user,omitempty"`
+	OriginalSupervisorID *int64    `json:"original_supervisor_id,omitempty"`
+	OriginalDepartment   *string   `json:"original_department,omitempty"`
+	OriginalRole         *Role     `json:"original_role,omitempty"`
+	OriginalSquadIDs     []int64   `json:"original_squad_ids,omitempty"`
+	NewSupervisorID      *int64    `json:"new_supervisor_id,omitempty"`
+	NewDepartment        *string   `json:"new_department,omitempty"`
+	NewRole              *Role     `json:"new_role,omitempty"`
+	NewSquadIDs          []int64   `json:"new_squad_ids,omitempty"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// OrgChartSnapshot is an immutable copy of the full reporting tree taken when a draft was published.
+// Tree is left out of history listings.
+type OrgChartSnapshot struct {
+	ID            int64         `json:"id"`
+	DraftID       *int64        `json:"draft_id,omitempty"`
+	DraftName     string        `json:"draft_name"`
+	PublishedByID *int64        `json:"published_by_id,omitempty"`
+	Headcount     int           `json:"headcount"`
+	Tree          []OrgTreeNode `json:"tree,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// OrgChartProblemKind identifies something wrong with the org tree a draft would produce
+type OrgChartProblemKind string
+
+const (
+	OrgChartProblemCycle             OrgChartProblemKind = "cycle"              // A supervisor chain loops back on itself
+	OrgChartProblemOrphanedReport    OrgChartProblemKind = "orphaned_report"    // A user would report to someone outside the org
+	OrgChartProblemDemotedSupervisor OrgChartProblemKind = "demoted_supervisor" // A supervisor would lose the role while still having reports
+)
+
+// OrgChartProblem is a validation problem found in the org tree a draft would produce.
+// For cycles, UserIDs lists the chain in reporting order.
+type OrgChartProblem struct {
+	Kind    OrgChartProblemKind `json:"kind"`
+	UserIDs []int64             `json:"user_ids"`
+	Message string              `json:"message"`
+}
+
+// OrgChartPlacement is where a user sits in the org
+type OrgChartPlacement struct {
+	SupervisorID *int64  `json:"supervisor_id,omitempty"`
+	Department   string  `json:"department"`
+	Role         Role    `json:"role"`
+	SquadIDs     []int64 `json:"squad_ids"`
+}
+
+// OrgChartUserDiff shows how a draft would move one user, naming the fields that change
+type OrgChartUserDiff struct {
+	UserID  int64             `json:"user_id"`
+	Name    string            `json:"name"`
+	Before  OrgChartPlacement `json:"before"`
+	After   OrgChartPlacement `json:"after"`
+	Changed []string          `json:"changed"`
+}
+
+// OrgChartDraftPreview shows the org tree a draft would produce before it's published
+type OrgChartDraftPreview struct {
+	DraftID  int64              `json:"draft_id"`
+	Tree     []OrgTreeNode      `json:"tree"`
+	Diff     []OrgChartUserDiff `json:"diff"`
+	Problems []OrgChartProblem  `json:"problems"`
+	Valid    bool               `json:"valid"`
+}
+
+// DraftPublishError explains why a draft couldn't be published. Publishing is all-or-nothing, so
+// none of the draft's changes were applied. ChangeID and UserID identify the change at fault.
+type DraftPublishError struct {
+	ChangeID *int64            `json:"change_id,omitempty"`
+	UserID   *int64            `json:"user_id,omitempty"`
+	Message  string            `json:"error"`
+	Problems []OrgChartProblem `json:"problems,omitempty"`
+	Err      error             `json:"-"`
+}
+
+// Error implements the error interface
+func (e *DraftPublishError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+// Unwrap returns the underlying error for errors.Is/As support
+func (e *DraftPublishError) Unwrap() error {
+	return e.Err
+}
+
+// NewDraftProblemsError reports the problems a draft would cause, blaming the change to the
+// first user named by the first problem that has one
+func NewDraftProblemsError(changes []DraftChange, problems []OrgChartProblem) *DraftPublishError {
+	err := &DraftPublishError{Message: problems[0].Message, Problems: problems}
+	for _, p := range problems {
+		for _, userID := range p.UserIDs {
+			for _, c := range changes {
+				if c.UserID == userID {
+					err.ChangeID, err.UserID = &c.ID, &c.UserID
+					err.Message = p.Message
+					return err
+				}
+			}
+		}
+	}
+	return err
+}
+
+// BuildOrgTrees arranges users into trees under those without a supervisor in the list. Users
+// in a reporting loop have no root and are left out. Nodes for users in pending carry their change.
+func BuildOrgTrees(users []User, pending map[int64]*DraftChange) []OrgTreeNode {
+	present := make(map[int64]bool, len(users))
+	for _, u := range users {
+		present[u.ID] = true
+	}
+	children := make(map[int64][]User)
+	var roots []User
+	for _, u := range users {
+		if u.SupervisorID == nil || !present[*u.SupervisorID] {
+			roots = append(roots, u)
+		} else {
+			children[*u.SupervisorID] = append(children[*u.SupervisorID], u)
+		}
+	}
+
+	var build func(u User) OrgTreeNode
+	build = func(u User) OrgTreeNode {
+		node := OrgTreeNode{User: u, Children: []OrgTreeNode{}, PendingChange: pending[u.ID]}
+		for _, child := range children[u.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	trees := make([]OrgTreeNode, 0, len(roots))
+	for _, root := range roots {
+		trees = append(trees, build(root))
+	}
+	return trees
+}
+
+// ApplyDraftChanges returns a copy of members with each change applied. Unset fields of a change
+// are left as they are, and changes to users who aren't members are ignored.
+func ApplyDraftChanges(members []User, changes []DraftChange) []User {
+	result := make([]User, len(members))
+	copy(result, members)
+	index := make(map[int64]int, len(result))
+	for i := range result {
+		index[result[i].ID] = i
+	}
+
+	for _, c := range changes {
+		i, ok := index[c.UserID]
+		if !ok {
+			continue
+		}
+		if c.NewSupervisorID != nil {
+			supervisorID := *c.NewSupervisorID
+			result[i].SupervisorID = &supervisorID
+		}
+		if c.NewDepartment != nil {
+			result[i].Department = *c.NewDepartment
+		}
+		if c.NewRole != nil {
+			result[i].Role = *c.NewRole
+		}
+	}
+	return result
+}
+
+// FindOrgChartProblems compares the org before and after a draft is applied and returns the
+// cycles, orphaned reports, and demoted supervisors the draft would cause. Both slices hold the
+// same members in the same order.
+func FindOrgChartProblems(before, after []User) []OrgChartProblem {
+	problems := []OrgChartProblem{}
+	byID := make(map[int64]*User, len(after))
+	for i := range after {
+		byID[after[i].ID] = &after[i]
+	}
+
+	for _, cycle := range findSupervisorCycles(after, byID) {
+		names := make([]string, 0, len(cycle)+1)
+		for _, id := range cycle {
+			names = append(names, byID[id].fullName())
+		}
+		names = append(names, names[0])
+		problems = append(problems, OrgChartProblem{
+			Kind:    OrgChartProblemCycle,
+			UserIDs: cycle,
+			Message: "Reporting chain loops back on itself: " + strings.Join(names, " → "),
+		})
+	}
+
+	reports := make(map[int64]int)
+	for _, u := range after {
+		if u.SupervisorID != nil {
+			reports[*u.SupervisorID]++
+		}
+	}
+
+	for i := range after {
+		was, now := &before[i], &after[i]
+		if now.SupervisorID != nil && !sameSupervisor(was.SupervisorID, now.SupervisorID) {
+			if _, ok := byID[*now.SupervisorID]; !ok {
+				problems = append(problems, OrgChartProblem{
+					Kind:    OrgChartProblemOrphanedReport,
+					UserIDs: []int64{now.ID},
+					Message: fmt.Sprintf("%s would report to user %d, who isn't an active member of the org", now.fullName(), *now.SupervisorID),
+				})
+			}
+		}
+		if was.IsSupervisorOrAdmin() && !now.IsSupervisorOrAdmin() && reports[now.ID] > 0 {
+			problems = append(problems, OrgChartProblem{
+				Kind:    OrgChartProblemDemotedSupervisor,
+				UserIDs: []int64{now.ID},
+				Message: fmt.Sprintf("%s would become %s but still have %d direct report(s)", now.fullName(), now.Role, reports[now.ID]),
+			})
+		}
+	}
+	return problems
+}
+
+// findSupervisorCycles returns each loop in the supervisor chains once, in reporting order
+func findSupervisorCycles(users []User, byID map[int64]*User) [][]int64 {
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[int64]int, len(users))
+	var cycles [][]int64
+
+	for _, u := range users {
+		var path []int64
+		id := u.ID
+		for {
+			if state[id] == done {
+				break
+			}
+			if state[id] == onPath {
+				for i, pathID := range path {
+					if pathID == id {
+						cycles = append(cycles, append([]int64(nil), path[i:]...))
+						break
+					}
+				}
+				break
+			}
+			state[id] = onPath
+			path = append(path, id)
+
+			next, ok := byID[id]
+			if !ok || next.SupervisorID == nil {
+				break
+			}
+			if _, ok := byID[*next.SupervisorID]; !ok {
+				break
+			}
+			id = *next.SupervisorID
+		}
+		for _, pathID := range path {
+			state[pathID] = done
+		}
+	}
+	return cycles
+}
+
+// sameSupervisor reports whether two optional supervisor IDs are equal
+func sameSupervisor(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// fullName returns the user's first and last name for messages
+func (u *User) fullName() string {
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
+
+// DraftPermission is what a user a draft is shared with may do with it
+type DraftPermission string
+
+const (
+	DraftPermissionViewer DraftPermission = "viewer" // View the draft and comment on its changes
+	DraftPermissionEditor DraftPermission = "editor" // Also change the draft and approve it
+)
+
+// DraftShare grants a supervisor or admin access to another user's draft
+type DraftShare struct {
+	DraftID    int64           `json:"draft_id"`
+	UserID     int64           `json:"user_id"`
+	Permission DraftPermission `json:"permission"`
+	SharedByID *int64          `json:"shared_by_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// ShareDraftRequest represents a request to share a draft or change a share's permission
+type ShareDraftRequest struct {
+	UserID     int64           `json:"user_id"`
+	Permission DraftPermission `json:"permission"`
+}
+
+// Validate validates the ShareDraftRequest
+func (r *ShareDraftRequest) Validate() error {
+	if r.UserID <= 0 {
+		return fmt.Errorf("user_id is required")
+	}
+	if r.Permission != DraftPermissionViewer && r.Permission != DraftPermissionEditor {
+		return fmt.Errorf("permission must be 'viewer' or 'editor'")
+	}
+	return nil
+}
+
+// MaxDraftCommentLength is the maximum length of a comment on a draft change
+const MaxDraftCommentLength = 5000
+
+// DraftComment is a review comment on one change in a draft
+type DraftComment struct {
+	ID        int64     `json:"id"`
+	DraftID   int64     `json:"draft_id"`
+	ChangeID  int64     `json:"change_id"`
+	AuthorID  int64     `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateDraftCommentRequest represents a request to comment on a draft change
+type CreateDraftCommentRequest struct {
+	ChangeID int64  `json:"change_id"`
+	Body     string `json:"body"`
+}
+
+// Validate validates the CreateDraftCommentRequest
+func (r *CreateDraftCommentRequest) Validate() error {
+	if r.ChangeID <= 0 {
+		return fmt.Errorf("change_id is required")
+	}
+	r.Body = strings.TrimSpace(r.Body)
+	if r.Body == "" {
+		return fmt.Errorf("body is required")
+	}
+	if len(r.Body) > MaxDraftCommentLength {
+		return fmt.Errorf("body must be less than %d characters", MaxDraftCommentLength)
+	}
+	return nil
+}
+
+// CreateDraftRequest represents a request to create an org chart draft
+type CreateDraftRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+// Validate validates the CreateDraftRequest
+func (r *CreateDraftRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(r.Name) > 255 {
+		return fmt.Errorf("name must be less than 255 characters")
+	}
+	return nil
+}
+
+// UpdateDraftRequest represents a request to update an org chart draft
+type UpdateDraftRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// Validate validates the UpdateDraftRequest
+func (r *UpdateDraftRequest) Validate() error {
+	if r.Name != nil {
+		*r.Name = strings.TrimSpace(*r.Name)
+		if *r.Name == "" {
+			return fmt.Errorf("name cannot be empty")
+		}
+		if len(*r.Name) > 255 {
+			return fmt.Errorf("name must be less than 255 characters")
+		}
+	}
+	return nil
+}
+
+// AddDraftChangeRequest represents a request to add/update a change in a draft
+type AddDraftChangeRequest struct {
+	UserID          int64   `json:"user_id"`
+	NewSupervisorID *int64  `json:"new_supervisor_id,omitempty"`
+	NewDepartment   *string `json:"new_department,omitempty"`
+	NewRole         *Role   `json:"new_role,omitempty"`
+	NewSquadIDs     []int64 `json:"new_squad_ids,omitempty"`
+}
+
+// Validate validates the AddDraftChangeRequest
+func (r *AddDraftChangeRequest) Validate() error {
+	if r.UserID <= 0 {
+		return fmt.Errorf("user_id is required")
+	}
+	// At least one change should be specified
+	if r.NewSupervisorID == nil && r.NewDepartment == nil && r.NewRole == nil && len(r.NewSquadIDs) == 0 {
+		return fmt.Errorf("at least one change (supervisor, department, role, or squad) is required")
+	}
+	// Validate role if provided
+	if r.NewRole != nil && !ValidRoles[*r.NewRole] {
+		return fmt.Errorf("invalid role: must be 'admin', 'supervisor', or 'employee'")
+	}
+	// Validate department length if provided
+	if r.NewDepartment != nil && len(*r.NewDepartment) > MaxDepartmentLength {
+		return fmt.Errorf("department must be less than %d characters", MaxDepartmentLength)
+	}
+	// SquadIDs are validated at the repository level
+	return nil
+}
+
+// OrgTreeNode represents a node in the organization tree
+type OrgTreeNode struct {
+	User          User            `json:"user"`
+	Children      []OrgTreeNode   `json:"children"`
+	PendingChange *DraftChange    `json:"pending_change,omitempty"`
+	Metrics       *OrgTreeMetrics `json:"metrics,omitempty"`
+	// The user has reports that weren't loaded because of the depth limit. Load them from
+	// /orgchart/tree/{userId}/children.
+	ChildrenTruncated bool `json:"children_truncated,omitempty"`
+}
+
+// OrgTreeScope limits how much of the org tree is loaded
+type OrgTreeScope struct {
+	// Start from this user instead of everyone at the top of the org
+	RootID *int64
+	// Levels of reports to load below the roots; nil loads all of them
+	Depth *int
+	// Also load deactivated users
+	IncludeInactive bool
+}
+
+// OrgChartExportFormat is the file format of an org chart export
+type OrgChartExportFormat string
+
+const (
+	OrgChartExportCSV  OrgChartExportFormat = "csv"  // Flattened reporting list
+	OrgChartExportJSON OrgChartExportFormat = "json" // Flattened reporting list
+	OrgChartExportDOT  OrgChartExportFormat = "dot"  // Graphviz graph of the tree
+)
+
+// OrgChartExportRow is one person in a flattened org chart export. Level is 0 for the top of a tree,
+// and ReportingChain names everyone above them from the top down.
+type OrgChartExportRow struct {
+	ID             int64    `json:"id"`
+	FirstName      string   `json:"first_name"`
+	LastName       string   `json:"last_name"`
+	Email          string   `json:"email"`
+	Title          string   `json:"title"`
+	Role           Role     `json:"role"`
+	Department     string   `json:"department"`
+	Squads         []string `json:"squads"`
+	SupervisorID   *int64   `json:"supervisor_id,omitempty"`
+	SupervisorName string   `json:"supervisor_name,omitempty"`
+	Level          int      `json:"level"`
+	ReportingChain []string `json:"reporting_chain"`
+	DirectReports  int      `json:"direct_reports"`
+	TotalReports   int      `json:"total_reports"`
+}
+
+// OrgTreeAggregate names an optional rollup computed over each org tree node's subtree
+type OrgTreeAggregate string
+
+const (
+	OrgTreeAggregateHeadcount    OrgTreeAggregate = "headcount"
+	OrgTreeAggregateOpenTasks    OrgTreeAggregate = "open_tasks"
+	OrgTreeAggregateOutOfOffice  OrgTreeAggregate = "out_of_office"
+	OrgTreeAggregateGoalProgress OrgTreeAggregate = "goal_progress"
+)
+
+// OrgTreeOptions selects which aggregates to compute for an org tree
+type OrgTreeOptions struct {
+	Headcount    bool
+	OpenTasks    bool
+	OutOfOffice  bool
+	GoalProgress bool
+}
+
+// ParseOrgTreeOptions parses a comma-separated list of aggregate names
+func ParseOrgTreeOptions(include string) (OrgTreeOptions, error) {
+	var opts OrgTreeOptions
+	for _, name := range strings.Split(include, ",") {
+		switch OrgTreeAggregate(strings.TrimSpace(name)) {
+		case "":
+		case OrgTreeAggregateHeadcount:
+			opts.Headcount = true
+		case OrgTreeAggregateOpenTasks:
+			opts.OpenTasks = true
+		case OrgTreeAggregateOutOfOffice:
+			opts.OutOfOffice = true
+		case OrgTreeAggregateGoalProgress:
+			opts.GoalProgress = true
+		default:
+			return opts, fmt.Errorf("unknown aggregate %q; use headcount, open_tasks, out_of_office, or goal_progress", strings.TrimSpace(name))
+		}
+	}
+	return opts, nil
+}
+
+// Any reports whether any aggregate is requested
+func (o OrgTreeOptions) Any() bool {
+	return o.Headcount || o.OpenTasks || o.OutOfOffice || o.GoalProgress
+}
+
+// NeedsMemberStats reports whether the requested aggregates need per-user task or time off data
+func (o OrgTreeOptions) NeedsMemberStats() bool {
+	return o.OpenTasks || o.OutOfOffice || o.GoalProgress
+}
+
+// OrgMemberStats holds one user's inputs to the org tree aggregates
+type OrgMemberStats struct {
+	OpenTasks int
+	// OutOfOffice is true when the user has approved time off covering today
+	OutOfOffice bool
+	// QuarterTasks and QuarterCompleted count non-cancelled tasks assigned to the user that are due this quarter
+	QuarterTasks     int
+	QuarterCompleted int
+}
+
+// OrgTreeMetrics holds the requested aggregates for a node's whole subtree, including the node's own user.
+// Only the aggregates that were requested are set.
+type OrgTreeMetrics struct {
+	Headcount   *int `json:"headcount,omitempty"`
+	OpenTasks   *int `json:"open_tasks,omitempty"`
+	OutOfOffice *int `json:"out_of_office,omitempty"`
+	// GoalProgress is the share (0-1) of this quarter's tasks that are completed; nil when no tasks are due this quarter
+	GoalProgress *float64 `json:"goal_progress,omitempty"`
+}
+
+// ============================================================================
+// Notification Types
+// ============================================================================
+
+// NotificationType identifies what an in-app notification is about
+type NotificationType string
+
+const (
+	NotificationTypeMeetingResponse    NotificationType = "meeting_response"
+	NotificationTypeKudos              NotificationType = "kudos"
+	NotificationTypeTimeOffReviewed    NotificationType = "time_off_reviewed"
+	NotificationTypeMeetingInvite      NotificationType = "meeting_invite"
+	NotificationTypeInvitationAccepted NotificationType = "invitation_accepted"
+	NotificationTypeOrgChartChange     NotificationType = "org_chart_change"
+	// A direct report asked to change their title or department
+	NotificationTypeProfileChangeRequested NotificationType = "profile_change_requested"
+	// A supervisor approved or rejected the user's title or department change
+	NotificationTypeProfileChangeReviewed NotificationType = "profile_change_reviewed"
+	// A direct report was moved to or from the user
+	NotificationTypeSupervisorChanged NotificationType = "supervisor_changed"
+)
+
+// Notification is an in-app message for one user, optionally about an entity such as a meeting
+type Notification struct {
+	ID         int64            `json:"id"`
+	UserID     int64            `json:"user_id"`
+	Type       NotificationType `json:"type"`
+	ActorID    *int64           `json:"actor_id,omitempty"`
+	EntityType *string          `json:"entity_type,omitempty"`
+	EntityID   *int64           `json:"entity_id,omitempty"`
+	Title      string           `json:"title"`
+	Body       string           `json:"body"`
+	ReadAt     *time.Time       `json:"read_at,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// UnreadNotificationCount is the number of notifications a user hasn't read
+type UnreadNotificationCount struct {
+	UnreadCount int `json:"unread_count"`
+}
+
+// ============================================================================
+// Calendar Types
+// ============================================================================
+
+// TaskStatus represents the status of a task
+type TaskStatus string
+
+const (
+	TaskStatusPending    TaskStatus = "pending"
+	TaskStatusInProgress TaskStatus = "in_progress"
+	TaskStatusCompleted  TaskStatus = "completed"
+	TaskStatusCancelled  TaskStatus = "cancelled"
+)
+
+// ValidTaskStatuses contains all valid task status values
+var ValidTaskStatuses = map[TaskStatus]bool{
+	TaskStatusPending:    true,
+	TaskStatusInProgress: true,
+	TaskStatusCompleted:  true,
+	TaskStatusCancelled:  true,
+}
+
+// IsOpen reports whether a task with this status still needs doing
+func (s TaskStatus) IsOpen() bool {
+	return s == TaskStatusPending || s == TaskStatusInProgress
+}
+
+// TaskPriority represents how urgent a task is
+type TaskPriority string
+
+const (
+	TaskPriorityLow    TaskPriority = "low"
+	TaskPriorityMedium TaskPriority = "medium"
+	TaskPriorityHigh   TaskPriority = "high"
+	TaskPriorityUrgent TaskPriority = "urgent"
+)
+
+// ValidTaskPriorities contains all valid task priority values
+var ValidTaskPriorities = map[TaskPriority]bool{
+	TaskPriorityLow:    true,
+	TaskPriorityMedium: true,
+	TaskPriorityHigh:   true,
+	TaskPriorityUrgent: true,
+}
+
+const (
+	MaxTaskLabels      = 20
+	MaxTaskLabelLength = 50
+)
+
+// NormalizeTaskLabels trims and lowercases labels, dropping blanks and duplicates
+func NormalizeTaskLabels(labels []string) ([]string, error) {
+	normalized := make([]string, 0, len(labels))
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label == "" || seen[label] {
+			continue
+		}
+		if len(label) > MaxTaskLabelLength {
+			return nil, fmt.Errorf("labels must be less than %d characters", MaxTaskLabelLength)
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+	if len(normalized) > MaxTaskLabels {
+		return nil, fmt.Errorf("a task can have at most %d labels", MaxTaskLabels)
+	}
+	return normalized, nil
+}
+
+// AssignmentType represents how a task is assigned
+type AssignmentType string
+
+const (
+	AssignmentTypeUser       AssignmentType = "user"
+	AssignmentTypeSquad      AssignmentType = "squad"
+	AssignmentTypeDepartment AssignmentType = "department"
+)
+
+// ValidAssignmentTypes contains all valid assignment type values
+var ValidAssignmentTypes = map[AssignmentType]bool{
+	AssignmentTypeUser:       true,
+	AssignmentTypeSquad:      true,
+	AssignmentTypeDepartment: true,
+}
+
+// RecurrenceType represents meeting recurrence patterns
+type RecurrenceType string
+
+const (
+	RecurrenceTypeDaily   RecurrenceType = "daily"
+	RecurrenceTypeWeekly  RecurrenceType = "weekly"
+	RecurrenceTypeMonthly RecurrenceType = "monthly"
+)
+
+// ValidRecurrenceTypes contains all valid recurrence type values
+var ValidRecurrenceTypes = map[RecurrenceType]bool{
+	RecurrenceTypeDaily:   true,
+	RecurrenceTypeWeekly:  true,
+	RecurrenceTypeMonthly: true,
+}
+
+// ResponseStatus represents meeting invitation response
+type ResponseStatus string
+
+const (
+	ResponseStatusPending   ResponseStatus = "pending"
+	ResponseStatusAccepted  ResponseStatus = "accepted"
+	ResponseStatusDeclined  ResponseStatus = "declined"
+	ResponseStatusTentative ResponseStatus = "tentative"
+)
+
+// ValidResponseStatuses contains all valid response status values
+var ValidResponseStatuses = map[ResponseStatus]bool{
+	ResponseStatusPending:   true,
+	ResponseStatusAccepted:  true,
+	ResponseStatusDeclined:  true,
+	ResponseStatusTentative: true,
+}
+
+// Task represents a calendar task or event
+type Task struct {
+	ID                 int64          `json:"id"`
+	Title              string         `json:"title"`
+	Description        *string        `json:"description,omitempty"`
+	Status             TaskStatus     `json:"status"`
+	Priority           TaskPriority   `json:"priority"`
+	Labels             []string       `json:"labels"`
+	DueDate            time.Time      `json:"due_date"`
+	StartTime          *time.Time     `json:"start_time,omitempty"`
+	EndTime            *time.Time     `json:"end_time,omitempty"`
+	AllDay             bool           `json:"all_day"`
+	CreatedByID        int64          `json:"created_by_id"`
+	CreatedBy          *User          `json:"created_by,omitempty"`
+	AssignmentType     AssignmentType `json:"assignment_type"`
+	AssignedUserID     *int64         `json:"assigned_user_id,omitempty"`
+	AssignedUser       *User          `json:"assigned_user,omitempty"`
+	AssignedSquadID    *int64         `json:"assigned_squad_id,omitempty"`
+	AssignedSquad      *Squad         `json:"assigned_squad,omitempty"`
+	AssignedDepartment *string        `json:"assigned_department,omitempty"`
+	MeetingID          *int64         `json:"meeting_id,omitempty"` // Set when created from a meeting action item
+	// Only the open instance of a recurring series carries its recurrence; finishing it generates the next
+	RecurrenceType     *RecurrenceType `json:"recurrence_type,omitempty"`
+	RecurrenceInterval int             `json:"recurrence_interval"`
+	RecurrenceEndDate  *time.Time      `json:"recurrence_end_date,omitempty"`
+	RecurrenceParentID *int64          `json:"recurrence_parent_id,omitempty"` // First task of the series
+	IsProjected        bool            `json:"is_projected,omitempty"`         // A future occurrence that hasn't been generated yet
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// NextOccurrence returns the due date of the occurrence following current for a recurring task.
+// Task dates are UTC days, so steps are taken in UTC.
+func (t *Task) NextOccurrence(current time.Time) time.Time {
+	interval := t.RecurrenceInterval
+	if interval < 1 {
+		interval = 1
+	}
+	switch *t.RecurrenceType {
+	case RecurrenceTypeDaily:
+		return current.AddDate(0, 0, interval)
+	case RecurrenceTypeWeekly:
+		return current.AddDate(0, 0, 7*interval)
+	default:
+		return current.AddDate(0, interval, 0)
+	}
+}
+
+// NextInstance returns the task that follows this instance of a recurring series, or nil once the
+// series has ended. The next instance starts pending with the same assignment, and its times move
+// with its due date.
+func (t *Task) NextInstance() *Task {
+	if t.RecurrenceType == nil {
+		return nil
+	}
+	due := t.NextOccurrence(t.DueDate)
+	if t.RecurrenceEndDate != nil && due.After(*t.RecurrenceEndDate) {
+		return nil
+	}
+
+	next := *t
+	next.ID = 0
+	next.Status = TaskStatusPending
+	next.DueDate = due
+	shift := due.Sub(t.DueDate)
+	if t.StartTime != nil {
+		start := t.StartTime.Add(shift)
+		next.StartTime = &start
+	}
+	if t.EndTime != nil {
+		end := t.EndTime.Add(shift)
+		next.EndTime = &end
+	}
+	if next.RecurrenceParentID == nil {
+		parentID := t.ID
+		next.RecurrenceParentID = &parentID
+	}
+	next.IsProjected = false
+	return &next
+}
+
+// ProjectOccurrences returns the not yet generated occurrences of an open recurring task that fall
+// within a date range, marked as projected and carrying the open instance's ID. They're what the
+// series will look like if each instance is finished on time.
+func (t *Task) ProjectOccurrences(start, end time.Time) []Task {
+	if t.RecurrenceType == nil || !t.IsOpen() {
+		return nil
+	}
+	var occurrences []Task
+	for next := t.NextInstance(); next != nil && !next.DueDate.After(end); next = next.NextInstance() {
+		if !next.DueDate.Before(start) {
+			next.ID = t.ID
+			next.IsProjected = true
+			occurrences = append(occurrences, *next)
+		}
+	}
+	return occurrences
+}
+
+// CreateTaskRequest represents a request to create a task or event
+type CreateTaskRequest struct {
+	Title              string          `json:"title"`
+	Description        *string         `json:"description,omitempty"`
+	DueDate            time.Time       `json:"due_date"`
+	StartTime          *time.Time      `json:"start_time,omitempty"`
+	EndTime            *time.Time      `json:"end_time,omitempty"`
+	AllDay             *bool           `json:"all_day,omitempty"`
+	Priority           TaskPriority    `json:"priority,omitempty"`
+	Labels             []string        `json:"labels,omitempty"`
+	AssignmentType     AssignmentType  `json:"assignment_type"`
+	AssignedUserID     *int64          `json:"assigned_user_id,omitempty"`
+	AssignedSquadID    *int64          `json:"assigned_squad_id,omitempty"`
+	AssignedDepartment *string         `json:"assigned_department,omitempty"`
+	RecurrenceType     *RecurrenceType `json:"recurrence_type,omitempty"`
+	RecurrenceInterval *int            `json:"recurrence_interval,omitempty"`
+	RecurrenceEndDate  *time.Time      `json:"recurrence_end_date,omitempty"`
+}
+
+// validateTaskRecurrence checks recurrence settings shared by task create and update requests
+func validateTaskRecurrence(recurrenceType *RecurrenceType, interval *int) error {
+	if recurrenceType != nil && !ValidRecurrenceTypes[*recurrenceType] {
+		return fmt.Errorf("invalid recurrence_type: must be 'daily', 'weekly', or 'monthly'")
+	}
+	if interval != nil && *interval < 1 {
+		return fmt.Errorf("recurrence_interval must be at least 1")
+	}
+	return nil
+}
+
+// Validate validates the CreateTaskRequest
+func (r *CreateTaskRequest) Validate() error {
+	r.Title = strings.TrimSpace(r.Title)
+	if r.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(r.Title) > 255 {
+		return fmt.Errorf("title must be less than 255 characters")
+	}
+	if r.DueDate.IsZero() {
+		return fmt.Errorf("due_date is required")
+	}
+	if r.Priority == "" {
+		r.Priority = TaskPriorityMedium
+	}
+	if !ValidTaskPriorities[r.Priority] {
+		return fmt.Errorf("invalid priority: must be 'low', 'medium', 'high', or 'urgent'")
+	}
+	labels, err := NormalizeTaskLabels(r.Labels)
+	if err != nil {
+		return err
+	}
+	r.Labels = labels
+	if !ValidAssignmentTypes[r.AssignmentType] {
+		return fmt.Errorf("invalid assignment_type: must be 'user', 'squad', or 'department'")
+	}
+	// Validate assignment based on type
+	switch r.AssignmentType {
+	case AssignmentTypeUser:
+		if r.AssignedUserID == nil {
+			return fmt.Errorf("assigned_user_id is required when assignment_type is 'user'")
+		}
+	case AssignmentTypeSquad:
+		if r.AssignedSquadID == nil {
+			return fmt.Errorf("assigned_squad_id is required when assignment_type is 'squad'")
+		}
+	case AssignmentTypeDepartment:
+		if r.AssignedDepartment == nil || *r.AssignedDepartment == "" {
+			return fmt.Errorf("assigned_department is required when assignment_type is 'department'")
+		}
+	}
+	if err := validateTaskRecurrence(r.RecurrenceType, r.RecurrenceInterval); err != nil {
+		return err
+	}
+	if r.RecurrenceType == nil && (r.RecurrenceInterval != nil || r.RecurrenceEndDate != nil) {
+		return fmt.Errorf("recurrence_type is required when setting recurrence")
+	}
+	if r.RecurrenceEndDate != nil && r.RecurrenceEndDate.Before(r.DueDate) {
+		return fmt.Errorf("recurrence_end_date must be on or after due_date")
+	}
+	return nil
+}
+
+// UpdateTaskRequest represents a request to update a task or event
+type UpdateTaskRequest struct {
+	Title              *string         `json:"title,omitempty"`
+	Description        *string         `json:"description,omitempty"`
+	Status             *TaskStatus     `json:"status,omitempty"`
+	Priority           *TaskPriority   `json:"priority,omitempty"`
+	Labels             *[]string       `json:"labels,omitempty"` // Replaces all labels; an empty list clears them
+	DueDate            *time.Time      `json:"due_date,omitempty"`
+	StartTime          *time.Time      `json:"start_time,omitempty"`
+	EndTime            *time.Time      `json:"end_time,omitempty"`
+	AllDay             *bool           `json:"all_day,omitempty"`
+	AssignmentType     *AssignmentType `json:"assignment_type,omitempty"`
+	AssignedUserID     *int64          `json:"assigned_user_id,omitempty"`
+	AssignedSquadID    *int64          `json:"assigned_squad_id,omitempty"`
+	AssignedDepartment *string         `json:"assigned_department,omitempty"`
+	RecurrenceType     *RecurrenceType `json:"recurrence_type,omitempty"`
+	RecurrenceInterval *int            `json:"recurrence_interval,omitempty"`
+	RecurrenceEndDate  *time.Time      `json:"recurrence_end_date,omitempty"`
+}
+
+// Validate validates the UpdateTaskRequest
+func (r *UpdateTaskRequest) Validate() error {
+	if r.Title != nil {
+		*r.Title = strings.TrimSpace(*r.Title)
+		if *r.Title == "" {
+			return fmt.Errorf("title cannot be empty")
+		}
+		if len(*r.Title) > 255 {
+			return fmt.Errorf("title must be less than 255 characters")
+		}
+	}
+	if r.Status != nil && !ValidTaskStatuses[*r.Status] {
+		return fmt.Errorf("invalid status: must be 'pending', 'in_progress', 'completed', or 'cancelled'")
+	}
+	if r.Priority != nil && !ValidTaskPriorities[*r.Priority] {
+		return fmt.Errorf("invalid priority: must be 'low', 'medium', 'high', or 'urgent'")
+	}
+	if r.Labels != nil {
+		labels, err := NormalizeTaskLabels(*r.Labels)
+		if err != nil {
+			return err
+		}
+		r.Labels = &labels
+	}
+	if r.AssignmentType != nil && !ValidAssignmentTypes[*r.AssignmentType] {
+		return fmt.Errorf("invalid assignment_type: must be 'user', 'squad', or 'department'")
+	}
+	return validateTaskRecurrence(r.RecurrenceType, r.RecurrenceInterval)
+}
+
+// TaskSortField is a field task listings can be sorted by
+type TaskSortField string
+
+const (
+	TaskSortDueDate   TaskSortField = "due_date"
+	TaskSortPriority  TaskSortField = "priority"
+	TaskSortCreatedAt TaskSortField = "created_at"
+	TaskSortUpdatedAt TaskSortField = "updated_at"
+)
+
+// ValidTaskSortFields contains all valid task sort fields
+var ValidTaskSortFields = map[TaskSortField]bool{
+	TaskSortDueDate:   true,
+	TaskSortPriority:  true,
+	TaskSortCreatedAt: true,
+	TaskSortUpdatedAt: true,
+}
+
+// MaxUserSearchQueryLength caps the length of a user search query
+const MaxUserSearchQueryLength = 200
+
+// UserSearchFilter holds the query and optional filters for a user search.
+// Nil filters match everyone.
+type UserSearchFilter struct {
+	Query      string
+	Department *string
+	SquadID    *int64
+	Role       *Role
+	Active     *bool
+}
+
+// Validate validates the UserSearchFilter
+func (f *UserSearchFilter) Validate() error {
+	f.Query = strings.TrimSpace(f.Query)
+	if len(f.Query) > MaxUserSearchQueryLength {
+		return fmt.Errorf("q must be %d characters or less", MaxUserSearchQueryLength)
+	}
+	if f.Role != nil && !ValidRoles[*f.Role] {
+		return fmt.Errorf("invalid role")
+	}
+	if f.SquadID != nil && *f.SquadID <= 0 {
+		return fmt.Errorf("squad must be a positive squad ID")
+	}
+	return nil
+}
+
+// TaskFilter holds optional filters and sorting for task listings
+type TaskFilter struct {
+	Status     *TaskStatus
+	Priority   *TaskPriority
+	Label      *string
+	AssigneeID *int64
+	// Overdue selects open tasks (not completed or cancelled) due before today
+	Overdue bool
+	SortBy  TaskSortField
+	SortAsc bool
+}
+
+// Validate validates the TaskFilter
+func (f *TaskFilter) Validate() error {
+	if f.Status != nil && !ValidTaskStatuses[*f.Status] {
+		return fmt.Errorf("invalid status")
+	}
+	if f.Priority != nil && !ValidTaskPriorities[*f.Priority] {
+		return fmt.Errorf("invalid priority")
+	}
+	if f.Label != nil {
+		label := strings.ToLower(strings.TrimSpace(*f.Label))
+		f.Label = &label
+	}
+	if f.SortBy != "" && !ValidTaskSortFields[f.SortBy] {
+		return fmt.Errorf("invalid sort: must be 'due_date', 'priority', 'created_at', or 'updated_at'")
+	}
+	return nil
+}
+
+// MaxBulkTasks caps how many tasks a single bulk request may create
+const MaxBulkTasks = 100
+
+// BulkTaskError explains why one item of a bulk task request was rejected. Errors that apply to
+// the whole request have neither an index nor a task ID.
+type BulkTaskError struct {
+	Index  *int   `json:"index,omitempty"`   // Position in the request's tasks, for bulk creation
+	TaskID *int64 `json:"task_id,omitempty"` // Task that couldn't be moved, for reassignment
+	Error  string `json:"error"`
+}
+
+// BulkTaskResult reports the outcome of a bulk task request. Bulk requests are all-or-nothing:
+// when any errors are reported no task was created or changed.
+type BulkTaskResult struct {
+	Tasks  []Task          `json:"tasks"`
+	Errors []BulkTaskError `json:"errors"`
+}
+
+// BulkCreateTasksRequest represents a request to create many tasks at once
+type BulkCreateTasksRequest struct {
+	Tasks []CreateTaskRequest `json:"tasks"`
+}
+
+// Validate checks the batch size; use ItemErrors to validate the tasks themselves
+func (r *BulkCreateTasksRequest) Validate() error {
+	if len(r.Tasks) == 0 {
+		return fmt.Errorf("tasks is required")
+	}
+	if len(r.Tasks) > MaxBulkTasks {
+		return fmt.Errorf("at most %d tasks can be created at once", MaxBulkTasks)
+	}
+	return nil
+}
+
+// ItemErrors validates every task in the batch, reporting each invalid one by its index
+func (r *BulkCreateTasksRequest) ItemErrors() []BulkTaskError {
+	var errs []BulkTaskError
+	for i := range r.Tasks {
+		if err := r.Tasks[i].Validate(); err != nil {
+			index := i
+			errs = append(errs, BulkTaskError{Index: &index, Error: err.Error()})
+		}
+	}
+	return errs
+}
+
+// ReassignTasksRequest represents a request to move open tasks from one user to another
+type ReassignTasksRequest struct {
+	FromUserID int64   `json:"from_user_id"`
+	ToUserID   int64   `json:"to_user_id"`
+	TaskIDs    []int64 `json:"task_ids,omitempty"` // Limits the move to these tasks; every open task by default
+}
+
+// Validate validates the ReassignTasksRequest
+func (r *ReassignTasksRequest) Validate() error {
+	if r.FromUserID <= 0 {
+		return fmt.Errorf("from_user_id is required")
+	}
+	if r.ToUserID <= 0 {
+		return fmt.Errorf("to_user_id is required")
+	}
+	if r.FromUserID == r.ToUserID {
+		return fmt.Errorf("to_user_id must be a different user")
+	}
+	return nil
+}
+
+// IsOpen reports whether a task still needs doing
+func (t *Task) IsOpen() bool {
+	return t.Status.IsOpen()
+}
+
+// ReassignmentErrors checks that the actor may move the given open tasks. Requested IDs missing from
+// tasks aren't open tasks assigned to the source user. Only a task's creator or an admin may move it,
+// matching who may edit it.
+func ReassignmentErrors(tasks []Task, requestedIDs []int64, actor *User) []BulkTaskError {
+	found := make(map[int64]bool, len(tasks))
+	for _, task := range tasks {
+		found[task.ID] = true
+	}
+
+	var errs []BulkTaskError
+	for _, id := range requestedIDs {
+		if !found[id] {
+			taskID := id
+			errs = append(errs, BulkTaskError{TaskID: &taskID, Error: "not an open task assigned to from_user_id"})
+		}
+	}
+	if actor.IsAdmin() {
+		return errs
+	}
+	for _, task := range tasks {
+		if task.CreatedByID != actor.ID {
+			taskID := task.ID
+			errs = append(errs, BulkTaskError{TaskID: &taskID, Error: "only the task creator can reassign it"})
+		}
+	}
+	return errs
+}
+
+// MaxTaskCommentLength is the maximum length of a task comment
+const MaxTaskCommentLength = 5000
+
+// TaskComment represents a comment in a task's discussion thread
+type TaskComment struct {
+	ID        int64     `json:"id"`
+	TaskID    int64     `json:"task_id"`
+	AuthorID  int64     `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskCommentRequest represents a request to post or edit a task comment
+type TaskCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// Validate validates the TaskCommentRequest
+func (r *TaskCommentRequest) Validate() error {
+	r.Body = strings.TrimSpace(r.Body)
+	if r.Body == "" {
+		return fmt.Errorf("body is required")
+	}
+	if len(r.Body) > MaxTaskCommentLength {
+		return fmt.Errorf("body must be less than %d characters", MaxTaskCommentLength)
+	}
+	return nil
+}
+
+// TaskActivity represents a status change in a task's activity feed
+type TaskActivity struct {
+	ID         int64      `json:"id"`
+	TaskID     int64      `json:"task_id"`
+	ActorID    *int64     `json:"actor_id,omitempty"` // Nil once the user who made the change is deleted
+	FromStatus TaskStatus `json:"from_status"`
+	ToStatus   TaskStatus `json:"to_status"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Meeting represents a calendar meeting
+type Meeting struct {
+	ID                   int64           `json:"id"`
+	Title                string          `json:"title"`
+	Description          *string         `json:"description,omitempty"`
+	StartTime            time.Time       `json:"start_time"`
+	EndTime              time.Time       `json:"end_time"`
+	CreatedByID          int64           `json:"created_by_id"`
+	CreatedBy            *User           `json:"created_by,omitempty"`
+	RecurrenceType       *RecurrenceType `json:"recurrence_type,omitempty"`
+	RecurrenceInterval   int             `json:"recurrence_interval"`
+	RecurrenceEndDate    *time.Time      `json:"recurrence_end_date,omitempty"`
+	RecurrenceDaysOfWeek []int           `json:"recurrence_days_of_week,omitempty"`
+	RecurrenceDayOfMonth *int            `json:"recurrence_day_of_month,omitempty"`
+	ParentMeetingID      *int64          `json:"parent_meeting_id,omitempty"`
+	OriginalStartTime    *time.Time      `json:"original_start_time,omitempty"` // Occurrence of the parent series this exception replaces
+	IsCancelled          bool            `json:"is_cancelled"`
+	ICalUID              *string         `json:"ical_uid,omitempty"` // Set when imported from an external .ics invite
+	Timezone             string          `json:"timezone"`           // Recurrence keeps the same wall-clock time in this zone
+	CreatedAt            time.Time       `json:"created_at"`
+	UpdatedAt            time.Time       `json:"updated_at"`
+	Attendees            []MeetingAttendee `json:"attendees,omitempty"`
+	ExceptionStarts      []time.Time     `json:"-"` // Occurrences of a recurring series replaced by exceptions
+}
+
+// Location returns the meeting's timezone, defaulting to UTC
+func (m *Meeting) Location() *time.Location {
+	return LocationOrUTC(m.Timezone)
+}
+
+// IsException reports whether the meeting overrides a single occurrence of a recurring series
+func (m *Meeting) IsException() bool {
+	return m.ParentMeetingID != nil && m.OriginalStartTime != nil
+}
+
+// HasException reports whether the given occurrence of a recurring series has been edited or cancelled
+func (m *Meeting) HasException(occurrenceStart time.Time) bool {
+	for _, start := range m.ExceptionStarts {
+		if start.Equal(occurrenceStart) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOccurrence returns the start of the occurrence following current for a recurring meeting.
+// Steps are taken in the meeting's timezone, so occurrences keep their local time across DST changes.
+func (m *Meeting) NextOccurrence(current time.Time) time.Time {
+	interval := m.RecurrenceInterval
+	if interval < 1 {
+		interval = 1
+	}
+	local := current.In(m.Location())
+	var next time.Time
+	switch *m.RecurrenceType {
+	case RecurrenceTypeDaily:
+		next = local.AddDate(0, 0, interval)
+	case RecurrenceTypeWeekly:
+		next = local.AddDate(0, 0, 7*interval)
+	default:
+		next = local.AddDate(0, interval, 0)
+	}
+	return next.In(current.Location())
+}
+
+// OccursAt reports whether a recurring meeting has an occurrence starting at t
+func (m *Meeting) OccursAt(t time.Time) bool {
+	if m.RecurrenceType == nil {
+		return m.StartTime.Equal(t)
+	}
+	if m.RecurrenceEndDate != nil && t.After(*m.RecurrenceEndDate) {
+		return false
+	}
+	for current := m.StartTime; !current.After(t); current = m.NextOccurrence(current) {
+		if current.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// MeetingAttendee represents an attendee of a meeting
+type MeetingAttendee struct {
+	ID             int64          `json:"id"`
+	MeetingID      int64          `json:"meeting_id"`
+	UserID         int64          `json:"user_id"`
+	User           *User          `json:"user,omitempty"`
+	ResponseStatus ResponseStatus `json:"response_status"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// MeetingResponseTally counts a meeting's attendees by response
+type MeetingResponseTally struct {
+	Accepted  int `json:"accepted"`
+	Declined  int `json:"declined"`
+	Tentative int `json:"tentative"`
+	Pending   int `json:"pending"`
+}
+
+// ResponseTally counts the meeting's attendees by response status
+func (m *Meeting) ResponseTally() MeetingResponseTally {
+	var tally MeetingResponseTally
+	for _, a := range m.Attendees {
+		switch a.ResponseStatus {
+		case ResponseStatusAccepted:
+			tally.Accepted++
+		case ResponseStatusDeclined:
+			tally.Declined++
+		case ResponseStatusTentative:
+			tally.Tentative++
+		default:
+			tally.Pending++
+		}
+	}
+	return tally
+}
+
+// CreateMeetingRequest represents a request to create a meeting
+type CreateMeetingRequest struct {
+	Title                string          `json:"title"`
+	Description          *string         `json:"description,omitempty"`
+	StartTime            time.Time       `json:"start_time"`
+	EndTime              time.Time       `json:"end_time"`
+	AttendeeIDs          []int64         `json:"attendee_ids"`
+	RecurrenceType       *RecurrenceType `json:"recurrence_type,omitempty"`
+	RecurrenceInterval   *int            `json:"recurrence_interval,omitempty"`
+	RecurrenceEndDate    *time.Time      `json:"recurrence_end_date,omitempty"`
+	RecurrenceDaysOfWeek []int           `json:"recurrence_days_of_week,omitempty"`
+	RecurrenceDayOfMonth *int            `json:"recurrence_day_of_month,omitempty"`
+	Timezone             *string         `json:"timezone,omitempty"` // Defaults to the organizer's timezone
+	ICalUID              *string         `json:"-"`                  // Only set by .ics import
+}
+
+// Validate validates the CreateMeetingRequest
+func (r *CreateMeetingRequest) Validate() error {
+	r.Title = strings.TrimSpace(r.Title)
+	if r.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(r.Title) > 255 {
+		return fmt.Errorf("title must be less than 255 characters")
+	}
+	if r.StartTime.IsZero() {
+		return fmt.Errorf("start_time is required")
+	}
+	if r.EndTime.IsZero() {
+		return fmt.Errorf("end_time is required")
+	}
+	if r.EndTime.Before(r.StartTime) || r.EndTime.Equal(r.StartTime) {
+		return fmt.Errorf("end_time must be after start_time")
+	}
+	// Validate recurrence settings
+	if r.RecurrenceType != nil {
+		if !ValidRecurrenceTypes[*r.RecurrenceType] {
+			return fmt.Errorf("invalid recurrence_type: must be 'daily', 'weekly', or 'monthly'")
+		}
+		if r.RecurrenceInterval != nil && *r.RecurrenceInterval < 1 {
+			return fmt.Errorf("recurrence_interval must be at least 1")
+		}
+		// Weekly recurrence needs days of week
+		if *r.RecurrenceType == RecurrenceTypeWeekly && len(r.RecurrenceDaysOfWeek) == 0 {
+			return fmt.Errorf("recurrence_days_of_week is required for weekly recurrence")
+		}
+		// Monthly recurrence needs day of month
+		if *r.RecurrenceType == RecurrenceTypeMonthly && r.RecurrenceDayOfMonth == nil {
+			return fmt.Errorf("recurrence_day_of_month is required for monthly recurrence")
+		}
+	}
+	if r.Timezone != nil {
+		if err := ValidateTimezone(*r.Timezone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateMeetingRequest represents a request to update a meeting
+type UpdateMeetingRequest struct {
+	Title                *string         `json:"title,omitempty"`
+	Description          *string         `json:"description,omitempty"`
+	StartTime            *time.Time      `json:"start_time,omitempty"`
+	EndTime              *time.Time      `json:"end_time,omitempty"`
+	AttendeeIDs          []int64         `json:"attendee_ids,omitempty"`
+	RecurrenceType       *RecurrenceType `json:"recurrence_type,omitempty"`
+	RecurrenceInterval   *int            `json:"recurrence_interval,omitempty"`
+	RecurrenceEndDate    *time.Time      `json:"recurrence_end_date,omitempty"`
+	RecurrenceDaysOfWeek []int           `json:"recurrence_days_of_week,omitempty"`
+	RecurrenceDayOfMonth *int            `json:"recurrence_day_of_month,omitempty"`
+	Timezone             *string         `json:"timezone,omitempty"`
+}
+
+// Validate validates the UpdateMeetingRequest
+func (r *UpdateMeetingRequest) Validate() error {
+	if r.Title != nil {
 		*r.Title = strings.TrimSpace(*r.Title)
 		if *r.Title == "" {
 			return fmt.Errorf("title cannot be empty")
 		}
-		if len(*r.Title) > 255 {
-			return fmt.Errorf("title must be less than 255 characters")
+		if len(*r.Title) > 255 {
+			return fmt.Errorf("title must be less than 255 characters")
+		}
+	}
+	if r.RecurrenceType != nil && !ValidRecurrenceTypes[*r.RecurrenceType] {
+		return fmt.Errorf("invalid recurrence_type: must be 'daily', 'weekly', or 'monthly'")
+	}
+	if r.RecurrenceInterval != nil && *r.RecurrenceInterval < 1 {
+		return fmt.Errorf("recurrence_interval must be at least 1")
+	}
+	if r.Timezone != nil {
+		if err := ValidateTimezone(*r.Timezone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MaxICSImportEvents caps how many events a single .ics import may contain
+const MaxICSImportEvents = 500
+
+// ICSImportSkipped describes an event from an .ics file that was not imported
+type ICSImportSkipped struct {
+	UID     string `json:"uid,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// ICSImportResult reports the outcome of importing an .ics file
+type ICSImportResult struct {
+	Imported           []Meeting          `json:"imported"`
+	Skipped            []ICSImportSkipped `json:"skipped"`
+	UnmatchedAttendees []string           `json:"unmatched_attendees"` // Invitee emails with no matching user
+}
+
+// OccurrenceScope selects which occurrences of a recurring meeting an edit or cancellation applies to
+type OccurrenceScope string
+
+const (
+	OccurrenceScopeThis      OccurrenceScope = "this"      // Only the selected occurrence
+	OccurrenceScopeFollowing OccurrenceScope = "following" // The selected occurrence and all later ones
+)
+
+// ValidOccurrenceScopes contains all valid occurrence scopes
+var ValidOccurrenceScopes = map[OccurrenceScope]bool{
+	OccurrenceScopeThis:      true,
+	OccurrenceScopeFollowing: true,
+}
+
+// UpdateMeetingOccurrenceRequest represents a request to edit one or more occurrences of a recurring meeting
+type UpdateMeetingOccurrenceRequest struct {
+	OccurrenceStart time.Time       `json:"occurrence_start"`
+	Scope           OccurrenceScope `json:"scope"`
+	UpdateMeetingRequest
+}
+
+// Validate validates the UpdateMeetingOccurrenceRequest
+func (r *UpdateMeetingOccurrenceRequest) Validate() error {
+	if r.OccurrenceStart.IsZero() {
+		return fmt.Errorf("occurrence_start is required")
+	}
+	if !ValidOccurrenceScopes[r.Scope] {
+		return fmt.Errorf("invalid scope: must be 'this' or 'following'")
+	}
+	if r.Scope == OccurrenceScopeThis && (r.RecurrenceType != nil || r.RecurrenceInterval != nil ||
+		r.RecurrenceEndDate != nil || len(r.RecurrenceDaysOfWeek) > 0 || r.RecurrenceDayOfMonth != nil) {
+		return fmt.Errorf("recurrence cannot be changed for a single occurrence")
+	}
+	if r.StartTime != nil && r.EndTime != nil && !r.EndTime.After(*r.StartTime) {
+		return fmt.Errorf("end_time must be after start_time")
+	}
+	return r.UpdateMeetingRequest.Validate()
+}
+
+// CancelMeetingOccurrenceRequest represents a request to cancel one or more occurrences of a recurring meeting
+type CancelMeetingOccurrenceRequest struct {
+	OccurrenceStart time.Time       `json:"occurrence_start"`
+	Scope           OccurrenceScope `json:"scope"`
+}
+
+// Validate validates the CancelMeetingOccurrenceRequest
+func (r *CancelMeetingOccurrenceRequest) Validate() error {
+	if r.OccurrenceStart.IsZero() {
+		return fmt.Errorf("occurrence_start is required")
+	}
+	if !ValidOccurrenceScopes[r.Scope] {
+		return fmt.Errorf("invalid scope: must be 'this' or 'following'")
+	}
+	return nil
+}
+
+// MeetingResponseRequest represents a request to respond to a meeting
+type MeetingResponseRequest struct {
+	Response ResponseStatus `json:"response"`
+}
+
+// Validate validates the MeetingResponseRequest
+func (r *MeetingResponseRequest) Validate() error {
+	if !ValidResponseStatuses[r.Response] {
+		return fmt.Errorf("invalid response: must be 'pending', 'accepted', 'declined', or 'tentative'")
+	}
+	return nil
+}
+
+// MaxConflictCheckAttendees is the maximum number of attendees checked in one conflict request
+const MaxConflictCheckAttendees = 100
+
+// MaxConflictCheckWindow is the longest proposed meeting window a conflict check accepts
+const MaxConflictCheckWindow = 7 * 24 * time.Hour
+
+// Default working hours for users who haven't set their own: 9:00–17:00, Monday to Friday
+const (
+	WorkdayStartHour = 9
+	WorkdayEndHour   = 17
+)
+
+// MaxFocusBlocks caps how many no-meeting blocks a user may define
+const MaxFocusBlocks = 20
+
+// FocusBlock is a weekly block of time a user keeps free of meetings
+type FocusBlock struct {
+	Weekday     int    `json:"weekday"`      // 0 = Sunday, as in recurrence_days_of_week
+	StartMinute int    `json:"start_minute"` // Minutes after local midnight
+	EndMinute   int    `json:"end_minute"`
+	Label       string `json:"label,omitempty"`
+}
+
+// WorkSchedule is a user's working hours and focus time, interpreted in their timezone
+type WorkSchedule struct {
+	UserID      int64        `json:"user_id"`
+	Timezone    string       `json:"timezone"`
+	StartMinute int          `json:"start_minute"` // Minutes after local midnight
+	EndMinute   int          `json:"end_minute"`
+	Weekdays    []int        `json:"weekdays"`
+	FocusBlocks []FocusBlock `json:"focus_blocks"`
+	UpdatedAt   *time.Time   `json:"updated_at,omitempty"` // Nil until the user saves a schedule
+}
+
+// DefaultWorkSchedule returns the schedule used for users who haven't set one
+func DefaultWorkSchedule(userID int64, timezone string) *WorkSchedule {
+	return &WorkSchedule{
+		UserID:      userID,
+		Timezone:    timezone,
+		StartMinute: WorkdayStartHour * 60,
+		EndMinute:   WorkdayEndHour * 60,
+		Weekdays:    []int{1, 2, 3, 4, 5},
+		FocusBlocks: []FocusBlock{},
+	}
+}
+
+// SchedulingIssue explains why a time doesn't suit an attendee
+type SchedulingIssue string
+
+const (
+	SchedulingIssueOutsideWorkingHours SchedulingIssue = "outside_working_hours"
+	SchedulingIssueFocusTime           SchedulingIssue = "focus_time"
+)
+
+// Check returns why [start, end) doesn't suit the schedule, or "" when it fits within one working day
+// and avoids every focus block
+func (s *WorkSchedule) Check(start, end time.Time) SchedulingIssue {
+	local := start.In(LocationOrUTC(s.Timezone))
+	at := func(minute int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, minute, 0, 0, local.Location())
+	}
+
+	workday := false
+	for _, d := range s.Weekdays {
+		if d == int(local.Weekday()) {
+			workday = true
+		}
+	}
+	if !workday || start.Before(at(s.StartMinute)) || end.After(at(s.EndMinute)) {
+		return SchedulingIssueOutsideWorkingHours
+	}
+
+	for _, block := range s.FocusBlocks {
+		if block.Weekday == int(local.Weekday()) && start.Before(at(block.EndMinute)) && end.After(at(block.StartMinute)) {
+			return SchedulingIssueFocusTime
+		}
+	}
+	return ""
+}
+
+// SchedulingWarning flags an attendee for whom a meeting falls outside working hours or in focus time
+type SchedulingWarning struct {
+	UserID         int64           `json:"user_id"`
+	Issue          SchedulingIssue `json:"issue"`
+	LocalStartTime string          `json:"local_start_time"` // The meeting's start in the attendee's timezone
+}
+
+// CreateMeetingResponse is a newly created meeting along with any scheduling warnings
+type CreateMeetingResponse struct {
+	*Meeting
+	Warnings []SchedulingWarning `json:"warnings"`
+}
+
+// Limits for meeting time suggestions
+const (
+	DefaultSuggestedTimes  = 5
+	MaxSuggestedTimes      = 20
+	MaxSuggestTimesWindow  = 14 * 24 * time.Hour
+	SuggestTimesStep       = 15 * time.Minute
+	MinSuggestTimeDuration = 15 * time.Minute
+	MaxSuggestTimeDuration = 8 * time.Hour
+)
+
+// SuggestMeetingTimesRequest asks for meeting slots within a window that suit the organizer and every attendee
+type SuggestMeetingTimesRequest struct {
+	AttendeeIDs []int64
+	Duration    time.Duration
+	Start       time.Time
+	End         time.Time
+	Limit       int
+}
+
+// Validate validates the SuggestMeetingTimesRequest
+func (r *SuggestMeetingTimesRequest) Validate() error {
+	if r.Duration < MinSuggestTimeDuration || r.Duration > MaxSuggestTimeDuration {
+		return fmt.Errorf("duration_minutes must be between %d and %d", int(MinSuggestTimeDuration.Minutes()), int(MaxSuggestTimeDuration.Minutes()))
+	}
+	if !r.End.After(r.Start) {
+		return fmt.Errorf("end must be after start")
+	}
+	if r.End.Sub(r.Start) > MaxSuggestTimesWindow {
+		return fmt.Errorf("search window must be 14 days or less")
+	}
+	if len(r.AttendeeIDs) > MaxConflictCheckAttendees {
+		return fmt.Errorf("cannot check more than %d attendees", MaxConflictCheckAttendees)
+	}
+	if r.Limit == 0 {
+		r.Limit = DefaultSuggestedTimes
+	}
+	if r.Limit < 1 || r.Limit > MaxSuggestedTimes {
+		return fmt.Errorf("limit must be between 1 and %d", MaxSuggestedTimes)
+	}
+	return nil
+}
+
+// MeetingTimeSuggestion is a slot every attendee is free and working
+type MeetingTimeSuggestion struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// SuggestMeetingTimesResponse lists suggested slots in chronological order
+type SuggestMeetingTimesResponse struct {
+	Duration    int                     `json:"duration_minutes"`
+	Suggestions []MeetingTimeSuggestion `json:"suggestions"`
+}
+
+// UpdateWorkScheduleRequest replaces a user's working hours and focus blocks
+type UpdateWorkScheduleRequest struct {
+	StartMinute int          `json:"start_minute"`
+	EndMinute   int          `json:"end_minute"`
+	Weekdays    []int        `json:"weekdays"`
+	FocusBlocks []FocusBlock `json:"focus_blocks"`
+}
+
+// Validate validates the UpdateWorkScheduleRequest
+func (r *UpdateWorkScheduleRequest) Validate() error {
+	if r.StartMinute < 0 || r.EndMinute > 24*60 || r.StartMinute >= r.EndMinute {
+		return fmt.Errorf("working hours must start before they end, within a single day")
+	}
+	if len(r.Weekdays) == 0 {
+		return fmt.Errorf("at least one working day is required")
+	}
+	seen := make(map[int]bool, len(r.Weekdays))
+	for _, d := range r.Weekdays {
+		if d < 0 || d > 6 {
+			return fmt.Errorf("weekdays must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		if seen[d] {
+			return fmt.Errorf("weekday %d is listed more than once", d)
+		}
+		seen[d] = true
+	}
+	if r.FocusBlocks == nil {
+		r.FocusBlocks = []FocusBlock{}
+	}
+	if len(r.FocusBlocks) > MaxFocusBlocks {
+		return fmt.Errorf("cannot have more than %d focus blocks", MaxFocusBlocks)
+	}
+	for i := range r.FocusBlocks {
+		block := &r.FocusBlocks[i]
+		block.Label = strings.TrimSpace(block.Label)
+		if block.Weekday < 0 || block.Weekday > 6 {
+			return fmt.Errorf("focus block %d: weekday must be between 0 (Sunday) and 6 (Saturday)", i+1)
+		}
+		if block.StartMinute < 0 || block.EndMinute > 24*60 || block.StartMinute >= block.EndMinute {
+			return fmt.Errorf("focus block %d: must start before it ends, within a single day", i+1)
+		}
+		if len(block.Label) > 100 {
+			return fmt.Errorf("focus block %d: label must be 100 characters or less", i+1)
+		}
+	}
+	return nil
+}
+
+// CheckMeetingConflictsRequest represents a proposed meeting window to check attendee availability for
+type CheckMeetingConflictsRequest struct {
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
+	AttendeeIDs      []int64   `json:"attendee_ids"`
+	ExcludeMeetingID *int64    `json:"exclude_meeting_id,omitempty"` // Ignore this meeting, e.g. when rescheduling it
+}
+
+// Validate validates the CheckMeetingConflictsRequest
+func (r *CheckMeetingConflictsRequest) Validate() error {
+	if r.StartTime.IsZero() {
+		return fmt.Errorf("start_time is required")
+	}
+	if r.EndTime.IsZero() {
+		return fmt.Errorf("end_time is required")
+	}
+	if !r.EndTime.After(r.StartTime) {
+		return fmt.Errorf("end_time must be after start_time")
+	}
+	if r.EndTime.Sub(r.StartTime) > MaxConflictCheckWindow {
+		return fmt.Errorf("meeting window must be 7 days or less")
+	}
+	if len(r.AttendeeIDs) > MaxConflictCheckAttendees {
+		return fmt.Errorf("cannot check more than %d attendees", MaxConflictCheckAttendees)
+	}
+	return nil
+}
+
+// MeetingConflict is an existing meeting occurrence overlapping the proposed window.
+// Title is only included when the requester can view the meeting.
+type MeetingConflict struct {
+	MeetingID int64     `json:"meeting_id"`
+	Title     *string   `json:"title,omitempty"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// TimeOffConflict is approved time off overlapping the proposed window
+type TimeOffConflict struct {
+	TimeOffID   int64       `json:"time_off_id"`
+	RequestType TimeOffType `json:"request_type"`
+	StartDate   time.Time   `json:"start_date"`
+	EndDate     time.Time   `json:"end_date"`
+}
+
+// AttendeeConflicts lists one attendee's conflicts with the proposed window, along with the
+// window in the attendee's own timezone
+type AttendeeConflicts struct {
+	UserID    int64             `json:"user_id"`
+	Available bool              `json:"available"`
+	Meetings  []MeetingConflict `json:"meetings"`
+	TimeOff   []TimeOffConflict `json:"time_off"`
+
+	Timezone            string `json:"timezone"`
+	LocalStartTime      string `json:"local_start_time"`
+	LocalEndTime        string `json:"local_end_time"`
+	OutsideWorkingHours bool   `json:"outside_working_hours"`
+	InFocusTime         bool   `json:"in_focus_time"`
+}
+
+// MeetingConflictsResponse contains per-attendee availability for a proposed meeting window
+type MeetingConflictsResponse struct {
+	StartTime    time.Time           `json:"start_time"`
+	EndTime      time.Time           `json:"end_time"`
+	HasConflicts bool                `json:"has_conflicts"`
+	Attendees    []AttendeeConflicts `json:"attendees"`
+}
+
+// MeetingAttachmentKind represents the kind of file attached to a meeting
+type MeetingAttachmentKind string
+
+const (
+	MeetingAttachmentKindRecording  MeetingAttachmentKind = "recording"
+	MeetingAttachmentKindTranscript MeetingAttachmentKind = "transcript"
+)
+
+// ValidMeetingAttachmentKinds contains all valid attachment kinds
+var ValidMeetingAttachmentKinds = map[MeetingAttachmentKind]bool{
+	MeetingAttachmentKindRecording:  true,
+	MeetingAttachmentKindTranscript: true,
+}
+
+// MaxTranscriptLength is the maximum number of bytes of transcript text stored for search
+const MaxTranscriptLength = 1 << 20
+
+// MeetingAttachment represents a recording or transcript attached to a meeting occurrence
+type MeetingAttachment struct {
+	ID              int64                 `json:"id"`
+	MeetingID       int64                 `json:"meeting_id"`
+	OccurrenceStart *time.Time            `json:"occurrence_start,omitempty"`
+	Kind            MeetingAttachmentKind `json:"kind"`
+	URL             string                `json:"url"`
+	StorageKey      *string               `json:"-"`
+	FileName        *string               `json:"file_name,omitempty"`
+	ContentType     *string               `json:"content_type,omitempty"`
+	SizeBytes       *int64                `json:"size_bytes,omitempty"`
+	TranscriptText  *string               `json:"transcript_text,omitempty"`
+	UploadedByID    int64                 `json:"uploaded_by_id"`
+	CreatedAt       time.Time             `json:"created_at"`
+}
+
+// IsUploaded returns true if the attachment file is held in our storage rather than linked
+func (a *MeetingAttachment) IsUploaded() bool {
+	return a.StorageKey != nil && *a.StorageKey != ""
+}
+
+// LinkMeetingAttachmentRequest represents a request to link an externally hosted recording or transcript
+type LinkMeetingAttachmentRequest struct {
+	Kind            MeetingAttachmentKind `json:"kind"`
+	URL             string                `json:"url"`
+	FileName        *string               `json:"file_name,omitempty"`
+	OccurrenceStart *time.Time            `json:"occurrence_start,omitempty"`
+	TranscriptText  *string               `json:"transcript_text,omitempty"`
+}
+
+// Validate validates the LinkMeetingAttachmentRequest
+func (r *LinkMeetingAttachmentRequest) Validate() error {
+	if !ValidMeetingAttachmentKinds[r.Kind] {
+		return fmt.Errorf("invalid kind: must be 'recording' or 'transcript'")
+	}
+	r.URL = strings.TrimSpace(r.URL)
+	if r.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	parsed, err := url.Parse(r.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if r.FileName != nil && len(*r.FileName) > 255 {
+		return fmt.Errorf("file_name must be less than 255 characters")
+	}
+	if r.TranscriptText != nil && len(*r.TranscriptText) > MaxTranscriptLength {
+		return fmt.Errorf("transcript_text is too large")
+	}
+	return nil
+}
+
+const (
+	MaxAgendaItems           = 50
+	MaxAgendaItemTitleLength = 200
+	MaxMeetingNotesLength    = 50000
+	MaxMeetingActionItems    = 50
+	MaxActionItemDescLength  = 255 // Action items become task titles
+)
+
+// MeetingAgendaItem is one topic on a meeting's agenda, in discussion order
+type MeetingAgendaItem struct {
+	ID              int64     `json:"id"`
+	MeetingID       int64     `json:"meeting_id"`
+	Position        int       `json:"position"`
+	Title           string    `json:"title"`
+	DurationMinutes *int      `json:"duration_minutes,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// MeetingAgendaItemInput describes one agenda topic in an UpdateMeetingAgendaRequest
+type MeetingAgendaItemInput struct {
+	Title           string `json:"title"`
+	DurationMinutes *int   `json:"duration_minutes,omitempty"`
+}
+
+// UpdateMeetingAgendaRequest replaces a meeting's agenda; items are stored in the order given
+type UpdateMeetingAgendaRequest struct {
+	Items []MeetingAgendaItemInput `json:"items"`
+}
+
+// Validate validates the UpdateMeetingAgendaRequest
+func (r *UpdateMeetingAgendaRequest) Validate() error {
+	if len(r.Items) > MaxAgendaItems {
+		return fmt.Errorf("an agenda can have at most %d items", MaxAgendaItems)
+	}
+	for i := range r.Items {
+		item := &r.Items[i]
+		item.Title = strings.TrimSpace(item.Title)
+		if item.Title == "" {
+			return fmt.Errorf("agenda item %d: title is required", i+1)
+		}
+		if len(item.Title) > MaxAgendaItemTitleLength {
+			return fmt.Errorf("agenda item %d: title must be less than %d characters", i+1, MaxAgendaItemTitleLength)
+		}
+		if item.DurationMinutes != nil && (*item.DurationMinutes < 1 || *item.DurationMinutes > 24*60) {
+			return fmt.Errorf("agenda item %d: duration_minutes must be between 1 and 1440", i+1)
+		}
+	}
+	return nil
+}
+
+// MeetingActionItem is a follow-up captured in a meeting's notes. TaskID is set once it has been converted to a task.
+type MeetingActionItem struct {
+	ID          int64      `json:"id"`
+	MeetingID   int64      `json:"meeting_id"`
+	Description string     `json:"description"`
+	AssigneeID  *int64     `json:"assignee_id,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	TaskID      *int64     `json:"task_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IsConverted reports whether the action item has already become a task
+func (a *MeetingActionItem) IsConverted() bool {
+	return a.TaskID != nil
+}
+
+// MeetingNotes holds a meeting's post-meeting notes and their action items
+type MeetingNotes struct {
+	MeetingID   int64               `json:"meeting_id"`
+	Notes       string              `json:"notes"`
+	UpdatedByID *int64              `json:"updated_by_id,omitempty"`
+	UpdatedAt   *time.Time          `json:"updated_at,omitempty"` // Nil until notes are first saved
+	ActionItems []MeetingActionItem `json:"action_items"`
+}
+
+// MeetingActionItemInput describes one action item in an UpdateMeetingNotesRequest
+type MeetingActionItemInput struct {
+	Description string     `json:"description"`
+	AssigneeID  *int64     `json:"assignee_id,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+}
+
+// UpdateMeetingNotesRequest saves a meeting's notes. The action items replace every action item that
+// hasn't been converted to a task yet; converted ones are kept as they are.
+type UpdateMeetingNotesRequest struct {
+	Notes       string                   `json:"notes"`
+	ActionItems []MeetingActionItemInput `json:"action_items"`
+}
+
+// Validate validates the UpdateMeetingNotesRequest
+func (r *UpdateMeetingNotesRequest) Validate() error {
+	if len(r.Notes) > MaxMeetingNotesLength {
+		return fmt.Errorf("notes must be less than %d characters", MaxMeetingNotesLength)
+	}
+	if len(r.ActionItems) > MaxMeetingActionItems {
+		return fmt.Errorf("notes can have at most %d action items", MaxMeetingActionItems)
+	}
+	for i := range r.ActionItems {
+		item := &r.ActionItems[i]
+		item.Description = strings.TrimSpace(item.Description)
+		if item.Description == "" {
+			return fmt.Errorf("action item %d: description is required", i+1)
+		}
+		if len(item.Description) > MaxActionItemDescLength {
+			return fmt.Errorf("action item %d: description must be less than %d characters", i+1, MaxActionItemDescLength)
+		}
+	}
+	return nil
+}
+
+// ConvertActionItemsRequest selects which action items to turn into tasks.
+// With no IDs, every unconverted action item that has an assignee is converted.
+type ConvertActionItemsRequest struct {
+	ActionItemIDs []int64 `json:"action_item_ids,omitempty"`
+}
+
+// Validate validates the ConvertActionItemsRequest
+func (r *ConvertActionItemsRequest) Validate() error {
+	if len(r.ActionItemIDs) > MaxMeetingActionItems {
+		return fmt.Errorf("at most %d action items can be converted at once", MaxMeetingActionItems)
+	}
+	return nil
+}
+
+// TranscriptSearchResult represents a transcript matching a search query
+type TranscriptSearchResult struct {
+	Attachment   MeetingAttachment `json:"attachment"`
+	MeetingTitle string            `json:"meeting_title"`
+	Snippet      string            `json:"snippet"`
+	Rank         float64           `json:"rank"`
+}
+
+// SearchResponse represents results from the global search endpoint, grouped by source
+type SearchResponse struct {
+	Query       string                   `json:"query"`
+	Transcripts []TranscriptSearchResult `json:"transcripts"`
+}
+
+// CalendarEventType represents the type of calendar event
+type CalendarEventType string
+
+const (
+	CalendarEventTypeJira    CalendarEventType = "jira"
+	CalendarEventTypeTask    CalendarEventType = "task"
+	CalendarEventTypeMeeting CalendarEventType = "meeting"
+	CalendarEventTypeTimeOff CalendarEventType = "time_off"
+	// Birthdays and work anniversaries of the viewer's team
+	CalendarEventTypeMilestone CalendarEventType = "milestone"
+)
+
+// CalendarEvent represents a unified calendar event (Jira, Task, Meeting, TimeOff, or Milestone)
+type CalendarEvent struct {
+	ID             string            `json:"id"`
+	Type           CalendarEventType `json:"type"`
+	Title          string            `json:"title"`
+	Start          time.Time         `json:"start"`
+	End            *time.Time        `json:"end,omitempty"`
+	AllDay         bool              `json:"all_day"`
+	URL            *string           `json:"url,omitempty"`
+	Task           *Task             `json:"task,omitempty"`
+	Meeting        *Meeting          `json:"meeting,omitempty"`
+	JiraIssue      *JiraIssue        `json:"jira_issue,omitempty"`
+	TimeOffRequest *TimeOffRequest   `json:"time_off_request,omitempty"`
+	Milestone      *TeamMilestone    `json:"milestone,omitempty"`
+	// Local representation of Start and End: RFC 3339 times in the organizer's timezone for
+	// meetings, and dates in the viewer's timezone for all-day events
+	Timezone   string  `json:"timezone"`
+	LocalStart string  `json:"local_start"`
+	LocalEnd   *string `json:"local_end,omitempty"`
+}
+
+// Localize sets the event's local representation and normalizes Start and End to UTC.
+// All-day events are dates, so they're pinned to the start of those days in loc; meetings are
+// shown in their own timezone instead.
+func (e *CalendarEvent) Localize(loc *time.Location) {
+	if e.AllDay {
+		e.Timezone = loc.String()
+		e.LocalStart = e.Start.UTC().Format("2006-01-02")
+		e.Start = LocalDayStart(e.Start, loc).UTC()
+		if e.End != nil {
+			localEnd := e.End.UTC().Format("2006-01-02")
+			end := LocalDayStart(*e.End, loc).UTC()
+			e.LocalEnd = &localEnd
+			e.End = &end
+		}
+		return
+	}
+
+	if e.Meeting != nil {
+		loc = e.Meeting.Location()
+	}
+	e.Timezone = loc.String()
+	e.LocalStart = e.Start.In(loc).Format(time.RFC3339)
+	e.Start = e.Start.UTC()
+	if e.End != nil {
+		localEnd := e.End.In(loc).Format(time.RFC3339)
+		end := e.End.UTC()
+		e.LocalEnd = &localEnd
+		e.End = &end
+	}
+}
+
+// CalendarEventTypes lists the calendar sources in the order their events are returned
+var CalendarEventTypes = []CalendarEventType{
+	CalendarEventTypeTask,
+	CalendarEventTypeMeeting,
+	CalendarEventTypeJira,
+	CalendarEventTypeTimeOff,
+	CalendarEventTypeMilestone,
+}
+
+// MaxCalendarWindowDays caps the date range a single calendar events request may span
+const MaxCalendarWindowDays = 92
+
+// ValidateCalendarWindow checks that a calendar date range is ordered and within MaxCalendarWindowDays
+func ValidateCalendarWindow(start, end time.Time) error {
+	if end.Before(start) {
+		return fmt.Errorf("end must not be before start")
+	}
+	if end.Sub(start) > MaxCalendarWindowDays*24*time.Hour {
+		return fmt.Errorf("date range cannot exceed %d days", MaxCalendarWindowDays)
+	}
+	return nil
+}
+
+// CalendarSourceCursor identifies the last event returned from one calendar source
+type CalendarSourceCursor struct {
+	Start time.Time `json:"s"`
+	ID    string    `json:"i"`
+}
+
+// CalendarCursor holds a position for each calendar source that has more events.
+// Sources missing from a cursor have been fully paged through.
+type CalendarCursor map[CalendarEventType]CalendarSourceCursor
+
+// Encode returns an opaque, URL-safe representation of the cursor
+func (c CalendarCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCalendarCursor parses a cursor previously produced by CalendarCursor.Encode
+func DecodeCalendarCursor(s string) (CalendarCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding")
+	}
+	var cursor CalendarCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || len(cursor) == 0 {
+		return nil, fmt.Errorf("invalid cursor format")
+	}
+	for source := range cursor {
+		switch source {
+		case CalendarEventTypeTask, CalendarEventTypeMeeting, CalendarEventTypeJira, CalendarEventTypeTimeOff, CalendarEventTypeMilestone:
+		default:
+			return nil, fmt.Errorf("invalid cursor source")
+		}
+	}
+	return cursor, nil
+}
+
+// ============================================================================
+// Time Off Types
+// ============================================================================
+
+// TimeOffType represents the type of time off request
+type TimeOffType string
+
+const (
+	TimeOffTypeVacation    TimeOffType = "vacation"
+	TimeOffTypeSick        TimeOffType = "sick"
+	TimeOffTypePersonal    TimeOffType = "personal"
+	TimeOffTypeBereavement TimeOffType = "bereavement"
+	TimeOffTypeJuryDuty    TimeOffType = "jury_duty"
+	TimeOffTypeOther       TimeOffType = "other"
+)
+
+// ValidTimeOffTypes contains all valid time off type values
+var ValidTimeOffTypes = map[TimeOffType]bool{
+	TimeOffTypeVacation:    true,
+	TimeOffTypeSick:        true,
+	TimeOffTypePersonal:    true,
+	TimeOffTypeBereavement: true,
+	TimeOffTypeJuryDuty:    true,
+	TimeOffTypeOther:       true,
+}
+
+// TimeOffStatus represents the status of a time off request
+type TimeOffStatus string
+
+const (
+	TimeOffStatusPending   TimeOffStatus = "pending"
+	TimeOffStatusApproved  TimeOffStatus = "approved"
+	TimeOffStatusRejected  TimeOffStatus = "rejected"
+	TimeOffStatusCancelled TimeOffStatus = "cancelled"
+)
+
+// ValidTimeOffStatuses contains all valid time off status values
+var ValidTimeOffStatuses = map[TimeOffStatus]bool{
+	TimeOffStatusPending:   true,
+	TimeOffStatusApproved:  true,
+	TimeOffStatusRejected:  true,
+	TimeOffStatusCancelled: true,
+}
+
+// TimeOffRequest represents a time off request
+type TimeOffRequest struct {
+	ID            int64         `json:"id"`
+	UserID        int64         `json:"user_id"`
+	User          *User         `json:"user,omitempty"`
+	StartDate     time.Time     `json:"start_date"`
+	EndDate       time.Time     `json:"end_date"`
+	RequestType   TimeOffType   `json:"request_type"`
+	Reason        *string       `json:"reason,omitempty"`
+	Status        TimeOffStatus `json:"status"`
+	ReviewerID    *int64        `json:"reviewer_id,omitempty"`
+	Reviewer      *User         `json:"reviewer,omitempty"`
+	ReviewerNotes *string       `json:"reviewer_notes,omitempty"`
+	ReviewedAt    *time.Time    `json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// WithoutNotes returns a copy of the request without the requester's reason or the reviewer's notes
+func (t TimeOffRequest) WithoutNotes() TimeOffRequest {
+	t.Reason = nil
+	t.ReviewerNotes = nil
+	return t
+}
+
+// CreateTimeOffRequestInput represents a request to create a time off request
+type CreateTimeOffRequestInput struct {
+	StartDate   string      `json:"start_date"`
+	EndDate     string      `json:"end_date"`
+	RequestType TimeOffType `json:"request_type"`
+	Reason      *string     `json:"reason,omitempty"`
+	// For supervisor/admin to create time off for another user
+	UserID      *int64 `json:"user_id,omitempty"`
+	AutoApprove bool   `json:"auto_approve,omitempty"`
+}
+
+// Validate validates the CreateTimeOffRequestInput
+func (r *CreateTimeOffRequestInput) Validate() error {
+	if r.StartDate == "" {
+		return fmt.Errorf("start_date is required")
+	}
+	if r.EndDate == "" {
+		return fmt.Errorf("end_date is required")
+	}
+	if !ValidTimeOffTypes[r.RequestType] {
+		return fmt.Errorf("invalid request_type: must be 'vacation', 'sick', 'personal', 'bereavement', 'jury_duty', or 'other'")
+	}
+	// Parse and validate dates
+	startDate, err := time.Parse("2006-01-02", r.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start_date format: use YYYY-MM-DD")
+	}
+	endDate, err := time.Parse("2006-01-02", r.EndDate)
+	if err != nil {
+		return fmt.Errorf("invalid end_date format: use YYYY-MM-DD")
+	}
+	if endDate.Before(startDate) {
+		return fmt.Errorf("end_date must be on or after start_date")
+	}
+	return nil
+}
+
+// ReviewTimeOffRequestInput represents a request to review a time off request
+type ReviewTimeOffRequestInput struct {
+	Status        TimeOffStatus `json:"status"`
+	ReviewerNotes *string       `json:"reviewer_notes,omitempty"`
+}
+
+// Validate validates the ReviewTimeOffRequestInput
+func (r *ReviewTimeOffRequestInput) Validate() error {
+	if r.Status != TimeOffStatusApproved && r.Status != TimeOffStatusRejected {
+		return fmt.Errorf("status must be 'approved' or 'rejected'")
+	}
+	return nil
+}
+
+// TimeOffImpact represents the impact of time off on a Jira task
+type TimeOffImpact struct {
+	HasTimeOff       bool    `json:"has_time_off"`
+	TimeOffDays      int     `json:"time_off_days"`
+	RemainingDays    int     `json:"remaining_days"`
+	ImpactPercent    float64 `json:"impact_percent"`
+}
+
+// TimeOffSortField represents a column time off listings can be sorted by
+type TimeOffSortField string
+
+const (
+	TimeOffSortStartDate TimeOffSortField = "start_date"
+	TimeOffSortCreatedAt TimeOffSortField = "created_at"
+)
+
+// ValidTimeOffSortFields contains all valid time off sort field values
+var ValidTimeOffSortFields = map[TimeOffSortField]bool{
+	TimeOffSortStartDate: true,
+	TimeOffSortCreatedAt: true,
+}
+
+// TimeOffCursor identifies the last row of a page for keyset pagination
+type TimeOffCursor struct {
+	SortValue time.Time
+	ID        int64
+}
+
+// Encode returns an opaque, URL-safe representation of the cursor
+func (c TimeOffCursor) Encode() string {
+	raw := c.SortValue.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeTimeOffCursor parses a cursor previously produced by TimeOffCursor.Encode
+func DecodeTimeOffCursor(s string) (*TimeOffCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding")
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor format")
+	}
+	sortValue, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor timestamp")
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor id")
+	}
+	return &TimeOffCursor{SortValue: sortValue, ID: id}, nil
+}
+
+// TimeOffScope selects whose time off requests a listing covers
+type TimeOffScope string
+
+const (
+	TimeOffScopeMine TimeOffScope = "mine" // only the current user's requests
+	TimeOffScopeTeam TimeOffScope = "team" // the current user's and their direct reports' requests
+	TimeOffScopeAll  TimeOffScope = "all"  // every request in the organization (admin only)
+)
+
+// ValidTimeOffScopes contains all valid time off scope values
+var ValidTimeOffScopes = map[TimeOffScope]bool{
+	TimeOffScopeMine: true,
+	TimeOffScopeTeam: true,
+	TimeOffScopeAll:  true,
+}
+
+// TimeOffFilter holds optional filters, sorting, and cursor pagination for time off listings
+type TimeOffFilter struct {
+	UserID      *int64
+	Status      *TimeOffStatus
+	RequestType *TimeOffType
+	// From and To select requests overlapping the given date range (inclusive)
+	From    *time.Time
+	To      *time.Time
+	SortBy  TimeOffSortField
+	SortAsc bool
+	Cursor  *TimeOffCursor
+	// Limit caps the number of rows returned; zero means no limit
+	Limit int
+}
+
+// Validate validates the TimeOffFilter
+func (f *TimeOffFilter) Validate() error {
+	if f.Status != nil && !ValidTimeOffStatuses[*f.Status] {
+		return fmt.Errorf("invalid status")
+	}
+	if f.RequestType != nil && !ValidTimeOffTypes[*f.RequestType] {
+		return fmt.Errorf("invalid request_type")
+	}
+	if f.From != nil && f.To != nil && f.To.Before(*f.From) {
+		return fmt.Errorf("to must be on or after from")
+	}
+	if f.SortBy != "" && !ValidTimeOffSortFields[f.SortBy] {
+		return fmt.Errorf("invalid sort: must be 'start_date' or 'created_at'")
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	return nil
+}
+
+// TimeOffRequestPage is a cursor-paginated slice of time off requests
+type TimeOffRequestPage struct {
+	Data       []TimeOffRequest `json:"data"`
+	NextCursor *string          `json:"next_cursor,omitempty"`
+	HasMore    bool             `json:"has_more"`
+}
+
+// ============================================================================
+// Jira OAuth Types
+// ============================================================================
+
+// JiraOAuthTokens holds OAuth tokens for user Jira integration
+type JiraOAuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+	CloudID      string
+	SiteURL      string
+}
+
+// OrgJiraSettings represents organization-wide Jira OAuth settings
+type OrgJiraSettings struct {
+	ID                  int64     `json:"id"`
+	OAuthAccessToken    string    `json:"-"` // Never expose
+	OAuthRefreshToken   string    `json:"-"` // Never expose
+	OAuthTokenExpiresAt time.Time `json:"-"` // Never expose
+	CloudID             string    `json:"cloud_id"`
+	SiteURL             string    `json:"site_url"`
+	SiteName            *string   `json:"site_name,omitempty"`
+	ConfiguredByID      int64     `json:"configured_by_id"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+
+	// Connection health
+	LastRefreshedAt    *time.Time `json:"last_refreshed_at,omitempty"`
+	RefreshFailures    int        `json:"refresh_failures"`
+	LastRefreshError   *string    `json:"last_refresh_error,omitempty"`
+	LastRefreshErrorAt *time.Time `json:"last_refresh_error_at,omitempty"`
+	LastAPIError       *string    `json:"last_api_error,omitempty"`
+	LastAPIErrorAt     *time.Time `json:"last_api_error_at,omitempty"`
+	LastWebhookAt      *time.Time `json:"last_webhook_at,omitempty"`
+
+	// Named JQL filters for the team task view
+	TaskFilters []JiraTaskFilter `json:"task_filters"`
+}
+
+// TaskFilter returns the task filter with the given name, if any
+func (s *OrgJiraSettings) TaskFilter(name string) (*JiraTaskFilter, bool) {
+	for i := range s.TaskFilters {
+		if strings.EqualFold(s.TaskFilters[i].Name, name) {
+			return &s.TaskFilters[i], true
+		}
+	}
+	return nil, false
+}
+
+// IsTokenExpired checks if the OAuth token is expired (with 5 min buffer)
+func (s *OrgJiraSettings) IsTokenExpired() bool {
+	return time.Now().Add(5 * time.Minute).After(s.OAuthTokenExpiresAt)
+}
+
+// JiraSite represents an Atlassian Cloud site returned by the accessible-resources API
+type JiraSite struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	URL       string   `json:"url"`
+	Scopes    []string `json:"scopes,omitempty"`
+	AvatarURL string   `json:"avatar_url,omitempty"`
+}
+
+// JiraPendingConnection holds OAuth tokens and the accessible sites from a completed
+// OAuth callback until an admin selects which site to connect
+type JiraPendingConnection struct {
+	ID                  int64      `json:"id"`
+	OAuthAccessToken    string     `json:"-"` // Never expose
+	OAuthRefreshToken   string     `json:"-"` // Never expose
+	OAuthTokenExpiresAt time.Time  `json:"-"` // Never expose
+	Sites               []JiraSite `json:"sites"`
+	ConfiguredByID      int64      `json:"configured_by_id"`
+	ExpiresAt           time.Time  `json:"expires_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// FindSite returns the site with the given cloud ID, or nil if it is not in the list
+func (p *JiraPendingConnection) FindSite(cloudID string) *JiraSite {
+	for i := range p.Sites {
+		if p.Sites[i].ID == cloudID {
+			return &p.Sites[i]
+		}
+	}
+	return nil
+}
+
+// SelectJiraSiteRequest represents a request to connect one of the pending Jira sites
+type SelectJiraSiteRequest struct {
+	CloudID string `json:"cloud_id"`
+}
+
+// Validate validates the SelectJiraSiteRequest
+func (r *SelectJiraSiteRequest) Validate() error {
+	r.CloudID = strings.TrimSpace(r.CloudID)
+	if r.CloudID == "" {
+		return fmt.Errorf("cloud_id is required")
+	}
+	return nil
+}
+
+// JiraHealthStatus summarizes the state of the org-wide Jira connection
+type JiraHealthStatus string
+
+const (
+	JiraHealthHealthy      JiraHealthStatus = "healthy"
+	JiraHealthDegraded     JiraHealthStatus = "degraded"     // Working, but recent API errors or a stale webhook
+	JiraHealthFailing      JiraHealthStatus = "failing"      // Token refresh is failing; Jira calls will stop working
+	JiraHealthDisconnected JiraHealthStatus = "disconnected" // No Jira site is connected
+)
+
+// JiraHealth reports token and delivery state for the org-wide Jira connection
+type JiraHealth struct {
+	Status             JiraHealthStatus  `json:"status"`
+	SiteURL            string            `json:"site_url,omitempty"`
+	TokenExpiresAt     *time.Time        `json:"token_expires_at,omitempty"`
+	TokenExpired       bool              `json:"token_expired"`
+	LastRefreshedAt    *time.Time        `json:"last_refreshed_at,omitempty"`
+	RefreshFailures    int               `json:"refresh_failures"`
+	LastRefreshError   *string           `json:"last_refresh_error,omitempty"`
+	LastRefreshErrorAt *time.Time        `json:"last_refresh_error_at,omitempty"`
+	LastAPIError       *string           `json:"last_api_error,omitempty"`
+	LastAPIErrorAt     *time.Time        `json:"last_api_error_at,omitempty"`
+	LastWebhookAt      *time.Time        `json:"last_webhook_at,omitempty"`
+	LastSyncAt         *time.Time        `json:"last_sync_at,omitempty"` // Most recent successful issue cache sync
+	TokenCheck         *JiraTokenCheck   `json:"token_check,omitempty"`
+	APICalls           *JiraAPICallStats `json:"api_calls,omitempty"`
+	CircuitOpen        bool              `json:"circuit_open"` // Jira calls are paused after repeated failures
+	Warnings           []string          `json:"warnings"`
+}
+
+// JiraTokenCheck is the result of verifying the stored tokens against Atlassian
+type JiraTokenCheck struct {
+	Verified      bool      `json:"verified"`
+	Error         string    `json:"error,omitempty"`
+	Scopes        []string  `json:"scopes"`         // Granted on the connected site
+	MissingScopes []string  `json:"missing_scopes"` // Requested when connecting but not granted
+	CheckedAt     time.Time `json:"checked_at"`
+}
+
+// JiraAPICallStats counts recent Jira API calls made over the org-wide connection
+type JiraAPICallStats struct {
+	WindowMinutes int     `json:"window_minutes"`
+	Calls         int     `json:"calls"`
+	Failures      int     `json:"failures"`
+	ErrorRate     float64 `json:"error_rate"` // Failures / calls; 0 when there were no calls
+}
+
+// ExportType identifies the dataset an export job produces
+type ExportType string
+
+const (
+	ExportTypeEmployees ExportType = "employees"
+	ExportTypeTimeOff   ExportType = "time_off"
+)
+
+// ValidExportTypes contains all valid export types
+var ValidExportTypes = map[ExportType]bool{
+	ExportTypeEmployees: true,
+	ExportTypeTimeOff:   true,
+}
+
+// ExportFormat is the file format an export job writes
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatJSON ExportFormat = "json"
+)
+
+// ValidExportFormats contains all valid export formats
+var ValidExportFormats = map[ExportFormat]bool{
+	ExportFormatCSV:  true,
+	ExportFormatJSON: true,
+}
+
+// TableFormat is the file format a list endpoint downloads instead of JSON
+type TableFormat string
+
+const (
+	TableFormatCSV  TableFormat = "csv"
+	TableFormatXLSX TableFormat = "xlsx"
+)
+
+// ValidTableFormats contains all valid list download formats
+var ValidTableFormats = map[TableFormat]bool{
+	TableFormatCSV:  true,
+	TableFormatXLSX: true,
+}
+
+// ExportJobStatus represents the progress of an export job
+type ExportJobStatus string
+
+const (
+	ExportJobStatusPending   ExportJobStatus = "pending"
+	ExportJobStatusRunning   ExportJobStatus = "running"
+	ExportJobStatusCompleted ExportJobStatus = "completed"
+	ExportJobStatusFailed    ExportJobStatus = "failed"
+	ExportJobStatusExpired   ExportJobStatus = "expired" // The file was removed after its retention period
+)
+
+// ExportFilters narrows the rows an export job includes. Fields that don't apply to the
+// export type are rejected by validation.
+type ExportFilters struct {
+	Department  *string        `json:"department,omitempty"`   // employees
+	Status      *TimeOffStatus `json:"status,omitempty"`       // time_off
+	RequestType *TimeOffType   `json:"request_type,omitempty"` // time_off
+	From        *string        `json:"from,omitempty"`         // time_off, YYYY-MM-DD
+	To          *string        `json:"to,omitempty"`           // time_off, YYYY-MM-DD
+}
+
+// TimeOffFilter converts the filters into a time off query without a row limit
+func (f ExportFilters) TimeOffFilter() (TimeOffFilter, error) {
+	filter := TimeOffFilter{Status: f.Status, RequestType: f.RequestType, SortAsc: true}
+	if f.From != nil {
+		parsed, err := time.Parse("2006-01-02", *f.From)
+		if err != nil {
+			return filter, fmt.Errorf("from must use YYYY-MM-DD")
+		}
+		filter.From = &parsed
+	}
+	if f.To != nil {
+		parsed, err := time.Parse("2006-01-02", *f.To)
+		if err != nil {
+			return filter, fmt.Errorf("to must use YYYY-MM-DD")
+		}
+		filter.To = &parsed
+	}
+	return filter, filter.Validate()
+}
+
+// ExportJob is a request to generate a downloadable file in the background
+type ExportJob struct {
+	ID            int64           `json:"id"`
+	RequestedByID int64           `json:"requested_by_id"`
+	Type          ExportType      `json:"type"`
+	Format        ExportFormat    `json:"format"`
+	Filters       ExportFilters   `json:"filters"`
+	Status        ExportJobStatus `json:"status"`
+	StorageKey    *string         `json:"-"`
+	FileName      *string         `json:"file_name,omitempty"`
+	RowCount      *int            `json:"row_count,omitempty"`
+	Error         *string         `json:"error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	StartedAt     *time.Time      `json:"started_at,omitempty"`
+	CompletedAt   *time.Time      `json:"completed_at,omitempty"`
+	ExpiresAt     *time.Time      `json:"expires_at,omitempty"` // When the generated file is removed
+	// Set on completed jobs when the status is fetched; the link itself expires after a few minutes
+	DownloadURL *string `json:"download_url,omitempty"`
+}
+
+// IsFinished reports whether the worker is done with the job, successfully or not
+func (j *ExportJob) IsFinished() bool {
+	return j.Status != ExportJobStatusPending && j.Status != ExportJobStatusRunning
+}
+
+// CreateExportJobRequest represents a request to start an export
+type CreateExportJobRequest struct {
+	Type    ExportType    `json:"type"`
+	Format  ExportFormat  `json:"format"`
+	Filters ExportFilters `json:"filters"`
+}
+
+// Validate validates the CreateExportJobRequest, defaulting the format to CSV
+func (r *CreateExportJobRequest) Validate() error {
+	if !ValidExportTypes[r.Type] {
+		return fmt.Errorf("invalid type: must be 'employees' or 'time_off'")
+	}
+	if r.Format == "" {
+		r.Format = ExportFormatCSV
+	}
+	if !ValidExportFormats[r.Format] {
+		return fmt.Errorf("invalid format: must be 'csv' or 'json'")
+	}
+
+	f := r.Filters
+	switch r.Type {
+	case ExportTypeEmployees:
+		if f.Status != nil || f.RequestType != nil || f.From != nil || f.To != nil {
+			return fmt.Errorf("employees exports only support the department filter")
+		}
+	case ExportTypeTimeOff:
+		if f.Department != nil {
+			return fmt.Errorf("time_off exports do not support the department filter")
+		}
+		if _, err := f.TimeOffFilter(); err != nil {
+			return fmt.Errorf("invalid filters: %w", err)
+		}
+	}
+	return nil
+}
+
+// WebhookTestEventType is sent to a single endpoint when an admin test-fires it. Endpoints can't
+// subscribe to it.
+const WebhookTestEventType = "webhook.test"
+
+// ValidWebhookEventTypes lists the lifecycle events webhook endpoints can subscribe to.
+// The names match the event types published on the events broker.
+var ValidWebhookEventTypes = map[string]bool{
+	"task.created":       true,
+	"task.updated":       true,
+	"task.deleted":       true,
+	"meeting.created":    true,
+	"meeting.updated":    true,
+	"meeting.deleted":    true,
+	"time_off.requested": true,
+	"time_off.reviewed":  true,
+	"time_off.cancelled": true,
+}
+
+const (
+	MaxWebhookURLLength = 2048
+	MaxWebhookAttempts  = 8 // Deliveries are given up on after this many failed attempts
+)
+
+// WebhookEndpoint is an external URL that receives signed callbacks for lifecycle events
+type WebhookEndpoint struct {
+	ID          int64     `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	EventTypes  []string  `json:"event_types"` // Empty subscribes to every event
+	IsActive    bool      `json:"is_active"`
+	Secret      string    `json:"-"` // Signs deliveries; only shown when created or rotated
+	OrgID       int64     `json:"-"`
+	CreatedByID *int64    `json:"created_by_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Subscribes reports whether the endpoint receives events of the given type
+func (e *WebhookEndpoint) Subscribes(eventType string) bool {
+	if len(e.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range e.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEndpointWithSecret is returned when an endpoint is created or its secret is rotated,
+// the only times the signing secret is shown
+type WebhookEndpointWithSecret struct {
+	*WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+// IsPublicWebhookAddress reports whether webhooks may be delivered to an address: anything but
+// private, loopback, link-local, and unspecified addresses, which reach the server's own network
+func IsPublicWebhookAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return !(addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsUnspecified())
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute http or https URL that doesn't
+// obviously point inside the network. Names can resolve anywhere, so deliveries check the
+// addresses they connect to as well.
+func validateWebhookURL(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("url is required")
+	}
+	if len(raw) > MaxWebhookURLLength {
+		return fmt.Errorf("url must be less than %d characters", MaxWebhookURLLength)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("url must not point to a private or local address")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicWebhookAddress(addr) {
+		return fmt.Errorf("url must not point to a private or local address")
+	}
+	return nil
+}
+
+// normalizeWebhookEventTypes validates event types and drops duplicates
+func normalizeWebhookEventTypes(eventTypes []string) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool, len(eventTypes))
+	for _, t := range eventTypes {
+		t = strings.TrimSpace(t)
+		if !ValidWebhookEventTypes[t] {
+			return nil, fmt.Errorf("invalid event type %q", t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			normalized = append(normalized, t)
+		}
+	}
+	return normalized, nil
+}
+
+// CreateWebhookEndpointRequest represents a request to register a webhook endpoint
+type CreateWebhookEndpointRequest struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types"`
+}
+
+// Validate validates the CreateWebhookEndpointRequest
+func (r *CreateWebhookEndpointRequest) Validate() error {
+	r.URL = strings.TrimSpace(r.URL)
+	if err := validateWebhookURL(r.URL); err != nil {
+		return err
+	}
+	r.Description = strings.TrimSpace(r.Description)
+	if len(r.Description) > 255 {
+		return fmt.Errorf("description must be less than 255 characters")
+	}
+	eventTypes, err := normalizeWebhookEventTypes(r.EventTypes)
+	if err != nil {
+		return err
+	}
+	r.EventTypes = eventTypes
+	return nil
+}
+
+// UpdateWebhookEndpointRequest represents a request to update a webhook endpoint
+type UpdateWebhookEndpointRequest struct {
+	URL          *string   `json:"url,omitempty"`
+	Description  *string   `json:"description,omitempty"`
+	EventTypes   *[]string `json:"event_types,omitempty"` // Replaces the subscriptions; an empty list subscribes to everything
+	IsActive     *bool     `json:"is_active,omitempty"`
+	RotateSecret bool      `json:"rotate_secret,omitempty"`
+}
+
+// Validate validates the UpdateWebhookEndpointRequest
+func (r *UpdateWebhookEndpointRequest) Validate() error {
+	if r.URL != nil {
+		*r.URL = strings.TrimSpace(*r.URL)
+		if err := validateWebhookURL(*r.URL); err != nil {
+			return err
+		}
+	}
+	if r.Description != nil {
+		*r.Description = strings.TrimSpace(*r.Description)
+		if len(*r.Description) > 255 {
+			return fmt.Errorf("description must be less than 255 characters")
+		}
+	}
+	if r.EventTypes != nil {
+		eventTypes, err := normalizeWebhookEventTypes(*r.EventTypes)
+		if err != nil {
+			return err
+		}
+		r.EventTypes = &eventTypes
+	}
+	return nil
+}
+
+// WebhookDeliveryStatus represents the progress of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending" // Waiting for its first or next attempt
+	WebhookDeliveryStatusSending   WebhookDeliveryStatus = "sending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed" // Gave up after MaxWebhookAttempts
+)
+
+// WebhookDelivery is one event sent, or waiting to be sent, to one endpoint
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	EndpointID     int64                 `json:"endpoint_id"`
+	EventType      string                `json:"event_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty"`
+	ResponseStatus *int                  `json:"response_status,omitempty"`
+	LastError      *string               `json:"last_error,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
+
+// ============================================================================
+// Outbox Types
+// ============================================================================
+
+// OutboxKind names the side effect an outbox message carries out
+type OutboxKind string
+
+const (
+	OutboxKindInvitationEmail      OutboxKind = "invitation_email"       // Payload is InvitationEmailPayload
+	OutboxKindMeetingResponseEmail OutboxKind = "meeting_response_email" // Payload is MeetingResponseEmailPayload
+	OutboxKindSlackMessage         OutboxKind = "slack_message"          // Payload is SlackMessagePayload
+	OutboxKindSupervisorDigest     OutboxKind = "supervisor_digest"      // Payload is SupervisorDigestEmailPayload
+)
+
+// OutboxMessageStatus represents the progress of an outbox message
+type OutboxMessageStatus string
+
+const (
+	OutboxMessageStatusPending OutboxMessageStatus = "pending" // Waiting for its first or next attempt
+	OutboxMessageStatusSending OutboxMessageStatus = "sending"
+	OutboxMessageStatusSent    OutboxMessageStatus = "sent"
+	OutboxMessageStatusFailed  OutboxMessageStatus = "failed" // Gave up after MaxOutboxAttempts
+)
+
+// MaxOutboxAttempts is how many times a side effect is tried before it is given up on
+const MaxOutboxAttempts = 8
+
+// OutboxMessage is a side effect, such as an email, saved with the change that causes it and carried
+// out afterwards by the outbox worker. It is never lost to a crash or a failed send, and never sent
+// for a change that was rolled back.
+type OutboxMessage struct {
+	ID            int64               `json:"id"`
+	Kind          OutboxKind          `json:"kind"`
+	Payload       json.RawMessage     `json:"payload"`
+	Status        OutboxMessageStatus `json:"status"`
+	Attempts      int                 `json:"attempts"`
+	NextAttemptAt time.Time           `json:"next_attempt_at"`
+	LastAttemptAt *time.Time          `json:"last_attempt_at,omitempty"`
+	LastError     *string             `json:"last_error,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	SentAt        *time.Time          `json:"sent_at,omitempty"`
+}
+
+// InvitationEmail asks for an invitation email to be queued along with the invitation
+type InvitationEmail struct {
+	InviterName string
+}
+
+// InvitationEmailPayload is everything needed to send an invitation email
+type InvitationEmailPayload struct {
+	InvitationID int64  `json:"invitation_id"`
+	Email        string `json:"email"`
+	Token        string `json:"token"`
+	Role         Role   `json:"role"`
+	InviterName  string `json:"inviter_name"`
+	// Organization whose templates are used, and the language to use; older messages have neither
+	OrgID    int64  `json:"org_id,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+// MeetingResponseEmailPayload tells a meeting organizer that an attendee responded
+type MeetingResponseEmailPayload struct {
+	MeetingID int64  `json:"meeting_id"`
+	To        string `json:"to"`
+	Subject   string `json:"subject"`
+	Text      string `json:"text"`
+	// The email is rendered from the meeting_response template with these variables, in the
+	// organizer's language. Older messages have none and are sent as Subject and Text.
+	OrgID     int64             `json:"org_id,omitempty"`
+	Language  string            `json:"language,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// SlackMessagePayload is a direct message to a user from the organization's Slack app.
+// The recipient is found in the workspace by email.
+type SlackMessagePayload struct {
+	OrgID int64  `json:"org_id"`
+	To    string `json:"to"`
+	Text  string `json:"text"`
+	// Adds Approve and Reject buttons for this time off request when set
+	ReviewTimeOffRequestID *int64 `json:"review_time_off_request_id,omitempty"`
+}
+
+// SupervisorDigestEmailPayload is a digest email to a supervisor
+type SupervisorDigestEmailPayload struct {
+	SupervisorID int64            `json:"supervisor_id"`
+	To           string           `json:"to"`
+	Digest       SupervisorDigest `json:"digest"`
+}
+
+// ============================================================================
+// Supervisor Digest Types
+// ============================================================================
+
+// DigestFrequency is how often supervisors are emailed a digest
+type DigestFrequency string
+
+const (
+	DigestFrequencyDaily  DigestFrequency = "daily"
+	DigestFrequencyWeekly DigestFrequency = "weekly" // Sent on Mondays
+)
+
+// SupervisorDigest summarizes what needs a supervisor's attention: their team's time off waiting
+// for review, overdue tasks, and upcoming absences, and meeting invites they haven't answered
+type SupervisorDigest struct {
+	SupervisorName    string          `json:"supervisor_name"`
+	Frequency         DigestFrequency `json:"frequency"`
+	PeriodStart       time.Time       `json:"period_start"` // Day or Monday the digest is for
+	PendingTimeOff    []DigestTimeOff `json:"pending_time_off"`
+	OverdueTasks      []DigestTask    `json:"overdue_tasks"`
+	UpcomingAbsences  []DigestTimeOff `json:"upcoming_absences"` // Approved time off under way or starting soon
+	UnansweredInvites []DigestMeeting `json:"unanswered_invites"`
+}
+
+// IsEmpty reports whether there's nothing to tell the supervisor
+func (d *SupervisorDigest) IsEmpty() bool {
+	return len(d.PendingTimeOff) == 0 && len(d.OverdueTasks) == 0 &&
+		len(d.UpcomingAbsences) == 0 && len(d.UnansweredInvites) == 0
+}
+
+// DigestTimeOff is a team member's time off in a digest
+type DigestTimeOff struct {
+	EmployeeName string      `json:"employee_name"`
+	RequestType  TimeOffType `json:"request_type"`
+	StartDate    time.Time   `json:"start_date"`
+	EndDate      time.Time   `json:"end_date"`
+}
+
+// DigestTask is a team member's overdue task in a digest
+type DigestTask struct {
+	Title        string    `json:"title"`
+	AssigneeName string    `json:"assignee_name"`
+	DueDate      time.Time `json:"due_date"`
+}
+
+// DigestMeeting is an unanswered meeting invite in a digest; recurring meetings are listed once,
+// at their next occurrence
+type DigestMeeting struct {
+	MeetingID int64     `json:"meeting_id"`
+	Title     string    `json:"title"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// ============================================================================
+// Email Template Types
+// ============================================================================
+
+// EmailTemplateKey names an email whose template organizations can customize
+type EmailTemplateKey string
+
+const (
+	EmailTemplateInvitation      EmailTemplateKey = "invitation"
+	EmailTemplateMeetingResponse EmailTemplateKey = "meeting_response"
+)
+
+// Limits on customized templates
+const (
+	MaxEmailSubjectLength = 255
+	MaxEmailBodyLength    = 100000
+)
+
+// EmailTemplate is an email's subject and bodies in one language, either built in or the
+// organization's override. Each part is a Go template filled in with the email's variables, such
+// as {{.InviterName}}; the HTML body escapes them.
+type EmailTemplate struct {
+	Key      EmailTemplateKey `json:"key"`
+	Language string           `json:"language"`
+	Subject  string           `json:"subject"`
+	HTMLBody string           `json:"html_body"`
+	TextBody string           `json:"text_body"`
+	// Set when the organization overrides the built-in template
+	Customized  bool       `json:"customized"`
+	UpdatedByID *int64     `json:"updated_by_id,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// EmailTemplateVariable is a value an email's templates can use
+type EmailTemplateVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Example     string `json:"example"` // Used when previewing
+}
+
+// EmailTemplateDefinition describes a customizable email
+type EmailTemplateDefinition struct {
+	Key         EmailTemplateKey        `json:"key"`
+	Description string                  `json:"description"`
+	Variables   []EmailTemplateVariable `json:"variables"`
+	// Languages the organization has overridden the built-in template in
+	CustomizedLanguages []string `json:"customized_languages"`
+}
+
+// UpdateEmailTemplateRequest overrides an email's built-in template in one language
+type UpdateEmailTemplateRequest struct {
+	Subject  string `json:"subject"`
+	HTMLBody string `json:"html_body"`
+	TextBody string `json:"text_body"`
+}
+
+// Validate validates the UpdateEmailTemplateRequest. Template syntax is checked when it's saved.
+func (r *UpdateEmailTemplateRequest) Validate() error {
+	if strings.TrimSpace(r.Subject) == "" || strings.TrimSpace(r.HTMLBody) == "" || strings.TrimSpace(r.TextBody) == "" {
+		return fmt.Errorf("subject, html_body, and text_body are required")
+	}
+	if len(r.Subject) > MaxEmailSubjectLength {
+		return fmt.Errorf("subject must be at most %d characters", MaxEmailSubjectLength)
+	}
+	if len(r.HTMLBody) > MaxEmailBodyLength || len(r.TextBody) > MaxEmailBodyLength {
+		return fmt.Errorf("bodies must be at most %d characters", MaxEmailBodyLength)
+	}
+	return nil
+}
+
+// PreviewEmailTemplateRequest renders an email with its example variables, overridden by
+// Variables. Template previews unsaved changes; without it the template in use is previewed.
+type PreviewEmailTemplateRequest struct {
+	Template  *UpdateEmailTemplateRequest `json:"template,omitempty"`
+	Variables map[string]string           `json:"variables,omitempty"`
+}
+
+// Validate validates the PreviewEmailTemplateRequest
+func (r *PreviewEmailTemplateRequest) Validate() error {
+	if r.Template != nil {
+		return r.Template.Validate()
+	}
+	return nil
+}
+
+// RenderedEmail is an email template filled in with its variables
+type RenderedEmail struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+// ============================================================================
+// GitHub Integration Types
+// ============================================================================
+
+// OrgGitHubSettings represents an organization's GitHub App connection
+type OrgGitHubSettings struct {
+	ID                  int64      `json:"id"`
+	OrgID               int64      `json:"-"`
+	OAuthAccessToken    string     `json:"-"`                   // Never expose
+	OAuthRefreshToken   string     `json:"-"`                   // Never expose
+	OAuthTokenExpiresAt *time.Time `json:"-"`                   // Never expose; nil when the app issues non-expiring tokens
+	AccountLogin        string     `json:"account_login"`       // GitHub account that authorized the app
+	OrgLogin            *string    `json:"org_login,omitempty"` // Pull request searches are limited to this org when set
+	ConfiguredByID      *int64     `json:"configured_by_id,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// IsTokenExpired checks if the OAuth token is expired (with 5 min buffer)
+func (s *OrgGitHubSettings) IsTokenExpired() bool {
+	return s.OAuthTokenExpiresAt != nil && time.Now().Add(5*time.Minute).After(*s.OAuthTokenExpiresAt)
+}
+
+// IsGitHubLogin reports whether s is a valid GitHub user or organization name: up to 39
+// letters, digits, and single hyphens, not starting or ending with a hyphen
+func IsGitHubLogin(s string) bool {
+	if s == "" || len(s) > 39 || s[0] == '-' || s[len(s)-1] == '-' || strings.Contains(s, "--") {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// validateGitHubLogin trims a login and checks it, treating an empty value as clearing it
+func validateGitHubLogin(field string, login *string) (*string, error) {
+	if login == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimPrefix(strings.TrimSpace(*login), "@")
+	if trimmed == "" {
+		return nil, nil
+	}
+	if !IsGitHubLogin(trimmed) {
+		return nil, fmt.Errorf("%s is not a valid GitHub name", field)
+	}
+	return &trimmed, nil
+}
+
+// UpdateGitHubSettingsRequest changes which GitHub organization pull requests are searched in
+type UpdateGitHubSettingsRequest struct {
+	OrgLogin *string `json:"org_login"` // Empty or null searches every repository the connection can see
+}
+
+// Validate validates the UpdateGitHubSettingsRequest
+func (r *UpdateGitHubSettingsRequest) Validate() error {
+	login, err := validateGitHubLogin("org_login", r.OrgLogin)
+	if err != nil {
+		return err
+	}
+	r.OrgLogin = login
+	return nil
+}
+
+// UpdateUserGitHubMappingRequest links an employee to a GitHub account
+type UpdateUserGitHubMappingRequest struct {
+	GitHubLogin *string `json:"github_login"` // Empty or null removes the link
+}
+
+// Validate validates the UpdateUserGitHubMappingRequest
+func (r *UpdateUserGitHubMappingRequest) Validate() error {
+	login, err := validateGitHubLogin("github_login", r.GitHubLogin)
+	if err != nil {
+		return err
+	}
+	r.GitHubLogin = login
+	return nil
+}
+
+// GitHubPullRequestRole is how a mapped employee is involved in an open pull request
+type GitHubPullRequestRole string
+
+const (
+	GitHubPullRequestAssignee GitHubPullRequestRole = "assignee"
+	GitHubPullRequestReviewer GitHubPullRequestRole = "reviewer" // The employee's review has been requested
+)
+
+// GitHubPullRequest is an open pull request from GitHub
+type GitHubPullRequest struct {
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	URL        string    `json:"url"`
+	Repository string    `json:"repository"` // owner/name
+	Author     string    `json:"author"`
+	Draft      bool      `json:"draft"`
+	Labels     []string  `json:"labels"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+}
+
+// ============================================================================
+// Slack Integration Types
+// ============================================================================
+
+// OrgSlackSettings represents an organization's Slack app installation
+type OrgSlackSettings struct {
+	ID             int64     `json:"id"`
+	OrgID          int64     `json:"-"`
+	BotAccessToken string    `json:"-"`       // Never expose
+	TeamID         string    `json:"team_id"` // Slack workspace the app is installed in
+	TeamName       string    `json:"team_name"`
+	BotUserID      string    `json:"-"`
+	ConfiguredByID *int64    `json:"configured_by_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ============================================================================
+// Linear Integration Types
+// ============================================================================
+
+// OrgLinearSettings represents an organization's Linear connection
+type OrgLinearSettings struct {
+	ID              int64     `json:"id"`
+	OrgID           int64     `json:"-"`
+	APIKey          string    `json:"-"` // Never expose
+	WorkspaceName   string    `json:"workspace_name"`
+	WorkspaceURLKey string    `json:"workspace_url_key"` // linear.app/{url_key}
+	ConfiguredByID  *int64    `json:"configured_by_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// UpdateLinearSettingsRequest connects Linear with a workspace API key
+type UpdateLinearSettingsRequest struct {
+	APIKey string `json:"api_key"`
+}
+
+// Validate validates the UpdateLinearSettingsRequest
+func (r *UpdateLinearSettingsRequest) Validate() error {
+	r.APIKey = strings.TrimSpace(r.APIKey)
+	if r.APIKey == "" {
+		return fmt.Errorf("api_key is required")
+	}
+	if !strings.HasPrefix(r.APIKey, "lin_api_") {
+		return fmt.Errorf("api_key must be a Linear API key (lin_api_...)")
+	}
+	return nil
+}
+
+// LinearUser is a member of the connected Linear workspace
+type LinearUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email,omitempty"`
+	Active      bool   `json:"active"`
+}
+
+// UpdateUserLinearMappingRequest links an employee to a Linear user
+type UpdateUserLinearMappingRequest struct {
+	LinearUserID *string `json:"linear_user_id"` // Empty or null removes the link
+}
+
+// Validate validates the UpdateUserLinearMappingRequest
+func (r *UpdateUserLinearMappingRequest) Validate() error {
+	if r.LinearUserID == nil {
+		return nil
+	}
+	id := strings.TrimSpace(*r.LinearUserID)
+	if id == "" {
+		r.LinearUserID = nil
+		return nil
+	}
+	if len(id) > 255 {
+		return fmt.Errorf("linear_user_id must be 255 characters or less")
+	}
+	r.LinearUserID = &id
+	return nil
+}
+
+// Work item providers, the task trackers team task views can be served from
+const (
+	WorkItemProviderJira   = "jira"
+	WorkItemProviderLinear = "linear"
+)
+
+// ============================================================================
+// Skills Types
+// ============================================================================
+
+// SkillLevel is how well someone knows a skill, from beginner to expert
+type SkillLevel int
+
+const (
+	SkillLevelBeginner     SkillLevel = 1
+	SkillLevelIntermediate SkillLevel = 2
+	SkillLevelAdvanced     SkillLevel = 3
+	SkillLevelExpert       SkillLevel = 4
+)
+
+// Skill limits
+const (
+	MaxSkillNameLength     = 100
+	MaxSkillCategoryLength = 100
+	MaxUserSkills          = 100
+)
+
+// IsValid checks the level is between beginner and expert
+func (l SkillLevel) IsValid() bool {
+	return l >= SkillLevelBeginner && l <= SkillLevelExpert
+}
+
+// Skill is an entry in the org's skills catalog
+type Skill struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Category  string    `json:"category"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserSkill is a skill on someone's profile along with how well they know it
+type UserSkill struct {
+	SkillID   int64      `json:"skill_id"`
+	Name      string     `json:"name"`
+	Category  string     `json:"category"`
+	Level     SkillLevel `json:"level"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// SkillRequest creates or renames a catalog skill
+type SkillRequest struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// Validate validates the SkillRequest
+func (r *SkillRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(r.Name) > MaxSkillNameLength {
+		return fmt.Errorf("name must be %d characters or less", MaxSkillNameLength)
+	}
+	r.Category = strings.TrimSpace(r.Category)
+	if len(r.Category) > MaxSkillCategoryLength {
+		return fmt.Errorf("category must be %d characters or less", MaxSkillCategoryLength)
+	}
+	return nil
+}
+
+// UserSkillLevel sets how well a user knows one skill
+type UserSkillLevel struct {
+	SkillID int64      `json:"skill_id"`
+	Level   SkillLevel `json:"level"`
+}
+
+// SetUserSkillsRequest replaces every skill on a user's profile
+type SetUserSkillsRequest struct {
+	Skills []UserSkillLevel `json:"skills"`
+}
+
+// Validate validates the SetUserSkillsRequest
+func (r *SetUserSkillsRequest) Validate() error {
+	if len(r.Skills) > MaxUserSkills {
+		return fmt.Errorf("a user can have at most %d skills", MaxUserSkills)
+	}
+	seen := make(map[int64]bool, len(r.Skills))
+	for _, s := range r.Skills {
+		if s.SkillID <= 0 {
+			return fmt.Errorf("skill_id must be a valid skill ID")
 		}
+		if !s.Level.IsValid() {
+			return fmt.Errorf("level must be between %d (beginner) and %d (expert)", SkillLevelBeginner, SkillLevelExpert)
+		}
+		if seen[s.SkillID] {
+			return fmt.Errorf("skill %d is listed more than once", s.SkillID)
+		}
+		seen[s.SkillID] = true
 	}
-	if r.Status != nil && !ValidTaskStatuses[*r.Status] {
-		return fmt.Errorf("invalid status: must be 'pending', 'in_progress', 'completed', or 'cancelled'")
+	return nil
+}
+
+// SkillExpert is an active user who knows a skill, returned when searching for who knows it
+type SkillExpert struct {
+	UserID     int64      `json:"user_id"`
+	FirstName  string     `json:"first_name"`
+	LastName   string     `json:"last_name"`
+	Title      string     `json:"title"`
+	Department string     `json:"department"`
+	AvatarURL  *string    `json:"avatar_url,omitempty"`
+	SkillID    int64      `json:"skill_id"`
+	SkillName  string     `json:"skill_name"`
+	Level      SkillLevel `json:"level"`
+}
+
+// SkillCoverage is how well a squad's active members cover one catalog skill
+type SkillCoverage struct {
+	SkillID  int64      `json:"skill_id"`
+	Name     string     `json:"name"`
+	Category string     `json:"category"`
+	Members  int        `json:"members"`   // Members with the skill at any level
+	Advanced int        `json:"advanced"`  // Members at advanced level or above
+	TopLevel SkillLevel `json:"top_level"` // Highest level in the squad, 0 if nobody has it
+}
+
+// SquadSkillCoverage reports every catalog skill's coverage within a squad, weakest first,
+// so planners can spot gaps
+type SquadSkillCoverage struct {
+	SquadID     int64           `json:"squad_id"`
+	SquadName   string          `json:"squad_name"`
+	MemberCount int             `json:"member_count"`
+	Skills      []SkillCoverage `json:"skills"`
+}
+
+// ============================================================================
+// Team Milestones Types
+// ============================================================================
+
+// MilestoneType is the kind of date a team milestone celebrates
+type MilestoneType string
+
+const (
+	MilestoneTypeBirthday        MilestoneType = "birthday"
+	MilestoneTypeWorkAnniversary MilestoneType = "work_anniversary"
+)
+
+// DefaultMilestoneDays and MaxMilestoneDays bound how far ahead the milestones feed looks
+const (
+	DefaultMilestoneDays = 30
+	MaxMilestoneDays     = 365
+)
+
+// TeamMilestone is an upcoming birthday or work anniversary of a team member
+type TeamMilestone struct {
+	UserID    int64         `json:"user_id"`
+	FirstName string        `json:"first_name"`
+	LastName  string        `json:"last_name"`
+	AvatarURL *string       `json:"avatar_url,omitempty"`
+	Type      MilestoneType `json:"type"`
+	Date      time.Time     `json:"date"`
+	// Years of service for work anniversaries; birthdays don't expose ages
+	Years int `json:"years,omitempty"`
+}
+
+// Title describes the milestone for calendars and notifications
+func (m *TeamMilestone) Title() string {
+	name := m.FirstName + " " + m.LastName
+	if m.Type == MilestoneTypeBirthday {
+		return "Birthday - " + name
+	}
+	if m.Years == 1 {
+		return "1 Year Work Anniversary - " + name
+	}
+	return fmt.Sprintf("%d Year Work Anniversary - %s", m.Years, name)
+}
+
+// MilestonesBetween returns the birthdays and work anniversaries of active users that fall on the
+// dates from start through end, in date order. Only the calendar date of start and end is used.
+// Leap day birthdays and start dates are celebrated on Feb 28 in other years.
+func MilestonesBetween(users []User, start, end time.Time) []TeamMilestone {
+	from, to := dateOnly(start), dateOnly(end)
+	milestones := []TeamMilestone{}
+	if to.Before(from) {
+		return milestones
+	}
+
+	for i := range users {
+		u := &users[i]
+		if !u.IsActive {
+			continue
+		}
+		for year := from.Year(); year <= to.Year(); year++ {
+			if u.Birthday != nil {
+				if date := anniversaryIn(*u.Birthday, year); !date.Before(from) && !date.After(to) {
+					milestones = append(milestones, newTeamMilestone(u, MilestoneTypeBirthday, date, 0))
+				}
+			}
+			if u.DateStarted != nil {
+				years := year - u.DateStarted.UTC().Year()
+				if date := anniversaryIn(*u.DateStarted, year); years > 0 && !date.Before(from) && !date.After(to) {
+					milestones = append(milestones, newTeamMilestone(u, MilestoneTypeWorkAnniversary, date, years))
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(milestones, func(a, b TeamMilestone) int {
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.LastName, b.LastName); c != 0 {
+			return c
+		}
+		return strings.Compare(a.FirstName, b.FirstName)
+	})
+	return milestones
+}
+
+func newTeamMilestone(u *User, kind MilestoneType, date time.Time, years int) TeamMilestone {
+	return TeamMilestone{
+		UserID:    u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		AvatarURL: u.AvatarURL,
+		Type:      kind,
+		Date:      date,
+		Years:     years,
+	}
+}
+
+// anniversaryIn returns the date's anniversary in the given year, moving Feb 29 to Feb 28
+// when the year isn't a leap year
+func anniversaryIn(date time.Time, year int) time.Time {
+	date = date.UTC()
+	anniversary := time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if anniversary.Month() != date.Month() {
+		anniversary = time.Date(year, date.Month(), 28, 0, 0, 0, 0, time.UTC)
+	}
+	return anniversary
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ============================================================================
+// Manager Notes Types
+// ============================================================================
+
+// MaxManagerNoteLength is the maximum length of a manager note
+const MaxManagerNoteLength = 20000
+
+// ManagerNote is a private note a supervisor keeps about one of their reports
+type ManagerNote struct {
+	ID        int64     `json:"id"`
+	AuthorID  int64     `json:"author_id"`
+	SubjectID int64     `json:"subject_id"`
+	MeetingID *int64    `json:"meeting_id,omitempty"` // The 1:1 the note was taken in, if any
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ManagerNoteRequest represents a request to write or edit a manager note
+type ManagerNoteRequest struct {
+	Body      string `json:"body"`
+	MeetingID *int64 `json:"meeting_id,omitempty"`
+}
+
+// Validate validates the ManagerNoteRequest
+func (r *ManagerNoteRequest) Validate() error {
+	r.Body = strings.TrimSpace(r.Body)
+	if r.Body == "" {
+		return fmt.Errorf("body is required")
+	}
+	if len(r.Body) > MaxManagerNoteLength {
+		return fmt.Errorf("body must be less than %d characters", MaxManagerNoteLength)
+	}
+	if r.MeetingID != nil && *r.MeetingID <= 0 {
+		return fmt.Errorf("invalid meeting_id")
+	}
+	return nil
+}
+
+// ============================================================================
+// Goals Types
+// ============================================================================
+
+// GoalOwnerType is what a goal belongs to
+type GoalOwnerType string
+
+const (
+	GoalOwnerUser       GoalOwnerType = "user"
+	GoalOwnerSquad      GoalOwnerType = "squad"
+	GoalOwnerDepartment GoalOwnerType = "department"
+)
+
+// IsValid checks if the owner type is supported
+func (t GoalOwnerType) IsValid() bool {
+	switch t {
+	case GoalOwnerUser, GoalOwnerSquad, GoalOwnerDepartment:
+		return true
 	}
-	if r.AssignmentType != nil && !ValidAssignmentTypes[*r.AssignmentType] {
-		return fmt.Errorf("invalid assignment_type: must be 'user', 'squad', or 'department'")
+	return false
+}
+
+// Limits for goals and key results
+const (
+	MaxGoalTitleLength        = 200
+	MaxGoalDescriptionLength  = 2000
+	MaxKeyResultsPerGoal      = 10
+	MaxKeyResultUnitLength    = 20
+	MaxProgressUpdateNoteSize = 1000
+)
+
+var quarterPattern = regexp.MustCompile(`^\d{4}-Q[1-4]$`)
+
+// QuarterOf returns the quarter containing t, such as "2025-Q3"
+func QuarterOf(t time.Time) string {
+	return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+}
+
+// ValidateQuarter checks that a quarter is in YYYY-Qn format
+func ValidateQuarter(quarter string) error {
+	if !quarterPattern.MatchString(quarter) {
+		return fmt.Errorf("quarter must be in YYYY-Qn format, such as 2025-Q3")
 	}
 	return nil
 }
 
-// Meeting represents a calendar meeting
-type Meeting struct {
-	ID                   int64           `json:"id"`
-	Title                string          `json:"title"`
-	Description          *string         `json:"description,omitempty"`
-	StartTime            time.Time       `json:"start_time"`
-	EndTime              time.Time       `json:"end_time"`
-	CreatedByID          int64           `json:"created_by_id"`
-	CreatedBy            *User           `json:"created_by,omitempty"`
-	RecurrenceType       *RecurrenceType `json:"recurrence_type,omitempty"`
-	RecurrenceInterval   int             `json:"recurrence_interval"`
-	RecurrenceEndDate    *time.Time      `json:"recurrence_end_date,omitempty"`
-	RecurrenceDaysOfWeek []int           `json:"recurrence_days_of_week,omitempty"`
-	RecurrenceDayOfMonth *int            `json:"recurrence_day_of_month,omitempty"`
-	ParentMeetingID      *int64          `json:"parent_meeting_id,omitempty"`
-	CreatedAt            time.Time       `json:"created_at"`
-	UpdatedAt            time.Time       `json:"updated_at"`
-	Attendees            []MeetingAttendee `json:"attendees,omitempty"`
+// Goal is an objective for a quarter, measured by its key results
+type Goal struct {
+	ID              int64         `json:"id"`
+	Title           string        `json:"title"`
+	Description     *string       `json:"description,omitempty"`
+	OwnerType       GoalOwnerType `json:"owner_type"`
+	OwnerID         *int64        `json:"owner_id,omitempty"`         // User or squad ID
+	OwnerDepartment *string       `json:"owner_department,omitempty"` // Department goals only
+	OwnerName       string        `json:"owner_name"`
+	Quarter         string        `json:"quarter"`
+	CreatedByID     *int64        `json:"created_by_id,omitempty"`
+	KeyResults      []KeyResult   `json:"key_results"`
+	Progress        float64       `json:"progress"` // Average progress of the key results, from 0 to 1
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
 }
 
-// MeetingAttendee represents an attendee of a meeting
-type MeetingAttendee struct {
-	ID             int64          `json:"id"`
-	MeetingID      int64          `json:"meeting_id"`
-	UserID         int64          `json:"user_id"`
-	User           *User          `json:"user,omitempty"`
-	ResponseStatus ResponseStatus `json:"response_status"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
+// KeyResult is a measurable outcome of a goal, tracked from its start value towards its target
+type KeyResult struct {
+	ID           int64     `json:"id"`
+	GoalID       int64     `json:"goal_id"`
+	Title        string    `json:"title"`
+	StartValue   float64   `json:"start_value"`
+	TargetValue  float64   `json:"target_value"`
+	CurrentValue float64   `json:"current_value"`
+	Unit         string    `json:"unit,omitempty"`
+	Progress     float64   `json:"progress"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
-// CreateMeetingRequest represents a request to create a meeting
-type CreateMeetingRequest struct {
-	Title                string          `json:"title"`
-	Description          *string         `json:"description,omitempty"`
-	StartTime            time.Time       `json:"start_time"`
-	EndTime              time.Time       `json:"end_time"`
-	AttendeeIDs          []int64         `json:"attendee_ids"`
-	RecurrenceType       *RecurrenceType `json:"recurrence_type,omitempty"`
-	RecurrenceInterval   *int            `json:"recurrence_interval,omitempty"`
-	RecurrenceEndDate    *time.Time      `json:"recurrence_end_date,omitempty"`
-	RecurrenceDaysOfWeek []int           `json:"recurrence_days_of_week,omitempty"`
-	RecurrenceDayOfMonth *int            `json:"recurrence_day_of_month,omitempty"`
+// ComputeProgress sets how far the key result has moved from its start value towards its target,
+// from 0 to 1. Targets below the start value track decreases.
+func (kr *KeyResult) ComputeProgress() {
+	span := kr.TargetValue - kr.StartValue
+	switch {
+	case span == 0 && kr.CurrentValue == kr.TargetValue:
+		kr.Progress = 1
+	case span == 0:
+		kr.Progress = 0
+	default:
+		kr.Progress = min(max((kr.CurrentValue-kr.StartValue)/span, 0), 1)
+	}
 }
 
-// Validate validates the CreateMeetingRequest
-func (r *CreateMeetingRequest) Validate() error {
+// ComputeProgress sets the progress of the goal and each of its key results
+func (g *Goal) ComputeProgress() {
+	g.Progress = 0
+	if len(g.KeyResults) == 0 {
+		return
+	}
+	var total float64
+	for i := range g.KeyResults {
+		g.KeyResults[i].ComputeProgress()
+		total += g.KeyResults[i].Progress
+	}
+	g.Progress = total / float64(len(g.KeyResults))
+}
+
+// KeyResultInput describes a key result when creating or editing a goal.
+// Key results with an ID update the existing key result and keep its progress.
+type KeyResultInput struct {
+	ID          *int64  `json:"id,omitempty"`
+	Title       string  `json:"title"`
+	StartValue  float64 `json:"start_value"`
+	TargetValue float64 `json:"target_value"`
+	Unit        string  `json:"unit,omitempty"`
+}
+
+// GoalRequest represents a request to create or edit a goal
+type GoalRequest struct {
+	Title           string           `json:"title"`
+	Description     *string          `json:"description,omitempty"`
+	OwnerType       GoalOwnerType    `json:"owner_type"`
+	OwnerID         *int64           `json:"owner_id,omitempty"`
+	OwnerDepartment *string          `json:"owner_department,omitempty"`
+	Quarter         string           `json:"quarter"`
+	KeyResults      []KeyResultInput `json:"key_results"`
+}
+
+// Validate validates the GoalRequest
+func (r *GoalRequest) Validate() error {
 	r.Title = strings.TrimSpace(r.Title)
 	if r.Title == "" {
 		return fmt.Errorf("title is required")
 	}
-	if len(r.Title) > 255 {
-		return fmt.Errorf("title must be less than 255 characters")
+	if len(r.Title) > MaxGoalTitleLength {
+		return fmt.Errorf("title must be less than %d characters", MaxGoalTitleLength)
 	}
-	if r.StartTime.IsZero() {
-		return fmt.Errorf("start_time is required")
+	if r.Description != nil {
+		*r.Description = strings.TrimSpace(*r.Description)
+		if len(*r.Description) > MaxGoalDescriptionLength {
+			return fmt.Errorf("description must be less than %d characters", MaxGoalDescriptionLength)
+		}
 	}
-	if r.EndTime.IsZero() {
-		return fmt.Errorf("end_time is required")
+
+	switch r.OwnerType {
+	case GoalOwnerUser, GoalOwnerSquad:
+		if r.OwnerID == nil || *r.OwnerID <= 0 {
+			return fmt.Errorf("owner_id is required for %s goals", r.OwnerType)
+		}
+		r.OwnerDepartment = nil
+	case GoalOwnerDepartment:
+		if r.OwnerDepartment == nil || strings.TrimSpace(*r.OwnerDepartment) == "" {
+			return fmt.Errorf("owner_department is required for department goals")
+		}
+		dept := strings.TrimSpace(*r.OwnerDepartment)
+		r.OwnerDepartment = &dept
+		r.OwnerID = nil
+	default:
+		return fmt.Errorf("owner_type must be 'user', 'squad', or 'department'")
 	}
-	if r.EndTime.Before(r.StartTime) || r.EndTime.Equal(r.StartTime) {
-		return fmt.Errorf("end_time must be after start_time")
+
+	r.Quarter = strings.TrimSpace(r.Quarter)
+	if err := ValidateQuarter(r.Quarter); err != nil {
+		return err
 	}
-	// Validate recurrence settings
-	if r.RecurrenceType != nil {
-		if !ValidRecurrenceTypes[*r.RecurrenceType] {
-			return fmt.Errorf("invalid recurrence_type: must be 'daily', 'weekly', or 'monthly'")
+
+	if len(r.KeyResults) == 0 {
+		return fmt.Errorf("at least one key result is required")
+	}
+	if len(r.KeyResults) > MaxKeyResultsPerGoal {
+		return fmt.Errorf("a goal can have at most %d key results", MaxKeyResultsPerGoal)
+	}
+	for i := range r.KeyResults {
+		kr := &r.KeyResults[i]
+		kr.Title = strings.TrimSpace(kr.Title)
+		kr.Unit = strings.TrimSpace(kr.Unit)
+		if kr.Title == "" {
+			return fmt.Errorf("key result %d: title is required", i+1)
 		}
-		if r.RecurrenceInterval != nil && *r.RecurrenceInterval < 1 {
-			return fmt.Errorf("recurrence_interval must be at least 1")
+		if len(kr.Title) > MaxGoalTitleLength {
+			return fmt.Errorf("key result %d: title must be less than %d characters", i+1, MaxGoalTitleLength)
 		}
-		// Weekly recurrence needs days of week
-		if *r.RecurrenceType == RecurrenceTypeWeekly && len(r.RecurrenceDaysOfWeek) == 0 {
-			return fmt.Errorf("recurrence_days_of_week is required for weekly recurrence")
+		if len(kr.Unit) > MaxKeyResultUnitLength {
+			return fmt.Errorf("key result %d: unit must be less than %d characters", i+1, MaxKeyResultUnitLength)
 		}
-		// Monthly recurrence needs day of month
-		if *r.RecurrenceType == RecurrenceTypeMonthly && r.RecurrenceDayOfMonth == nil {
-			return fmt.Errorf("recurrence_day_of_month is required for monthly recurrence")
+	}
+	return nil
+}
+
+// GoalProgressUpdate records a key result's value at a point in time
+type GoalProgressUpdate struct {
+	ID          int64     `json:"id"`
+	KeyResultID int64     `json:"key_result_id"`
+	Value       float64   `json:"value"`
+	Note        *string   `json:"note,omitempty"`
+	AuthorID    *int64    `json:"author_id,omitempty"` // Nil once the author is deleted
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ProgressUpdateRequest represents a request to record a key result's new value
+type ProgressUpdateRequest struct {
+	Value float64 `json:"value"`
+	Note  *string `json:"note,omitempty"`
+}
+
+// Validate validates the ProgressUpdateRequest
+func (r *ProgressUpdateRequest) Validate() error {
+	if r.Note != nil {
+		*r.Note = strings.TrimSpace(*r.Note)
+		if len(*r.Note) > MaxProgressUpdateNoteSize {
+			return fmt.Errorf("note must be less than %d characters", MaxProgressUpdateNoteSize)
+		}
+		if *r.Note == "" {
+			r.Note = nil
+		}
+	}
+	return nil
+}
+
+// GoalFilter narrows a goal listing. A goal matches when it belongs to any of the users, squads,
+// or departments given; when none are given, every owner's goals match. An empty quarter matches
+// every quarter.
+type GoalFilter struct {
+	Quarter     string
+	UserIDs     []int64
+	SquadIDs    []int64
+	Departments []string
+}
+
+// GoalRollup summarizes the goals of one owner for dashboards
+type GoalRollup struct {
+	OwnerType       GoalOwnerType `json:"owner_type"`
+	OwnerID         *int64        `json:"owner_id,omitempty"`
+	OwnerDepartment *string       `json:"owner_department,omitempty"`
+	OwnerName       string        `json:"owner_name"`
+	GoalCount       int           `json:"goal_count"`
+	CompletedCount  int           `json:"completed_count"` // Goals whose key results have all reached their targets
+	Progress        float64       `json:"progress"`        // Average goal progress
+}
+
+// RollupGoals groups goals by owner and averages their progress, ordered by owner type and name.
+// Goals must already have their progress computed.
+func RollupGoals(goals []Goal) []GoalRollup {
+	type ownerKey struct {
+		kind GoalOwnerType
+		id   int64
+		dept string
+	}
+	index := map[ownerKey]int{}
+	rollups := []GoalRollup{}
+	for _, g := range goals {
+		key := ownerKey{kind: g.OwnerType}
+		if g.OwnerID != nil {
+			key.id = *g.OwnerID
+		}
+		if g.OwnerDepartment != nil {
+			key.dept = *g.OwnerDepartment
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(rollups)
+			index[key] = i
+			rollups = append(rollups, GoalRollup{
+				OwnerType:       g.OwnerType,
+				OwnerID:         g.OwnerID,
+				OwnerDepartment: g.OwnerDepartment,
+				OwnerName:       g.OwnerName,
+			})
+		}
+		rollups[i].GoalCount++
+		rollups[i].Progress += g.Progress
+		if g.Progress >= 1 {
+			rollups[i].CompletedCount++
+		}
+	}
+
+	ownerOrder := map[GoalOwnerType]int{GoalOwnerDepartment: 0, GoalOwnerSquad: 1, GoalOwnerUser: 2}
+	for i := range rollups {
+		rollups[i].Progress /= float64(rollups[i].GoalCount)
+	}
+	slices.SortStableFunc(rollups, func(a, b GoalRollup) int {
+		if a.OwnerType != b.OwnerType {
+			return ownerOrder[a.OwnerType] - ownerOrder[b.OwnerType]
+		}
+		return strings.Compare(a.OwnerName, b.OwnerName)
+	})
+	return rollups
+}
+
+// ============================================================================
+// Kudos Types
+// ============================================================================
+
+// Limits for kudos
+const (
+	MaxKudosMessageLength = 1000
+	MaxCompanyValueLength = 50
+	DefaultKudosMonths    = 6
+	MaxKudosMonths        = 24
+)
+
+// Kudos is recognition one user gave another
+type Kudos struct {
+	ID           int64     `json:"id"`
+	FromUserID   int64     `json:"from_user_id"`
+	FromName     string    `json:"from_name"`
+	ToUserID     int64     `json:"to_user_id"`
+	ToName       string    `json:"to_name"`
+	ToAvatarURL  *string   `json:"to_avatar_url,omitempty"`
+	Message      string    `json:"message"`
+	CompanyValue *string   `json:"company_value,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// KudosRequest represents a request to give someone kudos
+type KudosRequest struct {
+	ToUserID     int64   `json:"to_user_id"`
+	Message      string  `json:"message"`
+	CompanyValue *string `json:"company_value,omitempty"`
+}
+
+// Validate validates the KudosRequest
+func (r *KudosRequest) Validate() error {
+	if r.ToUserID <= 0 {
+		return fmt.Errorf("to_user_id is required")
+	}
+	r.Message = strings.TrimSpace(r.Message)
+	if r.Message == "" {
+		return fmt.Errorf("message is required")
+	}
+	if len(r.Message) > MaxKudosMessageLength {
+		return fmt.Errorf("message must be less than %d characters", MaxKudosMessageLength)
+	}
+	if r.CompanyValue != nil {
+		value := strings.TrimSpace(*r.CompanyValue)
+		if len(value) > MaxCompanyValueLength {
+			return fmt.Errorf("company_value must be less than %d characters", MaxCompanyValueLength)
+		}
+		r.CompanyValue = &value
+		if value == "" {
+			r.CompanyValue = nil
+		}
+	}
+	return nil
+}
+
+// KudosDepartmentCount is how many kudos a department's members received in a month
+type KudosDepartmentCount struct {
+	Month      string `json:"month"` // YYYY-MM
+	Department string `json:"department"`
+	Count      int    `json:"count"`
+}
+
+// =============================================================================
+// Onboarding Types
+// =============================================================================
+
+// OnboardingAssignee is who an onboarding checklist item is assigned to
+type OnboardingAssignee string
+
+const (
+	OnboardingAssigneeNewHire    OnboardingAssignee = "new_hire"
+	OnboardingAssigneeSupervisor OnboardingAssignee = "supervisor"
+	OnboardingAssigneeIT         OnboardingAssignee = "it" // The configured IT department
+)
+
+// IsValid checks if the assignee is a known value
+func (a OnboardingAssignee) IsValid() bool {
+	switch a {
+	case OnboardingAssigneeNewHire, OnboardingAssigneeSupervisor, OnboardingAssigneeIT:
+		return true
+	}
+	return false
+}
+
+// Onboarding limits
+const (
+	MaxOnboardingTemplateNameLength = 200
+	MaxOnboardingTemplateItems      = 100
+	MaxOnboardingDueDays            = 365
+	OnboardingTaskLabel             = "onboarding" // Label added to every onboarding task
+)
+
+// OnboardingTemplate is an ordered checklist that becomes tasks when a matching user joins.
+// A nil department or role matches any user.
+type OnboardingTemplate struct {
+	ID         int64                    `json:"id"`
+	Name       string                   `json:"name"`
+	Department *string                  `json:"department,omitempty"`
+	Role       *Role                    `json:"role,omitempty"`
+	Items      []OnboardingTemplateItem `json:"items"`
+	CreatedAt  time.Time                `json:"created_at"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+}
+
+// Matches reports whether the template applies to a new user
+func (t *OnboardingTemplate) Matches(user *User) bool {
+	if t.Department != nil && !strings.EqualFold(*t.Department, user.Department) {
+		return false
+	}
+	return t.Role == nil || *t.Role == user.Role
+}
+
+// OnboardingTemplateItem is one step of an onboarding checklist
+type OnboardingTemplateItem struct {
+	ID          int64              `json:"id"`
+	TemplateID  int64              `json:"template_id"`
+	Position    int                `json:"position"`
+	Title       string             `json:"title"`
+	Description *string            `json:"description,omitempty"`
+	Assignee    OnboardingAssignee `json:"assignee"`
+	DueDays     int                `json:"due_days"` // Days after the start date the task is due
+}
+
+// OnboardingTemplateItemInput describes a checklist item when creating or editing a template
+type OnboardingTemplateItemInput struct {
+	Title       string             `json:"title"`
+	Description *string            `json:"description,omitempty"`
+	Assignee    OnboardingAssignee `json:"assignee"`
+	DueDays     int                `json:"due_days"`
+}
+
+// OnboardingTemplateRequest represents a request to create or replace an onboarding template.
+// Items are stored in the order given.
+type OnboardingTemplateRequest struct {
+	Name       string                        `json:"name"`
+	Department *string                       `json:"department,omitempty"`
+	Role       *Role                         `json:"role,omitempty"`
+	Items      []OnboardingTemplateItemInput `json:"items"`
+}
+
+// Validate validates the OnboardingTemplateRequest
+func (r *OnboardingTemplateRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(r.Name) > MaxOnboardingTemplateNameLength {
+		return fmt.Errorf("name must be less than %d characters", MaxOnboardingTemplateNameLength)
+	}
+	if r.Department != nil {
+		dept := strings.TrimSpace(*r.Department)
+		if len(dept) > MaxDepartmentLength {
+			return fmt.Errorf("department must be less than %d characters", MaxDepartmentLength)
+		}
+		r.Department = &dept
+		if dept == "" {
+			r.Department = nil
+		}
+	}
+	if r.Role != nil && !ValidRoles[*r.Role] {
+		return fmt.Errorf("invalid role: must be 'admin', 'supervisor', 'employee', or 'viewer'")
+	}
+
+	if len(r.Items) == 0 {
+		return fmt.Errorf("at least one item is required")
+	}
+	if len(r.Items) > MaxOnboardingTemplateItems {
+		return fmt.Errorf("a template can have at most %d items", MaxOnboardingTemplateItems)
+	}
+	for i := range r.Items {
+		item := &r.Items[i]
+		item.Title = strings.TrimSpace(item.Title)
+		if item.Title == "" {
+			return fmt.Errorf("item %d: title is required", i+1)
+		}
+		if len(item.Title) > 255 {
+			return fmt.Errorf("item %d: title must be less than 255 characters", i+1)
+		}
+		if !item.Assignee.IsValid() {
+			return fmt.Errorf("item %d: assignee must be 'new_hire', 'supervisor', or 'it'", i+1)
+		}
+		if item.DueDays < 0 || item.DueDays > MaxOnboardingDueDays {
+			return fmt.Errorf("item %d: due_days must be between 0 and %d", i+1, MaxOnboardingDueDays)
+		}
+	}
+	return nil
+}
+
+// OnboardingProgress summarizes the tasks created for a user's onboarding.
+// Cancelled tasks don't count towards the total.
+type OnboardingProgress struct {
+	UserID    int64   `json:"user_id"`
+	Total     int     `json:"total"`
+	Completed int     `json:"completed"`
+	Progress  float64 `json:"progress"` // Fraction of tasks completed, from 0 to 1
+	Tasks     []Task  `json:"tasks"`
+}
+
+// NewOnboardingProgress summarizes a user's onboarding tasks
+func NewOnboardingProgress(userID int64, tasks []Task) *OnboardingProgress {
+	progress := &OnboardingProgress{UserID: userID, Tasks: tasks}
+	for _, task := range tasks {
+		switch task.Status {
+		case TaskStatusCancelled:
+			continue
+		case TaskStatusCompleted:
+			progress.Completed++
+		}
+		progress.Total++
+	}
+	if progress.Total > 0 {
+		progress.Progress = float64(progress.Completed) / float64(progress.Total)
+	}
+	return progress
+}
+
+// =============================================================================
+// Avatar Types
+// =============================================================================
+
+// AvatarSizes are the square sizes, in pixels, uploaded avatars are resized to
+var AvatarSizes = []int{32, 128, 512}
+
+// AvatarVariant is one size of an uploaded avatar, stored as a JPEG or PNG and as a WebP
+type AvatarVariant struct {
+	Size        int    `json:"size"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"` // image/jpeg, or image/png for images with transparency
+	WebPURL     string `json:"webp_url"`
+}
+
+// AvatarVariants holds each size of an avatar, smallest first
+type AvatarVariants []AvatarVariant
+
+// SrcSets returns srcset attributes listing every size, keyed by content type, for use as
+// <source type="..." srcset="..."> elements. It returns nil when there are no variants.
+func (v AvatarVariants) SrcSets() map[string]string {
+	if len(v) == 0 {
+		return nil
+	}
+	entries := map[string][]string{}
+	for _, variant := range v {
+		entries[variant.ContentType] = append(entries[variant.ContentType], fmt.Sprintf("%s %dw", variant.URL, variant.Size))
+		entries["image/webp"] = append(entries["image/webp"], fmt.Sprintf("%s %dw", variant.WebPURL, variant.Size))
+	}
+	srcSets := make(map[string]string, len(entries))
+	for contentType, e := range entries {
+		srcSets[contentType] = strings.Join(e, ", ")
+	}
+	return srcSets
+}
+
+// =============================================================================
+// Audit Types
+// =============================================================================
+
+// AuditEvent records one mutating API request or GraphQL mutation
+type AuditEvent struct {
+	ID         int64  `json:"id"`
+	ActorID    *int64 `json:"actor_id,omitempty"` // Nil once the actor's account is deleted
+	ActorEmail string `json:"actor_email"`
+	// The user being impersonated, when the actor was impersonating someone
+	OnBehalfOfID *int64 `json:"on_behalf_of_id,omitempty"`
+	// The impersonation session the action was taken in, when it was started through the API
+	ImpersonationSessionID *int64 `json:"impersonation_session_id,omitempty"`
+	// "PUT /api/users/{id}" for REST requests, "mutation updateEmployee" for GraphQL
+	Action     string  `json:"action"`
+	EntityType string  `json:"entity_type"`
+	EntityID   *string `json:"entity_id,omitempty"`
+	// The entity before the change, when the handler recorded it, and the response after it
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Status    int             `json:"status"`
+	IPAddress string          `json:"ip_address"`
+	RequestID string          `json:"request_id"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditEventFilter narrows an audit log query. Zero values match everything; From and To bound
+// the time the events happened, with To exclusive.
+type AuditEventFilter struct {
+	ActorID                *int64
+	ImpersonationSessionID *int64
+	EntityType             string
+	EntityID               string
+	From                   *time.Time
+	To                     *time.Time
+}
+
+// =============================================================================
+// Custom Role Types
+// =============================================================================
+
+// Custom role limits
+const (
+	MaxCustomRoleNameLength        = 100
+	MaxCustomRoleDescriptionLength = 500
+)
+
+// RolePermission grants an action on resources within a scope, e.g. timeoff:review for the
+// holder's team. The authz package defines the actions and scopes.
+type RolePermission struct {
+	Action string `json:"action"`
+	Scope  string `json:"scope"`
+}
+
+// CustomRole is an admin-defined set of permissions, such as "HR" or "Read-only Exec", granted to
+// the users it is assigned to on top of their built-in role
+type CustomRole struct {
+	ID          int64            `json:"id"`
+	Name        string           `json:"name"`
+	Description *string          `json:"description,omitempty"`
+	Permissions []RolePermission `json:"permissions"`
+	UserCount   int              `json:"user_count"`
+	CreatedByID *int64           `json:"created_by_id,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// CustomRoleRequest creates or replaces a custom role
+type CustomRoleRequest struct {
+	Name        string           `json:"name"`
+	Description *string          `json:"description"`
+	Permissions []RolePermission `json:"permissions"`
+}
+
+// Validate validates the CustomRoleRequest. Whether its permissions exist is checked against authz.
+func (r *CustomRoleRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(r.Name) > MaxCustomRoleNameLength {
+		return fmt.Errorf("name must be %d characters or less", MaxCustomRoleNameLength)
+	}
+	if r.Description != nil && len(*r.Description) > MaxCustomRoleDescriptionLength {
+		return fmt.Errorf("description must be %d characters or less", MaxCustomRoleDescriptionLength)
+	}
+	if len(r.Permissions) == 0 {
+		return fmt.Errorf("permissions are required")
+	}
+	return nil
+}
+
+// AssignCustomRolesRequest replaces the custom roles assigned to a user; an empty list removes them all
+type AssignCustomRolesRequest struct {
+	RoleIDs []int64 `json:"role_ids"`
+}
+
+// Validate validates the AssignCustomRolesRequest
+func (r *AssignCustomRolesRequest) Validate() error {
+	for _, id := range r.RoleIDs {
+		if id <= 0 {
+			return fmt.Errorf("role_ids must be positive")
+		}
+	}
+	return nil
+}
+
+// =============================================================================
+// Session Types
+// =============================================================================
+
+// UserSession is a token a user has authenticated with, and where and when it was last used
+type UserSession struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// The token's jti claim, or a hash of the token when it has none
+	TokenID    string     `json:"token_id"`
+	Provider   string     `json:"provider"`
+	IPAddress  string     `json:"ip_address"`
+	UserAgent  string     `json:"user_agent"`
+	IssuedAt   *time.Time `json:"issued_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"` // When the token was first used
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// UserLogin is one sign-in: the first request a user made with a new token
+type UserLogin struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Provider  string    `json:"provider"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserLoginActivity is when a user last signed in and was last seen, with their recent sign-ins
+type UserLoginActivity struct {
+	UserID      int64       `json:"user_id"`
+	LastLoginAt *time.Time  `json:"last_login_at"`
+	LastSeenAt  *time.Time  `json:"last_seen_at"`
+	Logins      []UserLogin `json:"logins"`
+}
+
+// RevokedToken denylists one token, or with only a subject, every token issued to the subject
+// before it was revoked
+type RevokedToken struct {
+	ID          int64     `json:"id"`
+	Subject     *string   `json:"subject,omitempty"`
+	TokenID     *string   `json:"token_id,omitempty"`
+	UserID      *int64    `json:"user_id,omitempty"`
+	RevokedByID *int64    `json:"revoked_by_id,omitempty"`
+	RevokedAt   time.Time `json:"revoked_at"`
+	// When every token the entry revokes has expired, so it can be removed
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// =============================================================================
+// Impersonation Types
+// =============================================================================
+
+// MaxImpersonationReasonLength limits the reason given for impersonating a user
+const MaxImpersonationReasonLength = 500
+
+// ImpersonationSession is a period in which an admin acts as another user, to see what they see.
+// Requests made in it act as the user, and their audit events record both.
+type ImpersonationSession struct {
+	ID        int64      `json:"id"`
+	AdminID   int64      `json:"admin_id"`
+	UserID    int64      `json:"user_id"`
+	Reason    string     `json:"reason"`
+	StartedAt time.Time  `json:"started_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+}
+
+// IsActive reports whether the session can still be used
+func (s *ImpersonationSession) IsActive() bool {
+	return s.EndedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// StartImpersonationRequest starts impersonating a user
+type StartImpersonationRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Validate validates the StartImpersonationRequest
+func (r *StartImpersonationRequest) Validate() error {
+	r.Reason = strings.TrimSpace(r.Reason)
+	if len(r.Reason) > MaxImpersonationReasonLength {
+		return fmt.Errorf("reason must be %d characters or less", MaxImpersonationReasonLength)
+	}
+	return nil
+}
+
+// =============================================================================
+// IP Allowlist Types
+// =============================================================================
+
+// MaxIPAllowlistEntries limits the networks in an organization's IP allowlist
+const MaxIPAllowlistEntries = 100
+
+// IPAllowlist is the IP addresses and CIDR ranges an organization's users may connect from. An
+// empty allowlist allows any network.
+type IPAllowlist struct {
+	Entries []string `json:"entries"`
+}
+
+// UpdateIPAllowlistRequest replaces an organization's IP allowlist; an empty list removes it
+type UpdateIPAllowlistRequest struct {
+	Entries []string `json:"entries"`
+}
+
+// Validate validates the UpdateIPAllowlistRequest. Whether its entries parse is checked by the
+// middleware that enforces them.
+func (r *UpdateIPAllowlistRequest) Validate() error {
+	if len(r.Entries) > MaxIPAllowlistEntries {
+		return fmt.Errorf("entries must have %d items or less", MaxIPAllowlistEntries)
+	}
+	for i, entry := range r.Entries {
+		r.Entries[i] = strings.TrimSpace(entry)
+		if r.Entries[i] == "" {
+			return fmt.Errorf("entries must not be empty")
 		}
 	}
 	return nil
 }
 
-// UpdateMeetingRequest represents a request to update a meeting
-type UpdateMeetingRequest struct {
-	Title                *string         `json:"title,omitempty"`
-	Description          *string         `json:"description,omitempty"`
-	StartTime            *time.Time      `json:"start_time,omitempty"`
-	EndTime              *time.Time      `json:"end_time,omitempty"`
-	AttendeeIDs          []int64         `json:"attendee_ids,omitempty"`
-	RecurrenceType       *RecurrenceType `json:"recurrence_type,omitempty"`
-	RecurrenceInterval   *int            `json:"recurrence_interval,omitempty"`
-	RecurrenceEndDate    *time.Time      `json:"recurrence_end_date,omitempty"`
-	RecurrenceDaysOfWeek []int           `json:"recurrence_days_of_week,omitempty"`
-	RecurrenceDayOfMonth *int            `json:"recurrence_day_of_month,omitempty"`
+// =============================================================================
+// Report Types
+// =============================================================================
+
+// ReportKind names one of the team analytics reports
+type ReportKind string
+
+const (
+	ReportTimeOffUsage     ReportKind = "time-off-usage"    // Approved time off by department, quarter, and type
+	ReportApprovalLatency  ReportKind = "approval-latency"  // How long each reviewer takes to review time off
+	ReportMeetingLoad      ReportKind = "meeting-load"      // Meetings and meeting hours per person
+	ReportJiraThroughput   ReportKind = "jira-throughput"   // Jira issues resolved per squad
+	ReportHeadcount        ReportKind = "headcount"         // Hires, departures, and headcount per month
+	ReportInactiveAccounts ReportKind = "inactive-accounts" // Active accounts not seen for InactiveDays
+)
+
+// ReportKinds lists every report
+var ReportKinds = []ReportKind{ReportTimeOffUsage, ReportApprovalLatency, ReportMeetingLoad, ReportJiraThroughput, ReportHeadcount,
+	ReportInactiveAccounts}
+
+// ReportFormat is how a report is downloaded
+type ReportFormat string
+
+const (
+	ReportFormatJSON ReportFormat = "json"
+	ReportFormatCSV  ReportFormat = "csv"
+)
+
+const (
+	// DefaultReportDays is how far back reports look without a from date
+	DefaultReportDays = 365
+	// MaxReportDays caps the range a report covers
+	MaxReportDays = 5 * 366
+	// DefaultInactiveDays is how long accounts go unseen before the inactive accounts report flags
+	// them, without a days parameter
+	DefaultInactiveDays = 30
+)
+
+// ReportQuery is the whole days, in UTC, a report covers and whose people it counts: the
+// organization, or when TeamOf is set, that supervisor and their direct reports
+type ReportQuery struct {
+	From   time.Time
+	To     time.Time // Inclusive
+	TeamOf *int64
+	// InactiveDays is how many days before the end of the report accounts must not have been seen
+	// in to count as inactive
+	InactiveDays int
 }
 
-// Validate validates the UpdateMeetingRequest
-func (r *UpdateMeetingRequest) Validate() error {
-	if r.Title != nil {
-		*r.Title = strings.TrimSpace(*r.Title)
-		if *r.Title == "" {
-			return fmt.Errorf("title cannot be empty")
+// End returns the start of the day after the report's last day
+func (q *ReportQuery) End() time.Time {
+	return q.To.AddDate(0, 0, 1)
+}
+
+// ParseReportQuery reads a report's from and to dates (YYYY-MM-DD). To defaults to today and from
+// to DefaultReportDays before it.
+func ParseReportQuery(from, to string, now time.Time) (*ReportQuery, error) {
+	now = now.UTC()
+	q := &ReportQuery{To: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), InactiveDays: DefaultInactiveDays}
+	if to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, fmt.Errorf("to must be a date (YYYY-MM-DD)")
 		}
-		if len(*r.Title) > 255 {
-			return fmt.Errorf("title must be less than 255 characters")
+		q.To = t
+	}
+	q.From = q.To.AddDate(0, 0, 1-DefaultReportDays)
+	if from != "" {
+		f, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, fmt.Errorf("from must be a date (YYYY-MM-DD)")
 		}
+		q.From = f
 	}
-	if r.RecurrenceType != nil && !ValidRecurrenceTypes[*r.RecurrenceType] {
-		return fmt.Errorf("invalid recurrence_type: must be 'daily', 'weekly', or 'monthly'")
+
+	if q.From.After(q.To) {
+		return nil, fmt.Errorf("from must not be after to")
 	}
-	if r.RecurrenceInterval != nil && *r.RecurrenceInterval < 1 {
-		return fmt.Errorf("recurrence_interval must be at least 1")
+	if q.End().Sub(q.From) > MaxReportDays*24*time.Hour {
+		return nil, fmt.Errorf("reports can cover at most %d days", MaxReportDays)
+	}
+	return q, nil
+}
+
+// SetInactiveDays reads how many days unseen accounts are flagged after in the inactive accounts
+// report, leaving DefaultInactiveDays when days is empty
+func (q *ReportQuery) SetInactiveDays(days string) error {
+	if days == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(days)
+	if err != nil || n < 1 || n > MaxReportDays {
+		return fmt.Errorf("days must be a number from 1 to %d", MaxReportDays)
 	}
+	q.InactiveDays = n
 	return nil
 }
 
-// MeetingResponseRequest represents a request to respond to a meeting
-type MeetingResponseRequest struct {
-	Response ResponseStatus `json:"response"`
+// Report is a report's rows over the days it covers. Scope is "organization" or "team".
+type Report struct {
+	Report ReportKind `json:"report"`
+	From   string     `json:"from"`
+	To     string     `json:"to"`
+	Scope  string     `json:"scope"`
+	Rows   any        `json:"rows"`
 }
 
-// Validate validates the MeetingResponseRequest
-func (r *MeetingResponseRequest) Validate() error {
-	if !ValidResponseStatuses[r.Response] {
-		return fmt.Errorf("invalid response: must be 'pending', 'accepted', 'declined', or 'tentative'")
-	}
-	return nil
+// TimeOffUsageRow is the approved time off of one type starting in a quarter, in one department.
+// Days are calendar days.
+type TimeOffUsageRow struct {
+	Department  string      `json:"department"`
+	Quarter     string      `json:"quarter"` // e.g. 2026-Q1
+	RequestType TimeOffType `json:"request_type"`
+	Requests    int         `json:"requests"`
+	Days        int         `json:"days"`
 }
 
-// CalendarEventType represents the type of calendar event
-type CalendarEventType string
+// ApprovalLatencyRow is how quickly a reviewer reviewed the time off requests they reviewed in the
+// period, measured from when each request was made
+type ApprovalLatencyRow struct {
+	ReviewerID   int64   `json:"reviewer_id"`
+	ReviewerName string  `json:"reviewer_name"`
+	Reviewed     int     `json:"reviewed"`
+	Approved     int     `json:"approved"`
+	Rejected     int     `json:"rejected"`
+	AverageHours float64 `json:"average_hours"`
+	MaxHours     float64 `json:"max_hours"`
+}
+
+// MeetingLoadRow is the meetings someone was invited to and didn't decline. Each occurrence of a
+// recurring meeting counts.
+type MeetingLoadRow struct {
+	UserID   int64   `json:"user_id"`
+	Name     string  `json:"name"`
+	Meetings int     `json:"meetings"`
+	Hours    float64 `json:"hours"`
+}
+
+// JiraThroughputRow is the Jira issues resolved by a squad's members. People without a squad are
+// counted under a row with no squad.
+type JiraThroughputRow struct {
+	SquadID   *int64 `json:"squad_id"`
+	Squad     string `json:"squad"`
+	Resolved  int    `json:"resolved"`
+	Assignees int    `json:"assignees"` // Members who resolved at least one issue
+}
+
+// HeadcountRow is a month's hires and departures and the headcount at its end. Guests aren't
+// counted; people leave on their termination date, or when they were deleted.
+type HeadcountRow struct {
+	Month      string `json:"month"` // e.g. 2026-03
+	Hires      int    `json:"hires"`
+	Departures int    `json:"departures"`
+	Headcount  int    `json:"headcount"`
+}
+
+// InactiveAccountRow is an active account that hasn't been seen in the report's inactive days.
+// DaysInactive counts from when they were last seen, or were created if they never signed in.
+type InactiveAccountRow struct {
+	UserID       int64      `json:"user_id"`
+	Name         string     `json:"name"`
+	Email        string     `json:"email"`
+	Role         Role       `json:"role"`
+	Department   string     `json:"department"`
+	LastLoginAt  *time.Time `json:"last_login_at"`
+	LastSeenAt   *time.Time `json:"last_seen_at"`
+	DaysInactive int        `json:"days_inactive"`
+}
+
+// =============================================================================
+// Admin Stats Types
+// =============================================================================
+
+// AdminStats summarizes the organization for the admin home page
+type AdminStats struct {
+	Headcount         AdminHeadcount       `json:"headcount"`
+	ActiveInvitations int                  `json:"active_invitations"`
+	PendingTimeOff    PendingTimeOffCounts `json:"pending_time_off"`
+	JiraMapping       JiraMappingCoverage  `json:"jira_mapping"`
+	Storage           StorageUsage         `json:"storage"`
+	GeneratedAt       time.Time            `json:"generated_at"`
+}
+
+// AdminHeadcount counts active users by role and by department. Users without a department are
+// counted under "".
+type AdminHeadcount struct {
+	Total        int            `json:"total"`
+	ByRole       map[Role]int   `json:"by_role"`
+	ByDepartment map[string]int `json:"by_department"`
+}
+
+// PendingTimeOffCounts counts time off requests awaiting review by type
+type PendingTimeOffCounts struct {
+	Total  int                 `json:"total"`
+	ByType map[TimeOffType]int `json:"by_type"`
+}
+
+// JiraMappingCoverage is how many active users are mapped to a Jira account
+type JiraMappingCoverage struct {
+	Mapped  int     `json:"mapped"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+}
+
+// StorageUsage is what the organization has stored in file storage: uploaded meeting attachments,
+// and export files not yet removed
+type StorageUsage struct {
+	Attachments     int   `json:"attachments"`
+	AttachmentBytes int64 `json:"attachment_bytes"`
+	Exports         int   `json:"exports"`
+}
+
+// =============================================================================
+// Workload Snapshot Types
+// =============================================================================
 
 const (
-	CalendarEventTypeJira    CalendarEventType = "jira"
-	CalendarEventTypeTask    CalendarEventType = "task"
-	CalendarEventTypeMeeting CalendarEventType = "meeting"
-	CalendarEventTypeTimeOff CalendarEventType = "time_off"
+	// DefaultWorkloadTrendDays is how far back workload trends look by default
+	DefaultWorkloadTrendDays = 90
+	// MaxWorkloadTrendDays caps how far back workload trends look; older snapshots are purged
+	MaxWorkloadTrendDays = 365
 )
 
-// CalendarEvent represents a unified calendar event (Jira, Task, Meeting, or TimeOff)
-type CalendarEvent struct {
-	ID             string            `json:"id"`
-	Type           CalendarEventType `json:"type"`
-	Title          string            `json:"title"`
-	Start          time.Time         `json:"start"`
-	End            *time.Time        `json:"end,omitempty"`
-	AllDay         bool              `json:"all_day"`
-	URL            *string           `json:"url,omitempty"`
-	Task           *Task             `json:"task,omitempty"`
-	Meeting        *Meeting          `json:"meeting,omitempty"`
-	JiraIssue      *JiraIssue        `json:"jira_issue,omitempty"`
-	TimeOffRequest *TimeOffRequest   `json:"time_off_request,omitempty"`
+// WorkloadSnapshot is someone's workload at the end of a day: their open and overdue tasks, and their
+// unresolved Jira issues by status category (to_do, in_progress, or done) and by status
+type WorkloadSnapshot struct {
+	UserID               int64          `json:"user_id,omitempty"`
+	Date                 string         `json:"date"` // YYYY-MM-DD
+	OpenTasks            int            `json:"open_tasks"`
+	OverdueTasks         int            `json:"overdue_tasks"`
+	JiraOpen             int            `json:"jira_open"`
+	JiraStatusCategories map[string]int `json:"jira_status_categories"`
+	JiraStatuses         map[string]int `json:"jira_statuses"`
+}
+
+// Add adds another snapshot's counts to this one
+func (s *WorkloadSnapshot) Add(other *WorkloadSnapshot) {
+	s.OpenTasks += other.OpenTasks
+	s.OverdueTasks += other.OverdueTasks
+	s.JiraOpen += other.JiraOpen
+	if s.JiraStatusCategories == nil {
+		s.JiraStatusCategories = make(map[string]int)
+	}
+	for category, n := range other.JiraStatusCategories {
+		s.JiraStatusCategories[category] += n
+	}
+	if s.JiraStatuses == nil {
+		s.JiraStatuses = make(map[string]int)
+	}
+	for status, n := range other.JiraStatuses {
+		s.JiraStatuses[status] += n
+	}
+}
+
+// UserWorkloadTrend is one person's daily workload snapshots, oldest first
+type UserWorkloadTrend struct {
+	UserID    int64              `json:"user_id"`
+	FirstName string             `json:"first_name"`
+	LastName  string             `json:"last_name"`
+	Snapshots []WorkloadSnapshot `json:"snapshots"`
+}
+
+// WorkloadTrend is the daily workload of a group of people over a date range: each person's
+// snapshots and their totals per day. Start and End are inclusive YYYY-MM-DD dates.
+type WorkloadTrend struct {
+	Start  string              `json:"start"`
+	End    string              `json:"end"`
+	Totals []WorkloadSnapshot  `json:"totals"`
+	Users  []UserWorkloadTrend `json:"users"`
 }
 
 // ============================================================================
-// Time Off Types
+// Saved Views Types
 // ============================================================================
 
-// TimeOffType represents the type of time off request
-type TimeOffType string
+// SavedViewResource is the list a saved view filters
+type SavedViewResource string
 
 const (
-	TimeOffTypeVacation    TimeOffType = "vacation"
-	TimeOffTypeSick        TimeOffType = "sick"
-	TimeOffTypePersonal    TimeOffType = "personal"
-	TimeOffTypeBereavement TimeOffType = "bereavement"
-	TimeOffTypeJuryDuty    TimeOffType = "jury_duty"
-	TimeOffTypeOther       TimeOffType = "other"
+	SavedViewResourceTasks       SavedViewResource = "tasks"        // GET /calendar/tasks
+	SavedViewResourceTimeOff     SavedViewResource = "time_off"     // GET /time-off
+	SavedViewResourceTeamMembers SavedViewResource = "team_members" // GET /users/search
 )
 
-// ValidTimeOffTypes contains all valid time off type values
-var ValidTimeOffTypes = map[TimeOffType]bool{
-	TimeOffTypeVacation:    true,
-	TimeOffTypeSick:        true,
-	TimeOffTypePersonal:    true,
-	TimeOffTypeBereavement: true,
-	TimeOffTypeJuryDuty:    true,
-	TimeOffTypeOther:       true,
+// SavedViewFilterKeys are the query parameters a saved view may set for each list
+var SavedViewFilterKeys = map[SavedViewResource][]string{
+	SavedViewResourceTasks:       {"status", "priority", "label", "assignee", "overdue", "sort", "order"},
+	SavedViewResourceTimeOff:     {"scope", "status", "type", "from", "to", "sort", "order"},
+	SavedViewResourceTeamMembers: {"q", "department", "squad", "role", "active"},
 }
 
-// TimeOffStatus represents the status of a time off request
-type TimeOffStatus string
-
 const (
-	TimeOffStatusPending   TimeOffStatus = "pending"
-	TimeOffStatusApproved  TimeOffStatus = "approved"
-	TimeOffStatusRejected  TimeOffStatus = "rejected"
-	TimeOffStatusCancelled TimeOffStatus = "cancelled"
+	// MaxSavedViewNameLength is the maximum length of a saved view's name
+	MaxSavedViewNameLength = 100
+	// MaxSavedViewFilterLength is the maximum length of a saved view filter value
+	MaxSavedViewFilterLength = 200
 )
 
-// ValidTimeOffStatuses contains all valid time off status values
-var ValidTimeOffStatuses = map[TimeOffStatus]bool{
-	TimeOffStatusPending:   true,
-	TimeOffStatusApproved:  true,
-	TimeOffStatusRejected:  true,
-	TimeOffStatusCancelled: true,
-}
-
-// TimeOffRequest represents a time off request
-type TimeOffRequest struct {
-	ID            int64         `json:"id"`
-	UserID        int64         `json:"user_id"`
-	User          *User         `json:"user,omitempty"`
-	StartDate     time.Time     `json:"start_date"`
-	EndDate       time.Time     `json:"end_date"`
-	RequestType   TimeOffType   `json:"request_type"`
-	Reason        *string       `json:"reason,omitempty"`
-	Status        TimeOffStatus `json:"status"`
-	ReviewerID    *int64        `json:"reviewer_id,omitempty"`
-	Reviewer      *User         `json:"reviewer,omitempty"`
-	ReviewerNotes *string       `json:"reviewer_notes,omitempty"`
-	ReviewedAt    *time.Time    `json:"reviewed_at,omitempty"`
-	CreatedAt     time.Time     `json:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at"`
+// SavedView is a named set of filters a user saved for a list. Filters are the list's query
+// parameters and are only ever applied to their owner's requests.
+type SavedView struct {
+	ID        int64             `json:"id"`
+	UserID    int64             `json:"user_id"`
+	Resource  SavedViewResource `json:"resource"`
+	Name      string            `json:"name"`
+	Filters   map[string]string `json:"filters"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
 }
 
-// CreateTimeOffRequestInput represents a request to create a time off request
-type CreateTimeOffRequestInput struct {
-	StartDate   string      `json:"start_date"`
-	EndDate     string      `json:"end_date"`
-	RequestType TimeOffType `json:"request_type"`
-	Reason      *string     `json:"reason,omitempty"`
-	// For supervisor/admin to create time off for another user
-	UserID      *int64 `json:"user_id,omitempty"`
-	AutoApprove bool   `json:"auto_approve,omitempty"`
+// SavedViewRequest represents a request to save or edit a view
+type SavedViewRequest struct {
+	Resource SavedViewResource `json:"resource"`
+	Name     string            `json:"name"`
+	Filters  map[string]string `json:"filters"`
 }
 
-// Validate validates the CreateTimeOffRequestInput
-func (r *CreateTimeOffRequestInput) Validate() error {
-	if r.StartDate == "" {
-		return fmt.Errorf("start_date is required")
-	}
-	if r.EndDate == "" {
-		return fmt.Errorf("end_date is required")
+// Validate validates the SavedViewRequest. Filter values are checked by the list they apply to.
+func (r *SavedViewRequest) Validate() error {
+	keys, ok := SavedViewFilterKeys[r.Resource]
+	if !ok {
+		return fmt.Errorf("resource must be 'tasks', 'time_off', or 'team_members'")
 	}
-	if !ValidTimeOffTypes[r.RequestType] {
-		return fmt.Errorf("invalid request_type: must be 'vacation', 'sick', 'personal', 'bereavement', 'jury_duty', or 'other'")
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
 	}
-	// Parse and validate dates
-	startDate, err := time.Parse("2006-01-02", r.StartDate)
-	if err != nil {
-		return fmt.Errorf("invalid start_date format: use YYYY-MM-DD")
+	if len(r.Name) > MaxSavedViewNameLength {
+		return fmt.Errorf("name must be less than %d characters", MaxSavedViewNameLength)
 	}
-	endDate, err := time.Parse("2006-01-02", r.EndDate)
-	if err != nil {
-		return fmt.Errorf("invalid end_date format: use YYYY-MM-DD")
+	if r.Filters == nil {
+		r.Filters = map[string]string{}
 	}
-	if endDate.Before(startDate) {
-		return fmt.Errorf("end_date must be on or after start_date")
+	for key, value := range r.Filters {
+		if !slices.Contains(keys, key) {
+			return fmt.Errorf("unknown %s filter: %s", r.Resource, key)
+		}
+		if len(value) > MaxSavedViewFilterLength {
+			return fmt.Errorf("filter %s must be less than %d characters", key, MaxSavedViewFilterLength)
+		}
 	}
 	return nil
 }
 
-// ReviewTimeOffRequestInput represents a request to review a time off request
-type ReviewTimeOffRequestInput struct {
-	Status        TimeOffStatus `json:"status"`
-	ReviewerNotes *string       `json:"reviewer_notes,omitempty"`
+// Query returns the view's filters as query parameters
+func (v *SavedView) Query() url.Values {
+	q := url.Values{}
+	for key, value := range v.Filters {
+		q.Set(key, value)
+	}
+	return q
 }
 
-// Validate validates the ReviewTimeOffRequestInput
-func (r *ReviewTimeOffRequestInput) Validate() error {
-	if r.Status != TimeOffStatusApproved && r.Status != TimeOffStatusRejected {
-		return fmt.Errorf("status must be 'approved' or 'rejected'")
-	}
-	return nil
+// ============================================================================
+// Organization Settings Types
+// ============================================================================
+
+// OrgSettingKey names a setting an organization's admins can change without a redeploy
+type OrgSettingKey string
+
+const (
+	OrgSettingInvitationExpiryDays       OrgSettingKey = "invitation_expiry_days"
+	OrgSettingAvatarMaxSizeMB            OrgSettingKey = "avatar_max_size_mb"
+	OrgSettingMeetingAttachmentMaxSizeMB OrgSettingKey = "meeting_attachment_max_size_mb"
+)
+
+// OrgSettingDefinition describes an organization setting and the values it accepts. Every setting
+// is currently a whole number.
+type OrgSettingDefinition struct {
+	Key         OrgSettingKey `json:"key"`
+	Description string        `json:"description"`
+	Min         int           `json:"min"`
+	Max         int           `json:"max"`
 }
 
-// TimeOffImpact represents the impact of time off on a Jira task
-type TimeOffImpact struct {
-	HasTimeOff       bool    `json:"has_time_off"`
-	TimeOffDays      int     `json:"time_off_days"`
-	RemainingDays    int     `json:"remaining_days"`
-	ImpactPercent    float64 `json:"impact_percent"`
+// OrgSettingDefinitions lists every organization setting, in the order they are shown
+var OrgSettingDefinitions = []OrgSettingDefinition{
+	{OrgSettingInvitationExpiryDays, "Days until an invitation expires", 1, 90},
+	{OrgSettingAvatarMaxSizeMB, "Maximum avatar upload size in MB, within the server's request size limit", 1, 20},
+	{OrgSettingMeetingAttachmentMaxSizeMB, "Maximum meeting recording or transcript upload size in MB, within the server's request size limit", 1, 100},
 }
 
-// ============================================================================
-// Jira OAuth Types
-// ============================================================================
+// OrgSettingDefinitionFor returns the definition of a setting, or nil if there is no such setting
+func OrgSettingDefinitionFor(key OrgSettingKey) *OrgSettingDefinition {
+	for i := range OrgSettingDefinitions {
+		if OrgSettingDefinitions[i].Key == key {
+			return &OrgSettingDefinitions[i]
+		}
+	}
+	return nil
+}
 
-// JiraOAuthTokens holds OAuth tokens for user Jira integration
-type JiraOAuthTokens struct {
-	AccessToken  string
-	RefreshToken string
-	ExpiresAt    time.Time
-	CloudID      string
-	SiteURL      string
+// OrgSetting is a setting's value for an organization. Settings the organization hasn't changed
+// have the server's configured default.
+type OrgSetting struct {
+	OrgSettingDefinition
+	Value      int  `json:"value"`
+	Default    int  `json:"default"`
+	Overridden bool `json:"overridden"`
 }
 
-// OrgJiraSettings represents organization-wide Jira OAuth settings
-type OrgJiraSettings struct {
-	ID                  int64     `json:"id"`
-	OAuthAccessToken    string    `json:"-"` // Never expose
-	OAuthRefreshToken   string    `json:"-"` // Never expose
-	OAuthTokenExpiresAt time.Time `json:"-"` // Never expose
-	CloudID             string    `json:"cloud_id"`
-	SiteURL             string    `json:"site_url"`
-	SiteName            *string   `json:"site_name,omitempty"`
-	ConfiguredByID      int64     `json:"configured_by_id"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+// UpdateOrgSettingsRequest changes an organization's settings. A null value resets the setting to
+// the server's default; settings not included are left as they are.
+type UpdateOrgSettingsRequest struct {
+	Settings map[OrgSettingKey]*int `json:"settings"`
 }
 
-// IsTokenExpired checks if the OAuth token is expired (with 5 min buffer)
-func (s *OrgJiraSettings) IsTokenExpired() bool {
-	return time.Now().Add(5 * time.Minute).After(s.OAuthTokenExpiresAt)
+// Validate validates the UpdateOrgSettingsRequest
+func (r *UpdateOrgSettingsRequest) Validate() error {
+	if len(r.Settings) == 0 {
+		return fmt.Errorf("settings is required")
+	}
+	for key, value := range r.Settings {
+		def := OrgSettingDefinitionFor(key)
+		if def == nil {
+			return fmt.Errorf("unknown setting: %s", key)
+		}
+		if value != nil && (*value < def.Min || *value > def.Max) {
+			return fmt.Errorf("%s must be between %d and %d", key, def.Min, def.Max)
+		}
+	}
+	return nil
 }
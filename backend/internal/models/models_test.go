@@ -1,8 +1,11 @@
 package models
 
 import (
+	"fmt"
+	"maps"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestUser_IsSupervisor(t *testing.T) {
@@ -130,6 +133,9 @@ func TestRole_Constants(t *testing.T) {
 	if RoleAdmin != "admin" {
 		t.Errorf("RoleAdmin = %v, want %v", RoleAdmin, "admin")
 	}
+	if RoleViewer != "viewer" {
+		t.Errorf("RoleViewer = %v, want %v", RoleViewer, "viewer")
+	}
 }
 
 func TestUser_IsAdmin(t *testing.T) {
@@ -487,6 +493,13 @@ func TestUpdateUserRequest_Validate(t *testing.T) {
 	emptyLastName := ""
 	whitespaceFirst := "   "
 	whitespaceLast := "   "
+	validTimezone := "America/Denver"
+	unknownTimezone := "Mars/Olympus_Mons"
+	localTimezone := "Local"
+	validPhone := "+1 (555) 010-0100"
+	letterPhone := "call me"
+	shortPhone := "12"
+	emptyPhone := ""
 
 	tests := []struct {
 		name    string
@@ -540,6 +553,69 @@ func TestUpdateUserRequest_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid timezone",
+			req: UpdateUserRequest{
+				Timezone: &validTimezone,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown timezone is invalid",
+			req: UpdateUserRequest{
+				Timezone: &unknownTimezone,
+			},
+			wantErr: true,
+		},
+		{
+			name: "server local timezone is invalid",
+			req: UpdateUserRequest{
+				Timezone: &localTimezone,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid phone",
+			req: UpdateUserRequest{
+				Phone: &validPhone,
+			},
+			wantErr: false,
+		},
+		{
+			name: "clearing phone is valid",
+			req: UpdateUserRequest{
+				Phone: &emptyPhone,
+			},
+			wantErr: false,
+		},
+		{
+			name: "phone with letters is invalid",
+			req: UpdateUserRequest{
+				Phone: &letterPhone,
+			},
+			wantErr: true,
+		},
+		{
+			name: "phone with too few digits is invalid",
+			req: UpdateUserRequest{
+				Phone: &shortPhone,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid emergency contact",
+			req: UpdateUserRequest{
+				EmergencyContact: &EmergencyContact{Name: "Max", Phone: validPhone, Relationship: "Partner"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "emergency contact with invalid phone is invalid",
+			req: UpdateUserRequest{
+				EmergencyContact: &EmergencyContact{Name: "Max", Phone: letterPhone},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -851,3 +927,1584 @@ func TestResponseStatus_Constants(t *testing.T) {
 		t.Errorf("ResponseStatusTentative = %v, want tentative", ResponseStatusTentative)
 	}
 }
+
+func TestJiraPendingConnection_FindSite(t *testing.T) {
+	pending := &JiraPendingConnection{
+		Sites: []JiraSite{
+			{ID: "cloud-1", Name: "Acme", URL: "https://acme.atlassian.net"},
+			{ID: "cloud-2", Name: "Acme Labs", URL: "https://acme-labs.atlassian.net"},
+		},
+	}
+
+	site := pending.FindSite("cloud-2")
+	if site == nil || site.Name != "Acme Labs" {
+		t.Errorf("FindSite(cloud-2) = %+v, want Acme Labs", site)
+	}
+	if got := pending.FindSite("cloud-3"); got != nil {
+		t.Errorf("FindSite(cloud-3) = %+v, want nil", got)
+	}
+}
+
+func TestLinkMeetingAttachmentRequest_Validate(t *testing.T) {
+	tooLong := strings.Repeat("a", MaxTranscriptLength+1)
+
+	tests := []struct {
+		name    string
+		req     LinkMeetingAttachmentRequest
+		wantErr bool
+	}{
+		{
+			name:    "valid recording link",
+			req:     LinkMeetingAttachmentRequest{Kind: MeetingAttachmentKindRecording, URL: "https://zoom.example.com/rec/1"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid kind",
+			req:     LinkMeetingAttachmentRequest{Kind: MeetingAttachmentKind("slides"), URL: "https://example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "missing url",
+			req:     LinkMeetingAttachmentRequest{Kind: MeetingAttachmentKindTranscript, URL: "  "},
+			wantErr: true,
+		},
+		{
+			name:    "relative url",
+			req:     LinkMeetingAttachmentRequest{Kind: MeetingAttachmentKindTranscript, URL: "/files/1"},
+			wantErr: true,
+		},
+		{
+			name:    "javascript url",
+			req:     LinkMeetingAttachmentRequest{Kind: MeetingAttachmentKindTranscript, URL: "javascript:alert(1)"},
+			wantErr: true,
+		},
+		{
+			name:    "transcript text too large",
+			req:     LinkMeetingAttachmentRequest{Kind: MeetingAttachmentKindTranscript, URL: "https://example.com", TranscriptText: &tooLong},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSprintCapacityCheckRequest_Validate(t *testing.T) {
+	boardID := 1
+	badID := 0
+	zeroPoints := 0.0
+
+	tests := []struct {
+		name    string
+		req     SprintCapacityCheckRequest
+		wantErr bool
+	}{
+		{
+			name:    "valid board request",
+			req:     SprintCapacityCheckRequest{SquadID: 1, BoardID: &boardID},
+			wantErr: false,
+		},
+		{
+			name:    "missing squad",
+			req:     SprintCapacityCheckRequest{BoardID: &boardID},
+			wantErr: true,
+		},
+		{
+			name:    "missing board and sprint",
+			req:     SprintCapacityCheckRequest{SquadID: 1},
+			wantErr: true,
+		},
+		{
+			name:    "invalid sprint id",
+			req:     SprintCapacityCheckRequest{SquadID: 1, SprintID: &badID},
+			wantErr: true,
+		},
+		{
+			name:    "non-positive points per day",
+			req:     SprintCapacityCheckRequest{SquadID: 1, BoardID: &boardID, PointsPerDay: &zeroPoints},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateJiraIssueRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     CreateJiraIssueRequest
+		wantErr bool
+	}{
+		{"valid request", CreateJiraIssueRequest{ProjectKey: " web ", Summary: "Fix login"}, false},
+		{"missing project", CreateJiraIssueRequest{Summary: "Fix login"}, true},
+		{"malformed project key", CreateJiraIssueRequest{ProjectKey: "WEB-1", Summary: "Fix login"}, true},
+		{"blank summary", CreateJiraIssueRequest{ProjectKey: "WEB", Summary: "  "}, true},
+		{"multi-line summary", CreateJiraIssueRequest{ProjectKey: "WEB", Summary: "Fix\nlogin"}, true},
+		{"summary too long", CreateJiraIssueRequest{ProjectKey: "WEB", Summary: strings.Repeat("a", MaxJiraSummaryLength+1)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	req := CreateJiraIssueRequest{ProjectKey: "web", Summary: "Fix login"}
+	if err := req.Validate(); err != nil || req.ProjectKey != "WEB" || req.IssueType != "Task" {
+		t.Errorf("Validate() normalized to %+v, %v; want project WEB and issue type Task", req, err)
+	}
+}
+
+func TestIsJiraIssueKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"WEB-42":   true,
+		"AB_2-1":   true,
+		"web-42":   false,
+		"WEB":      false,
+		"WEB-":     false,
+		"-42":      false,
+		"WEB-4a":   false,
+		"WEB-1/..": false,
+	} {
+		if got := IsJiraIssueKey(key); got != want {
+			t.Errorf("IsJiraIssueKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestCheckMeetingConflictsRequest_Validate(t *testing.T) {
+	start := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	tooMany := make([]int64, MaxConflictCheckAttendees+1)
+
+	tests := []struct {
+		name    string
+		req     CheckMeetingConflictsRequest
+		wantErr bool
+	}{
+		{
+			name:    "valid request",
+			req:     CheckMeetingConflictsRequest{StartTime: start, EndTime: start.Add(time.Hour), AttendeeIDs: []int64{1, 2}},
+			wantErr: false,
+		},
+		{
+			name:    "missing start",
+			req:     CheckMeetingConflictsRequest{EndTime: start},
+			wantErr: true,
+		},
+		{
+			name:    "end equals start",
+			req:     CheckMeetingConflictsRequest{StartTime: start, EndTime: start},
+			wantErr: true,
+		},
+		{
+			name:    "window too long",
+			req:     CheckMeetingConflictsRequest{StartTime: start, EndTime: start.Add(MaxConflictCheckWindow + time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "too many attendees",
+			req:     CheckMeetingConflictsRequest{StartTime: start, EndTime: start.Add(time.Hour), AttendeeIDs: tooMany},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTimeOffRequest_WithoutNotes(t *testing.T) {
+	reason := "Medical appointment"
+	notes := "Approved, feel better"
+	original := TimeOffRequest{ID: 1, UserID: 2, Reason: &reason, ReviewerNotes: &notes, RequestType: TimeOffTypeSick}
+
+	redacted := original.WithoutNotes()
+
+	if redacted.Reason != nil || redacted.ReviewerNotes != nil {
+		t.Errorf("WithoutNotes() kept notes: reason=%v notes=%v", redacted.Reason, redacted.ReviewerNotes)
+	}
+	if redacted.ID != 1 || redacted.RequestType != TimeOffTypeSick {
+		t.Errorf("WithoutNotes() = %+v, want other fields kept", redacted)
+	}
+	if original.Reason == nil || original.ReviewerNotes == nil {
+		t.Error("WithoutNotes() should not modify the original")
+	}
+}
+
+func TestMeeting_OccursAt(t *testing.T) {
+	start := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	weekly := RecurrenceTypeWeekly
+	monthly := RecurrenceTypeMonthly
+	endDate := time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		meeting Meeting
+		at      time.Time
+		want    bool
+	}{
+		{name: "first occurrence", meeting: Meeting{StartTime: start, RecurrenceType: &weekly, RecurrenceInterval: 1}, at: start, want: true},
+		{name: "later weekly occurrence", meeting: Meeting{StartTime: start, RecurrenceType: &weekly, RecurrenceInterval: 1}, at: start.AddDate(0, 0, 14), want: true},
+		{name: "skipped by interval", meeting: Meeting{StartTime: start, RecurrenceType: &weekly, RecurrenceInterval: 2}, at: start.AddDate(0, 0, 7), want: false},
+		{name: "wrong time of day", meeting: Meeting{StartTime: start, RecurrenceType: &weekly, RecurrenceInterval: 1}, at: start.AddDate(0, 0, 7).Add(time.Hour), want: false},
+		{name: "before series start", meeting: Meeting{StartTime: start, RecurrenceType: &weekly, RecurrenceInterval: 1}, at: start.AddDate(0, 0, -7), want: false},
+		{name: "after recurrence end", meeting: Meeting{StartTime: start, RecurrenceType: &weekly, RecurrenceInterval: 1, RecurrenceEndDate: &endDate}, at: start.AddDate(0, 0, 21), want: false},
+		{name: "monthly follows AddDate", meeting: Meeting{StartTime: start, RecurrenceType: &monthly, RecurrenceInterval: 1}, at: start.AddDate(0, 1, 0), want: true},
+		{name: "non-recurring", meeting: Meeting{StartTime: start}, at: start, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.meeting.OccursAt(tt.at); got != tt.want {
+				t.Errorf("OccursAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeeting_NextOccurrence_KeepsLocalTimeAcrossDST(t *testing.T) {
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	weekly := RecurrenceTypeWeekly
+	// 9:00 in Denver the Monday before DST starts on March 8, 2026
+	start := time.Date(2026, 3, 2, 16, 0, 0, 0, time.UTC)
+
+	meeting := Meeting{StartTime: start, RecurrenceType: &weekly, RecurrenceInterval: 1, Timezone: "America/Denver"}
+	next := meeting.NextOccurrence(start)
+	if want := time.Date(2026, 3, 9, 15, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("NextOccurrence() = %v, want %v", next, want)
+	}
+	if local := next.In(denver); local.Hour() != 9 {
+		t.Errorf("local hour = %d, want 9", local.Hour())
+	}
+	if next.Location() != start.Location() {
+		t.Errorf("location = %v, want the input's %v", next.Location(), start.Location())
+	}
+
+	// Without a timezone the series repeats at the same UTC time
+	meeting.Timezone = ""
+	if next := meeting.NextOccurrence(start); !next.Equal(start.AddDate(0, 0, 7)) {
+		t.Errorf("UTC NextOccurrence() = %v", next)
+	}
+}
+
+func TestCalendarEvent_Localize(t *testing.T) {
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	due := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	task := CalendarEvent{Start: due, AllDay: true}
+	task.Localize(denver)
+	if want := time.Date(2026, 3, 4, 7, 0, 0, 0, time.UTC); !task.Start.Equal(want) {
+		t.Errorf("all-day Start = %v, want the start of the day in Denver %v", task.Start, want)
+	}
+	if task.LocalStart != "2026-03-04" || task.Timezone != "America/Denver" {
+		t.Errorf("all-day local = %q in %q", task.LocalStart, task.Timezone)
+	}
+
+	// Meetings use the organizer's timezone, not the viewer's
+	start := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	meeting := CalendarEvent{Start: start, End: &end, Meeting: &Meeting{Timezone: "Europe/London"}}
+	meeting.Localize(denver)
+	if meeting.Timezone != "Europe/London" || meeting.LocalStart != "2026-03-04T15:00:00Z" {
+		t.Errorf("meeting local = %q in %q", meeting.LocalStart, meeting.Timezone)
+	}
+	if meeting.LocalEnd == nil || *meeting.LocalEnd != "2026-03-04T16:00:00Z" {
+		t.Errorf("meeting LocalEnd = %v", meeting.LocalEnd)
+	}
+}
+
+func TestWorkSchedule_Check(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	// Wednesday 15:00 UTC is midnight Thursday in Tokyo
+	start := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	focus := DefaultWorkSchedule(1, "UTC")
+	focus.FocusBlocks = []FocusBlock{{Weekday: 3, StartMinute: 14 * 60, EndMinute: 15*60 + 30}}
+
+	tests := []struct {
+		name     string
+		schedule *WorkSchedule
+		start    time.Time
+		want     SchedulingIssue
+	}{
+		{name: "within default hours", schedule: DefaultWorkSchedule(1, "UTC"), start: start, want: ""},
+		{name: "midnight in the attendee's timezone", schedule: DefaultWorkSchedule(1, "Asia/Tokyo"), start: start, want: SchedulingIssueOutsideWorkingHours},
+		{name: "runs past the end of the day", schedule: DefaultWorkSchedule(1, "UTC"), start: start.Add(2 * time.Hour), want: SchedulingIssueOutsideWorkingHours},
+		{name: "weekend", schedule: DefaultWorkSchedule(1, "UTC"), start: start.AddDate(0, 0, 3), want: SchedulingIssueOutsideWorkingHours},
+		{name: "overlaps a focus block", schedule: focus, start: start, want: SchedulingIssueFocusTime},
+		{name: "after the focus block", schedule: focus, start: start.Add(30 * time.Minute), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Check(tt.start, tt.start.Add(30*time.Minute)); got != tt.want {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateWorkScheduleRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     UpdateWorkScheduleRequest
+		wantErr bool
+	}{
+		{name: "valid", req: UpdateWorkScheduleRequest{StartMinute: 480, EndMinute: 960, Weekdays: []int{1, 2, 3, 4}, FocusBlocks: []FocusBlock{{Weekday: 5, StartMinute: 540, EndMinute: 720, Label: "Deep work"}}}},
+		{name: "ends before it starts", req: UpdateWorkScheduleRequest{StartMinute: 960, EndMinute: 480, Weekdays: []int{1}}, wantErr: true},
+		{name: "runs past midnight", req: UpdateWorkScheduleRequest{StartMinute: 480, EndMinute: 1500, Weekdays: []int{1}}, wantErr: true},
+		{name: "no working days", req: UpdateWorkScheduleRequest{StartMinute: 480, EndMinute: 960}, wantErr: true},
+		{name: "duplicate weekday", req: UpdateWorkScheduleRequest{StartMinute: 480, EndMinute: 960, Weekdays: []int{1, 1}}, wantErr: true},
+		{name: "invalid focus weekday", req: UpdateWorkScheduleRequest{StartMinute: 480, EndMinute: 960, Weekdays: []int{1}, FocusBlocks: []FocusBlock{{Weekday: 7, StartMinute: 540, EndMinute: 600}}}, wantErr: true},
+		{name: "empty focus block", req: UpdateWorkScheduleRequest{StartMinute: 480, EndMinute: 960, Weekdays: []int{1}, FocusBlocks: []FocusBlock{{Weekday: 1, StartMinute: 540, EndMinute: 540}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateMeetingOccurrenceRequest_Validate(t *testing.T) {
+	occurrence := time.Date(2026, 3, 9, 15, 0, 0, 0, time.UTC)
+	title := "Moved"
+	daily := RecurrenceTypeDaily
+	later := occurrence.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		req     UpdateMeetingOccurrenceRequest
+		wantErr bool
+	}{
+		{
+			name:    "valid single occurrence edit",
+			req:     UpdateMeetingOccurrenceRequest{OccurrenceStart: occurrence, Scope: OccurrenceScopeThis, UpdateMeetingRequest: UpdateMeetingRequest{Title: &title}},
+			wantErr: false,
+		},
+		{
+			name:    "valid following edit with recurrence change",
+			req:     UpdateMeetingOccurrenceRequest{OccurrenceStart: occurrence, Scope: OccurrenceScopeFollowing, UpdateMeetingRequest: UpdateMeetingRequest{RecurrenceType: &daily}},
+			wantErr: false,
+		},
+		{
+			name:    "missing occurrence start",
+			req:     UpdateMeetingOccurrenceRequest{Scope: OccurrenceScopeThis},
+			wantErr: true,
+		},
+		{
+			name:    "invalid scope",
+			req:     UpdateMeetingOccurrenceRequest{OccurrenceStart: occurrence, Scope: "all"},
+			wantErr: true,
+		},
+		{
+			name:    "recurrence change on single occurrence",
+			req:     UpdateMeetingOccurrenceRequest{OccurrenceStart: occurrence, Scope: OccurrenceScopeThis, UpdateMeetingRequest: UpdateMeetingRequest{RecurrenceType: &daily}},
+			wantErr: true,
+		},
+		{
+			name:    "end before start",
+			req:     UpdateMeetingOccurrenceRequest{OccurrenceStart: occurrence, Scope: OccurrenceScopeThis, UpdateMeetingRequest: UpdateMeetingRequest{StartTime: &later, EndTime: &occurrence}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateExportJobRequest_Validate(t *testing.T) {
+	department := "Engineering"
+	approved := TimeOffStatusApproved
+	from, to, badDate := "2026-03-01", "2026-01-01", "03/01/2026"
+
+	tests := []struct {
+		name       string
+		req        CreateExportJobRequest
+		wantErr    bool
+		wantFormat ExportFormat
+	}{
+		{
+			name:       "defaults to csv",
+			req:        CreateExportJobRequest{Type: ExportTypeEmployees, Filters: ExportFilters{Department: &department}},
+			wantFormat: ExportFormatCSV,
+		},
+		{
+			name:       "time off with filters",
+			req:        CreateExportJobRequest{Type: ExportTypeTimeOff, Format: ExportFormatJSON, Filters: ExportFilters{Status: &approved, From: &to, To: &from}},
+			wantFormat: ExportFormatJSON,
+		},
+		{
+			name:    "unknown type",
+			req:     CreateExportJobRequest{Type: "payroll"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			req:     CreateExportJobRequest{Type: ExportTypeEmployees, Format: "xlsx"},
+			wantErr: true,
+		},
+		{
+			name:    "time off filter on employees",
+			req:     CreateExportJobRequest{Type: ExportTypeEmployees, Filters: ExportFilters{Status: &approved}},
+			wantErr: true,
+		},
+		{
+			name:    "department filter on time off",
+			req:     CreateExportJobRequest{Type: ExportTypeTimeOff, Filters: ExportFilters{Department: &department}},
+			wantErr: true,
+		},
+		{
+			name:    "malformed date",
+			req:     CreateExportJobRequest{Type: ExportTypeTimeOff, Filters: ExportFilters{From: &badDate}},
+			wantErr: true,
+		},
+		{
+			name:    "to before from",
+			req:     CreateExportJobRequest{Type: ExportTypeTimeOff, Filters: ExportFilters{From: &from, To: &to}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tt.req.Format != tt.wantFormat {
+				t.Errorf("Format = %s, want %s", tt.req.Format, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestCreateInvitationRequest_Validate_Employee(t *testing.T) {
+	supervisorID := int64(2)
+	nextWeek := time.Now().AddDate(0, 0, 7)
+
+	tests := []struct {
+		name    string
+		req     CreateInvitationRequest
+		wantErr bool
+	}{
+		{
+			name: "employee with reporting line",
+			req: CreateInvitationRequest{Email: "dev@example.com", Role: RoleEmployee, SupervisorID: &supervisorID, Title: " Engineer ",
+				DateStarted: &nextWeek, SquadIDs: []int64{3}},
+		},
+		{
+			name: "employee without details",
+			req:  CreateInvitationRequest{Email: "dev@example.com", Role: RoleEmployee},
+		},
+		{
+			name:    "title too long",
+			req:     CreateInvitationRequest{Email: "dev@example.com", Role: RoleEmployee, Title: strings.Repeat("a", 256)},
+			wantErr: true,
+		},
+		{
+			name:    "unknown role",
+			req:     CreateInvitationRequest{Email: "dev@example.com", Role: "contractor"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateInvitationRequest_Validate_Guest(t *testing.T) {
+	nextWeek := time.Now().AddDate(0, 0, 7)
+	yesterday := time.Now().AddDate(0, 0, -1)
+	tooFar := time.Now().AddDate(0, 0, MaxGuestAccessDays+1)
+	supervisorID := int64(2)
+
+	tests := []struct {
+		name    string
+		req     CreateInvitationRequest
+		wantErr bool
+	}{
+		{
+			name: "guest invited to meetings",
+			req:  CreateInvitationRequest{Email: "guest@example.com", Role: RoleGuest, AccessExpiresAt: &nextWeek, MeetingIDs: []int64{4, 9}},
+		},
+		{
+			name:    "guest without expiry",
+			req:     CreateInvitationRequest{Email: "guest@example.com", Role: RoleGuest},
+			wantErr: true,
+		},
+		{
+			name:    "guest expiring in the past",
+			req:     CreateInvitationRequest{Email: "guest@example.com", Role: RoleGuest, AccessExpiresAt: &yesterday},
+			wantErr: true,
+		},
+		{
+			name:    "guest expiring too far out",
+			req:     CreateInvitationRequest{Email: "guest@example.com", Role: RoleGuest, AccessExpiresAt: &tooFar},
+			wantErr: true,
+		},
+		{
+			name:    "guest in a department",
+			req:     CreateInvitationRequest{Email: "guest@example.com", Role: RoleGuest, AccessExpiresAt: &nextWeek, Department: "Engineering"},
+			wantErr: true,
+		},
+		{
+			name:    "guest with a supervisor",
+			req:     CreateInvitationRequest{Email: "guest@example.com", Role: RoleGuest, AccessExpiresAt: &nextWeek, SupervisorID: &supervisorID},
+			wantErr: true,
+		},
+		{
+			name:    "expiry on a supervisor invitation",
+			req:     CreateInvitationRequest{Email: "lead@example.com", Role: RoleSupervisor, AccessExpiresAt: &nextWeek},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUser_HasAccessExpired(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name string
+		user User
+		want bool
+	}{
+		{"no expiry", User{Role: RoleEmployee}, false},
+		{"active guest", User{Role: RoleGuest, AccessExpiresAt: &future}, false},
+		{"expired guest", User{Role: RoleGuest, AccessExpiresAt: &past}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.HasAccessExpired(); got != tt.want {
+				t.Errorf("HasAccessExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTaskRequest_Validate_PriorityAndLabels(t *testing.T) {
+	base := func() CreateTaskRequest {
+		userID := int64(1)
+		return CreateTaskRequest{Title: "Ship it", DueDate: time.Now(), AssignmentType: AssignmentTypeUser, AssignedUserID: &userID}
+	}
+	tooMany := make([]string, MaxTaskLabels+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("x", i+1)
+	}
+
+	tests := []struct {
+		name         string
+		priority     TaskPriority
+		labels       []string
+		wantErr      bool
+		wantPriority TaskPriority
+		wantLabels   []string
+	}{
+		{name: "defaults to medium", wantPriority: TaskPriorityMedium, wantLabels: []string{}},
+		{name: "labels are normalized", priority: TaskPriorityUrgent, labels: []string{" Release ", "release", "", "Docs"}, wantPriority: TaskPriorityUrgent, wantLabels: []string{"release", "docs"}},
+		{name: "unknown priority", priority: "critical", wantErr: true},
+		{name: "label too long", labels: []string{strings.Repeat("a", MaxTaskLabelLength+1)}, wantErr: true},
+		{name: "too many labels", labels: tooMany, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := base()
+			req.Priority = tt.priority
+			req.Labels = tt.labels
+			err := req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if req.Priority != tt.wantPriority {
+				t.Errorf("Priority = %s, want %s", req.Priority, tt.wantPriority)
+			}
+			if strings.Join(req.Labels, ",") != strings.Join(tt.wantLabels, ",") {
+				t.Errorf("Labels = %v, want %v", req.Labels, tt.wantLabels)
+			}
+		})
+	}
+}
+
+func TestCreateTaskRequest_Validate_Recurrence(t *testing.T) {
+	due := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+	weekly, yearly := RecurrenceTypeWeekly, RecurrenceType("yearly")
+	zero, two := 0, 2
+	before, after := due.AddDate(0, 0, -1), due.AddDate(0, 3, 0)
+
+	tests := []struct {
+		name           string
+		recurrenceType *RecurrenceType
+		interval       *int
+		endDate        *time.Time
+		wantErr        bool
+	}{
+		{name: "every other week until June", recurrenceType: &weekly, interval: &two, endDate: &after},
+		{name: "unknown recurrence", recurrenceType: &yearly, wantErr: true},
+		{name: "zero interval", recurrenceType: &weekly, interval: &zero, wantErr: true},
+		{name: "ends before it's due", recurrenceType: &weekly, endDate: &before, wantErr: true},
+		{name: "interval without a recurrence", interval: &two, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := int64(1)
+			req := CreateTaskRequest{Title: "Submit timesheet", DueDate: due, AssignmentType: AssignmentTypeUser, AssignedUserID: &userID,
+				RecurrenceType: tt.recurrenceType, RecurrenceInterval: tt.interval, RecurrenceEndDate: tt.endDate}
+			if err := req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTask_NextInstance(t *testing.T) {
+	weekly := RecurrenceTypeWeekly
+	due := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+	start := due.Add(16 * time.Hour)
+	end := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	task := Task{ID: 7, Title: "Submit timesheet", Status: TaskStatusCompleted, DueDate: due, StartTime: &start,
+		RecurrenceType: &weekly, RecurrenceInterval: 1, RecurrenceEndDate: &end}
+
+	next := task.NextInstance()
+	if next == nil {
+		t.Fatal("NextInstance() = nil, want the next week's task")
+	}
+	if !next.DueDate.Equal(due.AddDate(0, 0, 7)) || !next.StartTime.Equal(start.AddDate(0, 0, 7)) {
+		t.Errorf("next due %v starting %v, want a week later", next.DueDate, next.StartTime)
+	}
+	if next.ID != 0 || next.Status != TaskStatusPending || *next.RecurrenceParentID != 7 {
+		t.Errorf("next = %+v, want a new pending task linked to task 7", next)
+	}
+
+	last := next.NextInstance()
+	if last == nil || !last.DueDate.Equal(end) || *last.RecurrenceParentID != 7 {
+		t.Fatalf("last = %+v, want the instance due on the end date", last)
+	}
+	if after := last.NextInstance(); after != nil {
+		t.Errorf("NextInstance() past the end date = %+v, want nil", after)
+	}
+}
+
+func TestTask_ProjectOccurrences(t *testing.T) {
+	daily := RecurrenceTypeDaily
+	due := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	task := Task{ID: 3, Status: TaskStatusPending, DueDate: due, RecurrenceType: &daily, RecurrenceInterval: 2}
+
+	occurrences := task.ProjectOccurrences(due.AddDate(0, 0, 3), due.AddDate(0, 0, 8))
+	var got []string
+	for _, o := range occurrences {
+		if o.ID != 3 || !o.IsProjected {
+			t.Errorf("occurrence = %+v, want a projection of task 3", o)
+		}
+		got = append(got, o.DueDate.Format("01-02"))
+	}
+	if strings.Join(got, ",") != "03-06,03-08,03-10" {
+		t.Errorf("projected due dates = %v, want 03-06,03-08,03-10", got)
+	}
+
+	task.Status = TaskStatusCompleted
+	if occurrences := task.ProjectOccurrences(due, due.AddDate(0, 1, 0)); len(occurrences) != 0 {
+		t.Errorf("finished task projected %d occurrences, want none", len(occurrences))
+	}
+}
+
+func TestValidateCalendarWindow(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		end     time.Time
+		wantErr bool
+	}{
+		{"one month", start.AddDate(0, 1, 0), false},
+		{"exactly the maximum", start.AddDate(0, 0, MaxCalendarWindowDays), false},
+		{"one day too wide", start.AddDate(0, 0, MaxCalendarWindowDays+1), true},
+		{"end before start", start.Add(-time.Second), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCalendarWindow(start, tt.end); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCalendarWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeCalendarCursor_RejectsUnknownSource(t *testing.T) {
+	cursor := CalendarCursor{"holiday": {ID: "x"}}
+	if _, err := DecodeCalendarCursor(cursor.Encode()); err == nil {
+		t.Error("expected an error for an unknown source")
+	}
+	if _, err := DecodeCalendarCursor(CalendarCursor{}.Encode()); err == nil {
+		t.Error("expected an error for an empty cursor")
+	}
+}
+
+func TestParseOrgTreeOptions(t *testing.T) {
+	opts, err := ParseOrgTreeOptions("headcount, goal_progress")
+	if err != nil {
+		t.Fatalf("ParseOrgTreeOptions() error = %v", err)
+	}
+	if !opts.Headcount || !opts.GoalProgress || opts.OpenTasks || opts.OutOfOffice {
+		t.Errorf("opts = %+v", opts)
+	}
+	if !opts.NeedsMemberStats() {
+		t.Error("goal_progress should need member stats")
+	}
+
+	if opts, err := ParseOrgTreeOptions(""); err != nil || opts.Any() {
+		t.Errorf("empty include = %+v, %v", opts, err)
+	}
+	if _, err := ParseOrgTreeOptions("headcount,salary"); err == nil {
+		t.Error("expected an error for an unknown aggregate")
+	}
+}
+
+func TestUpdateJiraTaskFiltersRequest_Validate(t *testing.T) {
+	filter := func(name, jql string) JiraTaskFilter { return JiraTaskFilter{Name: name, JQL: jql} }
+	tests := []struct {
+		name    string
+		filters []JiraTaskFilter
+		wantErr bool
+	}{
+		{"valid filters", []JiraTaskFilter{filter("Exclude Done", "status != Done"), filter("Current sprint", "sprint in openSprints()")}, false},
+		{"no filters", nil, false},
+		{"blank name", []JiraTaskFilter{filter(" ", "status != Done")}, true},
+		{"duplicate name", []JiraTaskFilter{filter("Bugs", "issuetype = Bug"), filter("bugs", "type = Bug")}, true},
+		{"missing JQL", []JiraTaskFilter{filter("Bugs", "")}, true},
+		{"ORDER BY", []JiraTaskFilter{filter("Bugs", "issuetype = Bug order by priority")}, true},
+		{"JQL too long", []JiraTaskFilter{filter("Long", strings.Repeat("a", MaxJiraTaskFilterJQLLen+1))}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := UpdateJiraTaskFiltersRequest{Filters: tt.filters}
+			err := req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateUserGitHubMappingRequest_Validate(t *testing.T) {
+	login := func(s string) *string { return &s }
+	tests := []struct {
+		name      string
+		login     *string
+		wantLogin *string
+		wantErr   bool
+	}{
+		{"valid login", login("ada-lovelace"), login("ada-lovelace"), false},
+		{"trims spaces and @", login("  @octocat "), login("octocat"), false},
+		{"empty clears the link", login(" "), nil, false},
+		{"null clears the link", nil, nil, false},
+		{"leading hyphen", login("-ada"), nil, true},
+		{"double hyphen", login("ada--l"), nil, true},
+		{"spaces", login("ada l"), nil, true},
+		{"too long", login(strings.Repeat("a", 40)), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := UpdateUserGitHubMappingRequest{GitHubLogin: tt.login}
+			err := req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (req.GitHubLogin == nil) != (tt.wantLogin == nil) || (req.GitHubLogin != nil && *req.GitHubLogin != *tt.wantLogin) {
+				t.Errorf("GitHubLogin = %v, want %v", req.GitHubLogin, tt.wantLogin)
+			}
+		})
+	}
+}
+
+func TestUpdateLinearSettingsRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		apiKey  string
+		wantErr bool
+	}{
+		{"valid key", "lin_api_abc123", false},
+		{"trims spaces", "  lin_api_abc123\n", false},
+		{"empty", " ", true},
+		{"not a Linear key", "ghp_abc123", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := UpdateLinearSettingsRequest{APIKey: tt.apiKey}
+			err := req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && req.APIKey != "lin_api_abc123" {
+				t.Errorf("APIKey = %q, want it trimmed", req.APIKey)
+			}
+		})
+	}
+}
+
+func TestFindOrgChartProblems(t *testing.T) {
+	id := func(v int64) *int64 { return &v }
+	role := func(r Role) *Role { return &r }
+	members := []User{
+		{ID: 1, FirstName: "Ada", Role: RoleAdmin},
+		{ID: 2, FirstName: "Grace", Role: RoleSupervisor, SupervisorID: id(1)},
+		{ID: 3, FirstName: "Alan", Role: RoleSupervisor, SupervisorID: id(2)},
+		{ID: 4, FirstName: "Edsger", Role: RoleEmployee, SupervisorID: id(3)},
+	}
+
+	tests := []struct {
+		name      string
+		changes   []DraftChange
+		wantKinds []OrgChartProblemKind
+		wantIDs   []int64
+	}{
+		{"no changes", nil, nil, nil},
+		{"valid move", []DraftChange{{UserID: 4, NewSupervisorID: id(2)}}, nil, nil},
+		{"cycle", []DraftChange{{UserID: 2, NewSupervisorID: id(4)}}, []OrgChartProblemKind{OrgChartProblemCycle}, []int64{2, 4, 3}},
+		{"self report", []DraftChange{{UserID: 4, NewSupervisorID: id(4)}}, []OrgChartProblemKind{OrgChartProblemCycle}, []int64{4}},
+		{"orphaned report", []DraftChange{{UserID: 4, NewSupervisorID: id(99)}}, []OrgChartProblemKind{OrgChartProblemOrphanedReport}, []int64{4}},
+		{"demoted supervisor", []DraftChange{{UserID: 3, NewRole: role(RoleEmployee)}}, []OrgChartProblemKind{OrgChartProblemDemotedSupervisor}, []int64{3}},
+		{"demoted after reports move", []DraftChange{
+			{UserID: 3, NewRole: role(RoleEmployee)},
+			{UserID: 4, NewSupervisorID: id(2)},
+		}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := ApplyDraftChanges(members, tt.changes)
+			problems := FindOrgChartProblems(members, after)
+
+			if len(problems) != len(tt.wantKinds) {
+				t.Fatalf("problems = %+v, want kinds %v", problems, tt.wantKinds)
+			}
+			for i, p := range problems {
+				if p.Kind != tt.wantKinds[i] {
+					t.Errorf("problem %d kind = %s, want %s", i, p.Kind, tt.wantKinds[i])
+				}
+				if p.Message == "" {
+					t.Errorf("problem %d has no message", i)
+				}
+			}
+			if len(problems) > 0 && fmt.Sprint(problems[0].UserIDs) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("UserIDs = %v, want %v", problems[0].UserIDs, tt.wantIDs)
+			}
+		})
+	}
+
+	if members[3].SupervisorID == nil || *members[3].SupervisorID != 3 {
+		t.Error("ApplyDraftChanges modified the members it was given")
+	}
+}
+
+func TestNewDraftProblemsError(t *testing.T) {
+	changes := []DraftChange{{ID: 10, UserID: 2}, {ID: 11, UserID: 3}}
+	problems := []OrgChartProblem{
+		{Kind: OrgChartProblemDemotedSupervisor, UserIDs: []int64{5}, Message: "Ada would become employee"},
+		{Kind: OrgChartProblemCycle, UserIDs: []int64{4, 3}, Message: "loop"},
+	}
+
+	err := NewDraftProblemsError(changes, problems)
+	if err.ChangeID == nil || *err.ChangeID != 11 || *err.UserID != 3 {
+		t.Errorf("blamed change %v for user %v, want 11 for user 3", err.ChangeID, err.UserID)
+	}
+	if err.Error() != "loop" || len(err.Problems) != 2 {
+		t.Errorf("err = %+v, want the cycle's message and both problems", err)
+	}
+
+	// A problem no change caused still fails the publish
+	err = NewDraftProblemsError(changes, problems[:1])
+	if err.ChangeID != nil || err.Error() != "Ada would become employee" {
+		t.Errorf("err = %+v, want no change blamed", err)
+	}
+}
+
+func TestDraftReviewRequests_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     interface{ Validate() error }
+		wantErr bool
+	}{
+		{"share as editor", &ShareDraftRequest{UserID: 2, Permission: DraftPermissionEditor}, false},
+		{"share without user", &ShareDraftRequest{Permission: DraftPermissionViewer}, true},
+		{"share with unknown permission", &ShareDraftRequest{UserID: 2, Permission: "owner"}, true},
+		{"comment", &CreateDraftCommentRequest{ChangeID: 1, Body: "Looks good"}, false},
+		{"blank comment", &CreateDraftCommentRequest{ChangeID: 1, Body: "   "}, true},
+		{"comment without change", &CreateDraftCommentRequest{Body: "Looks good"}, true},
+		{"long comment", &CreateDraftCommentRequest{ChangeID: 1, Body: strings.Repeat("a", MaxDraftCommentLength+1)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildOrgTrees(t *testing.T) {
+	id := func(v int64) *int64 { return &v }
+	// Ordered the way the org tree query returns them, with reports before their own reports
+	users := []User{
+		{ID: 1},
+		{ID: 5, SupervisorID: id(1)},
+		{ID: 9, SupervisorID: id(5)},
+		{ID: 12, SupervisorID: id(9)},
+		{ID: 20, SupervisorID: id(99)}, // Supervisor isn't in the org
+	}
+
+	trees := BuildOrgTrees(users, nil)
+
+	if len(trees) != 2 || trees[0].User.ID != 1 || trees[1].User.ID != 20 {
+		t.Fatalf("roots = %+v, want users 1 and 20", trees)
+	}
+	depth, node := 0, &trees[0]
+	for len(node.Children) > 0 {
+		depth++
+		node = &node.Children[0]
+	}
+	if depth != 3 || node.User.ID != 12 {
+		t.Errorf("deepest node = %d at depth %d, want 12 at depth 3", node.User.ID, depth)
+	}
+}
+
+func TestCustomFieldRequests_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     interface{ Validate() error }
+		wantErr bool
+	}{
+		{"text field", &CreateCustomFieldRequest{Key: "cost_center", Label: "Cost center", Type: CustomFieldTypeText}, false},
+		{"select field", &CreateCustomFieldRequest{Key: "shirt_size", Label: "Shirt size", Type: CustomFieldTypeSelect, Options: []string{"S", "M", "L"}}, false},
+		{"missing key", &CreateCustomFieldRequest{Label: "Cost center", Type: CustomFieldTypeText}, true},
+		{"key with capitals", &CreateCustomFieldRequest{Key: "CostCenter", Label: "Cost center", Type: CustomFieldTypeText}, true},
+		{"key starting with digit", &CreateCustomFieldRequest{Key: "1st", Label: "First", Type: CustomFieldTypeText}, true},
+		{"missing label", &CreateCustomFieldRequest{Key: "cost_center", Type: CustomFieldTypeText}, true},
+		{"unknown type", &CreateCustomFieldRequest{Key: "cost_center", Label: "Cost center", Type: "number"}, true},
+		{"select without options", &CreateCustomFieldRequest{Key: "shirt_size", Label: "Shirt size", Type: CustomFieldTypeSelect}, true},
+		{"select with duplicate options", &CreateCustomFieldRequest{Key: "shirt_size", Label: "Shirt size", Type: CustomFieldTypeSelect, Options: []string{"S", " S "}}, true},
+		{"text with options", &CreateCustomFieldRequest{Key: "cost_center", Label: "Cost center", Type: CustomFieldTypeText, Options: []string{"A"}}, true},
+		{"update", &UpdateCustomFieldRequest{Label: "Cost centre"}, false},
+		{"update without label", &UpdateCustomFieldRequest{Label: "  "}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCustomFieldValues(t *testing.T) {
+	fields := []CustomField{
+		{ID: 1, Key: "cost_center", Type: CustomFieldTypeText},
+		{ID: 2, Key: "start_date", Type: CustomFieldTypeDate, Required: true},
+		{ID: 3, Key: "shirt_size", Type: CustomFieldTypeSelect, Options: []string{"S", "M", "L"}},
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    map[int64]string
+		wantErr bool
+	}{
+		{
+			name:   "valid values are trimmed",
+			values: map[string]string{"cost_center": " CC-100 ", "start_date": "2024-03-01", "shirt_size": "M"},
+			want:   map[int64]string{1: "CC-100", 2: "2024-03-01", 3: "M"},
+		},
+		{
+			name:   "empty optional value clears the field",
+			values: map[string]string{"shirt_size": ""},
+			want:   map[int64]string{3: ""},
+		},
+		{name: "empty required value", values: map[string]string{"start_date": " "}, wantErr: true},
+		{name: "unknown key", values: map[string]string{"favorite_color": "blue"}, wantErr: true},
+		{name: "malformed date", values: map[string]string{"start_date": "03/01/2024"}, wantErr: true},
+		{name: "option not allowed", values: map[string]string{"shirt_size": "XXL"}, wantErr: true},
+		{name: "text too long", values: map[string]string{"cost_center": strings.Repeat("a", MaxCustomFieldValueLength+1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateCustomFieldValues(fields, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateCustomFieldValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("ValidateCustomFieldValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffUserHistory(t *testing.T) {
+	supervisorID := int64(7)
+	before := &User{ID: 1, Title: "Engineer", Department: "Engineering", Role: RoleEmployee}
+	after := &User{ID: 1, Title: "Senior Engineer", Department: "Engineering", Role: RoleEmployee, SupervisorID: &supervisorID}
+
+	entries := DiffUserHistory(before, after)
+	if len(entries) != 2 {
+		t.Fatalf("DiffUserHistory() returned %d entries, want 2: %+v", len(entries), entries)
+	}
+	if e := entries[0]; e.Field != UserHistoryTitle || *e.OldValue != "Engineer" || *e.NewValue != "Senior Engineer" {
+		t.Errorf("title entry = %+v", e)
+	}
+	if e := entries[1]; e.Field != UserHistorySupervisor || e.OldValue != nil || *e.NewValue != "7" {
+		t.Errorf("supervisor entry = %+v", e)
+	}
+
+	if entries := DiffUserHistory(before, before); len(entries) != 0 {
+		t.Errorf("DiffUserHistory() with no changes returned %+v", entries)
+	}
+}
+
+func TestSquadHistoryEntry(t *testing.T) {
+	if entry := SquadHistoryEntry(1, []int64{3, 1}, []int64{1, 3}); entry != nil {
+		t.Errorf("reordered squads recorded a change: %+v", entry)
+	}
+
+	entry := SquadHistoryEntry(1, []int64{3, 1}, []int64{2})
+	if entry == nil || entry.Field != UserHistorySquads || *entry.OldValue != "1,3" || *entry.NewValue != "2" {
+		t.Errorf("SquadHistoryEntry() = %+v, want squads 1,3 -> 2", entry)
+	}
+
+	entry = SquadHistoryEntry(1, nil, []int64{4})
+	if entry == nil || entry.OldValue != nil || *entry.NewValue != "4" {
+		t.Errorf("SquadHistoryEntry() from no squads = %+v", entry)
+	}
+}
+
+func TestMilestonesBetween(t *testing.T) {
+	date := func(y int, m time.Month, d int) *time.Time {
+		t := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	users := []User{
+		{ID: 1, FirstName: "Ada", LastName: "Lovelace", IsActive: true, Birthday: date(1990, time.March, 3), DateStarted: date(2020, time.March, 10)},
+		{ID: 2, FirstName: "Leap", LastName: "Day", IsActive: true, Birthday: date(1996, time.February, 29)},
+		{ID: 3, FirstName: "New", LastName: "Hire", IsActive: true, DateStarted: date(2025, time.March, 1)},
+		{ID: 4, FirstName: "Gone", LastName: "Away", IsActive: false, Birthday: date(1985, time.March, 5)},
+	}
+
+	got := MilestonesBetween(users, time.Date(2025, time.February, 27, 15, 0, 0, 0, time.UTC), time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC))
+	want := []struct {
+		userID int64
+		kind   MilestoneType
+		date   time.Time
+		years  int
+	}{
+		{2, MilestoneTypeBirthday, *date(2025, time.February, 28), 0},
+		{1, MilestoneTypeBirthday, *date(2025, time.March, 3), 0},
+		{1, MilestoneTypeWorkAnniversary, *date(2025, time.March, 10), 5},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("MilestonesBetween() = %+v, want %d milestones", got, len(want))
+	}
+	for i, w := range want {
+		m := got[i]
+		if m.UserID != w.userID || m.Type != w.kind || !m.Date.Equal(w.date) || m.Years != w.years {
+			t.Errorf("milestone %d = %+v, want %+v", i, m, w)
+		}
+	}
+	if title := got[2].Title(); title != "5 Year Work Anniversary - Ada Lovelace" {
+		t.Errorf("Title() = %q", title)
+	}
+
+	// Windows spanning a year boundary
+	got = MilestonesBetween(users, time.Date(2025, time.December, 20, 0, 0, 0, 0, time.UTC), time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC))
+	if len(got) != 3 || got[0].UserID != 2 || got[1].Type != MilestoneTypeWorkAnniversary || got[1].Years != 1 || got[2].UserID != 1 {
+		t.Errorf("MilestonesBetween() across new year = %+v", got)
+	}
+}
+
+func TestUpdateUserRequest_ValidateBirthday(t *testing.T) {
+	for _, tt := range []struct {
+		birthday string
+		wantErr  bool
+	}{
+		{"1990-04-12", false},
+		{" ", false},
+		{"04/12/1990", true},
+		{time.Now().AddDate(0, 0, 2).Format("2006-01-02"), true},
+	} {
+		birthday := tt.birthday
+		req := UpdateUserRequest{Birthday: &birthday}
+		if err := req.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with birthday %q error = %v, wantErr %v", tt.birthday, err, tt.wantErr)
+		}
+	}
+}
+
+func TestManagerNoteRequest_Validate(t *testing.T) {
+	zero := int64(0)
+	for _, tt := range []struct {
+		name    string
+		req     ManagerNoteRequest
+		wantErr bool
+	}{
+		{"valid", ManagerNoteRequest{Body: " Notes from our 1:1 "}, false},
+		{"blank body", ManagerNoteRequest{Body: "  "}, true},
+		{"too long", ManagerNoteRequest{Body: strings.Repeat("a", MaxManagerNoteLength+1)}, true},
+		{"invalid meeting", ManagerNoteRequest{Body: "x", MeetingID: &zero}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyResultProgress(t *testing.T) {
+	for _, tt := range []struct {
+		name                   string
+		start, target, current float64
+		want                   float64
+	}{
+		{"halfway", 0, 10, 5, 0.5},
+		{"not started", 20, 40, 20, 0},
+		{"past target", 0, 10, 15, 1},
+		{"decrease halfway", 100, 50, 75, 0.5},
+		{"decrease gone backwards", 100, 50, 120, 0},
+		{"no change needed and met", 5, 5, 5, 1},
+		{"no change needed and missed", 5, 5, 4, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			kr := KeyResult{StartValue: tt.start, TargetValue: tt.target, CurrentValue: tt.current}
+			kr.ComputeProgress()
+			if kr.Progress != tt.want {
+				t.Errorf("Progress = %v, want %v", kr.Progress, tt.want)
+			}
+		})
+	}
+
+	goal := Goal{KeyResults: []KeyResult{
+		{StartValue: 0, TargetValue: 10, CurrentValue: 10},
+		{StartValue: 0, TargetValue: 4, CurrentValue: 1},
+	}}
+	goal.ComputeProgress()
+	if goal.Progress != 0.625 {
+		t.Errorf("goal Progress = %v, want 0.625", goal.Progress)
+	}
+}
+
+func TestQuarters(t *testing.T) {
+	if q := QuarterOf(time.Date(2025, time.August, 14, 0, 0, 0, 0, time.UTC)); q != "2025-Q3" {
+		t.Errorf("QuarterOf() = %q, want 2025-Q3", q)
+	}
+	if q := QuarterOf(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)); q != "2026-Q1" {
+		t.Errorf("QuarterOf() = %q, want 2026-Q1", q)
+	}
+	for _, q := range []string{"2025-Q5", "2025Q1", "25-Q1", ""} {
+		if ValidateQuarter(q) == nil {
+			t.Errorf("ValidateQuarter(%q) accepted an invalid quarter", q)
+		}
+	}
+}
+
+func TestGoalRequest_Validate(t *testing.T) {
+	ownerID := int64(3)
+	dept := " Engineering "
+	krs := []KeyResultInput{{Title: "Ship the beta", TargetValue: 1}}
+	for _, tt := range []struct {
+		name    string
+		req     GoalRequest
+		wantErr bool
+	}{
+		{"user goal", GoalRequest{Title: "Grow", OwnerType: GoalOwnerUser, OwnerID: &ownerID, Quarter: "2025-Q3", KeyResults: krs}, false},
+		{"department goal", GoalRequest{Title: "Grow", OwnerType: GoalOwnerDepartment, OwnerDepartment: &dept, Quarter: "2025-Q3", KeyResults: krs}, false},
+		{"missing title", GoalRequest{OwnerType: GoalOwnerUser, OwnerID: &ownerID, Quarter: "2025-Q3", KeyResults: krs}, true},
+		{"squad without ID", GoalRequest{Title: "Grow", OwnerType: GoalOwnerSquad, Quarter: "2025-Q3", KeyResults: krs}, true},
+		{"unknown owner type", GoalRequest{Title: "Grow", OwnerType: "team", OwnerID: &ownerID, Quarter: "2025-Q3", KeyResults: krs}, true},
+		{"bad quarter", GoalRequest{Title: "Grow", OwnerType: GoalOwnerUser, OwnerID: &ownerID, Quarter: "Q3", KeyResults: krs}, true},
+		{"no key results", GoalRequest{Title: "Grow", OwnerType: GoalOwnerUser, OwnerID: &ownerID, Quarter: "2025-Q3"}, true},
+		{"untitled key result", GoalRequest{Title: "Grow", OwnerType: GoalOwnerUser, OwnerID: &ownerID, Quarter: "2025-Q3", KeyResults: []KeyResultInput{{Title: " "}}}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRollupGoals(t *testing.T) {
+	userID, squadID := int64(3), int64(7)
+	dept := "Engineering"
+	rollups := RollupGoals([]Goal{
+		{OwnerType: GoalOwnerUser, OwnerID: &userID, OwnerName: "Ada Lovelace", Progress: 1},
+		{OwnerType: GoalOwnerSquad, OwnerID: &squadID, OwnerName: "Platform", Progress: 0.4},
+		{OwnerType: GoalOwnerUser, OwnerID: &userID, OwnerName: "Ada Lovelace", Progress: 0.5},
+		{OwnerType: GoalOwnerDepartment, OwnerDepartment: &dept, OwnerName: dept, Progress: 0.2},
+	})
+	if len(rollups) != 3 {
+		t.Fatalf("RollupGoals() = %+v, want 3 owners", rollups)
+	}
+	if rollups[0].OwnerType != GoalOwnerDepartment || rollups[1].OwnerType != GoalOwnerSquad {
+		t.Errorf("RollupGoals() order = %+v, want departments, then squads, then users", rollups)
+	}
+	user := rollups[2]
+	if user.GoalCount != 2 || user.CompletedCount != 1 || user.Progress != 0.75 {
+		t.Errorf("user rollup = %+v, want 2 goals, 1 completed, 0.75 progress", user)
+	}
+}
+
+func TestKudosRequest_Validate(t *testing.T) {
+	blank := "  "
+	long := strings.Repeat("a", MaxCompanyValueLength+1)
+	for _, tt := range []struct {
+		name      string
+		req       KudosRequest
+		wantErr   bool
+		wantValue bool
+	}{
+		{"valid", KudosRequest{ToUserID: 2, Message: "Thanks for the review!"}, false, false},
+		{"blank value is dropped", KudosRequest{ToUserID: 2, Message: "Thanks", CompanyValue: &blank}, false, false},
+		{"missing recipient", KudosRequest{Message: "Thanks"}, true, false},
+		{"blank message", KudosRequest{ToUserID: 2, Message: " "}, true, false},
+		{"message too long", KudosRequest{ToUserID: 2, Message: strings.Repeat("a", MaxKudosMessageLength+1)}, true, false},
+		{"value too long", KudosRequest{ToUserID: 2, Message: "Thanks", CompanyValue: &long}, true, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (tt.req.CompanyValue != nil) != tt.wantValue {
+				t.Errorf("CompanyValue = %v, want set %v", tt.req.CompanyValue, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestOnboardingTemplateRequest_Validate(t *testing.T) {
+	guest := RoleGuest
+	blank := " "
+	item := OnboardingTemplateItemInput{Title: "Order a laptop", Assignee: OnboardingAssigneeIT, DueDays: 1}
+	for _, tt := range []struct {
+		name    string
+		req     OnboardingTemplateRequest
+		wantErr bool
+	}{
+		{"valid", OnboardingTemplateRequest{Name: "Everyone", Department: &blank, Items: []OnboardingTemplateItemInput{item}}, false},
+		{"missing name", OnboardingTemplateRequest{Items: []OnboardingTemplateItemInput{item}}, true},
+		{"guest role", OnboardingTemplateRequest{Name: "Guests", Role: &guest, Items: []OnboardingTemplateItemInput{item}}, true},
+		{"no items", OnboardingTemplateRequest{Name: "Empty"}, true},
+		{"unknown assignee", OnboardingTemplateRequest{Name: "x", Items: []OnboardingTemplateItemInput{{Title: "x", Assignee: "hr"}}}, true},
+		{"negative due days", OnboardingTemplateRequest{Name: "x", Items: []OnboardingTemplateItemInput{{Title: "x", Assignee: OnboardingAssigneeNewHire, DueDays: -1}}}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tt.req.Department != nil {
+				t.Errorf("Department = %q, want blank departments cleared", *tt.req.Department)
+			}
+		})
+	}
+}
+
+func TestNewOnboardingProgress(t *testing.T) {
+	progress := NewOnboardingProgress(1, []Task{
+		{Status: TaskStatusCompleted},
+		{Status: TaskStatusPending},
+		{Status: TaskStatusCancelled},
+		{Status: TaskStatusCompleted},
+	})
+	if progress.Total != 3 || progress.Completed != 2 || progress.Progress != 2.0/3 {
+		t.Errorf("NewOnboardingProgress() = %+v, want 2 of 3 done", progress)
+	}
+	if empty := NewOnboardingProgress(1, []Task{}); empty.Progress != 0 {
+		t.Errorf("NewOnboardingProgress() with no tasks = %+v", empty)
+	}
+}
+
+func TestAvatarVariants_SrcSets(t *testing.T) {
+	if got := AvatarVariants(nil).SrcSets(); got != nil {
+		t.Errorf("SrcSets() with no variants = %v, want nil", got)
+	}
+
+	variants := AvatarVariants{
+		{Size: 32, URL: "https://cdn/a-32.jpg", ContentType: "image/jpeg", WebPURL: "https://cdn/a-32.webp"},
+		{Size: 128, URL: "https://cdn/a-128.jpg", ContentType: "image/jpeg", WebPURL: "https://cdn/a-128.webp"},
+	}
+	want := map[string]string{
+		"image/jpeg": "https://cdn/a-32.jpg 32w, https://cdn/a-128.jpg 128w",
+		"image/webp": "https://cdn/a-32.webp 32w, https://cdn/a-128.webp 128w",
+	}
+	if got := variants.SrcSets(); !maps.Equal(got, want) {
+		t.Errorf("SrcSets() = %v, want %v", got, want)
+	}
+}
+
+func TestToUserResponse_ResolvesAvatarURLs(t *testing.T) {
+	SetAvatarURLResolver(func(url string) string { return url + "?signed" })
+	t.Cleanup(func() { SetAvatarURLResolver(nil) })
+
+	avatar := "https://bucket/a-128.jpg"
+	user := &User{
+		AvatarURL:      &avatar,
+		AvatarVariants: AvatarVariants{{Size: 128, URL: avatar, ContentType: "image/jpeg", WebPURL: "https://bucket/a-128.webp"}},
+	}
+	resp := user.ToUserResponse()
+
+	if *resp.AvatarURL != avatar+"?signed" {
+		t.Errorf("AvatarURL = %s, want it resolved", *resp.AvatarURL)
+	}
+	if resp.AvatarSrcSet["image/webp"] != "https://bucket/a-128.webp?signed 128w" {
+		t.Errorf("AvatarSrcSet = %v, want resolved URLs", resp.AvatarSrcSet)
+	}
+	if *user.AvatarURL != avatar || user.AvatarVariants[0].URL != avatar {
+		t.Error("ToUserResponse should not modify the user")
+	}
+}
+
+func TestToUserResponse_DefaultAvatar(t *testing.T) {
+	fallback := "https://bucket/default/initials.svg"
+	user := &User{DefaultAvatarURL: &fallback}
+	if got := user.ToUserResponse().AvatarURL; got == nil || *got != fallback {
+		t.Errorf("AvatarURL = %v, want the default avatar", got)
+	}
+
+	uploaded := "https://bucket/a-512.jpg"
+	user.AvatarURL = &uploaded
+	if got := user.ToUserResponse().AvatarURL; *got != uploaded {
+		t.Errorf("AvatarURL = %s, want the uploaded avatar", *got)
+	}
+}
+
+func TestParseReportQuery(t *testing.T) {
+	now := time.Date(2026, 3, 15, 23, 30, 0, 0, time.FixedZone("PDT", -7*3600))
+
+	q, err := ParseReportQuery("", "", now)
+	if err != nil {
+		t.Fatalf("ParseReportQuery() error = %v", err)
+	}
+	if got := q.To.Format("2006-01-02"); got != "2026-03-16" {
+		t.Errorf("To = %s, want today in UTC", got)
+	}
+	if days := int(q.End().Sub(q.From).Hours() / 24); days != DefaultReportDays {
+		t.Errorf("default range = %d days, want %d", days, DefaultReportDays)
+	}
+
+	q, err = ParseReportQuery("2026-01-01", "2026-01-31", now)
+	if err != nil {
+		t.Fatalf("ParseReportQuery() error = %v", err)
+	}
+	if !q.End().Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("End() = %v, want the day after to", q.End())
+	}
+
+	for _, tt := range []struct{ from, to string }{
+		{"2026-02-01", "2026-01-31"},
+		{"01/01/2026", ""},
+		{"", "tomorrow"},
+		{"2016-01-01", "2026-01-01"},
+	} {
+		if _, err := ParseReportQuery(tt.from, tt.to, now); err == nil {
+			t.Errorf("ParseReportQuery(%q, %q) succeeded, want an error", tt.from, tt.to)
+		}
+	}
+}
+
+func TestReportQuery_SetInactiveDays(t *testing.T) {
+	q, _ := ParseReportQuery("", "", time.Now())
+	if q.InactiveDays != DefaultInactiveDays {
+		t.Errorf("InactiveDays = %d, want %d", q.InactiveDays, DefaultInactiveDays)
+	}
+	if err := q.SetInactiveDays(""); err != nil || q.InactiveDays != DefaultInactiveDays {
+		t.Errorf("SetInactiveDays(\"\") = %v, InactiveDays = %d, want the default kept", err, q.InactiveDays)
+	}
+	if err := q.SetInactiveDays("90"); err != nil || q.InactiveDays != 90 {
+		t.Errorf("SetInactiveDays(90) = %v, InactiveDays = %d", err, q.InactiveDays)
+	}
+	for _, days := range []string{"0", "-5", "ninety", "100000"} {
+		if err := q.SetInactiveDays(days); err == nil {
+			t.Errorf("SetInactiveDays(%q) succeeded, want an error", days)
+		}
+	}
+}
+
+func TestSavedViewRequest_Validate(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		req     SavedViewRequest
+		wantErr bool
+	}{
+		{"valid", SavedViewRequest{Resource: SavedViewResourceTasks, Name: " My overdue tasks ", Filters: map[string]string{"overdue": "true"}}, false},
+		{"no filters", SavedViewRequest{Resource: SavedViewResourceTeamMembers, Name: "Everyone"}, false},
+		{"unknown resource", SavedViewRequest{Resource: "meetings", Name: "x"}, true},
+		{"blank name", SavedViewRequest{Resource: SavedViewResourceTasks, Name: "  "}, true},
+		{"name too long", SavedViewRequest{Resource: SavedViewResourceTasks, Name: strings.Repeat("a", MaxSavedViewNameLength+1)}, true},
+		{"filter of another list", SavedViewRequest{Resource: SavedViewResourceTimeOff, Name: "x", Filters: map[string]string{"overdue": "true"}}, true},
+		{"filter too long", SavedViewRequest{Resource: SavedViewResourceTeamMembers, Name: "x", Filters: map[string]string{"q": strings.Repeat("a", MaxSavedViewFilterLength+1)}}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateOrgSettingsRequest_Validate(t *testing.T) {
+	days := func(n int) *int { return &n }
+	for _, tt := range []struct {
+		name    string
+		req     UpdateOrgSettingsRequest
+		wantErr bool
+	}{
+		{"valid", UpdateOrgSettingsRequest{Settings: map[OrgSettingKey]*int{OrgSettingInvitationExpiryDays: days(14)}}, false},
+		{"reset", UpdateOrgSettingsRequest{Settings: map[OrgSettingKey]*int{OrgSettingAvatarMaxSizeMB: nil}}, false},
+		{"empty", UpdateOrgSettingsRequest{}, true},
+		{"unknown setting", UpdateOrgSettingsRequest{Settings: map[OrgSettingKey]*int{"rate_limit": days(10)}}, true},
+		{"below minimum", UpdateOrgSettingsRequest{Settings: map[OrgSettingKey]*int{OrgSettingInvitationExpiryDays: days(0)}}, true},
+		{"above maximum", UpdateOrgSettingsRequest{Settings: map[OrgSettingKey]*int{OrgSettingMeetingAttachmentMaxSizeMB: days(101)}}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateInvitationDomainsRequest_Validate(t *testing.T) {
+	req := UpdateInvitationDomainsRequest{Domains: []string{" Example.com", "@eu.example.com", "example.com"}}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if strings.Join(req.Domains, ",") != "example.com,eu.example.com" {
+		t.Errorf("Domains = %v, want normalized and deduplicated", req.Domains)
+	}
+
+	for _, domain := range []string{"", "localhost", "exa mple.com", "-example.com", "user@example.com"} {
+		req := UpdateInvitationDomainsRequest{Domains: []string{domain}}
+		if err := req.Validate(); err == nil {
+			t.Errorf("Validate(%q) error = nil, want error", domain)
+		}
+	}
+}
+
+func TestEmailInDomains(t *testing.T) {
+	domains := []string{"example.com"}
+	tests := []struct {
+		email   string
+		domains []string
+		want    bool
+	}{
+		{"dev@example.com", domains, true},
+		{"Dev@EXAMPLE.com", domains, true},
+		{"dev@mail.example.com", domains, false},
+		{"dev@example.com.evil.io", domains, false},
+		{"dev@other.io", nil, true},
+	}
+	for _, tt := range tests {
+		if got := EmailInDomains(tt.email, tt.domains); got != tt.want {
+			t.Errorf("EmailInDomains(%q, %v) = %v, want %v", tt.email, tt.domains, got, tt.want)
+		}
+	}
+}
+
+func TestBulkInvitationRequest_Validate(t *testing.T) {
+	if err := (&BulkInvitationRequest{Role: RoleEmployee}).Validate(); err == nil {
+		t.Error("Validate() with no emails error = nil, want error")
+	}
+	tooMany := BulkInvitationRequest{Role: RoleEmployee, Emails: make([]string, MaxBulkInvitations+1)}
+	if err := tooMany.Validate(); err == nil {
+		t.Error("Validate() with too many emails error = nil, want error")
+	}
+	req := BulkInvitationRequest{Role: RoleEmployee, Title: "Engineer", Emails: []string{" dev@example.com "}}
+	if inv := req.Invitation(req.Emails[0]); inv.Email != "dev@example.com" || inv.Title != "Engineer" || inv.Role != RoleEmployee {
+		t.Errorf("Invitation() = %+v, want the shared details with a trimmed email", inv)
+	}
+}
+
+func TestApproveAccessRequestRequest_Validate(t *testing.T) {
+	for _, role := range []Role{RoleAdmin, RoleSupervisor, RoleEmployee} {
+		req := ApproveAccessRequestRequest{Role: role, Title: " Engineer "}
+		if err := req.Validate(); err != nil {
+			t.Errorf("Validate(%q) error = %v", role, err)
+		}
+		if req.Title != "Engineer" {
+			t.Errorf("Title = %q, want trimmed", req.Title)
+		}
+	}
+	for _, req := range []ApproveAccessRequestRequest{
+		{},
+		{Role: RoleGuest},
+		{Role: RoleEmployee, Title: strings.Repeat("x", 256)},
+	} {
+		if err := req.Validate(); err == nil {
+			t.Errorf("Validate(%+v) error = nil, want error", req)
+		}
+	}
+}
@@ -2,6 +2,36 @@ package models
 
 import "time"
 
+// avatarURLResolver rewrites stored avatar URLs in user responses, e.g. into presigned or CDN URLs
+var avatarURLResolver func(url string) string
+
+// SetAvatarURLResolver sets how ToUserResponse rewrites stored avatar URLs into ones clients can
+// load. It is called once at startup, before any requests are served; nil leaves URLs unchanged.
+func SetAvatarURLResolver(resolve func(url string) string) {
+	avatarURLResolver = resolve
+}
+
+// resolveAvatarURLs applies the avatar URL resolver to a user's avatar and its variants
+func resolveAvatarURLs(avatarURL *string, variants AvatarVariants) (*string, AvatarVariants) {
+	if avatarURLResolver == nil {
+		return avatarURL, variants
+	}
+	if avatarURL != nil {
+		resolved := avatarURLResolver(*avatarURL)
+		avatarURL = &resolved
+	}
+	if variants != nil {
+		resolved := make(AvatarVariants, len(variants))
+		for i, v := range variants {
+			v.URL = avatarURLResolver(v.URL)
+			v.WebPURL = avatarURLResolver(v.WebPURL)
+			resolved[i] = v
+		}
+		variants = resolved
+	}
+	return avatarURL, variants
+}
+
 // UserResponse is a DTO for API responses that excludes sensitive internal fields.
 // This follows the "deny by default" principle - only expose fields the client needs.
 type UserResponse struct {
@@ -21,6 +51,43 @@ type UserResponse struct {
 	UpdatedAt    time.Time  `json:"updated_at"`
 	// Jira status (only expose whether configured, not credentials)
 	JiraAccountID *string `json:"jira_account_id,omitempty"`
+	// Guest accounts only
+	AccessExpiresAt *time.Time `json:"access_expires_at,omitempty"`
+	Timezone        string     `json:"timezone"`
+	Language        string     `json:"language"`
+	// Set once the user has been offboarded
+	TerminationDate *time.Time `json:"termination_date,omitempty"`
+	Birthday        *time.Time `json:"birthday,omitempty"`
+	// Resized avatars, and srcset attributes for them keyed by content type
+	AvatarVariants AvatarVariants    `json:"avatar_variants,omitempty"`
+	AvatarSrcSet   map[string]string `json:"avatar_srcset,omitempty"`
+	// Admin-defined profile fields, keyed by field key
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+	// When the user last signed in and was last seen; only included for admins
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	LastSeenAt  *time.Time `json:"last_seen_at,omitempty"`
+	// Contact details the user keeps up to date themselves
+	PreferredName string `json:"preferred_name,omitempty"`
+	Pronouns      string `json:"pronouns,omitempty"`
+	Phone         string `json:"phone,omitempty"`
+	// Only included for the user themselves and whoever can update them
+	EmergencyContact *EmergencyContact `json:"emergency_contact,omitempty"`
+	// The user's title or department change awaiting review; only included for the user themselves
+	PendingProfileChange *ProfileChangeRequest `json:"pending_profile_change,omitempty"`
+}
+
+// WithEmergencyContact adds the user's emergency contact to the response
+func (r *UserResponse) WithEmergencyContact(u *User) *UserResponse {
+	contact := u.EmergencyContact
+	r.EmergencyContact = &contact
+	return r
+}
+
+// WithLoginActivity adds when the user last signed in and was last seen to the response
+func (r *UserResponse) WithLoginActivity(u *User) *UserResponse {
+	r.LastLoginAt = u.LastLoginAt
+	r.LastSeenAt = u.LastSeenAt
+	return r
 }
 
 // ToUserResponse converts a User model to a UserResponse DTO.
@@ -29,6 +96,11 @@ func (u *User) ToUserResponse() *UserResponse {
 	if u == nil {
 		return nil
 	}
+	avatarURL := u.AvatarURL
+	if avatarURL == nil {
+		avatarURL = u.DefaultAvatarURL
+	}
+	avatarURL, avatarVariants := resolveAvatarURLs(avatarURL, u.AvatarVariants)
 	return &UserResponse{
 		ID:            u.ID,
 		Email:         u.Email,
@@ -38,13 +110,25 @@ func (u *User) ToUserResponse() *UserResponse {
 		Title:         u.Title,
 		Department:    u.Department,
 		Squads:        u.Squads,
-		AvatarURL:     u.AvatarURL,
+		AvatarURL:     avatarURL,
 		SupervisorID:  u.SupervisorID,
 		DateStarted:   u.DateStarted,
 		IsActive:      u.IsActive,
 		CreatedAt:     u.CreatedAt,
 		UpdatedAt:     u.UpdatedAt,
 		JiraAccountID: u.JiraAccountID,
+
+		AccessExpiresAt: u.AccessExpiresAt,
+		Timezone:        u.Timezone,
+		Language:        u.Language,
+		TerminationDate: u.TerminationDate,
+		Birthday:        u.Birthday,
+		AvatarVariants:  avatarVariants,
+		AvatarSrcSet:    avatarVariants.SrcSets(),
+		CustomFields:    u.CustomFields,
+		PreferredName:   u.PreferredName,
+		Pronouns:        u.Pronouns,
+		Phone:           u.Phone,
 	}
 }
 
@@ -76,6 +160,13 @@ type InvitationResponse struct {
 	AcceptedAt  *time.Time       `json:"accepted_at,omitempty"`
 	CreatedAt   time.Time        `json:"created_at"`
 	UpdatedAt   time.Time        `json:"updated_at"`
+	// Guest invitations only
+	AccessExpiresAt *time.Time `json:"access_expires_at,omitempty"`
+	MeetingIDs      []int64    `json:"meeting_ids,omitempty"`
+	// What the new user starts with
+	SupervisorID *int64     `json:"supervisor_id,omitempty"`
+	Title        string     `json:"title,omitempty"`
+	DateStarted  *time.Time `json:"date_started,omitempty"`
 }
 
 // ToInvitationResponse converts an Invitation model to an InvitationResponse DTO.
@@ -96,6 +187,13 @@ func (i *Invitation) ToInvitationResponse() *InvitationResponse {
 		AcceptedAt:  i.AcceptedAt,
 		CreatedAt:   i.CreatedAt,
 		UpdatedAt:   i.UpdatedAt,
+
+		AccessExpiresAt: i.AccessExpiresAt,
+		MeetingIDs:      i.MeetingIDs,
+
+		SupervisorID: i.SupervisorID,
+		Title:        i.Title,
+		DateStarted:  i.DateStarted,
 	}
 	if i.InvitedBy != nil {
 		resp.InvitedBy = i.InvitedBy.ToUserResponse()
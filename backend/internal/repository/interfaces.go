@@ -16,20 +16,33 @@ type UserRepository interface {
 	GetAll(ctx context.Context) ([]models.User, error)
 	Create(ctx context.Context, req *models.CreateUserRequest, auth0ID string) (*models.User, error)
 	Update(ctx context.Context, id int64, req *models.UpdateUserRequest) (*models.User, error)
+	SetAvatar(ctx context.Context, id int64, avatarURL string, variants models.AvatarVariants) (*models.User, error)
+	SetSupervisor(ctx context.Context, id int64, supervisorID *int64) (*models.User, error)
+	ListMissingDefaultAvatars(ctx context.Context, afterID int64, limit int) ([]models.User, error)
+	SetDefaultAvatar(ctx context.Context, id int64, url string) error
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (*models.User, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 	Deactivate(ctx context.Context, id int64) error
 	Reactivate(ctx context.Context, id int64) error
+	Offboard(ctx context.Context, id int64, reassignTasksToID *int64, terminationDate time.Time) (*models.OffboardResult, error)
 	GetDirectReportsBySupervisorID(ctx context.Context, supervisorID int64) ([]models.User, error)
+	GetDirectReportsBySupervisorIDs(ctx context.Context, supervisorIDs []int64) (map[int64][]models.User, error)
 	GetAllSupervisors(ctx context.Context) ([]models.User, error)
 	GetAllDepartments(ctx context.Context) ([]string, error)
 	ClearDepartment(ctx context.Context, department string) error
 	RenameDepartment(ctx context.Context, oldName, newName string) error
 	GetUsersByDepartment(ctx context.Context, department string) ([]models.User, error)
+	Search(ctx context.Context, filter models.UserSearchFilter, limit, offset int) ([]models.User, int, error)
 	// Jira-related methods
 	UpdateJiraSettings(ctx context.Context, id int64, req *models.UpdateJiraSettingsRequest) error
 	ClearJiraSettings(ctx context.Context, id int64) error
 	UpdateJiraAccountID(ctx context.Context, id int64, jiraAccountID *string) error
 	SaveJiraOAuthTokens(ctx context.Context, id int64, tokens *models.JiraOAuthTokens) error
+	// GitHub-related methods
+	UpdateGitHubLogin(ctx context.Context, id int64, githubLogin *string) error
+	// Linear-related methods
+	UpdateLinearUserID(ctx context.Context, id int64, linearUserID *string) error
 }
 
 // SquadRepository defines the interface for squad data access
@@ -44,6 +57,10 @@ type SquadRepository interface {
 	Delete(ctx context.Context, id int64) error
 	SetUserSquads(ctx context.Context, userID int64, squadIDs []int64) error
 	GetUsersBySquadID(ctx context.Context, squadID int64) ([]models.User, error)
+	SetLead(ctx context.Context, squadID, userID int64) error
+	ClearLead(ctx context.Context, squadID int64) error
+	GetLedSquadIDs(ctx context.Context, userID int64) ([]int64, error)
+	IsSquadLeadOf(ctx context.Context, leadID, memberID int64) (bool, error)
 }
 
 // DepartmentRepository defines the interface for department data access
@@ -57,15 +74,166 @@ type DepartmentRepository interface {
 	Rename(ctx context.Context, oldName, newName string) error
 }
 
+// CustomFieldRepository defines the interface for custom profile field data access
+type CustomFieldRepository interface {
+	GetAll(ctx context.Context) ([]models.CustomField, error)
+	GetByID(ctx context.Context, id int64) (*models.CustomField, error)
+	GetByKey(ctx context.Context, key string) (*models.CustomField, error)
+	Create(ctx context.Context, req *models.CreateCustomFieldRequest) (*models.CustomField, error)
+	Update(ctx context.Context, id int64, req *models.UpdateCustomFieldRequest) (*models.CustomField, error)
+	Delete(ctx context.Context, id int64) error
+	GetValuesForUsers(ctx context.Context, userIDs []int64) (map[int64]map[string]string, error)
+	SetUserValues(ctx context.Context, userID int64, values map[int64]string) error
+}
+
+// UserHistoryRepository defines the interface for users' employment history
+type UserHistoryRepository interface {
+	Record(ctx context.Context, source models.UserHistorySource, changedByID *int64, entries []models.UserHistoryEntry) error
+	GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]models.UserHistoryEntry, int, error)
+}
+
+// SkillRepository defines the interface for the skills catalog and users' skill levels
+type SkillRepository interface {
+	GetAll(ctx context.Context) ([]models.Skill, error)
+	GetByID(ctx context.Context, id int64) (*models.Skill, error)
+	GetByName(ctx context.Context, name string) (*models.Skill, error)
+	Create(ctx context.Context, req *models.SkillRequest) (*models.Skill, error)
+	Update(ctx context.Context, id int64, req *models.SkillRequest) (*models.Skill, error)
+	Delete(ctx context.Context, id int64) error
+	GetUserSkills(ctx context.Context, userID int64) ([]models.UserSkill, error)
+	SetUserSkills(ctx context.Context, userID int64, skills []models.UserSkillLevel) error
+	FindExperts(ctx context.Context, query string, minLevel models.SkillLevel) ([]models.SkillExpert, error)
+	GetSquadCoverage(ctx context.Context, squadID int64) (*models.SquadSkillCoverage, error)
+}
+
+// GoalRepository defines the interface for goals, their key results, and progress updates
+type GoalRepository interface {
+	List(ctx context.Context, filter models.GoalFilter) ([]models.Goal, error)
+	GetByID(ctx context.Context, id int64) (*models.Goal, error)
+	Create(ctx context.Context, req *models.GoalRequest, createdByID int64) (*models.Goal, error)
+	Update(ctx context.Context, id int64, req *models.GoalRequest) (*models.Goal, error)
+	Delete(ctx context.Context, id int64) error
+	GetKeyResult(ctx context.Context, id int64) (*models.KeyResult, error)
+	RecordProgress(ctx context.Context, keyResultID, authorID int64, req *models.ProgressUpdateRequest) (*models.GoalProgressUpdate, error)
+	GetProgressUpdates(ctx context.Context, goalID int64) ([]models.GoalProgressUpdate, error)
+}
+
+// ManagerNoteRepository defines the interface for supervisors' private notes about their reports
+type ManagerNoteRepository interface {
+	Create(ctx context.Context, authorID, subjectID int64, req *models.ManagerNoteRequest) (*models.ManagerNote, error)
+	GetByID(ctx context.Context, id int64) (*models.ManagerNote, error)
+	ListByAuthor(ctx context.Context, authorID, subjectID int64, limit, offset int) ([]models.ManagerNote, int, error)
+	ListBySubject(ctx context.Context, subjectID int64, limit, offset int) ([]models.ManagerNote, int, error)
+	Update(ctx context.Context, id int64, req *models.ManagerNoteRequest) (*models.ManagerNote, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// SavedViewRepository defines the interface for users' saved list filters
+type SavedViewRepository interface {
+	Create(ctx context.Context, userID int64, req *models.SavedViewRequest) (*models.SavedView, error)
+	GetByID(ctx context.Context, id int64) (*models.SavedView, error)
+	ListByUser(ctx context.Context, userID int64, resource *models.SavedViewResource) ([]models.SavedView, error)
+	Update(ctx context.Context, id int64, req *models.SavedViewRequest) (*models.SavedView, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// AccessRequestRepository defines the interface for requests to join without an invitation
+type AccessRequestRepository interface {
+	Create(ctx context.Context, req *models.AccessRequest) (*models.AccessRequest, error)
+	GetByID(ctx context.Context, id int64) (*models.AccessRequest, error)
+	GetLatestByAuth0ID(ctx context.Context, auth0ID string) (*models.AccessRequest, error)
+	List(ctx context.Context, status *models.AccessRequestStatus) ([]models.AccessRequest, error)
+	Approve(ctx context.Context, id int64, approval *models.ApproveAccessRequestRequest, reviewerID int64) (*models.User, error)
+	Reject(ctx context.Context, id int64, reviewerID int64) (*models.AccessRequest, error)
+}
+
+// ProfileChangeRepository defines the interface for title and department changes awaiting review
+type ProfileChangeRepository interface {
+	Propose(ctx context.Context, userID int64, title, department *string) (*models.ProfileChangeRequest, error)
+	GetByID(ctx context.Context, id int64) (*models.ProfileChangeRequest, error)
+	GetLatestByUser(ctx context.Context, userID int64) (*models.ProfileChangeRequest, error)
+	List(ctx context.Context, status *models.ProfileChangeStatus, supervisorID *int64) ([]models.ProfileChangeRequest, error)
+	Approve(ctx context.Context, id int64, reviewerID int64) (*models.User, error)
+	Reject(ctx context.Context, id int64, reviewerID int64) (*models.ProfileChangeRequest, error)
+}
+
+// KudosRepository defines the interface for recognition between users
+type KudosRepository interface {
+	Create(ctx context.Context, fromUserID int64, req *models.KudosRequest) (*models.Kudos, error)
+	ListFeed(ctx context.Context, recipientIDs []int64, limit, offset int) ([]models.Kudos, int, error)
+	CountByDepartment(ctx context.Context, since time.Time) ([]models.KudosDepartmentCount, error)
+}
+
+// OnboardingRepository defines the interface for onboarding templates and users' onboarding tasks
+type OnboardingRepository interface {
+	ListTemplates(ctx context.Context) ([]models.OnboardingTemplate, error)
+	GetTemplate(ctx context.Context, id int64) (*models.OnboardingTemplate, error)
+	CreateTemplate(ctx context.Context, req *models.OnboardingTemplateRequest) (*models.OnboardingTemplate, error)
+	UpdateTemplate(ctx context.Context, id int64, req *models.OnboardingTemplateRequest) (*models.OnboardingTemplate, error)
+	DeleteTemplate(ctx context.Context, id int64) error
+	CreateTasks(ctx context.Context, userID, createdByID int64, reqs []models.CreateTaskRequest) ([]models.Task, error)
+	GetTasks(ctx context.Context, userID int64) ([]models.Task, error)
+}
+
+// AuditEventRepository defines the interface for the audit log of changes made through the API
+type AuditEventRepository interface {
+	Create(ctx context.Context, event *models.AuditEvent) error
+	List(ctx context.Context, filter models.AuditEventFilter, limit, offset int) ([]models.AuditEvent, int, error)
+}
+
+// CustomRoleRepository defines the interface for custom roles and their assignment to users
+type CustomRoleRepository interface {
+	GetAll(ctx context.Context) ([]models.CustomRole, error)
+	GetByID(ctx context.Context, id int64) (*models.CustomRole, error)
+	GetByName(ctx context.Context, name string) (*models.CustomRole, error)
+	Create(ctx context.Context, req *models.CustomRoleRequest, createdByID int64) (*models.CustomRole, error)
+	Update(ctx context.Context, id int64, req *models.CustomRoleRequest) (*models.CustomRole, error)
+	Delete(ctx context.Context, id int64) error
+	GetForUser(ctx context.Context, userID int64) ([]models.CustomRole, error)
+	SetForUser(ctx context.Context, userID int64, roleIDs []int64, assignedByID int64) error
+}
+
+// SessionRepository defines the interface for users' sessions and the denylist of revoked tokens
+type SessionRepository interface {
+	Touch(ctx context.Context, session *models.UserSession) error
+	GetByID(ctx context.Context, id int64) (*models.UserSession, error)
+	ListByUser(ctx context.Context, userID int64, limit int) ([]models.UserSession, error)
+	ListLogins(ctx context.Context, userID int64, limit int) ([]models.UserLogin, error)
+	Revoke(ctx context.Context, revoked *models.RevokedToken) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	GetSubjectRevokedAt(ctx context.Context, subject string) (*time.Time, error)
+	DeleteExpired(ctx context.Context, sessionsBefore, loginsBefore time.Time) (int64, error)
+}
+
+// ImpersonationRepository defines the interface for the sessions in which admins impersonate users
+type ImpersonationRepository interface {
+	Start(ctx context.Context, session *models.ImpersonationSession) error
+	GetByID(ctx context.Context, id int64) (*models.ImpersonationSession, error)
+	End(ctx context.Context, adminID, userID int64) (int64, error)
+}
+
+// OrganizationRepository defines the interface for organization-wide settings
+type OrganizationRepository interface {
+	GetIPAllowlist(ctx context.Context, orgID int64) ([]string, error)
+	UpdateIPAllowlist(ctx context.Context, orgID int64, allowlist []string) error
+	GetInvitationDomains(ctx context.Context, orgID int64) ([]string, error)
+	UpdateInvitationDomains(ctx context.Context, orgID int64, domains []string) error
+	GetByInvitationDomain(ctx context.Context, domain string) (*int64, error)
+	GetSettings(ctx context.Context, orgID int64) (map[models.OrgSettingKey]string, error)
+	UpdateSettings(ctx context.Context, orgID int64, changes map[models.OrgSettingKey]*string, updatedByID int64) error
+}
+
 // TimeOffRepository defines the interface for time-off request data access
 type TimeOffRepository interface {
 	Create(ctx context.Context, userID int64, req *models.CreateTimeOffRequestInput) (*models.TimeOffRequest, error)
 	GetByID(ctx context.Context, id int64) (*models.TimeOffRequest, error)
 	GetByIDWithUser(ctx context.Context, id int64) (*models.TimeOffRequest, error)
 	GetByUserID(ctx context.Context, userID int64, status *models.TimeOffStatus) ([]models.TimeOffRequest, error)
-	GetVisibleRequests(ctx context.Context, user *models.User, status *models.TimeOffStatus) ([]models.TimeOffRequest, error)
+	GetVisibleRequests(ctx context.Context, user *models.User, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error)
+	GetAllRequests(ctx context.Context, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error)
 	GetPendingForSupervisor(ctx context.Context, supervisorID int64) ([]models.TimeOffRequest, error)
 	GetAllPending(ctx context.Context) ([]models.TimeOffRequest, error)
+	CountPendingByUserIDs(ctx context.Context, userIDs []int64) (map[int64]int, error)
 	Review(ctx context.Context, id int64, reviewerID int64, req *models.ReviewTimeOffRequestInput) error
 	Cancel(ctx context.Context, id int64, userID int64) error
 	GetApprovedByDateRange(ctx context.Context, userID int64, start, end time.Time) ([]models.TimeOffRequest, error)
@@ -77,7 +245,7 @@ type TimeOffRepository interface {
 
 // InvitationRepository defines the interface for invitation data access
 type InvitationRepository interface {
-	Create(ctx context.Context, req *models.CreateInvitationRequest, invitedByID int64) (*models.Invitation, error)
+	Create(ctx context.Context, req *models.CreateInvitationRequest, invitedByID int64, email *models.InvitationEmail) (*models.Invitation, error)
 	GetByID(ctx context.Context, id int64) (*models.Invitation, error)
 	GetByToken(ctx context.Context, token string) (*models.Invitation, error)
 	GetByEmail(ctx context.Context, email string) (*models.Invitation, error)
@@ -98,9 +266,21 @@ type OrgChartRepository interface {
 	AddOrUpdateChange(ctx context.Context, draftID int64, req *models.AddDraftChangeRequest, userRepo UserRepository) (*models.DraftChange, error)
 	RemoveChange(ctx context.Context, draftID int64, userID int64) error
 	GetDraftChanges(ctx context.Context, draftID int64) ([]models.DraftChange, error)
-	PublishDraft(ctx context.Context, draftID int64) error
+	PublishDraft(ctx context.Context, draftID int64, publishedByID int64) error
+	ApproveDraft(ctx context.Context, draftID int64, approverID int64) (*models.OrgChartDraft, error)
+	GetSharedDrafts(ctx context.Context, userID int64) ([]models.OrgChartDraft, error)
+	GetDraftShares(ctx context.Context, draftID int64) ([]models.DraftShare, error)
+	GetDraftShare(ctx context.Context, draftID int64, userID int64) (*models.DraftShare, error)
+	ShareDraft(ctx context.Context, draftID int64, req *models.ShareDraftRequest, sharedByID int64) (*models.DraftShare, error)
+	UnshareDraft(ctx context.Context, draftID int64, userID int64) error
+	GetDraftComments(ctx context.Context, draftID int64) ([]models.DraftComment, error)
+	AddDraftComment(ctx context.Context, draftID int64, req *models.CreateDraftCommentRequest, authorID int64) (*models.DraftComment, error)
+	GetSnapshots(ctx context.Context, before *time.Time, limit, offset int) ([]models.OrgChartSnapshot, int, error)
+	GetSnapshotByID(ctx context.Context, id int64) (*models.OrgChartSnapshot, error)
 	GetOrgTree(ctx context.Context, supervisorID int64) (*models.OrgTreeNode, error)
 	GetFullOrgTree(ctx context.Context) ([]models.OrgTreeNode, error)
+	GetScopedOrgTree(ctx context.Context, scope models.OrgTreeScope) ([]models.OrgTreeNode, error)
+	GetMemberStats(ctx context.Context, userIDs []int64, now time.Time) (map[int64]models.OrgMemberStats, error)
 }
 
 // OrgJiraRepository defines the interface for organization Jira settings
@@ -108,20 +288,60 @@ type OrgJiraRepository interface {
 	Get(ctx context.Context) (*models.OrgJiraSettings, error)
 	Save(ctx context.Context, settings *models.OrgJiraSettings) error
 	UpdateTokens(ctx context.Context, accessToken, refreshToken string, expiresAt time.Time) error
+	RecordRefreshFailure(ctx context.Context, message string) (int, error)
+	RecordAPIError(ctx context.Context, message string) error
+	RecordWebhookDelivery(ctx context.Context) error
+	UpdateTaskFilters(ctx context.Context, filters []models.JiraTaskFilter) (bool, error)
+	SaveUserMatchReport(ctx context.Context, report *models.JiraUserMatchReport) (bool, error)
+	GetUserMatchReport(ctx context.Context) (*models.JiraUserMatchReport, error)
+	Delete(ctx context.Context) error
+	SavePendingConnection(ctx context.Context, pending *models.JiraPendingConnection) error
+	GetPendingConnection(ctx context.Context) (*models.JiraPendingConnection, error)
+	DeletePendingConnection(ctx context.Context) error
+}
+
+// OrgGitHubRepository defines the interface for organizations' GitHub connections
+type OrgGitHubRepository interface {
+	Get(ctx context.Context) (*models.OrgGitHubSettings, error)
+	Save(ctx context.Context, settings *models.OrgGitHubSettings) error
+	UpdateTokens(ctx context.Context, accessToken, refreshToken string, expiresAt *time.Time) error
+	UpdateOrgLogin(ctx context.Context, orgLogin *string) (bool, error)
+	Delete(ctx context.Context) error
+}
+
+// OrgSlackRepository defines the interface for organizations' Slack app installations
+type OrgSlackRepository interface {
+	Get(ctx context.Context) (*models.OrgSlackSettings, error)
+	GetByTeamID(ctx context.Context, teamID string) (*models.OrgSlackSettings, error)
+	Save(ctx context.Context, settings *models.OrgSlackSettings) error
+	Delete(ctx context.Context) error
+}
+
+// OrgLinearRepository defines the interface for organizations' Linear connections
+type OrgLinearRepository interface {
+	Get(ctx context.Context) (*models.OrgLinearSettings, error)
+	Save(ctx context.Context, settings *models.OrgLinearSettings) error
 	Delete(ctx context.Context) error
 }
 
 // TaskRepository defines the interface for task data access
 type TaskRepository interface {
 	Create(ctx context.Context, req *models.CreateTaskRequest, createdByID int64) (*models.Task, error)
+	CreateMany(ctx context.Context, reqs []models.CreateTaskRequest, createdByID int64) ([]models.Task, error)
+	Reassign(ctx context.Context, req *models.ReassignTasksRequest, actor *models.User) (*models.BulkTaskResult, error)
 	GetByID(ctx context.Context, id int64) (*models.Task, error)
 	Update(ctx context.Context, id int64, req *models.UpdateTaskRequest) (*models.Task, error)
+	AdvanceRecurrence(ctx context.Context, id int64) (*models.Task, *models.Task, error)
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (*models.Task, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 	GetByDateRange(ctx context.Context, userID int64, start, end time.Time) ([]models.Task, error)
 	GetByDateRangeForSquad(ctx context.Context, squadID int64, start, end time.Time) ([]models.Task, error)
 	GetByDateRangeForDepartment(ctx context.Context, department string, start, end time.Time) ([]models.Task, error)
 	GetAllByDateRange(ctx context.Context, start, end time.Time) ([]models.Task, error)
 	GetVisibleTasks(ctx context.Context, user *models.User, start, end time.Time) ([]models.Task, error)
+	List(ctx context.Context, user *models.User, filter models.TaskFilter) ([]models.Task, error)
+	CountOpenByAssigneeIDs(ctx context.Context, userIDs []int64) (map[int64]int, error)
 }
 
 // MeetingRepository defines the interface for meeting data access
@@ -131,10 +351,143 @@ type MeetingRepository interface {
 	GetAttendees(ctx context.Context, meetingID int64) ([]models.MeetingAttendee, error)
 	Update(ctx context.Context, id int64, req *models.UpdateMeetingRequest) (*models.Meeting, error)
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (*models.Meeting, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	UpdateOccurrence(ctx context.Context, seriesID int64, req *models.UpdateMeetingOccurrenceRequest) (*models.Meeting, error)
+	CancelOccurrence(ctx context.Context, seriesID int64, occurrenceStart time.Time, scope models.OccurrenceScope) error
 	RespondToMeeting(ctx context.Context, meetingID int64, userID int64, response models.ResponseStatus) error
 	GetByDateRange(ctx context.Context, userID int64, start, end time.Time) ([]models.Meeting, error)
 	GetAllByDateRange(ctx context.Context, start, end time.Time) ([]models.Meeting, error)
 	GetVisibleMeetings(ctx context.Context, user *models.User, start, end time.Time) ([]models.Meeting, error)
 	ExpandRecurringMeetings(meetings []models.Meeting, start, end time.Time) []models.Meeting
 	IsAttendee(ctx context.Context, meetingID, userID int64) (bool, error)
+	HasICalUID(ctx context.Context, createdByID int64, uid string) (bool, error)
+}
+
+// TaskCommentRepository defines the interface for task comment and activity data access
+type TaskCommentRepository interface {
+	Create(ctx context.Context, taskID, authorID int64, body string) (*models.TaskComment, error)
+	GetByID(ctx context.Context, id int64) (*models.TaskComment, error)
+	ListByTask(ctx context.Context, taskID int64) ([]models.TaskComment, error)
+	Update(ctx context.Context, id int64, body string) (*models.TaskComment, error)
+	Delete(ctx context.Context, id int64) error
+	RecordStatusChange(ctx context.Context, taskID, actorID int64, from, to models.TaskStatus) (*models.TaskActivity, error)
+	ListActivity(ctx context.Context, taskID int64) ([]models.TaskActivity, error)
+}
+
+// MeetingNotesRepository defines the interface for meeting agenda, notes, and action item data access
+type MeetingNotesRepository interface {
+	GetAgenda(ctx context.Context, meetingID int64) ([]models.MeetingAgendaItem, error)
+	ReplaceAgenda(ctx context.Context, meetingID int64, items []models.MeetingAgendaItemInput) ([]models.MeetingAgendaItem, error)
+	GetNotes(ctx context.Context, meetingID int64) (*models.MeetingNotes, error)
+	SaveNotes(ctx context.Context, meetingID, authorID int64, req *models.UpdateMeetingNotesRequest) (*models.MeetingNotes, error)
+	ConvertActionItems(ctx context.Context, meeting *models.Meeting, createdByID int64, itemIDs []int64, defaultDueDate time.Time) ([]models.Task, error)
+}
+
+// NotificationRepository defines the interface for in-app notification data access
+type NotificationRepository interface {
+	Create(ctx context.Context, n *models.Notification) (*models.Notification, error)
+	List(ctx context.Context, userID int64, unreadOnly bool, limit, offset int) ([]models.Notification, int, error)
+	CountUnread(ctx context.Context, userID int64) (int, error)
+	MarkRead(ctx context.Context, userID, id int64) error
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+}
+
+// WorkScheduleRepository defines the interface for working hours and focus time data access
+type WorkScheduleRepository interface {
+	GetForUsers(ctx context.Context, userIDs []int64) (map[int64]*models.WorkSchedule, error)
+	Get(ctx context.Context, userID int64) (*models.WorkSchedule, error)
+	Save(ctx context.Context, userID int64, req *models.UpdateWorkScheduleRequest) (*models.WorkSchedule, error)
+}
+
+// MeetingAttachmentRepository defines the interface for meeting recording and transcript data access
+type MeetingAttachmentRepository interface {
+	Create(ctx context.Context, attachment *models.MeetingAttachment) (*models.MeetingAttachment, error)
+	GetByID(ctx context.Context, id int64) (*models.MeetingAttachment, error)
+	ListByMeeting(ctx context.Context, meetingID int64, occurrenceStart *time.Time) ([]models.MeetingAttachment, error)
+	Delete(ctx context.Context, id int64) error
+	SearchTranscripts(ctx context.Context, userID int64, query string, limit int) ([]models.TranscriptSearchResult, error)
+}
+
+// JiraIssueCacheRepository defines the interface for the local Jira issue cache
+type JiraIssueCacheRepository interface {
+	Upsert(ctx context.Context, issue *models.JiraIssue, resolved bool) (bool, error)
+	Delete(ctx context.Context, issueKey string) (bool, error)
+	ListOpen(ctx context.Context, accountIDs []string) ([]models.JiraIssue, error)
+	ReplaceOpenForAssignee(ctx context.Context, accountID string, issues []models.JiraIssue, syncedAt time.Time) error
+	GetSyncTimes(ctx context.Context, accountIDs []string) (map[string]time.Time, error)
+	GetLastSyncTime(ctx context.Context) (*time.Time, error)
+}
+
+// WebhookRepository defines the interface for webhook endpoint and delivery data access
+type WebhookRepository interface {
+	ListEndpoints(ctx context.Context) ([]models.WebhookEndpoint, error)
+	GetEndpoint(ctx context.Context, id int64) (*models.WebhookEndpoint, error)
+	CreateEndpoint(ctx context.Context, req *models.CreateWebhookEndpointRequest, createdByID int64) (*models.WebhookEndpoint, error)
+	UpdateEndpoint(ctx context.Context, id int64, req *models.UpdateWebhookEndpointRequest) (*models.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id int64) error
+	EnqueueForEndpoint(ctx context.Context, endpointID int64, eventType string, payload []byte) (*models.WebhookDelivery, error)
+	ClaimNextDelivery(ctx context.Context, staleAfter time.Duration) (*models.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, responseStatus int) error
+	ScheduleRetry(ctx context.Context, id int64, responseStatus *int, message string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id int64, responseStatus *int, message string) error
+	ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]models.WebhookDelivery, error)
+	PurgeDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EmailTemplateRepository defines the interface for organizations' email template overrides
+type EmailTemplateRepository interface {
+	Get(ctx context.Context, key models.EmailTemplateKey, language string) (*models.EmailTemplate, error)
+	List(ctx context.Context) ([]models.EmailTemplate, error)
+	Save(ctx context.Context, t *models.EmailTemplate) error
+	Delete(ctx context.Context, key models.EmailTemplateKey, language string) error
+}
+
+// SupervisorDigestRepository defines the interface for supervisor digest data access
+type SupervisorDigestRepository interface {
+	Queue(ctx context.Context, payload *models.SupervisorDigestEmailPayload) (bool, error)
+}
+
+// OutboxRepository defines the interface for queued side effect data access
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, kind models.OutboxKind, payload any) error
+	ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.OutboxMessage, error)
+	MarkSent(ctx context.Context, id int64) error
+	ScheduleRetry(ctx context.Context, id int64, message string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id int64, message string) error
+	PurgeFinished(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ExportJobRepository defines the interface for background export job data access
+type ExportJobRepository interface {
+	Create(ctx context.Context, requestedByID int64, req *models.CreateExportJobRequest) (*models.ExportJob, error)
+	GetByID(ctx context.Context, id int64) (*models.ExportJob, error)
+	ListByUser(ctx context.Context, userID int64, limit int) ([]models.ExportJob, error)
+	ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.ExportJob, error)
+	Complete(ctx context.Context, id int64, storageKey, fileName string, rowCount int, expiresAt time.Time) error
+	Fail(ctx context.Context, id int64, message string) error
+	ListExpired(ctx context.Context, limit int) ([]models.ExportJob, error)
+	MarkExpired(ctx context.Context, id int64) error
+}
+
+// ReportRepository defines the interface for the team analytics report aggregates
+type ReportRepository interface {
+	TimeOffUsage(ctx context.Context, q *models.ReportQuery) ([]models.TimeOffUsageRow, error)
+	ApprovalLatency(ctx context.Context, q *models.ReportQuery) ([]models.ApprovalLatencyRow, error)
+	MeetingLoad(ctx context.Context, q *models.ReportQuery) ([]models.MeetingLoadRow, error)
+	JiraThroughput(ctx context.Context, q *models.ReportQuery) ([]models.JiraThroughputRow, error)
+	Headcount(ctx context.Context, q *models.ReportQuery) ([]models.HeadcountRow, error)
+	InactiveAccounts(ctx context.Context, q *models.ReportQuery) ([]models.InactiveAccountRow, error)
+}
+
+// AdminStatsRepository defines the interface for the admin home page's aggregates
+type AdminStatsRepository interface {
+	Get(ctx context.Context) (*models.AdminStats, error)
+}
+
+// WorkloadSnapshotRepository defines the interface for daily workload snapshot data access
+type WorkloadSnapshotRepository interface {
+	Snapshot(ctx context.Context, day time.Time) (int64, error)
+	ListForUsers(ctx context.Context, userIDs []int64, since time.Time) ([]models.WorkloadSnapshot, error)
+	PurgeBefore(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,130 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockAccessRequestRepository is a mock implementation of AccessRequestRepository for testing
+type MockAccessRequestRepository struct {
+	Requests map[int64]*models.AccessRequest
+	NextID   int64
+	// Users made by approving requests
+	Users      []*models.User
+	NextUserID int64
+
+	// Function hooks for custom behavior
+	CreateFunc  func(ctx context.Context, req *models.AccessRequest) (*models.AccessRequest, error)
+	ApproveFunc func(ctx context.Context, id int64, approval *models.ApproveAccessRequestRequest, reviewerID int64) (*models.User, error)
+}
+
+// NewMockAccessRequestRepository creates a new mock access request repository
+func NewMockAccessRequestRepository() *MockAccessRequestRepository {
+	return &MockAccessRequestRepository{
+		Requests:   make(map[int64]*models.AccessRequest),
+		NextID:     1,
+		NextUserID: 100,
+	}
+}
+
+// AddRequest adds an access request to the mock repository
+func (m *MockAccessRequestRepository) AddRequest(req *models.AccessRequest) {
+	m.Requests[req.ID] = req
+	if req.ID >= m.NextID {
+		m.NextID = req.ID + 1
+	}
+}
+
+func (m *MockAccessRequestRepository) Create(ctx context.Context, req *models.AccessRequest) (*models.AccessRequest, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, req)
+	}
+	for _, existing := range m.Requests {
+		if existing.Auth0ID == req.Auth0ID && existing.Status == models.AccessRequestStatusPending {
+			return nil, errors.New("duplicate pending access request")
+		}
+	}
+	created := *req
+	created.ID = m.NextID
+	created.Status = models.AccessRequestStatusPending
+	created.CreatedAt = time.Now()
+	created.UpdatedAt = created.CreatedAt
+	m.NextID++
+	m.Requests[created.ID] = &created
+	return &created, nil
+}
+
+func (m *MockAccessRequestRepository) GetByID(ctx context.Context, id int64) (*models.AccessRequest, error) {
+	if req, ok := m.Requests[id]; ok {
+		return req, nil
+	}
+	return nil, nil
+}
+
+func (m *MockAccessRequestRepository) GetLatestByAuth0ID(ctx context.Context, auth0ID string) (*models.AccessRequest, error) {
+	var latest *models.AccessRequest
+	for _, req := range m.Requests {
+		if req.Auth0ID == auth0ID && (latest == nil || req.ID > latest.ID) {
+			latest = req
+		}
+	}
+	return latest, nil
+}
+
+func (m *MockAccessRequestRepository) List(ctx context.Context, status *models.AccessRequestStatus) ([]models.AccessRequest, error) {
+	requests := []models.AccessRequest{}
+	for _, req := range m.Requests {
+		if status == nil || req.Status == *status {
+			requests = append(requests, *req)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].ID < requests[j].ID })
+	return requests, nil
+}
+
+func (m *MockAccessRequestRepository) Approve(ctx context.Context, id int64, approval *models.ApproveAccessRequestRequest, reviewerID int64) (*models.User, error) {
+	if m.ApproveFunc != nil {
+		return m.ApproveFunc(ctx, id, approval, reviewerID)
+	}
+	req, ok := m.Requests[id]
+	if !ok || req.Status != models.AccessRequestStatusPending {
+		return nil, errors.New("pending access request not found")
+	}
+	user := &models.User{
+		ID:           m.NextUserID,
+		Auth0ID:      req.Auth0ID,
+		Email:        req.Email,
+		FirstName:    req.FirstName,
+		LastName:     req.LastName,
+		Role:         approval.Role,
+		Title:        approval.Title,
+		Department:   approval.Department,
+		SupervisorID: approval.SupervisorID,
+		OrgID:        req.OrgID,
+	}
+	m.NextUserID++
+	m.Users = append(m.Users, user)
+
+	now := time.Now()
+	req.Status = models.AccessRequestStatusApproved
+	req.ReviewedByID = &reviewerID
+	req.ReviewedAt = &now
+	req.UserID = &user.ID
+	return user, nil
+}
+
+func (m *MockAccessRequestRepository) Reject(ctx context.Context, id int64, reviewerID int64) (*models.AccessRequest, error) {
+	req, ok := m.Requests[id]
+	if !ok || req.Status != models.AccessRequestStatusPending {
+		return nil, errors.New("pending access request not found")
+	}
+	now := time.Now()
+	req.Status = models.AccessRequestStatusRejected
+	req.ReviewedByID = &reviewerID
+	req.ReviewedAt = &now
+	return req, nil
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockAdminStatsRepository is a mock implementation of AdminStatsRepository for testing. It returns
+// a copy of Stats and counts how often it was asked.
+type MockAdminStatsRepository struct {
+	Stats models.AdminStats
+	Calls int
+	Err   error
+}
+
+// NewMockAdminStatsRepository creates a new mock admin stats repository
+func NewMockAdminStatsRepository() *MockAdminStatsRepository {
+	return &MockAdminStatsRepository{}
+}
+
+func (m *MockAdminStatsRepository) Get(ctx context.Context) (*models.AdminStats, error) {
+	m.Calls++
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	stats := m.Stats
+	return &stats, nil
+}
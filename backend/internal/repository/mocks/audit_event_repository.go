@@ -0,0 +1,75 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockAuditEventRepository is a mock implementation of AuditEventRepository for testing
+type MockAuditEventRepository struct {
+	mu     sync.Mutex
+	Events []models.AuditEvent
+
+	// Function hooks for custom behavior
+	CreateFunc func(ctx context.Context, event *models.AuditEvent) error
+	ListFunc   func(ctx context.Context, filter models.AuditEventFilter, limit, offset int) ([]models.AuditEvent, int, error)
+}
+
+// NewMockAuditEventRepository creates a new mock audit event repository
+func NewMockAuditEventRepository() *MockAuditEventRepository {
+	return &MockAuditEventRepository{Events: []models.AuditEvent{}}
+}
+
+func (m *MockAuditEventRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, event)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	event.ID = int64(len(m.Events) + 1)
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	m.Events = append(m.Events, *event)
+	return nil
+}
+
+func (m *MockAuditEventRepository) List(ctx context.Context, filter models.AuditEventFilter, limit, offset int) ([]models.AuditEvent, int, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, filter, limit, offset)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	matched := []models.AuditEvent{}
+	for i := len(m.Events) - 1; i >= 0; i-- {
+		e := m.Events[i]
+		if filter.ActorID != nil && (e.ActorID == nil || *e.ActorID != *filter.ActorID) {
+			continue
+		}
+		if filter.ImpersonationSessionID != nil &&
+			(e.ImpersonationSessionID == nil || *e.ImpersonationSessionID != *filter.ImpersonationSessionID) {
+			continue
+		}
+		if filter.EntityType != "" && e.EntityType != filter.EntityType {
+			continue
+		}
+		if filter.EntityID != "" && (e.EntityID == nil || *e.EntityID != filter.EntityID) {
+			continue
+		}
+		if filter.From != nil && e.CreatedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !e.CreatedAt.Before(*filter.To) {
+			continue
+		}
+		matched = append(matched, e)
+	}
+	total := len(matched)
+	if offset >= total {
+		return []models.AuditEvent{}, total, nil
+	}
+	return matched[offset:min(offset+limit, total)], total, nil
+}
@@ -0,0 +1,124 @@
+package mocks
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockCustomFieldRepository is a mock implementation of CustomFieldRepository for testing
+type MockCustomFieldRepository struct {
+	Fields map[int64]*models.CustomField
+	Values map[int64]map[int64]string // userID -> fieldID -> value
+	nextID int64
+}
+
+// NewMockCustomFieldRepository creates a new mock custom field repository
+func NewMockCustomFieldRepository() *MockCustomFieldRepository {
+	return &MockCustomFieldRepository{
+		Fields: make(map[int64]*models.CustomField),
+		Values: make(map[int64]map[int64]string),
+		nextID: 1,
+	}
+}
+
+func (m *MockCustomFieldRepository) GetAll(ctx context.Context) ([]models.CustomField, error) {
+	fields := []models.CustomField{}
+	for _, field := range m.Fields {
+		fields = append(fields, *field)
+	}
+	slices.SortFunc(fields, func(a, b models.CustomField) int {
+		return cmp.Or(cmp.Compare(a.Position, b.Position), cmp.Compare(a.ID, b.ID))
+	})
+	return fields, nil
+}
+
+func (m *MockCustomFieldRepository) GetByID(ctx context.Context, id int64) (*models.CustomField, error) {
+	return m.Fields[id], nil
+}
+
+func (m *MockCustomFieldRepository) GetByKey(ctx context.Context, key string) (*models.CustomField, error) {
+	for _, field := range m.Fields {
+		if field.Key == key {
+			return field, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockCustomFieldRepository) Create(ctx context.Context, req *models.CreateCustomFieldRequest) (*models.CustomField, error) {
+	now := time.Now()
+	field := &models.CustomField{
+		ID:        m.nextID,
+		Key:       req.Key,
+		Label:     req.Label,
+		Type:      req.Type,
+		Options:   req.Options,
+		Required:  req.Required,
+		Position:  req.Position,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	m.nextID++
+	m.Fields[field.ID] = field
+	return field, nil
+}
+
+func (m *MockCustomFieldRepository) Update(ctx context.Context, id int64, req *models.UpdateCustomFieldRequest) (*models.CustomField, error) {
+	field, ok := m.Fields[id]
+	if !ok {
+		return nil, nil
+	}
+	field.Label = req.Label
+	field.Options = req.Options
+	field.Required = req.Required
+	field.Position = req.Position
+	field.UpdatedAt = time.Now()
+	return field, nil
+}
+
+func (m *MockCustomFieldRepository) Delete(ctx context.Context, id int64) error {
+	if _, ok := m.Fields[id]; !ok {
+		return fmt.Errorf("custom field not found")
+	}
+	delete(m.Fields, id)
+	for _, values := range m.Values {
+		delete(values, id)
+	}
+	return nil
+}
+
+func (m *MockCustomFieldRepository) GetValuesForUsers(ctx context.Context, userIDs []int64) (map[int64]map[string]string, error) {
+	result := make(map[int64]map[string]string)
+	for _, userID := range userIDs {
+		for fieldID, value := range m.Values[userID] {
+			field, ok := m.Fields[fieldID]
+			if !ok {
+				continue
+			}
+			if result[userID] == nil {
+				result[userID] = make(map[string]string)
+			}
+			result[userID][field.Key] = value
+		}
+	}
+	return result, nil
+}
+
+func (m *MockCustomFieldRepository) SetUserValues(ctx context.Context, userID int64, values map[int64]string) error {
+	if m.Values[userID] == nil {
+		m.Values[userID] = make(map[int64]string)
+	}
+	for fieldID, value := range values {
+		if value == "" {
+			delete(m.Values[userID], fieldID)
+		} else {
+			m.Values[userID][fieldID] = value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package mocks
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockCustomRoleRepository is a mock implementation of CustomRoleRepository for testing
+type MockCustomRoleRepository struct {
+	Roles       map[int64]*models.CustomRole
+	Assignments map[int64][]int64 // userID -> role IDs
+	nextID      int64
+
+	// Function hooks for custom behavior
+	GetForUserFunc func(ctx context.Context, userID int64) ([]models.CustomRole, error)
+}
+
+// NewMockCustomRoleRepository creates a new mock custom role repository
+func NewMockCustomRoleRepository() *MockCustomRoleRepository {
+	return &MockCustomRoleRepository{
+		Roles:       make(map[int64]*models.CustomRole),
+		Assignments: make(map[int64][]int64),
+		nextID:      1,
+	}
+}
+
+// withUserCount copies a role with the number of users assigned to it
+func (m *MockCustomRoleRepository) withUserCount(role *models.CustomRole) models.CustomRole {
+	counted := *role
+	counted.UserCount = 0
+	for _, roleIDs := range m.Assignments {
+		if slices.Contains(roleIDs, role.ID) {
+			counted.UserCount++
+		}
+	}
+	return counted
+}
+
+func sortCustomRoles(roles []models.CustomRole) {
+	slices.SortFunc(roles, func(a, b models.CustomRole) int {
+		return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+}
+
+func (m *MockCustomRoleRepository) GetAll(ctx context.Context) ([]models.CustomRole, error) {
+	roles := []models.CustomRole{}
+	for _, role := range m.Roles {
+		roles = append(roles, m.withUserCount(role))
+	}
+	sortCustomRoles(roles)
+	return roles, nil
+}
+
+func (m *MockCustomRoleRepository) GetByID(ctx context.Context, id int64) (*models.CustomRole, error) {
+	role, ok := m.Roles[id]
+	if !ok {
+		return nil, nil
+	}
+	counted := m.withUserCount(role)
+	return &counted, nil
+}
+
+func (m *MockCustomRoleRepository) GetByName(ctx context.Context, name string) (*models.CustomRole, error) {
+	for _, role := range m.Roles {
+		if strings.EqualFold(role.Name, name) {
+			counted := m.withUserCount(role)
+			return &counted, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockCustomRoleRepository) Create(ctx context.Context, req *models.CustomRoleRequest, createdByID int64) (*models.CustomRole, error) {
+	now := time.Now()
+	role := &models.CustomRole{
+		ID:          m.nextID,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+		CreatedByID: &createdByID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	m.nextID++
+	m.Roles[role.ID] = role
+	return m.GetByID(ctx, role.ID)
+}
+
+func (m *MockCustomRoleRepository) Update(ctx context.Context, id int64, req *models.CustomRoleRequest) (*models.CustomRole, error) {
+	role, ok := m.Roles[id]
+	if !ok {
+		return nil, nil
+	}
+	role.Name = req.Name
+	role.Description = req.Description
+	role.Permissions = req.Permissions
+	role.UpdatedAt = time.Now()
+	return m.GetByID(ctx, id)
+}
+
+func (m *MockCustomRoleRepository) Delete(ctx context.Context, id int64) error {
+	delete(m.Roles, id)
+	for userID, roleIDs := range m.Assignments {
+		m.Assignments[userID] = slices.DeleteFunc(roleIDs, func(roleID int64) bool { return roleID == id })
+	}
+	return nil
+}
+
+func (m *MockCustomRoleRepository) GetForUser(ctx context.Context, userID int64) ([]models.CustomRole, error) {
+	if m.GetForUserFunc != nil {
+		return m.GetForUserFunc(ctx, userID)
+	}
+	roles := []models.CustomRole{}
+	for _, id := range m.Assignments[userID] {
+		if role, ok := m.Roles[id]; ok {
+			roles = append(roles, m.withUserCount(role))
+		}
+	}
+	sortCustomRoles(roles)
+	return roles, nil
+}
+
+func (m *MockCustomRoleRepository) SetForUser(ctx context.Context, userID int64, roleIDs []int64, assignedByID int64) error {
+	m.Assignments[userID] = slices.Clone(roleIDs)
+	return nil
+}
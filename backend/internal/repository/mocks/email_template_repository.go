@@ -0,0 +1,93 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// MockEmailTemplateRepository is a mock implementation of EmailTemplateRepository for testing
+type MockEmailTemplateRepository struct {
+	Templates map[string]*models.EmailTemplate // By organization, key, and language
+
+	// Function hooks for custom behavior
+	GetFunc func(ctx context.Context, key models.EmailTemplateKey, language string) (*models.EmailTemplate, error)
+}
+
+// NewMockEmailTemplateRepository creates a new mock email template repository with no overrides
+func NewMockEmailTemplateRepository() *MockEmailTemplateRepository {
+	return &MockEmailTemplateRepository{
+		Templates: make(map[string]*models.EmailTemplate),
+	}
+}
+
+// emailTemplateKey identifies an organization's override
+func emailTemplateKey(ctx context.Context, key models.EmailTemplateKey, language string) (string, error) {
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d/%s/%s", orgID, key, language), nil
+}
+
+func (m *MockEmailTemplateRepository) Get(ctx context.Context, key models.EmailTemplateKey, language string) (*models.EmailTemplate, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, key, language)
+	}
+	k, err := emailTemplateKey(ctx, key, language)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := m.Templates[k]; ok {
+		copied := *t
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockEmailTemplateRepository) List(ctx context.Context) ([]models.EmailTemplate, error) {
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("%d/", orgID)
+	templates := []models.EmailTemplate{}
+	for k, t := range m.Templates {
+		if len(k) > len(prefix) && k[:len(prefix)] == prefix {
+			templates = append(templates, *t)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Key != templates[j].Key {
+			return templates[i].Key < templates[j].Key
+		}
+		return templates[i].Language < templates[j].Language
+	})
+	return templates, nil
+}
+
+func (m *MockEmailTemplateRepository) Save(ctx context.Context, t *models.EmailTemplate) error {
+	k, err := emailTemplateKey(ctx, t.Key, t.Language)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	t.Customized = true
+	t.UpdatedAt = &now
+	copied := *t
+	m.Templates[k] = &copied
+	return nil
+}
+
+func (m *MockEmailTemplateRepository) Delete(ctx context.Context, key models.EmailTemplateKey, language string) error {
+	k, err := emailTemplateKey(ctx, key, language)
+	if err != nil {
+		return err
+	}
+	delete(m.Templates, k)
+	return nil
+}
@@ -0,0 +1,175 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockExportJobRepository is a mock implementation of ExportJobRepository for testing
+type MockExportJobRepository struct {
+	Jobs   map[int64]*models.ExportJob
+	NextID int64
+
+	// Function hooks for custom behavior
+	CreateFunc      func(ctx context.Context, requestedByID int64, req *models.CreateExportJobRequest) (*models.ExportJob, error)
+	GetByIDFunc     func(ctx context.Context, id int64) (*models.ExportJob, error)
+	ListByUserFunc  func(ctx context.Context, userID int64, limit int) ([]models.ExportJob, error)
+	ClaimNextFunc   func(ctx context.Context, staleAfter time.Duration) (*models.ExportJob, error)
+	CompleteFunc    func(ctx context.Context, id int64, storageKey, fileName string, rowCount int, expiresAt time.Time) error
+	FailFunc        func(ctx context.Context, id int64, message string) error
+	ListExpiredFunc func(ctx context.Context, limit int) ([]models.ExportJob, error)
+	MarkExpiredFunc func(ctx context.Context, id int64) error
+}
+
+// NewMockExportJobRepository creates a new mock export job repository
+func NewMockExportJobRepository() *MockExportJobRepository {
+	return &MockExportJobRepository{
+		Jobs:   make(map[int64]*models.ExportJob),
+		NextID: 1,
+	}
+}
+
+func (m *MockExportJobRepository) Create(ctx context.Context, requestedByID int64, req *models.CreateExportJobRequest) (*models.ExportJob, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, requestedByID, req)
+	}
+	job := &models.ExportJob{
+		ID:            m.NextID,
+		RequestedByID: requestedByID,
+		Type:          req.Type,
+		Format:        req.Format,
+		Filters:       req.Filters,
+		Status:        models.ExportJobStatusPending,
+		CreatedAt:     time.Now(),
+	}
+	m.NextID++
+	m.Jobs[job.ID] = job
+	copied := *job
+	return &copied, nil
+}
+
+func (m *MockExportJobRepository) GetByID(ctx context.Context, id int64) (*models.ExportJob, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	if job, ok := m.Jobs[id]; ok {
+		copied := *job
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockExportJobRepository) ListByUser(ctx context.Context, userID int64, limit int) ([]models.ExportJob, error) {
+	if m.ListByUserFunc != nil {
+		return m.ListByUserFunc(ctx, userID, limit)
+	}
+	jobs := []models.ExportJob{}
+	for _, job := range m.sortedJobs() {
+		if job.RequestedByID == userID {
+			jobs = append(jobs, *job)
+		}
+	}
+	// Newest first
+	for i, j := 0, len(jobs)-1; i < j; i, j = i+1, j-1 {
+		jobs[i], jobs[j] = jobs[j], jobs[i]
+	}
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+func (m *MockExportJobRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.ExportJob, error) {
+	if m.ClaimNextFunc != nil {
+		return m.ClaimNextFunc(ctx, staleAfter)
+	}
+	for _, job := range m.sortedJobs() {
+		stale := job.Status == models.ExportJobStatusRunning && job.StartedAt != nil && time.Since(*job.StartedAt) > staleAfter
+		if job.Status == models.ExportJobStatusPending || stale {
+			now := time.Now()
+			job.Status = models.ExportJobStatusRunning
+			job.StartedAt = &now
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockExportJobRepository) Complete(ctx context.Context, id int64, storageKey, fileName string, rowCount int, expiresAt time.Time) error {
+	if m.CompleteFunc != nil {
+		return m.CompleteFunc(ctx, id, storageKey, fileName, rowCount, expiresAt)
+	}
+	if job, ok := m.Jobs[id]; ok {
+		now := time.Now()
+		job.Status = models.ExportJobStatusCompleted
+		job.StorageKey = &storageKey
+		job.FileName = &fileName
+		job.RowCount = &rowCount
+		job.ExpiresAt = &expiresAt
+		job.Error = nil
+		job.CompletedAt = &now
+	}
+	return nil
+}
+
+func (m *MockExportJobRepository) Fail(ctx context.Context, id int64, message string) error {
+	if m.FailFunc != nil {
+		return m.FailFunc(ctx, id, message)
+	}
+	if job, ok := m.Jobs[id]; ok {
+		now := time.Now()
+		job.Status = models.ExportJobStatusFailed
+		job.Error = &message
+		job.CompletedAt = &now
+	}
+	return nil
+}
+
+func (m *MockExportJobRepository) ListExpired(ctx context.Context, limit int) ([]models.ExportJob, error) {
+	if m.ListExpiredFunc != nil {
+		return m.ListExpiredFunc(ctx, limit)
+	}
+	jobs := []models.ExportJob{}
+	for _, job := range m.sortedJobs() {
+		if job.Status == models.ExportJobStatusCompleted && job.ExpiresAt != nil && job.ExpiresAt.Before(time.Now()) {
+			jobs = append(jobs, *job)
+		}
+		if len(jobs) == limit {
+			break
+		}
+	}
+	return jobs, nil
+}
+
+func (m *MockExportJobRepository) MarkExpired(ctx context.Context, id int64) error {
+	if m.MarkExpiredFunc != nil {
+		return m.MarkExpiredFunc(ctx, id)
+	}
+	if job, ok := m.Jobs[id]; ok {
+		job.Status = models.ExportJobStatusExpired
+		job.StorageKey = nil
+	}
+	return nil
+}
+
+// AddJob is a helper method for setting up test data
+func (m *MockExportJobRepository) AddJob(job *models.ExportJob) {
+	m.Jobs[job.ID] = job
+	if job.ID >= m.NextID {
+		m.NextID = job.ID + 1
+	}
+}
+
+// sortedJobs returns the stored jobs oldest first
+func (m *MockExportJobRepository) sortedJobs() []*models.ExportJob {
+	jobs := make([]*models.ExportJob, 0, len(m.Jobs))
+	for _, job := range m.Jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs
+}
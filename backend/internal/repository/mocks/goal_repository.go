@@ -0,0 +1,202 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockGoalRepository is a mock implementation of GoalRepository for testing
+type MockGoalRepository struct {
+	Goals           map[int64]*models.Goal
+	Updates         []models.GoalProgressUpdate
+	NextID          int64
+	NextKeyResultID int64
+
+	// Function hooks for custom behavior
+	ListFunc               func(ctx context.Context, filter models.GoalFilter) ([]models.Goal, error)
+	GetByIDFunc            func(ctx context.Context, id int64) (*models.Goal, error)
+	CreateFunc             func(ctx context.Context, req *models.GoalRequest, createdByID int64) (*models.Goal, error)
+	UpdateFunc             func(ctx context.Context, id int64, req *models.GoalRequest) (*models.Goal, error)
+	DeleteFunc             func(ctx context.Context, id int64) error
+	GetKeyResultFunc       func(ctx context.Context, id int64) (*models.KeyResult, error)
+	RecordProgressFunc     func(ctx context.Context, keyResultID, authorID int64, req *models.ProgressUpdateRequest) (*models.GoalProgressUpdate, error)
+	GetProgressUpdatesFunc func(ctx context.Context, goalID int64) ([]models.GoalProgressUpdate, error)
+}
+
+// NewMockGoalRepository creates a new mock goal repository
+func NewMockGoalRepository() *MockGoalRepository {
+	return &MockGoalRepository{
+		Goals:           make(map[int64]*models.Goal),
+		NextID:          1,
+		NextKeyResultID: 1,
+	}
+}
+
+func (m *MockGoalRepository) List(ctx context.Context, filter models.GoalFilter) ([]models.Goal, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, filter)
+	}
+	anyOwner := len(filter.UserIDs) == 0 && len(filter.SquadIDs) == 0 && len(filter.Departments) == 0
+	goals := []models.Goal{}
+	for _, g := range m.Goals {
+		if filter.Quarter != "" && g.Quarter != filter.Quarter {
+			continue
+		}
+		matches := anyOwner
+		switch g.OwnerType {
+		case models.GoalOwnerUser:
+			matches = matches || slices.Contains(filter.UserIDs, *g.OwnerID)
+		case models.GoalOwnerSquad:
+			matches = matches || slices.Contains(filter.SquadIDs, *g.OwnerID)
+		case models.GoalOwnerDepartment:
+			matches = matches || slices.Contains(filter.Departments, *g.OwnerDepartment)
+		}
+		if matches {
+			goal := *g
+			goal.ComputeProgress()
+			goals = append(goals, goal)
+		}
+	}
+	sort.Slice(goals, func(i, j int) bool { return goals[i].ID < goals[j].ID })
+	return goals, nil
+}
+
+func (m *MockGoalRepository) GetByID(ctx context.Context, id int64) (*models.Goal, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	if g, ok := m.Goals[id]; ok {
+		g.ComputeProgress()
+		return g, nil
+	}
+	return nil, nil
+}
+
+func (m *MockGoalRepository) Create(ctx context.Context, req *models.GoalRequest, createdByID int64) (*models.Goal, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, req, createdByID)
+	}
+	now := time.Now()
+	goal := &models.Goal{ID: m.NextID, CreatedByID: &createdByID, CreatedAt: now}
+	m.NextID++
+	m.Goals[goal.ID] = goal
+	return m.apply(goal, req), nil
+}
+
+func (m *MockGoalRepository) Update(ctx context.Context, id int64, req *models.GoalRequest) (*models.Goal, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, id, req)
+	}
+	goal, ok := m.Goals[id]
+	if !ok {
+		return nil, errors.New("goal not found")
+	}
+	return m.apply(goal, req), nil
+}
+
+// apply copies a request onto a goal, keeping the current values of existing key results
+func (m *MockGoalRepository) apply(goal *models.Goal, req *models.GoalRequest) *models.Goal {
+	goal.Title = req.Title
+	goal.Description = req.Description
+	goal.OwnerType = req.OwnerType
+	goal.OwnerID = req.OwnerID
+	goal.OwnerDepartment = req.OwnerDepartment
+	goal.Quarter = req.Quarter
+	goal.UpdatedAt = time.Now()
+
+	current := map[int64]float64{}
+	for _, kr := range goal.KeyResults {
+		current[kr.ID] = kr.CurrentValue
+	}
+	goal.KeyResults = []models.KeyResult{}
+	for _, in := range req.KeyResults {
+		kr := models.KeyResult{
+			GoalID:       goal.ID,
+			Title:        in.Title,
+			StartValue:   in.StartValue,
+			TargetValue:  in.TargetValue,
+			CurrentValue: in.StartValue,
+			Unit:         in.Unit,
+		}
+		if in.ID != nil {
+			kr.ID = *in.ID
+			kr.CurrentValue = current[kr.ID]
+		} else {
+			kr.ID = m.NextKeyResultID
+			m.NextKeyResultID++
+		}
+		goal.KeyResults = append(goal.KeyResults, kr)
+	}
+	goal.ComputeProgress()
+	return goal
+}
+
+func (m *MockGoalRepository) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	delete(m.Goals, id)
+	return nil
+}
+
+func (m *MockGoalRepository) GetKeyResult(ctx context.Context, id int64) (*models.KeyResult, error) {
+	if m.GetKeyResultFunc != nil {
+		return m.GetKeyResultFunc(ctx, id)
+	}
+	for _, g := range m.Goals {
+		for i := range g.KeyResults {
+			if g.KeyResults[i].ID == id {
+				kr := g.KeyResults[i]
+				return &kr, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockGoalRepository) RecordProgress(ctx context.Context, keyResultID, authorID int64, req *models.ProgressUpdateRequest) (*models.GoalProgressUpdate, error) {
+	if m.RecordProgressFunc != nil {
+		return m.RecordProgressFunc(ctx, keyResultID, authorID, req)
+	}
+	for _, g := range m.Goals {
+		for i := range g.KeyResults {
+			if g.KeyResults[i].ID == keyResultID {
+				g.KeyResults[i].CurrentValue = req.Value
+			}
+		}
+	}
+	update := models.GoalProgressUpdate{
+		ID:          int64(len(m.Updates) + 1),
+		KeyResultID: keyResultID,
+		Value:       req.Value,
+		Note:        req.Note,
+		AuthorID:    &authorID,
+		CreatedAt:   time.Now(),
+	}
+	m.Updates = append(m.Updates, update)
+	return &update, nil
+}
+
+func (m *MockGoalRepository) GetProgressUpdates(ctx context.Context, goalID int64) ([]models.GoalProgressUpdate, error) {
+	if m.GetProgressUpdatesFunc != nil {
+		return m.GetProgressUpdatesFunc(ctx, goalID)
+	}
+	goal, ok := m.Goals[goalID]
+	if !ok {
+		return []models.GoalProgressUpdate{}, nil
+	}
+	updates := []models.GoalProgressUpdate{}
+	for i := len(m.Updates) - 1; i >= 0; i-- {
+		for _, kr := range goal.KeyResults {
+			if m.Updates[i].KeyResultID == kr.ID {
+				updates = append(updates, m.Updates[i])
+			}
+		}
+	}
+	return updates, nil
+}
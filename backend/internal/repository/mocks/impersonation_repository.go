@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockImpersonationRepository is a mock implementation of ImpersonationRepository for testing
+type MockImpersonationRepository struct {
+	Sessions map[int64]*models.ImpersonationSession
+	nextID   int64
+}
+
+// NewMockImpersonationRepository creates a new mock impersonation repository
+func NewMockImpersonationRepository() *MockImpersonationRepository {
+	return &MockImpersonationRepository{
+		Sessions: make(map[int64]*models.ImpersonationSession),
+		nextID:   1,
+	}
+}
+
+// Start ends the admin's active sessions and starts a new one
+func (m *MockImpersonationRepository) Start(ctx context.Context, session *models.ImpersonationSession) error {
+	now := time.Now()
+	for _, existing := range m.Sessions {
+		if existing.AdminID == session.AdminID && existing.IsActive() {
+			existing.EndedAt = &now
+		}
+	}
+	session.ID = m.nextID
+	session.StartedAt = now
+	m.nextID++
+	stored := *session
+	m.Sessions[session.ID] = &stored
+	return nil
+}
+
+// GetByID retrieves an impersonation session, or nil if it doesn't exist
+func (m *MockImpersonationRepository) GetByID(ctx context.Context, id int64) (*models.ImpersonationSession, error) {
+	session, ok := m.Sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *session
+	return &copied, nil
+}
+
+// End ends the admin's active sessions impersonating the user
+func (m *MockImpersonationRepository) End(ctx context.Context, adminID, userID int64) (int64, error) {
+	var ended int64
+	now := time.Now()
+	for _, session := range m.Sessions {
+		if session.AdminID == adminID && session.UserID == userID && session.IsActive() {
+			session.EndedAt = &now
+			ended++
+		}
+	}
+	return ended, nil
+}
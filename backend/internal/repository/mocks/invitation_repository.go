@@ -14,9 +14,10 @@ type MockInvitationRepository struct {
 	ByToken       map[string]*models.Invitation
 	ByEmail       map[string]*models.Invitation
 	NextID        int64
+	QueuedEmails  []models.InvitationEmailPayload
 
 	// Function hooks for custom behavior
-	CreateFunc        func(ctx context.Context, req *models.CreateInvitationRequest, invitedByID int64) (*models.Invitation, error)
+	CreateFunc        func(ctx context.Context, req *models.CreateInvitationRequest, invitedByID int64, email *models.InvitationEmail) (*models.Invitation, error)
 	GetByIDFunc       func(ctx context.Context, id int64) (*models.Invitation, error)
 	GetByTokenFunc    func(ctx context.Context, token string) (*models.Invitation, error)
 	GetByEmailFunc    func(ctx context.Context, email string) (*models.Invitation, error)
@@ -36,9 +37,9 @@ func NewMockInvitationRepository() *MockInvitationRepository {
 	}
 }
 
-func (m *MockInvitationRepository) Create(ctx context.Context, req *models.CreateInvitationRequest, invitedByID int64) (*models.Invitation, error) {
+func (m *MockInvitationRepository) Create(ctx context.Context, req *models.CreateInvitationRequest, invitedByID int64, email *models.InvitationEmail) (*models.Invitation, error) {
 	if m.CreateFunc != nil {
-		return m.CreateFunc(ctx, req, invitedByID)
+		return m.CreateFunc(ctx, req, invitedByID, email)
 	}
 	invitation := &models.Invitation{
 		ID:          m.NextID,
@@ -49,11 +50,33 @@ func (m *MockInvitationRepository) Create(ctx context.Context, req *models.Creat
 		InvitedByID: invitedByID,
 		ExpiresAt:   time.Now().Add(7 * 24 * time.Hour),
 		CreatedAt:   time.Now(),
+
+		AccessExpiresAt: req.AccessExpiresAt,
+		MeetingIDs:      req.MeetingIDs,
+		Language:        req.Language,
+		SquadIDs:        req.SquadIDs,
+		SupervisorID:    req.SupervisorID,
+		Title:           req.Title,
+		DateStarted:     req.DateStarted,
+	}
+	if invitation.Language == "" {
+		invitation.Language = models.DefaultLanguage
 	}
 	m.NextID++
 	m.Invitations[invitation.ID] = invitation
 	m.ByToken[invitation.Token] = invitation
 	m.ByEmail[invitation.Email] = invitation
+	if email != nil {
+		m.QueuedEmails = append(m.QueuedEmails, models.InvitationEmailPayload{
+			InvitationID: invitation.ID,
+			Email:        invitation.Email,
+			Token:        invitation.Token,
+			Role:         invitation.Role,
+			InviterName:  email.InviterName,
+			OrgID:        invitation.OrgID,
+			Language:     invitation.Language,
+		})
+	}
 	return invitation, nil
 }
 
@@ -117,6 +140,10 @@ func (m *MockInvitationRepository) Accept(ctx context.Context, token string, aut
 		FirstName: firstName,
 		LastName:  lastName,
 		Role:      inv.Role,
+
+		SupervisorID: inv.SupervisorID,
+		Title:        inv.Title,
+		DateStarted:  inv.DateStarted,
 	}, nil
 }
 
@@ -0,0 +1,117 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// CachedJiraIssue is an issue stored in MockJiraIssueCacheRepository
+type CachedJiraIssue struct {
+	Issue    models.JiraIssue
+	Resolved bool
+}
+
+// MockJiraIssueCacheRepository is a mock implementation of JiraIssueCacheRepository for testing.
+// It's safe for concurrent use since accounts are synced in parallel.
+type MockJiraIssueCacheRepository struct {
+	mu        sync.Mutex
+	Issues    map[string]CachedJiraIssue
+	SyncTimes map[string]time.Time
+}
+
+// NewMockJiraIssueCacheRepository creates a new mock Jira issue cache repository
+func NewMockJiraIssueCacheRepository() *MockJiraIssueCacheRepository {
+	return &MockJiraIssueCacheRepository{
+		Issues:    make(map[string]CachedJiraIssue),
+		SyncTimes: make(map[string]time.Time),
+	}
+}
+
+func (m *MockJiraIssueCacheRepository) Upsert(ctx context.Context, issue *models.JiraIssue, resolved bool) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.upsert(issue, resolved), nil
+}
+
+func (m *MockJiraIssueCacheRepository) upsert(issue *models.JiraIssue, resolved bool) bool {
+	if cached, ok := m.Issues[issue.Key]; ok && cached.Issue.Updated.After(issue.Updated) {
+		return false
+	}
+	m.Issues[issue.Key] = CachedJiraIssue{Issue: *issue, Resolved: resolved}
+	return true
+}
+
+func (m *MockJiraIssueCacheRepository) Delete(ctx context.Context, issueKey string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.Issues[issueKey]
+	delete(m.Issues, issueKey)
+	return ok, nil
+}
+
+func (m *MockJiraIssueCacheRepository) ListOpen(ctx context.Context, accountIDs []string) ([]models.JiraIssue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wanted := make(map[string]bool, len(accountIDs))
+	for _, id := range accountIDs {
+		wanted[id] = true
+	}
+	issues := []models.JiraIssue{}
+	for _, cached := range m.Issues {
+		if !cached.Resolved && cached.Issue.Assignee != nil && wanted[cached.Issue.Assignee.AccountID] {
+			issues = append(issues, cached.Issue)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		if !issues[i].Updated.Equal(issues[j].Updated) {
+			return issues[i].Updated.After(issues[j].Updated)
+		}
+		return issues[i].Key < issues[j].Key
+	})
+	return issues, nil
+}
+
+func (m *MockJiraIssueCacheRepository) ReplaceOpenForAssignee(ctx context.Context, accountID string, issues []models.JiraIssue, syncedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fetched := make(map[string]bool, len(issues))
+	for i := range issues {
+		fetched[issues[i].Key] = true
+		m.upsert(&issues[i], false)
+	}
+	for key, cached := range m.Issues {
+		if !cached.Resolved && !fetched[key] && cached.Issue.Assignee != nil && cached.Issue.Assignee.AccountID == accountID {
+			delete(m.Issues, key)
+		}
+	}
+	m.SyncTimes[accountID] = syncedAt
+	return nil
+}
+
+func (m *MockJiraIssueCacheRepository) GetSyncTimes(ctx context.Context, accountIDs []string) (map[string]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	syncTimes := make(map[string]time.Time)
+	for _, id := range accountIDs {
+		if t, ok := m.SyncTimes[id]; ok {
+			syncTimes[id] = t
+		}
+	}
+	return syncTimes, nil
+}
+
+func (m *MockJiraIssueCacheRepository) GetLastSyncTime(ctx context.Context) (*time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var last *time.Time
+	for _, t := range m.SyncTimes {
+		if last == nil || t.After(*last) {
+			last = &t
+		}
+	}
+	return last, nil
+}
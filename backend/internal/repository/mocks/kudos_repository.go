@@ -0,0 +1,92 @@
+package mocks
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockKudosRepository is a mock implementation of KudosRepository for testing
+type MockKudosRepository struct {
+	Kudos []models.Kudos
+	// Departments maps recipient IDs to their department for CountByDepartment
+	Departments map[int64]string
+
+	// Function hooks for custom behavior
+	CreateFunc            func(ctx context.Context, fromUserID int64, req *models.KudosRequest) (*models.Kudos, error)
+	ListFeedFunc          func(ctx context.Context, recipientIDs []int64, limit, offset int) ([]models.Kudos, int, error)
+	CountByDepartmentFunc func(ctx context.Context, since time.Time) ([]models.KudosDepartmentCount, error)
+}
+
+// NewMockKudosRepository creates a new mock kudos repository
+func NewMockKudosRepository() *MockKudosRepository {
+	return &MockKudosRepository{
+		Kudos:       []models.Kudos{},
+		Departments: make(map[int64]string),
+	}
+}
+
+func (m *MockKudosRepository) Create(ctx context.Context, fromUserID int64, req *models.KudosRequest) (*models.Kudos, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, fromUserID, req)
+	}
+	kudos := models.Kudos{
+		ID:           int64(len(m.Kudos) + 1),
+		FromUserID:   fromUserID,
+		ToUserID:     req.ToUserID,
+		Message:      req.Message,
+		CompanyValue: req.CompanyValue,
+		CreatedAt:    time.Now(),
+	}
+	m.Kudos = append(m.Kudos, kudos)
+	return &kudos, nil
+}
+
+func (m *MockKudosRepository) ListFeed(ctx context.Context, recipientIDs []int64, limit, offset int) ([]models.Kudos, int, error) {
+	if m.ListFeedFunc != nil {
+		return m.ListFeedFunc(ctx, recipientIDs, limit, offset)
+	}
+	feed := []models.Kudos{}
+	for i := len(m.Kudos) - 1; i >= 0; i-- {
+		if recipientIDs == nil || slices.Contains(recipientIDs, m.Kudos[i].ToUserID) {
+			feed = append(feed, m.Kudos[i])
+		}
+	}
+	total := len(feed)
+	if offset >= total {
+		return []models.Kudos{}, total, nil
+	}
+	return feed[offset:min(offset+limit, total)], total, nil
+}
+
+func (m *MockKudosRepository) CountByDepartment(ctx context.Context, since time.Time) ([]models.KudosDepartmentCount, error) {
+	if m.CountByDepartmentFunc != nil {
+		return m.CountByDepartmentFunc(ctx, since)
+	}
+	type key struct{ month, department string }
+	counts := map[key]int{}
+	for _, k := range m.Kudos {
+		if k.CreatedAt.Before(since) {
+			continue
+		}
+		department := m.Departments[k.ToUserID]
+		if department == "" {
+			department = "Unassigned"
+		}
+		counts[key{k.CreatedAt.UTC().Format("2006-01"), department}]++
+	}
+	result := []models.KudosDepartmentCount{}
+	for k, count := range counts {
+		result = append(result, models.KudosDepartmentCount{Month: k.month, Department: k.department, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Month != result[j].Month {
+			return result[i].Month > result[j].Month
+		}
+		return result[i].Department < result[j].Department
+	})
+	return result, nil
+}
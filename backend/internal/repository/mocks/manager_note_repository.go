@@ -0,0 +1,115 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockManagerNoteRepository is a mock implementation of ManagerNoteRepository for testing
+type MockManagerNoteRepository struct {
+	Notes  map[int64]*models.ManagerNote
+	NextID int64
+
+	// Function hooks for custom behavior
+	CreateFunc        func(ctx context.Context, authorID, subjectID int64, req *models.ManagerNoteRequest) (*models.ManagerNote, error)
+	GetByIDFunc       func(ctx context.Context, id int64) (*models.ManagerNote, error)
+	ListByAuthorFunc  func(ctx context.Context, authorID, subjectID int64, limit, offset int) ([]models.ManagerNote, int, error)
+	ListBySubjectFunc func(ctx context.Context, subjectID int64, limit, offset int) ([]models.ManagerNote, int, error)
+	UpdateFunc        func(ctx context.Context, id int64, req *models.ManagerNoteRequest) (*models.ManagerNote, error)
+	DeleteFunc        func(ctx context.Context, id int64) error
+}
+
+// NewMockManagerNoteRepository creates a new mock manager note repository
+func NewMockManagerNoteRepository() *MockManagerNoteRepository {
+	return &MockManagerNoteRepository{
+		Notes:  make(map[int64]*models.ManagerNote),
+		NextID: 1,
+	}
+}
+
+func (m *MockManagerNoteRepository) Create(ctx context.Context, authorID, subjectID int64, req *models.ManagerNoteRequest) (*models.ManagerNote, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, authorID, subjectID, req)
+	}
+	now := time.Now()
+	note := &models.ManagerNote{
+		ID:        m.NextID,
+		AuthorID:  authorID,
+		SubjectID: subjectID,
+		MeetingID: req.MeetingID,
+		Body:      req.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	m.NextID++
+	m.Notes[note.ID] = note
+	return note, nil
+}
+
+func (m *MockManagerNoteRepository) GetByID(ctx context.Context, id int64) (*models.ManagerNote, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	if note, ok := m.Notes[id]; ok {
+		return note, nil
+	}
+	return nil, nil
+}
+
+func (m *MockManagerNoteRepository) ListByAuthor(ctx context.Context, authorID, subjectID int64, limit, offset int) ([]models.ManagerNote, int, error) {
+	if m.ListByAuthorFunc != nil {
+		return m.ListByAuthorFunc(ctx, authorID, subjectID, limit, offset)
+	}
+	return m.list(func(n *models.ManagerNote) bool {
+		return n.AuthorID == authorID && n.SubjectID == subjectID
+	}, limit, offset)
+}
+
+func (m *MockManagerNoteRepository) ListBySubject(ctx context.Context, subjectID int64, limit, offset int) ([]models.ManagerNote, int, error) {
+	if m.ListBySubjectFunc != nil {
+		return m.ListBySubjectFunc(ctx, subjectID, limit, offset)
+	}
+	return m.list(func(n *models.ManagerNote) bool { return n.SubjectID == subjectID }, limit, offset)
+}
+
+func (m *MockManagerNoteRepository) list(match func(*models.ManagerNote) bool, limit, offset int) ([]models.ManagerNote, int, error) {
+	notes := []models.ManagerNote{}
+	for _, n := range m.Notes {
+		if match(n) {
+			notes = append(notes, *n)
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].ID > notes[j].ID })
+	total := len(notes)
+	if offset >= total {
+		return []models.ManagerNote{}, total, nil
+	}
+	end := min(offset+limit, total)
+	return notes[offset:end], total, nil
+}
+
+func (m *MockManagerNoteRepository) Update(ctx context.Context, id int64, req *models.ManagerNoteRequest) (*models.ManagerNote, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, id, req)
+	}
+	note, ok := m.Notes[id]
+	if !ok {
+		return nil, errors.New("manager note not found")
+	}
+	note.Body = req.Body
+	note.MeetingID = req.MeetingID
+	note.UpdatedAt = time.Now()
+	return note, nil
+}
+
+func (m *MockManagerNoteRepository) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	delete(m.Notes, id)
+	return nil
+}
@@ -0,0 +1,118 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockMeetingAttachmentRepository is a mock implementation of MeetingAttachmentRepository for testing
+type MockMeetingAttachmentRepository struct {
+	Attachments map[int64]*models.MeetingAttachment
+	NextID      int64
+	// VisibleMeetingIDs maps a user ID to the meetings whose transcripts they may search
+	VisibleMeetingIDs map[int64][]int64
+
+	// Function hooks for custom behavior
+	CreateFunc            func(ctx context.Context, attachment *models.MeetingAttachment) (*models.MeetingAttachment, error)
+	GetByIDFunc           func(ctx context.Context, id int64) (*models.MeetingAttachment, error)
+	ListByMeetingFunc     func(ctx context.Context, meetingID int64, occurrenceStart *time.Time) ([]models.MeetingAttachment, error)
+	DeleteFunc            func(ctx context.Context, id int64) error
+	SearchTranscriptsFunc func(ctx context.Context, userID int64, query string, limit int) ([]models.TranscriptSearchResult, error)
+}
+
+// NewMockMeetingAttachmentRepository creates a new mock meeting attachment repository
+func NewMockMeetingAttachmentRepository() *MockMeetingAttachmentRepository {
+	return &MockMeetingAttachmentRepository{
+		Attachments:       make(map[int64]*models.MeetingAttachment),
+		NextID:            1,
+		VisibleMeetingIDs: make(map[int64][]int64),
+	}
+}
+
+func (m *MockMeetingAttachmentRepository) Create(ctx context.Context, attachment *models.MeetingAttachment) (*models.MeetingAttachment, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, attachment)
+	}
+	created := *attachment
+	created.ID = m.NextID
+	created.CreatedAt = time.Now()
+	m.NextID++
+	stored := created
+	m.Attachments[created.ID] = &stored
+	return &created, nil
+}
+
+func (m *MockMeetingAttachmentRepository) GetByID(ctx context.Context, id int64) (*models.MeetingAttachment, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	if attachment, ok := m.Attachments[id]; ok {
+		found := *attachment
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (m *MockMeetingAttachmentRepository) ListByMeeting(ctx context.Context, meetingID int64, occurrenceStart *time.Time) ([]models.MeetingAttachment, error) {
+	if m.ListByMeetingFunc != nil {
+		return m.ListByMeetingFunc(ctx, meetingID, occurrenceStart)
+	}
+	attachments := []models.MeetingAttachment{}
+	for _, a := range m.Attachments {
+		if a.MeetingID != meetingID {
+			continue
+		}
+		if occurrenceStart != nil && (a.OccurrenceStart == nil || !a.OccurrenceStart.Equal(*occurrenceStart)) {
+			continue
+		}
+		attachments = append(attachments, *a)
+	}
+	return attachments, nil
+}
+
+func (m *MockMeetingAttachmentRepository) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	if _, ok := m.Attachments[id]; !ok {
+		return errors.New("meeting attachment not found")
+	}
+	delete(m.Attachments, id)
+	return nil
+}
+
+func (m *MockMeetingAttachmentRepository) SearchTranscripts(ctx context.Context, userID int64, query string, limit int) ([]models.TranscriptSearchResult, error) {
+	if m.SearchTranscriptsFunc != nil {
+		return m.SearchTranscriptsFunc(ctx, userID, query, limit)
+	}
+	visible := make(map[int64]bool)
+	for _, id := range m.VisibleMeetingIDs[userID] {
+		visible[id] = true
+	}
+	results := []models.TranscriptSearchResult{}
+	for _, a := range m.Attachments {
+		if !visible[a.MeetingID] || a.TranscriptText == nil {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(*a.TranscriptText), strings.ToLower(query)) {
+			continue
+		}
+		results = append(results, models.TranscriptSearchResult{Attachment: *a, Snippet: *a.TranscriptText})
+		if len(results) == limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// AddAttachment is a helper method for setting up test data
+func (m *MockMeetingAttachmentRepository) AddAttachment(attachment *models.MeetingAttachment) {
+	m.Attachments[attachment.ID] = attachment
+	if attachment.ID >= m.NextID {
+		m.NextID = attachment.ID + 1
+	}
+}
@@ -0,0 +1,155 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockMeetingNotesRepository is a mock implementation of MeetingNotesRepository for testing
+type MockMeetingNotesRepository struct {
+	Agendas    map[int64][]models.MeetingAgendaItem
+	Notes      map[int64]*models.MeetingNotes
+	Tasks      []models.Task // Tasks created by ConvertActionItems
+	NextID     int64
+	NextTaskID int64
+
+	// Function hooks for custom behavior
+	ConvertActionItemsFunc func(ctx context.Context, meeting *models.Meeting, createdByID int64, itemIDs []int64, defaultDueDate time.Time) ([]models.Task, error)
+}
+
+// NewMockMeetingNotesRepository creates a new mock meeting notes repository
+func NewMockMeetingNotesRepository() *MockMeetingNotesRepository {
+	return &MockMeetingNotesRepository{
+		Agendas:    make(map[int64][]models.MeetingAgendaItem),
+		Notes:      make(map[int64]*models.MeetingNotes),
+		NextID:     1,
+		NextTaskID: 1,
+	}
+}
+
+// AddActionItem adds an action item to a meeting's notes
+func (m *MockMeetingNotesRepository) AddActionItem(item models.MeetingActionItem) {
+	notes := m.notesFor(item.MeetingID)
+	if item.ID == 0 {
+		item.ID = m.NextID
+		m.NextID++
+	} else if item.ID >= m.NextID {
+		m.NextID = item.ID + 1
+	}
+	notes.ActionItems = append(notes.ActionItems, item)
+}
+
+func (m *MockMeetingNotesRepository) notesFor(meetingID int64) *models.MeetingNotes {
+	notes, ok := m.Notes[meetingID]
+	if !ok {
+		notes = &models.MeetingNotes{MeetingID: meetingID, ActionItems: []models.MeetingActionItem{}}
+		m.Notes[meetingID] = notes
+	}
+	return notes
+}
+
+func (m *MockMeetingNotesRepository) GetAgenda(ctx context.Context, meetingID int64) ([]models.MeetingAgendaItem, error) {
+	items := m.Agendas[meetingID]
+	if items == nil {
+		items = []models.MeetingAgendaItem{}
+	}
+	return items, nil
+}
+
+func (m *MockMeetingNotesRepository) ReplaceAgenda(ctx context.Context, meetingID int64, items []models.MeetingAgendaItemInput) ([]models.MeetingAgendaItem, error) {
+	agenda := make([]models.MeetingAgendaItem, len(items))
+	for i, item := range items {
+		agenda[i] = models.MeetingAgendaItem{
+			ID:              m.NextID,
+			MeetingID:       meetingID,
+			Position:        i,
+			Title:           item.Title,
+			DurationMinutes: item.DurationMinutes,
+			CreatedAt:       time.Now(),
+		}
+		m.NextID++
+	}
+	m.Agendas[meetingID] = agenda
+	return agenda, nil
+}
+
+func (m *MockMeetingNotesRepository) GetNotes(ctx context.Context, meetingID int64) (*models.MeetingNotes, error) {
+	notes := *m.notesFor(meetingID)
+	notes.ActionItems = append([]models.MeetingActionItem{}, notes.ActionItems...)
+	return &notes, nil
+}
+
+func (m *MockMeetingNotesRepository) SaveNotes(ctx context.Context, meetingID, authorID int64, req *models.UpdateMeetingNotesRequest) (*models.MeetingNotes, error) {
+	notes := m.notesFor(meetingID)
+	now := time.Now()
+	notes.Notes = req.Notes
+	notes.UpdatedByID = &authorID
+	notes.UpdatedAt = &now
+
+	kept := []models.MeetingActionItem{}
+	for _, item := range notes.ActionItems {
+		if item.IsConverted() {
+			kept = append(kept, item)
+		}
+	}
+	for _, input := range req.ActionItems {
+		kept = append(kept, models.MeetingActionItem{
+			ID:          m.NextID,
+			MeetingID:   meetingID,
+			Description: input.Description,
+			AssigneeID:  input.AssigneeID,
+			DueDate:     input.DueDate,
+			CreatedAt:   now,
+		})
+		m.NextID++
+	}
+	notes.ActionItems = kept
+
+	return m.GetNotes(ctx, meetingID)
+}
+
+func (m *MockMeetingNotesRepository) ConvertActionItems(ctx context.Context, meeting *models.Meeting, createdByID int64, itemIDs []int64, defaultDueDate time.Time) ([]models.Task, error) {
+	if m.ConvertActionItemsFunc != nil {
+		return m.ConvertActionItemsFunc(ctx, meeting, createdByID, itemIDs, defaultDueDate)
+	}
+
+	wanted := make(map[int64]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		wanted[id] = true
+	}
+
+	notes := m.notesFor(meeting.ID)
+	tasks := []models.Task{}
+	for i := range notes.ActionItems {
+		item := &notes.ActionItems[i]
+		if !wanted[item.ID] || item.IsConverted() || item.AssigneeID == nil {
+			continue
+		}
+		dueDate := defaultDueDate
+		if item.DueDate != nil {
+			dueDate = *item.DueDate
+		}
+		meetingID := meeting.ID
+		task := models.Task{
+			ID:             m.NextTaskID,
+			Title:          item.Description,
+			Status:         models.TaskStatusPending,
+			Priority:       models.TaskPriorityMedium,
+			Labels:         []string{},
+			DueDate:        dueDate,
+			AllDay:         true,
+			CreatedByID:    createdByID,
+			AssignmentType: models.AssignmentTypeUser,
+			AssignedUserID: item.AssigneeID,
+			MeetingID:      &meetingID,
+		}
+		m.NextTaskID++
+		taskID := task.ID
+		item.TaskID = &taskID
+		m.Tasks = append(m.Tasks, task)
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
@@ -13,6 +13,9 @@ type MockMeetingRepository struct {
 	Meetings  map[int64]*models.Meeting
 	Attendees map[int64][]models.MeetingAttendee
 	NextID    int64
+	// Soft-deleted meetings and when they were deleted
+	Deleted   map[int64]*models.Meeting
+	DeletedAt map[int64]time.Time
 
 	// Function hooks for custom behavior
 	CreateFunc                 func(ctx context.Context, req *models.CreateMeetingRequest, createdByID int64) (*models.Meeting, error)
@@ -20,12 +23,15 @@ type MockMeetingRepository struct {
 	GetAttendeesFunc           func(ctx context.Context, meetingID int64) ([]models.MeetingAttendee, error)
 	UpdateFunc                 func(ctx context.Context, id int64, req *models.UpdateMeetingRequest) (*models.Meeting, error)
 	DeleteFunc                 func(ctx context.Context, id int64) error
+	UpdateOccurrenceFunc       func(ctx context.Context, seriesID int64, req *models.UpdateMeetingOccurrenceRequest) (*models.Meeting, error)
+	CancelOccurrenceFunc       func(ctx context.Context, seriesID int64, occurrenceStart time.Time, scope models.OccurrenceScope) error
 	RespondToMeetingFunc       func(ctx context.Context, meetingID int64, userID int64, response models.ResponseStatus) error
 	GetByDateRangeFunc         func(ctx context.Context, userID int64, start, end time.Time) ([]models.Meeting, error)
 	GetAllByDateRangeFunc      func(ctx context.Context, start, end time.Time) ([]models.Meeting, error)
 	GetVisibleMeetingsFunc     func(ctx context.Context, user *models.User, start, end time.Time) ([]models.Meeting, error)
 	ExpandRecurringMeetingsFunc func(meetings []models.Meeting, start, end time.Time) []models.Meeting
 	IsAttendeeFunc             func(ctx context.Context, meetingID, userID int64) (bool, error)
+	HasICalUIDFunc             func(ctx context.Context, createdByID int64, uid string) (bool, error)
 }
 
 // NewMockMeetingRepository creates a new mock meeting repository
@@ -48,9 +54,24 @@ func (m *MockMeetingRepository) Create(ctx context.Context, req *models.CreateMe
 		StartTime:   req.StartTime,
 		EndTime:     req.EndTime,
 		CreatedByID: createdByID,
+		ICalUID:     req.ICalUID,
+		Timezone:    models.DefaultTimezone,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	if req.Timezone != nil {
+		meeting.Timezone = *req.Timezone
+	}
+	if req.RecurrenceType != nil {
+		meeting.RecurrenceType = req.RecurrenceType
+		meeting.RecurrenceInterval = 1
+		if req.RecurrenceInterval != nil {
+			meeting.RecurrenceInterval = *req.RecurrenceInterval
+		}
+		meeting.RecurrenceEndDate = req.RecurrenceEndDate
+		meeting.RecurrenceDaysOfWeek = req.RecurrenceDaysOfWeek
+		meeting.RecurrenceDayOfMonth = req.RecurrenceDayOfMonth
+	}
 	m.NextID++
 	m.Meetings[meeting.ID] = meeting
 
@@ -104,6 +125,9 @@ func (m *MockMeetingRepository) Update(ctx context.Context, id int64, req *model
 	if req.EndTime != nil {
 		meeting.EndTime = *req.EndTime
 	}
+	if req.Timezone != nil {
+		meeting.Timezone = *req.Timezone
+	}
 	meeting.UpdatedAt = time.Now()
 	return meeting, nil
 }
@@ -115,8 +139,92 @@ func (m *MockMeetingRepository) Delete(ctx context.Context, id int64) error {
 	if _, ok := m.Meetings[id]; !ok {
 		return errors.New("meeting not found")
 	}
+	if m.Deleted == nil {
+		m.Deleted = make(map[int64]*models.Meeting)
+		m.DeletedAt = make(map[int64]time.Time)
+	}
+	m.Deleted[id] = m.Meetings[id]
+	m.DeletedAt[id] = time.Now()
 	delete(m.Meetings, id)
-	delete(m.Attendees, id)
+	return nil
+}
+
+func (m *MockMeetingRepository) Restore(ctx context.Context, id int64) (*models.Meeting, error) {
+	meeting, ok := m.Deleted[id]
+	if !ok {
+		return nil, errors.New("deleted meeting not found")
+	}
+	m.Meetings[id] = meeting
+	delete(m.Deleted, id)
+	delete(m.DeletedAt, id)
+	return meeting, nil
+}
+
+func (m *MockMeetingRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	for id, deletedAt := range m.DeletedAt {
+		if deletedAt.Before(before) {
+			delete(m.Deleted, id)
+			delete(m.DeletedAt, id)
+			delete(m.Attendees, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (m *MockMeetingRepository) UpdateOccurrence(ctx context.Context, seriesID int64, req *models.UpdateMeetingOccurrenceRequest) (*models.Meeting, error) {
+	if m.UpdateOccurrenceFunc != nil {
+		return m.UpdateOccurrenceFunc(ctx, seriesID, req)
+	}
+	series, ok := m.Meetings[seriesID]
+	if !ok {
+		return nil, errors.New("meeting not found")
+	}
+	startTime := req.OccurrenceStart
+	if req.StartTime != nil {
+		startTime = *req.StartTime
+	}
+	occurrence := *series
+	occurrence.ID = m.NextID
+	occurrence.StartTime = startTime
+	occurrence.EndTime = startTime.Add(series.EndTime.Sub(series.StartTime))
+	occurrence.ExceptionStarts = nil
+	if req.EndTime != nil {
+		occurrence.EndTime = *req.EndTime
+	}
+	if req.Title != nil {
+		occurrence.Title = *req.Title
+	}
+	if req.Scope == models.OccurrenceScopeThis {
+		originalStart := req.OccurrenceStart
+		occurrence.RecurrenceType = nil
+		occurrence.ParentMeetingID = &series.ID
+		occurrence.OriginalStartTime = &originalStart
+		series.ExceptionStarts = append(series.ExceptionStarts, originalStart)
+	} else {
+		endDate := req.OccurrenceStart.Add(-time.Second)
+		series.RecurrenceEndDate = &endDate
+	}
+	m.NextID++
+	m.Meetings[occurrence.ID] = &occurrence
+	return &occurrence, nil
+}
+
+func (m *MockMeetingRepository) CancelOccurrence(ctx context.Context, seriesID int64, occurrenceStart time.Time, scope models.OccurrenceScope) error {
+	if m.CancelOccurrenceFunc != nil {
+		return m.CancelOccurrenceFunc(ctx, seriesID, occurrenceStart, scope)
+	}
+	series, ok := m.Meetings[seriesID]
+	if !ok {
+		return errors.New("meeting not found")
+	}
+	if scope == models.OccurrenceScopeThis {
+		series.ExceptionStarts = append(series.ExceptionStarts, occurrenceStart)
+	} else {
+		endDate := occurrenceStart.Add(-time.Second)
+		series.RecurrenceEndDate = &endDate
+	}
 	return nil
 }
 
@@ -190,6 +298,18 @@ func (m *MockMeetingRepository) IsAttendee(ctx context.Context, meetingID, userI
 	return m.isUserAttendee(meetingID, userID), nil
 }
 
+func (m *MockMeetingRepository) HasICalUID(ctx context.Context, createdByID int64, uid string) (bool, error) {
+	if m.HasICalUIDFunc != nil {
+		return m.HasICalUIDFunc(ctx, createdByID, uid)
+	}
+	for _, meeting := range m.Meetings {
+		if meeting.CreatedByID == createdByID && meeting.ICalUID != nil && *meeting.ICalUID == uid {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (m *MockMeetingRepository) isUserAttendee(meetingID, userID int64) bool {
 	for _, att := range m.Attendees[meetingID] {
 		if att.UserID == userID {
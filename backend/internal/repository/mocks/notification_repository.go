@@ -0,0 +1,92 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockNotificationRepository is a mock implementation of NotificationRepository for testing
+type MockNotificationRepository struct {
+	Notifications []models.Notification
+	NextID        int64
+
+	// Function hooks for custom behavior
+	CreateFunc func(ctx context.Context, n *models.Notification) (*models.Notification, error)
+}
+
+// NewMockNotificationRepository creates a new mock notification repository
+func NewMockNotificationRepository() *MockNotificationRepository {
+	return &MockNotificationRepository{NextID: 1}
+}
+
+func (m *MockNotificationRepository) Create(ctx context.Context, n *models.Notification) (*models.Notification, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, n)
+	}
+	created := *n
+	created.ID = m.NextID
+	created.CreatedAt = time.Now()
+	m.NextID++
+	m.Notifications = append(m.Notifications, created)
+	return &created, nil
+}
+
+func (m *MockNotificationRepository) List(ctx context.Context, userID int64, unreadOnly bool, limit, offset int) ([]models.Notification, int, error) {
+	matching := []models.Notification{}
+	// Newest first, like the database
+	for i := len(m.Notifications) - 1; i >= 0; i-- {
+		n := m.Notifications[i]
+		if n.UserID == userID && (!unreadOnly || n.ReadAt == nil) {
+			matching = append(matching, n)
+		}
+	}
+	total := len(matching)
+	if offset >= total {
+		return []models.Notification{}, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return matching[offset:end], total, nil
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	count := 0
+	for _, n := range m.Notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID, id int64) error {
+	for i := range m.Notifications {
+		n := &m.Notifications[i]
+		if n.ID == id && n.UserID == userID {
+			if n.ReadAt == nil {
+				now := time.Now()
+				n.ReadAt = &now
+			}
+			return nil
+		}
+	}
+	return errors.New("notification not found")
+}
+
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	var marked int64
+	now := time.Now()
+	for i := range m.Notifications {
+		n := &m.Notifications[i]
+		if n.UserID == userID && n.ReadAt == nil {
+			n.ReadAt = &now
+			marked++
+		}
+	}
+	return marked, nil
+}
@@ -0,0 +1,153 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockOnboardingRepository is a mock implementation of OnboardingRepository for testing
+type MockOnboardingRepository struct {
+	Templates map[int64]*models.OnboardingTemplate
+	// Tasks holds each user's onboarding tasks
+	Tasks      map[int64][]models.Task
+	NextID     int64
+	NextTaskID int64
+
+	// Function hooks for custom behavior
+	ListTemplatesFunc  func(ctx context.Context) ([]models.OnboardingTemplate, error)
+	GetTemplateFunc    func(ctx context.Context, id int64) (*models.OnboardingTemplate, error)
+	CreateTemplateFunc func(ctx context.Context, req *models.OnboardingTemplateRequest) (*models.OnboardingTemplate, error)
+	UpdateTemplateFunc func(ctx context.Context, id int64, req *models.OnboardingTemplateRequest) (*models.OnboardingTemplate, error)
+	DeleteTemplateFunc func(ctx context.Context, id int64) error
+	CreateTasksFunc    func(ctx context.Context, userID, createdByID int64, reqs []models.CreateTaskRequest) ([]models.Task, error)
+	GetTasksFunc       func(ctx context.Context, userID int64) ([]models.Task, error)
+}
+
+// NewMockOnboardingRepository creates a new mock onboarding repository
+func NewMockOnboardingRepository() *MockOnboardingRepository {
+	return &MockOnboardingRepository{
+		Templates:  make(map[int64]*models.OnboardingTemplate),
+		Tasks:      make(map[int64][]models.Task),
+		NextID:     1,
+		NextTaskID: 1,
+	}
+}
+
+func (m *MockOnboardingRepository) ListTemplates(ctx context.Context) ([]models.OnboardingTemplate, error) {
+	if m.ListTemplatesFunc != nil {
+		return m.ListTemplatesFunc(ctx)
+	}
+	templates := []models.OnboardingTemplate{}
+	for _, t := range m.Templates {
+		templates = append(templates, *t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+	return templates, nil
+}
+
+func (m *MockOnboardingRepository) GetTemplate(ctx context.Context, id int64) (*models.OnboardingTemplate, error) {
+	if m.GetTemplateFunc != nil {
+		return m.GetTemplateFunc(ctx, id)
+	}
+	if t, ok := m.Templates[id]; ok {
+		return t, nil
+	}
+	return nil, nil
+}
+
+func (m *MockOnboardingRepository) CreateTemplate(ctx context.Context, req *models.OnboardingTemplateRequest) (*models.OnboardingTemplate, error) {
+	if m.CreateTemplateFunc != nil {
+		return m.CreateTemplateFunc(ctx, req)
+	}
+	t := &models.OnboardingTemplate{ID: m.NextID, CreatedAt: time.Now()}
+	m.NextID++
+	m.Templates[t.ID] = t
+	return m.apply(t, req), nil
+}
+
+func (m *MockOnboardingRepository) UpdateTemplate(ctx context.Context, id int64, req *models.OnboardingTemplateRequest) (*models.OnboardingTemplate, error) {
+	if m.UpdateTemplateFunc != nil {
+		return m.UpdateTemplateFunc(ctx, id, req)
+	}
+	t, ok := m.Templates[id]
+	if !ok {
+		return nil, nil
+	}
+	return m.apply(t, req), nil
+}
+
+// apply copies a request onto a template
+func (m *MockOnboardingRepository) apply(t *models.OnboardingTemplate, req *models.OnboardingTemplateRequest) *models.OnboardingTemplate {
+	t.Name = req.Name
+	t.Department = req.Department
+	t.Role = req.Role
+	t.UpdatedAt = time.Now()
+	t.Items = make([]models.OnboardingTemplateItem, len(req.Items))
+	for i, in := range req.Items {
+		t.Items[i] = models.OnboardingTemplateItem{
+			ID:          int64(i + 1),
+			TemplateID:  t.ID,
+			Position:    i,
+			Title:       in.Title,
+			Description: in.Description,
+			Assignee:    in.Assignee,
+			DueDays:     in.DueDays,
+		}
+	}
+	return t
+}
+
+func (m *MockOnboardingRepository) DeleteTemplate(ctx context.Context, id int64) error {
+	if m.DeleteTemplateFunc != nil {
+		return m.DeleteTemplateFunc(ctx, id)
+	}
+	if _, ok := m.Templates[id]; !ok {
+		return errors.New("onboarding template not found")
+	}
+	delete(m.Templates, id)
+	return nil
+}
+
+func (m *MockOnboardingRepository) CreateTasks(ctx context.Context, userID, createdByID int64, reqs []models.CreateTaskRequest) ([]models.Task, error) {
+	if m.CreateTasksFunc != nil {
+		return m.CreateTasksFunc(ctx, userID, createdByID, reqs)
+	}
+	if len(m.Tasks[userID]) > 0 {
+		return nil, nil
+	}
+	tasks := make([]models.Task, len(reqs))
+	for i, req := range reqs {
+		tasks[i] = models.Task{
+			ID:                 m.NextTaskID,
+			Title:              req.Title,
+			Description:        req.Description,
+			Status:             models.TaskStatusPending,
+			Priority:           req.Priority,
+			Labels:             req.Labels,
+			DueDate:            req.DueDate,
+			AllDay:             true,
+			CreatedByID:        createdByID,
+			AssignmentType:     req.AssignmentType,
+			AssignedUserID:     req.AssignedUserID,
+			AssignedDepartment: req.AssignedDepartment,
+		}
+		m.NextTaskID++
+	}
+	m.Tasks[userID] = tasks
+	return tasks, nil
+}
+
+func (m *MockOnboardingRepository) GetTasks(ctx context.Context, userID int64) ([]models.Task, error) {
+	if m.GetTasksFunc != nil {
+		return m.GetTasksFunc(ctx, userID)
+	}
+	tasks := m.Tasks[userID]
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+	return tasks, nil
+}
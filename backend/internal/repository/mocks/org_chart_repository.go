@@ -0,0 +1,346 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// MockOrgChartRepository is a mock implementation of OrgChartRepository for testing
+type MockOrgChartRepository struct {
+	Drafts    map[int64]*models.OrgChartDraft
+	Snapshots map[int64]*models.OrgChartSnapshot
+	Shares    map[int64][]models.DraftShare // draftID -> shares
+	Comments  []models.DraftComment
+	Trees     []models.OrgTreeNode
+	nextID    int64
+
+	// Function hooks for custom behavior
+	PublishDraftFunc func(ctx context.Context, draftID int64, publishedByID int64) error
+}
+
+// NewMockOrgChartRepository creates a new mock org chart repository
+func NewMockOrgChartRepository() *MockOrgChartRepository {
+	return &MockOrgChartRepository{
+		Drafts:    make(map[int64]*models.OrgChartDraft),
+		Snapshots: make(map[int64]*models.OrgChartSnapshot),
+		Shares:    make(map[int64][]models.DraftShare),
+		nextID:    1,
+	}
+}
+
+func (m *MockOrgChartRepository) CreateDraft(ctx context.Context, req *models.CreateDraftRequest, createdByID int64) (*models.OrgChartDraft, error) {
+	now := time.Now()
+	draft := &models.OrgChartDraft{
+		ID:          m.nextID,
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedByID: createdByID,
+		Status:      models.DraftStatusDraft,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	m.nextID++
+	m.Drafts[draft.ID] = draft
+	return draft, nil
+}
+
+func (m *MockOrgChartRepository) GetDraftByID(ctx context.Context, id int64) (*models.OrgChartDraft, error) {
+	if draft, ok := m.Drafts[id]; ok {
+		return draft, nil
+	}
+	return nil, fmt.Errorf("draft not found")
+}
+
+func (m *MockOrgChartRepository) GetDraftsByCreator(ctx context.Context, creatorID int64) ([]models.OrgChartDraft, error) {
+	var drafts []models.OrgChartDraft
+	for _, draft := range m.Drafts {
+		if draft.CreatedByID == creatorID {
+			drafts = append(drafts, *draft)
+		}
+	}
+	return drafts, nil
+}
+
+func (m *MockOrgChartRepository) GetAllDrafts(ctx context.Context) ([]models.OrgChartDraft, error) {
+	drafts := make([]models.OrgChartDraft, 0, len(m.Drafts))
+	for _, draft := range m.Drafts {
+		drafts = append(drafts, *draft)
+	}
+	return drafts, nil
+}
+
+func (m *MockOrgChartRepository) UpdateDraft(ctx context.Context, id int64, req *models.UpdateDraftRequest) (*models.OrgChartDraft, error) {
+	draft, ok := m.Drafts[id]
+	if !ok || draft.Status != models.DraftStatusDraft {
+		return nil, fmt.Errorf("draft not found")
+	}
+	if req.Name != nil {
+		draft.Name = *req.Name
+	}
+	if req.Description != nil {
+		draft.Description = req.Description
+	}
+	draft.UpdatedAt = time.Now()
+	return draft, nil
+}
+
+func (m *MockOrgChartRepository) DeleteDraft(ctx context.Context, id int64) error {
+	draft, ok := m.Drafts[id]
+	if !ok || draft.Status != models.DraftStatusDraft {
+		return fmt.Errorf("draft not found or already published")
+	}
+	delete(m.Drafts, id)
+	return nil
+}
+
+func (m *MockOrgChartRepository) AddOrUpdateChange(ctx context.Context, draftID int64, req *models.AddDraftChangeRequest, userRepo repository.UserRepository) (*models.DraftChange, error) {
+	draft, ok := m.Drafts[draftID]
+	if !ok {
+		return nil, fmt.Errorf("draft not found")
+	}
+	user, err := userRepo.GetByID(ctx, req.UserID)
+	if err != nil || user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	change := models.DraftChange{
+		ID:                   m.nextID,
+		DraftID:              draftID,
+		UserID:               req.UserID,
+		User:                 user,
+		OriginalSupervisorID: user.SupervisorID,
+		OriginalDepartment:   &user.Department,
+		OriginalRole:         &user.Role,
+		NewSupervisorID:      req.NewSupervisorID,
+		NewDepartment:        req.NewDepartment,
+		NewRole:              req.NewRole,
+		NewSquadIDs:          req.NewSquadIDs,
+	}
+	m.nextID++
+	draft.Changes = append(draft.Changes, change)
+	draft.ApprovedByID, draft.ApprovedAt = nil, nil
+	return &change, nil
+}
+
+func (m *MockOrgChartRepository) RemoveChange(ctx context.Context, draftID int64, userID int64) error {
+	draft, ok := m.Drafts[draftID]
+	if !ok {
+		return fmt.Errorf("draft not found")
+	}
+	for i, change := range draft.Changes {
+		if change.UserID == userID {
+			draft.Changes = append(draft.Changes[:i], draft.Changes[i+1:]...)
+			draft.ApprovedByID, draft.ApprovedAt = nil, nil
+			return nil
+		}
+	}
+	return fmt.Errorf("change not found")
+}
+
+func (m *MockOrgChartRepository) GetDraftChanges(ctx context.Context, draftID int64) ([]models.DraftChange, error) {
+	if draft, ok := m.Drafts[draftID]; ok {
+		return draft.Changes, nil
+	}
+	return nil, nil
+}
+
+func (m *MockOrgChartRepository) PublishDraft(ctx context.Context, draftID int64, publishedByID int64) error {
+	if m.PublishDraftFunc != nil {
+		return m.PublishDraftFunc(ctx, draftID, publishedByID)
+	}
+	draft, ok := m.Drafts[draftID]
+	if !ok || draft.Status != models.DraftStatusDraft {
+		return fmt.Errorf("draft is not in draft status")
+	}
+	if draft.ApprovedAt == nil {
+		return &models.DraftPublishError{Message: "Draft must be approved before publishing"}
+	}
+	now := time.Now()
+	draft.Status = models.DraftStatusPublished
+	draft.PublishedAt = &now
+
+	snapshot := &models.OrgChartSnapshot{
+		ID:            m.nextID,
+		DraftID:       &draft.ID,
+		DraftName:     draft.Name,
+		PublishedByID: &publishedByID,
+		Tree:          m.Trees,
+		CreatedAt:     now,
+	}
+	m.nextID++
+	m.Snapshots[snapshot.ID] = snapshot
+	return nil
+}
+
+func (m *MockOrgChartRepository) ApproveDraft(ctx context.Context, draftID int64, approverID int64) (*models.OrgChartDraft, error) {
+	draft, ok := m.Drafts[draftID]
+	if !ok || draft.Status != models.DraftStatusDraft {
+		return nil, nil
+	}
+	now := time.Now()
+	draft.ApprovedByID = &approverID
+	draft.ApprovedAt = &now
+	return draft, nil
+}
+
+func (m *MockOrgChartRepository) GetSharedDrafts(ctx context.Context, userID int64) ([]models.OrgChartDraft, error) {
+	var drafts []models.OrgChartDraft
+	for draftID, shares := range m.Shares {
+		for _, share := range shares {
+			if share.UserID == userID && m.Drafts[draftID] != nil {
+				drafts = append(drafts, *m.Drafts[draftID])
+			}
+		}
+	}
+	return drafts, nil
+}
+
+func (m *MockOrgChartRepository) GetDraftShares(ctx context.Context, draftID int64) ([]models.DraftShare, error) {
+	return append([]models.DraftShare{}, m.Shares[draftID]...), nil
+}
+
+func (m *MockOrgChartRepository) GetDraftShare(ctx context.Context, draftID int64, userID int64) (*models.DraftShare, error) {
+	for _, share := range m.Shares[draftID] {
+		if share.UserID == userID {
+			return &share, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockOrgChartRepository) ShareDraft(ctx context.Context, draftID int64, req *models.ShareDraftRequest, sharedByID int64) (*models.DraftShare, error) {
+	share := models.DraftShare{DraftID: draftID, UserID: req.UserID, Permission: req.Permission, SharedByID: &sharedByID, CreatedAt: time.Now()}
+	shares := m.Shares[draftID]
+	for i := range shares {
+		if shares[i].UserID == req.UserID {
+			shares[i].Permission = req.Permission
+			return &shares[i], nil
+		}
+	}
+	m.Shares[draftID] = append(shares, share)
+	return &share, nil
+}
+
+func (m *MockOrgChartRepository) UnshareDraft(ctx context.Context, draftID int64, userID int64) error {
+	shares := m.Shares[draftID]
+	for i := range shares {
+		if shares[i].UserID == userID {
+			m.Shares[draftID] = append(shares[:i], shares[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("share not found")
+}
+
+func (m *MockOrgChartRepository) GetDraftComments(ctx context.Context, draftID int64) ([]models.DraftComment, error) {
+	comments := []models.DraftComment{}
+	for _, c := range m.Comments {
+		if c.DraftID == draftID {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+func (m *MockOrgChartRepository) AddDraftComment(ctx context.Context, draftID int64, req *models.CreateDraftCommentRequest, authorID int64) (*models.DraftComment, error) {
+	draft, ok := m.Drafts[draftID]
+	if !ok {
+		return nil, nil
+	}
+	for _, change := range draft.Changes {
+		if change.ID == req.ChangeID {
+			comment := models.DraftComment{ID: m.nextID, DraftID: draftID, ChangeID: req.ChangeID, AuthorID: authorID, Body: req.Body, CreatedAt: time.Now()}
+			m.nextID++
+			m.Comments = append(m.Comments, comment)
+			return &comment, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockOrgChartRepository) GetSnapshots(ctx context.Context, before *time.Time, limit, offset int) ([]models.OrgChartSnapshot, int, error) {
+	snapshots := []models.OrgChartSnapshot{}
+	for _, s := range m.Snapshots {
+		if before == nil || s.CreatedAt.Before(*before) {
+			summary := *s
+			summary.Tree = nil
+			snapshots = append(snapshots, summary)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	total := len(snapshots)
+	start := min(offset, total)
+	return snapshots[start:min(start+limit, total)], total, nil
+}
+
+func (m *MockOrgChartRepository) GetSnapshotByID(ctx context.Context, id int64) (*models.OrgChartSnapshot, error) {
+	return m.Snapshots[id], nil
+}
+
+func (m *MockOrgChartRepository) GetOrgTree(ctx context.Context, supervisorID int64) (*models.OrgTreeNode, error) {
+	for i := range m.Trees {
+		if m.Trees[i].User.ID == supervisorID {
+			return &m.Trees[i], nil
+		}
+	}
+	return nil, fmt.Errorf("supervisor not found")
+}
+
+func (m *MockOrgChartRepository) GetFullOrgTree(ctx context.Context) ([]models.OrgTreeNode, error) {
+	return m.Trees, nil
+}
+
+// GetScopedOrgTree finds the root anywhere in Trees and copies the trees down to the depth limit.
+// Trees are expected to hold only the users the scope should include.
+func (m *MockOrgChartRepository) GetScopedOrgTree(ctx context.Context, scope models.OrgTreeScope) ([]models.OrgTreeNode, error) {
+	var prune func(node models.OrgTreeNode, level int) models.OrgTreeNode
+	prune = func(node models.OrgTreeNode, level int) models.OrgTreeNode {
+		children := node.Children
+		node.Children = []models.OrgTreeNode{}
+		if scope.Depth != nil && level == *scope.Depth {
+			node.ChildrenTruncated = len(children) > 0
+			return node
+		}
+		for _, child := range children {
+			node.Children = append(node.Children, prune(child, level+1))
+		}
+		return node
+	}
+
+	var find func(nodes []models.OrgTreeNode) *models.OrgTreeNode
+	find = func(nodes []models.OrgTreeNode) *models.OrgTreeNode {
+		for i := range nodes {
+			if nodes[i].User.ID == *scope.RootID {
+				return &nodes[i]
+			}
+			if found := find(nodes[i].Children); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+
+	roots := m.Trees
+	if scope.RootID != nil {
+		root := find(m.Trees)
+		if root == nil {
+			return nil, fmt.Errorf("user not found")
+		}
+		roots = []models.OrgTreeNode{*root}
+	}
+	trees := make([]models.OrgTreeNode, 0, len(roots))
+	for _, root := range roots {
+		trees = append(trees, prune(root, 0))
+	}
+	return trees, nil
+}
+
+func (m *MockOrgChartRepository) GetMemberStats(ctx context.Context, userIDs []int64, now time.Time) (map[int64]models.OrgMemberStats, error) {
+	return make(map[int64]models.OrgMemberStats), nil
+}
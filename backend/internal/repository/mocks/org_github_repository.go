@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockOrgGitHubRepository is a mock implementation of OrgGitHubRepository for testing
+type MockOrgGitHubRepository struct {
+	Settings *models.OrgGitHubSettings
+
+	// Function hooks for custom behavior
+	GetFunc func(ctx context.Context) (*models.OrgGitHubSettings, error)
+}
+
+// NewMockOrgGitHubRepository creates a new mock org GitHub repository with no connection
+func NewMockOrgGitHubRepository() *MockOrgGitHubRepository {
+	return &MockOrgGitHubRepository{}
+}
+
+func (m *MockOrgGitHubRepository) Get(ctx context.Context) (*models.OrgGitHubSettings, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx)
+	}
+	return m.Settings, nil
+}
+
+func (m *MockOrgGitHubRepository) Save(ctx context.Context, settings *models.OrgGitHubSettings) error {
+	now := time.Now()
+	settings.ID = 1
+	settings.CreatedAt = now
+	settings.UpdatedAt = now
+	if settings.OrgLogin == nil && m.Settings != nil {
+		settings.OrgLogin = m.Settings.OrgLogin
+	}
+	m.Settings = settings
+	return nil
+}
+
+func (m *MockOrgGitHubRepository) UpdateTokens(ctx context.Context, accessToken, refreshToken string, expiresAt *time.Time) error {
+	if m.Settings == nil {
+		return nil
+	}
+	m.Settings.OAuthAccessToken = accessToken
+	m.Settings.OAuthRefreshToken = refreshToken
+	m.Settings.OAuthTokenExpiresAt = expiresAt
+	return nil
+}
+
+func (m *MockOrgGitHubRepository) UpdateOrgLogin(ctx context.Context, orgLogin *string) (bool, error) {
+	if m.Settings == nil {
+		return false, nil
+	}
+	m.Settings.OrgLogin = orgLogin
+	return true, nil
+}
+
+func (m *MockOrgGitHubRepository) Delete(ctx context.Context) error {
+	m.Settings = nil
+	return nil
+}
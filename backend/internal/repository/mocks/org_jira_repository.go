@@ -0,0 +1,145 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockOrgJiraRepository is a mock implementation of OrgJiraRepository for testing
+type MockOrgJiraRepository struct {
+	Settings    *models.OrgJiraSettings
+	Pending     *models.JiraPendingConnection
+	MatchReport *models.JiraUserMatchReport
+
+	// Function hooks for custom behavior
+	GetFunc                  func(ctx context.Context) (*models.OrgJiraSettings, error)
+	UpdateTokensFunc         func(ctx context.Context, accessToken, refreshToken string, expiresAt time.Time) error
+	RecordRefreshFailureFunc func(ctx context.Context, message string) (int, error)
+	RecordAPIErrorFunc       func(ctx context.Context, message string) error
+}
+
+// NewMockOrgJiraRepository creates a new mock org Jira repository with no connection
+func NewMockOrgJiraRepository() *MockOrgJiraRepository {
+	return &MockOrgJiraRepository{}
+}
+
+func (m *MockOrgJiraRepository) Get(ctx context.Context) (*models.OrgJiraSettings, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx)
+	}
+	return m.Settings, nil
+}
+
+func (m *MockOrgJiraRepository) Save(ctx context.Context, settings *models.OrgJiraSettings) error {
+	now := time.Now()
+	settings.ID = 1
+	settings.CreatedAt = now
+	settings.UpdatedAt = now
+	settings.TaskFilters = []models.JiraTaskFilter{}
+	if m.Settings != nil {
+		settings.TaskFilters = m.Settings.TaskFilters
+	}
+	m.Settings = settings
+	return nil
+}
+
+func (m *MockOrgJiraRepository) UpdateTokens(ctx context.Context, accessToken, refreshToken string, expiresAt time.Time) error {
+	if m.UpdateTokensFunc != nil {
+		return m.UpdateTokensFunc(ctx, accessToken, refreshToken, expiresAt)
+	}
+	if m.Settings == nil {
+		return nil
+	}
+	now := time.Now()
+	m.Settings.OAuthAccessToken = accessToken
+	m.Settings.OAuthRefreshToken = refreshToken
+	m.Settings.OAuthTokenExpiresAt = expiresAt
+	m.Settings.LastRefreshedAt = &now
+	m.Settings.RefreshFailures = 0
+	return nil
+}
+
+func (m *MockOrgJiraRepository) RecordRefreshFailure(ctx context.Context, message string) (int, error) {
+	if m.RecordRefreshFailureFunc != nil {
+		return m.RecordRefreshFailureFunc(ctx, message)
+	}
+	if m.Settings == nil {
+		return 0, nil
+	}
+	now := time.Now()
+	m.Settings.RefreshFailures++
+	m.Settings.LastRefreshError = &message
+	m.Settings.LastRefreshErrorAt = &now
+	return m.Settings.RefreshFailures, nil
+}
+
+func (m *MockOrgJiraRepository) RecordAPIError(ctx context.Context, message string) error {
+	if m.RecordAPIErrorFunc != nil {
+		return m.RecordAPIErrorFunc(ctx, message)
+	}
+	if m.Settings == nil {
+		return nil
+	}
+	now := time.Now()
+	m.Settings.LastAPIError = &message
+	m.Settings.LastAPIErrorAt = &now
+	return nil
+}
+
+func (m *MockOrgJiraRepository) RecordWebhookDelivery(ctx context.Context) error {
+	if m.Settings != nil {
+		now := time.Now()
+		m.Settings.LastWebhookAt = &now
+	}
+	return nil
+}
+
+func (m *MockOrgJiraRepository) UpdateTaskFilters(ctx context.Context, filters []models.JiraTaskFilter) (bool, error) {
+	if m.Settings == nil {
+		return false, nil
+	}
+	m.Settings.TaskFilters = filters
+	return true, nil
+}
+
+func (m *MockOrgJiraRepository) SaveUserMatchReport(ctx context.Context, report *models.JiraUserMatchReport) (bool, error) {
+	if m.Settings == nil {
+		return false, nil
+	}
+	m.MatchReport = report
+	return true, nil
+}
+
+func (m *MockOrgJiraRepository) GetUserMatchReport(ctx context.Context) (*models.JiraUserMatchReport, error) {
+	if m.Settings == nil {
+		return nil, nil
+	}
+	return m.MatchReport, nil
+}
+
+func (m *MockOrgJiraRepository) Delete(ctx context.Context) error {
+	m.Settings = nil
+	m.MatchReport = nil
+	return nil
+}
+
+func (m *MockOrgJiraRepository) SavePendingConnection(ctx context.Context, pending *models.JiraPendingConnection) error {
+	pending.ID = 1
+	pending.CreatedAt = time.Now()
+	m.Pending = pending
+	return nil
+}
+
+func (m *MockOrgJiraRepository) GetPendingConnection(ctx context.Context) (*models.JiraPendingConnection, error) {
+	if m.Pending != nil && m.Pending.ExpiresAt.After(time.Now()) {
+		return m.Pending, nil
+	}
+	return nil, nil
+}
+
+func (m *MockOrgJiraRepository) DeletePendingConnection(ctx context.Context) error {
+	m.Pending = nil
+	return nil
+}
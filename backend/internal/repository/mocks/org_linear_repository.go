@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockOrgLinearRepository is a mock implementation of OrgLinearRepository for testing
+type MockOrgLinearRepository struct {
+	Settings *models.OrgLinearSettings
+
+	// Function hooks for custom behavior
+	GetFunc func(ctx context.Context) (*models.OrgLinearSettings, error)
+}
+
+// NewMockOrgLinearRepository creates a new mock org Linear repository with no connection
+func NewMockOrgLinearRepository() *MockOrgLinearRepository {
+	return &MockOrgLinearRepository{}
+}
+
+func (m *MockOrgLinearRepository) Get(ctx context.Context) (*models.OrgLinearSettings, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx)
+	}
+	return m.Settings, nil
+}
+
+func (m *MockOrgLinearRepository) Save(ctx context.Context, settings *models.OrgLinearSettings) error {
+	now := time.Now()
+	settings.ID = 1
+	settings.CreatedAt = now
+	settings.UpdatedAt = now
+	m.Settings = settings
+	return nil
+}
+
+func (m *MockOrgLinearRepository) Delete(ctx context.Context) error {
+	m.Settings = nil
+	return nil
+}
@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// MockOrgSlackRepository is a mock implementation of OrgSlackRepository for testing
+type MockOrgSlackRepository struct {
+	Settings map[int64]*models.OrgSlackSettings // By organization
+
+	// Function hooks for custom behavior
+	GetFunc func(ctx context.Context) (*models.OrgSlackSettings, error)
+}
+
+// NewMockOrgSlackRepository creates a new mock org Slack repository with no installations
+func NewMockOrgSlackRepository() *MockOrgSlackRepository {
+	return &MockOrgSlackRepository{
+		Settings: make(map[int64]*models.OrgSlackSettings),
+	}
+}
+
+func (m *MockOrgSlackRepository) Get(ctx context.Context) (*models.OrgSlackSettings, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx)
+	}
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.Settings[orgID], nil
+}
+
+func (m *MockOrgSlackRepository) GetByTeamID(ctx context.Context, teamID string) (*models.OrgSlackSettings, error) {
+	for _, settings := range m.Settings {
+		if settings.TeamID == teamID {
+			return settings, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockOrgSlackRepository) Save(ctx context.Context, settings *models.OrgSlackSettings) error {
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	settings.ID = int64(len(m.Settings) + 1)
+	settings.OrgID = orgID
+	settings.CreatedAt = now
+	settings.UpdatedAt = now
+	m.Settings[settings.OrgID] = settings
+	return nil
+}
+
+func (m *MockOrgSlackRepository) Delete(ctx context.Context) error {
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return err
+	}
+	delete(m.Settings, orgID)
+	return nil
+}
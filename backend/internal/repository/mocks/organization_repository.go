@@ -0,0 +1,108 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockOrganizationRepository is a mock implementation of OrganizationRepository for testing
+type MockOrganizationRepository struct {
+	IPAllowlists      map[int64][]string
+	InvitationDomains map[int64][]string
+	Settings          map[int64]map[models.OrgSettingKey]string
+
+	// Function hooks for custom behavior
+	GetIPAllowlistFunc func(ctx context.Context, orgID int64) ([]string, error)
+	GetSettingsFunc    func(ctx context.Context, orgID int64) (map[models.OrgSettingKey]string, error)
+}
+
+// NewMockOrganizationRepository creates a new mock organization repository with the default
+// organization
+func NewMockOrganizationRepository() *MockOrganizationRepository {
+	return &MockOrganizationRepository{
+		IPAllowlists:      map[int64][]string{1: {}},
+		InvitationDomains: map[int64][]string{1: {}},
+		Settings:          map[int64]map[models.OrgSettingKey]string{1: {}},
+	}
+}
+
+// GetIPAllowlist returns the organization's IP allowlist
+func (m *MockOrganizationRepository) GetIPAllowlist(ctx context.Context, orgID int64) ([]string, error) {
+	if m.GetIPAllowlistFunc != nil {
+		return m.GetIPAllowlistFunc(ctx, orgID)
+	}
+	allowlist, ok := m.IPAllowlists[orgID]
+	if !ok {
+		return nil, fmt.Errorf("organization not found")
+	}
+	return slices.Clone(allowlist), nil
+}
+
+// UpdateIPAllowlist replaces the organization's IP allowlist
+func (m *MockOrganizationRepository) UpdateIPAllowlist(ctx context.Context, orgID int64, allowlist []string) error {
+	if _, ok := m.IPAllowlists[orgID]; !ok {
+		return fmt.Errorf("organization not found")
+	}
+	m.IPAllowlists[orgID] = slices.Clone(allowlist)
+	return nil
+}
+
+// GetInvitationDomains returns the email domains the organization's invitations are limited to
+func (m *MockOrganizationRepository) GetInvitationDomains(ctx context.Context, orgID int64) ([]string, error) {
+	domains, ok := m.InvitationDomains[orgID]
+	if !ok {
+		return nil, fmt.Errorf("organization not found")
+	}
+	return slices.Clone(domains), nil
+}
+
+// UpdateInvitationDomains replaces the organization's invitation domains
+func (m *MockOrganizationRepository) UpdateInvitationDomains(ctx context.Context, orgID int64, domains []string) error {
+	if _, ok := m.InvitationDomains[orgID]; !ok {
+		return fmt.Errorf("organization not found")
+	}
+	m.InvitationDomains[orgID] = slices.Clone(domains)
+	return nil
+}
+
+// GetByInvitationDomain returns the lowest ID of the organizations whose invitations are limited
+// to domain
+func (m *MockOrganizationRepository) GetByInvitationDomain(ctx context.Context, domain string) (*int64, error) {
+	for _, orgID := range slices.Sorted(maps.Keys(m.InvitationDomains)) {
+		if slices.Contains(m.InvitationDomains[orgID], domain) {
+			return &orgID, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetSettings returns the settings the organization has changed
+func (m *MockOrganizationRepository) GetSettings(ctx context.Context, orgID int64) (map[models.OrgSettingKey]string, error) {
+	if m.GetSettingsFunc != nil {
+		return m.GetSettingsFunc(ctx, orgID)
+	}
+	settings, ok := m.Settings[orgID]
+	if !ok {
+		return map[models.OrgSettingKey]string{}, nil
+	}
+	return maps.Clone(settings), nil
+}
+
+// UpdateSettings saves changes to the organization's settings; nil values remove them
+func (m *MockOrganizationRepository) UpdateSettings(ctx context.Context, orgID int64, changes map[models.OrgSettingKey]*string, updatedByID int64) error {
+	if m.Settings[orgID] == nil {
+		m.Settings[orgID] = make(map[models.OrgSettingKey]string)
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(m.Settings[orgID], key)
+		} else {
+			m.Settings[orgID][key] = *value
+		}
+	}
+	return nil
+}
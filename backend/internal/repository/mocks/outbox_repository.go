@@ -0,0 +1,106 @@
+package mocks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockOutboxRepository is a mock implementation of OutboxRepository for testing
+type MockOutboxRepository struct {
+	Messages map[int64]*models.OutboxMessage
+	NextID   int64
+
+	// Function hooks for custom behavior
+	EnqueueFunc func(ctx context.Context, kind models.OutboxKind, payload any) error
+}
+
+// NewMockOutboxRepository creates a new mock outbox repository
+func NewMockOutboxRepository() *MockOutboxRepository {
+	return &MockOutboxRepository{
+		Messages: make(map[int64]*models.OutboxMessage),
+		NextID:   1,
+	}
+}
+
+func (m *MockOutboxRepository) Enqueue(ctx context.Context, kind models.OutboxKind, payload any) error {
+	if m.EnqueueFunc != nil {
+		return m.EnqueueFunc(ctx, kind, payload)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	m.Messages[m.NextID] = &models.OutboxMessage{
+		ID:            m.NextID,
+		Kind:          kind,
+		Payload:       body,
+		Status:        models.OutboxMessageStatusPending,
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	m.NextID++
+	return nil
+}
+
+func (m *MockOutboxRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.OutboxMessage, error) {
+	var due *models.OutboxMessage
+	for _, message := range m.Messages {
+		if message.Status != models.OutboxMessageStatusPending || message.NextAttemptAt.After(time.Now()) {
+			continue
+		}
+		if due == nil || message.ID < due.ID {
+			due = message
+		}
+	}
+	if due == nil {
+		return nil, nil
+	}
+	now := time.Now()
+	due.Status = models.OutboxMessageStatusSending
+	due.Attempts++
+	due.LastAttemptAt = &now
+	copied := *due
+	return &copied, nil
+}
+
+func (m *MockOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	if message, ok := m.Messages[id]; ok {
+		now := time.Now()
+		message.Status = models.OutboxMessageStatusSent
+		message.LastError = nil
+		message.SentAt = &now
+	}
+	return nil
+}
+
+func (m *MockOutboxRepository) ScheduleRetry(ctx context.Context, id int64, errMessage string, nextAttemptAt time.Time) error {
+	if message, ok := m.Messages[id]; ok {
+		message.Status = models.OutboxMessageStatusPending
+		message.LastError = &errMessage
+		message.NextAttemptAt = nextAttemptAt
+	}
+	return nil
+}
+
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id int64, errMessage string) error {
+	if message, ok := m.Messages[id]; ok {
+		message.Status = models.OutboxMessageStatusFailed
+		message.LastError = &errMessage
+	}
+	return nil
+}
+
+func (m *MockOutboxRepository) PurgeFinished(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	for id, message := range m.Messages {
+		finished := message.Status == models.OutboxMessageStatusSent || message.Status == models.OutboxMessageStatusFailed
+		if finished && message.CreatedAt.Before(before) {
+			delete(m.Messages, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
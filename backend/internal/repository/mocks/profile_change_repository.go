@@ -0,0 +1,125 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockProfileChangeRepository is a mock implementation of ProfileChangeRepository for testing.
+// Approved changes are applied to the users of the mock user repository it was created with.
+type MockProfileChangeRepository struct {
+	Requests map[int64]*models.ProfileChangeRequest
+	NextID   int64
+	userRepo *MockUserRepository
+}
+
+// NewMockProfileChangeRepository creates a new mock profile change repository
+func NewMockProfileChangeRepository(userRepo *MockUserRepository) *MockProfileChangeRepository {
+	return &MockProfileChangeRepository{
+		Requests: make(map[int64]*models.ProfileChangeRequest),
+		NextID:   1,
+		userRepo: userRepo,
+	}
+}
+
+func (m *MockProfileChangeRepository) Propose(ctx context.Context, userID int64, title, department *string) (*models.ProfileChangeRequest, error) {
+	now := time.Now()
+	for _, req := range m.Requests {
+		if req.UserID == userID && req.Status == models.ProfileChangeStatusPending {
+			req.Title, req.Department, req.UpdatedAt = title, department, now
+			return req, nil
+		}
+	}
+	req := &models.ProfileChangeRequest{
+		ID:         m.NextID,
+		UserID:     userID,
+		Title:      title,
+		Department: department,
+		Status:     models.ProfileChangeStatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if user, ok := m.userRepo.Users[userID]; ok {
+		req.OrgID = user.OrgID
+	}
+	m.NextID++
+	m.Requests[req.ID] = req
+	return req, nil
+}
+
+func (m *MockProfileChangeRepository) GetByID(ctx context.Context, id int64) (*models.ProfileChangeRequest, error) {
+	if req, ok := m.Requests[id]; ok {
+		return req, nil
+	}
+	return nil, nil
+}
+
+func (m *MockProfileChangeRepository) GetLatestByUser(ctx context.Context, userID int64) (*models.ProfileChangeRequest, error) {
+	var latest *models.ProfileChangeRequest
+	for _, req := range m.Requests {
+		if req.UserID == userID && (latest == nil || req.ID > latest.ID) {
+			latest = req
+		}
+	}
+	return latest, nil
+}
+
+func (m *MockProfileChangeRepository) List(ctx context.Context, status *models.ProfileChangeStatus, supervisorID *int64) ([]models.ProfileChangeRequest, error) {
+	requests := []models.ProfileChangeRequest{}
+	for _, req := range m.Requests {
+		if status != nil && req.Status != *status {
+			continue
+		}
+		user := m.userRepo.Users[req.UserID]
+		if supervisorID != nil && (user == nil || user.SupervisorID == nil || *user.SupervisorID != *supervisorID) {
+			continue
+		}
+		listed := *req
+		if user != nil {
+			listed.UserName = user.FirstName + " " + user.LastName
+			listed.CurrentTitle, listed.CurrentDepartment = user.Title, user.Department
+		}
+		requests = append(requests, listed)
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].ID < requests[j].ID })
+	return requests, nil
+}
+
+func (m *MockProfileChangeRepository) Approve(ctx context.Context, id int64, reviewerID int64) (*models.User, error) {
+	req, ok := m.Requests[id]
+	if !ok || req.Status != models.ProfileChangeStatusPending {
+		return nil, errors.New("pending profile change not found")
+	}
+	user, ok := m.userRepo.Users[req.UserID]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	if req.Title != nil {
+		user.Title = *req.Title
+	}
+	if req.Department != nil {
+		user.Department = *req.Department
+	}
+
+	now := time.Now()
+	req.Status = models.ProfileChangeStatusApproved
+	req.ReviewedByID = &reviewerID
+	req.ReviewedAt = &now
+	return user, nil
+}
+
+func (m *MockProfileChangeRepository) Reject(ctx context.Context, id int64, reviewerID int64) (*models.ProfileChangeRequest, error) {
+	req, ok := m.Requests[id]
+	if !ok || req.Status != models.ProfileChangeStatusPending {
+		return nil, errors.New("pending profile change not found")
+	}
+	now := time.Now()
+	req.Status = models.ProfileChangeStatusRejected
+	req.ReviewedByID = &reviewerID
+	req.ReviewedAt = &now
+	return req, nil
+}
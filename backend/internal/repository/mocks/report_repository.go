@@ -0,0 +1,73 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockReportRepository is a mock implementation of ReportRepository for testing. It returns the
+// canned rows for every query and records the queries it was asked.
+type MockReportRepository struct {
+	TimeOffUsageRows    []models.TimeOffUsageRow
+	ApprovalLatencyRows []models.ApprovalLatencyRow
+	MeetingLoadRows     []models.MeetingLoadRow
+	JiraThroughputRows  []models.JiraThroughputRow
+	HeadcountRows       []models.HeadcountRow
+	InactiveAccountRows []models.InactiveAccountRow
+
+	Queries []models.ReportQuery
+	Err     error // Returned by every report when set
+}
+
+// NewMockReportRepository creates a new mock report repository with no rows
+func NewMockReportRepository() *MockReportRepository {
+	return &MockReportRepository{}
+}
+
+func (m *MockReportRepository) record(q *models.ReportQuery) error {
+	m.Queries = append(m.Queries, *q)
+	return m.Err
+}
+
+func (m *MockReportRepository) TimeOffUsage(ctx context.Context, q *models.ReportQuery) ([]models.TimeOffUsageRow, error) {
+	if err := m.record(q); err != nil {
+		return nil, err
+	}
+	return m.TimeOffUsageRows, nil
+}
+
+func (m *MockReportRepository) ApprovalLatency(ctx context.Context, q *models.ReportQuery) ([]models.ApprovalLatencyRow, error) {
+	if err := m.record(q); err != nil {
+		return nil, err
+	}
+	return m.ApprovalLatencyRows, nil
+}
+
+func (m *MockReportRepository) MeetingLoad(ctx context.Context, q *models.ReportQuery) ([]models.MeetingLoadRow, error) {
+	if err := m.record(q); err != nil {
+		return nil, err
+	}
+	return m.MeetingLoadRows, nil
+}
+
+func (m *MockReportRepository) JiraThroughput(ctx context.Context, q *models.ReportQuery) ([]models.JiraThroughputRow, error) {
+	if err := m.record(q); err != nil {
+		return nil, err
+	}
+	return m.JiraThroughputRows, nil
+}
+
+func (m *MockReportRepository) Headcount(ctx context.Context, q *models.ReportQuery) ([]models.HeadcountRow, error) {
+	if err := m.record(q); err != nil {
+		return nil, err
+	}
+	return m.HeadcountRows, nil
+}
+
+func (m *MockReportRepository) InactiveAccounts(ctx context.Context, q *models.ReportQuery) ([]models.InactiveAccountRow, error) {
+	if err := m.record(q); err != nil {
+		return nil, err
+	}
+	return m.InactiveAccountRows, nil
+}
@@ -0,0 +1,111 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockSavedViewRepository is a mock implementation of SavedViewRepository for testing
+type MockSavedViewRepository struct {
+	Views  map[int64]*models.SavedView
+	NextID int64
+
+	// Function hooks for custom behavior
+	CreateFunc     func(ctx context.Context, userID int64, req *models.SavedViewRequest) (*models.SavedView, error)
+	GetByIDFunc    func(ctx context.Context, id int64) (*models.SavedView, error)
+	ListByUserFunc func(ctx context.Context, userID int64, resource *models.SavedViewResource) ([]models.SavedView, error)
+	UpdateFunc     func(ctx context.Context, id int64, req *models.SavedViewRequest) (*models.SavedView, error)
+	DeleteFunc     func(ctx context.Context, id int64) error
+}
+
+// NewMockSavedViewRepository creates a new mock saved view repository
+func NewMockSavedViewRepository() *MockSavedViewRepository {
+	return &MockSavedViewRepository{
+		Views:  make(map[int64]*models.SavedView),
+		NextID: 1,
+	}
+}
+
+// AddView adds a saved view to the mock repository
+func (m *MockSavedViewRepository) AddView(view *models.SavedView) {
+	m.Views[view.ID] = view
+	if view.ID >= m.NextID {
+		m.NextID = view.ID + 1
+	}
+}
+
+func (m *MockSavedViewRepository) Create(ctx context.Context, userID int64, req *models.SavedViewRequest) (*models.SavedView, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, userID, req)
+	}
+	now := time.Now()
+	view := &models.SavedView{
+		ID:        m.NextID,
+		UserID:    userID,
+		Resource:  req.Resource,
+		Name:      req.Name,
+		Filters:   req.Filters,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	m.NextID++
+	m.Views[view.ID] = view
+	return view, nil
+}
+
+func (m *MockSavedViewRepository) GetByID(ctx context.Context, id int64) (*models.SavedView, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	if view, ok := m.Views[id]; ok {
+		return view, nil
+	}
+	return nil, nil
+}
+
+func (m *MockSavedViewRepository) ListByUser(ctx context.Context, userID int64, resource *models.SavedViewResource) ([]models.SavedView, error) {
+	if m.ListByUserFunc != nil {
+		return m.ListByUserFunc(ctx, userID, resource)
+	}
+	views := []models.SavedView{}
+	for _, v := range m.Views {
+		if v.UserID == userID && (resource == nil || v.Resource == *resource) {
+			views = append(views, *v)
+		}
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Resource != views[j].Resource {
+			return views[i].Resource < views[j].Resource
+		}
+		return strings.ToLower(views[i].Name) < strings.ToLower(views[j].Name)
+	})
+	return views, nil
+}
+
+func (m *MockSavedViewRepository) Update(ctx context.Context, id int64, req *models.SavedViewRequest) (*models.SavedView, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, id, req)
+	}
+	view, ok := m.Views[id]
+	if !ok {
+		return nil, errors.New("saved view not found")
+	}
+	view.Resource = req.Resource
+	view.Name = req.Name
+	view.Filters = req.Filters
+	view.UpdatedAt = time.Now()
+	return view, nil
+}
+
+func (m *MockSavedViewRepository) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	delete(m.Views, id)
+	return nil
+}
@@ -0,0 +1,172 @@
+package mocks
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockSessionRepository is a mock implementation of SessionRepository for testing
+type MockSessionRepository struct {
+	Sessions map[int64]*models.UserSession
+	Logins   []models.UserLogin
+	Revoked  []models.RevokedToken
+	nextID   int64
+
+	// Function hooks for custom behavior
+	IsTokenRevokedFunc      func(ctx context.Context, tokenID string) (bool, error)
+	GetSubjectRevokedAtFunc func(ctx context.Context, subject string) (*time.Time, error)
+}
+
+// NewMockSessionRepository creates a new mock session repository
+func NewMockSessionRepository() *MockSessionRepository {
+	return &MockSessionRepository{
+		Sessions: make(map[int64]*models.UserSession),
+		nextID:   1,
+	}
+}
+
+// Touch creates the session on its first use and updates it after
+func (m *MockSessionRepository) Touch(ctx context.Context, session *models.UserSession) error {
+	now := time.Now()
+	for _, existing := range m.Sessions {
+		if existing.TokenID == session.TokenID {
+			existing.IPAddress = session.IPAddress
+			existing.UserAgent = session.UserAgent
+			existing.LastSeenAt = now
+			*session = *existing
+			return nil
+		}
+	}
+	created := *session
+	created.ID = m.nextID
+	created.CreatedAt = now
+	created.LastSeenAt = now
+	m.nextID++
+	m.Sessions[created.ID] = &created
+	m.Logins = append(m.Logins, models.UserLogin{
+		ID:        int64(len(m.Logins) + 1),
+		UserID:    created.UserID,
+		Provider:  created.Provider,
+		IPAddress: created.IPAddress,
+		UserAgent: created.UserAgent,
+		CreatedAt: now,
+	})
+	*session = created
+	return nil
+}
+
+// ListLogins retrieves a user's most recent sign-ins
+func (m *MockSessionRepository) ListLogins(ctx context.Context, userID int64, limit int) ([]models.UserLogin, error) {
+	logins := []models.UserLogin{}
+	for i := len(m.Logins) - 1; i >= 0 && len(logins) < limit; i-- {
+		if m.Logins[i].UserID == userID {
+			logins = append(logins, m.Logins[i])
+		}
+	}
+	return logins, nil
+}
+
+// GetByID retrieves a session, or nil if it doesn't exist
+func (m *MockSessionRepository) GetByID(ctx context.Context, id int64) (*models.UserSession, error) {
+	session, ok := m.Sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *session
+	return &copied, nil
+}
+
+// ListByUser retrieves a user's most recently used sessions
+func (m *MockSessionRepository) ListByUser(ctx context.Context, userID int64, limit int) ([]models.UserSession, error) {
+	sessions := []models.UserSession{}
+	for _, session := range m.Sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, *session)
+		}
+	}
+	slices.SortFunc(sessions, func(a, b models.UserSession) int {
+		return b.LastSeenAt.Compare(a.LastSeenAt)
+	})
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
+
+// Revoke records the revocation and marks the matching sessions revoked
+func (m *MockSessionRepository) Revoke(ctx context.Context, revoked *models.RevokedToken) error {
+	revoked.ID = int64(len(m.Revoked) + 1)
+	revoked.RevokedAt = time.Now()
+	m.Revoked = append(m.Revoked, *revoked)
+	for _, session := range m.Sessions {
+		if session.RevokedAt != nil {
+			continue
+		}
+		if (revoked.TokenID != nil && session.TokenID == *revoked.TokenID) ||
+			(revoked.TokenID == nil && revoked.UserID != nil && session.UserID == *revoked.UserID) {
+			revokedAt := revoked.RevokedAt
+			session.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+// IsTokenRevoked reports whether a token is on the denylist
+func (m *MockSessionRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	if m.IsTokenRevokedFunc != nil {
+		return m.IsTokenRevokedFunc(ctx, tokenID)
+	}
+	for _, revoked := range m.Revoked {
+		if revoked.TokenID != nil && *revoked.TokenID == tokenID && revoked.ExpiresAt.After(time.Now()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetSubjectRevokedAt returns when every token issued to a subject was last revoked
+func (m *MockSessionRepository) GetSubjectRevokedAt(ctx context.Context, subject string) (*time.Time, error) {
+	if m.GetSubjectRevokedAtFunc != nil {
+		return m.GetSubjectRevokedAtFunc(ctx, subject)
+	}
+	var latest *time.Time
+	for _, revoked := range m.Revoked {
+		if revoked.TokenID == nil && revoked.Subject != nil && *revoked.Subject == subject &&
+			revoked.ExpiresAt.After(time.Now()) && (latest == nil || revoked.RevokedAt.After(*latest)) {
+			revokedAt := revoked.RevokedAt
+			latest = &revokedAt
+		}
+	}
+	return latest, nil
+}
+
+// DeleteExpired removes expired revocations, sessions last used before their cutoff, and sign-ins
+// made before theirs
+func (m *MockSessionRepository) DeleteExpired(ctx context.Context, sessionsBefore, loginsBefore time.Time) (int64, error) {
+	var deleted int64
+	now := time.Now()
+	m.Revoked = slices.DeleteFunc(m.Revoked, func(revoked models.RevokedToken) bool {
+		if !revoked.ExpiresAt.After(now) {
+			deleted++
+			return true
+		}
+		return false
+	})
+	for id, session := range m.Sessions {
+		if session.LastSeenAt.Before(sessionsBefore) {
+			delete(m.Sessions, id)
+			deleted++
+		}
+	}
+	m.Logins = slices.DeleteFunc(m.Logins, func(login models.UserLogin) bool {
+		if login.CreatedAt.Before(loginsBefore) {
+			deleted++
+			return true
+		}
+		return false
+	})
+	return deleted, nil
+}
@@ -0,0 +1,170 @@
+package mocks
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockSkillRepository is a mock implementation of SkillRepository for testing
+type MockSkillRepository struct {
+	Skills       map[int64]*models.Skill
+	Levels       map[int64]map[int64]models.SkillLevel // userID -> skillID -> level
+	Users        map[int64]*models.User                // Used to fill in experts and squad members
+	Squads       map[int64]*models.Squad
+	SquadMembers map[int64][]int64 // squadID -> user IDs
+	nextID       int64
+}
+
+// NewMockSkillRepository creates a new mock skill repository
+func NewMockSkillRepository() *MockSkillRepository {
+	return &MockSkillRepository{
+		Skills:       make(map[int64]*models.Skill),
+		Levels:       make(map[int64]map[int64]models.SkillLevel),
+		Users:        make(map[int64]*models.User),
+		Squads:       make(map[int64]*models.Squad),
+		SquadMembers: make(map[int64][]int64),
+		nextID:       1,
+	}
+}
+
+func (m *MockSkillRepository) GetAll(ctx context.Context) ([]models.Skill, error) {
+	skills := []models.Skill{}
+	for _, skill := range m.Skills {
+		skills = append(skills, *skill)
+	}
+	slices.SortFunc(skills, func(a, b models.Skill) int {
+		return cmp.Or(cmp.Compare(a.Category, b.Category), cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)))
+	})
+	return skills, nil
+}
+
+func (m *MockSkillRepository) GetByID(ctx context.Context, id int64) (*models.Skill, error) {
+	return m.Skills[id], nil
+}
+
+func (m *MockSkillRepository) GetByName(ctx context.Context, name string) (*models.Skill, error) {
+	for _, skill := range m.Skills {
+		if strings.EqualFold(skill.Name, name) {
+			return skill, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockSkillRepository) Create(ctx context.Context, req *models.SkillRequest) (*models.Skill, error) {
+	now := time.Now()
+	skill := &models.Skill{ID: m.nextID, Name: req.Name, Category: req.Category, CreatedAt: now, UpdatedAt: now}
+	m.nextID++
+	m.Skills[skill.ID] = skill
+	return skill, nil
+}
+
+func (m *MockSkillRepository) Update(ctx context.Context, id int64, req *models.SkillRequest) (*models.Skill, error) {
+	skill, ok := m.Skills[id]
+	if !ok {
+		return nil, nil
+	}
+	skill.Name = req.Name
+	skill.Category = req.Category
+	skill.UpdatedAt = time.Now()
+	return skill, nil
+}
+
+func (m *MockSkillRepository) Delete(ctx context.Context, id int64) error {
+	if _, ok := m.Skills[id]; !ok {
+		return fmt.Errorf("skill not found")
+	}
+	delete(m.Skills, id)
+	for _, levels := range m.Levels {
+		delete(levels, id)
+	}
+	return nil
+}
+
+func (m *MockSkillRepository) GetUserSkills(ctx context.Context, userID int64) ([]models.UserSkill, error) {
+	skills := []models.UserSkill{}
+	for skillID, level := range m.Levels[userID] {
+		if skill, ok := m.Skills[skillID]; ok {
+			skills = append(skills, models.UserSkill{SkillID: skillID, Name: skill.Name, Category: skill.Category, Level: level})
+		}
+	}
+	slices.SortFunc(skills, func(a, b models.UserSkill) int {
+		return cmp.Or(cmp.Compare(b.Level, a.Level), cmp.Compare(a.Name, b.Name))
+	})
+	return skills, nil
+}
+
+func (m *MockSkillRepository) SetUserSkills(ctx context.Context, userID int64, skills []models.UserSkillLevel) error {
+	levels := make(map[int64]models.SkillLevel, len(skills))
+	for _, s := range skills {
+		levels[s.SkillID] = s.Level
+	}
+	m.Levels[userID] = levels
+	return nil
+}
+
+func (m *MockSkillRepository) FindExperts(ctx context.Context, query string, minLevel models.SkillLevel) ([]models.SkillExpert, error) {
+	experts := []models.SkillExpert{}
+	for userID, levels := range m.Levels {
+		user, ok := m.Users[userID]
+		if !ok || !user.IsActive {
+			continue
+		}
+		for skillID, level := range levels {
+			skill, ok := m.Skills[skillID]
+			if !ok || level < minLevel || !strings.Contains(strings.ToLower(skill.Name), strings.ToLower(query)) {
+				continue
+			}
+			experts = append(experts, models.SkillExpert{
+				UserID: user.ID, FirstName: user.FirstName, LastName: user.LastName,
+				SkillID: skill.ID, SkillName: skill.Name, Level: level,
+			})
+		}
+	}
+	slices.SortFunc(experts, func(a, b models.SkillExpert) int {
+		return cmp.Or(cmp.Compare(b.Level, a.Level), cmp.Compare(a.LastName, b.LastName), cmp.Compare(a.SkillID, b.SkillID))
+	})
+	return experts, nil
+}
+
+func (m *MockSkillRepository) GetSquadCoverage(ctx context.Context, squadID int64) (*models.SquadSkillCoverage, error) {
+	squad, ok := m.Squads[squadID]
+	if !ok {
+		return nil, nil
+	}
+	coverage := &models.SquadSkillCoverage{SquadID: squadID, SquadName: squad.Name, Skills: []models.SkillCoverage{}}
+	var members []int64
+	for _, userID := range m.SquadMembers[squadID] {
+		if user, ok := m.Users[userID]; ok && user.IsActive {
+			members = append(members, userID)
+		}
+	}
+	coverage.MemberCount = len(members)
+
+	skills, _ := m.GetAll(ctx)
+	for _, skill := range skills {
+		c := models.SkillCoverage{SkillID: skill.ID, Name: skill.Name, Category: skill.Category}
+		for _, userID := range members {
+			level, ok := m.Levels[userID][skill.ID]
+			if !ok {
+				continue
+			}
+			c.Members++
+			if level >= models.SkillLevelAdvanced {
+				c.Advanced++
+			}
+			c.TopLevel = max(c.TopLevel, level)
+		}
+		coverage.Skills = append(coverage.Skills, c)
+	}
+	slices.SortStableFunc(coverage.Skills, func(a, b models.SkillCoverage) int {
+		return cmp.Or(cmp.Compare(a.Advanced, b.Advanced), cmp.Compare(a.Members, b.Members))
+	})
+	return coverage, nil
+}
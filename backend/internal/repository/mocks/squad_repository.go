@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"slices"
 
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 )
@@ -22,6 +23,7 @@ type MockSquadRepository struct {
 	DeleteFunc              func(ctx context.Context, id int64) error
 	SetUserSquadsFunc       func(ctx context.Context, userID int64, squadIDs []int64) error
 	GetUsersBySquadIDFunc   func(ctx context.Context, squadID int64) ([]models.User, error)
+	SetLeadFunc             func(ctx context.Context, squadID, userID int64) error
 }
 
 // NewMockSquadRepository creates a new mock squad repository
@@ -151,6 +153,47 @@ func (m *MockSquadRepository) SetUserSquads(ctx context.Context, userID int64, s
 	return nil
 }
 
+func (m *MockSquadRepository) SetLead(ctx context.Context, squadID, userID int64) error {
+	if m.SetLeadFunc != nil {
+		return m.SetLeadFunc(ctx, squadID, userID)
+	}
+	if !slices.Contains(m.UserSquads[userID], squadID) {
+		m.UserSquads[userID] = append(m.UserSquads[userID], squadID)
+	}
+	if squad, ok := m.Squads[squadID]; ok {
+		squad.LeadID = &userID
+	}
+	return nil
+}
+
+func (m *MockSquadRepository) ClearLead(ctx context.Context, squadID int64) error {
+	if squad, ok := m.Squads[squadID]; ok {
+		squad.LeadID = nil
+	}
+	return nil
+}
+
+func (m *MockSquadRepository) GetLedSquadIDs(ctx context.Context, userID int64) ([]int64, error) {
+	var squadIDs []int64
+	for _, squad := range m.Squads {
+		if squad.LeadID != nil && *squad.LeadID == userID {
+			squadIDs = append(squadIDs, squad.ID)
+		}
+	}
+	slices.Sort(squadIDs)
+	return squadIDs, nil
+}
+
+func (m *MockSquadRepository) IsSquadLeadOf(ctx context.Context, leadID, memberID int64) (bool, error) {
+	ledSquadIDs, _ := m.GetLedSquadIDs(ctx, leadID)
+	for _, squadID := range m.UserSquads[memberID] {
+		if slices.Contains(ledSquadIDs, squadID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // AddSquad is a helper method for setting up test data
 func (m *MockSquadRepository) AddSquad(squad *models.Squad) {
 	m.Squads[squad.ID] = squad
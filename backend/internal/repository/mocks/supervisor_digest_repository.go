@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockSupervisorDigestRepository is a mock implementation of SupervisorDigestRepository for testing
+type MockSupervisorDigestRepository struct {
+	Queued map[string]*models.SupervisorDigestEmailPayload // By supervisor, frequency, and period
+
+	// Function hooks for custom behavior
+	QueueFunc func(ctx context.Context, payload *models.SupervisorDigestEmailPayload) (bool, error)
+}
+
+// NewMockSupervisorDigestRepository creates a new mock supervisor digest repository
+func NewMockSupervisorDigestRepository() *MockSupervisorDigestRepository {
+	return &MockSupervisorDigestRepository{
+		Queued: make(map[string]*models.SupervisorDigestEmailPayload),
+	}
+}
+
+func (m *MockSupervisorDigestRepository) Queue(ctx context.Context, payload *models.SupervisorDigestEmailPayload) (bool, error) {
+	if m.QueueFunc != nil {
+		return m.QueueFunc(ctx, payload)
+	}
+	key := fmt.Sprintf("%d/%s/%s", payload.SupervisorID, payload.Digest.Frequency, payload.Digest.PeriodStart.Format("2006-01-02"))
+	if _, ok := m.Queued[key]; ok {
+		return false, nil
+	}
+	m.Queued[key] = payload
+	return true, nil
+}
@@ -0,0 +1,140 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockTaskCommentRepository is a mock implementation of TaskCommentRepository for testing
+type MockTaskCommentRepository struct {
+	Comments       map[int64]*models.TaskComment
+	Activity       []models.TaskActivity
+	NextID         int64
+	NextActivityID int64
+
+	// Function hooks for custom behavior
+	CreateFunc             func(ctx context.Context, taskID, authorID int64, body string) (*models.TaskComment, error)
+	GetByIDFunc            func(ctx context.Context, id int64) (*models.TaskComment, error)
+	ListByTaskFunc         func(ctx context.Context, taskID int64) ([]models.TaskComment, error)
+	UpdateFunc             func(ctx context.Context, id int64, body string) (*models.TaskComment, error)
+	DeleteFunc             func(ctx context.Context, id int64) error
+	RecordStatusChangeFunc func(ctx context.Context, taskID, actorID int64, from, to models.TaskStatus) (*models.TaskActivity, error)
+	ListActivityFunc       func(ctx context.Context, taskID int64) ([]models.TaskActivity, error)
+}
+
+// NewMockTaskCommentRepository creates a new mock task comment repository
+func NewMockTaskCommentRepository() *MockTaskCommentRepository {
+	return &MockTaskCommentRepository{
+		Comments:       make(map[int64]*models.TaskComment),
+		NextID:         1,
+		NextActivityID: 1,
+	}
+}
+
+func (m *MockTaskCommentRepository) Create(ctx context.Context, taskID, authorID int64, body string) (*models.TaskComment, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, taskID, authorID, body)
+	}
+	now := time.Now()
+	comment := &models.TaskComment{
+		ID:        m.NextID,
+		TaskID:    taskID,
+		AuthorID:  authorID,
+		Body:      body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	m.NextID++
+	m.Comments[comment.ID] = comment
+	return comment, nil
+}
+
+func (m *MockTaskCommentRepository) GetByID(ctx context.Context, id int64) (*models.TaskComment, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	if comment, ok := m.Comments[id]; ok {
+		return comment, nil
+	}
+	return nil, nil
+}
+
+func (m *MockTaskCommentRepository) ListByTask(ctx context.Context, taskID int64) ([]models.TaskComment, error) {
+	if m.ListByTaskFunc != nil {
+		return m.ListByTaskFunc(ctx, taskID)
+	}
+	comments := []models.TaskComment{}
+	for _, c := range m.Comments {
+		if c.TaskID == taskID {
+			comments = append(comments, *c)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].ID < comments[j].ID })
+	return comments, nil
+}
+
+func (m *MockTaskCommentRepository) Update(ctx context.Context, id int64, body string) (*models.TaskComment, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, id, body)
+	}
+	comment, ok := m.Comments[id]
+	if !ok {
+		return nil, errors.New("task comment not found")
+	}
+	comment.Body = body
+	comment.UpdatedAt = time.Now()
+	return comment, nil
+}
+
+func (m *MockTaskCommentRepository) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	if _, ok := m.Comments[id]; !ok {
+		return errors.New("task comment not found")
+	}
+	delete(m.Comments, id)
+	return nil
+}
+
+func (m *MockTaskCommentRepository) RecordStatusChange(ctx context.Context, taskID, actorID int64, from, to models.TaskStatus) (*models.TaskActivity, error) {
+	if m.RecordStatusChangeFunc != nil {
+		return m.RecordStatusChangeFunc(ctx, taskID, actorID, from, to)
+	}
+	activity := models.TaskActivity{
+		ID:         m.NextActivityID,
+		TaskID:     taskID,
+		ActorID:    &actorID,
+		FromStatus: from,
+		ToStatus:   to,
+		CreatedAt:  time.Now(),
+	}
+	m.NextActivityID++
+	m.Activity = append(m.Activity, activity)
+	return &activity, nil
+}
+
+func (m *MockTaskCommentRepository) ListActivity(ctx context.Context, taskID int64) ([]models.TaskActivity, error) {
+	if m.ListActivityFunc != nil {
+		return m.ListActivityFunc(ctx, taskID)
+	}
+	activity := []models.TaskActivity{}
+	for _, a := range m.Activity {
+		if a.TaskID == taskID {
+			activity = append(activity, a)
+		}
+	}
+	return activity, nil
+}
+
+// AddComment is a helper method for setting up test data
+func (m *MockTaskCommentRepository) AddComment(comment *models.TaskComment) {
+	m.Comments[comment.ID] = comment
+	if comment.ID >= m.NextID {
+		m.NextID = comment.ID + 1
+	}
+}
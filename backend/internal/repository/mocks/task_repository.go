@@ -3,6 +3,8 @@ package mocks
 import (
 	"context"
 	"errors"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/smith-dallin/manager-dashboard/internal/models"
@@ -12,17 +14,24 @@ import (
 type MockTaskRepository struct {
 	Tasks  map[int64]*models.Task
 	NextID int64
+	// Soft-deleted tasks and when they were deleted
+	Deleted   map[int64]*models.Task
+	DeletedAt map[int64]time.Time
 
 	// Function hooks for custom behavior
 	CreateFunc                    func(ctx context.Context, req *models.CreateTaskRequest, createdByID int64) (*models.Task, error)
+	CreateManyFunc                func(ctx context.Context, reqs []models.CreateTaskRequest, createdByID int64) ([]models.Task, error)
+	ReassignFunc                  func(ctx context.Context, req *models.ReassignTasksRequest, actor *models.User) (*models.BulkTaskResult, error)
 	GetByIDFunc                   func(ctx context.Context, id int64) (*models.Task, error)
 	UpdateFunc                    func(ctx context.Context, id int64, req *models.UpdateTaskRequest) (*models.Task, error)
+	AdvanceRecurrenceFunc         func(ctx context.Context, id int64) (*models.Task, *models.Task, error)
 	DeleteFunc                    func(ctx context.Context, id int64) error
 	GetByDateRangeFunc            func(ctx context.Context, userID int64, start, end time.Time) ([]models.Task, error)
 	GetByDateRangeForSquadFunc    func(ctx context.Context, squadID int64, start, end time.Time) ([]models.Task, error)
 	GetByDateRangeForDepartmentFunc func(ctx context.Context, department string, start, end time.Time) ([]models.Task, error)
 	GetAllByDateRangeFunc         func(ctx context.Context, start, end time.Time) ([]models.Task, error)
 	GetVisibleTasksFunc           func(ctx context.Context, user *models.User, start, end time.Time) ([]models.Task, error)
+	ListFunc                      func(ctx context.Context, user *models.User, filter models.TaskFilter) ([]models.Task, error)
 }
 
 // NewMockTaskRepository creates a new mock task repository
@@ -43,6 +52,8 @@ func (m *MockTaskRepository) Create(ctx context.Context, req *models.CreateTaskR
 		Description:    req.Description,
 		DueDate:        req.DueDate,
 		Status:         models.TaskStatusPending,
+		Priority:       req.Priority,
+		Labels:         req.Labels,
 		AssignmentType: req.AssignmentType,
 		CreatedByID:    createdByID,
 		CreatedAt:      time.Now(),
@@ -57,11 +68,64 @@ func (m *MockTaskRepository) Create(ctx context.Context, req *models.CreateTaskR
 	if req.AssignedDepartment != nil {
 		task.AssignedDepartment = req.AssignedDepartment
 	}
+	if req.RecurrenceType != nil {
+		task.RecurrenceType = req.RecurrenceType
+		task.RecurrenceInterval = 1
+		if req.RecurrenceInterval != nil {
+			task.RecurrenceInterval = *req.RecurrenceInterval
+		}
+		task.RecurrenceEndDate = req.RecurrenceEndDate
+	}
 	m.NextID++
 	m.Tasks[task.ID] = task
 	return task, nil
 }
 
+func (m *MockTaskRepository) CreateMany(ctx context.Context, reqs []models.CreateTaskRequest, createdByID int64) ([]models.Task, error) {
+	if m.CreateManyFunc != nil {
+		return m.CreateManyFunc(ctx, reqs, createdByID)
+	}
+	tasks := make([]models.Task, 0, len(reqs))
+	for i := range reqs {
+		task, err := m.Create(ctx, &reqs[i], createdByID)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, nil
+}
+
+func (m *MockTaskRepository) Reassign(ctx context.Context, req *models.ReassignTasksRequest, actor *models.User) (*models.BulkTaskResult, error) {
+	if m.ReassignFunc != nil {
+		return m.ReassignFunc(ctx, req, actor)
+	}
+	var open []models.Task
+	for _, task := range m.Tasks {
+		if task.AssignmentType != models.AssignmentTypeUser || task.AssignedUserID == nil || *task.AssignedUserID != req.FromUserID || !task.IsOpen() {
+			continue
+		}
+		if len(req.TaskIDs) > 0 && !slices.Contains(req.TaskIDs, task.ID) {
+			continue
+		}
+		open = append(open, *task)
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].ID < open[j].ID })
+
+	if errs := models.ReassignmentErrors(open, req.TaskIDs, actor); len(errs) > 0 {
+		return &models.BulkTaskResult{Tasks: []models.Task{}, Errors: errs}, nil
+	}
+	moved := make([]models.Task, 0, len(open))
+	for _, task := range open {
+		stored := m.Tasks[task.ID]
+		toUserID := req.ToUserID
+		stored.AssignedUserID = &toUserID
+		stored.UpdatedAt = time.Now()
+		moved = append(moved, *stored)
+	}
+	return &models.BulkTaskResult{Tasks: moved, Errors: []models.BulkTaskError{}}, nil
+}
+
 func (m *MockTaskRepository) GetByID(ctx context.Context, id int64) (*models.Task, error) {
 	if m.GetByIDFunc != nil {
 		return m.GetByIDFunc(ctx, id)
@@ -89,10 +153,47 @@ func (m *MockTaskRepository) Update(ctx context.Context, id int64, req *models.U
 	if req.Status != nil {
 		task.Status = *req.Status
 	}
+	if req.Priority != nil {
+		task.Priority = *req.Priority
+	}
+	if req.Labels != nil {
+		task.Labels = *req.Labels
+	}
+	if req.RecurrenceType != nil {
+		task.RecurrenceType = req.RecurrenceType
+	}
+	if req.RecurrenceInterval != nil {
+		task.RecurrenceInterval = *req.RecurrenceInterval
+	}
+	if req.RecurrenceEndDate != nil {
+		task.RecurrenceEndDate = req.RecurrenceEndDate
+	}
 	task.UpdatedAt = time.Now()
 	return task, nil
 }
 
+func (m *MockTaskRepository) AdvanceRecurrence(ctx context.Context, id int64) (*models.Task, *models.Task, error) {
+	if m.AdvanceRecurrenceFunc != nil {
+		return m.AdvanceRecurrenceFunc(ctx, id)
+	}
+	task, ok := m.Tasks[id]
+	if !ok || task.RecurrenceType == nil {
+		return nil, nil, nil
+	}
+	next := task.NextInstance()
+	task.RecurrenceType = nil
+	task.RecurrenceEndDate = nil
+	task.UpdatedAt = time.Now()
+	if next != nil {
+		next.ID = m.NextID
+		next.CreatedAt = time.Now()
+		next.UpdatedAt = time.Now()
+		m.NextID++
+		m.Tasks[next.ID] = next
+	}
+	return task, next, nil
+}
+
 func (m *MockTaskRepository) Delete(ctx context.Context, id int64) error {
 	if m.DeleteFunc != nil {
 		return m.DeleteFunc(ctx, id)
@@ -100,10 +201,39 @@ func (m *MockTaskRepository) Delete(ctx context.Context, id int64) error {
 	if _, ok := m.Tasks[id]; !ok {
 		return errors.New("task not found")
 	}
+	if m.Deleted == nil {
+		m.Deleted = make(map[int64]*models.Task)
+		m.DeletedAt = make(map[int64]time.Time)
+	}
+	m.Deleted[id] = m.Tasks[id]
+	m.DeletedAt[id] = time.Now()
 	delete(m.Tasks, id)
 	return nil
 }
 
+func (m *MockTaskRepository) Restore(ctx context.Context, id int64) (*models.Task, error) {
+	task, ok := m.Deleted[id]
+	if !ok {
+		return nil, errors.New("deleted task not found")
+	}
+	m.Tasks[id] = task
+	delete(m.Deleted, id)
+	delete(m.DeletedAt, id)
+	return task, nil
+}
+
+func (m *MockTaskRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	for id, deletedAt := range m.DeletedAt {
+		if deletedAt.Before(before) {
+			delete(m.Deleted, id)
+			delete(m.DeletedAt, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
 func (m *MockTaskRepository) GetByDateRange(ctx context.Context, userID int64, start, end time.Time) ([]models.Task, error) {
 	if m.GetByDateRangeFunc != nil {
 		return m.GetByDateRangeFunc(ctx, userID, start, end)
@@ -176,6 +306,79 @@ func (m *MockTaskRepository) GetVisibleTasks(ctx context.Context, user *models.U
 	return tasks, nil
 }
 
+// taskPriorityRank orders priorities from least to most urgent, matching the database sort
+var taskPriorityRank = map[models.TaskPriority]int{
+	models.TaskPriorityLow:    1,
+	models.TaskPriorityMedium: 2,
+	models.TaskPriorityHigh:   3,
+	models.TaskPriorityUrgent: 4,
+}
+
+func (m *MockTaskRepository) CountOpenByAssigneeIDs(ctx context.Context, userIDs []int64) (map[int64]int, error) {
+	result := make(map[int64]int)
+	for _, task := range m.Tasks {
+		open := task.Status == models.TaskStatusPending || task.Status == models.TaskStatusInProgress
+		if open && task.AssignedUserID != nil && slices.Contains(userIDs, *task.AssignedUserID) {
+			result[*task.AssignedUserID]++
+		}
+	}
+	return result, nil
+}
+
+func (m *MockTaskRepository) List(ctx context.Context, user *models.User, filter models.TaskFilter) ([]models.Task, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, user, filter)
+	}
+	today := time.Now().Truncate(24 * time.Hour)
+	tasks := []models.Task{}
+	for _, task := range m.Tasks {
+		// Visibility is simplified to admins, creators, and direct assignees
+		assignee := task.AssignedUserID != nil && *task.AssignedUserID == user.ID
+		if !user.IsAdmin() && task.CreatedByID != user.ID && !assignee {
+			continue
+		}
+		if filter.Status != nil && task.Status != *filter.Status {
+			continue
+		}
+		if filter.Priority != nil && task.Priority != *filter.Priority {
+			continue
+		}
+		if filter.Label != nil && !slices.Contains(task.Labels, *filter.Label) {
+			continue
+		}
+		if filter.AssigneeID != nil && (task.AssignedUserID == nil || *task.AssignedUserID != *filter.AssigneeID) {
+			continue
+		}
+		if filter.Overdue && (!task.DueDate.Before(today) || task.Status == models.TaskStatusCompleted || task.Status == models.TaskStatusCancelled) {
+			continue
+		}
+		tasks = append(tasks, *task)
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		var less, equal bool
+		switch filter.SortBy {
+		case models.TaskSortPriority:
+			less, equal = taskPriorityRank[a.Priority] < taskPriorityRank[b.Priority], a.Priority == b.Priority
+		case models.TaskSortCreatedAt:
+			less, equal = a.CreatedAt.Before(b.CreatedAt), a.CreatedAt.Equal(b.CreatedAt)
+		case models.TaskSortUpdatedAt:
+			less, equal = a.UpdatedAt.Before(b.UpdatedAt), a.UpdatedAt.Equal(b.UpdatedAt)
+		default:
+			less, equal = a.DueDate.Before(b.DueDate), a.DueDate.Equal(b.DueDate)
+		}
+		if equal {
+			less = a.ID < b.ID
+		}
+		if filter.SortAsc {
+			return less
+		}
+		return !less
+	})
+	return tasks, nil
+}
+
 // AddTask is a helper method for setting up test data
 func (m *MockTaskRepository) AddTask(task *models.Task) {
 	m.Tasks[task.ID] = task
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/smith-dallin/manager-dashboard/internal/models"
@@ -18,7 +19,8 @@ type MockTimeOffRepository struct {
 	GetByIDFunc                      func(ctx context.Context, id int64) (*models.TimeOffRequest, error)
 	GetByIDWithUserFunc              func(ctx context.Context, id int64) (*models.TimeOffRequest, error)
 	GetByUserIDFunc                  func(ctx context.Context, userID int64, status *models.TimeOffStatus) ([]models.TimeOffRequest, error)
-	GetVisibleRequestsFunc           func(ctx context.Context, user *models.User, status *models.TimeOffStatus) ([]models.TimeOffRequest, error)
+	GetVisibleRequestsFunc           func(ctx context.Context, user *models.User, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error)
+	GetAllRequestsFunc               func(ctx context.Context, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error)
 	GetPendingForSupervisorFunc      func(ctx context.Context, supervisorID int64) ([]models.TimeOffRequest, error)
 	GetAllPendingFunc                func(ctx context.Context) ([]models.TimeOffRequest, error)
 	ReviewFunc                       func(ctx context.Context, id int64, reviewerID int64, req *models.ReviewTimeOffRequestInput) error
@@ -95,17 +97,18 @@ func (m *MockTimeOffRepository) GetByUserID(ctx context.Context, userID int64, s
 	return requests, nil
 }
 
-func (m *MockTimeOffRepository) GetVisibleRequests(ctx context.Context, user *models.User, status *models.TimeOffStatus) ([]models.TimeOffRequest, error) {
+func (m *MockTimeOffRepository) GetVisibleRequests(ctx context.Context, user *models.User, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error) {
 	if m.GetVisibleRequestsFunc != nil {
-		return m.GetVisibleRequestsFunc(ctx, user, status)
+		return m.GetVisibleRequestsFunc(ctx, user, filter)
 	}
-	var requests []models.TimeOffRequest
-	for _, req := range m.Requests {
-		if status == nil || req.Status == *status {
-			requests = append(requests, *req)
-		}
+	return m.filterRequests(filter), nil
+}
+
+func (m *MockTimeOffRepository) GetAllRequests(ctx context.Context, filter models.TimeOffFilter) (*models.TimeOffRequestPage, error) {
+	if m.GetAllRequestsFunc != nil {
+		return m.GetAllRequestsFunc(ctx, filter)
 	}
-	return requests, nil
+	return m.filterRequests(filter), nil
 }
 
 func (m *MockTimeOffRepository) GetPendingForSupervisor(ctx context.Context, supervisorID int64) ([]models.TimeOffRequest, error) {
@@ -134,6 +137,16 @@ func (m *MockTimeOffRepository) GetAllPending(ctx context.Context) ([]models.Tim
 	return requests, nil
 }
 
+func (m *MockTimeOffRepository) CountPendingByUserIDs(ctx context.Context, userIDs []int64) (map[int64]int, error) {
+	result := make(map[int64]int)
+	for _, req := range m.Requests {
+		if req.Status == models.TimeOffStatusPending && slices.Contains(userIDs, req.UserID) {
+			result[req.UserID]++
+		}
+	}
+	return result, nil
+}
+
 func (m *MockTimeOffRepository) Review(ctx context.Context, id int64, reviewerID int64, req *models.ReviewTimeOffRequestInput) error {
 	if m.ReviewFunc != nil {
 		return m.ReviewFunc(ctx, id, reviewerID, req)
@@ -244,6 +257,24 @@ func (m *MockTimeOffRepository) GetAllApproved(ctx context.Context) ([]models.Ti
 	return requests, nil
 }
 
+// filterRequests applies the non-pagination parts of a filter to the stored requests
+func (m *MockTimeOffRepository) filterRequests(filter models.TimeOffFilter) *models.TimeOffRequestPage {
+	requests := []models.TimeOffRequest{}
+	for _, req := range m.Requests {
+		if filter.UserID != nil && req.UserID != *filter.UserID {
+			continue
+		}
+		if filter.Status != nil && req.Status != *filter.Status {
+			continue
+		}
+		if filter.RequestType != nil && req.RequestType != *filter.RequestType {
+			continue
+		}
+		requests = append(requests, *req)
+	}
+	return &models.TimeOffRequestPage{Data: requests}
+}
+
 // AddRequest is a helper method for setting up test data
 func (m *MockTimeOffRepository) AddRequest(req *models.TimeOffRequest) {
 	m.Requests[req.ID] = req
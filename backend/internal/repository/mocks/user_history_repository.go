@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockUserHistoryRepository is a mock implementation of UserHistoryRepository for testing
+type MockUserHistoryRepository struct {
+	Entries []models.UserHistoryEntry // Oldest first
+	nextID  int64
+}
+
+// NewMockUserHistoryRepository creates a new mock user history repository
+func NewMockUserHistoryRepository() *MockUserHistoryRepository {
+	return &MockUserHistoryRepository{nextID: 1}
+}
+
+func (m *MockUserHistoryRepository) Record(ctx context.Context, source models.UserHistorySource, changedByID *int64, entries []models.UserHistoryEntry) error {
+	for _, e := range entries {
+		e.ID = m.nextID
+		e.Source = source
+		e.ChangedByID = changedByID
+		e.CreatedAt = time.Now()
+		m.nextID++
+		m.Entries = append(m.Entries, e)
+	}
+	return nil
+}
+
+func (m *MockUserHistoryRepository) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]models.UserHistoryEntry, int, error) {
+	var entries []models.UserHistoryEntry
+	for i := len(m.Entries) - 1; i >= 0; i-- {
+		if m.Entries[i].UserID == userID {
+			entries = append(entries, m.Entries[i])
+		}
+	}
+	total := len(entries)
+	start := min(offset, total)
+	end := min(start+limit, total)
+	return entries[start:end], total, nil
+}
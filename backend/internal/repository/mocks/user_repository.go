@@ -1,7 +1,12 @@
 package mocks
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 )
@@ -12,27 +17,39 @@ type MockUserRepository struct {
 	ByAuth0ID   map[string]*models.User
 	ByEmail     map[string]*models.User
 	Departments map[string]bool
+	// Soft-deleted users, which can be restored, and whether each was active when deleted
+	Deleted            map[int64]*models.User
+	activeBeforeDelete map[int64]bool
 
 	// Function hooks for custom behavior
-	GetByIDFunc                        func(ctx context.Context, id int64) (*models.User, error)
-	GetByAuth0IDFunc                   func(ctx context.Context, auth0ID string) (*models.User, error)
-	GetByEmailFunc                     func(ctx context.Context, email string) (*models.User, error)
-	GetAllFunc                         func(ctx context.Context) ([]models.User, error)
-	CreateFunc                         func(ctx context.Context, req *models.CreateUserRequest, auth0ID string) (*models.User, error)
-	UpdateFunc                         func(ctx context.Context, id int64, req *models.UpdateUserRequest) (*models.User, error)
-	DeleteFunc                         func(ctx context.Context, id int64) error
-	GetDirectReportsBySupervisorIDFunc func(ctx context.Context, supervisorID int64) ([]models.User, error)
-	GetAllSupervisorsFunc              func(ctx context.Context) ([]models.User, error)
-	GetAllDepartmentsFunc              func(ctx context.Context) ([]string, error)
-	ClearDepartmentFunc                func(ctx context.Context, department string) error
-	UpdateJiraSettingsFunc             func(ctx context.Context, id int64, req *models.UpdateJiraSettingsRequest) error
-	ClearJiraSettingsFunc              func(ctx context.Context, id int64) error
-	UpdateJiraAccountIDFunc            func(ctx context.Context, id int64, jiraAccountID *string) error
-	SaveJiraOAuthTokensFunc            func(ctx context.Context, id int64, tokens *models.JiraOAuthTokens) error
-	DeactivateFunc                     func(ctx context.Context, id int64) error
-	ReactivateFunc                     func(ctx context.Context, id int64) error
-	RenameDepartmentFunc               func(ctx context.Context, oldName, newName string) error
-	GetUsersByDepartmentFunc           func(ctx context.Context, department string) ([]models.User, error)
+	GetByIDFunc                         func(ctx context.Context, id int64) (*models.User, error)
+	GetByAuth0IDFunc                    func(ctx context.Context, auth0ID string) (*models.User, error)
+	GetByEmailFunc                      func(ctx context.Context, email string) (*models.User, error)
+	GetAllFunc                          func(ctx context.Context) ([]models.User, error)
+	CreateFunc                          func(ctx context.Context, req *models.CreateUserRequest, auth0ID string) (*models.User, error)
+	UpdateFunc                          func(ctx context.Context, id int64, req *models.UpdateUserRequest) (*models.User, error)
+	SetAvatarFunc                       func(ctx context.Context, id int64, avatarURL string, variants models.AvatarVariants) (*models.User, error)
+	SetSupervisorFunc                   func(ctx context.Context, id int64, supervisorID *int64) (*models.User, error)
+	ListMissingDefaultAvatarsFunc       func(ctx context.Context, afterID int64, limit int) ([]models.User, error)
+	SetDefaultAvatarFunc                func(ctx context.Context, id int64, url string) error
+	DeleteFunc                          func(ctx context.Context, id int64) error
+	GetDirectReportsBySupervisorIDFunc  func(ctx context.Context, supervisorID int64) ([]models.User, error)
+	GetDirectReportsBySupervisorIDsFunc func(ctx context.Context, supervisorIDs []int64) (map[int64][]models.User, error)
+	GetAllSupervisorsFunc               func(ctx context.Context) ([]models.User, error)
+	GetAllDepartmentsFunc               func(ctx context.Context) ([]string, error)
+	ClearDepartmentFunc                 func(ctx context.Context, department string) error
+	UpdateJiraSettingsFunc              func(ctx context.Context, id int64, req *models.UpdateJiraSettingsRequest) error
+	ClearJiraSettingsFunc               func(ctx context.Context, id int64) error
+	UpdateJiraAccountIDFunc             func(ctx context.Context, id int64, jiraAccountID *string) error
+	SaveJiraOAuthTokensFunc             func(ctx context.Context, id int64, tokens *models.JiraOAuthTokens) error
+	UpdateGitHubLoginFunc               func(ctx context.Context, id int64, githubLogin *string) error
+	UpdateLinearUserIDFunc              func(ctx context.Context, id int64, linearUserID *string) error
+	DeactivateFunc                      func(ctx context.Context, id int64) error
+	ReactivateFunc                      func(ctx context.Context, id int64) error
+	OffboardFunc                        func(ctx context.Context, id int64, reassignTasksToID *int64, terminationDate time.Time) (*models.OffboardResult, error)
+	RenameDepartmentFunc                func(ctx context.Context, oldName, newName string) error
+	GetUsersByDepartmentFunc            func(ctx context.Context, department string) ([]models.User, error)
+	SearchFunc                          func(ctx context.Context, filter models.UserSearchFilter, limit, offset int) ([]models.User, int, error)
 }
 
 // NewMockUserRepository creates a new mock user repository
@@ -42,6 +59,7 @@ func NewMockUserRepository() *MockUserRepository {
 		ByAuth0ID:   make(map[string]*models.User),
 		ByEmail:     make(map[string]*models.User),
 		Departments: make(map[string]bool),
+		Deleted:     make(map[int64]*models.User),
 	}
 }
 
@@ -102,14 +120,17 @@ func (m *MockUserRepository) Create(ctx context.Context, req *models.CreateUserR
 		return m.CreateFunc(ctx, req, auth0ID)
 	}
 	user := &models.User{
-		ID:        int64(len(m.Users) + 1),
-		Auth0ID:   auth0ID,
-		Email:     req.Email,
-		FirstName: req.FirstName,
-		LastName:  req.LastName,
-		Role:      req.Role,
-		Title:     req.Title,
-		Department: req.Department,
+		ID:           int64(len(m.Users) + 1),
+		Auth0ID:      auth0ID,
+		Email:        req.Email,
+		FirstName:    req.FirstName,
+		LastName:     req.LastName,
+		Role:         req.Role,
+		Title:        req.Title,
+		Department:   req.Department,
+		SupervisorID: req.SupervisorID,
+		DateStarted:  req.DateStarted,
+		IsActive:     true,
 	}
 	m.Users[user.ID] = user
 	m.ByAuth0ID[auth0ID] = user
@@ -137,17 +158,134 @@ func (m *MockUserRepository) Update(ctx context.Context, id int64, req *models.U
 	if req.Department != nil {
 		user.Department = *req.Department
 	}
+	if req.Timezone != nil {
+		user.Timezone = *req.Timezone
+	}
+	if req.Language != nil {
+		user.Language = *req.Language
+	}
+	if req.Birthday != nil {
+		user.Birthday = nil
+		if date, err := time.Parse("2006-01-02", *req.Birthday); err == nil {
+			user.Birthday = &date
+		}
+	}
+	if req.PreferredName != nil {
+		user.PreferredName = *req.PreferredName
+	}
+	if req.Pronouns != nil {
+		user.Pronouns = *req.Pronouns
+	}
+	if req.Phone != nil {
+		user.Phone = *req.Phone
+	}
+	if req.EmergencyContact != nil {
+		user.EmergencyContact = *req.EmergencyContact
+	}
+	return user, nil
+}
+
+func (m *MockUserRepository) SetAvatar(ctx context.Context, id int64, avatarURL string, variants models.AvatarVariants) (*models.User, error) {
+	if m.SetAvatarFunc != nil {
+		return m.SetAvatarFunc(ctx, id, avatarURL, variants)
+	}
+	user, ok := m.Users[id]
+	if !ok {
+		return nil, nil
+	}
+	user.AvatarURL = &avatarURL
+	user.AvatarVariants = variants
 	return user, nil
 }
 
+func (m *MockUserRepository) SetSupervisor(ctx context.Context, id int64, supervisorID *int64) (*models.User, error) {
+	if m.SetSupervisorFunc != nil {
+		return m.SetSupervisorFunc(ctx, id, supervisorID)
+	}
+	user, ok := m.Users[id]
+	if !ok {
+		return nil, nil
+	}
+	user.SupervisorID = supervisorID
+	return user, nil
+}
+
+func (m *MockUserRepository) ListMissingDefaultAvatars(ctx context.Context, afterID int64, limit int) ([]models.User, error) {
+	if m.ListMissingDefaultAvatarsFunc != nil {
+		return m.ListMissingDefaultAvatarsFunc(ctx, afterID, limit)
+	}
+	var users []models.User
+	for _, u := range m.Users {
+		if u.AvatarURL == nil && u.DefaultAvatarURL == nil && u.ID > afterID {
+			users = append(users, *u)
+		}
+	}
+	slices.SortFunc(users, func(a, b models.User) int { return cmp.Compare(a.ID, b.ID) })
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+func (m *MockUserRepository) SetDefaultAvatar(ctx context.Context, id int64, url string) error {
+	if m.SetDefaultAvatarFunc != nil {
+		return m.SetDefaultAvatarFunc(ctx, id, url)
+	}
+	if user, ok := m.Users[id]; ok {
+		user.DefaultAvatarURL = &url
+	}
+	return nil
+}
+
 func (m *MockUserRepository) Delete(ctx context.Context, id int64) error {
 	if m.DeleteFunc != nil {
 		return m.DeleteFunc(ctx, id)
 	}
+	user, ok := m.Users[id]
+	if !ok {
+		return nil
+	}
+	if m.Deleted == nil {
+		m.Deleted = make(map[int64]*models.User)
+	}
+	if m.activeBeforeDelete == nil {
+		m.activeBeforeDelete = make(map[int64]bool)
+	}
+	now := time.Now()
+	user.DeletedAt = &now
+	m.activeBeforeDelete[id] = user.IsActive
+	user.IsActive = false
+	m.Deleted[id] = user
 	delete(m.Users, id)
 	return nil
 }
 
+func (m *MockUserRepository) Restore(ctx context.Context, id int64) (*models.User, error) {
+	user, ok := m.Deleted[id]
+	if !ok {
+		return nil, errors.New("deleted user not found")
+	}
+	user.DeletedAt = nil
+	user.IsActive = m.activeBeforeDelete[id]
+	m.Users[id] = user
+	delete(m.Deleted, id)
+	delete(m.activeBeforeDelete, id)
+	return user, nil
+}
+
+func (m *MockUserRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	for id, user := range m.Deleted {
+		if user.DeletedAt.Before(before) {
+			delete(m.Deleted, id)
+			delete(m.ByAuth0ID, user.Auth0ID)
+			delete(m.ByEmail, user.Email)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
 func (m *MockUserRepository) GetDirectReportsBySupervisorID(ctx context.Context, supervisorID int64) ([]models.User, error) {
 	if m.GetDirectReportsBySupervisorIDFunc != nil {
 		return m.GetDirectReportsBySupervisorIDFunc(ctx, supervisorID)
@@ -161,6 +299,20 @@ func (m *MockUserRepository) GetDirectReportsBySupervisorID(ctx context.Context,
 	return reports, nil
 }
 
+func (m *MockUserRepository) GetDirectReportsBySupervisorIDs(ctx context.Context, supervisorIDs []int64) (map[int64][]models.User, error) {
+	if m.GetDirectReportsBySupervisorIDsFunc != nil {
+		return m.GetDirectReportsBySupervisorIDsFunc(ctx, supervisorIDs)
+	}
+	result := make(map[int64][]models.User)
+	for _, supervisorID := range supervisorIDs {
+		reports, _ := m.GetDirectReportsBySupervisorID(ctx, supervisorID)
+		if len(reports) > 0 {
+			result[supervisorID] = reports
+		}
+	}
+	return result, nil
+}
+
 func (m *MockUserRepository) GetAllSupervisors(ctx context.Context) ([]models.User, error) {
 	if m.GetAllSupervisorsFunc != nil {
 		return m.GetAllSupervisorsFunc(ctx)
@@ -231,6 +383,26 @@ func (m *MockUserRepository) SaveJiraOAuthTokens(ctx context.Context, id int64,
 	return nil
 }
 
+func (m *MockUserRepository) UpdateGitHubLogin(ctx context.Context, id int64, githubLogin *string) error {
+	if m.UpdateGitHubLoginFunc != nil {
+		return m.UpdateGitHubLoginFunc(ctx, id, githubLogin)
+	}
+	if user, ok := m.Users[id]; ok {
+		user.GitHubLogin = githubLogin
+	}
+	return nil
+}
+
+func (m *MockUserRepository) UpdateLinearUserID(ctx context.Context, id int64, linearUserID *string) error {
+	if m.UpdateLinearUserIDFunc != nil {
+		return m.UpdateLinearUserIDFunc(ctx, id, linearUserID)
+	}
+	if user, ok := m.Users[id]; ok {
+		user.LinearUserID = linearUserID
+	}
+	return nil
+}
+
 func (m *MockUserRepository) Deactivate(ctx context.Context, id int64) error {
 	if m.DeactivateFunc != nil {
 		return m.DeactivateFunc(ctx, id)
@@ -247,10 +419,30 @@ func (m *MockUserRepository) Reactivate(ctx context.Context, id int64) error {
 	}
 	if user, ok := m.Users[id]; ok {
 		user.IsActive = true
+		user.TerminationDate = nil
 	}
 	return nil
 }
 
+func (m *MockUserRepository) Offboard(ctx context.Context, id int64, reassignTasksToID *int64, terminationDate time.Time) (*models.OffboardResult, error) {
+	if m.OffboardFunc != nil {
+		return m.OffboardFunc(ctx, id, reassignTasksToID, terminationDate)
+	}
+	result := &models.OffboardResult{TerminationDate: terminationDate, ReassignedToID: reassignTasksToID}
+	if user, ok := m.Users[id]; ok {
+		user.IsActive = false
+		user.TerminationDate = &terminationDate
+		result.JiraMappingRevoked = user.JiraAccountID != nil
+		user.JiraAccountID = nil
+	}
+	for _, user := range m.Users {
+		if user.SupervisorID != nil && *user.SupervisorID == id {
+			user.SupervisorID = nil
+		}
+	}
+	return result, nil
+}
+
 func (m *MockUserRepository) RenameDepartment(ctx context.Context, oldName, newName string) error {
 	if m.RenameDepartmentFunc != nil {
 		return m.RenameDepartmentFunc(ctx, oldName, newName)
@@ -278,6 +470,32 @@ func (m *MockUserRepository) GetUsersByDepartment(ctx context.Context, departmen
 	return users, nil
 }
 
+// Search matches the query case-insensitively against names and email, ordered by ID
+func (m *MockUserRepository) Search(ctx context.Context, filter models.UserSearchFilter, limit, offset int) ([]models.User, int, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, filter, limit, offset)
+	}
+	query := strings.ToLower(filter.Query)
+	var users []models.User
+	for _, user := range m.Users {
+		text := strings.ToLower(user.FirstName + " " + user.LastName + " " + user.Email)
+		switch {
+		case !strings.Contains(text, query),
+			filter.Department != nil && user.Department != *filter.Department,
+			filter.Role != nil && user.Role != *filter.Role,
+			filter.Active != nil && user.IsActive != *filter.Active,
+			filter.SquadID != nil && !slices.ContainsFunc(user.Squads, func(s models.Squad) bool { return s.ID == *filter.SquadID }):
+			continue
+		}
+		users = append(users, *user)
+	}
+	slices.SortFunc(users, func(a, b models.User) int { return cmp.Compare(a.ID, b.ID) })
+
+	total := len(users)
+	users = users[min(offset, total):min(offset+limit, total)]
+	return users, total, nil
+}
+
 // AddUser is a helper method for setting up test data
 func (m *MockUserRepository) AddUser(user *models.User) {
 	m.Users[user.ID] = user
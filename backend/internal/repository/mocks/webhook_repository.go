@@ -0,0 +1,242 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// MockWebhookRepository is a mock implementation of WebhookRepository for testing
+type MockWebhookRepository struct {
+	Endpoints      map[int64]*models.WebhookEndpoint
+	Deliveries     map[int64]*models.WebhookDelivery
+	NextEndpointID int64
+	NextDeliveryID int64
+}
+
+// NewMockWebhookRepository creates a new mock webhook repository
+func NewMockWebhookRepository() *MockWebhookRepository {
+	return &MockWebhookRepository{
+		Endpoints:      make(map[int64]*models.WebhookEndpoint),
+		Deliveries:     make(map[int64]*models.WebhookDelivery),
+		NextEndpointID: 1,
+		NextDeliveryID: 1,
+	}
+}
+
+// AddEndpoint adds an endpoint to the mock repository
+func (m *MockWebhookRepository) AddEndpoint(endpoint *models.WebhookEndpoint) {
+	if endpoint.ID == 0 {
+		endpoint.ID = m.NextEndpointID
+	}
+	if endpoint.ID >= m.NextEndpointID {
+		m.NextEndpointID = endpoint.ID + 1
+	}
+	m.Endpoints[endpoint.ID] = endpoint
+}
+
+func (m *MockWebhookRepository) ListEndpoints(ctx context.Context) ([]models.WebhookEndpoint, error) {
+	endpoints := []models.WebhookEndpoint{}
+	for _, endpoint := range m.Endpoints {
+		if orgID, ok := tenant.OrgID(ctx); ok && endpoint.OrgID != orgID {
+			continue
+		}
+		endpoints = append(endpoints, *endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].ID < endpoints[j].ID })
+	return endpoints, nil
+}
+
+func (m *MockWebhookRepository) GetEndpoint(ctx context.Context, id int64) (*models.WebhookEndpoint, error) {
+	if endpoint, ok := m.Endpoints[id]; ok {
+		if orgID, ok := tenant.OrgID(ctx); ok && endpoint.OrgID != orgID {
+			return nil, nil
+		}
+		copied := *endpoint
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockWebhookRepository) CreateEndpoint(ctx context.Context, req *models.CreateWebhookEndpointRequest, createdByID int64) (*models.WebhookEndpoint, error) {
+	orgID, err := tenant.ScopedOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := &models.WebhookEndpoint{
+		ID:          m.NextEndpointID,
+		URL:         req.URL,
+		Description: req.Description,
+		EventTypes:  req.EventTypes,
+		IsActive:    true,
+		Secret:      fmt.Sprintf("whsec_test%d", m.NextEndpointID),
+		OrgID:       orgID,
+		CreatedByID: &createdByID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	m.NextEndpointID++
+	m.Endpoints[endpoint.ID] = endpoint
+	copied := *endpoint
+	return &copied, nil
+}
+
+func (m *MockWebhookRepository) UpdateEndpoint(ctx context.Context, id int64, req *models.UpdateWebhookEndpointRequest) (*models.WebhookEndpoint, error) {
+	endpoint, ok := m.Endpoints[id]
+	if !ok {
+		return nil, nil
+	}
+	if req.URL != nil {
+		endpoint.URL = *req.URL
+	}
+	if req.Description != nil {
+		endpoint.Description = *req.Description
+	}
+	if req.EventTypes != nil {
+		endpoint.EventTypes = *req.EventTypes
+	}
+	if req.IsActive != nil {
+		endpoint.IsActive = *req.IsActive
+	}
+	if req.RotateSecret {
+		endpoint.Secret += "-rotated"
+	}
+	endpoint.UpdatedAt = time.Now()
+	copied := *endpoint
+	return &copied, nil
+}
+
+func (m *MockWebhookRepository) DeleteEndpoint(ctx context.Context, id int64) error {
+	if _, ok := m.Endpoints[id]; !ok {
+		return errors.New("webhook endpoint not found")
+	}
+	delete(m.Endpoints, id)
+	for deliveryID, delivery := range m.Deliveries {
+		if delivery.EndpointID == id {
+			delete(m.Deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+// Enqueue queues an event for every active endpoint subscribed to it, as repositories do when they
+// commit a change, and returns how many deliveries were queued
+func (m *MockWebhookRepository) Enqueue(ctx context.Context, eventType string, payload []byte) int64 {
+	endpoints, _ := m.ListEndpoints(ctx)
+	var queued int64
+	for _, endpoint := range endpoints {
+		if !endpoint.IsActive || !endpoint.Subscribes(eventType) {
+			continue
+		}
+		m.Deliveries[m.NextDeliveryID] = &models.WebhookDelivery{
+			ID:            m.NextDeliveryID,
+			EndpointID:    endpoint.ID,
+			EventType:     eventType,
+			Payload:       append([]byte(nil), payload...),
+			Status:        models.WebhookDeliveryStatusPending,
+			NextAttemptAt: time.Now(),
+			CreatedAt:     time.Now(),
+		}
+		m.NextDeliveryID++
+		queued++
+	}
+	return queued
+}
+
+func (m *MockWebhookRepository) EnqueueForEndpoint(ctx context.Context, endpointID int64, eventType string, payload []byte) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{
+		ID:            m.NextDeliveryID,
+		EndpointID:    endpointID,
+		EventType:     eventType,
+		Payload:       append([]byte(nil), payload...),
+		Status:        models.WebhookDeliveryStatusPending,
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	m.Deliveries[delivery.ID] = delivery
+	m.NextDeliveryID++
+	copied := *delivery
+	return &copied, nil
+}
+
+func (m *MockWebhookRepository) ClaimNextDelivery(ctx context.Context, staleAfter time.Duration) (*models.WebhookDelivery, error) {
+	var due *models.WebhookDelivery
+	for _, delivery := range m.Deliveries {
+		if delivery.Status != models.WebhookDeliveryStatusPending || delivery.NextAttemptAt.After(time.Now()) {
+			continue
+		}
+		if due == nil || delivery.ID < due.ID {
+			due = delivery
+		}
+	}
+	if due == nil {
+		return nil, nil
+	}
+	now := time.Now()
+	due.Status = models.WebhookDeliveryStatusSending
+	due.Attempts++
+	due.LastAttemptAt = &now
+	copied := *due
+	return &copied, nil
+}
+
+func (m *MockWebhookRepository) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	if delivery, ok := m.Deliveries[id]; ok {
+		now := time.Now()
+		delivery.Status = models.WebhookDeliveryStatusDelivered
+		delivery.ResponseStatus = &responseStatus
+		delivery.LastError = nil
+		delivery.DeliveredAt = &now
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) ScheduleRetry(ctx context.Context, id int64, responseStatus *int, message string, nextAttemptAt time.Time) error {
+	if delivery, ok := m.Deliveries[id]; ok {
+		delivery.Status = models.WebhookDeliveryStatusPending
+		delivery.ResponseStatus = responseStatus
+		delivery.LastError = &message
+		delivery.NextAttemptAt = nextAttemptAt
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) MarkFailed(ctx context.Context, id int64, responseStatus *int, message string) error {
+	if delivery, ok := m.Deliveries[id]; ok {
+		delivery.Status = models.WebhookDeliveryStatusFailed
+		delivery.ResponseStatus = responseStatus
+		delivery.LastError = &message
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range m.Deliveries {
+		if delivery.EndpointID == endpointID {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (m *MockWebhookRepository) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	for id, delivery := range m.Deliveries {
+		finished := delivery.Status == models.WebhookDeliveryStatusDelivered || delivery.Status == models.WebhookDeliveryStatusFailed
+		if finished && delivery.CreatedAt.Before(before) {
+			delete(m.Deliveries, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockWorkScheduleRepository is a mock implementation of WorkScheduleRepository for testing.
+// Users without a saved schedule get the default one in UTC.
+type MockWorkScheduleRepository struct {
+	Schedules map[int64]*models.WorkSchedule
+
+	// Function hooks for custom behavior
+	GetForUsersFunc func(ctx context.Context, userIDs []int64) (map[int64]*models.WorkSchedule, error)
+}
+
+// NewMockWorkScheduleRepository creates a new mock work schedule repository
+func NewMockWorkScheduleRepository() *MockWorkScheduleRepository {
+	return &MockWorkScheduleRepository{Schedules: make(map[int64]*models.WorkSchedule)}
+}
+
+// AddSchedule adds a saved schedule to the mock repository
+func (m *MockWorkScheduleRepository) AddSchedule(schedule *models.WorkSchedule) {
+	m.Schedules[schedule.UserID] = schedule
+}
+
+func (m *MockWorkScheduleRepository) GetForUsers(ctx context.Context, userIDs []int64) (map[int64]*models.WorkSchedule, error) {
+	if m.GetForUsersFunc != nil {
+		return m.GetForUsersFunc(ctx, userIDs)
+	}
+	schedules := make(map[int64]*models.WorkSchedule, len(userIDs))
+	for _, id := range userIDs {
+		schedules[id], _ = m.Get(ctx, id)
+	}
+	return schedules, nil
+}
+
+func (m *MockWorkScheduleRepository) Get(ctx context.Context, userID int64) (*models.WorkSchedule, error) {
+	if schedule, ok := m.Schedules[userID]; ok {
+		return schedule, nil
+	}
+	return models.DefaultWorkSchedule(userID, models.DefaultTimezone), nil
+}
+
+func (m *MockWorkScheduleRepository) Save(ctx context.Context, userID int64, req *models.UpdateWorkScheduleRequest) (*models.WorkSchedule, error) {
+	schedule, _ := m.Get(ctx, userID)
+	now := time.Now()
+	saved := &models.WorkSchedule{
+		UserID:      userID,
+		Timezone:    schedule.Timezone,
+		StartMinute: req.StartMinute,
+		EndMinute:   req.EndMinute,
+		Weekdays:    req.Weekdays,
+		FocusBlocks: req.FocusBlocks,
+		UpdatedAt:   &now,
+	}
+	m.Schedules[userID] = saved
+	return saved, nil
+}
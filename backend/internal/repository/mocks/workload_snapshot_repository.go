@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// MockWorkloadSnapshotRepository is a mock implementation of WorkloadSnapshotRepository for testing
+type MockWorkloadSnapshotRepository struct {
+	Snapshots    []models.WorkloadSnapshot
+	SnapshotDays []time.Time // Days Snapshot was called for
+	PurgedBefore []time.Time
+
+	// Function hooks for custom behavior
+	SnapshotFunc func(ctx context.Context, day time.Time) (int64, error)
+}
+
+// NewMockWorkloadSnapshotRepository creates a new mock workload snapshot repository
+func NewMockWorkloadSnapshotRepository() *MockWorkloadSnapshotRepository {
+	return &MockWorkloadSnapshotRepository{}
+}
+
+func (m *MockWorkloadSnapshotRepository) Snapshot(ctx context.Context, day time.Time) (int64, error) {
+	m.SnapshotDays = append(m.SnapshotDays, day)
+	if m.SnapshotFunc != nil {
+		return m.SnapshotFunc(ctx, day)
+	}
+	return 0, nil
+}
+
+func (m *MockWorkloadSnapshotRepository) ListForUsers(ctx context.Context, userIDs []int64, since time.Time) ([]models.WorkloadSnapshot, error) {
+	sinceDate := since.Format("2006-01-02")
+	result := []models.WorkloadSnapshot{}
+	for _, s := range m.Snapshots {
+		if slices.Contains(userIDs, s.UserID) && s.Date >= sinceDate {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockWorkloadSnapshotRepository) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.PurgedBefore = append(m.PurgedBefore, before)
+	return 0, nil
+}
@@ -0,0 +1,49 @@
+package scim
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Filter is an equality filter, the only kind identity providers send when looking up a resource
+// before creating it, e.g. userName eq "ada@example.com"
+type Filter struct {
+	Attribute string
+	Value     string
+}
+
+// ParseFilter parses a filter of the form `attribute eq "value"`. An empty filter returns nil.
+func ParseFilter(filter string) (*Filter, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return nil, nil
+	}
+
+	attribute, rest, ok := strings.Cut(filter, " ")
+	if !ok {
+		return nil, invalidFilter(filter)
+	}
+	operator, value, ok := strings.Cut(strings.TrimSpace(rest), " ")
+	if !ok || !strings.EqualFold(operator, "eq") {
+		return nil, invalidFilter(filter)
+	}
+
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, invalidFilter(filter)
+		}
+		value = unquoted
+	}
+	return &Filter{Attribute: attribute, Value: value}, nil
+}
+
+// Is reports whether the filter is on the attribute, which SCIM compares ignoring case
+func (f *Filter) Is(attribute string) bool {
+	return strings.EqualFold(f.Attribute, attribute)
+}
+
+func invalidFilter(filter string) *Error {
+	return &Error{Status: "400", ScimType: ErrInvalidFilter, Detail: "unsupported filter: " + filter}
+}
@@ -0,0 +1,292 @@
+package scim
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PatchRequest is a SCIM PATCH request body
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation adds, replaces, or removes the value at a path. Without a path, the value is an
+// object of attributes to add or replace.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+const (
+	opAdd     = "add"
+	opReplace = "replace"
+	opRemove  = "remove"
+)
+
+// operation returns the lowercased op; Azure AD capitalizes them
+func (o *PatchOperation) operation() (string, error) {
+	op := strings.ToLower(o.Op)
+	switch op {
+	case opAdd, opReplace, opRemove:
+		return op, nil
+	}
+	return "", &Error{Status: "400", ScimType: ErrInvalidSyntax, Detail: "unsupported patch op: " + o.Op}
+}
+
+// attributes decodes the value of an operation without a path
+func (o *PatchOperation) attributes(op string) (map[string]json.RawMessage, error) {
+	if op == opRemove {
+		return nil, &Error{Status: "400", ScimType: ErrInvalidPath, Detail: "remove requires a path"}
+	}
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(o.Value, &attributes); err != nil {
+		return nil, invalidValue("value must be an object of attributes")
+	}
+	return attributes, nil
+}
+
+// ApplyUserPatch applies patch operations to a user
+func ApplyUserPatch(user *User, operations []PatchOperation) error {
+	for _, operation := range operations {
+		op, err := operation.operation()
+		if err != nil {
+			return err
+		}
+		if operation.Path != "" {
+			if err := setUserAttribute(user, operation.Path, operation.Value, op == opRemove); err != nil {
+				return err
+			}
+			continue
+		}
+
+		attributes, err := operation.attributes(op)
+		if err != nil {
+			return err
+		}
+		for path, value := range attributes {
+			if err := setUserAttribute(user, path, value, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setUserAttribute sets or, when removing, clears the user attribute at path
+func setUserAttribute(user *User, path string, value json.RawMessage, remove bool) error {
+	attribute := strings.ToLower(path)
+	attribute = strings.TrimPrefix(attribute, strings.ToLower(SchemaUser)+":")
+	if user.Enterprise == nil {
+		user.Enterprise = &EnterpriseUser{}
+	}
+	if user.Name == nil {
+		user.Name = &Name{}
+	}
+
+	switch attribute {
+	case "active":
+		if remove {
+			return invalidValue("active cannot be removed")
+		}
+		active, err := decodeBool(value)
+		if err != nil {
+			return err
+		}
+		user.Active = &active
+	case "username":
+		return decodeRequiredString(value, remove, "userName", &user.UserName)
+	case "name":
+		if remove {
+			return invalidValue("name cannot be removed")
+		}
+		if err := json.Unmarshal(value, user.Name); err != nil {
+			return invalidValue("name must be an object")
+		}
+	case "name.givenname":
+		return decodeRequiredString(value, remove, "name.givenName", &user.Name.GivenName)
+	case "name.familyname":
+		return decodeRequiredString(value, remove, "name.familyName", &user.Name.FamilyName)
+	case "title":
+		return decodeString(value, remove, &user.Title)
+	case "emails":
+		if remove {
+			return invalidValue("emails cannot be removed")
+		}
+		if err := json.Unmarshal(value, &user.Emails); err != nil {
+			return invalidValue("emails must be a list")
+		}
+	case `emails[type eq "work"].value`, "emails.value":
+		email := user.Email()
+		if err := decodeRequiredString(value, remove, "email", &email); err != nil {
+			return err
+		}
+		user.Emails = []Value{{Value: email, Type: "work", Primary: true}}
+	case "displayname", "name.formatted", "externalid":
+		// Derived from the name, or not stored
+	case strings.ToLower(SchemaEnterpriseUser):
+		if remove {
+			return invalidValue("the enterprise extension cannot be removed")
+		}
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(value, &attributes); err != nil {
+			return invalidValue("the enterprise extension must be an object")
+		}
+		for name, attributeValue := range attributes {
+			if err := setUserAttribute(user, SchemaEnterpriseUser+":"+name, attributeValue, false); err != nil {
+				return err
+			}
+		}
+	case strings.ToLower(SchemaEnterpriseUser) + ":department":
+		return decodeString(value, remove, &user.Enterprise.Department)
+	case strings.ToLower(SchemaEnterpriseUser) + ":manager", strings.ToLower(SchemaEnterpriseUser) + ":manager.value":
+		if remove {
+			user.Enterprise.Manager = nil
+			return nil
+		}
+		manager, err := decodeManager(value)
+		if err != nil {
+			return err
+		}
+		user.Enterprise.Manager = manager
+	default:
+		return &Error{Status: "400", ScimType: ErrInvalidPath, Detail: "unsupported attribute: " + path}
+	}
+	return nil
+}
+
+// ApplyGroupPatch applies patch operations to a group
+func ApplyGroupPatch(group *Group, operations []PatchOperation) error {
+	for _, operation := range operations {
+		op, err := operation.operation()
+		if err != nil {
+			return err
+		}
+		if operation.Path != "" {
+			if err := patchGroupAttribute(group, op, operation.Path, operation.Value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		attributes, err := operation.attributes(op)
+		if err != nil {
+			return err
+		}
+		for path, value := range attributes {
+			if err := patchGroupAttribute(group, op, path, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// patchGroupAttribute applies one operation to the group attribute at path
+func patchGroupAttribute(group *Group, op, path string, value json.RawMessage) error {
+	attribute := strings.ToLower(path)
+
+	// members[value eq "42"] addresses a single member
+	if strings.HasPrefix(attribute, "members[") && strings.HasSuffix(attribute, "]") {
+		parsed, err := ParseFilter(path[len("members[") : len(path)-1])
+		if err != nil || parsed == nil || !parsed.Is("value") || op != opRemove {
+			return &Error{Status: "400", ScimType: ErrInvalidPath, Detail: "unsupported path: " + path}
+		}
+		group.Members = removeMembers(group.Members, []Value{{Value: parsed.Value}})
+		return nil
+	}
+
+	switch attribute {
+	case "displayname":
+		return decodeRequiredString(value, op == opRemove, "displayName", &group.DisplayName)
+	case "members":
+		if op == opRemove && len(value) == 0 {
+			group.Members = []Value{}
+			return nil
+		}
+		var members []Value
+		if err := json.Unmarshal(value, &members); err != nil {
+			return invalidValue("members must be a list")
+		}
+		switch op {
+		case opAdd:
+			for _, member := range members {
+				if !slices.ContainsFunc(group.Members, func(existing Value) bool { return existing.Value == member.Value }) {
+					group.Members = append(group.Members, member)
+				}
+			}
+		case opReplace:
+			group.Members = members
+		case opRemove:
+			group.Members = removeMembers(group.Members, members)
+		}
+	case "externalid":
+		// Not stored
+	default:
+		return &Error{Status: "400", ScimType: ErrInvalidPath, Detail: "unsupported attribute: " + path}
+	}
+	return nil
+}
+
+func removeMembers(members, removed []Value) []Value {
+	return slices.DeleteFunc(members, func(member Value) bool {
+		return slices.ContainsFunc(removed, func(r Value) bool { return r.Value == member.Value })
+	})
+}
+
+// decodeBool decodes a boolean, which Azure AD sends as the string "True" or "False"
+func decodeBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if parsed, err := strconv.ParseBool(s); err == nil {
+			return parsed, nil
+		}
+	}
+	return false, invalidValue("expected a boolean")
+}
+
+// decodeString decodes a string into dst, or clears it when removing
+func decodeString(value json.RawMessage, remove bool, dst *string) error {
+	if remove {
+		*dst = ""
+		return nil
+	}
+	if err := json.Unmarshal(value, dst); err != nil {
+		return invalidValue("expected a string")
+	}
+	return nil
+}
+
+// decodeRequiredString decodes a string that cannot be removed or left empty
+func decodeRequiredString(value json.RawMessage, remove bool, name string, dst *string) error {
+	var s string
+	if remove || json.Unmarshal(value, &s) != nil || strings.TrimSpace(s) == "" {
+		return invalidValue(name + " must be a non-empty string")
+	}
+	*dst = s
+	return nil
+}
+
+// decodeManager decodes a manager given as an object or, as Azure AD sends it, a bare ID
+func decodeManager(value json.RawMessage) (*Manager, error) {
+	var id string
+	if err := json.Unmarshal(value, &id); err == nil {
+		return &Manager{Value: id}, nil
+	}
+	var manager Manager
+	if err := json.Unmarshal(value, &manager); err != nil {
+		return nil, invalidValue("manager must be an ID or an object with a value")
+	}
+	return &manager, nil
+}
+
+func invalidValue(detail string) *Error {
+	return &Error{Status: "400", ScimType: ErrInvalidValue, Detail: detail}
+}
@@ -0,0 +1,287 @@
+// Package scim implements the parts of SCIM 2.0 (RFC 7643 and 7644) identity providers such as
+// Okta and Azure AD use to provision people: Users map to users and Groups to squads.
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// Schema URNs of the resources and messages this package speaks
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaEnterpriseUser        = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// ContentType is the media type of SCIM requests and responses
+const ContentType = "application/scim+json"
+
+// scimType values that explain 400 and 409 errors
+const (
+	ErrInvalidFilter = "invalidFilter"
+	ErrInvalidSyntax = "invalidSyntax"
+	ErrInvalidPath   = "invalidPath"
+	ErrInvalidValue  = "invalidValue"
+	ErrUniqueness    = "uniqueness"
+	ErrMutability    = "mutability"
+)
+
+// Meta describes a resource
+type Meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+// Name is a user's name
+type Name struct {
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+	Formatted  string `json:"formatted,omitempty"`
+}
+
+// Value is an entry of a multi-valued attribute, such as an email or a group member
+type Value struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// Manager references a user's supervisor by their SCIM ID
+type Manager struct {
+	Value       string `json:"value,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// EnterpriseUser holds the enterprise extension attributes that map onto users
+type EnterpriseUser struct {
+	Department string   `json:"department,omitempty"`
+	Manager    *Manager `json:"manager,omitempty"`
+}
+
+// User is a SCIM User resource
+type User struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id,omitempty"`
+	UserName    string          `json:"userName"`
+	Name        *Name           `json:"name,omitempty"`
+	DisplayName string          `json:"displayName,omitempty"`
+	Title       string          `json:"title,omitempty"`
+	Active      *bool           `json:"active,omitempty"`
+	Emails      []Value         `json:"emails,omitempty"`
+	Groups      []Value         `json:"groups,omitempty"`
+	Enterprise  *EnterpriseUser `json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,omitempty"`
+	Meta        *Meta           `json:"meta,omitempty"`
+}
+
+// Group is a SCIM Group resource
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Value  `json:"members"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// ListResponse is a page of resources
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources"`
+}
+
+// NewListResponse wraps a page of resources starting at the 1-based startIndex
+func NewListResponse[T any](resources []T, total, startIndex int) ListResponse {
+	if resources == nil {
+		resources = []T{}
+	}
+	return ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// Error is a SCIM error response
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// WriteJSON writes a SCIM response
+func WriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// WriteError writes a SCIM error response. scimType may be empty.
+func WriteError(w http.ResponseWriter, status int, scimType, detail string) {
+	WriteJSON(w, status, Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// FormatID renders a database ID as a SCIM resource ID
+func FormatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// ParseID parses a SCIM resource ID back into a database ID
+func ParseID(id string) (int64, bool) {
+	parsed, err := strconv.ParseInt(id, 10, 64)
+	return parsed, err == nil && parsed > 0
+}
+
+// FromUser describes a user and the squads they belong to as a SCIM User. baseURL is the
+// /scim/v2 root resources are located under.
+func FromUser(user *models.User, squads []models.Squad, baseURL string) User {
+	active := user.IsActive
+	resource := User{
+		Schemas:     []string{SchemaUser, SchemaEnterpriseUser},
+		ID:          FormatID(user.ID),
+		UserName:    user.Email,
+		Name:        &Name{GivenName: user.FirstName, FamilyName: user.LastName, Formatted: user.FirstName + " " + user.LastName},
+		DisplayName: user.FirstName + " " + user.LastName,
+		Title:       user.Title,
+		Active:      &active,
+		Emails:      []Value{{Value: user.Email, Type: "work", Primary: true}},
+		Enterprise:  &EnterpriseUser{Department: user.Department},
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      &user.CreatedAt,
+			LastModified: &user.UpdatedAt,
+			Location:     baseURL + "/Users/" + FormatID(user.ID),
+		},
+	}
+	if user.SupervisorID != nil {
+		resource.Enterprise.Manager = &Manager{Value: FormatID(*user.SupervisorID)}
+	}
+	for _, squad := range squads {
+		resource.Groups = append(resource.Groups, Value{
+			Value:   FormatID(squad.ID),
+			Display: squad.Name,
+			Ref:     baseURL + "/Groups/" + FormatID(squad.ID),
+		})
+	}
+	return resource
+}
+
+// FromSquad describes a squad and its members as a SCIM Group
+func FromSquad(squad *models.Squad, members []models.User, baseURL string) Group {
+	group := Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          FormatID(squad.ID),
+		DisplayName: squad.Name,
+		Members:     []Value{},
+		Meta: &Meta{
+			ResourceType: "Group",
+			Created:      &squad.CreatedAt,
+			Location:     baseURL + "/Groups/" + FormatID(squad.ID),
+		},
+	}
+	for _, member := range members {
+		group.Members = append(group.Members, Value{
+			Value:   FormatID(member.ID),
+			Display: member.FirstName + " " + member.LastName,
+			Ref:     baseURL + "/Users/" + FormatID(member.ID),
+		})
+	}
+	return group
+}
+
+// Email returns the user's sign-in email: the primary email when one is given, else userName
+func (u *User) Email() string {
+	for _, email := range u.Emails {
+		if email.Primary && email.Value != "" {
+			return email.Value
+		}
+	}
+	return u.UserName
+}
+
+// IsActive reports whether the user should be active; users are active unless told otherwise
+func (u *User) IsActive() bool {
+	return u.Active == nil || *u.Active
+}
+
+// ManagerID returns the ID of the user's supervisor, if one is set
+func (u *User) ManagerID() (*int64, error) {
+	if u.Enterprise == nil || u.Enterprise.Manager == nil || u.Enterprise.Manager.Value == "" {
+		return nil, nil
+	}
+	id, ok := ParseID(u.Enterprise.Manager.Value)
+	if !ok {
+		return nil, &Error{Status: "400", ScimType: ErrInvalidValue, Detail: "manager must reference a user ID"}
+	}
+	return &id, nil
+}
+
+// CreateRequest converts the user into a request creating an employee
+func (u *User) CreateRequest() (*models.CreateUserRequest, error) {
+	supervisorID, err := u.ManagerID()
+	if err != nil {
+		return nil, err
+	}
+	req := &models.CreateUserRequest{
+		Email:        u.Email(),
+		Role:         models.RoleEmployee,
+		Title:        u.Title,
+		SupervisorID: supervisorID,
+	}
+	if u.Name != nil {
+		req.FirstName, req.LastName = u.Name.GivenName, u.Name.FamilyName
+	}
+	if u.Enterprise != nil {
+		req.Department = u.Enterprise.Department
+	}
+	return req, nil
+}
+
+// UpdateRequest converts the user into a request replacing the profile attributes SCIM manages
+func (u *User) UpdateRequest() (*models.UpdateUserRequest, error) {
+	supervisorID, err := u.ManagerID()
+	if err != nil {
+		return nil, err
+	}
+	title := u.Title
+	department := ""
+	if u.Enterprise != nil {
+		department = u.Enterprise.Department
+	}
+	req := &models.UpdateUserRequest{
+		Title:        &title,
+		Department:   &department,
+		SupervisorID: supervisorID,
+	}
+	if u.Name != nil {
+		req.FirstName, req.LastName = &u.Name.GivenName, &u.Name.FamilyName
+	}
+	return req, nil
+}
+
+// Error makes SCIM errors usable as Go errors
+func (e *Error) Error() string {
+	return e.Detail
+}
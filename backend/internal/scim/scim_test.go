@@ -0,0 +1,195 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter    string
+		wantAttr  string
+		wantValue string
+		wantErr   bool
+	}{
+		{`userName eq "ada@example.com"`, "userName", "ada@example.com", false},
+		{`displayName EQ "Platform \"Core\""`, "displayName", `Platform "Core"`, false},
+		{`userName sw "ada"`, "", "", true},
+		{`userName`, "", "", true},
+		{`userName eq "unterminated`, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := ParseFilter(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if filter.Attribute != tt.wantAttr || filter.Value != tt.wantValue {
+				t.Errorf("ParseFilter() = %q %q, want %q %q", filter.Attribute, filter.Value, tt.wantAttr, tt.wantValue)
+			}
+		})
+	}
+
+	if filter, err := ParseFilter(" "); filter != nil || err != nil {
+		t.Errorf("empty filter = %v, %v, want nil", filter, err)
+	}
+}
+
+func TestFromUser(t *testing.T) {
+	supervisorID := int64(2)
+	user := &models.User{
+		ID: 7, Email: "ada@example.com", FirstName: "Ada", LastName: "Lovelace", Title: "Engineer",
+		Department: "R&D", SupervisorID: &supervisorID, IsActive: true, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+
+	resource := FromUser(user, []models.Squad{{ID: 3, Name: "Platform"}}, "https://api.example.com/scim/v2")
+	if resource.ID != "7" || resource.UserName != user.Email || !resource.IsActive() {
+		t.Errorf("resource = %q %q active %v", resource.ID, resource.UserName, resource.IsActive())
+	}
+	if resource.Enterprise.Manager == nil || resource.Enterprise.Manager.Value != "2" {
+		t.Errorf("manager = %v, want 2", resource.Enterprise.Manager)
+	}
+	if len(resource.Groups) != 1 || resource.Groups[0].Value != "3" || resource.Groups[0].Display != "Platform" {
+		t.Errorf("groups = %v, want Platform", resource.Groups)
+	}
+	if resource.Meta.Location != "https://api.example.com/scim/v2/Users/7" {
+		t.Errorf("location = %q", resource.Meta.Location)
+	}
+}
+
+func TestApplyUserPatch(t *testing.T) {
+	newUser := func() User {
+		active := true
+		return User{
+			UserName:   "ada@example.com",
+			Name:       &Name{GivenName: "Ada", FamilyName: "Lovelace"},
+			Title:      "Engineer",
+			Active:     &active,
+			Enterprise: &EnterpriseUser{Department: "R&D"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		check   func(t *testing.T, user User)
+		wantErr bool
+	}{
+		{
+			name: "okta deactivation",
+			body: `[{"op":"replace","value":{"active":false}}]`,
+			check: func(t *testing.T, user User) {
+				if user.IsActive() {
+					t.Error("user is still active")
+				}
+			},
+		},
+		{
+			name: "azure deactivation with a string boolean",
+			body: `[{"op":"Replace","path":"active","value":"False"}]`,
+			check: func(t *testing.T, user User) {
+				if user.IsActive() {
+					t.Error("user is still active")
+				}
+			},
+		},
+		{
+			name: "names, title, department, and manager",
+			body: `[
+				{"op":"replace","path":"name.givenName","value":"Augusta"},
+				{"op":"replace","value":{"title":"Principal Engineer","urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department":"Research"}},
+				{"op":"add","path":"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:manager","value":"5"}
+			]`,
+			check: func(t *testing.T, user User) {
+				if user.Name.GivenName != "Augusta" || user.Name.FamilyName != "Lovelace" {
+					t.Errorf("name = %+v", user.Name)
+				}
+				if user.Title != "Principal Engineer" || user.Enterprise.Department != "Research" {
+					t.Errorf("title, department = %q %q", user.Title, user.Enterprise.Department)
+				}
+				if id, err := user.ManagerID(); err != nil || id == nil || *id != 5 {
+					t.Errorf("manager = %v %v, want 5", id, err)
+				}
+			},
+		},
+		{
+			name: "remove title",
+			body: `[{"op":"remove","path":"title"}]`,
+			check: func(t *testing.T, user User) {
+				if user.Title != "" {
+					t.Errorf("title = %q, want it removed", user.Title)
+				}
+			},
+		},
+		{name: "unknown attribute", body: `[{"op":"replace","path":"nickName","value":"Ada"}]`, wantErr: true},
+		{name: "unknown op", body: `[{"op":"move","path":"title","value":"x"}]`, wantErr: true},
+		{name: "empty given name", body: `[{"op":"replace","path":"name.givenName","value":" "}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operations []PatchOperation
+			if err := json.Unmarshal([]byte(tt.body), &operations); err != nil {
+				t.Fatalf("bad test body: %v", err)
+			}
+			user := newUser()
+			err := ApplyUserPatch(&user, operations)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyUserPatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, user)
+			}
+		})
+	}
+}
+
+func TestApplyGroupPatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantName    string
+		wantMembers []string
+	}{
+		{"add members", `[{"op":"add","path":"members","value":[{"value":"2"},{"value":"3"}]}]`, "Platform", []string{"1", "2", "3"}},
+		{"remove member by filter", `[{"op":"remove","path":"members[value eq \"2\"]"}]`, "Platform", []string{"1"}},
+		{"remove members by value", `[{"op":"Remove","path":"members","value":[{"value":"1"}]}]`, "Platform", []string{"2"}},
+		{"replace members", `[{"op":"replace","path":"members","value":[{"value":"4"}]}]`, "Platform", []string{"4"}},
+		{"rename", `[{"op":"replace","value":{"displayName":"Infra"}}]`, "Infra", []string{"1", "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operations []PatchOperation
+			if err := json.Unmarshal([]byte(tt.body), &operations); err != nil {
+				t.Fatalf("bad test body: %v", err)
+			}
+			group := Group{DisplayName: "Platform", Members: []Value{{Value: "1"}, {Value: "2"}}}
+			if err := ApplyGroupPatch(&group, operations); err != nil {
+				t.Fatalf("ApplyGroupPatch() error = %v", err)
+			}
+			if group.DisplayName != tt.wantName {
+				t.Errorf("displayName = %q, want %q", group.DisplayName, tt.wantName)
+			}
+			var members []string
+			for _, member := range group.Members {
+				members = append(members, member.Value)
+			}
+			if len(members) != len(tt.wantMembers) {
+				t.Fatalf("members = %v, want %v", members, tt.wantMembers)
+			}
+			for i := range members {
+				if members[i] != tt.wantMembers[i] {
+					t.Fatalf("members = %v, want %v", members, tt.wantMembers)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Package secrets encrypts credentials, such as OAuth tokens, before they're stored.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks an encrypted value, so plaintext stored before encryption was enabled
+// can still be read and later encrypted in place
+const prefix = "enc:v1:"
+
+// dataKeySize is the size of each value's AES-256 data key
+const dataKeySize = 32
+
+// ErrNoKey is returned when reading an encrypted value without an encryption key configured
+var ErrNoKey = errors.New("value is encrypted but no encryption key is configured")
+
+// KeyWrapper encrypts and decrypts data keys with a key encryption key, which may be held
+// locally or in a KMS
+type KeyWrapper interface {
+	WrapKey(dataKey []byte) ([]byte, error)
+	UnwrapKey(wrapped []byte) ([]byte, error)
+}
+
+// LocalKeyWrapper wraps data keys with AES-GCM under a key from config
+type LocalKeyWrapper struct {
+	aead cipher.AEAD
+}
+
+// NewLocalKeyWrapper creates a key wrapper from a 32-byte AES-256 key
+func NewLocalKeyWrapper(key []byte) (*LocalKeyWrapper, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalKeyWrapper{aead: aead}, nil
+}
+
+// WrapKey encrypts a data key
+func (w *LocalKeyWrapper) WrapKey(dataKey []byte) ([]byte, error) {
+	return seal(w.aead, dataKey)
+}
+
+// UnwrapKey decrypts a data key
+func (w *LocalKeyWrapper) UnwrapKey(wrapped []byte) ([]byte, error) {
+	return open(w.aead, wrapped)
+}
+
+// Cipher envelope-encrypts values: each value is sealed with AES-GCM under its own random
+// data key, which is stored alongside it wrapped by the key encryption key. Values without
+// the encrypted prefix are treated as legacy plaintext. A nil Cipher stores values as plaintext.
+type Cipher struct {
+	wrapper KeyWrapper
+}
+
+// NewCipher creates a cipher that wraps data keys with wrapper
+func NewCipher(wrapper KeyWrapper) *Cipher {
+	return &Cipher{wrapper: wrapper}
+}
+
+// NewCipherFromKey creates a cipher from a base64-encoded 32-byte key.
+// It returns nil when key is empty, leaving encryption disabled.
+func NewCipherFromKey(key string) (*Cipher, error) {
+	if key == "" {
+		return nil, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key must be base64: %w", err)
+	}
+	wrapper, err := NewLocalKeyWrapper(raw)
+	if err != nil {
+		return nil, err
+	}
+	return NewCipher(wrapper), nil
+}
+
+// Enabled reports whether values are encrypted
+func (c *Cipher) Enabled() bool {
+	return c != nil
+}
+
+// IsEncrypted reports whether value was produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt encrypts value. Empty values, and all values for a nil cipher, are returned unchanged.
+func (c *Cipher) Encrypt(value string) (string, error) {
+	if c == nil || value == "" || IsEncrypted(value) {
+		return value, nil
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, []byte(value))
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := c.wrapper.WrapKey(dataKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	return prefix + base64.RawStdEncoding.EncodeToString(wrapped) + "." + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value from Encrypt. Plaintext values are returned unchanged.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if c == nil {
+		return "", ErrNoKey
+	}
+
+	wrapped, sealed, err := split(value)
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := c.wrapper.UnwrapKey(wrapped)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// WrappedKey returns the wrapped data key of a value from Encrypt, so the key encryption key it
+// was wrapped with can be identified
+func WrappedKey(value string) ([]byte, error) {
+	if !IsEncrypted(value) {
+		return nil, fmt.Errorf("value is not encrypted")
+	}
+	wrapped, _, err := split(value)
+	return wrapped, err
+}
+
+// split decodes an encrypted value into its wrapped data key and sealed value
+func split(value string) ([]byte, []byte, error) {
+	encodedKey, encodedValue, ok := strings.Cut(strings.TrimPrefix(value, prefix), ".")
+	if !ok {
+		return nil, nil, fmt.Errorf("malformed encrypted value")
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encodedValue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	return wrapped, sealed, nil
+}
+
+// EncryptPtr encrypts an optional value; nil stays nil
+func (c *Cipher) EncryptPtr(value *string) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	encrypted, err := c.Encrypt(*value)
+	if err != nil {
+		return nil, err
+	}
+	return &encrypted, nil
+}
+
+// DecryptPtr decrypts an optional value in place
+func (c *Cipher) DecryptPtr(value *string) error {
+	if value == nil {
+		return nil
+	}
+	decrypted, err := c.Decrypt(*value)
+	if err != nil {
+		return err
+	}
+	*value = decrypted
+	return nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// seal encrypts plaintext with a random nonce, which is prepended to the result
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts the output of seal
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("malformed encrypted value")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
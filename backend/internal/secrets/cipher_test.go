@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestCipher(t *testing.T) *Cipher {
+	t.Helper()
+	c, err := NewCipherFromKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32)))
+	if err != nil {
+		t.Fatalf("NewCipherFromKey() error = %v", err)
+	}
+	return c
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c := newTestCipher(t)
+
+	encrypted, err := c.Encrypt("refresh-token-123")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !IsEncrypted(encrypted) || strings.Contains(encrypted, "refresh-token-123") {
+		t.Errorf("Encrypt() = %q, want an encrypted value", encrypted)
+	}
+
+	// Each value gets its own data key and nonce
+	again, _ := c.Encrypt("refresh-token-123")
+	if again == encrypted {
+		t.Error("encrypting the same value twice should give different ciphertexts")
+	}
+
+	decrypted, err := c.Decrypt(encrypted)
+	if err != nil || decrypted != "refresh-token-123" {
+		t.Errorf("Decrypt() = %q, %v", decrypted, err)
+	}
+
+	// Already encrypted values aren't encrypted twice
+	if twice, _ := c.Encrypt(encrypted); twice != encrypted {
+		t.Error("Encrypt() of an encrypted value should return it unchanged")
+	}
+}
+
+func TestCipher_Plaintext(t *testing.T) {
+	c := newTestCipher(t)
+
+	// Legacy plaintext reads back unchanged
+	if got, err := c.Decrypt("legacy-token"); err != nil || got != "legacy-token" {
+		t.Errorf("Decrypt(plaintext) = %q, %v", got, err)
+	}
+	if got, _ := c.Encrypt(""); got != "" {
+		t.Errorf("Encrypt(\"\") = %q, want empty", got)
+	}
+
+	// A nil cipher stores plaintext but can't read encrypted values
+	var disabled *Cipher
+	if got, _ := disabled.Encrypt("token"); got != "token" {
+		t.Errorf("nil Encrypt() = %q, want plaintext", got)
+	}
+	encrypted, _ := c.Encrypt("token")
+	if _, err := disabled.Decrypt(encrypted); !errors.Is(err, ErrNoKey) {
+		t.Errorf("nil Decrypt() error = %v, want ErrNoKey", err)
+	}
+}
+
+func TestCipher_WrongKeyOrTampered(t *testing.T) {
+	c := newTestCipher(t)
+	encrypted, _ := c.Encrypt("token")
+
+	other, _ := NewCipherFromKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32)))
+	if _, err := other.Decrypt(encrypted); err == nil {
+		t.Error("Decrypt() with the wrong key should fail")
+	}
+
+	tampered := encrypted[:len(encrypted)-2] + "AA"
+	if _, err := c.Decrypt(tampered); err == nil {
+		t.Error("Decrypt() of a tampered value should fail")
+	}
+}
+
+func TestNewCipherFromKey(t *testing.T) {
+	if c, err := NewCipherFromKey(""); c != nil || err != nil {
+		t.Errorf("empty key = %v, %v, want encryption disabled", c, err)
+	}
+	if _, err := NewCipherFromKey("not base64!"); err == nil {
+		t.Error("expected an error for a key that isn't base64")
+	}
+	if _, err := NewCipherFromKey(base64.StdEncoding.EncodeToString([]byte("too short"))); err == nil {
+		t.Error("expected an error for a key that isn't 32 bytes")
+	}
+}
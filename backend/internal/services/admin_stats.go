@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/cache"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// adminStatsCacheTTL is how long an organization's admin stats are cached, and so how stale they
+// can be
+const adminStatsCacheTTL = 30 * time.Second
+
+// AdminStatsService serves the stats on the admin home page. They're cached briefly, since the
+// page is reloaded often and each load would otherwise run every aggregate.
+type AdminStatsService struct {
+	repo  repository.AdminStatsRepository
+	cache *cache.Cache
+}
+
+// NewAdminStatsService creates a new admin stats service
+func NewAdminStatsService(repo repository.AdminStatsRepository) *AdminStatsService {
+	return &AdminStatsService{
+		repo:  repo,
+		cache: cache.New(adminStatsCacheTTL, time.Minute),
+	}
+}
+
+// Get returns the stats of the organization, which must be the organization of ctx
+func (s *AdminStatsService) Get(ctx context.Context, orgID int64) (*models.AdminStats, error) {
+	key := strconv.FormatInt(orgID, 10)
+	if cached, ok := s.cache.Get(key); ok {
+		return cached.(*models.AdminStats), nil
+	}
+
+	stats, err := s.repo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(key, stats)
+	return stats, nil
+}
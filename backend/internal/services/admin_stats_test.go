@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestAdminStatsService_GetCachesPerOrg(t *testing.T) {
+	repo := mocks.NewMockAdminStatsRepository()
+	repo.Stats.ActiveInvitations = 3
+	s := NewAdminStatsService(repo)
+	ctx := context.Background()
+
+	for range 3 {
+		stats, err := s.Get(ctx, 1)
+		if err != nil || stats.ActiveInvitations != 3 {
+			t.Fatalf("Get() = %+v, %v", stats, err)
+		}
+	}
+	if repo.Calls != 1 {
+		t.Errorf("repository called %d times, want stats cached after the first", repo.Calls)
+	}
+
+	if _, err := s.Get(ctx, 2); err != nil {
+		t.Fatalf("Get() for another org error = %v", err)
+	}
+	if repo.Calls != 2 {
+		t.Errorf("repository called %d times, want each org cached separately", repo.Calls)
+	}
+}
+
+func TestAdminStatsService_GetDoesNotCacheErrors(t *testing.T) {
+	repo := mocks.NewMockAdminStatsRepository()
+	repo.Err = errors.New("database unavailable")
+	s := NewAdminStatsService(repo)
+
+	if _, err := s.Get(context.Background(), 1); err == nil {
+		t.Fatal("Get() error = nil, want the repository error")
+	}
+
+	repo.Err = nil
+	repo.Stats.Headcount = models.AdminHeadcount{Total: 12}
+	stats, err := s.Get(context.Background(), 1)
+	if err != nil || stats.Headcount.Total != 12 {
+		t.Errorf("Get() after recovering = %+v, %v, want fresh stats", stats, err)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/auth0"
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// Auth0UserBlocker blocks and unblocks Auth0 users
+type Auth0UserBlocker interface {
+	SetBlocked(ctx context.Context, userID string, blocked bool) error
+}
+
+// Auth0SyncService keeps users' Auth0 accounts in step with their dashboard accounts, so people
+// who leave can't keep signing in. Users deactivated or deleted in the dashboard are blocked in
+// Auth0 and unblocked when reactivated or restored; users deleted or blocked in Auth0 are
+// deactivated in the dashboard. All methods are safe to call on a nil service.
+type Auth0SyncService struct {
+	client   Auth0UserBlocker
+	userRepo repository.UserRepository
+	broker   *events.Broker
+	logger   *logger.Logger
+}
+
+// NewAuth0SyncService creates a new Auth0 sync service
+func NewAuth0SyncService(client Auth0UserBlocker, userRepo repository.UserRepository, broker *events.Broker) *Auth0SyncService {
+	return &Auth0SyncService{
+		client:   client,
+		userRepo: userRepo,
+		broker:   broker,
+		logger:   logger.Default().WithComponent("auth0-sync"),
+	}
+}
+
+// Block blocks the user in Auth0. Failures are logged rather than returned so they never undo the
+// change in the dashboard, which keeps the user out on its own.
+func (s *Auth0SyncService) Block(ctx context.Context, user *models.User) {
+	s.setBlocked(ctx, user, true)
+}
+
+// Unblock unblocks the user in Auth0, failing like Block
+func (s *Auth0SyncService) Unblock(ctx context.Context, user *models.User) {
+	s.setBlocked(ctx, user, false)
+}
+
+func (s *Auth0SyncService) setBlocked(ctx context.Context, user *models.User, blocked bool) {
+	if s == nil || user == nil || user.Auth0ID == "" {
+		return
+	}
+
+	err := s.client.SetBlocked(ctx, user.Auth0ID, blocked)
+	if errors.Is(err, auth0.ErrUserNotFound) {
+		// Users of other identity providers have no Auth0 account
+		return
+	}
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to sync user to Auth0", err, "user_id", user.ID, "blocked", blocked)
+		return
+	}
+	s.logger.Info("Synced user to Auth0", "user_id", user.ID, "blocked", blocked)
+}
+
+// Revoked deactivates the dashboard user with the Auth0 ID, after they were deleted or blocked in
+// Auth0. It returns the user, or nil if there is no active user with the ID. A failed lookup is
+// returned rather than taken to mean there's no such user, so the revocation is retried.
+func (s *Auth0SyncService) Revoked(ctx context.Context, auth0ID string) (*models.User, error) {
+	if s == nil || auth0ID == "" {
+		return nil, nil
+	}
+
+	user, err := s.userRepo.GetByAuth0ID(ctx, auth0ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to look up user revoked in Auth0", err, "auth0_id", auth0ID)
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, nil
+	}
+
+	// The log stream isn't signed in as anyone, so act within the user's organization
+	ctx = tenant.WithOrgID(ctx, user.OrgID)
+	if err := s.userRepo.Deactivate(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	s.broker.Publish(ctx, events.UserChanged, nil)
+	s.logger.Info("Deactivated user revoked in Auth0", "user_id", user.ID)
+	return user, nil
+}
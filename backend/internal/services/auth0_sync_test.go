@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/smith-dallin/manager-dashboard/internal/auth0"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+// fakeAuth0Blocker records the blocked state set for each Auth0 user
+type fakeAuth0Blocker struct {
+	blocked map[string]bool
+	err     error
+}
+
+func (f *fakeAuth0Blocker) SetBlocked(ctx context.Context, userID string, blocked bool) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.blocked[userID] = blocked
+	return nil
+}
+
+func TestAuth0SyncService_Block(t *testing.T) {
+	client := &fakeAuth0Blocker{blocked: map[string]bool{}}
+	service := NewAuth0SyncService(client, mocks.NewMockUserRepository(), nil)
+	user := &models.User{ID: 1, Auth0ID: "auth0|1"}
+
+	service.Block(context.Background(), user)
+	if !client.blocked["auth0|1"] {
+		t.Error("Block() didn't block the user in Auth0")
+	}
+	service.Unblock(context.Background(), user)
+	if client.blocked["auth0|1"] {
+		t.Error("Unblock() didn't unblock the user in Auth0")
+	}
+
+	// Users without an Auth0 account are skipped, and failures don't panic
+	service.Block(context.Background(), &models.User{ID: 2})
+	if len(client.blocked) != 1 {
+		t.Errorf("Block() of a user without an Auth0 ID called Auth0: %v", client.blocked)
+	}
+	client.err = auth0.ErrUserNotFound
+	service.Block(context.Background(), user)
+	client.err = errors.New("auth0 unavailable")
+	service.Block(context.Background(), user)
+
+	var nilService *Auth0SyncService
+	nilService.Block(context.Background(), user)
+}
+
+func TestAuth0SyncService_Revoked(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Auth0ID: "auth0|1", IsActive: true})
+	service := NewAuth0SyncService(&fakeAuth0Blocker{blocked: map[string]bool{}}, userRepo, nil)
+
+	user, err := service.Revoked(context.Background(), "auth0|1")
+	if err != nil || user == nil || user.ID != 1 {
+		t.Fatalf("Revoked() = %+v, %v, want user 1", user, err)
+	}
+	if userRepo.Users[1].IsActive {
+		t.Error("Revoked() didn't deactivate the user")
+	}
+
+	// Already deactivated and unknown users are skipped
+	if user, err := service.Revoked(context.Background(), "auth0|1"); user != nil || err != nil {
+		t.Errorf("Revoked() of an inactive user = %+v, %v, want nil", user, err)
+	}
+	if user, err := service.Revoked(context.Background(), "auth0|unknown"); user != nil || err != nil {
+		t.Errorf("Revoked() of an unknown user = %+v, %v, want nil", user, err)
+	}
+
+	// Failing to look the user up isn't mistaken for there being no such user
+	userRepo.GetByAuth0IDFunc = func(ctx context.Context, auth0ID string) (*models.User, error) {
+		return nil, fmt.Errorf("failed to get user by Auth0 ID: %w", pgx.ErrNoRows)
+	}
+	if user, err := service.Revoked(context.Background(), "auth0|gone"); user != nil || err != nil {
+		t.Errorf("Revoked() of a missing user = %+v, %v, want nil", user, err)
+	}
+	userRepo.GetByAuth0IDFunc = func(ctx context.Context, auth0ID string) (*models.User, error) {
+		return nil, errors.New("database unavailable")
+	}
+	if _, err := service.Revoked(context.Background(), "auth0|1"); err == nil {
+		t.Error("Revoked() error = nil, want the lookup error")
+	}
+	userRepo.GetByAuth0IDFunc = nil
+
+	userRepo.AddUser(&models.User{ID: 2, Auth0ID: "auth0|2", IsActive: true})
+	userRepo.DeactivateFunc = func(ctx context.Context, id int64) error { return errors.New("database unavailable") }
+	if _, err := service.Revoked(context.Background(), "auth0|2"); err == nil {
+		t.Error("Revoked() error = nil, want the deactivation error")
+	}
+}
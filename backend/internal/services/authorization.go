@@ -4,11 +4,13 @@ import (
 	"context"
 
 	"github.com/smith-dallin/manager-dashboard/internal/apperrors"
+	"github.com/smith-dallin/manager-dashboard/internal/authz"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/repository"
 )
 
-// AuthorizationService handles authorization logic across the application
+// AuthorizationService handles authorization logic across the application, turning authz
+// permission checks that need other users loaded into errors handlers can return
 type AuthorizationService struct {
 	userRepo repository.UserRepository
 }
@@ -20,70 +22,122 @@ func NewAuthorizationService(userRepo repository.UserRepository) *AuthorizationS
 	}
 }
 
-// isDirectReport checks if targetUserID is a direct report of supervisorID.
-// Returns the target user, whether they are a direct report, and any error.
-func (s *AuthorizationService) isDirectReport(ctx context.Context, supervisorID, targetUserID int64) (*models.User, bool, error) {
+// canOnUser checks whether the current user may perform an action on another user, loading
+// them only when the answer depends on who they are
+func (s *AuthorizationService) canOnUser(ctx context.Context, currentUser *models.User, action authz.Action, targetUserID int64) (bool, error) {
+	if authz.CanAll(currentUser, action) {
+		return true, nil
+	}
+	if currentUser.ID == targetUserID {
+		return authz.Can(currentUser, action, &authz.Resource{OwnerID: targetUserID}), nil
+	}
+	if !authz.CanForOthers(currentUser, action) {
+		return false, nil
+	}
+
 	targetUser, err := s.userRepo.GetByID(ctx, targetUserID)
 	if err != nil {
-		return nil, false, apperrors.NewInternalError("failed to get user", err)
+		return false, apperrors.NewInternalError("failed to get user", err)
 	}
 	if targetUser == nil {
-		return nil, false, apperrors.NewNotFoundError("User")
+		return false, apperrors.NewNotFoundError("User")
 	}
-	isReport := targetUser.SupervisorID != nil && *targetUser.SupervisorID == supervisorID
-	return targetUser, isReport, nil
+	return authz.Can(currentUser, action, authz.UserResource(targetUser)), nil
 }
 
-// CanViewUser checks if the current user can view another user's details
-func (s *AuthorizationService) CanViewUser(ctx context.Context, currentUser *models.User, targetUserID int64) error {
-	// Guard: Admin can view anyone
-	if currentUser.IsAdmin() {
+// CanModify checks if the current user may make any changes at all.
+// Viewers have read-only access and are rejected before any other permission check.
+func (s *AuthorizationService) CanModify(currentUser *models.User) error {
+	if !authz.Can(currentUser, authz.ActionWrite, nil) {
+		return apperrors.NewForbiddenErrorWithCode(apperrors.CodeReadOnly, "Viewers have read-only access")
+	}
+	return nil
+}
+
+// CanViewDirectory checks if the current user can see anything beyond their own calendar,
+// such as the employee directory, org chart, squads, or Jira data. Guests never can.
+func (s *AuthorizationService) CanViewDirectory(currentUser *models.User) error {
+	if !authz.Can(currentUser, authz.ActionDirectoryView, nil) {
+		return apperrors.NewForbiddenErrorWithCode(apperrors.CodeGuestRestricted, "Guests can only access meetings they are invited to")
+	}
+	return nil
+}
+
+// CanViewOrgWide checks if the current user can see org-wide data such as the full org chart,
+// all employees, and everyone's time off on the calendar
+func (s *AuthorizationService) CanViewOrgWide(currentUser *models.User) error {
+	if authz.Can(currentUser, authz.ActionOrgWideView, nil) {
 		return nil
 	}
+	return apperrors.NewForbiddenError("Only admins and viewers can view org-wide data")
+}
 
-	// Guard: Users can view themselves
-	if currentUser.ID == targetUserID {
+// CanViewOrgChart checks if the current user can view the org chart tree
+func (s *AuthorizationService) CanViewOrgChart(currentUser *models.User) error {
+	if authz.Can(currentUser, authz.ActionOrgChartView, nil) {
 		return nil
 	}
+	return apperrors.NewForbiddenError("Only admins, supervisors, and viewers can view the org chart")
+}
 
-	// Guard: Supervisors can view their direct reports
-	if currentUser.IsSupervisor() {
-		_, isReport, err := s.isDirectReport(ctx, currentUser.ID, targetUserID)
-		if err != nil {
-			return err
-		}
-		if isReport {
+// CanViewOrgSubtree checks if the current user can view the org chart under a user: anyone's for
+// admins and viewers, and otherwise only their own and their reports', direct or indirect
+func (s *AuthorizationService) CanViewOrgSubtree(ctx context.Context, currentUser *models.User, rootID int64) error {
+	if err := s.CanViewOrgChart(currentUser); err != nil {
+		return err
+	}
+	if s.CanViewOrgWide(currentUser) == nil || rootID == currentUser.ID {
+		return nil
+	}
+
+	root, err := s.userRepo.GetByID(ctx, rootID)
+	if err != nil {
+		return apperrors.FromRepository(err, "User")
+	}
+	if root == nil {
+		return apperrors.NewNotFoundError("User")
+	}
+	// Walk up the root's reporting chain looking for the current user
+	seen := map[int64]bool{}
+	for next := root; next.SupervisorID != nil && !seen[next.ID]; {
+		seen[next.ID] = true
+		if *next.SupervisorID == currentUser.ID {
 			return nil
 		}
+		if next, err = s.userRepo.GetByID(ctx, *next.SupervisorID); err != nil || next == nil {
+			break
+		}
 	}
+	return apperrors.NewForbiddenError("You can only view your own part of the org chart")
+}
 
-	return apperrors.NewForbiddenError("You do not have permission to view this user")
+// CanViewUser checks if the current user can view another user's details
+func (s *AuthorizationService) CanViewUser(ctx context.Context, currentUser *models.User, targetUserID int64) error {
+	ok, err := s.canOnUser(ctx, currentUser, authz.ActionUserView, targetUserID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperrors.NewForbiddenError("You do not have permission to view this user")
+	}
+	return nil
 }
 
 // CanUpdateUser checks if the current user can update another user
 func (s *AuthorizationService) CanUpdateUser(ctx context.Context, currentUser *models.User, targetUserID int64) error {
-	// Guard: Admin can update anyone
-	if currentUser.IsAdmin() {
-		return nil
+	// Guard: Viewers cannot update anyone, including themselves
+	if err := s.CanModify(currentUser); err != nil {
+		return err
 	}
 
-	// Guard: Users can update themselves (limited fields handled by handler)
-	if currentUser.ID == targetUserID {
-		return nil
+	ok, err := s.canOnUser(ctx, currentUser, authz.ActionUserUpdate, targetUserID)
+	if err != nil {
+		return err
 	}
-
-	// Guard: Supervisors can update their direct reports
-	if currentUser.IsSupervisor() {
-		_, isReport, err := s.isDirectReport(ctx, currentUser.ID, targetUserID)
-		if err != nil {
-			return err
-		}
-		if isReport {
-			return nil
-		}
+	if !ok {
+		return apperrors.NewForbiddenError("You do not have permission to update this user")
 	}
-
-	return apperrors.NewForbiddenError("You do not have permission to update this user")
+	return nil
 }
 
 // CanDeleteUser checks if the current user can delete another user
@@ -93,28 +147,19 @@ func (s *AuthorizationService) CanDeleteUser(ctx context.Context, currentUser *m
 		return apperrors.NewForbiddenError("Cannot delete yourself")
 	}
 
-	// Guard: Admin can delete anyone (except themselves, checked above)
-	if currentUser.IsAdmin() {
-		return nil
+	ok, err := s.canOnUser(ctx, currentUser, authz.ActionUserDelete, targetUserID)
+	if err != nil {
+		return err
 	}
-
-	// Guard: Supervisors can delete their direct reports
-	if currentUser.IsSupervisor() {
-		_, isReport, err := s.isDirectReport(ctx, currentUser.ID, targetUserID)
-		if err != nil {
-			return err
-		}
-		if isReport {
-			return nil
-		}
+	if !ok {
+		return apperrors.NewForbiddenError("You do not have permission to delete this user")
 	}
-
-	return apperrors.NewForbiddenError("You do not have permission to delete this user")
+	return nil
 }
 
 // CanManageSquads checks if the current user can create/delete squads
 func (s *AuthorizationService) CanManageSquads(currentUser *models.User) error {
-	if currentUser.IsAdmin() || currentUser.IsSupervisor() {
+	if authz.Can(currentUser, authz.ActionSquadManage, nil) {
 		return nil
 	}
 	return apperrors.NewForbiddenError("Only admins and supervisors can manage squads")
@@ -122,7 +167,7 @@ func (s *AuthorizationService) CanManageSquads(currentUser *models.User) error {
 
 // CanManageDepartments checks if the current user can create/delete departments
 func (s *AuthorizationService) CanManageDepartments(currentUser *models.User) error {
-	if currentUser.IsAdmin() || currentUser.IsSupervisor() {
+	if authz.Can(currentUser, authz.ActionDepartmentManage, nil) {
 		return nil
 	}
 	return apperrors.NewForbiddenError("Only admins and supervisors can manage departments")
@@ -130,7 +175,7 @@ func (s *AuthorizationService) CanManageDepartments(currentUser *models.User) er
 
 // CanManageInvitations checks if the current user can manage invitations
 func (s *AuthorizationService) CanManageInvitations(currentUser *models.User) error {
-	if currentUser.IsAdmin() {
+	if authz.Can(currentUser, authz.ActionInvitationManage, nil) {
 		return nil
 	}
 	return apperrors.NewForbiddenError("Only admins can manage invitations")
@@ -138,93 +183,56 @@ func (s *AuthorizationService) CanManageInvitations(currentUser *models.User) er
 
 // CanConfigureJira checks if the current user can configure Jira integration
 func (s *AuthorizationService) CanConfigureJira(currentUser *models.User) error {
-	if currentUser.IsAdmin() {
+	if authz.Can(currentUser, authz.ActionIntegrationManage, nil) {
 		return nil
 	}
 	return apperrors.NewForbiddenError("Only admins can configure Jira integration")
 }
 
-// CanViewTimeOffRequest checks if the current user can view a time-off request
+// CanViewTimeOffRequest checks if the current user can view a time-off request.
+// Viewers only see other people's time off through the calendar, without reasons or reviewer notes.
 func (s *AuthorizationService) CanViewTimeOffRequest(currentUser *models.User, request *models.TimeOffRequest) error {
-	// Admin can view all
-	if currentUser.IsAdmin() {
+	if authz.Can(currentUser, authz.ActionTimeOffView, authz.TimeOffResource(request)) {
 		return nil
 	}
-
-	// Owner can view their own
-	if request.UserID == currentUser.ID {
-		return nil
-	}
-
-	// Supervisor can view their direct reports' requests
-	if currentUser.IsSupervisor() && request.User != nil && request.User.SupervisorID != nil {
-		if *request.User.SupervisorID == currentUser.ID {
-			return nil
-		}
-	}
-
 	return apperrors.NewForbiddenError("You do not have permission to view this time-off request")
 }
 
 // CanReviewTimeOffRequest checks if the current user can review a time-off request
 func (s *AuthorizationService) CanReviewTimeOffRequest(ctx context.Context, currentUser *models.User, requestingUserID int64) error {
-	// Only supervisors and admins can review
-	if !currentUser.IsSupervisorOrAdmin() {
+	if !authz.Can(currentUser, authz.ActionTimeOffReview, nil) {
 		return apperrors.NewForbiddenError("Only supervisors and admins can review time-off requests")
 	}
 
-	// Admin can review all
-	if currentUser.IsAdmin() {
-		return nil
-	}
-
-	// Supervisor can only review their direct reports
-	requestingUser, err := s.userRepo.GetByID(ctx, requestingUserID)
+	ok, err := s.canOnUser(ctx, currentUser, authz.ActionTimeOffReview, requestingUserID)
 	if err != nil {
-		return apperrors.NewInternalError("failed to get user", err)
-	}
-	if requestingUser == nil {
-		return apperrors.NewNotFoundError("User")
+		return err
 	}
-
-	if requestingUser.SupervisorID == nil || *requestingUser.SupervisorID != currentUser.ID {
+	if !ok {
 		return apperrors.NewForbiddenError("Can only review direct reports' requests")
 	}
-
 	return nil
 }
 
 // CanCreateTimeOffForOther checks if the current user can create time-off for another user
 func (s *AuthorizationService) CanCreateTimeOffForOther(ctx context.Context, currentUser *models.User, targetUserID int64) error {
-	// Only supervisors and admins can create for others
-	if !currentUser.IsSupervisorOrAdmin() {
+	if !authz.CanForOthers(currentUser, authz.ActionTimeOffCreate) {
 		return apperrors.NewForbiddenError("Only supervisors and admins can create time off for others")
 	}
 
-	// Admin can create for anyone
-	if currentUser.IsAdmin() {
-		return nil
-	}
-
-	// Supervisor can only create for their direct reports
-	targetUser, err := s.userRepo.GetByID(ctx, targetUserID)
+	ok, err := s.canOnUser(ctx, currentUser, authz.ActionTimeOffCreate, targetUserID)
 	if err != nil {
-		return apperrors.NewInternalError("failed to get user", err)
+		return err
 	}
-	if targetUser == nil {
-		return apperrors.NewNotFoundError("User")
-	}
-
-	if targetUser.SupervisorID == nil || *targetUser.SupervisorID != currentUser.ID {
+	if !ok {
 		return apperrors.NewForbiddenError("Can only create time off for direct reports")
 	}
-
 	return nil
 }
 
 // CanManageOrgChart checks if the current user can manage org chart drafts
 func (s *AuthorizationService) CanManageOrgChart(currentUser *models.User) error {
-	if currentUser.IsAdmin() || currentUser.IsSupervisor() {
+	if authz.Can(currentUser, authz.ActionOrgChartManage, nil) {
 		return nil
 	}
 	return apperrors.NewForbiddenError("Only admins and supervisors can manage org chart drafts")
@@ -232,7 +240,7 @@ func (s *AuthorizationService) CanManageOrgChart(currentUser *models.User) error
 
 // CanPublishOrgChart checks if the current user can publish org chart changes
 func (s *AuthorizationService) CanPublishOrgChart(currentUser *models.User) error {
-	if currentUser.IsAdmin() {
+	if authz.Can(currentUser, authz.ActionOrgChartPublish, nil) {
 		return nil
 	}
 	return apperrors.NewForbiddenError("Only admins can publish org chart changes")
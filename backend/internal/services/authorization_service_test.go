@@ -30,6 +30,16 @@ func TestAuthorizationService_CanViewUser(t *testing.T) {
 			setupMocks:   func(m *mocks.MockUserRepository) {},
 			wantErr:      false,
 		},
+		{
+			name: "viewer can view anyone",
+			currentUser: &models.User{
+				ID:   4,
+				Role: models.RoleViewer,
+			},
+			targetUserID: 2,
+			setupMocks:   func(m *mocks.MockUserRepository) {},
+			wantErr:      false,
+		},
 		{
 			name: "user can view themselves",
 			currentUser: &models.User{
@@ -165,6 +175,16 @@ func TestAuthorizationService_CanUpdateUser(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "viewer cannot update themselves",
+			currentUser: &models.User{
+				ID:   4,
+				Role: models.RoleViewer,
+			},
+			targetUserID: 4,
+			setupMocks:   func(m *mocks.MockUserRepository) {},
+			wantErr:      true,
+		},
 		{
 			name: "employee cannot update others",
 			currentUser: &models.User{
@@ -726,3 +746,57 @@ func TestAuthorizationService_CanManageDepartments(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorizationService_CanModify(t *testing.T) {
+	tests := []struct {
+		name        string
+		currentUser *models.User
+		wantErr     bool
+	}{
+		{name: "admin can modify", currentUser: &models.User{ID: 1, Role: models.RoleAdmin}, wantErr: false},
+		{name: "supervisor can modify", currentUser: &models.User{ID: 1, Role: models.RoleSupervisor}, wantErr: false},
+		{name: "employee can modify", currentUser: &models.User{ID: 1, Role: models.RoleEmployee}, wantErr: false},
+		{name: "viewer is read-only", currentUser: &models.User{ID: 1, Role: models.RoleViewer}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewAuthorizationService(nil)
+			err := svc.CanModify(tt.currentUser)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CanModify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && apperrors.GetErrorCode(err) != apperrors.CodeReadOnly {
+				t.Errorf("CanModify() code = %v, want %v", apperrors.GetErrorCode(err), apperrors.CodeReadOnly)
+			}
+		})
+	}
+}
+
+func TestAuthorizationService_CanViewOrgChart(t *testing.T) {
+	tests := []struct {
+		name        string
+		currentUser *models.User
+		wantChart   bool
+		wantOrgWide bool
+	}{
+		{name: "admin", currentUser: &models.User{ID: 1, Role: models.RoleAdmin}, wantChart: true, wantOrgWide: true},
+		{name: "viewer", currentUser: &models.User{ID: 1, Role: models.RoleViewer}, wantChart: true, wantOrgWide: true},
+		{name: "supervisor", currentUser: &models.User{ID: 1, Role: models.RoleSupervisor}, wantChart: true, wantOrgWide: false},
+		{name: "employee", currentUser: &models.User{ID: 1, Role: models.RoleEmployee}, wantChart: false, wantOrgWide: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewAuthorizationService(nil)
+
+			if got := svc.CanViewOrgChart(tt.currentUser) == nil; got != tt.wantChart {
+				t.Errorf("CanViewOrgChart() allowed = %v, want %v", got, tt.wantChart)
+			}
+			if got := svc.CanViewOrgWide(tt.currentUser) == nil; got != tt.wantOrgWide {
+				t.Errorf("CanViewOrgWide() allowed = %v, want %v", got, tt.wantOrgWide)
+			}
+		})
+	}
+}
@@ -3,24 +3,29 @@ package services
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/storage"
 )
 
 // AvatarService handles avatar image processing and storage
 type AvatarService struct {
-	storage storage.Storage
-	logger  *logger.Logger
+	storage  storage.Storage
+	tenantID string
+	logger   *logger.Logger
 }
 
-// NewAvatarService creates a new avatar service
-func NewAvatarService(store storage.Storage) *AvatarService {
+// NewAvatarService creates a new avatar service storing files under the given tenant's prefix
+func NewAvatarService(store storage.Storage, tenantID string) *AvatarService {
 	return &AvatarService{
-		storage: store,
-		logger:  logger.Default().WithComponent("avatar-service"),
+		storage:  store,
+		tenantID: tenantID,
+		logger:   logger.Default().WithComponent("avatar-service"),
 	}
 }
 
@@ -104,19 +109,53 @@ var ErrStorageNotConfigured = fmt.Errorf("image storage is not configured")
 // ErrUploadFailed is returned when the upload fails
 var ErrUploadFailed = fmt.Errorf("failed to upload image, please try again later")
 
-// Upload stores an avatar image and returns the URL
-func (s *AvatarService) Upload(ctx context.Context, userID int64, img *ImageData) (string, error) {
+// Upload resizes an avatar image into its variants, stores each one, and returns the URL of the
+// largest along with every variant. Already stored files are removed if a later upload fails.
+func (s *AvatarService) Upload(ctx context.Context, userID int64, img *ImageData) (string, models.AvatarVariants, error) {
 	if s.storage == nil {
 		s.logger.LogError(ctx, "S3 storage not configured", ErrStorageNotConfigured, "user_id", userID)
-		return "", ErrStorageNotConfigured
+		return "", nil, ErrStorageNotConfigured
 	}
 
-	key := storage.GenerateAvatarKey(userID, img.Extension)
-	url, err := s.storage.Upload(ctx, key, img.Data, img.ContentType)
+	renditions, err := renderAvatar(img.Data)
 	if err != nil {
-		s.logger.LogError(ctx, "S3 upload failed", err, "user_id", userID)
-		return "", ErrUploadFailed
+		if errors.Is(err, ErrInvalidImage) {
+			return "", nil, err
+		}
+		s.logger.LogError(ctx, "Avatar resize failed", err, "user_id", userID)
+		return "", nil, ErrUploadFailed
 	}
-	s.logger.Info("S3 upload successful", "user_id", userID, "url", url)
-	return url, nil
+
+	uploadID := time.Now().UnixNano()
+	var keys []string
+	upload := func(size int, data []byte, contentType, extension string) (string, error) {
+		key := storage.GenerateAvatarVariantKey(s.tenantID, userID, uploadID, size, extension)
+		url, err := s.storage.Upload(ctx, key, data, contentType)
+		if err == nil {
+			keys = append(keys, key)
+		}
+		return url, err
+	}
+
+	variants := make(models.AvatarVariants, 0, len(renditions))
+	for _, r := range renditions {
+		variant := models.AvatarVariant{Size: r.size, ContentType: r.contentType}
+		if variant.URL, err = upload(r.size, r.data, r.contentType, r.extension); err == nil {
+			variant.WebPURL, err = upload(r.size, r.webp, "image/webp", ".webp")
+		}
+		if err != nil {
+			s.logger.LogError(ctx, "S3 upload failed", err, "user_id", userID, "size", r.size)
+			for _, key := range keys {
+				if delErr := s.storage.Delete(ctx, key); delErr != nil {
+					s.logger.Warn("Failed to remove partial avatar upload", "key", key, "error", delErr)
+				}
+			}
+			return "", nil, ErrUploadFailed
+		}
+		variants = append(variants, variant)
+	}
+
+	url := variants[len(variants)-1].URL
+	s.logger.Info("S3 upload successful", "user_id", userID, "url", url, "variants", len(variants))
+	return url, variants, nil
 }
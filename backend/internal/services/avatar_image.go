@@ -0,0 +1,206 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	// Register the decoders for the other formats avatars may be uploaded in
+	_ "image/gif"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// maxAvatarPixels bounds the dimensions of uploaded images so a small, highly compressed file
+// can't be decoded into gigabytes of pixels
+const maxAvatarPixels = 50_000_000
+
+// avatarJPEGQuality is the quality of the JPEG variants of opaque avatars
+const avatarJPEGQuality = 85
+
+// ErrInvalidImage is returned when an uploaded avatar can't be decoded as an image
+var ErrInvalidImage = fmt.Errorf("invalid image: please upload a valid JPEG, PNG, GIF, or WebP image")
+
+// avatarRendition is one encoded size of an avatar
+type avatarRendition struct {
+	size        int
+	data        []byte
+	contentType string
+	extension   string
+	webp        []byte
+}
+
+// renderAvatar decodes an uploaded image and encodes a square, center-cropped copy at each of
+// models.AvatarSizes, as JPEG (or PNG when the image has transparency) and WebP. Sizes larger than
+// the image are skipped, except the smallest. Re-encoding drops EXIF and other metadata; a JPEG's
+// EXIF orientation is applied to the pixels first so the result stays upright.
+func renderAvatar(data []byte) ([]avatarRendition, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxAvatarPixels {
+		return nil, ErrInvalidImage
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	crop := centerSquare(src.Bounds())
+	orientation := jpegOrientation(data)
+
+	var renditions []avatarRendition
+	for i, size := range models.AvatarSizes {
+		if size > crop.Dx() && i > 0 {
+			break
+		}
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+		square := orient(dst, orientation)
+
+		r, err := encodeAvatar(square, size)
+		if err != nil {
+			return nil, err
+		}
+		renditions = append(renditions, r)
+	}
+	return renditions, nil
+}
+
+// encodeAvatar encodes one size of an avatar in its fallback format and as WebP
+func encodeAvatar(img *image.RGBA, size int) (avatarRendition, error) {
+	r := avatarRendition{size: size}
+
+	var buf bytes.Buffer
+	if img.Opaque() {
+		r.contentType, r.extension = "image/jpeg", ".jpg"
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: avatarJPEGQuality}); err != nil {
+			return r, fmt.Errorf("failed to encode %dpx JPEG: %w", size, err)
+		}
+	} else {
+		r.contentType, r.extension = "image/png", ".png"
+		if err := png.Encode(&buf, img); err != nil {
+			return r, fmt.Errorf("failed to encode %dpx PNG: %w", size, err)
+		}
+	}
+	r.data = buf.Bytes()
+
+	var webp bytes.Buffer
+	if err := nativewebp.Encode(&webp, img, nil); err != nil {
+		return r, fmt.Errorf("failed to encode %dpx WebP: %w", size, err)
+	}
+	r.webp = webp.Bytes()
+	return r, nil
+}
+
+// centerSquare returns the largest square centered in the bounds
+func centerSquare(b image.Rectangle) image.Rectangle {
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	return image.Rect(x, y, x+side, y+side)
+}
+
+// orient transforms a square image according to an EXIF orientation (1-8) so it displays upright
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	n := img.Bounds().Dx()
+	out := image.NewRGBA(img.Bounds())
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				dx, dy = n-1-x, y
+			case 3: // rotated 180
+				dx, dy = n-1-x, n-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, n-1-y
+			case 5: // mirrored along the main diagonal
+				dx, dy = y, x
+			case 6: // rotated 90 clockwise
+				dx, dy = n-1-y, x
+			case 7: // mirrored along the anti-diagonal
+				dx, dy = n-1-y, n-1-x
+			case 8: // rotated 90 counter-clockwise
+				dx, dy = y, n-1-x
+			}
+			out.SetRGBA(dx, dy, img.RGBAAt(x, y))
+		}
+	}
+	return out
+}
+
+// jpegOrientation reads the EXIF orientation tag of a JPEG, returning 1 (upright) if the data
+// isn't a JPEG or has no orientation
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		// Start of scan: metadata segments all come before the image data
+		if marker == 0xDA {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+		if marker == 0xE1 {
+			if o, ok := exifOrientation(data[i+4 : end]); ok {
+				return o
+			}
+		}
+		i = end
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of an APP1 EXIF segment
+func exifOrientation(seg []byte) (int, bool) {
+	const header = "Exif\x00\x00"
+	if len(seg) < len(header)+8 || string(seg[:len(header)]) != header {
+		return 0, false
+	}
+	tiff := seg[len(header):]
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, false
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0, false
+	}
+	count := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0, false
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8 : entry+10])), true
+		}
+	}
+	return 0, false
+}
@@ -1,10 +1,20 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/binary"
 	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
 )
 
 // MockStorage implements the Storage interface for testing
@@ -35,8 +45,12 @@ func (m *MockStorage) GetURL(key string) string {
 	return "https://example.com/" + key
 }
 
+func (m *MockStorage) KeyFromURL(url string) (string, bool) {
+	return strings.CutPrefix(url, "https://example.com/")
+}
+
 func TestAvatarService_ParseBase64Image(t *testing.T) {
-	service := NewAvatarService(nil)
+	service := NewAvatarService(nil, "acme")
 
 	t.Run("valid PNG image", func(t *testing.T) {
 		// Create a minimal valid base64 data URL
@@ -146,16 +160,57 @@ func TestAvatarService_ParseBase64Image(t *testing.T) {
 	})
 }
 
+// testImage encodes a w x h image whose left half is red and right half is blue
+func testImage(t *testing.T, w, h int, format string) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			if format == "png-alpha" && y < h/2 {
+				c = color.RGBA{}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, img, nil)
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// withEXIFOrientation inserts a little-endian EXIF segment holding only an orientation tag after a JPEG's SOI marker
+func withEXIFOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte{'I', 'I', 42, 0, 8, 0, 0, 0, 1, 0, 0x12, 0x01, 3, 0, 1, 0, 0, 0, byte(orientation), byte(orientation >> 8), 0, 0, 0, 0, 0, 0}
+	seg := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, byte((len(seg) + 2) >> 8), byte(len(seg) + 2)}
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	out = append(out, seg...)
+	return append(out, data[2:]...)
+}
+
 func TestAvatarService_Upload(t *testing.T) {
 	t.Run("returns error when storage is nil", func(t *testing.T) {
-		service := NewAvatarService(nil)
+		service := NewAvatarService(nil, "acme")
 		img := &ImageData{
-			Data:        []byte{0x89, 0x50, 0x4E, 0x47},
+			Data:        testImage(t, 64, 64, "png"),
 			ContentType: "image/png",
 			Extension:   ".png",
 		}
 
-		_, err := service.Upload(context.Background(), 1, img)
+		_, _, err := service.Upload(context.Background(), 1, img)
 		if err == nil {
 			t.Fatal("expected error when storage is nil")
 		}
@@ -164,53 +219,184 @@ func TestAvatarService_Upload(t *testing.T) {
 		}
 	})
 
-	t.Run("successful upload to S3", func(t *testing.T) {
+	t.Run("stores each size as JPEG and WebP", func(t *testing.T) {
+		uploads := map[string]string{}
 		mockStorage := &MockStorage{
 			UploadFunc: func(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+				uploads[key] = contentType
 				return "https://bucket.s3.amazonaws.com/" + key, nil
 			},
 		}
-		service := NewAvatarService(mockStorage)
+		service := NewAvatarService(mockStorage, "acme")
 		img := &ImageData{
-			Data:        []byte{0x89, 0x50, 0x4E, 0x47},
-			ContentType: "image/png",
-			Extension:   ".png",
+			Data:        testImage(t, 800, 600, "jpeg"),
+			ContentType: "image/jpeg",
+			Extension:   ".jpg",
 		}
 
-		url, err := service.Upload(context.Background(), 123, img)
+		url, variants, err := service.Upload(context.Background(), 123, img)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if url == "" {
-			t.Error("expected non-empty URL")
+		if len(variants) != 3 {
+			t.Fatalf("expected 3 variants, got %d", len(variants))
+		}
+		if len(uploads) != 6 {
+			t.Errorf("expected 6 uploads, got %d", len(uploads))
+		}
+		for i, v := range variants {
+			if v.Size != models.AvatarSizes[i] {
+				t.Errorf("variant %d size = %d, want %d", i, v.Size, models.AvatarSizes[i])
+			}
+			if v.ContentType != "image/jpeg" || !strings.HasSuffix(v.URL, ".jpg") {
+				t.Errorf("variant %d = %s %s, want a JPEG", i, v.ContentType, v.URL)
+			}
+			if !strings.HasSuffix(v.WebPURL, ".webp") {
+				t.Errorf("variant %d WebP URL = %s", i, v.WebPURL)
+			}
+			if !strings.Contains(v.URL, "tenants/acme/users/123/avatars/") {
+				t.Errorf("variant %d URL should be under the user's prefix, got %s", i, v.URL)
+			}
+		}
+		if url != variants[2].URL {
+			t.Errorf("URL = %s, want the largest variant %s", url, variants[2].URL)
+		}
+	})
+
+	t.Run("rejects data that isn't an image", func(t *testing.T) {
+		service := NewAvatarService(&MockStorage{}, "acme")
+		img := &ImageData{
+			Data:        []byte{0x89, 0x50, 0x4E, 0x47},
+			ContentType: "image/png",
+			Extension:   ".png",
+		}
+
+		_, _, err := service.Upload(context.Background(), 123, img)
+		if !errors.Is(err, ErrInvalidImage) {
+			t.Errorf("expected ErrInvalidImage, got %v", err)
 		}
 	})
 
-	t.Run("returns user-friendly error when S3 upload fails", func(t *testing.T) {
+	t.Run("returns user-friendly error and cleans up when S3 upload fails", func(t *testing.T) {
+		var stored, deleted []string
 		mockStorage := &MockStorage{
 			UploadFunc: func(ctx context.Context, key string, data []byte, contentType string) (string, error) {
-				return "", errors.New("S3 connection timeout")
+				if len(stored) == 3 {
+					return "", errors.New("S3 connection timeout")
+				}
+				stored = append(stored, key)
+				return "https://bucket.s3.amazonaws.com/" + key, nil
+			},
+			DeleteFunc: func(ctx context.Context, key string) error {
+				deleted = append(deleted, key)
+				return nil
 			},
 		}
-		service := NewAvatarService(mockStorage)
+		service := NewAvatarService(mockStorage, "acme")
 		img := &ImageData{
-			Data:        []byte{0x89, 0x50, 0x4E, 0x47},
+			Data:        testImage(t, 600, 600, "png"),
 			ContentType: "image/png",
 			Extension:   ".png",
 		}
 
-		_, err := service.Upload(context.Background(), 123, img)
+		_, _, err := service.Upload(context.Background(), 123, img)
 		if err == nil {
 			t.Fatal("expected error when S3 upload fails")
 		}
 		if !errors.Is(err, ErrUploadFailed) {
 			t.Errorf("expected ErrUploadFailed, got %v", err)
 		}
+		if !slices.Equal(stored, deleted) {
+			t.Errorf("deleted %v, want the stored keys %v", deleted, stored)
+		}
+	})
+}
+
+func TestRenderAvatar(t *testing.T) {
+	t.Run("keeps transparency as PNG", func(t *testing.T) {
+		renditions, err := renderAvatar(testImage(t, 200, 200, "png-alpha"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// 512px is larger than the image, so it's skipped
+		if len(renditions) != 2 {
+			t.Fatalf("expected 2 renditions, got %d", len(renditions))
+		}
+		for _, r := range renditions {
+			if r.contentType != "image/png" {
+				t.Errorf("%dpx content type = %s, want image/png", r.size, r.contentType)
+			}
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(r.webp))
+			if err != nil || format != "webp" || cfg.Width != r.size || cfg.Height != r.size {
+				t.Errorf("%dpx WebP = %s %dx%d (%v)", r.size, format, cfg.Width, cfg.Height, err)
+			}
+		}
+	})
+
+	t.Run("crops to a centered square", func(t *testing.T) {
+		renditions, err := renderAvatar(testImage(t, 1024, 512, "png"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		img, _, err := image.Decode(bytes.NewReader(renditions[0].data))
+		if err != nil {
+			t.Fatalf("failed to decode rendition: %v", err)
+		}
+		if b := img.Bounds(); b.Dx() != 32 || b.Dy() != 32 {
+			t.Fatalf("rendition is %dx%d, want 32x32", b.Dx(), b.Dy())
+		}
+		// The center of the source is where red meets blue
+		if r, _, b, _ := img.At(2, 16).RGBA(); r < b {
+			t.Error("left edge should be red")
+		}
+		if r, _, b, _ := img.At(29, 16).RGBA(); b < r {
+			t.Error("right edge should be blue")
+		}
+	})
+
+	t.Run("applies EXIF orientation and strips metadata", func(t *testing.T) {
+		// Rotating 90 clockwise moves the red left half to the top
+		data := withEXIFOrientation(testImage(t, 256, 256, "jpeg"), 6)
+		if jpegOrientation(data) != 6 {
+			t.Fatalf("orientation = %d, want 6", jpegOrientation(data))
+		}
+
+		renditions, err := renderAvatar(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out := renditions[1].data
+		if bytes.Contains(out, []byte("Exif")) {
+			t.Error("rendition should not contain EXIF data")
+		}
+		img, err := jpeg.Decode(bytes.NewReader(out))
+		if err != nil {
+			t.Fatalf("failed to decode rendition: %v", err)
+		}
+		if r, _, b, _ := img.At(64, 8).RGBA(); r < b {
+			t.Error("top should be red")
+		}
+		if r, _, b, _ := img.At(64, 120).RGBA(); b < r {
+			t.Error("bottom should be blue")
+		}
+	})
+
+	t.Run("rejects images with too many pixels", func(t *testing.T) {
+		var buf bytes.Buffer
+		// A header claiming 10000x10000 is enough; decoding stops at DecodeConfig
+		_ = png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1)))
+		data := buf.Bytes()
+		binary.BigEndian.PutUint32(data[16:20], 10000)
+		binary.BigEndian.PutUint32(data[20:24], 10000)
+
+		if _, err := renderAvatar(data); !errors.Is(err, ErrInvalidImage) {
+			t.Errorf("expected ErrInvalidImage, got %v", err)
+		}
 	})
 }
 
 func TestAvatarService_GetExtensionFromContentType(t *testing.T) {
-	service := NewAvatarService(nil)
+	service := NewAvatarService(nil, "acme")
 
 	tests := []struct {
 		contentType string
@@ -2,9 +2,11 @@ package services
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/smith-dallin/manager-dashboard/internal/database"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 )
 
@@ -15,12 +17,13 @@ type JiraClient interface {
 }
 
 // CalendarBFFService is the Backend For Frontend service that aggregates
-// calendar data from multiple sources (tasks, meetings, Jira, time off)
+// calendar data from multiple sources (tasks, meetings, Jira, time off, team milestones)
 // before sending to the frontend components
 type CalendarBFFService struct {
 	calendarRepo *database.CalendarRepository
 	jiraRepo     *database.OrgJiraRepository
 	jiraClient   JiraClient
+	logger       *logger.Logger
 }
 
 // NewCalendarBFFService creates a new Calendar BFF service
@@ -33,6 +36,7 @@ func NewCalendarBFFService(
 		calendarRepo: calendarRepo,
 		jiraRepo:     jiraRepo,
 		jiraClient:   jiraClient,
+		logger:       logger.Default().WithComponent("calendar-bff"),
 	}
 }
 
@@ -41,16 +45,31 @@ type CalendarEventsRequest struct {
 	User  *models.User
 	Start time.Time
 	End   time.Time
+	// Limit caps the events returned from each source; zero returns every event in the window
+	Limit  int
+	Cursor models.CalendarCursor
 }
 
-// CalendarEventsResponse contains the aggregated calendar events and metadata
+// CalendarEventsResponse contains the aggregated calendar events and metadata.
+// A source that fails to load is reported as unavailable rather than failing the whole response.
 type CalendarEventsResponse struct {
-	Events        []models.CalendarEvent `json:"events"`
-	JiraConnected bool                   `json:"jira_connected"`
-	TaskCount     int                    `json:"task_count"`
-	MeetingCount  int                    `json:"meeting_count"`
-	JiraCount     int                    `json:"jira_count"`
-	TimeOffCount  int                    `json:"time_off_count"`
+	Events         []models.CalendarEvent `json:"events"`
+	JiraConnected  bool                   `json:"jira_connected"`
+	TaskCount      int                    `json:"task_count"`
+	MeetingCount   int                    `json:"meeting_count"`
+	JiraCount      int                    `json:"jira_count"`
+	TimeOffCount   int                    `json:"time_off_count"`
+	MilestoneCount int                    `json:"milestone_count"`
+
+	Partial               bool `json:"partial"`
+	TasksUnavailable      bool `json:"tasks_unavailable"`
+	MeetingsUnavailable   bool `json:"meetings_unavailable"`
+	JiraUnavailable       bool `json:"jira_unavailable"`
+	TimeOffUnavailable    bool `json:"time_off_unavailable"`
+	MilestonesUnavailable bool `json:"milestones_unavailable"`
+
+	HasMore    bool    `json:"has_more"`
+	NextCursor *string `json:"next_cursor,omitempty"`
 }
 
 // GetCalendarEvents aggregates calendar data from all sources:
@@ -58,27 +77,95 @@ type CalendarEventsResponse struct {
 // - Meetings from the database
 // - Jira issues and epics (if connected)
 // - Time off requests
+// - Birthdays and work anniversaries of the user's team
+//
+// Each source is loaded independently. An error is only returned when none of the
+// database-backed sources could be loaded.
 func (s *CalendarBFFService) GetCalendarEvents(ctx context.Context, req CalendarEventsRequest) (*CalendarEventsResponse, error) {
-	// Fetch Jira issues if Jira is configured
-	jiraIssues, jiraConnected := s.fetchJiraData(ctx)
+	response := &CalendarEventsResponse{}
+	bySource := make(map[models.CalendarEventType][]models.CalendarEvent, len(models.CalendarEventTypes))
 
-	// Get all events from the calendar repository (tasks, meetings, time off + jira)
-	events, err := s.calendarRepo.GetEvents(ctx, req.User, req.Start, req.End, jiraIssues)
-	if err != nil {
-		return nil, err
+	// Later pages only revisit the sources the cursor says have more events
+	wants := func(source models.CalendarEventType) bool {
+		if req.Cursor == nil {
+			return true
+		}
+		_, ok := req.Cursor[source]
+		return ok
 	}
 
-	// Ensure we return an empty array instead of null
-	if events == nil {
-		events = []models.CalendarEvent{}
+	var firstErr error
+	failed, attempted := 0, 0
+	load := func(source models.CalendarEventType, unavailable *bool, fetch func() ([]models.CalendarEvent, error)) {
+		if !wants(source) {
+			return
+		}
+		attempted++
+		events, err := fetch()
+		if err != nil {
+			s.logger.LogError(ctx, "Failed to load calendar source "+string(source), err)
+			*unavailable = true
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		bySource[source] = events
 	}
 
-	// Count events by type for metadata
-	response := &CalendarEventsResponse{
-		Events:        events,
-		JiraConnected: jiraConnected,
+	// All-day events are dates, so they're fetched a day either side of the window and
+	// trimmed once their days are pinned to the viewer's timezone
+	viewerLoc := req.User.Location()
+	dayStart, dayEnd := req.Start.AddDate(0, 0, -1), req.End.AddDate(0, 0, 1)
+
+	load(models.CalendarEventTypeTask, &response.TasksUnavailable, func() ([]models.CalendarEvent, error) {
+		return s.calendarRepo.GetTaskEvents(ctx, req.User, dayStart, dayEnd)
+	})
+	load(models.CalendarEventTypeMeeting, &response.MeetingsUnavailable, func() ([]models.CalendarEvent, error) {
+		return s.calendarRepo.GetMeetingEvents(ctx, req.User, req.Start, req.End)
+	})
+	if s.calendarRepo.TimeOffRepo() != nil {
+		load(models.CalendarEventTypeTimeOff, &response.TimeOffUnavailable, func() ([]models.CalendarEvent, error) {
+			return s.calendarRepo.GetTimeOffEvents(ctx, req.User, dayStart, dayEnd)
+		})
 	}
+	if s.calendarRepo.HasMilestones() {
+		load(models.CalendarEventTypeMilestone, &response.MilestonesUnavailable, func() ([]models.CalendarEvent, error) {
+			return s.calendarRepo.GetMilestoneEvents(ctx, req.User, dayStart, dayEnd)
+		})
+	}
+	if attempted > 0 && failed == attempted {
+		return nil, firstErr
+	}
+
+	// Guests never see the org's Jira data
+	if !req.User.IsGuest() && wants(models.CalendarEventTypeJira) {
+		jiraIssues, connected, err := s.fetchJiraData(ctx)
+		response.JiraConnected = connected
+		if err != nil {
+			s.logger.LogError(ctx, "Failed to load Jira issues for calendar", err)
+			response.JiraUnavailable = true
+		}
+		bySource[models.CalendarEventTypeJira] = database.JiraEvents(jiraIssues, dayStart, dayEnd)
+	}
+
+	for source, events := range bySource {
+		bySource[source] = localizeCalendarEvents(events, viewerLoc, req.Start, req.End)
+	}
+
+	response.Partial = response.TasksUnavailable || response.MeetingsUnavailable ||
+		response.JiraUnavailable || response.TimeOffUnavailable || response.MilestonesUnavailable
 
+	events, next := PaginateCalendarEvents(bySource, req.Cursor, req.Limit)
+	response.Events = events
+	if next != nil {
+		encoded := next.Encode()
+		response.HasMore = true
+		response.NextCursor = &encoded
+	}
+
+	// Count events by type for metadata
 	for _, event := range events {
 		switch event.Type {
 		case models.CalendarEventTypeTask:
@@ -89,38 +176,385 @@ func (s *CalendarBFFService) GetCalendarEvents(ctx context.Context, req Calendar
 			response.JiraCount++
 		case models.CalendarEventTypeTimeOff:
 			response.TimeOffCount++
+		case models.CalendarEventTypeMilestone:
+			response.MilestoneCount++
 		}
 	}
 
 	return response, nil
 }
 
-// fetchJiraData retrieves Jira issues and epics from the configured Jira connection
-func (s *CalendarBFFService) fetchJiraData(ctx context.Context) ([]models.JiraIssue, bool) {
+// localizeCalendarEvents adds each event's local representation and drops all-day events
+// whose days fall outside the window in the viewer's timezone
+func localizeCalendarEvents(events []models.CalendarEvent, viewer *time.Location, start, end time.Time) []models.CalendarEvent {
+	kept := make([]models.CalendarEvent, 0, len(events))
+	for _, event := range events {
+		event.Localize(viewer)
+		if event.AllDay {
+			eventEnd := event.Start.In(viewer).AddDate(0, 0, 1)
+			if event.End != nil {
+				eventEnd = *event.End
+			}
+			if event.Start.After(end) || !eventEnd.After(start) {
+				continue
+			}
+		}
+		kept = append(kept, event)
+	}
+	return kept
+}
+
+// PaginateCalendarEvents orders each source's events by start time and returns up to limit events
+// per source after the cursor position, along with the cursor for the next page (nil when every
+// source is exhausted). Sources absent from a non-nil cursor are skipped. A zero limit returns
+// everything after the cursor.
+func PaginateCalendarEvents(bySource map[models.CalendarEventType][]models.CalendarEvent, cursor models.CalendarCursor, limit int) ([]models.CalendarEvent, models.CalendarCursor) {
+	events := []models.CalendarEvent{}
+	next := models.CalendarCursor{}
+
+	for _, source := range models.CalendarEventTypes {
+		position, resume := cursor[source]
+		if cursor != nil && !resume {
+			continue
+		}
+
+		sourceEvents := append([]models.CalendarEvent(nil), bySource[source]...)
+		sort.Slice(sourceEvents, func(i, j int) bool {
+			return calendarEventBefore(sourceEvents[i], sourceEvents[j].Start, sourceEvents[j].ID)
+		})
+
+		if resume {
+			skip := sort.Search(len(sourceEvents), func(i int) bool {
+				return calendarEventBefore(models.CalendarEvent{Start: position.Start, ID: position.ID}, sourceEvents[i].Start, sourceEvents[i].ID)
+			})
+			sourceEvents = sourceEvents[skip:]
+		}
+
+		if limit > 0 && len(sourceEvents) > limit {
+			sourceEvents = sourceEvents[:limit]
+			last := sourceEvents[limit-1]
+			next[source] = models.CalendarSourceCursor{Start: last.Start, ID: last.ID}
+		}
+		events = append(events, sourceEvents...)
+	}
+
+	if len(next) == 0 {
+		return events, nil
+	}
+	return events, next
+}
+
+// calendarEventBefore orders events by start time, breaking ties by ID
+func calendarEventBefore(event models.CalendarEvent, start time.Time, id string) bool {
+	if !event.Start.Equal(start) {
+		return event.Start.Before(start)
+	}
+	return event.ID < id
+}
+
+// CheckMeetingConflicts returns each attendee's overlapping meetings and approved time off for a
+// proposed meeting window. The organizer is always checked along with the requested attendees.
+func (s *CalendarBFFService) CheckMeetingConflicts(ctx context.Context, user *models.User, req *models.CheckMeetingConflictsRequest) (*models.MeetingConflictsResponse, error) {
+	userIDs := []int64{user.ID}
+	seen := map[int64]bool{user.ID: true}
+	for _, id := range req.AttendeeIDs {
+		if !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+
+	meetings, timeOff, err := s.calendarRepo.GetFreeBusy(ctx, userIDs, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.calendarRepo.GetWorkSchedules(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildMeetingConflicts(user, userIDs, schedules, meetings, timeOff, req), nil
+}
+
+// BuildMeetingConflicts groups overlapping meetings and time off by attendee.
+// Meeting titles are only exposed to viewers who organize or attend the meeting, or admins.
+// Time off days, working hours, and focus time are judged in each attendee's timezone;
+// attendees missing from schedules get the default schedule in UTC.
+func BuildMeetingConflicts(viewer *models.User, userIDs []int64, schedules map[int64]*models.WorkSchedule, meetings []models.Meeting, timeOff []models.TimeOffRequest, req *models.CheckMeetingConflictsRequest) *models.MeetingConflictsResponse {
+	byUser := make(map[int64]*models.AttendeeConflicts, len(userIDs))
+	response := &models.MeetingConflictsResponse{
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Attendees: make([]models.AttendeeConflicts, len(userIDs)),
+	}
+	locations := make(map[int64]*time.Location, len(userIDs))
+	for i, id := range userIDs {
+		schedule := scheduleFor(schedules, id)
+		loc := models.LocationOrUTC(schedule.Timezone)
+		locations[id] = loc
+		issue := schedule.Check(req.StartTime, req.EndTime)
+		response.Attendees[i] = models.AttendeeConflicts{
+			UserID:              id,
+			Meetings:            []models.MeetingConflict{},
+			TimeOff:             []models.TimeOffConflict{},
+			Timezone:            loc.String(),
+			LocalStartTime:      req.StartTime.In(loc).Format(time.RFC3339),
+			LocalEndTime:        req.EndTime.In(loc).Format(time.RFC3339),
+			OutsideWorkingHours: issue == models.SchedulingIssueOutsideWorkingHours,
+			InFocusTime:         issue == models.SchedulingIssueFocusTime,
+		}
+		byUser[id] = &response.Attendees[i]
+	}
+
+	for _, m := range meetings {
+		if req.ExcludeMeetingID != nil && m.ID == *req.ExcludeMeetingID {
+			continue
+		}
+		if !m.StartTime.Before(req.EndTime) || !m.EndTime.After(req.StartTime) {
+			continue
+		}
+
+		conflict := models.MeetingConflict{
+			MeetingID: m.ID,
+			StartTime: m.StartTime,
+			EndTime:   m.EndTime,
+		}
+		if canSeeMeetingDetails(viewer, &m) {
+			title := m.Title
+			conflict.Title = &title
+		}
+
+		// Declined attendees are free during the meeting
+		participants := map[int64]bool{m.CreatedByID: true}
+		for _, a := range m.Attendees {
+			participants[a.UserID] = a.ResponseStatus != models.ResponseStatusDeclined
+		}
+		for id, busy := range participants {
+			if attendee, ok := byUser[id]; ok && busy {
+				attendee.Meetings = append(attendee.Meetings, conflict)
+			}
+		}
+	}
+
+	for _, t := range timeOff {
+		attendee, ok := byUser[t.UserID]
+		if !ok {
+			continue
+		}
+		// Time off covers whole days in the attendee's timezone, through the end of its end date
+		loc := locations[t.UserID]
+		timeOffStart := models.LocalDayStart(t.StartDate, loc)
+		timeOffEnd := models.LocalDayStart(t.EndDate, loc).AddDate(0, 0, 1)
+		if !timeOffStart.Before(req.EndTime) || !timeOffEnd.After(req.StartTime) {
+			continue
+		}
+		attendee.TimeOff = append(attendee.TimeOff, models.TimeOffConflict{
+			TimeOffID:   t.ID,
+			RequestType: t.RequestType,
+			StartDate:   t.StartDate,
+			EndDate:     t.EndDate,
+		})
+	}
+
+	for i := range response.Attendees {
+		attendee := &response.Attendees[i]
+		sort.Slice(attendee.Meetings, func(a, b int) bool {
+			return attendee.Meetings[a].StartTime.Before(attendee.Meetings[b].StartTime)
+		})
+		attendee.Available = len(attendee.Meetings) == 0 && len(attendee.TimeOff) == 0
+		if !attendee.Available {
+			response.HasConflicts = true
+		}
+	}
+
+	return response
+}
+
+// scheduleFor returns a user's work schedule, or the default schedule in UTC when it wasn't loaded
+func scheduleFor(schedules map[int64]*models.WorkSchedule, userID int64) *models.WorkSchedule {
+	if schedule, ok := schedules[userID]; ok {
+		return schedule
+	}
+	return models.DefaultWorkSchedule(userID, models.DefaultTimezone)
+}
+
+// MeetingSchedulingWarnings flags the attendees a meeting falls outside working hours or in focus time for.
+// Organizers pick the time themselves, so they aren't warned about their own schedule.
+func (s *CalendarBFFService) MeetingSchedulingWarnings(ctx context.Context, meeting *models.Meeting) ([]models.SchedulingWarning, error) {
+	warnings := []models.SchedulingWarning{}
+	var attendeeIDs []int64
+	for _, a := range meeting.Attendees {
+		if a.UserID != meeting.CreatedByID {
+			attendeeIDs = append(attendeeIDs, a.UserID)
+		}
+	}
+	if len(attendeeIDs) == 0 {
+		return warnings, nil
+	}
+
+	schedules, err := s.calendarRepo.GetWorkSchedules(ctx, attendeeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range attendeeIDs {
+		schedule := scheduleFor(schedules, id)
+		if issue := schedule.Check(meeting.StartTime, meeting.EndTime); issue != "" {
+			warnings = append(warnings, models.SchedulingWarning{
+				UserID:         id,
+				Issue:          issue,
+				LocalStartTime: meeting.StartTime.In(models.LocationOrUTC(schedule.Timezone)).Format(time.RFC3339),
+			})
+		}
+	}
+	return warnings, nil
+}
+
+// SuggestMeetingTimes proposes slots within the request's window when the organizer and every
+// attendee are working, outside focus time, and free of meetings and time off
+func (s *CalendarBFFService) SuggestMeetingTimes(ctx context.Context, user *models.User, req *models.SuggestMeetingTimesRequest) (*models.SuggestMeetingTimesResponse, error) {
+	userIDs := []int64{user.ID}
+	seen := map[int64]bool{user.ID: true}
+	for _, id := range req.AttendeeIDs {
+		if !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+
+	meetings, timeOff, err := s.calendarRepo.GetFreeBusy(ctx, userIDs, req.Start, req.End)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.calendarRepo.GetWorkSchedules(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SuggestMeetingTimesResponse{
+		Duration:    int(req.Duration.Minutes()),
+		Suggestions: FindMeetingSlots(userIDs, schedules, meetings, timeOff, req),
+	}, nil
+}
+
+// busyInterval is a span of time a user can't meet
+type busyInterval struct {
+	start, end time.Time
+}
+
+// FindMeetingSlots walks the window in SuggestTimesStep increments and returns up to req.Limit
+// non-overlapping slots that suit every user. Declined meetings don't make a user busy, and time
+// off covers whole days in the user's timezone.
+func FindMeetingSlots(userIDs []int64, schedules map[int64]*models.WorkSchedule, meetings []models.Meeting, timeOff []models.TimeOffRequest, req *models.SuggestMeetingTimesRequest) []models.MeetingTimeSuggestion {
+	included := make(map[int64]bool, len(userIDs))
+	for _, id := range userIDs {
+		included[id] = true
+	}
+
+	busy := make(map[int64][]busyInterval, len(userIDs))
+	for _, m := range meetings {
+		participants := map[int64]bool{m.CreatedByID: true}
+		for _, a := range m.Attendees {
+			participants[a.UserID] = a.ResponseStatus != models.ResponseStatusDeclined
+		}
+		for id, isBusy := range participants {
+			if included[id] && isBusy {
+				busy[id] = append(busy[id], busyInterval{m.StartTime, m.EndTime})
+			}
+		}
+	}
+	for _, t := range timeOff {
+		if !included[t.UserID] {
+			continue
+		}
+		loc := models.LocationOrUTC(scheduleFor(schedules, t.UserID).Timezone)
+		busy[t.UserID] = append(busy[t.UserID], busyInterval{
+			models.LocalDayStart(t.StartDate, loc),
+			models.LocalDayStart(t.EndDate, loc).AddDate(0, 0, 1),
+		})
+	}
+
+	fits := func(start, end time.Time) bool {
+		for _, id := range userIDs {
+			if scheduleFor(schedules, id).Check(start, end) != "" {
+				return false
+			}
+			for _, b := range busy[id] {
+				if start.Before(b.end) && end.After(b.start) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	suggestions := []models.MeetingTimeSuggestion{}
+	start := req.Start.Truncate(models.SuggestTimesStep)
+	if start.Before(req.Start) {
+		start = start.Add(models.SuggestTimesStep)
+	}
+	for !start.Add(req.Duration).After(req.End) && len(suggestions) < req.Limit {
+		end := start.Add(req.Duration)
+		if !fits(start, end) {
+			start = start.Add(models.SuggestTimesStep)
+			continue
+		}
+		suggestions = append(suggestions, models.MeetingTimeSuggestion{StartTime: start.UTC(), EndTime: end.UTC()})
+		start = end
+	}
+	return suggestions
+}
+
+// canSeeMeetingDetails reports whether the viewer organizes or attends a meeting, or is an admin
+func canSeeMeetingDetails(viewer *models.User, meeting *models.Meeting) bool {
+	if viewer.IsAdmin() || meeting.CreatedByID == viewer.ID {
+		return true
+	}
+	for _, a := range meeting.Attendees {
+		if a.UserID == viewer.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchJiraData retrieves Jira issues and epics from the configured Jira connection.
+// It returns whatever issues loaded along with an error if any part of the fetch failed.
+func (s *CalendarBFFService) fetchJiraData(ctx context.Context) ([]models.JiraIssue, bool, error) {
 	if s.jiraRepo == nil || s.jiraClient == nil {
-		return nil, false
+		return nil, false, nil
 	}
 
 	settings, err := s.jiraRepo.Get(ctx)
-	if err != nil || settings == nil || settings.OAuthAccessToken == "" {
-		return nil, false
+	if err != nil {
+		return nil, false, err
+	}
+	if settings == nil || settings.OAuthAccessToken == "" {
+		return nil, false, nil
 	}
 
 	var jiraIssues []models.JiraIssue
+	var fetchErr error
 
 	// Fetch user's assigned tasks
 	issues, err := s.jiraClient.GetMyTasks(ctx, settings.CloudID, settings.OAuthAccessToken, 100)
-	if err == nil {
+	if err != nil {
+		fetchErr = err
+	} else {
 		jiraIssues = append(jiraIssues, issues...)
 	}
 
 	// Fetch epics (shown on calendar for visibility)
 	epics, err := s.jiraClient.GetEpics(ctx, settings.CloudID, settings.OAuthAccessToken, 50)
-	if err == nil {
+	if err != nil {
+		fetchErr = err
+	} else {
 		jiraIssues = append(jiraIssues, epics...)
 	}
 
-	return jiraIssues, true
+	return jiraIssues, true, fetchErr
 }
 
 // GetTaskRepo returns the underlying task repository for direct operations
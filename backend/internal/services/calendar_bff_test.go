@@ -0,0 +1,226 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestBuildMeetingConflicts(t *testing.T) {
+	start := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	meetings := []models.Meeting{
+		{
+			// Organized by the viewer, attended by user 2
+			ID: 1, Title: "Standup", CreatedByID: 1,
+			StartTime: start.Add(-30 * time.Minute), EndTime: start.Add(15 * time.Minute),
+			Attendees: []models.MeetingAttendee{{UserID: 2, ResponseStatus: models.ResponseStatusAccepted}},
+		},
+		{
+			// Private to user 3; user 2 declined so is not busy
+			ID: 2, Title: "1:1", CreatedByID: 3,
+			StartTime: start.Add(30 * time.Minute), EndTime: start.Add(90 * time.Minute),
+			Attendees: []models.MeetingAttendee{{UserID: 2, ResponseStatus: models.ResponseStatusDeclined}},
+		},
+		{
+			// Ends exactly when the window starts
+			ID: 3, Title: "Back to back", CreatedByID: 2,
+			StartTime: start.Add(-time.Hour), EndTime: start,
+		},
+		{
+			// Being rescheduled
+			ID: 4, Title: "Planning", CreatedByID: 3,
+			StartTime: start, EndTime: end,
+		},
+	}
+	timeOff := []models.TimeOffRequest{
+		{ID: 10, UserID: 4, RequestType: models.TimeOffTypeVacation, StartDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{ID: 11, UserID: 4, RequestType: models.TimeOffTypeVacation, StartDate: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+	}
+	exclude := int64(4)
+	req := &models.CheckMeetingConflictsRequest{StartTime: start, EndTime: end, AttendeeIDs: []int64{2, 3, 4}, ExcludeMeetingID: &exclude}
+	viewer := &models.User{ID: 1, Role: models.RoleSupervisor}
+
+	resp := BuildMeetingConflicts(viewer, []int64{1, 2, 3, 4}, nil, meetings, timeOff, req)
+
+	if !resp.HasConflicts {
+		t.Error("HasConflicts = false, want true")
+	}
+	if len(resp.Attendees) != 4 {
+		t.Fatalf("got %d attendees, want 4", len(resp.Attendees))
+	}
+
+	want := map[int64]struct {
+		meetingIDs []int64
+		timeOffIDs []int64
+	}{
+		1: {meetingIDs: []int64{1}},
+		2: {meetingIDs: []int64{1}},
+		3: {meetingIDs: []int64{2}},
+		4: {timeOffIDs: []int64{10}},
+	}
+
+	for _, attendee := range resp.Attendees {
+		w := want[attendee.UserID]
+		if len(attendee.Meetings) != len(w.meetingIDs) {
+			t.Errorf("user %d meetings = %+v, want ids %v", attendee.UserID, attendee.Meetings, w.meetingIDs)
+			continue
+		}
+		for i, id := range w.meetingIDs {
+			if attendee.Meetings[i].MeetingID != id {
+				t.Errorf("user %d meeting[%d] = %d, want %d", attendee.UserID, i, attendee.Meetings[i].MeetingID, id)
+			}
+		}
+		if len(attendee.TimeOff) != len(w.timeOffIDs) {
+			t.Errorf("user %d time off = %+v, want ids %v", attendee.UserID, attendee.TimeOff, w.timeOffIDs)
+		}
+		if attendee.Available != (len(w.meetingIDs) == 0 && len(w.timeOffIDs) == 0) {
+			t.Errorf("user %d Available = %v", attendee.UserID, attendee.Available)
+		}
+	}
+
+	// The viewer organizes meeting 1 but has no access to meeting 2
+	if title := resp.Attendees[0].Meetings[0].Title; title == nil || *title != "Standup" {
+		t.Errorf("meeting 1 title = %v, want Standup", title)
+	}
+	if title := resp.Attendees[2].Meetings[0].Title; title != nil {
+		t.Errorf("meeting 2 title = %q, want hidden", *title)
+	}
+}
+
+func TestBuildMeetingConflicts_AdminSeesTitles(t *testing.T) {
+	start := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	meetings := []models.Meeting{{ID: 1, Title: "Private", CreatedByID: 2, StartTime: start, EndTime: start.Add(time.Hour)}}
+	req := &models.CheckMeetingConflictsRequest{StartTime: start, EndTime: start.Add(time.Hour)}
+
+	resp := BuildMeetingConflicts(&models.User{ID: 1, Role: models.RoleAdmin}, []int64{1, 2}, nil, meetings, nil, req)
+
+	if !resp.Attendees[0].Available {
+		t.Error("admin should be available")
+	}
+	if title := resp.Attendees[1].Meetings[0].Title; title == nil || *title != "Private" {
+		t.Errorf("title = %v, want Private", title)
+	}
+}
+
+func TestBuildMeetingConflicts_AttendeeTimezones(t *testing.T) {
+	// Wednesday 15:00 UTC is midnight Thursday in Tokyo
+	start := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	req := &models.CheckMeetingConflictsRequest{StartTime: start, EndTime: start.Add(time.Hour)}
+	schedules := map[int64]*models.WorkSchedule{
+		1: models.DefaultWorkSchedule(1, "UTC"),
+		2: models.DefaultWorkSchedule(2, "Asia/Tokyo"),
+	}
+	// Both users are off on Thursday; that's already begun in Tokyo but not in UTC
+	thursday := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	timeOff := []models.TimeOffRequest{
+		{ID: 10, UserID: 1, StartDate: thursday, EndDate: thursday},
+		{ID: 11, UserID: 2, StartDate: thursday, EndDate: thursday},
+	}
+
+	resp := BuildMeetingConflicts(&models.User{ID: 1}, []int64{1, 2}, schedules, nil, timeOff, req)
+
+	utc, tokyo := resp.Attendees[0], resp.Attendees[1]
+	if !utc.Available || utc.OutsideWorkingHours {
+		t.Errorf("UTC attendee = %+v, want available within working hours", utc)
+	}
+	if tokyo.Available || len(tokyo.TimeOff) != 1 {
+		t.Errorf("Tokyo attendee time off = %+v, want Thursday's time off", tokyo.TimeOff)
+	}
+	if !tokyo.OutsideWorkingHours || tokyo.LocalStartTime != "2026-03-05T00:00:00+09:00" {
+		t.Errorf("Tokyo attendee = %+v, want midnight local time outside working hours", tokyo)
+	}
+}
+
+func TestFindMeetingSlots(t *testing.T) {
+	// Monday, March 2, 2026
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time { return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute) }
+
+	focus := models.DefaultWorkSchedule(2, "UTC")
+	focus.FocusBlocks = []models.FocusBlock{{Weekday: 1, StartMinute: 10 * 60, EndMinute: 12 * 60}}
+	schedules := map[int64]*models.WorkSchedule{1: models.DefaultWorkSchedule(1, "UTC"), 2: focus}
+	meetings := []models.Meeting{
+		{ID: 1, CreatedByID: 1, StartTime: at(9, 0), EndTime: at(9, 30)},
+		// User 2 declined, so this doesn't block them
+		{ID: 2, CreatedByID: 3, StartTime: at(12, 0), EndTime: at(13, 0), Attendees: []models.MeetingAttendee{{UserID: 2, ResponseStatus: models.ResponseStatusDeclined}}},
+		{ID: 3, CreatedByID: 2, StartTime: at(13, 0), EndTime: at(14, 0)},
+	}
+	req := &models.SuggestMeetingTimesRequest{Duration: time.Hour, Start: at(8, 50), End: at(17, 0), Limit: 3}
+
+	got := FindMeetingSlots([]int64{1, 2}, schedules, meetings, nil, req)
+
+	// 9:30 runs into user 2's focus time and 13:00 is their own meeting
+	want := []time.Time{at(12, 0), at(14, 0), at(15, 0)}
+	if len(got) != len(want) {
+		t.Fatalf("got %d suggestions, want %d: %+v", len(got), len(want), got)
+	}
+	for i, start := range want {
+		if !got[i].StartTime.Equal(start) || !got[i].EndTime.Equal(start.Add(time.Hour)) {
+			t.Errorf("suggestion %d = %v-%v, want to start at %v", i, got[i].StartTime, got[i].EndTime, start)
+		}
+	}
+}
+
+func TestFindMeetingSlots_TimeOff(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	timeOff := []models.TimeOffRequest{{ID: 1, UserID: 2, StartDate: day, EndDate: day}}
+	req := &models.SuggestMeetingTimesRequest{Duration: 30 * time.Minute, Start: day, End: day.AddDate(0, 0, 2), Limit: 1}
+
+	got := FindMeetingSlots([]int64{1, 2}, nil, nil, timeOff, req)
+
+	if len(got) != 1 || !got[0].StartTime.Equal(day.AddDate(0, 0, 1).Add(9*time.Hour)) {
+		t.Errorf("suggestions = %+v, want 9:00 the day after the time off", got)
+	}
+}
+
+func TestPaginateCalendarEvents(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	bySource := map[models.CalendarEventType][]models.CalendarEvent{
+		models.CalendarEventTypeTask: {
+			{ID: "task-3", Type: models.CalendarEventTypeTask, Start: day(5)},
+			{ID: "task-1", Type: models.CalendarEventTypeTask, Start: day(2)},
+			{ID: "task-2", Type: models.CalendarEventTypeTask, Start: day(2)},
+		},
+		models.CalendarEventTypeMeeting: {
+			{ID: "meeting-1", Type: models.CalendarEventTypeMeeting, Start: day(3)},
+		},
+	}
+
+	ids := func(events []models.CalendarEvent) []string {
+		out := make([]string, len(events))
+		for i, e := range events {
+			out[i] = e.ID
+		}
+		return out
+	}
+
+	// Without a limit everything comes back in one page
+	all, next := PaginateCalendarEvents(bySource, nil, 0)
+	if len(all) != 4 || next != nil {
+		t.Fatalf("unpaginated = %v, next = %v", ids(all), next)
+	}
+
+	first, next := PaginateCalendarEvents(bySource, nil, 2)
+	if got := ids(first); len(got) != 3 || got[0] != "task-1" || got[1] != "task-2" || got[2] != "meeting-1" {
+		t.Fatalf("first page = %v", got)
+	}
+	if _, ok := next[models.CalendarEventTypeMeeting]; ok || next[models.CalendarEventTypeTask].ID != "task-2" {
+		t.Fatalf("next cursor = %+v, want only tasks to continue", next)
+	}
+
+	// The cursor survives encoding, and exhausted sources are not repeated
+	decoded, err := models.DecodeCalendarCursor(next.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCalendarCursor() error = %v", err)
+	}
+	second, next := PaginateCalendarEvents(bySource, decoded, 2)
+	if got := ids(second); len(got) != 1 || got[0] != "task-3" {
+		t.Errorf("second page = %v", got)
+	}
+	if next != nil {
+		t.Errorf("next cursor = %+v, want nil after the last page", next)
+	}
+}
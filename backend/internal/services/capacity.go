@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// CapacityIssueSource provides people's open, estimated work from Jira
+type CapacityIssueSource interface {
+	GetEstimatedIssuesByAccountID(accountID string, dueBy time.Time, storyPointsField string) ([]models.JiraSprintIssue, error)
+}
+
+// CapacityService compares people's availability with the Jira work assigned to them
+type CapacityService struct {
+	squadRepo        repository.SquadRepository
+	userRepo         repository.UserRepository
+	timeOffRepo      repository.TimeOffRepository
+	holidays         []string
+	storyPointsField string
+	pointsPerDay     float64
+}
+
+// NewCapacityService creates a new capacity service
+func NewCapacityService(
+	squadRepo repository.SquadRepository,
+	userRepo repository.UserRepository,
+	timeOffRepo repository.TimeOffRepository,
+	cfg *config.Config,
+) *CapacityService {
+	pointsPerDay := cfg.SprintPointsPerDay
+	if pointsPerDay <= 0 {
+		pointsPerDay = 1
+	}
+	return &CapacityService{
+		squadRepo:        squadRepo,
+		userRepo:         userRepo,
+		timeOffRepo:      timeOffRepo,
+		holidays:         cfg.CompanyHolidays,
+		storyPointsField: cfg.JiraStoryPointsField,
+		pointsPerDay:     pointsPerDay,
+	}
+}
+
+// Plan builds the capacity plan for the days from start to end inclusive. With a squad it covers
+// the squad's members; without one, the current user and their direct reports. Members' Jira
+// issues are fetched at most concurrency at a time.
+func (s *CapacityService) Plan(ctx context.Context, currentUser *models.User, source CapacityIssueSource, squadID *int64, start, end time.Time, concurrency int) (*models.CapacityPlan, error) {
+	var squad *models.Squad
+	var members []models.User
+	if squadID != nil {
+		var err error
+		squad, err = s.squadRepo.GetByID(ctx, *squadID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get squad: %w", err)
+		}
+		if squad == nil {
+			return nil, ErrSquadNotFound
+		}
+		members, err = s.squadRepo.GetUsersBySquadID(ctx, squad.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get squad members: %w", err)
+		}
+		if !canCheckSquad(currentUser, members) {
+			return nil, ErrSquadAccessDenied
+		}
+	} else {
+		reports, err := s.userRepo.GetDirectReportsBySupervisorID(ctx, currentUser.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get direct reports: %w", err)
+		}
+		members = append([]models.User{*currentUser}, reports...)
+	}
+
+	userIDs := make([]int64, len(members))
+	for i, m := range members {
+		userIDs[i] = m.ID
+	}
+	timeOff, err := s.timeOffRepo.GetApprovedForUsers(ctx, userIDs, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time off: %w", err)
+	}
+
+	var accountIDs []string
+	for _, m := range members {
+		if m.JiraAccountID != nil && *m.JiraAccountID != "" {
+			accountIDs = append(accountIDs, *m.JiraAccountID)
+		}
+	}
+
+	issuesByAccount := make(map[string][]models.JiraSprintIssue, len(accountIDs))
+	var mu sync.Mutex
+	var fetchErr error
+	fetchConcurrently(accountIDs, concurrency, func(accountID string) {
+		issues, err := source.GetEstimatedIssuesByAccountID(accountID, end, s.storyPointsField)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if fetchErr == nil {
+				fetchErr = fmt.Errorf("failed to get issues: %w", err)
+			}
+			return
+		}
+		issuesByAccount[accountID] = issues
+	})
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+
+	return BuildCapacityPlan(squad, members, issuesByAccount, timeOff, s.holidays, s.pointsPerDay, start, end), nil
+}
+
+// BuildCapacityPlan computes each person's available business days from start to end inclusive,
+// less company holidays and approved time off, and compares them with the story points of the
+// open issues assigned to them. Someone is overallocated when their assigned points need more
+// days than they have.
+func BuildCapacityPlan(
+	squad *models.Squad,
+	members []models.User,
+	issuesByAccount map[string][]models.JiraSprintIssue,
+	timeOff []models.TimeOffRequest,
+	holidays []string,
+	pointsPerDay float64,
+	start, end time.Time,
+) *models.CapacityPlan {
+	startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	endDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	workingDays, rangeHolidays := sprintWorkingDays(startDay, endDay.AddDate(0, 0, 1), holidays)
+
+	plan := &models.CapacityPlan{
+		Start:        startDay.Format("2006-01-02"),
+		End:          endDay.Format("2006-01-02"),
+		WorkingDays:  len(workingDays),
+		Holidays:     rangeHolidays,
+		PointsPerDay: pointsPerDay,
+		People:       make([]models.PersonCapacity, 0, len(members)),
+		Warnings:     []string{},
+	}
+	if squad != nil {
+		plan.SquadID = &squad.ID
+		plan.SquadName = squad.Name
+	}
+
+	timeOffByUser := make(map[int64][]models.TimeOffRequest)
+	for _, t := range timeOff {
+		timeOffByUser[t.UserID] = append(timeOffByUser[t.UserID], t)
+	}
+
+	unmapped := 0
+	for _, m := range members {
+		timeOffDays := 0
+		for day := range workingDays {
+			if isOnTimeOff(timeOffByUser[m.ID], day) {
+				timeOffDays++
+			}
+		}
+		available := len(workingDays) - timeOffDays
+
+		person := models.PersonCapacity{
+			UserID:         m.ID,
+			FirstName:      m.FirstName,
+			LastName:       m.LastName,
+			JiraAccountID:  m.JiraAccountID,
+			AvailableDays:  available,
+			TimeOffDays:    timeOffDays,
+			CapacityPoints: float64(available) * pointsPerDay,
+			Issues:         []models.JiraSprintIssue{},
+		}
+		if m.JiraAccountID != nil && *m.JiraAccountID != "" {
+			for _, issue := range issuesByAccount[*m.JiraAccountID] {
+				if issue.StoryPoints == nil {
+					person.UnestimatedIssues++
+				} else {
+					person.AssignedPoints += *issue.StoryPoints
+				}
+				person.Issues = append(person.Issues, issue)
+			}
+		} else {
+			unmapped++
+		}
+		person.AssignedDays = math.Round(person.AssignedPoints/pointsPerDay*10) / 10
+		if person.CapacityPoints > 0 {
+			person.LoadPercent = math.Round(person.AssignedPoints/person.CapacityPoints*1000) / 10
+		}
+		person.Overallocated = person.AssignedPoints > person.CapacityPoints
+
+		if person.Overallocated {
+			plan.Overallocated++
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s %s is assigned %.1f points with capacity for %.1f",
+				m.FirstName, m.LastName, person.AssignedPoints, person.CapacityPoints))
+		}
+		plan.People = append(plan.People, person)
+	}
+
+	// Overallocated people first, then the most loaded
+	sort.SliceStable(plan.People, func(i, j int) bool {
+		a, b := plan.People[i], plan.People[j]
+		if a.Overallocated != b.Overallocated {
+			return a.Overallocated
+		}
+		return a.LoadPercent > b.LoadPercent
+	})
+
+	if unmapped > 0 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("%d people are not linked to a Jira account", unmapped))
+	}
+	return plan
+}
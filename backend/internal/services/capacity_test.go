@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+// fakeCapacitySource is an in-memory CapacityIssueSource for testing
+type fakeCapacitySource struct {
+	mu     sync.Mutex
+	issues map[string][]models.JiraSprintIssue
+	failOn string
+	asked  []string
+}
+
+func (f *fakeCapacitySource) GetEstimatedIssuesByAccountID(accountID string, dueBy time.Time, storyPointsField string) ([]models.JiraSprintIssue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.asked = append(f.asked, accountID)
+	if accountID == f.failOn {
+		return nil, errors.New("jira unavailable")
+	}
+	return f.issues[accountID], nil
+}
+
+func testCapacityIssues() map[string][]models.JiraSprintIssue {
+	return map[string][]models.JiraSprintIssue{
+		"a": {{Key: "P-1", StoryPoints: ptrFloat(8)}, {Key: "P-2"}},
+		"b": {{Key: "P-3", StoryPoints: ptrFloat(5)}},
+	}
+}
+
+func TestBuildCapacityPlan(t *testing.T) {
+	members := append(testSquadMembers(), models.User{ID: 3, FirstName: "Grace", LastName: "Hopper"})
+	timeOff := []models.TimeOffRequest{
+		{UserID: 1, StartDate: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)
+
+	plan := BuildCapacityPlan(&models.Squad{ID: 3, Name: "Platform"}, members, testCapacityIssues(), timeOff, []string{"2026-03-06"}, 1, start, end)
+
+	if plan.WorkingDays != 9 || len(plan.Holidays) != 1 {
+		t.Errorf("WorkingDays = %d with holidays %v, want 9 after one holiday", plan.WorkingDays, plan.Holidays)
+	}
+	if plan.SquadID == nil || *plan.SquadID != 3 || plan.Start != "2026-03-02" || plan.End != "2026-03-13" {
+		t.Errorf("plan = %+v, want squad 3 from 2026-03-02 to 2026-03-13", plan)
+	}
+	if plan.Overallocated != 1 || len(plan.People) != 3 {
+		t.Fatalf("Overallocated = %d of %d people, want 1 of 3", plan.Overallocated, len(plan.People))
+	}
+
+	ada := plan.People[0]
+	if ada.UserID != 1 || !ada.Overallocated || ada.AvailableDays != 7 || ada.TimeOffDays != 2 {
+		t.Errorf("Ada = %+v, want first, overallocated with 7 days after 2 days off", ada)
+	}
+	if ada.AssignedPoints != 8 || ada.UnestimatedIssues != 1 || len(ada.Issues) != 2 || ada.LoadPercent != 114.3 {
+		t.Errorf("Ada = %+v, want 8 points and one unestimated issue at 114.3%%", ada)
+	}
+	alan := plan.People[1]
+	if alan.UserID != 2 || alan.Overallocated || alan.AvailableDays != 9 || alan.AssignedDays != 5 {
+		t.Errorf("Alan = %+v, want 5 of 9 days assigned", alan)
+	}
+	if grace := plan.People[2]; grace.AssignedPoints != 0 || grace.Issues == nil {
+		t.Errorf("Grace = %+v, want no assigned work", grace)
+	}
+	if len(plan.Warnings) != 2 {
+		t.Errorf("Warnings = %v, want Ada's overallocation and Grace's missing Jira account", plan.Warnings)
+	}
+}
+
+func TestCapacityService_Plan(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)
+	squad := int64(3)
+	unknownSquad := int64(4)
+
+	tests := []struct {
+		name       string
+		user       *models.User
+		squadID    *int64
+		wantErr    error
+		wantPeople int
+	}{
+		{"admin plans a squad", &models.User{ID: 99, Role: models.RoleAdmin}, &squad, nil, 2},
+		{"supervisor plans their team", &models.User{ID: 10, Role: models.RoleSupervisor, JiraAccountID: ptrString("c")}, nil, nil, 3},
+		{"supervisor with no members in squad", &models.User{ID: 11, Role: models.RoleSupervisor}, &squad, ErrSquadAccessDenied, 0},
+		{"unknown squad", &models.User{ID: 99, Role: models.RoleAdmin}, &unknownSquad, ErrSquadNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			squadRepo := mocks.NewMockSquadRepository()
+			squadRepo.Squads[3] = &models.Squad{ID: 3, Name: "Platform"}
+			squadRepo.GetUsersBySquadIDFunc = func(ctx context.Context, squadID int64) ([]models.User, error) {
+				return testSquadMembers(), nil
+			}
+			userRepo := mocks.NewMockUserRepository()
+			for _, m := range testSquadMembers() {
+				userRepo.Users[m.ID] = &m
+			}
+			source := &fakeCapacitySource{issues: testCapacityIssues()}
+			service := NewCapacityService(squadRepo, userRepo, mocks.NewMockTimeOffRepository(), &config.Config{})
+
+			plan, err := service.Plan(context.Background(), tt.user, source, tt.squadID, start, end, 2)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Plan() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Plan() unexpected error: %v", err)
+			}
+			if len(plan.People) != tt.wantPeople || len(source.asked) != tt.wantPeople {
+				t.Errorf("planned %d people with %d Jira lookups, want %d", len(plan.People), len(source.asked), tt.wantPeople)
+			}
+			if plan.PointsPerDay != 1 {
+				t.Errorf("PointsPerDay = %v, want the default of 1", plan.PointsPerDay)
+			}
+		})
+	}
+}
+
+func TestCapacityService_Plan_FailsWhenAnyMembersIssuesFail(t *testing.T) {
+	squadRepo := mocks.NewMockSquadRepository()
+	squadRepo.Squads[3] = &models.Squad{ID: 3, Name: "Platform"}
+	squadRepo.GetUsersBySquadIDFunc = func(ctx context.Context, squadID int64) ([]models.User, error) {
+		return testSquadMembers(), nil
+	}
+	source := &fakeCapacitySource{issues: testCapacityIssues(), failOn: "b"}
+	service := NewCapacityService(squadRepo, mocks.NewMockUserRepository(), mocks.NewMockTimeOffRepository(), &config.Config{})
+
+	squad := int64(3)
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	plan, err := service.Plan(context.Background(), &models.User{ID: 99, Role: models.RoleAdmin}, source, &squad, start, start.AddDate(0, 0, 11), 2)
+	if err == nil {
+		t.Fatalf("Plan() = %+v, want an error", plan)
+	}
+}
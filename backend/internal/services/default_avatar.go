@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/storage"
+)
+
+const (
+	// defaultAvatarBatchSize is how many users are loaded at a time when filling in default avatars
+	defaultAvatarBatchSize = 100
+	// defaultAvatarSweepInterval is how often users missing a default avatar are looked for
+	// between user changes
+	defaultAvatarSweepInterval = time.Hour
+	// gravatarBaseURL serves Gravatar images by email hash
+	gravatarBaseURL = "https://www.gravatar.com/avatar/"
+	// maxGravatarBytes bounds a downloaded Gravatar image
+	maxGravatarBytes = 1 << 20
+)
+
+// defaultAvatarColors are the backgrounds of initials avatars, picked by a hash of the user's email
+var defaultAvatarColors = []string{
+	"#2563eb", "#7c3aed", "#db2777", "#dc2626", "#ea580c",
+	"#ca8a04", "#16a34a", "#0d9488", "#0891b2", "#4f46e5",
+}
+
+// DefaultAvatarService gives every user without an uploaded avatar a generated one: their
+// Gravatar when enabled and they have one, otherwise an SVG of their initials. Generated images
+// are cached in storage under a content-addressed key; without storage, initials are stored as a
+// data URL and Gravatars are linked directly.
+type DefaultAvatarService struct {
+	userRepo repository.UserRepository
+	storage  storage.Storage
+	tenantID string
+	gravatar bool
+	// gravatarBase is the base URL Gravatar images are fetched from
+	gravatarBase string
+	client       *http.Client
+	wake         chan struct{}
+	logger       *logger.Logger
+}
+
+// NewDefaultAvatarService creates a new default avatar service. store may be nil.
+func NewDefaultAvatarService(userRepo repository.UserRepository, store storage.Storage, tenantID string, gravatar bool) *DefaultAvatarService {
+	return &DefaultAvatarService{
+		userRepo:     userRepo,
+		storage:      store,
+		tenantID:     tenantID,
+		gravatar:     gravatar,
+		gravatarBase: gravatarBaseURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		wake:         make(chan struct{}, 1),
+		logger:       logger.Default().WithComponent("default-avatars"),
+	}
+}
+
+// Start fills in missing default avatars now, whenever users change, and periodically until workers are stopped
+func (s *DefaultAvatarService) Start(workers *lifecycle.Group, broker *events.Broker) {
+	if broker != nil {
+		sub, unsubscribe := broker.Subscribe()
+		workers.Go("default-avatar-events", func(ctx context.Context) {
+			defer unsubscribe()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event, ok := <-sub:
+					if !ok {
+						return
+					}
+					if event.Type == events.UserChanged {
+						s.Wake()
+					}
+				}
+			}
+		})
+	}
+
+	workers.Go("default-avatars", func(ctx context.Context) {
+		sweep := time.NewTicker(defaultAvatarSweepInterval)
+		defer sweep.Stop()
+
+		for {
+			s.FillMissing(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.wake:
+			case <-sweep.C:
+			}
+		}
+	})
+}
+
+// Wake makes the worker look for users missing a default avatar without waiting for the next sweep
+func (s *DefaultAvatarService) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// FillMissing generates a default avatar for every user without an avatar or a default and returns
+// how many were set. Users whose avatar fails to generate are retried on the next sweep.
+func (s *DefaultAvatarService) FillMissing(ctx context.Context) int {
+	filled := 0
+	var afterID int64
+	for ctx.Err() == nil {
+		users, err := s.userRepo.ListMissingDefaultAvatars(ctx, afterID, defaultAvatarBatchSize)
+		if err != nil {
+			s.logger.LogError(ctx, "Failed to list users missing default avatars", err)
+			return filled
+		}
+		for i := range users {
+			user := &users[i]
+			afterID = user.ID
+			url, err := s.Generate(ctx, user)
+			if err != nil {
+				s.logger.LogError(ctx, "Failed to generate default avatar", err, "user_id", user.ID)
+				continue
+			}
+			if err := s.userRepo.SetDefaultAvatar(ctx, user.ID, url); err != nil {
+				s.logger.LogError(ctx, "Failed to set default avatar", err, "user_id", user.ID)
+				continue
+			}
+			filled++
+		}
+		if len(users) < defaultAvatarBatchSize {
+			break
+		}
+	}
+	if filled > 0 {
+		s.logger.Info("Default avatars generated", "count", filled)
+	}
+	return filled
+}
+
+// Generate returns the URL of a user's default avatar, storing the image if needed. Users whose
+// Gravatar can't be fetched get initials.
+func (s *DefaultAvatarService) Generate(ctx context.Context, user *models.User) (string, error) {
+	if s.gravatar {
+		url, ok, err := s.gravatarURL(ctx, user)
+		if err != nil {
+			s.logger.Warn("Gravatar lookup failed, using initials", "user_id", user.ID, "error", err)
+		}
+		if ok {
+			return url, nil
+		}
+	}
+
+	svg := InitialsSVG(user)
+	if s.storage == nil {
+		return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(svg), nil
+	}
+	return s.store(ctx, user.ID, "initials", svg, "image/svg+xml", ".svg")
+}
+
+// gravatarURL fetches the user's Gravatar, returning false if they don't have one
+func (s *DefaultAvatarService) gravatarURL(ctx context.Context, user *models.User) (string, bool, error) {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(user.Email))))
+	// d=404 makes Gravatar fail for emails without an image instead of serving a placeholder
+	url := s.gravatarBase + hex.EncodeToString(sum[:]) + "?s=512&d=404"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", false, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to fetch Gravatar: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("gravatar returned status %d", resp.StatusCode)
+	}
+	if s.storage == nil {
+		return url, true, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxGravatarBytes))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read Gravatar: %w", err)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", false, fmt.Errorf("gravatar returned content type %q", contentType)
+	}
+	stored, err := s.store(ctx, user.ID, "gravatar", data, contentType, storage.GetExtension(contentType))
+	if err != nil {
+		return "", false, err
+	}
+	return stored, true, nil
+}
+
+// store uploads an image under a key derived from its content, so regenerating an unchanged image
+// overwrites the same file
+func (s *DefaultAvatarService) store(ctx context.Context, userID int64, kind string, data []byte, contentType, extension string) (string, error) {
+	sum := sha256.Sum256(data)
+	key := storage.GenerateDefaultAvatarKey(s.tenantID, userID, kind+"-"+hex.EncodeToString(sum[:8])+extension)
+	url, err := s.storage.Upload(ctx, key, data, contentType)
+	if err != nil {
+		return "", fmt.Errorf("failed to store default avatar: %w", err)
+	}
+	return url, nil
+}
+
+// InitialsSVG renders a square avatar of the user's initials on a background color picked from
+// their email, so the same user always gets the same image
+func InitialsSVG(user *models.User) []byte {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.ToLower(user.Email)))
+	color := defaultAvatarColors[h.Sum32()%uint32(len(defaultAvatarColors))]
+
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="128" height="128" viewBox="0 0 128 128">`+
+		`<rect width="128" height="128" fill="%s"/>`+
+		`<text x="50%%" y="50%%" dy=".35em" text-anchor="middle" fill="#ffffff" `+
+		`font-family="Helvetica, Arial, sans-serif" font-size="52" font-weight="600">%s</text></svg>`,
+		color, html.EscapeString(initials(user))))
+}
+
+// initials returns the first letters of the user's first and last names, or of their email
+// when they have no name
+func initials(user *models.User) string {
+	var b strings.Builder
+	for _, part := range []string{user.FirstName, user.LastName} {
+		if r, _ := utf8.DecodeRuneInString(strings.TrimSpace(part)); r != utf8.RuneError {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	if b.Len() == 0 {
+		if r, _ := utf8.DecodeRuneInString(user.Email); r != utf8.RuneError {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	if b.Len() == 0 {
+		return "?"
+	}
+	return b.String()
+}
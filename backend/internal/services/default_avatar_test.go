@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestInitialsSVG(t *testing.T) {
+	tests := []struct {
+		name string
+		user models.User
+		want string
+	}{
+		{"first and last name", models.User{FirstName: "ada", LastName: "Lovelace", Email: "ada@example.com"}, ">AL<"},
+		{"no last name", models.User{FirstName: "Émile", Email: "e@example.com"}, ">É<"},
+		{"no name", models.User{Email: "zed@example.com"}, ">Z<"},
+		{"nothing", models.User{}, ">?<"},
+		{"escaped", models.User{FirstName: "<script>", LastName: "&", Email: "x@example.com"}, ">&lt;&amp;<"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svg := string(InitialsSVG(&tt.user))
+			if !strings.Contains(svg, tt.want) {
+				t.Errorf("InitialsSVG() = %s, want it to contain %s", svg, tt.want)
+			}
+		})
+	}
+
+	user := &models.User{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com"}
+	if string(InitialsSVG(user)) != string(InitialsSVG(user)) {
+		t.Error("InitialsSVG() should be deterministic")
+	}
+}
+
+func TestDefaultAvatarService_FillMissing(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	uploaded := "https://example.com/avatar.png"
+	userRepo.AddUser(&models.User{ID: 1, FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com"})
+	userRepo.AddUser(&models.User{ID: 2, FirstName: "Grace", LastName: "Hopper", Email: "grace@example.com", AvatarURL: &uploaded})
+
+	var keys []string
+	store := &MockStorage{
+		UploadFunc: func(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+			if contentType != "image/svg+xml" {
+				t.Errorf("content type = %s, want image/svg+xml", contentType)
+			}
+			keys = append(keys, key)
+			return "https://example.com/" + key, nil
+		},
+	}
+	service := NewDefaultAvatarService(userRepo, store, "acme", false)
+
+	if filled := service.FillMissing(context.Background()); filled != 1 {
+		t.Fatalf("FillMissing() = %d, want 1", filled)
+	}
+	if len(keys) != 1 || !strings.HasPrefix(keys[0], "tenants/acme/users/1/avatars/default/initials-") || !strings.HasSuffix(keys[0], ".svg") {
+		t.Errorf("uploaded keys = %v", keys)
+	}
+	if got := userRepo.Users[1].DefaultAvatarURL; got == nil || *got != "https://example.com/"+keys[0] {
+		t.Errorf("default avatar = %v, want the stored SVG", got)
+	}
+	if userRepo.Users[2].DefaultAvatarURL != nil {
+		t.Error("users with an uploaded avatar should not get a default")
+	}
+
+	if filled := service.FillMissing(context.Background()); filled != 0 {
+		t.Errorf("second FillMissing() = %d, want 0", filled)
+	}
+}
+
+func TestDefaultAvatarService_Generate(t *testing.T) {
+	gravatar := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("d") != "404" {
+			t.Errorf("expected d=404, got %s", r.URL.RawQuery)
+		}
+		// sha256("has@example.com")
+		if strings.HasSuffix(r.URL.Path, "/05e173496b0700e4e64dc96c242f97a1f34daa5e063403a1b4c3aee73267379c") {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("png"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer gravatar.Close()
+
+	t.Run("without storage initials become a data URL", func(t *testing.T) {
+		service := NewDefaultAvatarService(mocks.NewMockUserRepository(), nil, "acme", false)
+		url, err := service.Generate(context.Background(), &models.User{FirstName: "Ada", LastName: "Lovelace"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(url, "data:image/svg+xml;base64,") {
+			t.Errorf("Generate() = %s, want a data URL", url)
+		}
+	})
+
+	t.Run("falls back to initials without a Gravatar", func(t *testing.T) {
+		service := NewDefaultAvatarService(mocks.NewMockUserRepository(), &MockStorage{}, "acme", true)
+		service.gravatarBase = gravatar.URL + "/avatar/"
+		url, err := service.Generate(context.Background(), &models.User{ID: 3, FirstName: "No", Email: "none@example.com"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasSuffix(url, ".svg") {
+			t.Errorf("Generate() = %s, want initials", url)
+		}
+	})
+
+	t.Run("stores a Gravatar", func(t *testing.T) {
+		service := NewDefaultAvatarService(mocks.NewMockUserRepository(), &MockStorage{}, "acme", true)
+		service.gravatarBase = gravatar.URL + "/avatar/"
+		url, err := service.Generate(context.Background(), &models.User{ID: 4, Email: " Has@Example.com"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(url, "users/4/avatars/default/gravatar-") || !strings.HasSuffix(url, ".png") {
+			t.Errorf("Generate() = %s, want the stored Gravatar", url)
+		}
+	})
+
+	t.Run("without storage Gravatars are linked directly", func(t *testing.T) {
+		service := NewDefaultAvatarService(mocks.NewMockUserRepository(), nil, "acme", true)
+		service.gravatarBase = gravatar.URL + "/avatar/"
+		url, err := service.Generate(context.Background(), &models.User{ID: 4, Email: "has@example.com"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(url, gravatar.URL) {
+			t.Errorf("Generate() = %s, want the Gravatar URL", url)
+		}
+	})
+}
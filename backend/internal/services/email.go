@@ -3,10 +3,15 @@ package services
 import (
 	"context"
 	"fmt"
+	"html"
+	"maps"
+	"strings"
 	"time"
 
 	"github.com/resend/resend-go/v2"
 	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/emailtemplates"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
 )
 
 // EmailService handles sending emails via Resend
@@ -16,6 +21,7 @@ type EmailService struct {
 	fromName    string
 	frontendURL string
 	timeout     time.Duration
+	templates   *EmailTemplateService // Organizations' customized templates; nil sends the built-in ones
 }
 
 // NewEmailService creates a new email service with Resend
@@ -30,40 +36,66 @@ func NewEmailService(cfg *config.Config) *EmailService {
 	}
 }
 
-// SendInvitation sends an invitation email to the specified email address
-func (s *EmailService) SendInvitation(ctx context.Context, email, token, role, inviterName string) error {
-	inviteLink := fmt.Sprintf("%s/invite/%s", s.frontendURL, token)
-
-	subject := fmt.Sprintf("%s has invited you to join Manager Dashboard", inviterName)
+// WithTemplates renders invitation and notification emails with organizations' customized templates
+func (s *EmailService) WithTemplates(templates *EmailTemplateService) *EmailService {
+	s.templates = templates
+	return s
+}
 
-	htmlContent := s.buildInvitationHTML(inviterName, role, inviteLink)
-	textContent := s.buildInvitationText(inviterName, role, inviteLink)
+// SendInvitation sends an invitation email to the specified email address, in the invitation's language
+func (s *EmailService) SendInvitation(ctx context.Context, email, token, role, inviterName, language string) error {
+	rendered, err := s.render(ctx, models.EmailTemplateInvitation, language, map[string]string{
+		"InviterName": inviterName,
+		"Role":        role,
+		"InviteLink":  fmt.Sprintf("%s/invite/%s", s.frontendURL, token),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render invitation email: %w", err)
+	}
 
 	params := &resend.SendEmailRequest{
 		From:    fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail),
 		To:      []string{email},
-		Subject: subject,
-		Html:    htmlContent,
-		Text:    textContent,
+		Subject: rendered.Subject,
+		Html:    rendered.HTML,
+		Text:    rendered.Text,
 	}
 
-	// Execute email send with timeout to prevent indefinite blocking
-	// The Resend SDK doesn't support context cancellation, so we use a goroutine pattern
-	type result struct {
-		err error
+	return s.send(ctx, params, "invitation")
+}
+
+// SendNotification sends one of the customizable emails, rendered in the recipient's language with vars
+func (s *EmailService) SendNotification(ctx context.Context, to string, key models.EmailTemplateKey, language string, vars map[string]string) error {
+	rendered, err := s.render(ctx, key, language, vars)
+	if err != nil {
+		return fmt.Errorf("failed to render %s email: %w", key, err)
 	}
-	resultCh := make(chan result, 1)
 
+	params := &resend.SendEmailRequest{
+		From:    fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail),
+		To:      []string{to},
+		Subject: rendered.Subject,
+		Html:    rendered.HTML,
+		Text:    rendered.Text,
+	}
+
+	return s.send(ctx, params, string(key))
+}
+
+// send sends an email, giving up after the service's timeout or when ctx is done. The Resend SDK
+// doesn't support context cancellation, so the send runs in a goroutine; what names the email in
+// errors.
+func (s *EmailService) send(ctx context.Context, params *resend.SendEmailRequest, what string) error {
+	errCh := make(chan error, 1)
 	go func() {
 		_, err := s.client.Emails.Send(params)
-		resultCh <- result{err: err}
+		errCh <- err
 	}()
 
-	// Wait for either completion, timeout, or context cancellation
 	select {
-	case res := <-resultCh:
-		if res.err != nil {
-			return fmt.Errorf("failed to send invitation email: %w", res.err)
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("failed to send %s email: %w", what, err)
 		}
 		return nil
 	case <-time.After(s.timeout):
@@ -73,37 +105,76 @@ func (s *EmailService) SendInvitation(ctx context.Context, email, token, role, i
 	}
 }
 
-// buildInvitationHTML creates the HTML email template
-func (s *EmailService) buildInvitationHTML(inviterName, role, inviteLink string) string {
+// render fills in an email's template with vars and the dashboard's address. Without a template
+// service the built-in templates are used.
+func (s *EmailService) render(ctx context.Context, key models.EmailTemplateKey, language string, vars map[string]string) (*models.RenderedEmail, error) {
+	data := maps.Clone(vars)
+	if data == nil {
+		data = map[string]string{}
+	}
+	data["DashboardURL"] = s.frontendURL
+
+	if s.templates != nil {
+		return s.templates.Render(ctx, key, language, data)
+	}
+	t, ok := emailtemplates.Default(key, language)
+	if !ok {
+		if t, ok = emailtemplates.Default(key, models.DefaultLanguage); !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownEmailTemplate, key)
+		}
+	}
+	return emailtemplates.Render(t, data)
+}
+
+// SendPasswordReset sends a password reset email to the specified email address
+func (s *EmailService) SendPasswordReset(ctx context.Context, email, resetLink string) error {
+	subject := "Set Your Password - Manager Dashboard"
+
+	htmlContent := s.buildPasswordResetHTML(resetLink)
+	textContent := s.buildPasswordResetText(resetLink)
+
+	params := &resend.SendEmailRequest{
+		From:    fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail),
+		To:      []string{email},
+		Subject: subject,
+		Html:    htmlContent,
+		Text:    textContent,
+	}
+
+	return s.send(ctx, params, "password reset")
+}
+
+// buildPasswordResetHTML creates the HTML email template for password reset
+func (s *EmailService) buildPasswordResetHTML(resetLink string) string {
 	return fmt.Sprintf(`<!DOCTYPE html>
 <html>
 <head>
   <meta charset="utf-8">
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
-  <title>You're Invited!</title>
+  <title>Set Your Password</title>
 </head>
 <body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
   <div style="background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); padding: 30px; border-radius: 10px 10px 0 0; text-align: center;">
-    <h1 style="color: white; margin: 0; font-size: 24px;">You're Invited!</h1>
+    <h1 style="color: white; margin: 0; font-size: 24px;">Set Your Password</h1>
   </div>
 
   <div style="background: #ffffff; padding: 30px; border: 1px solid #e0e0e0; border-top: none; border-radius: 0 0 10px 10px;">
     <p style="font-size: 16px; margin-bottom: 20px;">
-      <strong>%s</strong> has invited you to join <strong>Manager Dashboard</strong> as a <strong>%s</strong>.
+      Your account has been created on <strong>Manager Dashboard</strong>.
     </p>
 
     <p style="font-size: 14px; color: #666; margin-bottom: 25px;">
-      Click the button below to accept your invitation and create your account.
+      Click the button below to set your password and complete your account setup.
     </p>
 
     <div style="text-align: center; margin: 30px 0;">
       <a href="%s" style="display: inline-block; background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); color: white; text-decoration: none; padding: 14px 30px; border-radius: 6px; font-weight: 600; font-size: 16px;">
-        Accept Invitation
+        Set Password
       </a>
     </div>
 
     <p style="font-size: 13px; color: #888; margin-top: 25px; padding-top: 20px; border-top: 1px solid #eee;">
-      This invitation will expire in <strong>7 days</strong>.
+      This link will expire in <strong>24 hours</strong>.
     </p>
 
     <p style="font-size: 12px; color: #999; margin-top: 15px;">
@@ -116,121 +187,165 @@ func (s *EmailService) buildInvitationHTML(inviterName, role, inviteLink string)
     <p>This email was sent by Manager Dashboard</p>
   </div>
 </body>
-</html>`, inviterName, role, inviteLink, inviteLink, inviteLink)
+</html>`, resetLink, resetLink, resetLink)
 }
 
-// buildInvitationText creates the plain text email content
-func (s *EmailService) buildInvitationText(inviterName, role, inviteLink string) string {
-	return fmt.Sprintf(`You're Invited to Manager Dashboard!
+// buildPasswordResetText creates the plain text email content for password reset
+func (s *EmailService) buildPasswordResetText(resetLink string) string {
+	return fmt.Sprintf(`Set Your Password - Manager Dashboard
 
-%s has invited you to join Manager Dashboard as a %s.
+Your account has been created on Manager Dashboard.
 
-To accept your invitation and create your account, visit the following link:
+To set your password and complete your account setup, visit the following link:
 
 %s
 
-This invitation will expire in 7 days.
+This link will expire in 24 hours.
 
 ---
-This email was sent by Manager Dashboard`, inviterName, role, inviteLink)
+This email was sent by Manager Dashboard`, resetLink)
 }
 
-// SendPasswordReset sends a password reset email to the specified email address
-func (s *EmailService) SendPasswordReset(ctx context.Context, email, resetLink string) error {
-	subject := "Set Your Password - Manager Dashboard"
+// SendAdminAlert sends a plain-text operational alert, such as a failing integration, to admins
+func (s *EmailService) SendAdminAlert(ctx context.Context, to []string, subject, text string) error {
+	params := &resend.SendEmailRequest{
+		From:    fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail),
+		To:      to,
+		Subject: subject,
+		Text:    text + fmt.Sprintf("\n\nManage integrations: %s/settings\n", s.frontendURL),
+	}
 
-	htmlContent := s.buildPasswordResetHTML(resetLink)
-	textContent := s.buildPasswordResetText(resetLink)
+	return s.send(ctx, params, "admin alert")
+}
 
+// SendMeetingResponse emails a meeting organizer that an attendee responded to their invite
+func (s *EmailService) SendMeetingResponse(ctx context.Context, to, subject, text string) error {
 	params := &resend.SendEmailRequest{
 		From:    fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail),
-		To:      []string{email},
+		To:      []string{to},
 		Subject: subject,
-		Html:    htmlContent,
-		Text:    textContent,
+		Text:    text + fmt.Sprintf("\n\nView your calendar: %s/calendar\n", s.frontendURL),
 	}
 
-	type result struct {
-		err error
+	return s.send(ctx, params, "meeting response")
+}
+
+// SendSupervisorDigest emails a supervisor their daily or weekly digest
+func (s *EmailService) SendSupervisorDigest(ctx context.Context, to string, digest *models.SupervisorDigest) error {
+	subject := fmt.Sprintf("Your team this week - %s", digest.PeriodStart.Format("Jan 2"))
+	if digest.Frequency == models.DigestFrequencyDaily {
+		subject = fmt.Sprintf("Your team today - %s", digest.PeriodStart.Format("Mon, Jan 2"))
 	}
-	resultCh := make(chan result, 1)
 
-	go func() {
-		_, err := s.client.Emails.Send(params)
-		resultCh <- result{err: err}
-	}()
+	sections := supervisorDigestSections(digest)
+	params := &resend.SendEmailRequest{
+		From:    fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail),
+		To:      []string{to},
+		Subject: subject,
+		Html:    s.buildSupervisorDigestHTML(digest.SupervisorName, sections),
+		Text:    s.buildSupervisorDigestText(digest.SupervisorName, sections),
+	}
 
-	select {
-	case res := <-resultCh:
-		if res.err != nil {
-			return fmt.Errorf("failed to send password reset email: %w", res.err)
+	return s.send(ctx, params, "supervisor digest")
+}
+
+// digestSection is a titled list in a supervisor digest, linking to the page where it's handled
+type digestSection struct {
+	title string
+	path  string
+	items []string
+}
+
+// supervisorDigestSections lists a digest's non-empty sections
+func supervisorDigestSections(digest *models.SupervisorDigest) []digestSection {
+	timeOff := func(entries []models.DigestTimeOff) []string {
+		items := make([]string, 0, len(entries))
+		for _, e := range entries {
+			items = append(items, fmt.Sprintf("%s - %s, %s to %s", e.EmployeeName,
+				strings.ReplaceAll(string(e.RequestType), "_", " "), e.StartDate.Format("Jan 2"), e.EndDate.Format("Jan 2")))
 		}
-		return nil
-	case <-time.After(s.timeout):
-		return fmt.Errorf("email send timed out after %v", s.timeout)
-	case <-ctx.Done():
-		return fmt.Errorf("email send cancelled: %w", ctx.Err())
+		return items
+	}
+
+	var tasks, invites []string
+	for _, task := range digest.OverdueTasks {
+		tasks = append(tasks, fmt.Sprintf("%s - %s, due %s", task.Title, task.AssigneeName, task.DueDate.Format("Jan 2")))
 	}
+	for _, meeting := range digest.UnansweredInvites {
+		invites = append(invites, fmt.Sprintf("%s - %s UTC", meeting.Title, meeting.StartTime.UTC().Format("Mon, Jan 2 at 15:04")))
+	}
+
+	var sections []digestSection
+	for _, section := range []digestSection{
+		{"Time off waiting for your review", "/time-off", timeOff(digest.PendingTimeOff)},
+		{"Overdue team tasks", "/", tasks},
+		{"Upcoming team absences", "/time-off", timeOff(digest.UpcomingAbsences)},
+		{"Meeting invites you haven't answered", "/calendar", invites},
+	} {
+		if len(section.items) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	return sections
 }
 
-// buildPasswordResetHTML creates the HTML email template for password reset
-func (s *EmailService) buildPasswordResetHTML(resetLink string) string {
+// buildSupervisorDigestHTML creates the HTML email template for a supervisor digest
+func (s *EmailService) buildSupervisorDigestHTML(name string, sections []digestSection) string {
+	var body strings.Builder
+	for _, section := range sections {
+		fmt.Fprintf(&body, `
+    <h2 style="font-size: 16px; margin: 25px 0 10px;">%s (%d)</h2>
+    <ul style="font-size: 14px; color: #444; padding-left: 20px; margin: 0;">`, html.EscapeString(section.title), len(section.items))
+		for _, item := range section.items {
+			fmt.Fprintf(&body, `
+      <li style="margin-bottom: 6px;">%s</li>`, html.EscapeString(item))
+		}
+		fmt.Fprintf(&body, `
+    </ul>
+    <p style="font-size: 13px; margin: 8px 0 0;"><a href="%s%s" style="color: #667eea;">View in Manager Dashboard</a></p>`, s.frontendURL, section.path)
+	}
+
 	return fmt.Sprintf(`<!DOCTYPE html>
 <html>
 <head>
   <meta charset="utf-8">
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
-  <title>Set Your Password</title>
+  <title>Your Team Digest</title>
 </head>
 <body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px;">
   <div style="background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); padding: 30px; border-radius: 10px 10px 0 0; text-align: center;">
-    <h1 style="color: white; margin: 0; font-size: 24px;">Set Your Password</h1>
+    <h1 style="color: white; margin: 0; font-size: 24px;">Your Team Digest</h1>
   </div>
 
   <div style="background: #ffffff; padding: 30px; border: 1px solid #e0e0e0; border-top: none; border-radius: 0 0 10px 10px;">
-    <p style="font-size: 16px; margin-bottom: 20px;">
-      Your account has been created on <strong>Manager Dashboard</strong>.
-    </p>
-
-    <p style="font-size: 14px; color: #666; margin-bottom: 25px;">
-      Click the button below to set your password and complete your account setup.
-    </p>
-
-    <div style="text-align: center; margin: 30px 0;">
-      <a href="%s" style="display: inline-block; background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); color: white; text-decoration: none; padding: 14px 30px; border-radius: 6px; font-weight: 600; font-size: 16px;">
-        Set Password
-      </a>
-    </div>
-
-    <p style="font-size: 13px; color: #888; margin-top: 25px; padding-top: 20px; border-top: 1px solid #eee;">
-      This link will expire in <strong>24 hours</strong>.
-    </p>
-
-    <p style="font-size: 12px; color: #999; margin-top: 15px;">
-      If the button doesn't work, copy and paste this link into your browser:<br>
-      <a href="%s" style="color: #667eea; word-break: break-all;">%s</a>
+    <p style="font-size: 16px; margin-bottom: 10px;">
+      Hi %s, here's what needs your attention.
     </p>
+%s
   </div>
 
   <div style="text-align: center; padding: 20px; color: #999; font-size: 12px;">
     <p>This email was sent by Manager Dashboard</p>
   </div>
 </body>
-</html>`, resetLink, resetLink, resetLink)
+</html>`, html.EscapeString(name), body.String())
 }
 
-// buildPasswordResetText creates the plain text email content for password reset
-func (s *EmailService) buildPasswordResetText(resetLink string) string {
-	return fmt.Sprintf(`Set Your Password - Manager Dashboard
-
-Your account has been created on Manager Dashboard.
-
-To set your password and complete your account setup, visit the following link:
+// buildSupervisorDigestText creates the plain text email content for a supervisor digest
+func (s *EmailService) buildSupervisorDigestText(name string, sections []digestSection) string {
+	var body strings.Builder
+	for _, section := range sections {
+		fmt.Fprintf(&body, "%s (%d)\n", section.title, len(section.items))
+		for _, item := range section.items {
+			fmt.Fprintf(&body, "- %s\n", item)
+		}
+		fmt.Fprintf(&body, "%s%s\n\n", s.frontendURL, section.path)
+	}
 
-%s
+	return fmt.Sprintf(`Your Team Digest - Manager Dashboard
 
-This link will expire in 24 hours.
+Hi %s, here's what needs your attention.
 
----
-This email was sent by Manager Dashboard`, resetLink)
+%s---
+This email was sent by Manager Dashboard`, name, body.String())
 }
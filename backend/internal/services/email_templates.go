@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/smith-dallin/manager-dashboard/internal/emailtemplates"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+var (
+	// ErrUnknownEmailTemplate is returned for an email that can't be customized or an unsupported language
+	ErrUnknownEmailTemplate = errors.New("unknown email template")
+	// ErrInvalidEmailTemplate is returned when a customized template doesn't parse or render
+	ErrInvalidEmailTemplate = errors.New("invalid email template")
+)
+
+// EmailTemplateService resolves the templates emails are rendered with. An organization's override of
+// an email in the recipient's language is used if it has one, then the built-in template in that
+// language, then the organization's English override, then the built-in English template.
+type EmailTemplateService struct {
+	repo repository.EmailTemplateRepository
+}
+
+// NewEmailTemplateService creates a new email template service
+func NewEmailTemplateService(repo repository.EmailTemplateRepository) *EmailTemplateService {
+	return &EmailTemplateService{repo: repo}
+}
+
+// checkTemplate returns ErrUnknownEmailTemplate unless key is a customizable email and language is supported
+func checkTemplate(key models.EmailTemplateKey, language string) error {
+	if _, ok := emailtemplates.Lookup(key); !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownEmailTemplate, key)
+	}
+	if err := models.ValidateLanguage(language); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnknownEmailTemplate, err)
+	}
+	return nil
+}
+
+// Get returns the template the organization ctx is scoped to sends an email in a language with.
+// Unsupported languages fall back to English.
+func (s *EmailTemplateService) Get(ctx context.Context, key models.EmailTemplateKey, language string) (*models.EmailTemplate, error) {
+	if _, ok := emailtemplates.Lookup(key); !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEmailTemplate, key)
+	}
+
+	languages := []string{models.DefaultLanguage}
+	if language != models.DefaultLanguage && models.ValidateLanguage(language) == nil {
+		languages = []string{language, models.DefaultLanguage}
+	}
+	for _, lang := range languages {
+		override, err := s.repo.Get(ctx, key, lang)
+		if err != nil {
+			return nil, err
+		}
+		if override != nil {
+			return override, nil
+		}
+		if t, ok := emailtemplates.Default(key, lang); ok {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no %q template", ErrUnknownEmailTemplate, key)
+}
+
+// List returns the customizable emails and which languages the organization has overridden each in
+func (s *EmailTemplateService) List(ctx context.Context) ([]models.EmailTemplateDefinition, error) {
+	overrides, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := emailtemplates.Definitions()
+	for i := range defs {
+		defs[i].CustomizedLanguages = []string{}
+		for _, t := range overrides {
+			if t.Key == defs[i].Key {
+				defs[i].CustomizedLanguages = append(defs[i].CustomizedLanguages, t.Language)
+			}
+		}
+	}
+	return defs, nil
+}
+
+// Save overrides an email in a language for the organization ctx is scoped to
+func (s *EmailTemplateService) Save(ctx context.Context, key models.EmailTemplateKey, language string, req *models.UpdateEmailTemplateRequest, updatedByID int64) (*models.EmailTemplate, error) {
+	if err := checkTemplate(key, language); err != nil {
+		return nil, err
+	}
+	t := &models.EmailTemplate{
+		Key:         key,
+		Language:    language,
+		Subject:     req.Subject,
+		HTMLBody:    req.HTMLBody,
+		TextBody:    req.TextBody,
+		UpdatedByID: &updatedByID,
+	}
+	if err := emailtemplates.Validate(t); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEmailTemplate, err)
+	}
+	if err := s.repo.Save(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Reset removes the organization's override of an email in a language and returns the built-in template
+func (s *EmailTemplateService) Reset(ctx context.Context, key models.EmailTemplateKey, language string) (*models.EmailTemplate, error) {
+	if err := checkTemplate(key, language); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Delete(ctx, key, language); err != nil {
+		return nil, err
+	}
+	t, _ := emailtemplates.Default(key, language)
+	return t, nil
+}
+
+// Render renders an email in a language with vars, using the organization's template for it
+func (s *EmailTemplateService) Render(ctx context.Context, key models.EmailTemplateKey, language string, vars map[string]string) (*models.RenderedEmail, error) {
+	t, err := s.Get(ctx, key, language)
+	if err != nil {
+		return nil, err
+	}
+	return emailtemplates.Render(t, vars)
+}
+
+// Preview renders an email in a language with its example variables, overlaid with req.Variables.
+// req.Template is rendered instead of the organization's template when given, so edits can be
+// previewed before they're saved.
+func (s *EmailTemplateService) Preview(ctx context.Context, key models.EmailTemplateKey, language string, req *models.PreviewEmailTemplateRequest) (*models.RenderedEmail, error) {
+	if err := checkTemplate(key, language); err != nil {
+		return nil, err
+	}
+
+	var t *models.EmailTemplate
+	if req.Template != nil {
+		t = &models.EmailTemplate{
+			Key:      key,
+			Language: language,
+			Subject:  req.Template.Subject,
+			HTMLBody: req.Template.HTMLBody,
+			TextBody: req.Template.TextBody,
+		}
+	} else {
+		var err error
+		if t, err = s.Get(ctx, key, language); err != nil {
+			return nil, err
+		}
+	}
+
+	vars := emailtemplates.Examples(key)
+	maps.Copy(vars, req.Variables)
+	rendered, err := emailtemplates.Render(t, vars)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEmailTemplate, err)
+	}
+	return rendered, nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+func TestEmailTemplateService_Get(t *testing.T) {
+	repo := mocks.NewMockEmailTemplateRepository()
+	service := NewEmailTemplateService(repo)
+	org2 := tenant.WithOrgID(context.Background(), 2)
+	update := &models.UpdateEmailTemplateRequest{Subject: "Join {{.InviterName}}", HTMLBody: "<p>Hi</p>", TextBody: "Hi"}
+	if _, err := service.Save(org2, models.EmailTemplateInvitation, "en", update, 1); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		language       string
+		wantLanguage   string
+		wantCustomized bool
+	}{
+		{"organization's override", org2, "en", "en", true},
+		{"built-in template in the requested language before the English override", org2, "es", "es", false},
+		{"unsupported languages fall back to the English override", org2, "fr", "en", true},
+		{"other organizations get the built-in template", tenant.WithOrgID(context.Background(), 3), "en", "en", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := service.Get(tt.ctx, models.EmailTemplateInvitation, tt.language)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if tmpl.Language != tt.wantLanguage || tmpl.Customized != tt.wantCustomized {
+				t.Errorf("Get() = %s customized %v, want %s customized %v", tmpl.Language, tmpl.Customized, tt.wantLanguage, tt.wantCustomized)
+			}
+		})
+	}
+
+	if _, err := service.Get(org2, "carrier_pigeon", "en"); !errors.Is(err, ErrUnknownEmailTemplate) {
+		t.Errorf("Get() error = %v, want ErrUnknownEmailTemplate", err)
+	}
+}
+
+func TestEmailTemplateService_SaveAndReset(t *testing.T) {
+	repo := mocks.NewMockEmailTemplateRepository()
+	service := NewEmailTemplateService(repo)
+	ctx := context.Background()
+
+	broken := &models.UpdateEmailTemplateRequest{Subject: "{{.InviterName", HTMLBody: "<p>Hi</p>", TextBody: "Hi"}
+	if _, err := service.Save(ctx, models.EmailTemplateInvitation, "en", broken, 1); !errors.Is(err, ErrInvalidEmailTemplate) {
+		t.Errorf("Save() error = %v, want ErrInvalidEmailTemplate", err)
+	}
+	valid := &models.UpdateEmailTemplateRequest{Subject: "Hola", HTMLBody: "<p>Hola</p>", TextBody: "Hola"}
+	if _, err := service.Save(ctx, models.EmailTemplateInvitation, "fr", valid, 1); !errors.Is(err, ErrUnknownEmailTemplate) {
+		t.Errorf("Save() error = %v, want ErrUnknownEmailTemplate for an unsupported language", err)
+	}
+	if _, err := service.Save(ctx, models.EmailTemplateInvitation, "es", valid, 1); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	defs, err := service.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	for _, def := range defs {
+		customized := strings.Join(def.CustomizedLanguages, ",")
+		if def.Key == models.EmailTemplateInvitation && customized != "es" || def.Key != models.EmailTemplateInvitation && customized != "" {
+			t.Errorf("%s customized in %q", def.Key, customized)
+		}
+	}
+
+	builtIn, err := service.Reset(ctx, models.EmailTemplateInvitation, "es")
+	if err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if builtIn.Customized || builtIn.Subject == "Hola" || len(repo.Templates) != 0 {
+		t.Errorf("Reset() = %+v, want the built-in template with the override removed", builtIn)
+	}
+}
+
+func TestEmailTemplateService_Preview(t *testing.T) {
+	service := NewEmailTemplateService(mocks.NewMockEmailTemplateRepository())
+	ctx := context.Background()
+
+	rendered, err := service.Preview(ctx, models.EmailTemplateInvitation, "es", &models.PreviewEmailTemplateRequest{
+		Variables: map[string]string{"InviterName": "Marie Curie"},
+	})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if !strings.Contains(rendered.Text, "Marie Curie") || !strings.Contains(rendered.Text, "example-token") {
+		t.Errorf("Text = %q, want the given inviter and example link", rendered.Text)
+	}
+
+	unsaved := &models.UpdateEmailTemplateRequest{Subject: "{{.ResponderName}} {{.Response}}", HTMLBody: "<p>{{.MeetingTitle}}</p>", TextBody: "{{.Pending}}"}
+	rendered, err = service.Preview(ctx, models.EmailTemplateMeetingResponse, "en", &models.PreviewEmailTemplateRequest{Template: unsaved})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if rendered.Subject != "Grace Hopper accepted" || rendered.HTML != "<p>Design sync</p>" || rendered.Text != "2" {
+		t.Errorf("Preview() = %+v, want the unsaved template with example variables", rendered)
+	}
+
+	unsaved.TextBody = "{{.Pending"
+	_, err = service.Preview(ctx, models.EmailTemplateMeetingResponse, "en", &models.PreviewEmailTemplateRequest{Template: unsaved})
+	if !errors.Is(err, ErrInvalidEmailTemplate) {
+		t.Errorf("Preview() error = %v, want ErrInvalidEmailTemplate", err)
+	}
+}
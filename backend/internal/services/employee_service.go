@@ -66,6 +66,7 @@ type EmployeeService struct {
 	orgJiraRepo repository.OrgJiraRepository
 	auth0Client Auth0Client
 	emailClient EmailClient
+	onboarding  *OnboardingService
 	frontendURL string
 	logger      *logger.Logger
 }
@@ -92,6 +93,12 @@ func NewEmployeeService(
 	}
 }
 
+// WithOnboarding creates onboarding tasks for each new employee
+func (s *EmployeeService) WithOnboarding(onboarding *OnboardingService) *EmployeeService {
+	s.onboarding = onboarding
+	return s
+}
+
 // CreateEmployee creates a new employee, handling Auth0 user creation,
 // Jira user creation (if configured), and database persistence.
 func (s *EmployeeService) CreateEmployee(ctx context.Context, input CreateEmployeeInput) (*CreateEmployeeResult, error) {
@@ -128,6 +135,9 @@ func (s *EmployeeService) CreateEmployee(ctx context.Context, input CreateEmploy
 		s.assignSquads(ctx, user, input.SquadIDs)
 	}
 
+	// Step 6: Create onboarding tasks from the matching templates
+	s.onboarding.Start(ctx, user)
+
 	return result, nil
 }
 
@@ -214,7 +224,7 @@ func (s *EmployeeService) createJiraUser(ctx context.Context, input CreateEmploy
 		orgJiraSettings.OAuthAccessToken,
 		orgJiraSettings.CloudID,
 		orgJiraSettings.SiteURL,
-	)
+	).WithContext(ctx)
 
 	// Create user in Jira
 	displayName := fmt.Sprintf("%s %s", input.FirstName, input.LastName)
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/database"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// ErrNotAnEpic is returned when progress is requested for an issue that isn't an epic
+var ErrNotAnEpic = fmt.Errorf("issue is not an epic")
+
+const (
+	// epicThroughputWindowDays is how far back resolved issues count towards an epic's pace
+	epicThroughputWindowDays = 28
+	// epicForecastHorizonDays is how far ahead an epic's completion is forecast
+	epicForecastHorizonDays = 730
+)
+
+// EpicSource provides an epic and its child issues from Jira
+type EpicSource interface {
+	GetIssue(issueKey string) (*models.JiraIssue, error)
+	GetEpicIssues(epicKey string) ([]models.JiraIssue, error)
+}
+
+// EpicProgressService rolls up epic progress and forecasts completion around team time off
+type EpicProgressService struct {
+	userRepo    repository.UserRepository
+	timeOffRepo repository.TimeOffRepository
+	holidays    []string
+}
+
+// NewEpicProgressService creates a new epic progress service
+func NewEpicProgressService(userRepo repository.UserRepository, timeOffRepo repository.TimeOffRepository, cfg *config.Config) *EpicProgressService {
+	return &EpicProgressService{
+		userRepo:    userRepo,
+		timeOffRepo: timeOffRepo,
+		holidays:    cfg.CompanyHolidays,
+	}
+}
+
+// Progress builds the progress report for an epic
+func (s *EpicProgressService) Progress(ctx context.Context, source EpicSource, epicKey string) (*models.EpicProgress, error) {
+	epic, err := source.GetIssue(epicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epic: %w", err)
+	}
+	if epic.IssueType != "Epic" {
+		return nil, ErrNotAnEpic
+	}
+
+	children, err := source.GetEpicIssues(epic.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epic issues: %w", err)
+	}
+
+	users, err := s.userRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	usersByAccount := make(map[string]models.User)
+	for _, u := range users {
+		if u.IsActive && u.JiraAccountID != nil && *u.JiraAccountID != "" {
+			usersByAccount[*u.JiraAccountID] = u
+		}
+	}
+
+	var userIDs []int64
+	seen := make(map[int64]bool)
+	for _, issue := range children {
+		if issue.Assignee == nil {
+			continue
+		}
+		if u, ok := usersByAccount[issue.Assignee.AccountID]; ok && !seen[u.ID] {
+			seen[u.ID] = true
+			userIDs = append(userIDs, u.ID)
+		}
+	}
+
+	now := time.Now()
+	var timeOff []models.TimeOffRequest
+	if len(userIDs) > 0 {
+		timeOff, err = s.timeOffRepo.GetApprovedForUsers(ctx, userIDs, now, now.AddDate(0, 0, epicForecastHorizonDays))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get time off: %w", err)
+		}
+	}
+
+	return BuildEpicProgress(*epic, children, usersByAccount, timeOff, s.holidays, now), nil
+}
+
+// BuildEpicProgress counts an epic's issues by status and assignee and forecasts completion.
+// The pace is the number of issues resolved per working day over the last four weeks. Each
+// working day ahead contributes that pace, scaled down by the share of the epic's remaining
+// assignees who are on approved time off that day.
+func BuildEpicProgress(
+	epic models.JiraIssue,
+	children []models.JiraIssue,
+	usersByAccount map[string]models.User,
+	timeOff []models.TimeOffRequest,
+	holidays []string,
+	now time.Time,
+) *models.EpicProgress {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	progress := &models.EpicProgress{
+		Epic:         epic,
+		TotalIssues:  len(children),
+		StatusCounts: []models.EpicStatusCount{},
+		Assignees:    []models.EpicAssigneeProgress{},
+		Warnings:     []string{},
+	}
+
+	timeOffByUser := make(map[int64][]models.TimeOffRequest)
+	for _, t := range timeOff {
+		timeOffByUser[t.UserID] = append(timeOffByUser[t.UserID], t)
+	}
+
+	statusIndex := make(map[string]int)
+	assigneeIndex := make(map[string]int)
+	windowStart := today.AddDate(0, 0, -epicThroughputWindowDays)
+	recentlyResolved := 0
+	var lastResolved *time.Time
+	for _, issue := range children {
+		done := issue.StatusCategory == models.JiraStatusCategoryDone
+		switch issue.StatusCategory {
+		case models.JiraStatusCategoryDone:
+			progress.DoneIssues++
+		case models.JiraStatusCategoryInProgress:
+			progress.InProgressIssues++
+		default:
+			progress.ToDoIssues++
+		}
+
+		if i, ok := statusIndex[issue.Status]; ok {
+			progress.StatusCounts[i].Count++
+		} else {
+			statusIndex[issue.Status] = len(progress.StatusCounts)
+			progress.StatusCounts = append(progress.StatusCounts, models.EpicStatusCount{Status: issue.Status, Category: issue.StatusCategory, Count: 1})
+		}
+
+		accountID, displayName := "", "Unassigned"
+		if issue.Assignee != nil {
+			accountID, displayName = issue.Assignee.AccountID, issue.Assignee.DisplayName
+		}
+		i, ok := assigneeIndex[accountID]
+		if !ok {
+			i = len(progress.Assignees)
+			assigneeIndex[accountID] = i
+			assignee := models.EpicAssigneeProgress{AccountID: accountID, DisplayName: displayName}
+			if u, ok := usersByAccount[accountID]; ok && accountID != "" {
+				assignee.UserID = &u.ID
+			}
+			progress.Assignees = append(progress.Assignees, assignee)
+		}
+		progress.Assignees[i].TotalIssues++
+		if done {
+			progress.Assignees[i].DoneIssues++
+		} else {
+			progress.Assignees[i].Remaining++
+		}
+
+		if done && issue.ResolvedAt != nil {
+			if !issue.ResolvedAt.Before(windowStart) {
+				recentlyResolved++
+			}
+			if lastResolved == nil || issue.ResolvedAt.After(*lastResolved) {
+				lastResolved = issue.ResolvedAt
+			}
+		}
+	}
+
+	sort.SliceStable(progress.StatusCounts, func(i, j int) bool {
+		return statusCategoryOrder(progress.StatusCounts[i].Category) < statusCategoryOrder(progress.StatusCounts[j].Category)
+	})
+	sort.SliceStable(progress.Assignees, func(i, j int) bool {
+		return progress.Assignees[i].Remaining > progress.Assignees[j].Remaining
+	})
+
+	if progress.TotalIssues == 0 {
+		progress.Warnings = append(progress.Warnings, "Epic has no child issues")
+		return progress
+	}
+	progress.PercentComplete = math.Round(float64(progress.DoneIssues)/float64(progress.TotalIssues)*1000) / 10
+
+	windowDays, _ := sprintWorkingDays(windowStart, today, holidays)
+	var throughput float64
+	if len(windowDays) > 0 {
+		throughput = float64(recentlyResolved) / float64(len(windowDays))
+	}
+	progress.ThroughputPerDay = math.Round(throughput*100) / 100
+
+	// Only remaining work is held up by its assignees' time off
+	var contributors []int64
+	unmapped := 0
+	for _, a := range progress.Assignees {
+		if a.Remaining == 0 || a.AccountID == "" {
+			continue
+		}
+		if a.UserID == nil {
+			unmapped++
+			continue
+		}
+		contributors = append(contributors, *a.UserID)
+	}
+
+	remaining := progress.TotalIssues - progress.DoneIssues
+	switch {
+	case remaining == 0:
+		progress.EstimatedCompletion = lastResolved
+	case throughput == 0:
+		progress.Warnings = append(progress.Warnings, fmt.Sprintf("No issues were resolved in the last %d days, so completion can't be estimated", epicThroughputWindowDays))
+	default:
+		progress.EstimatedCompletion, progress.TimeOffDays = forecastEpicCompletion(today, float64(remaining), throughput, contributors, timeOffByUser, holidays)
+		if progress.EstimatedCompletion == nil {
+			progress.Warnings = append(progress.Warnings, "Completion is more than two years out at the current pace")
+		}
+	}
+
+	if epic.DueDate != nil && progress.EstimatedCompletion != nil && remaining > 0 {
+		onTrack := !progress.EstimatedCompletion.After(*epic.DueDate)
+		progress.OnTrack = &onTrack
+		if !onTrack {
+			progress.Warnings = append(progress.Warnings, fmt.Sprintf("Estimated to finish %s, after the %s due date",
+				progress.EstimatedCompletion.Format("2006-01-02"), epic.DueDate.Format("2006-01-02")))
+		}
+	}
+
+	// Flag assignees whose time off eats into the time left before the deadline
+	deadline := epic.DueDate
+	if deadline == nil {
+		deadline = progress.EstimatedCompletion
+	}
+	for i := range progress.Assignees {
+		a := &progress.Assignees[i]
+		if a.UserID != nil && a.Remaining > 0 {
+			a.TimeOffImpact = database.CalculateTimeOffImpact(deadline, timeOffByUser[*a.UserID])
+		}
+	}
+
+	if unmapped > 0 {
+		progress.Warnings = append(progress.Warnings, fmt.Sprintf("%d assignees aren't linked to dashboard users, so their time off isn't counted", unmapped))
+	}
+
+	return progress
+}
+
+// forecastEpicCompletion walks working days from today until the remaining issues are done at the
+// given pace, returning the completion day (nil past the horizon) and contributor days lost to time off
+func forecastEpicCompletion(today time.Time, remaining, throughput float64, contributors []int64, timeOffByUser map[int64][]models.TimeOffRequest, holidays []string) (*time.Time, int) {
+	workingDays, _ := sprintWorkingDays(today, today.AddDate(0, 0, epicForecastHorizonDays), holidays)
+	timeOffDays := 0
+	completed := 0.0
+	for day := today; day.Before(today.AddDate(0, 0, epicForecastHorizonDays)); day = day.AddDate(0, 0, 1) {
+		if _, ok := workingDays[day]; !ok {
+			continue
+		}
+
+		available := 1.0
+		if len(contributors) > 0 {
+			off := 0
+			for _, id := range contributors {
+				if isOnTimeOff(timeOffByUser[id], day) {
+					off++
+				}
+			}
+			timeOffDays += off
+			available = float64(len(contributors)-off) / float64(len(contributors))
+		}
+
+		completed += throughput * available
+		if completed >= remaining-1e-9 {
+			return &day, timeOffDays
+		}
+	}
+	return nil, timeOffDays
+}
+
+// statusCategoryOrder sorts to do before in progress before done, with uncategorized statuses first
+func statusCategoryOrder(category string) int {
+	switch category {
+	case models.JiraStatusCategoryToDo:
+		return 1
+	case models.JiraStatusCategoryInProgress:
+		return 2
+	case models.JiraStatusCategoryDone:
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestBuildEpicProgress(t *testing.T) {
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC) // Monday
+	resolved := ptrTime(time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC))
+	longAgo := ptrTime(time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC))
+	ada := &models.JiraUser{AccountID: "a", DisplayName: "Ada Lovelace"}
+	alan := &models.JiraUser{AccountID: "b", DisplayName: "Alan Turing"}
+	grace := &models.JiraUser{AccountID: "c", DisplayName: "Grace Hopper"}
+	issue := func(status, category string, assignee *models.JiraUser, resolvedAt *time.Time) models.JiraIssue {
+		return models.JiraIssue{Status: status, StatusCategory: category, Assignee: assignee, ResolvedAt: resolvedAt}
+	}
+
+	children := []models.JiraIssue{
+		issue("Done", models.JiraStatusCategoryDone, ada, resolved),
+		issue("Done", models.JiraStatusCategoryDone, ada, resolved),
+		issue("Done", models.JiraStatusCategoryDone, alan, resolved),
+		issue("Done", models.JiraStatusCategoryDone, alan, resolved),
+		issue("Done", models.JiraStatusCategoryDone, grace, longAgo),
+		issue("In Review", models.JiraStatusCategoryInProgress, ada, nil),
+		issue("To Do", models.JiraStatusCategoryToDo, ada, nil),
+		issue("To Do", models.JiraStatusCategoryToDo, alan, nil),
+	}
+	users := map[string]models.User{"a": {ID: 1}, "b": {ID: 2}}
+	// Ada is out the whole first week
+	timeOff := []models.TimeOffRequest{{UserID: 1, StartDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)}}
+	epic := models.JiraIssue{Key: "WEB-1", IssueType: "Epic", DueDate: ptrTime(time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC))}
+
+	progress := BuildEpicProgress(epic, children, users, timeOff, nil, now)
+
+	if progress.TotalIssues != 8 || progress.DoneIssues != 5 || progress.InProgressIssues != 1 || progress.ToDoIssues != 2 || progress.PercentComplete != 62.5 {
+		t.Errorf("counts = %d total, %d done, %d in progress, %d to do (%v%%)",
+			progress.TotalIssues, progress.DoneIssues, progress.InProgressIssues, progress.ToDoIssues, progress.PercentComplete)
+	}
+	if len(progress.StatusCounts) != 3 || progress.StatusCounts[0].Status != "To Do" || progress.StatusCounts[2].Status != "Done" || progress.StatusCounts[2].Count != 5 {
+		t.Errorf("status counts = %+v, want To Do, In Review, Done", progress.StatusCounts)
+	}
+	if len(progress.Assignees) != 3 || progress.Assignees[0].AccountID != "a" || progress.Assignees[0].Remaining != 2 || progress.Assignees[0].UserID == nil {
+		t.Errorf("assignees = %+v, want Ada first with 2 remaining", progress.Assignees)
+	}
+
+	// 4 issues resolved over the 20 working days in the last four weeks
+	if progress.ThroughputPerDay != 0.2 {
+		t.Errorf("throughput = %v, want 0.2", progress.ThroughputPerDay)
+	}
+	// Half pace while Ada is out (0.5 issues), then 2.5 issues at full pace takes 13 more working days
+	want := time.Date(2026, 3, 25, 0, 0, 0, 0, time.UTC)
+	if progress.EstimatedCompletion == nil || !progress.EstimatedCompletion.Equal(want) {
+		t.Errorf("estimated completion = %v, want %v", progress.EstimatedCompletion, want)
+	}
+	if progress.TimeOffDays != 5 {
+		t.Errorf("time off days = %d, want 5", progress.TimeOffDays)
+	}
+	if progress.OnTrack == nil || *progress.OnTrack {
+		t.Errorf("on track = %v, want false for a 2026-03-20 due date", progress.OnTrack)
+	}
+}
+
+func TestBuildEpicProgress_NoRecentPace(t *testing.T) {
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	children := []models.JiraIssue{{Status: "To Do", StatusCategory: models.JiraStatusCategoryToDo}}
+
+	progress := BuildEpicProgress(models.JiraIssue{Key: "WEB-1", IssueType: "Epic"}, children, nil, nil, nil, now)
+
+	if progress.EstimatedCompletion != nil || len(progress.Warnings) != 1 {
+		t.Errorf("estimate = %v, warnings = %v, want no estimate and a warning", progress.EstimatedCompletion, progress.Warnings)
+	}
+	if progress.Assignees[0].DisplayName != "Unassigned" {
+		t.Errorf("assignee = %+v, want unassigned issues grouped", progress.Assignees[0])
+	}
+}
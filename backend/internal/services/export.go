@@ -0,0 +1,380 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/storage"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+const (
+	// exportPollInterval is how often the worker checks for jobs queued by other instances
+	exportPollInterval = 5 * time.Second
+	// exportStaleAfter is how long a job may stay running before another worker retries it
+	exportStaleAfter = 30 * time.Minute
+	// exportCleanupInterval is how often expired export files are removed
+	exportCleanupInterval = time.Hour
+	// exportListLimit caps how many recent jobs are listed for a user
+	exportListLimit = 20
+)
+
+// ErrExportStorageNotConfigured is returned when no storage backend can hold export files
+var ErrExportStorageNotConfigured = fmt.Errorf("export storage is not configured")
+
+// ErrExportJobNotFound is returned when a job doesn't exist or belongs to another user
+var ErrExportJobNotFound = fmt.Errorf("export job not found")
+
+// ExportService queues export jobs and generates their files in a background worker
+type ExportService struct {
+	jobRepo     repository.ExportJobRepository
+	userService *UserService
+	timeOffRepo repository.TimeOffRepository
+	userRepo    repository.UserRepository
+	storage     storage.SignedURLStorage
+	tenantID    string
+	retention   time.Duration
+	urlTTL      time.Duration
+	wake        chan struct{}
+	logger      *logger.Logger
+}
+
+// NewExportService creates a new export service. Exports are unavailable unless the
+// storage backend can sign download URLs.
+func NewExportService(jobRepo repository.ExportJobRepository, userService *UserService, timeOffRepo repository.TimeOffRepository, userRepo repository.UserRepository, store storage.Storage, tenantID string, retention, urlTTL time.Duration) *ExportService {
+	signed, _ := store.(storage.SignedURLStorage)
+	return &ExportService{
+		jobRepo:     jobRepo,
+		userService: userService,
+		timeOffRepo: timeOffRepo,
+		userRepo:    userRepo,
+		storage:     signed,
+		tenantID:    tenantID,
+		retention:   retention,
+		urlTTL:      urlTTL,
+		wake:        make(chan struct{}, 1),
+		logger:      logger.Default().WithComponent("export-service"),
+	}
+}
+
+// Create queues an export job for the user and wakes the worker
+func (s *ExportService) Create(ctx context.Context, user *models.User, req *models.CreateExportJobRequest) (*models.ExportJob, error) {
+	if s.storage == nil {
+		return nil, ErrExportStorageNotConfigured
+	}
+
+	job, err := s.jobRepo.Create(ctx, user.ID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default: // The worker already has a wake-up pending
+	}
+	return job, nil
+}
+
+// Get returns one of the user's export jobs. Completed jobs carry a freshly signed download URL.
+func (s *ExportService) Get(ctx context.Context, user *models.User, id int64) (*models.ExportJob, error) {
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.RequestedByID != user.ID {
+		return nil, ErrExportJobNotFound
+	}
+
+	if job.Status != models.ExportJobStatusCompleted {
+		return job, nil
+	}
+	// The file may outlive its retention period until the next cleanup pass
+	if job.ExpiresAt != nil && time.Now().After(*job.ExpiresAt) {
+		job.Status = models.ExportJobStatusExpired
+		return job, nil
+	}
+	if s.storage == nil || job.StorageKey == nil {
+		return nil, ErrExportStorageNotConfigured
+	}
+
+	url, err := s.storage.SignedURL(ctx, *job.StorageKey, s.urlTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign export download URL: %w", err)
+	}
+	job.DownloadURL = &url
+	return job, nil
+}
+
+// List returns the user's most recent export jobs, without download URLs
+func (s *ExportService) List(ctx context.Context, user *models.User) ([]models.ExportJob, error) {
+	return s.jobRepo.ListByUser(ctx, user.ID, exportListLimit)
+}
+
+// Start runs the export worker until the group is stopped.
+// Jobs are claimed from the database, so any number of instances can run a worker.
+func (s *ExportService) Start(workers *lifecycle.Group) {
+	if s.storage == nil {
+		s.logger.Info("Export storage not configured - export worker not started")
+		return
+	}
+
+	workers.Go("export-jobs", func(ctx context.Context) {
+		poll := time.NewTicker(exportPollInterval)
+		defer poll.Stop()
+		cleanup := time.NewTicker(exportCleanupInterval)
+		defer cleanup.Stop()
+
+		for {
+			s.ProcessPending(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.wake:
+			case <-poll.C:
+			case <-cleanup.C:
+				s.PurgeExpired(ctx)
+			}
+		}
+	})
+}
+
+// ProcessPending runs queued jobs until none are left and returns how many were processed
+func (s *ExportService) ProcessPending(ctx context.Context) int {
+	processed := 0
+	for ctx.Err() == nil {
+		job, err := s.jobRepo.ClaimNext(ctx, exportStaleAfter)
+		if err != nil {
+			s.logger.LogError(ctx, "Failed to claim export job", err)
+			return processed
+		}
+		if job == nil {
+			return processed
+		}
+		s.runJob(ctx, job)
+		processed++
+	}
+	return processed
+}
+
+// runJob generates, uploads, and records the file for a claimed job
+func (s *ExportService) runJob(ctx context.Context, job *models.ExportJob) {
+	data, rowCount, err := s.generate(ctx, job)
+	if err == nil {
+		ext := "." + string(job.Format)
+		key := storage.GenerateExportKey(s.tenantID, job.RequestedByID, job.ID, ext)
+		fileName := fmt.Sprintf("%s-%s%s", job.Type, time.Now().UTC().Format("20060102-150405"), ext)
+
+		if _, err = s.storage.Upload(ctx, key, data, exportContentType(job.Format)); err == nil {
+			err = s.jobRepo.Complete(ctx, job.ID, key, fileName, rowCount, time.Now().Add(s.retention))
+		}
+	}
+	if err == nil {
+		return
+	}
+
+	// Leave jobs interrupted by shutdown running so they are retried once stale
+	if ctx.Err() != nil {
+		return
+	}
+	s.logger.LogError(ctx, "Export job failed", err, "job_id", job.ID, "type", job.Type)
+	if err := s.jobRepo.Fail(ctx, job.ID, "Failed to generate export, please try again"); err != nil {
+		s.logger.LogError(ctx, "Failed to record export job failure", err, "job_id", job.ID)
+	}
+}
+
+// PurgeExpired deletes export files past their retention period
+func (s *ExportService) PurgeExpired(ctx context.Context) {
+	jobs, err := s.jobRepo.ListExpired(ctx, 100)
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to list expired export jobs", err)
+		return
+	}
+	for _, job := range jobs {
+		if job.StorageKey != nil {
+			if err := s.storage.Delete(ctx, *job.StorageKey); err != nil {
+				s.logger.LogError(ctx, "Failed to delete expired export file", err, "job_id", job.ID)
+				continue
+			}
+		}
+		if err := s.jobRepo.MarkExpired(ctx, job.ID); err != nil {
+			s.logger.LogError(ctx, "Failed to mark export job expired", err, "job_id", job.ID)
+		}
+	}
+}
+
+// generate builds the export file for a job as the requesting user currently sees the data
+func (s *ExportService) generate(ctx context.Context, job *models.ExportJob) ([]byte, int, error) {
+	user, err := s.userRepo.GetByID(ctx, job.RequestedByID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, 0, fmt.Errorf("requesting user %d is no longer active", job.RequestedByID)
+	}
+	// The worker runs outside any request, so limit the export to the requester's organization
+	ctx = tenant.WithOrgID(ctx, user.OrgID)
+
+	switch job.Type {
+	case models.ExportTypeEmployees:
+		return s.generateEmployees(ctx, user, job)
+	case models.ExportTypeTimeOff:
+		return s.generateTimeOff(ctx, user, job)
+	}
+	return nil, 0, fmt.Errorf("unsupported export type %q", job.Type)
+}
+
+// generateEmployees exports the users visible to the requester
+func (s *ExportService) generateEmployees(ctx context.Context, user *models.User, job *models.ExportJob) ([]byte, int, error) {
+	users, err := s.userService.GetEmployeesForUser(ctx, user)
+	if err != nil {
+		return nil, 0, err
+	}
+	if department := job.Filters.Department; department != nil {
+		filtered := users[:0]
+		for _, u := range users {
+			if strings.EqualFold(u.Department, *department) {
+				filtered = append(filtered, u)
+			}
+		}
+		users = filtered
+	}
+
+	if job.Format == models.ExportFormatJSON {
+		data, err := json.Marshal(models.ToUserResponses(users))
+		return data, len(users), err
+	}
+
+	rows := [][]string{{"id", "first_name", "last_name", "email", "role", "title", "department", "squads", "supervisor_id", "date_started", "is_active"}}
+	for _, u := range users {
+		squads := make([]string, len(u.Squads))
+		for i, squad := range u.Squads {
+			squads[i] = squad.Name
+		}
+		rows = append(rows, []string{
+			strconv.FormatInt(u.ID, 10), u.FirstName, u.LastName, u.Email, string(u.Role), u.Title, u.Department,
+			strings.Join(squads, "; "), formatOptionalID(u.SupervisorID), formatOptionalDate(u.DateStarted),
+			strconv.FormatBool(u.IsActive),
+		})
+	}
+	data, err := encodeCSV(rows)
+	return data, len(users), err
+}
+
+// generateTimeOff exports the time off requests visible to the requester.
+// Viewers get availability only, without reasons or reviewer notes.
+func (s *ExportService) generateTimeOff(ctx context.Context, user *models.User, job *models.ExportJob) ([]byte, int, error) {
+	filter, err := job.Filters.TimeOffFilter()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var page *models.TimeOffRequestPage
+	if user.IsAdmin() || user.IsViewer() {
+		page, err = s.timeOffRepo.GetAllRequests(ctx, filter)
+	} else {
+		page, err = s.timeOffRepo.GetVisibleRequests(ctx, user, filter)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	requests := page.Data
+	if requests == nil {
+		requests = []models.TimeOffRequest{}
+	}
+	if user.IsViewer() {
+		for i := range requests {
+			requests[i] = requests[i].WithoutNotes()
+		}
+	}
+
+	if job.Format == models.ExportFormatJSON {
+		data, err := json.Marshal(requests)
+		return data, len(requests), err
+	}
+
+	rows := [][]string{{"id", "user_id", "employee", "email", "request_type", "status", "start_date", "end_date", "reason", "reviewer_notes", "reviewed_at", "created_at"}}
+	for _, req := range requests {
+		var name, email string
+		if req.User != nil {
+			name = strings.TrimSpace(req.User.FirstName + " " + req.User.LastName)
+			email = req.User.Email
+		}
+		reviewedAt := ""
+		if req.ReviewedAt != nil {
+			reviewedAt = req.ReviewedAt.UTC().Format(time.RFC3339)
+		}
+		rows = append(rows, []string{
+			strconv.FormatInt(req.ID, 10), strconv.FormatInt(req.UserID, 10), name, email,
+			string(req.RequestType), string(req.Status),
+			req.StartDate.Format("2006-01-02"), req.EndDate.Format("2006-01-02"),
+			derefString(req.Reason), derefString(req.ReviewerNotes), reviewedAt,
+			req.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	data, err := encodeCSV(rows)
+	return data, len(requests), err
+}
+
+// encodeCSV writes rows as CSV, neutralizing cells a spreadsheet would evaluate as formulas
+func encodeCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = neutralizeFormula(cell)
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// neutralizeFormula prefixes a cell a spreadsheet would evaluate as a formula with a quote
+func neutralizeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// exportContentType returns the MIME type of an export format
+func exportContentType(format models.ExportFormat) string {
+	if format == models.ExportFormatJSON {
+		return "application/json"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+func formatOptionalID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatInt(*id, 10)
+}
+
+func formatOptionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/storage"
+)
+
+// MockSignedStorage extends MockStorage with URL signing and records uploaded files
+type MockSignedStorage struct {
+	MockStorage
+	Files   map[string][]byte
+	Deleted []string
+}
+
+func newMockSignedStorage() *MockSignedStorage {
+	s := &MockSignedStorage{Files: make(map[string][]byte)}
+	s.UploadFunc = func(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+		s.Files[key] = data
+		return "https://example.com/" + key, nil
+	}
+	s.DeleteFunc = func(ctx context.Context, key string) error {
+		s.Deleted = append(s.Deleted, key)
+		return nil
+	}
+	return s
+}
+
+func (s *MockSignedStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return "https://example.com/" + key + "?signature=abc&expires=" + ttl.String(), nil
+}
+
+func newTestExportService(store *MockSignedStorage) (*ExportService, *mocks.MockExportJobRepository, *mocks.MockUserRepository, *mocks.MockTimeOffRepository) {
+	jobRepo := mocks.NewMockExportJobRepository()
+	userRepo := mocks.NewMockUserRepository()
+	timeOffRepo := mocks.NewMockTimeOffRepository()
+	userService := NewUserService(userRepo, mocks.NewMockSquadRepository())
+	service := NewExportService(jobRepo, userService, timeOffRepo, userRepo, store, "acme", 72*time.Hour, 15*time.Minute)
+	return service, jobRepo, userRepo, timeOffRepo
+}
+
+func TestExportService_Create_RequiresSignedStorage(t *testing.T) {
+	jobRepo := mocks.NewMockExportJobRepository()
+	user := &models.User{ID: 1}
+	req := &models.CreateExportJobRequest{Type: models.ExportTypeEmployees, Format: models.ExportFormatCSV}
+
+	// Plain storage can't hand out private download links
+	for name, store := range map[string]storage.Storage{"no storage": nil, "unsigned storage": &MockStorage{}} {
+		t.Run(name, func(t *testing.T) {
+			service := NewExportService(jobRepo, nil, nil, nil, store, "acme", time.Hour, time.Minute)
+			if _, err := service.Create(context.Background(), user, req); !errors.Is(err, ErrExportStorageNotConfigured) {
+				t.Errorf("Create() error = %v, want ErrExportStorageNotConfigured", err)
+			}
+		})
+	}
+}
+
+func TestExportService_EmployeesCSV(t *testing.T) {
+	store := newMockSignedStorage()
+	service, jobRepo, userRepo, _ := newTestExportService(store)
+	supervisorID := int64(1)
+	userRepo.AddUser(&models.User{ID: 1, FirstName: "Grace", Role: models.RoleSupervisor, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Department: "Engineering", SupervisorID: &supervisorID, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 3, FirstName: "Linus", Department: "Sales", SupervisorID: &supervisorID, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 4, FirstName: "Alan", Department: "Engineering", IsActive: true})
+	supervisor := &models.User{ID: 1, Role: models.RoleSupervisor}
+
+	ctx := context.Background()
+	department := "engineering"
+	job, err := service.Create(ctx, supervisor, &models.CreateExportJobRequest{
+		Type:    models.ExportTypeEmployees,
+		Format:  models.ExportFormatCSV,
+		Filters: models.ExportFilters{Department: &department},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if job.Status != models.ExportJobStatusPending || job.DownloadURL != nil {
+		t.Errorf("new job = %+v, want pending without a download URL", job)
+	}
+
+	if processed := service.ProcessPending(ctx); processed != 1 {
+		t.Fatalf("ProcessPending() = %d, want 1", processed)
+	}
+
+	stored := jobRepo.Jobs[job.ID]
+	if stored.Status != models.ExportJobStatusCompleted || stored.RowCount == nil || *stored.RowCount != 1 {
+		t.Fatalf("job after processing = %+v, want completed with 1 row", stored)
+	}
+	if !strings.HasPrefix(*stored.StorageKey, "tenants/acme/users/1/exports/") {
+		t.Errorf("storage key = %s, want it under the requester's prefix", *stored.StorageKey)
+	}
+
+	// Only the supervisor's direct report in Engineering is exported
+	csv := string(store.Files[*stored.StorageKey])
+	lines := strings.Split(strings.TrimSpace(csv), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "2,Ada,Lovelace,ada@example.com,") {
+		t.Errorf("csv = %q", csv)
+	}
+
+	got, err := service.Get(ctx, supervisor, job.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.DownloadURL == nil || !strings.Contains(*got.DownloadURL, "signature=") {
+		t.Errorf("DownloadURL = %v, want a signed URL", got.DownloadURL)
+	}
+
+	if _, err := service.Get(ctx, &models.User{ID: 2}, job.ID); !errors.Is(err, ErrExportJobNotFound) {
+		t.Errorf("Get() by another user error = %v, want ErrExportJobNotFound", err)
+	}
+}
+
+func TestExportService_TimeOff(t *testing.T) {
+	store := newMockSignedStorage()
+	service, jobRepo, userRepo, timeOffRepo := newTestExportService(store)
+	userRepo.AddUser(&models.User{ID: 1, Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 9, Role: models.RoleViewer, IsActive: true})
+	reason := "=HYPERLINK(\"http://evil\")"
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	timeOffRepo.AddRequest(&models.TimeOffRequest{ID: 1, UserID: 2, RequestType: models.TimeOffTypeVacation, Status: models.TimeOffStatusApproved, StartDate: start, EndDate: start.AddDate(0, 0, 4), Reason: &reason})
+	timeOffRepo.AddRequest(&models.TimeOffRequest{ID: 2, UserID: 3, RequestType: models.TimeOffTypeSick, Status: models.TimeOffStatusPending, StartDate: start, EndDate: start, Reason: &reason})
+
+	ctx := context.Background()
+	approved := models.TimeOffStatusApproved
+	filters := models.ExportFilters{Status: &approved}
+
+	t.Run("csv escapes formula cells", func(t *testing.T) {
+		job, err := service.Create(ctx, &models.User{ID: 1}, &models.CreateExportJobRequest{Type: models.ExportTypeTimeOff, Format: models.ExportFormatCSV, Filters: filters})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		service.ProcessPending(ctx)
+
+		stored := jobRepo.Jobs[job.ID]
+		if stored.Status != models.ExportJobStatusCompleted || *stored.RowCount != 1 {
+			t.Fatalf("job = %+v, want completed with 1 row", stored)
+		}
+		csv := string(store.Files[*stored.StorageKey])
+		if !strings.Contains(csv, `"'=HYPERLINK(""http://evil"")"`) {
+			t.Errorf("csv should neutralize the formula in the reason, got %q", csv)
+		}
+	})
+
+	t.Run("json for viewers omits notes", func(t *testing.T) {
+		job, err := service.Create(ctx, &models.User{ID: 9}, &models.CreateExportJobRequest{Type: models.ExportTypeTimeOff, Format: models.ExportFormatJSON, Filters: filters})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		service.ProcessPending(ctx)
+
+		stored := jobRepo.Jobs[job.ID]
+		var requests []models.TimeOffRequest
+		if err := json.Unmarshal(store.Files[*stored.StorageKey], &requests); err != nil {
+			t.Fatalf("export is not valid JSON: %v", err)
+		}
+		if len(requests) != 1 || requests[0].Reason != nil {
+			t.Errorf("requests = %+v, want one request without a reason", requests)
+		}
+	})
+}
+
+func TestExportService_ProcessPending_Failures(t *testing.T) {
+	store := newMockSignedStorage()
+	service, jobRepo, userRepo, _ := newTestExportService(store)
+	userRepo.AddUser(&models.User{ID: 1, IsActive: false})
+
+	job, err := service.Create(context.Background(), &models.User{ID: 1}, &models.CreateExportJobRequest{Type: models.ExportTypeEmployees, Format: models.ExportFormatCSV})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	service.ProcessPending(context.Background())
+
+	stored := jobRepo.Jobs[job.ID]
+	if stored.Status != models.ExportJobStatusFailed || stored.Error == nil {
+		t.Errorf("job = %+v, want failed with an error message", stored)
+	}
+	if len(store.Files) != 0 {
+		t.Errorf("failed job uploaded %d files", len(store.Files))
+	}
+}
+
+func TestExportService_PurgeExpired(t *testing.T) {
+	store := newMockSignedStorage()
+	service, jobRepo, _, _ := newTestExportService(store)
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	oldKey, newKey := "tenants/acme/users/1/exports/1.csv", "tenants/acme/users/1/exports/2.csv"
+	jobRepo.AddJob(&models.ExportJob{ID: 1, RequestedByID: 1, Status: models.ExportJobStatusCompleted, StorageKey: &oldKey, ExpiresAt: &past})
+	jobRepo.AddJob(&models.ExportJob{ID: 2, RequestedByID: 1, Status: models.ExportJobStatusCompleted, StorageKey: &newKey, ExpiresAt: &future})
+
+	service.PurgeExpired(context.Background())
+
+	if len(store.Deleted) != 1 || store.Deleted[0] != oldKey {
+		t.Errorf("deleted = %v, want only %s", store.Deleted, oldKey)
+	}
+	if jobRepo.Jobs[1].Status != models.ExportJobStatusExpired || jobRepo.Jobs[2].Status != models.ExportJobStatusCompleted {
+		t.Errorf("statuses = %s, %s", jobRepo.Jobs[1].Status, jobRepo.Jobs[2].Status)
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// PullRequestSource provides open pull requests from GitHub
+type PullRequestSource interface {
+	GetPullRequests(login string, role models.GitHubPullRequestRole, org string, maxResults int) ([]models.GitHubPullRequest, error)
+}
+
+// GitHubWork is the open pull requests a GitHub account is assigned to or asked to review
+type GitHubWork struct {
+	Assigned        []models.GitHubPullRequest
+	ReviewRequested []models.GitHubPullRequest
+}
+
+// FetchGitHubWork fetches each login's assigned pull requests and review requests, at most
+// concurrency logins at a time. Logins whose fetch fails are logged and left out of the result.
+func FetchGitHubWork(ctx context.Context, source PullRequestSource, logins []string, org string, maxResults, concurrency int) map[string]GitHubWork {
+	work := make(map[string]GitHubWork, len(logins))
+	var mu sync.Mutex
+	fetchConcurrently(logins, concurrency, func(login string) {
+		assigned, err := source.GetPullRequests(login, models.GitHubPullRequestAssignee, org, maxResults)
+		if err != nil {
+			logger.Default().WithComponent("github").LogError(ctx, "Failed to fetch assigned pull requests", err, "github_login", login)
+			return
+		}
+		reviews, err := source.GetPullRequests(login, models.GitHubPullRequestReviewer, org, maxResults)
+		if err != nil {
+			logger.Default().WithComponent("github").LogError(ctx, "Failed to fetch review requests", err, "github_login", login)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		work[login] = GitHubWork{Assigned: assigned, ReviewRequested: reviews}
+	})
+	return work
+}
+
+// OnTimeOffToday reports whether approved time off covers the current day in loc
+func OnTimeOffToday(requests []models.TimeOffRequest, now time.Time, loc *time.Location) bool {
+	local := now.In(loc)
+	return isOnTimeOff(requests, time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+const (
+	// jiraRecentAPIErrorWindow is how long a failed Jira API call marks the connection as degraded
+	jiraRecentAPIErrorWindow = time.Hour
+	// jiraWebhookStaleAfter is how long without a Jira webhook before deliveries are reported as stale
+	jiraWebhookStaleAfter = 24 * time.Hour
+	// maxJiraErrorLength caps stored error messages, which can embed whole response bodies
+	maxJiraErrorLength = 500
+	// jiraErrorRateMinCalls is how many recent calls are needed before the error rate is judged
+	jiraErrorRateMinCalls = 10
+	// jiraErrorRateDegraded is the recent error rate at which the connection is reported as degraded
+	jiraErrorRateDegraded = 0.2
+)
+
+// AdminAlertSender delivers operational alerts to admins by email
+type AdminAlertSender interface {
+	SendAdminAlert(ctx context.Context, to []string, subject, text string) error
+}
+
+// JiraHealthService tracks the org-wide Jira connection's token refreshes and API failures,
+// and alerts admins when token refresh starts failing so the integration doesn't break silently.
+// All methods are safe to call on a nil service.
+type JiraHealthService struct {
+	repo     repository.OrgJiraRepository
+	userRepo repository.UserRepository
+	slack    *SlackNotifier
+	email    AdminAlertSender
+	logger   *logger.Logger
+}
+
+// NewJiraHealthService creates a new Jira health service; slack and email may be nil
+func NewJiraHealthService(repo repository.OrgJiraRepository, userRepo repository.UserRepository, slack *SlackNotifier, email AdminAlertSender) *JiraHealthService {
+	return &JiraHealthService{
+		repo:     repo,
+		userRepo: userRepo,
+		slack:    slack,
+		email:    email,
+		logger:   logger.Default().WithComponent("jira-health"),
+	}
+}
+
+// RecordRefreshFailure stores a failed token refresh and alerts admins on the first failure in a row
+func (s *JiraHealthService) RecordRefreshFailure(ctx context.Context, refreshErr error) {
+	if s == nil {
+		return
+	}
+
+	message := truncateJiraError(refreshErr)
+	failures, err := s.repo.RecordRefreshFailure(ctx, message)
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to record Jira token refresh failure", err)
+		return
+	}
+
+	// Later failures are already covered by the first alert
+	if failures == 1 {
+		s.notifyAdmins(ctx, "Jira connection needs attention",
+			fmt.Sprintf("Refreshing the Jira access token failed: %s\n\nJira tasks, projects, and epics will stop loading until an admin reconnects Jira.", message))
+	}
+}
+
+// RecordAPIError stores the most recent failed Jira API call
+func (s *JiraHealthService) RecordAPIError(ctx context.Context, apiErr error) {
+	if s == nil {
+		return
+	}
+	if err := s.repo.RecordAPIError(ctx, truncateJiraError(apiErr)); err != nil {
+		s.logger.LogError(ctx, "Failed to record Jira API error", err)
+	}
+}
+
+// notifyAdmins posts the alert to Slack and emails every active admin, logging any delivery failures
+func (s *JiraHealthService) notifyAdmins(ctx context.Context, subject, text string) {
+	if s.slack.IsEnabled() {
+		if err := s.slack.Notify(ctx, fmt.Sprintf(":rotating_light: *%s*\n%s", subject, text)); err != nil {
+			s.logger.LogError(ctx, "Failed to post Jira alert to Slack", err)
+		}
+	}
+
+	if s.email == nil {
+		return
+	}
+	users, err := s.userRepo.GetAll(ctx)
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to load admins for Jira alert", err)
+		return
+	}
+	var admins []string
+	for _, user := range users {
+		if user.IsAdmin() && user.IsActive {
+			admins = append(admins, user.Email)
+		}
+	}
+	if len(admins) == 0 {
+		return
+	}
+	if err := s.email.SendAdminAlert(ctx, admins, subject, text); err != nil {
+		s.logger.LogError(ctx, "Failed to email Jira alert to admins", err)
+	}
+}
+
+// BuildJiraHealth summarizes stored connection state as of now
+func BuildJiraHealth(settings *models.OrgJiraSettings, now time.Time) *models.JiraHealth {
+	health := &models.JiraHealth{Status: models.JiraHealthDisconnected, Warnings: []string{}}
+	if settings == nil {
+		return health
+	}
+
+	expiresAt := settings.OAuthTokenExpiresAt
+	health.Status = models.JiraHealthHealthy
+	health.SiteURL = settings.SiteURL
+	health.TokenExpiresAt = &expiresAt
+	health.TokenExpired = !now.Before(expiresAt)
+	health.LastRefreshedAt = settings.LastRefreshedAt
+	health.RefreshFailures = settings.RefreshFailures
+	health.LastRefreshError = settings.LastRefreshError
+	health.LastRefreshErrorAt = settings.LastRefreshErrorAt
+	health.LastAPIError = settings.LastAPIError
+	health.LastAPIErrorAt = settings.LastAPIErrorAt
+	health.LastWebhookAt = settings.LastWebhookAt
+
+	if settings.LastAPIErrorAt != nil && now.Sub(*settings.LastAPIErrorAt) < jiraRecentAPIErrorWindow {
+		health.Status = models.JiraHealthDegraded
+		health.Warnings = append(health.Warnings, "Jira API calls have failed within the last hour")
+	}
+	if settings.LastWebhookAt != nil && now.Sub(*settings.LastWebhookAt) > jiraWebhookStaleAfter {
+		health.Status = models.JiraHealthDegraded
+		health.Warnings = append(health.Warnings, "No Jira webhooks received in the last 24 hours")
+	}
+	if settings.RefreshFailures > 0 {
+		health.Status = models.JiraHealthFailing
+		health.Warnings = append(health.Warnings, fmt.Sprintf("Token refresh has failed %d time(s) in a row; reconnect Jira to restore access", settings.RefreshFailures))
+	}
+
+	return health
+}
+
+// JiraDiagnostics are live checks of the org-wide Jira connection, beyond its stored state
+type JiraDiagnostics struct {
+	TokenCheck  *models.JiraTokenCheck
+	APICalls    *models.JiraAPICallStats
+	CircuitOpen bool
+	LastSyncAt  *time.Time
+}
+
+// ApplyJiraDiagnostics adds live checks to a connected org's health, worsening its status and
+// adding warnings for unverified tokens, missing scopes, a high recent error rate, or an open circuit
+func ApplyJiraDiagnostics(health *models.JiraHealth, diag JiraDiagnostics) {
+	if health.Status == models.JiraHealthDisconnected {
+		return
+	}
+	health.TokenCheck = diag.TokenCheck
+	health.APICalls = diag.APICalls
+	health.CircuitOpen = diag.CircuitOpen
+	health.LastSyncAt = diag.LastSyncAt
+
+	if check := diag.TokenCheck; check != nil {
+		if !check.Verified {
+			worsenJiraHealth(health, models.JiraHealthFailing)
+			health.Warnings = append(health.Warnings, fmt.Sprintf("The stored Jira tokens could not be verified: %s", check.Error))
+		} else if len(check.MissingScopes) > 0 {
+			worsenJiraHealth(health, models.JiraHealthDegraded)
+			health.Warnings = append(health.Warnings, fmt.Sprintf("Jira didn't grant %s; reconnect Jira to grant all scopes", strings.Join(check.MissingScopes, ", ")))
+		}
+	}
+	if calls := diag.APICalls; calls != nil && calls.Calls >= jiraErrorRateMinCalls && calls.ErrorRate >= jiraErrorRateDegraded {
+		worsenJiraHealth(health, models.JiraHealthDegraded)
+		health.Warnings = append(health.Warnings, fmt.Sprintf("%.0f%% of Jira API calls failed in the last %d minutes", calls.ErrorRate*100, calls.WindowMinutes))
+	}
+	if diag.CircuitOpen {
+		worsenJiraHealth(health, models.JiraHealthDegraded)
+		health.Warnings = append(health.Warnings, "Jira calls are paused after repeated failures")
+	}
+}
+
+// worsenJiraHealth sets the health status unless it's already worse
+func worsenJiraHealth(health *models.JiraHealth, status models.JiraHealthStatus) {
+	if health.Status == models.JiraHealthHealthy || status == models.JiraHealthFailing {
+		health.Status = status
+	}
+}
+
+// NewJiraAPICallStats summarizes a count of recent calls
+func NewJiraAPICallStats(window time.Duration, calls, failures int) *models.JiraAPICallStats {
+	stats := &models.JiraAPICallStats{
+		WindowMinutes: int(window / time.Minute),
+		Calls:         calls,
+		Failures:      failures,
+	}
+	if calls > 0 {
+		stats.ErrorRate = float64(failures) / float64(calls)
+	}
+	return stats
+}
+
+// truncateJiraError shortens an error for storage
+func truncateJiraError(err error) string {
+	message := err.Error()
+	if len(message) > maxJiraErrorLength {
+		message = message[:maxJiraErrorLength] + "…"
+	}
+	return message
+}
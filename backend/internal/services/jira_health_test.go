@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+// fakeAdminAlerts records alert emails instead of sending them
+type fakeAdminAlerts struct {
+	to      [][]string
+	subject []string
+}
+
+func (f *fakeAdminAlerts) SendAdminAlert(ctx context.Context, to []string, subject, text string) error {
+	f.to = append(f.to, to)
+	f.subject = append(f.subject, subject)
+	return nil
+}
+
+func TestBuildJiraHealth(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-10 * time.Minute)
+	old := now.Add(-48 * time.Hour)
+	apiError := "GET /rest/api/3/search/jql returned status 503"
+
+	tests := []struct {
+		name         string
+		settings     *models.OrgJiraSettings
+		wantStatus   models.JiraHealthStatus
+		wantWarnings int
+		wantExpired  bool
+	}{
+		{
+			name:       "not connected",
+			wantStatus: models.JiraHealthDisconnected,
+		},
+		{
+			name:       "healthy",
+			settings:   &models.OrgJiraSettings{OAuthTokenExpiresAt: now.Add(time.Hour), LastRefreshedAt: &recent, LastWebhookAt: &recent},
+			wantStatus: models.JiraHealthHealthy,
+		},
+		{
+			name:        "expired token is refreshed on the next call",
+			settings:    &models.OrgJiraSettings{OAuthTokenExpiresAt: now.Add(-time.Minute)},
+			wantStatus:  models.JiraHealthHealthy,
+			wantExpired: true,
+		},
+		{
+			name:       "old api error",
+			settings:   &models.OrgJiraSettings{OAuthTokenExpiresAt: now.Add(time.Hour), LastAPIError: &apiError, LastAPIErrorAt: &old},
+			wantStatus: models.JiraHealthHealthy,
+		},
+		{
+			name:         "recent api error and stale webhook",
+			settings:     &models.OrgJiraSettings{OAuthTokenExpiresAt: now.Add(time.Hour), LastAPIError: &apiError, LastAPIErrorAt: &recent, LastWebhookAt: &old},
+			wantStatus:   models.JiraHealthDegraded,
+			wantWarnings: 2,
+		},
+		{
+			name:         "refresh failing",
+			settings:     &models.OrgJiraSettings{OAuthTokenExpiresAt: now.Add(-time.Hour), RefreshFailures: 3},
+			wantStatus:   models.JiraHealthFailing,
+			wantWarnings: 1,
+			wantExpired:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health := BuildJiraHealth(tt.settings, now)
+			if health.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", health.Status, tt.wantStatus)
+			}
+			if len(health.Warnings) != tt.wantWarnings {
+				t.Errorf("Warnings = %v, want %d", health.Warnings, tt.wantWarnings)
+			}
+			if health.TokenExpired != tt.wantExpired {
+				t.Errorf("TokenExpired = %v, want %v", health.TokenExpired, tt.wantExpired)
+			}
+		})
+	}
+}
+
+func TestApplyJiraDiagnostics(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       models.JiraHealthStatus
+		diag         JiraDiagnostics
+		wantStatus   models.JiraHealthStatus
+		wantWarnings int
+	}{
+		{
+			name:       "all good",
+			status:     models.JiraHealthHealthy,
+			diag:       JiraDiagnostics{TokenCheck: &models.JiraTokenCheck{Verified: true}, APICalls: NewJiraAPICallStats(time.Hour, 50, 1)},
+			wantStatus: models.JiraHealthHealthy,
+		},
+		{
+			name:         "unverified tokens",
+			status:       models.JiraHealthDegraded,
+			diag:         JiraDiagnostics{TokenCheck: &models.JiraTokenCheck{Error: "token refresh failed (status 403)"}},
+			wantStatus:   models.JiraHealthFailing,
+			wantWarnings: 1,
+		},
+		{
+			name:         "missing scopes",
+			status:       models.JiraHealthHealthy,
+			diag:         JiraDiagnostics{TokenCheck: &models.JiraTokenCheck{Verified: true, MissingScopes: []string{"read:jql:jira"}}},
+			wantStatus:   models.JiraHealthDegraded,
+			wantWarnings: 1,
+		},
+		{
+			name:         "high error rate and open circuit",
+			status:       models.JiraHealthHealthy,
+			diag:         JiraDiagnostics{APICalls: NewJiraAPICallStats(time.Hour, 20, 8), CircuitOpen: true},
+			wantStatus:   models.JiraHealthDegraded,
+			wantWarnings: 2,
+		},
+		{
+			name:       "too few calls to judge",
+			status:     models.JiraHealthHealthy,
+			diag:       JiraDiagnostics{APICalls: NewJiraAPICallStats(time.Hour, 3, 3)},
+			wantStatus: models.JiraHealthHealthy,
+		},
+		{
+			name:       "failing stays failing",
+			status:     models.JiraHealthFailing,
+			diag:       JiraDiagnostics{CircuitOpen: true},
+			wantStatus: models.JiraHealthFailing,
+			// Only the circuit warning is added here
+			wantWarnings: 1,
+		},
+		{
+			name:       "disconnected is left alone",
+			status:     models.JiraHealthDisconnected,
+			diag:       JiraDiagnostics{CircuitOpen: true},
+			wantStatus: models.JiraHealthDisconnected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health := &models.JiraHealth{Status: tt.status, Warnings: []string{}}
+			ApplyJiraDiagnostics(health, tt.diag)
+			if health.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", health.Status, tt.wantStatus)
+			}
+			if len(health.Warnings) != tt.wantWarnings {
+				t.Errorf("Warnings = %v, want %d", health.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestNewJiraAPICallStats(t *testing.T) {
+	stats := NewJiraAPICallStats(time.Hour, 8, 2)
+	if stats.WindowMinutes != 60 || stats.ErrorRate != 0.25 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if NewJiraAPICallStats(time.Hour, 0, 0).ErrorRate != 0 {
+		t.Error("ErrorRate with no calls should be 0")
+	}
+}
+
+func TestJiraHealthService_RecordRefreshFailure_AlertsOnce(t *testing.T) {
+	var slackCalls atomic.Int32
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slackCalls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slack.Close()
+
+	repo := mocks.NewMockOrgJiraRepository()
+	repo.Settings = &models.OrgJiraSettings{ID: 1, SiteURL: "https://acme.atlassian.net"}
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Email: "admin@example.com", Role: models.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&models.User{ID: 2, Email: "former@example.com", Role: models.RoleAdmin})
+	userRepo.AddUser(&models.User{ID: 3, Email: "lead@example.com", Role: models.RoleSupervisor, IsActive: true})
+	alerts := &fakeAdminAlerts{}
+
+	service := NewJiraHealthService(repo, userRepo, NewSlackNotifier(&config.Config{SlackWebhookURL: slack.URL}), alerts)
+	ctx := context.Background()
+
+	service.RecordRefreshFailure(ctx, errors.New("token refresh failed (status 400): invalid_grant"))
+	service.RecordRefreshFailure(ctx, errors.New("token refresh failed (status 400): invalid_grant"))
+
+	if repo.Settings.RefreshFailures != 2 || !strings.Contains(*repo.Settings.LastRefreshError, "invalid_grant") {
+		t.Errorf("stored failures = %d, error = %v", repo.Settings.RefreshFailures, repo.Settings.LastRefreshError)
+	}
+	if slackCalls.Load() != 1 {
+		t.Errorf("slack called %d times, want 1", slackCalls.Load())
+	}
+	if len(alerts.to) != 1 || len(alerts.to[0]) != 1 || alerts.to[0][0] != "admin@example.com" {
+		t.Errorf("alert emails = %v, want one to admin@example.com", alerts.to)
+	}
+
+	// A successful refresh resets the streak, so the next failure alerts again
+	_ = repo.UpdateTokens(ctx, "access", "refresh", time.Now().Add(time.Hour))
+	service.RecordRefreshFailure(ctx, errors.New("token refresh failed (status 500)"))
+	if len(alerts.to) != 2 {
+		t.Errorf("sent %d alert emails after a new failure streak, want 2", len(alerts.to))
+	}
+}
+
+func TestJiraHealthService_NilIsNoop(t *testing.T) {
+	var service *JiraHealthService
+	service.RecordRefreshFailure(context.Background(), errors.New("boom"))
+	service.RecordAPIError(context.Background(), errors.New("boom"))
+}
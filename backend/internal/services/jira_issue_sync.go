@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// jiraSyncMaxIssues caps how many unresolved issues are synced per account
+const jiraSyncMaxIssues = 100
+
+// JiraIssueSource fetches an account's unresolved Jira issues
+type JiraIssueSource = WorkItemSource
+
+// FilteredJiraIssueSource fetches an account's unresolved Jira issues matching a JQL clause
+type FilteredJiraIssueSource interface {
+	GetFilteredIssuesByAccountID(accountID, filterJQL string, maxResults int) ([]models.JiraIssue, error)
+}
+
+// JiraConnector returns a source for the Jira connection of the organization ctx is scoped to
+type JiraConnector func(ctx context.Context) (JiraIssueSource, error)
+
+// JiraIssueSyncService serves each mapped user's open Jira issues from the local cache and keeps
+// it current in a background worker. Jira webhooks update cached issues between syncs.
+// All methods are safe to call on a nil service, which always fetches live.
+type JiraIssueSyncService struct {
+	cacheRepo repository.JiraIssueCacheRepository
+	userRepo  repository.UserRepository
+	interval  time.Duration
+	logger    *logger.Logger
+}
+
+// NewJiraIssueSyncService creates a new Jira issue sync service that resyncs accounts older than interval
+func NewJiraIssueSyncService(cacheRepo repository.JiraIssueCacheRepository, userRepo repository.UserRepository, interval time.Duration) *JiraIssueSyncService {
+	return &JiraIssueSyncService{
+		cacheRepo: cacheRepo,
+		userRepo:  userRepo,
+		interval:  interval,
+		logger:    logger.Default().WithComponent("jira-issue-sync"),
+	}
+}
+
+// Load returns the open issues for each account along with when they were synced.
+// Synced accounts are served from the cache; the rest, or all of them with refresh set, are fetched
+// live and cached, at most concurrency at a time. Accounts whose live fetch fails are logged and
+// left out of the result.
+func (s *JiraIssueSyncService) Load(ctx context.Context, connect JiraConnector, accountIDs []string, refresh bool, concurrency int) (map[string][]models.JiraIssue, map[string]time.Time, error) {
+	issues := make(map[string][]models.JiraIssue, len(accountIDs))
+	syncTimes := make(map[string]time.Time, len(accountIDs))
+
+	missing := accountIDs
+	if s != nil && !refresh {
+		var err error
+		if syncTimes, err = s.cacheRepo.GetSyncTimes(ctx, accountIDs); err != nil {
+			return nil, nil, err
+		}
+		missing = nil
+		for _, id := range accountIDs {
+			if _, ok := syncTimes[id]; !ok {
+				missing = append(missing, id)
+			}
+		}
+
+		if len(syncTimes) > 0 {
+			cached, err := s.cacheRepo.ListOpen(ctx, accountIDs)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, issue := range cached {
+				if issue.Assignee == nil {
+					continue
+				}
+				accountID := issue.Assignee.AccountID
+				if _, ok := syncTimes[accountID]; ok {
+					issues[accountID] = append(issues[accountID], issue)
+				}
+			}
+			for id := range syncTimes {
+				if issues[id] == nil {
+					issues[id] = []models.JiraIssue{}
+				}
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return issues, syncTimes, nil
+	}
+
+	source, err := connect(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mu sync.Mutex
+	fetchConcurrently(missing, concurrency, func(accountID string) {
+		fetched, syncedAt, err := s.syncAccount(ctx, source, accountID)
+		if err != nil {
+			s.log().LogError(ctx, "Failed to fetch Jira issues", err, "account_id", accountID)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		issues[accountID] = fetched
+		if syncedAt != nil {
+			syncTimes[accountID] = *syncedAt
+		}
+	})
+
+	return issues, syncTimes, nil
+}
+
+// FetchFilteredJiraIssues fetches each account's open issues matching filterJQL live, without
+// touching the cache, at most concurrency at a time. Accounts whose fetch fails are logged and
+// left out of the result.
+func FetchFilteredJiraIssues(ctx context.Context, source FilteredJiraIssueSource, accountIDs []string, filterJQL string, maxResults, concurrency int) map[string][]models.JiraIssue {
+	issues := make(map[string][]models.JiraIssue, len(accountIDs))
+	var mu sync.Mutex
+	fetchConcurrently(accountIDs, concurrency, func(accountID string) {
+		fetched, err := source.GetFilteredIssuesByAccountID(accountID, filterJQL, maxResults)
+		if err != nil {
+			logger.Default().WithComponent("jira-issue-sync").LogError(ctx, "Failed to fetch filtered Jira issues", err, "account_id", accountID)
+			return
+		}
+		if fetched == nil {
+			fetched = []models.JiraIssue{}
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		issues[accountID] = fetched
+	})
+	return issues
+}
+
+// fetchConcurrently calls fetch for each account from a fixed pool of at most concurrency
+// workers, so a large team never holds more than that many goroutines waiting on a tracker
+func fetchConcurrently(accountIDs []string, concurrency int, fetch func(accountID string)) {
+	pending := make(chan string)
+	var wg sync.WaitGroup
+	for range min(max(concurrency, 1), len(accountIDs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for accountID := range pending {
+				fetch(accountID)
+			}
+		}()
+	}
+	for _, accountID := range accountIDs {
+		pending <- accountID
+	}
+	close(pending)
+	wg.Wait()
+}
+
+// syncAccount fetches an account's open issues and, unless the service is nil, caches them.
+// The sync time is nil when nothing was cached.
+func (s *JiraIssueSyncService) syncAccount(ctx context.Context, source JiraIssueSource, accountID string) ([]models.JiraIssue, *time.Time, error) {
+	issues, err := source.GetIssuesByAccountID(accountID, jiraSyncMaxIssues)
+	if err != nil {
+		return nil, nil, err
+	}
+	if issues == nil {
+		issues = []models.JiraIssue{}
+	}
+	if s == nil {
+		return issues, nil, nil
+	}
+
+	syncedAt := time.Now()
+	if err := s.cacheRepo.ReplaceOpenForAssignee(ctx, accountID, issues, syncedAt); err != nil {
+		// The live issues are still good to serve
+		s.logger.LogError(ctx, "Failed to cache Jira issues", err, "account_id", accountID)
+		return issues, nil, nil
+	}
+	return issues, &syncedAt, nil
+}
+
+// log returns the service logger, or the default logger for a nil service
+func (s *JiraIssueSyncService) log() *logger.Logger {
+	if s == nil {
+		return logger.Default().WithComponent("jira-issue-sync")
+	}
+	return s.logger
+}
+
+// LastSyncedAt returns when any account's issues were last synced, or nil if none have been
+// or the service is nil
+func (s *JiraIssueSyncService) LastSyncedAt(ctx context.Context) (*time.Time, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return s.cacheRepo.GetLastSyncTime(ctx)
+}
+
+// Start resyncs stale accounts every interval until workers are stopped
+func (s *JiraIssueSyncService) Start(workers *lifecycle.Group, connect JiraConnector) {
+	if s == nil || s.interval <= 0 {
+		return
+	}
+
+	workers.Go("jira-issue-sync", func(ctx context.Context) {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.SyncStale(ctx, connect)
+			}
+		}
+	})
+}
+
+// SyncStale resyncs every mapped active user's issues that haven't been synced within the interval
+// and returns how many accounts were synced. Each organization's users are synced from its own
+// Jira connection.
+func (s *JiraIssueSyncService) SyncStale(ctx context.Context, connect JiraConnector) int {
+	users, err := s.userRepo.GetAll(ctx)
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to list users for Jira sync", err)
+		return 0
+	}
+	accountIDsByOrg := make(map[int64][]string)
+	for _, u := range users {
+		if u.IsActive && u.JiraAccountID != nil && *u.JiraAccountID != "" {
+			accountIDsByOrg[u.OrgID] = append(accountIDsByOrg[u.OrgID], *u.JiraAccountID)
+		}
+	}
+
+	synced := 0
+	for _, orgID := range slices.Sorted(maps.Keys(accountIDsByOrg)) {
+		if ctx.Err() != nil {
+			break
+		}
+		synced += s.syncStaleInOrg(tenant.WithOrgID(ctx, orgID), connect, accountIDsByOrg[orgID])
+	}
+	return synced
+}
+
+// syncStaleInOrg resyncs the accounts, all of the organization ctx is scoped to, that haven't
+// been synced within the interval
+func (s *JiraIssueSyncService) syncStaleInOrg(ctx context.Context, connect JiraConnector, accountIDs []string) int {
+	syncTimes, err := s.cacheRepo.GetSyncTimes(ctx, accountIDs)
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to get Jira sync times", err)
+		return 0
+	}
+	cutoff := time.Now().Add(-s.interval)
+	var stale []string
+	for _, id := range accountIDs {
+		if syncedAt, ok := syncTimes[id]; !ok || syncedAt.Before(cutoff) {
+			stale = append(stale, id)
+		}
+	}
+	if len(stale) == 0 {
+		return 0
+	}
+
+	source, err := connect(ctx)
+	if err != nil {
+		// Usually Jira isn't connected; the next tick tries again
+		s.logger.WithContext(ctx).Debug("Skipping Jira sync", "error", err)
+		return 0
+	}
+
+	synced := 0
+	for _, id := range stale {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, syncedAt, err := s.syncAccount(ctx, source, id); err != nil {
+			s.logger.LogError(ctx, "Failed to sync Jira issues", err, "account_id", id)
+		} else if syncedAt != nil {
+			synced++
+		}
+	}
+	return synced
+}
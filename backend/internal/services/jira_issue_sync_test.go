@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// fakeJiraIssueSource serves fixed issues per account and counts fetches
+type fakeJiraIssueSource struct {
+	mu      sync.Mutex
+	issues  map[string][]models.JiraIssue
+	fetched map[string]int
+}
+
+func (f *fakeJiraIssueSource) GetIssuesByAccountID(accountID string, maxResults int) ([]models.JiraIssue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetched[accountID]++
+	issues, ok := f.issues[accountID]
+	if !ok {
+		return nil, errors.New("jira unavailable")
+	}
+	return issues, nil
+}
+
+func (f *fakeJiraIssueSource) connect(ctx context.Context) (JiraIssueSource, error) {
+	return f, nil
+}
+
+func openJiraIssue(key, accountID string, updated time.Time) models.JiraIssue {
+	return models.JiraIssue{Key: key, Assignee: &models.JiraUser{AccountID: accountID}, Updated: updated}
+}
+
+func TestJiraIssueSyncService_Load(t *testing.T) {
+	now := time.Now()
+	cache := mocks.NewMockJiraIssueCacheRepository()
+	cachedAt := now.Add(-5 * time.Minute)
+	cache.SyncTimes["acct-cached"] = cachedAt
+	_, _ = cache.Upsert(context.Background(), &models.JiraIssue{Key: "WEB-1", Assignee: &models.JiraUser{AccountID: "acct-cached"}, Updated: now}, false)
+
+	source := &fakeJiraIssueSource{
+		issues: map[string][]models.JiraIssue{
+			"acct-cached": {openJiraIssue("WEB-9", "acct-cached", now)},
+			"acct-new":    {openJiraIssue("WEB-2", "acct-new", now)},
+		},
+		fetched: map[string]int{},
+	}
+	service := NewJiraIssueSyncService(cache, mocks.NewMockUserRepository(), 15*time.Minute)
+
+	issues, syncTimes, err := service.Load(context.Background(), source.connect, []string{"acct-cached", "acct-new", "acct-broken"}, false, 2)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if source.fetched["acct-cached"] != 0 {
+		t.Error("synced account was fetched live")
+	}
+	if got := issues["acct-cached"]; len(got) != 1 || got[0].Key != "WEB-1" || !syncTimes["acct-cached"].Equal(cachedAt) {
+		t.Errorf("cached account = %v synced %v, want the cached WEB-1", got, syncTimes["acct-cached"])
+	}
+	if got := issues["acct-new"]; len(got) != 1 || got[0].Key != "WEB-2" {
+		t.Errorf("new account = %v, want WEB-2 fetched live", got)
+	}
+	if _, ok := cache.SyncTimes["acct-new"]; !ok {
+		t.Error("live fetch wasn't cached")
+	}
+	if _, ok := issues["acct-broken"]; ok {
+		t.Error("failed account was included")
+	}
+
+	// A refresh fetches everything live and drops issues no longer assigned
+	issues, _, err = service.Load(context.Background(), source.connect, []string{"acct-cached"}, true, 2)
+	if err != nil {
+		t.Fatalf("Load(refresh) error = %v", err)
+	}
+	if got := issues["acct-cached"]; len(got) != 1 || got[0].Key != "WEB-9" {
+		t.Errorf("refreshed account = %v, want WEB-9", got)
+	}
+	if _, ok := cache.Issues["WEB-1"]; ok {
+		t.Error("refresh kept a stale issue in the cache")
+	}
+}
+
+func TestJiraIssueSyncService_Load_NilService(t *testing.T) {
+	source := &fakeJiraIssueSource{
+		issues:  map[string][]models.JiraIssue{"acct-1": {openJiraIssue("WEB-1", "acct-1", time.Now())}},
+		fetched: map[string]int{},
+	}
+
+	var service *JiraIssueSyncService
+	issues, syncTimes, err := service.Load(context.Background(), source.connect, []string{"acct-1"}, false, 1)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(issues["acct-1"]) != 1 || len(syncTimes) != 0 {
+		t.Errorf("issues = %v, sync times = %v, want a live fetch with no sync time", issues, syncTimes)
+	}
+}
+
+func TestJiraIssueSyncService_SyncStale(t *testing.T) {
+	now := time.Now()
+	accountID := func(id string) *string { return &id }
+	users := mocks.NewMockUserRepository()
+	users.Users[1] = &models.User{ID: 1, IsActive: true, JiraAccountID: accountID("acct-stale")}
+	users.Users[2] = &models.User{ID: 2, IsActive: true, JiraAccountID: accountID("acct-fresh")}
+	users.Users[3] = &models.User{ID: 3, IsActive: false, JiraAccountID: accountID("acct-inactive")}
+	users.Users[4] = &models.User{ID: 4, IsActive: true}
+
+	cache := mocks.NewMockJiraIssueCacheRepository()
+	cache.SyncTimes["acct-stale"] = now.Add(-time.Hour)
+	cache.SyncTimes["acct-fresh"] = now.Add(-time.Minute)
+
+	source := &fakeJiraIssueSource{
+		issues: map[string][]models.JiraIssue{
+			"acct-stale":    {openJiraIssue("WEB-1", "acct-stale", now)},
+			"acct-fresh":    {},
+			"acct-inactive": {},
+		},
+		fetched: map[string]int{},
+	}
+	service := NewJiraIssueSyncService(cache, users, 15*time.Minute)
+
+	if synced := service.SyncStale(context.Background(), source.connect); synced != 1 {
+		t.Errorf("SyncStale() = %d, want 1", synced)
+	}
+	if source.fetched["acct-stale"] != 1 || source.fetched["acct-fresh"] != 0 || source.fetched["acct-inactive"] != 0 {
+		t.Errorf("fetched = %v, want only the stale account", source.fetched)
+	}
+	if !cache.SyncTimes["acct-stale"].After(now.Add(-time.Minute)) {
+		t.Error("stale account's sync time wasn't updated")
+	}
+}
+
+func TestJiraIssueSyncService_SyncStale_NotConnected(t *testing.T) {
+	users := mocks.NewMockUserRepository()
+	id := "acct-1"
+	users.Users[1] = &models.User{ID: 1, IsActive: true, JiraAccountID: &id}
+	service := NewJiraIssueSyncService(mocks.NewMockJiraIssueCacheRepository(), users, 15*time.Minute)
+
+	notConnected := func(ctx context.Context) (JiraIssueSource, error) {
+		return nil, errors.New("jira is not configured for this organization")
+	}
+	if synced := service.SyncStale(context.Background(), notConnected); synced != 0 {
+		t.Errorf("SyncStale() = %d, want 0", synced)
+	}
+}
+
+func TestJiraIssueSyncService_SyncStale_PerOrganization(t *testing.T) {
+	accountID := func(id string) *string { return &id }
+	users := mocks.NewMockUserRepository()
+	users.Users[1] = &models.User{ID: 1, OrgID: 1, IsActive: true, JiraAccountID: accountID("acct-acme")}
+	users.Users[2] = &models.User{ID: 2, OrgID: 2, IsActive: true, JiraAccountID: accountID("acct-globex")}
+
+	sources := map[int64]*fakeJiraIssueSource{
+		1: {issues: map[string][]models.JiraIssue{"acct-acme": {}}, fetched: map[string]int{}},
+		2: {issues: map[string][]models.JiraIssue{"acct-globex": {}}, fetched: map[string]int{}},
+	}
+	connected := map[int64]int{}
+	connect := func(ctx context.Context) (JiraIssueSource, error) {
+		orgID, ok := tenant.OrgID(ctx)
+		if !ok {
+			return nil, errors.New("not scoped to an organization")
+		}
+		connected[orgID]++
+		return sources[orgID], nil
+	}
+	service := NewJiraIssueSyncService(mocks.NewMockJiraIssueCacheRepository(), users, 15*time.Minute)
+
+	if synced := service.SyncStale(tenant.AcrossOrgs(context.Background()), connect); synced != 2 {
+		t.Errorf("SyncStale() = %d, want 2", synced)
+	}
+	if connected[1] != 1 || connected[2] != 1 {
+		t.Errorf("connections = %v, want one per organization", connected)
+	}
+	if sources[1].fetched["acct-acme"] != 1 || sources[1].fetched["acct-globex"] != 0 {
+		t.Errorf("organization 1 fetched %v, want only its own account", sources[1].fetched)
+	}
+	if sources[2].fetched["acct-globex"] != 1 || sources[2].fetched["acct-acme"] != 0 {
+		t.Errorf("organization 2 fetched %v, want only its own account", sources[2].fetched)
+	}
+}
+
+func TestFetchConcurrently_BoundsWorkers(t *testing.T) {
+	accountIDs := make([]string, 50)
+	for i := range accountIDs {
+		accountIDs[i] = fmt.Sprintf("acct-%d", i)
+	}
+
+	var mu sync.Mutex
+	active, peak := 0, 0
+	fetched := make(map[string]bool)
+	fetchConcurrently(accountIDs, 3, func(accountID string) {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		fetched[accountID] = true
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+	})
+
+	if len(fetched) != len(accountIDs) {
+		t.Errorf("fetched %d accounts, want %d", len(fetched), len(accountIDs))
+	}
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", peak)
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+const (
+	// maxJiraMatchSuggestions caps how many likely Jira accounts are suggested per employee
+	maxJiraMatchSuggestions = 3
+	// minJiraMatchScore is the lowest name similarity worth suggesting
+	minJiraMatchScore = 0.6
+)
+
+// BuildJiraUserMatchReport lists the active employees without a Jira account, each with the
+// unmatched Jira accounts whose names most resemble theirs, and the Jira accounts no employee
+// is mapped to. employees must reflect any matches just made.
+func BuildJiraUserMatchReport(employees []models.User, jiraUsers []models.JiraUser, matched int, now time.Time) *models.JiraUserMatchReport {
+	report := &models.JiraUserMatchReport{
+		GeneratedAt:    now,
+		Matched:        matched,
+		TotalEmployees: len(employees),
+		TotalJiraUsers: len(jiraUsers),
+	}
+	fillUnmatched(report, employees, jiraUsers)
+	return report
+}
+
+// RefreshJiraUserMatchReport drops employees and Jira accounts that have been mapped since the
+// report was generated, without rescanning Jira. Suggestions are recomputed from what's left.
+func RefreshJiraUserMatchReport(report *models.JiraUserMatchReport, employees []models.User) *models.JiraUserMatchReport {
+	stillUnmatched := make(map[int64]bool, len(report.UnmatchedEmployees))
+	for _, emp := range report.UnmatchedEmployees {
+		stillUnmatched[emp.ID] = true
+	}
+	// Employees added since the report ran weren't compared with Jira, so only earlier ones are kept
+	var candidates []models.User
+	for _, emp := range employees {
+		if stillUnmatched[emp.ID] || (emp.JiraAccountID != nil && *emp.JiraAccountID != "") {
+			candidates = append(candidates, emp)
+		}
+	}
+
+	refreshed := *report
+	fillUnmatched(&refreshed, candidates, report.UnmatchedJiraUsers)
+	return &refreshed
+}
+
+// fillUnmatched sets the report's unmatched employees and Jira accounts
+func fillUnmatched(report *models.JiraUserMatchReport, employees []models.User, jiraUsers []models.JiraUser) {
+	mapped := make(map[string]bool)
+	for _, emp := range employees {
+		if emp.JiraAccountID != nil && *emp.JiraAccountID != "" {
+			mapped[*emp.JiraAccountID] = true
+		}
+	}
+
+	report.UnmatchedJiraUsers = []models.JiraUser{}
+	for _, ju := range jiraUsers {
+		if !mapped[ju.AccountID] {
+			report.UnmatchedJiraUsers = append(report.UnmatchedJiraUsers, ju)
+		}
+	}
+
+	report.UnmatchedEmployees = []models.JiraUnmatchedEmployee{}
+	for _, emp := range employees {
+		if !emp.IsActive || (emp.JiraAccountID != nil && *emp.JiraAccountID != "") {
+			continue
+		}
+		report.UnmatchedEmployees = append(report.UnmatchedEmployees, models.JiraUnmatchedEmployee{
+			ID:          emp.ID,
+			FirstName:   emp.FirstName,
+			LastName:    emp.LastName,
+			Email:       emp.Email,
+			Suggestions: suggestJiraUsers(emp, report.UnmatchedJiraUsers),
+		})
+	}
+}
+
+// suggestJiraUsers returns the Jira accounts most likely to belong to an employee, best first
+func suggestJiraUsers(emp models.User, jiraUsers []models.JiraUser) []models.JiraUserMatchSuggestion {
+	name := normalizeName(emp.FirstName + " " + emp.LastName)
+	localPart, _, _ := strings.Cut(strings.ToLower(emp.Email), "@")
+
+	suggestions := []models.JiraUserMatchSuggestion{}
+	for _, ju := range jiraUsers {
+		score := nameSimilarity(name, normalizeName(ju.DisplayName))
+		// The same mailbox name at another domain, e.g. after a company rename
+		if jiraLocal, _, ok := strings.Cut(strings.ToLower(ju.Email), "@"); ok && localPart != "" {
+			score = max(score, similarity(localPart, jiraLocal))
+		}
+		if score >= minJiraMatchScore {
+			suggestions = append(suggestions, models.JiraUserMatchSuggestion{JiraUser: ju, Score: roundScore(score)})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	return suggestions[:min(len(suggestions), maxJiraMatchSuggestions)]
+}
+
+// normalizeName lowercases a name and reduces it to words of letters and digits
+func normalizeName(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// nameSimilarity compares two names in either word order, so "Lovelace, Ada" matches "Ada Lovelace"
+func nameSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	inOrder := similarity(strings.Join(a, " "), strings.Join(b, " "))
+
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	return max(inOrder, similarity(strings.Join(sortedA, " "), strings.Join(sortedB, " ")))
+}
+
+// similarity returns 1 minus the edit distance between a and b relative to the longer of them
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the number of single-rune edits that turn a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// roundScore rounds a score to two decimal places for display
+func roundScore(score float64) float64 {
+	return float64(int(score*100+0.5)) / 100
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestBuildJiraUserMatchReport(t *testing.T) {
+	mapped := "acct-ada"
+	employees := []models.User{
+		{ID: 1, FirstName: "Ada", LastName: "Lovelace", Email: "ada@acme.com", IsActive: true, JiraAccountID: &mapped},
+		{ID: 2, FirstName: "Grace", LastName: "Hopper", Email: "grace@acme.com", IsActive: true},
+		{ID: 3, FirstName: "Alan", LastName: "Turing", Email: "alan@acme.com", IsActive: false},
+		{ID: 4, FirstName: "Edsger", LastName: "Dijkstra", Email: "ewd@acme.com", IsActive: true},
+	}
+	jiraUsers := []models.JiraUser{
+		{AccountID: "acct-ada", DisplayName: "Ada Lovelace"},
+		{AccountID: "acct-grace", DisplayName: "Hopper, Grace"},
+		{AccountID: "acct-gracie", DisplayName: "Gracie Hooper"},
+		{AccountID: "acct-ewd", DisplayName: "E. W. D.", Email: "ewd@old-acme.com"},
+		{AccountID: "acct-bot", DisplayName: "Automation for Jira"},
+	}
+
+	report := BuildJiraUserMatchReport(employees, jiraUsers, 1, time.Now())
+
+	if report.Matched != 1 || report.TotalEmployees != 4 || report.TotalJiraUsers != 5 {
+		t.Errorf("counts = %d/%d/%d, want 1/4/5", report.Matched, report.TotalEmployees, report.TotalJiraUsers)
+	}
+	if len(report.UnmatchedJiraUsers) != 4 {
+		t.Errorf("UnmatchedJiraUsers = %d, want 4 (all but the mapped account)", len(report.UnmatchedJiraUsers))
+	}
+	if len(report.UnmatchedEmployees) != 2 {
+		t.Fatalf("UnmatchedEmployees = %+v, want Grace and Edsger", report.UnmatchedEmployees)
+	}
+
+	grace := report.UnmatchedEmployees[0]
+	if grace.ID != 2 || len(grace.Suggestions) != 2 {
+		t.Fatalf("Grace = %+v, want two suggestions", grace)
+	}
+	if grace.Suggestions[0].AccountID != "acct-grace" || grace.Suggestions[0].Score != 1 {
+		t.Errorf("best suggestion = %+v, want acct-grace with score 1", grace.Suggestions[0])
+	}
+
+	edsger := report.UnmatchedEmployees[1]
+	if len(edsger.Suggestions) != 1 || edsger.Suggestions[0].AccountID != "acct-ewd" {
+		t.Errorf("Edsger suggestions = %+v, want acct-ewd by email", edsger.Suggestions)
+	}
+}
+
+func TestRefreshJiraUserMatchReport(t *testing.T) {
+	employees := []models.User{
+		{ID: 2, FirstName: "Grace", LastName: "Hopper", IsActive: true},
+		{ID: 4, FirstName: "Edsger", LastName: "Dijkstra", IsActive: true},
+	}
+	jiraUsers := []models.JiraUser{
+		{AccountID: "acct-grace", DisplayName: "Grace Hopper"},
+		{AccountID: "acct-gracie", DisplayName: "Gracie Hopper"},
+	}
+	report := BuildJiraUserMatchReport(employees, jiraUsers, 0, time.Now())
+
+	// Grace is mapped by hand and a new hire joins after the report ran
+	graceAccount := "acct-grace"
+	employees[0].JiraAccountID = &graceAccount
+	employees = append(employees, models.User{ID: 9, FirstName: "New", LastName: "Hire", IsActive: true})
+
+	refreshed := RefreshJiraUserMatchReport(report, employees)
+
+	if len(refreshed.UnmatchedEmployees) != 1 || refreshed.UnmatchedEmployees[0].ID != 4 {
+		t.Errorf("UnmatchedEmployees = %+v, want only Edsger", refreshed.UnmatchedEmployees)
+	}
+	if len(refreshed.UnmatchedJiraUsers) != 1 || refreshed.UnmatchedJiraUsers[0].AccountID != "acct-gracie" {
+		t.Errorf("UnmatchedJiraUsers = %+v, want only acct-gracie", refreshed.UnmatchedJiraUsers)
+	}
+	if len(report.UnmatchedEmployees) != 2 {
+		t.Error("refresh modified the stored report")
+	}
+}
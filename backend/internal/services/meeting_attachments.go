@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/storage"
+)
+
+// ErrAttachmentStorageNotConfigured is returned when no storage backend is available for meeting files
+var ErrAttachmentStorageNotConfigured = fmt.Errorf("file storage is not configured")
+
+// ErrAttachmentUploadFailed is returned when storing a meeting file fails
+var ErrAttachmentUploadFailed = fmt.Errorf("failed to upload file, please try again later")
+
+// transcriptCueTiming matches WebVTT/SRT cue timing lines such as "00:00:01.000 --> 00:00:04.000"
+var transcriptCueTiming = regexp.MustCompile(`^\d{1,2}:\d{2}(:\d{2})?[.,]\d{3}\s+-->`)
+
+// MeetingAttachmentService stores meeting recordings and transcripts. Uploaded files are only
+// reachable through short-lived signed URLs, handed out once access to the meeting is checked.
+type MeetingAttachmentService struct {
+	storage  storage.SignedURLStorage
+	tenantID string
+	urlTTL   time.Duration
+	logger   *logger.Logger
+}
+
+// NewMeetingAttachmentService creates a new meeting attachment service storing files under the
+// given tenant's prefix, whose download URLs stay valid for urlTTL. Uploads are unavailable
+// unless the storage backend can sign download URLs.
+func NewMeetingAttachmentService(store storage.Storage, tenantID string, urlTTL time.Duration) *MeetingAttachmentService {
+	signed, _ := store.(storage.SignedURLStorage)
+	return &MeetingAttachmentService{
+		storage:  signed,
+		tenantID: tenantID,
+		urlTTL:   urlTTL,
+		logger:   logger.Default().WithComponent("meeting-attachment-service"),
+	}
+}
+
+// Upload stores a meeting file under the uploader's prefix and returns its storage key. Sign the
+// key with SignURLs to give someone access to the file.
+func (s *MeetingAttachmentService) Upload(ctx context.Context, meetingID, uploaderID int64, fileName, contentType string, data []byte) (string, error) {
+	if s.storage == nil {
+		s.logger.LogError(ctx, "Storage not configured", ErrAttachmentStorageNotConfigured, "meeting_id", meetingID)
+		return "", ErrAttachmentStorageNotConfigured
+	}
+
+	key := storage.GenerateMeetingAttachmentKey(s.tenantID, uploaderID, meetingID, strings.ToLower(filepath.Ext(fileName)))
+	if _, err := s.storage.Upload(ctx, key, data, contentType); err != nil {
+		s.logger.LogError(ctx, "Meeting attachment upload failed", err, "meeting_id", meetingID)
+		return "", ErrAttachmentUploadFailed
+	}
+	return key, nil
+}
+
+// SignURLs sets the URL of each uploaded attachment to a freshly signed one that expires after the
+// service's TTL. Linked attachments keep their URL.
+func (s *MeetingAttachmentService) SignURLs(ctx context.Context, attachments ...*models.MeetingAttachment) error {
+	for _, attachment := range attachments {
+		if !attachment.IsUploaded() {
+			continue
+		}
+		if s.storage == nil {
+			return ErrAttachmentStorageNotConfigured
+		}
+		url, err := s.storage.SignedURL(ctx, *attachment.StorageKey, s.urlTTL)
+		if err != nil {
+			return fmt.Errorf("failed to sign meeting attachment URL: %w", err)
+		}
+		attachment.URL = url
+	}
+	return nil
+}
+
+// Delete removes a previously uploaded meeting file
+func (s *MeetingAttachmentService) Delete(ctx context.Context, key string) error {
+	if s.storage == nil {
+		return ErrAttachmentStorageNotConfigured
+	}
+	return s.storage.Delete(ctx, key)
+}
+
+// IsTranscriptFile reports whether an uploaded file holds plain-text transcript content
+func IsTranscriptFile(fileName, contentType string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".txt", ".vtt", ".srt":
+		return true
+	}
+	contentType = strings.ToLower(contentType)
+	return strings.HasPrefix(contentType, "text/plain") ||
+		strings.HasPrefix(contentType, "text/vtt") ||
+		strings.HasPrefix(contentType, "application/x-subrip")
+}
+
+// IsRecordingFile reports whether an uploaded file is an audio or video recording
+func IsRecordingFile(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	return strings.HasPrefix(contentType, "audio/") || strings.HasPrefix(contentType, "video/")
+}
+
+// ExtractTranscriptText strips WebVTT/SRT headers, cue numbers, and timings, leaving the spoken text
+func ExtractTranscriptText(data []byte) string {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "WEBVTT" || transcriptCueTiming.MatchString(line) || isCueNumber(line) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// isCueNumber reports whether a line is an SRT cue sequence number
+func isCueNumber(line string) bool {
+	for _, r := range line {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestMeetingAttachmentService_Upload(t *testing.T) {
+	t.Run("returns error when storage not configured", func(t *testing.T) {
+		svc := NewMeetingAttachmentService(nil, "acme", time.Minute)
+		_, err := svc.Upload(context.Background(), 1, 2, "notes.txt", "text/plain", []byte("hi"))
+		if !errors.Is(err, ErrAttachmentStorageNotConfigured) {
+			t.Errorf("expected ErrAttachmentStorageNotConfigured, got %v", err)
+		}
+	})
+
+	t.Run("returns error when storage can't sign download links", func(t *testing.T) {
+		svc := NewMeetingAttachmentService(&MockStorage{}, "acme", time.Minute)
+		_, err := svc.Upload(context.Background(), 1, 2, "notes.txt", "text/plain", []byte("hi"))
+		if !errors.Is(err, ErrAttachmentStorageNotConfigured) {
+			t.Errorf("expected ErrAttachmentStorageNotConfigured, got %v", err)
+		}
+	})
+
+	t.Run("stores file under the uploader and meeting prefix", func(t *testing.T) {
+		store := newMockSignedStorage()
+		svc := NewMeetingAttachmentService(store, "acme", time.Minute)
+
+		key, err := svc.Upload(context.Background(), 7, 3, "Standup.VTT", "text/vtt", []byte("WEBVTT"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := store.Files[key]; !ok || !strings.HasPrefix(key, "tenants/acme/users/3/meetings/7/") || !strings.HasSuffix(key, ".vtt") {
+			t.Errorf("unexpected key %q", key)
+		}
+	})
+
+	t.Run("hides storage errors", func(t *testing.T) {
+		store := newMockSignedStorage()
+		store.UploadFunc = func(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+			return "", errors.New("connection reset")
+		}
+		svc := NewMeetingAttachmentService(store, "acme", time.Minute)
+
+		_, err := svc.Upload(context.Background(), 1, 2, "a.mp4", "video/mp4", []byte{0})
+		if !errors.Is(err, ErrAttachmentUploadFailed) {
+			t.Errorf("expected ErrAttachmentUploadFailed, got %v", err)
+		}
+	})
+}
+
+func TestMeetingAttachmentService_SignURLs(t *testing.T) {
+	key := "tenants/acme/users/3/meetings/7/call.mp4"
+	uploaded := &models.MeetingAttachment{StorageKey: &key, URL: "https://example.com/" + key}
+	linked := &models.MeetingAttachment{URL: "https://zoom.example.com/rec/abc"}
+
+	svc := NewMeetingAttachmentService(newMockSignedStorage(), "acme", 5*time.Minute)
+	if err := svc.SignURLs(context.Background(), uploaded, linked); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uploaded.URL != "https://example.com/"+key+"?signature=abc&expires=5m0s" {
+		t.Errorf("uploaded attachment URL = %q, want a signed link", uploaded.URL)
+	}
+	if linked.URL != "https://zoom.example.com/rec/abc" {
+		t.Errorf("linked attachment URL = %q, want it unchanged", linked.URL)
+	}
+
+	unsigned := NewMeetingAttachmentService(nil, "acme", time.Minute)
+	if err := unsigned.SignURLs(context.Background(), linked); err != nil {
+		t.Errorf("linked attachments need no storage, got %v", err)
+	}
+	if err := unsigned.SignURLs(context.Background(), uploaded); !errors.Is(err, ErrAttachmentStorageNotConfigured) {
+		t.Errorf("expected ErrAttachmentStorageNotConfigured, got %v", err)
+	}
+}
+
+func TestIsTranscriptFile(t *testing.T) {
+	tests := []struct {
+		fileName    string
+		contentType string
+		expected    bool
+	}{
+		{"notes.txt", "application/octet-stream", true},
+		{"call.vtt", "", true},
+		{"call.SRT", "", true},
+		{"notes", "text/plain; charset=utf-8", true},
+		{"call.mp4", "video/mp4", false},
+		{"slides.pdf", "application/pdf", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsTranscriptFile(tt.fileName, tt.contentType); got != tt.expected {
+			t.Errorf("IsTranscriptFile(%q, %q) = %v, want %v", tt.fileName, tt.contentType, got, tt.expected)
+		}
+	}
+}
+
+func TestExtractTranscriptText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "plain text is kept",
+			input:    "First line\nSecond line",
+			expected: "First line\nSecond line",
+		},
+		{
+			name:     "webvtt header and timings are stripped",
+			input:    "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\nHello team\n\n00:00:03.000 --> 00:00:04.000\nLet's start",
+			expected: "Hello team\nLet's start",
+		},
+		{
+			name:     "srt cue numbers and timings are stripped",
+			input:    "1\r\n00:00:01,000 --> 00:00:02,000\r\nBudget is approved\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nShip it",
+			expected: "Budget is approved\nShip it",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractTranscriptText([]byte(tt.input)); got != tt.expected {
+				t.Errorf("ExtractTranscriptText() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/ical"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// icsUIDDomain qualifies the UIDs of exported meetings so external calendars can tell them apart
+const icsUIDDomain = "tava-team-dashboard"
+
+// ErrInvalidICS is returned when an uploaded file is not a readable iCalendar document
+var ErrInvalidICS = fmt.Errorf("file is not a valid iCalendar (.ics) document")
+
+// ErrTooManyICSEvents is returned when an uploaded file holds more events than one import allows
+var ErrTooManyICSEvents = fmt.Errorf("file contains more than %d events", models.MaxICSImportEvents)
+
+// MeetingICSService exports meetings to and imports meetings from iCalendar files
+type MeetingICSService struct {
+	meetingRepo repository.MeetingRepository
+	userRepo    repository.UserRepository
+	logger      *logger.Logger
+}
+
+// NewMeetingICSService creates a new meeting iCalendar service
+func NewMeetingICSService(meetingRepo repository.MeetingRepository, userRepo repository.UserRepository) *MeetingICSService {
+	return &MeetingICSService{
+		meetingRepo: meetingRepo,
+		userRepo:    userRepo,
+		logger:      logger.Default().WithComponent("meeting-ics-service"),
+	}
+}
+
+// Export returns the meetings the user organizes or attends within a date range as an .ics document.
+// Recurring series are written once with an RRULE rather than expanded.
+func (s *MeetingICSService) Export(ctx context.Context, user *models.User, start, end time.Time) ([]byte, error) {
+	meetings, err := s.meetingRepo.GetByDateRange(ctx, user.ID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meetings: %w", err)
+	}
+
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	return ical.Encode(meetings, name+" - Meetings", icsUIDDomain), nil
+}
+
+// Import creates meetings organized by the user from the events in an .ics document.
+// Invitees are added as attendees when their email matches a user. Events the user
+// already imported, cancelled events, and recurrence rules that meetings can't
+// represent are skipped and reported in the result.
+func (s *MeetingICSService) Import(ctx context.Context, user *models.User, data []byte) (*models.ICSImportResult, error) {
+	events, err := ical.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidICS, err)
+	}
+	if len(events) > models.MaxICSImportEvents {
+		return nil, ErrTooManyICSEvents
+	}
+
+	result := &models.ICSImportResult{
+		Imported:           []models.Meeting{},
+		Skipped:            []models.ICSImportSkipped{},
+		UnmatchedAttendees: []string{},
+	}
+	attendeeIDs := make(map[string]int64) // Cache of email lookups; 0 means no matching user
+	unmatched := make(map[string]bool)
+
+	for i := range events {
+		event := &events[i]
+		skip := func(reason string) {
+			result.Skipped = append(result.Skipped, models.ICSImportSkipped{UID: event.UID, Summary: event.Summary, Reason: reason})
+		}
+
+		if event.Status == "CANCELLED" {
+			skip("event is cancelled")
+			continue
+		}
+		if event.RecurrenceID != nil {
+			skip("changes to a single occurrence of a recurring event are not imported")
+			continue
+		}
+
+		req, err := event.MeetingRequest()
+		if err != nil {
+			skip(err.Error())
+			continue
+		}
+
+		if req.ICalUID != nil {
+			exists, err := s.meetingRepo.HasICalUID(ctx, user.ID, *req.ICalUID)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				skip("already imported")
+				continue
+			}
+		}
+
+		added := make(map[int64]bool)
+		for _, email := range event.AttendeeEmails {
+			if email == strings.ToLower(user.Email) {
+				continue
+			}
+			id, seen := attendeeIDs[email]
+			if !seen {
+				if attendee, err := s.userRepo.GetByEmail(ctx, email); err == nil && attendee != nil {
+					id = attendee.ID
+				}
+				attendeeIDs[email] = id
+			}
+			if id == 0 {
+				unmatched[email] = true
+				continue
+			}
+			if !added[id] {
+				added[id] = true
+				req.AttendeeIDs = append(req.AttendeeIDs, id)
+			}
+		}
+
+		if err := req.Validate(); err != nil {
+			skip(err.Error())
+			continue
+		}
+
+		meeting, err := s.meetingRepo.Create(ctx, req, user.ID)
+		if err != nil {
+			s.logger.LogError(ctx, "Failed to create imported meeting", err, "user_id", user.ID, "uid", event.UID)
+			skip("failed to save meeting")
+			continue
+		}
+		result.Imported = append(result.Imported, *meeting)
+	}
+
+	for email := range unmatched {
+		result.UnmatchedAttendees = append(result.UnmatchedAttendees, email)
+	}
+	sort.Strings(result.UnmatchedAttendees)
+
+	s.logger.Info("Imported meetings from iCalendar file", "user_id", user.ID,
+		"imported", len(result.Imported), "skipped", len(result.Skipped))
+	return result, nil
+}
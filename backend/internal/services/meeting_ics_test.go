@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+const importCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+UID:planning@example.com
+SUMMARY:Quarterly planning
+DTSTART:20260302T160000Z
+DTEND:20260302T170000Z
+ATTENDEE:mailto:ada@example.com
+ATTENDEE:mailto:alan@example.com
+ATTENDEE:mailto:ALAN@example.com
+ATTENDEE:mailto:vendor@partner.com
+END:VEVENT
+BEGIN:VEVENT
+UID:birthday@example.com
+SUMMARY:Birthday
+DTSTART;VALUE=DATE:20260305
+RRULE:FREQ=YEARLY
+END:VEVENT
+BEGIN:VEVENT
+UID:dropped@example.com
+SUMMARY:Dropped
+STATUS:CANCELLED
+DTSTART:20260306T160000Z
+DTEND:20260306T170000Z
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestMeetingICSService_Import(t *testing.T) {
+	meetingRepo := mocks.NewMockMeetingRepository()
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, Email: "ada@example.com"})
+	userRepo.AddUser(&models.User{ID: 2, Email: "alan@example.com"})
+	service := NewMeetingICSService(meetingRepo, userRepo)
+	importer := &models.User{ID: 1, Email: "Ada@example.com"}
+
+	result, err := service.Import(context.Background(), importer, []byte(importCalendar))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if len(result.Imported) != 1 {
+		t.Fatalf("Imported %d meetings, want 1", len(result.Imported))
+	}
+	meeting := result.Imported[0]
+	if meeting.Title != "Quarterly planning" || meeting.CreatedByID != 1 {
+		t.Errorf("imported meeting = %+v", meeting)
+	}
+	if attendees := meetingRepo.Attendees[meeting.ID]; len(attendees) != 1 || attendees[0].UserID != 2 {
+		t.Errorf("attendees = %+v, want only user 2", attendees)
+	}
+	if len(result.UnmatchedAttendees) != 1 || result.UnmatchedAttendees[0] != "vendor@partner.com" {
+		t.Errorf("UnmatchedAttendees = %v", result.UnmatchedAttendees)
+	}
+
+	reasons := make(map[string]string)
+	for _, skipped := range result.Skipped {
+		reasons[skipped.UID] = skipped.Reason
+	}
+	if !strings.Contains(reasons["birthday@example.com"], "frequency") {
+		t.Errorf("yearly event skip reason = %q", reasons["birthday@example.com"])
+	}
+	if reasons["dropped@example.com"] != "event is cancelled" {
+		t.Errorf("cancelled event skip reason = %q", reasons["dropped@example.com"])
+	}
+
+	// Importing the same file again creates nothing new
+	again, err := service.Import(context.Background(), importer, []byte(importCalendar))
+	if err != nil {
+		t.Fatalf("second Import() error = %v", err)
+	}
+	if len(again.Imported) != 0 {
+		t.Errorf("re-import created %d meetings, want 0", len(again.Imported))
+	}
+}
+
+func TestMeetingICSService_Import_Invalid(t *testing.T) {
+	service := NewMeetingICSService(mocks.NewMockMeetingRepository(), mocks.NewMockUserRepository())
+
+	_, err := service.Import(context.Background(), &models.User{ID: 1}, []byte("not a calendar"))
+	if !errors.Is(err, ErrInvalidICS) {
+		t.Errorf("expected ErrInvalidICS, got %v", err)
+	}
+}
+
+func TestMeetingICSService_Export(t *testing.T) {
+	meetingRepo := mocks.NewMockMeetingRepository()
+	start := time.Date(2026, 3, 2, 16, 0, 0, 0, time.UTC)
+	meetingRepo.AddMeeting(&models.Meeting{ID: 1, Title: "Mine", StartTime: start, EndTime: start.Add(time.Hour), CreatedByID: 1})
+	meetingRepo.AddMeeting(&models.Meeting{ID: 2, Title: "Someone else's", StartTime: start, EndTime: start.Add(time.Hour), CreatedByID: 2})
+	service := NewMeetingICSService(meetingRepo, mocks.NewMockUserRepository())
+
+	data, err := service.Export(context.Background(), &models.User{ID: 1, FirstName: "Ada", LastName: "Lovelace"}, start.AddDate(0, 0, -1), start.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	ics := string(data)
+	if !strings.Contains(ics, "SUMMARY:Mine") || strings.Contains(ics, "Someone else") {
+		t.Errorf("unexpected export:\n%s", ics)
+	}
+	if !strings.Contains(ics, "X-WR-CALNAME:Ada Lovelace - Meetings") {
+		t.Errorf("missing calendar name:\n%s", ics)
+	}
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// maxNotificationTitleLength matches the notifications.title column
+const maxNotificationTitleLength = 255
+
+// NotificationService records in-app notifications and queues the matching emails in the outbox.
+// All methods are safe to call on a nil service.
+type NotificationService struct {
+	repo     repository.NotificationRepository
+	userRepo repository.UserRepository
+	outbox   repository.OutboxRepository
+	logger   *logger.Logger
+}
+
+// NewNotificationService creates a new notification service; outbox is nil when email is disabled
+func NewNotificationService(repo repository.NotificationRepository, userRepo repository.UserRepository, outbox repository.OutboxRepository) *NotificationService {
+	return &NotificationService{
+		repo:     repo,
+		userRepo: userRepo,
+		outbox:   outbox,
+		logger:   logger.Default().WithComponent("notifications"),
+	}
+}
+
+// NotifyMeetingResponse tells the organizer that an attendee responded, including the running tally.
+// The meeting's attendees must already reflect the new response. Resetting a response to pending
+// isn't announced. The in-app notification is stored and the email queued before returning; the
+// outbox worker sends the email so it never delays the response.
+func (s *NotificationService) NotifyMeetingResponse(ctx context.Context, meeting *models.Meeting, responder *models.User, response models.ResponseStatus) {
+	if s == nil || meeting.CreatedByID == responder.ID || response == models.ResponseStatusPending {
+		return
+	}
+
+	title, body := meetingResponseMessage(meeting, responder, response)
+	s.record(ctx, meeting.CreatedByID, models.NotificationTypeMeetingResponse, responder.ID, "meeting", meeting.ID, title, body)
+
+	if s.outbox == nil {
+		return
+	}
+	organizer, err := s.userRepo.GetByID(ctx, meeting.CreatedByID)
+	if err != nil || organizer == nil || !organizer.IsActive {
+		return
+	}
+
+	tally := meeting.ResponseTally()
+	err = s.outbox.Enqueue(ctx, models.OutboxKindMeetingResponseEmail, models.MeetingResponseEmailPayload{
+		MeetingID: meeting.ID,
+		To:        organizer.Email,
+		Subject:   title,
+		Text:      body,
+		OrgID:     organizer.OrgID,
+		Language:  organizer.Language,
+		Variables: map[string]string{
+			"ResponderName": responder.FirstName + " " + responder.LastName,
+			"Response":      string(response),
+			"MeetingTitle":  meeting.Title,
+			"StartTime":     meeting.StartTime.UTC().Format("Mon, Jan 2 at 15:04 MST"),
+			"Accepted":      strconv.Itoa(tally.Accepted),
+			"Declined":      strconv.Itoa(tally.Declined),
+			"Tentative":     strconv.Itoa(tally.Tentative),
+			"Pending":       strconv.Itoa(tally.Pending),
+		},
+	})
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to queue meeting response email", err, "meeting_id", meeting.ID)
+	}
+}
+
+// meetingResponseMessage builds the title and body shared by the in-app notification and email
+func meetingResponseMessage(meeting *models.Meeting, responder *models.User, response models.ResponseStatus) (string, string) {
+	name := responder.FirstName + " " + responder.LastName
+	var verb string
+	switch response {
+	case models.ResponseStatusAccepted:
+		verb = "accepted"
+	case models.ResponseStatusDeclined:
+		verb = "declined"
+	case models.ResponseStatusTentative:
+		verb = "tentatively accepted"
+	default:
+		verb = "responded to"
+	}
+
+	tally := meeting.ResponseTally()
+	title := truncateNotificationTitle(fmt.Sprintf("%s %s %s", name, verb, meeting.Title))
+	body := fmt.Sprintf("%s %s your meeting \"%s\" on %s.\n\nResponses so far: %d accepted, %d declined, %d tentative, %d awaiting a response.",
+		name, verb, meeting.Title, meeting.StartTime.UTC().Format("Mon, Jan 2 at 15:04 MST"),
+		tally.Accepted, tally.Declined, tally.Tentative, tally.Pending)
+	return title, body
+}
+
+// NotifyKudos tells the recipient that someone recognized them. Kudos are only announced in-app.
+func (s *NotificationService) NotifyKudos(ctx context.Context, kudos *models.Kudos, giver *models.User) {
+	if s == nil {
+		return
+	}
+
+	name := giver.FirstName + " " + giver.LastName
+	title := fmt.Sprintf("%s gave you kudos", name)
+	if kudos.CompanyValue != nil {
+		title = fmt.Sprintf("%s gave you kudos for %s", name, *kudos.CompanyValue)
+	}
+	s.record(ctx, kudos.ToUserID, models.NotificationTypeKudos, giver.ID, "kudos", kudos.ID, title, kudos.Message)
+}
+
+// NotifyTimeOffReviewed tells the requester that their time off request was approved or rejected.
+// Reviewing your own request isn't announced.
+func (s *NotificationService) NotifyTimeOffReviewed(ctx context.Context, request *models.TimeOffRequest, reviewer *models.User) {
+	if s == nil || request.UserID == reviewer.ID {
+		return
+	}
+
+	kind := strings.ReplaceAll(string(request.RequestType), "_", " ")
+	title := fmt.Sprintf("%s %s %s your %s request", reviewer.FirstName, reviewer.LastName, request.Status, kind)
+	body := fmt.Sprintf("Your %s request for %s to %s was %s.", kind,
+		request.StartDate.Format("Jan 2, 2006"), request.EndDate.Format("Jan 2, 2006"), request.Status)
+	if request.ReviewerNotes != nil && *request.ReviewerNotes != "" {
+		body += "\n\n" + *request.ReviewerNotes
+	}
+	s.record(ctx, request.UserID, models.NotificationTypeTimeOffReviewed, reviewer.ID, "time_off_request", request.ID, title, body)
+}
+
+// NotifyMeetingInvite tells attendees that they were invited to a meeting. The organizer isn't
+// notified about their own meeting.
+func (s *NotificationService) NotifyMeetingInvite(ctx context.Context, meeting *models.Meeting, organizer *models.User, attendeeIDs []int64) {
+	if s == nil {
+		return
+	}
+
+	name := organizer.FirstName + " " + organizer.LastName
+	title := fmt.Sprintf("%s invited you to %s", name, meeting.Title)
+	body := fmt.Sprintf("%s invited you to \"%s\" on %s.", name, meeting.Title,
+		meeting.StartTime.UTC().Format("Mon, Jan 2 at 15:04 MST"))
+	for _, attendeeID := range attendeeIDs {
+		if attendeeID == organizer.ID {
+			continue
+		}
+		s.record(ctx, attendeeID, models.NotificationTypeMeetingInvite, organizer.ID, "meeting", meeting.ID, title, body)
+	}
+}
+
+// NotifyInvitationAccepted tells whoever sent an invitation that the invitee joined
+func (s *NotificationService) NotifyInvitationAccepted(ctx context.Context, invitation *models.Invitation, user *models.User) {
+	if s == nil {
+		return
+	}
+
+	name := user.FirstName + " " + user.LastName
+	title := fmt.Sprintf("%s accepted your invitation", name)
+	body := fmt.Sprintf("%s (%s) joined as %s.", name, user.Email, user.Role)
+	s.record(ctx, invitation.InvitedByID, models.NotificationTypeInvitationAccepted, user.ID, "user", user.ID, title, body)
+}
+
+// NotifyOrgChartPublished tells each person a published draft moved, and each supervisor who gained
+// a direct report. The publisher isn't notified about their own changes.
+func (s *NotificationService) NotifyOrgChartPublished(ctx context.Context, draft *models.OrgChartDraft, publisher *models.User) {
+	if s == nil {
+		return
+	}
+
+	name := publisher.FirstName + " " + publisher.LastName
+	for _, change := range draft.Changes {
+		if change.UserID != publisher.ID {
+			title := fmt.Sprintf("%s updated your place in the org chart", name)
+			body := fmt.Sprintf("Changes from \"%s\" were published. Your supervisor, department, role, or squads may have changed.", draft.Name)
+			s.record(ctx, change.UserID, models.NotificationTypeOrgChartChange, publisher.ID, "org_chart_draft", draft.ID, title, body)
+		}
+
+		gained := change.NewSupervisorID != nil &&
+			(change.OriginalSupervisorID == nil || *change.OriginalSupervisorID != *change.NewSupervisorID)
+		if gained && *change.NewSupervisorID != publisher.ID && *change.NewSupervisorID != change.UserID {
+			title := "You have a new direct report"
+			if change.User != nil {
+				title = fmt.Sprintf("%s %s now reports to you", change.User.FirstName, change.User.LastName)
+			}
+			body := fmt.Sprintf("%s published \"%s\", which changed who reports to you.", name, draft.Name)
+			s.record(ctx, *change.NewSupervisorID, models.NotificationTypeOrgChartChange, publisher.ID, "org_chart_draft", draft.ID, title, body)
+		}
+	}
+}
+
+// NotifyProfileChangeRequested tells the user's supervisor that they asked to change their title or
+// department. Users without a supervisor are reviewed by admins, who see the queue instead.
+func (s *NotificationService) NotifyProfileChangeRequested(ctx context.Context, request *models.ProfileChangeRequest, user *models.User) {
+	if s == nil || user.SupervisorID == nil {
+		return
+	}
+
+	name := user.FirstName + " " + user.LastName
+	title := fmt.Sprintf("%s asked to change their %s", name, profileChangeFields(request))
+	body := fmt.Sprintf("%s asked to change their %s. Approve or reject it from your team's profile changes.", name, describeProfileChange(request))
+	s.record(ctx, *user.SupervisorID, models.NotificationTypeProfileChangeRequested, user.ID, "profile_change_request", request.ID, title, body)
+}
+
+// NotifyProfileChangeReviewed tells the user that their title or department change was approved or
+// rejected
+func (s *NotificationService) NotifyProfileChangeReviewed(ctx context.Context, request *models.ProfileChangeRequest, reviewer *models.User) {
+	if s == nil || request.UserID == reviewer.ID {
+		return
+	}
+
+	title := fmt.Sprintf("%s %s %s your %s change", reviewer.FirstName, reviewer.LastName, request.Status, profileChangeFields(request))
+	body := fmt.Sprintf("Your request to change your %s was %s.", describeProfileChange(request), request.Status)
+	s.record(ctx, request.UserID, models.NotificationTypeProfileChangeReviewed, reviewer.ID, "profile_change_request", request.ID, title, body)
+}
+
+// NotifySupervisorChanged tells a user's previous and new supervisors that they were moved. Whoever
+// moved them isn't notified.
+func (s *NotificationService) NotifySupervisorChanged(ctx context.Context, user *models.User, previousSupervisorID *int64, actor *models.User) {
+	if s == nil {
+		return
+	}
+
+	name := user.FirstName + " " + user.LastName
+	actorName := actor.FirstName + " " + actor.LastName
+	if previousSupervisorID != nil && *previousSupervisorID != actor.ID {
+		title := fmt.Sprintf("%s no longer reports to you", name)
+		body := fmt.Sprintf("%s moved %s to another supervisor.", actorName, name)
+		if user.SupervisorID == nil {
+			body = fmt.Sprintf("%s removed %s's supervisor.", actorName, name)
+		}
+		s.record(ctx, *previousSupervisorID, models.NotificationTypeSupervisorChanged, actor.ID, "user", user.ID, title, body)
+	}
+	if user.SupervisorID != nil && *user.SupervisorID != actor.ID {
+		title := fmt.Sprintf("%s now reports to you", name)
+		body := fmt.Sprintf("%s made you %s's supervisor.", actorName, name)
+		s.record(ctx, *user.SupervisorID, models.NotificationTypeSupervisorChanged, actor.ID, "user", user.ID, title, body)
+	}
+}
+
+// profileChangeFields names the fields a profile change request changes, e.g. "title and department"
+func profileChangeFields(request *models.ProfileChangeRequest) string {
+	switch {
+	case request.Title != nil && request.Department != nil:
+		return "title and department"
+	case request.Department != nil:
+		return "department"
+	default:
+		return "title"
+	}
+}
+
+// describeProfileChange describes what a profile change request changes, e.g. `title to "Staff Engineer"`
+func describeProfileChange(request *models.ProfileChangeRequest) string {
+	var changes []string
+	if request.Title != nil {
+		changes = append(changes, fmt.Sprintf("title to %q", *request.Title))
+	}
+	if request.Department != nil {
+		changes = append(changes, fmt.Sprintf("department to %q", *request.Department))
+	}
+	return strings.Join(changes, " and ")
+}
+
+// record stores an in-app notification. Failures are logged rather than returned so they never
+// fail the action being announced.
+func (s *NotificationService) record(ctx context.Context, userID int64, notificationType models.NotificationType, actorID int64, entityType string, entityID int64, title, body string) {
+	_, err := s.repo.Create(ctx, &models.Notification{
+		UserID:     userID,
+		Type:       notificationType,
+		ActorID:    &actorID,
+		EntityType: &entityType,
+		EntityID:   &entityID,
+		Title:      truncateNotificationTitle(title),
+		Body:       body,
+	})
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to record notification", err, "type", notificationType, "user_id", userID)
+	}
+}
+
+// truncateNotificationTitle shortens a title to fit the notifications.title column
+func truncateNotificationTitle(title string) string {
+	if runes := []rune(title); len(runes) > maxNotificationTitleLength {
+		return string(runes[:maxNotificationTitleLength-1]) + "…"
+	}
+	return title
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestNotificationService_NotifyMeetingResponse(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.AddUser(&models.User{ID: 1, OrgID: 2, Email: "organizer@example.com", Language: "es", IsActive: true})
+	notificationRepo := mocks.NewMockNotificationRepository()
+	outbox := mocks.NewMockOutboxRepository()
+	service := NewNotificationService(notificationRepo, userRepo, outbox)
+
+	meeting := &models.Meeting{
+		ID:          7,
+		Title:       "Design sync",
+		StartTime:   time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC),
+		CreatedByID: 1,
+		Attendees: []models.MeetingAttendee{
+			{UserID: 2, ResponseStatus: models.ResponseStatusTentative},
+			{UserID: 3, ResponseStatus: models.ResponseStatusPending},
+		},
+	}
+	responder := &models.User{ID: 2, FirstName: "Grace", LastName: "Hopper"}
+
+	service.NotifyMeetingResponse(context.Background(), meeting, responder, models.ResponseStatusTentative)
+
+	queued, ok := outbox.Messages[1]
+	if !ok || queued.Kind != models.OutboxKindMeetingResponseEmail {
+		t.Fatalf("queued messages = %+v, want a meeting response email", outbox.Messages)
+	}
+	var email models.MeetingResponseEmailPayload
+	if err := json.Unmarshal(queued.Payload, &email); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if email.To != "organizer@example.com" || email.Subject != "Grace Hopper tentatively accepted Design sync" {
+		t.Errorf("email = %+v", email)
+	}
+	if email.OrgID != 2 || email.Language != "es" || email.Variables["Response"] != "tentative" || email.Variables["Pending"] != "1" {
+		t.Errorf("email = %+v, want it rendered in the organizer's organization and language", email)
+	}
+	if len(notificationRepo.Notifications) != 1 || !strings.Contains(notificationRepo.Notifications[0].Body, "0 accepted, 0 declined, 1 tentative, 1 awaiting") {
+		t.Errorf("notifications = %+v", notificationRepo.Notifications)
+	}
+
+	// Organizers responding to their own meeting, and resets to pending, aren't announced
+	service.NotifyMeetingResponse(context.Background(), meeting, &models.User{ID: 1}, models.ResponseStatusAccepted)
+	service.NotifyMeetingResponse(context.Background(), meeting, responder, models.ResponseStatusPending)
+	if len(notificationRepo.Notifications) != 1 {
+		t.Errorf("recorded %d notifications, want 1", len(notificationRepo.Notifications))
+	}
+
+	var nilService *NotificationService
+	nilService.NotifyMeetingResponse(context.Background(), meeting, responder, models.ResponseStatusAccepted)
+}
+
+func TestNotificationService_Producers(t *testing.T) {
+	ada := &models.User{ID: 1, FirstName: "Ada", LastName: "Lovelace"}
+	grace := &models.User{ID: 2, FirstName: "Grace", LastName: "Hopper", Email: "grace@example.com", Role: models.RoleEmployee}
+	notes := "Enjoy the trip"
+
+	tests := []struct {
+		name   string
+		notify func(s *NotificationService)
+		want   []models.Notification
+	}{
+		{
+			name: "time off reviewed",
+			notify: func(s *NotificationService) {
+				s.NotifyTimeOffReviewed(context.Background(), &models.TimeOffRequest{
+					ID: 5, UserID: 2, RequestType: models.TimeOffTypeJuryDuty, Status: models.TimeOffStatusApproved,
+					StartDate: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC),
+					ReviewerNotes: &notes,
+				}, ada)
+				// Reviewing your own request isn't announced
+				s.NotifyTimeOffReviewed(context.Background(), &models.TimeOffRequest{ID: 6, UserID: 1}, ada)
+			},
+			want: []models.Notification{{UserID: 2, Type: models.NotificationTypeTimeOffReviewed, Title: "Ada Lovelace approved your jury duty request"}},
+		},
+		{
+			name: "meeting invite skips the organizer",
+			notify: func(s *NotificationService) {
+				s.NotifyMeetingInvite(context.Background(), &models.Meeting{ID: 7, Title: "Design sync"}, ada, []int64{1, 2, 3})
+			},
+			want: []models.Notification{
+				{UserID: 2, Type: models.NotificationTypeMeetingInvite, Title: "Ada Lovelace invited you to Design sync"},
+				{UserID: 3, Type: models.NotificationTypeMeetingInvite, Title: "Ada Lovelace invited you to Design sync"},
+			},
+		},
+		{
+			name: "invitation accepted",
+			notify: func(s *NotificationService) {
+				s.NotifyInvitationAccepted(context.Background(), &models.Invitation{ID: 9, InvitedByID: 1}, grace)
+			},
+			want: []models.Notification{{UserID: 1, Type: models.NotificationTypeInvitationAccepted, Title: "Grace Hopper accepted your invitation"}},
+		},
+		{
+			name: "org chart published",
+			notify: func(s *NotificationService) {
+				oldSupervisor, newSupervisor, publisher := int64(4), int64(3), int64(1)
+				s.NotifyOrgChartPublished(context.Background(), &models.OrgChartDraft{ID: 11, Name: "Q3 reorg", Changes: []models.DraftChange{
+					{UserID: 2, User: grace, OriginalSupervisorID: &oldSupervisor, NewSupervisorID: &newSupervisor},
+					// The publisher's own move and their new report aren't announced to them
+					{UserID: 1, NewSupervisorID: &newSupervisor},
+					{UserID: 5, NewSupervisorID: &publisher},
+				}}, ada)
+			},
+			want: []models.Notification{
+				{UserID: 2, Type: models.NotificationTypeOrgChartChange, Title: "Ada Lovelace updated your place in the org chart"},
+				{UserID: 3, Type: models.NotificationTypeOrgChartChange, Title: "Grace Hopper now reports to you"},
+				{UserID: 3, Type: models.NotificationTypeOrgChartChange, Title: "You have a new direct report"},
+				{UserID: 5, Type: models.NotificationTypeOrgChartChange, Title: "Ada Lovelace updated your place in the org chart"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notificationRepo := mocks.NewMockNotificationRepository()
+			tt.notify(NewNotificationService(notificationRepo, mocks.NewMockUserRepository(), nil))
+
+			got := notificationRepo.Notifications
+			if len(got) != len(tt.want) {
+				t.Fatalf("notifications = %+v, want %d", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got[i].UserID != want.UserID || got[i].Type != want.Type || got[i].Title != want.Title {
+					t.Errorf("notification %d = {%d %s %q}, want {%d %s %q}", i, got[i].UserID, got[i].Type, got[i].Title, want.UserID, want.Type, want.Title)
+				}
+				if got[i].ActorID == nil || *got[i].ActorID == got[i].UserID {
+					t.Errorf("notification %d actor = %v, want someone other than the recipient", i, got[i].ActorID)
+				}
+			}
+		})
+	}
+
+	var nilService *NotificationService
+	nilService.NotifyTimeOffReviewed(context.Background(), &models.TimeOffRequest{UserID: 2}, ada)
+	nilService.NotifyOrgChartPublished(context.Background(), &models.OrgChartDraft{}, ada)
+}
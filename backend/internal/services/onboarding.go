@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// OnboardingService turns the onboarding templates matching a new user into tasks for the new hire,
+// their supervisor, and IT. All methods are safe to call on a nil service.
+type OnboardingService struct {
+	repo         repository.OnboardingRepository
+	itDepartment string
+	logger       *logger.Logger
+}
+
+// NewOnboardingService creates a new onboarding service. IT items become tasks assigned to itDepartment.
+func NewOnboardingService(repo repository.OnboardingRepository, itDepartment string) *OnboardingService {
+	return &OnboardingService{
+		repo:         repo,
+		itDepartment: itDepartment,
+		logger:       logger.Default().WithComponent("onboarding"),
+	}
+}
+
+// Start creates the user's onboarding tasks from every matching template of their organization,
+// due relative to their start date. Guests aren't onboarded, and supervisor items are skipped for
+// users without a supervisor. Failures are logged rather than returned so they never block
+// creating the account.
+func (s *OnboardingService) Start(ctx context.Context, user *models.User) {
+	if s == nil || user.IsGuest() {
+		return
+	}
+	ctx = tenant.WithOrgID(ctx, user.OrgID)
+
+	templates, err := s.repo.ListTemplates(ctx)
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to load onboarding templates", err, "user_id", user.ID)
+		return
+	}
+	reqs := s.taskRequests(templates, user)
+	if len(reqs) == 0 {
+		return
+	}
+
+	createdByID := user.ID
+	if user.SupervisorID != nil {
+		createdByID = *user.SupervisorID
+	}
+	tasks, err := s.repo.CreateTasks(ctx, user.ID, createdByID, reqs)
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to create onboarding tasks", err, "user_id", user.ID)
+		return
+	}
+	s.logger.Info("Onboarding started", "user_id", user.ID, "tasks", len(tasks))
+}
+
+// taskRequests builds the tasks for each item of the templates matching the user, in order
+func (s *OnboardingService) taskRequests(templates []models.OnboardingTemplate, user *models.User) []models.CreateTaskRequest {
+	start := time.Now().UTC()
+	if user.DateStarted != nil {
+		start = user.DateStarted.UTC()
+	}
+	// Task dates are UTC days
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+	var reqs []models.CreateTaskRequest
+	for _, t := range templates {
+		if !t.Matches(user) {
+			continue
+		}
+		for _, item := range t.Items {
+			req := models.CreateTaskRequest{
+				Title:          item.Title,
+				Description:    item.Description,
+				DueDate:        start.AddDate(0, 0, item.DueDays),
+				Priority:       models.TaskPriorityMedium,
+				Labels:         []string{models.OnboardingTaskLabel},
+				AssignmentType: models.AssignmentTypeUser,
+			}
+			switch item.Assignee {
+			case models.OnboardingAssigneeNewHire:
+				req.AssignedUserID = &user.ID
+			case models.OnboardingAssigneeSupervisor:
+				if user.SupervisorID == nil {
+					continue
+				}
+				req.AssignedUserID = user.SupervisorID
+			case models.OnboardingAssigneeIT:
+				req.AssignmentType = models.AssignmentTypeDepartment
+				req.AssignedDepartment = &s.itDepartment
+			}
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+// Progress returns the user's onboarding tasks and how many are done
+func (s *OnboardingService) Progress(ctx context.Context, userID int64) (*models.OnboardingProgress, error) {
+	tasks, err := s.repo.GetTasks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewOnboardingProgress(userID, tasks), nil
+}
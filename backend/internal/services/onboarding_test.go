@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+func TestOnboardingService_Start(t *testing.T) {
+	engineering := "engineering"
+	employee := models.RoleEmployee
+	supervisor := models.RoleSupervisor
+	repo := mocks.NewMockOnboardingRepository()
+	repo.Templates[1] = &models.OnboardingTemplate{ID: 1, Name: "Everyone", Items: []models.OnboardingTemplateItem{
+		{Title: "Sign the handbook", Assignee: models.OnboardingAssigneeNewHire, DueDays: 2},
+		{Title: "Order a laptop", Assignee: models.OnboardingAssigneeIT},
+	}}
+	repo.Templates[2] = &models.OnboardingTemplate{ID: 2, Name: "Engineers", Department: &engineering, Role: &employee, Items: []models.OnboardingTemplateItem{
+		{Title: "Pair on a first PR", Assignee: models.OnboardingAssigneeSupervisor, DueDays: 7},
+	}}
+	repo.Templates[3] = &models.OnboardingTemplate{ID: 3, Name: "New managers", Role: &supervisor, Items: []models.OnboardingTemplateItem{
+		{Title: "Management training", Assignee: models.OnboardingAssigneeNewHire},
+	}}
+	service := NewOnboardingService(repo, "IT")
+
+	supervisorID := int64(2)
+	started := time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC)
+	user := &models.User{ID: 5, Role: models.RoleEmployee, Department: "Engineering", SupervisorID: &supervisorID, DateStarted: &started}
+	service.Start(context.Background(), user)
+
+	tasks := repo.Tasks[5]
+	if len(tasks) != 3 {
+		t.Fatalf("Start() created %d tasks, want 3: %+v", len(tasks), tasks)
+	}
+	if *tasks[0].AssignedUserID != 5 || !tasks[0].DueDate.Equal(time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("new hire task = %+v, want assigned to the new hire two days after they start", tasks[0])
+	}
+	if tasks[1].AssignmentType != models.AssignmentTypeDepartment || *tasks[1].AssignedDepartment != "IT" {
+		t.Errorf("IT task = %+v, want assigned to the IT department", tasks[1])
+	}
+	if *tasks[2].AssignedUserID != supervisorID || tasks[2].CreatedByID != supervisorID {
+		t.Errorf("supervisor task = %+v, want assigned to and created by the supervisor", tasks[2])
+	}
+
+	// Users are only onboarded once
+	service.Start(context.Background(), user)
+	if len(repo.Tasks[5]) != 3 {
+		t.Errorf("second Start() changed tasks to %d", len(repo.Tasks[5]))
+	}
+
+	// Supervisor items are skipped without a supervisor, and guests aren't onboarded
+	service.Start(context.Background(), &models.User{ID: 6, Role: models.RoleEmployee, Department: "Engineering"})
+	if len(repo.Tasks[6]) != 2 {
+		t.Errorf("Start() without a supervisor created %d tasks, want 2", len(repo.Tasks[6]))
+	}
+	service.Start(context.Background(), &models.User{ID: 7, Role: models.RoleGuest})
+	if len(repo.Tasks[7]) != 0 {
+		t.Errorf("Start() for a guest created %d tasks, want 0", len(repo.Tasks[7]))
+	}
+
+	var nilService *OnboardingService
+	nilService.Start(context.Background(), user)
+}
+
+func TestOnboardingService_Start_UsesTheUsersOrganization(t *testing.T) {
+	repo := mocks.NewMockOnboardingRepository()
+	var listedIn int64
+	repo.ListTemplatesFunc = func(ctx context.Context) ([]models.OnboardingTemplate, error) {
+		listedIn, _ = tenant.OrgID(ctx)
+		return nil, nil
+	}
+	service := NewOnboardingService(repo, "IT")
+
+	// Templates come from the new user's organization, whoever's request created them
+	service.Start(tenant.WithOrgID(context.Background(), 1), &models.User{ID: 5, OrgID: 2, Role: models.RoleEmployee})
+
+	if listedIn != 2 {
+		t.Errorf("templates were listed in organization %d, want 2", listedIn)
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// orgTreeTotals accumulates member stats over a subtree
+type orgTreeTotals struct {
+	headcount        int
+	openTasks        int
+	outOfOffice      int
+	quarterTasks     int
+	quarterCompleted int
+}
+
+// LoadOrgTreeMetrics annotates every node in the trees with the requested aggregates, loading
+// member stats in a single batch when any aggregate needs them
+func LoadOrgTreeMetrics(ctx context.Context, repo repository.OrgChartRepository, trees []*models.OrgTreeNode, opts models.OrgTreeOptions, now time.Time) error {
+	if !opts.Any() {
+		return nil
+	}
+
+	stats := map[int64]models.OrgMemberStats{}
+	if opts.NeedsMemberStats() {
+		var userIDs []int64
+		for _, tree := range trees {
+			userIDs = appendOrgTreeUserIDs(userIDs, tree)
+		}
+		var err error
+		stats, err = repo.GetMemberStats(ctx, userIDs, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, tree := range trees {
+		annotateOrgTree(tree, stats, opts)
+	}
+	return nil
+}
+
+// annotateOrgTree sets Metrics on the node and all its descendants, rolling up member stats over each
+// subtree, and returns the node's subtree totals
+func annotateOrgTree(node *models.OrgTreeNode, stats map[int64]models.OrgMemberStats, opts models.OrgTreeOptions) orgTreeTotals {
+	own := stats[node.User.ID]
+	totals := orgTreeTotals{
+		headcount:        1,
+		openTasks:        own.OpenTasks,
+		quarterTasks:     own.QuarterTasks,
+		quarterCompleted: own.QuarterCompleted,
+	}
+	if own.OutOfOffice {
+		totals.outOfOffice = 1
+	}
+
+	for i := range node.Children {
+		child := annotateOrgTree(&node.Children[i], stats, opts)
+		totals.headcount += child.headcount
+		totals.openTasks += child.openTasks
+		totals.outOfOffice += child.outOfOffice
+		totals.quarterTasks += child.quarterTasks
+		totals.quarterCompleted += child.quarterCompleted
+	}
+
+	metrics := &models.OrgTreeMetrics{}
+	if opts.Headcount {
+		metrics.Headcount = &totals.headcount
+	}
+	if opts.OpenTasks {
+		metrics.OpenTasks = &totals.openTasks
+	}
+	if opts.OutOfOffice {
+		metrics.OutOfOffice = &totals.outOfOffice
+	}
+	if opts.GoalProgress && totals.quarterTasks > 0 {
+		progress := float64(totals.quarterCompleted) / float64(totals.quarterTasks)
+		metrics.GoalProgress = &progress
+	}
+	node.Metrics = metrics
+
+	return totals
+}
+
+// appendOrgTreeUserIDs appends the IDs of every user in the tree
+func appendOrgTreeUserIDs(userIDs []int64, node *models.OrgTreeNode) []int64 {
+	userIDs = append(userIDs, node.User.ID)
+	for i := range node.Children {
+		userIDs = appendOrgTreeUserIDs(userIDs, &node.Children[i])
+	}
+	return userIDs
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestAnnotateOrgTree(t *testing.T) {
+	// 1 manages 2 and 3; 2 manages 4
+	tree := &models.OrgTreeNode{
+		User: models.User{ID: 1},
+		Children: []models.OrgTreeNode{
+			{
+				User:     models.User{ID: 2},
+				Children: []models.OrgTreeNode{{User: models.User{ID: 4}}},
+			},
+			{User: models.User{ID: 3}},
+		},
+	}
+	stats := map[int64]models.OrgMemberStats{
+		1: {OpenTasks: 1, QuarterTasks: 2, QuarterCompleted: 1},
+		2: {OpenTasks: 2, OutOfOffice: true},
+		4: {OpenTasks: 3, OutOfOffice: true, QuarterTasks: 2, QuarterCompleted: 2},
+	}
+
+	annotateOrgTree(tree, stats, models.OrgTreeOptions{Headcount: true, OpenTasks: true, OutOfOffice: true, GoalProgress: true})
+
+	root := tree.Metrics
+	if *root.Headcount != 4 || *root.OpenTasks != 6 || *root.OutOfOffice != 2 || *root.GoalProgress != 0.75 {
+		t.Errorf("root metrics = headcount %d, open %d, ooo %d, progress %v",
+			*root.Headcount, *root.OpenTasks, *root.OutOfOffice, *root.GoalProgress)
+	}
+
+	squad := tree.Children[0].Metrics
+	if *squad.Headcount != 2 || *squad.OpenTasks != 5 || *squad.OutOfOffice != 2 || *squad.GoalProgress != 1 {
+		t.Errorf("squad metrics = headcount %d, open %d, ooo %d, progress %v",
+			*squad.Headcount, *squad.OpenTasks, *squad.OutOfOffice, *squad.GoalProgress)
+	}
+
+	// No tasks due this quarter leaves progress unset rather than zero
+	if leaf := tree.Children[1].Metrics; leaf.GoalProgress != nil || *leaf.Headcount != 1 {
+		t.Errorf("leaf metrics = %+v", leaf)
+	}
+}
+
+func TestAnnotateOrgTree_OnlyRequestedAggregates(t *testing.T) {
+	tree := &models.OrgTreeNode{User: models.User{ID: 1}, Children: []models.OrgTreeNode{{User: models.User{ID: 2}}}}
+
+	annotateOrgTree(tree, nil, models.OrgTreeOptions{Headcount: true})
+
+	m := tree.Metrics
+	if m.Headcount == nil || *m.Headcount != 2 {
+		t.Fatalf("headcount = %v, want 2", m.Headcount)
+	}
+	if m.OpenTasks != nil || m.OutOfOffice != nil || m.GoalProgress != nil {
+		t.Errorf("unrequested aggregates set: %+v", m)
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/cache"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// orgSettingsCacheTTL is how long an organization's settings are cached, and so how long a change
+// made on another instance can take to apply
+const orgSettingsCacheTTL = time.Minute
+
+// OrgSettingsService serves the settings admins can change for their organization, falling back
+// to the server's configured defaults for those they haven't. Settings are cached so reading them
+// doesn't query the database on every request.
+type OrgSettingsService struct {
+	repo     repository.OrganizationRepository
+	defaults map[models.OrgSettingKey]int
+	cache    *cache.Cache
+	logger   *logger.Logger
+}
+
+// NewOrgSettingsService creates a new organization settings service. defaults are the configured
+// values of every setting.
+func NewOrgSettingsService(repo repository.OrganizationRepository, defaults map[models.OrgSettingKey]int) *OrgSettingsService {
+	return &OrgSettingsService{
+		repo:     repo,
+		defaults: defaults,
+		cache:    cache.New(orgSettingsCacheTTL, time.Minute),
+		logger:   logger.Default().WithComponent("org-settings-service"),
+	}
+}
+
+// List returns every setting's value for the organization
+func (s *OrgSettingsService) List(ctx context.Context, orgID int64) ([]models.OrgSetting, error) {
+	overrides, err := s.overrides(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make([]models.OrgSetting, len(models.OrgSettingDefinitions))
+	for i, def := range models.OrgSettingDefinitions {
+		settings[i] = models.OrgSetting{OrgSettingDefinition: def, Default: s.defaults[def.Key]}
+		settings[i].Value, settings[i].Overridden = s.value(def.Key, overrides)
+	}
+	return settings, nil
+}
+
+// Update saves the request's changes to the organization's settings and returns them all
+func (s *OrgSettingsService) Update(ctx context.Context, orgID int64, req *models.UpdateOrgSettingsRequest, updatedByID int64) ([]models.OrgSetting, error) {
+	changes := make(map[models.OrgSettingKey]*string, len(req.Settings))
+	for key, value := range req.Settings {
+		if value == nil {
+			changes[key] = nil
+			continue
+		}
+		v := strconv.Itoa(*value)
+		changes[key] = &v
+	}
+
+	if err := s.repo.UpdateSettings(ctx, orgID, changes, updatedByID); err != nil {
+		return nil, err
+	}
+	s.cache.Delete(strconv.FormatInt(orgID, 10))
+	return s.List(ctx, orgID)
+}
+
+// Int returns a setting's value for the organization of ctx. If the settings can't be loaded the
+// default is used, so a database outage doesn't change how the server behaves.
+func (s *OrgSettingsService) Int(ctx context.Context, key models.OrgSettingKey) int {
+	orgID := tenant.OrgIDOrDefault(ctx)
+	overrides, err := s.overrides(ctx, orgID)
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to load org settings, using defaults", err, "org_id", orgID, "setting", key)
+		return s.defaults[key]
+	}
+	value, _ := s.value(key, overrides)
+	return value
+}
+
+// InvitationExpiryDays returns how many days invitations from the organization of ctx last
+func (s *OrgSettingsService) InvitationExpiryDays(ctx context.Context) int {
+	return s.Int(ctx, models.OrgSettingInvitationExpiryDays)
+}
+
+// AvatarMaxSizeMB returns the largest avatar the organization of ctx accepts, in MB
+func (s *OrgSettingsService) AvatarMaxSizeMB(ctx context.Context) int {
+	return s.Int(ctx, models.OrgSettingAvatarMaxSizeMB)
+}
+
+// MeetingAttachmentMaxSizeMB returns the largest meeting attachment the organization of ctx
+// accepts, in MB
+func (s *OrgSettingsService) MeetingAttachmentMaxSizeMB(ctx context.Context) int {
+	return s.Int(ctx, models.OrgSettingMeetingAttachmentMaxSizeMB)
+}
+
+// overrides returns the organization's saved settings, from the cache when it can
+func (s *OrgSettingsService) overrides(ctx context.Context, orgID int64) (map[models.OrgSettingKey]string, error) {
+	cached, err := s.cache.GetOrSet(strconv.FormatInt(orgID, 10), func() (interface{}, error) {
+		return s.repo.GetSettings(ctx, orgID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cached.(map[models.OrgSettingKey]string), nil
+}
+
+// value returns a setting's value from the organization's overrides, and whether it was
+// overridden. Saved values that no longer parse or fit the setting's range fall back to the
+// default.
+func (s *OrgSettingsService) value(key models.OrgSettingKey, overrides map[models.OrgSettingKey]string) (int, bool) {
+	raw, ok := overrides[key]
+	if !ok {
+		return s.defaults[key], false
+	}
+	value, err := strconv.Atoi(raw)
+	if def := models.OrgSettingDefinitionFor(key); err != nil || def == nil || value < def.Min || value > def.Max {
+		return s.defaults[key], false
+	}
+	return value, true
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+var testOrgSettingDefaults = map[models.OrgSettingKey]int{
+	models.OrgSettingInvitationExpiryDays:       7,
+	models.OrgSettingAvatarMaxSizeMB:            5,
+	models.OrgSettingMeetingAttachmentMaxSizeMB: 25,
+}
+
+func TestOrgSettingsService_Int(t *testing.T) {
+	orgRepo := mocks.NewMockOrganizationRepository()
+	orgRepo.Settings[2] = map[models.OrgSettingKey]string{
+		models.OrgSettingInvitationExpiryDays: "30",
+		models.OrgSettingAvatarMaxSizeMB:      "500",
+	}
+	s := NewOrgSettingsService(orgRepo, testOrgSettingDefaults)
+
+	ctx := tenant.WithOrgID(context.Background(), 2)
+	if got := s.InvitationExpiryDays(ctx); got != 30 {
+		t.Errorf("InvitationExpiryDays() = %d, want the override 30", got)
+	}
+	if got := s.AvatarMaxSizeMB(ctx); got != 5 {
+		t.Errorf("AvatarMaxSizeMB() = %d, want the default 5 for an out of range override", got)
+	}
+	if got := s.MeetingAttachmentMaxSizeMB(ctx); got != 25 {
+		t.Errorf("MeetingAttachmentMaxSizeMB() = %d, want the default 25", got)
+	}
+	if got := s.InvitationExpiryDays(context.Background()); got != 7 {
+		t.Errorf("InvitationExpiryDays() for the default org = %d, want 7", got)
+	}
+}
+
+func TestOrgSettingsService_Update(t *testing.T) {
+	orgRepo := mocks.NewMockOrganizationRepository()
+	s := NewOrgSettingsService(orgRepo, testOrgSettingDefaults)
+	ctx := context.Background()
+
+	if got := s.InvitationExpiryDays(ctx); got != 7 {
+		t.Fatalf("InvitationExpiryDays() = %d, want 7", got)
+	}
+
+	days := 14
+	settings, err := s.Update(ctx, 1, &models.UpdateOrgSettingsRequest{
+		Settings: map[models.OrgSettingKey]*int{models.OrgSettingInvitationExpiryDays: &days},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(settings) != len(models.OrgSettingDefinitions) {
+		t.Fatalf("Update() returned %d settings, want %d", len(settings), len(models.OrgSettingDefinitions))
+	}
+	for _, setting := range settings {
+		if setting.Key == models.OrgSettingInvitationExpiryDays && (setting.Value != 14 || !setting.Overridden || setting.Default != 7) {
+			t.Errorf("invitation expiry = %+v, want 14 overriding 7", setting)
+		}
+	}
+	if got := s.InvitationExpiryDays(ctx); got != 14 {
+		t.Errorf("InvitationExpiryDays() after update = %d, want 14", got)
+	}
+
+	if _, err := s.Update(ctx, 1, &models.UpdateOrgSettingsRequest{
+		Settings: map[models.OrgSettingKey]*int{models.OrgSettingInvitationExpiryDays: nil},
+	}, 1); err != nil {
+		t.Fatalf("Update() reset error = %v", err)
+	}
+	if got := s.InvitationExpiryDays(ctx); got != 7 {
+		t.Errorf("InvitationExpiryDays() after reset = %d, want 7", got)
+	}
+}
+
+func TestOrgSettingsService_FallsBackOnError(t *testing.T) {
+	orgRepo := mocks.NewMockOrganizationRepository()
+	orgRepo.GetSettingsFunc = func(ctx context.Context, orgID int64) (map[models.OrgSettingKey]string, error) {
+		return nil, errors.New("database unavailable")
+	}
+	s := NewOrgSettingsService(orgRepo, testOrgSettingDefaults)
+
+	if got := s.MeetingAttachmentMaxSizeMB(context.Background()); got != 25 {
+		t.Errorf("MeetingAttachmentMaxSizeMB() = %d, want the default 25", got)
+	}
+	if _, err := s.List(context.Background(), 1); err == nil {
+		t.Error("List() error = nil, want the repository error")
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// ExportOrgChart encodes the org trees in the given format and returns the file with its MIME type
+func ExportOrgChart(trees []models.OrgTreeNode, format models.OrgChartExportFormat) ([]byte, string, error) {
+	switch format {
+	case models.OrgChartExportCSV:
+		data, err := encodeCSV(orgChartCSVRows(FlattenOrgTrees(trees)))
+		return data, "text/csv; charset=utf-8", err
+	case models.OrgChartExportJSON:
+		data, err := json.Marshal(FlattenOrgTrees(trees))
+		return data, "application/json", err
+	case models.OrgChartExportDOT:
+		return encodeOrgChartDOT(trees), "text/vnd.graphviz; charset=utf-8", nil
+	default:
+		return nil, "", fmt.Errorf("format must be 'csv', 'json', or 'dot'")
+	}
+}
+
+// FlattenOrgTrees lists everyone in the trees depth-first, so each person follows their supervisor
+func FlattenOrgTrees(trees []models.OrgTreeNode) []models.OrgChartExportRow {
+	rows := []models.OrgChartExportRow{}
+	var walk func(node *models.OrgTreeNode, chain []string) int
+	walk = func(node *models.OrgTreeNode, chain []string) int {
+		u := node.User
+		squads := make([]string, len(u.Squads))
+		for i, squad := range u.Squads {
+			squads[i] = squad.Name
+		}
+		row := models.OrgChartExportRow{
+			ID:             u.ID,
+			FirstName:      u.FirstName,
+			LastName:       u.LastName,
+			Email:          u.Email,
+			Title:          u.Title,
+			Role:           u.Role,
+			Department:     u.Department,
+			Squads:         squads,
+			Level:          len(chain),
+			ReportingChain: append([]string{}, chain...),
+			DirectReports:  len(node.Children),
+		}
+		if len(chain) > 0 {
+			row.SupervisorID = u.SupervisorID
+			row.SupervisorName = chain[len(chain)-1]
+		}
+		index := len(rows)
+		rows = append(rows, row)
+
+		below := len(node.Children)
+		childChain := append(chain[:len(chain):len(chain)], orgChartName(u))
+		for i := range node.Children {
+			below += walk(&node.Children[i], childChain)
+		}
+		rows[index].TotalReports = below
+		return below
+	}
+	for i := range trees {
+		walk(&trees[i], nil)
+	}
+	return rows
+}
+
+// orgChartCSVRows lays out flattened org chart rows as CSV with a header
+func orgChartCSVRows(people []models.OrgChartExportRow) [][]string {
+	rows := [][]string{{"id", "first_name", "last_name", "email", "title", "role", "department", "squads",
+		"supervisor_id", "supervisor_name", "level", "reporting_chain", "direct_reports", "total_reports"}}
+	for _, p := range people {
+		rows = append(rows, []string{
+			strconv.FormatInt(p.ID, 10), p.FirstName, p.LastName, p.Email, p.Title, string(p.Role), p.Department,
+			strings.Join(p.Squads, "; "), formatOptionalID(p.SupervisorID), p.SupervisorName,
+			strconv.Itoa(p.Level), strings.Join(p.ReportingChain, " > "),
+			strconv.Itoa(p.DirectReports), strconv.Itoa(p.TotalReports),
+		})
+	}
+	return rows
+}
+
+// encodeOrgChartDOT writes the trees as a top-down Graphviz digraph, one box per person
+func encodeOrgChartDOT(trees []models.OrgTreeNode) []byte {
+	var b strings.Builder
+	b.WriteString("digraph org_chart {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+
+	var walk func(node *models.OrgTreeNode)
+	walk = func(node *models.OrgTreeNode) {
+		label := orgChartName(node.User)
+		if node.User.Title != "" {
+			label += "\n" + node.User.Title
+		}
+		fmt.Fprintf(&b, "  u%d [label=%s];\n", node.User.ID, dotQuote(label))
+		for i := range node.Children {
+			fmt.Fprintf(&b, "  u%d -> u%d;\n", node.User.ID, node.Children[i].User.ID)
+			walk(&node.Children[i])
+		}
+	}
+	for i := range trees {
+		walk(&trees[i])
+	}
+
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
+
+// dotQuote quotes a label for DOT, keeping line breaks as DOT's \n escape
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\r", "")
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// orgChartName returns a person's full name for exports
+func orgChartName(u models.User) string {
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func testOrgTrees() []models.OrgTreeNode {
+	id := func(v int64) *int64 { return &v }
+	users := []models.User{
+		{ID: 1, FirstName: "Ada", LastName: "Lovelace", Title: "CEO", Role: models.RoleAdmin},
+		{ID: 2, FirstName: "Grace", LastName: "Hopper", Title: `VP "Eng"`, Role: models.RoleSupervisor, SupervisorID: id(1), Squads: []models.Squad{{Name: "Core"}, {Name: "Infra"}}},
+		{ID: 3, FirstName: "Alan", LastName: "Turing", Role: models.RoleEmployee, SupervisorID: id(2), Department: "=SUM(A1)"},
+		{ID: 4, FirstName: "Edsger", LastName: "Dijkstra", Role: models.RoleEmployee, SupervisorID: id(1)},
+	}
+	return models.BuildOrgTrees(users, nil)
+}
+
+func TestFlattenOrgTrees(t *testing.T) {
+	rows := FlattenOrgTrees(testOrgTrees())
+
+	if len(rows) != 4 {
+		t.Fatalf("rows = %d, want 4", len(rows))
+	}
+	ada, grace, alan := rows[0], rows[1], rows[2]
+	if ada.Level != 0 || ada.TotalReports != 3 || ada.DirectReports != 2 || ada.SupervisorName != "" {
+		t.Errorf("Ada = %+v, want the top with 2 direct and 3 total reports", ada)
+	}
+	if grace.SupervisorName != "Ada Lovelace" || grace.TotalReports != 1 || len(grace.Squads) != 2 {
+		t.Errorf("Grace = %+v, want one report under Ada", grace)
+	}
+	if alan.Level != 2 || strings.Join(alan.ReportingChain, " > ") != "Ada Lovelace > Grace Hopper" {
+		t.Errorf("Alan = %+v, want level 2 under Ada and Grace", alan)
+	}
+	if rows[3].ID != 4 || len(rows[3].ReportingChain) != 1 {
+		t.Errorf("Edsger = %+v, want a direct report of Ada", rows[3])
+	}
+}
+
+func TestExportOrgChart(t *testing.T) {
+	trees := testOrgTrees()
+
+	data, contentType, err := ExportOrgChart(trees, models.OrgChartExportCSV)
+	if err != nil || !strings.HasPrefix(contentType, "text/csv") {
+		t.Fatalf("csv: %v, %s", err, contentType)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "id,first_name") {
+		t.Errorf("csv = %q, want a header and 4 rows", data)
+	}
+	if !strings.Contains(lines[2], "Core; Infra") || !strings.Contains(lines[3], "'=SUM(A1)") {
+		t.Errorf("csv rows = %q, want joined squads and neutralized formulas", lines[1:])
+	}
+
+	data, _, err = ExportOrgChart(trees, models.OrgChartExportJSON)
+	var rows []models.OrgChartExportRow
+	if err != nil || json.Unmarshal(data, &rows) != nil || len(rows) != 4 {
+		t.Errorf("json = %s (%v), want 4 rows", data, err)
+	}
+
+	data, contentType, err = ExportOrgChart(trees, models.OrgChartExportDOT)
+	if err != nil || !strings.HasPrefix(contentType, "text/vnd.graphviz") {
+		t.Fatalf("dot: %v, %s", err, contentType)
+	}
+	dot := string(data)
+	for _, want := range []string{"digraph org_chart {", `u2 [label="Grace Hopper\nVP \"Eng\""];`, "u1 -> u2;", "u2 -> u3;", "u1 -> u4;"} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("dot missing %q:\n%s", want, dot)
+		}
+	}
+
+	if _, _, err := ExportOrgChart(trees, "png"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// PreviewOrgChartDraft applies a draft's changes to the current org members and returns the
+// resulting tree, how each changed user would move, and the problems publishing would cause.
+// Squads are compared using the memberships recorded when each change was added.
+func PreviewOrgChartDraft(draftID int64, members []models.User, changes []models.DraftChange) *models.OrgChartDraftPreview {
+	after := models.ApplyDraftChanges(members, changes)
+	index := make(map[int64]int, len(members))
+	for i := range members {
+		index[members[i].ID] = i
+	}
+
+	preview := &models.OrgChartDraftPreview{
+		DraftID: draftID,
+		Diff:    []models.OrgChartUserDiff{},
+	}
+	pending := make(map[int64]*models.DraftChange, len(changes))
+	for i := range changes {
+		c := &changes[i]
+		idx, ok := index[c.UserID]
+		if !ok {
+			continue
+		}
+		if diff := diffDraftChange(&members[idx], &after[idx], c); len(diff.Changed) > 0 {
+			preview.Diff = append(preview.Diff, diff)
+			pending[c.UserID] = c
+		}
+	}
+
+	preview.Tree = models.BuildOrgTrees(after, pending)
+	preview.Problems = models.FindOrgChartProblems(members, after)
+	preview.Valid = len(preview.Problems) == 0
+	return preview
+}
+
+// diffDraftChange describes how a change moves a user
+func diffDraftChange(before, after *models.User, c *models.DraftChange) models.OrgChartUserDiff {
+	diff := models.OrgChartUserDiff{
+		UserID: before.ID,
+		Name:   strings.TrimSpace(before.FirstName + " " + before.LastName),
+		Before: models.OrgChartPlacement{SupervisorID: before.SupervisorID, Department: before.Department, Role: before.Role, SquadIDs: c.OriginalSquadIDs},
+		After:  models.OrgChartPlacement{SupervisorID: after.SupervisorID, Department: after.Department, Role: after.Role, SquadIDs: c.OriginalSquadIDs},
+	}
+	if c.NewSquadIDs != nil {
+		diff.After.SquadIDs = c.NewSquadIDs
+	}
+
+	diff.Changed = []string{}
+	if !sameInt64Ptr(diff.Before.SupervisorID, diff.After.SupervisorID) {
+		diff.Changed = append(diff.Changed, "supervisor")
+	}
+	if diff.Before.Department != diff.After.Department {
+		diff.Changed = append(diff.Changed, "department")
+	}
+	if diff.Before.Role != diff.After.Role {
+		diff.Changed = append(diff.Changed, "role")
+	}
+	if !sameSquads(diff.Before.SquadIDs, diff.After.SquadIDs) {
+		diff.Changed = append(diff.Changed, "squads")
+	}
+	return diff
+}
+
+// sameInt64Ptr reports whether two optional IDs are equal
+func sameInt64Ptr(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sameSquads reports whether two squad ID lists hold the same squads in any order
+func sameSquads(a, b []int64) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
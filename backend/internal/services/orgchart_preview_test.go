@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestPreviewOrgChartDraft(t *testing.T) {
+	id := func(v int64) *int64 { return &v }
+	dept := "Platform"
+	members := []models.User{
+		{ID: 1, FirstName: "Ada", LastName: "Lovelace", Role: models.RoleAdmin},
+		{ID: 2, FirstName: "Grace", LastName: "Hopper", Role: models.RoleSupervisor, SupervisorID: id(1)},
+		{ID: 3, FirstName: "Alan", LastName: "Turing", Role: models.RoleSupervisor, SupervisorID: id(1)},
+		{ID: 4, FirstName: "Edsger", LastName: "Dijkstra", Role: models.RoleEmployee, SupervisorID: id(3), Department: "Research"},
+	}
+	changes := []models.DraftChange{
+		{UserID: 4, NewSupervisorID: id(2), NewDepartment: &dept, OriginalSquadIDs: []int64{7}, NewSquadIDs: []int64{7}},
+		// Already the case, so there's nothing to show
+		{UserID: 2, NewSupervisorID: id(1)},
+	}
+
+	preview := PreviewOrgChartDraft(10, members, changes)
+
+	if !preview.Valid || len(preview.Problems) != 0 {
+		t.Errorf("Problems = %+v, want none", preview.Problems)
+	}
+	if len(preview.Diff) != 1 {
+		t.Fatalf("Diff = %+v, want one entry", preview.Diff)
+	}
+	diff := preview.Diff[0]
+	if diff.Name != "Edsger Dijkstra" || *diff.Before.SupervisorID != 3 || *diff.After.SupervisorID != 2 {
+		t.Errorf("diff = %+v, want Edsger moving from Alan to Grace", diff)
+	}
+	if len(diff.Changed) != 2 || diff.Changed[0] != "supervisor" || diff.Changed[1] != "department" {
+		t.Errorf("Changed = %v, want [supervisor department]", diff.Changed)
+	}
+
+	if len(preview.Tree) != 1 || len(preview.Tree[0].Children) != 2 {
+		t.Fatalf("Tree = %+v, want Ada with two reports", preview.Tree)
+	}
+	grace := preview.Tree[0].Children[0]
+	if grace.User.ID != 2 || len(grace.Children) != 1 || grace.Children[0].User.ID != 4 {
+		t.Fatalf("Grace's subtree = %+v, want Edsger under her", grace)
+	}
+	if grace.Children[0].PendingChange == nil || grace.PendingChange != nil {
+		t.Error("only Edsger's node should carry a pending change")
+	}
+}
+
+func TestPreviewOrgChartDraft_Cycle(t *testing.T) {
+	id := func(v int64) *int64 { return &v }
+	members := []models.User{
+		{ID: 1, FirstName: "Ada", Role: models.RoleAdmin},
+		{ID: 2, FirstName: "Grace", Role: models.RoleSupervisor, SupervisorID: id(1)},
+		{ID: 3, FirstName: "Alan", Role: models.RoleEmployee, SupervisorID: id(2)},
+	}
+
+	preview := PreviewOrgChartDraft(10, members, []models.DraftChange{{UserID: 2, NewSupervisorID: id(3)}})
+
+	if preview.Valid || len(preview.Problems) != 1 || preview.Problems[0].Kind != models.OrgChartProblemCycle {
+		t.Fatalf("Problems = %+v, want one cycle", preview.Problems)
+	}
+	if len(preview.Tree) != 1 || len(preview.Tree[0].Children) != 0 {
+		t.Errorf("Tree = %+v, want only Ada since Grace and Alan report to each other", preview.Tree)
+	}
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+const (
+	// outboxPollInterval is how often the worker checks for queued or retried messages
+	outboxPollInterval = 5 * time.Second
+	// outboxStaleAfter is how long a message may stay sending before another worker retries it
+	outboxStaleAfter = 5 * time.Minute
+	// outboxPurgeInterval is how often finished messages are removed
+	outboxPurgeInterval = time.Hour
+	// outboxRetention is how long finished messages are kept for troubleshooting
+	outboxRetention = 30 * 24 * time.Hour
+	// outboxRetryBase and outboxRetryMax bound the exponential backoff between attempts
+	outboxRetryBase = 30 * time.Second
+	outboxRetryMax  = time.Hour
+)
+
+// errOutboxPermanent marks failures that retrying can't fix
+var errOutboxPermanent = errors.New("permanent outbox failure")
+
+// OutboxEmailer sends the emails queued in the outbox
+type OutboxEmailer interface {
+	SendInvitation(ctx context.Context, email, token, role, inviterName, language string) error
+	SendNotification(ctx context.Context, to string, key models.EmailTemplateKey, language string, vars map[string]string) error
+	SendMeetingResponse(ctx context.Context, to, subject, text string) error
+	SendSupervisorDigest(ctx context.Context, to string, digest *models.SupervisorDigest) error
+}
+
+// OutboxSlackSender sends the Slack direct messages queued in the outbox
+type OutboxSlackSender interface {
+	SendMessage(ctx context.Context, payload *models.SlackMessagePayload) error
+}
+
+// outboxRetryDelay returns how long to wait after the given number of failed attempts
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxRetryMax {
+			return outboxRetryMax
+		}
+	}
+	return delay
+}
+
+// OutboxService carries out side effects, such as emails, that were queued in the outbox alongside
+// the change that caused them. Failures are retried with exponential backoff.
+type OutboxService struct {
+	repo    repository.OutboxRepository
+	emailer OutboxEmailer
+	slack   OutboxSlackSender
+	logger  *logger.Logger
+}
+
+// NewOutboxService creates a new outbox service
+func NewOutboxService(repo repository.OutboxRepository) *OutboxService {
+	return &OutboxService{
+		repo:   repo,
+		logger: logger.Default().WithComponent("outbox"),
+	}
+}
+
+// WithEmailer sets the emailer used for email messages. Without one, email messages fail.
+func (s *OutboxService) WithEmailer(emailer OutboxEmailer) *OutboxService {
+	s.emailer = emailer
+	return s
+}
+
+// WithSlack sets the sender used for Slack messages. Without one, Slack messages fail.
+func (s *OutboxService) WithSlack(sender OutboxSlackSender) *OutboxService {
+	s.slack = sender
+	return s
+}
+
+// Start runs the worker until the group is stopped. Messages are claimed from the database, so any
+// number of instances can run a worker.
+func (s *OutboxService) Start(workers *lifecycle.Group) {
+	workers.Go("outbox", func(ctx context.Context) {
+		poll := time.NewTicker(outboxPollInterval)
+		defer poll.Stop()
+		purge := time.NewTicker(outboxPurgeInterval)
+		defer purge.Stop()
+
+		for {
+			s.ProcessDue(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-poll.C:
+			case <-purge.C:
+				s.PurgeOld(ctx)
+			}
+		}
+	})
+}
+
+// ProcessDue carries out messages until none are due and returns how many were attempted
+func (s *OutboxService) ProcessDue(ctx context.Context) int {
+	processed := 0
+	for ctx.Err() == nil {
+		message, err := s.repo.ClaimNext(ctx, outboxStaleAfter)
+		if err != nil {
+			s.logger.LogError(ctx, "Failed to claim outbox message", err)
+			return processed
+		}
+		if message == nil {
+			return processed
+		}
+		s.deliver(ctx, message)
+		processed++
+	}
+	return processed
+}
+
+// deliver carries out a claimed message and records the outcome
+func (s *OutboxService) deliver(ctx context.Context, message *models.OutboxMessage) {
+	err := s.perform(ctx, message)
+	// Leave messages interrupted by shutdown sending so they are retried once stale
+	if ctx.Err() != nil {
+		return
+	}
+	if err == nil {
+		if err := s.repo.MarkSent(ctx, message.ID); err != nil {
+			s.logger.LogError(ctx, "Failed to record outbox message", err, "message_id", message.ID)
+		}
+		return
+	}
+
+	if errors.Is(err, errOutboxPermanent) || message.Attempts >= models.MaxOutboxAttempts {
+		s.logger.LogError(ctx, "Giving up on outbox message", err, "message_id", message.ID, "kind", message.Kind)
+		err = s.repo.MarkFailed(ctx, message.ID, err.Error())
+	} else {
+		err = s.repo.ScheduleRetry(ctx, message.ID, err.Error(), time.Now().Add(outboxRetryDelay(message.Attempts)))
+	}
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to record outbox message failure", err, "message_id", message.ID)
+	}
+}
+
+// perform carries out the side effect a message describes
+func (s *OutboxService) perform(ctx context.Context, message *models.OutboxMessage) error {
+	switch message.Kind {
+	case models.OutboxKindInvitationEmail:
+		var payload models.InvitationEmailPayload
+		if err := s.decodeEmail(message, &payload); err != nil {
+			return err
+		}
+		return s.emailer.SendInvitation(emailContext(ctx, payload.OrgID), payload.Email, payload.Token, string(payload.Role),
+			payload.InviterName, payload.Language)
+	case models.OutboxKindMeetingResponseEmail:
+		var payload models.MeetingResponseEmailPayload
+		if err := s.decodeEmail(message, &payload); err != nil {
+			return err
+		}
+		if payload.Variables != nil {
+			return s.emailer.SendNotification(emailContext(ctx, payload.OrgID), payload.To, models.EmailTemplateMeetingResponse,
+				payload.Language, payload.Variables)
+		}
+		return s.emailer.SendMeetingResponse(ctx, payload.To, payload.Subject, payload.Text)
+	case models.OutboxKindSupervisorDigest:
+		var payload models.SupervisorDigestEmailPayload
+		if err := s.decodeEmail(message, &payload); err != nil {
+			return err
+		}
+		return s.emailer.SendSupervisorDigest(ctx, payload.To, &payload.Digest)
+	case models.OutboxKindSlackMessage:
+		if s.slack == nil {
+			return fmt.Errorf("%w: slack is not configured", errOutboxPermanent)
+		}
+		var payload models.SlackMessagePayload
+		if err := decodeOutboxPayload(message, &payload); err != nil {
+			return err
+		}
+		return s.slack.SendMessage(ctx, &payload)
+	default:
+		return fmt.Errorf("%w: unknown kind %q", errOutboxPermanent, message.Kind)
+	}
+}
+
+// emailContext scopes ctx to the organization whose email templates a message is sent with. Messages
+// queued before templates could be customized have no organization and use the default one's.
+func emailContext(ctx context.Context, orgID int64) context.Context {
+	if orgID == 0 {
+		return ctx
+	}
+	return tenant.WithOrgID(ctx, orgID)
+}
+
+// decodeEmail reads an email message's payload, failing permanently if it can't be sent
+func (s *OutboxService) decodeEmail(message *models.OutboxMessage, payload any) error {
+	if s.emailer == nil {
+		return fmt.Errorf("%w: email is not configured", errOutboxPermanent)
+	}
+	return decodeOutboxPayload(message, payload)
+}
+
+// decodeOutboxPayload reads a message's payload, failing permanently if it's invalid
+func decodeOutboxPayload(message *models.OutboxMessage, payload any) error {
+	if err := json.Unmarshal(message.Payload, payload); err != nil {
+		return fmt.Errorf("%w: invalid payload: %v", errOutboxPermanent, err)
+	}
+	return nil
+}
+
+// PurgeOld removes finished messages past the retention period
+func (s *OutboxService) PurgeOld(ctx context.Context) {
+	if _, err := s.repo.PurgeFinished(ctx, time.Now().Add(-outboxRetention)); err != nil {
+		s.logger.LogError(ctx, "Failed to purge outbox messages", err)
+	}
+}
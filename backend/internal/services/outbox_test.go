@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// fakeOutboxEmailer records sent emails and fails while err is set
+type fakeOutboxEmailer struct {
+	sent []string
+	err  error
+	// Organization ctx was scoped to and language of each templated email
+	locales []string
+}
+
+func (f *fakeOutboxEmailer) SendInvitation(ctx context.Context, email, token, role, inviterName, language string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, "invitation:"+email+":"+role+":"+inviterName)
+	f.locales = append(f.locales, fmt.Sprintf("%d/%s", tenant.OrgIDOrDefault(ctx), language))
+	return nil
+}
+
+func (f *fakeOutboxEmailer) SendNotification(ctx context.Context, to string, key models.EmailTemplateKey, language string, vars map[string]string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, "notification:"+to+":"+string(key)+":"+vars["ResponderName"])
+	f.locales = append(f.locales, fmt.Sprintf("%d/%s", tenant.OrgIDOrDefault(ctx), language))
+	return nil
+}
+
+func (f *fakeOutboxEmailer) SendMeetingResponse(ctx context.Context, to, subject, text string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, "meeting:"+to+":"+subject)
+	return nil
+}
+
+func (f *fakeOutboxEmailer) SendSupervisorDigest(ctx context.Context, to string, digest *models.SupervisorDigest) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, "digest:"+to+":"+string(digest.Frequency))
+	return nil
+}
+
+func TestOutboxService_ProcessDue(t *testing.T) {
+	repo := mocks.NewMockOutboxRepository()
+	_ = repo.Enqueue(context.Background(), models.OutboxKindInvitationEmail, models.InvitationEmailPayload{
+		InvitationID: 3, Email: "new@example.com", Token: "abc", Role: models.RoleSupervisor, InviterName: "Ada Lovelace",
+	})
+	_ = repo.Enqueue(context.Background(), models.OutboxKindMeetingResponseEmail, models.MeetingResponseEmailPayload{
+		MeetingID: 7, To: "organizer@example.com", Subject: "Grace accepted Design sync",
+	})
+	emailer := &fakeOutboxEmailer{}
+	service := NewOutboxService(repo).WithEmailer(emailer)
+
+	if processed := service.ProcessDue(context.Background()); processed != 2 {
+		t.Errorf("processed %d messages, want 2", processed)
+	}
+	want := []string{
+		"invitation:new@example.com:supervisor:Ada Lovelace",
+		"meeting:organizer@example.com:Grace accepted Design sync",
+	}
+	if len(emailer.sent) != len(want) || emailer.sent[0] != want[0] || emailer.sent[1] != want[1] {
+		t.Errorf("sent = %v, want %v", emailer.sent, want)
+	}
+	for id, message := range repo.Messages {
+		if message.Status != models.OutboxMessageStatusSent || message.SentAt == nil {
+			t.Errorf("message %d status = %s, want sent", id, message.Status)
+		}
+	}
+}
+
+func TestOutboxService_ProcessDue_TemplatedEmails(t *testing.T) {
+	repo := mocks.NewMockOutboxRepository()
+	_ = repo.Enqueue(context.Background(), models.OutboxKindInvitationEmail, models.InvitationEmailPayload{
+		InvitationID: 3, Email: "new@example.com", Token: "abc", Role: models.RoleEmployee, InviterName: "Ada Lovelace",
+		OrgID: 2, Language: "es",
+	})
+	_ = repo.Enqueue(context.Background(), models.OutboxKindMeetingResponseEmail, models.MeetingResponseEmailPayload{
+		MeetingID: 7, To: "organizer@example.com", OrgID: 2, Language: "en",
+		Variables: map[string]string{"ResponderName": "Grace Hopper", "Response": "accepted"},
+	})
+	emailer := &fakeOutboxEmailer{}
+	service := NewOutboxService(repo).WithEmailer(emailer)
+
+	if processed := service.ProcessDue(context.Background()); processed != 2 {
+		t.Errorf("processed %d messages, want 2", processed)
+	}
+	if len(emailer.sent) != 2 || emailer.sent[1] != "notification:organizer@example.com:meeting_response:Grace Hopper" {
+		t.Errorf("sent = %v, want the meeting response rendered from its template", emailer.sent)
+	}
+	if len(emailer.locales) != 2 || emailer.locales[0] != "2/es" || emailer.locales[1] != "2/en" {
+		t.Errorf("locales = %v, want each email in its organization and language", emailer.locales)
+	}
+}
+
+func TestOutboxService_ProcessDue_Failures(t *testing.T) {
+	tests := []struct {
+		name          string
+		kind          models.OutboxKind
+		emailer       *fakeOutboxEmailer
+		priorAttempts int
+		wantStatus    models.OutboxMessageStatus
+	}{
+		{"send error is retried", models.OutboxKindMeetingResponseEmail, &fakeOutboxEmailer{err: errors.New("resend unavailable")}, 0, models.OutboxMessageStatusPending},
+		{"last attempt gives up", models.OutboxKindMeetingResponseEmail, &fakeOutboxEmailer{err: errors.New("resend unavailable")}, models.MaxOutboxAttempts - 1, models.OutboxMessageStatusFailed},
+		{"unknown kind gives up", models.OutboxKind("carrier_pigeon"), &fakeOutboxEmailer{}, 0, models.OutboxMessageStatusFailed},
+		{"email not configured gives up", models.OutboxKindInvitationEmail, nil, 0, models.OutboxMessageStatusFailed},
+		{"slack not configured gives up", models.OutboxKindSlackMessage, &fakeOutboxEmailer{}, 0, models.OutboxMessageStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockOutboxRepository()
+			_ = repo.Enqueue(context.Background(), tt.kind, map[string]string{"to": "someone@example.com"})
+			repo.Messages[1].Attempts = tt.priorAttempts
+			service := NewOutboxService(repo)
+			if tt.emailer != nil {
+				service.WithEmailer(tt.emailer)
+			}
+
+			service.ProcessDue(context.Background())
+
+			message := repo.Messages[1]
+			if message.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", message.Status, tt.wantStatus)
+			}
+			if message.LastError == nil {
+				t.Error("last error wasn't recorded")
+			}
+			if tt.wantStatus == models.OutboxMessageStatusPending && !message.NextAttemptAt.After(time.Now()) {
+				t.Error("retry wasn't scheduled in the future")
+			}
+		})
+	}
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{3, 2 * time.Minute},
+		{20, outboxRetryMax},
+	}
+	for _, tt := range tests {
+		if got := outboxRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("outboxRetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestOutboxService_PurgeOld(t *testing.T) {
+	repo := mocks.NewMockOutboxRepository()
+	repo.Messages[1] = &models.OutboxMessage{ID: 1, Status: models.OutboxMessageStatusSent, CreatedAt: time.Now().Add(-2 * outboxRetention)}
+	repo.Messages[2] = &models.OutboxMessage{ID: 2, Status: models.OutboxMessageStatusPending, CreatedAt: time.Now().Add(-2 * outboxRetention)}
+	repo.Messages[3] = &models.OutboxMessage{ID: 3, Status: models.OutboxMessageStatusFailed, CreatedAt: time.Now()}
+
+	NewOutboxService(repo).PurgeOld(context.Background())
+
+	if _, ok := repo.Messages[1]; ok {
+		t.Error("old sent message wasn't purged")
+	}
+	if len(repo.Messages) != 2 {
+		t.Errorf("%d messages left, want 2", len(repo.Messages))
+	}
+}
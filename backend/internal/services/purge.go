@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// purgeInterval is how often soft-deleted records past the retention period are removed
+const purgeInterval = time.Hour
+
+// PurgeService permanently removes deleted users, tasks, and meetings once they can no longer be
+// restored
+type PurgeService struct {
+	userRepo    repository.UserRepository
+	taskRepo    repository.TaskRepository
+	meetingRepo repository.MeetingRepository
+	// retention is how long a deleted record can be restored
+	retention time.Duration
+	logger    *logger.Logger
+}
+
+// NewPurgeService creates a new purge service
+func NewPurgeService(userRepo repository.UserRepository, taskRepo repository.TaskRepository, meetingRepo repository.MeetingRepository, retention time.Duration) *PurgeService {
+	return &PurgeService{
+		userRepo:    userRepo,
+		taskRepo:    taskRepo,
+		meetingRepo: meetingRepo,
+		retention:   retention,
+		logger:      logger.Default().WithComponent("purge-service"),
+	}
+}
+
+// Purge removes records deleted longer ago than the retention period. Meetings and tasks go
+// first; purging a user then removes the rest of what they created.
+func (s *PurgeService) Purge(ctx context.Context, now time.Time) error {
+	before := now.Add(-s.retention)
+	purges := []struct {
+		name  string
+		purge func(context.Context, time.Time) (int64, error)
+	}{
+		{"meetings", s.meetingRepo.PurgeDeleted},
+		{"tasks", s.taskRepo.PurgeDeleted},
+		{"users", s.userRepo.PurgeDeleted},
+	}
+	for _, p := range purges {
+		purged, err := p.purge(ctx, before)
+		if err != nil {
+			return err
+		}
+		if purged > 0 {
+			s.logger.Info("Purged deleted records", "type", p.name, "count", purged)
+		}
+	}
+	return nil
+}
+
+// Start purges deleted records every hour until workers are stopped
+func (s *PurgeService) Start(workers *lifecycle.Group) {
+	workers.Go("soft-delete-purge", func(ctx context.Context) {
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Purge(ctx, time.Now()); err != nil {
+					s.logger.LogError(ctx, "Failed to purge deleted records", err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestPurgeService_Purge(t *testing.T) {
+	ctx := context.Background()
+	userRepo := mocks.NewMockUserRepository()
+	taskRepo := mocks.NewMockTaskRepository()
+	meetingRepo := mocks.NewMockMeetingRepository()
+
+	userRepo.AddUser(&models.User{ID: 2, Email: "old@example.com"})
+	userRepo.AddUser(&models.User{ID: 3, Email: "recent@example.com"})
+	taskRepo.AddTask(&models.Task{ID: 1})
+	taskRepo.AddTask(&models.Task{ID: 2})
+	meetingRepo.AddMeeting(&models.Meeting{ID: 1})
+	for _, id := range []int64{2, 3} {
+		_ = userRepo.Delete(ctx, id)
+	}
+	_ = taskRepo.Delete(ctx, 1)
+	_ = meetingRepo.Delete(ctx, 1)
+
+	// Only user 2 was deleted before the retention period; everything else was deleted just now
+	longAgo := time.Now().AddDate(0, 0, -40)
+	userRepo.Deleted[2].DeletedAt = &longAgo
+
+	service := NewPurgeService(userRepo, taskRepo, meetingRepo, 30*24*time.Hour)
+	if err := service.Purge(ctx, time.Now()); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if _, ok := userRepo.Deleted[2]; ok {
+		t.Error("user deleted before the retention period was not purged")
+	}
+	if _, ok := userRepo.Deleted[3]; !ok {
+		t.Error("recently deleted user was purged")
+	}
+	if len(taskRepo.Deleted) != 1 || len(meetingRepo.Deleted) != 1 {
+		t.Error("recently deleted task and meeting should still be restorable")
+	}
+
+	// Once the retention period has passed for everything, it is all purged
+	if err := service.Purge(ctx, time.Now().AddDate(0, 0, 31)); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if len(userRepo.Deleted) != 0 || len(taskRepo.Deleted) != 0 || len(meetingRepo.Deleted) != 0 {
+		t.Error("records past the retention period were not purged")
+	}
+	if len(taskRepo.Tasks) != 1 {
+		t.Error("a task that wasn't deleted was purged")
+	}
+}
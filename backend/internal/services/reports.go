@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// ErrUnknownReport is returned for a report that doesn't exist
+var ErrUnknownReport = errors.New("unknown report")
+
+// ReportService runs the team analytics reports and lays them out for download
+type ReportService struct {
+	repo repository.ReportRepository
+}
+
+// NewReportService creates a new report service
+func NewReportService(repo repository.ReportRepository) *ReportService {
+	return &ReportService{repo: repo}
+}
+
+// Run runs a report over the query's days and people, returning it with its CSV rows
+func (s *ReportService) Run(ctx context.Context, kind models.ReportKind, q *models.ReportQuery) (*models.Report, [][]string, error) {
+	report := &models.Report{
+		Report: kind,
+		From:   q.From.Format("2006-01-02"),
+		To:     q.To.Format("2006-01-02"),
+		Scope:  "organization",
+	}
+	if q.TeamOf != nil {
+		report.Scope = "team"
+	}
+
+	var table [][]string
+	var err error
+	switch kind {
+	case models.ReportTimeOffUsage:
+		var rows []models.TimeOffUsageRow
+		rows, err = s.repo.TimeOffUsage(ctx, q)
+		report.Rows, table = rows, timeOffUsageCSVRows(rows)
+	case models.ReportApprovalLatency:
+		var rows []models.ApprovalLatencyRow
+		rows, err = s.repo.ApprovalLatency(ctx, q)
+		report.Rows, table = rows, approvalLatencyCSVRows(rows)
+	case models.ReportMeetingLoad:
+		var rows []models.MeetingLoadRow
+		rows, err = s.repo.MeetingLoad(ctx, q)
+		report.Rows, table = rows, meetingLoadCSVRows(rows)
+	case models.ReportJiraThroughput:
+		var rows []models.JiraThroughputRow
+		rows, err = s.repo.JiraThroughput(ctx, q)
+		report.Rows, table = rows, jiraThroughputCSVRows(rows)
+	case models.ReportHeadcount:
+		var rows []models.HeadcountRow
+		rows, err = s.repo.Headcount(ctx, q)
+		report.Rows, table = rows, headcountCSVRows(rows)
+	case models.ReportInactiveAccounts:
+		var rows []models.InactiveAccountRow
+		rows, err = s.repo.InactiveAccounts(ctx, q)
+		report.Rows, table = rows, inactiveAccountsCSVRows(rows)
+	default:
+		return nil, nil, ErrUnknownReport
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return report, table, nil
+}
+
+// EncodeReportCSV encodes a report's CSV rows
+func EncodeReportCSV(table [][]string) ([]byte, error) {
+	return encodeCSV(table)
+}
+
+func formatHours(hours float64) string {
+	return strconv.FormatFloat(hours, 'f', 1, 64)
+}
+
+func timeOffUsageCSVRows(rows []models.TimeOffUsageRow) [][]string {
+	table := [][]string{{"department", "quarter", "request_type", "requests", "days"}}
+	for _, r := range rows {
+		table = append(table, []string{r.Department, r.Quarter, string(r.RequestType), strconv.Itoa(r.Requests), strconv.Itoa(r.Days)})
+	}
+	return table
+}
+
+func approvalLatencyCSVRows(rows []models.ApprovalLatencyRow) [][]string {
+	table := [][]string{{"reviewer_id", "reviewer_name", "reviewed", "approved", "rejected", "average_hours", "max_hours"}}
+	for _, r := range rows {
+		table = append(table, []string{
+			strconv.FormatInt(r.ReviewerID, 10), r.ReviewerName, strconv.Itoa(r.Reviewed), strconv.Itoa(r.Approved),
+			strconv.Itoa(r.Rejected), formatHours(r.AverageHours), formatHours(r.MaxHours),
+		})
+	}
+	return table
+}
+
+func meetingLoadCSVRows(rows []models.MeetingLoadRow) [][]string {
+	table := [][]string{{"user_id", "name", "meetings", "hours"}}
+	for _, r := range rows {
+		table = append(table, []string{strconv.FormatInt(r.UserID, 10), r.Name, strconv.Itoa(r.Meetings), formatHours(r.Hours)})
+	}
+	return table
+}
+
+func jiraThroughputCSVRows(rows []models.JiraThroughputRow) [][]string {
+	table := [][]string{{"squad_id", "squad", "resolved", "assignees"}}
+	for _, r := range rows {
+		table = append(table, []string{formatOptionalID(r.SquadID), r.Squad, strconv.Itoa(r.Resolved), strconv.Itoa(r.Assignees)})
+	}
+	return table
+}
+
+func headcountCSVRows(rows []models.HeadcountRow) [][]string {
+	table := [][]string{{"month", "hires", "departures", "headcount"}}
+	for _, r := range rows {
+		table = append(table, []string{r.Month, strconv.Itoa(r.Hires), strconv.Itoa(r.Departures), strconv.Itoa(r.Headcount)})
+	}
+	return table
+}
+
+func inactiveAccountsCSVRows(rows []models.InactiveAccountRow) [][]string {
+	table := [][]string{{"user_id", "name", "email", "role", "department", "last_login_at", "last_seen_at", "days_inactive"}}
+	for _, r := range rows {
+		table = append(table, []string{
+			strconv.FormatInt(r.UserID, 10), r.Name, r.Email, string(r.Role), r.Department,
+			formatOptionalTimestamp(r.LastLoginAt), formatOptionalTimestamp(r.LastSeenAt), strconv.Itoa(r.DaysInactive),
+		})
+	}
+	return table
+}
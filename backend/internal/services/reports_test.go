@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestReportService_Run(t *testing.T) {
+	repo := mocks.NewMockReportRepository()
+	repo.ApprovalLatencyRows = []models.ApprovalLatencyRow{
+		{ReviewerID: 7, ReviewerName: "=Grace Hopper", Reviewed: 3, Approved: 2, Rejected: 1, AverageHours: 5.5, MaxHours: 12},
+	}
+	svc := NewReportService(repo)
+	supervisorID := int64(7)
+	q := &models.ReportQuery{
+		From:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		TeamOf: &supervisorID,
+	}
+
+	report, table, err := svc.Run(context.Background(), models.ReportApprovalLatency, q)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Scope != "team" || report.From != "2026-01-01" || report.To != "2026-03-31" {
+		t.Errorf("report = %+v, want the team's first quarter", report)
+	}
+	if len(repo.Queries) != 1 || repo.Queries[0].TeamOf == nil || *repo.Queries[0].TeamOf != supervisorID {
+		t.Errorf("queries = %+v, want one for the team", repo.Queries)
+	}
+
+	data, err := EncodeReportCSV(table)
+	if err != nil {
+		t.Fatalf("EncodeReportCSV() error = %v", err)
+	}
+	want := "reviewer_id,reviewer_name,reviewed,approved,rejected,average_hours,max_hours\n7,'=Grace Hopper,3,2,1,5.5,12.0\n"
+	if string(data) != want {
+		t.Errorf("CSV = %q, want %q", data, want)
+	}
+
+	if _, _, err := svc.Run(context.Background(), "burndown", q); !errors.Is(err, ErrUnknownReport) {
+		t.Errorf("Run(burndown) error = %v, want ErrUnknownReport", err)
+	}
+}
+
+func TestReportService_RunEveryReport(t *testing.T) {
+	svc := NewReportService(mocks.NewMockReportRepository())
+	q, _ := models.ParseReportQuery("", "", time.Now())
+
+	for _, kind := range models.ReportKinds {
+		report, table, err := svc.Run(context.Background(), kind, q)
+		if err != nil {
+			t.Fatalf("Run(%s) error = %v", kind, err)
+		}
+		if report.Scope != "organization" || len(table) != 1 || len(table[0]) < 2 {
+			t.Errorf("Run(%s) = %+v with %v, want an organization report with a header only", kind, report, table)
+		}
+	}
+}
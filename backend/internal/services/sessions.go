@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/cache"
+	"github.com/smith-dallin/manager-dashboard/internal/identity"
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+const (
+	// revocationCacheTTL is how long a revocation check is cached, and so how long a revocation
+	// made on another instance can take to apply
+	revocationCacheTTL = 30 * time.Second
+	// sessionTouchInterval is how often a session's last use is written while its token is in use
+	sessionTouchInterval = time.Minute
+	// sessionCleanupInterval is how often expired sessions and revocations are removed
+	sessionCleanupInterval = time.Hour
+	// sessionRetention is how long a session is listed after its token was last used
+	sessionRetention = 30 * 24 * time.Hour
+	// sessionListLimit caps how many recent sessions are listed for a user
+	sessionListLimit = 50
+	// loginRetention is how long a user's sign-ins are kept
+	loginRetention = 365 * 24 * time.Hour
+	// loginListLimit caps how many recent sign-ins are listed for a user
+	loginListLimit = 100
+)
+
+// ErrUserNeverSignedIn is returned when revoking the sessions of a user who has no tokens to revoke
+var ErrUserNeverSignedIn = fmt.Errorf("user has never signed in")
+
+// SessionService records the sessions users authenticate with and keeps the denylist of revoked
+// tokens, caching lookups so checking every request doesn't query the database
+type SessionService struct {
+	repo repository.SessionRepository
+	// tokenLifetime is how long a revocation is enforced: the longest an access token can live
+	tokenLifetime time.Duration
+	cache         *cache.Cache
+	logger        *logger.Logger
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(repo repository.SessionRepository, tokenLifetime time.Duration) *SessionService {
+	return &SessionService{
+		repo:          repo,
+		tokenLifetime: tokenLifetime,
+		cache:         cache.New(revocationCacheTTL, time.Minute),
+		logger:        logger.Default().WithComponent("session-service"),
+	}
+}
+
+// IsRevoked reports whether a token has been revoked, by itself or along with every token issued
+// to its subject before then. Tokens without an issue time can't be told apart from those issued
+// before a revocation and are revoked too.
+func (s *SessionService) IsRevoked(ctx context.Context, ident *identity.Identity) (bool, error) {
+	tokenRevoked, err := s.cache.GetOrSet("token:"+ident.TokenID, func() (interface{}, error) {
+		return s.repo.IsTokenRevoked(ctx, ident.TokenID)
+	})
+	if err != nil {
+		return false, err
+	}
+	if tokenRevoked.(bool) {
+		return true, nil
+	}
+
+	subjectRevokedAt, err := s.cache.GetOrSet("subject:"+ident.Subject, func() (interface{}, error) {
+		revokedAt, err := s.repo.GetSubjectRevokedAt(ctx, ident.Subject)
+		if err != nil || revokedAt == nil {
+			return time.Time{}, err
+		}
+		return *revokedAt, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	revokedAt := subjectRevokedAt.(time.Time)
+	if revokedAt.IsZero() {
+		return false, nil
+	}
+	// Issue times are whole seconds, so a token issued in the second the user was signed out is
+	// taken to be the new sign-in
+	return ident.IssuedAt.IsZero() || ident.IssuedAt.Before(revokedAt.Truncate(time.Second)), nil
+}
+
+// Touch records that the user authenticated with a token, writing at most once a minute per token
+func (s *SessionService) Touch(ctx context.Context, userID int64, ident *identity.Identity, ipAddress, userAgent string) error {
+	key := "touched:" + ident.TokenID
+	if _, ok := s.cache.Get(key); ok {
+		return nil
+	}
+
+	session := &models.UserSession{
+		UserID:    userID,
+		TokenID:   ident.TokenID,
+		Provider:  ident.Provider,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	}
+	if !ident.IssuedAt.IsZero() {
+		session.IssuedAt = &ident.IssuedAt
+	}
+	if !ident.ExpiresAt.IsZero() {
+		session.ExpiresAt = &ident.ExpiresAt
+	}
+	if err := s.repo.Touch(ctx, session); err != nil {
+		return err
+	}
+	s.cache.SetWithTTL(key, true, sessionTouchInterval)
+	return nil
+}
+
+// List returns a user's most recently used sessions
+func (s *SessionService) List(ctx context.Context, userID int64) ([]models.UserSession, error) {
+	return s.repo.ListByUser(ctx, userID, sessionListLimit)
+}
+
+// LoginActivity returns when a user last signed in and was last seen, with their recent sign-ins
+func (s *SessionService) LoginActivity(ctx context.Context, user *models.User) (*models.UserLoginActivity, error) {
+	logins, err := s.repo.ListLogins(ctx, user.ID, loginListLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UserLoginActivity{
+		UserID:      user.ID,
+		LastLoginAt: user.LastLoginAt,
+		LastSeenAt:  user.LastSeenAt,
+		Logins:      logins,
+	}, nil
+}
+
+// GetSession returns one of a user's sessions, or nil if the user has no such session
+func (s *SessionService) GetSession(ctx context.Context, userID, sessionID int64) (*models.UserSession, error) {
+	session, err := s.repo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userID {
+		return nil, nil
+	}
+	return session, nil
+}
+
+// RevokeAll signs a user out everywhere by revoking every token issued to them so far. They can
+// sign in again, unless they are also deactivated.
+func (s *SessionService) RevokeAll(ctx context.Context, user *models.User, revokedByID int64) error {
+	if user.Auth0ID == "" {
+		return ErrUserNeverSignedIn
+	}
+	revoked := &models.RevokedToken{
+		Subject:     &user.Auth0ID,
+		UserID:      &user.ID,
+		RevokedByID: &revokedByID,
+		ExpiresAt:   time.Now().Add(s.tokenLifetime),
+	}
+	if err := s.repo.Revoke(ctx, revoked); err != nil {
+		return err
+	}
+	s.cache.Delete("subject:" + user.Auth0ID)
+	return nil
+}
+
+// RevokeSession signs a user out of one session by revoking its token
+func (s *SessionService) RevokeSession(ctx context.Context, session *models.UserSession, revokedByID int64) error {
+	expiresAt := time.Now().Add(s.tokenLifetime)
+	if session.ExpiresAt != nil {
+		expiresAt = *session.ExpiresAt
+	}
+	revoked := &models.RevokedToken{
+		TokenID:     &session.TokenID,
+		UserID:      &session.UserID,
+		RevokedByID: &revokedByID,
+		ExpiresAt:   expiresAt,
+	}
+	if err := s.repo.Revoke(ctx, revoked); err != nil {
+		return err
+	}
+	s.cache.Delete("token:" + session.TokenID)
+	return nil
+}
+
+// Start removes expired sessions, revocations, and sign-ins every hour until workers are stopped
+func (s *SessionService) Start(workers *lifecycle.Group) {
+	workers.Go("session-cleanup", func(ctx context.Context) {
+		ticker := time.NewTicker(sessionCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				now := time.Now()
+				deleted, err := s.repo.DeleteExpired(ctx, now.Add(-sessionRetention), now.Add(-loginRetention))
+				if err != nil {
+					s.logger.LogError(ctx, "Failed to delete expired sessions", err)
+				} else if deleted > 0 {
+					s.logger.Info("Deleted expired sessions and revocations", "count", deleted)
+				}
+			}
+		}
+	})
+}
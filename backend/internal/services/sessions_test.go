@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/identity"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func sessionIdentity(tokenID string, issuedAt time.Time) *identity.Identity {
+	return &identity.Identity{
+		Subject:   "auth0|ada",
+		Provider:  "auth0",
+		TokenID:   tokenID,
+		IssuedAt:  issuedAt,
+		ExpiresAt: issuedAt.Add(time.Hour),
+	}
+}
+
+func TestSessionService_RevokeAll(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockSessionRepository()
+	service := NewSessionService(repo, 24*time.Hour)
+	user := &models.User{ID: 2, Auth0ID: "auth0|ada"}
+
+	oldToken := sessionIdentity("old", time.Now().Add(-time.Hour))
+	if err := service.Touch(ctx, user.ID, oldToken, "10.0.0.1", "curl"); err != nil {
+		t.Fatalf("Touch() error = %v", err)
+	}
+	// Cache the check before revoking, as a request made just before would
+	if revoked, _ := service.IsRevoked(ctx, oldToken); revoked {
+		t.Fatal("token revoked before signing the user out")
+	}
+
+	if err := service.RevokeAll(ctx, user, 1); err != nil {
+		t.Fatalf("RevokeAll() error = %v", err)
+	}
+
+	if revoked, _ := service.IsRevoked(ctx, oldToken); !revoked {
+		t.Error("token issued before signing out is not revoked")
+	}
+	if revoked, _ := service.IsRevoked(ctx, sessionIdentity("new", time.Now().Add(time.Second))); revoked {
+		t.Error("token issued after signing out is revoked")
+	}
+	if revoked, _ := service.IsRevoked(ctx, sessionIdentity("no-iat", time.Time{})); !revoked {
+		t.Error("token without an issue time is not revoked")
+	}
+
+	sessions, _ := service.List(ctx, user.ID)
+	if len(sessions) != 1 || sessions[0].RevokedAt == nil {
+		t.Errorf("sessions = %+v, want the one session marked revoked", sessions)
+	}
+}
+
+func TestSessionService_RevokeAll_NeverSignedIn(t *testing.T) {
+	service := NewSessionService(mocks.NewMockSessionRepository(), time.Hour)
+	err := service.RevokeAll(context.Background(), &models.User{ID: 3}, 1)
+	if !errors.Is(err, ErrUserNeverSignedIn) {
+		t.Errorf("RevokeAll() error = %v, want ErrUserNeverSignedIn", err)
+	}
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockSessionRepository()
+	service := NewSessionService(repo, 24*time.Hour)
+
+	laptop := sessionIdentity("laptop", time.Now())
+	phone := sessionIdentity("phone", time.Now())
+	_ = service.Touch(ctx, 2, laptop, "10.0.0.1", "Firefox")
+	_ = service.Touch(ctx, 2, phone, "10.0.0.2", "Safari")
+	if revoked, _ := service.IsRevoked(ctx, laptop); revoked {
+		t.Fatal("token revoked before revoking its session")
+	}
+
+	session, err := service.GetSession(ctx, 2, 1)
+	if err != nil || session == nil {
+		t.Fatalf("GetSession() = %v, %v", session, err)
+	}
+	if err := service.RevokeSession(ctx, session, 1); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+
+	if revoked, _ := service.IsRevoked(ctx, laptop); !revoked {
+		t.Error("revoked session's token is not revoked")
+	}
+	if revoked, _ := service.IsRevoked(ctx, phone); revoked {
+		t.Error("another session's token is revoked")
+	}
+}
+
+func TestSessionService_GetSession_OtherUser(t *testing.T) {
+	ctx := context.Background()
+	service := NewSessionService(mocks.NewMockSessionRepository(), time.Hour)
+	_ = service.Touch(ctx, 2, sessionIdentity("laptop", time.Now()), "", "")
+
+	session, err := service.GetSession(ctx, 3, 1)
+	if err != nil || session != nil {
+		t.Errorf("GetSession() = %v, %v, want no session for another user", session, err)
+	}
+}
+
+func TestSessionService_TouchThrottlesWrites(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockSessionRepository()
+	service := NewSessionService(repo, time.Hour)
+	ident := sessionIdentity("laptop", time.Now())
+
+	_ = service.Touch(ctx, 2, ident, "10.0.0.1", "Firefox")
+	_ = service.Touch(ctx, 2, ident, "10.0.0.9", "Firefox")
+
+	if len(repo.Sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(repo.Sessions))
+	}
+	if repo.Sessions[1].IPAddress != "10.0.0.1" {
+		t.Errorf("IPAddress = %q, want the second use within a minute not written", repo.Sessions[1].IPAddress)
+	}
+}
+
+func TestSessionService_TouchRecordsSignIns(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockSessionRepository()
+	service := NewSessionService(repo, time.Hour)
+	issuedAt := time.Now()
+
+	_ = service.Touch(ctx, 2, sessionIdentity("laptop", issuedAt), "10.0.0.1", "Firefox")
+	_ = service.Touch(ctx, 2, sessionIdentity("laptop", issuedAt), "10.0.0.1", "Firefox")
+	_ = service.Touch(ctx, 2, sessionIdentity("phone", issuedAt), "10.0.0.2", "Safari")
+
+	activity, err := service.LoginActivity(ctx, &models.User{ID: 2})
+	if err != nil {
+		t.Fatalf("LoginActivity() error = %v", err)
+	}
+	if len(activity.Logins) != 2 {
+		t.Fatalf("got %d sign-ins, want one per new token", len(activity.Logins))
+	}
+	if activity.Logins[0].UserAgent != "Safari" {
+		t.Errorf("first sign-in = %+v, want the most recent first", activity.Logins[0])
+	}
+}
+
+func TestSessionService_IsRevoked_Error(t *testing.T) {
+	repo := mocks.NewMockSessionRepository()
+	repo.IsTokenRevokedFunc = func(ctx context.Context, tokenID string) (bool, error) {
+		return false, errors.New("database down")
+	}
+	service := NewSessionService(repo, time.Hour)
+
+	if _, err := service.IsRevoked(context.Background(), sessionIdentity("laptop", time.Now())); err == nil {
+		t.Error("IsRevoked() error = nil, want the repository error")
+	}
+}
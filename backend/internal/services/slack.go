@@ -0,0 +1,62 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+)
+
+// SlackNotifier posts messages to a Slack incoming webhook
+type SlackNotifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewSlackNotifier creates a new Slack notifier from config
+func NewSlackNotifier(cfg *config.Config) *SlackNotifier {
+	return &SlackNotifier{
+		webhookURL: cfg.SlackWebhookURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// IsEnabled reports whether a webhook is configured
+func (n *SlackNotifier) IsEnabled() bool {
+	return n != nil && n.webhookURL != ""
+}
+
+// Notify posts a plain-text message to the configured webhook
+func (n *SlackNotifier) Notify(ctx context.Context, text string) error {
+	if !n.IsEnabled() {
+		return fmt.Errorf("slack notifications are not configured")
+	}
+
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post slack message: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("slack webhook failed (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
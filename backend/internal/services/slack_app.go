@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/slack"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+// SlackAppService sends direct messages from organizations' Slack app installations: supervisors
+// hear about time off requests waiting for them, with buttons to approve or reject them, and
+// employees hear how their requests were reviewed. Messages are queued in the outbox and sent by
+// its worker, so Slack never delays or fails the change. All Notify methods are safe to call on
+// a nil service, and do nothing for organizations that haven't installed the app.
+type SlackAppService struct {
+	repo     repository.OrgSlackRepository
+	userRepo repository.UserRepository
+	outbox   repository.OutboxRepository
+	apiURL   string
+	logger   *logger.Logger
+}
+
+// NewSlackAppService creates a new Slack app service
+func NewSlackAppService(repo repository.OrgSlackRepository, userRepo repository.UserRepository, outbox repository.OutboxRepository) *SlackAppService {
+	return &SlackAppService{
+		repo:     repo,
+		userRepo: userRepo,
+		outbox:   outbox,
+		logger:   logger.Default().WithComponent("slack-app"),
+	}
+}
+
+// WithAPIURL points the service at another Slack Web API root
+func (s *SlackAppService) WithAPIURL(apiURL string) *SlackAppService {
+	s.apiURL = apiURL
+	return s
+}
+
+// Client returns a client for an installation
+func (s *SlackAppService) Client(settings *models.OrgSlackSettings) *slack.Client {
+	client := slack.NewClient(settings.BotAccessToken)
+	if s.apiURL != "" {
+		client.WithBaseURL(s.apiURL)
+	}
+	return client
+}
+
+// NotifyTimeOffRequested asks the requester's supervisor to review a pending request.
+// Requests created already approved, and requests from people without a supervisor, aren't sent.
+func (s *SlackAppService) NotifyTimeOffRequested(ctx context.Context, request *models.TimeOffRequest, requester *models.User) {
+	if s == nil || request.Status != models.TimeOffStatusPending || requester.SupervisorID == nil {
+		return
+	}
+	supervisor, err := s.userRepo.GetByID(ctx, *requester.SupervisorID)
+	if err != nil || supervisor == nil || !supervisor.IsActive {
+		return
+	}
+
+	text := fmt.Sprintf("*%s %s* requested %s from %s to %s.", requester.FirstName, requester.LastName,
+		timeOffKind(request), request.StartDate.Format("Jan 2, 2006"), request.EndDate.Format("Jan 2, 2006"))
+	if request.Reason != nil && *request.Reason != "" {
+		text += "\n>" + *request.Reason
+	}
+	s.send(ctx, supervisor, text, &request.ID)
+}
+
+// NotifyTimeOffReviewed tells the requester that their time off request was approved or rejected.
+// Reviewing your own request isn't announced.
+func (s *SlackAppService) NotifyTimeOffReviewed(ctx context.Context, request *models.TimeOffRequest, reviewer *models.User) {
+	if s == nil || request.UserID == reviewer.ID {
+		return
+	}
+	requester := request.User
+	if requester == nil {
+		var err error
+		if requester, err = s.userRepo.GetByID(ctx, request.UserID); err != nil || requester == nil {
+			return
+		}
+	}
+
+	text := fmt.Sprintf("Your %s request for %s to %s was *%s* by %s %s.", timeOffKind(request),
+		request.StartDate.Format("Jan 2, 2006"), request.EndDate.Format("Jan 2, 2006"), request.Status,
+		reviewer.FirstName, reviewer.LastName)
+	if request.ReviewerNotes != nil && *request.ReviewerNotes != "" {
+		text += "\n>" + *request.ReviewerNotes
+	}
+	s.send(ctx, requester, text, nil)
+}
+
+// timeOffKind names a request's type for messages, such as "sick leave"
+func timeOffKind(request *models.TimeOffRequest) string {
+	return strings.ReplaceAll(string(request.RequestType), "_", " ")
+}
+
+// send queues a direct message to an active user if their organization has installed the app
+func (s *SlackAppService) send(ctx context.Context, to *models.User, text string, reviewRequestID *int64) {
+	if !to.IsActive {
+		return
+	}
+	settings, err := s.repo.Get(ctx)
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to get Slack settings", err)
+		return
+	}
+	if settings == nil {
+		return
+	}
+
+	err = s.outbox.Enqueue(ctx, models.OutboxKindSlackMessage, models.SlackMessagePayload{
+		OrgID:                  settings.OrgID,
+		To:                     to.Email,
+		Text:                   text,
+		ReviewTimeOffRequestID: reviewRequestID,
+	})
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to queue Slack message", err, "user_id", to.ID)
+	}
+}
+
+// SendMessage sends a queued direct message. Messages to people who aren't in the workspace, and
+// to organizations that have since removed the app, fail permanently.
+func (s *SlackAppService) SendMessage(ctx context.Context, payload *models.SlackMessagePayload) error {
+	ctx = tenant.WithOrgID(ctx, payload.OrgID)
+	settings, err := s.repo.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if settings == nil {
+		return fmt.Errorf("%w: slack is no longer connected", errOutboxPermanent)
+	}
+
+	client := s.Client(settings)
+	slackUserID, err := client.LookupUserByEmail(ctx, payload.To)
+	if errors.Is(err, slack.ErrUserNotFound) {
+		return fmt.Errorf("%w: %s is not in the slack workspace", errOutboxPermanent, payload.To)
+	}
+	if err != nil {
+		return err
+	}
+
+	msg := slack.Message{Text: payload.Text}
+	if payload.ReviewTimeOffRequestID != nil {
+		msg = slack.TimeOffReviewMessage(payload.Text, *payload.ReviewTimeOffRequestID)
+	}
+	return client.PostMessage(ctx, slackUserID, msg)
+}
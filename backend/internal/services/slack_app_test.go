@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestSlackAppService_NotifyTimeOff(t *testing.T) {
+	supervisorID := int64(1)
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.Users[1] = &models.User{ID: 1, FirstName: "Grace", LastName: "Hopper", Email: "grace@example.com", IsActive: true}
+	requester := &models.User{ID: 2, FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", IsActive: true, SupervisorID: &supervisorID}
+	userRepo.Users[2] = requester
+
+	slackRepo := mocks.NewMockOrgSlackRepository()
+	outbox := mocks.NewMockOutboxRepository()
+	service := NewSlackAppService(slackRepo, userRepo, outbox)
+
+	start := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	request := &models.TimeOffRequest{ID: 9, UserID: 2, RequestType: "sick_leave", Status: models.TimeOffStatusPending, StartDate: start, EndDate: start.AddDate(0, 0, 2)}
+
+	// Nothing is queued until the organization installs the app
+	service.NotifyTimeOffRequested(context.Background(), request, requester)
+	if len(outbox.Messages) != 0 {
+		t.Fatalf("queued %d messages without a Slack installation", len(outbox.Messages))
+	}
+
+	_ = slackRepo.Save(context.Background(), &models.OrgSlackSettings{BotAccessToken: "xoxb-1", TeamID: "T1", TeamName: "Acme"})
+	service.NotifyTimeOffRequested(context.Background(), request, requester)
+
+	request.Status = models.TimeOffStatusApproved
+	service.NotifyTimeOffReviewed(context.Background(), request, userRepo.Users[1])
+	// Reviewing your own request isn't announced
+	service.NotifyTimeOffReviewed(context.Background(), request, requester)
+
+	var payloads []models.SlackMessagePayload
+	for id := int64(1); id < outbox.NextID; id++ {
+		var payload models.SlackMessagePayload
+		if outbox.Messages[id].Kind != models.OutboxKindSlackMessage {
+			t.Errorf("message kind = %s", outbox.Messages[id].Kind)
+		}
+		_ = json.Unmarshal(outbox.Messages[id].Payload, &payload)
+		payloads = append(payloads, payload)
+	}
+	if len(payloads) != 2 {
+		t.Fatalf("queued %d messages, want 2: %+v", len(payloads), payloads)
+	}
+	if p := payloads[0]; p.To != "grace@example.com" || p.ReviewTimeOffRequestID == nil || *p.ReviewTimeOffRequestID != 9 ||
+		!strings.Contains(p.Text, "Ada Lovelace* requested sick leave from Jul 1, 2024 to Jul 3, 2024") {
+		t.Errorf("supervisor message = %+v", p)
+	}
+	if p := payloads[1]; p.To != "ada@example.com" || p.ReviewTimeOffRequestID != nil || !strings.Contains(p.Text, "was *approved* by Grace Hopper") {
+		t.Errorf("requester message = %+v", p)
+	}
+}
+
+func TestSlackAppService_SendMessage(t *testing.T) {
+	var posted map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users.lookupByEmail":
+			if r.URL.Query().Get("email") != "grace@example.com" {
+				_, _ = w.Write([]byte(`{"ok":false,"error":"users_not_found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"U1"}}`))
+		case "/chat.postMessage":
+			_ = json.NewDecoder(r.Body).Decode(&posted)
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	slackRepo := mocks.NewMockOrgSlackRepository()
+	_ = slackRepo.Save(context.Background(), &models.OrgSlackSettings{BotAccessToken: "xoxb-1", TeamID: "T1"})
+	service := NewSlackAppService(slackRepo, mocks.NewMockUserRepository(), mocks.NewMockOutboxRepository()).WithAPIURL(server.URL)
+
+	requestID := int64(9)
+	err := service.SendMessage(context.Background(), &models.SlackMessagePayload{OrgID: 1, To: "grace@example.com", Text: "Ada requested PTO", ReviewTimeOffRequestID: &requestID})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if posted["channel"] != "U1" || posted["blocks"] == nil {
+		t.Errorf("posted = %v, want a DM with review buttons", posted)
+	}
+
+	// People outside the workspace, and organizations without the app, can't be retried into success
+	err = service.SendMessage(context.Background(), &models.SlackMessagePayload{OrgID: 1, To: "nobody@example.com", Text: "hi"})
+	if !errors.Is(err, errOutboxPermanent) {
+		t.Errorf("SendMessage() to unknown user error = %v, want permanent", err)
+	}
+	err = service.SendMessage(context.Background(), &models.SlackMessagePayload{OrgID: 2, To: "grace@example.com", Text: "hi"})
+	if !errors.Is(err, errOutboxPermanent) {
+		t.Errorf("SendMessage() without an installation error = %v, want permanent", err)
+	}
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// ErrSquadNotFound is returned when the requested squad does not exist
+var ErrSquadNotFound = fmt.Errorf("squad not found")
+
+// ErrSquadAccessDenied is returned when a supervisor checks a squad they have no members in
+var ErrSquadAccessDenied = fmt.Errorf("you can only check squads you or your direct reports belong to")
+
+// ErrNoUpcomingSprint is returned when a board has no future sprint to check
+var ErrNoUpcomingSprint = fmt.Errorf("board has no upcoming sprint")
+
+// ErrSprintNotScheduled is returned when a sprint has no start or end date yet
+var ErrSprintNotScheduled = fmt.Errorf("sprint has no start and end dates")
+
+// SprintDataSource provides sprint scope from Jira
+type SprintDataSource interface {
+	GetSprint(sprintID int) (*models.JiraSprint, error)
+	GetBoardSprints(boardID int, state string) ([]models.JiraSprint, error)
+	GetSprintIssues(sprintID int, storyPointsField string) ([]models.JiraSprintIssue, error)
+}
+
+// SprintCapacityService compares a sprint's committed scope with a squad's available capacity
+type SprintCapacityService struct {
+	squadRepo        repository.SquadRepository
+	timeOffRepo      repository.TimeOffRepository
+	notifier         *SlackNotifier
+	holidays         []string
+	storyPointsField string
+	pointsPerDay     float64
+	logger           *logger.Logger
+}
+
+// NewSprintCapacityService creates a new sprint capacity service
+func NewSprintCapacityService(
+	squadRepo repository.SquadRepository,
+	timeOffRepo repository.TimeOffRepository,
+	notifier *SlackNotifier,
+	cfg *config.Config,
+) *SprintCapacityService {
+	pointsPerDay := cfg.SprintPointsPerDay
+	if pointsPerDay <= 0 {
+		pointsPerDay = 1
+	}
+	return &SprintCapacityService{
+		squadRepo:        squadRepo,
+		timeOffRepo:      timeOffRepo,
+		notifier:         notifier,
+		holidays:         cfg.CompanyHolidays,
+		storyPointsField: cfg.JiraStoryPointsField,
+		pointsPerDay:     pointsPerDay,
+		logger:           logger.Default().WithComponent("sprint-capacity"),
+	}
+}
+
+// Check builds a capacity report for the requested sprint and squad, posting to Slack
+// when requested and the sprint is over-committed
+func (s *SprintCapacityService) Check(ctx context.Context, currentUser *models.User, source SprintDataSource, req *models.SprintCapacityCheckRequest) (*models.SprintCapacityReport, error) {
+	squad, err := s.squadRepo.GetByID(ctx, req.SquadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get squad: %w", err)
+	}
+	if squad == nil {
+		return nil, ErrSquadNotFound
+	}
+
+	members, err := s.squadRepo.GetUsersBySquadID(ctx, squad.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get squad members: %w", err)
+	}
+	if !canCheckSquad(currentUser, members) {
+		return nil, ErrSquadAccessDenied
+	}
+
+	sprint, err := s.resolveSprint(source, req)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.StartDate == nil || sprint.EndDate == nil {
+		return nil, ErrSprintNotScheduled
+	}
+
+	issues, err := source.GetSprintIssues(sprint.ID, s.storyPointsField)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint issues: %w", err)
+	}
+
+	var timeOff []models.TimeOffRequest
+	if len(members) > 0 {
+		userIDs := make([]int64, len(members))
+		for i, m := range members {
+			userIDs[i] = m.ID
+		}
+		timeOff, err = s.timeOffRepo.GetApprovedForUsers(ctx, userIDs, *sprint.StartDate, *sprint.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get time off: %w", err)
+		}
+	}
+
+	pointsPerDay := s.pointsPerDay
+	if req.PointsPerDay != nil {
+		pointsPerDay = *req.PointsPerDay
+	}
+
+	report := BuildSprintCapacityReport(*sprint, squad, members, issues, timeOff, s.holidays, pointsPerDay, time.Now())
+
+	if req.NotifySlack && report.OverCommitted && s.notifier.IsEnabled() {
+		if err := s.notifier.Notify(ctx, formatOverCommitMessage(report)); err != nil {
+			s.logger.LogError(ctx, "Failed to post sprint capacity warning to Slack", err, "squad_id", squad.ID, "sprint_id", sprint.ID)
+			report.Warnings = append(report.Warnings, "Failed to post warning to Slack")
+		} else {
+			report.SlackNotified = true
+		}
+	}
+
+	return report, nil
+}
+
+// resolveSprint returns the requested sprint, or the board's earliest upcoming sprint
+func (s *SprintCapacityService) resolveSprint(source SprintDataSource, req *models.SprintCapacityCheckRequest) (*models.JiraSprint, error) {
+	if req.SprintID != nil {
+		sprint, err := source.GetSprint(*req.SprintID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sprint: %w", err)
+		}
+		return sprint, nil
+	}
+
+	sprints, err := source.GetBoardSprints(*req.BoardID, "future")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board sprints: %w", err)
+	}
+
+	var next *models.JiraSprint
+	for i := range sprints {
+		sprint := &sprints[i]
+		if sprint.StartDate == nil {
+			continue
+		}
+		if next == nil || sprint.StartDate.Before(*next.StartDate) {
+			next = sprint
+		}
+	}
+	if next == nil {
+		return nil, ErrNoUpcomingSprint
+	}
+	return next, nil
+}
+
+// canCheckSquad allows admins any squad, and supervisors squads they or their direct reports belong to
+func canCheckSquad(user *models.User, members []models.User) bool {
+	if user.IsAdmin() {
+		return true
+	}
+	for _, m := range members {
+		if m.ID == user.ID || (m.SupervisorID != nil && *m.SupervisorID == user.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildSprintCapacityReport computes per-member and squad capacity for a sprint and compares it
+// with the committed story points. The sprint's end day is exclusive, matching Jira's default of
+// ending a sprint at the same time of day it started.
+func BuildSprintCapacityReport(
+	sprint models.JiraSprint,
+	squad *models.Squad,
+	members []models.User,
+	issues []models.JiraSprintIssue,
+	timeOff []models.TimeOffRequest,
+	holidays []string,
+	pointsPerDay float64,
+	now time.Time,
+) *models.SprintCapacityReport {
+	workingDays, sprintHolidays := sprintWorkingDays(*sprint.StartDate, *sprint.EndDate, holidays)
+
+	report := &models.SprintCapacityReport{
+		Sprint:       sprint,
+		SquadID:      squad.ID,
+		SquadName:    squad.Name,
+		WorkingDays:  len(workingDays),
+		Holidays:     sprintHolidays,
+		PointsPerDay: pointsPerDay,
+		Members:      make([]models.MemberSprintCapacity, 0, len(members)),
+		Warnings:     []string{},
+	}
+
+	timeOffByUser := make(map[int64][]models.TimeOffRequest)
+	for _, t := range timeOff {
+		timeOffByUser[t.UserID] = append(timeOffByUser[t.UserID], t)
+	}
+
+	// Sum committed points per Jira account
+	committedByAccount := make(map[string]float64)
+	for _, issue := range issues {
+		if issue.StoryPoints == nil {
+			report.UnestimatedIssues++
+			continue
+		}
+		report.CommittedPoints += *issue.StoryPoints
+		if issue.AssigneeAccountID != nil {
+			committedByAccount[*issue.AssigneeAccountID] += *issue.StoryPoints
+		}
+	}
+
+	assignedToSquad := 0.0
+	unmapped := 0
+	for _, m := range members {
+		timeOffDays := 0
+		for day := range workingDays {
+			if isOnTimeOff(timeOffByUser[m.ID], day) {
+				timeOffDays++
+			}
+		}
+		available := len(workingDays) - timeOffDays
+
+		member := models.MemberSprintCapacity{
+			UserID:         m.ID,
+			FirstName:      m.FirstName,
+			LastName:       m.LastName,
+			JiraAccountID:  m.JiraAccountID,
+			AvailableDays:  available,
+			TimeOffDays:    timeOffDays,
+			CapacityPoints: float64(available) * pointsPerDay,
+		}
+		if m.JiraAccountID != nil && *m.JiraAccountID != "" {
+			member.CommittedPoints = committedByAccount[*m.JiraAccountID]
+			assignedToSquad += member.CommittedPoints
+		} else {
+			unmapped++
+		}
+		member.OverCommitted = member.CommittedPoints > member.CapacityPoints
+
+		report.CapacityDays += available
+		report.CapacityPoints += member.CapacityPoints
+		report.Members = append(report.Members, member)
+
+		if member.OverCommitted {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s %s is committed to %.1f points with capacity for %.1f",
+				m.FirstName, m.LastName, member.CommittedPoints, member.CapacityPoints))
+		}
+	}
+	report.UnassignedPoints = report.CommittedPoints - assignedToSquad
+
+	report.OverCommitted = report.CommittedPoints > report.CapacityPoints
+	if report.CapacityPoints > 0 {
+		report.LoadPercent = math.Round(report.CommittedPoints/report.CapacityPoints*1000) / 10
+	}
+
+	if report.OverCommitted {
+		report.Warnings = append([]string{fmt.Sprintf("Sprint is over-committed: %.1f points committed with capacity for %.1f",
+			report.CommittedPoints, report.CapacityPoints)}, report.Warnings...)
+	}
+	if sprint.State != "future" || !now.Before(*sprint.StartDate) {
+		report.Warnings = append(report.Warnings, "Sprint has already started")
+	}
+	if report.UnestimatedIssues > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d issues have no story point estimate", report.UnestimatedIssues))
+	}
+	if report.UnassignedPoints > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%.1f points are unassigned or assigned outside the squad", report.UnassignedPoints))
+	}
+	if unmapped > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d squad members are not linked to a Jira account", unmapped))
+	}
+
+	return report
+}
+
+// sprintWorkingDays returns the weekdays in [start, end) that are not company holidays,
+// along with the holidays that fell on weekdays in the sprint
+func sprintWorkingDays(start, end time.Time, holidays []string) (map[time.Time]struct{}, []string) {
+	holidaySet := make(map[string]struct{}, len(holidays))
+	for _, h := range holidays {
+		holidaySet[h] = struct{}{}
+	}
+
+	startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	endDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	if !endDay.After(startDay) {
+		endDay = startDay.AddDate(0, 0, 1)
+	}
+
+	days := make(map[time.Time]struct{})
+	sprintHolidays := []string{}
+	for day := startDay; day.Before(endDay); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		if _, ok := holidaySet[day.Format("2006-01-02")]; ok {
+			sprintHolidays = append(sprintHolidays, day.Format("2006-01-02"))
+			continue
+		}
+		days[day] = struct{}{}
+	}
+	sort.Strings(sprintHolidays)
+	return days, sprintHolidays
+}
+
+// isOnTimeOff reports whether any of the time off requests covers the given day
+func isOnTimeOff(requests []models.TimeOffRequest, day time.Time) bool {
+	for _, t := range requests {
+		start := time.Date(t.StartDate.Year(), t.StartDate.Month(), t.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+		end := time.Date(t.EndDate.Year(), t.EndDate.Month(), t.EndDate.Day(), 0, 0, 0, 0, time.UTC)
+		if !day.Before(start) && !day.After(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatOverCommitMessage builds the Slack warning for an over-committed sprint
+func formatOverCommitMessage(report *models.SprintCapacityReport) string {
+	msg := fmt.Sprintf(":warning: *%s* for squad *%s* is over-committed: %.1f points committed with capacity for %.1f (%.0f%%).",
+		report.Sprint.Name, report.SquadName, report.CommittedPoints, report.CapacityPoints, report.LoadPercent)
+	for _, m := range report.Members {
+		if m.OverCommitted {
+			msg += fmt.Sprintf("\n• %s %s: %.1f committed / %.1f capacity", m.FirstName, m.LastName, m.CommittedPoints, m.CapacityPoints)
+		}
+	}
+	return msg
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+// fakeSprintSource is an in-memory SprintDataSource for testing
+type fakeSprintSource struct {
+	sprints []models.JiraSprint
+	issues  []models.JiraSprintIssue
+}
+
+func (f *fakeSprintSource) GetSprint(sprintID int) (*models.JiraSprint, error) {
+	for i := range f.sprints {
+		if f.sprints[i].ID == sprintID {
+			return &f.sprints[i], nil
+		}
+	}
+	return nil, errors.New("sprint not found")
+}
+
+func (f *fakeSprintSource) GetBoardSprints(boardID int, state string) ([]models.JiraSprint, error) {
+	return f.sprints, nil
+}
+
+func (f *fakeSprintSource) GetSprintIssues(sprintID int, storyPointsField string) ([]models.JiraSprintIssue, error) {
+	return f.issues, nil
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
+func ptrFloat(f float64) *float64    { return &f }
+func ptrString(s string) *string     { return &s }
+
+func testSprint() models.JiraSprint {
+	return models.JiraSprint{
+		ID:        7,
+		Name:      "Sprint 7",
+		State:     "future",
+		StartDate: ptrTime(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)),
+		EndDate:   ptrTime(time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)),
+	}
+}
+
+func testSquadMembers() []models.User {
+	supervisorID := int64(10)
+	return []models.User{
+		{ID: 1, FirstName: "Ada", LastName: "Lovelace", JiraAccountID: ptrString("a"), SupervisorID: &supervisorID},
+		{ID: 2, FirstName: "Alan", LastName: "Turing", JiraAccountID: ptrString("b"), SupervisorID: &supervisorID},
+	}
+}
+
+func testSprintIssues() []models.JiraSprintIssue {
+	return []models.JiraSprintIssue{
+		{Key: "P-1", AssigneeAccountID: ptrString("a"), StoryPoints: ptrFloat(8)},
+		{Key: "P-2", AssigneeAccountID: ptrString("b"), StoryPoints: ptrFloat(5)},
+		{Key: "P-3", StoryPoints: ptrFloat(2)},
+		{Key: "P-4", AssigneeAccountID: ptrString("b")},
+	}
+}
+
+func TestBuildSprintCapacityReport(t *testing.T) {
+	squad := &models.Squad{ID: 3, Name: "Platform"}
+	timeOff := []models.TimeOffRequest{
+		{UserID: 1, StartDate: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)},
+		// Falls on the holiday, so it must not be counted twice
+		{UserID: 2, StartDate: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)},
+	}
+	holidays := []string{"2026-03-09", "2026-03-14", "2026-04-01"}
+	beforeStart := time.Date(2026, 2, 27, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		pointsPerDay      float64
+		wantCapacity      float64
+		wantOverCommitted bool
+		wantLoad          float64
+	}{
+		{name: "within capacity", pointsPerDay: 1, wantCapacity: 16, wantOverCommitted: false, wantLoad: 93.8},
+		{name: "over capacity", pointsPerDay: 0.5, wantCapacity: 8, wantOverCommitted: true, wantLoad: 187.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := BuildSprintCapacityReport(testSprint(), squad, testSquadMembers(), testSprintIssues(), timeOff, holidays, tt.pointsPerDay, beforeStart)
+
+			// Ten weekdays with the end day exclusive, minus one weekday holiday
+			if report.WorkingDays != 9 {
+				t.Errorf("WorkingDays = %d, want 9", report.WorkingDays)
+			}
+			if len(report.Holidays) != 1 || report.Holidays[0] != "2026-03-09" {
+				t.Errorf("Holidays = %v, want [2026-03-09]", report.Holidays)
+			}
+			if report.CapacityDays != 16 {
+				t.Errorf("CapacityDays = %d, want 16", report.CapacityDays)
+			}
+			if report.CapacityPoints != tt.wantCapacity {
+				t.Errorf("CapacityPoints = %v, want %v", report.CapacityPoints, tt.wantCapacity)
+			}
+			if report.CommittedPoints != 15 {
+				t.Errorf("CommittedPoints = %v, want 15", report.CommittedPoints)
+			}
+			if report.UnassignedPoints != 2 {
+				t.Errorf("UnassignedPoints = %v, want 2", report.UnassignedPoints)
+			}
+			if report.UnestimatedIssues != 1 {
+				t.Errorf("UnestimatedIssues = %d, want 1", report.UnestimatedIssues)
+			}
+			if report.OverCommitted != tt.wantOverCommitted {
+				t.Errorf("OverCommitted = %v, want %v", report.OverCommitted, tt.wantOverCommitted)
+			}
+			if report.LoadPercent != tt.wantLoad {
+				t.Errorf("LoadPercent = %v, want %v", report.LoadPercent, tt.wantLoad)
+			}
+
+			ada := report.Members[0]
+			if ada.TimeOffDays != 2 || ada.AvailableDays != 7 || ada.CommittedPoints != 8 {
+				t.Errorf("Ada = %+v, want 2 days off, 7 available, 8 committed", ada)
+			}
+			if !ada.OverCommitted {
+				t.Error("Ada should be over-committed")
+			}
+			alan := report.Members[1]
+			if alan.TimeOffDays != 0 || alan.AvailableDays != 9 {
+				t.Errorf("Alan = %+v, want 0 days off, 9 available", alan)
+			}
+		})
+	}
+}
+
+func TestBuildSprintCapacityReport_StartedSprint(t *testing.T) {
+	sprint := testSprint()
+	sprint.State = "active"
+
+	report := BuildSprintCapacityReport(sprint, &models.Squad{ID: 3}, nil, nil, nil, nil, 1, *sprint.StartDate)
+
+	found := false
+	for _, w := range report.Warnings {
+		if w == "Sprint has already started" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Warnings = %v, want started warning", report.Warnings)
+	}
+}
+
+func TestSprintCapacityService_Check(t *testing.T) {
+	var slackCalls int
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slackCalls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slack.Close()
+
+	later := testSprint()
+	later.ID = 8
+	later.StartDate = ptrTime(later.StartDate.AddDate(0, 0, 14))
+	later.EndDate = ptrTime(later.EndDate.AddDate(0, 0, 14))
+	source := &fakeSprintSource{
+		sprints: []models.JiraSprint{later, testSprint(), {ID: 9, Name: "Unscheduled", State: "future"}},
+		issues:  testSprintIssues(),
+	}
+	boardID := 1
+
+	tests := []struct {
+		name      string
+		user      *models.User
+		req       models.SprintCapacityCheckRequest
+		wantErr   error
+		wantSlack bool
+	}{
+		{
+			name:      "admin picks earliest upcoming sprint and notifies slack",
+			user:      &models.User{ID: 99, Role: models.RoleAdmin},
+			req:       models.SprintCapacityCheckRequest{SquadID: 3, BoardID: &boardID, PointsPerDay: ptrFloat(0.5), NotifySlack: true},
+			wantSlack: true,
+		},
+		{
+			name: "supervisor of a squad member",
+			user: &models.User{ID: 10, Role: models.RoleSupervisor},
+			req:  models.SprintCapacityCheckRequest{SquadID: 3, BoardID: &boardID},
+		},
+		{
+			name:    "supervisor with no members in squad",
+			user:    &models.User{ID: 11, Role: models.RoleSupervisor},
+			req:     models.SprintCapacityCheckRequest{SquadID: 3, BoardID: &boardID},
+			wantErr: ErrSquadAccessDenied,
+		},
+		{
+			name:    "unknown squad",
+			user:    &models.User{ID: 99, Role: models.RoleAdmin},
+			req:     models.SprintCapacityCheckRequest{SquadID: 4, BoardID: &boardID},
+			wantErr: ErrSquadNotFound,
+		},
+		{
+			name:    "unscheduled sprint",
+			user:    &models.User{ID: 99, Role: models.RoleAdmin},
+			req:     models.SprintCapacityCheckRequest{SquadID: 3, SprintID: func() *int { id := 9; return &id }()},
+			wantErr: ErrSprintNotScheduled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slackCalls = 0
+			squadRepo := mocks.NewMockSquadRepository()
+			squadRepo.Squads[3] = &models.Squad{ID: 3, Name: "Platform"}
+			squadRepo.GetUsersBySquadIDFunc = func(ctx context.Context, squadID int64) ([]models.User, error) {
+				return testSquadMembers(), nil
+			}
+			timeOffRepo := mocks.NewMockTimeOffRepository()
+
+			cfg := &config.Config{SprintPointsPerDay: 1, SlackWebhookURL: slack.URL}
+			service := NewSprintCapacityService(squadRepo, timeOffRepo, NewSlackNotifier(cfg), cfg)
+
+			report, err := service.Check(context.Background(), tt.user, source, &tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Check() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() unexpected error: %v", err)
+			}
+			if report.Sprint.ID != 7 {
+				t.Errorf("Sprint.ID = %d, want 7", report.Sprint.ID)
+			}
+			if report.SlackNotified != tt.wantSlack || (slackCalls == 1) != tt.wantSlack {
+				t.Errorf("SlackNotified = %v with %d calls, want %v", report.SlackNotified, slackCalls, tt.wantSlack)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+const (
+	// digestCheckInterval is how often the worker checks whether digests are due
+	digestCheckInterval = 15 * time.Minute
+	// digestLookahead is how far ahead digests list absences and meeting invites
+	digestLookahead = 7 * 24 * time.Hour
+)
+
+// SupervisorDigestService emails each supervisor a daily or weekly digest of their team's time off
+// waiting for review, overdue tasks, and upcoming absences, and the meeting invites they haven't
+// answered. Digests are queued in the outbox once per supervisor and period, from the configured
+// hour on; supervisors with nothing to report aren't emailed.
+type SupervisorDigestService struct {
+	repo        repository.SupervisorDigestRepository
+	userRepo    repository.UserRepository
+	timeOffRepo repository.TimeOffRepository
+	taskRepo    repository.TaskRepository
+	meetingRepo repository.MeetingRepository
+	frequency   models.DigestFrequency
+	hour        int // Hour of the day, in UTC, digests are due from
+	// queuedPeriod is the last period every digest was queued for, so it isn't rebuilt until the next
+	queuedPeriod time.Time
+	logger       *logger.Logger
+}
+
+// NewSupervisorDigestService creates a new supervisor digest service
+func NewSupervisorDigestService(repo repository.SupervisorDigestRepository, userRepo repository.UserRepository, timeOffRepo repository.TimeOffRepository, taskRepo repository.TaskRepository, meetingRepo repository.MeetingRepository, frequency models.DigestFrequency, hour int) *SupervisorDigestService {
+	return &SupervisorDigestService{
+		repo:        repo,
+		userRepo:    userRepo,
+		timeOffRepo: timeOffRepo,
+		taskRepo:    taskRepo,
+		meetingRepo: meetingRepo,
+		frequency:   frequency,
+		hour:        hour,
+		logger:      logger.Default().WithComponent("supervisor-digest"),
+	}
+}
+
+// period returns the day, or for weekly digests the Monday, that now falls in, and whether its
+// digests are due yet
+func (s *SupervisorDigestService) period(now time.Time) (time.Time, bool) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if s.frequency == models.DigestFrequencyWeekly {
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	}
+	return start, !now.Before(start.Add(time.Duration(s.hour) * time.Hour))
+}
+
+// QueueDue queues the current period's digests once they're due and returns how many were queued.
+// Digests another instance already queued are skipped.
+func (s *SupervisorDigestService) QueueDue(ctx context.Context, now time.Time) (int, error) {
+	periodStart, due := s.period(now)
+	if !due || periodStart.Equal(s.queuedPeriod) {
+		return 0, nil
+	}
+
+	// Supervisors in every organization
+	supervisors, err := s.userRepo.GetAllSupervisors(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	queued, failed := 0, false
+	for i := range supervisors {
+		supervisor := &supervisors[i]
+		supervisorCtx := tenant.WithOrgID(ctx, supervisor.OrgID)
+
+		digest, err := s.Build(supervisorCtx, supervisor, periodStart, now)
+		if err != nil {
+			s.logger.LogError(ctx, "Failed to build supervisor digest", err, "user_id", supervisor.ID)
+			failed = true
+			continue
+		}
+		if digest.IsEmpty() {
+			continue
+		}
+
+		ok, err := s.repo.Queue(supervisorCtx, &models.SupervisorDigestEmailPayload{
+			SupervisorID: supervisor.ID,
+			To:           supervisor.Email,
+			Digest:       *digest,
+		})
+		if err != nil {
+			s.logger.LogError(ctx, "Failed to queue supervisor digest", err, "user_id", supervisor.ID)
+			failed = true
+			continue
+		}
+		if ok {
+			queued++
+		}
+	}
+
+	// Supervisors that failed are retried on the next check
+	if !failed {
+		s.queuedPeriod = periodStart
+	}
+	return queued, nil
+}
+
+// Build gathers a supervisor's digest for the period starting at periodStart
+func (s *SupervisorDigestService) Build(ctx context.Context, supervisor *models.User, periodStart, now time.Time) (*models.SupervisorDigest, error) {
+	digest := &models.SupervisorDigest{
+		SupervisorName: supervisor.FirstName,
+		Frequency:      s.frequency,
+		PeriodStart:    periodStart,
+	}
+	utc := now.UTC()
+	today := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+	horizon := now.Add(digestLookahead)
+
+	pending, err := s.timeOffRepo.GetPendingForSupervisor(ctx, supervisor.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pending {
+		digest.PendingTimeOff = append(digest.PendingTimeOff, digestTimeOff(&pending[i]))
+	}
+
+	absences, err := s.timeOffRepo.GetTeamTimeOff(ctx, supervisor.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range absences {
+		if absences[i].EndDate.Before(today) || absences[i].StartDate.After(horizon) {
+			continue
+		}
+		digest.UpcomingAbsences = append(digest.UpcomingAbsences, digestTimeOff(&absences[i]))
+	}
+
+	reports, err := s.userRepo.GetDirectReportsBySupervisorID(ctx, supervisor.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reports {
+		report := &reports[i]
+		tasks, err := s.taskRepo.List(ctx, report, models.TaskFilter{
+			AssigneeID: &report.ID,
+			Overdue:    true,
+			SortBy:     models.TaskSortDueDate,
+			SortAsc:    true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			digest.OverdueTasks = append(digest.OverdueTasks, models.DigestTask{
+				Title:        task.Title,
+				AssigneeName: report.FirstName + " " + report.LastName,
+				DueDate:      task.DueDate,
+			})
+		}
+	}
+	sort.SliceStable(digest.OverdueTasks, func(i, j int) bool {
+		return digest.OverdueTasks[i].DueDate.Before(digest.OverdueTasks[j].DueDate)
+	})
+
+	invites, err := s.unansweredInvites(ctx, supervisor.ID, now, horizon)
+	if err != nil {
+		return nil, err
+	}
+	digest.UnansweredInvites = invites
+
+	return digest, nil
+}
+
+// unansweredInvites lists meetings between now and horizon that the user was invited to by someone
+// else and hasn't responded to. A recurring series is listed once, at its next occurrence.
+func (s *SupervisorDigestService) unansweredInvites(ctx context.Context, userID int64, now, horizon time.Time) ([]models.DigestMeeting, error) {
+	meetings, err := s.meetingRepo.GetByDateRange(ctx, userID, now, horizon)
+	if err != nil {
+		return nil, err
+	}
+	occurrences := s.meetingRepo.ExpandRecurringMeetings(meetings, now, horizon)
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartTime.Before(occurrences[j].StartTime)
+	})
+
+	var invites []models.DigestMeeting
+	seen := make(map[int64]bool)
+	for _, meeting := range occurrences {
+		series := meeting.ID
+		if meeting.ParentMeetingID != nil {
+			series = *meeting.ParentMeetingID
+		}
+		if seen[series] || meeting.CreatedByID == userID || !isPendingAttendee(&meeting, userID) {
+			continue
+		}
+		seen[series] = true
+		invites = append(invites, models.DigestMeeting{
+			MeetingID: series,
+			Title:     meeting.Title,
+			StartTime: meeting.StartTime,
+		})
+	}
+	return invites, nil
+}
+
+// isPendingAttendee reports whether the user is invited to a meeting and hasn't responded
+func isPendingAttendee(meeting *models.Meeting, userID int64) bool {
+	for _, attendee := range meeting.Attendees {
+		if attendee.UserID == userID {
+			return attendee.ResponseStatus == models.ResponseStatusPending
+		}
+	}
+	return false
+}
+
+// digestTimeOff summarizes a time off request for a digest
+func digestTimeOff(request *models.TimeOffRequest) models.DigestTimeOff {
+	entry := models.DigestTimeOff{
+		RequestType: request.RequestType,
+		StartDate:   request.StartDate,
+		EndDate:     request.EndDate,
+	}
+	if request.User != nil {
+		entry.EmployeeName = request.User.FirstName + " " + request.User.LastName
+	}
+	return entry
+}
+
+// Start checks for due digests every fifteen minutes until workers are stopped
+func (s *SupervisorDigestService) Start(workers *lifecycle.Group) {
+	workers.Go("supervisor-digest", func(ctx context.Context) {
+		ticker := time.NewTicker(digestCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				queued, err := s.QueueDue(ctx, time.Now())
+				if err != nil {
+					s.logger.LogError(ctx, "Failed to queue supervisor digests", err)
+				}
+				if queued > 0 {
+					s.logger.Info("Queued supervisor digests", "count", queued, "frequency", s.frequency)
+				}
+			}
+		}
+	})
+}
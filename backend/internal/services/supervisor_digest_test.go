@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+// newDigestFixture sets up a supervisor with one report who has a pending and an approved request,
+// an overdue task, and a meeting invite the supervisor hasn't answered
+func newDigestFixture(t *testing.T, now time.Time) (*mocks.MockSupervisorDigestRepository, *mocks.MockUserRepository, *mocks.MockTimeOffRepository, *mocks.MockTaskRepository, *mocks.MockMeetingRepository) {
+	t.Helper()
+	supervisorID := int64(1)
+	userRepo := mocks.NewMockUserRepository()
+	supervisor := &models.User{ID: supervisorID, OrgID: 1, Email: "sam@example.com", FirstName: "Sam", LastName: "Lead", Role: models.RoleSupervisor, IsActive: true}
+	report := &models.User{ID: 2, OrgID: 1, Email: "ada@example.com", FirstName: "Ada", LastName: "Lovelace", Role: models.RoleEmployee, SupervisorID: &supervisorID, IsActive: true}
+	userRepo.Users[supervisor.ID] = supervisor
+	userRepo.Users[report.ID] = report
+
+	timeOffRepo := mocks.NewMockTimeOffRepository()
+	timeOffRepo.Requests[1] = &models.TimeOffRequest{ID: 1, UserID: report.ID, User: report, RequestType: models.TimeOffTypeVacation,
+		Status: models.TimeOffStatusPending, StartDate: now.AddDate(0, 1, 0), EndDate: now.AddDate(0, 1, 2)}
+	timeOffRepo.Requests[2] = &models.TimeOffRequest{ID: 2, UserID: report.ID, User: report, RequestType: models.TimeOffTypeSick,
+		Status: models.TimeOffStatusApproved, StartDate: now.AddDate(0, 0, 2), EndDate: now.AddDate(0, 0, 3)}
+	// Too far off to mention yet
+	timeOffRepo.Requests[3] = &models.TimeOffRequest{ID: 3, UserID: report.ID, User: report, RequestType: models.TimeOffTypeVacation,
+		Status: models.TimeOffStatusApproved, StartDate: now.AddDate(0, 2, 0), EndDate: now.AddDate(0, 2, 1)}
+
+	taskRepo := mocks.NewMockTaskRepository()
+	taskRepo.Tasks[1] = &models.Task{ID: 1, Title: "Ship report", Status: models.TaskStatusPending, DueDate: now.AddDate(0, 0, -3),
+		CreatedByID: supervisor.ID, AssignedUserID: &report.ID}
+	taskRepo.Tasks[2] = &models.Task{ID: 2, Title: "Done already", Status: models.TaskStatusCompleted, DueDate: now.AddDate(0, 0, -3),
+		CreatedByID: supervisor.ID, AssignedUserID: &report.ID}
+
+	meetingRepo := mocks.NewMockMeetingRepository()
+	pending := []models.MeetingAttendee{{MeetingID: 1, UserID: supervisor.ID, ResponseStatus: models.ResponseStatusPending}}
+	meetingRepo.Meetings[1] = &models.Meeting{ID: 1, Title: "Planning", CreatedByID: report.ID, Attendees: pending,
+		StartTime: now.Add(24 * time.Hour), EndTime: now.Add(25 * time.Hour)}
+	meetingRepo.AddAttendee(1, supervisor.ID, models.ResponseStatusPending)
+	accepted := []models.MeetingAttendee{{MeetingID: 2, UserID: supervisor.ID, ResponseStatus: models.ResponseStatusAccepted}}
+	meetingRepo.Meetings[2] = &models.Meeting{ID: 2, Title: "Retro", CreatedByID: report.ID, Attendees: accepted,
+		StartTime: now.Add(48 * time.Hour), EndTime: now.Add(49 * time.Hour)}
+	meetingRepo.AddAttendee(2, supervisor.ID, models.ResponseStatusAccepted)
+
+	return mocks.NewMockSupervisorDigestRepository(), userRepo, timeOffRepo, taskRepo, meetingRepo
+}
+
+func TestSupervisorDigestService_Build(t *testing.T) {
+	now := time.Date(2026, 3, 9, 14, 0, 0, 0, time.UTC)
+	repo, userRepo, timeOffRepo, taskRepo, meetingRepo := newDigestFixture(t, now)
+	service := NewSupervisorDigestService(repo, userRepo, timeOffRepo, taskRepo, meetingRepo, models.DigestFrequencyDaily, 13)
+
+	digest, err := service.Build(context.Background(), userRepo.Users[1], now, now)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if len(digest.PendingTimeOff) != 1 || digest.PendingTimeOff[0].EmployeeName != "Ada Lovelace" {
+		t.Errorf("PendingTimeOff = %+v, want Ada's vacation", digest.PendingTimeOff)
+	}
+	if len(digest.UpcomingAbsences) != 1 || digest.UpcomingAbsences[0].RequestType != models.TimeOffTypeSick {
+		t.Errorf("UpcomingAbsences = %+v, want only the sick leave this week", digest.UpcomingAbsences)
+	}
+	if len(digest.OverdueTasks) != 1 || digest.OverdueTasks[0].Title != "Ship report" {
+		t.Errorf("OverdueTasks = %+v, want only the open task", digest.OverdueTasks)
+	}
+	if len(digest.UnansweredInvites) != 1 || digest.UnansweredInvites[0].Title != "Planning" {
+		t.Errorf("UnansweredInvites = %+v, want only Planning", digest.UnansweredInvites)
+	}
+}
+
+func TestSupervisorDigestService_QueueDue(t *testing.T) {
+	// A Tuesday
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		frequency  models.DigestFrequency
+		now        time.Time
+		wantQueued int
+		wantPeriod time.Time
+	}{
+		{"daily after the hour", models.DigestFrequencyDaily, now, 1, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"daily before the hour", models.DigestFrequencyDaily, now.Add(-2 * time.Hour), 0, time.Time{}},
+		{"weekly is for Monday", models.DigestFrequencyWeekly, now, 1, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, userRepo, timeOffRepo, taskRepo, meetingRepo := newDigestFixture(t, now)
+			service := NewSupervisorDigestService(repo, userRepo, timeOffRepo, taskRepo, meetingRepo, tt.frequency, 13)
+
+			queued, err := service.QueueDue(context.Background(), tt.now)
+			if err != nil {
+				t.Fatalf("QueueDue() error = %v", err)
+			}
+			if queued != tt.wantQueued {
+				t.Fatalf("queued %d digests, want %d", queued, tt.wantQueued)
+			}
+			for _, payload := range repo.Queued {
+				if payload.To != "sam@example.com" || !payload.Digest.PeriodStart.Equal(tt.wantPeriod) {
+					t.Errorf("queued digest to %s for %v, want sam@example.com for %v", payload.To, payload.Digest.PeriodStart, tt.wantPeriod)
+				}
+			}
+
+			// Another instance has nothing left to queue for the period
+			other := NewSupervisorDigestService(repo, userRepo, timeOffRepo, taskRepo, meetingRepo, tt.frequency, 13)
+			if queued, _ := other.QueueDue(context.Background(), tt.now); queued != 0 {
+				t.Errorf("second instance queued %d digests, want 0", queued)
+			}
+		})
+	}
+}
+
+func TestSupervisorDigestService_QueueDue_SkipsEmptyDigests(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	repo := mocks.NewMockSupervisorDigestRepository()
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.Users[1] = &models.User{ID: 1, Email: "sam@example.com", Role: models.RoleSupervisor, IsActive: true}
+	service := NewSupervisorDigestService(repo, userRepo, mocks.NewMockTimeOffRepository(), mocks.NewMockTaskRepository(),
+		mocks.NewMockMeetingRepository(), models.DigestFrequencyDaily, 13)
+
+	if queued, err := service.QueueDue(context.Background(), now); err != nil || queued != 0 {
+		t.Errorf("QueueDue() = %d, %v; want 0, nil", queued, err)
+	}
+}
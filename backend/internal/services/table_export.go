@@ -0,0 +1,288 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+// ErrUnknownExportColumn is returned when a requested column isn't exported for the list
+var ErrUnknownExportColumn = errors.New("unknown column")
+
+// TableWriter streams rows of a table download
+type TableWriter interface {
+	WriteRow(cells []string) error
+	// Close finishes the file; rows written after Close are lost
+	Close() error
+}
+
+// NewTableWriter returns a writer that streams rows to w in the given format
+func NewTableWriter(w io.Writer, format models.TableFormat) (TableWriter, error) {
+	switch format {
+	case models.TableFormatCSV:
+		return &csvTableWriter{w: csv.NewWriter(w)}, nil
+	case models.TableFormatXLSX:
+		return newXLSXTableWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported table format: %s", format)
+	}
+}
+
+// TableContentType returns the MIME type of a table download format
+func TableContentType(format models.TableFormat) string {
+	if format == models.TableFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportColumn is a named column of a table download
+type ExportColumn[T any] struct {
+	Name  string
+	Value func(T) string
+}
+
+// SelectExportColumns returns the named columns in the requested order, or every column
+// when none are named
+func SelectExportColumns[T any](all []ExportColumn[T], names []string) ([]ExportColumn[T], error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	byName := make(map[string]ExportColumn[T], len(all))
+	for _, column := range all {
+		byName[column.Name] = column
+	}
+
+	selected := make([]ExportColumn[T], 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		column, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownExportColumn, name)
+		}
+		seen[name] = true
+		selected = append(selected, column)
+	}
+	if len(selected) == 0 {
+		return all, nil
+	}
+	return selected, nil
+}
+
+// WriteTable writes a header row and one row per item, then closes the writer
+func WriteTable[T any](tw TableWriter, columns []ExportColumn[T], rows []T) error {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+	if err := tw.WriteRow(header); err != nil {
+		return err
+	}
+
+	cells := make([]string, len(columns))
+	for _, row := range rows {
+		for i, column := range columns {
+			cells[i] = column.Value(row)
+		}
+		if err := tw.WriteRow(cells); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// csvTableWriter writes CSV, neutralizing cells a spreadsheet would evaluate as formulas
+type csvTableWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvTableWriter) WriteRow(cells []string) error {
+	row := make([]string, len(cells))
+	for i, cell := range cells {
+		row[i] = neutralizeFormula(cell)
+	}
+	return c.w.Write(row)
+}
+
+func (c *csvTableWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// xlsxParts are the fixed parts of a single-sheet workbook, written before the sheet itself
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// xlsxTableWriter streams a single-sheet workbook of inline strings. Inline strings are never
+// evaluated, so cells need no formula neutralization.
+type xlsxTableWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+	buf   bytes.Buffer
+}
+
+func newXLSXTableWriter(w io.Writer) (*xlsxTableWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write sheet: %w", err)
+	}
+	if _, err := io.WriteString(sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, fmt.Errorf("failed to write sheet: %w", err)
+	}
+	return &xlsxTableWriter{zw: zw, sheet: sheet}, nil
+}
+
+func (x *xlsxTableWriter) WriteRow(cells []string) error {
+	x.row++
+	x.buf.Reset()
+	fmt.Fprintf(&x.buf, `<row r="%d">`, x.row)
+	for i, cell := range cells {
+		fmt.Fprintf(&x.buf, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, xlsxColumnName(i), x.row)
+		if err := xml.EscapeText(&x.buf, []byte(cell)); err != nil {
+			return err
+		}
+		x.buf.WriteString(`</t></is></c>`)
+	}
+	x.buf.WriteString(`</row>`)
+	_, err := x.sheet.Write(x.buf.Bytes())
+	return err
+}
+
+func (x *xlsxTableWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+// xlsxColumnName returns the spreadsheet letters of a zero-based column index (A, B, ..., Z, AA, ...)
+func xlsxColumnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatOptionalTimestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatTimestamp(*t)
+}
+
+// UserExportColumns are the columns of the user and employee list downloads
+var UserExportColumns = []ExportColumn[models.UserResponse]{
+	{"id", func(u models.UserResponse) string { return strconv.FormatInt(u.ID, 10) }},
+	{"email", func(u models.UserResponse) string { return u.Email }},
+	{"first_name", func(u models.UserResponse) string { return u.FirstName }},
+	{"last_name", func(u models.UserResponse) string { return u.LastName }},
+	{"role", func(u models.UserResponse) string { return string(u.Role) }},
+	{"title", func(u models.UserResponse) string { return u.Title }},
+	{"department", func(u models.UserResponse) string { return u.Department }},
+	{"squads", func(u models.UserResponse) string {
+		names := make([]string, len(u.Squads))
+		for i, squad := range u.Squads {
+			names[i] = squad.Name
+		}
+		return strings.Join(names, "; ")
+	}},
+	{"supervisor_id", func(u models.UserResponse) string { return formatOptionalID(u.SupervisorID) }},
+	{"date_started", func(u models.UserResponse) string { return formatOptionalDate(u.DateStarted) }},
+	{"is_active", func(u models.UserResponse) string { return strconv.FormatBool(u.IsActive) }},
+	{"timezone", func(u models.UserResponse) string { return u.Timezone }},
+	{"created_at", func(u models.UserResponse) string { return formatTimestamp(u.CreatedAt) }},
+}
+
+// SquadExportColumns are the columns of the squad list download
+var SquadExportColumns = []ExportColumn[models.Squad]{
+	{"id", func(s models.Squad) string { return strconv.FormatInt(s.ID, 10) }},
+	{"name", func(s models.Squad) string { return s.Name }},
+	{"lead_id", func(s models.Squad) string { return formatOptionalID(s.LeadID) }},
+	{"created_at", func(s models.Squad) string { return formatTimestamp(s.CreatedAt) }},
+}
+
+// TimeOffExportColumns are the columns of the time off list downloads
+var TimeOffExportColumns = []ExportColumn[models.TimeOffRequest]{
+	{"id", func(t models.TimeOffRequest) string { return strconv.FormatInt(t.ID, 10) }},
+	{"user_id", func(t models.TimeOffRequest) string { return strconv.FormatInt(t.UserID, 10) }},
+	{"user_name", func(t models.TimeOffRequest) string {
+		if t.User == nil {
+			return ""
+		}
+		return strings.TrimSpace(t.User.FirstName + " " + t.User.LastName)
+	}},
+	{"start_date", func(t models.TimeOffRequest) string { return t.StartDate.Format("2006-01-02") }},
+	{"end_date", func(t models.TimeOffRequest) string { return t.EndDate.Format("2006-01-02") }},
+	{"request_type", func(t models.TimeOffRequest) string { return string(t.RequestType) }},
+	{"status", func(t models.TimeOffRequest) string { return string(t.Status) }},
+	{"reason", func(t models.TimeOffRequest) string { return derefString(t.Reason) }},
+	{"reviewer_id", func(t models.TimeOffRequest) string { return formatOptionalID(t.ReviewerID) }},
+	{"reviewer_notes", func(t models.TimeOffRequest) string { return derefString(t.ReviewerNotes) }},
+	{"reviewed_at", func(t models.TimeOffRequest) string { return formatOptionalTimestamp(t.ReviewedAt) }},
+	{"created_at", func(t models.TimeOffRequest) string { return formatTimestamp(t.CreatedAt) }},
+}
+
+// TaskExportColumns are the columns of the task list download
+var TaskExportColumns = []ExportColumn[models.Task]{
+	{"id", func(t models.Task) string { return strconv.FormatInt(t.ID, 10) }},
+	{"title", func(t models.Task) string { return t.Title }},
+	{"status", func(t models.Task) string { return string(t.Status) }},
+	{"priority", func(t models.Task) string { return string(t.Priority) }},
+	{"labels", func(t models.Task) string { return strings.Join(t.Labels, "; ") }},
+	{"due_date", func(t models.Task) string { return t.DueDate.Format("2006-01-02") }},
+	{"assignment_type", func(t models.Task) string { return string(t.AssignmentType) }},
+	{"assigned_user_id", func(t models.Task) string { return formatOptionalID(t.AssignedUserID) }},
+	{"assigned_squad_id", func(t models.Task) string { return formatOptionalID(t.AssignedSquadID) }},
+	{"assigned_department", func(t models.Task) string { return derefString(t.AssignedDepartment) }},
+	{"created_by_id", func(t models.Task) string { return strconv.FormatInt(t.CreatedByID, 10) }},
+	{"created_at", func(t models.Task) string { return formatTimestamp(t.CreatedAt) }},
+}
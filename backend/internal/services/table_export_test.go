@@ -0,0 +1,122 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+var testExportColumns = []ExportColumn[models.Squad]{
+	{"id", func(s models.Squad) string { return "1" }},
+	{"name", func(s models.Squad) string { return s.Name }},
+}
+
+func TestSelectExportColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "defaults to all", names: nil, want: []string{"id", "name"}},
+		{name: "requested order", names: []string{"name", " id"}, want: []string{"name", "id"}},
+		{name: "duplicates and blanks are dropped", names: []string{"name", "", "name"}, want: []string{"name"}},
+		{name: "unknown column", names: []string{"id", "lead"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := SelectExportColumns(testExportColumns, tt.names)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownExportColumn) {
+					t.Fatalf("SelectExportColumns() error = %v, want ErrUnknownExportColumn", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectExportColumns() error = %v", err)
+			}
+			got := make([]string, len(columns))
+			for i, column := range columns {
+				got[i] = column.Name
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("columns = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTable_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	tw, err := NewTableWriter(&buf, models.TableFormatCSV)
+	if err != nil {
+		t.Fatalf("NewTableWriter() error = %v", err)
+	}
+	rows := []models.Squad{{Name: "Platform, Core"}, {Name: "@evil"}}
+	if err := WriteTable(tw, testExportColumns, rows); err != nil {
+		t.Fatalf("WriteTable() error = %v", err)
+	}
+
+	want := "id,name\n1,\"Platform, Core\"\n1,'@evil\n"
+	if buf.String() != want {
+		t.Errorf("csv = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteTable_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	tw, err := NewTableWriter(&buf, models.TableFormatXLSX)
+	if err != nil {
+		t.Fatalf("NewTableWriter() error = %v", err)
+	}
+	rows := []models.Squad{{Name: "R&D <core>"}}
+	if err := WriteTable(tw, testExportColumns, rows); err != nil {
+		t.Fatalf("WriteTable() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("workbook is not a zip: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("workbook is missing %s", name)
+		}
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`,
+		`<c r="B2" t="inlineStr"><is><t xml:space="preserve">R&amp;D &lt;core&gt;</t></is></c>`,
+		`</sheetData></worksheet>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet is missing %s:\n%s", want, sheet)
+		}
+	}
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for i, want := range tests {
+		if got := xlsxColumnName(i); got != want {
+			t.Errorf("xlsxColumnName(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
@@ -2,16 +2,44 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
 	"github.com/smith-dallin/manager-dashboard/internal/models"
 	"github.com/smith-dallin/manager-dashboard/internal/repository"
 	"github.com/smith-dallin/manager-dashboard/internal/sanitize"
 )
 
+// ErrInvalidCustomField is returned when a user update sets an unknown or invalid custom field value
+var ErrInvalidCustomField = errors.New("invalid custom field")
+
+var (
+	// ErrInvalidSupervisor is returned when a user can't report to the chosen supervisor
+	ErrInvalidSupervisor = errors.New("invalid supervisor")
+	// ErrSupervisorCycle is returned when a reassignment would make a user report to themselves,
+	// directly or through their reports
+	ErrSupervisorCycle = errors.New("supervisor cycle")
+)
+
+// SupervisorReassignment is the result of moving a user to a new supervisor
+type SupervisorReassignment struct {
+	User *models.User
+	// PreviousSupervisorID is who the user reported to before, if anyone
+	PreviousSupervisorID *int64
+	// Supervisor is who the user reports to now, if anyone
+	Supervisor *models.User
+	// Warnings describe things about the move worth double-checking that didn't stop it
+	Warnings []string
+}
+
 // UserService handles user-related business logic
 type UserService struct {
-	userRepo  repository.UserRepository
-	squadRepo repository.SquadRepository
+	userRepo        repository.UserRepository
+	squadRepo       repository.SquadRepository
+	customFieldRepo repository.CustomFieldRepository
+	historyRepo     repository.UserHistoryRepository
+	logger          *logger.Logger
 }
 
 // NewUserService creates a new user service
@@ -19,9 +47,22 @@ func NewUserService(userRepo repository.UserRepository, squadRepo repository.Squ
 	return &UserService{
 		userRepo:  userRepo,
 		squadRepo: squadRepo,
+		logger:    logger.Default().WithComponent("user-service"),
 	}
 }
 
+// WithHistory records changes made through Update in users' employment history
+func (s *UserService) WithHistory(historyRepo repository.UserHistoryRepository) *UserService {
+	s.historyRepo = historyRepo
+	return s
+}
+
+// WithCustomFields loads users' custom field values and lets updates set them
+func (s *UserService) WithCustomFields(customFieldRepo repository.CustomFieldRepository) *UserService {
+	s.customFieldRepo = customFieldRepo
+	return s
+}
+
 // GetByID retrieves a user by ID with squads loaded
 func (s *UserService) GetByID(ctx context.Context, id int64) (*models.User, error) {
 	user, err := s.userRepo.GetByID(ctx, id)
@@ -35,6 +76,7 @@ func (s *UserService) GetByID(ctx context.Context, id int64) (*models.User, erro
 		squads = []models.Squad{}
 	}
 	user.Squads = squads
+	s.loadCustomFieldsForUsers(ctx, []*models.User{user})
 
 	return user, nil
 }
@@ -49,6 +91,21 @@ func (s *UserService) GetAll(ctx context.Context) ([]models.User, error) {
 	return s.loadSquadsForUsers(ctx, users)
 }
 
+// Search retrieves a page of users matching the filter with squads loaded, along with the total
+// number of matches
+func (s *UserService) Search(ctx context.Context, filter models.UserSearchFilter, limit, offset int) ([]models.User, int, error) {
+	users, total, err := s.userRepo.Search(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users, err = s.loadSquadsForUsers(ctx, users)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
 // GetDirectReports retrieves direct reports for a supervisor with squads loaded
 func (s *UserService) GetDirectReports(ctx context.Context, supervisorID int64) ([]models.User, error) {
 	users, err := s.userRepo.GetDirectReportsBySupervisorID(ctx, supervisorID)
@@ -68,7 +125,7 @@ func (s *UserService) GetEmployeesForUser(ctx context.Context, currentUser *mode
 	var err error
 
 	switch currentUser.Role {
-	case models.RoleAdmin:
+	case models.RoleAdmin, models.RoleViewer:
 		users, err = s.userRepo.GetAll(ctx)
 	case models.RoleSupervisor:
 		users, err = s.userRepo.GetDirectReportsBySupervisorID(ctx, currentUser.ID)
@@ -117,22 +174,80 @@ func (s *UserService) loadSquadsForUsers(ctx context.Context, users []models.Use
 		}
 	}
 
+	ptrs := make([]*models.User, len(users))
+	for i := range users {
+		ptrs[i] = &users[i]
+	}
+	s.loadCustomFieldsForUsers(ctx, ptrs)
+
 	return users, nil
 }
 
-// Update updates a user and optionally their squad assignments
-func (s *UserService) Update(ctx context.Context, id int64, req *models.UpdateUserRequest) (*models.User, error) {
+// loadCustomFieldsForUsers batch loads custom field values when custom fields are configured.
+// Like squads, a failed load leaves the users without values rather than failing the request.
+func (s *UserService) loadCustomFieldsForUsers(ctx context.Context, users []*models.User) {
+	if s.customFieldRepo == nil || len(users) == 0 {
+		return
+	}
+
+	userIDs := make([]int64, len(users))
+	for i, u := range users {
+		userIDs[i] = u.ID
+	}
+	values, err := s.customFieldRepo.GetValuesForUsers(ctx, userIDs)
+	if err != nil {
+		return
+	}
+	for _, u := range users {
+		u.CustomFields = values[u.ID]
+	}
+}
+
+// Update updates a user and optionally their squad assignments on behalf of changedByID
+func (s *UserService) Update(ctx context.Context, id int64, req *models.UpdateUserRequest, changedByID int64) (*models.User, error) {
 	// Sanitize department field if provided
 	if req.Department != nil {
 		sanitized := sanitize.Name(*req.Department, 100)
 		req.Department = &sanitized
 	}
 
+	// Check custom field values before changing anything
+	var customValues map[int64]string
+	if len(req.CustomFields) > 0 {
+		if s.customFieldRepo == nil {
+			return nil, fmt.Errorf("%w: custom fields are not configured", ErrInvalidCustomField)
+		}
+		fields, err := s.customFieldRepo.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if customValues, err = models.ValidateCustomFieldValues(fields, req.CustomFields); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCustomField, err)
+		}
+	}
+
+	// Keep a copy of the user as they were so history can record what changed
+	var before *models.User
+	if s.historyRepo != nil {
+		current, err := s.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		snapshot := *current
+		before = &snapshot
+	}
+
 	user, err := s.userRepo.Update(ctx, id, req)
 	if err != nil {
 		return nil, err
 	}
 
+	if customValues != nil {
+		if err := s.customFieldRepo.SetUserValues(ctx, id, customValues); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update squads if provided
 	if req.SquadIDs != nil {
 		if err := s.squadRepo.SetUserSquads(ctx, id, req.SquadIDs); err != nil {
@@ -146,6 +261,88 @@ func (s *UserService) Update(ctx context.Context, id int64, req *models.UpdateUs
 		squads = []models.Squad{}
 	}
 	user.Squads = squads
+	s.loadCustomFieldsForUsers(ctx, []*models.User{user})
+
+	if before != nil {
+		s.recordHistory(ctx, before, user, req.SquadIDs != nil, changedByID)
+	}
 
 	return user, nil
 }
+
+// ReassignSupervisor moves a user to a new supervisor on behalf of changedByID, or leaves them
+// without one if supervisorID is nil. The supervisor must be an active supervisor or admin in the
+// organization, and can't be the user or anyone who reports to them. Moving someone to a
+// supervisor in another department is allowed but comes back as a warning.
+func (s *UserService) ReassignSupervisor(ctx context.Context, id int64, supervisorID *int64, changedByID int64) (*SupervisorReassignment, error) {
+	before, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SupervisorReassignment{PreviousSupervisorID: before.SupervisorID, Warnings: []string{}}
+	if supervisorID != nil {
+		if result.Supervisor, err = s.checkSupervisor(ctx, before, *supervisorID); err != nil {
+			return nil, err
+		}
+		if result.Supervisor.Department != "" && before.Department != "" && result.Supervisor.Department != before.Department {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s %s is in %s but %s %s is in %s",
+				before.FirstName, before.LastName, before.Department,
+				result.Supervisor.FirstName, result.Supervisor.LastName, result.Supervisor.Department))
+		}
+	}
+
+	snapshot := *before
+	user, err := s.userRepo.SetSupervisor(ctx, id, supervisorID)
+	if err != nil {
+		return nil, err
+	}
+	user.Squads = before.Squads
+	user.CustomFields = before.CustomFields
+	result.User = user
+
+	if s.historyRepo != nil {
+		s.recordHistory(ctx, &snapshot, user, false, changedByID)
+	}
+	return result, nil
+}
+
+// checkSupervisor loads the supervisor a user would report to, returning ErrInvalidSupervisor if
+// they can't supervise and ErrSupervisorCycle if they already report to the user
+func (s *UserService) checkSupervisor(ctx context.Context, user *models.User, supervisorID int64) (*models.User, error) {
+	if supervisorID == user.ID {
+		return nil, fmt.Errorf("%w: users can't report to themselves", ErrInvalidSupervisor)
+	}
+	supervisor, err := s.userRepo.GetByID(ctx, supervisorID)
+	if err != nil || supervisor == nil || !supervisor.IsActive || !supervisor.IsSupervisorOrAdmin() {
+		return nil, fmt.Errorf("%w: supervisor must be an active supervisor or admin in your organization", ErrInvalidSupervisor)
+	}
+
+	// Walk up the new supervisor's reporting chain looking for the user
+	seen := map[int64]bool{}
+	for next := supervisor; next.SupervisorID != nil && !seen[next.ID]; {
+		seen[next.ID] = true
+		if *next.SupervisorID == user.ID {
+			return nil, fmt.Errorf("%w: %s %s already reports to %s %s, directly or indirectly",
+				ErrSupervisorCycle, supervisor.FirstName, supervisor.LastName, user.FirstName, user.LastName)
+		}
+		if next, err = s.userRepo.GetByID(ctx, *next.SupervisorID); err != nil || next == nil {
+			break
+		}
+	}
+	return supervisor, nil
+}
+
+// recordHistory records what an update changed. The update has already been saved, so a failure
+// is logged rather than returned.
+func (s *UserService) recordHistory(ctx context.Context, before, after *models.User, squadsChanged bool, changedByID int64) {
+	history := models.DiffUserHistory(before, after)
+	if squadsChanged {
+		if entry := models.SquadHistoryEntry(after.ID, before.SquadIDs(), after.SquadIDs()); entry != nil {
+			history = append(history, *entry)
+		}
+	}
+	if err := s.historyRepo.Record(ctx, models.UserHistorySourceProfile, &changedByID, history); err != nil {
+		s.logger.LogError(ctx, "Failed to record user history", err, "user_id", after.ID)
+	}
+}
@@ -366,7 +366,7 @@ func TestUserService_Update(t *testing.T) {
 			tt.setupMocks(userRepo, squadRepo)
 
 			svc := NewUserService(userRepo, squadRepo)
-			user, err := svc.Update(context.Background(), tt.userID, tt.req)
+			user, err := svc.Update(context.Background(), tt.userID, tt.req, 1)
 
 			if tt.wantErr {
 				if err == nil {
@@ -451,3 +451,111 @@ func TestUserService_loadSquadsForUsers(t *testing.T) {
 		}
 	})
 }
+
+// Listing users must load every user's squads in one batch query rather than one query per user
+func TestUserService_ListingsBatchLoadSquads(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	squadRepo := mocks.NewMockSquadRepository()
+	admin := &models.User{ID: 1, Role: models.RoleAdmin}
+	userRepo.AddUser(admin)
+	for id := int64(2); id <= 50; id++ {
+		userRepo.AddUser(&models.User{ID: id, Role: models.RoleEmployee, SupervisorID: &admin.ID})
+	}
+
+	var batchCalls, singleCalls int
+	squadRepo.GetByUserIDFunc = func(ctx context.Context, userID int64) ([]models.Squad, error) {
+		singleCalls++
+		return nil, nil
+	}
+	squadRepo.GetByUserIDsFunc = func(ctx context.Context, userIDs []int64) (map[int64][]models.Squad, error) {
+		batchCalls++
+		return map[int64][]models.Squad{7: {{ID: 1, Name: "Engineering"}}}, nil
+	}
+	svc := NewUserService(userRepo, squadRepo)
+
+	listings := map[string]func() ([]models.User, error){
+		"GetAll":              func() ([]models.User, error) { return svc.GetAll(context.Background()) },
+		"GetEmployeesForUser": func() ([]models.User, error) { return svc.GetEmployeesForUser(context.Background(), admin) },
+		"GetDirectReports":    func() ([]models.User, error) { return svc.GetDirectReports(context.Background(), admin.ID) },
+	}
+	for name, list := range listings {
+		batchCalls, singleCalls = 0, 0
+		users, err := list()
+		if err != nil {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+		if batchCalls != 1 || singleCalls != 0 {
+			t.Errorf("%s() made %d batch and %d per-user squad queries, want 1 and 0", name, batchCalls, singleCalls)
+		}
+		for _, user := range users {
+			if user.ID == 7 && len(user.Squads) != 1 {
+				t.Errorf("%s() user 7 squads = %v, want Engineering", name, user.Squads)
+			}
+		}
+	}
+}
+
+func TestUserService_ReassignSupervisor(t *testing.T) {
+	id := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name         string
+		userID       int64
+		supervisorID *int64
+		wantErr      error
+		wantWarnings int
+	}{
+		{name: "moves to a supervisor in the same department", userID: 4, supervisorID: id(1)},
+		{name: "warns about another department", userID: 4, supervisorID: id(3), wantWarnings: 1},
+		{name: "clears the supervisor", userID: 4, supervisorID: nil},
+		{name: "rejects the user themselves", userID: 2, supervisorID: id(2), wantErr: ErrInvalidSupervisor},
+		{name: "rejects an employee", userID: 4, supervisorID: id(5), wantErr: ErrInvalidSupervisor},
+		{name: "rejects an inactive supervisor", userID: 4, supervisorID: id(6), wantErr: ErrInvalidSupervisor},
+		{name: "rejects a missing supervisor", userID: 4, supervisorID: id(99), wantErr: ErrInvalidSupervisor},
+		{name: "rejects a direct report", userID: 2, supervisorID: id(3), wantErr: ErrSupervisorCycle},
+		{name: "rejects an indirect report", userID: 1, supervisorID: id(3), wantErr: ErrSupervisorCycle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewMockUserRepository()
+			userRepo.AddUser(&models.User{ID: 1, FirstName: "Ada", Role: models.RoleAdmin, Department: "Engineering", IsActive: true})
+			userRepo.AddUser(&models.User{ID: 2, FirstName: "Sam", Role: models.RoleSupervisor, Department: "Engineering", SupervisorID: id(1), IsActive: true})
+			userRepo.AddUser(&models.User{ID: 3, FirstName: "Kim", Role: models.RoleSupervisor, Department: "Sales", SupervisorID: id(2), IsActive: true})
+			userRepo.AddUser(&models.User{ID: 4, FirstName: "Lee", Role: models.RoleEmployee, Department: "Engineering", SupervisorID: id(2), IsActive: true})
+			userRepo.AddUser(&models.User{ID: 5, FirstName: "Max", Role: models.RoleEmployee, IsActive: true})
+			userRepo.AddUser(&models.User{ID: 6, FirstName: "Pat", Role: models.RoleSupervisor, IsActive: false})
+			historyRepo := mocks.NewMockUserHistoryRepository()
+			svc := NewUserService(userRepo, mocks.NewMockSquadRepository()).WithHistory(historyRepo)
+			previous := userRepo.Users[tt.userID].SupervisorID
+
+			result, err := svc.ReassignSupervisor(context.Background(), tt.userID, tt.supervisorID, 1)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ReassignSupervisor() error = %v, want %v", err, tt.wantErr)
+				}
+				if got := userRepo.Users[tt.userID].SupervisorID; got != previous {
+					t.Errorf("supervisor changed to %v after a rejected reassignment", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReassignSupervisor() unexpected error: %v", err)
+			}
+
+			if (result.User.SupervisorID == nil) != (tt.supervisorID == nil) ||
+				(tt.supervisorID != nil && *result.User.SupervisorID != *tt.supervisorID) {
+				t.Errorf("ReassignSupervisor() supervisor = %v, want %v", result.User.SupervisorID, tt.supervisorID)
+			}
+			if result.PreviousSupervisorID == nil || *result.PreviousSupervisorID != 2 {
+				t.Errorf("ReassignSupervisor() previous supervisor = %v, want 2", result.PreviousSupervisorID)
+			}
+			if len(result.Warnings) != tt.wantWarnings {
+				t.Errorf("ReassignSupervisor() warnings = %v, want %d", result.Warnings, tt.wantWarnings)
+			}
+			if len(historyRepo.Entries) != 1 || historyRepo.Entries[0].Field != models.UserHistorySupervisor {
+				t.Errorf("history = %+v, want one supervisor change", historyRepo.Entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+const (
+	// webhookPollInterval is how often the worker checks for deliveries queued by other instances or due for a retry
+	webhookPollInterval = 10 * time.Second
+	// webhookStaleAfter is how long a delivery may stay sending before another worker retries it
+	webhookStaleAfter = 5 * time.Minute
+	// webhookPurgeInterval is how often old deliveries are removed from the log
+	webhookPurgeInterval = time.Hour
+	// webhookRetention is how long finished deliveries stay in the log
+	webhookRetention = 30 * 24 * time.Hour
+	// webhookRetryBase and webhookRetryMax bound the exponential backoff between attempts
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = 6 * time.Hour
+	// webhookTimeout caps how long an endpoint has to respond
+	webhookTimeout = 10 * time.Second
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// SignWebhookPayload returns the signature header value for a delivery body.
+// Receivers verify it by computing HMAC-SHA256 of "<timestamp>.<body>" with the endpoint secret.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// errWebhookAddressNotAllowed is returned for deliveries to addresses inside the network
+var errWebhookAddressNotAllowed = errors.New("address is not allowed")
+
+// newWebhookClient returns the client deliveries are sent with. Admins choose endpoint URLs, so it
+// only connects to addresses allowed accepts. They are checked as each connection is made, after
+// DNS resolution, so a public name can't be pointed at an internal address. Redirects aren't
+// followed, since they could lead anywhere; they are reported as failed deliveries.
+func newWebhookClient(allowed func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !allowed(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", errWebhookAddressNotAllowed, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would make the connection instead, out of reach of the check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// webhookRetryDelay returns how long to wait after the given number of failed attempts
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMax {
+			return webhookRetryMax
+		}
+	}
+	return delay
+}
+
+// WebhookService delivers lifecycle events to registered webhook endpoints in a background worker,
+// retrying failures with exponential backoff. Repositories queue the deliveries in the transaction
+// making the change, so no event is lost or sent for a change that rolled back.
+type WebhookService struct {
+	repo       repository.WebhookRepository
+	httpClient *http.Client
+	wake       chan struct{}
+	logger     *logger.Logger
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo repository.WebhookRepository) *WebhookService {
+	return &WebhookService{
+		repo:       repo,
+		httpClient: newWebhookClient(models.IsPublicWebhookAddress),
+		wake:       make(chan struct{}, 1),
+		logger:     logger.Default().WithComponent("webhook-service"),
+	}
+}
+
+// Start runs the delivery worker until workers are stopped. Deliveries are claimed from the
+// database, so any number of instances can run a worker. Events published on the broker wake the
+// worker so changes made on this instance are delivered without waiting for the next poll; events
+// the broker drops are still delivered then.
+func (s *WebhookService) Start(workers *lifecycle.Group, broker *events.Broker) {
+	if broker != nil {
+		sub, unsubscribe := broker.Subscribe()
+		workers.Go("webhook-events", func(ctx context.Context) {
+			defer unsubscribe()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event, ok := <-sub:
+					if !ok {
+						return
+					}
+					if models.ValidWebhookEventTypes[string(event.Type)] {
+						s.wakeWorker()
+					}
+				}
+			}
+		})
+	}
+
+	workers.Go("webhook-deliveries", func(ctx context.Context) {
+		poll := time.NewTicker(webhookPollInterval)
+		defer poll.Stop()
+		purge := time.NewTicker(webhookPurgeInterval)
+		defer purge.Stop()
+
+		for {
+			s.ProcessDue(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.wake:
+			case <-poll.C:
+			case <-purge.C:
+				s.PurgeOld(ctx)
+			}
+		}
+	})
+}
+
+// SendTest queues a webhook.test event for an endpoint, so admins can check it receives and verifies
+// deliveries. It's delivered, retried, and logged like any other event.
+func (s *WebhookService) SendTest(ctx context.Context, endpoint *models.WebhookEndpoint) (*models.WebhookDelivery, error) {
+	body, err := json.Marshal(events.Event{
+		Type:       models.WebhookTestEventType,
+		Payload:    map[string]int64{"webhook_id": endpoint.ID},
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode test event: %w", err)
+	}
+
+	delivery, err := s.repo.EnqueueForEndpoint(ctx, endpoint.ID, models.WebhookTestEventType, body)
+	if err != nil {
+		return nil, err
+	}
+	s.wakeWorker()
+	return delivery, nil
+}
+
+// wakeWorker has the worker send queued deliveries now instead of at its next poll
+func (s *WebhookService) wakeWorker() {
+	select {
+	case s.wake <- struct{}{}:
+	default: // The worker already has a wake-up pending
+	}
+}
+
+// ProcessDue sends deliveries until none are due and returns how many were attempted
+func (s *WebhookService) ProcessDue(ctx context.Context) int {
+	processed := 0
+	for ctx.Err() == nil {
+		delivery, err := s.repo.ClaimNextDelivery(ctx, webhookStaleAfter)
+		if err != nil {
+			s.logger.LogError(ctx, "Failed to claim webhook delivery", err)
+			return processed
+		}
+		if delivery == nil {
+			return processed
+		}
+		s.deliver(ctx, delivery)
+		processed++
+	}
+	return processed
+}
+
+// deliver sends a claimed delivery and records the outcome
+func (s *WebhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	endpoint, err := s.repo.GetEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to load webhook endpoint", err, "endpoint_id", delivery.EndpointID)
+		return // Retried once the claim goes stale
+	}
+	if endpoint == nil || !endpoint.IsActive {
+		s.recordFailure(ctx, delivery, nil, "endpoint is disabled", true)
+		return
+	}
+
+	status, err := s.send(ctx, endpoint, delivery)
+	// Leave deliveries interrupted by shutdown sending so they are retried once stale
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		s.recordFailure(ctx, delivery, status, err.Error(), delivery.Attempts >= models.MaxWebhookAttempts)
+		return
+	}
+	if err := s.repo.MarkDelivered(ctx, delivery.ID, *status); err != nil {
+		s.logger.LogError(ctx, "Failed to record webhook delivery", err, "delivery_id", delivery.ID)
+	}
+}
+
+// send posts the delivery to the endpoint. The response status is returned whenever one was
+// received. The response body is never read, so the delivery log can't reveal what a service
+// answered.
+func (s *WebhookService) send(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) (*int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	status := resp.StatusCode
+	if status < 200 || status >= 300 {
+		return &status, fmt.Errorf("endpoint responded with status %d", status)
+	}
+	return &status, nil
+}
+
+// recordFailure schedules the next attempt, or gives up on the delivery if final is set
+func (s *WebhookService) recordFailure(ctx context.Context, delivery *models.WebhookDelivery, status *int, message string, final bool) {
+	var err error
+	if final {
+		err = s.repo.MarkFailed(ctx, delivery.ID, status, message)
+	} else {
+		err = s.repo.ScheduleRetry(ctx, delivery.ID, status, message, time.Now().Add(webhookRetryDelay(delivery.Attempts)))
+	}
+	if err != nil {
+		s.logger.LogError(ctx, "Failed to record webhook delivery failure", err, "delivery_id", delivery.ID)
+	}
+}
+
+// PurgeOld removes finished deliveries past the retention period from the log
+func (s *WebhookService) PurgeOld(ctx context.Context) {
+	if _, err := s.repo.PurgeDeliveries(ctx, time.Now().Add(-webhookRetention)); err != nil {
+		s.logger.LogError(ctx, "Failed to purge webhook deliveries", err)
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/events"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+	"github.com/smith-dallin/manager-dashboard/internal/tenant"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"type":"task.created"}`)
+	signature := SignWebhookPayload("whsec_test", 1700000000, body)
+
+	if signature != SignWebhookPayload("whsec_test", 1700000000, body) {
+		t.Error("signature isn't deterministic")
+	}
+	for name, other := range map[string]string{
+		"secret":    SignWebhookPayload("whsec_other", 1700000000, body),
+		"timestamp": SignWebhookPayload("whsec_test", 1700000001, body),
+		"body":      SignWebhookPayload("whsec_test", 1700000000, []byte(`{"type":"task.deleted"}`)),
+	} {
+		if other == signature {
+			t.Errorf("changing the %s didn't change the signature", name)
+		}
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{20, webhookRetryMax},
+	}
+	for _, tt := range tests {
+		if got := webhookRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("webhookRetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// queueWebhookEvent queues an event in organization 1 the way repositories do when they commit a change
+func queueWebhookEvent(t *testing.T, repo *mocks.MockWebhookRepository, eventType events.Type, payload any) {
+	t.Helper()
+	body, err := json.Marshal(events.Event{Type: eventType, Payload: payload, OccurredAt: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("failed to encode event: %v", err)
+	}
+	repo.Enqueue(tenant.WithOrgID(context.Background(), 1), string(eventType), body)
+}
+
+// newLocalWebhookService creates a webhook service that may deliver to test servers on loopback
+func newLocalWebhookService(repo *mocks.MockWebhookRepository) *WebhookService {
+	service := NewWebhookService(repo)
+	service.httpClient = newWebhookClient(func(netip.Addr) bool { return true })
+	return service
+}
+
+func TestWebhookService_ProcessDue(t *testing.T) {
+	tests := []struct {
+		name           string
+		responseStatus int
+		priorAttempts  int
+		wantStatus     models.WebhookDeliveryStatus
+	}{
+		{"delivered on success", http.StatusNoContent, 0, models.WebhookDeliveryStatusDelivered},
+		{"retried on failure", http.StatusInternalServerError, 0, models.WebhookDeliveryStatusPending},
+		{"failed after the last attempt", http.StatusBadGateway, models.MaxWebhookAttempts - 1, models.WebhookDeliveryStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *http.Request
+			var receivedBody []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				receivedBody, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.responseStatus)
+			}))
+			defer server.Close()
+
+			repo := mocks.NewMockWebhookRepository()
+			repo.AddEndpoint(&models.WebhookEndpoint{ID: 1, URL: server.URL, Secret: "whsec_test", IsActive: true, OrgID: 1})
+			service := newLocalWebhookService(repo)
+			queueWebhookEvent(t, repo, events.TaskUpdated, map[string]int{"id": 7})
+			repo.Deliveries[1].Attempts = tt.priorAttempts
+
+			if processed := service.ProcessDue(context.Background()); processed != 1 {
+				t.Fatalf("ProcessDue() = %d, want 1", processed)
+			}
+
+			delivery := repo.Deliveries[1]
+			if delivery.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", delivery.Status, tt.wantStatus)
+			}
+			if delivery.ResponseStatus == nil || *delivery.ResponseStatus != tt.responseStatus {
+				t.Errorf("response status = %v, want %d", delivery.ResponseStatus, tt.responseStatus)
+			}
+			if tt.wantStatus == models.WebhookDeliveryStatusPending && !delivery.NextAttemptAt.After(time.Now()) {
+				t.Errorf("next attempt at %v, want a delay", delivery.NextAttemptAt)
+			}
+
+			if received.Header.Get(WebhookEventHeader) != "task.updated" || received.Header.Get(WebhookDeliveryHeader) != "1" {
+				t.Errorf("headers = %v", received.Header)
+			}
+			timestamp, _ := strconv.ParseInt(received.Header.Get(WebhookTimestampHeader), 10, 64)
+			if got := received.Header.Get(WebhookSignatureHeader); got != SignWebhookPayload("whsec_test", timestamp, receivedBody) {
+				t.Errorf("signature %q doesn't match the body", got)
+			}
+			var event struct {
+				Type    string         `json:"type"`
+				Payload map[string]int `json:"payload"`
+			}
+			if err := json.Unmarshal(receivedBody, &event); err != nil || event.Type != "task.updated" || event.Payload["id"] != 7 {
+				t.Errorf("body = %s", receivedBody)
+			}
+		})
+	}
+}
+
+func TestWebhookService_ProcessDue_DisabledEndpoint(t *testing.T) {
+	repo := mocks.NewMockWebhookRepository()
+	repo.AddEndpoint(&models.WebhookEndpoint{ID: 1, URL: "http://127.0.0.1:1", IsActive: true, OrgID: 1})
+	service := NewWebhookService(repo)
+	queueWebhookEvent(t, repo, events.TaskDeleted, nil)
+	repo.Endpoints[1].IsActive = false
+
+	service.ProcessDue(context.Background())
+
+	if status := repo.Deliveries[1].Status; status != models.WebhookDeliveryStatusFailed {
+		t.Errorf("status = %s, want failed without sending", status)
+	}
+}
+
+func TestWebhookService_SendTest(t *testing.T) {
+	var receivedEvent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedEvent = r.Header.Get(WebhookEventHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	repo := mocks.NewMockWebhookRepository()
+	// Test events are sent whatever the endpoint subscribes to
+	endpoint := &models.WebhookEndpoint{ID: 1, URL: server.URL, Secret: "whsec_test", EventTypes: []string{"task.created"}, IsActive: true}
+	repo.AddEndpoint(endpoint)
+	service := newLocalWebhookService(repo)
+
+	delivery, err := service.SendTest(context.Background(), endpoint)
+	if err != nil {
+		t.Fatalf("SendTest() error = %v", err)
+	}
+	if delivery.EventType != models.WebhookTestEventType || delivery.Status != models.WebhookDeliveryStatusPending {
+		t.Errorf("delivery = %+v, want a pending test delivery", delivery)
+	}
+
+	service.ProcessDue(context.Background())
+	if receivedEvent != models.WebhookTestEventType || repo.Deliveries[delivery.ID].Status != models.WebhookDeliveryStatusDelivered {
+		t.Errorf("received %q, delivery status %s; want the test event delivered", receivedEvent, repo.Deliveries[delivery.ID].Status)
+	}
+}
+
+func TestWebhookService_RefusesInternalAddresses(t *testing.T) {
+	received := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer server.Close()
+
+	repo := mocks.NewMockWebhookRepository()
+	// A name resolving to an internal address is refused when connecting, not just literal IPs
+	repo.AddEndpoint(&models.WebhookEndpoint{ID: 1, URL: strings.Replace(server.URL, "127.0.0.1", "localhost", 1), IsActive: true, OrgID: 1})
+	service := NewWebhookService(repo)
+	queueWebhookEvent(t, repo, events.TaskCreated, nil)
+
+	service.ProcessDue(context.Background())
+
+	delivery := repo.Deliveries[1]
+	if received {
+		t.Error("delivery was sent to a loopback address")
+	}
+	if delivery.Status != models.WebhookDeliveryStatusPending || delivery.LastError == nil || !strings.Contains(*delivery.LastError, "not allowed") {
+		t.Errorf("delivery = %+v, want a retry for a refused address", delivery)
+	}
+}
+
+func TestWebhookService_DoesNotFollowRedirectsOrKeepResponses(t *testing.T) {
+	followed := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/internal", func(w http.ResponseWriter, r *http.Request) {
+		followed = true
+	})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal secret", http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	repo := mocks.NewMockWebhookRepository()
+	repo.AddEndpoint(&models.WebhookEndpoint{ID: 1, URL: server.URL + "/hook", IsActive: true, OrgID: 1})
+	service := newLocalWebhookService(repo)
+	queueWebhookEvent(t, repo, events.TaskCreated, nil)
+	service.ProcessDue(context.Background())
+
+	if followed {
+		t.Error("redirect was followed")
+	}
+	if status := repo.Deliveries[1].ResponseStatus; status == nil || *status != http.StatusTemporaryRedirect {
+		t.Errorf("response status = %v, want the redirect recorded as a failure", status)
+	}
+
+	repo.Endpoints[1].URL = server.URL + "/error"
+	repo.Deliveries[1].NextAttemptAt = time.Now()
+	service.ProcessDue(context.Background())
+
+	if lastError := repo.Deliveries[1].LastError; lastError == nil || *lastError != "endpoint responded with status 500" {
+		t.Errorf("last error = %v, want only the status kept", lastError)
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// WorkItemSource fetches an account's open work items from a task tracker. Items come back
+// in the Jira issue shape whichever tracker they're from.
+type WorkItemSource interface {
+	GetIssuesByAccountID(accountID string, maxResults int) ([]models.JiraIssue, error)
+}
+
+// WorkItemConnector returns a source for an org-wide task tracker connection
+type WorkItemConnector func(ctx context.Context) (WorkItemSource, error)
+
+// WorkItemProvider serves team members' open work items from one task tracker, so team task
+// views can be built the same way for any tracker the org uses
+type WorkItemProvider interface {
+	// Name identifies the tracker, such as models.WorkItemProviderJira
+	Name() string
+	// AccountID returns the user's account in the tracker, or "" if they aren't mapped
+	AccountID(user *models.User) string
+	// Connected reports whether the org has connected the tracker
+	Connected(ctx context.Context) (bool, error)
+	// Load returns the open work items for each account, at most concurrency fetched at a time,
+	// and when cached items were synced. Accounts whose fetch fails are left out.
+	Load(ctx context.Context, accountIDs []string, refresh bool, concurrency int) (map[string][]models.JiraIssue, map[string]time.Time, error)
+}
+
+// JiraWorkItemProvider serves work items from the org-wide Jira connection through the issue cache
+type JiraWorkItemProvider struct {
+	orgJiraRepo repository.OrgJiraRepository
+	issueSync   *JiraIssueSyncService
+	connect     JiraConnector
+}
+
+// NewJiraWorkItemProvider creates a Jira work item provider. issueSync may be nil to always fetch live.
+func NewJiraWorkItemProvider(orgJiraRepo repository.OrgJiraRepository, issueSync *JiraIssueSyncService, connect JiraConnector) *JiraWorkItemProvider {
+	return &JiraWorkItemProvider{
+		orgJiraRepo: orgJiraRepo,
+		issueSync:   issueSync,
+		connect:     connect,
+	}
+}
+
+// Name identifies the provider
+func (p *JiraWorkItemProvider) Name() string {
+	return models.WorkItemProviderJira
+}
+
+// AccountID returns the user's Jira account ID
+func (p *JiraWorkItemProvider) AccountID(user *models.User) string {
+	if user.JiraAccountID == nil {
+		return ""
+	}
+	return *user.JiraAccountID
+}
+
+// Connected reports whether Jira is connected for the org
+func (p *JiraWorkItemProvider) Connected(ctx context.Context) (bool, error) {
+	settings, err := p.orgJiraRepo.Get(ctx)
+	return settings != nil, err
+}
+
+// Load returns each account's open issues from the cache, fetching unsynced accounts live
+func (p *JiraWorkItemProvider) Load(ctx context.Context, accountIDs []string, refresh bool, concurrency int) (map[string][]models.JiraIssue, map[string]time.Time, error) {
+	return p.issueSync.Load(ctx, p.connect, accountIDs, refresh, concurrency)
+}
+
+// linearMaxIssues caps how many open Linear issues are fetched per account
+const linearMaxIssues = 100
+
+// LinearWorkItemProvider serves work items from the org-wide Linear connection. Linear has no
+// local cache, so items are always fetched live.
+type LinearWorkItemProvider struct {
+	orgLinearRepo repository.OrgLinearRepository
+	connect       WorkItemConnector
+}
+
+// NewLinearWorkItemProvider creates a Linear work item provider
+func NewLinearWorkItemProvider(orgLinearRepo repository.OrgLinearRepository, connect WorkItemConnector) *LinearWorkItemProvider {
+	return &LinearWorkItemProvider{
+		orgLinearRepo: orgLinearRepo,
+		connect:       connect,
+	}
+}
+
+// Name identifies the provider
+func (p *LinearWorkItemProvider) Name() string {
+	return models.WorkItemProviderLinear
+}
+
+// AccountID returns the user's Linear user ID
+func (p *LinearWorkItemProvider) AccountID(user *models.User) string {
+	if user.LinearUserID == nil {
+		return ""
+	}
+	return *user.LinearUserID
+}
+
+// Connected reports whether Linear is connected for the org
+func (p *LinearWorkItemProvider) Connected(ctx context.Context) (bool, error) {
+	settings, err := p.orgLinearRepo.Get(ctx)
+	return settings != nil, err
+}
+
+// Load fetches each account's open issues live. Nothing is cached, so no sync times are returned.
+func (p *LinearWorkItemProvider) Load(ctx context.Context, accountIDs []string, refresh bool, concurrency int) (map[string][]models.JiraIssue, map[string]time.Time, error) {
+	source, err := p.connect(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issues := make(map[string][]models.JiraIssue, len(accountIDs))
+	var mu sync.Mutex
+	fetchConcurrently(accountIDs, concurrency, func(accountID string) {
+		fetched, err := source.GetIssuesByAccountID(accountID, linearMaxIssues)
+		if err != nil {
+			logger.Default().WithComponent("linear").LogError(ctx, "Failed to fetch Linear issues", err, "linear_user_id", accountID)
+			return
+		}
+		if fetched == nil {
+			fetched = []models.JiraIssue{}
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		issues[accountID] = fetched
+	})
+	return issues, map[string]time.Time{}, nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/lifecycle"
+	"github.com/smith-dallin/manager-dashboard/internal/logger"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// workloadSnapshotCheckInterval is how often the worker checks whether the day's snapshot is due
+const workloadSnapshotCheckInterval = 15 * time.Minute
+
+// WorkloadSnapshotService snapshots everyone's open tasks and Jira status distribution once a day,
+// from the configured hour on, and serves the snapshots as workload trends. Snapshots older than
+// MaxWorkloadTrendDays are purged.
+type WorkloadSnapshotService struct {
+	repo repository.WorkloadSnapshotRepository
+	hour int // Hour of the day, in UTC, snapshots are taken from
+	// snapshotDay is the last day snapshotted, so it isn't snapshotted again until the next
+	snapshotDay time.Time
+	logger      *logger.Logger
+}
+
+// NewWorkloadSnapshotService creates a new workload snapshot service
+func NewWorkloadSnapshotService(repo repository.WorkloadSnapshotRepository, hour int) *WorkloadSnapshotService {
+	return &WorkloadSnapshotService{
+		repo:   repo,
+		hour:   hour,
+		logger: logger.Default().WithComponent("workload-snapshots"),
+	}
+}
+
+// SnapshotDue snapshots today's workloads once they're due and returns how many users were
+// recorded. Another instance snapshotting the same day just replaces the same rows.
+func (s *WorkloadSnapshotService) SnapshotDue(ctx context.Context, now time.Time) (int64, error) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if now.Before(day.Add(time.Duration(s.hour)*time.Hour)) || day.Equal(s.snapshotDay) {
+		return 0, nil
+	}
+
+	recorded, err := s.repo.Snapshot(ctx, day)
+	if err != nil {
+		return 0, err
+	}
+	s.snapshotDay = day
+
+	if _, err := s.repo.PurgeBefore(ctx, day.AddDate(0, 0, -models.MaxWorkloadTrendDays)); err != nil {
+		s.logger.LogError(ctx, "Failed to purge workload snapshots", err)
+	}
+	return recorded, nil
+}
+
+// Start checks for a due snapshot every fifteen minutes until workers are stopped
+func (s *WorkloadSnapshotService) Start(workers *lifecycle.Group) {
+	workers.Go("workload-snapshots", func(ctx context.Context) {
+		ticker := time.NewTicker(workloadSnapshotCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				recorded, err := s.SnapshotDue(ctx, time.Now())
+				if err != nil {
+					s.logger.LogError(ctx, "Failed to snapshot workloads", err)
+				}
+				if recorded > 0 {
+					s.logger.Info("Snapshotted workloads", "users", recorded)
+				}
+			}
+		}
+	})
+}
+
+// Trend returns the people's workload snapshots over the last days days, today included, with their
+// totals for each day a snapshot was taken
+func (s *WorkloadSnapshotService) Trend(ctx context.Context, users []models.User, days int, now time.Time) (*models.WorkloadTrend, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start := today.AddDate(0, 0, 1-days)
+
+	trend := &models.WorkloadTrend{
+		Start:  start.Format("2006-01-02"),
+		End:    today.Format("2006-01-02"),
+		Totals: []models.WorkloadSnapshot{},
+		Users:  make([]models.UserWorkloadTrend, 0, len(users)),
+	}
+	if len(users) == 0 {
+		return trend, nil
+	}
+
+	userIDs := make([]int64, len(users))
+	for i, u := range users {
+		userIDs[i] = u.ID
+	}
+	snapshots, err := s.repo.ListForUsers(ctx, userIDs, start)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[int64][]models.WorkloadSnapshot)
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		byUser[snapshot.UserID] = append(byUser[snapshot.UserID], *snapshot)
+
+		// Snapshots are oldest first, so each day's total is started by its first snapshot
+		if n := len(trend.Totals); n == 0 || trend.Totals[n-1].Date != snapshot.Date {
+			trend.Totals = append(trend.Totals, models.WorkloadSnapshot{Date: snapshot.Date})
+		}
+		trend.Totals[len(trend.Totals)-1].Add(snapshot)
+	}
+
+	for _, u := range users {
+		userSnapshots := byUser[u.ID]
+		if userSnapshots == nil {
+			userSnapshots = []models.WorkloadSnapshot{}
+		}
+		trend.Users = append(trend.Users, models.UserWorkloadTrend{
+			UserID:    u.ID,
+			FirstName: u.FirstName,
+			LastName:  u.LastName,
+			Snapshots: userSnapshots,
+		})
+	}
+	return trend, nil
+}
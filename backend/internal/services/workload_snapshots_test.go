@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository/mocks"
+)
+
+func TestWorkloadSnapshotService_SnapshotDue(t *testing.T) {
+	repo := mocks.NewMockWorkloadSnapshotRepository()
+	repo.SnapshotFunc = func(ctx context.Context, day time.Time) (int64, error) { return 4, nil }
+	service := NewWorkloadSnapshotService(repo, 6)
+	ctx := context.Background()
+
+	if n, _ := service.SnapshotDue(ctx, time.Date(2026, 3, 2, 5, 59, 0, 0, time.UTC)); n != 0 || len(repo.SnapshotDays) != 0 {
+		t.Fatalf("snapshotted %d users before the hour, want none", n)
+	}
+	if n, err := service.SnapshotDue(ctx, time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)); err != nil || n != 4 {
+		t.Fatalf("SnapshotDue() = %d, %v, want 4 users", n, err)
+	}
+	if n, _ := service.SnapshotDue(ctx, time.Date(2026, 3, 2, 23, 0, 0, 0, time.UTC)); n != 0 {
+		t.Errorf("snapshotted the same day twice")
+	}
+	if _, err := service.SnapshotDue(ctx, time.Date(2026, 3, 3, 7, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("SnapshotDue() error = %v", err)
+	}
+
+	if len(repo.SnapshotDays) != 2 || !repo.SnapshotDays[1].Equal(time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("snapshot days = %v, want March 2nd and 3rd", repo.SnapshotDays)
+	}
+	wantPurge := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -models.MaxWorkloadTrendDays)
+	if len(repo.PurgedBefore) != 2 || !repo.PurgedBefore[1].Equal(wantPurge) {
+		t.Errorf("purged before %v, want %v", repo.PurgedBefore, wantPurge)
+	}
+}
+
+func TestWorkloadSnapshotService_Trend(t *testing.T) {
+	repo := mocks.NewMockWorkloadSnapshotRepository()
+	repo.Snapshots = []models.WorkloadSnapshot{
+		{UserID: 1, Date: "2026-02-01", OpenTasks: 9},
+		{UserID: 1, Date: "2026-03-01", OpenTasks: 3, OverdueTasks: 1, JiraOpen: 2, JiraStatusCategories: map[string]int{"to_do": 2}, JiraStatuses: map[string]int{"Backlog": 2}},
+		{UserID: 2, Date: "2026-03-01", OpenTasks: 1, JiraOpen: 3, JiraStatusCategories: map[string]int{"to_do": 1, "in_progress": 2}, JiraStatuses: map[string]int{"Backlog": 1, "In Review": 2}},
+		{UserID: 1, Date: "2026-03-02", OpenTasks: 2},
+		{UserID: 3, Date: "2026-03-02", OpenTasks: 50},
+	}
+	service := NewWorkloadSnapshotService(repo, 6)
+	users := []models.User{{ID: 1, FirstName: "Ada"}, {ID: 2, FirstName: "Alan"}, {ID: 4, FirstName: "Grace"}}
+
+	trend, err := service.Trend(context.Background(), users, 7, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Trend() error = %v", err)
+	}
+
+	if trend.Start != "2026-02-24" || trend.End != "2026-03-02" {
+		t.Errorf("range = %s to %s, want 2026-02-24 to 2026-03-02", trend.Start, trend.End)
+	}
+	if len(trend.Totals) != 2 {
+		t.Fatalf("totals = %+v, want two days", trend.Totals)
+	}
+	first := trend.Totals[0]
+	if first.Date != "2026-03-01" || first.OpenTasks != 4 || first.OverdueTasks != 1 || first.JiraOpen != 5 {
+		t.Errorf("first total = %+v, want Ada's and Alan's counts summed", first)
+	}
+	if first.JiraStatusCategories["to_do"] != 3 || first.JiraStatuses["In Review"] != 2 {
+		t.Errorf("first total statuses = %v %v", first.JiraStatusCategories, first.JiraStatuses)
+	}
+	if trend.Totals[1].OpenTasks != 2 {
+		t.Errorf("second total = %+v, want only Ada's snapshot", trend.Totals[1])
+	}
+	if len(trend.Users) != 3 || len(trend.Users[0].Snapshots) != 2 || trend.Users[2].Snapshots == nil {
+		t.Errorf("users = %+v, want Ada with two snapshots and Grace with none", trend.Users)
+	}
+}
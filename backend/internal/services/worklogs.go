@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+	"github.com/smith-dallin/manager-dashboard/internal/repository"
+)
+
+// WorklogSource provides logged time from Jira
+type WorklogSource interface {
+	GetWorklogsByAccountID(accountID string, start, end time.Time) ([]models.JiraWorklog, error)
+}
+
+// WorklogService compares the time employees log in Jira with their capacity
+type WorklogService struct {
+	timeOffRepo repository.TimeOffRepository
+	holidays    []string
+	hoursPerDay float64
+}
+
+// NewWorklogService creates a new worklog service
+func NewWorklogService(timeOffRepo repository.TimeOffRepository, cfg *config.Config) *WorklogService {
+	hoursPerDay := cfg.WorkHoursPerDay
+	if hoursPerDay <= 0 {
+		hoursPerDay = 8
+	}
+	return &WorklogService{
+		timeOffRepo: timeOffRepo,
+		holidays:    cfg.CompanyHolidays,
+		hoursPerDay: hoursPerDay,
+	}
+}
+
+// Summarize builds a worklog summary for a Jira-linked user over the days from start to end inclusive
+func (s *WorklogService) Summarize(ctx context.Context, user *models.User, source WorklogSource, start, end time.Time) (*models.WorklogSummary, error) {
+	endExclusive := end.AddDate(0, 0, 1)
+
+	worklogs, err := source.GetWorklogsByAccountID(*user.JiraAccountID, start, endExclusive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worklogs: %w", err)
+	}
+
+	timeOff, err := s.timeOffRepo.GetApprovedForUsers(ctx, []int64{user.ID}, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time off: %w", err)
+	}
+
+	return BuildWorklogSummary(user, worklogs, timeOff, s.holidays, s.hoursPerDay, start, end), nil
+}
+
+// BuildWorklogSummary totals logged hours per issue and per day, and compares them with the
+// user's capacity: working days that aren't company holidays or approved time off. Worklogs
+// count towards the day they were started on in the author's own time zone.
+func BuildWorklogSummary(
+	user *models.User,
+	worklogs []models.JiraWorklog,
+	timeOff []models.TimeOffRequest,
+	holidays []string,
+	hoursPerDay float64,
+	start, end time.Time,
+) *models.WorklogSummary {
+	startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	endDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	workingDays, rangeHolidays := sprintWorkingDays(startDay, endDay.AddDate(0, 0, 1), holidays)
+	holidaySet := make(map[string]bool, len(rangeHolidays))
+	for _, h := range rangeHolidays {
+		holidaySet[h] = true
+	}
+
+	summary := &models.WorklogSummary{
+		UserID:      user.ID,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Start:       startDay.Format("2006-01-02"),
+		End:         endDay.Format("2006-01-02"),
+		HoursPerDay: hoursPerDay,
+		Issues:      []models.IssueWorklogTotal{},
+		Days:        []models.DailyWorklogTotal{},
+	}
+
+	secondsByDay := make(map[string]int)
+	secondsByIssue := make(map[string]int)
+	summaries := make(map[string]string)
+	totalSeconds := 0
+	for _, w := range worklogs {
+		secondsByDay[w.Started.Format("2006-01-02")] += w.TimeSpentSeconds
+		secondsByIssue[w.IssueKey] += w.TimeSpentSeconds
+		summaries[w.IssueKey] = w.IssueSummary
+		totalSeconds += w.TimeSpentSeconds
+	}
+
+	for day := startDay; !day.After(endDay); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		total := models.DailyWorklogTotal{
+			Date:        date,
+			LoggedHours: secondsToHours(secondsByDay[date]),
+			Holiday:     holidaySet[date],
+		}
+		if _, ok := workingDays[day]; ok {
+			if isOnTimeOff(timeOff, day) {
+				total.TimeOff = true
+				summary.TimeOffDays++
+			} else {
+				total.CapacityHours = hoursPerDay
+			}
+			summary.WorkingDays++
+		}
+		summary.CapacityHours += total.CapacityHours
+		summary.Days = append(summary.Days, total)
+	}
+
+	for key, seconds := range secondsByIssue {
+		summary.Issues = append(summary.Issues, models.IssueWorklogTotal{
+			IssueKey:     key,
+			IssueSummary: summaries[key],
+			Hours:        secondsToHours(seconds),
+		})
+	}
+	sort.Slice(summary.Issues, func(i, j int) bool {
+		if summary.Issues[i].Hours != summary.Issues[j].Hours {
+			return summary.Issues[i].Hours > summary.Issues[j].Hours
+		}
+		return summary.Issues[i].IssueKey < summary.Issues[j].IssueKey
+	})
+
+	summary.LoggedHours = secondsToHours(totalSeconds)
+	if summary.CapacityHours > 0 {
+		summary.UtilizationPercent = math.Round(summary.LoggedHours/summary.CapacityHours*1000) / 10
+	}
+
+	return summary
+}
+
+// secondsToHours converts seconds to hours rounded to two decimal places
+func secondsToHours(seconds int) float64 {
+	return math.Round(float64(seconds)/36) / 100
+}
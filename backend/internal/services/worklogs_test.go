@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/internal/models"
+)
+
+func TestBuildWorklogSummary(t *testing.T) {
+	user := &models.User{ID: 1, FirstName: "Ada", LastName: "Lovelace"}
+	// Monday 2 March to Sunday 8 March 2026
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	mountain := time.FixedZone("MST", -7*3600)
+
+	worklogs := []models.JiraWorklog{
+		{IssueKey: "WEB-1", IssueSummary: "Login", Started: time.Date(2026, 3, 2, 9, 0, 0, 0, mountain), TimeSpentSeconds: 4 * 3600},
+		{IssueKey: "WEB-2", IssueSummary: "Signup", Started: time.Date(2026, 3, 2, 14, 0, 0, 0, mountain), TimeSpentSeconds: 3 * 3600},
+		// Logged in the evening locally, which is already the next day in UTC
+		{IssueKey: "WEB-1", IssueSummary: "Login", Started: time.Date(2026, 3, 3, 20, 0, 0, 0, mountain), TimeSpentSeconds: 5400},
+	}
+	timeOff := []models.TimeOffRequest{{UserID: 1, StartDate: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)}}
+
+	summary := BuildWorklogSummary(user, worklogs, timeOff, []string{"2026-03-06"}, 8, start, end)
+
+	if summary.Start != "2026-03-02" || summary.End != "2026-03-08" || len(summary.Days) != 7 {
+		t.Fatalf("range = %s..%s with %d days, want 7 days from 2026-03-02", summary.Start, summary.End, len(summary.Days))
+	}
+	// Five weekdays minus the holiday, one of which is time off
+	if summary.WorkingDays != 4 || summary.TimeOffDays != 1 || summary.CapacityHours != 24 {
+		t.Errorf("working days = %d, time off = %d, capacity = %v, want 4, 1, 24", summary.WorkingDays, summary.TimeOffDays, summary.CapacityHours)
+	}
+	if summary.LoggedHours != 8.5 || summary.UtilizationPercent != 35.4 {
+		t.Errorf("logged = %v (%v%%), want 8.5 (35.4%%)", summary.LoggedHours, summary.UtilizationPercent)
+	}
+
+	if len(summary.Issues) != 2 || summary.Issues[0].IssueKey != "WEB-1" || summary.Issues[0].Hours != 5.5 || summary.Issues[1].Hours != 3 {
+		t.Errorf("issues = %+v, want WEB-1 5.5h then WEB-2 3h", summary.Issues)
+	}
+
+	days := map[string]models.DailyWorklogTotal{}
+	for _, d := range summary.Days {
+		days[d.Date] = d
+	}
+	if days["2026-03-02"].LoggedHours != 7 || days["2026-03-03"].LoggedHours != 1.5 {
+		t.Errorf("logged per day = %v and %v, want 7 and 1.5 on the author's local days", days["2026-03-02"].LoggedHours, days["2026-03-03"].LoggedHours)
+	}
+	if !days["2026-03-05"].TimeOff || days["2026-03-05"].CapacityHours != 0 {
+		t.Errorf("time off day = %+v", days["2026-03-05"])
+	}
+	if !days["2026-03-06"].Holiday || days["2026-03-06"].CapacityHours != 0 {
+		t.Errorf("holiday = %+v", days["2026-03-06"])
+	}
+	if days["2026-03-07"].CapacityHours != 0 {
+		t.Errorf("weekend capacity = %v, want 0", days["2026-03-07"].CapacityHours)
+	}
+}
@@ -0,0 +1,186 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiURL is the Slack Web API
+const apiURL = "https://slack.com/api"
+
+// ErrUserNotFound is returned when no one in the workspace has the email address
+var ErrUserNotFound = errors.New("slack user not found")
+
+// APIError is an error Slack reported with ok set to false, such as "channel_not_found"
+type APIError struct {
+	Method string
+	Code   string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("slack %s failed: %s", e.Method, e.Code)
+}
+
+// Client represents a Slack Web API client authenticated with a bot token
+type Client struct {
+	botToken   string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Slack client with a bot token
+func NewClient(botToken string) *Client {
+	return &Client{
+		botToken: botToken,
+		baseURL:  apiURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// WithBaseURL points the client at another API root
+func (c *Client) WithBaseURL(baseURL string) *Client {
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+	return c
+}
+
+// get calls a read method, which takes its arguments in the query string
+func (c *Client) get(ctx context.Context, method string, params url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+method+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	return c.do(req, method, out)
+}
+
+// post calls a write method with a JSON body
+func (c *Client) post(ctx context.Context, method string, body any, out any) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+method, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return c.do(req, method, out)
+}
+
+// do performs an authenticated request. Slack reports errors with a 200 status and ok set to
+// false, so both are checked before the response is decoded into out.
+func (c *Client) do(req *http.Request, method string, out any) error {
+	req.Header.Set("Authorization", "Bearer "+c.botToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack %s failed (status %d): %s", method, resp.StatusCode, string(body))
+	}
+
+	var envelope struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !envelope.OK {
+		return &APIError{Method: method, Code: envelope.Error}
+	}
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// LookupUserByEmail returns the ID of the workspace member with the email address, or
+// ErrUserNotFound
+func (c *Client) LookupUserByEmail(ctx context.Context, email string) (string, error) {
+	var resp struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	err := c.get(ctx, "users.lookupByEmail", url.Values{"email": {email}}, &resp)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Code == "users_not_found" {
+		return "", ErrUserNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return resp.User.ID, nil
+}
+
+// GetUserEmail returns the email address of a workspace member
+func (c *Client) GetUserEmail(ctx context.Context, userID string) (string, error) {
+	var resp struct {
+		User struct {
+			Profile struct {
+				Email string `json:"email"`
+			} `json:"profile"`
+		} `json:"user"`
+	}
+	if err := c.get(ctx, "users.info", url.Values{"user": {userID}}, &resp); err != nil {
+		return "", err
+	}
+	if resp.User.Profile.Email == "" {
+		return "", fmt.Errorf("slack user %s has no email address", userID)
+	}
+	return resp.User.Profile.Email, nil
+}
+
+// PostMessage sends a message to a channel. Posting to a user ID sends a direct message from
+// the app.
+func (c *Client) PostMessage(ctx context.Context, channel string, msg Message) error {
+	return c.post(ctx, "chat.postMessage", struct {
+		Channel string `json:"channel"`
+		Message
+	}{channel, msg}, nil)
+}
+
+// RespondToAction replaces the message a button was clicked in, using the response URL Slack
+// sent with the interaction. Response URLs don't need the bot token.
+func (c *Client) RespondToAction(ctx context.Context, responseURL string, msg Message) error {
+	encoded, err := json.Marshal(struct {
+		ReplaceOriginal bool `json:"replace_original"`
+		Message
+	}{true, msg})
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("slack action response failed (status %d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
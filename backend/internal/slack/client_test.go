@@ -0,0 +1,75 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_LookupUserByEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxb-1" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Path != "/users.lookupByEmail" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if r.URL.Query().Get("email") == "ada@example.com" {
+			_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"U1"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":false,"error":"users_not_found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("xoxb-1").WithBaseURL(server.URL + "/")
+
+	id, err := client.LookupUserByEmail(context.Background(), "ada@example.com")
+	if err != nil || id != "U1" {
+		t.Errorf("LookupUserByEmail() = %q, %v, want U1", id, err)
+	}
+	if _, err := client.LookupUserByEmail(context.Background(), "nobody@example.com"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("LookupUserByEmail() for unknown email error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestClient_PostMessage(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/chat.postMessage" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if got["channel"] == "UGONE" {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("xoxb-1").WithBaseURL(server.URL)
+
+	if err := client.PostMessage(context.Background(), "U1", TimeOffReviewMessage("Ada requested PTO", 42)); err != nil {
+		t.Fatalf("PostMessage() error = %v", err)
+	}
+	if got["channel"] != "U1" || got["text"] != "Ada requested PTO" {
+		t.Errorf("message = %v", got)
+	}
+	blocks, _ := got["blocks"].([]any)
+	if len(blocks) != 2 {
+		t.Fatalf("blocks = %v, want a section and actions", got["blocks"])
+	}
+	buttons, _ := blocks[1].(map[string]any)["elements"].([]any)
+	if len(buttons) != 2 || buttons[0].(map[string]any)["value"] != "42" || buttons[1].(map[string]any)["action_id"] != ActionRejectTimeOff {
+		t.Errorf("buttons = %v", buttons)
+	}
+
+	var apiErr *APIError
+	if err := client.PostMessage(context.Background(), "UGONE", Message{Text: "hi"}); !errors.As(err, &apiErr) || apiErr.Code != "channel_not_found" {
+		t.Errorf("PostMessage() error = %v, want channel_not_found", err)
+	}
+}
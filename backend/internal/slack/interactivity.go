@@ -0,0 +1,141 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers Slack signs interactive requests with
+const (
+	SignatureHeader = "X-Slack-Signature"
+	TimestampHeader = "X-Slack-Request-Timestamp"
+)
+
+// maxRequestAge rejects replayed requests, as Slack recommends
+const maxRequestAge = 5 * time.Minute
+
+// Action IDs of the time off review buttons; their value is the request ID
+const (
+	ActionApproveTimeOff = "approve_time_off"
+	ActionRejectTimeOff  = "reject_time_off"
+)
+
+// Message is a message body: plain text, which is also the notification fallback, and optional
+// Block Kit blocks
+type Message struct {
+	Text   string  `json:"text"`
+	Blocks []Block `json:"blocks,omitempty"`
+}
+
+// Block is a Block Kit layout block
+type Block struct {
+	Type     string  `json:"type"`
+	Text     *Text   `json:"text,omitempty"`
+	Elements []Block `json:"elements,omitempty"` // Buttons in an actions block
+	ActionID string  `json:"action_id,omitempty"`
+	Value    string  `json:"value,omitempty"`
+	Style    string  `json:"style,omitempty"`
+}
+
+// Text is a Block Kit text object
+type Text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// TimeOffReviewMessage shows text with Approve and Reject buttons for a time off request
+func TimeOffReviewMessage(text string, requestID int64) Message {
+	id := strconv.FormatInt(requestID, 10)
+	return Message{
+		Text: text,
+		Blocks: []Block{
+			{Type: "section", Text: &Text{Type: "mrkdwn", Text: text}},
+			{Type: "actions", Elements: []Block{
+				{Type: "button", Text: &Text{Type: "plain_text", Text: "Approve"}, ActionID: ActionApproveTimeOff, Value: id, Style: "primary"},
+				{Type: "button", Text: &Text{Type: "plain_text", Text: "Reject"}, ActionID: ActionRejectTimeOff, Value: id, Style: "danger"},
+			}},
+		},
+	}
+}
+
+// VerifyRequestSignature reports whether a request came from Slack. Slack signs with "v0="
+// followed by the hex HMAC-SHA256 of "v0:{timestamp}:{body}" using the app's signing secret.
+// Requests older than five minutes are rejected so they can't be replayed.
+func VerifyRequestSignature(signingSecret string, body []byte, timestamp, signature string, now time.Time) bool {
+	if signingSecret == "" {
+		return false
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return false
+	}
+	expected, ok := strings.CutPrefix(signature, "v0=")
+	if !ok {
+		return false
+	}
+	given, err := hex.DecodeString(expected)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return hmac.Equal(given, mac.Sum(nil))
+}
+
+// Interaction is a button click in one of the app's messages
+type Interaction struct {
+	TeamID      string
+	UserID      string
+	ResponseURL string
+	ActionID    string
+	Value       string
+}
+
+// ParseInteraction decodes an interactive request, which Slack sends as a form with the JSON
+// payload in its payload field. Only button clicks are supported.
+func ParseInteraction(body []byte) (*Interaction, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode interaction form: %w", err)
+	}
+
+	var payload struct {
+		Type string `json:"type"`
+		Team struct {
+			ID string `json:"id"`
+		} `json:"team"`
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+		ResponseURL string `json:"response_url"`
+		Actions     []struct {
+			ActionID string `json:"action_id"`
+			Value    string `json:"value"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode interaction payload: %w", err)
+	}
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		return nil, fmt.Errorf("unsupported interaction %q", payload.Type)
+	}
+
+	return &Interaction{
+		TeamID:      payload.Team.ID,
+		UserID:      payload.User.ID,
+		ResponseURL: payload.ResponseURL,
+		ActionID:    payload.Actions[0].ActionID,
+		Value:       payload.Actions[0].Value,
+	}, nil
+}
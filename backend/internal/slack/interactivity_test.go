@@ -0,0 +1,65 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func signSlackRequest(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyRequestSignature(t *testing.T) {
+	body := []byte("payload=%7B%7D")
+	now := time.Unix(1700000000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	stale := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		want      bool
+	}{
+		{"valid", "s3cret", ts, signSlackRequest("s3cret", ts, body), true},
+		{"wrong secret", "s3cret", ts, signSlackRequest("other", ts, body), false},
+		{"replayed", "s3cret", stale, signSlackRequest("s3cret", stale, body), false},
+		{"missing prefix", "s3cret", ts, signSlackRequest("s3cret", ts, body)[len("v0="):], false},
+		{"bad timestamp", "s3cret", "soon", signSlackRequest("s3cret", "soon", body), false},
+		{"no secret configured", "", ts, signSlackRequest("", ts, body), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyRequestSignature(tt.secret, body, tt.timestamp, tt.signature, now); got != tt.want {
+				t.Errorf("VerifyRequestSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInteraction(t *testing.T) {
+	payload := `{"type":"block_actions","team":{"id":"T1"},"user":{"id":"U1"},"response_url":"https://hooks.slack.com/actions/1",
+		"actions":[{"action_id":"approve_time_off","value":"42"}]}`
+	interaction, err := ParseInteraction([]byte(url.Values{"payload": {payload}}.Encode()))
+	if err != nil {
+		t.Fatalf("ParseInteraction() error = %v", err)
+	}
+	want := Interaction{TeamID: "T1", UserID: "U1", ResponseURL: "https://hooks.slack.com/actions/1", ActionID: ActionApproveTimeOff, Value: "42"}
+	if *interaction != want {
+		t.Errorf("ParseInteraction() = %+v, want %+v", *interaction, want)
+	}
+
+	if _, err := ParseInteraction([]byte(url.Values{"payload": {`{"type":"view_submission"}`}}.Encode())); err == nil {
+		t.Error("ParseInteraction() accepted a modal submission")
+	}
+}
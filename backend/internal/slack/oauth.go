@@ -0,0 +1,110 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+)
+
+const (
+	// Slack OAuth v2 endpoints for installing the app in a workspace
+	authorizationURL = "https://slack.com/oauth/v2/authorize"
+	tokenURL         = "https://slack.com/api/oauth.v2.access"
+)
+
+// BotScopes are the bot token permissions the app asks for: sending direct messages, and finding
+// employees' Slack accounts by email
+var BotScopes = []string{"chat:write", "users:read", "users:read.email"}
+
+// OAuthService handles the Slack app installation flow
+type OAuthService struct {
+	clientID     string
+	clientSecret string
+	callbackURL  string
+	tokenURL     string
+	httpClient   *http.Client
+}
+
+// NewOAuthService creates a new Slack OAuth service
+func NewOAuthService(cfg *config.Config) *OAuthService {
+	return &OAuthService{
+		clientID:     cfg.SlackClientID,
+		clientSecret: cfg.SlackClientSecret,
+		callbackURL:  cfg.SlackCallbackURL,
+		tokenURL:     tokenURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// TokenResponse represents the OAuth token response from Slack. Bot tokens don't expire.
+type TokenResponse struct {
+	OK          bool   `json:"ok"`
+	Error       string `json:"error"`
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	Scope       string `json:"scope"`
+	BotUserID   string `json:"bot_user_id"`
+	Team        struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"team"`
+}
+
+// GetAuthorizationURL returns the URL to redirect the user to for installing the app
+func (s *OAuthService) GetAuthorizationURL(state string) string {
+	params := url.Values{
+		"client_id":    {s.clientID},
+		"scope":        {strings.Join(BotScopes, ",")},
+		"redirect_uri": {s.callbackURL},
+		"state":        {state},
+	}
+	return authorizationURL + "?" + params.Encode()
+}
+
+// ExchangeCode exchanges an authorization code for a bot token. Slack reports OAuth errors with a
+// 200 status and ok set to false, so both are checked.
+func (s *OAuthService) ExchangeCode(code string) (*TokenResponse, error) {
+	data := url.Values{
+		"client_id":     {s.clientID},
+		"client_secret": {s.clientSecret},
+		"code":          {code},
+		"redirect_uri":  {s.callbackURL},
+	}
+	req, err := http.NewRequest("POST", s.tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if !tokenResp.OK {
+		return nil, fmt.Errorf("token request failed: %s", tokenResp.Error)
+	}
+	if tokenResp.AccessToken == "" || tokenResp.Team.ID == "" {
+		return nil, fmt.Errorf("token response has no bot token or workspace")
+	}
+
+	return &tokenResp, nil
+}
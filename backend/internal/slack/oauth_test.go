@@ -0,0 +1,69 @@
+package slack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/smith-dallin/manager-dashboard/config"
+)
+
+func TestOAuthService_GetAuthorizationURL(t *testing.T) {
+	s := NewOAuthService(&config.Config{SlackClientID: "123.456", SlackCallbackURL: "https://dash.example.com/api/slack/oauth/callback"})
+
+	u, err := url.Parse(s.GetAuthorizationURL("state-123"))
+	if err != nil {
+		t.Fatalf("invalid URL: %v", err)
+	}
+	q := u.Query()
+	if q.Get("client_id") != "123.456" || q.Get("state") != "state-123" || q.Get("redirect_uri") != "https://dash.example.com/api/slack/oauth/callback" {
+		t.Errorf("authorization URL params = %v", q)
+	}
+	if q.Get("scope") != "chat:write,users:read,users:read.email" {
+		t.Errorf("scope = %q", q.Get("scope"))
+	}
+}
+
+func TestOAuthService_ExchangeCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  string
+	}{
+		{"installed", `{"ok":true,"access_token":"xoxb-1","token_type":"bot","bot_user_id":"UBOT","team":{"id":"T1","name":"Acme"}}`, ""},
+		{"error with 200 status", `{"ok":false,"error":"invalid_code"}`, "invalid_code"},
+		{"no workspace", `{"ok":true,"access_token":"xoxb-1"}`, "no bot token or workspace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				if r.PostForm.Get("code") != "code-1" || r.PostForm.Get("client_secret") != "secret" {
+					t.Errorf("form = %v", r.PostForm)
+				}
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			s := NewOAuthService(&config.Config{SlackClientID: "id", SlackClientSecret: "secret"})
+			s.tokenURL = server.URL
+
+			resp, err := s.ExchangeCode("code-1")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ExchangeCode() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExchangeCode() error = %v", err)
+			}
+			if resp.AccessToken != "xoxb-1" || resp.Team.ID != "T1" || resp.Team.Name != "Acme" || resp.BotUserID != "UBOT" {
+				t.Errorf("ExchangeCode() = %+v", resp)
+			}
+		})
+	}
+}
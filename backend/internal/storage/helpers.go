@@ -1,7 +1,9 @@
 package storage
 
 import (
+	"fmt"
 	"path/filepath"
+	"strings"
 )
 
 // ExtractKeyFromURL extracts the storage key from a full URL
@@ -18,3 +20,23 @@ func ExtractKeyFromURL(url, baseURL string) string {
 	// Try to extract from path
 	return filepath.Base(url)
 }
+
+// MigrateAvatarKey maps a legacy avatar key (avatars/{userID}_{timestamp}{ext}) to its location under the user's prefix.
+// It returns false if the key is not in the legacy layout.
+func MigrateAvatarKey(tenantID string, userID int64, key string) (string, bool) {
+	name, ok := strings.CutPrefix(key, "avatars/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	name = strings.TrimPrefix(name, fmt.Sprintf("%d_", userID))
+	return UserPrefix(tenantID, userID) + "avatars/" + name, true
+}
+
+// MigrateMeetingAttachmentKey maps a legacy meeting file key (meetings/{meetingID}/...) to its location under the uploader's prefix.
+// It returns false if the key is not in the legacy layout.
+func MigrateMeetingAttachmentKey(tenantID string, uploaderID int64, key string) (string, bool) {
+	if !strings.HasPrefix(key, "meetings/") {
+		return "", false
+	}
+	return UserPrefix(tenantID, uploaderID) + key, true
+}
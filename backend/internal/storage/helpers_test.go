@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -60,38 +61,23 @@ func TestExtractKeyFromURL(t *testing.T) {
 }
 
 func TestGenerateAvatarKey(t *testing.T) {
-	t.Run("generates key with correct format", func(t *testing.T) {
-		key := GenerateAvatarKey(123, ".png")
+	t.Run("generates key under the user prefix", func(t *testing.T) {
+		key := GenerateAvatarKey("acme", 123, ".png")
 
-		// Should start with avatars/
-		if len(key) < 8 || key[:8] != "avatars/" {
-			t.Errorf("key should start with 'avatars/', got %s", key)
+		if !strings.HasPrefix(key, "tenants/acme/users/123/avatars/") {
+			t.Errorf("key should start with 'tenants/acme/users/123/avatars/', got %s", key)
 		}
-
-		// Should contain the user ID
-		if key[8:11] != "123" {
-			t.Errorf("key should contain user ID 123, got %s", key)
-		}
-
-		// Should end with the extension
-		if key[len(key)-4:] != ".png" {
+		if !strings.HasSuffix(key, ".png") {
 			t.Errorf("key should end with .png, got %s", key)
 		}
 	})
 
 	t.Run("generates keys with timestamp component", func(t *testing.T) {
-		key := GenerateAvatarKey(123, ".png")
-
-		// Key format: avatars/{userID}_{timestamp}{extension}
-		// Should have underscores separating user ID and timestamp
-		if key[11] != '_' {
-			t.Errorf("key should have underscore after user ID, got %s", key)
-		}
-
-		// Generate multiple keys and verify they all have the expected format
+		// Key format: tenants/{tenantID}/users/{userID}/avatars/{timestamp}{extension}
 		for i := 0; i < 5; i++ {
-			k := GenerateAvatarKey(int64(i), ".jpg")
-			if len(k) < 20 {
+			k := GenerateAvatarKey("acme", int64(i), ".jpg")
+			name := strings.TrimSuffix(k[strings.LastIndex(k, "/")+1:], ".jpg")
+			if len(name) < 10 {
 				t.Errorf("key seems too short, expected timestamp component: %s", k)
 			}
 		}
@@ -100,7 +86,7 @@ func TestGenerateAvatarKey(t *testing.T) {
 	t.Run("handles different extensions", func(t *testing.T) {
 		extensions := []string{".png", ".jpg", ".gif", ".webp"}
 		for _, ext := range extensions {
-			key := GenerateAvatarKey(1, ext)
+			key := GenerateAvatarKey("acme", 1, ext)
 			if key[len(key)-len(ext):] != ext {
 				t.Errorf("key should end with %s, got %s", ext, key)
 			}
@@ -108,6 +94,107 @@ func TestGenerateAvatarKey(t *testing.T) {
 	})
 }
 
+func TestGenerateAvatarVariantKey(t *testing.T) {
+	key := GenerateAvatarVariantKey("acme", 123, 456, 128, ".webp")
+	if key != "tenants/acme/users/123/avatars/456-128.webp" {
+		t.Errorf("unexpected key: %s", key)
+	}
+}
+
+func TestGenerateDefaultAvatarKey(t *testing.T) {
+	key := GenerateDefaultAvatarKey("acme", 123, "initials-abc.svg")
+	if key != "tenants/acme/users/123/avatars/default/initials-abc.svg" {
+		t.Errorf("unexpected key: %s", key)
+	}
+}
+
+func TestGenerateMeetingAttachmentKey(t *testing.T) {
+	key := GenerateMeetingAttachmentKey("acme", 5, 42, ".vtt")
+
+	if !strings.HasPrefix(key, UserPrefix("acme", 5)+"meetings/42/") {
+		t.Errorf("key should be under the uploader's meetings/42/ prefix, got %s", key)
+	}
+	if !strings.HasSuffix(key, ".vtt") {
+		t.Errorf("key should end with .vtt, got %s", key)
+	}
+}
+
+func TestGenerateExportKey(t *testing.T) {
+	key := GenerateExportKey("acme", 5, 42, ".csv")
+
+	if !strings.HasPrefix(key, UserPrefix("acme", 5)+"exports/42-") {
+		t.Errorf("key should be under the requester's exports/ prefix, got %s", key)
+	}
+	if !strings.HasSuffix(key, ".csv") {
+		t.Errorf("key should end with .csv, got %s", key)
+	}
+}
+
+func TestUserPrefix(t *testing.T) {
+	prefix := UserPrefix("acme", 7)
+	if prefix != "tenants/acme/users/7/" {
+		t.Errorf("expected tenants/acme/users/7/, got %s", prefix)
+	}
+	if !strings.HasPrefix(prefix, TenantPrefix("acme")) {
+		t.Errorf("user prefix %s should be inside tenant prefix %s", prefix, TenantPrefix("acme"))
+	}
+}
+
+func TestMigrateAvatarKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int64
+		key      string
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "legacy key drops user ID from file name",
+			userID:   123,
+			key:      "avatars/123_456.png",
+			expected: "tenants/acme/users/123/avatars/456.png",
+			ok:       true,
+		},
+		{
+			name:     "legacy key for another user keeps file name",
+			userID:   9,
+			key:      "avatars/123_456.png",
+			expected: "tenants/acme/users/9/avatars/123_456.png",
+			ok:       true,
+		},
+		{
+			name:   "already migrated",
+			userID: 123,
+			key:    "tenants/acme/users/123/avatars/456.png",
+		},
+		{
+			name:   "unrelated key",
+			userID: 123,
+			key:    "https://gravatar.com/avatar/abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := MigrateAvatarKey("acme", tt.userID, tt.key)
+			if ok != tt.ok || result != tt.expected {
+				t.Errorf("expected (%q, %v), got (%q, %v)", tt.expected, tt.ok, result, ok)
+			}
+		})
+	}
+}
+
+func TestMigrateMeetingAttachmentKey(t *testing.T) {
+	result, ok := MigrateMeetingAttachmentKey("acme", 5, "meetings/42/789.mp4")
+	if !ok || result != "tenants/acme/users/5/meetings/42/789.mp4" {
+		t.Errorf("unexpected migration result (%q, %v)", result, ok)
+	}
+
+	if _, ok := MigrateMeetingAttachmentKey("acme", 5, "tenants/acme/users/5/meetings/42/789.mp4"); ok {
+		t.Error("already migrated key should not be migrated again")
+	}
+}
+
 func TestGetContentType(t *testing.T) {
 	tests := []struct {
 		extension   string
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	appconfig "github.com/smith-dallin/manager-dashboard/config"
 )
 
@@ -19,6 +21,22 @@ type Storage interface {
 	Upload(ctx context.Context, key string, data []byte, contentType string) (string, error)
 	Delete(ctx context.Context, key string) error
 	GetURL(key string) string
+	// KeyFromURL returns the key of an object from a URL returned by GetURL, or false if the URL
+	// doesn't point into this storage
+	KeyFromURL(url string) (string, bool)
+}
+
+// PrefixStorage is implemented by backends that can copy objects and remove everything under a key prefix
+type PrefixStorage interface {
+	Storage
+	Copy(ctx context.Context, srcKey, dstKey string) error
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+}
+
+// SignedURLStorage is implemented by backends that can grant temporary access to private objects
+type SignedURLStorage interface {
+	Storage
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
 }
 
 // S3Storage implements Storage interface for AWS S3 and compatible services
@@ -117,6 +135,89 @@ func (s *S3Storage) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Copy duplicates an object to a new key within the bucket
+func (s *S3Storage) Copy(ctx context.Context, srcKey, dstKey string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		CopySource: aws.String(s.bucket + "/" + escapeKey(srcKey)),
+		Key:        aws.String(dstKey),
+	}
+
+	_, err := s.client.CopyObject(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s in S3: %w", srcKey, err)
+	}
+
+	return nil
+}
+
+// escapeKey URL-encodes each segment of a key for use as a copy source
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// DeletePrefix removes every object whose key starts with prefix and returns how many were deleted
+func (s *S3Storage) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	// An empty prefix would empty the bucket
+	if prefix == "" {
+		return 0, fmt.Errorf("refusing to delete with an empty prefix")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	deleted := 0
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to list S3 objects: %w", err)
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+
+		// List pages hold at most 1000 keys, which is also the DeleteObjects limit
+		objects := make([]types.ObjectIdentifier, 0, len(page.Contents))
+		for _, obj := range page.Contents {
+			objects = append(objects, types.ObjectIdentifier{Key: obj.Key})
+		}
+		_, err = s.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucket),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete from S3: %w", err)
+		}
+		deleted += len(objects)
+	}
+
+	return deleted, nil
+}
+
+// SignedURL returns a presigned GET URL for a key that stays valid for ttl
+func (s *S3Storage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	presigner := s3.NewPresignClient(s.client, s3.WithPresignExpires(ttl))
+	req, err := presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to presign S3 URL: %w", err)
+	}
+	return req.URL, nil
+}
+
 // GetURL returns the public URL for a given key
 func (s *S3Storage) GetURL(key string) string {
 	if s.publicURL != "" {
@@ -125,13 +226,61 @@ func (s *S3Storage) GetURL(key string) string {
 	}
 
 	// Default S3 URL format
+	return s.defaultURL(key)
+}
+
+// defaultURL returns the virtual-hosted S3 URL for a key
+func (s *S3Storage) defaultURL(key string) string {
 	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, key)
 }
 
-// GenerateAvatarKey creates a unique key for avatar storage
-func GenerateAvatarKey(userID int64, extension string) string {
+// KeyFromURL returns the key of an object from its public URL. The default S3 URL is also
+// recognized so files uploaded before a custom public URL was configured still resolve.
+func (s *S3Storage) KeyFromURL(rawURL string) (string, bool) {
+	for _, base := range []string{s.GetURL(""), s.defaultURL("")} {
+		if key, ok := strings.CutPrefix(rawURL, base); ok && key != "" {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// TenantPrefix returns the key prefix holding every object stored for a tenant
+func TenantPrefix(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/", tenantID)
+}
+
+// UserPrefix returns the key prefix holding every object stored for one user within a tenant
+func UserPrefix(tenantID string, userID int64) string {
+	return fmt.Sprintf("%susers/%d/", TenantPrefix(tenantID), userID)
+}
+
+// GenerateAvatarKey creates a unique key for avatar storage under the user's prefix
+func GenerateAvatarKey(tenantID string, userID int64, extension string) string {
 	timestamp := time.Now().UnixNano()
-	return fmt.Sprintf("avatars/%d_%d%s", userID, timestamp, extension)
+	return fmt.Sprintf("%savatars/%d%s", UserPrefix(tenantID, userID), timestamp, extension)
+}
+
+// GenerateAvatarVariantKey creates the key for one resized variant of an avatar upload under the user's prefix
+func GenerateAvatarVariantKey(tenantID string, userID, uploadID int64, size int, extension string) string {
+	return fmt.Sprintf("%savatars/%d-%d%s", UserPrefix(tenantID, userID), uploadID, size, extension)
+}
+
+// GenerateDefaultAvatarKey creates the key of a generated default avatar under the user's prefix
+func GenerateDefaultAvatarKey(tenantID string, userID int64, name string) string {
+	return fmt.Sprintf("%savatars/default/%s", UserPrefix(tenantID, userID), name)
+}
+
+// GenerateMeetingAttachmentKey creates a unique key for a meeting recording or transcript under the uploader's prefix
+func GenerateMeetingAttachmentKey(tenantID string, uploaderID, meetingID int64, extension string) string {
+	timestamp := time.Now().UnixNano()
+	return fmt.Sprintf("%smeetings/%d/%d%s", UserPrefix(tenantID, uploaderID), meetingID, timestamp, extension)
+}
+
+// GenerateExportKey creates a unique key for a generated export file under the requester's prefix
+func GenerateExportKey(tenantID string, userID, jobID int64, extension string) string {
+	timestamp := time.Now().UnixNano()
+	return fmt.Sprintf("%sexports/%d-%d%s", UserPrefix(tenantID, userID), jobID, timestamp, extension)
 }
 
 // Helper to get content type from extension
@@ -149,3 +298,19 @@ func GetContentType(extension string) string {
 		return "application/octet-stream"
 	}
 }
+
+// GetExtension returns the file extension for an image content type, defaulting to .jpg
+func GetExtension(contentType string) string {
+	switch strings.ToLower(contentType) {
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/svg+xml":
+		return ".svg"
+	default:
+		return ".jpg"
+	}
+}
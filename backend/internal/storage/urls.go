@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// URL modes control how the URLs of stored files are handed to clients
+const (
+	// URLModePublic serves the stored URL as is, for public buckets
+	URLModePublic = "public"
+	// URLModeSigned serves time-limited presigned URLs, for private buckets
+	URLModeSigned = "signed"
+	// URLModeCDN serves files from a CDN in front of the bucket
+	URLModeCDN = "cdn"
+)
+
+// maxCachedSignedURLs bounds the signed URL cache; expired entries are dropped once it is reached
+const maxCachedSignedURLs = 10000
+
+// signedURL is a cached presigned URL
+type signedURL struct {
+	url       string
+	expiresAt time.Time
+}
+
+// URLResolver rewrites the stored URLs of files into ones clients can load, either presigned
+// URLs that expire or URLs under a CDN. URLs that don't point into the storage, such as
+// identity provider pictures, are returned unchanged.
+type URLResolver struct {
+	store      Storage
+	mode       string
+	cdnBaseURL string
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]signedURL
+}
+
+// NewURLResolver creates a resolver for the given mode. Signed mode requires storage that can
+// presign URLs, and CDN mode requires a base URL.
+func NewURLResolver(store Storage, mode, cdnBaseURL string, ttl time.Duration) (*URLResolver, error) {
+	r := &URLResolver{
+		store:      store,
+		mode:       mode,
+		cdnBaseURL: strings.TrimRight(cdnBaseURL, "/"),
+		ttl:        ttl,
+		now:        time.Now,
+		cache:      make(map[string]signedURL),
+	}
+
+	switch mode {
+	case URLModePublic:
+	case URLModeSigned:
+		if _, ok := store.(SignedURLStorage); !ok {
+			return nil, fmt.Errorf("storage does not support signed URLs")
+		}
+		if ttl <= 0 {
+			return nil, fmt.Errorf("signed URL TTL must be positive")
+		}
+	case URLModeCDN:
+		if r.cdnBaseURL == "" {
+			return nil, fmt.Errorf("CDN base URL is required")
+		}
+	default:
+		return nil, fmt.Errorf("unknown URL mode %q", mode)
+	}
+	return r, nil
+}
+
+// Resolve returns the URL clients should use for a stored URL. Signed URLs are reused until half
+// their lifetime has passed so browsers can cache the file. On error the stored URL is returned.
+func (r *URLResolver) Resolve(rawURL string) string {
+	if r == nil || r.mode == URLModePublic {
+		return rawURL
+	}
+	key, ok := r.store.KeyFromURL(rawURL)
+	if !ok {
+		return rawURL
+	}
+
+	if r.mode == URLModeCDN {
+		return r.cdnBaseURL + "/" + key
+	}
+
+	now := r.now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, ok := r.cache[key]; ok && cached.expiresAt.Sub(now) > r.ttl/2 {
+		return cached.url
+	}
+
+	url, err := r.store.(SignedURLStorage).SignedURL(context.Background(), key, r.ttl)
+	if err != nil {
+		return rawURL
+	}
+	if len(r.cache) >= maxCachedSignedURLs {
+		for k, cached := range r.cache {
+			if !cached.expiresAt.After(now) {
+				delete(r.cache, k)
+			}
+		}
+		if len(r.cache) >= maxCachedSignedURLs {
+			clear(r.cache)
+		}
+	}
+	r.cache[key] = signedURL{url: url, expiresAt: now.Add(r.ttl)}
+	return url
+}
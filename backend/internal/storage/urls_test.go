@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSignedStorage is a Storage that presigns URLs, counting how many it has signed
+type fakeSignedStorage struct {
+	signed int
+}
+
+func (s *fakeSignedStorage) Upload(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	return s.GetURL(key), nil
+}
+
+func (s *fakeSignedStorage) Delete(ctx context.Context, key string) error { return nil }
+
+func (s *fakeSignedStorage) GetURL(key string) string { return "https://bucket.example.com/" + key }
+
+func (s *fakeSignedStorage) KeyFromURL(url string) (string, bool) {
+	return strings.CutPrefix(url, "https://bucket.example.com/")
+}
+
+func (s *fakeSignedStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	s.signed++
+	return fmt.Sprintf("https://bucket.example.com/%s?sig=%d", key, s.signed), nil
+}
+
+func TestNewURLResolver(t *testing.T) {
+	store := &fakeSignedStorage{}
+	tests := []struct {
+		name    string
+		mode    string
+		cdn     string
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{"public", URLModePublic, "", 0, false},
+		{"signed", URLModeSigned, "", time.Hour, false},
+		{"signed without TTL", URLModeSigned, "", 0, true},
+		{"cdn", URLModeCDN, "https://cdn.example.com", 0, false},
+		{"cdn without base URL", URLModeCDN, "", 0, true},
+		{"unknown mode", "proxy", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewURLResolver(store, tt.mode, tt.cdn, tt.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewURLResolver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestURLResolver_Resolve(t *testing.T) {
+	const stored = "https://bucket.example.com/tenants/acme/users/1/avatars/5-128.jpg"
+
+	t.Run("cdn rewrites stored URLs", func(t *testing.T) {
+		r, err := NewURLResolver(&fakeSignedStorage{}, URLModeCDN, "https://cdn.example.com/", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Resolve(stored); got != "https://cdn.example.com/tenants/acme/users/1/avatars/5-128.jpg" {
+			t.Errorf("Resolve() = %s", got)
+		}
+	})
+
+	t.Run("leaves external URLs alone", func(t *testing.T) {
+		r, err := NewURLResolver(&fakeSignedStorage{}, URLModeCDN, "https://cdn.example.com", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		external := "https://gravatar.com/avatar/abc"
+		if got := r.Resolve(external); got != external {
+			t.Errorf("Resolve() = %s, want %s", got, external)
+		}
+	})
+
+	t.Run("signed URLs are reused until half their lifetime", func(t *testing.T) {
+		store := &fakeSignedStorage{}
+		r, err := NewURLResolver(store, URLModeSigned, "", time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		now := time.Now()
+		r.now = func() time.Time { return now }
+
+		first := r.Resolve(stored)
+		if !strings.HasSuffix(first, "?sig=1") {
+			t.Fatalf("Resolve() = %s, want a signed URL", first)
+		}
+		now = now.Add(20 * time.Minute)
+		if got := r.Resolve(stored); got != first {
+			t.Errorf("Resolve() = %s, want the cached %s", got, first)
+		}
+		now = now.Add(20 * time.Minute)
+		if got := r.Resolve(stored); !strings.HasSuffix(got, "?sig=2") {
+			t.Errorf("Resolve() = %s, want a freshly signed URL", got)
+		}
+	})
+}
+
+func TestS3Storage_KeyFromURL(t *testing.T) {
+	s := &S3Storage{bucket: "files", region: "us-west-2", publicURL: "https://cdn.example.com/"}
+
+	tests := []struct {
+		url    string
+		key    string
+		wantOK bool
+	}{
+		{"https://cdn.example.com/tenants/acme/users/1/avatars/5.png", "tenants/acme/users/1/avatars/5.png", true},
+		{"https://files.s3.us-west-2.amazonaws.com/tenants/acme/users/1/avatars/5.png", "tenants/acme/users/1/avatars/5.png", true},
+		{"https://gravatar.com/avatar/abc", "", false},
+		{"https://cdn.example.com/", "", false},
+	}
+	for _, tt := range tests {
+		key, ok := s.KeyFromURL(tt.url)
+		if key != tt.key || ok != tt.wantOK {
+			t.Errorf("KeyFromURL(%q) = %q, %v, want %q, %v", tt.url, key, ok, tt.key, tt.wantOK)
+		}
+	}
+}
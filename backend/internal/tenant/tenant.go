@@ -0,0 +1,87 @@
+// Package tenant carries the organization a request acts in, so repositories can keep each
+// organization's data apart in a hosted, multi-company deployment
+package tenant
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// DefaultOrgID is the organization that single-company deployments, and data created before
+// organizations existed, belong to
+const DefaultOrgID int64 = 1
+
+// ErrNoOrg is returned when organizations are required and a context that must act in one isn't
+// scoped to any
+var ErrNoOrg = errors.New("no organization in context")
+
+// orgContextKey is the context key for storing the organization ID
+type orgContextKey struct{}
+
+// acrossOrgsContextKey marks contexts that act across every organization
+type acrossOrgsContextKey struct{}
+
+// required is whether contexts must be scoped to an organization to see any organization's data
+var required atomic.Bool
+
+// Require makes contexts that aren't scoped to an organization, or marked as acting across
+// every one, see no organization's data. Hosted deployments shared by several companies turn it
+// on, so a request that misses its scope fails closed rather than seeing every company's data.
+func Require(enabled bool) {
+	required.Store(enabled)
+}
+
+// IsRequired reports whether contexts must be scoped to an organization, as set by Require
+func IsRequired() bool {
+	return required.Load()
+}
+
+// AcrossOrgs marks ctx as acting across every organization, for background jobs and for
+// callbacks that find the organization from the data they're about
+func AcrossOrgs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, acrossOrgsContextKey{}, true)
+}
+
+// IsAcrossOrgs reports whether ctx acts across every organization: it was marked so, or isn't
+// scoped to one while organizations aren't required
+func IsAcrossOrgs(ctx context.Context) bool {
+	if _, ok := OrgID(ctx); ok {
+		return false
+	}
+	across, _ := ctx.Value(acrossOrgsContextKey{}).(bool)
+	return across || !required.Load()
+}
+
+// WithOrgID scopes ctx to an organization
+func WithOrgID(ctx context.Context, orgID int64) context.Context {
+	return context.WithValue(ctx, orgContextKey{}, orgID)
+}
+
+// OrgID returns the organization ctx is scoped to. Contexts that aren't, such as those of
+// background jobs, act across every organization when IsAcrossOrgs says so, and otherwise see none.
+func OrgID(ctx context.Context) (int64, bool) {
+	orgID, ok := ctx.Value(orgContextKey{}).(int64)
+	return orgID, ok
+}
+
+// OrgIDOrDefault returns the organization ctx is scoped to, or the default organization
+func OrgIDOrDefault(ctx context.Context) int64 {
+	if orgID, ok := OrgID(ctx); ok {
+		return orgID
+	}
+	return DefaultOrgID
+}
+
+// ScopedOrgID returns the organization ctx is scoped to, for settings and records that belong to
+// exactly one. Single-company deployments fall back to the default organization; once
+// organizations are required, unscoped contexts get ErrNoOrg instead of another company's data.
+func ScopedOrgID(ctx context.Context) (int64, error) {
+	if orgID, ok := OrgID(ctx); ok {
+		return orgID, nil
+	}
+	if required.Load() {
+		return 0, ErrNoOrg
+	}
+	return DefaultOrgID, nil
+}
@@ -0,0 +1,75 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestOrgID(t *testing.T) {
+	if _, ok := OrgID(context.Background()); ok {
+		t.Error("expected unscoped context to have no organization")
+	}
+
+	ctx := WithOrgID(context.Background(), 7)
+	orgID, ok := OrgID(ctx)
+	if !ok || orgID != 7 {
+		t.Errorf("expected organization 7, got %d (ok=%v)", orgID, ok)
+	}
+}
+
+func TestOrgIDOrDefault(t *testing.T) {
+	if got := OrgIDOrDefault(context.Background()); got != DefaultOrgID {
+		t.Errorf("expected default organization %d, got %d", DefaultOrgID, got)
+	}
+	if got := OrgIDOrDefault(WithOrgID(context.Background(), 3)); got != 3 {
+		t.Errorf("expected organization 3, got %d", got)
+	}
+}
+
+func TestScopedOrgID(t *testing.T) {
+	defer Require(false)
+
+	if got, err := ScopedOrgID(WithOrgID(context.Background(), 3)); err != nil || got != 3 {
+		t.Errorf("expected organization 3, got %d (err=%v)", got, err)
+	}
+	if got, err := ScopedOrgID(context.Background()); err != nil || got != DefaultOrgID {
+		t.Errorf("expected default organization without multi-tenancy, got %d (err=%v)", got, err)
+	}
+
+	Require(true)
+	if _, err := ScopedOrgID(context.Background()); !errors.Is(err, ErrNoOrg) {
+		t.Errorf("expected ErrNoOrg for an unscoped context with multi-tenancy, got %v", err)
+	}
+	if _, err := ScopedOrgID(AcrossOrgs(context.Background())); !errors.Is(err, ErrNoOrg) {
+		t.Errorf("expected ErrNoOrg for a context acting across organizations, got %v", err)
+	}
+	if got, err := ScopedOrgID(WithOrgID(context.Background(), 3)); err != nil || got != 3 {
+		t.Errorf("expected organization 3 with multi-tenancy, got %d (err=%v)", got, err)
+	}
+}
+
+func TestIsAcrossOrgs(t *testing.T) {
+	defer Require(false)
+
+	tests := []struct {
+		name     string
+		required bool
+		ctx      context.Context
+		want     bool
+	}{
+		{"unscoped without multi-tenancy", false, context.Background(), true},
+		{"unscoped with multi-tenancy", true, context.Background(), false},
+		{"explicitly across organizations", true, AcrossOrgs(context.Background()), true},
+		{"scoped to an organization", false, WithOrgID(context.Background(), 2), false},
+		{"scoping wins over acting across", true, WithOrgID(AcrossOrgs(context.Background()), 2), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Require(tt.required)
+			if got := IsAcrossOrgs(tt.ctx); got != tt.want {
+				t.Errorf("IsAcrossOrgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
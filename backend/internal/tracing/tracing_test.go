@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/smith-dallin/manager-dashboard/config"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider that keeps finished spans in memory
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), &config.Config{})
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v", err)
+	}
+}
+
+func TestMiddleware_NamesSpansByRoute(t *testing.T) {
+	recorder := recordSpans(t)
+
+	r := chi.NewRouter()
+	r.Use(chimiddleware.RequestID)
+	r.Use(Middleware)
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {})
+	r.Route("/api/users", func(r chi.Router) {
+		r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	for _, path := range []string{"/api/users/7", "/api/users/42", "/health"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2 (health checks are not traced)", len(spans))
+	}
+	for _, span := range spans {
+		if span.Name() != "GET /api/users/{id}" {
+			t.Errorf("span name = %q, want %q", span.Name(), "GET /api/users/{id}")
+		}
+		var hasRequestID bool
+		for _, attr := range span.Attributes() {
+			if attr.Key == "http.request_id" && attr.Value.AsString() != "" {
+				hasRequestID = true
+			}
+		}
+		if !hasRequestID {
+			t.Errorf("span %q has no http.request_id attribute", span.Name())
+		}
+	}
+}
+
+func TestTransport_PropagatesTraceContext(t *testing.T) {
+	recorder := recordSpans(t)
+	_, _ = Setup(context.Background(), &config.Config{}) // Installs the trace context propagator
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx, parent := Tracer().Start(context.Background(), "request")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	if traceparent == "" {
+		t.Fatal("outbound request has no traceparent header")
+	}
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want a client span and its parent", len(spans))
+	}
+	client := spans[0]
+	if client.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("client span parent = %s, want %s", client.Parent().SpanID(), parent.SpanContext().SpanID())
+	}
+}
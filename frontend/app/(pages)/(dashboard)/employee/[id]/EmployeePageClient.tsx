@@ -37,6 +37,9 @@ export const EmployeePageClient = ({
   const [isRemoveModalOpen, setIsRemoveModalOpen] = useState(false);
   const [isRemoving, setIsRemoving] = useState(false);
   const [removeError, setRemoveError] = useState<string | null>(null);
+  const [impersonateError, setImpersonateError] = useState<string | null>(
+    null,
+  );
   const { startImpersonation, isImpersonating } = useImpersonation();
 
   // Admin can impersonate any active user except themselves
@@ -55,9 +58,16 @@ export const EmployeePageClient = ({
       (currentUser?.role === "supervisor" &&
         employee.supervisor_id === currentUser?.id));
 
-  const handleImpersonate = () => {
-    startImpersonation(employee);
-    router.push("/");
+  const handleImpersonate = async () => {
+    setImpersonateError(null);
+    try {
+      await startImpersonation(employee);
+      router.push("/");
+    } catch (err) {
+      setImpersonateError(
+        err instanceof Error ? err.message : "Failed to start impersonation",
+      );
+    }
   };
 
   const handleRemoveUser = async () => {
@@ -103,6 +113,9 @@ export const EmployeePageClient = ({
         </Link>
 
         <div className="flex items-center gap-2">
+          {impersonateError && (
+            <p className="text-sm text-red-400">{impersonateError}</p>
+          )}
           {canImpersonate && (
             <button
               onClick={handleImpersonate}
@@ -1,5 +1,6 @@
 import {
   TimeOffRequest,
+  TimeOffRequestPage,
   TimeOffStatus,
   CreateTimeOffRequest,
   ReviewTimeOffRequest,
@@ -24,10 +25,11 @@ export const getMyTimeOffRequests = async (
 ): Promise<TimeOffRequest[]> => {
   const params = status ? `?status=${status}` : "";
   const response = await fetchWithProxy(`/time-off${params}`);
-  return handleResponse<TimeOffRequest[]>(
+  const page = await handleResponse<TimeOffRequestPage>(
     response,
     "Failed to fetch time off requests",
   );
+  return page.data;
 };
 
 export const getTimeOffRequest = async (
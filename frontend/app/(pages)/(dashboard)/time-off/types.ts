@@ -26,6 +26,12 @@ export interface TimeOffRequest {
   updated_at: string;
 }
 
+export interface TimeOffRequestPage {
+  data: TimeOffRequest[];
+  next_cursor?: string;
+  has_more: boolean;
+}
+
 export interface TimeOffImpact {
   has_time_off: boolean;
   time_off_days: number;
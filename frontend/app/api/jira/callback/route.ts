@@ -47,11 +47,18 @@ export const GET = async (request: NextRequest) => {
 
     const backendResponse = await fetch(backendUrl, {
       method: "GET",
+      redirect: "manual",
       headers: {
         Authorization: `Bearer ${accessToken}`,
       },
     });
 
+    // The backend redirects to the settings page, to pick a site or with an error
+    const location = backendResponse.headers.get("location");
+    if (location) {
+      return NextResponse.redirect(new URL(location, request.url));
+    }
+
     if (!backendResponse.ok) {
       const errorText = await backendResponse.text();
       const settingsUrl = new URL("/settings", request.url);
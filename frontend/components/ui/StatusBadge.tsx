@@ -125,7 +125,7 @@ export const StatusBadge = ({
  *
  * A specialized badge for user roles.
  */
-export type UserRole = "admin" | "supervisor" | "employee";
+export type UserRole = "admin" | "supervisor" | "employee" | "viewer";
 
 export interface RoleBadgeProps {
   role: UserRole;
@@ -137,12 +137,14 @@ const roleVariants: Record<UserRole, BadgeVariant> = {
   admin: "error",
   supervisor: "info",
   employee: "default",
+  viewer: "default",
 };
 
 const roleLabels: Record<UserRole, string> = {
   admin: "Admin",
   supervisor: "Supervisor",
   employee: "Employee",
+  viewer: "Viewer",
 };
 
 export const RoleBadge = ({
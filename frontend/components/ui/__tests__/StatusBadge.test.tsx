@@ -287,11 +287,12 @@ describe("RoleBadge", () => {
   });
 
   describe("all roles render correctly", () => {
-    const roles: UserRole[] = ["admin", "supervisor", "employee"];
+    const roles: UserRole[] = ["admin", "supervisor", "employee", "viewer"];
     const expectedLabels: Record<UserRole, string> = {
       admin: "Admin",
       supervisor: "Supervisor",
       employee: "Employee",
+      viewer: "Viewer",
     };
 
     it.each(roles)("%s role displays correct label", (role) => {
@@ -14,10 +14,8 @@ const API_BASE_URL = "/api/proxy";
 
 const getImpersonationHeader = (): Record<string, string> => {
   if (typeof window === "undefined") return {};
-  const impersonatedUserId = sessionStorage.getItem("impersonation_user_id");
-  return impersonatedUserId
-    ? { "X-Impersonate-User-Id": impersonatedUserId }
-    : {};
+  const sessionId = sessionStorage.getItem("impersonation_session_id");
+  return sessionId ? { "X-Impersonation-Session-Id": sessionId } : {};
 };
 
 export const fetchWithProxy = async (
@@ -67,8 +67,11 @@ export const proxyToBackend = async (
     const headers: HeadersInit = { "Content-Type": "application/json" };
     if (token) headers["Authorization"] = `Bearer ${token}`;
 
-    const impersonateHeader = request.headers.get("X-Impersonate-User-Id");
-    if (impersonateHeader) headers["X-Impersonate-User-Id"] = impersonateHeader;
+    const impersonationSession = request.headers.get(
+      "X-Impersonation-Session-Id",
+    );
+    if (impersonationSession)
+      headers["X-Impersonation-Session-Id"] = impersonationSession;
 
     console.log(`Proxy: Fetching ${backendUrl}`);
     const res = await fetch(backendUrl, {
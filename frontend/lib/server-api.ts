@@ -1,7 +1,10 @@
 import "server-only";
 import { auth0 } from "./auth0";
 import { User, Squad } from "@/shared/types/user";
-import { TimeOffRequest } from "@/app/(pages)/(dashboard)/time-off/types";
+import {
+  TimeOffRequest,
+  TimeOffRequestPage,
+} from "@/app/(pages)/(dashboard)/time-off/types";
 import { JiraSettings, JiraUserWithMapping } from "@/app/(pages)/jira/types";
 import {
   OrgTreeNode,
@@ -33,8 +36,8 @@ export const getUserByIdServer = (id: number) => get<User>(`/users/${id}`);
 export const getCurrentUserServer = () => get<User>("/me");
 export const getEmployeesServer = () => get<User[]>("/employees");
 export const getAllUsersServer = () => get<User[]>("/users");
-export const getMyTimeOffRequestsServer = () =>
-  get<TimeOffRequest[]>("/time-off");
+export const getMyTimeOffRequestsServer = async () =>
+  (await get<TimeOffRequestPage>("/time-off")).data;
 export const getPendingTimeOffRequestsServer = () =>
   get<TimeOffRequest[]>("/time-off/pending");
 export const getJiraSettingsServer = () => get<JiraSettings>("/jira/settings");
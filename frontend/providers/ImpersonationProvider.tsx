@@ -9,13 +9,19 @@ import {
   ReactNode,
 } from "react";
 import { User } from "@/shared/types/user";
+import { fetchWithProxy, handleResponse } from "@/lib/api";
 
 const IMPERSONATION_STORAGE_KEY = "impersonation_user_id";
+export const IMPERSONATION_SESSION_STORAGE_KEY = "impersonation_session_id";
+
+interface ImpersonationSessionResponse {
+  session: { id: number };
+}
 
 interface ImpersonationContextType {
   impersonatedUser: User | null;
   isImpersonating: boolean;
-  startImpersonation: (user: User) => void;
+  startImpersonation: (user: User) => Promise<void>;
   endImpersonation: () => void;
   impersonatedUserId: number | null;
   setImpersonatedUser: (user: User | null) => void;
@@ -37,7 +43,10 @@ export const ImpersonationProvider = ({
 
   useEffect(() => {
     const storedId = sessionStorage.getItem(IMPERSONATION_STORAGE_KEY);
-    if (storedId) {
+    const storedSession = sessionStorage.getItem(
+      IMPERSONATION_SESSION_STORAGE_KEY,
+    );
+    if (storedId && storedSession) {
       const id = parseInt(storedId, 10);
       if (!isNaN(id)) {
         setImpersonatedUserId(id);
@@ -45,16 +54,35 @@ export const ImpersonationProvider = ({
     }
   }, []);
 
-  const startImpersonation = useCallback((user: User) => {
+  const startImpersonation = useCallback(async (user: User) => {
+    const response = await fetchWithProxy(`/admin/impersonate/${user.id}`, {
+      method: "POST",
+    });
+    const { session } = await handleResponse<ImpersonationSessionResponse>(
+      response,
+      "Failed to start impersonation",
+    );
+    sessionStorage.setItem(
+      IMPERSONATION_SESSION_STORAGE_KEY,
+      session.id.toString(),
+    );
+    sessionStorage.setItem(IMPERSONATION_STORAGE_KEY, user.id.toString());
     setImpersonatedUserId(user.id);
     setImpersonatedUser(user);
-    sessionStorage.setItem(IMPERSONATION_STORAGE_KEY, user.id.toString());
   }, []);
 
   const endImpersonation = useCallback(() => {
+    const storedId = sessionStorage.getItem(IMPERSONATION_STORAGE_KEY);
     setImpersonatedUserId(null);
     setImpersonatedUser(null);
     sessionStorage.removeItem(IMPERSONATION_STORAGE_KEY);
+    sessionStorage.removeItem(IMPERSONATION_SESSION_STORAGE_KEY);
+    // The session expires on its own if ending it fails
+    if (storedId) {
+      fetchWithProxy(`/admin/impersonate/${storedId}`, {
+        method: "DELETE",
+      }).catch(() => {});
+    }
   }, []);
 
   const value: ImpersonationContextType = {
@@ -76,7 +104,7 @@ export const ImpersonationProvider = ({
 const defaultImpersonationContext: ImpersonationContextType = {
   impersonatedUser: null,
   isImpersonating: false,
-  startImpersonation: () => {},
+  startImpersonation: async () => {},
   endImpersonation: () => {},
   impersonatedUserId: null,
   setImpersonatedUser: () => {},
@@ -1,6 +1,6 @@
 /**
  * Tests for providers/ImpersonationProvider.tsx
- * Impersonation context provider with sessionStorage persistence
+ * Impersonation context provider backed by impersonation sessions, persisted in sessionStorage
  */
 
 import React from "react";
@@ -11,6 +11,12 @@ import {
 } from "../ImpersonationProvider";
 import { User } from "@/shared/types/user";
 
+const mockFetchWithProxy = jest.fn();
+jest.mock("@/lib/api", () => ({
+  fetchWithProxy: (...args: unknown[]) => mockFetchWithProxy(...args),
+  handleResponse: (response: { json: () => unknown }) => response.json(),
+}));
+
 // Mock sessionStorage
 const mockSessionStorage = (() => {
   let store: Record<string, string> = {};
@@ -97,6 +103,9 @@ describe("ImpersonationProvider", () => {
   beforeEach(() => {
     jest.clearAllMocks();
     mockSessionStorage.clear();
+    mockFetchWithProxy.mockResolvedValue({
+      json: async () => ({ session: { id: 7 } }),
+    });
   });
 
   describe("rendering", () => {
@@ -148,28 +157,28 @@ describe("ImpersonationProvider", () => {
   });
 
   describe("startImpersonation", () => {
-    it("sets isImpersonating to true", () => {
+    it("sets isImpersonating to true", async () => {
       render(
         <ImpersonationProvider>
           <TestConsumer />
         </ImpersonationProvider>,
       );
 
-      act(() => {
+      await act(async () => {
         screen.getByTestId("start-btn").click();
       });
 
       expect(screen.getByTestId("is-impersonating")).toHaveTextContent("true");
     });
 
-    it("sets impersonatedUserId", () => {
+    it("sets impersonatedUserId", async () => {
       render(
         <ImpersonationProvider>
           <TestConsumer />
         </ImpersonationProvider>,
       );
 
-      act(() => {
+      await act(async () => {
         screen.getByTestId("start-btn").click();
       });
 
@@ -178,14 +187,14 @@ describe("ImpersonationProvider", () => {
       );
     });
 
-    it("sets impersonatedUser", () => {
+    it("sets impersonatedUser", async () => {
       render(
         <ImpersonationProvider>
           <TestConsumer />
         </ImpersonationProvider>,
       );
 
-      act(() => {
+      await act(async () => {
         screen.getByTestId("start-btn").click();
       });
 
@@ -194,14 +203,14 @@ describe("ImpersonationProvider", () => {
       );
     });
 
-    it("saves to sessionStorage", () => {
+    it("saves to sessionStorage", async () => {
       render(
         <ImpersonationProvider>
           <TestConsumer />
         </ImpersonationProvider>,
       );
 
-      act(() => {
+      await act(async () => {
         screen.getByTestId("start-btn").click();
       });
 
@@ -209,18 +218,38 @@ describe("ImpersonationProvider", () => {
         "impersonation_user_id",
         "42",
       );
+      expect(mockSessionStorage.setItem).toHaveBeenCalledWith(
+        "impersonation_session_id",
+        "7",
+      );
+    });
+
+    it("starts an impersonation session", async () => {
+      render(
+        <ImpersonationProvider>
+          <TestConsumer />
+        </ImpersonationProvider>,
+      );
+
+      await act(async () => {
+        screen.getByTestId("start-btn").click();
+      });
+
+      expect(mockFetchWithProxy).toHaveBeenCalledWith("/admin/impersonate/42", {
+        method: "POST",
+      });
     });
   });
 
   describe("endImpersonation", () => {
-    it("sets isImpersonating to false", () => {
+    it("sets isImpersonating to false", async () => {
       render(
         <ImpersonationProvider>
           <TestConsumer />
         </ImpersonationProvider>,
       );
 
-      act(() => {
+      await act(async () => {
         screen.getByTestId("start-btn").click();
       });
 
@@ -231,14 +260,14 @@ describe("ImpersonationProvider", () => {
       expect(screen.getByTestId("is-impersonating")).toHaveTextContent("false");
     });
 
-    it("clears impersonatedUserId", () => {
+    it("clears impersonatedUserId", async () => {
       render(
         <ImpersonationProvider>
           <TestConsumer />
         </ImpersonationProvider>,
       );
 
-      act(() => {
+      await act(async () => {
         screen.getByTestId("start-btn").click();
       });
 
@@ -251,14 +280,14 @@ describe("ImpersonationProvider", () => {
       );
     });
 
-    it("clears impersonatedUser", () => {
+    it("clears impersonatedUser", async () => {
       render(
         <ImpersonationProvider>
           <TestConsumer />
         </ImpersonationProvider>,
       );
 
-      act(() => {
+      await act(async () => {
         screen.getByTestId("start-btn").click();
       });
 
@@ -271,14 +300,14 @@ describe("ImpersonationProvider", () => {
       );
     });
 
-    it("removes from sessionStorage", () => {
+    it("removes from sessionStorage", async () => {
       render(
         <ImpersonationProvider>
           <TestConsumer />
         </ImpersonationProvider>,
       );
 
-      act(() => {
+      await act(async () => {
         screen.getByTestId("start-btn").click();
       });
 
@@ -289,6 +318,29 @@ describe("ImpersonationProvider", () => {
       expect(mockSessionStorage.removeItem).toHaveBeenCalledWith(
         "impersonation_user_id",
       );
+      expect(mockSessionStorage.removeItem).toHaveBeenCalledWith(
+        "impersonation_session_id",
+      );
+    });
+
+    it("ends the impersonation session", async () => {
+      render(
+        <ImpersonationProvider>
+          <TestConsumer />
+        </ImpersonationProvider>,
+      );
+
+      await act(async () => {
+        screen.getByTestId("start-btn").click();
+      });
+
+      act(() => {
+        screen.getByTestId("end-btn").click();
+      });
+
+      expect(mockFetchWithProxy).toHaveBeenCalledWith("/admin/impersonate/42", {
+        method: "DELETE",
+      });
     });
   });
 
@@ -355,6 +407,20 @@ describe("ImpersonationProvider", () => {
       );
     });
 
+    it("does not restore a user without a session", () => {
+      mockSessionStorage.getItem.mockImplementation((key: string) =>
+        key === "impersonation_user_id" ? "123" : null,
+      );
+
+      render(
+        <ImpersonationProvider>
+          <TestConsumer />
+        </ImpersonationProvider>,
+      );
+
+      expect(screen.getByTestId("is-impersonating")).toHaveTextContent("false");
+    });
+
     it("handles null sessionStorage value", () => {
       mockSessionStorage.getItem.mockReturnValue(null);
 
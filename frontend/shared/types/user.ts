@@ -1,4 +1,4 @@
-export type Role = "admin" | "supervisor" | "employee";
+export type Role = "admin" | "supervisor" | "employee" | "viewer";
 
 export interface Squad {
   id: number;